@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -9,103 +11,202 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/agnath18K/lumo/dbus/common"
 	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/completion"
 	"github.com/agnath18K/lumo/pkg/config"
 	"github.com/agnath18K/lumo/pkg/daemon"
+	"github.com/agnath18K/lumo/pkg/deprecation"
 	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
 	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/paths"
 	"github.com/agnath18K/lumo/pkg/pipe"
+	"github.com/agnath18K/lumo/pkg/script"
 	"github.com/agnath18K/lumo/pkg/server"
+	"github.com/agnath18K/lumo/pkg/setup"
 	"github.com/agnath18K/lumo/pkg/terminal"
 	"github.com/agnath18K/lumo/pkg/utils"
+	"github.com/agnath18K/lumo/pkg/vcr"
 	"github.com/agnath18K/lumo/pkg/version"
+	"github.com/agnath18K/lumo/pkg/widget"
 )
 
 func main() {
+	// Global flags go before the command and apply to any command
+	opts, args, err := cli.Parse(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\nUsage: lumo [options] <command>\n%s", err, cli.Usage())
+		os.Exit(2)
+	}
+	// The parser tells command-line input from interactive input by os.Args,
+	// so it is left holding only the command. args shares os.Args' array, so
+	// os.Args gets a new one.
+	os.Args = append([]string{os.Args[0]}, args...)
+
+	// Files older versions kept in the home directory move to the state directory
+	paths.MigrateLegacy()
+
 	// Initialize configuration
+	if err := paths.SetConfigFile(opts.Config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
 
+	if opts.Provider != "" {
+		if _, ok := ai.Lookup(opts.Provider); !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown AI provider: %s (available: %s)\n", opts.Provider, strings.Join(ai.ProviderNames(), ", "))
+			os.Exit(2)
+		}
+		cfg.UseProvider(opts.Provider)
+	}
+	cfg.Quiet = opts.Quiet
+	cfg.NoCache = opts.NoCache
+	cfg.Safe = opts.Safe
+	if cfg.Safe {
+		hooks.SetDisabled(true)
+		if !cfg.Quiet {
+			fmt.Fprintln(os.Stderr, "Safe mode: hooks, the REST server, and desktop integrations are off")
+		}
+	}
+	utils.SetTimeStyle(cfg.TimeFormat)
+
+	// Cassettes capture or stand in for provider requests, so clients must
+	// be created after the transport is set
+	switch {
+	case opts.Record != "":
+		recorder, err := vcr.NewRecorder(opts.Record, nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ai.SetTransport(recorder)
+	case opts.Replay != "":
+		player, err := vcr.NewPlayer(opts.Replay)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ai.SetTransport(player)
+	}
+
+	// Requests from the server's handlers, bots and the agent share each
+	// provider's limit on requests in flight
+	ai.SetLimiter(ai.LimiterFromConfig(cfg))
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	// Renamed prefixes still work, rewritten by the parser, but scripts
+	// using them should be updated
+	if deprecated, ok := deprecation.Lookup(strings.Join(args, " ")); ok && !deprecated.Retired {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", deprecated.Warning())
+	}
+
+	// Prompts run the widget every time they are drawn, so it skips
+	// setting up the executor and agent
+	if name == "widget" {
+		runWidget(cfg, args[1:])
+		return
+	}
+
 	// Initialize components
 	parser := nlp.NewParser(cfg)
 	exec := executor.NewExecutor(cfg)
 	term := terminal.NewTerminal(cfg)
+	if opts.Out != "" {
+		term.SetOutputFile(opts.Out, opts.Append)
+	}
+	exec.SetCopyBlock(opts.Copy)
+
+	switch {
+	case opts.Version || name == "version":
+		version.PrintVersion()
+		return
+	case opts.Help || name == "help":
+		result, err := exec.Execute(&nlp.Command{
+			Type:       nlp.CommandTypeHelp,
+			Intent:     "help",
+			Parameters: make(map[string]string),
+			RawInput:   "help",
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error displaying help: %v\n", err)
+			os.Exit(1)
+		}
+		term.Display(result)
+		return
+	}
 
 	// Initialize agent
 	_ = agent.Initialize(cfg, exec)
 
-	// Check for server daemon commands
-	if len(os.Args) > 1 {
-		// Handle server daemon commands
-		if os.Args[1] == "server:start" {
-			// Start the server daemon
-			d := daemon.New(cfg)
-			if err := d.Start(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error starting server daemon: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Println("Server daemon started")
-			os.Exit(0)
-		} else if os.Args[1] == "server:stop" {
-			// Stop the server daemon
-			d := daemon.New(cfg)
-			if err := d.Stop(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error stopping server daemon: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Println("Server daemon stopped")
-			os.Exit(0)
-		} else if os.Args[1] == "server:status" {
-			// Check server daemon status
-			d := daemon.New(cfg)
-			running, pid, err := d.Status()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error checking server daemon status: %v\n", err)
-				os.Exit(1)
-			}
-			if running {
-				fmt.Printf("Server daemon is running with PID %d\n", pid)
-			} else {
-				fmt.Println("Server daemon is not running")
-			}
-			os.Exit(0)
-		} else if os.Args[1] == "server:daemon" {
-			// This is the daemon process
-			d := daemon.New(cfg)
-			if err := d.RunServer(exec); err != nil {
-				fmt.Fprintf(os.Stderr, "Error running server daemon: %v\n", err)
-				os.Exit(1)
-			}
-			os.Exit(0)
+	// Commands that manage lumo itself run before the REST server starts
+	switch name {
+	case "server:start", "server:stop", "server:status", "server:install", "server:uninstall":
+		runServerCommand(cfg, strings.TrimPrefix(name, "server:"))
+		return
+	case "server:daemon":
+		// This is the daemon process
+		d := daemon.New(cfg)
+		if err := d.RunServer(exec); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running server daemon: %s\n", lumoerr.Format(err))
+			os.Exit(1)
 		}
+		return
+	case "completion":
+		// Print a shell completion script
+		shell := ""
+		if len(args) > 1 {
+			shell = args[1]
+		}
+		script, err := completion.Script(shell)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\nUsage: lumo completion %s\n", err, strings.Join(completion.Shells, "|"))
+			os.Exit(1)
+		}
+		fmt.Print(script)
+		return
+	case "script":
+		runScript(cfg, exec, args[1:])
+		return
+	case "__complete":
+		// Called by the completion scripts with the line being completed
+		for _, candidate := range completion.Complete(cfg, strings.Join(args[1:], " ")) {
+			fmt.Println(candidate)
+		}
+		return
+	case common.RecordNotificationsArg:
+		// Started by enable-dnd to keep the notifications that arrive
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := common.RecordNotifications(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording notifications: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Check if a server daemon is already running
-	d := daemon.New(cfg)
-	running, _, err := d.IsRunning()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error checking if server daemon is running: %v\n", err)
-	}
-
-	// Start the REST server if enabled and not already running as a daemon
-	var srv *server.Server
-	if cfg.EnableServer && !running {
-		srv = server.New(cfg, exec)
-		if err := srv.Start(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting REST server: %v\n", err)
-			// Continue execution even if server fails to start
-		} else {
-			// Set up signal handling for graceful shutdown
-			setupSignalHandling(srv)
-
-			// Notify the user that the server is running
-			if !cfg.ServerQuietOutput {
-				fmt.Fprintf(os.Stderr, "\nNOTE: Lumo REST server is running on port %d\n", cfg.ServerPort)
-				fmt.Fprintf(os.Stderr, "To disable the server, run: lumo config:server disable\n\n")
-			}
+	// Start the REST server if enabled and not already running as a
+	// daemon; safe mode does neither
+	if cfg.EnableServer && !cfg.Safe {
+		// Check if a server daemon is already running
+		running, _, err := daemon.New(cfg).IsRunning()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking if server daemon is running: %v\n", err)
+		}
+		if !running {
+			startServer(cfg, exec)
 		}
 	}
 
@@ -113,154 +214,207 @@ func main() {
 	stat, _ := os.Stdin.Stat()
 	isPiped := (stat.Mode() & os.ModeCharDevice) == 0
 
+	// "lumo -" and "ask:-" read the question from stdin, and "ask:--edit"
+	// from the editor, so long questions need no shell quoting
+	if len(args) > 0 && processComposedQuestion(parser, exec, term, isPiped) {
+		return
+	}
+
 	if isPiped && cfg.EnablePipeProcessing {
 		// Process piped input
-		processPipedInput(exec, term)
-	} else if len(os.Args) > 1 {
-		// Check for version flag
-		if os.Args[1] == "--version" || os.Args[1] == "-v" || os.Args[1] == "version" {
-			version.PrintVersion()
-			os.Exit(0)
-		}
-
-		// Check for help flag
-		if os.Args[1] == "--help" || os.Args[1] == "-h" || os.Args[1] == "help" {
-			// Display help message
-			helpCmd := &nlp.Command{
-				Type:       nlp.CommandTypeHelp,
-				Intent:     "help",
-				Parameters: make(map[string]string),
-				RawInput:   "help",
-			}
-			result, err := exec.Execute(helpCmd)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error displaying help: %v\n", err)
-				os.Exit(1)
-			}
-			term.Display(result)
-			os.Exit(0)
-		}
-
-		// Process command from arguments
-		// Join arguments with spaces, preserving quotes if present
-		command := strings.Join(os.Args[1:], " ")
-
-		// In AI-first mode (default), we don't need special handling for quoted strings
-		// as everything will be treated as an AI query by default unless it has a specific prefix
-		// or is a single executable command in command-first mode.
-
-		// However, we still want to handle the case where a command might be a quoted string
-		// that was split by the shell, for better user experience
-		if len(os.Args) > 2 && !cfg.CommandFirstMode {
-			// If we have multiple arguments and none of them start with a prefix like "lumo:" or "shell:",
-			// it might be a quoted string that was split
-			hasPrefix := false
-			for _, prefix := range []string{"lumo:", "shell:", "ask:", "ai:", "auto:", "agent:",
-				"health:", "syshealth:", "report:", "sysreport:", "chat:", "talk:", "config:",
-				"speed:", "speedtest:", "speed-test:", "magic:", "clipboard", "connect", "create", "server:"} {
-				if strings.HasPrefix(command, prefix) {
-					hasPrefix = true
-					break
-				}
-			}
+		processPipedInput(parser, exec, term)
+		return
+	}
 
-			if !hasPrefix {
-				// In AI-first mode, treat it as an AI query by default
-				cmd := &nlp.Command{
-					Type:       nlp.CommandTypeAI,
-					Intent:     command,
-					Parameters: make(map[string]string),
-					RawInput:   command,
-				}
-				result, err := exec.Execute(cmd)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
-					os.Exit(1)
-				}
-				term.Display(result)
-				os.Exit(0)
-			}
+	// The first time lumo runs at a terminal, ask whether to keep local
+	// usage metrics; they stay off unless the answer is yes
+	if !cfg.MetricsAsked && !cfg.EnableMetrics && !cfg.Quiet && !cfg.Safe && !isPiped && utils.IsTerminal(os.Stdout) {
+		if err := setup.AskMetricsConsent(cfg, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
+	}
 
-		// Special handling for commands with specific prefixes
-		if strings.HasPrefix(command, "shell:") {
-			// Handle shell commands (ONLY with shell: prefix)
-			intent := strings.TrimSpace(command[6:])
-			cmd := &nlp.Command{
-				Type:       nlp.CommandTypeShell,
-				Intent:     intent,
-				Parameters: make(map[string]string),
-				RawInput:   command,
-			}
-			result, err := exec.Execute(cmd)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
-				os.Exit(1)
-			}
-			term.Display(result)
-		} else if strings.HasPrefix(command, "server:") {
-			// Handle server commands
-			intent := strings.TrimSpace(command[7:])
-			if intent == "start" {
-				// Start the server daemon
-				d := daemon.New(cfg)
-				if err := d.Start(); err != nil {
-					fmt.Fprintf(os.Stderr, "Error starting server daemon: %v\n", err)
-					os.Exit(1)
-				}
-				fmt.Println("Server daemon started")
-			} else if intent == "stop" {
-				// Stop the server daemon
-				d := daemon.New(cfg)
-				if err := d.Stop(); err != nil {
-					fmt.Fprintf(os.Stderr, "Error stopping server daemon: %v\n", err)
-					os.Exit(1)
-				}
-				fmt.Println("Server daemon stopped")
-			} else if intent == "status" {
-				// Check server daemon status
-				d := daemon.New(cfg)
-				running, pid, err := d.Status()
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error checking server daemon status: %v\n", err)
-					os.Exit(1)
-				}
-				if running {
-					fmt.Printf("Server daemon is running with PID %d\n", pid)
-				} else {
-					fmt.Println("Server daemon is not running")
-				}
-			} else {
-				fmt.Fprintf(os.Stderr, "Unknown server command: %s\n", intent)
-				fmt.Println("Available commands: server:start, server:stop, server:status")
-				os.Exit(1)
-			}
-		} else if strings.HasPrefix(command, "lumo:") {
-			// Legacy "lumo:" prefix is now treated as an AI query for safety
-			intent := strings.TrimSpace(command[5:])
-			cmd := &nlp.Command{
+	if len(args) == 0 {
+		// Display welcome message when run without arguments
+		result, err := exec.ShowWelcome()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error displaying welcome message: %v\n", err)
+			os.Exit(1)
+		}
+		term.Display(result)
+		return
+	}
+
+	processArgs(args, parser, exec, term)
+}
+
+// processArgs runs the command given as lumo's arguments
+func processArgs(args []string, parser *nlp.Parser, exec *executor.Executor, term *terminal.Terminal) {
+	cfg := exec.GetConfig()
+
+	// Join arguments with spaces; the shell has already removed the quotes
+	command := strings.Join(args, " ")
+
+	// A question the shell split into words is treated as an AI query in
+	// AI-first mode, unless its intent is obvious enough to handle locally
+	if len(args) > 1 && !cfg.CommandFirstMode && !cli.IsCommand(command) {
+		cmd, routed := parser.Classify(command)
+		if !routed {
+			cmd = &nlp.Command{
 				Type:       nlp.CommandTypeAI,
-				Intent:     intent,
+				Intent:     command,
 				Parameters: make(map[string]string),
 				RawInput:   command,
 			}
-			result, err := exec.Execute(cmd)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
-				os.Exit(1)
-			}
-			term.Display(result)
-		} else {
-			processCommand(command, parser, exec, term)
+		} else if cfg.Debug {
+			fmt.Printf("Handled locally (%s)\n", cmd.Parameters["classifier"])
 		}
-	} else {
-		// Display welcome message when run without arguments
-		result, err := exec.ShowWelcome()
+		result, err := exec.Execute(cmd)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error displaying welcome message: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error executing command: %s\n", lumoerr.Format(err))
+			os.Exit(1)
+		}
+		term.Display(result)
+		return
+	}
+
+	// Special handling for commands with specific prefixes
+	switch {
+	case strings.HasPrefix(command, "shell:"):
+		// Handle shell commands (ONLY with shell: prefix)
+		cmd := &nlp.Command{
+			Type:       nlp.CommandTypeShell,
+			Intent:     strings.TrimSpace(command[6:]),
+			Parameters: make(map[string]string),
+			RawInput:   command,
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error executing command: %s\n", lumoerr.Format(err))
 			os.Exit(1)
 		}
 		term.Display(result)
+	case strings.HasPrefix(command, "server:apikey"):
+		// API keys are managed by the executor, like other commands
+		processCommand(command, parser, exec, term)
+	case strings.HasPrefix(command, "server:"):
+		runServerCommand(cfg, strings.TrimSpace(command[7:]))
+	default:
+		processCommand(command, parser, exec, term)
+	}
+}
+
+// runServerCommand starts, stops, checks, or installs the REST server daemon
+func runServerCommand(cfg *config.Config, command string) {
+	d := daemon.New(cfg)
+	switch command {
+	case "start":
+		if err := d.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting server daemon: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Server daemon started")
+	case "stop":
+		if err := d.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error stopping server daemon: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Server daemon stopped")
+	case "status":
+		running, pid, err := d.Status()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking server daemon status: %v\n", err)
+			os.Exit(1)
+		}
+		if running {
+			fmt.Printf("Server daemon is running with PID %d\n", pid)
+		} else {
+			fmt.Println("Server daemon is not running")
+		}
+	case "install", "uninstall":
+		install := d.Install
+		if command == "uninstall" {
+			install = d.Uninstall
+		}
+		message, err := install()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(message)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown server command: %s\n", command)
+		fmt.Println("Available commands: server:start, server:stop, server:status, server:install, server:uninstall")
+		os.Exit(1)
+	}
+}
+
+// runScript handles "lumo script run [--yes] <file.star> [args...]"
+func runScript(cfg *config.Config, exec *executor.Executor, args []string) {
+	const usage = "Usage: lumo script run [--yes] <file.star> [args...]"
+	if len(args) == 0 || args[0] != "run" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+	args = args[1:]
+	yes := false
+	if len(args) > 0 && (args[0] == "--yes" || args[0] == "-y") {
+		yes = true
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner := script.NewRunner(cfg, exec)
+	runner.SetYes(yes)
+	if err := runner.Run(ctx, args[0], args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// runWidget handles "lumo widget status [--tmux|--json]"
+func runWidget(cfg *config.Config, args []string) {
+	const usage = "Usage: lumo widget status [--tmux|--json]"
+	if len(args) == 0 || args[0] != "status" || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+	format := widget.FormatPlain
+	if len(args) == 2 {
+		format = strings.TrimPrefix(args[1], "--")
+	}
+
+	line, err := widget.Format(widget.Get(cfg, time.Now()), format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n%s\n", err, usage)
+		os.Exit(1)
+	}
+	fmt.Println(line)
+}
+
+// startServer starts the REST server for the length of this run
+func startServer(cfg *config.Config, exec *executor.Executor) {
+	srv := server.New(cfg, exec)
+	if err := srv.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting REST server: %s\n", lumoerr.Format(err))
+		// Continue execution even if server fails to start
+		return
+	}
+
+	// Set up signal handling for graceful shutdown
+	setupSignalHandling(srv)
+
+	// Notify the user that the server is running
+	if !cfg.ServerQuietOutput && !cfg.Quiet {
+		fmt.Fprintf(os.Stderr, "\nNOTE: Lumo REST server is running on port %d\n", cfg.ServerPort)
+		fmt.Fprintf(os.Stderr, "To disable the server, run: lumo config:server disable\n\n")
 	}
 }
 
@@ -285,7 +439,66 @@ func setupSignalHandling(srv *server.Server) {
 	}()
 }
 
-func processPipedInput(exec *executor.Executor, term *terminal.Terminal) {
+// processComposedQuestion answers a question or chat message whose text is
+// read from stdin or written in the editor. It returns false if the command
+// line is not one.
+func processComposedQuestion(parser *nlp.Parser, exec *executor.Executor, term *terminal.Terminal, isPiped bool) bool {
+	command := strings.Join(os.Args[1:], " ")
+	if command == terminal.PromptFromStdin {
+		command = "ask:" + command
+	}
+	cmd, err := parser.Parse(command)
+	if err != nil || (cmd.Type != nlp.CommandTypeAI && cmd.Type != nlp.CommandTypeChat) {
+		return false
+	}
+	source, flags, draft := terminal.ParsePromptSource(cmd.Intent)
+	if source == "" {
+		return false
+	}
+
+	startTime := time.Now()
+	var prompt string
+	if source == terminal.PromptFromStdin {
+		if !isPiped {
+			fmt.Fprintln(os.Stderr, "Type your question, then press Ctrl-D on a new line to send it:")
+		}
+		prompt, err = terminal.ReadPrompt(os.Stdin)
+	} else {
+		if isPiped {
+			fmt.Fprintln(os.Stderr, "Error: --edit needs a terminal; pipe the question with \"lumo -\" instead")
+			os.Exit(1)
+		}
+		prompt, err = terminal.ComposePrompt(draft)
+	}
+	if errors.Is(err, terminal.ErrEmptyPrompt) {
+		fmt.Fprintln(os.Stderr, "No question written; nothing was sent.")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if source == terminal.PromptFromStdin && draft != "" {
+		prompt = draft + "\n\n" + prompt
+	}
+	cmd.Intent = strings.TrimSpace(flags + " " + prompt)
+
+	result, err := exec.Execute(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
+		os.Exit(1)
+	}
+	term.Display(result)
+
+	duration := time.Since(startTime)
+	term.LogCommand(cmd.RawInput, result, duration)
+	if exec.GetConfig().Debug {
+		fmt.Printf("Execution time: %s\n", utils.FormatDuration(duration))
+	}
+	return true
+}
+
+func processPipedInput(parser *nlp.Parser, exec *executor.Executor, term *terminal.Terminal) {
 	// Record start time for performance measurement
 	startTime := time.Now()
 
@@ -329,10 +542,37 @@ func processPipedInput(exec *executor.Executor, term *terminal.Terminal) {
 		return
 	}
 
-	// For non-clipboard commands, process as before
 	// Create a pipe processor
 	pipeProcessor := pipe.NewProcessor(exec.GetAIClient())
 
+	// A question or chat message asks about the piped input, which goes
+	// along as context
+	if len(os.Args) > 1 {
+		cmd, err := parser.Parse(strings.Join(os.Args[1:], " "))
+		if err == nil && (cmd.Type == nlp.CommandTypeAI || cmd.Type == nlp.CommandTypeChat) && strings.TrimSpace(cmd.Intent) != "" {
+			if cmd.Intent, err = pipeProcessor.MergeInput(os.Stdin, cmd.Intent); err != nil {
+				fmt.Fprintf(os.Stderr, "Error processing piped input: %v\n", err)
+				os.Exit(1)
+			}
+
+			result, err := exec.Execute(cmd)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
+				os.Exit(1)
+			}
+			term.Display(result)
+
+			duration := time.Since(startTime)
+			term.LogCommand(cmd.RawInput, result, duration)
+			if exec.GetConfig().Debug {
+				fmt.Printf("Execution time: %s\n", utils.FormatDuration(duration))
+			}
+			return
+		}
+	}
+
+	// Anything else is an explanation of the piped input
+
 	// Process the piped input
 	result, err := pipeProcessor.ProcessInput(os.Stdin)
 	if err != nil {
@@ -384,6 +624,9 @@ func processCommand(input string, parser *nlp.Parser, exec *executor.Executor, t
 		fmt.Fprintf(os.Stderr, "Error parsing command: %v\n", err)
 		return
 	}
+	if source := cmd.Parameters["classifier"]; source != "" && exec.GetConfig().Debug {
+		fmt.Printf("Handled locally (%s)\n", source)
+	}
 
 	// Execute the command
 	result, err := exec.Execute(cmd)
@@ -0,0 +1,301 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/godbus/dbus/v5"
+)
+
+// MPRIS names every media player uses on the session bus
+const (
+	// MPRISServicePrefix starts the bus name of every MPRIS player
+	MPRISServicePrefix = "org.mpris.MediaPlayer2."
+	// MPRISPath is the object path of an MPRIS player
+	MPRISPath = "/org/mpris/MediaPlayer2"
+	// MPRISInterface describes the player application
+	MPRISInterface = "org.mpris.MediaPlayer2"
+	// MPRISPlayerInterface controls playback
+	MPRISPlayerInterface = "org.mpris.MediaPlayer2.Player"
+)
+
+// NowPlaying is what a media player is playing
+type NowPlaying struct {
+	// Player is the player's name, like "spotify" or "vlc"
+	Player string
+	// Status is Playing, Paused, or Stopped
+	Status   string
+	Title    string
+	Artists  []string
+	Album    string
+	Position time.Duration
+	// Length is zero when the player does not know it, as for streams
+	Length time.Duration
+}
+
+// String formats what is playing on one or two lines
+func (n NowPlaying) String() string {
+	symbol := map[string]string{"Playing": "▶", "Paused": "⏸", "Stopped": "⏹"}[n.Status]
+	if symbol == "" {
+		symbol = "♪"
+	}
+
+	title := n.Title
+	if title == "" {
+		title = "Unknown title"
+	}
+	line := fmt.Sprintf("%s %s", symbol, title)
+	if len(n.Artists) > 0 {
+		line += " — " + strings.Join(n.Artists, ", ")
+	}
+	if n.Album != "" {
+		line += fmt.Sprintf(" (%s)", n.Album)
+	}
+
+	position := formatTrackTime(n.Position)
+	if n.Length > 0 {
+		position += " / " + formatTrackTime(n.Length)
+	}
+	return fmt.Sprintf("%s\n  %s on %s", line, position, n.Player)
+}
+
+// formatTrackTime formats a position in a track like 3:05 or 1:02:03
+func formatTrackTime(d time.Duration) string {
+	seconds := int(d.Round(time.Second) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// PlayerName returns a player's name from its bus name, e.g. "vlc" for
+// org.mpris.MediaPlayer2.vlc.instance4321
+func PlayerName(service string) string {
+	name := strings.TrimPrefix(service, MPRISServicePrefix)
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// MediaPlayers returns the MPRIS players among bus names
+func MediaPlayers(services []string) []string {
+	var players []string
+	for _, service := range services {
+		if strings.HasPrefix(service, MPRISServicePrefix) {
+			players = append(players, service)
+		}
+	}
+	return players
+}
+
+// ChoosePlayer picks the player media commands control: the preferred
+// player when it is running, otherwise the first one playing, otherwise the
+// first one
+func ChoosePlayer(handler core.DBusHandler, players []string, preferred string) (string, error) {
+	if len(players) == 0 {
+		return "", fmt.Errorf("no active media player found")
+	}
+	if preferred != "" {
+		for _, player := range players {
+			if strings.EqualFold(PlayerName(player), preferred) {
+				return player, nil
+			}
+		}
+	}
+	for _, player := range players {
+		if status, err := handler.GetProperty(player, MPRISPath, MPRISPlayerInterface, "PlaybackStatus"); err == nil && status == "Playing" {
+			return player, nil
+		}
+	}
+	return players[0], nil
+}
+
+// ReadNowPlaying asks a player what it is playing
+func ReadNowPlaying(handler core.DBusHandler, player string) (*NowPlaying, error) {
+	value, err := handler.GetProperty(player, MPRISPath, MPRISPlayerInterface, "Metadata")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read what %s is playing: %w", PlayerName(player), err)
+	}
+	metadata, _ := value.(map[string]dbus.Variant)
+
+	nowPlaying := &NowPlaying{Player: PlayerName(player)}
+	if identity, err := handler.GetProperty(player, MPRISPath, MPRISInterface, "Identity"); err == nil {
+		if name, ok := identity.(string); ok && name != "" {
+			nowPlaying.Player = name
+		}
+	}
+	if status, err := handler.GetProperty(player, MPRISPath, MPRISPlayerInterface, "PlaybackStatus"); err == nil {
+		nowPlaying.Status, _ = status.(string)
+	}
+	if position, err := handler.GetProperty(player, MPRISPath, MPRISPlayerInterface, "Position"); err == nil {
+		nowPlaying.Position = microseconds(position)
+	}
+
+	nowPlaying.Title, _ = metadata["xesam:title"].Value().(string)
+	nowPlaying.Album, _ = metadata["xesam:album"].Value().(string)
+	nowPlaying.Artists, _ = metadata["xesam:artist"].Value().([]string)
+	nowPlaying.Length = microseconds(metadata["mpris:length"].Value())
+	return nowPlaying, nil
+}
+
+// microseconds converts an MPRIS time, which players send as a signed or
+// unsigned integer of microseconds
+func microseconds(value interface{}) time.Duration {
+	switch v := value.(type) {
+	case int64:
+		return time.Duration(v) * time.Microsecond
+	case uint64:
+		return time.Duration(v) * time.Microsecond
+	case int32:
+		return time.Duration(v) * time.Microsecond
+	case uint32:
+		return time.Duration(v) * time.Microsecond
+	}
+	return 0
+}
+
+// seekPattern matches seek offsets such as 30, -10, +1:30, 45s, or 2m
+var seekPattern = regexp.MustCompile(`^([+-]?)(?:(\d+):(\d{1,2})|(\d+)\s*(s|sec|secs|seconds?|m|min|mins|minutes?)?)$`)
+
+// ParseSeekOffset parses how far to move in a track. Plain numbers are
+// seconds, and negative offsets go back.
+func ParseSeekOffset(target string) (time.Duration, error) {
+	match := seekPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(target)))
+	if match == nil {
+		return 0, fmt.Errorf("invalid seek offset: %q (use seconds, e.g. 30 or -10)", target)
+	}
+
+	var offset time.Duration
+	if match[2] != "" {
+		minutes, _ := strconv.Atoi(match[2])
+		seconds, _ := strconv.Atoi(match[3])
+		offset = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else {
+		amount, _ := strconv.Atoi(match[4])
+		offset = time.Duration(amount) * time.Second
+		if strings.HasPrefix(match[5], "m") {
+			offset = time.Duration(amount) * time.Minute
+		}
+	}
+	if offset == 0 {
+		return 0, fmt.Errorf("invalid seek offset: %q (use seconds, e.g. 30 or -10)", target)
+	}
+	if match[1] == "-" {
+		offset = -offset
+	}
+	return offset, nil
+}
+
+// Seek moves a player's position by offset, and returns the new position,
+// or zero if the player does not report it
+func Seek(handler core.DBusHandler, player string, offset time.Duration) (time.Duration, error) {
+	if canSeek, err := handler.GetProperty(player, MPRISPath, MPRISPlayerInterface, "CanSeek"); err == nil && canSeek == any(false) {
+		return 0, fmt.Errorf("%s cannot seek in what it is playing", PlayerName(player))
+	}
+	if _, err := handler.Call(player, MPRISPath, MPRISPlayerInterface, "Seek", offset.Microseconds()); err != nil {
+		return 0, fmt.Errorf("failed to seek: %w", err)
+	}
+	position, err := handler.GetProperty(player, MPRISPath, MPRISPlayerInterface, "Position")
+	if err != nil {
+		return 0, nil
+	}
+	return microseconds(position), nil
+}
+
+// SeekResult describes a seek for the user
+func SeekResult(offset, position time.Duration) string {
+	direction := "Skipped ahead"
+	if offset < 0 {
+		direction, offset = "Went back", -offset
+	}
+	seconds := int(offset.Round(time.Second) / time.Second)
+	unit := "seconds"
+	if seconds == 1 {
+		unit = "second"
+	}
+	result := fmt.Sprintf("%s %d %s", direction, seconds, unit)
+	if position > 0 {
+		result += fmt.Sprintf(" (now at %s)", formatTrackTime(position))
+	}
+	return result
+}
+
+// preferredPlayerPath returns the file holding the player chosen with
+// set-player
+func preferredPlayerPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "media_player"), nil
+}
+
+// PreferredPlayer returns the name of the player chosen with set-player,
+// or "" to control whichever is playing
+func PreferredPlayer() string {
+	path, err := preferredPlayerPath()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetPreferredPlayer makes media commands control the named player while
+// it runs; "" or "auto" goes back to whichever is playing
+func SetPreferredPlayer(name string) error {
+	path, err := preferredPlayerPath()
+	if err != nil {
+		return err
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "auto" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to forget the media player: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to save the media player: %w", err)
+	}
+	return nil
+}
+
+// SetPlayerResult chooses the player media commands control and describes
+// the choice, noting when the player is not running
+func SetPlayerResult(players []string, name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if err := SetPreferredPlayer(name); err != nil {
+		return "", err
+	}
+	if name == "" || name == "auto" {
+		return "Media commands control whichever player is playing", nil
+	}
+
+	var running []string
+	for _, player := range players {
+		if strings.EqualFold(PlayerName(player), name) {
+			return fmt.Sprintf("Media commands now control %s", name), nil
+		}
+		running = append(running, PlayerName(player))
+	}
+	message := fmt.Sprintf("Media commands will control %s once it is running", name)
+	if len(running) > 0 {
+		message += fmt.Sprintf(" (running now: %s)", strings.Join(running, ", "))
+	}
+	return message, nil
+}
@@ -0,0 +1,303 @@
+package common
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/godbus/dbus/v5"
+)
+
+// RecordNotificationsArg is the hidden lumo command that records the
+// notifications sent while do not disturb is on
+const RecordNotificationsArg = "__record-notifications"
+
+// MaxMissedNotifications is how many missed notifications are kept
+const MaxMissedNotifications = 200
+
+// notifyMatch matches the calls that show a notification
+const notifyMatch = "type='method_call',interface='org.freedesktop.Notifications',member='Notify'"
+
+// dndCheckInterval is how often the recorder checks that do not disturb is
+// still on
+const dndCheckInterval = 10 * time.Second
+
+// MissedNotification is a notification that arrived while do not disturb
+// was on
+type MissedNotification struct {
+	Time    time.Time `json:"time"`
+	App     string    `json:"app,omitempty"`
+	Summary string    `json:"summary"`
+	Body    string    `json:"body,omitempty"`
+}
+
+// String formats a missed notification on one line
+func (n MissedNotification) String() string {
+	line := n.Time.Local().Format("15:04")
+	if n.App != "" {
+		line += " " + n.App + ":"
+	}
+	line += " " + n.Summary
+	if body := strings.Join(strings.Fields(n.Body), " "); body != "" {
+		line += " — " + body
+	}
+	return line
+}
+
+// DNDSession is a do not disturb session started with enable-dnd
+type DNDSession struct {
+	Since time.Time `json:"since"`
+	// Recording is set when notifications are being recorded
+	Recording bool `json:"recording"`
+}
+
+// stateFile returns a file in lumo's state directory
+func stateFile(name string) (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// LoadDNDSession returns the running do not disturb session, or nil if
+// there is none
+func LoadDNDSession() (*DNDSession, error) {
+	path, err := stateFile("dnd.json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read do not disturb state: %w", err)
+	}
+
+	var session DNDSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse do not disturb state: %w", err)
+	}
+	return &session, nil
+}
+
+// SaveDNDSession saves the running do not disturb session
+func SaveDNDSession(session *DNDSession) error {
+	path, err := stateFile("dnd.json")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode do not disturb state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write do not disturb state: %w", err)
+	}
+	return nil
+}
+
+// EndDNDSession removes the do not disturb session. Its recorder stops
+// recording at once and exits at its next check.
+func EndDNDSession() error {
+	path, err := stateFile("dnd.json")
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove do not disturb state: %w", err)
+	}
+	return nil
+}
+
+// StartNotificationRecorder starts a lumo process in the background that
+// records notifications until the do not disturb session ends
+func StartNotificationRecorder() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate lumo executable: %w", err)
+	}
+	recorder := exec.Command("nohup", executable, RecordNotificationsArg)
+	if err := recorder.Start(); err != nil {
+		return fmt.Errorf("failed to start notification recorder: %w", err)
+	}
+	return recorder.Process.Release()
+}
+
+// RecordNotifications watches the session bus for notifications and keeps
+// them as missed until ctx is done or the do not disturb session ends
+func RecordNotifications(ctx context.Context) error {
+	conn, err := dbus.SessionBusPrivate()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the session bus: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.Auth(nil); err != nil {
+		return fmt.Errorf("failed to authenticate on the session bus: %w", err)
+	}
+	if err := conn.Hello(); err != nil {
+		return fmt.Errorf("failed to register on the session bus: %w", err)
+	}
+
+	// A monitor receives copies of the calls it matches, but can no longer
+	// make calls of its own
+	call := conn.BusObject().Call("org.freedesktop.DBus.Monitoring.BecomeMonitor", 0, []string{notifyMatch}, uint32(0))
+	if call.Err != nil {
+		return fmt.Errorf("failed to watch notifications: %w", call.Err)
+	}
+	messages := make(chan *dbus.Message, 16)
+	conn.Eavesdrop(messages)
+
+	ticker := time.NewTicker(dndCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if session, err := LoadDNDSession(); err != nil || session == nil {
+				return err
+			}
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			notification, ok := NotificationFromCall(message.Body)
+			if !ok {
+				continue
+			}
+			if session, err := LoadDNDSession(); err != nil || session == nil {
+				return err
+			}
+			if err := AddMissedNotification(notification); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+}
+
+// NotificationFromCall reads a notification from the arguments of a
+// Notify call: app name, replaced ID, icon, summary, body, actions, hints,
+// and timeout
+func NotificationFromCall(args []interface{}) (MissedNotification, bool) {
+	if len(args) < 5 {
+		return MissedNotification{}, false
+	}
+	app, _ := args[0].(string)
+	summary, ok := args[3].(string)
+	if !ok || summary == "" {
+		return MissedNotification{}, false
+	}
+	body, _ := args[4].(string)
+	return MissedNotification{Time: time.Now(), App: app, Summary: summary, Body: body}, true
+}
+
+// AddMissedNotification keeps a notification as missed
+func AddMissedNotification(notification MissedNotification) error {
+	path, err := stateFile("missed_notifications.jsonl")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open missed notifications: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write missed notifications: %w", err)
+	}
+	return file.Close()
+}
+
+// LoadMissedNotifications returns the missed notifications that arrived at
+// or after since, oldest first
+func LoadMissedNotifications(since time.Time) ([]MissedNotification, error) {
+	path, err := stateFile("missed_notifications.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open missed notifications: %w", err)
+	}
+	defer file.Close()
+
+	var notifications []MissedNotification
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var notification MissedNotification
+		if err := json.Unmarshal(scanner.Bytes(), &notification); err != nil {
+			continue
+		}
+		if !notification.Time.Before(since) {
+			notifications = append(notifications, notification)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read missed notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// TrimMissedNotifications drops all but the newest MaxMissedNotifications
+func TrimMissedNotifications() error {
+	notifications, err := LoadMissedNotifications(time.Time{})
+	if err != nil || len(notifications) <= MaxMissedNotifications {
+		return err
+	}
+	path, err := stateFile("missed_notifications.jsonl")
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, notification := range notifications[len(notifications)-MaxMissedNotifications:] {
+		data, err := json.Marshal(notification)
+		if err != nil {
+			return err
+		}
+		b.Write(append(data, '\n'))
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write missed notifications: %w", err)
+	}
+	return nil
+}
+
+// FormatMissedNotifications lists missed notifications, newest last
+func FormatMissedNotifications(notifications []MissedNotification) string {
+	if len(notifications) == 0 {
+		return "No missed notifications"
+	}
+	noun := "notifications"
+	if len(notifications) == 1 {
+		noun = "notification"
+	}
+	lines := []string{fmt.Sprintf("%d missed %s:", len(notifications), noun)}
+	for _, notification := range notifications {
+		lines = append(lines, "  "+notification.String())
+	}
+	return strings.Join(lines, "\n")
+}
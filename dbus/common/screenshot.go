@@ -0,0 +1,136 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// screenshotTool is a command-line screenshot program
+type screenshotTool struct {
+	name string
+	// wayland and x11 are the sessions the tool works in
+	wayland, x11 bool
+	// args returns the arguments to capture mode to path, or nil if the
+	// tool cannot capture it
+	args func(ctx context.Context, mode core.ScreenshotMode, path string) ([]string, error)
+}
+
+// screenshotTools are tried in order when the desktop's own screenshot
+// service is unavailable
+var screenshotTools = []screenshotTool{
+	{name: "gnome-screenshot", wayland: true, x11: true, args: func(_ context.Context, mode core.ScreenshotMode, path string) ([]string, error) {
+		switch mode {
+		case core.ScreenshotWindow:
+			return []string{"-w", "-f", path}, nil
+		case core.ScreenshotArea:
+			return []string{"-a", "-f", path}, nil
+		}
+		return []string{"-f", path}, nil
+	}},
+	{name: "spectacle", wayland: true, x11: true, args: func(_ context.Context, mode core.ScreenshotMode, path string) ([]string, error) {
+		switch mode {
+		case core.ScreenshotWindow:
+			return []string{"-b", "-n", "-a", "-o", path}, nil
+		case core.ScreenshotArea:
+			return []string{"-b", "-n", "-r", "-o", path}, nil
+		}
+		return []string{"-b", "-n", "-f", "-o", path}, nil
+	}},
+	{name: "grim", wayland: true, args: func(ctx context.Context, mode core.ScreenshotMode, path string) ([]string, error) {
+		switch mode {
+		case core.ScreenshotWindow:
+			return nil, nil
+		case core.ScreenshotArea:
+			// grim captures a geometry, which slurp lets the user select
+			geometry, err := exec.CommandContext(ctx, "slurp").Output()
+			if err != nil {
+				return nil, fmt.Errorf("failed to select an area with slurp: %w", err)
+			}
+			return []string{"-g", strings.TrimSpace(string(geometry)), path}, nil
+		}
+		return []string{path}, nil
+	}},
+	{name: "scrot", x11: true, args: func(_ context.Context, mode core.ScreenshotMode, path string) ([]string, error) {
+		switch mode {
+		case core.ScreenshotWindow:
+			return []string{"-u", path}, nil
+		case core.ScreenshotArea:
+			return []string{"-s", path}, nil
+		}
+		return []string{path}, nil
+	}},
+	{name: "import", x11: true, args: func(_ context.Context, mode core.ScreenshotMode, path string) ([]string, error) {
+		switch mode {
+		case core.ScreenshotWindow:
+			return nil, nil
+		case core.ScreenshotArea:
+			return []string{path}, nil
+		}
+		return []string{"-window", "root", path}, nil
+	}},
+}
+
+// IsWayland reports whether the session is a Wayland session
+func IsWayland() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != "" || strings.EqualFold(os.Getenv("XDG_SESSION_TYPE"), "wayland")
+}
+
+// ScreenshotFile returns a new file name for a screenshot in ~/Pictures,
+// creating the directory if needed
+func ScreenshotFile() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	dir := filepath.Join(homeDir, "Pictures")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	return filepath.Join(dir, fmt.Sprintf("screenshot-%s.png", time.Now().Format("20060102-150405"))), nil
+}
+
+// ScreenshotWithTool takes a screenshot with the first installed
+// command-line tool that works in this session and can capture mode
+func ScreenshotWithTool(ctx context.Context, mode core.ScreenshotMode, path string) error {
+	wayland := IsWayland()
+	var tried []string
+	for _, tool := range screenshotTools {
+		if (wayland && !tool.wayland) || (!wayland && !tool.x11) {
+			continue
+		}
+		if _, err := exec.LookPath(tool.name); err != nil {
+			continue
+		}
+		args, err := tool.args(ctx, mode, path)
+		if err != nil {
+			return err
+		}
+		if args == nil {
+			continue
+		}
+
+		tried = append(tried, tool.name)
+		if err := exec.CommandContext(ctx, tool.name, args...).Run(); err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+	}
+
+	session := "X11"
+	if wayland {
+		session = "Wayland"
+	}
+	if len(tried) == 0 {
+		return fmt.Errorf("no screenshot tool for a %s %s screenshot found; install gnome-screenshot, or grim and slurp on Wayland, or scrot on X11", session, mode)
+	}
+	return fmt.Errorf("screenshot failed with %s", strings.Join(tried, ", "))
+}
@@ -123,6 +123,8 @@ func (e *Environment) executeAppearanceCommand(ctx context.Context, cmd *core.Co
 				"background": background,
 			},
 		}, nil
+	case "set-night-light", "set-color-temperature", "get-night-light":
+		return e.executeNightLightCommand(ctx, cmd)
 	case "get-icon-theme":
 		theme, err := e.GetCurrentIconTheme(ctx)
 		if err != nil {
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/agnath18K/lumo/internal/core"
@@ -93,6 +96,155 @@ func (e *Environment) executeConnectivityCommand(ctx context.Context, cmd *core.
 				"enabled": enabled,
 			},
 		}, nil
+	case "list-networks":
+		networks, err := e.ListWifiNetworks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(networks) == 0 {
+			return &core.Result{
+				Output:  "No WiFi networks found",
+				Success: true,
+			}, nil
+		}
+		width := 0
+		for _, network := range networks {
+			width = max(width, len([]rune(network.SSID)))
+		}
+		var output strings.Builder
+		output.WriteString("WiFi networks:\n")
+		for _, network := range networks {
+			marker := " "
+			if network.Active {
+				marker = "*"
+			}
+			security := network.Security
+			if security == "" {
+				security = "open"
+			}
+			output.WriteString(fmt.Sprintf("%s %-*s  %s %3d%%  %s\n", marker, width, network.SSID, SignalBars(network.Signal), network.Signal, security))
+		}
+		return &core.Result{
+			Output:  output.String(),
+			Success: true,
+			Data: map[string]interface{}{
+				"networks": networks,
+			},
+		}, nil
+	case "connect-network":
+		ssid := cmd.Target
+		password := ""
+		if passwordVal, ok := cmd.Arguments["password"]; ok {
+			if passwordStr, ok := passwordVal.(string); ok {
+				password = passwordStr
+			}
+		}
+		if ssid == "" {
+			return nil, fmt.Errorf("SSID is required")
+		}
+		if err := e.ConnectWifiNetwork(ctx, ssid, password); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Connected to WiFi network: %s", ssid),
+			Success: true,
+		}, nil
+	case "forget-network":
+		if cmd.Target == "" {
+			return nil, fmt.Errorf("SSID is required")
+		}
+		if err := e.ForgetWifiNetwork(ctx, cmd.Target); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Forgot WiFi network: %s", cmd.Target),
+			Success: true,
+		}, nil
+	case "enable-vpn":
+		connections, err := e.ListVPNConnections(ctx)
+		if err != nil {
+			return nil, err
+		}
+		vpn, err := MatchVPNConnection(connections, cmd.Target)
+		if err != nil {
+			return nil, err
+		}
+		if !vpn.Active {
+			if err := e.EnableVPN(ctx, vpn.Name); err != nil {
+				return nil, err
+			}
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("VPN connected: %s", vpn.Name),
+			Success: true,
+		}, nil
+	case "disable-vpn":
+		connections, err := e.ListVPNConnections(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Without a name, every connected VPN is disconnected
+		var targets []core.VPNConnection
+		if cmd.Target != "" {
+			vpn, err := MatchVPNConnection(connections, cmd.Target)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, vpn)
+		} else {
+			for _, vpn := range connections {
+				if vpn.Active {
+					targets = append(targets, vpn)
+				}
+			}
+		}
+		var names []string
+		for _, vpn := range targets {
+			if !vpn.Active {
+				continue
+			}
+			if err := e.DisableVPN(ctx, vpn.Name); err != nil {
+				return nil, err
+			}
+			names = append(names, vpn.Name)
+		}
+		if len(names) == 0 {
+			return &core.Result{
+				Output:  "No VPN is connected",
+				Success: true,
+			}, nil
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("VPN disconnected: %s", strings.Join(names, ", ")),
+			Success: true,
+		}, nil
+	case "vpn-status":
+		connections, err := e.ListVPNConnections(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var active, saved []string
+		for _, vpn := range connections {
+			saved = append(saved, vpn.Name)
+			if vpn.Active {
+				active = append(active, vpn.Name)
+			}
+		}
+		output := "No VPN connections are set up"
+		switch {
+		case len(active) > 0:
+			output = fmt.Sprintf("VPN is connected: %s", strings.Join(active, ", "))
+		case len(saved) > 0:
+			output = fmt.Sprintf("VPN is disconnected. Saved VPNs: %s", strings.Join(saved, ", "))
+		}
+		return &core.Result{
+			Output:  output,
+			Success: true,
+			Data: map[string]interface{}{
+				"enabled":     len(active) > 0,
+				"connections": connections,
+			},
+		}, nil
 	case "enable-bluetooth":
 		if err := e.EnableBluetooth(ctx); err != nil {
 			return nil, err
@@ -322,6 +474,242 @@ func (e *Environment) GetWifiStatus(ctx context.Context) (bool, error) {
 	return enabled, nil
 }
 
+// ListWifiNetworks lists the Wi-Fi networks in range, strongest first
+func (e *Environment) ListWifiNetworks(ctx context.Context) ([]core.WifiNetwork, error) {
+	output, err := runNmcli(ctx, "-t", "-f", "IN-USE,SSID,SIGNAL,SECURITY", "device", "wifi", "list", "--rescan", "auto")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list WiFi networks: %w", err)
+	}
+	return ParseWifiNetworks(output), nil
+}
+
+// ConnectWifiNetwork connects to a Wi-Fi network. NetworkManager saves the
+// connection, so later connections need no password.
+func (e *Environment) ConnectWifiNetwork(ctx context.Context, ssid, password string) error {
+	args := []string{"device", "wifi", "connect", ssid}
+	if password != "" {
+		args = append(args, "password", password)
+	}
+	if _, err := runNmcli(ctx, args...); err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", ssid, err)
+	}
+	return nil
+}
+
+// ForgetWifiNetwork deletes the saved connections to a Wi-Fi network
+func (e *Environment) ForgetWifiNetwork(ctx context.Context, ssid string) error {
+	output, err := runNmcli(ctx, "-t", "-f", "NAME,TYPE", "connection", "show")
+	if err != nil {
+		return fmt.Errorf("failed to list saved connections: %w", err)
+	}
+
+	// NetworkManager names a connection after its SSID, adding a number
+	// when the name is taken
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitNmcliFields(line)
+		if len(fields) < 2 || fields[1] != "802-11-wireless" {
+			continue
+		}
+		name := fields[0]
+		suffix, found := strings.CutPrefix(name, ssid+" ")
+		if _, numErr := strconv.Atoi(suffix); name == ssid || (found && numErr == nil) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no saved WiFi network named %s", ssid)
+	}
+
+	for _, name := range names {
+		if _, err := runNmcli(ctx, "connection", "delete", "id", name); err != nil {
+			return fmt.Errorf("failed to forget %s: %w", ssid, err)
+		}
+	}
+	return nil
+}
+
+// vpnConnectionTypes are the NetworkManager connection types that are VPNs
+var vpnConnectionTypes = []string{"vpn", "wireguard"}
+
+// ListVPNConnections lists the VPN connections saved in NetworkManager
+func (e *Environment) ListVPNConnections(ctx context.Context) ([]core.VPNConnection, error) {
+	output, err := runNmcli(ctx, "-t", "-f", "NAME,TYPE,ACTIVE", "connection", "show")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VPN connections: %w", err)
+	}
+	return ParseVPNConnections(output), nil
+}
+
+// EnableVPN activates a VPN connection
+func (e *Environment) EnableVPN(ctx context.Context, name string) error {
+	if _, err := runNmcli(ctx, "connection", "up", "id", name); err != nil {
+		return fmt.Errorf("failed to connect VPN %s: %w", name, err)
+	}
+	return nil
+}
+
+// DisableVPN deactivates a VPN connection
+func (e *Environment) DisableVPN(ctx context.Context, name string) error {
+	if _, err := runNmcli(ctx, "connection", "down", "id", name); err != nil {
+		return fmt.Errorf("failed to disconnect VPN %s: %w", name, err)
+	}
+	return nil
+}
+
+// ParseVPNConnections parses the output of
+// nmcli -t -f NAME,TYPE,ACTIVE connection show, keeping the VPNs
+func ParseVPNConnections(output string) []core.VPNConnection {
+	var connections []core.VPNConnection
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitNmcliFields(line)
+		if len(fields) < 3 || !slices.Contains(vpnConnectionTypes, fields[1]) {
+			continue
+		}
+		connections = append(connections, core.VPNConnection{
+			Name:   fields[0],
+			Type:   fields[1],
+			Active: fields[2] == "yes",
+		})
+	}
+	return connections
+}
+
+// MatchVPNConnection finds the VPN a user means by name: an exact match
+// ignoring case, else the only one whose name contains it, as "work" for
+// "Work VPN". With no name, the only saved VPN is used.
+func MatchVPNConnection(connections []core.VPNConnection, name string) (core.VPNConnection, error) {
+	if len(connections) == 0 {
+		return core.VPNConnection{}, fmt.Errorf("no VPN connections are set up in NetworkManager")
+	}
+	var names []string
+	for _, vpn := range connections {
+		names = append(names, vpn.Name)
+	}
+	if name == "" {
+		if len(connections) == 1 {
+			return connections[0], nil
+		}
+		return core.VPNConnection{}, fmt.Errorf("which VPN? Saved VPNs: %s", strings.Join(names, ", "))
+	}
+
+	var partial []core.VPNConnection
+	for _, vpn := range connections {
+		if strings.EqualFold(vpn.Name, name) {
+			return vpn, nil
+		}
+		if strings.Contains(strings.ToLower(vpn.Name), strings.ToLower(name)) {
+			partial = append(partial, vpn)
+		}
+	}
+	switch len(partial) {
+	case 1:
+		return partial[0], nil
+	case 0:
+		return core.VPNConnection{}, fmt.Errorf("no VPN named %s. Saved VPNs: %s", name, strings.Join(names, ", "))
+	}
+	var matches []string
+	for _, vpn := range partial {
+		matches = append(matches, vpn.Name)
+	}
+	return core.VPNConnection{}, fmt.Errorf("%s matches several VPNs: %s", name, strings.Join(matches, ", "))
+}
+
+// ParseWifiNetworks parses the output of
+// nmcli -t -f IN-USE,SSID,SIGNAL,SECURITY device wifi list. Hidden networks
+// are left out, and a network seen from several access points is listed
+// once, with its strongest signal.
+func ParseWifiNetworks(output string) []core.WifiNetwork {
+	bySSID := make(map[string]int)
+	var networks []core.WifiNetwork
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitNmcliFields(line)
+		if len(fields) < 4 || fields[1] == "" {
+			continue
+		}
+		signal, _ := strconv.Atoi(fields[2])
+		network := core.WifiNetwork{
+			SSID:     fields[1],
+			Signal:   signal,
+			Security: strings.TrimSpace(fields[3]),
+			Active:   fields[0] == "*",
+		}
+		if i, ok := bySSID[network.SSID]; ok {
+			networks[i].Active = networks[i].Active || network.Active
+			networks[i].Signal = max(networks[i].Signal, network.Signal)
+			continue
+		}
+		bySSID[network.SSID] = len(networks)
+		networks = append(networks, network)
+	}
+
+	sort.SliceStable(networks, func(i, j int) bool {
+		if networks[i].Active != networks[j].Active {
+			return networks[i].Active
+		}
+		return networks[i].Signal > networks[j].Signal
+	})
+	return networks
+}
+
+// SignalBars draws a signal strength (0-100) as four bars
+func SignalBars(signal int) string {
+	bars := []rune("▂▄▆█")
+	lit := (signal + 12) / 25
+	var b strings.Builder
+	for i, bar := range bars {
+		if i < lit {
+			b.WriteRune(bar)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// splitNmcliFields splits a line of nmcli terse output, in which colons
+// and backslashes within a field are escaped with a backslash
+func splitNmcliFields(line string) []string {
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return nil
+	}
+	var fields []string
+	var field strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(r)
+		}
+	}
+	return append(fields, field.String())
+}
+
+// runNmcli runs nmcli with args, without a shell so SSIDs and passwords
+// need no quoting. Errors carry nmcli's own message.
+func runNmcli(ctx context.Context, args ...string) (string, error) {
+	if _, err := exec.LookPath("nmcli"); err != nil {
+		return "", fmt.Errorf("nmcli not found")
+	}
+	output, err := exec.CommandContext(ctx, "nmcli", args...).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return "", fmt.Errorf("%s", strings.TrimPrefix(message, "Error: "))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
 // EnableBluetooth enables Bluetooth
 func (e *Environment) EnableBluetooth(ctx context.Context) error {
 	// Try using rfkill
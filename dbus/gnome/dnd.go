@@ -0,0 +1,117 @@
+package gnome
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agnath18K/lumo/dbus/common"
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// enableDND hides notification banners and records the notifications that
+// arrive, so they can be reviewed once do not disturb is off
+func (e *Environment) enableDND(ctx context.Context) (*core.Result, error) {
+	session, err := common.LoadDNDSession()
+	if err != nil {
+		return nil, err
+	}
+	if session != nil {
+		if err := e.SetDoNotDisturb(ctx, true); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Do not disturb is already on, since %s", session.Since.Format("15:04")),
+			Success: true,
+		}, nil
+	}
+
+	if err := e.SetDoNotDisturb(ctx, true); err != nil {
+		return nil, err
+	}
+	if err := common.TrimMissedNotifications(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	session = &common.DNDSession{Since: time.Now(), Recording: true}
+	if err := common.SaveDNDSession(session); err != nil {
+		return nil, err
+	}
+	output := "Do not disturb on. Notifications that arrive are kept; review them with desktop:notification:list-notifications"
+	if err := common.StartNotificationRecorder(); err != nil {
+		session.Recording = false
+		if err := common.SaveDNDSession(session); err != nil {
+			return nil, err
+		}
+		output = fmt.Sprintf("Do not disturb on\nWarning: notifications that arrive will not be kept (%v)", err)
+	}
+
+	return &core.Result{
+		Output:  output,
+		Success: true,
+		Data: map[string]interface{}{
+			"since": session.Since,
+		},
+	}, nil
+}
+
+// disableDND shows notification banners again and lists what arrived
+// while they were hidden
+func (e *Environment) disableDND(ctx context.Context) (*core.Result, error) {
+	if err := e.SetDoNotDisturb(ctx, false); err != nil {
+		return nil, err
+	}
+	session, err := common.LoadDNDSession()
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return &core.Result{
+			Output:  "Do not disturb off",
+			Success: true,
+		}, nil
+	}
+	if err := common.EndDNDSession(); err != nil {
+		return nil, err
+	}
+
+	output := fmt.Sprintf("Do not disturb off after %s", time.Since(session.Since).Round(time.Second))
+	if session.Recording {
+		missed, err := common.LoadMissedNotifications(session.Since)
+		if err != nil {
+			return nil, err
+		}
+		output += "\n" + common.FormatMissedNotifications(missed)
+	}
+	return &core.Result{
+		Output:  output,
+		Success: true,
+	}, nil
+}
+
+// listedNotifications is how many of the newest missed notifications
+// list-notifications shows
+const listedNotifications = 20
+
+// listMissedNotifications lists the newest notifications that arrived while
+// do not disturb was on
+func listMissedNotifications() (*core.Result, error) {
+	missed, err := common.LoadMissedNotifications(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if len(missed) > listedNotifications {
+		missed = missed[len(missed)-listedNotifications:]
+	}
+	output := common.FormatMissedNotifications(missed)
+	if session, err := common.LoadDNDSession(); err == nil && session != nil {
+		output = fmt.Sprintf("Do not disturb is on, since %s\n%s", session.Since.Format("15:04"), output)
+	}
+	return &core.Result{
+		Output:  output,
+		Success: true,
+		Data: map[string]interface{}{
+			"notifications": missed,
+		},
+	}, nil
+}
@@ -0,0 +1,293 @@
+package gnome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// DefaultFocusDuration is used when focus mode is turned on without a duration
+const DefaultFocusDuration = time.Hour
+
+// focusRestoreUnit is the systemd user unit that ends a focus session
+const focusRestoreUnit = "lumo-focus-restore"
+
+// focusState records what focus mode changed so it can be undone
+type focusState struct {
+	// ID identifies the session so a stale restore timer cannot end a newer one
+	ID            int64     `json:"id"`
+	Until         time.Time `json:"until"`
+	PreviousDND   bool      `json:"previous_dnd"`
+	PausedPlayers []string  `json:"paused_players,omitempty"`
+}
+
+// executeFocusCommand executes a focus mode command
+func (e *Environment) executeFocusCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Action {
+	case "on":
+		duration := DefaultFocusDuration
+		if cmd.Target != "" {
+			parsed, err := time.ParseDuration(cmd.Target)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid focus duration: %s (use e.g. 25m or 1h30m)", cmd.Target)
+			}
+			duration = parsed
+		}
+
+		pauseMedia := false
+		if val, ok := cmd.Arguments["pause_media"]; ok {
+			switch v := val.(type) {
+			case bool:
+				pauseMedia = v
+			case string:
+				pauseMedia, _ = strconv.ParseBool(v)
+			}
+		}
+
+		return e.startFocus(ctx, duration, pauseMedia)
+	case "off":
+		return e.endFocus(ctx, cmd.Target)
+	case "status":
+		state, err := loadFocusState()
+		if err != nil {
+			return nil, err
+		}
+		if state == nil {
+			return &core.Result{
+				Output:  "Focus mode is off",
+				Success: true,
+			}, nil
+		}
+
+		return &core.Result{
+			Output:  fmt.Sprintf("Focus mode is on until %s", state.Until.Format("15:04")),
+			Success: true,
+			Data: map[string]interface{}{
+				"until": state.Until,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported focus action: %s", cmd.Action)
+	}
+}
+
+// startFocus enables do not disturb and schedules its restoration
+func (e *Environment) startFocus(ctx context.Context, duration time.Duration, pauseMedia bool) (*core.Result, error) {
+	existing, err := loadFocusState()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	state := &focusState{
+		ID:    now.Unix(),
+		Until: now.Add(duration),
+	}
+
+	if existing != nil {
+		// Extending a running session keeps the original state to restore
+		state.PreviousDND = existing.PreviousDND
+		state.PausedPlayers = existing.PausedPlayers
+	} else {
+		previous, err := e.GetDoNotDisturb(ctx)
+		if err != nil {
+			return nil, err
+		}
+		state.PreviousDND = previous
+	}
+
+	if err := e.SetDoNotDisturb(ctx, true); err != nil {
+		return nil, err
+	}
+
+	if pauseMedia {
+		paused, err := e.pausePlayingMedia()
+		if err != nil {
+			fmt.Printf("Warning: Failed to pause media: %v\n", err)
+		}
+		state.PausedPlayers = append(state.PausedPlayers, paused...)
+	}
+
+	if err := saveFocusState(state); err != nil {
+		return nil, err
+	}
+
+	output := fmt.Sprintf("Focus mode on until %s", state.Until.Format("15:04"))
+	if err := scheduleFocusRestore(state.ID, duration); err != nil {
+		output += fmt.Sprintf("\nWarning: automatic restore could not be scheduled (%v); run desktop:focus off when done", err)
+	}
+	if len(state.PausedPlayers) > 0 {
+		output += fmt.Sprintf("\nPaused %d media player(s)", len(state.PausedPlayers))
+	}
+
+	// Hooks run synchronously because lumo focus exits right after
+	hookCtx, cancel := context.WithTimeout(ctx, hooks.DefaultTimeout)
+	defer cancel()
+	if err := hooks.Run(hookCtx, hooks.EventFocusStart, map[string]interface{}{
+		"until":    state.Until,
+		"duration": duration.String(),
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	return &core.Result{
+		Output:  output,
+		Success: true,
+		Data: map[string]interface{}{
+			"until": state.Until,
+		},
+	}, nil
+}
+
+// endFocus restores the state saved by startFocus. When sessionID is set,
+// the call came from a restore timer and only ends that session.
+func (e *Environment) endFocus(ctx context.Context, sessionID string) (*core.Result, error) {
+	state, err := loadFocusState()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return &core.Result{
+			Output:  "Focus mode is not on",
+			Success: true,
+		}, nil
+	}
+	if sessionID != "" && sessionID != strconv.FormatInt(state.ID, 10) {
+		return &core.Result{
+			Output:  "Focus session already replaced, nothing to restore",
+			Success: true,
+		}, nil
+	}
+
+	if err := e.SetDoNotDisturb(ctx, state.PreviousDND); err != nil {
+		return nil, err
+	}
+	e.resumeMedia(state.PausedPlayers)
+
+	if err := removeFocusState(); err != nil {
+		return nil, err
+	}
+	if sessionID == "" {
+		cancelFocusRestore()
+	}
+
+	// Hooks run synchronously here because the restore timer exits right after
+	hookCtx, cancel := context.WithTimeout(ctx, hooks.DefaultTimeout)
+	defer cancel()
+	if err := hooks.Run(hookCtx, hooks.EventFocusEnd, map[string]interface{}{
+		"started": time.Unix(state.ID, 0),
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	return &core.Result{
+		Output:  "Focus mode off, notifications restored",
+		Success: true,
+	}, nil
+}
+
+// scheduleFocusRestore arranges for `lumo desktop:focus off <id>` to run after
+// the given duration, preferring a systemd user timer so it survives logouts
+func scheduleFocusRestore(id int64, duration time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate lumo executable: %w", err)
+	}
+	command := fmt.Sprintf("desktop:focus off %d", id)
+
+	if _, err := exec.LookPath("systemd-run"); err == nil {
+		cancelFocusRestore()
+		err := exec.Command("systemd-run", "--user", "--quiet",
+			"--unit="+focusRestoreUnit,
+			fmt.Sprintf("--on-active=%ds", int(duration.Seconds())),
+			executable, command).Run()
+		if err == nil {
+			return nil
+		}
+	}
+
+	// Fall back to a detached sleep
+	restore := exec.Command("nohup", "sh", "-c",
+		fmt.Sprintf("sleep %d && '%s' '%s'", int(duration.Seconds()), executable, command))
+	if err := restore.Start(); err != nil {
+		return fmt.Errorf("failed to start restore timer: %w", err)
+	}
+	return restore.Process.Release()
+}
+
+// cancelFocusRestore stops a pending systemd restore timer, if any
+func cancelFocusRestore() {
+	_ = exec.Command("systemctl", "--user", "stop", focusRestoreUnit+".timer").Run()
+}
+
+// focusStatePath returns the path of the focus session state file
+func focusStatePath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "focus.json"), nil
+}
+
+// loadFocusState loads the running focus session, or nil if there is none
+func loadFocusState() (*focusState, error) {
+	path, err := focusStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read focus state: %w", err)
+	}
+
+	var state focusState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse focus state: %w", err)
+	}
+	return &state, nil
+}
+
+// saveFocusState saves the running focus session
+func saveFocusState(state *focusState) error {
+	path, err := focusStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode focus state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write focus state: %w", err)
+	}
+	return nil
+}
+
+// removeFocusState deletes the focus session state file
+func removeFocusState() error {
+	path, err := focusStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove focus state: %w", err)
+	}
+	return nil
+}
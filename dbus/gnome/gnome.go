@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/agnath18K/lumo/dbus/common"
 	"github.com/agnath18K/lumo/internal/core"
@@ -52,6 +53,7 @@ func NewEnvironment() (*Environment, error) {
 		core.CapabilityAppearanceManagement,
 		core.CapabilitySoundManagement,
 		core.CapabilityConnectivityManagement,
+		core.CapabilityFocusMode,
 	}
 
 	// Create base environment
@@ -112,6 +114,10 @@ func (e *Environment) ExecuteCommand(ctx context.Context, cmd *core.Command) (*c
 		return e.executeSoundCommand(ctx, cmd)
 	case core.CommandTypeConnectivity:
 		return e.executeConnectivityCommand(ctx, cmd)
+	case core.CommandTypeFocus:
+		return e.executeFocusCommand(ctx, cmd)
+	case core.CommandTypeScreenshot:
+		return e.executeScreenshotCommand(ctx, cmd)
 	default:
 		return nil, fmt.Errorf("unsupported command type: %s", cmd.Type)
 	}
@@ -160,6 +166,73 @@ func (e *Environment) executeWindowCommand(ctx context.Context, cmd *core.Comman
 			Output:  fmt.Sprintf("Focused window: %s", cmd.Target),
 			Success: true,
 		}, nil
+	case "tile":
+		position := core.TilePosition(argumentString(cmd.Arguments, "position"))
+		if err := e.TileWindow(ctx, cmd.Target, position); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Tiled window %s: %s", position, cmd.Target),
+			Success: true,
+		}, nil
+	case "move-to-monitor":
+		monitor, err := e.resolveMonitor(ctx, cmd.Target, argumentString(cmd.Arguments, "monitor"))
+		if err != nil {
+			return nil, err
+		}
+		if err := e.MoveWindowToMonitor(ctx, cmd.Target, monitor-1); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Moved window to monitor %d: %s", monitor, cmd.Target),
+			Success: true,
+		}, nil
+	case "move-to-workspace":
+		workspace, err := strconv.Atoi(argumentString(cmd.Arguments, "workspace"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace: %v", cmd.Arguments["workspace"])
+		}
+		if err := e.MoveWindowToWorkspace(ctx, cmd.Target, workspace); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Moved window to workspace %d: %s", workspace, cmd.Target),
+			Success: true,
+		}, nil
+	case "switch-workspace":
+		workspace, err := strconv.Atoi(cmd.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace: %s", cmd.Target)
+		}
+		if err := e.SwitchWorkspace(ctx, workspace); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Switched to workspace %d", workspace),
+			Success: true,
+		}, nil
+	case "list-monitors":
+		monitors, err := e.GetMonitors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var output strings.Builder
+		output.WriteString("Monitors:\n")
+		for _, monitor := range monitors {
+			primary := ""
+			if monitor.Primary {
+				primary = " (primary)"
+			}
+			output.WriteString(fmt.Sprintf("%d. %s %dx%d%s\n", monitor.Index+1, monitor.Name,
+				monitor.Geometry.Width, monitor.Geometry.Height, primary))
+		}
+		return &core.Result{
+			Output:  output.String(),
+			Success: true,
+			Data: map[string]interface{}{
+				"monitors": monitors,
+			},
+		}, nil
 	case "list":
 		windows, err := e.GetWindows(ctx)
 		if err != nil {
@@ -194,13 +267,35 @@ func (e *Environment) executeApplicationCommand(ctx context.Context, cmd *core.C
 				args = argsSlice
 			}
 		}
-		if err := e.LaunchApplication(ctx, cmd.Target, args...); err != nil {
+		opts := core.LaunchOptions{
+			Args:          args,
+			NewInstance:   argumentString(cmd.Arguments, "new_instance") == "true",
+			WaitForWindow: argumentString(cmd.Arguments, "wait") == "true",
+		}
+		if timeout := argumentString(cmd.Arguments, "timeout"); timeout != "" {
+			duration, err := time.ParseDuration(timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout: %s", timeout)
+			}
+			opts.WaitTimeout = duration
+		}
+
+		window, err := e.LaunchApplicationWithOptions(ctx, cmd.Target, opts)
+		if err != nil {
 			return nil, err
 		}
-		return &core.Result{
+
+		result := &core.Result{
 			Output:  fmt.Sprintf("Launched application: %s", cmd.Target),
 			Success: true,
-		}, nil
+		}
+		if window != nil {
+			result.Output += fmt.Sprintf(" (window %s)", window.ID)
+			result.Data = map[string]interface{}{
+				"window_id": window.ID,
+			}
+		}
+		return result, nil
 	case "list":
 		apps, err := e.GetRunningApplications(ctx)
 		if err != nil {
@@ -287,6 +382,16 @@ func (e *Environment) executeSystemCommand(ctx context.Context, cmd *core.Comman
 			Output:  "Screen locked",
 			Success: true,
 		}, nil
+	case "suspend":
+		return e.suspend()
+	case "hibernate":
+		return e.hibernate()
+	case "schedule-shutdown":
+		return e.scheduleShutdown(cmd)
+	case "cancel-shutdown":
+		return e.cancelScheduledShutdown()
+	case "shutdown-status":
+		return e.scheduledShutdownStatus()
 	default:
 		return nil, fmt.Errorf("unsupported system action: %s", cmd.Action)
 	}
@@ -343,6 +448,12 @@ func (e *Environment) executeNotificationCommand(ctx context.Context, cmd *core.
 			Output:  fmt.Sprintf("Notification closed (ID: %d)", id),
 			Success: true,
 		}, nil
+	case "enable-dnd":
+		return e.enableDND(ctx)
+	case "disable-dnd":
+		return e.disableDND(ctx)
+	case "list-notifications":
+		return listMissedNotifications()
 	default:
 		return nil, fmt.Errorf("unsupported notification action: %s", cmd.Action)
 	}
@@ -351,34 +462,52 @@ func (e *Environment) executeNotificationCommand(ctx context.Context, cmd *core.
 // executeMediaCommand executes a media control command
 func (e *Environment) executeMediaCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
 	// Find the active media player
-	playerService := ""
-
-	// List DBus services
-	conn, err := common.NewDBusConnection(common.DBusTypeSession)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to DBus: %w", err)
-	}
-	defer conn.Close()
-
-	services, err := common.ListDBusServices(conn)
+	players, err := e.findMediaPlayers()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list DBus services: %w", err)
+		return nil, err
 	}
 
-	// Find a media player service
-	for _, service := range services {
-		if strings.HasPrefix(service, "org.mpris.MediaPlayer2.") {
-			playerService = service
-			break
+	// The player can be chosen before it starts
+	if cmd.Action == "set-player" {
+		output, err := common.SetPlayerResult(players, cmd.Target)
+		if err != nil {
+			return nil, err
 		}
+		return &core.Result{
+			Output:  output,
+			Success: true,
+		}, nil
 	}
 
-	if playerService == "" {
-		return nil, fmt.Errorf("no active media player found")
+	playerService, err := common.ChoosePlayer(e.sessionHandler, players, common.PreferredPlayer())
+	if err != nil {
+		return nil, err
 	}
 
 	// Execute the command
 	switch cmd.Action {
+	case "now-playing":
+		nowPlaying, err := common.ReadNowPlaying(e.sessionHandler, playerService)
+		if err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  nowPlaying.String(),
+			Success: true,
+		}, nil
+	case "seek":
+		offset, err := common.ParseSeekOffset(cmd.Target)
+		if err != nil {
+			return nil, err
+		}
+		position, err := common.Seek(e.sessionHandler, playerService, offset)
+		if err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  common.SeekResult(offset, position),
+			Success: true,
+		}, nil
 	case "play":
 		_, err := e.sessionHandler.Call(
 			playerService,
@@ -453,3 +582,65 @@ func (e *Environment) executeMediaCommand(ctx context.Context, cmd *core.Command
 		return nil, fmt.Errorf("unsupported media action: %s", cmd.Action)
 	}
 }
+
+// findMediaPlayers lists the MPRIS media players on the session bus
+func (e *Environment) findMediaPlayers() ([]string, error) {
+	// List DBus services
+	conn, err := common.NewDBusConnection(common.DBusTypeSession)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DBus: %w", err)
+	}
+	defer conn.Close()
+
+	services, err := common.ListDBusServices(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DBus services: %w", err)
+	}
+
+	return common.MediaPlayers(services), nil
+}
+
+// pausePlayingMedia pauses every player that is currently playing and
+// returns the players it paused so they can be resumed later
+func (e *Environment) pausePlayingMedia() ([]string, error) {
+	players, err := e.findMediaPlayers()
+	if err != nil {
+		return nil, err
+	}
+
+	var paused []string
+	for _, player := range players {
+		status, err := e.sessionHandler.GetProperty(player, MediaPlayerPath, MediaPlayerPlayerInterface, "PlaybackStatus")
+		if err != nil || status != "Playing" {
+			continue
+		}
+
+		if _, err := e.sessionHandler.Call(player, MediaPlayerPath, MediaPlayerPlayerInterface, "Pause"); err != nil {
+			fmt.Printf("Warning: Failed to pause %s: %v\n", player, err)
+			continue
+		}
+		paused = append(paused, player)
+	}
+
+	return paused, nil
+}
+
+// resumeMedia resumes playback on the given players
+func (e *Environment) resumeMedia(players []string) {
+	for _, player := range players {
+		if _, err := e.sessionHandler.Call(player, MediaPlayerPath, MediaPlayerPlayerInterface, "Play"); err != nil {
+			// The player may have been closed in the meantime
+			fmt.Printf("Warning: Failed to resume %s: %v\n", player, err)
+		}
+	}
+}
+
+// argumentString returns a command argument as a string. Arguments parsed
+// from AI output are strings, while the pattern matcher may store other types.
+func argumentString(args map[string]interface{}, key string) string {
+	value, ok := args[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
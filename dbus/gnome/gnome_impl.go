@@ -144,24 +144,8 @@ func (e *Environment) GetRunningApplications(ctx context.Context) ([]core.Applic
 
 // LaunchApplication launches an application
 func (e *Environment) LaunchApplication(ctx context.Context, appName string, args ...string) error {
-	// Try to launch the application using DBus
-	_, err := e.sessionHandler.Call(
-		AppLauncher,
-		AppLauncherPath,
-		AppLauncherInterface,
-		"LaunchApplication",
-		appName,
-		args,
-	)
-	if err != nil {
-		// Fallback to using the command line
-		cmd := exec.Command(appName, args...)
-		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("failed to launch application: %w", err)
-		}
-	}
-
-	return nil
+	_, err := e.LaunchApplicationWithOptions(ctx, appName, core.LaunchOptions{Args: args})
+	return err
 }
 
 // CloseWindow closes a window
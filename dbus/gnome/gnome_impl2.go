@@ -3,11 +3,8 @@ package gnome
 import (
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
-	"time"
 )
 
 // ShowDesktop shows the desktop
@@ -74,76 +71,30 @@ func (e *Environment) CloseNotification(ctx context.Context, id uint32) error {
 	return nil
 }
 
-// TakeScreenshot takes a screenshot
-func (e *Environment) TakeScreenshot(ctx context.Context, fullScreen bool, delay int) (string, error) {
-	// Create a temporary file to store the screenshot
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
+// GSettingsSchemaNotifications is the schema for desktop notification settings
+const GSettingsSchemaNotifications = "org.gnome.desktop.notifications"
 
-	// Create a timestamp for the filename
-	timestamp := time.Now().Format("20060102-150405")
-	filename := fmt.Sprintf("screenshot-%s.png", timestamp)
-	screenshotDir := filepath.Join(homeDir, "Pictures")
-	screenshotPath := filepath.Join(screenshotDir, filename)
-
-	// Ensure the directory exists
-	if err := os.MkdirAll(screenshotDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+// SetDoNotDisturb enables or disables do not disturb mode
+func (e *Environment) SetDoNotDisturb(ctx context.Context, enabled bool) error {
+	// GNOME implements do not disturb by hiding notification banners
+	showBanners := "true"
+	if enabled {
+		showBanners = "false"
 	}
 
-	// Try to use the DBus method to take a screenshot
-	var result []interface{}
-	if fullScreen {
-		result, err = e.sessionHandler.Call(
-			Screenshot,
-			ScreenshotPath,
-			ScreenshotInterface,
-			"Screenshot",
-			true,  // Include cursor
-			delay, // Delay in seconds
-			screenshotPath,
-		)
-	} else {
-		result, err = e.sessionHandler.Call(
-			Screenshot,
-			ScreenshotPath,
-			ScreenshotInterface,
-			"ScreenshotArea",
-			0, 0, -1, -1, // x, y, width, height (-1 = full screen)
-			true,  // Include cursor
-			delay, // Delay in seconds
-			screenshotPath,
-		)
+	if err := e.setGSetting(GSettingsSchemaNotifications, "show-banners", showBanners); err != nil {
+		return fmt.Errorf("failed to set do not disturb: %w", err)
 	}
+	return nil
+}
 
+// GetDoNotDisturb gets the current do not disturb state
+func (e *Environment) GetDoNotDisturb(ctx context.Context) (bool, error) {
+	showBanners, err := e.getGSetting(GSettingsSchemaNotifications, "show-banners")
 	if err != nil {
-		// Fallback to using the command line
-		var cmd *exec.Cmd
-		if fullScreen {
-			cmd = exec.Command("gnome-screenshot", "-f", screenshotPath)
-		} else {
-			cmd = exec.Command("gnome-screenshot", "-a", "-f", screenshotPath)
-		}
-
-		if delay > 0 {
-			cmd.Args = append(cmd.Args, "-d", fmt.Sprintf("%d", delay))
-		}
-
-		if err := cmd.Run(); err != nil {
-			return "", fmt.Errorf("failed to take screenshot: %w", err)
-		}
-	} else {
-		// Parse the result
-		if len(result) > 0 {
-			if success, ok := result[0].(bool); ok && !success {
-				return "", fmt.Errorf("failed to take screenshot")
-			}
-		}
+		return false, fmt.Errorf("failed to get do not disturb state: %w", err)
 	}
-
-	return screenshotPath, nil
+	return showBanners == "false", nil
 }
 
 // GetClipboardText gets the text from the clipboard
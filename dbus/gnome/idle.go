@@ -0,0 +1,36 @@
+package gnome
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetIdleTime gets how long the user has been idle
+func (e *Environment) GetIdleTime(ctx context.Context) (time.Duration, error) {
+	// Ask Mutter's idle monitor first, it works on both X11 and Wayland
+	result, err := e.sessionHandler.Call(IdleMonitor, IdleMonitorPath, IdleMonitorInterface, "GetIdletime")
+	if err == nil && len(result) > 0 {
+		if ms, ok := result[0].(uint64); ok {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+	}
+
+	// Fall back to xprintidle on X11 sessions
+	output, cmdErr := e.runCommand("xprintidle")
+	if cmdErr != nil {
+		if err != nil {
+			return 0, fmt.Errorf("failed to get idle time: %w", err)
+		}
+		return 0, fmt.Errorf("failed to get idle time: %w", cmdErr)
+	}
+
+	ms, parseErr := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if parseErr != nil {
+		return 0, fmt.Errorf("failed to parse idle time: %w", parseErr)
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,180 @@
+package gnome
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// GNOME custom keybinding settings
+const (
+	// GSettingsSchemaMediaKeys is the schema holding the list of custom keybindings
+	GSettingsSchemaMediaKeys = "org.gnome.settings-daemon.plugins.media-keys"
+	// GSettingsSchemaCustomKeybinding is the relocatable schema for a single custom keybinding
+	GSettingsSchemaCustomKeybinding = "org.gnome.settings-daemon.plugins.media-keys.custom-keybinding"
+	// CustomKeybindingsPath is the dconf directory custom keybindings live under
+	CustomKeybindingsPath = "/org/gnome/settings-daemon/plugins/media-keys/custom-keybindings/"
+)
+
+// shortcutPrefix marks keybindings managed by lumo so they can be told apart from the user's own
+const shortcutPrefix = "lumo-"
+
+// RegisterShortcut registers a GNOME custom keybinding
+func (e *Environment) RegisterShortcut(ctx context.Context, shortcut core.Shortcut) error {
+	if shortcut.ID == "" || strings.ContainsAny(shortcut.ID, "/ ") {
+		return fmt.Errorf("invalid shortcut ID: %q", shortcut.ID)
+	}
+
+	path := shortcutPath(shortcut.ID)
+	schema := GSettingsSchemaCustomKeybinding + ":" + path
+
+	for key, value := range map[string]string{
+		"name":    shortcut.Name,
+		"command": shortcut.Command,
+		"binding": shortcut.Binding,
+	} {
+		if err := gsettingsSet(schema, key, gvariantString(value)); err != nil {
+			return fmt.Errorf("failed to set shortcut %s: %w", key, err)
+		}
+	}
+
+	// Add the keybinding to the list GNOME watches, if it isn't there yet
+	paths, err := e.customKeybindingPaths()
+	if err != nil {
+		return err
+	}
+	for _, existing := range paths {
+		if existing == path {
+			return nil
+		}
+	}
+
+	return e.setCustomKeybindingPaths(append(paths, path))
+}
+
+// RemoveShortcut removes a lumo-managed GNOME custom keybinding
+func (e *Environment) RemoveShortcut(ctx context.Context, id string) error {
+	path := shortcutPath(id)
+
+	paths, err := e.customKeybindingPaths()
+	if err != nil {
+		return err
+	}
+
+	var remaining []string
+	for _, existing := range paths {
+		if existing != path {
+			remaining = append(remaining, existing)
+		}
+	}
+	if len(remaining) == len(paths) {
+		return fmt.Errorf("shortcut not found: %s", id)
+	}
+
+	if err := e.setCustomKeybindingPaths(remaining); err != nil {
+		return err
+	}
+
+	// Clear the stored values so no stale keys are left in dconf
+	schema := GSettingsSchemaCustomKeybinding + ":" + path
+	for _, key := range []string{"name", "command", "binding"} {
+		if output, err := exec.Command("gsettings", "reset", schema, key).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to reset shortcut %s: %w (output: %s)", key, err, output)
+		}
+	}
+
+	return nil
+}
+
+// ListShortcuts lists the GNOME custom keybindings registered by lumo
+func (e *Environment) ListShortcuts(ctx context.Context) ([]core.Shortcut, error) {
+	paths, err := e.customKeybindingPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var shortcuts []core.Shortcut
+	for _, path := range paths {
+		id := strings.TrimSuffix(strings.TrimPrefix(path, CustomKeybindingsPath), "/")
+		if !strings.HasPrefix(id, shortcutPrefix) {
+			continue
+		}
+
+		schema := GSettingsSchemaCustomKeybinding + ":" + path
+		shortcut := core.Shortcut{ID: strings.TrimPrefix(id, shortcutPrefix)}
+		shortcut.Name, _ = e.getGSetting(schema, "name")
+		shortcut.Command, _ = e.getGSetting(schema, "command")
+		shortcut.Binding, _ = e.getGSetting(schema, "binding")
+		shortcuts = append(shortcuts, shortcut)
+	}
+
+	return shortcuts, nil
+}
+
+// customKeybindingPaths returns every custom keybinding path, including the user's own
+func (e *Environment) customKeybindingPaths() ([]string, error) {
+	value, err := e.getGSetting(GSettingsSchemaMediaKeys, "custom-keybindings")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom keybindings: %w", err)
+	}
+	return parseGVariantStringArray(value), nil
+}
+
+// setCustomKeybindingPaths replaces the list of custom keybinding paths
+func (e *Environment) setCustomKeybindingPaths(paths []string) error {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = gvariantString(path)
+	}
+
+	value := "[" + strings.Join(quoted, ", ") + "]"
+	if len(paths) == 0 {
+		value = "@as []"
+	}
+
+	if err := gsettingsSet(GSettingsSchemaMediaKeys, "custom-keybindings", value); err != nil {
+		return fmt.Errorf("failed to update custom keybindings: %w", err)
+	}
+	return nil
+}
+
+// shortcutPath returns the dconf path of a lumo-managed keybinding
+func shortcutPath(id string) string {
+	return CustomKeybindingsPath + shortcutPrefix + id + "/"
+}
+
+// gsettingsSet sets a GSettings key to a serialized GVariant value without
+// going through the shell, so quotes in commands survive intact
+func gsettingsSet(schema, key, value string) error {
+	output, err := exec.Command("gsettings", "set", schema, key, value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// gvariantString quotes a string as a GVariant text literal
+func gvariantString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+// parseGVariantStringArray parses gsettings output like "['/a/', '/b/']".
+// Keybinding paths never contain commas or quotes, so a simple split is enough.
+func parseGVariantStringArray(value string) []string {
+	value = strings.TrimSpace(strings.TrimPrefix(value, "@as"))
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.Trim(strings.TrimSpace(item), "'\"")
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,387 @@
+package gnome
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/godbus/dbus/v5"
+)
+
+// ApplicationInterface is the freedesktop interface implemented by DBus-activatable apps
+const ApplicationInterface = "org.freedesktop.Application"
+
+// DefaultWaitTimeout is how long a launch waits for a window when no timeout is given
+const DefaultWaitTimeout = 10 * time.Second
+
+// desktopEntry holds the fields of a .desktop file needed to launch an application
+type desktopEntry struct {
+	ID              string
+	Name            string
+	Exec            string
+	DBusActivatable bool
+	StartupWMClass  string
+}
+
+// appWindow is a window together with its WM_CLASS, as listed by `wmctrl -lx`
+type appWindow struct {
+	ID    string
+	Class string
+	Title string
+}
+
+// LaunchApplicationWithOptions launches an application. Running instances are
+// activated instead of spawning a new process unless NewInstance is set.
+func (e *Environment) LaunchApplicationWithOptions(ctx context.Context, appName string, opts core.LaunchOptions) (*core.Window, error) {
+	entry := findDesktopEntry(appName)
+	before := listAppWindows()
+
+	switch {
+	case entry != nil && entry.DBusActivatable && !opts.NewInstance:
+		// DBus activation reuses the running instance if there is one
+		if err := e.activateApplication(entry, opts.Args); err != nil {
+			fmt.Printf("Warning: DBus activation of %s failed, spawning it instead: %v\n", entry.ID, err)
+			if err := spawnApplication(appName, entry, opts.Args); err != nil {
+				return nil, err
+			}
+		}
+	case !opts.NewInstance && len(opts.Args) == 0:
+		if window := findAppWindow(before, appName, entry); window != nil {
+			// Bring the running instance to the front
+			if output, err := exec.Command("wmctrl", "-i", "-a", window.ID).CombinedOutput(); err != nil {
+				return nil, fmt.Errorf("failed to activate %s: %w (output: %s)", appName, err, output)
+			}
+			return window.toCore(), nil
+		}
+		if err := spawnApplication(appName, entry, opts.Args); err != nil {
+			return nil, err
+		}
+	default:
+		if err := spawnApplication(appName, entry, opts.Args); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.WaitForWindow {
+		return nil, nil
+	}
+
+	timeout := opts.WaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	return waitForAppWindow(ctx, appName, entry, before, timeout)
+}
+
+// activateApplication activates a DBus-activatable application, opening any
+// files or URIs through org.freedesktop.Application.Open
+func (e *Environment) activateApplication(entry *desktopEntry, args []string) error {
+	objectPath := "/" + strings.ReplaceAll(strings.ReplaceAll(entry.ID, ".", "/"), "-", "_")
+	platformData := map[string]dbus.Variant{}
+
+	var err error
+	if len(args) > 0 {
+		uris := make([]string, len(args))
+		for i, arg := range args {
+			uris[i] = toURI(arg)
+		}
+		_, err = e.sessionHandler.Call(entry.ID, objectPath, ApplicationInterface, "Open", uris, platformData)
+	} else {
+		_, err = e.sessionHandler.Call(entry.ID, objectPath, ApplicationInterface, "Activate", platformData)
+	}
+	return err
+}
+
+// spawnApplication starts a new process for the application
+func spawnApplication(appName string, entry *desktopEntry, args []string) error {
+	argv := append([]string{appName}, args...)
+	if entry != nil && entry.Exec != "" {
+		argv = expandExec(entry.Exec, args)
+	}
+	if len(argv) == 0 {
+		return fmt.Errorf("failed to launch application: empty command for %s", appName)
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to launch application: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// waitForAppWindow polls until the application has a window that was not open before
+func waitForAppWindow(ctx context.Context, appName string, entry *desktopEntry, before []appWindow, timeout time.Duration) (*core.Window, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	existing := make(map[string]bool, len(before))
+	for _, window := range before {
+		existing[window.ID] = true
+	}
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		windows := listAppWindows()
+		var fresh []appWindow
+		for _, window := range windows {
+			if !existing[window.ID] {
+				fresh = append(fresh, window)
+			}
+		}
+		if window := findAppWindow(fresh, appName, entry); window != nil {
+			return window.toCore(), nil
+		}
+		// An activated instance may not open a new window, so accept its existing one
+		if window := findAppWindow(windows, appName, entry); window != nil && entry != nil && entry.DBusActivatable {
+			return window.toCore(), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for a %s window", timeout, appName)
+		case <-ticker.C:
+		}
+	}
+}
+
+// listAppWindows lists windows with their WM_CLASS
+func listAppWindows() []appWindow {
+	output, err := exec.Command("wmctrl", "-lx").Output()
+	if err != nil {
+		return nil
+	}
+
+	var windows []appWindow
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: id desktop wm_class hostname title
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		windows = append(windows, appWindow{
+			ID:    fields[0],
+			Class: fields[2],
+			Title: strings.Join(fields[4:], " "),
+		})
+	}
+	return windows
+}
+
+// findAppWindow finds a window belonging to the application by its WM_CLASS
+func findAppWindow(windows []appWindow, appName string, entry *desktopEntry) *appWindow {
+	candidates := []string{strings.ToLower(appName)}
+	if entry != nil {
+		candidates = append(candidates, strings.ToLower(entry.ID))
+		if entry.StartupWMClass != "" {
+			candidates = append(candidates, strings.ToLower(entry.StartupWMClass))
+		}
+	}
+
+	for i, window := range windows {
+		// WM_CLASS is listed as instance.class, e.g. Navigator.firefox
+		class := strings.ToLower(window.Class)
+		for _, candidate := range candidates {
+			if class == candidate || strings.HasPrefix(class, candidate+".") || strings.HasSuffix(class, "."+candidate) {
+				return &windows[i]
+			}
+		}
+	}
+	return nil
+}
+
+// toCore converts the window to the core representation
+func (w *appWindow) toCore() *core.Window {
+	return &core.Window{
+		ID:          w.ID,
+		Title:       w.Title,
+		Application: w.Class,
+	}
+}
+
+// findDesktopEntry looks up the .desktop file for an application by ID, name, or executable
+func findDesktopEntry(appName string) *desktopEntry {
+	name := strings.ToLower(strings.TrimSuffix(appName, ".desktop"))
+
+	var byName, bySuffix *desktopEntry
+	for _, dir := range applicationDirs() {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.desktop"))
+		for _, file := range files {
+			entry := parseDesktopEntry(file)
+			if entry == nil {
+				continue
+			}
+
+			id := strings.ToLower(entry.ID)
+			switch {
+			case id == name:
+				return entry
+			case byName == nil && (strings.ToLower(entry.Name) == name || execName(entry.Exec) == name):
+				byName = entry
+			case bySuffix == nil && strings.HasSuffix(id, "."+name):
+				bySuffix = entry
+			}
+		}
+	}
+
+	if byName != nil {
+		return byName
+	}
+	return bySuffix
+}
+
+// applicationDirs returns the XDG directories that contain .desktop files, highest priority first
+func applicationDirs() []string {
+	var dirs []string
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+	}
+	if dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "applications"),
+			filepath.Join(dataHome, "flatpak", "exports", "share", "applications"))
+	}
+
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range filepath.SplitList(dataDirs) {
+		dirs = append(dirs, filepath.Join(dir, "applications"))
+	}
+
+	return append(dirs, "/var/lib/flatpak/exports/share/applications", "/var/lib/snapd/desktop/applications")
+}
+
+// parseDesktopEntry reads the [Desktop Entry] group of a .desktop file
+func parseDesktopEntry(path string) *desktopEntry {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	entry := &desktopEntry{ID: strings.TrimSuffix(filepath.Base(path), ".desktop")}
+	inEntry := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Name":
+			entry.Name = strings.TrimSpace(value)
+		case "Exec":
+			entry.Exec = strings.TrimSpace(value)
+		case "DBusActivatable":
+			entry.DBusActivatable = strings.TrimSpace(value) == "true"
+		case "StartupWMClass":
+			entry.StartupWMClass = strings.TrimSpace(value)
+		}
+	}
+
+	return entry
+}
+
+// expandExec expands the field codes of a desktop entry Exec line
+func expandExec(execLine string, args []string) []string {
+	var argv []string
+	usedArgs := false
+
+	for _, token := range splitExec(execLine) {
+		switch token {
+		case "%f", "%u":
+			if len(args) > 0 {
+				argv = append(argv, args[0])
+			}
+			usedArgs = true
+		case "%F", "%U":
+			argv = append(argv, args...)
+			usedArgs = true
+		case "%i", "%c", "%k":
+			// Icon, name, and location codes are not needed to launch
+		default:
+			argv = append(argv, strings.ReplaceAll(token, "%%", "%"))
+		}
+	}
+
+	// Apps without field codes still get the arguments appended
+	if !usedArgs {
+		argv = append(argv, args...)
+	}
+	return argv
+}
+
+// splitExec splits an Exec line into words, honoring double quotes
+func splitExec(execLine string) []string {
+	var words []string
+	var current strings.Builder
+	inQuotes := false
+	hasWord := false
+
+	for i := 0; i < len(execLine); i++ {
+		c := execLine[i]
+		switch {
+		case c == '\\' && inQuotes && i+1 < len(execLine):
+			i++
+			current.WriteByte(execLine[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			hasWord = true
+		case c == ' ' && !inQuotes:
+			if hasWord {
+				words = append(words, current.String())
+				current.Reset()
+				hasWord = false
+			}
+		default:
+			current.WriteByte(c)
+			hasWord = true
+		}
+	}
+	if hasWord {
+		words = append(words, current.String())
+	}
+	return words
+}
+
+// execName returns the executable name of an Exec line
+func execName(execLine string) string {
+	words := splitExec(execLine)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(filepath.Base(words[0]))
+}
+
+// toURI converts a file path to a file:// URI, leaving URIs untouched
+func toURI(arg string) string {
+	if strings.Contains(arg, "://") {
+		return arg
+	}
+	if abs, err := filepath.Abs(arg); err == nil {
+		arg = abs
+	}
+	return "file://" + arg
+}
@@ -0,0 +1,233 @@
+package gnome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// GSettingsSchemaColor is the schema for the settings daemon color plugin
+const GSettingsSchemaColor = "org.gnome.settings-daemon.plugins.color"
+
+// Color temperature range accepted by the color plugin, in Kelvin
+const (
+	// MinColorTemperature is the warmest supported color temperature
+	MinColorTemperature = 1700
+	// MaxColorTemperature is the coolest supported color temperature
+	MaxColorTemperature = 4700
+)
+
+// nightLightScheduleKeys are the settings SetNightLight overrides to keep
+// night light on, and restores when it is turned off
+var nightLightScheduleKeys = []string{
+	"night-light-schedule-automatic",
+	"night-light-schedule-from",
+	"night-light-schedule-to",
+}
+
+// SetNightLight enables or disables night light
+func (e *Environment) SetNightLight(ctx context.Context, enabled bool) error {
+	if enabled {
+		// Night light only shows inside its schedule, so widen the schedule to
+		// the whole day; lumo's daemon takes care of scheduling instead. The
+		// user's schedule is saved first, unless lumo already replaced it.
+		if err := e.saveNightLightSchedule(); err != nil {
+			return err
+		}
+		if err := e.setGSetting(GSettingsSchemaColor, "night-light-schedule-automatic", "false"); err != nil {
+			return fmt.Errorf("failed to disable automatic night light schedule: %w", err)
+		}
+		if err := e.setGSetting(GSettingsSchemaColor, "night-light-schedule-from", "0.0"); err != nil {
+			return fmt.Errorf("failed to set night light schedule: %w", err)
+		}
+		if err := e.setGSetting(GSettingsSchemaColor, "night-light-schedule-to", "23.99"); err != nil {
+			return fmt.Errorf("failed to set night light schedule: %w", err)
+		}
+	}
+
+	if err := e.setGSetting(GSettingsSchemaColor, "night-light-enabled", strconv.FormatBool(enabled)); err != nil {
+		return fmt.Errorf("failed to set night light: %w", err)
+	}
+
+	if !enabled {
+		if err := e.restoreNightLightSchedule(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nightLightSchedulePath returns the file the user's night light schedule
+// is saved in while lumo keeps night light on
+func nightLightSchedulePath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "nightlight.json"), nil
+}
+
+// saveNightLightSchedule saves the user's night light schedule, unless it
+// is already saved from an earlier SetNightLight
+func (e *Environment) saveNightLightSchedule() error {
+	path, err := nightLightSchedulePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	schedule := make(map[string]string, len(nightLightScheduleKeys))
+	for _, key := range nightLightScheduleKeys {
+		value, err := e.getGSetting(GSettingsSchemaColor, key)
+		if err != nil {
+			return fmt.Errorf("failed to read night light schedule: %w", err)
+		}
+		schedule[key] = value
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(schedule, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode night light schedule: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save night light schedule: %w", err)
+	}
+	return nil
+}
+
+// restoreNightLightSchedule puts back the schedule saved by
+// saveNightLightSchedule, if there is one
+func (e *Environment) restoreNightLightSchedule() error {
+	path, err := nightLightSchedulePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read saved night light schedule: %w", err)
+	}
+
+	var schedule map[string]string
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return fmt.Errorf("failed to parse saved night light schedule: %w", err)
+	}
+	for _, key := range nightLightScheduleKeys {
+		if value, ok := schedule[key]; ok {
+			if err := e.setGSetting(GSettingsSchemaColor, key, value); err != nil {
+				return fmt.Errorf("failed to restore night light schedule: %w", err)
+			}
+		}
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove saved night light schedule: %w", err)
+	}
+	return nil
+}
+
+// GetNightLight gets whether night light is enabled
+func (e *Environment) GetNightLight(ctx context.Context) (bool, error) {
+	enabled, err := e.getGSetting(GSettingsSchemaColor, "night-light-enabled")
+	if err != nil {
+		return false, fmt.Errorf("failed to get night light state: %w", err)
+	}
+	return enabled == "true", nil
+}
+
+// SetColorTemperature sets the night light color temperature in Kelvin
+func (e *Environment) SetColorTemperature(ctx context.Context, kelvin int) error {
+	if kelvin < MinColorTemperature || kelvin > MaxColorTemperature {
+		return fmt.Errorf("color temperature must be between %dK and %dK", MinColorTemperature, MaxColorTemperature)
+	}
+
+	if err := e.setGSetting(GSettingsSchemaColor, "night-light-temperature", fmt.Sprintf("uint32 %d", kelvin)); err != nil {
+		return fmt.Errorf("failed to set color temperature: %w", err)
+	}
+	return nil
+}
+
+// GetColorTemperature gets the night light color temperature in Kelvin
+func (e *Environment) GetColorTemperature(ctx context.Context) (int, error) {
+	value, err := e.getGSetting(GSettingsSchemaColor, "night-light-temperature")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get color temperature: %w", err)
+	}
+
+	// gsettings prints the value as "uint32 4000"
+	kelvin, err := strconv.Atoi(strings.TrimPrefix(value, "uint32 "))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse color temperature: %s", value)
+	}
+	return kelvin, nil
+}
+
+// executeNightLightCommand executes a night light command
+func (e *Environment) executeNightLightCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Action {
+	case "set-night-light":
+		enable := cmd.Target != "false" && cmd.Target != "off" && cmd.Target != "0"
+		if err := e.SetNightLight(ctx, enable); err != nil {
+			return nil, err
+		}
+
+		state := "off"
+		if enable {
+			state = "on"
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Night light turned %s", state),
+			Success: true,
+		}, nil
+	case "set-color-temperature":
+		kelvin, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(cmd.Target), "k"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid color temperature: %s", cmd.Target)
+		}
+		if err := e.SetColorTemperature(ctx, kelvin); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Night light color temperature set to %dK", kelvin),
+			Success: true,
+		}, nil
+	case "get-night-light":
+		enabled, err := e.GetNightLight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		kelvin, err := e.GetColorTemperature(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		state := "off"
+		if enabled {
+			state = "on"
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Night light is %s (%dK)", state, kelvin),
+			Success: true,
+			Data: map[string]any{
+				"enabled":     enabled,
+				"temperature": kelvin,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported night light action: %s", cmd.Action)
+	}
+}
@@ -16,6 +16,8 @@ const (
 	FileManager = "org.gnome.Nautilus"
 	// Screenshot is the GNOME screenshot service
 	Screenshot = "org.gnome.Screenshot"
+	// ShellScreenshot is the GNOME Shell screenshot service
+	ShellScreenshot = "org.gnome.Shell.Screenshot"
 	// Settings is the GNOME settings service
 	Settings = "org.gnome.Settings"
 	// MediaPlayer is the MPRIS media player service
@@ -40,6 +42,8 @@ const (
 	FileManagerPath = "/org/gnome/Nautilus"
 	// ScreenshotPath is the GNOME screenshot object path
 	ScreenshotPath = "/org/gnome/Screenshot"
+	// ShellScreenshotPath is the GNOME Shell screenshot object path
+	ShellScreenshotPath = "/org/gnome/Shell/Screenshot"
 	// SettingsPath is the GNOME settings object path
 	SettingsPath = "/org/gnome/Settings"
 	// MediaPlayerPath is the MPRIS media player object path
@@ -64,6 +68,8 @@ const (
 	FileManagerInterface = "org.gnome.Nautilus"
 	// ScreenshotInterface is the GNOME screenshot interface
 	ScreenshotInterface = "org.gnome.Screenshot"
+	// ShellScreenshotInterface is the GNOME Shell screenshot interface
+	ShellScreenshotInterface = "org.gnome.Shell.Screenshot"
 	// SettingsInterface is the GNOME settings interface
 	SettingsInterface = "org.gnome.Settings"
 	// MediaPlayerInterface is the MPRIS media player interface
@@ -103,3 +109,23 @@ const (
 	// ClipboardInterface is the clipboard interface
 	ClipboardInterface = "org.gnome.Shell"
 )
+
+// Idle monitor DBus service names
+const (
+	// IdleMonitor is the Mutter idle monitor service
+	IdleMonitor = "org.gnome.Mutter.IdleMonitor"
+	// IdleMonitorPath is the core idle monitor object path
+	IdleMonitorPath = "/org/gnome/Mutter/IdleMonitor/Core"
+	// IdleMonitorInterface is the Mutter idle monitor interface
+	IdleMonitorInterface = "org.gnome.Mutter.IdleMonitor"
+)
+
+// Login manager DBus service names
+const (
+	// Login1 is the systemd-logind service on the system bus
+	Login1 = "org.freedesktop.login1"
+	// Login1Path is the systemd-logind object path
+	Login1Path = "/org/freedesktop/login1"
+	// Login1ManagerInterface is the systemd-logind manager interface
+	Login1ManagerInterface = "org.freedesktop.login1.Manager"
+)
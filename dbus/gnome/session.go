@@ -0,0 +1,176 @@
+package gnome
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// Shutdown types understood by logind's ScheduleShutdown
+const (
+	ShutdownTypePoweroff = "poweroff"
+	ShutdownTypeReboot   = "reboot"
+)
+
+// suspend suspends the system to RAM through logind
+func (e *Environment) suspend() (*core.Result, error) {
+	if err := e.checkLoginCapability("CanSuspend", "suspend"); err != nil {
+		return nil, err
+	}
+
+	// Interactive, so polkit may ask the user for authorization
+	if _, err := e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface, "Suspend", true); err != nil {
+		return nil, fmt.Errorf("failed to suspend: %w", err)
+	}
+	return &core.Result{
+		Output:  "System is suspending",
+		Success: true,
+	}, nil
+}
+
+// hibernate hibernates the system to disk through logind
+func (e *Environment) hibernate() (*core.Result, error) {
+	if err := e.checkLoginCapability("CanHibernate", "hibernate"); err != nil {
+		return nil, err
+	}
+
+	if _, err := e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface, "Hibernate", true); err != nil {
+		return nil, fmt.Errorf("failed to hibernate: %w", err)
+	}
+	return &core.Result{
+		Output:  "System is hibernating",
+		Success: true,
+	}, nil
+}
+
+// checkLoginCapability asks logind whether a power action is available.
+// logind answers "yes", "no", "challenge" (needs authorization), or "na".
+func (e *Environment) checkLoginCapability(method, action string) error {
+	result, err := e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface, method)
+	if err != nil {
+		return fmt.Errorf("failed to check whether %s is available: %w", action, err)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	switch answer, _ := result[0].(string); answer {
+	case "no", "na":
+		return fmt.Errorf("%s is not available on this system", action)
+	}
+	return nil
+}
+
+// scheduleShutdown schedules a poweroff or reboot through logind. The target
+// is a duration such as 30m or a time of day such as 22:30.
+func (e *Environment) scheduleShutdown(cmd *core.Command) (*core.Result, error) {
+	at, err := parseShutdownTime(cmd.Target, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownType := ShutdownTypePoweroff
+	if argumentString(cmd.Arguments, "type") == ShutdownTypeReboot {
+		shutdownType = ShutdownTypeReboot
+	}
+
+	_, err = e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface,
+		"ScheduleShutdown", shutdownType, uint64(at.UnixMicro()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule %s: %w", shutdownType, err)
+	}
+
+	verb := "shut down"
+	if shutdownType == ShutdownTypeReboot {
+		verb = "restart"
+	}
+	return &core.Result{
+		Output:  fmt.Sprintf("System will %s at %s. Cancel with desktop:cancel shutdown", verb, at.Format("15:04")),
+		Success: true,
+		Data: map[string]interface{}{
+			"type": shutdownType,
+			"at":   at,
+		},
+	}, nil
+}
+
+// cancelScheduledShutdown cancels a shutdown scheduled with scheduleShutdown
+func (e *Environment) cancelScheduledShutdown() (*core.Result, error) {
+	result, err := e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface, "CancelScheduledShutdown")
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled shutdown: %w", err)
+	}
+
+	if len(result) > 0 {
+		if cancelled, ok := result[0].(bool); ok && !cancelled {
+			return &core.Result{
+				Output:  "No shutdown is scheduled",
+				Success: true,
+			}, nil
+		}
+	}
+	return &core.Result{
+		Output:  "Scheduled shutdown cancelled",
+		Success: true,
+	}, nil
+}
+
+// scheduledShutdownStatus reports the shutdown scheduled with logind, if any
+func (e *Environment) scheduledShutdownStatus() (*core.Result, error) {
+	value, err := e.systemHandler.GetProperty(Login1, Login1Path, Login1ManagerInterface, "ScheduledShutdown")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled shutdown: %w", err)
+	}
+
+	// The property is a (type, usec) struct; an empty type means nothing is scheduled
+	fields, _ := value.([]interface{})
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("unexpected scheduled shutdown value: %v", value)
+	}
+	shutdownType, _ := fields[0].(string)
+	usec, _ := fields[1].(uint64)
+	if shutdownType == "" || usec == 0 {
+		return &core.Result{
+			Output:  "No shutdown is scheduled",
+			Success: true,
+		}, nil
+	}
+
+	at := time.UnixMicro(int64(usec))
+	return &core.Result{
+		Output:  fmt.Sprintf("Scheduled %s at %s", shutdownType, at.Format("15:04")),
+		Success: true,
+		Data: map[string]interface{}{
+			"type": shutdownType,
+			"at":   at,
+		},
+	}, nil
+}
+
+// parseShutdownTime parses a delay like 30m or 1h30m, or a time of day like
+// 22:30 which is taken to mean tomorrow once it has passed
+func parseShutdownTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("missing shutdown time (use e.g. 30m or 22:30)")
+	}
+
+	if delay, err := time.ParseDuration(value); err == nil {
+		if delay <= 0 {
+			return time.Time{}, fmt.Errorf("invalid shutdown delay: %s", value)
+		}
+		return now.Add(delay), nil
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid shutdown time: %s (use e.g. 30m or 22:30)", value)
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
@@ -56,6 +56,12 @@ func (e *Environment) executeSoundCommand(ctx context.Context, cmd *core.Command
 		mute := true
 		if cmd.Target == "false" || cmd.Target == "off" || cmd.Target == "0" {
 			mute = false
+		} else if cmd.Target == "toggle" {
+			current, err := e.GetMute(ctx)
+			if err != nil {
+				return nil, err
+			}
+			mute = !current
 		}
 		if err := e.SetMute(ctx, mute); err != nil {
 			return nil, err
@@ -162,6 +168,38 @@ func (e *Environment) executeSoundCommand(ctx context.Context, cmd *core.Command
 			Output:  fmt.Sprintf("Set default sound device to: %s", cmd.Target),
 			Success: true,
 		}, nil
+	case "switch-output":
+		if cmd.Target == "" {
+			return nil, fmt.Errorf("no output given, e.g. switch audio to headphones")
+		}
+		devices, err := e.GetSoundDevices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		device, err := MatchSoundOutput(devices, cmd.Target)
+		if err != nil {
+			return nil, err
+		}
+		// The sink name is stable, unlike its index, and no source shares it
+		if err := e.SetDefaultSoundDevice(ctx, device.Name); err != nil {
+			return nil, err
+		}
+		moved, err := moveSinkInputs(ctx, device.Name)
+		if err != nil {
+			return nil, fmt.Errorf("switched to %s but could not move playing sound: %w", device.Description, err)
+		}
+		output := fmt.Sprintf("Sound now plays through %s", device.Description)
+		if moved > 0 {
+			output += fmt.Sprintf(" (moved %d playing stream(s))", moved)
+		}
+		return &core.Result{
+			Output:  output,
+			Success: true,
+			Data: map[string]any{
+				"device": device,
+				"moved":  moved,
+			},
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported sound action: %s", cmd.Action)
 	}
@@ -883,7 +921,7 @@ func (e *Environment) isInputDevice(deviceID string) (bool, error) {
 		return e.inferDeviceTypeFromID(deviceID)
 	}
 
-	if strings.Contains(output, deviceID) {
+	if shortListHas(output, deviceID) {
 		return true, nil
 	}
 
@@ -895,7 +933,7 @@ func (e *Environment) isInputDevice(deviceID string) (bool, error) {
 		return e.inferDeviceTypeFromID(deviceID)
 	}
 
-	if strings.Contains(output, deviceID) {
+	if shortListHas(output, deviceID) {
 		return false, nil
 	}
 
@@ -903,6 +941,132 @@ func (e *Environment) isInputDevice(deviceID string) (bool, error) {
 	return e.inferDeviceTypeFromID(deviceID)
 }
 
+// shortListHas reports whether a "pactl list ... short" listing has a device
+// with the given index or name. Matching whole fields keeps a sink from
+// matching its ".monitor" source.
+func shortListHas(output, deviceID string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && (fields[0] == deviceID || fields[1] == deviceID) {
+			return true
+		}
+	}
+	return false
+}
+
+// outputSynonyms are the words a device description may use for what people
+// call an output
+var outputSynonyms = map[string][]string{
+	"headphones": {"headphone", "headset", "bluez", "a2dp", "earbuds", "buds"},
+	"headphone":  {"headphone", "headset", "bluez", "a2dp", "earbuds", "buds"},
+	"headset":    {"headset", "headphone", "bluez"},
+	"earbuds":    {"earbuds", "buds", "headphone", "bluez"},
+	"bluetooth":  {"bluez", "bluetooth"},
+	"speakers":   {"speaker", "analog"},
+	"speaker":    {"speaker", "analog"},
+	"laptop":     {"speaker", "analog", "built-in"},
+	"internal":   {"speaker", "analog", "built-in"},
+	"hdmi":       {"hdmi", "displayport"},
+	"tv":         {"hdmi", "displayport"},
+	"monitor":    {"hdmi", "displayport"},
+	"display":    {"hdmi", "displayport"},
+}
+
+// outputFillerWords are words in a spoken output name that say nothing
+// about which device is meant
+var outputFillerWords = map[string]bool{
+	"audio": true, "sound": true, "output": true, "device": true, "the": true, "my": true,
+}
+
+// MatchSoundOutput finds the output device a friendly name like
+// "headphones" or "hdmi" refers to. Each word of the name that appears in a
+// device's description or name, directly or through a synonym, counts
+// toward it; the device with the most matching words wins.
+func MatchSoundOutput(devices []core.SoundDevice, name string) (core.SoundDevice, error) {
+	var outputs []core.SoundDevice
+	for _, device := range devices {
+		if !device.IsInput {
+			outputs = append(outputs, device)
+		}
+	}
+	if len(outputs) == 0 {
+		return core.SoundDevice{}, fmt.Errorf("no sound outputs found")
+	}
+
+	wanted := strings.ToLower(strings.TrimSpace(name))
+	for _, device := range outputs {
+		if strings.EqualFold(device.Description, wanted) || device.Name == name || device.ID == name {
+			return device, nil
+		}
+	}
+
+	var words []string
+	for _, word := range strings.Fields(wanted) {
+		if !outputFillerWords[word] {
+			words = append(words, word)
+		}
+	}
+
+	var best []core.SoundDevice
+	bestScore := 0
+	for _, device := range outputs {
+		text := strings.ToLower(device.Description + " " + device.Name)
+		score := 0
+		for _, word := range words {
+			terms := append([]string{word}, outputSynonyms[word]...)
+			for _, term := range terms {
+				if strings.Contains(text, term) {
+					score++
+					break
+				}
+			}
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore = []core.SoundDevice{device}, score
+		case score == bestScore && score > 0:
+			best = append(best, device)
+		}
+	}
+
+	describe := func(devices []core.SoundDevice) string {
+		names := make([]string, len(devices))
+		for i, device := range devices {
+			names[i] = device.Description
+		}
+		return strings.Join(names, ", ")
+	}
+	switch len(best) {
+	case 0:
+		return core.SoundDevice{}, fmt.Errorf("no sound output matches %q (outputs: %s)", name, describe(outputs))
+	case 1:
+		return best[0], nil
+	}
+	return core.SoundDevice{}, fmt.Errorf("%q matches several outputs: %s", name, describe(best))
+}
+
+// moveSinkInputs moves the sound streams playing now to a sink, since
+// changing the default sink only affects new streams. It returns how many
+// streams were moved.
+func moveSinkInputs(ctx context.Context, sink string) (int, error) {
+	output, err := exec.CommandContext(ctx, "pactl", "list", "short", "sink-inputs").Output()
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := exec.CommandContext(ctx, "pactl", "move-sink-input", fields[0], sink).Run(); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
 // inferDeviceTypeFromID tries to infer if a device is an input device from its ID
 func (e *Environment) inferDeviceTypeFromID(deviceID string) (bool, error) {
 	// Common input device identifiers
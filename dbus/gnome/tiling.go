@@ -0,0 +1,299 @@
+package gnome
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// xrandrMonitorPattern matches a line of `xrandr --listmonitors`, e.g.
+// " 0: +*eDP-1 1920/344x1080/193+0+0  eDP-1"
+var xrandrMonitorPattern = regexp.MustCompile(`^\s*(\d+):\s+\+?(\*?)(\S+)\s+(\d+)/\d+x(\d+)/\d+\+(-?\d+)\+(-?\d+)`)
+
+// TileWindow tiles a window to a half or quarter of its monitor
+func (e *Environment) TileWindow(ctx context.Context, windowID string, position core.TilePosition) error {
+	window, err := e.findWindowGeometry(windowID)
+	if err != nil {
+		return err
+	}
+
+	monitors, err := e.GetMonitors(ctx)
+	if err != nil {
+		return err
+	}
+	area := monitorForWindow(monitors, window.Geometry).Geometry
+
+	halfW, halfH := area.Width/2, area.Height/2
+	var target core.WindowGeometry
+	switch position {
+	case core.TileLeft:
+		target = core.WindowGeometry{X: area.X, Y: area.Y, Width: halfW, Height: area.Height}
+	case core.TileRight:
+		target = core.WindowGeometry{X: area.X + halfW, Y: area.Y, Width: halfW, Height: area.Height}
+	case core.TileTop:
+		target = core.WindowGeometry{X: area.X, Y: area.Y, Width: area.Width, Height: halfH}
+	case core.TileBottom:
+		target = core.WindowGeometry{X: area.X, Y: area.Y + halfH, Width: area.Width, Height: halfH}
+	case core.TileTopLeft:
+		target = core.WindowGeometry{X: area.X, Y: area.Y, Width: halfW, Height: halfH}
+	case core.TileTopRight:
+		target = core.WindowGeometry{X: area.X + halfW, Y: area.Y, Width: halfW, Height: halfH}
+	case core.TileBottomLeft:
+		target = core.WindowGeometry{X: area.X, Y: area.Y + halfH, Width: halfW, Height: halfH}
+	case core.TileBottomRight:
+		target = core.WindowGeometry{X: area.X + halfW, Y: area.Y + halfH, Width: halfW, Height: halfH}
+	default:
+		return fmt.Errorf("unsupported tile position: %s", position)
+	}
+
+	return e.setWindowGeometry(window.ID, target)
+}
+
+// GetMonitors returns the connected monitors using xrandr
+func (e *Environment) GetMonitors(ctx context.Context) ([]core.Monitor, error) {
+	output, err := exec.Command("xrandr", "--listmonitors").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	monitors := parseXrandrMonitors(string(output))
+	if len(monitors) == 0 {
+		return nil, fmt.Errorf("no monitors found")
+	}
+	return monitors, nil
+}
+
+// MoveWindowToMonitor moves a window to another monitor, keeping its
+// position relative to the monitor's top left corner
+func (e *Environment) MoveWindowToMonitor(ctx context.Context, windowID string, monitor int) error {
+	window, err := e.findWindowGeometry(windowID)
+	if err != nil {
+		return err
+	}
+
+	monitors, err := e.GetMonitors(ctx)
+	if err != nil {
+		return err
+	}
+	if monitor < 0 || monitor >= len(monitors) {
+		return fmt.Errorf("monitor %d does not exist (found %d monitors)", monitor+1, len(monitors))
+	}
+
+	from := monitorForWindow(monitors, window.Geometry).Geometry
+	to := monitors[monitor].Geometry
+
+	target := window.Geometry
+	target.X = to.X + clamp(window.Geometry.X-from.X, 0, to.Width-window.Geometry.Width)
+	target.Y = to.Y + clamp(window.Geometry.Y-from.Y, 0, to.Height-window.Geometry.Height)
+	target.Width = min(window.Geometry.Width, to.Width)
+	target.Height = min(window.Geometry.Height, to.Height)
+
+	return e.setWindowGeometry(window.ID, target)
+}
+
+// MoveWindowToWorkspace moves a window to a workspace
+func (e *Environment) MoveWindowToWorkspace(ctx context.Context, windowID string, workspace int) error {
+	if workspace < 1 {
+		return fmt.Errorf("invalid workspace: %d", workspace)
+	}
+
+	window, err := e.findWindowGeometry(windowID)
+	if err != nil {
+		return err
+	}
+
+	// wmctrl numbers workspaces from 0
+	output, err := exec.Command("wmctrl", "-i", "-r", window.ID, "-t", strconv.Itoa(workspace-1)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to move window to workspace %d: %w (output: %s)", workspace, err, output)
+	}
+	return nil
+}
+
+// SwitchWorkspace switches to a workspace
+func (e *Environment) SwitchWorkspace(ctx context.Context, workspace int) error {
+	if workspace < 1 {
+		return fmt.Errorf("invalid workspace: %d", workspace)
+	}
+
+	output, err := exec.Command("wmctrl", "-s", strconv.Itoa(workspace-1)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to switch to workspace %d: %w (output: %s)", workspace, err, output)
+	}
+	return nil
+}
+
+// resolveMonitor turns a 1-based monitor number, or "next", into a 1-based monitor number
+func (e *Environment) resolveMonitor(ctx context.Context, windowID, monitor string) (int, error) {
+	if monitor != "next" {
+		number, err := strconv.Atoi(monitor)
+		if err != nil {
+			return 0, fmt.Errorf("invalid monitor: %s", monitor)
+		}
+		return number, nil
+	}
+
+	window, err := e.findWindowGeometry(windowID)
+	if err != nil {
+		return 0, err
+	}
+	monitors, err := e.GetMonitors(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	current := monitorForWindow(monitors, window.Geometry)
+	return (current.Index+1)%len(monitors) + 1, nil
+}
+
+// findWindowGeometry finds a window by ID, title, or "current" and returns its geometry
+func (e *Environment) findWindowGeometry(windowID string) (*core.Window, error) {
+	output, err := exec.Command("wmctrl", "-lG").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list windows: %w", err)
+	}
+
+	// Resolve the active window when no specific window was named
+	var activeID uint64
+	if windowID == "" || windowID == "current" {
+		activeID, err = activeWindowID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	search := strings.ToLower(windowID)
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: id desktop x y width height hostname title
+		fields := strings.Fields(line)
+		if len(fields) < 7 {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		title := strings.Join(fields[7:], " ")
+
+		var matches bool
+		switch {
+		case activeID != 0:
+			matches = id == activeID
+		case strings.HasPrefix(search, "0x"):
+			want, err := strconv.ParseUint(strings.TrimPrefix(search, "0x"), 16, 64)
+			matches = err == nil && id == want
+		default:
+			matches = strings.Contains(strings.ToLower(title), search)
+		}
+		if !matches {
+			continue
+		}
+
+		geometry := make([]int, 4)
+		for i := range geometry {
+			geometry[i], _ = strconv.Atoi(fields[2+i])
+		}
+
+		return &core.Window{
+			ID:    fields[0],
+			Title: title,
+			Geometry: core.WindowGeometry{
+				X:      geometry[0],
+				Y:      geometry[1],
+				Width:  geometry[2],
+				Height: geometry[3],
+			},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("window not found: %s", windowID)
+}
+
+// setWindowGeometry unmaximizes a window and moves it to the given geometry
+func (e *Environment) setWindowGeometry(windowID string, geometry core.WindowGeometry) error {
+	// A maximized window ignores geometry changes
+	if output, err := exec.Command("wmctrl", "-i", "-r", windowID, "-b", "remove,maximized_vert,maximized_horz").CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to unmaximize window: %w (output: %s)", err, output)
+	}
+
+	spec := fmt.Sprintf("0,%d,%d,%d,%d", geometry.X, geometry.Y, geometry.Width, geometry.Height)
+	if output, err := exec.Command("wmctrl", "-i", "-r", windowID, "-e", spec).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to move window: %w (output: %s)", err, output)
+	}
+	return nil
+}
+
+// activeWindowID returns the X11 ID of the focused window
+func activeWindowID() (uint64, error) {
+	output, err := exec.Command("xprop", "-root", "_NET_ACTIVE_WINDOW").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active window: %w", err)
+	}
+
+	// Format: _NET_ACTIVE_WINDOW(WINDOW): window id # 0x3a00007
+	idx := strings.LastIndex(string(output), "0x")
+	if idx == -1 {
+		return 0, fmt.Errorf("no active window found")
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(output)[idx+2:]), 16, 64)
+}
+
+// parseXrandrMonitors parses the output of `xrandr --listmonitors`
+func parseXrandrMonitors(output string) []core.Monitor {
+	var monitors []core.Monitor
+	for _, line := range strings.Split(output, "\n") {
+		match := xrandrMonitorPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		index, _ := strconv.Atoi(match[1])
+		width, _ := strconv.Atoi(match[4])
+		height, _ := strconv.Atoi(match[5])
+		x, _ := strconv.Atoi(match[6])
+		y, _ := strconv.Atoi(match[7])
+
+		monitors = append(monitors, core.Monitor{
+			Index:   index,
+			Name:    match[3],
+			Primary: match[2] == "*",
+			Geometry: core.WindowGeometry{
+				X:      x,
+				Y:      y,
+				Width:  width,
+				Height: height,
+			},
+		})
+	}
+	return monitors
+}
+
+// monitorForWindow returns the monitor containing the window's center
+func monitorForWindow(monitors []core.Monitor, window core.WindowGeometry) core.Monitor {
+	cx := window.X + window.Width/2
+	cy := window.Y + window.Height/2
+	for _, monitor := range monitors {
+		g := monitor.Geometry
+		if cx >= g.X && cx < g.X+g.Width && cy >= g.Y && cy < g.Y+g.Height {
+			return monitor
+		}
+	}
+	return monitors[0]
+}
+
+// clamp limits value to the range [low, high], preferring low when the range is empty
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
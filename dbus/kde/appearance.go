@@ -0,0 +1,258 @@
+package kde
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// Breeze color schemes used for dark mode
+const (
+	// DarkColorScheme is the color scheme applied when dark mode is enabled
+	DarkColorScheme = "BreezeDark"
+	// LightColorScheme is the color scheme applied when dark mode is disabled
+	LightColorScheme = "BreezeLight"
+)
+
+// executeAppearanceCommand executes an appearance management command
+func (e *Environment) executeAppearanceCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Action {
+	case "set-theme":
+		theme := cmd.Target
+		if theme == "" {
+			return nil, fmt.Errorf("color scheme name is required")
+		}
+		if err := e.SetGtkTheme(ctx, theme); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Set color scheme to: %s", theme),
+			Success: true,
+		}, nil
+	case "set-dark-mode":
+		enable := true
+		if cmd.Target == "false" || cmd.Target == "off" || cmd.Target == "0" {
+			enable = false
+		}
+
+		scheme := DarkColorScheme
+		if !enable {
+			scheme = LightColorScheme
+		}
+		if err := e.SetGtkTheme(ctx, scheme); err != nil {
+			return nil, err
+		}
+
+		return &core.Result{
+			Output:  fmt.Sprintf("Set dark mode to: %v", enable),
+			Success: true,
+		}, nil
+	case "set-background":
+		imagePath := cmd.Target
+		if imagePath == "" {
+			return nil, fmt.Errorf("background image path is required")
+		}
+		if err := e.SetDesktopBackground(ctx, imagePath); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Set desktop background to: %s", imagePath),
+			Success: true,
+		}, nil
+	case "set-accent-color":
+		color := cmd.Target
+		if color == "" {
+			return nil, fmt.Errorf("accent color is required")
+		}
+		if err := e.SetAccentColor(ctx, color); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Set accent color to: %s", color),
+			Success: true,
+		}, nil
+	case "set-icon-theme":
+		theme := cmd.Target
+		if theme == "" {
+			return nil, fmt.Errorf("icon theme name is required")
+		}
+		if err := e.SetIconTheme(ctx, theme); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Set icon theme to: %s", theme),
+			Success: true,
+		}, nil
+	case "get-theme":
+		theme, err := e.GetCurrentTheme(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Current color scheme: %s", theme),
+			Success: true,
+			Data: map[string]any{
+				"theme": theme,
+			},
+		}, nil
+	case "get-background":
+		background, err := e.GetCurrentBackground(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Current desktop background: %s", background),
+			Success: true,
+			Data: map[string]any{
+				"background": background,
+			},
+		}, nil
+	case "get-icon-theme":
+		theme, err := e.GetCurrentIconTheme(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Current icon theme: %s", theme),
+			Success: true,
+			Data: map[string]any{
+				"icon_theme": theme,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported appearance action: %s", cmd.Action)
+	}
+}
+
+// SetGtkTheme applies a Plasma color scheme, which Plasma also propagates to GTK applications
+func (e *Environment) SetGtkTheme(ctx context.Context, theme string) error {
+	if _, err := runTool(ctx, []string{"plasma-apply-colorscheme"}, theme); err != nil {
+		return fmt.Errorf("failed to apply color scheme: %w", err)
+	}
+	return nil
+}
+
+// SetDesktopBackground sets the desktop background image
+func (e *Environment) SetDesktopBackground(ctx context.Context, imagePath string) error {
+	// Verify the image file exists
+	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
+		return fmt.Errorf("background image does not exist: %s", imagePath)
+	}
+
+	absPath, err := filepath.Abs(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	if _, err := runTool(ctx, []string{"plasma-apply-wallpaperimage"}, absPath); err != nil {
+		return fmt.Errorf("failed to set desktop background: %w", err)
+	}
+	return nil
+}
+
+// SetAccentColor sets the accent color by reapplying the current color scheme with it
+func (e *Environment) SetAccentColor(ctx context.Context, color string) error {
+	if _, err := runTool(ctx, []string{"plasma-apply-colorscheme"}, "--accent-color", color); err != nil {
+		return fmt.Errorf("failed to set accent color (this needs Plasma 5.23 or newer): %w", err)
+	}
+	return nil
+}
+
+// SetIconTheme sets the icon theme
+func (e *Environment) SetIconTheme(ctx context.Context, theme string) error {
+	// plasma-changeicons also refreshes running applications, but most
+	// distributions keep it out of PATH
+	if _, err := runTool(ctx, []string{"plasma-changeicons"}, theme); err == nil {
+		return nil
+	}
+	if _, err := runTool(ctx, []string{"kwriteconfig6", "kwriteconfig5"},
+		"--file", "kdeglobals", "--group", "Icons", "--key", "Theme", theme); err != nil {
+		return fmt.Errorf("failed to set icon theme: %w", err)
+	}
+	return nil
+}
+
+// GetCurrentTheme gets the current color scheme
+func (e *Environment) GetCurrentTheme(ctx context.Context) (string, error) {
+	theme, err := readGlobalConfig(ctx, "General", "ColorScheme")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current color scheme: %w", err)
+	}
+	return theme, nil
+}
+
+// GetCurrentIconTheme gets the current icon theme
+func (e *Environment) GetCurrentIconTheme(ctx context.Context) (string, error) {
+	theme, err := readGlobalConfig(ctx, "Icons", "Theme")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current icon theme: %w", err)
+	}
+	if theme == "" {
+		// Plasma leaves the key unset while the default theme is in use
+		theme = "breeze"
+	}
+	return theme, nil
+}
+
+// GetCurrentBackground gets the current desktop background. Plasma stores
+// the wallpaper per desktop containment, so the first image found is used.
+func (e *Environment) GetCurrentBackground(ctx context.Context) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current desktop background: %w", err)
+	}
+
+	file, err := os.Open(filepath.Join(configDir, "plasma-org.kde.plasma.desktop-appletsrc"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get current desktop background: %w", err)
+	}
+	defer file.Close()
+
+	inWallpaper := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inWallpaper = strings.HasSuffix(line, "[Wallpaper][org.kde.image][General]")
+			continue
+		}
+		if inWallpaper && strings.HasPrefix(line, "Image=") {
+			return strings.TrimPrefix(strings.TrimPrefix(line, "Image="), "file://"), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to get current desktop background: %w", err)
+	}
+	return "", fmt.Errorf("no image wallpaper is set")
+}
+
+// readGlobalConfig reads a key from kdeglobals
+func readGlobalConfig(ctx context.Context, group, key string) (string, error) {
+	output, err := runTool(ctx, []string{"kreadconfig6", "kreadconfig5"},
+		"--file", "kdeglobals", "--group", group, "--key", key)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// runTool runs the first of the given Plasma tools found in PATH. Tools were
+// renamed between Plasma 5 and 6, so callers list both names.
+func runTool(ctx context.Context, names []string, args ...string) (string, error) {
+	path := firstCommand(names...)
+	if path == "" {
+		return "", fmt.Errorf("%s not found", names[0])
+	}
+
+	output, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("%w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
@@ -0,0 +1,627 @@
+package kde
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/dbus/common"
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/internal/desktop"
+)
+
+// DefaultWaitTimeout is how long a launch waits for a window when no timeout is given
+const DefaultWaitTimeout = 10 * time.Second
+
+// Environment implements the core.DesktopEnvironment interface for KDE Plasma
+type Environment struct {
+	*desktop.BaseEnvironment
+	sessionHandler core.DBusHandler
+	systemHandler  core.DBusHandler
+	// Keep a reference to the connections to prevent them from being closed
+	sessionConn common.DBusConnection
+	systemConn  common.DBusConnection
+}
+
+// NewEnvironment creates a new KDE Plasma desktop environment
+func NewEnvironment() (*Environment, error) {
+	// Create session DBus connection
+	sessionConn, err := common.NewDBusConnection(common.DBusTypeSession)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to session DBus: %w", err)
+	}
+
+	// Create system DBus connection
+	systemConn, err := common.NewDBusConnection(common.DBusTypeSystem)
+	if err != nil {
+		// Close the session connection if system connection fails
+		sessionConn.Close()
+		return nil, fmt.Errorf("failed to connect to system DBus: %w", err)
+	}
+
+	// Create DBus handlers
+	sessionHandler := common.NewDBusHandler(sessionConn)
+	systemHandler := common.NewDBusHandler(systemConn)
+
+	// Define capabilities
+	capabilities := []core.Capability{
+		core.CapabilityWindowManagement,
+		core.CapabilityApplicationLaunch,
+		core.CapabilityNotifications,
+		core.CapabilityMediaControl,
+		core.CapabilityAppearanceManagement,
+	}
+
+	// Create base environment
+	baseEnv := desktop.NewBaseEnvironment("kde", capabilities, sessionHandler)
+
+	return &Environment{
+		BaseEnvironment: baseEnv,
+		sessionHandler:  sessionHandler,
+		systemHandler:   systemHandler,
+		sessionConn:     sessionConn,
+		systemConn:      systemConn,
+	}, nil
+}
+
+// IsAvailable checks if KDE Plasma is available on the system
+func (e *Environment) IsAvailable() bool {
+	if e.sessionConn == nil {
+		return false
+	}
+
+	// Window management needs KWin; the Plasma shell tells KWin apart from
+	// other desktops that happen to run it
+	return common.IsDBusServiceAvailable(e.sessionConn, KWin) &&
+		common.IsDBusServiceAvailable(e.sessionConn, PlasmaShell)
+}
+
+// ExecuteCommand executes a desktop command
+func (e *Environment) ExecuteCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Type {
+	case core.CommandTypeWindow:
+		return e.executeWindowCommand(ctx, cmd)
+	case core.CommandTypeApplication:
+		return e.executeApplicationCommand(ctx, cmd)
+	case core.CommandTypeSystem:
+		return e.executeSystemCommand(ctx, cmd)
+	case core.CommandTypeNotification:
+		return e.executeNotificationCommand(ctx, cmd)
+	case core.CommandTypeMedia:
+		return e.executeMediaCommand(ctx, cmd)
+	case core.CommandTypeAppearance:
+		return e.executeAppearanceCommand(ctx, cmd)
+	default:
+		return nil, fmt.Errorf("%s commands are not supported on KDE Plasma yet", cmd.Type)
+	}
+}
+
+// executeWindowCommand executes a window management command
+func (e *Environment) executeWindowCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	var action func(context.Context, string) error
+	var done string
+	switch cmd.Action {
+	case "close":
+		action, done = e.CloseWindow, "Closed window"
+	case "minimize":
+		action, done = e.MinimizeWindow, "Minimized window"
+	case "maximize":
+		action, done = e.MaximizeWindow, "Maximized window"
+	case "restore":
+		action, done = e.RestoreWindow, "Restored window"
+	case "focus":
+		action, done = e.FocusWindow, "Focused window"
+	}
+	if action != nil {
+		if err := action(ctx, cmd.Target); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("%s: %s", done, cmd.Target),
+			Success: true,
+		}, nil
+	}
+
+	switch cmd.Action {
+	case "tile":
+		position := core.TilePosition(argumentString(cmd.Arguments, "position"))
+		if err := e.TileWindow(ctx, cmd.Target, position); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Tiled window %s: %s", position, cmd.Target),
+			Success: true,
+		}, nil
+	case "move-to-monitor":
+		value := argumentString(cmd.Arguments, "monitor")
+		var monitor int
+		var err error
+		if value == "next" {
+			monitor, err = e.nextMonitor(ctx, cmd.Target)
+		} else if monitor, err = strconv.Atoi(value); err != nil {
+			err = fmt.Errorf("invalid monitor: %s", value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := e.MoveWindowToMonitor(ctx, cmd.Target, monitor-1); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Moved window to monitor %d: %s", monitor, cmd.Target),
+			Success: true,
+		}, nil
+	case "move-to-workspace":
+		workspace, err := strconv.Atoi(argumentString(cmd.Arguments, "workspace"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace: %v", cmd.Arguments["workspace"])
+		}
+		if err := e.MoveWindowToWorkspace(ctx, cmd.Target, workspace); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Moved window to workspace %d: %s", workspace, cmd.Target),
+			Success: true,
+		}, nil
+	case "switch-workspace":
+		workspace, err := strconv.Atoi(cmd.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace: %s", cmd.Target)
+		}
+		if err := e.SwitchWorkspace(ctx, workspace); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Switched to workspace %d", workspace),
+			Success: true,
+		}, nil
+	case "list-monitors":
+		monitors, err := e.GetMonitors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var output strings.Builder
+		output.WriteString("Monitors:\n")
+		for _, monitor := range monitors {
+			output.WriteString(fmt.Sprintf("%d. %s %dx%d\n", monitor.Index+1, monitor.Name,
+				monitor.Geometry.Width, monitor.Geometry.Height))
+		}
+		return &core.Result{
+			Output:  output.String(),
+			Success: true,
+			Data: map[string]interface{}{
+				"monitors": monitors,
+			},
+		}, nil
+	case "list":
+		windows, err := e.GetWindows(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var output strings.Builder
+		output.WriteString("Windows:\n")
+		for _, window := range windows {
+			output.WriteString(fmt.Sprintf("- %s (%s)\n", window.Title, window.Application))
+		}
+		return &core.Result{
+			Output:  output.String(),
+			Success: true,
+			Data: map[string]interface{}{
+				"windows": windows,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported window action: %s", cmd.Action)
+	}
+}
+
+// executeApplicationCommand executes an application management command
+func (e *Environment) executeApplicationCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Action {
+	case "launch":
+		opts := core.LaunchOptions{
+			Args:          strings.Fields(argumentString(cmd.Arguments, "args")),
+			NewInstance:   argumentString(cmd.Arguments, "new_instance") == "true",
+			WaitForWindow: argumentString(cmd.Arguments, "wait") == "true",
+		}
+		if timeout := argumentString(cmd.Arguments, "timeout"); timeout != "" {
+			duration, err := time.ParseDuration(timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout: %s", timeout)
+			}
+			opts.WaitTimeout = duration
+		}
+
+		window, err := e.LaunchApplicationWithOptions(ctx, cmd.Target, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &core.Result{
+			Output:  fmt.Sprintf("Launched application: %s", cmd.Target),
+			Success: true,
+		}
+		if window != nil {
+			result.Output += fmt.Sprintf(" (window %s)", window.ID)
+			result.Data = map[string]interface{}{
+				"window_id": window.ID,
+			}
+		}
+		return result, nil
+	case "list":
+		apps, err := e.GetRunningApplications(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var output strings.Builder
+		output.WriteString("Running applications:\n")
+		for _, app := range apps {
+			output.WriteString(fmt.Sprintf("- %s\n", app.Name))
+		}
+		return &core.Result{
+			Output:  output.String(),
+			Success: true,
+			Data: map[string]interface{}{
+				"applications": apps,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported application action: %s", cmd.Action)
+	}
+}
+
+// GetRunningApplications returns the applications that have windows open
+func (e *Environment) GetRunningApplications(ctx context.Context) ([]core.Application, error) {
+	windows, err := e.GetWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var apps []core.Application
+	for _, window := range windows {
+		if window.Application == "" || seen[window.Application] {
+			continue
+		}
+		seen[window.Application] = true
+		apps = append(apps, core.Application{
+			ID:      window.Application,
+			Name:    window.Application,
+			Running: true,
+		})
+	}
+	return apps, nil
+}
+
+// LaunchApplication launches an application
+func (e *Environment) LaunchApplication(ctx context.Context, appName string, args ...string) error {
+	_, err := e.LaunchApplicationWithOptions(ctx, appName, core.LaunchOptions{Args: args})
+	return err
+}
+
+// LaunchApplicationWithOptions launches an application. A running instance
+// is focused instead of spawning a new process unless NewInstance is set or
+// arguments are given.
+func (e *Environment) LaunchApplicationWithOptions(ctx context.Context, appName string, opts core.LaunchOptions) (*core.Window, error) {
+	before, err := e.GetWindows(ctx)
+	if err != nil {
+		before = nil
+	}
+
+	if !opts.NewInstance && len(opts.Args) == 0 {
+		if window := findAppWindow(before, appName); window != nil {
+			if err := e.FocusWindow(ctx, window.ID); err != nil {
+				return nil, fmt.Errorf("failed to activate %s: %w", appName, err)
+			}
+			return window, nil
+		}
+	}
+
+	if err := spawnApplication(appName, opts.Args); err != nil {
+		return nil, err
+	}
+	if !opts.WaitForWindow {
+		return nil, nil
+	}
+
+	timeout := opts.WaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	return e.waitForAppWindow(ctx, appName, before, timeout)
+}
+
+// spawnApplication starts an application, preferring a binary of that name
+// and falling back to kstart, which resolves desktop file names
+func spawnApplication(appName string, args []string) error {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath(appName); err == nil {
+		cmd = exec.Command(path, args...)
+	} else if kstart := firstCommand("kstart", "kstart5"); kstart != "" {
+		cmd = exec.Command(kstart, append([]string{"--application", appName, "--"}, args...)...)
+	} else {
+		return fmt.Errorf("failed to launch application: %s not found", appName)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to launch application: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// waitForAppWindow polls until the application has a window that was not open before
+func (e *Environment) waitForAppWindow(ctx context.Context, appName string, before []core.Window, timeout time.Duration) (*core.Window, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	known := make(map[string]bool, len(before))
+	for _, window := range before {
+		known[window.ID] = true
+	}
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s did not open a window within %s", appName, timeout)
+		case <-ticker.C:
+			windows, err := e.GetWindows(ctx)
+			if err != nil {
+				continue
+			}
+			var fresh []core.Window
+			for _, window := range windows {
+				if !known[window.ID] {
+					fresh = append(fresh, window)
+				}
+			}
+			if window := findAppWindow(fresh, appName); window != nil {
+				return window, nil
+			}
+		}
+	}
+}
+
+// executeSystemCommand executes a system command
+func (e *Environment) executeSystemCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Action {
+	case "shutdown":
+		if err := e.endSession("logoutAndShutdown", 2); err != nil {
+			return nil, fmt.Errorf("failed to shut down: %w", err)
+		}
+		return &core.Result{
+			Output:  "System is shutting down",
+			Success: true,
+		}, nil
+	case "restart":
+		if err := e.endSession("logoutAndReboot", 1); err != nil {
+			return nil, fmt.Errorf("failed to restart: %w", err)
+		}
+		return &core.Result{
+			Output:  "System is restarting",
+			Success: true,
+		}, nil
+	case "logout":
+		if err := e.endSession("logout", 0); err != nil {
+			return nil, fmt.Errorf("failed to log out: %w", err)
+		}
+		return &core.Result{
+			Output:  "Logging out",
+			Success: true,
+		}, nil
+	case "lock":
+		if _, err := e.sessionHandler.Call(ScreenSaver, ScreenSaverPath, ScreenSaverInterface, "Lock"); err != nil {
+			return nil, fmt.Errorf("failed to lock screen: %w", err)
+		}
+		return &core.Result{
+			Output:  "Screen locked",
+			Success: true,
+		}, nil
+	case "suspend", "hibernate":
+		method, check := "Suspend", "CanSuspend"
+		if cmd.Action == "hibernate" {
+			method, check = "Hibernate", "CanHibernate"
+		}
+		if err := e.checkLoginCapability(check, cmd.Action); err != nil {
+			return nil, err
+		}
+		// Interactive, so polkit may ask the user for authorization
+		if _, err := e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface, method, true); err != nil {
+			return nil, fmt.Errorf("failed to %s: %w", cmd.Action, err)
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("System is %s", map[string]string{"suspend": "suspending", "hibernate": "hibernating"}[cmd.Action]),
+			Success: true,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported system action: %s", cmd.Action)
+	}
+}
+
+// endSession ends the Plasma session through org.kde.Shutdown on Plasma 6,
+// or KSMServer's logout(confirm, type, mode) on Plasma 5
+func (e *Environment) endSession(method string, shutdownType int32) error {
+	if _, err := e.sessionHandler.Call(Shutdown, ShutdownPath, ShutdownInterface, method); err == nil {
+		return nil
+	}
+	// No confirmation dialog, and the default shutdown mode
+	_, err := e.sessionHandler.Call(KSMServer, KSMServerPath, KSMServerInterface, "logout", int32(0), shutdownType, int32(-1))
+	return err
+}
+
+// checkLoginCapability asks logind whether a power action is available
+func (e *Environment) checkLoginCapability(method, action string) error {
+	result, err := e.systemHandler.Call(Login1, Login1Path, Login1ManagerInterface, method)
+	if err != nil {
+		return fmt.Errorf("failed to check whether %s is available: %w", action, err)
+	}
+	if len(result) > 0 {
+		switch answer, _ := result[0].(string); answer {
+		case "no", "na":
+			return fmt.Errorf("%s is not available on this system", action)
+		}
+	}
+	return nil
+}
+
+// executeNotificationCommand executes a notification command
+func (e *Environment) executeNotificationCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	switch cmd.Action {
+	case "send":
+		id, err := e.SendNotification(ctx, cmd.Target, argumentString(cmd.Arguments, "body"), argumentString(cmd.Arguments, "icon"))
+		if err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Notification sent (ID: %d)", id),
+			Success: true,
+			Data: map[string]interface{}{
+				"notification_id": id,
+			},
+		}, nil
+	case "close":
+		id, err := strconv.ParseUint(cmd.Target, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification ID: %s", cmd.Target)
+		}
+		if err := e.CloseNotification(ctx, uint32(id)); err != nil {
+			return nil, err
+		}
+		return &core.Result{
+			Output:  fmt.Sprintf("Notification closed (ID: %d)", id),
+			Success: true,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notification action: %s", cmd.Action)
+	}
+}
+
+// SendNotification sends a notification through org.freedesktop.Notifications
+func (e *Environment) SendNotification(ctx context.Context, summary, body, icon string) (uint32, error) {
+	result, err := e.sessionHandler.Call(
+		Notifications,
+		NotificationsPath,
+		NotificationsInterface,
+		"Notify",
+		"Lumo",                   // Application name
+		uint32(0),                // Replaces ID (0 = new notification)
+		icon,                     // Icon
+		summary,                  // Summary
+		body,                     // Body
+		[]string{},               // Actions
+		map[string]interface{}{}, // Hints
+		int32(5000),              // Timeout (5 seconds)
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	if len(result) > 0 {
+		if id, ok := result[0].(uint32); ok {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("failed to get notification ID")
+}
+
+// CloseNotification closes a notification
+func (e *Environment) CloseNotification(ctx context.Context, id uint32) error {
+	if _, err := e.sessionHandler.Call(Notifications, NotificationsPath, NotificationsInterface, "CloseNotification", id); err != nil {
+		return fmt.Errorf("failed to close notification: %w", err)
+	}
+	return nil
+}
+
+// mediaActions maps media actions to MPRIS methods and their results
+var mediaActions = map[string][2]string{
+	"play":     {"Play", "Media playback started"},
+	"pause":    {"Pause", "Media playback paused"},
+	"stop":     {"Stop", "Media playback stopped"},
+	"next":     {"Next", "Skipped to next track"},
+	"previous": {"Previous", "Skipped to previous track"},
+}
+
+// executeMediaCommand sends a media control command to the chosen MPRIS
+// player, or the one playing
+func (e *Environment) executeMediaCommand(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	services, err := common.ListDBusServices(e.sessionConn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DBus services: %w", err)
+	}
+	players := common.MediaPlayers(services)
+
+	var output string
+	switch cmd.Action {
+	case "set-player":
+		// The player can be chosen before it starts
+		output, err = common.SetPlayerResult(players, cmd.Target)
+	case "now-playing", "seek", "play", "pause", "stop", "next", "previous":
+		output, err = e.controlMedia(players, cmd)
+	default:
+		return nil, fmt.Errorf("unsupported media action: %s", cmd.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &core.Result{
+		Output:  output,
+		Success: true,
+	}, nil
+}
+
+// controlMedia runs a media action on the player media commands control
+func (e *Environment) controlMedia(players []string, cmd *core.Command) (string, error) {
+	player, err := common.ChoosePlayer(e.sessionHandler, players, common.PreferredPlayer())
+	if err != nil {
+		return "", err
+	}
+
+	switch cmd.Action {
+	case "now-playing":
+		nowPlaying, err := common.ReadNowPlaying(e.sessionHandler, player)
+		if err != nil {
+			return "", err
+		}
+		return nowPlaying.String(), nil
+	case "seek":
+		offset, err := common.ParseSeekOffset(cmd.Target)
+		if err != nil {
+			return "", err
+		}
+		position, err := common.Seek(e.sessionHandler, player, offset)
+		if err != nil {
+			return "", err
+		}
+		return common.SeekResult(offset, position), nil
+	}
+
+	action := mediaActions[cmd.Action]
+	if _, err := e.sessionHandler.Call(player, MediaPlayerPath, MediaPlayerPlayerInterface, action[0]); err != nil {
+		return "", fmt.Errorf("failed to %s media: %w", cmd.Action, err)
+	}
+	return action[1], nil
+}
+
+// firstCommand returns the first of the given commands found in PATH
+func firstCommand(names ...string) string {
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// argumentString returns a command argument as a string. Arguments parsed
+// from AI output are strings, while the pattern matcher may store other types.
+func argumentString(args map[string]interface{}, key string) string {
+	value, ok := args[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,412 @@
+package kde
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	// kwinReplyInterface is the interface KWin scripts call to hand results back
+	kwinReplyInterface = "org.lumo.KWinReply"
+	// kwinScriptTimeout bounds how long a script may take to reply
+	kwinScriptTimeout = 5 * time.Second
+)
+
+// kwinPrelude holds helpers shared by every script. It papers over the
+// Plasma 5 and Plasma 6 scripting APIs (clientList/windowList,
+// activeClient/activeWindow, numeric/object desktops).
+const kwinPrelude = `
+const lumoWindows = () => (workspace.windowList ? workspace.windowList() : workspace.clientList()).filter(w => w.normalWindow);
+const lumoActive = () => workspace.activeWindow !== undefined ? workspace.activeWindow : workspace.activeClient;
+const lumoActivate = w => { if (workspace.activeWindow !== undefined) { workspace.activeWindow = w; } else { workspace.activeClient = w; } };
+const lumoId = w => String(w.internalId);
+const lumoFind = target => {
+    if (target === "" || target === "current") { return lumoActive(); }
+    const t = target.toLowerCase();
+    const ws = lumoWindows();
+    return ws.find(w => lumoId(w) === target) ||
+        ws.find(w => w.caption.toLowerCase() === t) ||
+        ws.find(w => String(w.resourceClass).toLowerCase() === t) ||
+        ws.find(w => w.caption.toLowerCase().includes(t) || String(w.resourceClass).toLowerCase().includes(t));
+};
+const lumoScreens = () => Array.isArray(workspace.screens) ? workspace.screens : [...Array(workspace.numScreens).keys()];
+const lumoScreenIndex = w => typeof w.output === "object" ? lumoScreens().indexOf(w.output) : w.screen;
+const lumoDesktops = () => Array.isArray(workspace.desktops) ? workspace.desktops : [...Array(workspace.desktops).keys()].map(i => i + 1);
+const lumoGeometry = g => ({x: Math.round(g.x), y: Math.round(g.y), width: Math.round(g.width), height: Math.round(g.height)});
+const lumoReply = data => callDBus(LUMO_SERVICE, LUMO_PATH, "` + kwinReplyInterface + `", "Reply", JSON.stringify(data));
+const lumoWindow = target => {
+    const w = lumoFind(target);
+    if (!w) { lumoReply({error: "window not found: " + target}); }
+    return w;
+};
+`
+
+// kwinReceiver receives the reply of a single KWin script
+type kwinReceiver struct {
+	replies chan string
+}
+
+// Reply is called by the script with its result encoded as JSON
+func (r *kwinReceiver) Reply(data string) *dbus.Error {
+	select {
+	case r.replies <- data:
+	default:
+	}
+	return nil
+}
+
+// kwinReply is the JSON a script sends back
+type kwinReply struct {
+	Error string          `json:"error"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// kwinWindow is a window as described by the scripts
+type kwinWindow struct {
+	ID         string              `json:"id"`
+	Title      string              `json:"title"`
+	Class      string              `json:"class"`
+	Geometry   core.WindowGeometry `json:"geometry"`
+	Maximized  bool                `json:"maximized"`
+	Minimized  bool                `json:"minimized"`
+	Fullscreen bool                `json:"fullscreen"`
+	Active     bool                `json:"active"`
+}
+
+// kwinWindowJSON describes a window w for the reply
+const kwinWindowJSON = `({id: lumoId(w), title: w.caption, class: String(w.resourceClass),
+    geometry: lumoGeometry(w.frameGeometry), minimized: w.minimized, fullscreen: w.fullScreen,
+    maximized: (() => { const a = workspace.clientArea(KWin.MaximizeArea, w); return w.frameGeometry.width >= a.width && w.frameGeometry.height >= a.height; })(),
+    active: w === lumoActive()})`
+
+func (w kwinWindow) toCore() core.Window {
+	return core.Window{
+		ID:          w.ID,
+		Title:       w.Title,
+		Application: w.Class,
+		Geometry:    w.Geometry,
+		State: core.WindowState{
+			Maximized:  w.Maximized,
+			Minimized:  w.Minimized,
+			Fullscreen: w.Fullscreen,
+			Active:     w.Active,
+		},
+	}
+}
+
+// jsString quotes a Go string as a JavaScript string literal
+func jsString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// runKWinScript loads a script into KWin, runs it, and decodes the data it
+// replies with into out, which may be nil. Scripts must call lumoReply
+// exactly once.
+func (e *Environment) runKWinScript(ctx context.Context, body string, out interface{}) error {
+	conn := e.sessionConn.GetConn()
+	names := conn.Names()
+	if len(names) == 0 {
+		return fmt.Errorf("session bus connection has no name")
+	}
+
+	// Each script gets its own reply object so concurrent scripts can't mix up replies
+	id := time.Now().UnixNano()
+	replyPath := dbus.ObjectPath(fmt.Sprintf("/org/lumo/KWinReply/r%d", id))
+	receiver := &kwinReceiver{replies: make(chan string, 1)}
+	if err := conn.Export(receiver, replyPath, kwinReplyInterface); err != nil {
+		return fmt.Errorf("failed to export KWin reply object: %w", err)
+	}
+	defer conn.Export(nil, replyPath, kwinReplyInterface)
+
+	script := fmt.Sprintf("const LUMO_SERVICE = %s;\nconst LUMO_PATH = %s;\n%s\n%s\n",
+		jsString(names[0]), jsString(string(replyPath)), kwinPrelude, body)
+
+	file, err := os.CreateTemp("", "lumo-kwin-*.js")
+	if err != nil {
+		return fmt.Errorf("failed to write KWin script: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(script); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write KWin script: %w", err)
+	}
+	file.Close()
+
+	pluginName := fmt.Sprintf("lumo-%d", id)
+	result, err := e.sessionHandler.Call(KWin, KWinScriptingPath, KWinScriptingInterface, "loadScript", file.Name(), pluginName)
+	if err != nil {
+		return fmt.Errorf("failed to load KWin script: %w", err)
+	}
+	defer e.sessionHandler.Call(KWin, KWinScriptingPath, KWinScriptingInterface, "unloadScript", pluginName)
+
+	scriptID, ok := result[0].(int32)
+	if !ok || scriptID < 0 {
+		return fmt.Errorf("KWin rejected the script")
+	}
+
+	// Plasma 6 puts loaded scripts under /Scripting, Plasma 5 at the root
+	if _, err := e.sessionHandler.Call(KWin, fmt.Sprintf("%s/Script%d", KWinScriptingPath, scriptID), KWinScriptInterface, "run"); err != nil {
+		if _, err := e.sessionHandler.Call(KWin, fmt.Sprintf("/%d", scriptID), KWinScriptInterface, "run"); err != nil {
+			return fmt.Errorf("failed to run KWin script: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kwinScriptTimeout)
+	defer cancel()
+
+	var data string
+	select {
+	case data = <-receiver.replies:
+	case <-ctx.Done():
+		return fmt.Errorf("KWin script did not reply: %w", ctx.Err())
+	}
+
+	var reply kwinReply
+	if err := json.Unmarshal([]byte(data), &reply); err != nil {
+		return fmt.Errorf("invalid reply from KWin script: %w", err)
+	}
+	if reply.Error != "" {
+		return fmt.Errorf("%s", reply.Error)
+	}
+	if out != nil && len(reply.Data) > 0 {
+		if err := json.Unmarshal(reply.Data, out); err != nil {
+			return fmt.Errorf("invalid reply from KWin script: %w", err)
+		}
+	}
+	return nil
+}
+
+// withWindow runs a script body against the window matching windowID,
+// which is bound to w. The body must reply itself.
+func (e *Environment) withWindow(ctx context.Context, windowID, body string) error {
+	script := fmt.Sprintf("const w = lumoWindow(%s);\nif (w) {\n%s\n}", jsString(windowID), body)
+	return e.runKWinScript(ctx, script, nil)
+}
+
+// GetWindows returns a list of all windows
+func (e *Environment) GetWindows(ctx context.Context) ([]core.Window, error) {
+	var windows []kwinWindow
+	if err := e.runKWinScript(ctx, "lumoReply({data: lumoWindows().map(w => "+kwinWindowJSON+")});", &windows); err != nil {
+		return nil, fmt.Errorf("failed to list windows: %w", err)
+	}
+
+	result := make([]core.Window, len(windows))
+	for i, window := range windows {
+		result[i] = window.toCore()
+	}
+	return result, nil
+}
+
+// GetActiveWindow returns the currently active window
+func (e *Environment) GetActiveWindow(ctx context.Context) (*core.Window, error) {
+	var window *kwinWindow
+	script := "const w = lumoActive();\nlumoReply({data: w ? " + kwinWindowJSON + " : null});"
+	if err := e.runKWinScript(ctx, script, &window); err != nil {
+		return nil, fmt.Errorf("failed to get active window: %w", err)
+	}
+	if window == nil {
+		return nil, fmt.Errorf("no active window found")
+	}
+	active := window.toCore()
+	return &active, nil
+}
+
+// CloseWindow closes a window
+func (e *Environment) CloseWindow(ctx context.Context, windowID string) error {
+	if err := e.withWindow(ctx, windowID, "w.closeWindow(); lumoReply({});"); err != nil {
+		return fmt.Errorf("failed to close window: %w", err)
+	}
+	return nil
+}
+
+// MinimizeWindow minimizes a window
+func (e *Environment) MinimizeWindow(ctx context.Context, windowID string) error {
+	if err := e.withWindow(ctx, windowID, "w.minimized = true; lumoReply({});"); err != nil {
+		return fmt.Errorf("failed to minimize window: %w", err)
+	}
+	return nil
+}
+
+// MaximizeWindow maximizes a window
+func (e *Environment) MaximizeWindow(ctx context.Context, windowID string) error {
+	if err := e.withWindow(ctx, windowID, "w.setMaximize(true, true); lumoReply({});"); err != nil {
+		return fmt.Errorf("failed to maximize window: %w", err)
+	}
+	return nil
+}
+
+// RestoreWindow restores a window
+func (e *Environment) RestoreWindow(ctx context.Context, windowID string) error {
+	if err := e.withWindow(ctx, windowID, "w.minimized = false; w.setMaximize(false, false); lumoReply({});"); err != nil {
+		return fmt.Errorf("failed to restore window: %w", err)
+	}
+	return nil
+}
+
+// MoveWindow moves a window to a new position
+func (e *Environment) MoveWindow(ctx context.Context, windowID string, x, y int) error {
+	body := fmt.Sprintf("const g = w.frameGeometry;\nw.frameGeometry = {x: %d, y: %d, width: g.width, height: g.height};\nlumoReply({});", x, y)
+	if err := e.withWindow(ctx, windowID, body); err != nil {
+		return fmt.Errorf("failed to move window: %w", err)
+	}
+	return nil
+}
+
+// ResizeWindow resizes a window
+func (e *Environment) ResizeWindow(ctx context.Context, windowID string, width, height int) error {
+	body := fmt.Sprintf("const g = w.frameGeometry;\nw.frameGeometry = {x: g.x, y: g.y, width: %d, height: %d};\nlumoReply({});", width, height)
+	if err := e.withWindow(ctx, windowID, body); err != nil {
+		return fmt.Errorf("failed to resize window: %w", err)
+	}
+	return nil
+}
+
+// FocusWindow focuses a window
+func (e *Environment) FocusWindow(ctx context.Context, windowID string) error {
+	if err := e.withWindow(ctx, windowID, "w.minimized = false; lumoActivate(w); lumoReply({});"); err != nil {
+		return fmt.Errorf("failed to focus window: %w", err)
+	}
+	return nil
+}
+
+// tileFractions gives each tile position as x, y, width, and height
+// fractions of the work area
+var tileFractions = map[core.TilePosition][4]float64{
+	core.TileLeft:        {0, 0, 0.5, 1},
+	core.TileRight:       {0.5, 0, 0.5, 1},
+	core.TileTop:         {0, 0, 1, 0.5},
+	core.TileBottom:      {0, 0.5, 1, 0.5},
+	core.TileTopLeft:     {0, 0, 0.5, 0.5},
+	core.TileTopRight:    {0.5, 0, 0.5, 0.5},
+	core.TileBottomLeft:  {0, 0.5, 0.5, 0.5},
+	core.TileBottomRight: {0.5, 0.5, 0.5, 0.5},
+}
+
+// TileWindow tiles a window to a half or quarter of its monitor's work area
+func (e *Environment) TileWindow(ctx context.Context, windowID string, position core.TilePosition) error {
+	f, ok := tileFractions[position]
+	if !ok {
+		return fmt.Errorf("unsupported tile position: %s", position)
+	}
+
+	body := fmt.Sprintf(`w.setMaximize(false, false);
+const a = workspace.clientArea(KWin.MaximizeArea, w);
+w.frameGeometry = {x: Math.round(a.x + a.width * %g), y: Math.round(a.y + a.height * %g),
+    width: Math.round(a.width * %g), height: Math.round(a.height * %g)};
+lumoReply({});`, f[0], f[1], f[2], f[3])
+	if err := e.withWindow(ctx, windowID, body); err != nil {
+		return fmt.Errorf("failed to tile window: %w", err)
+	}
+	return nil
+}
+
+// GetMonitors returns the connected monitors
+func (e *Environment) GetMonitors(ctx context.Context) ([]core.Monitor, error) {
+	var monitors []struct {
+		Name     string              `json:"name"`
+		Geometry core.WindowGeometry `json:"geometry"`
+	}
+	script := `lumoReply({data: lumoScreens().map((s, i) => ({
+    name: typeof s === "object" ? s.name : "Screen " + (i + 1),
+    geometry: lumoGeometry(typeof s === "object" ? s.geometry : workspace.clientArea(KWin.ScreenArea, s, 1))}))});`
+	if err := e.runKWinScript(ctx, script, &monitors); err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+	if len(monitors) == 0 {
+		return nil, fmt.Errorf("no monitors found")
+	}
+
+	result := make([]core.Monitor, len(monitors))
+	for i, monitor := range monitors {
+		result[i] = core.Monitor{
+			Index:    i,
+			Name:     monitor.Name,
+			Geometry: monitor.Geometry,
+			// KWin lists the primary output first
+			Primary: i == 0,
+		}
+	}
+	return result, nil
+}
+
+// MoveWindowToMonitor moves a window to another monitor (0-based index)
+func (e *Environment) MoveWindowToMonitor(ctx context.Context, windowID string, monitor int) error {
+	body := fmt.Sprintf(`const screens = lumoScreens();
+if (%[1]d < 0 || %[1]d >= screens.length) {
+    lumoReply({error: "monitor " + (%[1]d + 1) + " does not exist"});
+} else {
+    workspace.sendClientToScreen(w, screens[%[1]d]);
+    lumoReply({});
+}`, monitor)
+	if err := e.withWindow(ctx, windowID, body); err != nil {
+		return fmt.Errorf("failed to move window to monitor: %w", err)
+	}
+	return nil
+}
+
+// nextMonitor returns the 1-based number of the monitor after the one the window is on
+func (e *Environment) nextMonitor(ctx context.Context, windowID string) (int, error) {
+	var next int
+	script := fmt.Sprintf("const w = lumoWindow(%s);\nif (w) { lumoReply({data: (lumoScreenIndex(w) + 1) %% lumoScreens().length + 1}); }", jsString(windowID))
+	if err := e.runKWinScript(ctx, script, &next); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// MoveWindowToWorkspace moves a window to a virtual desktop (1-based)
+func (e *Environment) MoveWindowToWorkspace(ctx context.Context, windowID string, workspace int) error {
+	body := fmt.Sprintf(`const desktops = lumoDesktops();
+if (%[1]d < 1 || %[1]d > desktops.length) {
+    lumoReply({error: "workspace %[1]d does not exist"});
+} else {
+    if (w.desktops !== undefined && typeof desktops[0] === "object") { w.desktops = [desktops[%[1]d - 1]]; } else { w.desktop = %[1]d; }
+    lumoReply({});
+}`, workspace)
+	if err := e.withWindow(ctx, windowID, body); err != nil {
+		return fmt.Errorf("failed to move window to workspace: %w", err)
+	}
+	return nil
+}
+
+// SwitchWorkspace switches to a virtual desktop (1-based)
+func (e *Environment) SwitchWorkspace(ctx context.Context, workspace int) error {
+	script := fmt.Sprintf(`const desktops = lumoDesktops();
+if (%[1]d < 1 || %[1]d > desktops.length) {
+    lumoReply({error: "workspace %[1]d does not exist"});
+} else {
+    workspace.currentDesktop = desktops[%[1]d - 1];
+    lumoReply({});
+}`, workspace)
+	if err := e.runKWinScript(ctx, script, nil); err != nil {
+		return fmt.Errorf("failed to switch workspace: %w", err)
+	}
+	return nil
+}
+
+// ShowDesktop shows the desktop
+func (e *Environment) ShowDesktop(ctx context.Context) error {
+	if err := e.runKWinScript(ctx, "workspace.showingDesktop = true; lumoReply({});", nil); err != nil {
+		return fmt.Errorf("failed to show desktop: %w", err)
+	}
+	return nil
+}
+
+// findAppWindow returns the first window whose class or title matches the application name
+func findAppWindow(windows []core.Window, appName string) *core.Window {
+	name := strings.ToLower(appName)
+	for i, window := range windows {
+		if strings.ToLower(window.Application) == name || strings.Contains(strings.ToLower(window.Title), name) {
+			return &windows[i]
+		}
+	}
+	return nil
+}
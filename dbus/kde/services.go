@@ -0,0 +1,63 @@
+package kde
+
+// DBus service names for KDE Plasma
+const (
+	// KWin is the KWin window manager service
+	KWin = "org.kde.KWin"
+	// PlasmaShell is the Plasma shell service
+	PlasmaShell = "org.kde.plasmashell"
+	// Shutdown is the Plasma 6 session shutdown service
+	Shutdown = "org.kde.Shutdown"
+	// KSMServer is the Plasma 5 session manager service
+	KSMServer = "org.kde.ksmserver"
+	// ScreenSaver is the freedesktop screen saver service, provided by KScreenLocker
+	ScreenSaver = "org.freedesktop.ScreenSaver"
+	// Notifications is the desktop notifications service
+	Notifications = "org.freedesktop.Notifications"
+	// MediaPlayer is the MPRIS media player service
+	MediaPlayer = "org.mpris.MediaPlayer2"
+)
+
+// DBus object paths for KDE Plasma
+const (
+	// KWinScriptingPath is the KWin scripting object path
+	KWinScriptingPath = "/Scripting"
+	// ShutdownPath is the Plasma 6 shutdown object path
+	ShutdownPath = "/Shutdown"
+	// KSMServerPath is the Plasma 5 session manager object path
+	KSMServerPath = "/KSMServer"
+	// ScreenSaverPath is the screen saver object path
+	ScreenSaverPath = "/ScreenSaver"
+	// NotificationsPath is the desktop notifications object path
+	NotificationsPath = "/org/freedesktop/Notifications"
+	// MediaPlayerPath is the MPRIS media player object path
+	MediaPlayerPath = "/org/mpris/MediaPlayer2"
+)
+
+// DBus interfaces for KDE Plasma
+const (
+	// KWinScriptingInterface is the KWin scripting interface
+	KWinScriptingInterface = "org.kde.kwin.Scripting"
+	// KWinScriptInterface is the interface of a loaded KWin script
+	KWinScriptInterface = "org.kde.kwin.Script"
+	// ShutdownInterface is the Plasma 6 shutdown interface
+	ShutdownInterface = "org.kde.Shutdown"
+	// KSMServerInterface is the Plasma 5 session manager interface
+	KSMServerInterface = "org.kde.KSMServerInterface"
+	// ScreenSaverInterface is the screen saver interface
+	ScreenSaverInterface = "org.freedesktop.ScreenSaver"
+	// NotificationsInterface is the desktop notifications interface
+	NotificationsInterface = "org.freedesktop.Notifications"
+	// MediaPlayerPlayerInterface is the MPRIS media player player interface
+	MediaPlayerPlayerInterface = "org.mpris.MediaPlayer2.Player"
+)
+
+// Login manager DBus service names
+const (
+	// Login1 is the systemd-logind service on the system bus
+	Login1 = "org.freedesktop.login1"
+	// Login1Path is the systemd-logind object path
+	Login1Path = "/org/freedesktop/login1"
+	// Login1ManagerInterface is the systemd-logind manager interface
+	Login1ManagerInterface = "org.freedesktop.login1.Manager"
+)
@@ -0,0 +1,269 @@
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// ActionPrefix starts the text form of a desktop action, e.g.
+// desktop:sound:set-volume 40 or desktop:notification:send "Done" --body="All tests passed"
+const ActionPrefix = "desktop:"
+
+// ActionSpec describes a desktop action that agent plans can use as a step
+type ActionSpec struct {
+	// Type is the command type the action belongs to
+	Type core.CommandType
+	// Action is the action name within its type
+	Action string
+	// Target describes the value the action takes, empty if it takes none
+	Target string
+	// Arguments are the optional named arguments the action accepts
+	Arguments []string
+	// Description is what the action does
+	Description string
+	// ReadOnly is set for actions that only report state
+	ReadOnly bool
+}
+
+// actionSpecs are the actions plans may use. Actions that end the session,
+// such as shutdown, are left out, since a plan must not run them unasked.
+var actionSpecs = []ActionSpec{
+	{Type: core.CommandTypeSound, Action: "set-volume", Target: "level 0-100", Description: "set the output volume"},
+	{Type: core.CommandTypeSound, Action: "get-volume", Description: "report the output volume", ReadOnly: true},
+	{Type: core.CommandTypeSound, Action: "set-mute", Target: "true, false or toggle", Description: "mute or unmute the output"},
+	{Type: core.CommandTypeSound, Action: "get-mute", Description: "report whether the output is muted", ReadOnly: true},
+	{Type: core.CommandTypeSound, Action: "set-input-volume", Target: "level 0-100", Description: "set the microphone volume"},
+	{Type: core.CommandTypeSound, Action: "set-input-mute", Target: "true or false", Description: "mute or unmute the microphone"},
+	{Type: core.CommandTypeSound, Action: "list-devices", Description: "list the sound devices", ReadOnly: true},
+	{Type: core.CommandTypeSound, Action: "switch-output", Target: "output name, e.g. headphones or hdmi", Description: "play sound through another output, including sound already playing"},
+	{Type: core.CommandTypeNotification, Action: "send", Target: "summary", Arguments: []string{"body", "icon"}, Description: "show a desktop notification"},
+	{Type: core.CommandTypeNotification, Action: "enable-dnd", Description: "hide notification banners until disable-dnd, keeping the notifications that arrive"},
+	{Type: core.CommandTypeNotification, Action: "disable-dnd", Description: "show notification banners again and list the ones missed"},
+	{Type: core.CommandTypeNotification, Action: "list-notifications", Description: "list the notifications missed during do not disturb", ReadOnly: true},
+	{Type: core.CommandTypeConnectivity, Action: "list-devices", Description: "list the network devices", ReadOnly: true},
+	{Type: core.CommandTypeConnectivity, Action: "enable-wifi", Description: "turn Wi-Fi on"},
+	{Type: core.CommandTypeConnectivity, Action: "disable-wifi", Description: "turn Wi-Fi off"},
+	{Type: core.CommandTypeConnectivity, Action: "wifi-status", Description: "report whether Wi-Fi is on", ReadOnly: true},
+	{Type: core.CommandTypeConnectivity, Action: "list-networks", Description: "list the Wi-Fi networks in range with their signal strength", ReadOnly: true},
+	{Type: core.CommandTypeConnectivity, Action: "connect-network", Target: "SSID", Arguments: []string{"password"}, Description: "connect to a Wi-Fi network"},
+	{Type: core.CommandTypeConnectivity, Action: "enable-vpn", Target: "VPN connection name", Description: "connect a saved VPN"},
+	{Type: core.CommandTypeConnectivity, Action: "disable-vpn", Description: "disconnect every connected VPN"},
+	{Type: core.CommandTypeConnectivity, Action: "vpn-status", Description: "report which VPN is connected", ReadOnly: true},
+	{Type: core.CommandTypeConnectivity, Action: "enable-bluetooth", Description: "turn Bluetooth on"},
+	{Type: core.CommandTypeConnectivity, Action: "disable-bluetooth", Description: "turn Bluetooth off"},
+	{Type: core.CommandTypeConnectivity, Action: "bluetooth-status", Description: "report whether Bluetooth is on", ReadOnly: true},
+	{Type: core.CommandTypeMedia, Action: "play", Description: "resume media playback"},
+	{Type: core.CommandTypeMedia, Action: "pause", Description: "pause media playback"},
+	{Type: core.CommandTypeMedia, Action: "next", Description: "skip to the next track"},
+	{Type: core.CommandTypeMedia, Action: "previous", Description: "go back to the previous track"},
+	{Type: core.CommandTypeMedia, Action: "now-playing", Description: "report the title, artist and position of what is playing", ReadOnly: true},
+	{Type: core.CommandTypeMedia, Action: "seek", Target: "seconds, negative to go back", Description: "move within the playing track"},
+	{Type: core.CommandTypeMedia, Action: "set-player", Target: "player name such as spotify or vlc, or auto", Description: "choose the player media actions control"},
+	{Type: core.CommandTypeAppearance, Action: "set-dark-mode", Target: "on or off", Description: "switch between dark and light mode"},
+	{Type: core.CommandTypeAppearance, Action: "set-background", Target: "image path", Description: "set the desktop background"},
+	{Type: core.CommandTypeAppearance, Action: "get-theme", Description: "report the current theme", ReadOnly: true},
+	{Type: core.CommandTypeFocus, Action: "on", Target: "duration such as 25m", Arguments: []string{"pause_media"}, Description: "silence notifications for a while"},
+	{Type: core.CommandTypeFocus, Action: "off", Description: "end focus mode"},
+	{Type: core.CommandTypeFocus, Action: "status", Description: "report whether focus mode is on", ReadOnly: true},
+	{Type: core.CommandTypeScreenshot, Action: "take", Target: "screen, window or area", Arguments: []string{"delay"}, Description: "save a screenshot to ~/Pictures"},
+	{Type: core.CommandTypeScreenshot, Action: "pick-color", Description: "pick a color on the screen and copy its hex code", ReadOnly: true},
+	{Type: core.CommandTypeApplication, Action: "launch", Target: "application name", Description: "start an application"},
+	{Type: core.CommandTypeWindow, Action: "list", Description: "list the open windows", ReadOnly: true},
+	{Type: core.CommandTypeSystem, Action: "lock", Description: "lock the screen"},
+}
+
+// LookupAction returns the spec of a desktop action plans may use
+func LookupAction(commandType core.CommandType, action string) (ActionSpec, bool) {
+	for _, spec := range actionSpecs {
+		if spec.Type == commandType && spec.Action == action {
+			return spec, true
+		}
+	}
+	return ActionSpec{}, false
+}
+
+// IsAction reports whether a plan step is a desktop action rather than a
+// shell command
+func IsAction(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), ActionPrefix)
+}
+
+// ParseAction parses the text form of a desktop action, checking it against
+// the actions plans may use
+func ParseAction(text string) (*core.Command, ActionSpec, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, ActionPrefix) {
+		return nil, ActionSpec{}, fmt.Errorf("not a desktop action: %s", text)
+	}
+
+	words, err := splitActionWords(strings.TrimPrefix(text, ActionPrefix))
+	if err != nil {
+		return nil, ActionSpec{}, err
+	}
+	if len(words) == 0 {
+		return nil, ActionSpec{}, fmt.Errorf("desktop action is missing its name")
+	}
+	commandType, action, _ := strings.Cut(words[0], ":")
+	spec, ok := LookupAction(core.CommandType(commandType), action)
+	if !ok {
+		return nil, ActionSpec{}, fmt.Errorf("unknown desktop action: %s", words[0])
+	}
+
+	cmd := &core.Command{
+		Type:      spec.Type,
+		Action:    spec.Action,
+		Arguments: make(map[string]interface{}),
+		RawInput:  text,
+	}
+	var target []string
+	for _, word := range words[1:] {
+		if !strings.HasPrefix(word, "--") {
+			target = append(target, word)
+			continue
+		}
+		name, value, _ := strings.Cut(strings.TrimPrefix(word, "--"), "=")
+		if !containsString(spec.Arguments, name) {
+			return nil, ActionSpec{}, fmt.Errorf("%s does not take --%s", words[0], name)
+		}
+		cmd.Arguments[name] = value
+	}
+	cmd.Target = strings.Join(target, " ")
+
+	if spec.Target != "" && cmd.Target == "" {
+		return nil, ActionSpec{}, fmt.Errorf("%s needs a %s", words[0], spec.Target)
+	}
+	if spec.Target == "" && cmd.Target != "" {
+		return nil, ActionSpec{}, fmt.Errorf("%s takes no value, got %q", words[0], cmd.Target)
+	}
+	return cmd, spec, nil
+}
+
+// FormatAction returns the text form of a desktop action
+func FormatAction(cmd *core.Command) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s%s:%s", ActionPrefix, cmd.Type, cmd.Action))
+	if cmd.Target != "" {
+		b.WriteString(" " + quoteActionWord(cmd.Target))
+	}
+
+	names := make([]string, 0, len(cmd.Arguments))
+	for name := range cmd.Arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf(" --%s=%s", name, quoteActionWord(fmt.Sprint(cmd.Arguments[name]))))
+	}
+	return b.String()
+}
+
+// ActionCatalog describes the actions plans may use, one per line, as the
+// JSON objects AI prompts ask for
+func ActionCatalog() string {
+	var b strings.Builder
+	for _, spec := range actionSpecs {
+		line := fmt.Sprintf(`{"type": "%s", "action": "%s"`, spec.Type, spec.Action)
+		if spec.Target != "" {
+			line += fmt.Sprintf(`, "target": "<%s>"`, spec.Target)
+		}
+		if len(spec.Arguments) > 0 {
+			arguments := make([]string, 0, len(spec.Arguments))
+			for _, argument := range spec.Arguments {
+				arguments = append(arguments, fmt.Sprintf(`"%s": "..."`, argument))
+			}
+			line += fmt.Sprintf(`, "arguments": {%s}`, strings.Join(arguments, ", "))
+		}
+		b.WriteString(fmt.Sprintf("- %s}: %s\n", line, spec.Description))
+	}
+	return b.String()
+}
+
+// ExecuteAction runs a desktop command that is already structured, such as a
+// plan step, asking first if it ends the session
+func (a *Assistant) ExecuteAction(ctx context.Context, cmd *core.Command) (*core.Result, error) {
+	if a.confirm != nil && RequiresConfirmation(cmd) && !a.confirm(cmd) {
+		return &core.Result{
+			Output:  fmt.Sprintf("Cancelled: system %s was not confirmed", cmd.Action),
+			Success: false,
+		}, nil
+	}
+
+	// Get the desktop environment
+	env, err := a.factory.DetectEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect desktop environment: %w", err)
+	}
+
+	// Execute the command
+	return env.ExecuteCommand(ctx, cmd)
+}
+
+// splitActionWords splits the text of an action into words, removing quotes
+func splitActionWords(text string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+			}
+			word.Reset()
+			inWord = false
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in desktop action")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// quoteActionWord quotes a value of an action if it would otherwise split
+func quoteActionWord(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"") {
+		return value
+	}
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// containsString reports whether a list contains a string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -46,6 +46,8 @@ Valid command types:
 - appearance (for appearance settings)
 - sound (for sound settings)
 - connectivity (for network connectivity settings)
+- focus (for focus mode / do not disturb)
+- screenshot (for taking screenshots)
 
 Valid actions for window:
 - close (close a window)
@@ -54,9 +56,14 @@ Valid actions for window:
 - restore (restore a window)
 - focus (focus a window)
 - list (list all windows)
+- tile (tile a window; add position=left|right|top|bottom|top-left|top-right|bottom-left|bottom-right)
+- move-to-monitor (move a window to another monitor; add monitor=<number> or monitor=next)
+- move-to-workspace (move a window to a workspace; add workspace=<number>)
+- switch-workspace (switch to the workspace given as TARGET)
+- list-monitors (list connected monitors)
 
 Valid actions for application:
-- launch (launch an application)
+- launch (launch an application; add args=<files or URIs>, wait=true to wait for its window, new_instance=true to skip reusing a running instance)
 - list (list all applications)
 
 Valid actions for system:
@@ -64,10 +71,18 @@ Valid actions for system:
 - restart (restart the system)
 - logout (logout the user)
 - lock (lock the screen)
+- suspend (suspend the system to RAM)
+- hibernate (hibernate the system to disk)
+- schedule-shutdown (shut down later; TARGET is a delay like 30m or a time like 22:30, add type=reboot to restart instead)
+- cancel-shutdown (cancel a scheduled shutdown)
+- shutdown-status (show the scheduled shutdown, if any)
 
 Valid actions for notification:
 - send (send a notification)
 - close (close a notification)
+- enable-dnd (hide notification banners until disable-dnd, keeping what arrives)
+- disable-dnd (show notification banners again and list what arrived meanwhile)
+- list-notifications (list the notifications that arrived during do not disturb)
 
 Valid actions for media:
 - play (play media)
@@ -75,6 +90,18 @@ Valid actions for media:
 - stop (stop media)
 - next (next track)
 - previous (previous track)
+- now-playing (show the title, artist, and position of what is playing)
+- seek (move within the track; TARGET is seconds, negative to go back)
+- set-player (control the named MPRIS player, like spotify or vlc, from now on; auto controls whichever is playing)
+
+Valid actions for focus:
+- on (enable do not disturb; TARGET is an optional duration like 90m, add pause_media=true to pause playing media)
+- off (end focus mode and restore notifications)
+- status (show whether focus mode is on)
+
+Valid actions for screenshot:
+- take (save a screenshot to ~/Pictures; TARGET is screen, window for the active window, or area for a selected area; add delay=<seconds> to wait first)
+- pick-color (pick a color on the screen, show it as hex, RGB, and HSL, and copy the hex code)
 
 Valid actions for appearance:
 - set-theme (set GTK theme)
@@ -85,6 +112,9 @@ Valid actions for appearance:
 - get-theme (get current GTK theme)
 - get-background (get current desktop background)
 - get-icon-theme (get current icon theme)
+- set-night-light (turn night light on/off)
+- set-color-temperature (set night light color temperature in Kelvin)
+- get-night-light (get night light state and color temperature)
 
 Valid actions for sound:
 - set-volume (set system volume level)
@@ -97,12 +127,19 @@ Valid actions for sound:
 - get-input-mute (get current microphone mute state)
 - list-devices (list available sound devices)
 - set-default-device (set default sound device)
+- switch-output (play sound through the output a friendly name like headphones or hdmi refers to)
 
 Valid actions for connectivity:
 - list-devices (list all network devices)
 - enable-wifi (enable WiFi)
 - disable-wifi (disable WiFi)
 - wifi-status (get WiFi status)
+- list-networks (list WiFi networks in range with signal strength)
+- connect-network (connect to a WiFi network by SSID, with an optional password argument)
+- forget-network (delete the saved connection to a WiFi network)
+- enable-vpn (connect a saved VPN connection by name)
+- disable-vpn (disconnect a VPN connection, or every connected VPN without a name)
+- vpn-status (get which VPN is connected)
 - enable-bluetooth (enable Bluetooth)
 - disable-bluetooth (disable Bluetooth)
 - bluetooth-status (get Bluetooth status)
@@ -116,9 +153,21 @@ Valid actions for connectivity:
 Examples:
 - "Close Firefox window" -> "window:close:firefox"
 - "Launch Terminal" -> "application:launch:gnome-terminal"
+- "Move firefox to workspace 2" -> "window:move-to-workspace:firefox:workspace=2"
+- "Snap the terminal to the left half" -> "window:tile:terminal:position=left"
 - "Lock the screen" -> "system:lock:"
+- "Turn off the computer in half an hour" -> "system:schedule-shutdown:30m"
+- "Do not disturb me for 90 minutes and pause the music" -> "focus:on:90m:pause_media=true"
+- "Take a screenshot of this window in 3 seconds" -> "screenshot:take:window:delay=3"
+- "What color is that button?" -> "screenshot:pick-color:"
 - "Send notification Hello World with body This is a test" -> "notification:send:Hello World:body=This is a test"
+- "Silence notifications until I say so" -> "notification:enable-dnd:"
+- "What notifications did I miss?" -> "notification:list-notifications:"
 - "Play media" -> "media:play:"
+- "What's playing?" -> "media:now-playing:"
+- "Skip ahead 30 seconds" -> "media:seek:30"
+- "Go back a minute" -> "media:seek:-60"
+- "Control Spotify from now on" -> "media:set-player:spotify"
 - "Launch Firefox and maximize it" -> "application:launch:firefox"
 - "Set dark mode on" -> "appearance:set-dark-mode:on"
 - "Change desktop background to /path/to/image.jpg" -> "appearance:set-background:/path/to/image.jpg"
@@ -128,9 +177,12 @@ Examples:
 - "Mute the sound" -> "sound:set-mute:true"
 - "Unmute the microphone" -> "sound:set-input-mute:false"
 - "Show sound devices" -> "sound:list-devices:"
+- "Switch audio to my headphones" -> "sound:switch-output:headphones"
 - "Set microphone volume to 75 percent" -> "sound:set-input-volume:75"
 - "Show all network devices" -> "connectivity:list-devices:"
 - "Turn on WiFi" -> "connectivity:enable-wifi:"
+- "Connect to the WiFi network HomeNet with password secret123" -> "connectivity:connect-network:HomeNet:password=secret123"
+- "Turn on my work VPN" -> "connectivity:enable-vpn:work"
 - "Turn off Bluetooth" -> "connectivity:disable-bluetooth:"
 - "Check airplane mode status" -> "connectivity:airplane-mode-status:"
 - "Create a WiFi hotspot with name MyHotspot" -> "connectivity:enable-hotspot:MyHotspot"
@@ -13,6 +13,21 @@ type Assistant struct {
 	factory core.DesktopFactory
 	// processor is the natural language processor
 	processor *Processor
+	// confirm is asked before commands that end the session, if set
+	confirm ConfirmFunc
+}
+
+// ConfirmFunc decides whether a command that ends the session may run
+type ConfirmFunc func(cmd *core.Command) bool
+
+// sessionEndingActions are the system actions that need confirmation
+var sessionEndingActions = map[string]bool{
+	"shutdown":          true,
+	"restart":           true,
+	"logout":            true,
+	"suspend":           true,
+	"hibernate":         true,
+	"schedule-shutdown": true,
 }
 
 // NewAssistant creates a new desktop assistant
@@ -31,6 +46,17 @@ func NewAssistantWithAI(factory core.DesktopFactory, aiClient AIClient) *Assista
 	}
 }
 
+// SetConfirmFunc sets the function asked before commands that end the session
+func (a *Assistant) SetConfirmFunc(confirm ConfirmFunc) {
+	a.confirm = confirm
+}
+
+// RequiresConfirmation reports whether a command ends the session, e.g. by
+// shutting down or suspending the system
+func RequiresConfirmation(cmd *core.Command) bool {
+	return cmd.Type == core.CommandTypeSystem && sessionEndingActions[cmd.Action]
+}
+
 // ProcessCommand processes a natural language command
 func (a *Assistant) ProcessCommand(ctx context.Context, input string) (*core.Result, error) {
 	// Process the input to extract the command
@@ -39,14 +65,7 @@ func (a *Assistant) ProcessCommand(ctx context.Context, input string) (*core.Res
 		return nil, fmt.Errorf("failed to process command: %w", err)
 	}
 
-	// Get the desktop environment
-	env, err := a.factory.DetectEnvironment()
-	if err != nil {
-		return nil, fmt.Errorf("failed to detect desktop environment: %w", err)
-	}
-
-	// Execute the command
-	return env.ExecuteCommand(ctx, cmd)
+	return a.ExecuteAction(ctx, cmd)
 }
 
 // GetSupportedCommands returns a list of supported commands
@@ -58,19 +77,41 @@ func (a *Assistant) GetSupportedCommands() []string {
 		"window:restore <window>",
 		"window:focus <window>",
 		"window:list",
-		"application:launch <app> [args]",
+		"window:tile <window> <left/right/top/bottom/top-left/...>",
+		"window:move-to-monitor <window> <number/next>",
+		"window:move-to-workspace <window> <number>",
+		"window:switch-workspace <number>",
+		"window:list-monitors",
+		"application:launch <app> [files/URIs] [--wait] [--timeout=10s] [--new-instance]",
 		"application:list",
 		"system:shutdown",
 		"system:restart",
 		"system:logout",
 		"system:lock",
+		"system:suspend",
+		"system:hibernate",
+		"shutdown in <delay>|at <hh:mm>",
+		"restart in <delay>|at <hh:mm>",
+		"cancel shutdown",
+		"shutdown status",
 		"notification:send <summary> [body] [icon]",
 		"notification:close <id>",
+		"notification:enable-dnd",
+		"notification:disable-dnd",
+		"notification:list-notifications",
 		"media:play",
 		"media:pause",
 		"media:stop",
 		"media:next",
 		"media:previous",
+		"media:now-playing",
+		"media:seek <seconds>",
+		"media:set-player <name>",
+		"focus:on [duration] [--pause-media]",
+		"focus:off",
+		"focus:status",
+		"take screenshot [of window|area] [in <seconds>s]",
+		"pick-color",
 		"appearance:set-theme <theme>",
 		"appearance:set-dark-mode <on/off>",
 		"appearance:set-background <path>",
@@ -79,20 +120,31 @@ func (a *Assistant) GetSupportedCommands() []string {
 		"appearance:get-theme",
 		"appearance:get-background",
 		"appearance:get-icon-theme",
+		"nightlight on|off",
+		"nightlight temp <kelvin>",
+		"nightlight status",
 		"sound:set-volume <level>",
 		"sound:get-volume",
 		"sound:set-mute <true/false>",
 		"sound:get-mute",
+		"toggle mute",
 		"sound:set-input-volume <level>",
 		"sound:get-input-volume",
 		"sound:set-input-mute <true/false>",
 		"sound:get-input-mute",
 		"sound:list-devices",
 		"sound:set-default-device <device-id>",
+		"switch audio to <output>",
 		"connectivity:list-devices",
 		"connectivity:enable-wifi",
 		"connectivity:disable-wifi",
 		"connectivity:wifi-status",
+		"connectivity:list-networks",
+		"connectivity:connect-network <ssid> [password]",
+		"connectivity:forget-network <ssid>",
+		"connectivity:enable-vpn <name>",
+		"connectivity:disable-vpn [name]",
+		"connectivity:vpn-status",
 		"connectivity:enable-bluetooth",
 		"connectivity:disable-bluetooth",
 		"connectivity:bluetooth-status",
@@ -112,18 +164,39 @@ func (a *Assistant) GetCommandExamples() []string {
 		"Minimize all windows",
 		"Maximize the current window",
 		"Show all open windows",
+		"Tile firefox to the left half",
+		"Snap terminal to the top right corner",
+		"Move firefox to workspace 2",
+		"Switch to workspace 3",
+		"Move code to the next monitor",
 		"Launch Firefox",
 		"Open Terminal",
+		"Open firefox https://example.com --wait",
 		"List running applications",
 		"Lock the screen",
 		"Shutdown the computer",
 		"Restart the system",
+		"Suspend",
+		"Shutdown in 30m",
+		"Cancel shutdown",
 		"Log out",
 		"Send a notification with the message 'Hello World'",
+		"Enable do not disturb",
+		"Show missed notifications",
 		"Play music",
 		"Pause media playback",
 		"Skip to the next track",
 		"Go to the previous song",
+		"What's playing?",
+		"Skip ahead 30 seconds",
+		"Use spotify as the media player",
+		"Focus on 90m",
+		"Focus on 25m --pause-media",
+		"Focus off",
+		"Take screenshot",
+		"Take screenshot of window",
+		"Take screenshot of area in 5s",
+		"Pick a color",
 		"Set dark mode on",
 		"Change to light mode",
 		"Set desktop background to /path/to/image.jpg",
@@ -131,6 +204,8 @@ func (a *Assistant) GetCommandExamples() []string {
 		"Set icon theme to Papirus",
 		"Get current theme",
 		"Show desktop background",
+		"Nightlight on",
+		"Nightlight temp 4000",
 		"Set volume to 50 percent",
 		"Increase volume to 75 percent",
 		"Mute the sound",
@@ -143,6 +218,12 @@ func (a *Assistant) GetCommandExamples() []string {
 		"Turn on WiFi",
 		"Turn off WiFi",
 		"Check WiFi status",
+		"List WiFi networks",
+		"Connect to wifi HomeNet password secret123",
+		"Forget wifi network CafeGuest",
+		"Turn on my work VPN",
+		"Turn off the VPN",
+		"Check VPN status",
 		"Enable Bluetooth",
 		"Disable Bluetooth",
 		"Check Bluetooth status",
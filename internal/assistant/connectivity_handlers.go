@@ -1,6 +1,8 @@
 package assistant
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/agnath18K/lumo/internal/core"
@@ -46,6 +48,94 @@ func (p *Processor) handleWifiStatus(input string) (*core.Command, error) {
 	}, nil
 }
 
+// handleListWifiNetworks handles the "list wifi networks" command
+func (p *Processor) handleListWifiNetworks(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:     core.CommandTypeConnectivity,
+		Action:   "list-networks",
+		Target:   "",
+		RawInput: input,
+	}, nil
+}
+
+// handleConnectWifiNetwork handles the "connect to wifi" command
+func (p *Processor) handleConnectWifiNetwork(input string) (*core.Command, error) {
+	ssid, password := p.extractWifiNetwork(input, "connect", "join")
+	if ssid == "" {
+		return nil, fmt.Errorf("no WiFi network given, e.g. connect to wifi HomeNet password secret")
+	}
+
+	cmd := &core.Command{
+		Type:      core.CommandTypeConnectivity,
+		Action:    "connect-network",
+		Target:    ssid,
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}
+	if password != "" {
+		cmd.Arguments["password"] = password
+	}
+	return cmd, nil
+}
+
+// handleForgetWifiNetwork handles the "forget wifi network" command
+func (p *Processor) handleForgetWifiNetwork(input string) (*core.Command, error) {
+	ssid, _ := p.extractWifiNetwork(input, "forget")
+	if ssid == "" {
+		return nil, fmt.Errorf("no WiFi network given, e.g. forget wifi network HomeNet")
+	}
+	return &core.Command{
+		Type:     core.CommandTypeConnectivity,
+		Action:   "forget-network",
+		Target:   ssid,
+		RawInput: input,
+	}, nil
+}
+
+// wifiNetworkFillers are words between the verb and the SSID, as in
+// "connect to the wifi network named HomeNet"
+var wifiNetworkFillers = []string{"to", "the", "my", "wifi", "wi-fi", "network", "named", "called", "ssid"}
+
+// extractWifiNetwork returns the SSID and password following one of verbs in
+// input. SSIDs and passwords are case-sensitive, so they are taken from the
+// original input when it is the command being handled.
+func (p *Processor) extractWifiNetwork(input string, verbs ...string) (string, string) {
+	source := input
+	if strings.ToLower(p.rawInput) == input {
+		source = p.rawInput
+	}
+
+	words := strings.Fields(source)
+	start := slices.IndexFunc(words, func(word string) bool {
+		return slices.Contains(verbs, strings.ToLower(word))
+	})
+	if start < 0 {
+		return "", ""
+	}
+	words = words[start+1:]
+	for len(words) > 0 && slices.Contains(wifiNetworkFillers, strings.ToLower(words[0])) {
+		words = words[1:]
+	}
+
+	ssidWords, passwordWords := words, []string(nil)
+	if i := slices.IndexFunc(words, func(word string) bool { return strings.EqualFold(word, "password") }); i >= 0 {
+		ssidWords, passwordWords = words[:i], words[i+1:]
+		// Drop the "with" or "and" joining the password
+		if n := len(ssidWords); n > 0 && slices.Contains([]string{"with", "and", "using"}, strings.ToLower(ssidWords[n-1])) {
+			ssidWords = ssidWords[:n-1]
+		}
+	}
+	return unquoteWord(strings.Join(ssidWords, " ")), unquoteWord(strings.Join(passwordWords, " "))
+}
+
+// unquoteWord removes matching single or double quotes around s
+func unquoteWord(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
 // handleEnableBluetooth handles the "enable bluetooth" command
 func (p *Processor) handleEnableBluetooth(input string) (*core.Command, error) {
 	return &core.Command{
@@ -106,6 +196,73 @@ func (p *Processor) handleAirplaneModeStatus(input string) (*core.Command, error
 	}, nil
 }
 
+// handleEnableVPN handles the "enable vpn" command
+func (p *Processor) handleEnableVPN(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:     core.CommandTypeConnectivity,
+		Action:   "enable-vpn",
+		Target:   p.extractVPNName(input),
+		RawInput: input,
+	}, nil
+}
+
+// handleDisableVPN handles the "disable vpn" command
+func (p *Processor) handleDisableVPN(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:     core.CommandTypeConnectivity,
+		Action:   "disable-vpn",
+		Target:   p.extractVPNName(input),
+		RawInput: input,
+	}, nil
+}
+
+// handleVPNStatus handles the "vpn status" command
+func (p *Processor) handleVPNStatus(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:     core.CommandTypeConnectivity,
+		Action:   "vpn-status",
+		Target:   "",
+		RawInput: input,
+	}, nil
+}
+
+// inferVPNCommand picks the VPN command for input that mentions a VPN,
+// such as "turn off my work vpn" or "is the vpn on"
+func (p *Processor) inferVPNCommand(input string) (*core.Command, error) {
+	words := strings.Fields(input)
+	switch {
+	case strings.Contains(input, "status") || words[0] == "is" || words[0] == "am":
+		return p.handleVPNStatus(input)
+	case strings.Contains(input, "disable") || strings.Contains(input, "turn off") || strings.Contains(input, "disconnect") ||
+		strings.Contains(input, "deactivate") || slices.Contains(words, "stop") || slices.Contains(words, "off") || slices.Contains(words, "down"):
+		return p.handleDisableVPN(input)
+	}
+	return p.handleEnableVPN(input)
+}
+
+// vpnCommandWords are the words of a VPN command that are not part of the
+// connection name
+var vpnCommandWords = []string{
+	"turn", "switch", "on", "off", "enable", "disable", "connect", "disconnect", "activate", "deactivate",
+	"start", "stop", "bring", "up", "down", "to", "from", "the", "my", "please", "vpn", "connection",
+}
+
+// extractVPNName returns the VPN connection named in input, keeping its case
+// from the original input, or "" for commands such as "turn on the vpn"
+func (p *Processor) extractVPNName(input string) string {
+	source := input
+	if strings.ToLower(p.rawInput) == input {
+		source = p.rawInput
+	}
+	var name []string
+	for _, word := range strings.Fields(source) {
+		if !slices.Contains(vpnCommandWords, strings.ToLower(word)) {
+			name = append(name, word)
+		}
+	}
+	return unquoteWord(strings.Join(name, " "))
+}
+
 // handleEnableHotspot handles the "enable hotspot" command
 func (p *Processor) handleEnableHotspot(input string) (*core.Command, error) {
 	// Extract SSID and password from the input
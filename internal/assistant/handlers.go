@@ -1,6 +1,11 @@
 package assistant
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
 	"github.com/agnath18K/lumo/internal/core"
 )
 
@@ -85,23 +90,131 @@ func (p *Processor) handleListWindows(input string) (*core.Command, error) {
 	}, nil
 }
 
+// handleTileWindow handles commands like "tile firefox left" or "snap terminal to the top right corner"
+func (p *Processor) handleTileWindow(input string) (*core.Command, error) {
+	vertical := ""
+	if strings.Contains(input, "top") {
+		vertical = "top"
+	} else if strings.Contains(input, "bottom") {
+		vertical = "bottom"
+	}
+
+	horizontal := ""
+	if strings.Contains(input, "left") {
+		horizontal = "left"
+	} else if strings.Contains(input, "right") {
+		horizontal = "right"
+	}
+
+	var position core.TilePosition
+	switch {
+	case vertical != "" && horizontal != "":
+		position = core.TilePosition(vertical + "-" + horizontal)
+	case horizontal != "":
+		position = core.TilePosition(horizontal)
+	case vertical != "":
+		position = core.TilePosition(vertical)
+	default:
+		return nil, fmt.Errorf("missing tile position, e.g. left, right, or top left")
+	}
+
+	return &core.Command{
+		Type:   core.CommandTypeWindow,
+		Action: "tile",
+		Target: extractWindowName(input),
+		Arguments: map[string]interface{}{
+			"position": string(position),
+		},
+		RawInput: input,
+	}, nil
+}
+
+// handleMonitor handles commands like "move firefox to monitor 2" and "list monitors"
+func (p *Processor) handleMonitor(input string) (*core.Command, error) {
+	if strings.Contains(input, "list") || strings.Contains(input, "show") {
+		return &core.Command{
+			Type:      core.CommandTypeWindow,
+			Action:    "list-monitors",
+			Target:    "",
+			Arguments: make(map[string]interface{}),
+			RawInput:  input,
+		}, nil
+	}
+
+	monitor := extractNumberAfter(input, "monitor")
+	if monitor == "" {
+		if !strings.Contains(input, "next") && !strings.Contains(input, "other") {
+			return nil, fmt.Errorf("missing monitor number, e.g. move firefox to monitor 2")
+		}
+		monitor = "next"
+	}
+
+	return &core.Command{
+		Type:   core.CommandTypeWindow,
+		Action: "move-to-monitor",
+		Target: extractWindowName(input),
+		Arguments: map[string]interface{}{
+			"monitor": monitor,
+		},
+		RawInput: input,
+	}, nil
+}
+
+// handleWorkspace handles "move firefox to workspace 2" and "switch to workspace 3"
+func (p *Processor) handleWorkspace(input string) (*core.Command, error) {
+	workspace := extractNumberAfter(input, "workspace")
+	if workspace == "" {
+		return nil, fmt.Errorf("missing workspace number, e.g. switch to workspace 2")
+	}
+
+	fields := strings.Fields(input)
+	if fields[0] == "switch" || fields[0] == "go" || fields[0] == "workspace" {
+		return &core.Command{
+			Type:      core.CommandTypeWindow,
+			Action:    "switch-workspace",
+			Target:    workspace,
+			Arguments: make(map[string]interface{}),
+			RawInput:  input,
+		}, nil
+	}
+
+	return &core.Command{
+		Type:   core.CommandTypeWindow,
+		Action: "move-to-workspace",
+		Target: extractWindowName(input),
+		Arguments: map[string]interface{}{
+			"workspace": workspace,
+		},
+		RawInput: input,
+	}, nil
+}
+
 // handleLaunchApplication handles the "launch application" command
 func (p *Processor) handleLaunchApplication(input string) (*core.Command, error) {
+	// Files and URIs are case-sensitive, so take them from the original input
+	// when it is the command being handled
+	source := input
+	if strings.ToLower(p.rawInput) == input {
+		source = p.rawInput
+	}
+	rest, files, flags := extractLaunchFlagsAndFiles(source)
+
 	// Extract the application name and arguments
-	appName, args := extractApplicationAndArgs(input)
+	appName, args := extractApplicationAndArgs(strings.ToLower(rest))
 
 	// Create the command
 	cmd := &core.Command{
 		Type:      core.CommandTypeApplication,
 		Action:    "launch",
 		Target:    appName,
-		Arguments: make(map[string]interface{}),
+		Arguments: flags,
 		RawInput:  input,
 	}
 
 	// Add arguments if any
-	if args != "" {
-		cmd.Arguments["args"] = args
+	launchArgs := append(strings.Fields(args), files...)
+	if len(launchArgs) > 0 {
+		cmd.Arguments["args"] = launchArgs
 	}
 
 	return cmd, nil
@@ -118,8 +231,22 @@ func (p *Processor) handleListApplications(input string) (*core.Command, error)
 	}, nil
 }
 
-// handleShutdownSystem handles the "shutdown system" command
+// handleShutdownSystem handles the "shutdown system" command, including
+// delayed shutdowns like "shutdown in 30m" and "shutdown at 22:30"
 func (p *Processor) handleShutdownSystem(input string) (*core.Command, error) {
+	if strings.Contains(input, "cancel") || strings.Contains(input, "abort") {
+		return p.handleCancelShutdown(input)
+	}
+	if when := extractShutdownTime(input); when != "" {
+		return &core.Command{
+			Type:      core.CommandTypeSystem,
+			Action:    "schedule-shutdown",
+			Target:    when,
+			Arguments: map[string]interface{}{"type": "poweroff"},
+			RawInput:  input,
+		}, nil
+	}
+
 	return &core.Command{
 		Type:      core.CommandTypeSystem,
 		Action:    "shutdown",
@@ -129,8 +256,19 @@ func (p *Processor) handleShutdownSystem(input string) (*core.Command, error) {
 	}, nil
 }
 
-// handleRestartSystem handles the "restart system" command
+// handleRestartSystem handles the "restart system" command, including
+// delayed restarts like "restart in 10m"
 func (p *Processor) handleRestartSystem(input string) (*core.Command, error) {
+	if when := extractShutdownTime(input); when != "" {
+		return &core.Command{
+			Type:      core.CommandTypeSystem,
+			Action:    "schedule-shutdown",
+			Target:    when,
+			Arguments: map[string]interface{}{"type": "reboot"},
+			RawInput:  input,
+		}, nil
+	}
+
 	return &core.Command{
 		Type:      core.CommandTypeSystem,
 		Action:    "restart",
@@ -162,6 +300,50 @@ func (p *Processor) handleLockScreen(input string) (*core.Command, error) {
 	}, nil
 }
 
+// handleSuspend handles the "suspend" command
+func (p *Processor) handleSuspend(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeSystem,
+		Action:    "suspend",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleHibernate handles the "hibernate" command
+func (p *Processor) handleHibernate(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeSystem,
+		Action:    "hibernate",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleCancelShutdown handles the "cancel shutdown" command
+func (p *Processor) handleCancelShutdown(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeSystem,
+		Action:    "cancel-shutdown",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleShutdownStatus handles the "shutdown status" command
+func (p *Processor) handleShutdownStatus(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeSystem,
+		Action:    "shutdown-status",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
 // handleSendNotification handles the "send notification" command
 func (p *Processor) handleSendNotification(input string) (*core.Command, error) {
 	// Extract the notification summary and body
@@ -198,6 +380,40 @@ func (p *Processor) handleCloseNotification(input string) (*core.Command, error)
 	}, nil
 }
 
+// dndOffWords turn do not disturb off rather than on
+var dndOffWords = []string{"disable", " off", "stop", "end ", "cancel"}
+
+// handleDoNotDisturb handles turning do not disturb on and off, e.g.
+// "enable do not disturb" or "turn dnd off"
+func (p *Processor) handleDoNotDisturb(input string) (*core.Command, error) {
+	action := "enable-dnd"
+	for _, word := range dndOffWords {
+		if strings.Contains(input, word) {
+			action = "disable-dnd"
+			break
+		}
+	}
+
+	return &core.Command{
+		Type:      core.CommandTypeNotification,
+		Action:    action,
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleListNotifications handles the "missed notifications" command
+func (p *Processor) handleListNotifications(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeNotification,
+		Action:    "list-notifications",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
 // handlePlayMedia handles the "play media" command
 func (p *Processor) handlePlayMedia(input string) (*core.Command, error) {
 	return &core.Command{
@@ -252,3 +468,265 @@ func (p *Processor) handlePreviousTrack(input string) (*core.Command, error) {
 		RawInput:  input,
 	}, nil
 }
+
+// handleNowPlaying handles the "what's playing" command
+func (p *Processor) handleNowPlaying(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeMedia,
+		Action:    "now-playing",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// defaultSeekSeconds is how far "skip ahead" and "rewind" move without an amount
+const defaultSeekSeconds = 10
+
+// seekAmountPattern matches the amount of a seek request, e.g. "30 seconds" or "2 min"
+var seekAmountPattern = regexp.MustCompile(`(\d+)\s*(s|sec|secs|seconds?|m|min|mins|minutes?)?\b`)
+
+// seekWords are requests to move within a track rather than between tracks
+var seekWords = []string{"seek", "rewind", "fast forward", "skip ahead", "skip forward", "skip back", "jump ahead", "jump forward", "jump back"}
+
+// isSeekRequest reports whether input asks to move within the playing
+// track, as in "skip ahead 30 seconds" or "go back 10 seconds"
+func isSeekRequest(input string) bool {
+	if slices.ContainsFunc(seekWords, func(word string) bool { return strings.Contains(input, word) }) {
+		return true
+	}
+	return (strings.Contains(input, "back") || strings.Contains(input, "ahead") || strings.Contains(input, "forward")) &&
+		(strings.Contains(input, "second") || strings.Contains(input, "minute"))
+}
+
+// handleSeekMedia handles the "skip ahead 30 seconds" and "rewind 10 seconds" commands
+func (p *Processor) handleSeekMedia(input string) (*core.Command, error) {
+	seconds := defaultSeekSeconds
+	if match := seekAmountPattern.FindStringSubmatch(input); match != nil {
+		fmt.Sscanf(match[1], "%d", &seconds)
+		if strings.HasPrefix(match[2], "m") {
+			seconds *= 60
+		}
+	} else if strings.Contains(input, "minute") {
+		// "go back a minute"
+		seconds = 60
+	}
+	if strings.Contains(input, "back") || strings.Contains(input, "rewind") {
+		seconds = -seconds
+	}
+
+	return &core.Command{
+		Type:      core.CommandTypeMedia,
+		Action:    "seek",
+		Target:    fmt.Sprintf("%d", seconds),
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// mediaPlayerWords are words of requests to choose the media player around
+// the player's name, e.g. "use spotify as the media player"
+var mediaPlayerWords = []string{
+	"set", "set-player", "use", "control", "choose", "switch", "the", "my", "media", "music",
+	"player", "to", "as", "for", "with", "default", "from", "now", "on", "please",
+}
+
+// handleSetPlayer handles the "set player <name>" command
+func (p *Processor) handleSetPlayer(input string) (*core.Command, error) {
+	var name []string
+	for _, word := range strings.Fields(input) {
+		if !slices.Contains(mediaPlayerWords, word) {
+			name = append(name, word)
+		}
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("no player given, e.g. set player spotify")
+	}
+	return &core.Command{
+		Type:      core.CommandTypeMedia,
+		Action:    "set-player",
+		Target:    unquoteWord(strings.Join(name, " ")),
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleFocusOn handles the "focus on [duration] [--pause-media]" command
+func (p *Processor) handleFocusOn(input string) (*core.Command, error) {
+	// "focus on the firefox window" is a window command, not focus mode
+	if strings.Contains(input, "window") {
+		return p.handleFocusWindow(input)
+	}
+
+	args := make(map[string]interface{})
+	var duration string
+	for _, field := range strings.Fields(extractAfter(input, "focus on")) {
+		if field == "--pause-media" {
+			args["pause_media"] = true
+			continue
+		}
+		duration = field
+	}
+
+	return &core.Command{
+		Type:      core.CommandTypeFocus,
+		Action:    "on",
+		Target:    duration,
+		Arguments: args,
+		RawInput:  input,
+	}, nil
+}
+
+// handleFocusOff handles the "focus off" command
+func (p *Processor) handleFocusOff(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeFocus,
+		Action:    "off",
+		Target:    strings.TrimSpace(extractAfter(input, "focus off")),
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleFocusStatus handles the "focus status" command
+func (p *Processor) handleFocusStatus(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeFocus,
+		Action:    "status",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// screenshotDelayPattern matches a delay such as "in 5 seconds" or "after 3s"
+var screenshotDelayPattern = regexp.MustCompile(`(\d+)\s*(?:s|sec|secs|second|seconds)\b`)
+
+// handleScreenshot handles the "take screenshot [of window|area] [in <seconds>s]" command
+func (p *Processor) handleScreenshot(input string) (*core.Command, error) {
+	mode := core.ScreenshotScreen
+	switch {
+	case strings.Contains(input, "window"):
+		mode = core.ScreenshotWindow
+	case strings.Contains(input, "area") || strings.Contains(input, "region") || strings.Contains(input, "select"):
+		mode = core.ScreenshotArea
+	}
+
+	args := make(map[string]interface{})
+	if match := screenshotDelayPattern.FindStringSubmatch(input); match != nil {
+		args["delay"] = match[1]
+	}
+
+	return &core.Command{
+		Type:      core.CommandTypeScreenshot,
+		Action:    "take",
+		Target:    string(mode),
+		Arguments: args,
+		RawInput:  input,
+	}, nil
+}
+
+// handlePickColor handles the "pick color" command
+func (p *Processor) handlePickColor(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeScreenshot,
+		Action:    "pick-color",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// handleNightLight handles the "nightlight on|off|temp <kelvin>|status" command
+func (p *Processor) handleNightLight(input string) (*core.Command, error) {
+	rest := extractAfter(input, "nightlight")
+	if !strings.Contains(input, "nightlight") {
+		rest = extractAfter(input, "night light")
+	}
+	fields := strings.Fields(rest)
+
+	cmd := &core.Command{
+		Type:      core.CommandTypeAppearance,
+		Action:    "get-night-light",
+		Target:    "",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}
+
+	if len(fields) == 0 {
+		return cmd, nil
+	}
+
+	switch fields[0] {
+	case "on", "off":
+		cmd.Action = "set-night-light"
+		cmd.Target = fields[0]
+	case "temp", "temperature":
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("missing color temperature, e.g. nightlight temp 4000")
+		}
+		cmd.Action = "set-color-temperature"
+		cmd.Target = fields[1]
+	case "status":
+	default:
+		return nil, fmt.Errorf("unknown night light command: %s (use on, off, temp <kelvin>, or status)", fields[0])
+	}
+
+	return cmd, nil
+}
+
+// handleToggleMute handles the "toggle mute" command
+func (p *Processor) handleToggleMute(input string) (*core.Command, error) {
+	return &core.Command{
+		Type:      core.CommandTypeSound,
+		Action:    "set-mute",
+		Target:    "toggle",
+		Arguments: make(map[string]interface{}),
+		RawInput:  input,
+	}, nil
+}
+
+// soundOutputVerbs are the verbs of requests to change the sound output
+var soundOutputVerbs = []string{"switch", "use", "change", "move", "route", "set"}
+
+// soundOutputWords are words of sound output requests around the name of
+// the output, e.g. "switch the audio output to headphones"
+var soundOutputWords = []string{
+	"switch", "use", "change", "move", "route", "set", "to", "the", "my", "audio",
+	"sound", "output", "device", "through", "via", "on", "for", "please", "default",
+}
+
+// isSoundOutputSwitch reports whether input asks to play sound through
+// another device, as in "switch audio to headphones" or "use hdmi sound"
+func isSoundOutputSwitch(input string) bool {
+	fields := strings.Fields(input)
+	if !slices.ContainsFunc(fields, func(word string) bool {
+		return word == "audio" || word == "sound" || word == "output" || word == "speakers" || word == "headphones"
+	}) {
+		return false
+	}
+	if strings.Contains(input, "volume") || strings.Contains(input, "mute") || strings.Contains(input, "mic") {
+		return false
+	}
+	return slices.ContainsFunc(fields, func(word string) bool {
+		return slices.Contains(soundOutputVerbs, word)
+	})
+}
+
+// handleSwitchOutput handles the "switch audio to <output>" command
+func (p *Processor) handleSwitchOutput(input string) (*core.Command, error) {
+	var name []string
+	for _, word := range strings.Fields(input) {
+		if !slices.Contains(soundOutputWords, word) {
+			name = append(name, word)
+		}
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("no output given, e.g. switch audio to headphones")
+	}
+	return &core.Command{
+		Type:     core.CommandTypeSound,
+		Action:   "switch-output",
+		Target:   unquoteWord(strings.Join(name, " ")),
+		RawInput: input,
+	}, nil
+}
@@ -2,6 +2,7 @@ package assistant
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/agnath18K/lumo/internal/core"
@@ -15,6 +16,9 @@ type Processor struct {
 	aiEnabled bool
 	// aiClient is the AI client for processing complex commands
 	aiClient AIClient
+	// rawInput is the command being processed before it was lowercased,
+	// for handlers whose arguments are case-sensitive
+	rawInput string
 }
 
 // AIClient is an interface for AI processing
@@ -64,6 +68,15 @@ func (p *Processor) registerCommandPatterns() {
 	p.commandPatterns["focus window"] = p.handleFocusWindow
 	p.commandPatterns["list windows"] = p.handleListWindows
 
+	// Tiling, monitor, and workspace commands
+	p.commandPatterns["tile"] = p.handleTileWindow
+	p.commandPatterns["snap"] = p.handleTileWindow
+	p.commandPatterns[" half"] = p.handleTileWindow
+	p.commandPatterns["quarter"] = p.handleTileWindow
+	p.commandPatterns["corner"] = p.handleTileWindow
+	p.commandPatterns["monitor"] = p.handleMonitor
+	p.commandPatterns["workspace"] = p.handleWorkspace
+
 	// Application commands
 	p.commandPatterns["launch application"] = p.handleLaunchApplication
 	p.commandPatterns["open application"] = p.handleLaunchApplication
@@ -75,10 +88,24 @@ func (p *Processor) registerCommandPatterns() {
 	p.commandPatterns["restart system"] = p.handleRestartSystem
 	p.commandPatterns["logout"] = p.handleLogout
 	p.commandPatterns["lock screen"] = p.handleLockScreen
+	p.commandPatterns["suspend"] = p.handleSuspend
+	p.commandPatterns["hibernate"] = p.handleHibernate
+	p.commandPatterns["shutdown in "] = p.handleShutdownSystem
+	p.commandPatterns["shutdown at "] = p.handleShutdownSystem
+	p.commandPatterns["restart in "] = p.handleRestartSystem
+	p.commandPatterns["reboot in "] = p.handleRestartSystem
+	p.commandPatterns["cancel shutdown"] = p.handleCancelShutdown
+	p.commandPatterns["shutdown status"] = p.handleShutdownStatus
 
 	// Notification commands
 	p.commandPatterns["send notification"] = p.handleSendNotification
 	p.commandPatterns["close notification"] = p.handleCloseNotification
+	p.commandPatterns["do not disturb"] = p.handleDoNotDisturb
+	p.commandPatterns["dnd"] = p.handleDoNotDisturb
+	p.commandPatterns["missed notifications"] = p.handleListNotifications
+	p.commandPatterns["list notifications"] = p.handleListNotifications
+	p.commandPatterns["list-notifications"] = p.handleListNotifications
+	p.commandPatterns["notification history"] = p.handleListNotifications
 
 	// Media commands
 	p.commandPatterns["play media"] = p.handlePlayMedia
@@ -86,18 +113,65 @@ func (p *Processor) registerCommandPatterns() {
 	p.commandPatterns["stop media"] = p.handleStopMedia
 	p.commandPatterns["next track"] = p.handleNextTrack
 	p.commandPatterns["previous track"] = p.handlePreviousTrack
+	p.commandPatterns["now playing"] = p.handleNowPlaying
+	p.commandPatterns["what's playing"] = p.handleNowPlaying
+	p.commandPatterns["what is playing"] = p.handleNowPlaying
+	p.commandPatterns["skip ahead"] = p.handleSeekMedia
+	p.commandPatterns["skip forward"] = p.handleSeekMedia
+	p.commandPatterns["skip back"] = p.handleSeekMedia
+	p.commandPatterns["rewind"] = p.handleSeekMedia
+	p.commandPatterns["fast forward"] = p.handleSeekMedia
+	p.commandPatterns["set player"] = p.handleSetPlayer
+	p.commandPatterns["set-player"] = p.handleSetPlayer
+
+	// Sound commands
+	p.commandPatterns["toggle mute"] = p.handleToggleMute
+	p.commandPatterns["switch audio"] = p.handleSwitchOutput
+	p.commandPatterns["switch sound"] = p.handleSwitchOutput
+	p.commandPatterns["switch output"] = p.handleSwitchOutput
+
+	// Night light commands
+	p.commandPatterns["nightlight"] = p.handleNightLight
+	p.commandPatterns["night light"] = p.handleNightLight
+
+	// Focus mode commands
+	p.commandPatterns["focus on"] = p.handleFocusOn
+	p.commandPatterns["focus off"] = p.handleFocusOff
+	p.commandPatterns["focus status"] = p.handleFocusStatus
+
+	// Screenshot commands
+	p.commandPatterns["screenshot"] = p.handleScreenshot
+	p.commandPatterns["screen shot"] = p.handleScreenshot
+	p.commandPatterns["pick-color"] = p.handlePickColor
+	p.commandPatterns["pick color"] = p.handlePickColor
+	p.commandPatterns["pick a color"] = p.handlePickColor
+	p.commandPatterns["color picker"] = p.handlePickColor
 
 	// Connectivity commands
 	p.commandPatterns["list network devices"] = p.handleListNetworkDevices
 	p.commandPatterns["enable wifi"] = p.handleEnableWifi
 	p.commandPatterns["disable wifi"] = p.handleDisableWifi
 	p.commandPatterns["wifi status"] = p.handleWifiStatus
+	p.commandPatterns["wifi networks"] = p.handleListWifiNetworks
+	p.commandPatterns["list networks"] = p.handleListWifiNetworks
+	p.commandPatterns["available networks"] = p.handleListWifiNetworks
+	p.commandPatterns["connect to wifi"] = p.handleConnectWifiNetwork
+	p.commandPatterns["connect to the wifi"] = p.handleConnectWifiNetwork
+	p.commandPatterns["connect to network"] = p.handleConnectWifiNetwork
+	p.commandPatterns["connect to the network"] = p.handleConnectWifiNetwork
+	p.commandPatterns["forget wifi"] = p.handleForgetWifiNetwork
+	p.commandPatterns["forget network"] = p.handleForgetWifiNetwork
+	p.commandPatterns["forget the wifi"] = p.handleForgetWifiNetwork
+	p.commandPatterns["forget the network"] = p.handleForgetWifiNetwork
 	p.commandPatterns["enable bluetooth"] = p.handleEnableBluetooth
 	p.commandPatterns["disable bluetooth"] = p.handleDisableBluetooth
 	p.commandPatterns["bluetooth status"] = p.handleBluetoothStatus
 	p.commandPatterns["enable airplane mode"] = p.handleEnableAirplaneMode
 	p.commandPatterns["disable airplane mode"] = p.handleDisableAirplaneMode
 	p.commandPatterns["airplane mode status"] = p.handleAirplaneModeStatus
+	p.commandPatterns["enable vpn"] = p.handleEnableVPN
+	p.commandPatterns["disable vpn"] = p.handleDisableVPN
+	p.commandPatterns["vpn status"] = p.handleVPNStatus
 	p.commandPatterns["enable hotspot"] = p.handleEnableHotspot
 	p.commandPatterns["disable hotspot"] = p.handleDisableHotspot
 	p.commandPatterns["hotspot status"] = p.handleHotspotStatus
@@ -125,6 +199,7 @@ func (p *Processor) Process(input string) (*core.Command, error) {
 
 	// Normalize the input
 	normalizedInput := strings.ToLower(strings.TrimSpace(input))
+	p.rawInput = strings.TrimSpace(input)
 	fmt.Printf("DEBUG: Normalized input: %s\n", normalizedInput)
 
 	// Try to match the input to a command pattern
@@ -223,6 +298,17 @@ func (p *Processor) processWithAI(input string) (*core.Command, error) {
 func (p *Processor) inferCommand(input string) (*core.Command, error) {
 	fmt.Printf("DEBUG: Inferring command from: %s\n", input)
 
+	// VPN names can hold words like "open" or "off" that other commands look
+	// for, so VPN commands such as "turn on my work vpn" are checked first
+	if slices.Contains(strings.Fields(input), "vpn") {
+		return p.inferVPNCommand(input)
+	}
+
+	// "use hdmi sound" would otherwise reach the checks below by its device name
+	if isSoundOutputSwitch(input) {
+		return p.handleSwitchOutput(input)
+	}
+
 	// Check for window commands
 	if strings.Contains(input, "close") && (strings.Contains(input, "window") || strings.Contains(input, "app")) {
 		return p.handleCloseWindow(input)
@@ -268,6 +354,12 @@ func (p *Processor) inferCommand(input string) (*core.Command, error) {
 	if strings.Contains(input, "lock") && strings.Contains(input, "screen") {
 		return p.handleLockScreen(input)
 	}
+	if strings.Contains(input, "suspend") || strings.Contains(input, "go to sleep") {
+		return p.handleSuspend(input)
+	}
+	if strings.Contains(input, "hibernate") {
+		return p.handleHibernate(input)
+	}
 
 	// Check for notification commands
 	if strings.Contains(input, "send") && strings.Contains(input, "notification") {
@@ -276,8 +368,20 @@ func (p *Processor) inferCommand(input string) (*core.Command, error) {
 	if strings.Contains(input, "close") && strings.Contains(input, "notification") {
 		return p.handleCloseNotification(input)
 	}
+	if strings.Contains(input, "notifications") && (strings.Contains(input, "miss") || strings.Contains(input, "show") || strings.Contains(input, "what")) {
+		return p.handleListNotifications(input)
+	}
 
 	// Check for media commands
+	if strings.Contains(input, "playing") && (strings.Contains(input, "what") || strings.Contains(input, "which")) {
+		return p.handleNowPlaying(input)
+	}
+	if isSeekRequest(input) {
+		return p.handleSeekMedia(input)
+	}
+	if strings.Contains(input, "player") && (strings.Contains(input, "use") || strings.Contains(input, "control") || strings.Contains(input, "set ")) {
+		return p.handleSetPlayer(input)
+	}
 	if strings.Contains(input, "play") && (strings.Contains(input, "media") || strings.Contains(input, "music") || strings.Contains(input, "song")) {
 		return p.handlePlayMedia(input)
 	}
@@ -309,6 +413,16 @@ func (p *Processor) inferCommand(input string) (*core.Command, error) {
 	}
 
 	// Check for connectivity commands
+	if strings.Contains(input, "forget") && (strings.Contains(input, "wifi") || strings.Contains(input, "network")) {
+		return p.handleForgetWifiNetwork(input)
+	}
+	if ((strings.Contains(input, "connect") && !strings.Contains(input, "disconnect")) || strings.Contains(input, "join")) &&
+		(strings.Contains(input, "wifi") || strings.Contains(input, "network")) {
+		return p.handleConnectWifiNetwork(input)
+	}
+	if (strings.Contains(input, "list") || strings.Contains(input, "scan")) && strings.Contains(input, "wifi") {
+		return p.handleListWifiNetworks(input)
+	}
 	if strings.Contains(input, "list") && (strings.Contains(input, "network") || strings.Contains(input, "device")) {
 		return p.handleListNetworkDevices(input)
 	}
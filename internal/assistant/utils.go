@@ -2,6 +2,9 @@ package assistant
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -25,6 +28,72 @@ func extractTarget(input string, keywords []string) string {
 	return target
 }
 
+// extractAfter returns the text following the first occurrence of keyword
+func extractAfter(input, keyword string) string {
+	idx := strings.Index(input, keyword)
+	if idx == -1 {
+		return ""
+	}
+	return strings.TrimSpace(input[idx+len(keyword):])
+}
+
+// windowNameFillers are words that never form part of a window name in
+// tiling, monitor, and workspace commands
+var windowNameFillers = map[string]bool{
+	"move": true, "send": true, "put": true, "tile": true, "snap": true, "throw": true,
+	"the": true, "window": true, "to": true, "on": true, "in": true, "into": true, "of": true,
+	"a": true, "my": true, "screen": true, "side": true, "half": true, "quarter": true, "corner": true,
+	"left": true, "right": true, "top": true, "bottom": true, "top-left": true, "top-right": true,
+	"bottom-left": true, "bottom-right": true, "monitor": true, "display": true, "workspace": true,
+	"next": true, "other": true, "please": true,
+}
+
+// extractWindowName extracts the window name from a tiling, monitor, or
+// workspace command, returning "current" when no window is named
+func extractWindowName(input string) string {
+	var words []string
+	for _, word := range strings.Fields(input) {
+		word = strings.Trim(word, "\"'.,")
+		if windowNameFillers[word] || extractNumber(word) != "" {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	if len(words) == 0 {
+		return "current"
+	}
+	return strings.Join(words, " ")
+}
+
+// numberWords maps spelled-out numbers to digits
+var numberWords = map[string]string{
+	"one": "1", "two": "2", "three": "3", "four": "4", "five": "5",
+	"six": "6", "seven": "7", "eight": "8", "nine": "9", "ten": "10",
+	"first": "1", "second": "2", "third": "3", "fourth": "4",
+}
+
+// extractNumber returns the number in a word, e.g. "2", "two", or "second"
+func extractNumber(word string) string {
+	if digits, ok := numberWords[word]; ok {
+		return digits
+	}
+	if _, err := strconv.Atoi(word); err == nil {
+		return word
+	}
+	return ""
+}
+
+// extractNumberAfter returns the first number following keyword in the input
+func extractNumberAfter(input, keyword string) string {
+	for _, word := range strings.Fields(extractAfter(input, keyword)) {
+		if number := extractNumber(strings.Trim(word, ".,")); number != "" {
+			return number
+		}
+	}
+	return ""
+}
+
 // extractApplicationAndArgs extracts the application name and arguments from the input
 func extractApplicationAndArgs(input string) (string, string) {
 	fmt.Printf("DEBUG: Extracting application and args from: %s\n", input)
@@ -96,6 +165,64 @@ func extractApplicationAndArgs(input string) (string, string) {
 	return cleaned, ""
 }
 
+// extractLaunchFlagsAndFiles splits launch flags (--wait, --new-instance,
+// --timeout=<duration>) and file or URI arguments out of a launch command
+func extractLaunchFlagsAndFiles(input string) (string, []string, map[string]interface{}) {
+	flags := make(map[string]interface{})
+	var files []string
+	var rest []string
+
+	for _, word := range strings.Fields(input) {
+		lower := strings.ToLower(word)
+		switch {
+		case lower == "--wait":
+			flags["wait"] = "true"
+		case lower == "--new-instance" || lower == "--new":
+			flags["new_instance"] = "true"
+		case strings.HasPrefix(lower, "--timeout="):
+			flags["wait"] = "true"
+			flags["timeout"] = strings.TrimPrefix(lower, "--timeout=")
+		case looksLikeFileOrURI(word):
+			files = append(files, expandHome(strings.Trim(word, "\"'")))
+		default:
+			rest = append(rest, word)
+		}
+	}
+
+	return strings.Join(rest, " "), files, flags
+}
+
+// looksLikeFileOrURI reports whether a launch argument is a file path or URI
+func looksLikeFileOrURI(word string) bool {
+	word = strings.Trim(word, "\"'")
+	if strings.Contains(word, "://") || strings.HasPrefix(word, "mailto:") {
+		return true
+	}
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+
+	// Bare file names count only if they exist, so "code" stays an app name
+	if strings.Contains(word, ".") {
+		if _, err := os.Stat(word); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// expandHome expands a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, path[2:])
+		}
+	}
+	return path
+}
+
 // extractNotificationContent extracts the notification summary and body from the input
 func extractNotificationContent(input string) (string, string) {
 	// Remove keywords from the input
@@ -127,3 +254,29 @@ func extractNotificationContent(input string) (string, string) {
 	// If no body is found, return the cleaned input as the summary
 	return cleaned, ""
 }
+
+// extractShutdownTime extracts the delay or time of day from commands like
+// "shutdown in 30m" or "restart at 22:30", returning "" for an immediate one
+func extractShutdownTime(input string) string {
+	for _, keyword := range []string{" in ", " at "} {
+		fields := strings.Fields(extractAfter(input, keyword))
+		if len(fields) == 0 {
+			continue
+		}
+		// Accept spelled-out units, e.g. "in 30 minutes"
+		if len(fields) > 1 {
+			if _, err := strconv.Atoi(fields[0]); err == nil {
+				switch {
+				case strings.HasPrefix(fields[1], "min"):
+					return fields[0] + "m"
+				case strings.HasPrefix(fields[1], "hour"):
+					return fields[0] + "h"
+				case strings.HasPrefix(fields[1], "sec"):
+					return fields[0] + "s"
+				}
+			}
+		}
+		return fields[0]
+	}
+	return ""
+}
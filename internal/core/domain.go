@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 // CommandType represents the type of desktop command
 type CommandType string
 
@@ -20,6 +22,10 @@ const (
 	CommandTypeSound CommandType = "sound"
 	// CommandTypeConnectivity represents network connectivity commands
 	CommandTypeConnectivity CommandType = "connectivity"
+	// CommandTypeFocus represents focus mode (do not disturb) commands
+	CommandTypeFocus CommandType = "focus"
+	// CommandTypeScreenshot represents screenshot commands
+	CommandTypeScreenshot CommandType = "screenshot"
 )
 
 // Command represents a desktop command to be executed
@@ -70,6 +76,8 @@ const (
 	CapabilitySoundManagement Capability = "sound_management"
 	// CapabilityConnectivityManagement represents network connectivity management capabilities
 	CapabilityConnectivityManagement Capability = "connectivity_management"
+	// CapabilityFocusMode represents do not disturb and focus mode capabilities
+	CapabilityFocusMode Capability = "focus_mode"
 )
 
 // Window represents a desktop window
@@ -102,6 +110,52 @@ type WindowState struct {
 	Active     bool
 }
 
+// TilePosition is a screen region a window can be tiled into
+type TilePosition string
+
+const (
+	// TileLeft tiles a window to the left half of its monitor
+	TileLeft TilePosition = "left"
+	// TileRight tiles a window to the right half of its monitor
+	TileRight TilePosition = "right"
+	// TileTop tiles a window to the top half of its monitor
+	TileTop TilePosition = "top"
+	// TileBottom tiles a window to the bottom half of its monitor
+	TileBottom TilePosition = "bottom"
+	// TileTopLeft tiles a window to the top left quarter of its monitor
+	TileTopLeft TilePosition = "top-left"
+	// TileTopRight tiles a window to the top right quarter of its monitor
+	TileTopRight TilePosition = "top-right"
+	// TileBottomLeft tiles a window to the bottom left quarter of its monitor
+	TileBottomLeft TilePosition = "bottom-left"
+	// TileBottomRight tiles a window to the bottom right quarter of its monitor
+	TileBottomRight TilePosition = "bottom-right"
+)
+
+// Monitor represents a connected display
+type Monitor struct {
+	// Index is the monitor's position in the monitor list, starting at 0
+	Index int
+	// Name is the output name, e.g. eDP-1
+	Name string
+	// Primary indicates whether this is the primary monitor
+	Primary bool
+	// Geometry is the monitor's area in the global screen space
+	Geometry WindowGeometry
+}
+
+// ScreenshotMode is what a screenshot captures
+type ScreenshotMode string
+
+const (
+	// ScreenshotScreen captures the whole screen
+	ScreenshotScreen ScreenshotMode = "screen"
+	// ScreenshotWindow captures the active window
+	ScreenshotWindow ScreenshotMode = "window"
+	// ScreenshotArea captures an area the user selects
+	ScreenshotArea ScreenshotMode = "area"
+)
+
 // Application represents a desktop application
 type Application struct {
 	// ID is the unique identifier for the application
@@ -116,6 +170,30 @@ type Application struct {
 	Running bool
 }
 
+// LaunchOptions controls how an application is launched
+type LaunchOptions struct {
+	// Args are files, URIs, or plain arguments passed to the application
+	Args []string
+	// NewInstance spawns a new process even if the application is already running
+	NewInstance bool
+	// WaitForWindow blocks until the application has a window
+	WaitForWindow bool
+	// WaitTimeout bounds WaitForWindow; zero means the backend's default
+	WaitTimeout time.Duration
+}
+
+// Shortcut represents a global keyboard shortcut registered by lumo
+type Shortcut struct {
+	// ID is the unique identifier for the shortcut
+	ID string
+	// Name is the human-readable name shown in the desktop settings
+	Name string
+	// Command is the command run when the shortcut is pressed
+	Command string
+	// Binding is the key combination, e.g. <Super><Shift>m
+	Binding string
+}
+
 // Notification represents a desktop notification
 type Notification struct {
 	// ID is the unique identifier for the notification
@@ -183,3 +261,25 @@ type NetworkDevice struct {
 	// Properties contains additional device-specific properties
 	Properties map[string]interface{}
 }
+
+// VPNConnection represents a saved VPN connection
+type VPNConnection struct {
+	// Name is the connection name
+	Name string
+	// Type is the kind of VPN, such as "vpn" or "wireguard"
+	Type string
+	// Active indicates whether the VPN is connected
+	Active bool
+}
+
+// WifiNetwork represents a Wi-Fi network in range
+type WifiNetwork struct {
+	// SSID is the network name
+	SSID string
+	// Signal is the signal strength (0-100)
+	Signal int
+	// Security is the network's security, such as "WPA2", or empty for an open network
+	Security string
+	// Active indicates whether this is the connected network
+	Active bool
+}
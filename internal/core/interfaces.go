@@ -1,6 +1,11 @@
 package core
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/palette"
+)
 
 // DesktopEnvironment represents a desktop environment
 type DesktopEnvironment interface {
@@ -28,6 +33,10 @@ type DesktopEnvironment interface {
 	// LaunchApplication launches an application
 	LaunchApplication(ctx context.Context, appName string, args ...string) error
 
+	// LaunchApplicationWithOptions launches an application, activating a running
+	// instance when possible, and returns its window if WaitForWindow is set
+	LaunchApplicationWithOptions(ctx context.Context, appName string, opts LaunchOptions) (*Window, error)
+
 	// CloseWindow closes a window
 	CloseWindow(ctx context.Context, windowID string) error
 
@@ -49,6 +58,21 @@ type DesktopEnvironment interface {
 	// FocusWindow focuses a window
 	FocusWindow(ctx context.Context, windowID string) error
 
+	// TileWindow tiles a window to a half or quarter of its monitor
+	TileWindow(ctx context.Context, windowID string, position TilePosition) error
+
+	// GetMonitors returns the connected monitors
+	GetMonitors(ctx context.Context) ([]Monitor, error)
+
+	// MoveWindowToMonitor moves a window to another monitor (0-based index)
+	MoveWindowToMonitor(ctx context.Context, windowID string, monitor int) error
+
+	// MoveWindowToWorkspace moves a window to a workspace (1-based)
+	MoveWindowToWorkspace(ctx context.Context, windowID string, workspace int) error
+
+	// SwitchWorkspace switches to a workspace (1-based)
+	SwitchWorkspace(ctx context.Context, workspace int) error
+
 	// ShowDesktop shows the desktop
 	ShowDesktop(ctx context.Context) error
 
@@ -58,8 +82,17 @@ type DesktopEnvironment interface {
 	// CloseNotification closes a notification
 	CloseNotification(ctx context.Context, id uint32) error
 
-	// TakeScreenshot takes a screenshot
-	TakeScreenshot(ctx context.Context, fullScreen bool, delay int) (string, error)
+	// SetDoNotDisturb enables or disables do not disturb mode
+	SetDoNotDisturb(ctx context.Context, enabled bool) error
+
+	// GetDoNotDisturb gets the current do not disturb state
+	GetDoNotDisturb(ctx context.Context) (bool, error)
+
+	// TakeScreenshot takes a screenshot after delay seconds and returns the path it was saved to
+	TakeScreenshot(ctx context.Context, mode ScreenshotMode, delay int) (string, error)
+
+	// PickColor lets the user pick a color on the screen and returns it
+	PickColor(ctx context.Context) (palette.Color, error)
 
 	// GetClipboardText gets the text from the clipboard
 	GetClipboardText(ctx context.Context) (string, error)
@@ -88,6 +121,18 @@ type DesktopEnvironment interface {
 	// GetCurrentIconTheme gets the current icon theme
 	GetCurrentIconTheme(ctx context.Context) (string, error)
 
+	// SetNightLight enables or disables night light
+	SetNightLight(ctx context.Context, enabled bool) error
+
+	// GetNightLight gets whether night light is enabled
+	GetNightLight(ctx context.Context) (bool, error)
+
+	// SetColorTemperature sets the night light color temperature in Kelvin
+	SetColorTemperature(ctx context.Context, kelvin int) error
+
+	// GetColorTemperature gets the night light color temperature in Kelvin
+	GetColorTemperature(ctx context.Context) (int, error)
+
 	// SetVolume sets the system volume level (0-100)
 	SetVolume(ctx context.Context, level int) error
 
@@ -148,6 +193,25 @@ type DesktopEnvironment interface {
 	// GetWifiStatus gets the current WiFi status
 	GetWifiStatus(ctx context.Context) (bool, error)
 
+	// ListWifiNetworks lists the Wi-Fi networks in range, strongest first
+	ListWifiNetworks(ctx context.Context) ([]WifiNetwork, error)
+
+	// ConnectWifiNetwork connects to a Wi-Fi network; the password is only
+	// needed the first time a secured network is joined
+	ConnectWifiNetwork(ctx context.Context, ssid, password string) error
+
+	// ForgetWifiNetwork deletes the saved connection to a Wi-Fi network
+	ForgetWifiNetwork(ctx context.Context, ssid string) error
+
+	// ListVPNConnections lists the saved VPN connections
+	ListVPNConnections(ctx context.Context) ([]VPNConnection, error)
+
+	// EnableVPN connects the VPN connection with the given name
+	EnableVPN(ctx context.Context, name string) error
+
+	// DisableVPN disconnects the VPN connection with the given name
+	DisableVPN(ctx context.Context, name string) error
+
 	// EnableBluetooth enables Bluetooth
 	EnableBluetooth(ctx context.Context) error
 
@@ -165,6 +229,18 @@ type DesktopEnvironment interface {
 
 	// GetHotspotStatus gets the current WiFi hotspot status
 	GetHotspotStatus(ctx context.Context) (bool, map[string]interface{}, error)
+
+	// RegisterShortcut registers a global keyboard shortcut, replacing one with the same ID
+	RegisterShortcut(ctx context.Context, shortcut Shortcut) error
+
+	// RemoveShortcut removes a keyboard shortcut registered by lumo
+	RemoveShortcut(ctx context.Context, id string) error
+
+	// ListShortcuts lists the keyboard shortcuts registered by lumo
+	ListShortcuts(ctx context.Context) ([]Shortcut, error)
+
+	// GetIdleTime gets how long the user has been idle
+	GetIdleTime(ctx context.Context) (time.Duration, error)
 }
 
 // DesktopFactory creates desktop environment instances
@@ -3,8 +3,10 @@ package desktop
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/palette"
 )
 
 // BaseEnvironment provides a base implementation of the core.DesktopEnvironment interface
@@ -72,6 +74,12 @@ func (e *BaseEnvironment) LaunchApplication(ctx context.Context, appName string,
 	return fmt.Errorf("not implemented")
 }
 
+// LaunchApplicationWithOptions launches an application with launch options
+func (e *BaseEnvironment) LaunchApplicationWithOptions(ctx context.Context, appName string, opts core.LaunchOptions) (*core.Window, error) {
+	// This should be overridden by specific implementations
+	return nil, fmt.Errorf("not implemented")
+}
+
 // CloseWindow closes a window
 func (e *BaseEnvironment) CloseWindow(ctx context.Context, windowID string) error {
 	// This should be overridden by specific implementations
@@ -114,6 +122,36 @@ func (e *BaseEnvironment) FocusWindow(ctx context.Context, windowID string) erro
 	return fmt.Errorf("not implemented")
 }
 
+// TileWindow tiles a window to a half or quarter of its monitor
+func (e *BaseEnvironment) TileWindow(ctx context.Context, windowID string, position core.TilePosition) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// GetMonitors returns the connected monitors
+func (e *BaseEnvironment) GetMonitors(ctx context.Context) ([]core.Monitor, error) {
+	// This should be overridden by specific implementations
+	return nil, fmt.Errorf("not implemented")
+}
+
+// MoveWindowToMonitor moves a window to another monitor
+func (e *BaseEnvironment) MoveWindowToMonitor(ctx context.Context, windowID string, monitor int) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// MoveWindowToWorkspace moves a window to a workspace
+func (e *BaseEnvironment) MoveWindowToWorkspace(ctx context.Context, windowID string, workspace int) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// SwitchWorkspace switches to a workspace
+func (e *BaseEnvironment) SwitchWorkspace(ctx context.Context, workspace int) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
 // ShowDesktop shows the desktop
 func (e *BaseEnvironment) ShowDesktop(ctx context.Context) error {
 	// This should be overridden by specific implementations
@@ -133,11 +171,17 @@ func (e *BaseEnvironment) CloseNotification(ctx context.Context, id uint32) erro
 }
 
 // TakeScreenshot takes a screenshot
-func (e *BaseEnvironment) TakeScreenshot(ctx context.Context, fullScreen bool, delay int) (string, error) {
+func (e *BaseEnvironment) TakeScreenshot(ctx context.Context, mode core.ScreenshotMode, delay int) (string, error) {
 	// This should be overridden by specific implementations
 	return "", fmt.Errorf("not implemented")
 }
 
+// PickColor lets the user pick a color on the screen
+func (e *BaseEnvironment) PickColor(ctx context.Context) (palette.Color, error) {
+	// This should be overridden by specific implementations
+	return palette.Color{}, fmt.Errorf("not implemented")
+}
+
 // GetClipboardText gets the text from the clipboard
 func (e *BaseEnvironment) GetClipboardText(ctx context.Context) (string, error) {
 	// This should be overridden by specific implementations
@@ -192,6 +236,30 @@ func (e *BaseEnvironment) GetCurrentIconTheme(ctx context.Context) (string, erro
 	return "", fmt.Errorf("not implemented")
 }
 
+// SetNightLight enables or disables night light
+func (e *BaseEnvironment) SetNightLight(ctx context.Context, enabled bool) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// GetNightLight gets whether night light is enabled
+func (e *BaseEnvironment) GetNightLight(ctx context.Context) (bool, error) {
+	// This should be overridden by specific implementations
+	return false, fmt.Errorf("not implemented")
+}
+
+// SetColorTemperature sets the night light color temperature in Kelvin
+func (e *BaseEnvironment) SetColorTemperature(ctx context.Context, kelvin int) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// GetColorTemperature gets the night light color temperature in Kelvin
+func (e *BaseEnvironment) GetColorTemperature(ctx context.Context) (int, error) {
+	// This should be overridden by specific implementations
+	return 0, fmt.Errorf("not implemented")
+}
+
 // SetVolume sets the system volume level (0-100)
 func (e *BaseEnvironment) SetVolume(ctx context.Context, level int) error {
 	// This should be overridden by specific implementations
@@ -312,6 +380,42 @@ func (e *BaseEnvironment) GetWifiStatus(ctx context.Context) (bool, error) {
 	return false, fmt.Errorf("not implemented")
 }
 
+// ListWifiNetworks lists the Wi-Fi networks in range
+func (e *BaseEnvironment) ListWifiNetworks(ctx context.Context) ([]core.WifiNetwork, error) {
+	// This should be overridden by specific implementations
+	return nil, fmt.Errorf("not implemented")
+}
+
+// ConnectWifiNetwork connects to a Wi-Fi network
+func (e *BaseEnvironment) ConnectWifiNetwork(ctx context.Context, ssid, password string) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// ForgetWifiNetwork deletes the saved connection to a Wi-Fi network
+func (e *BaseEnvironment) ForgetWifiNetwork(ctx context.Context, ssid string) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// ListVPNConnections lists the saved VPN connections
+func (e *BaseEnvironment) ListVPNConnections(ctx context.Context) ([]core.VPNConnection, error) {
+	// This should be overridden by specific implementations
+	return nil, fmt.Errorf("not implemented")
+}
+
+// EnableVPN connects a VPN connection
+func (e *BaseEnvironment) EnableVPN(ctx context.Context, name string) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// DisableVPN disconnects a VPN connection
+func (e *BaseEnvironment) DisableVPN(ctx context.Context, name string) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
 // EnableBluetooth enables Bluetooth
 func (e *BaseEnvironment) EnableBluetooth(ctx context.Context) error {
 	// This should be overridden by specific implementations
@@ -347,3 +451,39 @@ func (e *BaseEnvironment) GetHotspotStatus(ctx context.Context) (bool, map[strin
 	// This should be overridden by specific implementations
 	return false, nil, fmt.Errorf("not implemented")
 }
+
+// SetDoNotDisturb enables or disables do not disturb mode
+func (e *BaseEnvironment) SetDoNotDisturb(ctx context.Context, enabled bool) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// GetDoNotDisturb gets the current do not disturb state
+func (e *BaseEnvironment) GetDoNotDisturb(ctx context.Context) (bool, error) {
+	// This should be overridden by specific implementations
+	return false, fmt.Errorf("not implemented")
+}
+
+// RegisterShortcut registers a global keyboard shortcut
+func (e *BaseEnvironment) RegisterShortcut(ctx context.Context, shortcut core.Shortcut) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// RemoveShortcut removes a keyboard shortcut registered by lumo
+func (e *BaseEnvironment) RemoveShortcut(ctx context.Context, id string) error {
+	// This should be overridden by specific implementations
+	return fmt.Errorf("not implemented")
+}
+
+// ListShortcuts lists the keyboard shortcuts registered by lumo
+func (e *BaseEnvironment) ListShortcuts(ctx context.Context) ([]core.Shortcut, error) {
+	// This should be overridden by specific implementations
+	return nil, fmt.Errorf("not implemented")
+}
+
+// GetIdleTime gets how long the user has been idle
+func (e *BaseEnvironment) GetIdleTime(ctx context.Context) (time.Duration, error) {
+	// This should be overridden by specific implementations
+	return 0, fmt.Errorf("not implemented")
+}
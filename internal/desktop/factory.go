@@ -2,6 +2,7 @@ package desktop
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/agnath18K/lumo/dbus/common"
@@ -33,7 +34,7 @@ func (f *Factory) RegisterEnvironment(env core.DesktopEnvironment) {
 // DetectEnvironment detects the current desktop environment
 func (f *Factory) DetectEnvironment() (core.DesktopEnvironment, error) {
 	// Detect the current desktop environment
-	desktopName := common.DetectDesktopEnvironment()
+	desktopName := EnvironmentName(common.DetectDesktopEnvironment())
 
 	// Try to get the environment by name
 	env, err := f.GetEnvironment(desktopName)
@@ -54,6 +55,26 @@ func (f *Factory) DetectEnvironment() (core.DesktopEnvironment, error) {
 	return nil, fmt.Errorf("no available desktop environment found")
 }
 
+// EnvironmentName maps a detected desktop name to the name its environment
+// is registered under. XDG_CURRENT_DESKTOP may list several names separated
+// by colons (e.g. "ubuntu:GNOME"), and Plasma sessions report "KDE" or a
+// "plasma" session name depending on the display manager.
+func EnvironmentName(detected string) string {
+	parts := strings.Split(strings.ToLower(detected), ":")
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "kde" || strings.HasPrefix(part, "plasma"):
+			return "kde"
+		case strings.Contains(part, "gnome"):
+			return "gnome"
+		case strings.HasPrefix(part, "xfce"):
+			return "xfce"
+		}
+	}
+	return strings.TrimSpace(parts[0])
+}
+
 // GetEnvironment gets a specific desktop environment by name
 func (f *Factory) GetEnvironment(name string) (core.DesktopEnvironment, error) {
 	f.mutex.RLock()
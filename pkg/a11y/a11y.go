@@ -0,0 +1,72 @@
+// Package a11y turns lumo's decorated terminal output into plain linear
+// text that screen readers can follow: no colors, no box drawing, and
+// section headings announced in words instead of drawn into borders.
+package a11y
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// isBox reports whether r draws boxes, lines, or blocks
+func isBox(r rune) bool {
+	return r >= 0x2500 && r <= 0x259F
+}
+
+// Heading announces a section heading, e.g. "Section: Stats"
+func Heading(title string) string {
+	return "Section: " + strings.TrimSpace(title)
+}
+
+// Linearize rewrites output for screen readers. Borders are dropped,
+// titles drawn into them become headings, the sides of boxed lines and the
+// columns of tables become plain text, and repeated blank lines collapse
+// into one.
+func Linearize(text string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(utils.StripANSI(text), "\n") {
+		line = linearizeLine(line)
+		if line == "" {
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// linearizeLine rewrites one line of output, returning "" for a line that
+// only draws
+func linearizeLine(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return ""
+	}
+
+	// A border, with or without a title drawn into it
+	if first := []rune(line)[0]; isBox(first) && first != '│' && first != '┃' && first != '║' {
+		title := strings.TrimFunc(line, func(r rune) bool { return isBox(r) || unicode.IsSpace(r) })
+		title = strings.TrimLeftFunc(title, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if title == "" || strings.IndexFunc(title, isBox) >= 0 {
+			return ""
+		}
+		return Heading(title)
+	}
+
+	// The sides of a boxed line, and the columns of a table row
+	line = strings.TrimFunc(line, func(r rune) bool { return isBox(r) || unicode.IsSpace(r) })
+	var cells []string
+	for _, cell := range strings.FieldsFunc(line, isBox) {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, cell)
+		}
+	}
+	return strings.Join(cells, ", ")
+}
@@ -3,11 +3,16 @@ package agent
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/agnath18K/lumo/pkg/ai"
 	"github.com/agnath18K/lumo/pkg/config"
 	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/replay"
+	"github.com/agnath18K/lumo/pkg/utils"
 )
 
 // Agent represents the auto command executor
@@ -22,6 +27,12 @@ type Agent struct {
 
 // Execute processes a task and executes the necessary commands
 func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.Result, error) {
+	return a.ExecuteWithContext(ctx, taskDescription, "")
+}
+
+// ExecuteWithContext processes a task like Execute, planning it with
+// background such as the chat conversation that led to it
+func (a *Agent) ExecuteWithContext(ctx context.Context, taskDescription, background string) (*executor.Result, error) {
 	// Check if agent mode is enabled
 	if !a.config.EnableAgentMode {
 		return &executor.Result{
@@ -30,10 +41,41 @@ func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.
 		}, nil
 	}
 
+	// Runs followed by an observer, such as from the web UI, ask it
+	// instead of the terminal
+	observer := executor.AgentObserverFrom(ctx)
+
+	// agent:resume <run-id> continues a journaled run instead of planning a new one
+	if runID, ok := cutResume(taskDescription); ok {
+		if observer != nil {
+			return &executor.Result{
+				IsError: true,
+				Output:  "Runs can only be resumed from the terminal.",
+			}, nil
+		}
+		return a.resume(ctx, runID)
+	}
+
+	// agent:approvals, agent:approve, and agent:reject answer remote runs
+	// from this machine's console
+	if verb, token, ok := cutApproval(taskDescription); ok {
+		if observer != nil {
+			return &executor.Result{
+				IsError: true,
+				Output:  "Approvals can only be answered from this machine's console or the approval endpoint.",
+			}, nil
+		}
+		return answerApproval(verb, token), nil
+	}
+
+	// A leading --dry-run only reports what the plan would touch
+	taskDescription, dryRun := cutDryRunFlag(taskDescription)
+
 	// Create a new task
 	task := &Task{
 		Description: taskDescription,
 		CreatedAt:   time.Now(),
+		Context:     background,
 	}
 
 	// Update agent state
@@ -51,6 +93,30 @@ func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.
 
 	// Update agent state
 	a.state.CurrentPlan = plan
+	notify(ctx, planEvent(plan))
+
+	if dryRun {
+		return a.dryRun(plan, observer != nil)
+	}
+
+	// Give the user's pre-agent-run hook a chance to veto the plan
+	if err := hooks.Run(ctx, hooks.EventPreAgentRun, planHookData(plan)); err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Agent run blocked by hook: %v", err),
+		}, nil
+	}
+
+	// Checksum the files the plan refers to before anything changes them
+	inputs := replay.ChecksumInputs(planCommands(plan))
+
+	// Journal the run as it executes so it can be resumed if interrupted
+	if journal, err := NewJournal(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create run journal: %v\n", err)
+	} else {
+		plan.Journal = journal
+		fmt.Printf("\n📓 Run %s is journaled. If it is interrupted, continue it with: lumo agent:resume %s\n", journal.ID(), journal.ID())
+	}
 
 	// Display warning about agent mode
 	fmt.Println("\nAGENT MODE WARNING:")
@@ -62,7 +128,7 @@ func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.
 	var result *ExecutionResult
 	var executionErr error
 
-	if a.config.EnableAgentREPL {
+	if a.config.EnableAgentREPL && !executor.PlanApproved(ctx) && observer == nil {
 		// Use interactive REPL mode
 		result, executionErr = a.feedback.InteractiveREPL(ctx, plan, a.executor)
 		if executionErr != nil {
@@ -83,9 +149,10 @@ func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.
 		// Use traditional confirmation mode
 		a.feedback.DisplayPlan(plan)
 
-		// Confirm execution with the user if required
-		if a.config.AgentConfirmBeforeExecution {
-			confirmed, err := a.feedback.ConfirmExecution()
+		// Confirm execution with the user if required; runs followed by an
+		// observer always ask, since they were requested remotely
+		if (a.config.AgentConfirmBeforeExecution || observer != nil) && !executor.PlanApproved(ctx) {
+			confirmed, err := a.confirmPlan(ctx)
 			if err != nil {
 				return &executor.Result{
 					IsError: true,
@@ -121,6 +188,11 @@ func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.
 		a.state.Status = StatusFailed
 	}
 
+	// Record the run so it can be replayed with `lumo last --as-script`
+	if err := replay.Save(replayRecord(result, inputs)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record agent run: %v\n", err)
+	}
+
 	// Provide final summary
 	a.feedback.DisplaySummary(result)
 
@@ -130,3 +202,117 @@ func (a *Agent) Execute(ctx context.Context, taskDescription string) (*executor.
 		Output:  result.Message,
 	}, nil
 }
+
+// confirmPlan asks whether to execute the plan: the observer following the
+// run if there is one, or else the terminal
+func (a *Agent) confirmPlan(ctx context.Context) (bool, error) {
+	if observer := executor.AgentObserverFrom(ctx); observer != nil {
+		return observer.Confirm(ctx, executor.AgentEvent{
+			Type:    executor.AgentEventConfirm,
+			Subject: "plan",
+		}), nil
+	}
+	return a.feedback.ConfirmExecution()
+}
+
+// planHookData converts a plan into the payload sent to agent hooks
+func planHookData(plan *Plan) map[string]interface{} {
+	steps := make([]map[string]interface{}, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		steps = append(steps, map[string]interface{}{
+			"id":          step.ID,
+			"command":     step.Command,
+			"description": step.Description,
+			"is_critical": step.IsCritical,
+		})
+	}
+
+	return map[string]interface{}{
+		"task":        plan.Task.Description,
+		"description": plan.Description,
+		"steps":       steps,
+	}
+}
+
+// planCommands returns the commands of every step in a plan
+func planCommands(plan *Plan) []string {
+	commands := make([]string, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		commands = append(commands, step.Command)
+	}
+	return commands
+}
+
+// replayRecord converts an executed plan into a replay record
+func replayRecord(result *ExecutionResult, inputs []replay.FileChecksum) *replay.Record {
+	plan := result.Plan
+	record := &replay.Record{
+		Source:      "agent",
+		Description: plan.Description,
+		StartedAt:   result.StartTime,
+		FinishedAt:  result.EndTime,
+		Success:     result.Success,
+		Environment: replay.CaptureEnvironment(planCommands(plan)),
+		Inputs:      inputs,
+	}
+	if plan.Task != nil {
+		record.Task = plan.Task.Description
+	}
+
+	for _, step := range plan.Steps {
+		recorded := replay.Step{
+			Command:     step.Command,
+			Description: step.Description,
+			Critical:    step.IsCritical,
+			Executed:    step.Executed,
+		}
+		if step.Result != nil {
+			recorded.Success = step.Result.Success
+			recorded.Duration = step.Result.Duration
+			recorded.OutputSHA256 = replay.OutputChecksum(step.Result.Output)
+			if step.Result.Error != nil {
+				recorded.Error = step.Result.Error.Error()
+			}
+		}
+		record.Steps = append(record.Steps, recorded)
+	}
+
+	return record
+}
+
+// cutDryRunFlag removes a leading --dry-run flag from a task description
+func cutDryRunFlag(taskDescription string) (string, bool) {
+	trimmed := strings.TrimSpace(taskDescription)
+	rest, found := strings.CutPrefix(trimmed, "--dry-run")
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return taskDescription, false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// dryRun shows the plan and what each step would touch without executing
+// anything. Observers, which do not see the terminal, get the report as the
+// output.
+func (a *Agent) dryRun(plan *Plan, observed bool) (*executor.Result, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to get working directory: %v", err),
+		}, nil
+	}
+
+	a.feedback.DisplayPlan(plan)
+	report := SimulatePlan(plan, workDir)
+	fmt.Print(report.Format(utils.IsTerminal(os.Stdout) && !a.config.Accessible()))
+	a.state.Status = StatusIdle
+
+	output := "Dry run complete: no commands were executed."
+	if observed {
+		output = report.Format(false) + "\n" + output
+	}
+	return &executor.Result{
+		IsError: false,
+		Output:  output,
+	}, nil
+}
@@ -0,0 +1,417 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/shellwords"
+	"github.com/agnath18K/lumo/pkg/system"
+)
+
+// maxFindingOutput is how much of each step's output is sent to the AI when
+// it explains the findings of an analysis
+const maxFindingOutput = 4000
+
+// analysisTools are the programs an analysis may run. Unlike readOnlyTools,
+// which only predicts effects, this is an allowlist: anything not named here
+// is refused, so an unfamiliar tool can never change the system.
+var analysisTools = map[string]bool{
+	"ls": true, "cat": true, "head": true, "tail": true, "grep": true, "egrep": true, "fgrep": true,
+	"rg": true, "echo": true, "printf": true, "pwd": true, "wc": true, "du": true, "df": true,
+	"stat": true, "file": true, "which": true, "whereis": true, "type": true, "date": true,
+	"uname": true, "whoami": true, "id": true, "groups": true, "ps": true, "pgrep": true, "free": true,
+	"uptime": true, "printenv": true, "uniq": true, "cut": true, "tr": true, "awk": true,
+	"diff": true, "cmp": true, "md5sum": true, "sha1sum": true, "sha256sum": true, "test": true,
+	"[": true, "[[": true, "true": true, "false": true, "sleep": true, "hostname": true,
+	"ping": true, "dig": true, "nslookup": true, "host": true, "lsblk": true, "lscpu": true,
+	"lsmem": true, "lspci": true, "lsusb": true, "lsmod": true, "blkid": true, "findmnt": true,
+	"tree": true, "realpath": true, "readlink": true, "basename": true, "dirname": true, "jq": true,
+	"column": true, "seq": true, "find": true, "sort": true, "nproc": true, "arch": true,
+	"locale": true, "getent": true, "last": true, "lastlog": true, "w": true, "who": true,
+	"dmesg": true, "journalctl": true, "lsof": true, "ss": true, "netstat": true, "vmstat": true,
+	"iostat": true, "mpstat": true, "sar": true, "strings": true, "hexdump": true,
+	"od": true, "nl": true, "fold": true, "comm": true, "join": true, "paste": true, "expand": true,
+	"cd": true, "pushd": true, "popd": true, "git": true, "systemctl": true, "docker": true,
+	"podman": true, "ip": true, "dpkg": true, "dpkg-query": true, "rpm": true, "apt": true,
+	"apt-cache": true, "flatpak": true, "snap": true, "timedatectl": true, "hostnamectl": true,
+	"loginctl": true, "top": true, "sensors": true, "smartctl": true, "zcat": true, "zgrep": true,
+}
+
+// readOnlySubcommands lists the subcommands that only report state, for
+// tools that can also change it
+var readOnlySubcommands = map[string]map[string]bool{
+	"git": wordSet("status", "log", "diff", "show", "blame", "ls-files", "ls-tree", "rev-parse",
+		"describe", "shortlog", "count-objects", "cat-file", "grep", "reflog"),
+	"systemctl": wordSet("status", "show", "cat", "list-units", "list-unit-files", "list-timers",
+		"list-sockets", "list-dependencies", "is-active", "is-enabled", "is-failed", "is-system-running"),
+	"docker":      wordSet("ps", "images", "inspect", "logs", "stats", "top", "info", "version", "df", "port", "history"),
+	"podman":      wordSet("ps", "images", "inspect", "logs", "stats", "top", "info", "version", "port", "history"),
+	"apt":         wordSet("list", "show", "search", "policy"),
+	"apt-cache":   wordSet("show", "search", "policy", "depends", "rdepends", "showpkg", "stats"),
+	"flatpak":     wordSet("list", "info", "search", "history"),
+	"snap":        wordSet("list", "info", "find", "changes", "services"),
+	"timedatectl": wordSet("status", "show", "list-timezones", "timesync-status"),
+	"hostnamectl": wordSet("status"),
+	"loginctl":    wordSet("list-sessions", "list-users", "session-status", "user-status", "show-session", "show-user"),
+}
+
+// wordSet builds a lookup table from a list of words
+func wordSet(words ...string) map[string]bool {
+	table := make(map[string]bool, len(words))
+	for _, word := range words {
+		table[word] = true
+	}
+	return table
+}
+
+// awkSideEffects matches awk code that runs commands or writes files
+var awkSideEffects = regexp.MustCompile(`system\s*\(|\|\s*getline|\bprintf?\b[^;}]*(>|\|)`)
+
+// ReadOnlyViolation returns why a command could change the system, or ""
+// if it only inspects it. Every simple command must use an allowlisted
+// inspection tool, and the dry-run classification must predict no writes,
+// which catches redirections and output files.
+func ReadOnlyViolation(command string) string {
+	if strings.TrimSpace(command) == "" {
+		return "empty command"
+	}
+	if assistant.IsAction(command) {
+		return desktopReadOnlyViolation(command)
+	}
+	if strings.Contains(command, "`") {
+		return "command substitution with backticks is not allowed"
+	}
+	for _, inner := range quotedSubstitutions(command) {
+		if reason := ReadOnlyViolation(inner); reason != "" {
+			return reason
+		}
+	}
+
+	workDir, _ := os.Getwd()
+	sim := newSimulator(workDir)
+	for _, words := range shellCommands(command) {
+		words = shellwords.StripKeywords(words)
+		if len(words) == 0 {
+			continue
+		}
+		if reason := readOnlyCommandViolation(words); reason != "" {
+			return reason
+		}
+		for _, effect := range sim.simulate(words) {
+			// Unknown and system effects are for tools the allowlist has
+			// already ruled on; files written are always refused
+			switch effect.Kind {
+			case EffectCreate, EffectModify, EffectDelete:
+				if effect.Note != "" {
+					return fmt.Sprintf("writes %s (%s)", effect.Path, effect.Note)
+				}
+				return fmt.Sprintf("writes %s", effect.Path)
+			}
+		}
+	}
+	return ""
+}
+
+// quotedSubstitutions returns the commands of $(...) substitutions inside
+// double quotes, which shellCommands reads as part of a word. Arithmetic
+// $((...)) runs no command.
+func quotedSubstitutions(command string) []string {
+	var commands []string
+	var quote rune
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			}
+		case r == '\\':
+			i++
+		case quote == '"' && r == '$' && i+1 < len(runes) && runes[i+1] == '(':
+			if i+2 < len(runes) && runes[i+2] == '(' {
+				continue
+			}
+			depth, end := 0, len(runes)
+			for j := i + 1; j < len(runes); j++ {
+				if runes[j] == '(' {
+					depth++
+				} else if runes[j] == ')' {
+					if depth--; depth == 0 {
+						end = j
+						break
+					}
+				}
+			}
+			commands = append(commands, string(runes[i+2:end]))
+			i = end
+		case r == '"':
+			if quote == '"' {
+				quote = 0
+			} else {
+				quote = '"'
+			}
+		case r == '\'' && quote == 0:
+			quote = '\''
+		}
+	}
+	return commands
+}
+
+// readOnlyCommandViolation checks one simple command against the allowlist
+func readOnlyCommandViolation(words []string) string {
+	// A program named by a variable, glob, or substitution could be anything
+	if strings.ContainsAny(words[0], "$`*?[]{}~\\") && words[0] != "[" && words[0] != "[[" {
+		return fmt.Sprintf("'%s' does not name a program literally", words[0])
+	}
+	tool := filepath.Base(words[0])
+	if !analysisTools[tool] {
+		return fmt.Sprintf("'%s' is not an inspection tool", tool)
+	}
+
+	args, _ := splitRedirects(words[1:])
+	var flags, operands []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			flags = append(flags, arg)
+		} else {
+			operands = append(operands, arg)
+		}
+	}
+
+	// Without a subcommand these tools only print their state or usage
+	if subcommands, ok := readOnlySubcommands[tool]; ok && len(operands) > 0 && !subcommands[operands[0]] {
+		return fmt.Sprintf("'%s %s' may change the system", tool, operands[0])
+	}
+
+	switch tool {
+	case "date":
+		if hasFlag(flags, "-s") || hasFlag(flags, "--set") {
+			return "'date --set' changes the clock"
+		}
+	case "hostname":
+		if len(operands) > 0 || hasFlag(flags, "-F") || hasFlag(flags, "--file") {
+			return "'hostname' with a name changes the hostname"
+		}
+	case "uniq":
+		if len(operands) > 1 {
+			return "'uniq' with two files writes the second"
+		}
+	case "tree":
+		if hasFlag(flags, "-o") {
+			return "'tree -o' writes a file"
+		}
+	case "awk":
+		for _, arg := range args {
+			if awkSideEffects.MatchString(arg) {
+				return "'awk' programs that run commands or write files are not allowed"
+			}
+		}
+	case "find":
+		for _, flag := range flags {
+			switch flag {
+			case "-delete", "-exec", "-execdir", "-ok", "-okdir", "-fprint", "-fprint0", "-fprintf", "-fls":
+				return fmt.Sprintf("'find %s' may change or write files", flag)
+			}
+		}
+	case "journalctl":
+		for _, flag := range flags {
+			if strings.HasPrefix(flag, "--vacuum") || flag == "--rotate" || flag == "--flush" || flag == "--sync" {
+				return fmt.Sprintf("'journalctl %s' changes the journal", flag)
+			}
+		}
+	case "dmesg":
+		if hasFlag(flags, "-c") || hasFlag(flags, "-C") || hasFlag(flags, "--clear") || hasFlag(flags, "--read-clear") {
+			return "'dmesg --clear' clears the kernel log"
+		}
+	case "ip":
+		for _, operand := range operands {
+			switch operand {
+			case "add", "del", "delete", "set", "change", "replace", "flush", "append", "prepend", "exec":
+				return fmt.Sprintf("'ip ... %s' changes the network configuration", operand)
+			}
+		}
+	case "dpkg":
+		for _, flag := range flags {
+			if !strings.HasPrefix(flag, "-l") && !strings.HasPrefix(flag, "-L") && !strings.HasPrefix(flag, "-s") &&
+				!strings.HasPrefix(flag, "-S") && !strings.HasPrefix(flag, "-p") && !strings.HasPrefix(flag, "--list") &&
+				!strings.HasPrefix(flag, "--status") && !strings.HasPrefix(flag, "--search") &&
+				flag != "--get-selections" && flag != "--print-architecture" {
+				return fmt.Sprintf("'dpkg %s' may change installed packages", flag)
+			}
+		}
+	case "rpm":
+		if len(flags) == 0 || !(strings.HasPrefix(flags[0], "-q") || flags[0] == "--query" || strings.HasPrefix(flags[0], "-V") || flags[0] == "--verify") {
+			return "'rpm' may only query packages"
+		}
+	case "smartctl":
+		for _, flag := range flags {
+			if hasFlag([]string{flag}, "-t") || hasFlag([]string{flag}, "-s") || strings.HasPrefix(flag, "--test") || strings.HasPrefix(flag, "--smart") {
+				return fmt.Sprintf("'smartctl %s' changes the drive's settings or starts a test", flag)
+			}
+		}
+	case "git":
+		for _, flag := range flags {
+			if hasFlag([]string{flag}, "--output") {
+				return "'git --output' writes a file"
+			}
+			if hasFlag([]string{flag}, "-O") || hasFlag([]string{flag}, "--open-files-in-pager") {
+				return "'git grep -O' runs a pager on the matches"
+			}
+		}
+	case "ss":
+		if hasFlag(flags, "-K") || hasFlag(flags, "--kill") {
+			return "'ss --kill' closes sockets"
+		}
+		if hasFlag(flags, "-D") || hasFlag(flags, "--diag") {
+			return "'ss --diag' writes a file"
+		}
+	case "sar":
+		if hasFlag(flags, "-o") {
+			return "'sar -o' writes a file"
+		}
+	case "top":
+		if !hasFlag(flags, "-b") {
+			return "'top' needs -b to run without a terminal"
+		}
+	}
+	return ""
+}
+
+// Analyze investigates a question with a plan of read-only commands. Steps
+// that could change anything are dropped before the plan is shown and refused
+// again when they would run, and the AI then explains what the output shows.
+func (a *Agent) Analyze(ctx context.Context, question string) (*executor.Result, error) {
+	if !a.config.EnableAgentMode {
+		return &executor.Result{
+			IsError: true,
+			Output:  "Agent mode is disabled. Enable it in the configuration file.",
+		}, nil
+	}
+
+	task := &Task{
+		Description: question,
+		CreatedAt:   time.Now(),
+		ReadOnly:    true,
+	}
+	a.state.Status = StatusPlanning
+	a.state.CurrentTask = task
+
+	plan, err := a.planner.CreatePlan(ctx, task)
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to create plan: %v", err),
+		}, nil
+	}
+	plan.ReadOnly = true
+	a.state.CurrentPlan = plan
+
+	// Drop steps that could change the system rather than running around them
+	var kept []*Step
+	for _, step := range plan.Steps {
+		if reason := ReadOnlyViolation(step.Command); reason != "" {
+			fmt.Printf("🚫 Dropped step %d (%s): %s\n", step.ID, step.Command, reason)
+			continue
+		}
+		kept = append(kept, step)
+	}
+	plan.Steps = kept
+	if len(plan.Steps) == 0 {
+		a.state.Status = StatusFailed
+		return &executor.Result{
+			IsError: true,
+			Output:  "The plan had no read-only steps to run. Try rephrasing the question.",
+		}, nil
+	}
+
+	fmt.Println("\n🔍 ANALYSIS MODE: only commands that inspect the system will run.")
+	a.feedback.DisplayPlan(plan)
+	if a.config.AgentConfirmBeforeExecution {
+		confirmed, err := a.feedback.ConfirmExecution()
+		if err != nil {
+			return &executor.Result{
+				IsError: true,
+				Output:  fmt.Sprintf("Failed to confirm execution: %v", err),
+			}, nil
+		}
+		if !confirmed {
+			a.state.Status = StatusIdle
+			return &executor.Result{
+				IsError: false,
+				Output:  "Analysis cancelled by user.",
+			}, nil
+		}
+	}
+
+	a.state.Status = StatusExecuting
+	result, err := a.executor.ExecutePlan(ctx, plan, a.feedback)
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to execute plan: %v", err),
+		}, nil
+	}
+	a.feedback.DisplaySummary(result)
+	a.state.Status = StatusCompleted
+
+	findings, err := a.explainFindings(ctx, question, plan)
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("The commands ran, but explaining their output failed: %v", err),
+		}, nil
+	}
+	return &executor.Result{
+		IsError: false,
+		Output:  findings,
+	}, nil
+}
+
+// explainFindings asks the AI to answer the question from the output of the
+// analysis steps
+func (a *Agent) explainFindings(ctx context.Context, question string, plan *Plan) (string, error) {
+	var b strings.Builder
+	for _, step := range plan.Steps {
+		if !step.Executed || step.Result == nil {
+			continue
+		}
+		output := step.Result.Output
+		if len(output) > maxFindingOutput {
+			output = "...\n" + output[len(output)-maxFindingOutput:]
+		}
+		b.WriteString(fmt.Sprintf("$ %s\n", step.Command))
+		if step.Result.Error != nil {
+			b.WriteString(fmt.Sprintf("(failed: %v)\n", step.Result.Error))
+		}
+		b.WriteString(output)
+		b.WriteString("\n")
+	}
+
+	prompt := fmt.Sprintf(`
+You are Lumo, an AI-powered command-line assistant.
+The user asked you to investigate the following on their system:
+
+%s
+
+The system is:
+%s
+These read-only commands were run, with their output:
+
+%s
+Answer the question from this output. Name the specific causes you found,
+quoting the relevant numbers, paths, or messages. If the output is not
+enough to tell, say what is missing. Where the user would need to change
+something, suggest the commands, but do not claim they were run.
+Keep the answer concise and use plain text.
+`, question, system.DetectPlatform().PromptContext(), b.String())
+
+	return a.aiClient.GetCompletion(ctx, prompt)
+}
@@ -0,0 +1,275 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// ApprovalTTL is how long the token of a remote run's approval stays valid
+const ApprovalTTL = 2 * time.Minute
+
+// approvalPollInterval is how often a waiting run checks for an answer
+const approvalPollInterval = 250 * time.Millisecond
+
+// Errors answering an approval
+var (
+	ErrApprovalNotFound = errors.New("no agent run is waiting for that approval token")
+	ErrApprovalExpired  = errors.New("the approval token has expired")
+)
+
+// approvalTokenPattern matches approval tokens
+var approvalTokenPattern = regexp.MustCompile(`^[0-9a-f]{10}$`)
+
+// Approval is a confirmation that an agent run requested over the REST API
+// is waiting on. It is kept as a file in the state directory, so it can be
+// answered from this machine's console with agent:approve or agent:reject,
+// or by an authenticated client of the approval endpoint that has the token,
+// until it expires.
+type Approval struct {
+	Token string `json:"token"`
+	Task  string `json:"task"`
+	// Subject is what needs approval: plan, step, or revision
+	Subject   string               `json:"subject"`
+	Reason    string               `json:"reason,omitempty"`
+	Steps     []executor.AgentStep `json:"steps,omitempty"`
+	CreatedAt time.Time            `json:"created_at"`
+	ExpiresAt time.Time            `json:"expires_at"`
+	// Approved is set once the approval is answered
+	Approved *bool `json:"approved,omitempty"`
+
+	path string
+}
+
+// approvalDir returns the directory that holds pending approvals
+func approvalDir() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "approvals"), nil
+}
+
+// RequestApproval records that a remote run needs approval for what a
+// confirmation event describes, with the steps it concerns
+func RequestApproval(task string, event executor.AgentEvent, steps []executor.AgentStep) (*Approval, error) {
+	dir, err := approvalDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create approval directory: %w", err)
+	}
+	pruneApprovals(dir)
+
+	b := make([]byte, 5)
+	rand.Read(b)
+	now := time.Now()
+	approval := &Approval{
+		Token:     hex.EncodeToString(b),
+		Task:      task,
+		Subject:   event.Subject,
+		Reason:    event.Reason,
+		Steps:     steps,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ApprovalTTL),
+	}
+	approval.path = filepath.Join(dir, approval.Token+".json")
+	if err := approval.save(); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// Wait waits for the approval to be answered and reports whether it was
+// approved. It returns false once the approval expires or ctx is done.
+func (a *Approval) Wait(ctx context.Context) bool {
+	defer os.Remove(a.path)
+
+	ticker := time.NewTicker(approvalPollInterval)
+	defer ticker.Stop()
+	for {
+		if current, err := loadApproval(a.path); err == nil && current.Approved != nil {
+			return *current.Approved
+		}
+		if time.Now().After(a.ExpiresAt) {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// AnswerApproval approves or rejects the approval with a token
+func AnswerApproval(token string, approve bool) (*Approval, error) {
+	if !approvalTokenPattern.MatchString(token) {
+		return nil, ErrApprovalNotFound
+	}
+	dir, err := approvalDir()
+	if err != nil {
+		return nil, err
+	}
+	approval, err := loadApproval(filepath.Join(dir, token+".json"))
+	if err != nil {
+		return nil, ErrApprovalNotFound
+	}
+	if approval.Approved != nil {
+		return nil, ErrApprovalNotFound
+	}
+	if time.Now().After(approval.ExpiresAt) {
+		return nil, ErrApprovalExpired
+	}
+
+	approval.Approved = &approve
+	if err := approval.save(); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// PendingApprovals returns the approvals still waiting for an answer,
+// oldest first
+func PendingApprovals() ([]*Approval, error) {
+	dir, err := approvalDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var approvals []*Approval
+	for _, path := range files {
+		approval, err := loadApproval(path)
+		if err != nil || approval.Approved != nil || time.Now().After(approval.ExpiresAt) {
+			continue
+		}
+		approvals = append(approvals, approval)
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].CreatedAt.Before(approvals[j].CreatedAt)
+	})
+	return approvals, nil
+}
+
+// pruneApprovals deletes approvals that expired without their run cleaning
+// up, such as when the server stopped while waiting
+func pruneApprovals(dir string) {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range files {
+		if approval, err := loadApproval(path); err == nil && time.Since(approval.ExpiresAt) > ApprovalTTL {
+			os.Remove(path)
+		}
+	}
+}
+
+// loadApproval reads an approval file
+func loadApproval(path string) (*Approval, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var approval Approval
+	if err := json.Unmarshal(data, &approval); err != nil {
+		return nil, err
+	}
+	approval.path = path
+	return &approval, nil
+}
+
+// save writes the approval, replacing the file so a waiting run never
+// reads it half-written
+func (a *Approval) save() error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := a.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to save approval: %w", err)
+	}
+	return os.Rename(tmp, a.path)
+}
+
+// cutApproval recognizes "approvals", "approve <token>", and
+// "reject <token>", returning the verb and token
+func cutApproval(taskDescription string) (string, string, bool) {
+	fields := strings.Fields(taskDescription)
+	switch {
+	case len(fields) == 1 && fields[0] == "approvals":
+		return fields[0], "", true
+	case len(fields) == 2 && (fields[0] == "approve" || fields[0] == "reject") && approvalTokenPattern.MatchString(fields[1]):
+		return fields[0], fields[1], true
+	}
+	return "", "", false
+}
+
+// answerApproval handles agent:approvals, agent:approve, and agent:reject
+// on this machine's console
+func answerApproval(verb, token string) *executor.Result {
+	if verb == "approvals" {
+		return listApprovals()
+	}
+
+	approval, err := AnswerApproval(token, verb == "approve")
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to %s %s: %v", verb, token, err),
+		}
+	}
+	what := approval.Subject
+	if what == "plan" {
+		what = "plan for: " + approval.Task
+	}
+	if verb == "approve" {
+		return &executor.Result{Output: fmt.Sprintf("✅ Approved the %s", what)}
+	}
+	return &executor.Result{Output: fmt.Sprintf("Rejected the %s", what)}
+}
+
+// listApprovals lists the remote runs waiting for approval
+func listApprovals() *executor.Result {
+	approvals, err := PendingApprovals()
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to list approvals: %v", err),
+		}
+	}
+	if len(approvals) == 0 {
+		return &executor.Result{Output: "No remote agent runs are waiting for approval."}
+	}
+
+	var b strings.Builder
+	b.WriteString("Remote agent runs waiting for approval:\n")
+	for _, approval := range approvals {
+		b.WriteString(fmt.Sprintf("\n  %s  %s: %s (expires in %s)\n",
+			approval.Token, approval.Subject, approval.Task, time.Until(approval.ExpiresAt).Round(time.Second)))
+		if approval.Reason != "" {
+			b.WriteString(fmt.Sprintf("    %s\n", approval.Reason))
+		}
+		for _, step := range approval.Steps {
+			b.WriteString(fmt.Sprintf("    %d. %s\n", step.ID, step.Command))
+		}
+	}
+	b.WriteString("\nAnswer with: lumo agent:approve <token> or lumo agent:reject <token>")
+
+	return &executor.Result{Output: b.String()}
+}
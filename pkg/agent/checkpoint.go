@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// shellOwnedVariables are set by bash itself and are never restored
+var shellOwnedVariables = map[string]bool{
+	"PWD": true, "OLDPWD": true, "SHLVL": true, "_": true,
+}
+
+// secretNameParts mark variables that are kept out of journals; a resumed
+// run has to set them again
+var secretNameParts = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "CREDENTIAL"}
+
+// ShellState is what earlier steps left behind in the plan's bash session,
+// which a resumed run starts from
+type ShellState struct {
+	// Dir is the session's working directory
+	Dir string `json:"dir"`
+	// Env holds the variables the steps set or changed
+	Env map[string]string `json:"env,omitempty"`
+	// Withheld names variables that were set but look like secrets, so
+	// were not recorded
+	Withheld []string `json:"withheld,omitempty"`
+}
+
+// captureShellState asks the plan's bash session for its working directory
+// and environment
+func (e *Executor) captureShellState(stdin io.Writer, scanner *bufio.Scanner) *ShellState {
+	marker := fmt.Sprintf("LUMO_STATE_COMPLETE_%d", time.Now().UnixNano())
+	fmt.Fprintf(stdin, "printf '%%s\\n' \"$PWD\"\nenv\necho %s\n", marker)
+
+	var lines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == marker {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return newShellState(lines[0], parseEnv(lines[1:]), os.Environ())
+}
+
+// newShellState records the variables of env that differ from those lumo
+// started with
+func newShellState(dir string, env map[string]string, base []string) *ShellState {
+	inherited := parseEnv(base)
+	state := &ShellState{Dir: dir, Env: make(map[string]string)}
+	for name, value := range env {
+		if shellOwnedVariables[name] || inherited[name] == value {
+			continue
+		}
+		if looksSecret(name) {
+			state.Withheld = append(state.Withheld, name)
+			continue
+		}
+		state.Env[name] = value
+	}
+	sort.Strings(state.Withheld)
+	return state
+}
+
+// parseEnv parses NAME=value lines; a line without = continues the value
+// of the previous variable
+func parseEnv(lines []string) map[string]string {
+	env := make(map[string]string)
+	last := ""
+	for _, line := range lines {
+		name, value, found := strings.Cut(line, "=")
+		if !found || name == "" || strings.ContainsAny(name, " \t") {
+			if last != "" {
+				env[last] += "\n" + line
+			}
+			continue
+		}
+		env[name] = value
+		last = name
+	}
+	return env
+}
+
+// looksSecret reports whether a variable name suggests it holds a secret
+func looksSecret(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, part := range secretNameParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// environ returns the environment of a session restored to the state,
+// starting from base
+func (s *ShellState) environ(base []string) []string {
+	if s == nil || len(s.Env) == 0 {
+		return base
+	}
+	env := make([]string, 0, len(base)+len(s.Env))
+	for _, entry := range base {
+		name, _, _ := strings.Cut(entry, "=")
+		if _, restored := s.Env[name]; !restored {
+			env = append(env, entry)
+		}
+	}
+	for name, value := range s.Env {
+		env = append(env, name+"="+value)
+	}
+	return env
+}
@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+)
+
+// desktopStepData is a desktop action as the AI gives it in a plan, in place
+// of a shell command
+type desktopStepData struct {
+	Type      string                 `json:"type"`
+	Action    string                 `json:"action"`
+	Target    interface{}            `json:"target"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// stepData is a step as the AI gives it in a plan
+type stepData struct {
+	ID          int              `json:"id"`
+	Command     string           `json:"command"`
+	Desktop     *desktopStepData `json:"desktop"`
+	Description string           `json:"description"`
+	IsCritical  bool             `json:"isCritical"`
+	Retries     int              `json:"retries"`
+}
+
+// step converts the AI's step, turning a desktop action into its text form
+func (d stepData) step() *Step {
+	command := d.Command
+	if d.Desktop != nil && strings.TrimSpace(command) == "" {
+		// Numbers such as a volume level may be given unquoted
+		target := ""
+		if d.Desktop.Target != nil {
+			target = fmt.Sprint(d.Desktop.Target)
+		}
+		command = assistant.FormatAction(&core.Command{
+			Type:      core.CommandType(d.Desktop.Type),
+			Action:    d.Desktop.Action,
+			Target:    target,
+			Arguments: d.Desktop.Arguments,
+		})
+	}
+	return &Step{
+		ID:          d.ID,
+		Command:     command,
+		Description: d.Description,
+		IsCritical:  d.IsCritical,
+		Retries:     d.Retries,
+	}
+}
+
+// hasDesktopSession reports whether lumo runs inside a graphical session
+// that desktop actions can control
+func hasDesktopSession() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("DISPLAY") != ""
+}
+
+// desktopActionsPrompt tells the AI which desktop actions a plan can use, or
+// nothing outside a graphical session or in safe mode
+func desktopActionsPrompt(cfg *config.Config) string {
+	if !hasDesktopSession() || cfg.Safe {
+		return ""
+	}
+	return `
+For desktop settings, use a desktop action instead of a shell command such as
+pactl, amixer, nmcli, rfkill, notify-send, playerctl, or gsettings. Desktop
+actions work the same on every desktop environment. Give the step a "desktop"
+object in place of "command", for example:
+{"desktop": {"type": "sound", "action": "set-volume", "target": "40"}, "description": "...", "isCritical": false, "retries": 0}
+These desktop actions are available:
+` + assistant.ActionCatalog()
+}
+
+// desktopReadOnlyViolation explains why a desktop action step could change the
+// system, or returns an empty string if it only reports state
+func desktopReadOnlyViolation(command string) string {
+	_, spec, err := assistant.ParseAction(command)
+	if err != nil {
+		return err.Error()
+	}
+	if !spec.ReadOnly {
+		return fmt.Sprintf("desktop action %s:%s would %s", spec.Type, spec.Action, spec.Description)
+	}
+	return ""
+}
+
+// executeDesktopAction runs a desktop action step through the desktop
+// assistant rather than the shell
+func (e *Executor) executeDesktopAction(ctx context.Context, step *Step, result *StepResult) {
+	cmd, _, err := assistant.ParseAction(step.Command)
+	if err == nil && e.config.Safe {
+		err = fmt.Errorf("desktop actions do not run in safe mode")
+	}
+	if err == nil {
+		if e.desktop == nil {
+			e.desktop = assistant.NewAssistant(executor.NewDesktopFactory())
+		}
+		var desktopResult *core.Result
+		desktopResult, err = e.desktop.ExecuteAction(ctx, cmd)
+		if err == nil {
+			if desktopResult.Output != "" {
+				result.Output = strings.TrimRight(desktopResult.Output, "\n") + "\n"
+			}
+			if !desktopResult.Success {
+				message := desktopResult.Error
+				if message == "" {
+					message = desktopResult.Output
+				}
+				err = fmt.Errorf("%s", message)
+			}
+		}
+	}
+
+	result.Success = err == nil
+	result.Error = err
+	result.EndTime = time.Now()
+	result.Duration = result.EndTime.Sub(result.StartTime)
+}
@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeKind describes how a step differs between two versions of a plan
+type ChangeKind int
+
+const (
+	// StepUnchanged means the step is the same in both plans
+	StepUnchanged ChangeKind = iota
+	// StepAdded means the step only exists in the new plan
+	StepAdded
+	// StepRemoved means the step only exists in the old plan
+	StepRemoved
+	// StepModified means the step was changed in place
+	StepModified
+)
+
+// StepChange is one entry of a plan diff
+type StepChange struct {
+	Kind ChangeKind
+	// Old is nil for added steps
+	Old *Step
+	// New is nil for removed steps
+	New *Step
+}
+
+// ANSI colors used for the diff
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// DiffSteps compares two step lists. Steps are matched by command; a removed
+// step directly followed by an added one is reported as modified.
+func DiffSteps(oldSteps, newSteps []*Step) []StepChange {
+	// Longest common subsequence of the commands
+	lcs := make([][]int, len(oldSteps)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newSteps)+1)
+	}
+	for i := len(oldSteps) - 1; i >= 0; i-- {
+		for j := len(newSteps) - 1; j >= 0; j-- {
+			if sameCommand(oldSteps[i], newSteps[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []StepChange
+	var removed, added []*Step
+
+	// flush pairs up pending removals and additions as modifications
+	flush := func() {
+		paired := min(len(removed), len(added))
+		for k := 0; k < paired; k++ {
+			changes = append(changes, StepChange{Kind: StepModified, Old: removed[k], New: added[k]})
+		}
+		for _, step := range removed[paired:] {
+			changes = append(changes, StepChange{Kind: StepRemoved, Old: step})
+		}
+		for _, step := range added[paired:] {
+			changes = append(changes, StepChange{Kind: StepAdded, New: step})
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(oldSteps) || j < len(newSteps) {
+		switch {
+		case i < len(oldSteps) && j < len(newSteps) && sameCommand(oldSteps[i], newSteps[j]):
+			flush()
+			kind := StepUnchanged
+			if oldSteps[i].Description != newSteps[j].Description || oldSteps[i].IsCritical != newSteps[j].IsCritical {
+				kind = StepModified
+			}
+			changes = append(changes, StepChange{Kind: kind, Old: oldSteps[i], New: newSteps[j]})
+			i++
+			j++
+		case j < len(newSteps) && (i == len(oldSteps) || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, newSteps[j])
+			j++
+		default:
+			removed = append(removed, oldSteps[i])
+			i++
+		}
+	}
+	flush()
+
+	return changes
+}
+
+// HasChanges reports whether a diff contains anything besides unchanged steps
+func HasChanges(changes []StepChange) bool {
+	for _, change := range changes {
+		if change.Kind != StepUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatStepDiff renders a plan diff, one line per step, optionally colored
+func FormatStepDiff(changes []StepChange, color bool) string {
+	var b strings.Builder
+
+	paint := func(code, line string) string {
+		if !color {
+			return line
+		}
+		return code + line + colorReset
+	}
+
+	for _, change := range changes {
+		switch change.Kind {
+		case StepUnchanged:
+			b.WriteString(fmt.Sprintf("  %s\n", change.New.Command))
+		case StepAdded:
+			b.WriteString(paint(colorGreen, fmt.Sprintf("+ %s%s", change.New.Command, criticalTag(change.New))) + "\n")
+		case StepRemoved:
+			b.WriteString(paint(colorRed, fmt.Sprintf("- %s%s", change.Old.Command, criticalTag(change.Old))) + "\n")
+		case StepModified:
+			if change.Old.Command == change.New.Command {
+				b.WriteString(paint(colorYellow, fmt.Sprintf("~ %s%s", change.New.Command, criticalTag(change.New))) + "\n")
+			} else {
+				b.WriteString(paint(colorRed, fmt.Sprintf("- %s%s", change.Old.Command, criticalTag(change.Old))) + "\n")
+				b.WriteString(paint(colorYellow, fmt.Sprintf("~ %s%s", change.New.Command, criticalTag(change.New))) + "\n")
+			}
+			if change.Old.Description != change.New.Description {
+				b.WriteString(paint(colorYellow, fmt.Sprintf("    %s → %s", change.Old.Description, change.New.Description)) + "\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// sameCommand reports whether two steps run the same command
+func sameCommand(a, b *Step) bool {
+	return strings.TrimSpace(a.Command) == strings.TrimSpace(b.Command)
+}
+
+// criticalTag flags critical steps in a diff line
+func criticalTag(step *Step) string {
+	if step.IsCritical {
+		return " [critical]"
+	}
+	return ""
+}
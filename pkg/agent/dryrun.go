@@ -0,0 +1,651 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+)
+
+// EffectKind describes what a step would do to a path
+type EffectKind int
+
+const (
+	// EffectCreate means the step would create the path
+	EffectCreate EffectKind = iota
+	// EffectModify means the step would change an existing path
+	EffectModify
+	// EffectDelete means the step would remove the path
+	EffectDelete
+	// EffectSystem means the step would change the system outside the working tree
+	EffectSystem
+	// EffectUnknown means the step's effects cannot be predicted
+	EffectUnknown
+)
+
+// Effect is one predicted change of a dry run
+type Effect struct {
+	Kind EffectKind
+	// Path is the affected path, relative to the working directory when inside it
+	Path string
+	// Note explains the change, e.g. "new directory"
+	Note string
+}
+
+// StepEffects are the predicted changes of a single step
+type StepEffects struct {
+	Step    *Step
+	Effects []Effect
+}
+
+// DryRunReport is the result of simulating a plan
+type DryRunReport struct {
+	Steps []StepEffects
+}
+
+// readOnlyTools never change files on their own; only their redirections do
+var readOnlyTools = map[string]bool{
+	"ls": true, "cat": true, "less": true, "more": true, "head": true, "tail": true, "grep": true,
+	"egrep": true, "fgrep": true, "rg": true, "echo": true, "printf": true, "pwd": true, "wc": true,
+	"du": true, "df": true, "stat": true, "file": true, "which": true, "whereis": true, "type": true,
+	"date": true, "uname": true, "whoami": true, "id": true, "ps": true, "free": true, "uptime": true,
+	"env": true, "printenv": true, "uniq": true, "cut": true, "tr": true, "awk": true, "diff": true,
+	"cmp": true, "md5sum": true, "sha256sum": true, "test": true, "[": true, "[[": true, "true": true,
+	"false": true, "sleep": true, "hostname": true, "ping": true, "dig": true, "nslookup": true,
+	"lsblk": true, "lscpu": true, "tree": true, "realpath": true, "basename": true, "dirname": true,
+	"jq": true, "column": true, "seq": true, "export": true, "set": true, "read": true, "history": true,
+}
+
+// systemTools change the system rather than files in the working tree
+var systemTools = map[string]string{
+	"apt": "changes installed packages", "apt-get": "changes installed packages",
+	"dnf": "changes installed packages", "yum": "changes installed packages",
+	"pacman": "changes installed packages", "zypper": "changes installed packages",
+	"brew": "changes installed packages", "snap": "changes installed packages",
+	"flatpak": "changes installed packages", "pip": "changes installed packages",
+	"pip3": "changes installed packages", "npm": "changes installed packages",
+	"yarn": "changes installed packages", "cargo": "changes installed packages",
+	"systemctl": "changes system services", "service": "changes system services",
+	"kill": "stops processes", "pkill": "stops processes", "killall": "stops processes",
+	"reboot": "restarts the machine", "shutdown": "powers off the machine",
+	"crontab": "changes scheduled jobs", "useradd": "changes user accounts",
+	"usermod": "changes user accounts", "userdel": "changes user accounts",
+	"mount": "changes mounted file systems", "umount": "changes mounted file systems",
+}
+
+// redirectOperators are shell redirections that write to the following word
+var redirectOperators = map[string]bool{
+	">": true, ">>": true, "1>": true, "1>>": true, "2>": true, "2>>": true, "&>": true, "&>>": true, ">|": true,
+}
+
+// simulator tracks the state a plan would leave behind without touching the disk
+type simulator struct {
+	root    string
+	cwd     string
+	created map[string]bool
+	dirs    map[string]bool
+	deleted map[string]bool
+}
+
+// SimulatePlan predicts which files and directories each step would touch,
+// starting from workDir. Nothing is executed or written.
+func SimulatePlan(plan *Plan, workDir string) *DryRunReport {
+	sim := newSimulator(workDir)
+	report := &DryRunReport{}
+	for _, step := range plan.Steps {
+		var effects []Effect
+		if assistant.IsAction(step.Command) {
+			// Desktop actions change desktop settings, not files
+			if _, spec, err := assistant.ParseAction(step.Command); err != nil {
+				effects = append(effects, Effect{Kind: EffectUnknown, Note: err.Error()})
+			} else if !spec.ReadOnly {
+				effects = append(effects, Effect{Kind: EffectSystem, Note: "desktop: " + spec.Description})
+			}
+			report.Steps = append(report.Steps, StepEffects{Step: step, Effects: effects})
+			continue
+		}
+		for _, words := range shellCommands(step.Command) {
+			effects = append(effects, sim.simulate(words)...)
+		}
+		report.Steps = append(report.Steps, StepEffects{Step: step, Effects: effects})
+	}
+	return report
+}
+
+// newSimulator starts a simulation in workDir
+func newSimulator(workDir string) *simulator {
+	return &simulator{
+		root:    workDir,
+		cwd:     workDir,
+		created: make(map[string]bool),
+		dirs:    make(map[string]bool),
+		deleted: make(map[string]bool),
+	}
+}
+
+// simulate predicts the effects of one simple command
+func (s *simulator) simulate(words []string) []Effect {
+	tool := filepath.Base(words[0])
+	args, redirects := splitRedirects(words[1:])
+
+	var effects []Effect
+	for _, target := range redirects {
+		effects = append(effects, s.write(target, "output redirected here"))
+	}
+
+	var flags, operands []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			flags = append(flags, arg)
+		} else {
+			operands = append(operands, arg)
+		}
+	}
+
+	switch tool {
+	case "cd", "pushd":
+		if len(operands) > 0 {
+			s.cwd = s.abs(operands[0])
+		}
+	case "mkdir":
+		for _, operand := range operands {
+			if !s.exists(s.abs(operand)) {
+				effects = append(effects, s.mkdir(operand))
+			}
+		}
+	case "touch":
+		for _, operand := range operands {
+			effects = append(effects, s.write(operand, ""))
+		}
+	case "rm", "rmdir", "unlink":
+		for _, operand := range s.expand(operands) {
+			if effect, ok := s.remove(operand); ok {
+				effects = append(effects, effect)
+			}
+		}
+	case "cp", "mv", "ln", "install", "rsync":
+		effects = append(effects, s.copy(tool, flags, operands)...)
+	case "tee":
+		for _, operand := range operands {
+			effects = append(effects, s.write(operand, ""))
+		}
+	case "sed", "perl":
+		if hasFlag(flags, "-i") {
+			files := operands
+			if !hasFlag(flags, "-e") && len(files) > 0 {
+				// The first operand is the script
+				files = files[1:]
+			}
+			for _, file := range s.expand(files) {
+				effects = append(effects, s.modify(file, "edited in place"))
+			}
+		}
+	case "chmod", "chown", "chgrp":
+		note := "permissions change"
+		if tool != "chmod" {
+			note = "ownership changes"
+		}
+		if len(operands) > 1 {
+			for _, file := range s.expand(operands[1:]) {
+				effects = append(effects, s.modify(file, note))
+			}
+		}
+	case "tar":
+		effects = append(effects, s.tar(args)...)
+	case "unzip":
+		dir := "."
+		for i, arg := range args {
+			if arg == "-d" && i+1 < len(args) {
+				dir = args[i+1]
+			}
+		}
+		effects = append(effects, s.modify(dir, "archive extracted here"))
+	case "zip", "gzip", "bzip2", "xz":
+		if tool == "zip" && len(operands) > 0 {
+			effects = append(effects, s.write(operands[0], "archive"))
+		} else {
+			for _, file := range s.expand(operands) {
+				effects = append(effects, s.modify(file, "compressed"))
+			}
+		}
+	case "git":
+		effects = append(effects, s.git(operands)...)
+	case "curl", "wget":
+		effects = append(effects, s.download(tool, args)...)
+	case "find":
+		if hasFlag(flags, "-delete") || hasFlag(flags, "-exec") || hasFlag(flags, "-execdir") {
+			effects = append(effects, Effect{Kind: EffectUnknown, Note: "find may delete or change the files it matches"})
+		}
+	case "sort":
+		for _, file := range sortOutputs(args) {
+			effects = append(effects, s.write(file, "sorted output"))
+		}
+	default:
+		if note, ok := systemTools[tool]; ok {
+			effects = append(effects, Effect{Kind: EffectSystem, Note: fmt.Sprintf("%s %s", tool, note)})
+		} else if !readOnlyTools[tool] && !shellBuiltins[tool] {
+			effects = append(effects, Effect{Kind: EffectUnknown, Note: fmt.Sprintf("cannot predict what '%s' changes", tool)})
+		}
+	}
+
+	return effects
+}
+
+// copy predicts cp, mv, ln, install, and rsync
+func (s *simulator) copy(tool string, flags, operands []string) []Effect {
+	if len(operands) == 0 {
+		return nil
+	}
+	if tool == "ln" && len(operands) == 1 {
+		operands = append(operands, filepath.Base(operands[0]))
+	}
+	if len(operands) < 2 {
+		return nil
+	}
+
+	dest := operands[len(operands)-1]
+	sources := s.expand(operands[:len(operands)-1])
+	intoDir := len(sources) > 1 || strings.HasSuffix(dest, "/") || s.isDir(s.abs(dest))
+
+	note := map[string]string{"cp": "copy", "mv": "moved here", "ln": "link", "install": "installed copy", "rsync": "synced copy"}[tool]
+	var effects []Effect
+	for _, source := range sources {
+		target := dest
+		if intoDir {
+			target = filepath.Join(dest, filepath.Base(source))
+		}
+		if tool == "rsync" && hasFlag(flags, "--delete") {
+			note = "synced copy, extra files deleted"
+		}
+		effects = append(effects, s.write(target, fmt.Sprintf("%s of %s", note, s.display(s.abs(source)))))
+		if tool == "mv" {
+			if effect, ok := s.remove(source); ok {
+				effect.Note = "moved away"
+				effects = append(effects, effect)
+			}
+		}
+	}
+	return effects
+}
+
+// tar predicts archive creation and extraction
+func (s *simulator) tar(args []string) []Effect {
+	mode := ""
+	archive := ""
+	dir := "."
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-C" || arg == "--directory":
+			if i+1 < len(args) {
+				dir = args[i+1]
+				i++
+			}
+		case arg == "-f" || arg == "--file":
+			if i+1 < len(args) {
+				archive = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--file="):
+			archive = strings.TrimPrefix(arg, "--file=")
+		case i == 0 || (strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--")):
+			// Bundled options such as czf or -xzf; f takes the next word
+			letters := strings.TrimPrefix(arg, "-")
+			if strings.ContainsAny(letters, "cx") {
+				mode = string(letters[strings.IndexAny(letters, "cx")])
+			}
+			if strings.HasSuffix(letters, "f") && i+1 < len(args) {
+				archive = args[i+1]
+				i++
+			}
+		}
+	}
+
+	switch {
+	case mode == "c" && archive != "":
+		return []Effect{s.write(archive, "archive")}
+	case mode == "x":
+		return []Effect{s.modify(dir, "archive extracted here")}
+	}
+	return nil
+}
+
+// git predicts the git subcommands that change files
+func (s *simulator) git(operands []string) []Effect {
+	if len(operands) == 0 {
+		return nil
+	}
+	switch operands[0] {
+	case "clone":
+		if len(operands) < 2 {
+			return nil
+		}
+		dest := strings.TrimSuffix(filepath.Base(operands[1]), ".git")
+		if len(operands) > 2 {
+			dest = operands[2]
+		}
+		return []Effect{s.mkdir(dest)}
+	case "init":
+		return []Effect{s.mkdir(".git")}
+	case "status", "log", "diff", "show", "branch", "remote", "fetch", "blame", "grep", "ls-files", "rev-parse", "describe", "tag":
+		return nil
+	default:
+		return []Effect{s.modify(".", fmt.Sprintf("git %s changes the repository", operands[0]))}
+	}
+}
+
+// download predicts where curl and wget save files
+func (s *simulator) download(tool string, args []string) []Effect {
+	var url, output string
+	remoteName := tool == "wget"
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (arg == "-o" && tool == "curl") || (arg == "-O" && tool == "wget") || arg == "--output" || arg == "--output-document":
+			if i+1 < len(args) {
+				output = args[i+1]
+				i++
+			}
+		case arg == "-O" && tool == "curl", arg == "--remote-name":
+			remoteName = true
+		case !strings.HasPrefix(arg, "-"):
+			url = arg
+		}
+	}
+
+	switch {
+	case output == "-":
+		return nil
+	case output != "":
+		return []Effect{s.write(output, "download")}
+	case remoteName && url != "":
+		name := filepath.Base(strings.SplitN(url, "?", 2)[0])
+		if name == "" || name == "." || name == "/" || strings.Contains(name, ":") {
+			name = "index.html"
+		}
+		return []Effect{s.write(name, "download")}
+	}
+	return nil
+}
+
+// write records that a path is written, creating it if it does not exist
+func (s *simulator) write(path, note string) Effect {
+	abs := s.abs(path)
+	kind := EffectModify
+	if !s.exists(abs) {
+		kind = EffectCreate
+		s.created[abs] = true
+		delete(s.deleted, abs)
+	}
+	return Effect{Kind: kind, Path: s.display(abs), Note: note}
+}
+
+// modify records that an existing path changes
+func (s *simulator) modify(path, note string) Effect {
+	abs := s.abs(path)
+	return Effect{Kind: EffectModify, Path: s.display(abs), Note: note}
+}
+
+// mkdir records a new directory
+func (s *simulator) mkdir(path string) Effect {
+	abs := s.abs(path)
+	s.created[abs] = true
+	s.dirs[abs] = true
+	delete(s.deleted, abs)
+	return Effect{Kind: EffectCreate, Path: s.display(abs) + "/", Note: "new directory"}
+}
+
+// remove records that a path is deleted, if it would exist at that point
+func (s *simulator) remove(path string) (Effect, bool) {
+	abs := s.abs(path)
+	if !s.exists(abs) {
+		return Effect{}, false
+	}
+
+	effect := Effect{Kind: EffectDelete, Path: s.display(abs)}
+	if s.isDir(abs) {
+		effect.Path += "/"
+		if entries, err := os.ReadDir(abs); err == nil && len(entries) > 0 {
+			effect.Note = fmt.Sprintf("directory with %d entries", len(entries))
+		}
+	}
+	s.deleted[abs] = true
+	delete(s.created, abs)
+	return effect, true
+}
+
+// exists reports whether a path would exist at this point of the plan
+func (s *simulator) exists(abs string) bool {
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		if s.deleted[dir] {
+			return false
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	if s.created[abs] {
+		return true
+	}
+	_, err := os.Lstat(abs)
+	return err == nil
+}
+
+// isDir reports whether a path would be a directory at this point of the plan
+func (s *simulator) isDir(abs string) bool {
+	if s.dirs[abs] {
+		return s.exists(abs)
+	}
+	info, err := os.Stat(abs)
+	return err == nil && info.IsDir() && s.exists(abs)
+}
+
+// expand resolves glob patterns against the current contents of the disk
+func (s *simulator) expand(paths []string) []string {
+	var expanded []string
+	for _, path := range paths {
+		if !strings.ContainsAny(path, "*?[") {
+			expanded = append(expanded, path)
+			continue
+		}
+		matches, err := filepath.Glob(s.abs(path))
+		if err != nil || len(matches) == 0 {
+			expanded = append(expanded, path)
+			continue
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded
+}
+
+// abs resolves a path against the simulated working directory
+func (s *simulator) abs(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(s.cwd, path)
+}
+
+// display shows a path relative to the starting directory when it is inside it
+func (s *simulator) display(abs string) string {
+	if rel, err := filepath.Rel(s.root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return abs
+}
+
+// splitRedirects separates output redirections from a command's arguments
+// and returns the files they write to
+func splitRedirects(words []string) (args, targets []string) {
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if redirectOperators[word] {
+			if i+1 < len(words) {
+				targets = appendTarget(targets, words[i+1])
+				i++
+			}
+			continue
+		}
+		if op, target, ok := cutRedirect(word); ok {
+			if op != "" {
+				targets = appendTarget(targets, target)
+			}
+			continue
+		}
+		args = append(args, word)
+	}
+	return args, targets
+}
+
+// cutRedirect splits a word such as ">out.txt" or "2>&1" into its operator and
+// target. The operator is empty for redirections that do not write a file.
+func cutRedirect(word string) (string, string, bool) {
+	if word == "2>&1" || word == ">&2" || word == "1>&2" {
+		return "", "", true
+	}
+	for _, op := range []string{"&>>", "&>", "2>>", "1>>", "2>", "1>", ">>", ">"} {
+		if strings.HasPrefix(word, op) && len(word) > len(op) {
+			return op, word[len(op):], true
+		}
+	}
+	return "", "", false
+}
+
+// appendTarget adds a redirect target unless it discards the output
+func appendTarget(targets []string, target string) []string {
+	if target == "/dev/null" || strings.HasPrefix(target, "&") {
+		return targets
+	}
+	return append(targets, target)
+}
+
+// sortOptionsWithValue are sort's short options that take a value, which
+// ends a bundle of short flags
+const sortOptionsWithValue = "kStT"
+
+// sortOutputs returns the files sort writes with -o FILE, -oFILE, bundled
+// flags such as -uo FILE, or --output=FILE
+func sortOutputs(args []string) []string {
+	var files []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return files
+		case strings.HasPrefix(arg, "--o"):
+			// Long options may be abbreviated, and --output is the only one
+			// starting with o
+			name, value, found := strings.Cut(arg, "=")
+			if !strings.HasPrefix("--output", name) {
+				continue
+			}
+			if found {
+				files = append(files, value)
+			} else if i+1 < len(args) {
+				files = append(files, args[i+1])
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--"):
+			for j, r := range arg[1:] {
+				if strings.ContainsRune(sortOptionsWithValue, r) {
+					break
+				}
+				if r == 'o' {
+					if value := arg[j+2:]; value != "" {
+						files = append(files, value)
+					} else if i+1 < len(args) {
+						files = append(files, args[i+1])
+						i++
+					}
+					break
+				}
+			}
+		}
+	}
+	return files
+}
+
+// hasFlag reports whether a flag, or a bundle of short flags containing it, was given
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag || strings.HasPrefix(f, flag+"=") {
+			return true
+		}
+		// -i.bak for sed, or bundled short flags like -rf
+		if len(flag) == 2 && !strings.HasPrefix(f, "--") && strings.HasPrefix(flag, "-") && strings.Contains(f[1:], flag[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Counts returns how many created, modified, deleted, system, and unknown effects the report has
+func (r *DryRunReport) Counts() map[EffectKind]int {
+	counts := make(map[EffectKind]int)
+	for _, step := range r.Steps {
+		for _, effect := range step.Effects {
+			counts[effect.Kind]++
+		}
+	}
+	return counts
+}
+
+// Format renders the report as a diff-style listing, optionally colored
+func (r *DryRunReport) Format(color bool) string {
+	var b strings.Builder
+
+	paint := func(code, line string) string {
+		if !color {
+			return line
+		}
+		return code + line + colorReset
+	}
+
+	b.WriteString("🧪 Dry run: nothing was executed\n")
+	for _, step := range r.Steps {
+		b.WriteString(fmt.Sprintf("\n%d. %s\n", step.Step.ID, step.Step.Command))
+		if len(step.Effects) == 0 {
+			b.WriteString("   (no file changes)\n")
+			continue
+		}
+		for _, effect := range step.Effects {
+			line := effect.Path
+			if effect.Note != "" {
+				if line != "" {
+					line += "  "
+				}
+				line += "(" + effect.Note + ")"
+			}
+			switch effect.Kind {
+			case EffectCreate:
+				b.WriteString(paint(colorGreen, "   + "+line) + "\n")
+			case EffectModify:
+				b.WriteString(paint(colorYellow, "   ~ "+line) + "\n")
+			case EffectDelete:
+				b.WriteString(paint(colorRed, "   - "+line) + "\n")
+			case EffectSystem:
+				b.WriteString(paint(colorRed, "   ! "+line) + "\n")
+			default:
+				b.WriteString("   ? " + line + "\n")
+			}
+		}
+	}
+
+	counts := r.Counts()
+	b.WriteString(fmt.Sprintf("\nSummary: %d created, %d modified, %d deleted", counts[EffectCreate], counts[EffectModify], counts[EffectDelete]))
+	if counts[EffectSystem] > 0 {
+		b.WriteString(fmt.Sprintf(", %d system change(s)", counts[EffectSystem]))
+	}
+	if counts[EffectUnknown] > 0 {
+		b.WriteString(fmt.Sprintf(", %d unpredictable command(s)", counts[EffectUnknown]))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -5,18 +5,27 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/agnath18K/lumo/internal/assistant"
 	"github.com/agnath18K/lumo/pkg/ai"
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/privacy"
 )
 
+// retryDelay is how long to wait before retrying a failed step
+const retryDelay = time.Second
+
 // Executor handles the execution of plans
 type Executor struct {
 	config   *config.Config
 	aiClient ai.Client
+	// desktop runs desktop action steps; it is created on first use
+	desktop *assistant.Assistant
 }
 
 // NewExecutor creates a new executor instance
@@ -35,8 +44,14 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *Plan, feedback *Feedba
 		Success:   true,
 	}
 
-	// Start a single bash session for the entire plan
+	// Start a single bash session for the entire plan. A resumed run starts
+	// where its earlier steps left the session, even if resumed from elsewhere.
 	cmd := exec.CommandContext(ctx, "bash")
+	if plan.Journal != nil {
+		plan.Journal.begin(plan)
+		cmd.Dir = plan.Journal.sessionDir()
+		cmd.Env = plan.Journal.shell.environ(os.Environ())
+	}
 
 	// Create pipes for stdin, stdout, and stderr
 	stdin, err := cmd.StdinPipe()
@@ -63,37 +78,105 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *Plan, feedback *Feedba
 	outputReader := io.MultiReader(stdout, stderr)
 	outputScanner := bufio.NewScanner(outputReader)
 
-	// Execute each step in the plan
-	for _, step := range plan.Steps {
-		// Update the current step
-		feedback.DisplayStepStart(step)
+	// After every few steps, and after a step fails, the output so far is
+	// used to revise the rest of the plan, as many times as configured
+	revisions := 0
+	sinceRevision := 0
 
-		// Execute the step in the inline terminal
-		stepResult, err := e.ExecuteStepInline(ctx, step, stdin, outputScanner)
-		if err != nil {
-			// Try to terminate the bash process
-			cmd.Process.Kill()
-			return nil, fmt.Errorf("failed to execute step %d: %w", step.ID, err)
+	// Execute each step in the plan; revisions replace the steps after the
+	// current one, so the plan is indexed rather than ranged over
+	for i := 0; i < len(plan.Steps); i++ {
+		step := plan.Steps[i]
+
+		// Steps that completed before a resumed run stopped are not repeated
+		if stepCompleted(step) {
+			continue
 		}
+		aborted := false
+
+		for {
+			// Update the current step
+			feedback.DisplayStepStart(step)
+			notify(ctx, executor.AgentEvent{Type: executor.AgentEventStepStart, Step: observedStep(step, nil)})
+			if plan.Journal != nil {
+				plan.Journal.StepStarted(step)
+			}
 
-		// Update the step with the result
-		step.Result = stepResult
-		step.Executed = true
+			// Execute the step in the inline terminal, unless it is refused:
+			// a read-only plan's step could change the system, the deny list
+			// blocks it, or the user does not confirm it
+			var stepResult *StepResult
+			var err error
+			if refusal := e.stepRefusal(ctx, plan, step, feedback); refusal != nil {
+				now := time.Now()
+				stepResult = &StepResult{
+					Error:     refusal,
+					StartTime: now,
+					EndTime:   now,
+					Attempts:  1,
+				}
+			} else {
+				stepResult, err = e.executeStepWithRetries(ctx, step, stdin, outputScanner)
+			}
+			if err != nil {
+				// Try to terminate the bash process
+				cmd.Process.Kill()
+				return nil, fmt.Errorf("failed to execute step %d: %w", step.ID, err)
+			}
 
-		// Display the step result
-		feedback.DisplayStepResult(step)
+			// Update the step with the result
+			step.Result = stepResult
+			step.Executed = true
+			if plan.Journal != nil {
+				plan.Journal.StepFinished(step, e.captureShellState(stdin, outputScanner))
+			}
+
+			// Display the step result
+			feedback.DisplayStepResult(step)
+			notify(ctx, stepFinishEvent(step))
+
+			if stepResult.Success || step.IsCritical {
+				break
+			}
+
+			// Let the user decide what happens after a non-critical failure;
+			// observed runs skip the step, as runs without a terminal do
+			action := StepActionSkip
+			if executor.AgentObserverFrom(ctx) == nil {
+				action = feedback.PromptStepFailure(step)
+			}
+			if action == StepActionRetry || action == StepActionEdit {
+				continue
+			}
+			aborted = action == StepActionAbort
+			break
+		}
 
 		// Check if the step failed
-		if !stepResult.Success {
+		if !step.Result.Success {
 			// If the step is critical, stop execution
 			if step.IsCritical {
 				result.Success = false
-				result.Message = fmt.Sprintf("Critical step %d failed: %v", step.ID, stepResult.Error)
+				result.Message = fmt.Sprintf("Critical step %d failed: %v", step.ID, step.Result.Error)
 				break
 			}
-			// For non-critical steps, mark the overall result as failed but continue execution
 			result.Success = false
-			result.Message = fmt.Sprintf("Step %d failed: %v", step.ID, stepResult.Error)
+			if aborted {
+				result.Message = fmt.Sprintf("Aborted after step %d failed: %v", step.ID, step.Result.Error)
+				break
+			}
+			// For skipped non-critical steps, mark the overall result as failed but continue execution
+			result.Message = fmt.Sprintf("Step %d failed: %v", step.ID, step.Result.Error)
+		}
+
+		sinceRevision++
+		if e.aiClient != nil && revisions+1 < e.config.AgentMaxIterations &&
+			(sinceRevision >= stepsPerIteration || !step.Result.Success || i == len(plan.Steps)-1) {
+			revisions++
+			sinceRevision = 0
+			if e.replan(ctx, plan, i+1, feedback) {
+				break
+			}
 		}
 	}
 
@@ -112,10 +195,41 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *Plan, feedback *Feedba
 	if result.Success {
 		result.Message = "All steps completed successfully"
 	}
+	if plan.Journal != nil {
+		plan.Journal.Finished(result)
+	}
 
 	return result, nil
 }
 
+// executeStepWithRetries runs a step in the inline terminal, retrying it
+// automatically as many times as the step or the configuration allows
+func (e *Executor) executeStepWithRetries(ctx context.Context, step *Step, stdin io.Writer, scanner *bufio.Scanner) (*StepResult, error) {
+	retries := step.Retries
+	if retries <= 0 {
+		retries = e.config.AgentStepRetries
+	}
+	attempts := retries + 1
+
+	for attempt := 1; ; attempt++ {
+		result, err := e.ExecuteStepInline(ctx, step, stdin, scanner)
+		if err != nil {
+			return nil, err
+		}
+		result.Attempts = attempt
+		if result.Success || attempt >= attempts {
+			return result, nil
+		}
+
+		fmt.Printf("🔁 [%d] Failed (%v), retrying (attempt %d of %d)...\n", step.ID, result.Error, attempt+1, attempts)
+		select {
+		case <-ctx.Done():
+			return result, nil
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
 // ExecuteStepInline executes a single step in the inline terminal
 func (e *Executor) ExecuteStepInline(ctx context.Context, step *Step, stdin io.Writer, scanner *bufio.Scanner) (*StepResult, error) {
 	result := &StepResult{
@@ -131,13 +245,39 @@ func (e *Executor) ExecuteStepInline(ctx context.Context, step *Step, stdin io.W
 		return result, nil
 	}
 
+	// Strict privacy mode never lets a step send data off this machine
+	if err := e.privacyViolation(step); err != nil {
+		result.Success = false
+		result.Error = err
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		return result, nil
+	}
+
+	// Commands on the deny list never run, whatever the safety level
+	if err := e.denyListViolation(step); err != nil {
+		result.Success = false
+		result.Error = err
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		return result, nil
+	}
+
+	// Desktop actions go to the desktop environment instead of the shell
+	if assistant.IsAction(step.Command) {
+		e.executeDesktopAction(ctx, step, result)
+		return result, nil
+	}
+
 	// Add a unique marker to identify the end of command output
 	marker := fmt.Sprintf("LUMO_CMD_COMPLETE_%d", time.Now().UnixNano())
 
 	// Send the command followed by an echo of the marker
 	fmt.Fprintf(stdin, "%s\necho $? > /tmp/lumo_exit_code\necho %s\n", step.Command, marker)
 
-	// Collect output until we see the marker
+	// Collect output until we see the marker, passing it on to observers
+	// as it comes
+	observer := executor.AgentObserverFrom(ctx)
 	var outputBuilder strings.Builder
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -146,6 +286,9 @@ func (e *Executor) ExecuteStepInline(ctx context.Context, step *Step, stdin io.W
 		}
 		outputBuilder.WriteString(line)
 		outputBuilder.WriteString("\n")
+		if observer != nil {
+			observer.Event(executor.AgentEvent{Type: executor.AgentEventStepOutput, Step: observedStep(step, nil), Output: line})
+		}
 	}
 
 	// Send command to get the exit code
@@ -196,6 +339,30 @@ func (e *Executor) ExecuteStep(ctx context.Context, step *Step) (*StepResult, er
 		return result, nil
 	}
 
+	// Strict privacy mode never lets a step send data off this machine
+	if err := e.privacyViolation(step); err != nil {
+		result.Success = false
+		result.Error = err
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		return result, nil
+	}
+
+	// Commands on the deny list never run, whatever the safety level
+	if err := e.denyListViolation(step); err != nil {
+		result.Success = false
+		result.Error = err
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		return result, nil
+	}
+
+	// Desktop actions go to the desktop environment instead of the shell
+	if assistant.IsAction(step.Command) {
+		e.executeDesktopAction(ctx, step, result)
+		return result, nil
+	}
+
 	// Create the command using bash to handle pipes, redirects, etc.
 	cmd := exec.CommandContext(ctx, "bash", "-c", step.Command)
 
@@ -222,6 +389,26 @@ func (e *Executor) ExecuteStep(ctx context.Context, step *Step) (*StepResult, er
 	return result, nil
 }
 
+// readOnlyStepViolation returns why a step of a read-only plan may not run,
+// or "" if it may
+func readOnlyStepViolation(plan *Plan, step *Step) string {
+	if !plan.ReadOnly {
+		return ""
+	}
+	return ReadOnlyViolation(step.Command)
+}
+
+// privacyViolation returns why strict privacy mode blocks a step, or nil if it may run
+func (e *Executor) privacyViolation(step *Step) error {
+	if !privacy.IsStrict(e.config.PrivacyMode) {
+		return nil
+	}
+	if reason := privacy.UploadReason(step.Command); reason != "" {
+		return privacy.UploadBlocked(reason)
+	}
+	return nil
+}
+
 // GetAIClient returns the AI client
 func (e *Executor) GetAIClient() ai.Client {
 	return e.aiClient
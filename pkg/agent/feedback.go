@@ -9,66 +9,233 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/agnath18K/lumo/pkg/a11y"
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/system"
 	"github.com/agnath18K/lumo/pkg/utils"
 )
 
 // Feedback handles user interaction and feedback
 type Feedback struct {
-	config *config.Config
-	reader *bufio.Reader
+	config    *config.Config
+	reader    *bufio.Reader
+	validator *PlanValidator
 }
 
 // NewFeedback creates a new feedback instance
 func NewFeedback(cfg *config.Config) *Feedback {
 	return &Feedback{
-		config: cfg,
-		reader: bufio.NewReader(os.Stdin),
+		config:    cfg,
+		reader:    bufio.NewReader(os.Stdin),
+		validator: NewPlanValidator(),
 	}
 }
 
+// heading starts a section, announced in words in accessible mode
+func (f *Feedback) heading(icon, title string) {
+	if f.config.Accessible() {
+		fmt.Println("\n" + a11y.Heading(title))
+		return
+	}
+	fmt.Println("\n" + icon + " " + title)
+	fmt.Println("───────────────────────────────────────────────")
+}
+
+// warning returns the prefix of a warning line
+func (f *Feedback) warning() string {
+	if f.config.Accessible() {
+		return "Warning: "
+	}
+	return "⚠️  "
+}
+
+// status returns the symbol that starts a status line, or the words that
+// replace it in accessible mode
+func (f *Feedback) status(symbol, words string) string {
+	if f.config.Accessible() {
+		return words
+	}
+	return symbol
+}
+
+// criticalMark marks a critical step after its command
+func (f *Feedback) criticalMark(step *Step) string {
+	switch {
+	case !step.IsCritical:
+		return ""
+	case f.config.Accessible():
+		return " (critical)"
+	}
+	return " ⚠️"
+}
+
+// askYesNo asks a yes or no question and reports whether the answer was yes
+func (f *Feedback) askYesNo(question string) (bool, error) {
+	if f.config.Accessible() {
+		fmt.Printf("\n%s Type yes or no: ", question)
+	} else {
+		fmt.Printf("\n%s (y/n): ", question)
+	}
+	response, err := f.reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
+
 // DisplayPlan shows the plan to the user
 func (f *Feedback) DisplayPlan(plan *Plan) {
-	fmt.Println("\n📋 " + plan.Task.Description)
-	fmt.Println("───────────────────────────────────────────────")
+	f.heading("📋", plan.Task.Description)
 
 	if plan.Description != "" {
 		fmt.Printf("➤ %s\n\n", plan.Description)
 	}
 
-	for i, step := range plan.Steps {
-		criticalMark := ""
-		if step.IsCritical {
-			criticalMark = " ⚠️"
-		}
+	// Catch missing tools, files, and flags now rather than halfway through the run
+	warnings := f.validator.Validate(plan)
+	warned := 0
 
+	for i, step := range plan.Steps {
 		// Add a separator between steps except for the first one
 		if i > 0 {
 			fmt.Println()
 		}
 
-		fmt.Printf("%d. %s%s\n", step.ID, step.Command, criticalMark)
+		retryMark := ""
+		if step.Retries > 0 && f.config.Accessible() {
+			retryMark = fmt.Sprintf(" (retried up to %d times)", step.Retries)
+		} else if step.Retries > 0 {
+			retryMark = fmt.Sprintf(" 🔁×%d", step.Retries)
+		}
+
+		fmt.Printf("%d. %s%s%s\n", step.ID, step.Command, f.criticalMark(step), retryMark)
 		fmt.Printf("   %s\n", step.Description)
+		for _, warning := range warnings[i] {
+			fmt.Printf("   %s%s\n", f.warning(), warning)
+		}
+		if len(warnings[i]) > 0 {
+			warned++
+		}
+	}
+
+	if warned > 0 {
+		fmt.Printf("\n%s%d step(s) may fail on this system. Review the warnings above before running the plan.\n", f.warning(), warned)
 	}
 }
 
 // ConfirmExecution asks the user to confirm execution
 func (f *Feedback) ConfirmExecution() (bool, error) {
-	fmt.Println("\n🧐 I'm about to unleash these commands on your system...")
-	fmt.Println("Don't worry, I've checked them twice, but you should too!")
-	fmt.Println("Remember: with great commands comes great responsibility! 🦸")
-	fmt.Print("\nDo you want to execute this plan? (y/n): ")
-	response, err := f.reader.ReadString('\n')
-	if err != nil {
-		return false, fmt.Errorf("failed to read input: %w", err)
+	if !f.config.Accessible() {
+		fmt.Println("\n🧐 I'm about to unleash these commands on your system...")
+		fmt.Println("Don't worry, I've checked them twice, but you should too!")
+		fmt.Println("Remember: with great commands comes great responsibility! 🦸")
 	}
+	return f.askYesNo("Do you want to execute this plan?")
+}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes", nil
+// DisplayRun shows how far a journaled run got before it stopped
+func (f *Feedback) DisplayRun(run *Run) {
+	f.heading("📓", fmt.Sprintf("Run %s: %s", run.ID, run.Task))
+	fmt.Printf("Directory: %s\n", run.WorkDir)
+	if shell := run.Shell; shell != nil {
+		fmt.Printf("Shell:     continues in %s", shell.Dir)
+		if len(shell.Env) > 0 {
+			fmt.Printf(" with %d variable(s) set by earlier steps", len(shell.Env))
+		}
+		fmt.Println()
+		if len(shell.Withheld) > 0 {
+			fmt.Printf("%sNot restored, since they look like secrets: %s\n", f.warning(), strings.Join(shell.Withheld, ", "))
+		}
+	}
+	fmt.Printf("Started:   %s\n", utils.FormatTimestamp(run.StartedAt))
+	fmt.Printf("Stopped:   %s (%s)\n\n", utils.FormatWhen(run.UpdatedAt), run.Status())
+
+	for _, step := range run.Plan.Steps {
+		switch {
+		case stepCompleted(step):
+			fmt.Printf("%s%d. %s\n", f.status("✅ ", "Done: "), step.ID, step.Command)
+		case step == run.Interrupted:
+			fmt.Printf("%s%d. %s\n", f.status("⚠️  ", "Interrupted: "), step.ID, step.Command)
+			fmt.Println("   Was running when the run stopped and may have partly run")
+		case step.Executed:
+			fmt.Printf("%s%d. %s\n", f.status("❌ ", "Failed: "), step.ID, step.Command)
+			fmt.Printf("   Failed: %v\n", step.Result.Error)
+		default:
+			fmt.Printf("%s%d. %s\n", f.status("⏳ ", "To do: "), step.ID, step.Command)
+		}
+	}
+
+	fmt.Println("\nCompleted steps are skipped. Check that the system is still as they left it,")
+	fmt.Println("since anything changed since then is not detected.")
+}
+
+// ConfirmResume asks the user to confirm continuing a run
+func (f *Feedback) ConfirmResume(run *Run) (bool, error) {
+	next := 0
+	for _, step := range run.Plan.Steps {
+		if !stepCompleted(step) {
+			next = step.ID
+			break
+		}
+	}
+	return f.askYesNo(fmt.Sprintf("Resume from step %d?", next))
+}
+
+// DisplayRevision shows how the AI revised the steps that have not run yet
+func (f *Feedback) DisplayRevision(revision *Revision, replaced []*Step) {
+	if revision.Done {
+		fmt.Printf("\n%sThe task looks complete: %s\n", f.status("✅ ", ""), revision.Reason)
+		fmt.Printf("The remaining %d step(s) would be skipped.\n", len(replaced))
+		return
+	}
+
+	f.heading("🔄", "Revised remaining plan")
+	if revision.Reason != "" {
+		fmt.Printf("%s%s\n\n", f.status("➤ ", ""), revision.Reason)
+	}
+	for _, step := range replaced {
+		fmt.Printf("%s%s\n", f.status("- ", "Removed: "), step.Command)
+	}
+	for _, step := range revision.Steps {
+		fmt.Printf("%s%d. %s%s\n", f.status("+ ", "Added: "), step.ID, step.Command, f.criticalMark(step))
+		fmt.Printf("     %s\n", step.Description)
+	}
+}
+
+// ConfirmRevision asks the user to accept a revised plan. Without a terminal
+// to ask on, the original steps are kept.
+func (f *Feedback) ConfirmRevision(revision *Revision) bool {
+	if !utils.IsTerminal(os.Stdin) {
+		return false
+	}
+
+	question := "Continue with the revised steps?"
+	if revision.Done {
+		question = "Stop here?"
+	}
+	confirmed, _ := f.askYesNo(question)
+	return confirmed
+}
+
+// ConfirmStep asks the user to confirm a step the safety level asks about.
+// Without a terminal to ask on, the step is not run.
+func (f *Feedback) ConfirmStep(step *Step, reason string) bool {
+	if !utils.IsTerminal(os.Stdin) {
+		return false
+	}
+
+	confirmed, _ := f.askYesNo(fmt.Sprintf("%sStep %d: %s. Run it?", f.warning(), step.ID, reason))
+	return confirmed
 }
 
 // DisplayStepStart shows that a step is starting
 func (f *Feedback) DisplayStepStart(step *Step) {
+	if f.config.Accessible() {
+		fmt.Printf("\nStep %d: %s\n", step.ID, step.Command)
+		return
+	}
 	fmt.Printf("\n▶️ [%d] %s\n", step.ID, step.Command)
 }
 
@@ -76,10 +243,20 @@ func (f *Feedback) DisplayStepStart(step *Step) {
 func (f *Feedback) DisplayStepResult(step *Step) {
 	result := step.Result
 
-	if result.Success {
-		fmt.Printf("✅ [%d] Completed in %s\n", step.ID, utils.FormatDuration(result.Duration))
-	} else {
-		fmt.Printf("❌ [%d] Failed in %s: %v\n", step.ID, utils.FormatDuration(result.Duration), result.Error)
+	attempts := ""
+	if result.Attempts > 1 {
+		attempts = fmt.Sprintf(" after %d attempts", result.Attempts)
+	}
+
+	switch {
+	case f.config.Accessible() && result.Success:
+		fmt.Printf("Step %d completed in %s%s\n", step.ID, utils.FormatDuration(result.Duration), attempts)
+	case f.config.Accessible():
+		fmt.Printf("Step %d failed in %s%s: %v\n", step.ID, utils.FormatDuration(result.Duration), attempts, result.Error)
+	case result.Success:
+		fmt.Printf("✅ [%d] Completed in %s%s\n", step.ID, utils.FormatDuration(result.Duration), attempts)
+	default:
+		fmt.Printf("❌ [%d] Failed in %s%s: %v\n", step.ID, utils.FormatDuration(result.Duration), attempts, result.Error)
 	}
 
 	// Display output if not empty, but limit it to avoid overwhelming the user
@@ -93,6 +270,11 @@ func (f *Feedback) DisplayStepResult(step *Step) {
 			output = strings.Join(lines[:maxLines], "\n") + "\n... (" + fmt.Sprintf("%d", len(lines)-maxLines) + " more lines)"
 		}
 
+		if f.config.Accessible() {
+			fmt.Printf("Output of step %d:\n%s\nEnd of output\n", step.ID, utils.StripANSI(output))
+			return
+		}
+
 		// Add a subtle border around the output
 		fmt.Println("┌─ Output ─────────────────────────────────")
 		fmt.Printf("│ %s\n", strings.ReplaceAll(output, "\n", "\n│ "))
@@ -100,6 +282,49 @@ func (f *Feedback) DisplayStepResult(step *Step) {
 	}
 }
 
+// PromptStepFailure asks the user what to do after a non-critical step failed.
+// Without a terminal to ask on, the step is skipped as before.
+func (f *Feedback) PromptStepFailure(step *Step) StepAction {
+	if !utils.IsTerminal(os.Stdin) {
+		return StepActionSkip
+	}
+
+	for {
+		if f.config.Accessible() {
+			fmt.Printf("\nStep %d failed. Type retry, skip, abort, or edit: ", step.ID)
+		} else {
+			fmt.Printf("\n⚠️  Step %d failed. [r]etry, [s]kip, [a]bort, or [e]dit the command? ", step.ID)
+		}
+		input, err := f.reader.ReadString('\n')
+		if err != nil {
+			return StepActionSkip
+		}
+
+		switch strings.TrimSpace(strings.ToLower(input)) {
+		case "r", "retry":
+			return StepActionRetry
+		case "s", "skip", "":
+			return StepActionSkip
+		case "a", "abort":
+			return StepActionAbort
+		case "e", "edit":
+			fmt.Printf("Current command: %s\n", step.Command)
+			fmt.Print("Enter new command: ")
+			command, err := f.reader.ReadString('\n')
+			if err != nil {
+				return StepActionSkip
+			}
+			if command = strings.TrimSpace(command); command != "" {
+				step.Command = command
+				return StepActionEdit
+			}
+			fmt.Println("❌ Error: Command required")
+		default:
+			fmt.Println("❌ Error: Please answer retry, skip, abort, or edit")
+		}
+	}
+}
+
 // DisplaySummary shows a summary of the execution
 func (f *Feedback) DisplaySummary(result *ExecutionResult) {
 	// Count successful and failed steps
@@ -116,6 +341,17 @@ func (f *Feedback) DisplaySummary(result *ExecutionResult) {
 		}
 	}
 
+	if f.config.Accessible() {
+		fmt.Println("\n" + a11y.Heading("Summary"))
+		if result.Success {
+			fmt.Printf("Task completed in %s.\n", utils.FormatDuration(result.Duration))
+		} else {
+			fmt.Printf("Task failed in %s: %s\n", utils.FormatDuration(result.Duration), result.Message)
+		}
+		fmt.Printf("%d of %d steps successful.\n", successCount, successCount+failedCount)
+		return
+	}
+
 	fmt.Println("\n╭─────────────────────────────────────────╮")
 	if result.Success {
 		fmt.Printf("│ ✅ Task completed in %s              │\n", utils.FormatDuration(result.Duration))
@@ -143,12 +379,16 @@ func (f *Feedback) InteractiveREPL(ctx context.Context, plan *Plan, executor *Ex
 
 		// Display REPL options in a more compact and beautiful format
 		fmt.Println()
-		fmt.Println("╭─ Commands ──────────────────────────────────╮")
-		fmt.Println("│ run                refine		           │")
-		fmt.Println("│ add <cmd>          edit <num>               │")
-		fmt.Println("│ delete <num>       move <num> <pos>         │")
-		fmt.Println("│ exit               help                     │")
-		fmt.Println("╰─────────────────────────────────────────────╯")
+		if f.config.Accessible() {
+			fmt.Println("Commands: run, refine, add, edit, delete, move, exit, or help.")
+		} else {
+			fmt.Println("╭─ Commands ──────────────────────────────────╮")
+			fmt.Println("│ run                refine		           │")
+			fmt.Println("│ add <cmd>          edit <num>               │")
+			fmt.Println("│ delete <num>       move <num> <pos>         │")
+			fmt.Println("│ exit               help                     │")
+			fmt.Println("╰─────────────────────────────────────────────╯")
+		}
 
 		// Get user input with a simple prompt
 		fmt.Print("\nlumo> ")
@@ -229,6 +469,9 @@ func (f *Feedback) InteractiveREPL(ctx context.Context, plan *Plan, executor *Ex
 				if step.IsCritical {
 					criticalMark = " (critical)"
 				}
+				if step.Retries > 0 {
+					criticalMark += fmt.Sprintf(" (retries: %d)", step.Retries)
+				}
 				planText.WriteString(fmt.Sprintf("%d. %s%s\n", step.ID, step.Command, criticalMark))
 				planText.WriteString(fmt.Sprintf("   %s\n\n", step.Description))
 			}
@@ -240,6 +483,10 @@ Current Plan:
 
 User's modification request: "%s"
 
+The commands will run in bash on this system:
+%s
+Use the package manager and service tools listed above.
+
 Please modify the plan according to the user's request. Your response must be a valid JSON object with the following structure:
 {
   "description": "Overall approach description",
@@ -248,7 +495,8 @@ Please modify the plan according to the user's request. Your response must be a
       "id": 1,
       "command": "exact shell command",
       "description": "what this command does",
-      "isCritical": true/false
+      "isCritical": true/false,
+      "retries": 0
     },
     ...
   ]
@@ -258,7 +506,7 @@ Do not include any text before or after the JSON object. The response must be pa
 Ensure all commands are safe to execute and won't cause data loss or system damage.
 Use relative paths when possible and avoid commands that require sudo.
 Limit the plan to at most %d steps.
-`, planText.String(), modificationRequest, executor.GetConfig().AgentMaxSteps)
+%s`, planText.String(), modificationRequest, system.DetectPlatform().PromptContext(), executor.GetConfig().AgentMaxSteps, desktopActionsPrompt(executor.GetConfig()))
 
 			// Get response from AI
 			response, err := aiClient.GetCompletion(ctx, prompt)
@@ -303,13 +551,8 @@ Limit the plan to at most %d steps.
 			// Parse the JSON
 			jsonStr := response[jsonStart:jsonEnd]
 			var planData struct {
-				Description string `json:"description"`
-				Steps       []struct {
-					ID          int    `json:"id"`
-					Command     string `json:"command"`
-					Description string `json:"description"`
-					IsCritical  bool   `json:"isCritical"`
-				} `json:"steps"`
+				Description string     `json:"description"`
+				Steps       []stepData `json:"steps"`
 			}
 
 			if err := json.Unmarshal([]byte(jsonStr), &planData); err != nil {
@@ -317,21 +560,33 @@ Limit the plan to at most %d steps.
 				continue
 			}
 
-			// Update the plan
-			plan.Description = planData.Description
-
 			// Create new steps
 			newSteps := make([]*Step, 0, len(planData.Steps))
 			for _, stepData := range planData.Steps {
-				newSteps = append(newSteps, &Step{
-					ID:          stepData.ID,
-					Command:     stepData.Command,
-					Description: stepData.Description,
-					IsCritical:  stepData.IsCritical,
-				})
+				newSteps = append(newSteps, stepData.step())
+			}
+
+			// Show what the refinement changes before replacing the plan
+			changes := DiffSteps(plan.Steps, newSteps)
+			if !HasChanges(changes) {
+				fmt.Println("ℹ️  The refined plan has the same steps as the current one.")
+				plan.Description = planData.Description
+				continue
 			}
 
-			// Replace the steps
+			fmt.Printf("\n%sProposed changes:\n", f.status("🔀 ", ""))
+			fmt.Print(FormatStepDiff(changes, utils.IsTerminal(os.Stdout) && !f.config.Accessible()))
+			apply, err := f.askYesNo("Apply these changes?")
+			if err != nil {
+				return nil, err
+			}
+			if !apply {
+				fmt.Println("Plan left unchanged.")
+				continue
+			}
+
+			// Replace the plan
+			plan.Description = planData.Description
 			plan.Steps = newSteps
 
 			fmt.Println("✅ Plan modified successfully!")
@@ -401,6 +656,30 @@ Limit the plan to at most %d steps.
 			// Move the step
 			f.moveStep(plan, srcNum, destNum)
 
+		case "retries":
+			// Parse the arguments
+			retryParts := strings.Fields(args)
+			if len(retryParts) != 2 {
+				fmt.Println("❌ Error: Both step number and retry count required")
+				continue
+			}
+
+			stepNum, err := strconv.Atoi(retryParts[0])
+			if err != nil || stepNum < 1 || stepNum > len(plan.Steps) {
+				fmt.Println("❌ Error: Invalid step number")
+				continue
+			}
+
+			retries, err := strconv.Atoi(retryParts[1])
+			if err != nil || retries < 0 {
+				fmt.Println("❌ Error: Invalid retry count")
+				continue
+			}
+
+			// Set how often the step is retried automatically
+			plan.Steps[stepNum-1].Retries = retries
+			fmt.Println("✅ Step updated successfully")
+
 		case "exit":
 			// Exit without executing
 			return nil, nil
@@ -414,6 +693,7 @@ Limit the plan to at most %d steps.
 			fmt.Println("  edit <num>           - Edit a step in the plan")
 			fmt.Println("  delete <num>         - Delete a step from the plan")
 			fmt.Println("  move <num> <pos>     - Move a step to a new position")
+			fmt.Println("  retries <num> <n>    - Retry a step up to n times if it fails")
 			fmt.Println("  exit                 - Exit without executing")
 			fmt.Println("  help                 - Show this help message")
 			continue
@@ -0,0 +1,497 @@
+package agent
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/replay"
+)
+
+// maxJournals is how many run journals are kept before the oldest are deleted
+const maxJournals = 50
+
+// Journal events
+const (
+	eventRunStarted   = "run-started"
+	eventRunResumed   = "run-resumed"
+	eventPlanRevised  = "plan-revised"
+	eventStepStarted  = "step-started"
+	eventStepFinished = "step-finished"
+	eventRunFinished  = "run-finished"
+)
+
+// runIDPattern matches the IDs given to agent runs
+var runIDPattern = regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`)
+
+// Journal records the progress of an agent run as it executes, one JSON
+// entry per line, so a run that crashed or was interrupted can be resumed
+// from the last step that completed
+type Journal struct {
+	id      string
+	path    string
+	workDir string
+	started bool // Whether the plan has been recorded
+	// shell is the state a resumed run's bash session starts from
+	shell *ShellState
+
+	mutex  sync.Mutex
+	failed bool
+}
+
+// journalEntry is one line of a journal
+type journalEntry struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+
+	// Set when the run starts; steps are also set when the plan is revised
+	Task        string        `json:"task,omitempty"`
+	Description string        `json:"description,omitempty"`
+	WorkDir     string        `json:"work_dir,omitempty"`
+	Steps       []journalStep `json:"steps,omitempty"`
+
+	// Set for step events; the command is recorded because it may have
+	// been edited since the plan was made
+	Step         int           `json:"step,omitempty"`
+	Command      string        `json:"command,omitempty"`
+	Success      bool          `json:"success,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	OutputSHA256 string        `json:"output_sha256,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	Attempts     int           `json:"attempts,omitempty"`
+	// Shell is the session's state after the step, set when it could be read
+	Shell *ShellState `json:"shell,omitempty"`
+}
+
+// journalStep is a step of the plan as it was when the run started
+type journalStep struct {
+	ID          int    `json:"id"`
+	Command     string `json:"command"`
+	Description string `json:"description,omitempty"`
+	Critical    bool   `json:"critical,omitempty"`
+	Retries     int    `json:"retries,omitempty"`
+}
+
+// Run is an agent run reconstructed from its journal
+type Run struct {
+	ID        string
+	Task      string
+	WorkDir   string
+	StartedAt time.Time
+	UpdatedAt time.Time
+	// Plan holds the run's steps; those that completed are marked executed
+	// with a successful result
+	Plan *Plan
+	// Interrupted is the step that was running when the run stopped, which
+	// may have partly run
+	Interrupted *Step
+	// Shell is the state of the bash session after the last step that ran
+	Shell *ShellState
+	// Finished is set if the run ended on its own, whether or not it succeeded
+	Finished bool
+	Success  bool
+
+	// validSize is the length of the journal up to its last complete entry
+	validSize int64
+}
+
+// journalDir returns the directory that holds the run journals
+func journalDir() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "agent_runs"), nil
+}
+
+// newRunID returns a unique, sortable ID for a run
+func newRunID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// IsRunID reports whether s looks like the ID of an agent run
+func IsRunID(s string) bool {
+	return runIDPattern.MatchString(s)
+}
+
+// NewJournal creates the journal for a new run in the current directory.
+// Nothing is written until the plan starts executing, since it may still
+// be edited.
+func NewJournal() (*Journal, error) {
+	dir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	pruneJournals(dir)
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	id := newRunID()
+	return &Journal{
+		id:      id,
+		path:    filepath.Join(dir, id+".jsonl"),
+		workDir: workDir,
+	}, nil
+}
+
+// ID returns the run's ID, which resumes it with agent:resume
+func (j *Journal) ID() string {
+	return j.id
+}
+
+// begin records the plan as execution starts, or that the run was resumed
+func (j *Journal) begin(plan *Plan) {
+	if j.started {
+		j.record(journalEntry{Event: eventRunResumed})
+		return
+	}
+	j.started = true
+
+	entry := journalEntry{
+		Event:       eventRunStarted,
+		Description: plan.Description,
+		WorkDir:     j.workDir,
+	}
+	if plan.Task != nil {
+		entry.Task = plan.Task.Description
+	}
+	entry.Steps = journalSteps(plan)
+	j.record(entry)
+}
+
+// PlanRevised records the plan's steps after the steps that had not run yet
+// were revised
+func (j *Journal) PlanRevised(plan *Plan) {
+	j.record(journalEntry{Event: eventPlanRevised, Description: plan.Description, Steps: journalSteps(plan)})
+}
+
+// journalSteps converts the steps of a plan for recording
+func journalSteps(plan *Plan) []journalStep {
+	steps := make([]journalStep, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		steps = append(steps, journalStep{
+			ID:          step.ID,
+			Command:     step.Command,
+			Description: step.Description,
+			Critical:    step.IsCritical,
+			Retries:     step.Retries,
+		})
+	}
+	return steps
+}
+
+// sessionDir returns the directory the plan's bash session starts in: where
+// the resumed run's last step left it, if it still exists, or else where the
+// run started
+func (j *Journal) sessionDir() string {
+	if j.shell != nil && j.shell.Dir != "" {
+		if info, err := os.Stat(j.shell.Dir); err == nil && info.IsDir() {
+			return j.shell.Dir
+		}
+	}
+	return j.workDir
+}
+
+// StepStarted records that a step is about to run
+func (j *Journal) StepStarted(step *Step) {
+	j.record(journalEntry{Event: eventStepStarted, Step: step.ID, Command: step.Command})
+}
+
+// StepFinished records the result of a step and the state of the shell
+// session after it. Only a checksum of the output is kept, since it may hold
+// secrets.
+func (j *Journal) StepFinished(step *Step, shell *ShellState) {
+	entry := journalEntry{Event: eventStepFinished, Step: step.ID, Command: step.Command, Shell: shell}
+	if result := step.Result; result != nil {
+		entry.Success = result.Success
+		entry.OutputSHA256 = replay.OutputChecksum(result.Output)
+		entry.Duration = result.Duration
+		entry.Attempts = result.Attempts
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+	}
+	j.record(entry)
+}
+
+// Finished records that the run ended
+func (j *Journal) Finished(result *ExecutionResult) {
+	j.record(journalEntry{Event: eventRunFinished, Success: result.Success, Error: result.Message})
+}
+
+// record appends an entry, warning once if the journal cannot be written so
+// a full disk does not stop the run
+func (j *Journal) record(entry journalEntry) {
+	if err := j.append(entry); err != nil {
+		j.mutex.Lock()
+		defer j.mutex.Unlock()
+		if !j.failed {
+			j.failed = true
+			fmt.Fprintf(os.Stderr, "Warning: failed to write run journal; this run cannot be resumed: %v\n", err)
+		}
+	}
+}
+
+// append writes an entry and syncs it to disk, so it survives a crash
+func (j *Journal) append(entry journalEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	// The journal holds commands and paths, so keep it private
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open run journal: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write run journal: %w", err)
+	}
+	return file.Sync()
+}
+
+// LoadRun reconstructs a run from its journal
+func LoadRun(id string) (*Run, error) {
+	if !IsRunID(id) {
+		return nil, fmt.Errorf("invalid run ID: %s", id)
+	}
+	dir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	run, err := readJournal(filepath.Join(dir, id+".jsonl"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no agent run with ID %s", id)
+	}
+	return run, err
+}
+
+// ListRuns returns the journaled runs, most recent first
+func ListRuns() ([]*Run, error) {
+	dir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*Run
+	for _, path := range paths {
+		run, err := readJournal(path)
+		if err != nil {
+			// A journal cut short mid-line is still readable; anything
+			// else is not a journal
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, k int) bool {
+		return runs[i].StartedAt.After(runs[k].StartedAt)
+	})
+	return runs, nil
+}
+
+// readJournal replays the entries of a journal file
+func readJournal(path string) (*Run, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	run := &Run{ID: strings.TrimSuffix(filepath.Base(path), ".jsonl")}
+	steps := make(map[int]*Step)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// The last line may be cut short if the process died while
+			// writing it; everything before it still counts
+			break
+		}
+		run.validSize += int64(len(scanner.Bytes())) + 1
+		run.UpdatedAt = entry.Time
+
+		switch entry.Event {
+		case eventRunStarted:
+			run.Task = entry.Task
+			run.WorkDir = entry.WorkDir
+			run.StartedAt = entry.Time
+			run.Plan = &Plan{
+				Task:        &Task{Description: entry.Task, CreatedAt: entry.Time},
+				Description: entry.Description,
+				CreatedAt:   entry.Time,
+			}
+			for _, recorded := range entry.Steps {
+				step := &Step{
+					ID:          recorded.ID,
+					Command:     recorded.Command,
+					Description: recorded.Description,
+					IsCritical:  recorded.Critical,
+					Retries:     recorded.Retries,
+				}
+				steps[step.ID] = step
+				run.Plan.Steps = append(run.Plan.Steps, step)
+			}
+		case eventPlanRevised:
+			if run.Plan == nil {
+				continue
+			}
+			// Steps that already ran keep their results; the rest are
+			// replaced
+			revised := make(map[int]*Step)
+			run.Plan.Steps = nil
+			for _, recorded := range entry.Steps {
+				step, ok := steps[recorded.ID]
+				if !ok || !step.Executed {
+					step = &Step{
+						ID:          recorded.ID,
+						Command:     recorded.Command,
+						Description: recorded.Description,
+						IsCritical:  recorded.Critical,
+						Retries:     recorded.Retries,
+					}
+				}
+				revised[step.ID] = step
+				run.Plan.Steps = append(run.Plan.Steps, step)
+			}
+			steps = revised
+		case eventRunResumed:
+			run.Finished = false
+			run.Success = false
+		case eventStepStarted:
+			if step, ok := steps[entry.Step]; ok {
+				step.Command = entry.Command
+				run.Interrupted = step
+			}
+		case eventStepFinished:
+			step, ok := steps[entry.Step]
+			if !ok {
+				continue
+			}
+			step.Command = entry.Command
+			step.Executed = true
+			if entry.Shell != nil {
+				run.Shell = entry.Shell
+			}
+			step.Result = &StepResult{
+				Success:  entry.Success,
+				Duration: entry.Duration,
+				Attempts: entry.Attempts,
+			}
+			if entry.Error != "" {
+				step.Result.Error = fmt.Errorf("%s", entry.Error)
+			}
+			if run.Interrupted == step {
+				run.Interrupted = nil
+			}
+		case eventRunFinished:
+			run.Finished = true
+			run.Success = entry.Success
+			run.Interrupted = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run journal: %w", err)
+	}
+	if run.Plan == nil {
+		return nil, fmt.Errorf("run journal %s has no plan", run.ID)
+	}
+	return run, nil
+}
+
+// Completed returns how many of the run's steps completed successfully
+func (r *Run) Completed() int {
+	completed := 0
+	for _, step := range r.Plan.Steps {
+		if stepCompleted(step) {
+			completed++
+		}
+	}
+	return completed
+}
+
+// Status describes where the run stopped
+func (r *Run) Status() string {
+	switch {
+	case r.Finished && r.Success:
+		return "completed"
+	case r.Finished:
+		return "failed"
+	case r.Interrupted != nil:
+		return fmt.Sprintf("interrupted at step %d", r.Interrupted.ID)
+	default:
+		return "interrupted"
+	}
+}
+
+// ResumePlan returns the run's plan, set to continue the run's journal when
+// it executes. Any entry left half written when the run stopped is dropped.
+func (r *Run) ResumePlan() (*Plan, error) {
+	dir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, r.ID+".jsonl")
+	if info, err := os.Stat(path); err == nil && info.Size() > r.validSize {
+		if err := os.Truncate(path, r.validSize); err != nil {
+			return nil, fmt.Errorf("failed to repair run journal: %w", err)
+		}
+	}
+	r.Plan.Journal = &Journal{
+		id:      r.ID,
+		path:    path,
+		workDir: r.WorkDir,
+		started: true,
+		shell:   r.Shell,
+	}
+	return r.Plan, nil
+}
+
+// stepCompleted reports whether a step already ran successfully, so
+// resuming a run does not repeat it
+func stepCompleted(step *Step) bool {
+	return step.Executed && step.Result != nil && step.Result.Success
+}
+
+// pruneJournals deletes the oldest journals beyond maxJournals
+func pruneJournals(dir string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil || len(paths) < maxJournals {
+		return
+	}
+	// Run IDs start with the time, so they sort oldest first
+	sort.Strings(paths)
+	for _, path := range paths[:len(paths)-maxJournals+1] {
+		os.Remove(path)
+	}
+}
@@ -26,6 +26,11 @@ type Task struct {
 	Description string
 	// CreatedAt is the time when the task was created
 	CreatedAt time.Time
+	// ReadOnly asks for a plan that only inspects the system
+	ReadOnly bool
+	// Context is background for planning, such as what a chat conversation
+	// decided before handing the task over
+	Context string
 }
 
 // Plan represents a sequence of steps to accomplish a task
@@ -38,6 +43,10 @@ type Plan struct {
 	CreatedAt time.Time
 	// Description is a brief description of the overall approach
 	Description string
+	// Journal records the execution so it can be resumed; nil disables it
+	Journal *Journal
+	// ReadOnly refuses to run any step that could change the system
+	ReadOnly bool
 }
 
 // Step represents a single command to be executed
@@ -50,6 +59,8 @@ type Step struct {
 	Description string
 	// IsCritical indicates whether the step is critical for the task
 	IsCritical bool
+	// Retries is how many times the step is retried automatically if it fails
+	Retries int
 	// Executed indicates whether the step has been executed
 	Executed bool
 	// Result is the result of executing the step
@@ -70,8 +81,24 @@ type StepResult struct {
 	EndTime time.Time
 	// Duration is how long the step took to execute
 	Duration time.Duration
+	// Attempts is how many times the step was run
+	Attempts int
 }
 
+// StepAction is what to do after a non-critical step fails
+type StepAction string
+
+const (
+	// StepActionRetry runs the step again
+	StepActionRetry StepAction = "retry"
+	// StepActionSkip continues with the next step
+	StepActionSkip StepAction = "skip"
+	// StepActionAbort stops executing the plan
+	StepActionAbort StepAction = "abort"
+	// StepActionEdit runs the step again with an edited command
+	StepActionEdit StepAction = "edit"
+)
+
 // ExecutionResult represents the overall result of executing a plan
 type ExecutionResult struct {
 	// Success indicates whether the execution was successful
@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/executor"
+)
+
+// notify reports an event to the observer following the run of ctx, if any
+func notify(ctx context.Context, event executor.AgentEvent) {
+	if observer := executor.AgentObserverFrom(ctx); observer != nil {
+		observer.Event(event)
+	}
+}
+
+// observedStep converts a step for observers
+func observedStep(step *Step, warnings []string) *executor.AgentStep {
+	return &executor.AgentStep{
+		ID:          step.ID,
+		Command:     step.Command,
+		Description: step.Description,
+		Critical:    step.IsCritical,
+		Warnings:    warnings,
+	}
+}
+
+// planEvent reports a plan to observers, with the warnings of the checks
+// the terminal shows along with it
+func planEvent(plan *Plan) executor.AgentEvent {
+	warnings := NewPlanValidator().Validate(plan)
+	event := executor.AgentEvent{
+		Type:        executor.AgentEventPlan,
+		Description: plan.Description,
+	}
+	if plan.Task != nil {
+		event.Task = plan.Task.Description
+	}
+	for i, step := range plan.Steps {
+		event.Steps = append(event.Steps, *observedStep(step, warnings[i]))
+	}
+	return event
+}
+
+// stepFinishEvent reports the result of a step to observers
+func stepFinishEvent(step *Step) executor.AgentEvent {
+	result := step.Result
+	event := executor.AgentEvent{
+		Type:       executor.AgentEventStepFinish,
+		Step:       observedStep(step, nil),
+		Output:     result.Output,
+		Success:    result.Success,
+		Attempts:   result.Attempts,
+		DurationMS: result.Duration.Milliseconds(),
+	}
+	if result.Error != nil {
+		event.Error = result.Error.Error()
+	}
+	return event
+}
+
+// revisionEvent reports a revision of the remaining steps to observers,
+// with the steps it replaces as the description
+func revisionEvent(revision *Revision, replaced []*Step) executor.AgentEvent {
+	event := executor.AgentEvent{
+		Type:   executor.AgentEventRevision,
+		Reason: revision.Reason,
+	}
+	removed := make([]string, 0, len(replaced))
+	for _, step := range replaced {
+		removed = append(removed, step.Command)
+	}
+	if len(removed) > 0 {
+		event.Description = "Replaces: " + strings.Join(removed, "; ")
+	}
+	for _, step := range revision.Steps {
+		event.Steps = append(event.Steps, *observedStep(step, nil))
+	}
+	return event
+}
+
+// confirmStep asks whether to run a step the safety level asks about: the
+// observer following the run if there is one, or else the terminal
+func confirmStep(ctx context.Context, feedback *Feedback, step *Step, reason string) bool {
+	if observer := executor.AgentObserverFrom(ctx); observer != nil {
+		return observer.Confirm(ctx, executor.AgentEvent{
+			Type:    executor.AgentEventConfirm,
+			Subject: "step",
+			Step:    observedStep(step, nil),
+			Reason:  reason,
+		})
+	}
+	return feedback.ConfirmStep(step, reason)
+}
+
+// confirmRevision asks whether to accept a revised plan: the observer
+// following the run if there is one, or else the terminal
+func confirmRevision(ctx context.Context, feedback *Feedback, revision *Revision) bool {
+	if observer := executor.AgentObserverFrom(ctx); observer != nil {
+		reason := revision.Reason
+		if revision.Done {
+			reason = "the task looks complete, so the remaining steps would be skipped: " + reason
+		}
+		return observer.Confirm(ctx, executor.AgentEvent{
+			Type:    executor.AgentEventConfirm,
+			Subject: "revision",
+			Reason:  reason,
+		})
+	}
+	return feedback.ConfirmRevision(revision)
+}
@@ -8,8 +8,20 @@ import (
 
 	"github.com/agnath18K/lumo/pkg/ai"
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/system"
 )
 
+// readOnlyPolicy is added to the planning prompt of read-only tasks
+const readOnlyPolicy = `
+This is a read-only investigation. Every command must only inspect the system:
+do not create, modify, move, or delete files, do not redirect output to files,
+do not install or remove packages, and do not start, stop, or restart services
+or processes. Use tools such as du, df, find, ls, stat, ps, journalctl, dmesg,
+systemctl status, and git status, and keep each command's output short with
+head, sort, or grep. Commands that could change anything will be refused.
+Mark every step as not critical, so one failing check does not stop the others.
+`
+
 // Planner handles the generation of execution plans
 type Planner struct {
 	config   *config.Config
@@ -26,12 +38,29 @@ func NewPlanner(cfg *config.Config, aiClient ai.Client) *Planner {
 
 // CreatePlan generates a plan for the given task
 func (p *Planner) CreatePlan(ctx context.Context, task *Task) (*Plan, error) {
+	// Read-only tasks investigate the system without changing it
+	policy := ""
+	if task.ReadOnly {
+		policy = readOnlyPolicy
+	}
+
+	// Background, such as a chat that discussed the approach, follows the task
+	background := ""
+	if task.Context != "" {
+		background = fmt.Sprintf("\nBackground from the conversation that led to this task:\n%s\n", task.Context)
+	}
+
 	// Create the prompt for the AI
 	prompt := fmt.Sprintf(`
 You are Lumo, an AI-powered command-line assistant.
 Create a step-by-step plan to accomplish the following task using shell commands:
 
 Task: %s
+%s
+The commands will run in bash on this system:
+%s
+Use the package manager and service tools listed above when installing software or
+managing services, instead of the ones of another distribution.
 
 Provide a detailed plan with the following structure:
 1. A brief description of the overall approach
@@ -49,7 +78,8 @@ IMPORTANT: Your response MUST be a valid JSON object with the following structur
       "id": 1,
       "command": "exact shell command",
       "description": "what this command does",
-      "isCritical": true/false
+      "isCritical": true/false,
+      "retries": 0
     },
     ...
   ]
@@ -60,8 +90,9 @@ Do not include markdown formatting, code blocks, or any other non-JSON content.
 
 Ensure all commands are safe to execute and won't cause data loss or system damage.
 Use relative paths when possible and avoid commands that require sudo.
+Set "retries" above 0 only for steps that may fail transiently, such as network downloads.
 Limit the plan to at most %d steps.
-`, task.Description, p.config.AgentMaxSteps)
+%s%s`, task.Description, background, system.DetectPlatform().PromptContext(), p.config.AgentMaxSteps, desktopActionsPrompt(p.config), policy)
 
 	// Get response from AI
 	response, err := p.aiClient.GetCompletion(ctx, prompt)
@@ -70,6 +101,40 @@ Limit the plan to at most %d steps.
 	}
 
 	// Extract JSON from the response
+	jsonData, err := extractJSONObject(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response
+	var planData struct {
+		Description string     `json:"description"`
+		Steps       []stepData `json:"steps"`
+	}
+
+	if err := json.Unmarshal([]byte(jsonData), &planData); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	// Create the plan
+	plan := &Plan{
+		Task:        task,
+		Description: planData.Description,
+		CreatedAt:   time.Now(),
+		Steps:       make([]*Step, len(planData.Steps)),
+	}
+
+	// Add steps to the plan
+	for i, stepData := range planData.Steps {
+		plan.Steps[i] = stepData.step()
+	}
+
+	return plan, nil
+}
+
+// extractJSONObject returns the first complete JSON object in an AI response,
+// which may surround it with other text
+func extractJSONObject(response string) (string, error) {
 	jsonStart := -1
 	jsonEnd := -1
 
@@ -97,47 +162,8 @@ Limit the plan to at most %d steps.
 		}
 	}
 
-	// Extract the JSON part
-	var jsonData string
-	if jsonStart >= 0 && jsonEnd > jsonStart {
-		jsonData = response[jsonStart:jsonEnd]
-	} else {
-		return nil, fmt.Errorf("failed to extract JSON from AI response")
-	}
-
-	// Parse the JSON response
-	var planData struct {
-		Description string `json:"description"`
-		Steps       []struct {
-			ID          int    `json:"id"`
-			Command     string `json:"command"`
-			Description string `json:"description"`
-			IsCritical  bool   `json:"isCritical"`
-		} `json:"steps"`
-	}
-
-	if err := json.Unmarshal([]byte(jsonData), &planData); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	if jsonStart < 0 || jsonEnd <= jsonStart {
+		return "", fmt.Errorf("failed to extract JSON from AI response")
 	}
-
-	// Create the plan
-	plan := &Plan{
-		Task:        task,
-		Description: planData.Description,
-		CreatedAt:   time.Now(),
-		Steps:       make([]*Step, len(planData.Steps)),
-	}
-
-	// Add steps to the plan
-	for i, stepData := range planData.Steps {
-		plan.Steps[i] = &Step{
-			ID:          stepData.ID,
-			Command:     stepData.Command,
-			Description: stepData.Description,
-			IsCritical:  stepData.IsCritical,
-			Executed:    false,
-		}
-	}
-
-	return plan, nil
+	return response[jsonStart:jsonEnd], nil
 }
@@ -0,0 +1,215 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/system"
+)
+
+// stepsPerIteration is how many steps run before their output is shown to
+// the AI to revise the rest of the plan
+const stepsPerIteration = 3
+
+// maxObservedOutput is how much of each step's output is sent to the AI when
+// revising a plan; the end of the output is kept, since that is where
+// errors and summaries usually are
+const maxObservedOutput = 2000
+
+// Revision is the AI's update to the steps of a plan that have not run yet
+type Revision struct {
+	// Done is set when the steps that ran already accomplished the task
+	Done bool
+	// Reason explains why the steps were changed, or why the task is done
+	Reason string
+	// Steps replace the remaining steps of the plan
+	Steps []*Step
+}
+
+// RevisePlan shows the AI what the steps before next did and asks it to
+// revise the steps from next on, which may add steps after the last one
+func (p *Planner) RevisePlan(ctx context.Context, plan *Plan, next int) (*Revision, error) {
+	var executed strings.Builder
+	for _, step := range plan.Steps[:next] {
+		if !step.Executed || step.Result == nil {
+			continue
+		}
+		status := "succeeded"
+		if !step.Result.Success {
+			status = fmt.Sprintf("failed: %v", step.Result.Error)
+		}
+		output := step.Result.Output
+		if len(output) > maxObservedOutput {
+			output = "...\n" + output[len(output)-maxObservedOutput:]
+		}
+		executed.WriteString(fmt.Sprintf("%d. $ %s (%s)\n%s\n", step.ID, step.Command, status, output))
+	}
+
+	remaining := "(none)\n"
+	if next < len(plan.Steps) {
+		var b strings.Builder
+		for _, step := range plan.Steps[next:] {
+			b.WriteString(fmt.Sprintf("%d. %s  # %s\n", step.ID, step.Command, step.Description))
+		}
+		remaining = b.String()
+	}
+
+	policy := ""
+	if plan.ReadOnly {
+		policy = readOnlyPolicy
+	}
+	background := ""
+	if plan.Task.Context != "" {
+		background = fmt.Sprintf("Background: %s\n", plan.Task.Context)
+	}
+
+	prompt := fmt.Sprintf(`
+You are Lumo, an AI-powered command-line assistant.
+You are part way through a plan of shell commands for the following task:
+
+Task: %s
+Approach: %s
+%s
+The commands run in bash on this system:
+%s
+These steps have run, with their output:
+
+%s
+These steps have not run yet:
+
+%s
+Based on what the output shows, revise the steps that have not run yet.
+Keep them unchanged if they still fit, fix steps that would fail for the same
+reason an earlier one did, drop steps that are no longer needed, and add steps
+the output shows are missing. Never repeat a step that succeeded.
+If the steps that ran already accomplished the task, set "done" to true.
+
+IMPORTANT: Your response MUST be a valid JSON object with the following structure:
+{
+  "done": true/false,
+  "reason": "what the output showed and how the steps changed",
+  "steps": [
+    {
+      "command": "exact shell command",
+      "description": "what this command does",
+      "isCritical": true/false,
+      "retries": 0
+    },
+    ...
+  ]
+}
+
+Do not include any text before or after the JSON object.
+Ensure all commands are safe to execute and won't cause data loss or system damage.
+Limit the remaining steps to at most %d.
+%s%s`, plan.Task.Description, plan.Description, background, system.DetectPlatform().PromptContext(),
+		executed.String(), remaining, max(p.config.AgentMaxSteps-next, 1), desktopActionsPrompt(p.config), policy)
+
+	response, err := p.aiClient.GetCompletion(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI completion: %w", err)
+	}
+
+	jsonData, err := extractJSONObject(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisionData struct {
+		Done   bool       `json:"done"`
+		Reason string     `json:"reason"`
+		Steps  []stepData `json:"steps"`
+	}
+	if err := json.Unmarshal([]byte(jsonData), &revisionData); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	revision := &Revision{
+		Done:   revisionData.Done,
+		Reason: revisionData.Reason,
+	}
+	if revision.Done {
+		return revision, nil
+	}
+	for _, stepData := range revisionData.Steps {
+		if step := stepData.step(); strings.TrimSpace(step.Command) != "" {
+			revision.Steps = append(revision.Steps, step)
+		}
+	}
+	return revision, nil
+}
+
+// Unchanged reports whether the revision keeps the given steps as they are
+func (r *Revision) Unchanged(steps []*Step) bool {
+	if r.Done || len(r.Steps) != len(steps) {
+		return false
+	}
+	for i, step := range steps {
+		if strings.TrimSpace(r.Steps[i].Command) != strings.TrimSpace(step.Command) {
+			return false
+		}
+	}
+	return true
+}
+
+// replan revises the steps of the plan from next on, after the user agrees
+// to the change. It reports whether the task is done and the run should stop.
+func (e *Executor) replan(ctx context.Context, plan *Plan, next int, feedback *Feedback) bool {
+	revision, err := NewPlanner(e.config, e.aiClient).RevisePlan(ctx, plan, next)
+	if err != nil {
+		fmt.Printf("\n⚠️  Could not revise the plan, continuing as planned: %v\n", err)
+		return false
+	}
+
+	remaining := plan.Steps[next:]
+	if revision.Unchanged(remaining) {
+		return false
+	}
+	if revision.Done && len(remaining) == 0 {
+		// Nothing would be skipped, so there is nothing to ask about
+		fmt.Printf("\n✅ Task complete: %s\n", revision.Reason)
+		return true
+	}
+
+	// A read-only plan keeps its guarantee through revisions
+	if plan.ReadOnly {
+		kept := revision.Steps[:0]
+		for _, step := range revision.Steps {
+			if reason := ReadOnlyViolation(step.Command); reason != "" {
+				fmt.Printf("⚠️  Dropped revised step %q: %s\n", step.Command, reason)
+				continue
+			}
+			kept = append(kept, step)
+		}
+		revision.Steps = kept
+	}
+
+	id := 0
+	if next > 0 {
+		id = plan.Steps[next-1].ID
+	}
+	for _, step := range revision.Steps {
+		id++
+		step.ID = id
+	}
+
+	feedback.DisplayRevision(revision, remaining)
+	notify(ctx, revisionEvent(revision, remaining))
+	confirm := e.config.AgentConfirmBeforeExecution || executor.AgentObserverFrom(ctx) != nil
+	if confirm && !confirmRevision(ctx, feedback, revision) {
+		fmt.Println("Keeping the original steps.")
+		return false
+	}
+	if revision.Done {
+		return true
+	}
+
+	plan.Steps = append(plan.Steps[:next:next], revision.Steps...)
+	if plan.Journal != nil {
+		plan.Journal.PlanRevised(plan)
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/replay"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// maxListedRuns is how many runs agent:resume lists without a run ID
+const maxListedRuns = 10
+
+// cutResume recognizes "resume" and "resume <run-id>". A task that merely
+// starts with the word, such as "resume the download", is not a resume.
+func cutResume(taskDescription string) (string, bool) {
+	fields := strings.Fields(taskDescription)
+	if len(fields) == 0 || fields[0] != "resume" {
+		return "", false
+	}
+	switch {
+	case len(fields) == 1:
+		return "", true
+	case len(fields) == 2 && IsRunID(fields[1]):
+		return fields[1], true
+	}
+	return "", false
+}
+
+// resume shows the user where a journaled run stopped and, once they
+// confirm, runs the steps that did not complete
+func (a *Agent) resume(ctx context.Context, runID string) (*executor.Result, error) {
+	if runID == "" {
+		return listRuns()
+	}
+
+	run, err := LoadRun(runID)
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to load run: %v", err),
+		}, nil
+	}
+	if (run.Finished && run.Success) || run.Completed() == len(run.Plan.Steps) {
+		return &executor.Result{
+			IsError: false,
+			Output:  fmt.Sprintf("Run %s already completed; there is nothing to resume.", run.ID),
+		}, nil
+	}
+	if info, err := os.Stat(run.WorkDir); err != nil || !info.IsDir() {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Cannot resume run %s: its directory %s no longer exists.", run.ID, run.WorkDir),
+		}, nil
+	}
+
+	a.feedback.DisplayRun(run)
+	confirmed, err := a.feedback.ConfirmResume(run)
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to confirm execution: %v", err),
+		}, nil
+	}
+	if !confirmed {
+		return &executor.Result{
+			IsError: false,
+			Output:  "Resume cancelled by user.",
+		}, nil
+	}
+
+	if err := hooks.Run(ctx, hooks.EventPreAgentRun, planHookData(run.Plan)); err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Agent run blocked by hook: %v", err),
+		}, nil
+	}
+
+	plan, err := run.ResumePlan()
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to resume run: %v", err),
+		}, nil
+	}
+	a.state.CurrentTask = plan.Task
+	a.state.CurrentPlan = plan
+
+	var remaining []string
+	for _, step := range plan.Steps {
+		if !stepCompleted(step) {
+			remaining = append(remaining, step.Command)
+		}
+	}
+	inputs := replay.ChecksumInputs(remaining)
+
+	a.state.Status = StatusExecuting
+	result, err := a.executor.ExecutePlan(ctx, plan, a.feedback)
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to execute plan: %v", err),
+		}, nil
+	}
+
+	if result.Success {
+		a.state.Status = StatusCompleted
+	} else {
+		a.state.Status = StatusFailed
+	}
+
+	if err := replay.Save(replayRecord(result, inputs)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record agent run: %v\n", err)
+	}
+	a.feedback.DisplaySummary(result)
+
+	return &executor.Result{
+		IsError: !result.Success,
+		Output:  result.Message,
+	}, nil
+}
+
+// listRuns describes the most recent journaled runs
+func listRuns() (*executor.Result, error) {
+	runs, err := ListRuns()
+	if err != nil {
+		return &executor.Result{
+			IsError: true,
+			Output:  fmt.Sprintf("Failed to list runs: %v", err),
+		}, nil
+	}
+	if len(runs) == 0 {
+		return &executor.Result{
+			IsError: false,
+			Output:  "No agent runs have been journaled yet.",
+		}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("Recent agent runs:\n\n")
+	for i, run := range runs {
+		if i == maxListedRuns {
+			break
+		}
+		b.WriteString(fmt.Sprintf("  %s  %-8s %-24s %d/%d steps  %s\n",
+			run.ID, utils.FormatRelative(run.UpdatedAt), run.Status(), run.Completed(), len(run.Plan.Steps), strings.Join(strings.Fields(run.Task), " ")))
+	}
+	b.WriteString("\nContinue a run from where it stopped with: lumo agent:resume <run-id>")
+
+	return &executor.Result{
+		IsError: false,
+		Output:  b.String(),
+	}, nil
+}
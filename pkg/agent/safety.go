@@ -0,0 +1,258 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/privacy"
+	"github.com/agnath18K/lumo/pkg/shellwords"
+)
+
+// destructiveTools are programs whose effects cannot be undone, with what
+// they do
+var destructiveTools = map[string]string{
+	"rm":     "deletes files",
+	"shred":  "destroys file contents",
+	"dd":     "writes raw data",
+	"wipefs": "erases filesystem signatures",
+	"fdisk":  "changes the partition table",
+	"sfdisk": "changes the partition table",
+	"gdisk":  "changes the partition table",
+	"parted": "changes the partition table",
+}
+
+// recursiveOwnershipTools change permissions or owners, which is only hard
+// to undo when applied to a whole tree
+var recursiveOwnershipTools = wordSet("chmod", "chown", "chgrp")
+
+// destructiveReason returns what makes a command destructive, or "" if it
+// is not. Desktop actions are never destructive; the ones that end the
+// session are not available to plans.
+func destructiveReason(command string) string {
+	if assistant.IsAction(command) {
+		return ""
+	}
+	for _, words := range effectiveCommands(command) {
+		if shellwords.IsVariable(words[0]) {
+			return fmt.Sprintf("'%s' runs whatever program the variable names", words[0])
+		}
+		tool := filepath.Base(words[0])
+		var flags []string
+		for _, word := range words[1:] {
+			if strings.HasPrefix(word, "-") {
+				flags = append(flags, word)
+			}
+		}
+
+		switch {
+		case destructiveTools[tool] != "":
+			return fmt.Sprintf("%s %s", tool, destructiveTools[tool])
+		case strings.HasPrefix(tool, "mkfs"):
+			return fmt.Sprintf("%s formats a filesystem", tool)
+		case recursiveOwnershipTools[tool] && (hasFlag(flags, "-R") || hasFlag(flags, "--recursive")):
+			return fmt.Sprintf("%s -R changes a whole directory tree", tool)
+		case tool == "find" && hasFlag(flags, "-delete"):
+			return "find -delete deletes files"
+		}
+	}
+	return ""
+}
+
+// flagAliases maps long and alternative flags to the short flag they mean,
+// per tool, so deny list patterns catch every spelling
+var flagAliases = map[string]map[string]string{
+	"rm":    {"--recursive": "-r", "-R": "-r", "--force": "-f", "--dir": "-d"},
+	"cp":    {"--recursive": "-r", "-R": "-r", "--force": "-f"},
+	"mv":    {"--force": "-f"},
+	"chmod": {"--recursive": "-R"},
+	"chown": {"--recursive": "-R"},
+	"chgrp": {"--recursive": "-R"},
+	"git":   {"--force": "-f"},
+}
+
+// singleDashLongTools take long options after a single dash, like find
+// -delete, so their flags are not split into letters
+var singleDashLongTools = wordSet("find")
+
+// deniedPattern returns the deny-list pattern a command matches, or "" if it
+// matches none. Patterns are matched against the whole command and against
+// each simple command in it, so "rm -rf /" also catches "cd x && sudo rm -rf /".
+// Wrappers such as nice and xargs are skipped, and the scripts given to
+// sh -c are checked too. A * in a pattern matches anything. Simple commands
+// are also matched flag
+// by flag, so "rm -rf /" catches "rm -fr /", "rm -r -f /", and
+// "rm --recursive --force /", and extra flags do not get around it.
+func deniedPattern(command string, denyList []string) string {
+	candidates := []string{strings.Join(strings.Fields(command), " ")}
+	simple := effectiveCommands(command)
+	for _, words := range simple {
+		candidates = append(candidates, strings.Join(words, " "))
+	}
+
+	for _, pattern := range denyList {
+		fields := strings.Fields(pattern)
+		if len(fields) == 0 {
+			continue
+		}
+		re := wildcardPattern(strings.Join(fields, " "))
+		for _, candidate := range candidates {
+			if re.MatchString(candidate) {
+				return pattern
+			}
+		}
+		if patternCommands := shellCommands(pattern); len(patternCommands) == 1 {
+			for _, words := range simple {
+				if matchesFlags(patternCommands[0], words) {
+					return pattern
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// effectiveCommands returns the simple commands a command line runs, as
+// the safety checks see them: keywords and wrappers such as nice, xargs,
+// and env are skipped to reach the program, and the scripts passed to
+// sh -c are split into their own commands as well
+func effectiveCommands(command string) [][]string {
+	var commands [][]string
+	for _, cmd := range shellwords.Unwrap(command) {
+		commands = append(commands, cmd.Words)
+	}
+	return commands
+}
+
+// wildcardPattern compiles a pattern in which * matches anything
+func wildcardPattern(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$")
+}
+
+// matchesFlags reports whether a simple command runs the pattern's tool
+// with at least the pattern's flags, in any order or spelling, and with
+// operands the pattern's operands match
+func matchesFlags(pattern, words []string) bool {
+	patternTool, patternFlags, patternOperands := splitFlags(pattern)
+	tool, flags, operands := splitFlags(words)
+	if !wildcardPattern(patternTool).MatchString(tool) {
+		return false
+	}
+	for flag := range patternFlags {
+		if !flags[flag] {
+			return false
+		}
+	}
+	return wildcardPattern(strings.Join(patternOperands, " ")).MatchString(strings.Join(operands, " "))
+}
+
+// splitFlags splits a simple command into its tool, its flags, and its
+// other words. Bundled short flags are split and aliases resolved, so
+// -rf, -r -f, and --recursive --force give the same flags.
+func splitFlags(words []string) (string, map[string]bool, []string) {
+	tool := filepath.Base(words[0])
+	flags := make(map[string]bool)
+	var operands []string
+	endOfFlags := false
+	for _, word := range words[1:] {
+		switch {
+		case endOfFlags || word == "-" || !strings.HasPrefix(word, "-"):
+			operands = append(operands, word)
+		case word == "--":
+			endOfFlags = true
+		case strings.HasPrefix(word, "--") || singleDashLongTools[tool]:
+			flags[flagAlias(tool, word)] = true
+		default:
+			for _, letter := range word[1:] {
+				flags[flagAlias(tool, "-"+string(letter))] = true
+			}
+		}
+	}
+	return tool, flags, operands
+}
+
+// flagAlias returns the short flag a tool's flag means
+func flagAlias(tool, flag string) string {
+	if alias, ok := flagAliases[tool][flag]; ok {
+		return alias
+	}
+	return flag
+}
+
+// denyListBlocked returns the error for a command matching a deny list pattern
+func denyListBlocked(pattern string) error {
+	return lumoerr.New(lumoerr.ErrPolicyBlocked, fmt.Sprintf("the agent deny list (%s)", pattern)).
+		WithHint(fmt.Sprintf("Remove the pattern with 'lumo config:agent deny remove %s' to allow it.", pattern))
+}
+
+// denyListViolation returns why the deny list blocks a step, or nil if it may run
+func (e *Executor) denyListViolation(step *Step) error {
+	if pattern := deniedPattern(step.Command, e.config.AgentDenyList); pattern != "" {
+		return denyListBlocked(pattern)
+	}
+	return nil
+}
+
+// CommandRefusal returns why a shell command run outside a plan, such as by
+// a script, is refused, or nil if it may run. The deny list and strict
+// privacy mode apply as they do to plan steps, and unless the safety level
+// is fast, destructive commands run only if confirm agrees.
+func CommandRefusal(cfg *config.Config, command string, confirm func(reason string) bool) error {
+	if pattern := deniedPattern(command, cfg.AgentDenyList); pattern != "" {
+		return denyListBlocked(pattern)
+	}
+	if privacy.IsStrict(cfg.PrivacyMode) {
+		if reason := privacy.UploadReason(command); reason != "" {
+			return privacy.UploadBlocked(reason)
+		}
+	}
+	if cfg.AgentSafety() == config.AgentSafetyFast {
+		return nil
+	}
+	if reason := destructiveReason(command); reason != "" && !confirm(reason) {
+		return fmt.Errorf("not confirmed: %s, which the %s safety level asks about", reason, cfg.AgentSafety())
+	}
+	return nil
+}
+
+// confirmationReason returns why the safety level wants the user to confirm
+// a step before it runs, or "" if it runs without asking. The fast level
+// relies on the confirmation of the plan as a whole.
+func (e *Executor) confirmationReason(step *Step) string {
+	switch e.config.AgentSafety() {
+	case config.AgentSafetyFast:
+		return ""
+	case config.AgentSafetyStrict:
+		if reason := destructiveReason(step.Command); reason != "" {
+			return reason
+		}
+		if step.IsCritical {
+			return "the step is critical"
+		}
+		return ""
+	default:
+		return destructiveReason(step.Command)
+	}
+}
+
+// stepRefusal returns why a step of a running plan is not run, or nil if it
+// may run. Steps are checked as they come up, since they may have been
+// edited or revised since the plan was confirmed.
+func (e *Executor) stepRefusal(ctx context.Context, plan *Plan, step *Step, feedback *Feedback) error {
+	if reason := readOnlyStepViolation(plan, step); reason != "" {
+		return fmt.Errorf("refused in read-only mode: %s", reason)
+	}
+	if err := e.denyListViolation(step); err != nil {
+		return err
+	}
+	if reason := e.confirmationReason(step); reason != "" && !confirmStep(ctx, feedback, step, reason) {
+		return fmt.Errorf("not confirmed: %s, which the %s safety level asks about", reason, e.config.AgentSafety())
+	}
+	return nil
+}
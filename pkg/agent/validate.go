@@ -0,0 +1,268 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/pkg/shellwords"
+)
+
+// helpProbeTimeout bounds how long a tool may take to print its --help text
+const helpProbeTimeout = 2 * time.Second
+
+// shellBuiltins are commands that are never looked up in PATH
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "export": true, "source": true, ".": true, "[": true, "[[": true,
+	"test": true, "true": true, "false": true, "if": true, "then": true, "else": true, "elif": true,
+	"fi": true, "for": true, "while": true, "until": true, "do": true, "done": true, "case": true,
+	"esac": true, "read": true, "set": true, "unset": true, "alias": true, "exit": true, "pwd": true,
+	"printf": true, "type": true, "command": true, "exec": true, "eval": true, "time": true,
+	"ulimit": true, "umask": true, "wait": true, "shift": true, "local": true, "return": true,
+	"pushd": true, "popd": true, "history": true, "let": true, "declare": true, "{": true, "}": true,
+}
+
+// fileReaders are commands whose non-flag arguments must be existing files.
+// The value is how many leading arguments are not files (a mode, an owner).
+var fileReaders = map[string]int{
+	"cat": 0, "less": 0, "more": 0, "head": 0, "tail": 0, "wc": 0, "sort": 0, "uniq": 0,
+	"md5sum": 0, "sha256sum": 0, "file": 0, "stat": 0, "diff": 0, "source": 0, ".": 0,
+	"chmod": 1, "chown": 1, "chgrp": 1,
+}
+
+// helpProbed are common tools whose flags are checked against their --help text
+var helpProbed = map[string]bool{
+	"ls": true, "cp": true, "mv": true, "rm": true, "mkdir": true, "grep": true, "find": true,
+	"tar": true, "sed": true, "sort": true, "head": true, "tail": true, "du": true, "df": true,
+	"chmod": true, "chown": true, "ln": true, "wc": true, "cat": true, "curl": true, "wget": true,
+	"zip": true, "unzip": true, "rsync": true, "touch": true, "uniq": true, "cut": true,
+}
+
+// PlanValidator checks plan steps against the tools and files on this machine
+type PlanValidator struct {
+	// help caches --help output per tool path; an empty string means no usable help
+	help map[string]string
+}
+
+// NewPlanValidator creates a new plan validator
+func NewPlanValidator() *PlanValidator {
+	return &PlanValidator{
+		help: make(map[string]string),
+	}
+}
+
+// Validate returns the warnings for each step of the plan, indexed like plan.Steps
+func (v *PlanValidator) Validate(plan *Plan) [][]string {
+	warnings := make([][]string, len(plan.Steps))
+
+	// Paths mentioned by earlier commands may be created before they are read
+	mentioned := make(map[string]bool)
+	// Once a step changes directory, relative paths can no longer be checked
+	changedDir := false
+
+	for i, step := range plan.Steps {
+		if assistant.IsAction(step.Command) {
+			if _, _, err := assistant.ParseAction(step.Command); err != nil {
+				warnings[i] = append(warnings[i], err.Error())
+			} else if !hasDesktopSession() {
+				warnings[i] = append(warnings[i], "there is no desktop session to run this desktop action in")
+			}
+			continue
+		}
+		for _, fields := range shellCommands(step.Command) {
+			tool := fields[0]
+			args := fields[1:]
+
+			if tool == "cd" || tool == "pushd" {
+				changedDir = true
+			}
+
+			if !shellBuiltins[tool] && !strings.Contains(tool, "$") {
+				path, err := exec.LookPath(tool)
+				if err != nil {
+					warnings[i] = append(warnings[i], fmt.Sprintf("'%s' is not installed (not found in PATH)", tool))
+				} else if helpProbed[filepath.Base(tool)] {
+					for _, flag := range v.unsupportedFlags(path, filepath.Base(tool), args) {
+						warnings[i] = append(warnings[i], fmt.Sprintf("'%s' does not appear to support %s", tool, flag))
+					}
+				}
+			}
+
+			for _, file := range requiredFiles(tool, args) {
+				if mentionedPath(mentioned, file) || (changedDir && !filepath.IsAbs(file)) {
+					continue
+				}
+				if _, err := os.Stat(file); os.IsNotExist(err) {
+					warnings[i] = append(warnings[i], fmt.Sprintf("'%s' does not exist", file))
+				}
+			}
+
+			for _, field := range fields {
+				mentioned[field] = true
+			}
+		}
+	}
+
+	return warnings
+}
+
+// mentionedPath reports whether a path, or a directory containing it, was
+// mentioned by an earlier command
+func mentionedPath(mentioned map[string]bool, path string) bool {
+	dir := filepath.Clean(path)
+	for dir != "." && dir != filepath.Dir(dir) {
+		if mentioned[dir] || mentioned[dir+"/"] {
+			return true
+		}
+		dir = filepath.Dir(dir)
+	}
+	return false
+}
+
+// unsupportedFlags returns the flags that do not appear in a tool's --help text
+func (v *PlanValidator) unsupportedFlags(path, tool string, args []string) []string {
+	help, ok := v.help[path]
+	if !ok {
+		help = probeHelp(path)
+		v.help[path] = help
+	}
+	if help == "" {
+		return nil
+	}
+
+	var unsupported []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" || isNumericFlag(arg) {
+			continue
+		}
+
+		flag := arg
+		if strings.HasPrefix(flag, "--") {
+			flag, _, _ = strings.Cut(flag, "=")
+			if !strings.Contains(help, flag) {
+				unsupported = append(unsupported, flag)
+			}
+			continue
+		}
+
+		if strings.Contains(help, flag) {
+			continue
+		}
+		// find takes long options with a single dash, so only the whole word counts
+		if tool == "find" {
+			unsupported = append(unsupported, flag)
+			continue
+		}
+
+		// Combined short options, e.g. -czf
+		for _, letter := range flag[1:] {
+			if !unicode.IsLetter(letter) && !unicode.IsDigit(letter) {
+				break
+			}
+			short := "-" + string(letter)
+			if !strings.Contains(help, short) {
+				unsupported = append(unsupported, short)
+			}
+		}
+	}
+	return unsupported
+}
+
+// probeHelp returns a tool's --help text, or "" if it does not print one
+func probeHelp(path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), helpProbeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "--help")
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	output, _ := cmd.CombinedOutput()
+
+	// BSD tools reject --help with a short usage line, which is not enough to go on
+	help := string(output)
+	if ctx.Err() != nil || strings.Count(help, "\n") < 5 {
+		return ""
+	}
+	return help
+}
+
+// requiredFiles returns the arguments of a command that must be existing files
+func requiredFiles(tool string, args []string) []string {
+	var files []string
+
+	// Input redirection always needs an existing file
+	for i, arg := range args {
+		if arg == "<" && i+1 < len(args) {
+			files = append(files, args[i+1])
+		}
+	}
+
+	var operands []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "<" || arg == ">" || arg == ">>" || arg == "2>" || arg == "<<" || arg == "<<<" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, "<>") {
+			continue
+		}
+		operands = append(operands, arg)
+	}
+
+	skip, reads := fileReaders[tool]
+	switch {
+	case reads:
+		if len(operands) > skip {
+			files = append(files, operands[skip:]...)
+		}
+	case tool == "cp" || tool == "mv":
+		// Every operand but the destination is a source
+		if len(operands) > 1 {
+			files = append(files, operands[:len(operands)-1]...)
+		}
+	}
+
+	// Paths with expansions can only be resolved by the shell
+	checkable := files[:0]
+	for _, file := range files {
+		if !strings.ContainsAny(file, "$*?[{~`") {
+			checkable = append(checkable, file)
+		}
+	}
+	return checkable
+}
+
+// shellCommands splits a command line into the simple commands it runs, each
+// as a list of words with quotes removed. sudo, env, and variable assignments
+// before the program name are dropped.
+func shellCommands(command string) [][]string {
+	var commands [][]string
+	for _, words := range shellwords.Split(command) {
+		for len(words) > 0 && (words[0] == "sudo" || words[0] == "env" || shellwords.IsAssignment(words[0])) {
+			words = words[1:]
+		}
+		if len(words) > 0 {
+			commands = append(commands, words)
+		}
+	}
+	return commands
+}
+
+// isNumericFlag reports whether a flag is a count such as head -20
+func isNumericFlag(flag string) bool {
+	for _, r := range strings.TrimLeft(flag, "-") {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
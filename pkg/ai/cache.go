@@ -0,0 +1,243 @@
+package ai
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// ResponseCache keeps AI answers so an identical question is answered
+// without another request. Answers are held in memory, most recently used
+// first, and on disk so later runs can use them too.
+type ResponseCache struct {
+	mu       sync.Mutex
+	dir      string
+	ttl      time.Duration
+	capacity int
+	// order lists keys from most to least recently used
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// cachedResponse is an answer in the cache, as stored on disk
+type cachedResponse struct {
+	Key       string    `json:"key"`
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewResponseCache creates a cache that keeps up to capacity answers in dir
+// for ttl
+func NewResponseCache(dir string, ttl time.Duration, capacity int) *ResponseCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ResponseCache{
+		dir:      dir,
+		ttl:      ttl,
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// OpenResponseCache opens the response cache in lumo's cache directory
+// with the configured lifetime and size
+func OpenResponseCache(cfg *config.Config) (*ResponseCache, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(cfg.ResponseCacheTTLMinutes) * time.Minute
+	return NewResponseCache(filepath.Join(dir, "responses"), ttl, cfg.ResponseCacheSize), nil
+}
+
+// CacheKey identifies a question to the model behind client. The answer
+// can depend on the system instructions and on context such as the
+// working directory, so they are part of the key.
+func CacheKey(client Client, instructions, query, context string) string {
+	provider, model := describeClient(client)
+	sum := sha256.Sum256([]byte(strings.Join([]string{provider, model, instructions, context, query}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// describeClient returns the provider and model a client talks to
+func describeClient(client Client) (string, string) {
+	switch c := client.(type) {
+	case *GeminiClient:
+		return string(ProviderGemini), c.model
+	case *OpenAIClient:
+		return string(ProviderOpenAI), c.model
+	case *OllamaClient:
+		return string(ProviderOllama), c.model
+	case *MockClient:
+		return string(ProviderMock), c.path
+	default:
+		return fmt.Sprintf("%T", client), ""
+	}
+}
+
+// Get returns the cached answer for key, if it has not expired
+func (c *ResponseCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cachedResponse)
+		if c.expired(entry) {
+			c.remove(key)
+			return "", false
+		}
+		c.order.MoveToFront(element)
+		return entry.Response, true
+	}
+
+	// Another run may have cached it
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key || c.expired(&entry) {
+		os.Remove(c.path(key))
+		return "", false
+	}
+	c.add(&entry)
+	return entry.Response, true
+}
+
+// Put caches an answer in memory and on disk
+func (c *ResponseCache) Put(key, response string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cachedResponse{Key: key, Response: response, CreatedAt: time.Now()}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+	} else {
+		c.add(entry)
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path(key), data, 0600); err != nil {
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	return c.prune()
+}
+
+// Clear removes every cached answer and returns how many there were on disk
+func (c *ResponseCache) Clear() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+
+	files, err := c.files()
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to remove cached response: %w", err)
+		}
+	}
+	return len(files), nil
+}
+
+// Stats returns how many answers are cached on disk and their total size in bytes
+func (c *ResponseCache) Stats() (int, int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.files()
+	if err != nil {
+		return 0, 0, err
+	}
+	var size int64
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			size += info.Size()
+		}
+	}
+	return len(files), size, nil
+}
+
+// add puts an entry at the front of the memory cache, evicting the least
+// recently used one when it is full
+func (c *ResponseCache) add(entry *cachedResponse) {
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).Key)
+	}
+}
+
+// remove drops an entry from memory and disk
+func (c *ResponseCache) remove(key string) {
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+	os.Remove(c.path(key))
+}
+
+// prune deletes the oldest answers on disk beyond the cache's capacity
+func (c *ResponseCache) prune() error {
+	files, err := c.files()
+	if err != nil || len(files) <= c.capacity {
+		return err
+	}
+
+	modified := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modified[file] = info.ModTime()
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return modified[files[i]].Before(modified[files[j]])
+	})
+	for _, file := range files[:len(files)-c.capacity] {
+		os.Remove(file)
+	}
+	return nil
+}
+
+// files returns the cached answers on disk
+func (c *ResponseCache) files() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached responses: %w", err)
+	}
+	return files, nil
+}
+
+// path returns the file an answer is cached in
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// expired reports whether an entry is older than the cache's lifetime
+func (c *ResponseCache) expired(entry *cachedResponse) bool {
+	return time.Since(entry.CreatedAt) > c.ttl
+}
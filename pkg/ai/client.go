@@ -23,6 +23,14 @@ type ChatClient interface {
 	ProcessChatMessage(ctx context.Context, conversation string) (string, error)
 }
 
+// VisionClient extends the Client interface for models that understand images
+type VisionClient interface {
+	Client
+
+	// QueryWithImage sends a prompt about the image at path and returns the response
+	QueryWithImage(path string, prompt string) (string, error)
+}
+
 // Provider represents the type of AI provider
 type Provider string
 
@@ -31,4 +39,8 @@ const (
 	ProviderGemini Provider = "gemini"
 	// ProviderOpenAI represents OpenAI's GPT
 	ProviderOpenAI Provider = "openai"
+	// ProviderOllama represents models served by a local Ollama server
+	ProviderOllama Provider = "ollama"
+	// ProviderMock answers with canned responses, for tests
+	ProviderMock Provider = "mock"
 )
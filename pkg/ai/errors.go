@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+)
+
+// errorTransport turns failures every provider has in common into errors
+// from the catalog: requests that cannot reach the provider are
+// lumoerr.ErrOffline, and a refused API key is lumoerr.ErrProviderAuth.
+// Other answers are left for the provider's client to read.
+type errorTransport struct {
+	provider Provider
+	base     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		if !isNetworkError(err) {
+			return nil, err
+		}
+		offline := lumoerr.Wrap(lumoerr.ErrOffline, err)
+		if t.provider == ProviderOllama && isLoopback(req.URL.Hostname()) {
+			offline.WithHint("Ollama is not running here; start it with 'ollama serve'.")
+		}
+		return nil, offline
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+	case http.StatusBadRequest:
+		// Gemini answers a bad key with 400 rather than 401
+		if t.provider != ProviderGemini {
+			return resp, nil
+		}
+	default:
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	message := providerErrorMessage(body)
+	if resp.StatusCode == http.StatusBadRequest && !strings.Contains(message, "API key not valid") &&
+		!bytes.Contains(body, []byte("API_KEY_INVALID")) {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	if message == "" {
+		message = resp.Status
+	}
+	return nil, lumoerr.New(lumoerr.ErrProviderAuth, fmt.Sprintf("%s: %s", t.provider, message))
+}
+
+// isNetworkError reports whether err means the request never reached the
+// server: the name did not resolve, or the connection failed
+func isNetworkError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// providerErrorMessage returns the message of a provider's error answer.
+// Gemini, OpenAI and Ollama all put it in "error", as an object with a
+// message or as a string.
+func providerErrorMessage(body []byte) string {
+	var answer struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &answer) != nil || answer.Error == nil {
+		return ""
+	}
+	var detail struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(answer.Error, &detail) == nil && detail.Message != "" {
+		return detail.Message
+	}
+	var message string
+	if json.Unmarshal(answer.Error, &message) == nil {
+		return message
+	}
+	return ""
+}
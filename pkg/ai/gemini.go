@@ -8,8 +8,23 @@ import (
 	"io"
 	"net/http"
 	"os"
+
+	"github.com/agnath18K/lumo/pkg/config"
 )
 
+func init() {
+	Register(ProviderInfo{
+		Name:        string(ProviderGemini),
+		DisplayName: "Gemini",
+		Description: "Google's Gemini AI models",
+		Factory: func(cfg *config.Config) Client {
+			return NewGeminiClient(cfg.GeminiAPIKey, cfg.GeminiModel)
+		},
+		Capabilities: Capabilities{Chat: true, NeedsAPIKey: true, Vision: true},
+		APIKey:       func(cfg *config.Config) string { return cfg.GeminiAPIKey },
+	})
+}
+
 // GeminiClient implements the Client interface for Google's Gemini API
 type GeminiClient struct {
 	apiKey string
@@ -27,15 +42,29 @@ type GeminiContent struct {
 	Parts []GeminiPart `json:"parts"`
 }
 
-// GeminiPart represents a part of a Gemini content
+// GeminiPart represents a part of a Gemini content: text or an image
 type GeminiPart struct {
-	Text string `json:"text"`
+	Text       string            `json:"text,omitempty"`
+	InlineData *GeminiInlineData `json:"inline_data,omitempty"`
+}
+
+// GeminiInlineData represents an image sent inline in a Gemini request
+type GeminiInlineData struct {
+	MimeType string `json:"mime_type"`
+	Data     string `json:"data"`
 }
 
 // GeminiResponse represents a response from the Gemini API
 type GeminiResponse struct {
-	Candidates []GeminiCandidate `json:"candidates"`
-	Error      *GeminiError      `json:"error,omitempty"`
+	Candidates    []GeminiCandidate `json:"candidates"`
+	UsageMetadata GeminiUsage       `json:"usageMetadata"`
+	Error         *GeminiError      `json:"error,omitempty"`
+}
+
+// GeminiUsage represents the token counts of a Gemini request
+type GeminiUsage struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
 }
 
 // GeminiCandidate represents a candidate response from Gemini
@@ -60,12 +89,18 @@ func NewGeminiClient(apiKey string, model string) *GeminiClient {
 	return &GeminiClient{
 		apiKey: apiKey,
 		model:  model,
-		client: &http.Client{},
+		client: &http.Client{Transport: providerTransport(ProviderGemini)},
 	}
 }
 
 // Query sends a query to the Gemini API and returns the response
 func (c *GeminiClient) Query(query string) (string, error) {
+	return c.QueryWithInstructions(query, SystemInstructions)
+}
+
+// QueryWithInstructions sends a query with the given system instructions
+// in place of Lumo's default ones
+func (c *GeminiClient) QueryWithInstructions(query string, instructions string) (string, error) {
 	// Get current working directory for better context
 	pwd, err := os.Getwd()
 	if err != nil {
@@ -75,7 +110,7 @@ func (c *GeminiClient) Query(query string) (string, error) {
 	// For Gemini, we need to combine system instructions and user query
 	// as Gemini doesn't support separate system and user roles like OpenAI
 	combinedQuery := fmt.Sprintf("System Instructions: %s\n\nCurrent Working Directory: %s\n\nUser Query: %s",
-		SystemInstructions, pwd, query)
+		instructions, pwd, query)
 
 	// Create request body
 	reqBody := GeminiRequest{
@@ -133,10 +168,84 @@ func (c *GeminiClient) Query(query string) (string, error) {
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderGemini, c.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
+
 	// Return the text from the first candidate
 	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
 }
 
+// QueryWithImage sends a prompt about the image at path to the Gemini API.
+// Gemini models are multimodal, so the configured model answers.
+func (c *GeminiClient) QueryWithImage(path string, prompt string) (string, error) {
+	img, err := LoadImage(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Create request body with the image after the instructions and question
+	reqBody := GeminiRequest{
+		Contents: []GeminiContent{
+			{
+				Parts: []GeminiPart{
+					{
+						Text: fmt.Sprintf("System Instructions: %s\n\nUser Query: %s", VisionInstructions, prompt),
+					},
+					{
+						InlineData: &GeminiInlineData{MimeType: img.MIMEType, Data: img.Base64()},
+					},
+				},
+			},
+		},
+	}
+
+	// Marshal request to JSON
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	// Create HTTP request
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", c.model, c.apiKey)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Send request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %w", err)
+	}
+
+	// Parse response
+	var geminiResp GeminiResponse
+	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		return "", fmt.Errorf("error parsing response: %w", err)
+	}
+
+	// Check for API error
+	if geminiResp.Error != nil {
+		return "", fmt.Errorf("API error: %s", geminiResp.Error.Message)
+	}
+
+	// Check for empty response
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("empty response from API")
+	}
+
+	recordUsage(ProviderGemini, c.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
+
+	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+}
+
 // QueryChat sends a chat query to the Gemini API with conversation history
 func (c *GeminiClient) QueryChat(conversation string) (string, error) {
 	// Create request body
@@ -195,6 +304,8 @@ func (c *GeminiClient) QueryChat(conversation string) (string, error) {
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderGemini, c.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
+
 	// Return the text from the first candidate
 	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
 }
@@ -257,6 +368,8 @@ func (c *GeminiClient) GetCompletion(ctx context.Context, prompt string) (string
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderGemini, c.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
+
 	// Return the text from the first candidate
 	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
 }
@@ -320,6 +433,8 @@ func (c *GeminiClient) ProcessChatMessage(ctx context.Context, conversation stri
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderGemini, c.model, geminiResp.UsageMetadata.PromptTokenCount, geminiResp.UsageMetadata.CandidatesTokenCount)
+
 	// Return the text from the first candidate
 	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
 }
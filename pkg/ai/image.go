@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // GIF screenshots and recordings
+	"image/jpeg"
+	"image/png"
+	"os"
+)
+
+const (
+	// maxImageSide is the longest side images are scaled down to; models
+	// read larger images no better and bill more tokens for them
+	maxImageSide = 2048
+	// maxImageBytes is the largest encoded image sent without re-encoding
+	maxImageBytes = 4 << 20
+)
+
+// EncodedImage is an image ready to send to a multimodal model
+type EncodedImage struct {
+	// MIMEType is image/png or image/jpeg
+	MIMEType string
+	Data     []byte
+}
+
+// Base64 returns the image data encoded as standard base64
+func (i *EncodedImage) Base64() string {
+	return base64.StdEncoding.EncodeToString(i.Data)
+}
+
+// DataURL returns the image as a data: URL
+func (i *EncodedImage) DataURL() string {
+	return "data:" + i.MIMEType + ";base64," + i.Base64()
+}
+
+// LoadImage reads a PNG, JPEG, or GIF image for a multimodal model. Images
+// larger than the models need are scaled down, and images in other formats
+// or too large to send are re-encoded.
+func LoadImage(path string) (*EncodedImage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a PNG, JPEG, or GIF image", path)
+	}
+	if (format == "png" || format == "jpeg") && max(config.Width, config.Height) <= maxImageSide && len(data) <= maxImageBytes {
+		return &EncodedImage{MIMEType: "image/" + format, Data: data}, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	img = fitImage(img, maxImageSide)
+
+	// Screenshots keep their sharp text as PNG unless that is too large
+	var buf bytes.Buffer
+	if format != "jpeg" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		if buf.Len() <= maxImageBytes {
+			return &EncodedImage{MIMEType: "image/png", Data: buf.Bytes()}, nil
+		}
+		buf.Reset()
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return &EncodedImage{MIMEType: "image/jpeg", Data: buf.Bytes()}, nil
+}
+
+// fitImage scales an image down so its longest side is at most maxSide,
+// averaging the pixels each new pixel covers
+func fitImage(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return src
+	}
+
+	newWidth, newHeight := maxSide, height*maxSide/width
+	if height > width {
+		newWidth, newHeight = width*maxSide/height, maxSide
+	}
+	newWidth, newHeight = max(newWidth, 1), max(newHeight, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	for y := 0; y < newHeight; y++ {
+		y0, y1 := y*height/newHeight, max((y+1)*height/newHeight, y*height/newHeight+1)
+		for x := 0; x < newWidth; x++ {
+			x0, x1 := x*width/newWidth, max((x+1)*width/newWidth, x*width/newWidth+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/vcr"
+)
+
+// ErrProviderBusy is returned when a request waited too long for a free
+// slot under a provider's concurrency limit
+var ErrProviderBusy = errors.New("AI provider is busy")
+
+// Limiter bounds how many requests are in flight to each provider, so the
+// server, the agent and commands running side by side stay within the
+// provider's rate limits. Requests over the limit queue for a free slot,
+// for up to the queue timeout.
+type Limiter struct {
+	limits  map[string]int
+	timeout time.Duration
+	// slots holds a token for each request in flight, per provider
+	slots map[string]chan struct{}
+}
+
+// NewLimiter creates a limiter allowing limits[provider] requests in
+// flight. Providers that are not listed, or whose limit is 0, are not
+// limited. A timeout of 0 waits for as long as the request's context.
+func NewLimiter(limits map[string]int, timeout time.Duration) *Limiter {
+	l := &Limiter{
+		limits:  make(map[string]int, len(limits)),
+		timeout: timeout,
+		slots:   make(map[string]chan struct{}),
+	}
+	for provider, limit := range limits {
+		if limit > 0 {
+			l.limits[provider] = limit
+			l.slots[provider] = make(chan struct{}, limit)
+		}
+	}
+	return l
+}
+
+// LimiterFromConfig creates a limiter with the configured limits
+func LimiterFromConfig(cfg *config.Config) *Limiter {
+	return NewLimiter(cfg.ProviderConcurrency, time.Duration(cfg.ProviderQueueTimeoutSeconds)*time.Second)
+}
+
+// Limit returns the most requests allowed in flight to provider, or 0 when
+// it is not limited
+func (l *Limiter) Limit(provider string) int {
+	return l.limits[provider]
+}
+
+// InFlight returns how many requests to provider hold a slot
+func (l *Limiter) InFlight(provider string) int {
+	return len(l.slots[provider])
+}
+
+// Acquire waits for a free slot for a request to provider, and returns a
+// function that frees it. It fails with ErrProviderBusy once the queue
+// timeout passes, or with the context's error if it is done first.
+func (l *Limiter) Acquire(ctx context.Context, provider string) (func(), error) {
+	slots := l.slots[provider]
+	if slots == nil {
+		return func() {}, nil
+	}
+	var once sync.Once
+	release := func() {
+		once.Do(func() { <-slots })
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	var expired <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-expired:
+		return nil, fmt.Errorf("%w: %d requests to %s were still in flight after waiting %s", ErrProviderBusy, l.limits[provider], provider, l.timeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+var (
+	limiterMu sync.RWMutex
+	// limiter bounds provider requests; nil means no limits
+	limiter *Limiter
+)
+
+// SetLimiter sets the limiter provider requests go through, including
+// those of clients created earlier. nil removes the limits.
+func SetLimiter(l *Limiter) {
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+	limiter = l
+}
+
+// currentLimiter returns the limiter set with SetLimiter
+func currentLimiter() *Limiter {
+	limiterMu.RLock()
+	defer limiterMu.RUnlock()
+	return limiter
+}
+
+// limitedTransport sends a provider's requests through the limiter. A
+// request holds its slot until its response body is closed, so streamed
+// answers count for as long as they stream.
+type limitedTransport struct {
+	provider Provider
+	base     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// Replayed answers never reach the provider
+	l := currentLimiter()
+	if _, replaying := base.(*vcr.Player); l == nil || replaying {
+		return base.RoundTrip(req)
+	}
+
+	release, err := l.Acquire(req.Context(), string(t.provider))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody frees a limiter slot when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and frees the slot
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
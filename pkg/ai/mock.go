@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+func init() {
+	Register(ProviderInfo{
+		Name:        string(ProviderMock),
+		DisplayName: "Mock",
+		Description: "Canned answers from a fixture file, for tests",
+		Factory: func(cfg *config.Config) Client {
+			return NewMockClient(MockFixturesPath(cfg))
+		},
+		Capabilities: Capabilities{Chat: true, Local: true, Vision: true},
+		Check: func(cfg *config.Config) error {
+			return NewMockClient(MockFixturesPath(cfg)).err
+		},
+	})
+}
+
+// mockPlan answers prompts that ask for a JSON plan when no rule matches,
+// so agent runs work without a fixture file. It is a finished one-step
+// plan, which also ends any revision of it.
+const mockPlan = `{
+  "description": "Mock plan",
+  "done": true,
+  "reason": "The mock provider always finishes after one step",
+  "steps": [
+    {
+      "id": 1,
+      "command": "echo \"mock step\"",
+      "description": "Print a message",
+      "isCritical": false,
+      "retries": 0
+    }
+  ]
+}`
+
+// MockRule answers prompts that match it
+type MockRule struct {
+	// Contains matches prompts containing this text, ignoring case
+	Contains string `json:"contains,omitempty"`
+	// Pattern matches prompts against a regular expression
+	Pattern string `json:"pattern,omitempty"`
+	// Response is the answer to every matching prompt
+	Response string `json:"response,omitempty"`
+	// Responses are answered in turn, repeating the last one, to script
+	// a conversation or an agent plan and its revisions
+	Responses []string `json:"responses,omitempty"`
+
+	pattern *regexp.Regexp
+	next    int
+}
+
+// MockFixtures are the answers of the mock provider, as read from a
+// fixture file
+type MockFixtures struct {
+	// Rules are tried in order; the first one that matches answers
+	Rules []*MockRule `json:"rules"`
+	// Default answers prompts no rule matches
+	Default string `json:"default,omitempty"`
+}
+
+// MockClient implements the Client interface with canned answers, so
+// agent, chat, and pipe flows can be exercised without an API key
+type MockClient struct {
+	mu       sync.Mutex
+	path     string
+	fixtures MockFixtures
+	// err is why the fixture file could not be used, returned by every request
+	err error
+}
+
+// MockFixturesPath returns the fixture file of the mock provider: the
+// configured one, or mock.json in the configuration directory
+func MockFixturesPath(cfg *config.Config) string {
+	if cfg.MockFixtures != "" {
+		return cfg.MockFixtures
+	}
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mock.json")
+}
+
+// NewMockClient creates a mock client answering from the fixture file at
+// path. Without the file, it answers with built-in canned responses.
+func NewMockClient(path string) *MockClient {
+	c := &MockClient{path: path}
+	if path == "" {
+		return c
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c
+	}
+	if err != nil {
+		c.err = fmt.Errorf("failed to read mock fixtures: %w", err)
+		return c
+	}
+	if err := json.Unmarshal(data, &c.fixtures); err != nil {
+		c.err = fmt.Errorf("invalid mock fixtures in %s: %w", path, err)
+		return c
+	}
+	for i, rule := range c.fixtures.Rules {
+		if rule.Pattern == "" {
+			continue
+		}
+		if rule.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+			c.err = fmt.Errorf("invalid pattern in mock rule %d: %w", i+1, err)
+			return c
+		}
+	}
+	return c
+}
+
+// Query answers a query from the fixtures
+func (c *MockClient) Query(query string) (string, error) {
+	return c.answer(query)
+}
+
+// QueryWithInstructions answers a query from the fixtures. The
+// instructions are ignored, so rules match the question alone.
+func (c *MockClient) QueryWithInstructions(query string, instructions string) (string, error) {
+	return c.answer(query)
+}
+
+// GetCompletion answers a prompt from the fixtures
+func (c *MockClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
+	return c.answer(prompt)
+}
+
+// ProcessChatMessage answers a conversation from the fixtures
+func (c *MockClient) ProcessChatMessage(ctx context.Context, conversation string) (string, error) {
+	return c.answer(conversation)
+}
+
+// QueryWithImage answers a prompt about an image from the fixtures, after
+// checking that the image can be sent
+func (c *MockClient) QueryWithImage(path string, prompt string) (string, error) {
+	if _, err := LoadImage(path); err != nil {
+		return "", err
+	}
+	return c.answer(prompt)
+}
+
+// answer returns the answer of the first rule that matches prompt
+func (c *MockClient) answer(prompt string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, rule := range c.fixtures.Rules {
+		if !rule.matches(prompt) {
+			continue
+		}
+		if len(rule.Responses) == 0 {
+			return rule.Response, nil
+		}
+		response := rule.Responses[rule.next]
+		if rule.next < len(rule.Responses)-1 {
+			rule.next++
+		}
+		return response, nil
+	}
+
+	if c.fixtures.Default != "" {
+		return c.fixtures.Default, nil
+	}
+	if strings.Contains(prompt, "valid JSON object") {
+		return mockPlan, nil
+	}
+	return "Mock answer to: " + strings.TrimSpace(prompt), nil
+}
+
+// matches reports whether a rule answers prompt. A rule without a
+// condition matches every prompt.
+func (r *MockRule) matches(prompt string) bool {
+	if r.Contains != "" && !strings.Contains(strings.ToLower(prompt), strings.ToLower(r.Contains)) {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.MatchString(prompt) {
+		return false
+	}
+	return true
+}
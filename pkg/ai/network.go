@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// meteredTransport adds the bytes of each provider request and its answer
+// to the network log behind 'lumo usage network'. Requests to this machine,
+// like a local Ollama server, use no network and are not counted.
+type meteredTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *meteredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if Replaying() || isLoopback(req.URL.Hostname()) {
+		return resp, err
+	}
+
+	sent := max(req.ContentLength, 0)
+	if err != nil {
+		_ = usage.RecordNetwork(usage.NetworkAI, sent, 0)
+		return nil, err
+	}
+	resp.Body = &meteredBody{ReadCloser: resp.Body, sent: sent}
+	return resp, nil
+}
+
+// meteredBody counts what is read of an answer, and records the request's
+// traffic when it is closed
+type meteredBody struct {
+	io.ReadCloser
+	sent     int64
+	received int64
+	once     sync.Once
+}
+
+// Read counts the bytes read
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.received += int64(n)
+	return n, err
+}
+
+// Close closes the body and records the traffic. The answer matters more
+// than the log, so failing to write it is ignored.
+func (b *meteredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		_ = usage.RecordNetwork(usage.NetworkAI, b.sent, b.received)
+	})
+	return err
+}
+
+// isLoopback reports whether host is this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -9,8 +9,37 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
 )
 
+func init() {
+	Register(ProviderInfo{
+		Name:        string(ProviderOllama),
+		DisplayName: "Ollama",
+		Description: "Local Ollama models",
+		Factory: func(cfg *config.Config) Client {
+			return NewOllamaClient(cfg.OllamaURL, cfg.OllamaModel)
+		},
+		Capabilities: Capabilities{Local: true, ListModels: true},
+		Check:        checkOllama,
+	})
+}
+
+// checkOllama verifies that the configured Ollama server is reachable
+func checkOllama(cfg *config.Config) error {
+	client := &http.Client{
+		Transport: providerTransport(ProviderOllama),
+		Timeout:   5 * time.Second,
+	}
+	resp, err := client.Get(strings.TrimSuffix(cfg.OllamaURL, "/") + "/api/tags")
+	if err != nil {
+		return fmt.Errorf("cannot connect to Ollama server at %s. Please make sure Ollama is running and accessible", cfg.OllamaURL)
+	}
+	resp.Body.Close()
+	return nil
+}
+
 // Message represents a chat message
 type Message struct {
 	Role    string `json:"role"`
@@ -38,7 +67,10 @@ type OllamaResponse struct {
 	Done          bool    `json:"done"`
 	DoneReason    string  `json:"done_reason,omitempty"`
 	TotalDuration int64   `json:"total_duration,omitempty"`
-	Error         string  `json:"error,omitempty"`
+	// PromptEvalCount and EvalCount are the prompt and response token counts
+	PromptEvalCount int    `json:"prompt_eval_count,omitempty"`
+	EvalCount       int    `json:"eval_count,omitempty"`
+	Error           string `json:"error,omitempty"`
 }
 
 // NewOllamaClient creates a new Ollama client
@@ -88,11 +120,12 @@ func (c *OllamaClient) GenerateText(prompt string, systemPrompt string) (string,
 
 	// Send request
 	client := &http.Client{
-		Timeout: 60 * time.Second, // Set a longer timeout for model responses
+		Transport: providerTransport(ProviderOllama),
+		Timeout:   60 * time.Second, // Set a longer timeout for model responses
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("error sending request to Ollama: %v", err)
+		return "", fmt.Errorf("error sending request to Ollama: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -110,6 +143,7 @@ func (c *OllamaClient) GenerateText(prompt string, systemPrompt string) (string,
 	// Handle streaming response
 	lines := strings.Split(string(body), "\n")
 	var fullContent strings.Builder
+	promptTokens, completionTokens := 0, 0
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -120,9 +154,12 @@ func (c *OllamaClient) GenerateText(prompt string, systemPrompt string) (string,
 		var resp OllamaResponse
 		if err := json.Unmarshal([]byte(line), &resp); err == nil {
 			fullContent.WriteString(resp.Message.Content)
+			promptTokens += resp.PromptEvalCount
+			completionTokens += resp.EvalCount
 		}
 	}
 
+	recordUsage(ProviderOllama, c.model, promptTokens, completionTokens)
 	result := fullContent.String()
 
 	// Clean up markdown formatting if present
@@ -166,11 +203,12 @@ func (c *OllamaClient) GenerateChat(messages []Message, systemPrompt string) (st
 
 	// Send request
 	client := &http.Client{
-		Timeout: 60 * time.Second, // Set a longer timeout for model responses
+		Transport: providerTransport(ProviderOllama),
+		Timeout:   60 * time.Second, // Set a longer timeout for model responses
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("error sending request to Ollama: %v", err)
+		return "", fmt.Errorf("error sending request to Ollama: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -188,6 +226,7 @@ func (c *OllamaClient) GenerateChat(messages []Message, systemPrompt string) (st
 	// Handle streaming response
 	lines := strings.Split(string(body), "\n")
 	var fullContent strings.Builder
+	promptTokens, completionTokens := 0, 0
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -198,9 +237,12 @@ func (c *OllamaClient) GenerateChat(messages []Message, systemPrompt string) (st
 		var resp OllamaResponse
 		if err := json.Unmarshal([]byte(line), &resp); err == nil {
 			fullContent.WriteString(resp.Message.Content)
+			promptTokens += resp.PromptEvalCount
+			completionTokens += resp.EvalCount
 		}
 	}
 
+	recordUsage(ProviderOllama, c.model, promptTokens, completionTokens)
 	result := fullContent.String()
 
 	// Clean up markdown formatting if present
@@ -218,6 +260,11 @@ func (c *OllamaClient) Query(query string) (string, error) {
 	return c.GenerateText(query, systemPrompt)
 }
 
+// QueryWithInstructions sends a query with the given system prompt
+func (c *OllamaClient) QueryWithInstructions(query string, instructions string) (string, error) {
+	return c.GenerateText(query, instructions)
+}
+
 // GetCompletion sends a prompt to the Ollama API and returns the completion
 func (c *OllamaClient) GetCompletion(ctx context.Context, prompt string) (string, error) {
 	// Use the system prompt for agent mode
@@ -234,10 +281,10 @@ func (c *OllamaClient) ListModels() ([]string, error) {
 	}
 
 	// Send request
-	client := &http.Client{}
+	client := &http.Client{Transport: providerTransport(ProviderOllama)}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request to Ollama: %v", err)
+		return nil, fmt.Errorf("error sending request to Ollama: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -9,8 +9,23 @@ import (
 	"net/http"
 	"os"
 	"strings"
+
+	"github.com/agnath18K/lumo/pkg/config"
 )
 
+func init() {
+	Register(ProviderInfo{
+		Name:        string(ProviderOpenAI),
+		DisplayName: "OpenAI",
+		Description: "OpenAI's GPT models",
+		Factory: func(cfg *config.Config) Client {
+			return NewOpenAIClient(cfg.OpenAIAPIKey, cfg.OpenAIModel)
+		},
+		Capabilities: Capabilities{Chat: true, NeedsAPIKey: true, Vision: true},
+		APIKey:       func(cfg *config.Config) string { return cfg.OpenAIAPIKey },
+	})
+}
+
 // OpenAIClient implements the Client interface for OpenAI's API
 type OpenAIClient struct {
 	apiKey string
@@ -31,12 +46,51 @@ type OpenAIMessage struct {
 	Content string `json:"content"`
 }
 
+// openAIVisionModel answers questions about images when the configured
+// model cannot see them
+const openAIVisionModel = "gpt-4o"
+
+// openAIVisionModels are prefixes of the OpenAI models that accept images
+var openAIVisionModels = []string{"gpt-4o", "gpt-4.1", "gpt-4-turbo", "gpt-5", "o1", "o3", "o4"}
+
+// OpenAIVisionRequest represents a request to the OpenAI API with images
+type OpenAIVisionRequest struct {
+	Model       string                `json:"model"`
+	Messages    []OpenAIVisionMessage `json:"messages"`
+	Temperature float64               `json:"temperature"`
+}
+
+// OpenAIVisionMessage represents a message whose content mixes text and images
+type OpenAIVisionMessage struct {
+	Role    string              `json:"role"`
+	Content []OpenAIContentPart `json:"content"`
+}
+
+// OpenAIContentPart represents text or an image in an OpenAIVisionMessage
+type OpenAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *OpenAIImageURL `json:"image_url,omitempty"`
+}
+
+// OpenAIImageURL represents an image, here always as a data: URL
+type OpenAIImageURL struct {
+	URL string `json:"url"`
+}
+
 // OpenAIResponse represents a response from the OpenAI API
 type OpenAIResponse struct {
 	Choices []OpenAIChoice `json:"choices"`
+	Usage   OpenAIUsage    `json:"usage"`
 	Error   *OpenAIError   `json:"error,omitempty"`
 }
 
+// OpenAIUsage represents the token counts of an OpenAI request
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
 // OpenAIChoice represents a choice in an OpenAI response
 type OpenAIChoice struct {
 	Message OpenAIMessage `json:"message"`
@@ -58,26 +112,31 @@ func NewOpenAIClient(apiKey string, model string) *OpenAIClient {
 	return &OpenAIClient{
 		apiKey: apiKey,
 		model:  model,
-		client: &http.Client{},
+		client: &http.Client{Transport: providerTransport(ProviderOpenAI)},
 	}
 }
 
 // Query sends a query to the OpenAI API and returns the response
 func (c *OpenAIClient) Query(query string) (string, error) {
+	return c.QueryWithInstructions(query, "You are Lumo, an AI assistant in the terminal. Be concise and helpful.\n\n"+SystemInstructions)
+}
+
+// QueryWithInstructions sends a query with the given system instructions
+// in place of Lumo's default ones
+func (c *OpenAIClient) QueryWithInstructions(query string, instructions string) (string, error) {
 	// Get current working directory for better context
 	pwd, err := os.Getwd()
 	if err != nil {
 		pwd = "unknown" // Fallback if we can't get the current directory
 	}
 
-	// Create request body with enhanced system instructions including pwd
+	// Create request body with the system instructions including pwd
 	reqBody := OpenAIRequest{
 		Model: c.model,
 		Messages: []OpenAIMessage{
 			{
-				Role: "system",
-				Content: fmt.Sprintf("You are Lumo, an AI assistant in the terminal. Be concise and helpful.\n\n%s\n\nCurrent Working Directory: %s",
-					SystemInstructions, pwd),
+				Role:    "system",
+				Content: fmt.Sprintf("%s\n\nCurrent Working Directory: %s", instructions, pwd),
 			},
 			{
 				Role:    "user",
@@ -130,10 +189,102 @@ func (c *OpenAIClient) Query(query string) (string, error) {
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderOpenAI, c.model, openaiResp.Usage.PromptTokens, openaiResp.Usage.CompletionTokens)
+
 	// Return the content from the first choice
 	return openaiResp.Choices[0].Message.Content, nil
 }
 
+// QueryWithImage sends a prompt about the image at path to the OpenAI API.
+// Models that cannot see images are replaced with gpt-4o for the question.
+func (c *OpenAIClient) QueryWithImage(path string, prompt string) (string, error) {
+	img, err := LoadImage(path)
+	if err != nil {
+		return "", err
+	}
+
+	model := c.model
+	if !openAIHasVision(model) {
+		model = openAIVisionModel
+	}
+
+	// Create request body with the image after the question
+	reqBody := OpenAIVisionRequest{
+		Model: model,
+		Messages: []OpenAIVisionMessage{
+			{
+				Role:    "system",
+				Content: []OpenAIContentPart{{Type: "text", Text: VisionInstructions}},
+			},
+			{
+				Role: "user",
+				Content: []OpenAIContentPart{
+					{Type: "text", Text: prompt},
+					{Type: "image_url", ImageURL: &OpenAIImageURL{URL: img.DataURL()}},
+				},
+			},
+		},
+		Temperature: 0.7,
+	}
+
+	// Marshal request to JSON
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+
+	// Send request
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %w", err)
+	}
+
+	// Parse response
+	var openaiResp OpenAIResponse
+	if err := json.Unmarshal(body, &openaiResp); err != nil {
+		return "", fmt.Errorf("error parsing response: %w", err)
+	}
+
+	// Check for API error
+	if openaiResp.Error != nil {
+		return "", fmt.Errorf("API error: %s", openaiResp.Error.Message)
+	}
+
+	// Check for empty response
+	if len(openaiResp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from API")
+	}
+
+	recordUsage(ProviderOpenAI, model, openaiResp.Usage.PromptTokens, openaiResp.Usage.CompletionTokens)
+
+	return openaiResp.Choices[0].Message.Content, nil
+}
+
+// openAIHasVision reports whether an OpenAI model accepts images
+func openAIHasVision(model string) bool {
+	for _, prefix := range openAIVisionModels {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // QueryChat sends a chat query to the OpenAI API with conversation history
 func (c *OpenAIClient) QueryChat(messages []OpenAIMessage) (string, error) {
 	// Create request body
@@ -186,6 +337,8 @@ func (c *OpenAIClient) QueryChat(messages []OpenAIMessage) (string, error) {
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderOpenAI, c.model, openaiResp.Usage.PromptTokens, openaiResp.Usage.CompletionTokens)
+
 	// Return the content from the first choice
 	return openaiResp.Choices[0].Message.Content, nil
 }
@@ -247,6 +400,8 @@ func (c *OpenAIClient) GetCompletion(ctx context.Context, prompt string) (string
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderOpenAI, c.model, openaiResp.Usage.PromptTokens, openaiResp.Usage.CompletionTokens)
+
 	// Return the content from the first choice
 	return openaiResp.Choices[0].Message.Content, nil
 }
@@ -366,6 +521,8 @@ func (c *OpenAIClient) ProcessChatMessage(ctx context.Context, conversation stri
 		return "", fmt.Errorf("empty response from API")
 	}
 
+	recordUsage(ProviderOpenAI, c.model, openaiResp.Usage.PromptTokens, openaiResp.Usage.CompletionTokens)
+
 	// Return the content from the first choice
 	return openaiResp.Choices[0].Message.Content, nil
 }
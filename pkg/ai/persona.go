@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// BuiltinPersonas are the personas available without defining any. A
+// persona defined in the configuration with the same name replaces one.
+var BuiltinPersonas = map[string]string{
+	"sysadmin": `You are Lumo, a terse senior sysadmin working in the terminal. Answer with the command first, in a code block, followed by at most one line of explanation. Skip caveats unless a command is destructive, and never explain basics.`,
+	"tutor":    `You are Lumo, a patient tutor for people learning the terminal. Show each command in a code block, then explain step by step what every part and flag does and why it is needed. Mention a simpler or safer alternative when there is one, and end with a short tip for learning more.`,
+}
+
+// Persona returns the system instructions of the named persona, looking in
+// the configuration before the built-in personas
+func Persona(cfg *config.Config, name string) (string, bool) {
+	if instructions, ok := cfg.Personas[name]; ok {
+		return instructions, true
+	}
+	instructions, ok := BuiltinPersonas[name]
+	return instructions, ok
+}
+
+// PersonaNames returns the names of all personas sorted by name
+func PersonaNames(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, personas := range []map[string]string{cfg.Personas, BuiltinPersonas} {
+		for name := range personas {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InstructedClient is a Client that can answer a query with system
+// instructions other than Lumo's default ones
+type InstructedClient interface {
+	Client
+
+	// QueryWithInstructions sends a query with the given system instructions
+	QueryWithInstructions(query string, instructions string) (string, error)
+}
+
+// QueryAs sends a query with the given system instructions in place of the
+// default ones. Clients that cannot take instructions get them at the start
+// of the query instead.
+func QueryAs(client Client, query string, instructions string) (string, error) {
+	if instructed, ok := client.(InstructedClient); ok {
+		return instructed.QueryWithInstructions(query, instructions)
+	}
+	return client.Query(fmt.Sprintf("System Instructions: %s\n\nUser Query: %s", instructions, query))
+}
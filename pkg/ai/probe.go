@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// probePrompt is the tiny request sent to test a provider
+const probePrompt = "Reply with the single word OK."
+
+// probeInstructions keep the test answer, and what it costs, short
+const probeInstructions = "You are testing a connection. Answer in one word."
+
+// ProbeResult is the outcome of testing a provider with Probe
+type ProbeResult struct {
+	Provider ProviderInfo
+	// Model is the model that answered, or the configured one
+	Model string
+	// Skipped says why the provider was not tested, like a missing API key
+	Skipped string
+	Err     error
+	Latency time.Duration
+	Reply   string
+	// Usage holds the request's token counts, when the provider sent them
+	Usage    usage.Entry
+	HasUsage bool
+}
+
+// Cost estimates what the test request cost in US dollars. It reports
+// false when the provider sent no token counts or the model has no price.
+func (r ProbeResult) Cost() (float64, bool) {
+	if !r.HasUsage {
+		return 0, false
+	}
+	return r.Usage.Cost()
+}
+
+// modelLister is a client that can list the models it serves
+type modelLister interface {
+	ListModels() ([]string, error)
+}
+
+// Probe sends a tiny prompt to the named provider and reports how long it
+// took, which model answered, and its token counts. Providers that list
+// their models, like Ollama, must also have the configured model installed.
+func Probe(name string, cfg *config.Config) ProbeResult {
+	info, ok := Lookup(name)
+	if !ok {
+		return ProbeResult{Err: fmt.Errorf("unknown AI provider: %s", name)}
+	}
+	result := ProbeResult{Provider: info, Model: ConfiguredModel(name, cfg)}
+	if MissingAPIKey(name, cfg) {
+		result.Skipped = fmt.Sprintf("no API key; set one with 'lumo config:key set %s <key>'", name)
+		return result
+	}
+	if info.Check != nil {
+		if result.Err = info.Check(cfg); result.Err != nil {
+			return result
+		}
+	}
+
+	client := info.Factory(cfg)
+	if lister, ok := client.(modelLister); ok && info.Capabilities.ListModels {
+		models, err := lister.ListModels()
+		if err != nil {
+			result.Err = fmt.Errorf("failed to list models: %w", err)
+			return result
+		}
+		if !hasModel(models, result.Model) {
+			result.Err = fmt.Errorf("the model %s is not pulled; run 'ollama pull %s'", result.Model, result.Model)
+			return result
+		}
+	}
+
+	before, _ := LastUsage(name)
+	start := time.Now()
+	result.Reply, result.Err = QueryAs(client, probePrompt, probeInstructions)
+	result.Latency = time.Since(start)
+	if result.Err != nil {
+		return result
+	}
+	if entry, ok := LastUsage(name); ok && entry.Time != before.Time {
+		result.Usage, result.HasUsage = entry, true
+		if entry.Model != "" {
+			result.Model = entry.Model
+		}
+	}
+	return result
+}
+
+// hasModel reports whether model is among models, where a model without a
+// tag means the latest one
+func hasModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model || (!strings.Contains(model, ":") && m == model+":latest") {
+			return true
+		}
+	}
+	return false
+}
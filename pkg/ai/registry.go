@@ -0,0 +1,150 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// Capabilities describes what an AI provider supports
+type Capabilities struct {
+	// Chat means the provider's client implements ChatClient
+	Chat bool
+	// Local means requests never leave this machine
+	Local bool
+	// NeedsAPIKey means the provider cannot be used without an API key
+	NeedsAPIKey bool
+	// ListModels means the provider can list the models it serves
+	ListModels bool
+	// Vision means the provider's client implements VisionClient
+	Vision bool
+}
+
+// Factory creates a client for a provider from the configuration
+type Factory func(cfg *config.Config) Client
+
+// ProviderInfo describes an AI provider registered with Register
+type ProviderInfo struct {
+	// Name is the identifier used in the configuration, e.g. "gemini"
+	Name string
+	// DisplayName is the name shown to users, e.g. "Gemini"
+	DisplayName string
+	// Description is a short description for provider listings
+	Description string
+	// Factory creates the provider's client
+	Factory Factory
+	// Capabilities describes what the provider supports
+	Capabilities Capabilities
+	// APIKey returns the configured API key for providers that need one
+	APIKey func(cfg *config.Config) string
+	// Check, if set, verifies the provider can be reached before switching to it
+	Check func(cfg *config.Config) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ProviderInfo)
+)
+
+// Register makes an AI provider available by name. It is meant to be called
+// from an init function and panics if the provider is invalid or already registered.
+func Register(info ProviderInfo) {
+	if info.Name == "" || info.Factory == nil {
+		panic("ai: Register requires a provider name and factory")
+	}
+	if info.Capabilities.NeedsAPIKey && info.APIKey == nil {
+		panic("ai: provider " + info.Name + " needs an API key but has no APIKey function")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[info.Name]; exists {
+		panic("ai: provider " + info.Name + " registered twice")
+	}
+	if info.DisplayName == "" {
+		info.DisplayName = info.Name
+	}
+	registry[info.Name] = info
+}
+
+// Lookup returns the registered provider with the given name
+func Lookup(name string) (ProviderInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	info, ok := registry[name]
+	return info, ok
+}
+
+// Providers returns all registered providers sorted by name
+func Providers() []ProviderInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	providers := make([]ProviderInfo, 0, len(registry))
+	for _, info := range registry {
+		providers = append(providers, info)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
+
+// ProviderNames returns the names of all registered providers sorted by name
+func ProviderNames() []string {
+	providers := Providers()
+	names := make([]string, len(providers))
+	for i, info := range providers {
+		names[i] = info.Name
+	}
+	return names
+}
+
+// NewClient creates a client for the named provider
+func NewClient(name string, cfg *config.Config) (Client, error) {
+	info, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown AI provider: %s", name)
+	}
+	return info.Factory(cfg), nil
+}
+
+// MissingAPIKey reports whether the named provider needs an API key that is
+// not configured. Replayed requests need no key.
+func MissingAPIKey(name string, cfg *config.Config) bool {
+	info, ok := Lookup(name)
+	return ok && info.Capabilities.NeedsAPIKey && info.APIKey(cfg) == "" && !Replaying()
+}
+
+// textOnlyModels are models of vision providers that cannot read images
+var textOnlyModels = map[string]bool{
+	"gpt-3.5-turbo": true,
+}
+
+// SupportsVision reports whether the named provider answers questions about
+// images with the given model
+func SupportsVision(name, model string) bool {
+	info, ok := Lookup(name)
+	return ok && info.Capabilities.Vision && !textOnlyModels[model]
+}
+
+// IsLocal reports whether the named provider runs on this machine
+func IsLocal(name string) bool {
+	info, ok := Lookup(name)
+	return ok && info.Capabilities.Local
+}
+
+// ConfiguredModel returns the model the named provider is set to use
+func ConfiguredModel(name string, cfg *config.Config) string {
+	switch Provider(name) {
+	case ProviderGemini:
+		return cfg.GeminiModel
+	case ProviderOllama:
+		return cfg.OllamaModel
+	case ProviderMock:
+		return "canned responses"
+	}
+	return cfg.OpenAIModel
+}
@@ -0,0 +1,50 @@
+package ai
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/agnath18K/lumo/pkg/vcr"
+)
+
+var (
+	transportMu sync.RWMutex
+	// transport sends provider requests; nil means http.DefaultTransport
+	transport http.RoundTripper
+)
+
+// SetTransport sets how clients created afterwards send provider requests,
+// such as through a vcr.Recorder or vcr.Player. nil restores the network.
+func SetTransport(rt http.RoundTripper) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transport = rt
+}
+
+// baseTransport returns the transport set with SetTransport
+func baseTransport() http.RoundTripper {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return transport
+}
+
+// providerTransport returns the transport for a provider's requests,
+// which keeps to the provider's concurrency limit, counts their traffic, and
+// reports being offline or a refused key as errors from the catalog
+func providerTransport(provider Provider) http.RoundTripper {
+	limited := &limitedTransport{provider: provider, base: baseTransport()}
+	return &errorTransport{provider: provider, base: &meteredTransport{base: limited}}
+}
+
+// Replaying reports whether provider requests are answered from a cassette
+// rather than the network
+func Replaying() bool {
+	_, ok := baseTransport().(*vcr.Player)
+	return ok
+}
+
+// Recording reports whether provider requests are being recorded to a cassette
+func Recording() bool {
+	_, ok := baseTransport().(*vcr.Recorder)
+	return ok
+}
@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+var (
+	lastUsageMu sync.Mutex
+	// lastUsage is the most recent request's token counts per provider
+	lastUsage = make(map[Provider]usage.Entry)
+)
+
+// recordUsage adds a request's token counts to the usage log behind
+// `lumo usage` and the budget. The answer matters more than the log, so
+// failing to write it is ignored. Replayed answers cost nothing.
+func recordUsage(provider Provider, model string, promptTokens, completionTokens int) {
+	lastUsageMu.Lock()
+	lastUsage[provider] = usage.Entry{
+		Time:             time.Now(),
+		Provider:         string(provider),
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+	}
+	lastUsageMu.Unlock()
+
+	if (promptTokens == 0 && completionTokens == 0) || Replaying() {
+		return
+	}
+	_ = usage.Record(string(provider), model, promptTokens, completionTokens)
+}
+
+// LastUsage returns the token counts of the named provider's most recent
+// request in this process
+func LastUsage(name string) (usage.Entry, bool) {
+	lastUsageMu.Lock()
+	defer lastUsageMu.Unlock()
+	entry, ok := lastUsage[Provider(name)]
+	return entry, ok
+}
@@ -4,6 +4,7 @@ document.addEventListener('DOMContentLoaded', function() {
     // Check authentication status and show appropriate page
     if (isAuthenticated()) {
         showAppPage();
+        loadStats();
     } else {
         showLoginPage();
     }
@@ -27,6 +28,7 @@ document.addEventListener('DOMContentLoaded', function() {
             loginError.classList.add('hidden');
             await login(username, password);
             showAppPage();
+            loadStats();
         } catch (error) {
             loginError.textContent = error.message || 'Login failed. Please check your credentials.';
             loginError.classList.remove('hidden');
@@ -198,4 +200,236 @@ document.addEventListener('DOMContentLoaded', function() {
             executeButton.click();
         }
     });
+    
+    // Refresh the usage stats
+    document.getElementById('refresh-stats-button').addEventListener('click', loadStats);
+    
+    // Run a task with the agent, streaming its progress
+    const agentRunButton = document.getElementById('agent-run-button');
+    const agentTaskInput = document.getElementById('agent-task');
+    agentRunButton.addEventListener('click', function() {
+        const task = agentTaskInput.value.trim();
+        if (task) {
+            runAgent(task);
+        }
+    });
+    agentTaskInput.addEventListener('keypress', function(e) {
+        if (e.key === 'Enter') {
+            agentRunButton.click();
+        }
+    });
+    document.getElementById('agent-approve-button').addEventListener('click', function() {
+        answerAgent(true);
+    });
+    document.getElementById('agent-reject-button').addEventListener('click', function() {
+        answerAgent(false);
+    });
 });
+
+// The ID of the agent run streaming to this page, if any
+let agentRunID = null;
+
+// Run a task with the agent and show its events as they stream in
+async function runAgent(task) {
+    const log = document.getElementById('agent-log');
+    const runButton = document.getElementById('agent-run-button');
+    log.textContent = '';
+    runButton.disabled = true;
+    
+    try {
+        const token = getAuthToken();
+        const response = await fetch('/api/v1/agent/run', {
+            method: 'POST',
+            headers: Object.assign({ 'Content-Type': 'application/json' },
+                token ? { 'Authorization': `Bearer ${token}` } : {}),
+            body: JSON.stringify({ task: task })
+        });
+        if (!response.ok) {
+            throw new Error((await response.text()).trim() || response.statusText);
+        }
+        agentRunID = response.headers.get('X-Lumo-Run-ID');
+        
+        // Events are newline-delimited JSON
+        const reader = response.body.getReader();
+        const decoder = new TextDecoder();
+        let buffered = '';
+        for (;;) {
+            const { value, done } = await reader.read();
+            if (done) {
+                break;
+            }
+            buffered += decoder.decode(value, { stream: true });
+            const lines = buffered.split('\n');
+            buffered = lines.pop();
+            lines.filter(line => line.trim()).forEach(line => showAgentEvent(JSON.parse(line)));
+        }
+    } catch (error) {
+        log.textContent += `Error: ${error.message}\n`;
+        console.error('Agent error:', error);
+    } finally {
+        agentRunID = null;
+        runButton.disabled = false;
+        document.getElementById('agent-confirm').classList.add('hidden');
+    }
+}
+
+// Add an agent event to the log, or ask about it if it needs an answer
+function showAgentEvent(event) {
+    const log = document.getElementById('agent-log');
+    const stepName = step => `[${step.id}] ${step.command}`;
+    
+    switch (event.type) {
+    case 'plan':
+        log.textContent += `Plan: ${event.description || event.task}\n`;
+        (event.steps || []).forEach(function(step) {
+            log.textContent += `  ${stepName(step)}${step.critical ? ' (critical)' : ''}\n`;
+            (step.warnings || []).forEach(warning => log.textContent += `      ⚠️ ${warning}\n`);
+        });
+        break;
+    case 'confirm': {
+        let question = `Execute this plan? ${event.reason || ''}`;
+        if (event.subject === 'step') {
+            question = `Run ${stepName(event.step)}? ${event.reason || ''}`;
+        } else if (event.subject === 'revision') {
+            question = `Accept the revised plan? ${event.reason || ''}`;
+        }
+        document.getElementById('agent-confirm-text').textContent = question;
+        document.getElementById('agent-approval-token').classList.toggle('hidden', event.subject !== 'plan');
+        document.getElementById('agent-confirm').classList.remove('hidden');
+        break;
+    }
+    case 'step_start':
+        log.textContent += `\n▶ ${stepName(event.step)}\n`;
+        break;
+    case 'step_output':
+        log.textContent += `${event.output}\n`;
+        break;
+    case 'step_finish':
+        log.textContent += event.success
+            ? `✅ Step ${event.step.id} done in ${event.duration_ms} ms\n`
+            : `❌ Step ${event.step.id} failed: ${event.error || 'unknown error'}\n`;
+        break;
+    case 'revision':
+        log.textContent += `\n🔄 Plan revised: ${event.reason}\n`;
+        (event.steps || []).forEach(step => log.textContent += `  ${stepName(step)}\n`);
+        break;
+    case 'done':
+        log.textContent += `\n${event.success ? '✅' : '❌'} ${event.output}\n`;
+        break;
+    case 'error':
+        log.textContent += `\nError: ${event.error}\n`;
+        break;
+    }
+    log.scrollTop = log.scrollHeight;
+}
+
+// Answer the question the running agent is waiting on. Plans are approved
+// with the approval token shown on the server's console.
+async function answerAgent(approve) {
+    const tokenInput = document.getElementById('agent-approval-token');
+    const approvalToken = tokenInput.classList.contains('hidden') ? '' : tokenInput.value.trim();
+    if (approve && !tokenInput.classList.contains('hidden') && !approvalToken) {
+        tokenInput.focus();
+        return;
+    }
+    document.getElementById('agent-confirm').classList.add('hidden');
+    tokenInput.value = '';
+    if (!agentRunID) {
+        return;
+    }
+    
+    try {
+        const token = getAuthToken();
+        const approving = approve && approvalToken;
+        const response = await fetch(approving ? '/api/v1/agent/approve' : '/api/v1/agent/confirm', {
+            method: 'POST',
+            headers: Object.assign({ 'Content-Type': 'application/json' },
+                token ? { 'Authorization': `Bearer ${token}` } : {}),
+            body: JSON.stringify(approving
+                ? { token: approvalToken, approve: true }
+                : { run_id: agentRunID, approve: approve })
+        });
+        if (!response.ok) {
+            throw new Error((await response.text()).trim() || response.statusText);
+        }
+    } catch (error) {
+        document.getElementById('agent-log').textContent += `Could not send the answer: ${error.message}\n`;
+        // The run is still waiting, so let the user try again
+        document.getElementById('agent-confirm').classList.remove('hidden');
+        console.error('Agent confirm error:', error);
+    }
+}
+
+// Format a byte count with binary units
+function formatBytes(bytes) {
+    const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB'];
+    let i = 0;
+    while (bytes >= 1024 && i < units.length - 1) {
+        bytes /= 1024;
+        i++;
+    }
+    return i === 0 ? `${bytes} B` : `${bytes.toFixed(1)} ${units[i]}`;
+}
+
+// Summarize the traffic of connect and AI requests under the stats
+function showNetworkUsage(days, cap) {
+    const network = document.getElementById('stats-network');
+    const month = new Date().toISOString().slice(0, 7);
+    let total = 0, monthTotal = 0;
+    days.forEach(function(day) {
+        total += day.sent + day.received;
+        if (day.date.startsWith(month)) {
+            monthTotal += day.sent + day.received;
+        }
+    });
+    network.textContent = `Network: ${formatBytes(total)} sent and received by connect and AI requests.` +
+        (cap ? ` ${formatBytes(monthTotal)} of the ${formatBytes(cap)} monthly cap used.` : '');
+}
+
+// Load the local usage metrics into the stats panel
+async function loadStats() {
+    const summary = document.getElementById('stats-summary');
+    const table = document.getElementById('stats-table');
+    const rows = document.getElementById('stats-rows');
+    
+    try {
+        const token = getAuthToken();
+        const response = await fetch('/api/v1/stats', {
+            headers: token ? { 'Authorization': `Bearer ${token}` } : {}
+        });
+        if (!response.ok) {
+            throw new Error(response.statusText);
+        }
+        
+        const data = await response.json();
+        const stats = data.stats;
+        rows.innerHTML = '';
+        showNetworkUsage(data.network || [], data.network_cap);
+        
+        if (stats.runs === 0) {
+            summary.textContent = data.enabled
+                ? 'No commands recorded yet.'
+                : "Local metrics are off. Turn them on with 'lumo config:metrics enable'.";
+            table.classList.add('hidden');
+            return;
+        }
+        
+        summary.textContent = `${stats.runs} commands on ${stats.active_days} days, ${stats.failures} failed. ` +
+            `Busiest around ${String(stats.busiest_hour).padStart(2, '0')}:00.` +
+            (data.enabled ? '' : ' Collection is off.');
+        stats.commands.forEach(function(command) {
+            const row = document.createElement('tr');
+            [command.command, command.runs, command.failures, `${command.average_ms} ms`].forEach(function(value) {
+                const cell = document.createElement('td');
+                cell.className = 'py-1';
+                cell.textContent = value;
+                row.appendChild(cell);
+            });
+            rows.appendChild(row);
+        });
+        table.classList.remove('hidden');
+    } catch (error) {
+        summary.textContent = `Could not load stats: ${error.message}`;
+        console.error('Stats error:', error);
+    }
+}
@@ -0,0 +1,161 @@
+// Package audit keeps a log of the commands the REST server runs, for
+// reviewing who ran what with 'lumo server:audit' and /api/v1/audit. The
+// log is only appended to, and rotated once it grows past MaxSize.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// MaxSize is how large the log may grow before it is rotated
+const MaxSize = 10 << 20
+
+// Keep is how many rotated logs are kept, as audit.jsonl.1 to .Keep
+const Keep = 5
+
+// mu keeps one process from interleaving writes and rotations
+var mu sync.Mutex
+
+// Entry is one command run through /api/v1/execute
+type Entry struct {
+	Time time.Time `json:"time"`
+	// User is the logged-in user, "apikey:<name>" for an API key, or empty
+	// when authentication is off
+	User   string `json:"user,omitempty"`
+	Client string `json:"client"`
+	// Type is the kind of command, like "ai", "shell", or "agent"
+	Type  string `json:"type"`
+	Input string `json:"input"`
+	// Status is the HTTP status the server answered with; Success is
+	// whether the command itself succeeded
+	Status     int    `json:"status"`
+	Success    bool   `json:"success"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Path returns the file the audit log is kept in
+func Path() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.jsonl"), nil
+}
+
+// rotatedPath returns the path of the nth rotated log
+func rotatedPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// Record appends an entry to the audit log, rotating it first if it has
+// grown past MaxSize
+func Record(entry Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size()+int64(len(data)) >= MaxSize {
+		if err := rotate(path); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// rotate moves the log to audit.jsonl.1, shifting older logs up and
+// dropping the oldest
+func rotate(path string) error {
+	for n := Keep; n > 1; n-- {
+		err := os.Rename(rotatedPath(path, n-1), rotatedPath(path, n))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(path, rotatedPath(path, 1))
+}
+
+// Tail returns the last n entries, oldest first, reading rotated logs when
+// the current one holds fewer. Lines that cannot be read are skipped.
+func Tail(n int) ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var entries []Entry
+	for i := 0; i <= Keep && len(entries) < n; i++ {
+		file := path
+		if i > 0 {
+			file = rotatedPath(path, i)
+		}
+		older, err := readLog(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(older, entries...)
+	}
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+// readLog reads the entries of one log file; a missing file is empty
+func readLog(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		var entry Entry
+		if len(line) > 0 && json.Unmarshal(line, &entry) == nil {
+			entries = append(entries, entry)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so keys can be told apart from JWT
+// tokens in an Authorization header
+const APIKeyPrefix = "lumo_"
+
+// Scopes say which endpoints an API key may call
+const (
+	// ScopeExecute runs commands with /api/v1/execute
+	ScopeExecute = "execute"
+	// ScopeAgent runs and approves agent tasks under /api/v1/agent/
+	ScopeAgent = "agent"
+	// ScopeChat uses the OpenAI-compatible endpoints under /v1/
+	ScopeChat = "chat"
+	// ScopeStats reads /api/v1/stats
+	ScopeStats = "stats"
+	// ScopeConnect transfers files under /api/v1/connect/
+	ScopeConnect = "connect"
+	// ScopeAudit reads the audit log at /api/v1/audit
+	ScopeAudit = "audit"
+	// ScopeAll allows every endpoint an API key can call
+	ScopeAll = "all"
+)
+
+// Scopes are the scopes an API key can have
+var Scopes = []string{ScopeExecute, ScopeAgent, ScopeChat, ScopeStats, ScopeConnect, ScopeAudit, ScopeAll}
+
+// ErrInvalidAPIKey is returned when an API key is unknown or revoked
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// validKeyName matches API key names
+var validKeyName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,31}$`)
+
+// APIKey is a long-lived key for clients that cannot log in, like CI jobs.
+// Only a hash of the key is stored; the key is shown once, when it is
+// created.
+type APIKey struct {
+	Name string `json:"name"`
+	// Hint is the start of the key, to recognize it by
+	Hint      string   `json:"hint"`
+	Hash      string   `json:"hash"`
+	Scopes    []string `json:"scopes"`
+	CreatedAt string   `json:"created_at"`
+	// Role is one of Roles; keys created before roles existed have none
+	Role string `json:"role,omitempty"`
+}
+
+// Allows reports whether the key may call endpoints that need scope
+func (k *APIKey) Allows(scope string) bool {
+	return scope != "" && (slices.Contains(k.Scopes, ScopeAll) || slices.Contains(k.Scopes, scope))
+}
+
+// hashAPIKey returns the stored form of a key. Keys are long and random,
+// so a fast hash is enough, unlike for passwords.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey creates a key with the given name, scopes, and role and
+// returns it; it cannot be shown again
+func (a *Authenticator) CreateAPIKey(name string, scopes []string, role string) (string, error) {
+	if !validKeyName.MatchString(name) {
+		return "", fmt.Errorf("invalid key name %q: use up to 32 letters, digits, dots, dashes, and underscores", name)
+	}
+	if len(scopes) == 0 {
+		return "", fmt.Errorf("an API key needs at least one scope: %s", strings.Join(Scopes, ", "))
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return "", fmt.Errorf("unknown scope %q: use %s", scope, strings.Join(Scopes, ", "))
+		}
+	}
+	if err := checkRole(role); err != nil {
+		return "", err
+	}
+
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return "", err
+	}
+	for _, key := range store.APIKeys {
+		if key.Name == name {
+			return "", fmt.Errorf("API key already exists: %s", name)
+		}
+	}
+
+	secret, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := APIKeyPrefix + strings.TrimRight(secret, "=")
+	store.APIKeys = append(store.APIKeys, APIKey{
+		Name:      name,
+		Hint:      key[:len(APIKeyPrefix)+6],
+		Hash:      hashAPIKey(key),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		Role:      role,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	})
+	if err := a.saveCredentialsStore(store); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns the API keys, without the keys themselves
+func (a *Authenticator) ListAPIKeys() ([]APIKey, error) {
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.APIKeys, nil
+}
+
+// RevokeAPIKey removes the API key with the given name
+func (a *Authenticator) RevokeAPIKey(name string) error {
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return err
+	}
+	for i, key := range store.APIKeys {
+		if key.Name == name {
+			store.APIKeys = append(store.APIKeys[:i], store.APIKeys[i+1:]...)
+			return a.saveCredentialsStore(store)
+		}
+	}
+	return fmt.Errorf("no API key named %q", name)
+}
+
+// ValidateAPIKey returns the stored key matching key
+func (a *Authenticator) ValidateAPIKey(key string) (*APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return nil, err
+	}
+	hash := []byte(hashAPIKey(key))
+	for i := range store.APIKeys {
+		if subtle.ConstantTimeCompare(hash, []byte(store.APIKeys[i].Hash)) == 1 {
+			return &store.APIKeys[i], nil
+		}
+	}
+	return nil, ErrInvalidAPIKey
+}
@@ -53,11 +53,14 @@ type Credentials struct {
 	PasswordHash string `json:"password_hash"`
 	CreatedAt    string `json:"created_at"`
 	UpdatedAt    string `json:"updated_at"`
+	// Role is one of Roles; users saved before roles existed have none
+	Role string `json:"role,omitempty"`
 }
 
 // CredentialsStore represents the credentials store
 type CredentialsStore struct {
 	Credentials []Credentials `json:"credentials"`
+	APIKeys     []APIKey      `json:"api_keys,omitempty"`
 	UpdatedAt   string        `json:"updated_at"`
 }
 
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Roles say what a user or API key may do on the REST server
+const (
+	// RoleAdmin may do everything, including changing settings
+	RoleAdmin = "admin"
+	// RoleOperator may also run shell commands, agent tasks, and transfers
+	RoleOperator = "operator"
+	// RoleViewer may read status and stats and ask the AI
+	RoleViewer = "viewer"
+)
+
+// Roles are the roles a user or API key can have, from most to least
+// trusted
+var Roles = []string{RoleAdmin, RoleOperator, RoleViewer}
+
+// Permissions group the endpoints and commands a role may use
+const (
+	// PermRead reads status and stats, asks the AI, and runs reports
+	PermRead = "read"
+	// PermRun runs shell commands, agent tasks, and file transfers
+	PermRun = "run"
+	// PermAdmin changes settings and manages the server
+	PermAdmin = "admin"
+)
+
+// rolePermissions lists the permissions of each role
+var rolePermissions = map[string][]string{
+	RoleAdmin:    {PermRead, PermRun, PermAdmin},
+	RoleOperator: {PermRead, PermRun},
+	RoleViewer:   {PermRead},
+}
+
+// ValidRole reports whether role is one of Roles
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// EffectiveRole returns the role a user or key has. Users and keys saved
+// before roles existed have none and stay admins, so they keep what they
+// could do.
+func EffectiveRole(role string) string {
+	if role == "" {
+		return RoleAdmin
+	}
+	return role
+}
+
+// RoleAllows reports whether role has permission
+func RoleAllows(role, permission string) bool {
+	return slices.Contains(rolePermissions[EffectiveRole(role)], permission)
+}
+
+// checkRole returns an error unless role is one of Roles
+func checkRole(role string) error {
+	if !ValidRole(role) {
+		return fmt.Errorf("unknown role %q: use %s", role, strings.Join(Roles, ", "))
+	}
+	return nil
+}
+
+// UserRole returns the role of the given user
+func (a *Authenticator) UserRole(username string) (string, error) {
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return "", err
+	}
+	for _, cred := range store.Credentials {
+		if cred.Username == username {
+			return EffectiveRole(cred.Role), nil
+		}
+	}
+	return "", ErrUserNotFound
+}
+
+// SetUserRole changes the role of the given user. The last admin cannot
+// lose the role, or nobody could manage the server.
+func (a *Authenticator) SetUserRole(username, role string) error {
+	if err := checkRole(role); err != nil {
+		return err
+	}
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return err
+	}
+	for i, cred := range store.Credentials {
+		if cred.Username != username {
+			continue
+		}
+		if role != RoleAdmin && isLastAdmin(store, username) {
+			return fmt.Errorf("%s is the only admin; make another user an admin first", username)
+		}
+		store.Credentials[i].Role = role
+		store.Credentials[i].UpdatedAt = time.Now().Format(time.RFC3339)
+		return a.saveCredentialsStore(store)
+	}
+	return ErrUserNotFound
+}
+
+// isLastAdmin reports whether username is the only admin in store
+func isLastAdmin(store *CredentialsStore, username string) bool {
+	admins, isAdmin := 0, false
+	for _, cred := range store.Credentials {
+		if EffectiveRole(cred.Role) == RoleAdmin {
+			admins++
+			isAdmin = isAdmin || cred.Username == username
+		}
+	}
+	return isAdmin && admins == 1
+}
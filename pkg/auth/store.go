@@ -65,8 +65,12 @@ func (a *Authenticator) saveCredentialsStore(store *CredentialsStore) error {
 	return nil
 }
 
-// AddUser adds a new user to the credentials store
-func (a *Authenticator) AddUser(username, password string) error {
+// AddUser adds a new user with the given role to the credentials store
+func (a *Authenticator) AddUser(username, password, role string) error {
+	if err := checkRole(role); err != nil {
+		return err
+	}
+
 	// Load the credentials store
 	store, err := a.loadCredentialsStore()
 	if err != nil {
@@ -91,6 +95,7 @@ func (a *Authenticator) AddUser(username, password string) error {
 	cred := Credentials{
 		Username:     username,
 		PasswordHash: hash,
+		Role:         role,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -186,6 +191,15 @@ func (a *Authenticator) GetUsers() ([]string, error) {
 	return usernames, nil
 }
 
+// ListUsers returns the users in the credentials store
+func (a *Authenticator) ListUsers() ([]Credentials, error) {
+	store, err := a.loadCredentialsStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Credentials, nil
+}
+
 // RemoveUser removes a user from the credentials store
 func (a *Authenticator) RemoveUser(username string) error {
 	// Load the credentials store
@@ -198,6 +212,9 @@ func (a *Authenticator) RemoveUser(username string) error {
 	found := false
 	for i, cred := range store.Credentials {
 		if cred.Username == username {
+			if isLastAdmin(store, username) {
+				return fmt.Errorf("%s is the only admin; make another user an admin first", username)
+			}
 			// Remove the user
 			store.Credentials = append(store.Credentials[:i], store.Credentials[i+1:]...)
 			found = true
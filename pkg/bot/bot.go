@@ -0,0 +1,231 @@
+// Package bot bridges a Telegram chat or Matrix room to lumo, so questions
+// can be asked from a phone. Allowlisted users can ask and chat; running
+// agent tasks needs a second allowlist.
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// Platforms are the chat services the bridge connects to
+var Platforms = []string{"telegram", "matrix"}
+
+// maxTurns is how many messages of a chat the AI sees
+const maxTurns = 20
+
+// Retry delays after the chat service cannot be reached
+const (
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 5 * time.Minute
+)
+
+// Message is a text message received from the chat service
+type Message struct {
+	// Chat is where the reply goes
+	Chat string
+	// Sender is the sender's user ID
+	Sender string
+	// SenderName is another name the sender may be allowlisted by, such as
+	// a Telegram @username
+	SenderName string
+	Text       string
+}
+
+// Transport receives and sends messages on a chat service
+type Transport interface {
+	// Name returns the service's name
+	Name() string
+	// Receive waits for new messages
+	Receive(ctx context.Context) ([]Message, error)
+	// Send sends a text message to a chat
+	Send(ctx context.Context, chat, text string) error
+}
+
+// Bridge answers messages from a chat service with lumo
+type Bridge struct {
+	config    *config.Config
+	exec      *executor.Executor
+	transport Transport
+
+	mu sync.Mutex
+	// conversations holds the recent messages of each chat, for chat replies
+	conversations map[string][]string
+}
+
+// New creates the bridge for the configured chat service
+func New(cfg *config.Config, exec *executor.Executor) (*Bridge, error) {
+	if cfg.BotToken == "" {
+		return nil, fmt.Errorf("no bot token is set (see 'config:bot')")
+	}
+
+	var transport Transport
+	switch cfg.BotPlatform {
+	case "telegram":
+		transport = NewTelegram(TelegramAPI, cfg.BotToken)
+	case "matrix":
+		if cfg.BotMatrixHomeserver == "" {
+			return nil, fmt.Errorf("no Matrix homeserver is set (see 'config:bot')")
+		}
+		transport = NewMatrix(cfg.BotMatrixHomeserver, cfg.BotToken, cfg.BotMatrixRoom)
+	default:
+		return nil, fmt.Errorf("unknown bot platform: %s (use %s)", cfg.BotPlatform, strings.Join(Platforms, " or "))
+	}
+	return NewWithTransport(cfg, exec, transport), nil
+}
+
+// NewWithTransport creates a bridge that uses transport
+func NewWithTransport(cfg *config.Config, exec *executor.Executor, transport Transport) *Bridge {
+	return &Bridge{
+		config:        cfg,
+		exec:          exec,
+		transport:     transport,
+		conversations: make(map[string][]string),
+	}
+}
+
+// Run answers messages until ctx is done. Messages are answered one at a
+// time, in the order they arrive.
+func (b *Bridge) Run(ctx context.Context) {
+	log.Printf("Bot bridge connected to %s", b.transport.Name())
+	delay := minRetryDelay
+	for ctx.Err() == nil {
+		messages, err := b.transport.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Bot bridge: %v; retrying in %s", err, delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+			delay = min(delay*2, maxRetryDelay)
+			continue
+		}
+		delay = minRetryDelay
+
+		for _, msg := range messages {
+			reply := b.Handle(ctx, msg)
+			if reply == "" {
+				continue
+			}
+			if err := b.transport.Send(ctx, msg.Chat, reply); err != nil {
+				log.Printf("Bot bridge: failed to reply: %v", err)
+			}
+		}
+	}
+}
+
+// Handle returns the reply to a message, or "" if it needs none
+func (b *Bridge) Handle(ctx context.Context, msg Message) string {
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		return ""
+	}
+	if !b.allowed(b.config.BotAllowedUsers, msg) {
+		// Strangers get no reply, so the bot does not reveal itself to them
+		log.Printf("Bot bridge: ignored a message from %s, who is not allowed", msg.Sender)
+		return ""
+	}
+
+	command, arg := splitCommand(text)
+	switch command {
+	case "/start", "/help":
+		return helpText
+	case "/new":
+		b.mu.Lock()
+		delete(b.conversations, msg.Chat)
+		b.mu.Unlock()
+		return "Started a new conversation."
+	case "/ask":
+		if arg == "" {
+			return "Usage: /ask <question>"
+		}
+		return b.reply(b.exec.Ask(arg, ""))
+	case "/agent":
+		if arg == "" {
+			return "Usage: /agent <task>"
+		}
+		// Agent plans run unconfirmed, so only the sender's ID counts here;
+		// a Telegram @username can be changed and claimed by someone else
+		if !slices.Contains(b.config.BotAgentUsers, msg.Sender) {
+			return "You are not allowed to run agent tasks."
+		}
+		log.Printf("Bot bridge: %s runs agent task: %s", msg.Sender, arg)
+		result, err := b.exec.RunAgent(executor.WithPlanApproved(ctx), arg)
+		if err != nil {
+			return b.reply("", err)
+		}
+		return utils.StripANSI(result.Output)
+	case "":
+		return b.reply(b.chat(msg.Chat, text))
+	}
+	return fmt.Sprintf("Unknown command: %s\n\n%s", command, helpText)
+}
+
+// helpText lists what the bot understands
+const helpText = `Send a message to chat with lumo. Commands:
+/ask <question>  Ask a single question
+/new             Start a new conversation
+/agent <task>    Run a task with the agent, if allowed
+/help            Show this help`
+
+// chat answers a message in the context of the chat's recent messages
+func (b *Bridge) chat(chat, text string) (string, error) {
+	b.mu.Lock()
+	turns := append(b.conversations[chat], "user: "+text)
+	b.mu.Unlock()
+
+	prompt := text
+	if len(turns) > 1 {
+		prompt = strings.Join(turns, "\n\n") + "\n\nassistant:"
+	}
+	answer, err := b.exec.Ask(prompt, "")
+	if err != nil {
+		return "", err
+	}
+
+	turns = append(turns, "assistant: "+answer)
+	if len(turns) > maxTurns {
+		turns = turns[len(turns)-maxTurns:]
+	}
+	b.mu.Lock()
+	b.conversations[chat] = turns
+	b.mu.Unlock()
+	return answer, nil
+}
+
+// reply turns an answer or error into a reply
+func (b *Bridge) reply(answer string, err error) string {
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return answer
+}
+
+// allowed reports whether the sender of msg is on an allowlist
+func (b *Bridge) allowed(users []string, msg Message) bool {
+	return slices.Contains(users, msg.Sender) || (msg.SenderName != "" && slices.Contains(users, msg.SenderName))
+}
+
+// splitCommand splits a "/command argument" message. Telegram adds the
+// bot's name to commands in groups, as in /ask@lumo_bot, which is dropped.
+func splitCommand(text string) (string, string) {
+	if !strings.HasPrefix(text, "/") {
+		return "", text
+	}
+	command, arg, _ := strings.Cut(text, " ")
+	command, _, _ = strings.Cut(command, "@")
+	return strings.ToLower(command), strings.TrimSpace(arg)
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// matrixPollMillis is how long a sync request waits for new events
+const matrixPollMillis = 30000
+
+// Matrix is a Transport for a Matrix account that is already a member of
+// the rooms it answers in
+type Matrix struct {
+	homeserver string
+	token      string
+	room       string
+	client     *http.Client
+	// userID is the account's own ID, whose messages are not answered
+	userID string
+	// since is the sync position; events before the first sync are skipped
+	since string
+	txn   atomic.Int64
+}
+
+// NewMatrix creates a transport for the account with the access token on
+// homeserver. With room set, only messages in that room are answered.
+func NewMatrix(homeserver, token, room string) *Matrix {
+	return &Matrix{
+		homeserver: strings.TrimRight(homeserver, "/"),
+		token:      token,
+		room:       room,
+		client:     &http.Client{Timeout: matrixPollMillis*time.Millisecond + 10*time.Second},
+	}
+}
+
+// Name returns the service's name
+func (m *Matrix) Name() string {
+	return "Matrix"
+}
+
+// matrixSync is the part of a sync response the bridge reads
+type matrixSync struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			Timeline struct {
+				Events []struct {
+					Type    string `json:"type"`
+					Sender  string `json:"sender"`
+					Content struct {
+						MsgType string `json:"msgtype"`
+						Body    string `json:"body"`
+					} `json:"content"`
+				} `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+	} `json:"rooms"`
+}
+
+// Receive waits for new messages
+func (m *Matrix) Receive(ctx context.Context) ([]Message, error) {
+	if m.userID == "" {
+		var whoami struct {
+			UserID string `json:"user_id"`
+		}
+		if err := m.call(ctx, http.MethodGet, "/account/whoami", nil, &whoami); err != nil {
+			return nil, err
+		}
+		m.userID = whoami.UserID
+	}
+
+	// The first sync only finds the current position, so old messages are not answered
+	first := m.since == ""
+	query := url.Values{}
+	query.Set("timeout", strconv.Itoa(matrixPollMillis))
+	if first {
+		query.Set("timeout", "0")
+		query.Set("filter", `{"room":{"timeline":{"limit":1}}}`)
+	} else {
+		query.Set("since", m.since)
+	}
+
+	var sync matrixSync
+	if err := m.call(ctx, http.MethodGet, "/sync?"+query.Encode(), nil, &sync); err != nil {
+		return nil, err
+	}
+	m.since = sync.NextBatch
+	if first {
+		return nil, nil
+	}
+
+	var messages []Message
+	for roomID, room := range sync.Rooms.Join {
+		if m.room != "" && roomID != m.room {
+			continue
+		}
+		for _, event := range room.Timeline.Events {
+			if event.Type != "m.room.message" || event.Content.MsgType != "m.text" || event.Sender == m.userID {
+				continue
+			}
+			messages = append(messages, Message{Chat: roomID, Sender: event.Sender, Text: event.Content.Body})
+		}
+	}
+	return messages, nil
+}
+
+// Send sends a text message to a room
+func (m *Matrix) Send(ctx context.Context, chat, text string) error {
+	body, err := json.Marshal(map[string]string{"msgtype": "m.text", "body": text})
+	if err != nil {
+		return err
+	}
+	txnID := fmt.Sprintf("lumo-%d-%d", time.Now().UnixNano(), m.txn.Add(1))
+	path := fmt.Sprintf("/rooms/%s/send/m.room.message/%s", url.PathEscape(chat), txnID)
+	return m.call(ctx, http.MethodPut, path, body, nil)
+}
+
+// call calls a client-server API endpoint and decodes the response into result
+func (m *Matrix) call(ctx context.Context, method, path string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, m.homeserver+"/_matrix/client/v3"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("matrix request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var matrixErr struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &matrixErr) == nil && matrixErr.Error != "" {
+			return fmt.Errorf("matrix: %s (HTTP %d)", matrixErr.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("matrix: HTTP %d", resp.StatusCode)
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TelegramAPI is the Telegram Bot API server
+const TelegramAPI = "https://api.telegram.org"
+
+// telegramPollSeconds is how long a request for updates waits for one
+const telegramPollSeconds = 30
+
+// telegramMaxMessage is the longest message Telegram accepts, in characters
+const telegramMaxMessage = 4096
+
+// Telegram is a Transport for a Telegram bot
+type Telegram struct {
+	apiURL string
+	token  string
+	client *http.Client
+	// offset is the ID of the next update to receive
+	offset int64
+}
+
+// NewTelegram creates a transport for the bot with the given token, using
+// the Bot API at apiURL
+func NewTelegram(apiURL, token string) *Telegram {
+	return &Telegram{
+		apiURL: apiURL,
+		token:  token,
+		client: &http.Client{Timeout: (telegramPollSeconds + 10) * time.Second},
+	}
+}
+
+// Name returns the service's name
+func (t *Telegram) Name() string {
+	return "Telegram"
+}
+
+// telegramResponse is the envelope of every Bot API response
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// telegramUpdate is an update from getUpdates; only messages are requested
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		From struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"from"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// Receive waits for new messages
+func (t *Telegram) Receive(ctx context.Context) ([]Message, error) {
+	query := url.Values{}
+	query.Set("timeout", strconv.Itoa(telegramPollSeconds))
+	query.Set("offset", strconv.FormatInt(t.offset, 10))
+	query.Set("allowed_updates", `["message"]`)
+
+	var updates []telegramUpdate
+	if err := t.call(ctx, http.MethodGet, "getUpdates?"+query.Encode(), nil, &updates); err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	for _, update := range updates {
+		t.offset = update.UpdateID + 1
+		if update.Message == nil || update.Message.Text == "" {
+			continue
+		}
+		msg := Message{
+			Chat:   strconv.FormatInt(update.Message.Chat.ID, 10),
+			Sender: strconv.FormatInt(update.Message.From.ID, 10),
+			Text:   update.Message.Text,
+		}
+		if update.Message.From.Username != "" {
+			msg.SenderName = "@" + update.Message.From.Username
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// Send sends a text message to a chat, split into several if it is too long
+func (t *Telegram) Send(ctx context.Context, chat, text string) error {
+	for _, part := range splitMessage(text, telegramMaxMessage) {
+		body, err := json.Marshal(map[string]string{"chat_id": chat, "text": part})
+		if err != nil {
+			return err
+		}
+		if err := t.call(ctx, http.MethodPost, "sendMessage", body, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// call calls a Bot API method and decodes its result into result
+func (t *Telegram) call(ctx context.Context, httpMethod, method string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, httpMethod, fmt.Sprintf("%s/bot%s/%s", t.apiURL, t.token, method), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// The URL holds the bot token, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("invalid telegram response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("telegram: %s", envelope.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(envelope.Result, result)
+	}
+	return nil
+}
+
+// splitMessage splits text into parts of at most limit characters,
+// preferring to break at newlines
+func splitMessage(text string, limit int) []string {
+	runes := []rune(text)
+	var parts []string
+	for len(runes) > limit {
+		cut := limit
+		for i := limit; i > limit/2; i-- {
+			if runes[i-1] == '\n' {
+				cut = i
+				break
+			}
+		}
+		parts = append(parts, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return append(parts, string(runes))
+}
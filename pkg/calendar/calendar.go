@@ -0,0 +1,153 @@
+// Package calendar keeps the reminders set with 'lumo remind', which the
+// daemon delivers as desktop notifications, and builds the agenda shown
+// by 'lumo agenda' from them and from the calendars in Evolution Data
+// Server, which GNOME Calendar uses. Without Evolution Data Server,
+// reminders are kept in lumo's own store only.
+package calendar
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Event is something on the agenda: a calendar event or a reminder
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+	// Repeats is set for events with a recurrence rule
+	Repeats bool
+	// Calendar names the calendar the event is in; empty for reminders
+	// kept only by lumo
+	Calendar string
+	// Reminder is set for reminders set with 'lumo remind'
+	Reminder bool
+}
+
+// Day is one day of the agenda
+type Day struct {
+	Date   time.Time
+	Events []Event
+}
+
+// Agenda is what is planned for one or more days
+type Agenda struct {
+	Days []Day
+	// Calendars names the desktop calendars read; empty when Evolution
+	// Data Server is not available
+	Calendars []string
+	// Err is why the desktop calendars could not be read, if they could
+	// not
+	Err error
+}
+
+// GetAgenda returns the events and reminders of days days, starting with
+// the day of from
+func GetAgenda(ctx context.Context, from time.Time, days int) (*Agenda, error) {
+	reminders, err := Reminders()
+	if err != nil {
+		return nil, err
+	}
+
+	agenda := &Agenda{}
+	var calendars []edsSource
+	eds, err := connectEDS()
+	if err == nil {
+		calendars, err = eds.calendars(ctx)
+	}
+	agenda.Err = err
+	for _, source := range calendars {
+		agenda.Calendars = append(agenda.Calendars, source.name)
+	}
+
+	start := startOfDay(from)
+	for i := 0; i < days; i++ {
+		dayStart := start.AddDate(0, 0, i)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		day := Day{Date: dayStart}
+
+		events := map[string]bool{}
+		for _, source := range calendars {
+			dayEvents, err := eds.dayEvents(ctx, source, dayStart, dayEnd)
+			if err != nil {
+				agenda.Err = err
+				continue
+			}
+			for _, event := range dayEvents {
+				events[event.UID] = true
+				day.Events = append(day.Events, event)
+			}
+		}
+		for _, r := range reminders {
+			if r.At.Before(dayStart) || !r.At.Before(dayEnd) || (r.EventUID != "" && events[r.EventUID]) {
+				continue
+			}
+			day.Events = append(day.Events, Event{
+				UID:      reminderUID(r),
+				Summary:  r.Text,
+				Start:    r.At,
+				End:      r.At,
+				Reminder: true,
+			})
+		}
+		markReminders(day.Events, reminders)
+		sortEvents(day.Events)
+		agenda.Days = append(agenda.Days, day)
+	}
+	return agenda, nil
+}
+
+// dayEvents returns the events of a calendar on one day. Repeating
+// events are moved to the day, keeping their time of day.
+func (c *edsClient) dayEvents(ctx context.Context, source edsSource, dayStart, dayEnd time.Time) ([]Event, error) {
+	calendar, err := c.open(ctx, source.uid)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close(calendar)
+
+	events, err := c.events(ctx, calendar, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].Calendar = source.name
+		if events[i].Repeats && events[i].Start.Before(dayStart) {
+			length := events[i].End.Sub(events[i].Start)
+			start := events[i].Start
+			events[i].Start = time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(),
+				start.Hour(), start.Minute(), start.Second(), 0, dayStart.Location())
+			events[i].End = events[i].Start.Add(length)
+		}
+	}
+	return events, nil
+}
+
+// markReminders flags the calendar events that are reminders set with
+// 'lumo remind'
+func markReminders(events []Event, reminders []Reminder) {
+	uids := map[string]bool{}
+	for _, r := range reminders {
+		if r.EventUID != "" {
+			uids[r.EventUID] = true
+		}
+	}
+	for i := range events {
+		if uids[events[i].UID] {
+			events[i].Reminder = true
+		}
+	}
+}
+
+// sortEvents puts all-day events first, then the rest by start time
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].AllDay != events[j].AllDay {
+			return events[i].AllDay
+		}
+		return events[i].Start.Before(events[j].Start)
+	})
+}
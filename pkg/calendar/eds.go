@@ -0,0 +1,211 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Evolution Data Server keeps the calendars GNOME Calendar, Evolution, and
+// the GNOME Shell clock show, including online accounts
+const (
+	edsSourcesService           = "org.gnome.evolution.dataserver.Sources5"
+	edsSourcesPath              = "/org/gnome/evolution/dataserver/SourceManager"
+	edsSourceInterface          = "org.gnome.evolution.dataserver.Source"
+	edsCalendarFactoryPath      = "/org/gnome/evolution/dataserver/CalendarFactory"
+	edsCalendarFactoryInterface = "org.gnome.evolution.dataserver.CalendarFactory"
+	edsCalendarInterface        = "org.gnome.evolution.dataserver.Calendar"
+
+	// edsPersonalCalendar is the calendar EDS creates for every user,
+	// shown as "Personal"; reminders are added to it
+	edsPersonalCalendar = "system-calendar"
+
+	// edsModAll applies a change to every occurrence of an event
+	// (E_CAL_OBJ_MOD_ALL)
+	edsModAll = uint32(1 << 3)
+)
+
+// edsCalendarServices are the calendar factory's bus names, newest first;
+// the version changes with the D-Bus API
+var edsCalendarServices = []string{
+	"org.gnome.evolution.dataserver.Calendar8",
+	"org.gnome.evolution.dataserver.Calendar7",
+}
+
+// reminderLength is how long a reminder's event lasts in the calendar
+const reminderLength = 15 * time.Minute
+
+// edsClient talks to Evolution Data Server on the session bus
+type edsClient struct {
+	conn    *dbus.Conn
+	service string
+}
+
+// edsSource is a calendar EDS knows about
+type edsSource struct {
+	uid  string
+	name string
+}
+
+// connectEDS connects to Evolution Data Server, failing when it is not
+// installed
+func connectEDS() (*edsClient, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, err
+	}
+
+	available := map[string]bool{}
+	for _, method := range []string{"org.freedesktop.DBus.ListNames", "org.freedesktop.DBus.ListActivatableNames"} {
+		var names []string
+		if err := conn.BusObject().Call(method, 0).Store(&names); err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			available[name] = true
+		}
+	}
+	if !available[edsSourcesService] {
+		return nil, fmt.Errorf("evolution data server is not installed")
+	}
+	for _, service := range edsCalendarServices {
+		if available[service] {
+			return &edsClient{conn: conn, service: service}, nil
+		}
+	}
+	return nil, fmt.Errorf("evolution data server has no calendar service this version of lumo knows")
+}
+
+// calendars returns the enabled calendars that are shown in GNOME
+// Calendar
+func (c *edsClient) calendars(ctx context.Context) ([]edsSource, error) {
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	err := c.conn.Object(edsSourcesService, edsSourcesPath).
+		CallWithContext(ctx, "org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&objects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %w", err)
+	}
+
+	var sources []edsSource
+	for _, interfaces := range objects {
+		properties, ok := interfaces[edsSourceInterface]
+		if !ok {
+			continue
+		}
+		uid, _ := properties["UID"].Value().(string)
+		data, _ := properties["Data"].Value().(string)
+		keys := parseKeyFile(data)
+		if _, isCalendar := keys["Calendar"]; !isCalendar || uid == "" {
+			continue
+		}
+		if keys["Data Source"]["Enabled"] == "false" || keys["Calendar"]["Selected"] == "false" {
+			continue
+		}
+		name := keys["Data Source"]["DisplayName"]
+		if name == "" {
+			name = uid
+		}
+		sources = append(sources, edsSource{uid: uid, name: name})
+	}
+	return sources, nil
+}
+
+// open opens a calendar, returning the object to query it through
+func (c *edsClient) open(ctx context.Context, sourceUID string) (dbus.BusObject, error) {
+	var objectPath, busName string
+	err := c.conn.Object(c.service, edsCalendarFactoryPath).
+		CallWithContext(ctx, edsCalendarFactoryInterface+".OpenCalendar", 0, sourceUID).Store(&objectPath, &busName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open calendar %s: %w", sourceUID, err)
+	}
+	calendar := c.conn.Object(busName, dbus.ObjectPath(objectPath))
+	if call := calendar.CallWithContext(ctx, edsCalendarInterface+".Open", 0); call.Err != nil {
+		return nil, fmt.Errorf("failed to open calendar %s: %w", sourceUID, call.Err)
+	}
+	return calendar, nil
+}
+
+// close lets EDS free a calendar opened with open
+func (c *edsClient) close(calendar dbus.BusObject) {
+	calendar.Call(edsCalendarInterface+".Close", 0)
+}
+
+// events returns the events of an open calendar that happen between from
+// and to. Repeating events are returned once, as written.
+func (c *edsClient) events(ctx context.Context, calendar dbus.BusObject, from, to time.Time) ([]Event, error) {
+	query := fmt.Sprintf(`(occur-in-time-range? (make-time "%s") (make-time "%s"))`,
+		from.UTC().Format(icalTimeLayout), to.UTC().Format(icalTimeLayout))
+	var objects []string
+	if err := calendar.CallWithContext(ctx, edsCalendarInterface+".GetObjectList", 0, query).Store(&objects); err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, object := range objects {
+		events = append(events, parseEvents(object)...)
+	}
+	return events, nil
+}
+
+// createEvent adds an event to a calendar and returns its UID
+func (c *edsClient) createEvent(ctx context.Context, sourceUID, uid, summary string, start time.Time, length time.Duration) (string, error) {
+	calendar, err := c.open(ctx, sourceUID)
+	if err != nil {
+		return "", err
+	}
+	defer c.close(calendar)
+
+	objects := []string{formatEvent(uid, summary, start, length, time.Now())}
+	var uids []string
+	err = calendar.CallWithContext(ctx, edsCalendarInterface+".CreateObjects", 0, objects, uint32(0)).Store(&uids)
+	if err != nil {
+		// Before Calendar8 the method took no flags
+		err = calendar.CallWithContext(ctx, edsCalendarInterface+".CreateObjects", 0, objects).Store(&uids)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to add the event: %w", err)
+	}
+	if len(uids) > 0 && uids[0] != "" {
+		return uids[0], nil
+	}
+	return uid, nil
+}
+
+// removeEvent deletes an event, with every occurrence, from a calendar
+func (c *edsClient) removeEvent(ctx context.Context, sourceUID, uid string) error {
+	calendar, err := c.open(ctx, sourceUID)
+	if err != nil {
+		return err
+	}
+	defer c.close(calendar)
+
+	ids := []struct{ UID, RID string }{{UID: uid}}
+	call := calendar.CallWithContext(ctx, edsCalendarInterface+".RemoveObjects", 0, ids, edsModAll, uint32(0))
+	if call.Err != nil {
+		call = calendar.CallWithContext(ctx, edsCalendarInterface+".RemoveObjects", 0, ids, edsModAll)
+	}
+	return call.Err
+}
+
+// parseKeyFile reads the key file EDS describes a source with into its
+// groups
+func parseKeyFile(data string) map[string]map[string]string {
+	groups := map[string]map[string]string{}
+	var group map[string]string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			group = map[string]string{}
+			groups[line[1:len(line)-1]] = group
+		case group != nil:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				group[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return groups
+}
@@ -0,0 +1,158 @@
+package calendar
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icalTimeLayout is how iCalendar writes times in UTC
+const icalTimeLayout = "20060102T150405Z"
+
+// icalDurationPattern matches iCalendar durations such as PT1H30M or P1D
+var icalDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// icalEscaper escapes text values in iCalendar
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icalUnescaper reverses icalEscaper
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// formatEvent writes an event as an iCalendar VEVENT
+func formatEvent(uid, summary string, start time.Time, length time.Duration, now time.Time) string {
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + now.UTC().Format(icalTimeLayout),
+		"DTSTART:" + start.UTC().Format(icalTimeLayout),
+		"DTEND:" + start.Add(length).UTC().Format(icalTimeLayout),
+		"SUMMARY:" + icalEscaper.Replace(summary),
+		"END:VEVENT",
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// parseEvents reads the events in iCalendar text, whether whole calendars
+// or single VEVENTs. Alarms and other components inside events are
+// skipped.
+func parseEvents(ics string) []Event {
+	// Long lines are folded onto lines starting with a space or a tab
+	ics = strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(ics)
+
+	var events []Event
+	var event *Event
+	var end time.Time
+	var duration time.Duration
+	depth := 0
+	for _, line := range strings.Split(ics, "\n") {
+		line = strings.TrimRight(line, "\r")
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(name, ";")
+		name = strings.ToUpper(name)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && event == nil:
+			event, end, duration, depth = &Event{}, time.Time{}, 0, 0
+			continue
+		case event == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			switch {
+			case !end.IsZero():
+				event.End = end
+			case duration > 0:
+				event.End = event.Start.Add(duration)
+			case event.AllDay:
+				event.End = event.Start.AddDate(0, 0, 1)
+			default:
+				event.End = event.Start
+			}
+			if !event.Start.IsZero() {
+				events = append(events, *event)
+			}
+			event = nil
+			continue
+		case depth > 0:
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.UID = value
+		case "SUMMARY":
+			event.Summary = icalUnescaper.Replace(value)
+		case "DTSTART":
+			event.Start, event.AllDay = parseICalTime(value, params)
+		case "DTEND":
+			end, _ = parseICalTime(value, params)
+		case "DURATION":
+			duration = parseICalDuration(value)
+		case "RRULE", "RDATE":
+			event.Repeats = true
+		}
+	}
+	return events
+}
+
+// parseICalTime reads a DATE or DATE-TIME value, with its TZID if it has
+// one, and reports whether it is a date without a time
+func parseICalTime(value, params string) (time.Time, bool) {
+	location := time.Local
+	allDay := false
+	for _, param := range strings.Split(params, ";") {
+		key, paramValue, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(key) {
+		case "TZID":
+			if loc, err := time.LoadLocation(strings.Trim(paramValue, `"`)); err == nil {
+				location = loc
+			}
+		case "VALUE":
+			allDay = strings.EqualFold(paramValue, "DATE")
+		}
+	}
+
+	if allDay || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalTimeLayout, value)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t.Local(), false
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.Local(), false
+}
+
+// parseICalDuration reads a DURATION value; negative ones are 0
+func parseICalDuration(value string) time.Duration {
+	m := icalDurationPattern.FindStringSubmatch(strings.ToUpper(value))
+	if m == nil || m[1] == "-" {
+		return 0
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if n, err := strconv.Atoi(m[i+2]); err == nil {
+			d += time.Duration(n) * unit
+		}
+	}
+	return d
+}
@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEvents(t *testing.T) {
+	ics := "BEGIN:VEVENT\r\n" +
+		"UID:standup@example.com\r\n" +
+		"DTSTART;TZID=UTC:20261016T093000\r\n" +
+		"DURATION:PT15M\r\n" +
+		"SUMMARY:Standup\\, daily\r\n" +
+		"RRULE:FREQ=DAILY\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"SUMMARY:Not the event\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n"
+	events := parseEvents(ics)
+	if len(events) != 1 {
+		t.Fatalf("Expected one event, got %+v", events)
+	}
+	event := events[0]
+	start := time.Date(2026, time.October, 16, 9, 30, 0, 0, time.UTC)
+	if event.UID != "standup@example.com" || event.Summary != "Standup, daily" || !event.Repeats ||
+		!event.Start.Equal(start) || !event.End.Equal(start.Add(15*time.Minute)) {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	holiday := parseEvents("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:h\nDTSTART;VALUE=DATE:20261026\nSUMMARY:Long\n  weekend\nEND:VEVENT\nEND:VCALENDAR\n")
+	if len(holiday) != 1 || !holiday[0].AllDay || holiday[0].Summary != "Long weekend" ||
+		!holiday[0].End.Equal(holiday[0].Start.AddDate(0, 0, 1)) {
+		t.Errorf("Unexpected all-day event: %+v", holiday)
+	}
+}
+
+func TestFormatEventRoundTrip(t *testing.T) {
+	start := time.Date(2026, time.October, 17, 18, 0, 0, 0, time.Local)
+	events := parseEvents(formatEvent("lumo-1@lumo", "call mom; bring cake", start, reminderLength, time.Now()))
+	if len(events) != 1 || events[0].Summary != "call mom; bring cake" || !events[0].Start.Equal(start) ||
+		!events[0].End.Equal(start.Add(reminderLength)) {
+		t.Errorf("Unexpected event: %+v", events)
+	}
+}
+
+func TestParseKeyFile(t *testing.T) {
+	keys := parseKeyFile("[Data Source]\nDisplayName=Work\nEnabled=true\n\n[Calendar]\nBackendName=caldav\nSelected=false\n")
+	if keys["Data Source"]["DisplayName"] != "Work" || keys["Calendar"]["Selected"] != "false" {
+		t.Errorf("Unexpected key file: %+v", keys)
+	}
+}
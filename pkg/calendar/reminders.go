@@ -0,0 +1,188 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// keepNotified is how long reminders that went off stay in the store, so
+// the agenda still shows them
+const keepNotified = 7 * 24 * time.Hour
+
+// Reminder is a note the daemon shows as a desktop notification when it is
+// due
+type Reminder struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	At        time.Time `json:"at"`
+	CreatedAt time.Time `json:"created_at"`
+	// Notified is set once the notification was shown
+	Notified bool `json:"notified,omitempty"`
+	// EventUID is the reminder's event in the desktop calendar, if it was
+	// added to one
+	EventUID string `json:"event_uid,omitempty"`
+}
+
+// Path returns the file reminders are kept in
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "reminders.json"), nil
+}
+
+// Reminders returns every reminder, soonest first
+func Reminders() ([]Reminder, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminders: %w", err)
+	}
+	var reminders []Reminder
+	if err := json.Unmarshal(data, &reminders); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	sort.SliceStable(reminders, func(i, j int) bool {
+		return reminders[i].At.Before(reminders[j].At)
+	})
+	return reminders, nil
+}
+
+// Pending returns the reminders that have not gone off yet, soonest first
+func Pending() ([]Reminder, error) {
+	reminders, err := Reminders()
+	if err != nil {
+		return nil, err
+	}
+	pending := reminders[:0]
+	for _, r := range reminders {
+		if !r.Notified {
+			pending = append(pending, r)
+		}
+	}
+	return pending, nil
+}
+
+// Add saves a reminder, and adds it to the desktop calendar too when
+// Evolution Data Server is running, so it shows in GNOME Calendar. The
+// reminder is kept in lumo's own store either way, for the daemon to
+// deliver.
+func Add(ctx context.Context, text string, at time.Time) (*Reminder, error) {
+	reminders, err := Reminders()
+	if err != nil {
+		return nil, err
+	}
+	id := 1
+	for _, r := range reminders {
+		id = max(id, r.ID+1)
+	}
+	reminder := Reminder{ID: id, Text: text, At: at, CreatedAt: time.Now()}
+
+	if eds, err := connectEDS(); err == nil {
+		if uid, err := eds.createEvent(ctx, edsPersonalCalendar, reminderUID(reminder), text, at, reminderLength); err == nil {
+			reminder.EventUID = uid
+		}
+	}
+
+	if err := write(append(reminders, reminder)); err != nil {
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// Remove deletes a reminder, and its event from the desktop calendar
+func Remove(ctx context.Context, id int) (*Reminder, error) {
+	reminders, err := Reminders()
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range reminders {
+		if r.ID != id {
+			continue
+		}
+		if r.EventUID != "" {
+			if eds, err := connectEDS(); err == nil {
+				eds.removeEvent(ctx, edsPersonalCalendar, r.EventUID)
+			}
+		}
+		return &r, write(append(reminders[:i], reminders[i+1:]...))
+	}
+	return nil, fmt.Errorf("no reminder with ID %d", id)
+}
+
+// Due returns the reminders that should have gone off by now but have not
+func Due(now time.Time) ([]Reminder, error) {
+	reminders, err := Pending()
+	if err != nil {
+		return nil, err
+	}
+	var due []Reminder
+	for _, r := range reminders {
+		if !r.At.After(now) {
+			due = append(due, r)
+		}
+	}
+	return due, nil
+}
+
+// MarkNotified records that a reminder went off
+func MarkNotified(id int) error {
+	reminders, err := Reminders()
+	if err != nil {
+		return err
+	}
+	for i := range reminders {
+		if reminders[i].ID == id {
+			reminders[i].Notified = true
+			return write(reminders)
+		}
+	}
+	return fmt.Errorf("no reminder with ID %d", id)
+}
+
+// write stores the reminders, dropping those that went off a while ago
+func write(reminders []Reminder) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	kept := make([]Reminder, 0, len(reminders))
+	for _, r := range reminders {
+		if !r.Notified || time.Since(r.At) < keepNotified {
+			kept = append(kept, r)
+		}
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reminders: %w", err)
+	}
+	// Written whole and renamed, as the daemon reads the file too
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write reminders: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// reminderUID returns the UID of a reminder's event in the desktop calendar
+func reminderUID(r Reminder) string {
+	return fmt.Sprintf("lumo-reminder-%d-%d@lumo", r.CreatedAt.Unix(), r.ID)
+}
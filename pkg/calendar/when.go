@@ -0,0 +1,281 @@
+package calendar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Times of day that words stand for, and the time a reminder set for a day
+// without a time goes off
+const (
+	defaultHour   = 9
+	morningHour   = 9
+	afternoonHour = 15
+	eveningHour   = 18
+	tonightHour   = 20
+)
+
+var (
+	// clockPattern matches times such as 6pm, 6:30pm, 18:00, and 6.30 am
+	clockPattern = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm|a\.m\.|p\.m\.)?$`)
+	// durationPattern matches compact durations such as 10m, 2h, and 1d
+	durationPattern = regexp.MustCompile(`^(\d+)(m|min|mins|h|hr|hrs|d)$`)
+)
+
+// connectors are words that only join a time to the rest of the sentence,
+// as in "at 6pm" or "on friday", and are dropped with it
+var connectors = map[string]bool{"at": true, "on": true, "by": true, "next": true, "this": true}
+
+// units are the words "in <n> <unit>" understands
+var units = map[string]time.Duration{
+	"minute": time.Minute, "minutes": time.Minute, "min": time.Minute, "mins": time.Minute,
+	"hour": time.Hour, "hours": time.Hour, "hr": time.Hour, "hrs": time.Hour,
+	"day": 24 * time.Hour, "days": 24 * time.Hour,
+	"week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// when collects what a reminder's words say about its time
+type when struct {
+	day    time.Time
+	hasDay bool
+	hour   int
+	minute int
+	clock  bool
+	offset time.Duration
+	// hint is a time of day from a word such as "tonight", used when no
+	// clock time is given
+	hint int
+}
+
+// ParseReminder splits what to be reminded of from when, as in "call mom
+// tomorrow 6pm", "stand up in 45 minutes", or "pay rent on friday". A day
+// without a time is at 9:00, and a time without a day is the next time it
+// comes around.
+func ParseReminder(input string, now time.Time) (string, time.Time, error) {
+	words := strings.Fields(input)
+	// "remind me to call mom" reads as the reminder "call mom"
+	if len(words) > 0 && strings.EqualFold(words[0], "me") {
+		words = words[1:]
+		if len(words) > 0 && strings.EqualFold(words[0], "to") {
+			words = words[1:]
+		}
+	}
+
+	var w when
+	var text []string
+	matched := false
+	for i := 0; i < len(words); {
+		n := w.match(words[i:], now)
+		// After "at", a bare hour is a time, as in "at 7"
+		if n == 0 && strings.EqualFold(words[i], "at") && i+1 < len(words) {
+			if hour, err := strconv.Atoi(strings.Trim(words[i+1], ".,;!?")); err == nil && w.matchClock(fmt.Sprintf("%d:00", hour)) {
+				n = 2
+			}
+		}
+		if n == 0 && connectors[strings.ToLower(words[i])] && i+1 < len(words) {
+			if n = w.match(words[i+1:], now); n > 0 {
+				n++
+			}
+		}
+		if n > 0 {
+			matched = true
+			i += n
+			continue
+		}
+		text = append(text, words[i])
+		i++
+	}
+
+	what := strings.TrimSpace(strings.Trim(strings.Join(text, " "), ",;"))
+	if what == "" {
+		return "", time.Time{}, fmt.Errorf("what should the reminder say? Try: lumo remind \"call mom tomorrow 6pm\"")
+	}
+	if !matched {
+		return "", time.Time{}, fmt.Errorf("when should the reminder go off? Add a time such as 'tomorrow 6pm' or 'in 20 minutes'")
+	}
+
+	at := w.resolve(now)
+	if !at.After(now) {
+		return "", time.Time{}, fmt.Errorf("%s is in the past", at.Format("Mon Jan 2 15:04"))
+	}
+	return what, at, nil
+}
+
+// match reads a time expression at the start of words, returning how many
+// words it used, or 0 if there is none
+func (w *when) match(words []string, now time.Time) int {
+	word := strings.ToLower(strings.Trim(words[0], ".,;!?"))
+	next := ""
+	if len(words) > 1 {
+		next = strings.ToLower(strings.Trim(words[1], ".,;!?"))
+	}
+
+	// in 20 minutes, in an hour, in 2h
+	if word == "in" && next != "" {
+		if m := durationPattern.FindStringSubmatch(next); m != nil {
+			w.offset += compactDuration(m)
+			return 2
+		}
+		if len(words) > 2 {
+			unit, ok := units[strings.ToLower(strings.Trim(words[2], ".,;!?"))]
+			if !ok {
+				return 0
+			}
+			count, err := strconv.Atoi(next)
+			if next == "a" || next == "an" {
+				count, err = 1, nil
+			}
+			if err != nil || count < 0 {
+				return 0
+			}
+			w.offset += time.Duration(count) * unit
+			return 3
+		}
+		return 0
+	}
+
+	if day, ok := ParseDay(word, now); ok {
+		w.day, w.hasDay = day, true
+		return 1
+	}
+
+	switch word {
+	case "tonight":
+		w.day, w.hasDay, w.hint = startOfDay(now), true, tonightHour
+		return 1
+	case "morning":
+		w.hint = morningHour
+		return 1
+	case "afternoon":
+		w.hint = afternoonHour
+		return 1
+	case "evening":
+		w.hint = eveningHour
+		return 1
+	case "noon", "midday":
+		w.hour, w.minute, w.clock = 12, 0, true
+		return 1
+	case "midnight":
+		w.hour, w.minute, w.clock = 0, 0, true
+		return 1
+	}
+
+	// 6 pm, written as two words
+	if next == "am" || next == "pm" {
+		if w.matchClock(word + next) {
+			return 2
+		}
+	}
+	if w.matchClock(word) {
+		return 1
+	}
+	return 0
+}
+
+// matchClock reads a clock time such as 6pm or 18:30. A bare number is
+// not a time, so "buy 2 apples" keeps its 2.
+func (w *when) matchClock(word string) bool {
+	m := clockPattern.FindStringSubmatch(word)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch strings.ReplaceAll(m[3], ".", "") {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return false
+		}
+		hour %= 12
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return false
+		}
+		hour = hour%12 + 12
+	}
+	if hour > 23 || minute > 59 {
+		return false
+	}
+	w.hour, w.minute, w.clock = hour, minute, true
+	return true
+}
+
+// resolve works out the time a reminder goes off
+func (w *when) resolve(now time.Time) time.Time {
+	if w.offset > 0 && !w.hasDay && !w.clock {
+		return now.Add(w.offset).Truncate(time.Minute)
+	}
+
+	hour, minute := defaultHour, 0
+	switch {
+	case w.clock:
+		hour, minute = w.hour, w.minute
+		// "6 tonight" and "8 in the evening" mean the afternoon hours
+		if w.hint >= afternoonHour && hour < 12 {
+			hour += 12
+		}
+	case w.hint > 0:
+		hour = w.hint
+	}
+
+	day := startOfDay(now)
+	if w.hasDay {
+		day = w.day
+	}
+	at := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location()).Add(w.offset)
+	if !w.hasDay && !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// ParseDay reads a day: today, tomorrow, yesterday, a weekday (the next
+// one to come), or a date as YYYY-MM-DD
+func ParseDay(word string, now time.Time) (time.Time, bool) {
+	today := startOfDay(now)
+	switch strings.ToLower(word) {
+	case "today":
+		return today, true
+	case "tomorrow", "tmrw", "tmr":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if strings.EqualFold(word, name) || strings.EqualFold(word, name[:3]) {
+			days := (int(weekday) - int(now.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), true
+		}
+	}
+	if day, err := time.ParseInLocation("2006-01-02", word, now.Location()); err == nil {
+		return day, true
+	}
+	return time.Time{}, false
+}
+
+// compactDuration turns a match of durationPattern into a duration
+func compactDuration(m []string) time.Duration {
+	count, _ := strconv.Atoi(m[1])
+	switch m[2] {
+	case "h", "hr", "hrs":
+		return time.Duration(count) * time.Hour
+	case "d":
+		return time.Duration(count) * 24 * time.Hour
+	}
+	return time.Duration(count) * time.Minute
+}
+
+// startOfDay returns midnight at the start of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
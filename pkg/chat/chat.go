@@ -19,16 +19,21 @@ const (
 
 // Message represents a single message in a conversation
 type Message struct {
-	Role      MessageRole
-	Content   string
-	Timestamp time.Time
+	Role      MessageRole `json:"role"`
+	Content   string      `json:"content"`
+	Timestamp time.Time   `json:"timestamp"`
 }
 
 // Conversation represents a chat conversation with history
 type Conversation struct {
-	ID       string
-	Messages []Message
-	MaxSize  int
+	ID string `json:"id"`
+	// Title and Tags describe the conversation in chat:history; they are
+	// generated after the first answer, and the title can be renamed
+	Title     string    `json:"title,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Messages  []Message `json:"messages"`
+	MaxSize   int       `json:"max_size"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // NewConversation creates a new conversation with the given system message
@@ -65,6 +70,18 @@ func (c *Conversation) AddSystemMessage(content string) {
 	c.trimIfNeeded()
 }
 
+// SetInstructions replaces the conversation's first system message, which
+// holds its instructions, keeping the rest of the history
+func (c *Conversation) SetInstructions(content string) {
+	for i, msg := range c.Messages {
+		if msg.Role == RoleSystem {
+			c.Messages[i].Content = content
+			return
+		}
+	}
+	c.Messages = append([]Message{{Role: RoleSystem, Content: content, Timestamp: time.Now()}}, c.Messages...)
+}
+
 // AddUserMessage adds a user message to the conversation
 func (c *Conversation) AddUserMessage(content string) {
 	c.Messages = append(c.Messages, Message{
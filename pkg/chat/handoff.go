@@ -0,0 +1,87 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxHandoffMessages is how many of the latest messages are read to work out
+// the task a conversation arrived at
+const maxHandoffMessages = 20
+
+// TaskRunner hands a task to the agent, along with the background from the
+// conversation that led to it, and returns the agent's report
+type TaskRunner func(ctx context.Context, task, background string) (string, error)
+
+// Handoff is the task a conversation concluded with, ready for the agent
+type Handoff struct {
+	// Task is the instruction for the agent
+	Task string `json:"task"`
+	// Context holds the details from the conversation the plan needs, such
+	// as paths, versions, and choices the user made
+	Context string `json:"context"`
+}
+
+// Handoff asks the AI to turn the conclusion of a conversation into a task
+// for the agent. A note from the user, if given, adjusts the task.
+func (m *Manager) Handoff(ctx context.Context, conv *Conversation, note string) (*Handoff, error) {
+	var transcript strings.Builder
+	messages := conv.GetMessages()
+	if len(messages) > maxHandoffMessages {
+		messages = messages[len(messages)-maxHandoffMessages:]
+	}
+	for _, msg := range messages {
+		if msg.Role == RoleSystem {
+			continue
+		}
+		transcript.WriteString(fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content))
+	}
+	if transcript.Len() == 0 {
+		return nil, fmt.Errorf("the conversation is empty; discuss the task first")
+	}
+
+	if note != "" {
+		note = fmt.Sprintf("\nThe user added this about the task: %s\n", note)
+	}
+
+	prompt := fmt.Sprintf(`
+The following is a conversation between a user and Lumo, a command-line assistant:
+
+%s
+The user now wants Lumo's agent, which plans and runs shell commands, to carry
+out the approach the conversation settled on.
+%s
+Respond with a JSON object with the following structure:
+{
+  "task": "one or two sentences telling the agent what to do",
+  "context": "the details from the conversation the agent needs, such as file paths, versions, names, commands that were suggested, and choices the user made; empty if there are none"
+}
+
+Write the task as an instruction, not a question. Use only what the
+conversation decided; do not add steps it did not discuss.
+Do not include any text before or after the JSON object.
+`, transcript.String(), note)
+
+	response, err := m.aiClient.GetCompletion(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI completion: %w", err)
+	}
+
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("failed to extract JSON from AI response")
+	}
+	var handoff Handoff
+	if err := json.Unmarshal([]byte(response[start:end+1]), &handoff); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+	handoff.Task = strings.TrimSpace(handoff.Task)
+	handoff.Context = strings.TrimSpace(handoff.Context)
+	if handoff.Task == "" {
+		return nil, fmt.Errorf("no task could be worked out from the conversation")
+	}
+	return &handoff, nil
+}
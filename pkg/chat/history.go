@@ -0,0 +1,285 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/notes"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// maxTitleLength is the longest a conversation title may be
+const maxTitleLength = 60
+
+// maxTitleTranscript is how much of a conversation is sent to generate its
+// title, which keeps the request cheap
+const maxTitleTranscript = 1500
+
+// maxTags is how many topic tags a conversation gets
+const maxTags = 4
+
+// validConversationID matches the IDs conversations are saved under
+var validConversationID = regexp.MustCompile(`^conv_[0-9]+$`)
+
+// historyDir returns the directory conversations are saved in
+func historyDir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "chats"), nil
+}
+
+// SetHistory sets whether conversations are saved for chat:history after
+// every answer, and whether a title and tags are generated for them
+func (m *Manager) SetHistory(save, autoTitle bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saveHistory = save
+	m.autoTitle = autoTitle
+}
+
+// recordConversation saves conv after an answer, titling it first if it
+// has no title yet. Saving is a convenience, so failures are only warned
+// about.
+func (m *Manager) recordConversation(ctx context.Context, conv *Conversation) {
+	m.mu.Lock()
+	save, autoTitle := m.saveHistory, m.autoTitle
+	m.mu.Unlock()
+	if !save {
+		return
+	}
+	if autoTitle && conv.Title == "" {
+		if title, tags, err := m.generateTitle(ctx, conv); err == nil {
+			conv.Title, conv.Tags = title, tags
+		}
+	}
+	if err := SaveConversation(conv); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save the conversation: %v\n", err)
+	}
+}
+
+// generateTitle asks the AI for a short title and topic tags for the start
+// of a conversation
+func (m *Manager) generateTitle(ctx context.Context, conv *Conversation) (string, []string, error) {
+	var transcript strings.Builder
+	for _, msg := range conv.GetMessages() {
+		if msg.Role == RoleSystem {
+			continue
+		}
+		transcript.WriteString(fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content))
+		if transcript.Len() > maxTitleTranscript {
+			break
+		}
+	}
+	text := transcript.String()
+	if len(text) > maxTitleTranscript {
+		text = text[:maxTitleTranscript]
+	}
+
+	prompt := fmt.Sprintf(`
+Give this conversation a title and topic tags:
+
+%s
+Respond with a JSON object with the following structure:
+{
+  "title": "a title of at most six words, without quotes or a final period",
+  "tags": ["one to %d short lowercase topic tags, such as docker or networking"]
+}
+
+Do not include any text before or after the JSON object.
+`, text, maxTags)
+
+	response, err := m.aiClient.GetCompletion(ctx, prompt)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get AI completion: %w", err)
+	}
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return "", nil, fmt.Errorf("failed to extract JSON from AI response")
+	}
+	var answer struct {
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &answer); err != nil {
+		return "", nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+	title := cleanTitle(answer.Title)
+	if title == "" {
+		return "", nil, fmt.Errorf("no title in AI response")
+	}
+	tags := notes.NormalizeTags(answer.Tags)
+	for i, tag := range tags {
+		tags[i] = strings.Join(strings.Fields(tag), "-")
+	}
+	if len(tags) > maxTags {
+		tags = tags[:maxTags]
+	}
+	return title, tags, nil
+}
+
+// cleanTitle puts a title on one line, without surrounding quotes, and
+// shortens it to maxTitleLength
+func cleanTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	title = strings.TrimRight(strings.Trim(title, `"'`), ".")
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = strings.TrimSpace(string(runes[:maxTitleLength-3])) + "..."
+	}
+	return title
+}
+
+// SaveConversation writes conv to the chat history
+func SaveConversation(conv *Conversation) error {
+	conv.UpdatedAt = time.Now()
+	return writeConversation(conv)
+}
+
+// writeConversation writes conv to the chat history as it is
+func writeConversation(conv *Conversation) error {
+	if !validConversationID.MatchString(conv.ID) {
+		return fmt.Errorf("invalid conversation ID %q", conv.ID)
+	}
+	dir, err := historyDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create chat history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(conv, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Conversations can hold anything the user typed, so only they may read them
+	return os.WriteFile(filepath.Join(dir, conv.ID+".json"), data, 0600)
+}
+
+// SavedConversations returns the conversations in the chat history, most
+// recently updated first
+func SavedConversations() ([]*Conversation, error) {
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "conv_*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var convs []*Conversation
+	for _, path := range files {
+		conv, err := readConversation(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", filepath.Base(path), err)
+			continue
+		}
+		convs = append(convs, conv)
+	}
+	sort.Slice(convs, func(i, j int) bool {
+		return convs[i].UpdatedAt.After(convs[j].UpdatedAt)
+	})
+	return convs, nil
+}
+
+// LoadConversation returns a conversation from the chat history by its ID,
+// or by its number in SavedConversations, counting from 1
+func LoadConversation(ref string) (*Conversation, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		convs, err := SavedConversations()
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 || n > len(convs) {
+			return nil, fmt.Errorf("no conversation number %d; see 'lumo chat:history list'", n)
+		}
+		return convs[n-1], nil
+	}
+
+	if !validConversationID.MatchString(ref) {
+		return nil, fmt.Errorf("no conversation %q; see 'lumo chat:history list'", ref)
+	}
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	conv, err := readConversation(filepath.Join(dir, ref+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no conversation %q; see 'lumo chat:history list'", ref)
+	}
+	return conv, err
+}
+
+// RenameConversation sets the title of a conversation in the chat history,
+// which keeps it from being generated again, and returns the conversation
+func RenameConversation(ref, title string) (*Conversation, error) {
+	title = cleanTitle(title)
+	if title == "" {
+		return nil, fmt.Errorf("the title is empty")
+	}
+	conv, err := LoadConversation(ref)
+	if err != nil {
+		return nil, err
+	}
+	conv.Title = title
+	// Renaming does not count as an update, so the history keeps its order
+	return conv, writeConversation(conv)
+}
+
+// DeleteSavedConversation removes a conversation from the chat history
+func DeleteSavedConversation(ref string) (*Conversation, error) {
+	conv, err := LoadConversation(ref)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	return conv, os.Remove(filepath.Join(dir, conv.ID+".json"))
+}
+
+// readConversation reads a saved conversation
+func readConversation(path string) (*Conversation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// Preview returns the start of the conversation's first question, for
+// conversations without a title
+func (c *Conversation) Preview() string {
+	for _, msg := range c.Messages {
+		if msg.Role == RoleUser {
+			return cleanTitle(msg.Content)
+		}
+	}
+	return ""
+}
+
+// HasTag reports whether the conversation is tagged with tag
+func (c *Conversation) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	for _, t := range c.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
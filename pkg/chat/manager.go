@@ -16,6 +16,11 @@ type Manager struct {
 	maxMessagesPerConv int
 	mu                 sync.Mutex
 	aiClient           ai.Client
+	// instructions are the system instructions of new conversations
+	instructions string
+	// saveHistory and autoTitle are set with SetHistory
+	saveHistory bool
+	autoTitle   bool
 }
 
 // NewManager creates a new chat manager
@@ -33,16 +38,25 @@ func NewManager(aiClient ai.Client, maxConversations, maxMessagesPerConv int) *M
 		maxConversations:   maxConversations,
 		maxMessagesPerConv: maxMessagesPerConv,
 		aiClient:           aiClient,
+		instructions:       ai.ChatInstructions,
 	}
 }
 
+// SetInstructions sets the system instructions of conversations started
+// from now on, such as a persona's
+func (m *Manager) SetInstructions(instructions string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instructions = instructions
+}
+
 // StartNewConversation starts a new conversation and makes it active
 func (m *Manager) StartNewConversation() *Conversation {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Create a new conversation with the chat system instructions
-	conv := NewConversation(ai.ChatInstructions, m.maxMessagesPerConv)
+	conv := NewConversation(m.instructions, m.maxMessagesPerConv)
 
 	// Add the conversation to the map
 	m.conversations[conv.ID] = conv
@@ -56,6 +70,21 @@ func (m *Manager) StartNewConversation() *Conversation {
 	return conv
 }
 
+// ResumeConversation makes a conversation from the chat history the active
+// one, so its messages are sent with the next ones
+func (m *Manager) ResumeConversation(conv *Conversation) *Conversation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if conv.MaxSize <= 0 {
+		conv.MaxSize = m.maxMessagesPerConv
+	}
+	m.conversations[conv.ID] = conv
+	m.activeConversation = conv.ID
+	m.trimConversationsIfNeeded()
+	return conv
+}
+
 // GetActiveConversation returns the active conversation
 // If there is no active conversation, it creates a new one
 func (m *Manager) GetActiveConversation() *Conversation {
@@ -64,7 +93,7 @@ func (m *Manager) GetActiveConversation() *Conversation {
 
 	// If there is no active conversation or it doesn't exist, create a new one
 	if m.activeConversation == "" || m.conversations[m.activeConversation] == nil {
-		conv := NewConversation(ai.ChatInstructions, m.maxMessagesPerConv)
+		conv := NewConversation(m.instructions, m.maxMessagesPerConv)
 		m.conversations[conv.ID] = conv
 		m.activeConversation = conv.ID
 	}
@@ -157,6 +186,7 @@ func (m *Manager) ProcessMessage(ctx context.Context, message string) (string, e
 
 	// Add the assistant response to the conversation
 	conv.AddAssistantMessage(response)
+	m.recordConversation(ctx, conv)
 
 	return response, nil
 }
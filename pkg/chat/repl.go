@@ -21,6 +21,10 @@ type REPL struct {
 	aiClient   ai.Client
 	ctx        context.Context
 	cancelFunc context.CancelFunc
+	// taskRunner runs /task handoffs; without it /task is unavailable
+	taskRunner TaskRunner
+	// persona is the persona this session talks to, or "" for the default
+	persona string
 }
 
 // NewREPL creates a new REPL instance
@@ -33,9 +37,15 @@ func NewREPL(cfg *config.Config, manager *Manager, aiClient ai.Client) *REPL {
 		aiClient:   aiClient,
 		ctx:        ctx,
 		cancelFunc: cancel,
+		persona:    cfg.DefaultPersona,
 	}
 }
 
+// SetTaskRunner sets how /task hands the conversation's conclusion to the agent
+func (r *REPL) SetTaskRunner(runner TaskRunner) {
+	r.taskRunner = runner
+}
+
 // Start starts the REPL loop
 func (r *REPL) Start() (string, error) {
 	// Display welcome message
@@ -122,6 +132,43 @@ func (r *REPL) Start() (string, error) {
 				fmt.Printf("Error: Conversation %s not found.\n", args)
 			}
 
+		case "rename":
+			// Rename the current conversation in the chat history
+			if args == "" {
+				fmt.Println("Error: Title required.")
+				continue
+			}
+			conv.Title = cleanTitle(args)
+			if r.config.SaveChatHistory && conv.Preview() != "" {
+				if err := writeConversation(conv); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					continue
+				}
+			}
+			fmt.Printf("Renamed this conversation to %q.\n", conv.Title)
+
+		case "resume":
+			// Continue a conversation from the chat history
+			saved, err := LoadConversation(args)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			conv = r.manager.ResumeConversation(saved)
+			title := conv.Title
+			if title == "" {
+				title = conv.Preview()
+			}
+			fmt.Printf("Resumed %q.\n", title)
+
+		case "persona":
+			// Talk to a different persona for the rest of the session
+			r.switchPersona(conv, args)
+
+		case "/task":
+			// Hand what the conversation settled on to the agent
+			r.handOffTask(conv, args)
+
 		default:
 			// Treat as a message to the AI
 			fmt.Println(ai.ThinkingIndicator)
@@ -162,9 +209,102 @@ func (r *REPL) displayHelp() {
 	fmt.Println("  list                 - List all conversations")
 	fmt.Println("  switch <id>          - Switch to another conversation")
 	fmt.Println("  delete <id>          - Delete a conversation")
+	fmt.Println("  rename <title>       - Rename this conversation in the chat history")
+	fmt.Println("  resume <n|id>        - Continue a conversation from 'lumo chat:history'")
+	fmt.Println("  persona [name|off]   - Show or switch the persona for this session")
+	fmt.Println("  /task [note]         - Have the agent carry out what the conversation settled on")
 	fmt.Println("  exit, quit           - Exit chat mode")
 }
 
+// switchPersona shows the session's persona, or switches the conversation
+// and the ones started after it to another persona's instructions
+func (r *REPL) switchPersona(conv *Conversation, name string) {
+	if name == "" {
+		current := r.persona
+		if current == "" {
+			current = "none (Lumo's chat instructions)"
+		}
+		fmt.Printf("Persona: %s\nAvailable: %s\n", current, strings.Join(ai.PersonaNames(r.config), ", "))
+		return
+	}
+
+	instructions := ai.ChatInstructions
+	if name == "off" || name == "none" {
+		name = ""
+	} else {
+		var ok bool
+		if instructions, ok = ai.Persona(r.config, name); !ok {
+			fmt.Printf("Error: Unknown persona %s. Available: %s\n", name, strings.Join(ai.PersonaNames(r.config), ", "))
+			return
+		}
+	}
+
+	r.persona = name
+	r.manager.SetInstructions(instructions)
+	conv.SetInstructions(instructions)
+	if name == "" {
+		fmt.Println("Persona off; using Lumo's chat instructions.")
+	} else {
+		fmt.Printf("Talking to the %s persona for the rest of this session.\n", name)
+	}
+}
+
+// handOffTask works out the task the conversation concluded with and, once
+// the user confirms or edits it, runs it with the agent. The agent's report
+// is added to the conversation so the discussion can continue from it.
+func (r *REPL) handOffTask(conv *Conversation, note string) {
+	if r.taskRunner == nil {
+		fmt.Println("Error: Agent mode is not available.")
+		return
+	}
+
+	fmt.Println(ai.ThinkingIndicator)
+	handoff, err := r.manager.Handoff(r.ctx, conv, note)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	for {
+		fmt.Printf("\n🤖 Agent task: %s\n", handoff.Task)
+		if handoff.Context != "" {
+			fmt.Printf("   Context: %s\n", handoff.Context)
+		}
+		fmt.Print("\nRun this with the agent? [y]es, [n]o, or [e]dit the task: ")
+		answer, err := r.reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch strings.TrimSpace(strings.ToLower(answer)) {
+		case "y", "yes":
+		case "e", "edit":
+			fmt.Print("Enter the task: ")
+			task, err := r.reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if task = strings.TrimSpace(task); task != "" {
+				handoff.Task = task
+			}
+			continue
+		default:
+			fmt.Println("Task not run.")
+			return
+		}
+		break
+	}
+
+	report, err := r.taskRunner(r.ctx, handoff.Task, handoff.Context)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		report = fmt.Sprintf("failed: %v", err)
+	} else {
+		fmt.Println("\n" + report)
+	}
+	conv.AddAssistantMessage(fmt.Sprintf("I handed this task to the agent: %s\nThe agent reported: %s", handoff.Task, report))
+}
+
 // displayHistory displays the conversation history
 func (r *REPL) displayHistory(conv *Conversation) {
 	messages := conv.GetMessages()
@@ -218,15 +358,17 @@ func (r *REPL) listConversations() {
 			continue
 		}
 
-		// Get the first user message as a preview
-		var preview string
-		for _, msg := range conv.GetMessages() {
-			if msg.Role == RoleUser {
-				preview = msg.Content
-				if len(preview) > 30 {
-					preview = preview[:27] + "..."
+		// Show the title, or the first user message as a preview
+		preview := conv.Title
+		if preview == "" {
+			for _, msg := range conv.GetMessages() {
+				if msg.Role == RoleUser {
+					preview = msg.Content
+					if len(preview) > 30 {
+						preview = preview[:27] + "..."
+					}
+					break
 				}
-				break
 			}
 		}
 
@@ -245,7 +387,7 @@ func (r *REPL) listConversations() {
 		}
 
 		// Format the creation time
-		timeAgo := utils.FormatTimeAgo(creationTime)
+		timeAgo := utils.FormatRelative(creationTime)
 
 		fmt.Printf("%s %s - %s (%s)\n", activeMarker, id, preview, timeAgo)
 	}
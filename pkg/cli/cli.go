@@ -0,0 +1,219 @@
+// Package cli parses lumo's command line: global flags, which go before the
+// command, followed by the command itself, given in prefix syntax such as
+// "ask:..." or "config:...", as a command word such as "notes", or as a
+// question.
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// Options are the global flags, which apply to any command
+type Options struct {
+	// Out is the file successful output is written to instead of stdout
+	Out string
+	// Append adds to Out instead of replacing it
+	Append bool
+	// Copy is the code block of the AI's answer to copy, counting from 1,
+	// or 0 to leave it to the configuration
+	Copy int
+	// Quiet hides status messages, leaving results and errors
+	Quiet bool
+	// NoCache asks the AI again instead of answering from the cache
+	NoCache bool
+	// Provider is the AI provider to use for this run only
+	Provider string
+	// Config is the configuration file to use instead of the default one
+	Config string
+	// Record is the cassette file AI provider requests are recorded to
+	Record string
+	// Replay is the cassette file AI provider requests are answered from
+	Replay string
+	// Safe starts lumo without hooks, the REST server, or desktop
+	// integrations, to recover from a broken setup
+	Safe bool
+	// Version and Help print version information or help instead of running a command
+	Version bool
+	Help    bool
+}
+
+// flag describes a global flag. Flags with an argument take it as the next
+// word or after "="; optional arguments can only be given after "=".
+type flag struct {
+	long  string
+	short string
+	// arg names the flag's argument in the usage; empty for switches
+	arg      string
+	optional bool
+	usage    string
+	set      func(opts *Options, value string) error
+}
+
+var flags = []flag{
+	{"out", "o", "file", false, "Write the output to a file, without colors", func(o *Options, v string) error {
+		o.Out = v
+		return nil
+	}},
+	{"output", "", "file", false, "Same as --out", func(o *Options, v string) error {
+		o.Out = v
+		return nil
+	}},
+	{"append", "", "", false, "With --out, add to the file instead of replacing it", func(o *Options, _ string) error {
+		o.Append = true
+		return nil
+	}},
+	{"copy", "", "n", true, "Copy the answer's first (or nth) code block", func(o *Options, v string) error {
+		if v == "" {
+			o.Copy = 1
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("--copy takes a code block number, like --copy=2")
+		}
+		o.Copy = n
+		return nil
+	}},
+	{"quiet", "q", "", false, "Show only results and errors", func(o *Options, _ string) error {
+		o.Quiet = true
+		return nil
+	}},
+	{"no-cache", "", "", false, "Ask the AI again instead of using a cached answer", func(o *Options, _ string) error {
+		o.NoCache = true
+		return nil
+	}},
+	{"provider", "p", "name", false, "Use an AI provider for this run only", func(o *Options, v string) error {
+		o.Provider = strings.ToLower(v)
+		return nil
+	}},
+	{"config", "c", "file", false, "Use another configuration file", func(o *Options, v string) error {
+		o.Config = v
+		return nil
+	}},
+	{"record", "", "cassette", false, "Record AI provider requests to a cassette file", func(o *Options, v string) error {
+		o.Record = v
+		return nil
+	}},
+	{"replay", "", "cassette", false, "Answer AI provider requests from a cassette file, offline", func(o *Options, v string) error {
+		o.Replay = v
+		return nil
+	}},
+	{"safe", "", "", false, "Safe mode: no hooks, server, or desktop integrations", func(o *Options, _ string) error {
+		o.Safe = true
+		return nil
+	}},
+	{"version", "v", "", false, "Show version information", func(o *Options, _ string) error {
+		o.Version = true
+		return nil
+	}},
+	{"help", "h", "", false, "Show help", func(o *Options, _ string) error {
+		o.Help = true
+		return nil
+	}},
+}
+
+// Parse reads the global flags at the start of args and returns them with
+// the command that follows. Flags end at the first word that is not one,
+// or after "--", so a command's own flags, like those of "shell:grep -q",
+// are left alone. "-" on its own is a command: read the question from stdin.
+func Parse(args []string) (*Options, []string, error) {
+	opts := &Options{}
+	i := 0
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			i++
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		spec, ok := lookup(name, strings.HasPrefix(arg, "--"))
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown flag: %s (put -- before a question that starts with a dash)", arg)
+		}
+		switch {
+		case spec.arg == "" && hasValue:
+			return nil, nil, fmt.Errorf("--%s does not take a value", spec.long)
+		case spec.arg != "" && !spec.optional && !hasValue:
+			if i+1 == len(args) || strings.HasPrefix(args[i+1], "-") {
+				return nil, nil, fmt.Errorf("--%s needs a %s", spec.long, spec.arg)
+			}
+			i++
+			value = args[i]
+		case spec.arg != "" && hasValue && value == "":
+			return nil, nil, fmt.Errorf("--%s needs a %s", spec.long, spec.arg)
+		}
+		if err := spec.set(opts, value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if opts.Append && opts.Out == "" {
+		return nil, nil, fmt.Errorf("--append needs --out <file>")
+	}
+	if opts.Record != "" && opts.Replay != "" {
+		return nil, nil, fmt.Errorf("--record and --replay cannot be used together")
+	}
+	return opts, args[i:], nil
+}
+
+// lookup finds a flag by its long name, or by its short name for flags
+// given with a single dash
+func lookup(name string, long bool) (flag, bool) {
+	for _, f := range flags {
+		if (long && f.long == name) || (!long && f.short != "" && f.short == name) {
+			return f, true
+		}
+	}
+	return flag{}, false
+}
+
+// Usage describes the global flags, one per line
+func Usage() string {
+	var b strings.Builder
+	for _, f := range flags {
+		names := "--" + f.long
+		if f.short != "" {
+			names = "-" + f.short + ", " + names
+		}
+		switch {
+		case f.optional:
+			names += "[=<" + f.arg + ">]"
+		case f.arg != "":
+			names += " <" + f.arg + ">"
+		}
+		b.WriteString(fmt.Sprintf("   • %-27s %s\n", names, f.usage))
+	}
+	return b.String()
+}
+
+// prefixes start commands in prefix syntax
+var prefixes = []string{
+	"lumo:", "shell:", "ask:", "ai:", "auto:", "agent:", "analyze:", "health:", "syshealth:",
+	"report:", "sysreport:", "chat:", "talk:", "config:", "speed:", "speedtest:", "speed-test:",
+	"magic:", "desktop:", "server:",
+}
+
+// IsCommand reports whether a command line names a command, rather than
+// being a question for the AI
+func IsCommand(command string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(command, prefix) {
+			return true
+		}
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	for _, word := range nlp.CommandWords() {
+		if first == word || strings.HasPrefix(first, word+":") {
+			return true
+		}
+	}
+	return false
+}
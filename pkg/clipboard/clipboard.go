@@ -32,10 +32,10 @@ type Clipboard struct {
 	provider ClipboardProvider
 }
 
-// NewClipboard creates a new Clipboard instance with the default provider
+// NewClipboard creates a new Clipboard instance with the provider for this platform
 func NewClipboard() *Clipboard {
 	return &Clipboard{
-		provider: &DefaultClipboardProvider{},
+		provider: platformProvider(),
 	}
 }
 
@@ -102,6 +102,19 @@ func (c *Clipboard) GetContent() (string, error) {
 	return content, nil
 }
 
+// Text returns the clipboard content for programs to use; unlike
+// GetContent, it is empty when the clipboard is
+func (c *Clipboard) Text() (string, error) {
+	content, err := c.provider.ReadAll()
+	if err != nil {
+		if strings.Contains(err.Error(), "No clipboard utilities available") {
+			return "", fmt.Errorf("clipboard utilities not available. Please install xsel, xclip, wl-clipboard, or Termux:API")
+		}
+		return "", fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	return content, nil
+}
+
 // SetContent sets the clipboard content
 func (c *Clipboard) SetContent(content string) (string, error) {
 	err := c.provider.WriteAll(content)
@@ -0,0 +1,52 @@
+package clipboard
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// codeFence matches a fenced markdown code block and its language, if any
+var codeFence = regexp.MustCompile("(?s)```([A-Za-z0-9_+.-]*)[^\n]*\n(.*?)\n?```")
+
+// CodeBlock is a fenced code block from a markdown response
+type CodeBlock struct {
+	Language string
+	Code     string
+}
+
+// ExtractCodeBlocks returns the fenced code blocks in text, in order
+func ExtractCodeBlocks(text string) []CodeBlock {
+	var blocks []CodeBlock
+	for _, match := range codeFence.FindAllStringSubmatch(text, -1) {
+		code := strings.TrimRight(match[2], "\n")
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+		blocks = append(blocks, CodeBlock{Language: strings.ToLower(match[1]), Code: code})
+	}
+	return blocks
+}
+
+// CopyCodeBlock copies the nth code block (counting from 1) of a response
+// to the clipboard and returns a message saying which one was copied
+func (c *Clipboard) CopyCodeBlock(response string, n int) (string, error) {
+	blocks := ExtractCodeBlocks(response)
+	if len(blocks) == 0 {
+		return "", fmt.Errorf("the response has no code blocks to copy")
+	}
+	if n < 1 || n > len(blocks) {
+		return "", fmt.Errorf("the response has %d code block(s), not %d", len(blocks), n)
+	}
+
+	block := blocks[n-1]
+	if _, err := c.SetContent(block.Code); err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("📋 Copied code block %d of %d", n, len(blocks))
+	if block.Language != "" {
+		message += " (" + block.Language + ")"
+	}
+	return message + " to the clipboard", nil
+}
@@ -0,0 +1,281 @@
+package clipboard
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+const (
+	// DefaultHistoryLimit is how many entries the history keeps, besides
+	// pinned ones, unless configured otherwise
+	DefaultHistoryLimit = 100
+
+	// WatchInterval is how often the clipboard is checked for changes
+	WatchInterval = time.Second
+
+	// maxHistoryEntry is the largest text recorded; bigger copies, such as
+	// whole files, are left out
+	maxHistoryEntry = 256 * 1024
+)
+
+// HistoryEntry is something that was copied
+type HistoryEntry struct {
+	Text     string    `json:"text"`
+	CopiedAt time.Time `json:"copied_at"`
+	// Pinned entries are kept whatever the limit, until unpinned
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// historyPath returns the encrypted file the history is kept in
+func historyPath() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "clipboard-history.enc"), nil
+}
+
+// historyKeyPath returns the file holding the history's key. It is kept in
+// the state directory, apart from the history, so copying one of them does
+// not give away what was copied.
+func historyKeyPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "clipboard-history.key"), nil
+}
+
+// historyCipher returns the cipher the history is sealed with, creating
+// its key the first time
+func historyCipher() (cipher.AEAD, error) {
+	path, err := historyKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	key, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate history key: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create state directory: %w", err)
+		}
+		if err := os.WriteFile(path, key, 0600); err != nil {
+			return nil, fmt.Errorf("failed to save history key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read history key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("the history key %s is damaged; delete it and the history to start over", path)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// History returns what was copied, newest first
+func History() ([]HistoryEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clipboard history: %w", err)
+	}
+
+	aead, err := historyCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("the clipboard history %s is damaged", path)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("the clipboard history %s cannot be decrypted with its key", path)
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse clipboard history: %w", err)
+	}
+	return entries, nil
+}
+
+// HistoryEntryAt returns the nth entry of the history, counting from 1 for
+// the newest
+func HistoryEntryAt(n int) (*HistoryEntry, error) {
+	entries, err := History()
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 || n > len(entries) {
+		return nil, fmt.Errorf("no entry %d in the clipboard history (it has %d)", n, len(entries))
+	}
+	return &entries[n-1], nil
+}
+
+// Record adds text to the history unless it was the last thing copied.
+// Text copied before moves to the top, keeping its pin. Beyond limit
+// entries, the oldest unpinned ones are dropped. It reports whether the
+// history changed.
+func Record(text string, limit int) (bool, error) {
+	if text == "" || len(text) > maxHistoryEntry {
+		return false, nil
+	}
+	entries, err := History()
+	if err != nil {
+		return false, err
+	}
+	if len(entries) > 0 && entries[0].Text == text {
+		return false, nil
+	}
+
+	entry := HistoryEntry{Text: text, CopiedAt: time.Now()}
+	kept := []HistoryEntry{entry}
+	for _, e := range entries {
+		if e.Text == text {
+			kept[0].Pinned = e.Pinned
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return true, writeHistory(trimHistory(kept, limit))
+}
+
+// SetPinned pins or unpins the nth entry of the history
+func SetPinned(n int, pinned bool) (*HistoryEntry, error) {
+	entries, err := History()
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 || n > len(entries) {
+		return nil, fmt.Errorf("no entry %d in the clipboard history (it has %d)", n, len(entries))
+	}
+	entries[n-1].Pinned = pinned
+	return &entries[n-1], writeHistory(entries)
+}
+
+// ClearHistory removes every entry that is not pinned, and reports how many
+// were removed
+func ClearHistory() (int, error) {
+	entries, err := History()
+	if err != nil {
+		return 0, err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Pinned {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(entries) - len(kept)
+	return removed, writeHistory(kept)
+}
+
+// trimHistory keeps the pinned entries and the newest limit unpinned ones
+func trimHistory(entries []HistoryEntry, limit int) []HistoryEntry {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	kept := entries[:0]
+	unpinned := 0
+	for _, e := range entries {
+		if !e.Pinned {
+			if unpinned == limit {
+				continue
+			}
+			unpinned++
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// writeHistory seals the history and replaces the file, so the daemon and
+// the command line never read it half-written
+func writeHistory(entries []HistoryEntry) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	aead, err := historyCipher()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode clipboard history: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, aead.Seal(nonce, nonce, data, nil), 0600); err != nil {
+		return fmt.Errorf("failed to write clipboard history: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// WatchHistory records every change to the clipboard until the context is
+// cancelled, calling onRecord, if set, for each one. It fails if the
+// clipboard cannot be read at all.
+func (c *Clipboard) WatchHistory(ctx context.Context, limit int, onRecord func(text string)) error {
+	last, err := c.provider.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	// What is on the clipboard when watching starts counts as copied
+	if recorded, err := Record(last, limit); err != nil {
+		return err
+	} else if recorded && onRecord != nil {
+		onRecord(last)
+	}
+
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		text, err := c.provider.ReadAll()
+		if err != nil || text == last {
+			continue
+		}
+		last = text
+		recorded, err := Record(text, limit)
+		if err != nil {
+			return err
+		}
+		if recorded && onRecord != nil {
+			onRecord(text)
+		}
+	}
+}
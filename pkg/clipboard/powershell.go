@@ -0,0 +1,37 @@
+package clipboard
+
+import (
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// PowerShellClipboardProvider uses PowerShell's Get-Clipboard and
+// Set-Clipboard, which are available on every supported Windows version
+type PowerShellClipboardProvider struct{}
+
+// ReadAll reads the clipboard content
+func (p *PowerShellClipboardProvider) ReadAll() (string, error) {
+	output, err := utils.RunPowerShell("Get-Clipboard -Raw", nil)
+	if err != nil {
+		return "", err
+	}
+
+	// PowerShell ends its output with a line break of its own; an empty
+	// clipboard prints nothing at all
+	content := strings.TrimSuffix(string(output), "\n")
+	content = strings.TrimSuffix(content, "\r")
+	return strings.ReplaceAll(content, "\r\n", "\n"), nil
+}
+
+// WriteAll writes text to the clipboard
+func (p *PowerShellClipboardProvider) WriteAll(text string) error {
+	if text == "" {
+		_, err := utils.RunPowerShell("Set-Clipboard -Value $null", nil)
+		return err
+	}
+
+	// The text goes through stdin so it never has to be quoted for PowerShell
+	_, err := utils.RunPowerShell("Set-Clipboard -Value ([Console]::In.ReadToEnd())", strings.NewReader(text))
+	return err
+}
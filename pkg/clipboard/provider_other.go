@@ -0,0 +1,8 @@
+//go:build !windows
+
+package clipboard
+
+// platformProvider uses xsel, xclip, wl-clipboard, pbcopy, or Termux:API
+func platformProvider() ClipboardProvider {
+	return &DefaultClipboardProvider{}
+}
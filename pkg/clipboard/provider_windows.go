@@ -0,0 +1,13 @@
+//go:build windows
+
+package clipboard
+
+import "github.com/agnath18K/lumo/pkg/utils"
+
+// platformProvider prefers PowerShell and falls back to the Win32 clipboard API
+func platformProvider() ClipboardProvider {
+	if utils.PowerShellPath() != "" {
+		return &PowerShellClipboardProvider{}
+	}
+	return &DefaultClipboardProvider{}
+}
@@ -0,0 +1,297 @@
+package completion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/contacts"
+	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/speedtest"
+)
+
+// Shells lists the shells completion scripts can be generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// topLevel are the words a lumo command line can start with. Words ending
+// in ":" take the rest of the line as their argument.
+var topLevel = []string{
+	"ask:", "ai:", "chat:", "chat", "talk:", "shell:", "auto:", "agent:",
+	"analyze:", "health:", "syshealth:", "report:", "sysreport:", "speed:", "magic:",
+	"clipboard", "connect", "create:", "desktop:", "server:", "config:",
+	"doctor", "integrate", "last", "save", "notes", "usage", "stats", "discover", "trigger", "today", "palette", "snip", "remind", "agenda", "send", "contacts", "script", "widget", "completion", "help", "version",
+}
+
+// expansions complete a prefix into full commands once it has been typed
+var expansions = map[string][]string{
+	"chat:":   {"chat:history", "chat:rename"},
+	"server:": {"server:start", "server:stop", "server:status", "server:install", "server:uninstall", "server:apikey", "server:user", "server:audit", "server:help"},
+	"speed:":  {"speed:download", "speed:upload", "speed:monitor"},
+	"config:": {
+		"config:provider", "config:model", "config:key", "config:ollama", "config:mode",
+		"config:server", "config:daemon", "config:power", "config:desktop", "config:privacy",
+		"config:metrics", "config:tracking",
+		"config:speedtest", "config:discovery", "config:agent", "config:clipboard",
+		"config:persona", "config:chat", "config:budget", "config:network", "config:cache", "config:limits",
+		"config:time", "config:bot", "config:report", "config:remote",
+		"config:feature", "config:restore-backup", "config:test",
+	},
+}
+
+// arguments are the fixed words that may follow a command
+var arguments = map[string][]string{
+	"clipboard":             {"append", "clear", "history", "get", "pick", "pin", "unpin", "watch"},
+	"connect":               {"--receive", "--port", "--path", "--chunked", "--staged", "--webrtc", "--signal", "--code", "--history", "--parallel", "--discover", "--help"},
+	"completion":            Shells,
+	"script":                {"run"},
+	"widget":                {"status"},
+	"last":                  {"--as-script"},
+	"save":                  {"--tag"},
+	"notes":                 {"list", "show", "search", "remove"},
+	"stats":                 {"all"},
+	"usage":                 {"network"},
+	"trigger":               {"add", "list", "test", "enable", "disable", "remove", "--watch", "--glob", "--run", "--name", "--debounce", "--dry-run"},
+	"today":                 {"--date", "--csv"},
+	"palette":               {"--count"},
+	"snip":                  {"list", "add", "show", "paste", "remove", "expand"},
+	"remind":                {"list", "remove"},
+	"agenda":                {"today", "tomorrow", "week"},
+	"contacts":              {"list", "add", "remove", "refresh"},
+	"integrate":             {"shortcuts"},
+	"speed:monitor":         {"--target", "--duration", "--interval", "--loss-threshold"},
+	"auto:":                 {"--dry-run"},
+	"agent:":                {"--dry-run"},
+	"ask:":                  {"--persona", "--image"},
+	"server:apikey":         {"create", "list", "revoke"},
+	"server:user":           {"add", "list", "role", "remove"},
+	"server:audit":          {"tail"},
+	"chat:history":          {"list", "show", "delete"},
+	"config:provider":       {"list", "show", "set"},
+	"config:model":          {"list", "show", "set"},
+	"config:key":            {"show", "set", "remove"},
+	"config:ollama":         {"show", "set", "test"},
+	"config:mode":           {"show", "ai", "command", "local", "model"},
+	"config:server":         {"show", "enable", "disable", "port", "quiet", "auth", "fleet", "ratelimit", "maxbody"},
+	"config:daemon":         {"show", "speedtest", "idle", "max-delay", "noisy", "nightlight"},
+	"config:power":          {"show", "mode", "prefer-local", "skip-speedtest", "health-factor"},
+	"config:desktop":        {"show", "confirm"},
+	"config:agent":          {"show", "safety", "deny"},
+	"config:clipboard":      {"show", "autocopy", "history", "limit"},
+	"config:persona":        {"list", "show", "set", "remove", "default"},
+	"config:chat":           {"show", "history", "titles"},
+	"config:budget":         {"show", "set", "off", "action"},
+	"config:network":        {"show", "cap", "off", "action"},
+	"config:cache":          {"show", "clear", "on", "off", "ttl", "size"},
+	"config:limits":         {"show", "set", "timeout"},
+	"config:time":           {"show", "format"},
+	"config:privacy":        {"show", "strict", "standard"},
+	"config:metrics":        {"show", "enable", "disable", "purge"},
+	"config:tracking":       {"show", "enable", "disable", "exclude", "include", "purge"},
+	"config:speedtest":      {"show", "backend"},
+	"config:bot":            {"show", "telegram", "matrix", "allow", "disallow", "agent", "off"},
+	"config:report":         {"show", "smtp", "email", "send"},
+	"config:remote":         {"show", "add", "key", "remove"},
+	"config:feature":        {"list", "enable", "disable"},
+	"config:restore-backup": {"list"},
+	"config:discovery":      {"show", "transport", "secret", "advertise", "hide-identity", "require-auth"},
+}
+
+// Complete returns the completions for a partial command line, without the
+// program name. The last word of the line is the one being completed; a
+// line ending in whitespace completes a new word.
+func Complete(cfg *config.Config, line string) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 || strings.TrimRight(line, " \t") != line {
+		words = append(words, "")
+	}
+	current := words[len(words)-1]
+	previous := words[:len(words)-1]
+
+	var candidates []string
+	if len(previous) == 0 {
+		candidates = topLevel
+		for prefix, expanded := range expansions {
+			if strings.HasPrefix(current, prefix) {
+				candidates = expanded
+			}
+		}
+	} else {
+		candidates = argumentsFor(cfg, previous)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, current) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
+
+// argumentsFor returns the words that may follow the given words
+func argumentsFor(cfg *config.Config, previous []string) []string {
+	path := strings.Join(previous, " ")
+	// config:feature enable and disable take several feature names
+	if strings.HasPrefix(path, "config:feature enable") || strings.HasPrefix(path, "config:feature disable") {
+		return config.FeatureNames()
+	}
+	// Files are sent to a contact named after "to"
+	if previous[0] == "send" && previous[len(previous)-1] == "to" || path == "contacts remove" {
+		return contactNames()
+	}
+	switch path {
+	case "config:provider set", "config:key set", "config:key remove", "config:test":
+		return ai.ProviderNames()
+	case "config:model set":
+		return modelNames(cfg)
+	case "config:speedtest backend":
+		return []string{"list", "set"}
+	case "config:speedtest backend set":
+		var names []string
+		for _, backend := range speedtest.Backends() {
+			names = append(names, backend.Name)
+		}
+		return names
+	case "config:discovery transport":
+		return discovery.Transports
+	case "config:discovery secret":
+		return []string{"generate"}
+	case "config:server quiet", "config:server fleet", "config:discovery advertise", "config:discovery hide-identity", "config:discovery require-auth", "config:power prefer-local", "config:power skip-speedtest":
+		return []string{"on", "off"}
+	case "config:server auth":
+		return []string{"enable", "disable", "password"}
+	case "config:power mode":
+		return []string{"auto", "battery", "ac"}
+	case "config:desktop confirm":
+		return []string{"always", "never"}
+	}
+
+	if len(previous) == 1 {
+		return arguments[previous[0]]
+	}
+	// Flags can be given in any order after these commands
+	switch previous[0] {
+	case "connect", "speed:monitor":
+		return arguments[previous[0]]
+	}
+	return nil
+}
+
+// contactNames returns the names of the saved contacts
+func contactNames() []string {
+	list, err := contacts.List()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(list))
+	for i, contact := range list {
+		names[i] = contact.Name
+	}
+	return names
+}
+
+// modelNames returns the configured model of every provider, with the
+// current provider's model first
+func modelNames(cfg *config.Config) []string {
+	models := map[string]string{
+		"gemini": cfg.GeminiModel,
+		"openai": cfg.OpenAIModel,
+		"ollama": cfg.OllamaModel,
+	}
+
+	var names []string
+	if current := models[cfg.AIProvider]; current != "" {
+		names = append(names, current)
+	}
+	var others []string
+	for provider, model := range models {
+		if provider != cfg.AIProvider && model != "" && model != models[cfg.AIProvider] {
+			others = append(others, model)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+// Script returns the completion script for a shell
+func Script(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashScript, nil
+	case "zsh":
+		return zshScript, nil
+	case "fish":
+		return fishScript, nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s (use %s)", shell, strings.Join(Shells, ", "))
+	}
+}
+
+// bashScript asks lumo for completions of the line up to the cursor. Bash
+// splits words at colons, so the part before the last colon is trimmed
+// from the candidates.
+const bashScript = `# bash completion for lumo
+# Load it with: source <(lumo completion bash)
+
+_lumo_completions() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    line="${line#*[[:space:]]}"
+    [[ "${COMP_LINE:0:COMP_POINT}" == "$line" ]] && line=""
+    local cur="${line##*[[:space:]]}"
+
+    local IFS=$'\n'
+    COMPREPLY=($(lumo __complete "$line" 2>/dev/null))
+
+    if [[ "$cur" == *:* && "$COMP_WORDBREAKS" == *:* ]]; then
+        local colon_word="${cur%"${cur##*:}"}"
+        local i
+        for i in "${!COMPREPLY[@]}"; do
+            COMPREPLY[$i]="${COMPREPLY[$i]#"$colon_word"}"
+        done
+    fi
+
+    if [[ ${#COMPREPLY[@]} -eq 1 && "${COMPREPLY[0]}" == *: ]]; then
+        compopt -o nospace
+    fi
+}
+
+complete -F _lumo_completions lumo
+`
+
+// zshScript asks lumo for completions of the words up to the cursor
+const zshScript = `#compdef lumo
+# zsh completion for lumo
+# Load it with: source <(lumo completion zsh)
+
+_lumo() {
+    local -a candidates prefixes
+    local line="${(j: :)words[2,CURRENT]}"
+    candidates=("${(@f)$(lumo __complete "$line" 2>/dev/null)}")
+    candidates=(${candidates:#})
+
+    prefixes=(${(M)candidates:#*:})
+    candidates=(${candidates:#*:})
+    compadd -S '' -- $prefixes
+    compadd -- $candidates
+}
+
+if [[ "$funcstack[1]" == "_lumo" ]]; then
+    _lumo "$@"
+else
+    compdef _lumo lumo
+fi
+`
+
+// fishScript asks lumo for completions of the command line up to the cursor
+const fishScript = `# fish completion for lumo
+# Load it with: lumo completion fish | source
+
+function __lumo_complete
+    set -l line (commandline -cp | string replace -r '^\S+\s*' '')
+    lumo __complete "$line" 2>/dev/null
+end
+
+complete -c lumo -f -a '(__lumo_complete)'
+`
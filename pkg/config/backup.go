@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupCount is how many earlier versions of the configuration file are
+// kept next to it
+const BackupCount = 3
+
+// Backup is an earlier version of the configuration file
+type Backup struct {
+	// Number is 1 for the newest backup, up to BackupCount
+	Number  int
+	Path    string
+	ModTime time.Time
+}
+
+// backupPath returns where backup n of the file at path is kept
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d.bak", path, n)
+}
+
+// Backups returns the backups of the configuration file, newest first
+func Backups() ([]Backup, error) {
+	path, err := getConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+	var backups []Backup
+	for n := 1; n <= BackupCount; n++ {
+		info, err := os.Stat(backupPath(path, n))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, Backup{Number: n, Path: backupPath(path, n), ModTime: info.ModTime()})
+	}
+	return backups, nil
+}
+
+// RestoreBackup replaces the configuration file with backup n, or with the
+// newest backup that parses when n is 0, and returns the number of the
+// backup restored. The file it replaces becomes the newest backup, so a
+// restore can itself be undone.
+func RestoreBackup(n int) (int, error) {
+	if n < 0 || n > BackupCount {
+		return 0, fmt.Errorf("no backup %d: backups are numbered 1 to %d, newest first", n, BackupCount)
+	}
+	path, err := getConfigFilePath()
+	if err != nil {
+		return 0, err
+	}
+	unlock, err := lockConfig(path)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	candidates := []int{n}
+	if n == 0 {
+		candidates = nil
+		for i := 1; i <= BackupCount; i++ {
+			candidates = append(candidates, i)
+		}
+	}
+	var lastErr error
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(backupPath(path, candidate))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			_, err = DefaultConfig().decodeMigrated(data)
+			if err != nil {
+				err = fmt.Errorf("backup %d is not a valid configuration: %w", candidate, err)
+			}
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return candidate, writeConfigFile(path, data)
+	}
+
+	if lastErr != nil {
+		return 0, lastErr
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("no backups of %s yet", path)
+	}
+	return 0, fmt.Errorf("no backup %d of %s", n, path)
+}
+
+// writeConfigFile replaces the file at path with data without ever leaving
+// it half written: data goes to a temporary file that is synced and then
+// renamed over the old one. When the contents change, the old file becomes
+// backup 1 and older backups move up, dropping the oldest.
+func writeConfigFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".config-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+
+	if err := rotateBackups(path, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not back up the configuration file: %v\n", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace configuration: %w", err)
+	}
+	syncDir(dir)
+	return nil
+}
+
+// rotateBackups makes the file at path backup 1 unless it already holds
+// data. The old file is linked rather than moved, so the configuration
+// file exists at every moment.
+func rotateBackups(path string, data []byte) error {
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) || err == nil && string(current) == string(data) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for n := BackupCount; n > 1; n-- {
+		err := os.Rename(backupPath(path, n-1), backupPath(path, n))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	newest := backupPath(path, 1)
+	if err := os.Link(path, newest); err == nil {
+		return os.Chmod(newest, 0600)
+	}
+	// Some file systems have no hard links
+	return os.WriteFile(newest, current, 0600)
+}
+
+// syncDir flushes a directory so a rename in it survives a crash; not every
+// platform supports it, so failures are ignored
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
@@ -1,16 +1,25 @@
 package config
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/paths"
 )
 
 // Config holds the application configuration
 type Config struct {
+	// SchemaVersion is the version of the file's layout, which Load
+	// upgrades with Migrations
+	SchemaVersion int `json:"schema_version"`
+
 	// AI provider settings
 	AIProvider   string `json:"ai_provider"`
 	GeminiAPIKey string `json:"gemini_api_key"`
@@ -20,41 +29,179 @@ type Config struct {
 	OllamaURL    string `json:"ollama_url"`
 	OllamaModel  string `json:"ollama_model"`
 
+	// Concurrency settings: the most requests in flight to each AI
+	// provider, where a missing provider or 0 means no limit, and how long
+	// a request waits for a free slot before failing
+	ProviderConcurrency         map[string]int `json:"provider_concurrency,omitempty"`
+	ProviderQueueTimeoutSeconds int            `json:"provider_queue_timeout_seconds"`
+
 	// Terminal settings
 	MaxHistorySize           int  `json:"max_history_size"`
 	EnableLogging            bool `json:"enable_logging"`
 	EnableShellInInteractive bool `json:"enable_shell_in_interactive"`
 	CommandFirstMode         bool `json:"command_first_mode"`
+	LocalIntentRouting       bool `json:"local_intent_routing"`
+	LocalIntentModel         bool `json:"local_intent_model"`
 
 	// Agent mode settings
-	EnableAgentMode             bool   `json:"enable_agent_mode"`
-	EnableAgentREPL             bool   `json:"enable_agent_repl"`
-	AgentConfirmBeforeExecution bool   `json:"agent_confirm_before_execution"`
-	AgentMaxSteps               int    `json:"agent_max_steps"`
-	AgentSafetyLevel            string `json:"agent_safety_level"`
+	EnableAgentMode             bool     `json:"enable_agent_mode"`
+	EnableAgentREPL             bool     `json:"enable_agent_repl"`
+	AgentConfirmBeforeExecution bool     `json:"agent_confirm_before_execution"`
+	AgentMaxSteps               int      `json:"agent_max_steps"`
+	AgentSafetyLevel            string   `json:"agent_safety_level"`
+	AgentStepRetries            int      `json:"agent_step_retries"`
+	AgentMaxIterations          int      `json:"agent_max_iterations"`
+	AgentDenyList               []string `json:"agent_deny_list"`
 
 	// Chat settings
 	EnableChatREPL bool `json:"enable_chat_repl"`
+	// SaveChatHistory keeps conversations for chat:history, and
+	// ChatAutoTitle asks the AI to title and tag them
+	SaveChatHistory bool `json:"save_chat_history"`
+	ChatAutoTitle   bool `json:"chat_auto_title"`
+
+	// Persona settings: named system prompts, and the one used by default
+	Personas       map[string]string `json:"personas,omitempty"`
+	DefaultPersona string            `json:"default_persona"`
+
+	// Response cache settings: identical questions are answered from the
+	// cache for ResponseCacheTTLMinutes, keeping up to ResponseCacheSize answers
+	EnableResponseCache     bool `json:"enable_response_cache"`
+	ResponseCacheTTLMinutes int  `json:"response_cache_ttl_minutes"`
+	ResponseCacheSize       int  `json:"response_cache_size"`
 
 	// Pipe settings
 	EnablePipeProcessing bool `json:"enable_pipe_processing"`
 
+	// Clipboard settings
+	AutoCopyCode bool `json:"auto_copy_code"`
+	// EnableClipboardHistory has the daemon record what is copied, encrypted
+	// on this machine, for 'lumo clipboard history'. The newest
+	// ClipboardHistoryLimit entries are kept, besides pinned ones.
+	EnableClipboardHistory bool `json:"enable_clipboard_history"`
+	ClipboardHistoryLimit  int  `json:"clipboard_history_limit"`
+
+	// Budget settings: a monthly limit on cloud AI spending in US dollars,
+	// where 0 means none, and whether reaching it warns or blocks
+	BudgetUSD    float64 `json:"budget_usd"`
+	BudgetAction string  `json:"budget_action"`
+
+	// Network cap settings: a monthly limit in megabytes on the traffic of
+	// connect and AI requests, where 0 means none, and whether reaching it
+	// only warns or also pauses scheduled speed tests
+	NetworkCapMB     int    `json:"network_cap_mb"`
+	NetworkCapAction string `json:"network_cap_action"`
+
+	// MockFixtures is the fixture file the mock provider answers from,
+	// instead of mock.json next to this file
+	MockFixtures string `json:"mock_fixtures,omitempty"`
+
+	// Time settings: "local" shows relative times and local timestamps,
+	// "iso" shows ISO-8601 timestamps for scripts
+	TimeFormat string `json:"time_format"`
+
 	// System settings
 	EnableSystemHealth bool `json:"enable_system_health"`
 	EnableSystemReport bool `json:"enable_system_report"`
 
 	// Speed test settings
-	EnableSpeedTest  bool `json:"enable_speed_test"`
-	SpeedTestTimeout int  `json:"speed_test_timeout"`
+	EnableSpeedTest  bool   `json:"enable_speed_test"`
+	SpeedTestTimeout int    `json:"speed_test_timeout"`
+	SpeedTestBackend string `json:"speed_test_backend"`
 
 	// Desktop assistant settings
 	EnableDesktopAssistant bool   `json:"enable_desktop_assistant"`
 	DefaultDesktopEnv      string `json:"default_desktop_env"`
+	DesktopConfirmPower    string `json:"desktop_confirm_power"`
 
 	// Server settings
 	EnableServer      bool `json:"enable_server"`
 	ServerPort        int  `json:"server_port"`
 	ServerQuietOutput bool `json:"server_quiet_output"`
+	// FleetManaged advertises this machine as managed by a fleet on the LAN
+	FleetManaged bool `json:"fleet_managed"`
+	// ServerRateLimit is how many API requests each user, or each address
+	// without a login, may make per minute, with bursts of up to
+	// ServerRateBurst; 0 turns rate limiting off
+	ServerRateLimit int `json:"server_rate_limit"`
+	ServerRateBurst int `json:"server_rate_burst"`
+	// ServerMaxBodyKB is the largest request body the server reads, apart
+	// from file transfers
+	ServerMaxBodyKB int `json:"server_max_body_kb"`
+
+	// Discovery settings
+	DiscoveryTransport string `json:"discovery_transport"`
+	DiscoverySecret    string `json:"discovery_secret"`
+	// DiscoveryAdvertise announces this machine to peers; when off, lumo only browses
+	DiscoveryAdvertise bool `json:"discovery_advertise"`
+	// DiscoveryHideIdentity leaves the hostname and username out of announcements
+	DiscoveryHideIdentity bool `json:"discovery_hide_identity"`
+	// DiscoveryRequireAuth only discovers and answers peers that know the shared secret
+	DiscoveryRequireAuth bool `json:"discovery_require_auth"`
+
+	// Daemon scheduler settings
+	ScheduledSpeedTestHours     int      `json:"scheduled_speed_test_hours"`
+	ScheduledHealthCheckMinutes int      `json:"scheduled_health_check_minutes"`
+	IdleThresholdMinutes        int      `json:"idle_threshold_minutes"`
+	IdleMaxDelayMinutes         int      `json:"idle_max_delay_minutes"`
+	NoisyTasks                  []string `json:"noisy_tasks"`
+	NightLightStart             string   `json:"night_light_start"`
+	NightLightEnd               string   `json:"night_light_end"`
+	NightLightTemperature       int      `json:"night_light_temperature"`
+
+	// Bot bridge settings: the daemon answers messages from a Telegram chat
+	// or Matrix room when BotPlatform is "telegram" or "matrix". BotToken is
+	// the Telegram bot token or Matrix access token.
+	BotPlatform         string `json:"bot_platform,omitempty"`
+	BotToken            string `json:"bot_token,omitempty"`
+	BotMatrixHomeserver string `json:"bot_matrix_homeserver,omitempty"`
+	// BotMatrixRoom limits the Matrix bridge to one room; empty answers in every joined room
+	BotMatrixRoom string `json:"bot_matrix_room,omitempty"`
+	// BotAllowedUsers may ask and chat; BotAgentUsers may also run agent tasks
+	BotAllowedUsers []string `json:"bot_allowed_users,omitempty"`
+	BotAgentUsers   []string `json:"bot_agent_users,omitempty"`
+
+	// Email report settings: the daemon emails each report in ReportRecipients
+	// once a week through the SMTP server. SMTPServer is host:port; port 465
+	// uses TLS from the start, other ports upgrade with STARTTLS when offered.
+	SMTPServer   string `json:"smtp_server,omitempty"`
+	SMTPUsername string `json:"smtp_username,omitempty"`
+	SMTPPassword string `json:"smtp_password,omitempty"`
+	SMTPFrom     string `json:"smtp_from,omitempty"`
+	// ReportRecipients maps a report name ("system" or "speedtest") to the
+	// addresses it is sent to
+	ReportRecipients map[string][]string `json:"report_recipients,omitempty"`
+
+	// Power settings
+	PowerMode                string `json:"power_mode"`
+	BatteryPreferLocalModel  bool   `json:"battery_prefer_local_model"`
+	BatterySkipSpeedTests    bool   `json:"battery_skip_speed_tests"`
+	BatteryHealthCheckFactor int    `json:"battery_health_check_factor"`
+
+	// Privacy settings
+	PrivacyMode string `json:"privacy_mode"`
+
+	// Metrics settings: counts of the commands lumo runs, kept on this
+	// machine for 'lumo stats'. MetricsAsked is set once the first-run
+	// prompt has been answered, so it is only shown once.
+	EnableMetrics bool `json:"enable_metrics"`
+	MetricsAsked  bool `json:"metrics_asked"`
+
+	// Time tracking settings: the daemon samples the application in focus
+	// for 'lumo today', keeping what it sees on this machine. Applications
+	// in TimeTrackingExclude are never recorded.
+	EnableTimeTracking  bool     `json:"enable_time_tracking"`
+	TimeTrackingExclude []string `json:"time_tracking_exclude,omitempty"`
+
+	// Snippet settings: with expansion on, the daemon reads the keyboard
+	// and replaces SnippetPrefix followed by a snippet's name, typed
+	// anywhere, with the snippet
+	EnableSnippetExpansion bool   `json:"enable_snippet_expansion"`
+	SnippetPrefix          string `json:"snippet_prefix"`
+
+	// ConnectRemotes are logins for SFTP servers and WebDAV shares that
+	// connect pushes files to, by host. What is missing is asked for.
+	ConnectRemotes map[string]RemoteLogin `json:"connect_remotes,omitempty"`
 
 	// Authentication settings
 	EnableAuth            bool   `json:"enable_auth"`
@@ -64,11 +211,123 @@ type Config struct {
 
 	// Application settings
 	Debug bool `json:"debug"`
+
+	// AccessibleOutput writes plain linear text for screen readers, without
+	// colors, box drawing, or live progress lines
+	AccessibleOutput bool `json:"accessible_output"`
+
+	// Quiet hides status messages for this run; it is set by --quiet and
+	// never saved
+	Quiet bool `json:"-"`
+
+	// NoCache asks the AI again instead of using a cached answer, and
+	// caches the new one; it is set by --no-cache and never saved
+	NoCache bool `json:"-"`
+
+	// Safe runs lumo without hooks, the REST server, or desktop
+	// integrations, and with only the prefix command parser, to recover
+	// from a broken setup; it is set by --safe and never saved
+	Safe bool `json:"-"`
+
+	// accessibleRun, when set from LUMO_A11Y, overrides AccessibleOutput
+	// for this run without being saved
+	accessibleRun *bool
+
+	// migration describes how Load upgraded the file
+	migration MigrationResult
+
+	// loaded is the file as this configuration last read or wrote it, to
+	// find the settings other processes changed since
+	loaded []byte
+
+	// runProvider and savedProvider remember a provider chosen with
+	// UseProvider, so Save keeps the configured one
+	runProvider   string
+	savedProvider string
+}
+
+// RemoteLogin signs in to an SFTP server or a WebDAV share
+type RemoteLogin struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// KeyFile is a private key for SFTP servers
+	KeyFile string `json:"key_file,omitempty"`
+}
+
+// UseProvider switches the AI provider for this run only, as --provider
+// does. Saving the configuration keeps the configured provider unless it
+// is changed again.
+func (c *Config) UseProvider(name string) {
+	if c.runProvider == "" {
+		c.savedProvider = c.AIProvider
+	}
+	c.AIProvider = name
+	c.runProvider = name
+}
+
+// Accessible reports whether output should be plain linear text for screen
+// readers, as configured or as LUMO_A11Y asks for this run
+func (c *Config) Accessible() bool {
+	if c.accessibleRun != nil {
+		return *c.accessibleRun
+	}
+	return c.AccessibleOutput
+}
+
+// Agent safety levels, from the most confirmations to the fewest
+const (
+	// AgentSafetyStrict confirms every critical or destructive step
+	AgentSafetyStrict = "strict"
+	// AgentSafetyNormal confirms destructive steps only
+	AgentSafetyNormal = "normal"
+	// AgentSafetyFast confirms only the plan as a whole
+	AgentSafetyFast = "fast"
+)
+
+// What happens to cloud AI requests once the monthly budget is spent
+const (
+	// BudgetWarn warns before each request but still sends it
+	BudgetWarn = "warn"
+	// BudgetBlock refuses requests until the next month or a higher budget
+	BudgetBlock = "block"
+)
+
+// What happens once the monthly network cap is reached
+const (
+	// NetworkCapWarn logs a warning and carries on
+	NetworkCapWarn = "warn"
+	// NetworkCapPause also skips scheduled speed tests until next month
+	NetworkCapPause = "pause"
+)
+
+// AgentSafety returns the agent safety level. Configs written before the
+// levels were enforced said "low", "medium", or "high".
+func (c *Config) AgentSafety() string {
+	switch strings.ToLower(c.AgentSafetyLevel) {
+	case AgentSafetyStrict, "high":
+		return AgentSafetyStrict
+	case AgentSafetyFast, "low":
+		return AgentSafetyFast
+	default:
+		return AgentSafetyNormal
+	}
+}
+
+// NetworkCapBytes returns the monthly network cap in bytes, or 0 for none
+func (c *Config) NetworkCapBytes() int64 {
+	return int64(max(c.NetworkCapMB, 0)) << 20
+}
+
+// defaultConcurrency returns the default limits on requests in flight to
+// each AI provider. A local Ollama server answers one request at a time.
+func defaultConcurrency() map[string]int {
+	return map[string]int{"gemini": 4, "openai": 4, "ollama": 1}
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
+		SchemaVersion:               CurrentSchemaVersion,
 		AIProvider:                  "gemini",                 // Default to Gemini
 		GeminiAPIKey:                "",                       // Will be loaded from environment
 		GeminiModel:                 "gemini-2.0-flash-lite",  // Default Gemini model
@@ -76,30 +335,79 @@ func DefaultConfig() *Config {
 		OpenAIModel:                 "gpt-3.5-turbo",          // Default OpenAI model
 		OllamaURL:                   "http://localhost:11434", // Default Ollama URL
 		OllamaModel:                 "llama3",                 // Default Ollama model
+		ProviderConcurrency:         defaultConcurrency(),     // Stay under the providers' rate limits
+		ProviderQueueTimeoutSeconds: 60,                       // Wait up to a minute for a free slot
 		MaxHistorySize:              1000,
 		EnableLogging:               true,
 		EnableShellInInteractive:    false,    // Shell commands disabled in interactive mode by default
 		CommandFirstMode:            false,    // Default to AI-first mode (treat input as AI queries by default)
+		LocalIntentRouting:          true,     // Handle obvious health, report, config, and inspection commands without the AI
+		LocalIntentModel:            false,    // The embedded intent model is opt-in; rules alone route by default
 		EnableAgentMode:             true,     // Agent mode enabled by default
 		EnableAgentREPL:             true,     // REPL mode enabled by default
 		AgentConfirmBeforeExecution: true,     // Confirm before execution by default
 		AgentMaxSteps:               10,       // Maximum 10 steps by default
-		AgentSafetyLevel:            "medium", // Medium safety level by default
+		AgentSafetyLevel:            "normal", // Confirm destructive commands such as rm and dd before they run
+		AgentStepRetries:            0,        // Failed steps are not retried automatically by default
+		AgentMaxIterations:          3,        // Revise the remaining plan from step output up to twice; 1 plans only once
+		AgentDenyList: []string{ // Commands the agent never runs, whatever the safety level
+			"rm -rf /", "rm -rf /*", "rm -rf ~", "rm -rf ~/*",
+			"mkfs*", "dd * of=/dev/sd*", "dd * of=/dev/nvme*",
+			"chmod -R * /", "chown -R * /", ":(){ :|:& };:",
+		},
 		EnableChatREPL:              true,     // Chat REPL mode enabled by default
+		SaveChatHistory:             true,     // Keep conversations for chat:history
+		ChatAutoTitle:               true,     // Title and tag saved conversations with a short AI request
+		DefaultPersona:              "",       // Use Lumo's own instructions unless a persona is chosen
+		EnableResponseCache:         true,     // Answer repeated questions from the cache
+		ResponseCacheTTLMinutes:     1440,     // Cached answers are used for a day
+		ResponseCacheSize:           200,      // Keep the 200 most recent answers
 		EnablePipeProcessing:        true,     // Pipe processing enabled by default
+		AutoCopyCode:                false,    // Copy code blocks from AI answers only when --copy is given
+		EnableClipboardHistory:      false,    // Nothing copied is recorded until the user turns it on
+		ClipboardHistoryLimit:       100,      // Keep the 100 most recent copies, besides pinned ones
+		BudgetUSD:                   0,        // No spending limit until one is set
+		BudgetAction:                "warn",   // Warn rather than block once the budget is spent
+		NetworkCapMB:                0,        // No network cap until one is set
+		NetworkCapAction:            "warn",   // Warn rather than pause speed tests once the cap is reached
+		TimeFormat:                  "local",  // Show "3m ago" and local timestamps
 		EnableSystemHealth:          true,     // System health checks enabled by default
 		EnableSystemReport:          true,     // System reports enabled by default
 		EnableSpeedTest:             true,     // Speed test feature enabled by default
 		SpeedTestTimeout:            30,       // 30 seconds timeout for speed tests
 		EnableDesktopAssistant:      true,     // Desktop assistant enabled by default
 		DefaultDesktopEnv:           "auto",   // Auto-detect desktop environment by default
+		DesktopConfirmPower:         "always", // Ask before shutting down, suspending, or logging out
 		EnableServer:                false,    // REST server disabled by default
 		ServerPort:                  7531,     // Default port for the REST server (uncommon port)
 		ServerQuietOutput:           true,     // Suppress server log messages by default
+		ServerRateLimit:             60,       // Requests per minute for each client
+		ServerRateBurst:             20,       // Requests a client may make at once
+		ServerMaxBodyKB:             1024,     // Request bodies up to 1 MB
+		ScheduledSpeedTestHours:     0,        // Scheduled speed tests disabled by default
+		ScheduledHealthCheckMinutes: 0,        // Scheduled health checks disabled by default
+		IdleThresholdMinutes:        5,        // User counts as idle after 5 minutes without input
+		IdleMaxDelayMinutes:         120,      // Never defer a noisy task for more than 2 hours
+		NightLightStart:             "",       // Night light schedule disabled by default
+		NightLightEnd:               "",       // e.g. "06:30" together with a start of "21:00"
+		NightLightTemperature:       0,        // Keep the desktop's color temperature by default
+		PowerMode:                   "auto",   // Detect battery power automatically
+		BatteryPreferLocalModel:     false,    // Keep the configured provider on battery by default
+		BatterySkipSpeedTests:       true,     // Skip scheduled speed tests on battery
+		BatteryHealthCheckFactor:    4,        // Run scheduled health checks 4x less often on battery
 		EnableAuth:                  true,     // Authentication enabled by default
 		JWTSecret:                   "",       // Will be generated on first run
 		TokenExpirationHours:        24,       // 24 hours token expiration
 		RefreshExpirationDays:       7,        // 7 days refresh token expiration
+		NoisyTasks:                  []string{"speedtest", "indexing"},
+		SpeedTestBackend:            "builtin",
+		PrivacyMode:                 "standard",
+		EnableMetrics:               false, // Nothing is collected until the user agrees
+		EnableTimeTracking:          false, // Nothing is tracked until the user turns it on
+		EnableSnippetExpansion:      false, // The keyboard is not read until the user turns it on
+		SnippetPrefix:               ";",
+		DiscoveryTransport:          "mdns",
+		DiscoveryAdvertise:          true,
 		Debug:                       false,
 	}
 }
@@ -110,6 +418,7 @@ func Load() (*Config, error) {
 	cfg := DefaultConfig()
 
 	// Try to load from config file
+	unreadable := false
 	if err := cfg.loadFromFile(); err != nil {
 		// If file doesn't exist, create it with default values
 		if os.IsNotExist(err) {
@@ -117,8 +426,12 @@ func Load() (*Config, error) {
 				fmt.Fprintf(os.Stderr, "Warning: Could not create config file: %v\n", err)
 			}
 		} else {
+			unreadable = true
 			fmt.Fprintf(os.Stderr, "Warning: Could not load config file: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Run 'lumo config:restore-backup' to go back to the last version that was saved.")
 		}
+	} else {
+		cfg.saveMigration()
 	}
 
 	// Load API keys from environment variables
@@ -130,6 +443,19 @@ func Load() (*Config, error) {
 		cfg.OpenAIAPIKey = openaiKey
 	}
 
+	// LUMO_MOCK=1 answers from the mock provider for this run, e.g. in CI
+	if fixtures := os.Getenv("LUMO_MOCK_FIXTURES"); fixtures != "" {
+		cfg.MockFixtures = fixtures
+	}
+	if mock, _ := strconv.ParseBool(os.Getenv("LUMO_MOCK")); mock {
+		cfg.UseProvider("mock")
+	}
+
+	// LUMO_A11Y=1 switches accessible output on for this run, and 0 off
+	if accessible, err := strconv.ParseBool(os.Getenv("LUMO_A11Y")); err == nil {
+		cfg.accessibleRun = &accessible
+	}
+
 	// Generate JWT secret if not set
 	if cfg.JWTSecret == "" {
 		// Generate a random 32-byte secret
@@ -140,7 +466,11 @@ func Load() (*Config, error) {
 		}
 		cfg.JWTSecret = base64.StdEncoding.EncodeToString(secretBytes)
 
-		// Save the updated configuration
+		// Save the updated configuration, unless that would replace a
+		// file that could still be repaired or restored
+		if unreadable {
+			return cfg, nil
+		}
 		if err := cfg.Save(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Could not save JWT secret to config file: %v\n", err)
 		}
@@ -167,8 +497,12 @@ func (c *Config) loadFromFile() error {
 		return err
 	}
 
-	// Parse JSON
-	return json.Unmarshal(data, c)
+	// Parse JSON, upgrading files written by older versions
+	c.migration, err = c.decodeMigrated(data)
+	if err == nil {
+		c.loaded = data
+	}
+	return err
 }
 
 // Save saves the configuration to file
@@ -184,22 +518,41 @@ func (c *Config) Save() error {
 		return err
 	}
 
+	// A provider chosen for this run only is not saved
+	saved := *c
+	saved.SchemaVersion = max(saved.SchemaVersion, CurrentSchemaVersion)
+	if c.runProvider != "" && c.AIProvider == c.runProvider {
+		saved.AIProvider = c.savedProvider
+	}
+
+	// Other lumo processes, like the daemon, wait while this one saves,
+	// and what they saved since this configuration was loaded is kept
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if disk, err := os.ReadFile(configPath); err == nil && c.loaded != nil && !bytes.Equal(disk, c.loaded) {
+		if err := c.mergeSaved(configPath, &saved, disk); err != nil {
+			return err
+		}
+	}
+
 	// Marshal to JSON
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(&saved, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	// Write to file
-	return os.WriteFile(configPath, data, 0644)
+	// Write to file, keeping the previous one as a backup
+	if err := writeConfigFile(configPath, data); err != nil {
+		return err
+	}
+	c.loaded = data
+	return nil
 }
 
 // getConfigFilePath returns the path to the config file
 func getConfigFilePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	return filepath.Join(homeDir, ".config", "lumo", "config.json"), nil
+	return paths.ConfigFile()
 }
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature is an optional part of lumo that is switched on and off with
+// config:feature
+type Feature struct {
+	// Name identifies the feature in config:feature, e.g. "agent-repl"
+	Name string
+	// Description is shown in feature listings and the help screen
+	Description string
+	// Requires are the features that must be enabled for this one to work
+	Requires []string
+	// flag returns the configuration field that enables the feature
+	flag func(c *Config) *bool
+}
+
+// Features are the optional features, in the order they are listed
+var Features = []Feature{
+	{Name: "shell", Description: "Shell in interactive mode", flag: func(c *Config) *bool { return &c.EnableShellInInteractive }},
+	{Name: "agent", Description: "Agent mode", flag: func(c *Config) *bool { return &c.EnableAgentMode }},
+	{Name: "agent-repl", Description: "Agent REPL mode", Requires: []string{"agent"}, flag: func(c *Config) *bool { return &c.EnableAgentREPL }},
+	{Name: "chat-repl", Description: "Chat REPL mode", flag: func(c *Config) *bool { return &c.EnableChatREPL }},
+	{Name: "pipe", Description: "Pipe processing", flag: func(c *Config) *bool { return &c.EnablePipeProcessing }},
+	{Name: "health", Description: "System health checks", flag: func(c *Config) *bool { return &c.EnableSystemHealth }},
+	{Name: "report", Description: "System reports", flag: func(c *Config) *bool { return &c.EnableSystemReport }},
+	{Name: "speedtest", Description: "Speed test", flag: func(c *Config) *bool { return &c.EnableSpeedTest }},
+	{Name: "server", Description: "REST server", flag: func(c *Config) *bool { return &c.EnableServer }},
+	{Name: "auth", Description: "REST server authentication", flag: func(c *Config) *bool { return &c.EnableAuth }},
+	{Name: "response-cache", Description: "Response cache", flag: func(c *Config) *bool { return &c.EnableResponseCache }},
+	{Name: "logging", Description: "Command logging", flag: func(c *Config) *bool { return &c.EnableLogging }},
+	{Name: "metrics", Description: "Local usage metrics", flag: func(c *Config) *bool { return &c.EnableMetrics }},
+	{Name: "time-tracking", Description: "Time tracking", flag: func(c *Config) *bool { return &c.EnableTimeTracking }},
+	{Name: "clipboard-history", Description: "Clipboard history", flag: func(c *Config) *bool { return &c.EnableClipboardHistory }},
+	{Name: "snippet-expansion", Description: "Snippet expansion", flag: func(c *Config) *bool { return &c.EnableSnippetExpansion }},
+	{Name: "accessibility", Description: "Screen reader friendly output", flag: func(c *Config) *bool { return &c.AccessibleOutput }},
+	{Name: "local-routing", Description: "Local intent routing", flag: func(c *Config) *bool { return &c.LocalIntentRouting }},
+	{Name: "intent-model", Description: "Local intent model", Requires: []string{"local-routing"}, flag: func(c *Config) *bool { return &c.LocalIntentModel }},
+}
+
+// LookupFeature returns the feature with the given name
+func LookupFeature(name string) (Feature, bool) {
+	for _, feature := range Features {
+		if feature.Name == name {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// FeatureNames returns the names of the features in listing order
+func FeatureNames() []string {
+	names := make([]string, len(Features))
+	for i, feature := range Features {
+		names[i] = feature.Name
+	}
+	return names
+}
+
+// FeatureEnabled reports whether the named feature is enabled; unknown
+// features are not
+func (c *Config) FeatureEnabled(name string) bool {
+	feature, ok := LookupFeature(name)
+	return ok && *feature.flag(c)
+}
+
+// MissingRequirements returns the features the named feature requires that
+// are disabled
+func (c *Config) MissingRequirements(name string) []string {
+	feature, _ := LookupFeature(name)
+	var missing []string
+	for _, required := range feature.Requires {
+		if !c.FeatureEnabled(required) {
+			missing = append(missing, required)
+		}
+	}
+	return missing
+}
+
+// EnableFeature enables a feature whose requirements are enabled
+func (c *Config) EnableFeature(name string) error {
+	feature, ok := LookupFeature(name)
+	if !ok {
+		return fmt.Errorf("unknown feature: %s (available: %s)", name, strings.Join(FeatureNames(), ", "))
+	}
+	if missing := c.MissingRequirements(name); len(missing) > 0 {
+		return fmt.Errorf("%s requires %s; enable it first with 'config:feature enable %s'",
+			name, strings.Join(missing, " and "), strings.Join(missing, " "))
+	}
+	*feature.flag(c) = true
+	return nil
+}
+
+// DisableFeature disables a feature along with the enabled features that
+// require it, and returns the names of those
+func (c *Config) DisableFeature(name string) ([]string, error) {
+	feature, ok := LookupFeature(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown feature: %s (available: %s)", name, strings.Join(FeatureNames(), ", "))
+	}
+	*feature.flag(c) = false
+
+	var dependents []string
+	for _, other := range Features {
+		for _, required := range other.Requires {
+			if required == name && *other.flag(c) {
+				more, _ := c.DisableFeature(other.Name)
+				dependents = append(append(dependents, other.Name), more...)
+			}
+		}
+	}
+	return dependents, nil
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldConflict is a setting that was changed both in the file and in
+// memory since the file was loaded
+type FieldConflict struct {
+	// Field is the setting's name in the configuration file
+	Field string
+	// Saved and Ours are its value in the file and in memory, with secrets
+	// hidden
+	Saved string
+	Ours  string
+}
+
+// ConflictError is returned by Save when another lumo process, such as the
+// daemon, saved different values for the same settings since this one
+// loaded them. Nothing is saved, so neither change is lost.
+type ConflictError struct {
+	Path      string
+	Conflicts []FieldConflict
+}
+
+func (e *ConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s was changed by another lumo process since it was loaded, and these settings differ:", e.Path)
+	for _, conflict := range e.Conflicts {
+		fmt.Fprintf(&b, "\n  • %s: %s in the file, %s here", conflict.Field, conflict.Saved, conflict.Ours)
+	}
+	b.WriteString("\nNothing was saved; run the command again to change the current settings.")
+	return b.String()
+}
+
+// lockConfig takes the lock that lumo processes hold while they read and
+// write the configuration file at path, and returns the function that
+// releases it
+func lockConfig(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open configuration lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock configuration: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// mergeSaved brings the settings another process changed in the file since
+// c loaded it into saved, the configuration about to be written, and into
+// c. Settings changed in both places to different values are a
+// ConflictError. A file that cannot be parsed has nothing to merge.
+func (c *Config) mergeSaved(path string, saved *Config, disk []byte) error {
+	base, err := fieldValues(c.loaded)
+	if err != nil {
+		return nil
+	}
+	theirs, err := fieldValues(disk)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	ours, err := fieldValues(data)
+	if err != nil {
+		return err
+	}
+
+	// Settings the other process cleared are missing from the file
+	names := make(map[string]bool)
+	for name := range base {
+		names[name] = true
+	}
+	for name := range theirs {
+		names[name] = true
+	}
+
+	changed := make(map[string]json.RawMessage)
+	var conflicts []FieldConflict
+	for name := range names {
+		value := theirs[name]
+		if _, known := ours[name]; !known && !isField(name) {
+			// Settings from a newer lumo are not kept anyway
+			continue
+		}
+		if sameValue(value, base[name]) {
+			continue
+		}
+		if sameValue(ours[name], base[name]) || sameValue(ours[name], value) {
+			changed[name] = value
+			continue
+		}
+		conflicts = append(conflicts, FieldConflict{
+			Field: name,
+			Saved: displayValue(name, value),
+			Ours:  displayValue(name, ours[name]),
+		})
+	}
+	if len(conflicts) > 0 {
+		sort.Slice(conflicts, func(i, j int) bool {
+			return conflicts[i].Field < conflicts[j].Field
+		})
+		return &ConflictError{Path: path, Conflicts: conflicts}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	subset, err := json.Marshal(changed)
+	if err != nil {
+		return err
+	}
+	if err := applyFields(saved, changed, subset); err != nil {
+		return err
+	}
+	// A provider chosen for this run only stays in use
+	provider := c.AIProvider
+	if err := applyFields(c, changed, subset); err != nil {
+		return err
+	}
+	if c.runProvider != "" {
+		c.savedProvider = c.AIProvider
+		c.AIProvider = provider
+	}
+	return nil
+}
+
+// fieldValues splits a configuration file into its settings, compacted so
+// they compare equal however the file was indented
+func fieldValues(data []byte) (map[string]json.RawMessage, error) {
+	if data == nil {
+		return nil, fmt.Errorf("no configuration")
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err == nil {
+			fields[name] = compact.Bytes()
+		}
+	}
+	return fields, nil
+}
+
+// sameValue reports whether two settings are equal; a missing setting only
+// equals another missing one
+func sameValue(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return bytes.Equal(a, b)
+}
+
+// isField reports whether name is a setting of Config, which it may leave
+// out of the file when it is empty
+func isField(name string) bool {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFields sets the named settings of c from subset. They are cleared
+// first, so maps and lists are replaced rather than merged into.
+func applyFields(c *Config, names map[string]json.RawMessage, subset []byte) error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := names[jsonName(t.Field(i))]; ok {
+			v.Field(i).Set(reflect.Zero(t.Field(i).Type))
+		}
+	}
+	return json.Unmarshal(subset, c)
+}
+
+// jsonName returns the name a struct field has in JSON, or "" for fields
+// that are not encoded
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// displayValue shows a setting in a conflict message, hiding keys,
+// passwords, and tokens
+func displayValue(name string, value json.RawMessage) string {
+	if value == nil {
+		return "not set"
+	}
+	if value[0] == '{' {
+		// Maps such as remote logins can hold passwords
+		return "{...}"
+	}
+	lower := strings.ToLower(name)
+	for _, secret := range []string{"key", "secret", "password", "token"} {
+		if strings.Contains(lower, secret) && value[0] == '"' && string(value) != `""` {
+			return "(hidden)"
+		}
+	}
+	if len(value) > 40 {
+		return string(value[:37]) + "..."
+	}
+	return string(value)
+}
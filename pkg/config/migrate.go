@@ -0,0 +1,173 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/agnath18K/lumo/pkg/deprecation"
+)
+
+// CurrentSchemaVersion is the version of the configuration file this lumo
+// writes. Files without a schema_version are version 0.
+const CurrentSchemaVersion = 1
+
+// Migration upgrades the configuration file from the version before it
+type Migration struct {
+	// Version is the schema version the migration upgrades to
+	Version int
+	// Description says what the migration changes
+	Description string
+	// Apply changes the decoded file in place, and describes each change
+	Apply func(raw map[string]interface{}) []string
+}
+
+// Migrations upgrade older configuration files, in order of version
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Rewrite deprecated command prefixes to their replacements",
+		Apply:       rewriteDeprecatedCommands,
+	},
+}
+
+// MigrationResult describes how a configuration file was upgraded
+type MigrationResult struct {
+	From, To int
+	// Changes describes each setting that changed
+	Changes []string
+	// Backup is the copy of the file made before it changed, if any
+	Backup string
+}
+
+// schemaVersion reads the schema version of a decoded configuration file
+func schemaVersion(raw map[string]interface{}) int {
+	version, _ := raw["schema_version"].(float64)
+	return int(version)
+}
+
+// Migrate runs the migrations a decoded configuration file has not had yet,
+// and returns what changed. A file from a newer lumo is left alone.
+func Migrate(raw map[string]interface{}) MigrationResult {
+	result := MigrationResult{From: schemaVersion(raw)}
+	result.To = result.From
+	for _, migration := range Migrations {
+		if migration.Version <= result.From {
+			continue
+		}
+		result.Changes = append(result.Changes, migration.Apply(raw)...)
+		result.To = migration.Version
+	}
+	if result.To > result.From {
+		raw["schema_version"] = result.To
+	}
+	return result
+}
+
+// rewriteDeprecatedCommands replaces renamed command prefixes in settings
+// whose value is a lumo command. Retired prefixes are rewritten too, since
+// they no longer work as they are.
+func rewriteDeprecatedCommands(raw map[string]interface{}) []string {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	for _, key := range keys {
+		raw[key] = rewriteValue(key, raw[key], &changes)
+	}
+	return changes
+}
+
+// rewriteValue rewrites the commands in a setting's value, which may be a
+// string, or a list or map of them
+func rewriteValue(key string, value interface{}, changes *[]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if prefix, ok := deprecation.Lookup(v); ok {
+			rewritten := prefix.Apply(v)
+			*changes = append(*changes, fmt.Sprintf("%s: %q → %q (%s)", key, v, rewritten, prefix.Code))
+			return rewritten
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = rewriteValue(key, v[i], changes)
+		}
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v[name] = rewriteValue(key+"."+name, v[name], changes)
+		}
+	}
+	return value
+}
+
+// decodeMigrated decodes a configuration file into c, migrating it first
+func (c *Config) decodeMigrated(data []byte) (MigrationResult, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return MigrationResult{}, err
+	}
+
+	result := Migrate(raw)
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(migrated, c); err != nil {
+		return result, err
+	}
+	c.SchemaVersion = result.To
+	return result, nil
+}
+
+// saveMigration saves the file Load upgraded. When settings changed, the
+// original is backed up to <file>.v<version>.bak first, and the changes
+// are reported.
+func (c *Config) saveMigration() {
+	result := &c.migration
+	if result.From > CurrentSchemaVersion {
+		fmt.Fprintf(os.Stderr, "Warning: the configuration file is from a newer lumo (schema version %d, this one knows %d); saving settings may drop ones this version does not know\n", result.From, CurrentSchemaVersion)
+		return
+	}
+	if result.To == result.From {
+		return
+	}
+
+	if len(result.Changes) > 0 {
+		path, err := getConfigFilePath()
+		if err != nil {
+			return
+		}
+		original, err := os.ReadFile(path)
+		if err == nil {
+			result.Backup = fmt.Sprintf("%s.v%d.bak", path, result.From)
+			err = os.WriteFile(result.Backup, original, 0600)
+		}
+		if err != nil {
+			// Without a backup the upgrade stays in memory, and the file as it was
+			fmt.Fprintf(os.Stderr, "Warning: Could not back up config file before upgrading it: %v\n", err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Upgraded the configuration to schema version %d (the original is in %s):\n", result.To, result.Backup)
+		for _, change := range result.Changes {
+			fmt.Fprintf(os.Stderr, "  • %s\n", change)
+		}
+	}
+
+	if err := c.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not save upgraded config file: %v\n", err)
+	}
+}
+
+// Migration returns how Load upgraded the configuration file
+func (c *Config) Migration() MigrationResult {
+	return c.migration
+}
@@ -0,0 +1,242 @@
+package connect
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dirArchiveExt marks a transferred file as a packed directory that the
+// receiver extracts instead of saving
+const dirArchiveExt = ".lumodir.tar.gz"
+
+// isDirectoryArchive reports whether a received file is a packed directory
+func isDirectoryArchive(filename string) bool {
+	return strings.HasSuffix(filename, dirArchiveExt) && len(filename) > len(dirArchiveExt)
+}
+
+// packDirectory streams dir into a gzipped tar archive in a temporary
+// directory, printing each file as it is added. The archive is named after
+// dir so it can be sent like any other file; cleanup removes it.
+func packDirectory(dir string) (archivePath string, cleanup func(), err error) {
+	dir = filepath.Clean(dir)
+	var total int
+	var totalSize int64
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total++
+			totalSize += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "lumo-connect-dir-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(tempDir) }
+
+	name := safeFilename(filepath.Base(dir))
+	archivePath = filepath.Join(tempDir, name+dirArchiveExt)
+	file, err := os.Create(archivePath)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer file.Close()
+
+	progress.printf("\033[1;32m📦 Packing folder: %s (%d files, %s)\033[0m\n", name, total, formatFileSize(totalSize))
+
+	gz, _ := gzip.NewWriterLevel(file, gzip.BestSpeed)
+	tw := tar.NewWriter(gz)
+	count := 0
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		// Only directories and regular files are sent; links and devices
+		// could point outside the folder on the receiver
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			progress.printf("\033[1;33m⚠️ Skipping %s: not a regular file\033[0m\n", rel)
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		count++
+		progress.printf("\033[1;32m  [%d/%d] %s (%s)\033[0m\n", count, total, header.Name, formatFileSize(info.Size()))
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		file.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to pack directory: %w", err)
+	}
+	return archivePath, cleanup, nil
+}
+
+// extractDirectory unpacks a directory archive from r into downloadDir and
+// returns the path of the new directory. Files are extracted into a hidden
+// directory first, so a broken archive never leaves a partial folder behind.
+func extractDirectory(r io.Reader, downloadDir, filename string) (string, error) {
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+	tempDir, err := os.MkdirTemp(downloadDir, ".lumo-extract-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	count := 0
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		rel, err := archiveEntryPath(header.Name)
+		if err != nil {
+			return "", err
+		}
+		target := filepath.Join(tempDir, rel)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return "", fmt.Errorf("failed to create directory: %w", err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return "", fmt.Errorf("failed to create directory: %w", err)
+			}
+			// Keep the executable bit, but nothing more permissive
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0755|0644)
+			if err != nil {
+				return "", fmt.Errorf("failed to create file: %w", err)
+			}
+			_, err = io.Copy(out, tr)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to extract %s: %w", rel, err)
+			}
+			count++
+		default:
+			// Senders only pack directories and regular files
+			continue
+		}
+	}
+
+	dirPath := timestampedPath(downloadDir, safeFilename(strings.TrimSuffix(filename, dirArchiveExt)))
+	if err := os.Rename(tempDir, dirPath); err != nil {
+		return "", fmt.Errorf("failed to save directory: %w", err)
+	}
+	fmt.Printf("\033[1;36m📂 Extracted %d files into %s\033[0m\n", count, dirPath)
+	return dirPath, nil
+}
+
+// archiveEntryPath turns the name of an archive entry into a relative path
+// that stays inside the extraction directory
+func archiveEntryPath(name string) (string, error) {
+	var parts []string
+	for _, part := range strings.Split(strings.ReplaceAll(name, `\`, "/"), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			return "", fmt.Errorf("archive entry escapes the directory: %s", name)
+		}
+		parts = append(parts, safeFilename(part))
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("archive entry has no name: %q", name)
+	}
+	return filepath.Join(parts...), nil
+}
+
+// placeReceived moves a fully received temporary file into downloadDir under
+// a timestamped name and returns its new path. Directory archives are
+// extracted there instead.
+func placeReceived(tempPath, downloadDir, filename string) (string, error) {
+	if isDirectoryArchive(filename) {
+		file, err := os.Open(tempPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer os.Remove(tempPath)
+		defer file.Close()
+		return extractDirectory(file, downloadDir, filename)
+	}
+
+	filePath := timestampedPath(downloadDir, safeFilename(filename))
+	if err := os.Rename(tempPath, filePath); err != nil {
+		// If rename fails (e.g., across different filesystems), try copy
+		if err := copyFile(tempPath, filePath); err != nil {
+			return "", fmt.Errorf("failed to move file: %w", err)
+		}
+		os.Remove(tempPath)
+	}
+	os.Chmod(filePath, 0644)
+	return filePath, nil
+}
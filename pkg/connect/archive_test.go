@@ -0,0 +1,93 @@
+package connect
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackAndExtractDirectory(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "project")
+	files := map[string]string{
+		"README.md":         "hello",
+		"src/main.go":       "package main",
+		"src/util/util.go":  "package util",
+		"assets/empty.txt":  "",
+		"assets/nested/a.b": strings.Repeat("x", 100000),
+	}
+	for name, content := range files {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(source, "emptydir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	archivePath, cleanup, err := packDirectory(source)
+	if err != nil {
+		t.Fatalf("packDirectory failed: %v", err)
+	}
+	defer cleanup()
+	if filepath.Base(archivePath) != "project"+dirArchiveExt {
+		t.Errorf("Unexpected archive name %s", filepath.Base(archivePath))
+	}
+
+	downloadDir := t.TempDir()
+	dirPath, err := placeReceived(archivePath, downloadDir, filepath.Base(archivePath))
+	if err != nil {
+		t.Fatalf("placeReceived failed: %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(dirPath), "project_") {
+		t.Errorf("Unexpected directory name %s", dirPath)
+	}
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(dirPath, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("Missing %s: %v", name, err)
+		} else if string(got) != content {
+			t.Errorf("%s has different content", name)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dirPath, "emptydir")); err != nil || !info.IsDir() {
+		t.Error("Empty directory was not recreated")
+	}
+
+	// Only the extracted directory is left behind
+	entries, _ := os.ReadDir(downloadDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the extracted directory, found %d entries", len(entries))
+	}
+}
+
+func TestExtractDirectoryRejectsEscapes(t *testing.T) {
+	var buffer bytes.Buffer
+	gz := gzip.NewWriter(&buffer)
+	tw := tar.NewWriter(gz)
+	content := []byte("owned")
+	tw.WriteHeader(&tar.Header{Name: "../../evil.txt", Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+
+	parent := t.TempDir()
+	downloadDir := filepath.Join(parent, "downloads")
+	if _, err := extractDirectory(&buffer, downloadDir, "bad"+dirArchiveExt); err == nil {
+		t.Fatal("Expected an archive escaping the directory to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "evil.txt")); err == nil {
+		t.Error("File was written outside the download directory")
+	}
+	entries, _ := os.ReadDir(downloadDir)
+	if len(entries) != 0 {
+		t.Errorf("Expected no partial directory, found %d entries", len(entries))
+	}
+}
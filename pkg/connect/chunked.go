@@ -1,15 +1,20 @@
 package connect
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/agnath18K/lumo/pkg/utils"
 )
 
 const (
@@ -27,6 +32,12 @@ const (
 
 	// DefaultDownloadTimeout is the default timeout for downloads (1 hour)
 	DefaultDownloadTimeout = 1 * time.Hour
+
+	// staleCheckInterval is how often the janitor looks for abandoned uploads
+	staleCheckInterval = 5 * time.Minute
+
+	// tempDirPattern names the temporary directories that hold partial uploads
+	tempDirPattern = "lumo-connect-uploads-*"
 )
 
 // ChunkInfo represents information about a file chunk
@@ -42,6 +53,7 @@ type UploadInfo struct {
 	UploadID    string      `json:"upload_id"`
 	Filename    string      `json:"filename"`
 	FileSize    int64       `json:"file_size"`
+	FileHash    string      `json:"file_hash,omitempty"` // SHA-256 of the whole file, if the sender provided one
 	ChunkSize   int64       `json:"chunk_size"`
 	TotalChunks int         `json:"total_chunks"`
 	Chunks      []ChunkInfo `json:"chunks,omitempty"`
@@ -49,6 +61,8 @@ type UploadInfo struct {
 	EndTime     time.Time   `json:"end_time,omitempty"`
 	Status      string      `json:"status"` // "pending", "in_progress", "completed", "failed"
 	TempPath    string      `json:"-"`      // Path to temporary file (not exposed in JSON)
+	LastActive  time.Time   `json:"-"`      // When a chunk was last received
+	Received    chunkBitmap `json:"-"`      // Which chunks have been written
 }
 
 // DownloadInfo represents information about a file download
@@ -72,26 +86,36 @@ type ChunkedTransferManager struct {
 	downloadsMutex sync.RWMutex
 	downloads      map[string]*DownloadInfo
 	tempDir        string
+	stateDir       string   // Where upload progress is recorded; empty when uploads do not survive a restart
+	staging        *Staging // Encrypts uploads and holds them for approval; nil saves them directly
 	downloadPath   string
 	chunkSize      int64
 }
 
 // NewChunkedTransferManager creates a new chunked transfer manager
 func NewChunkedTransferManager(downloadPath string, chunkSize int64) (*ChunkedTransferManager, error) {
+	// Remove partial uploads left behind by sessions that did not exit cleanly
+	removeStaleTempDirs(DefaultUploadTimeout)
+
 	// Create a temporary directory for uploads
-	tempDir, err := os.MkdirTemp("", "lumo-connect-uploads-*")
+	tempDir, err := os.MkdirTemp("", tempDirPattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
 	}
 
+	manager, err := newChunkedTransferManager(downloadPath, tempDir, chunkSize)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, err
+	}
+	return manager, nil
+}
+
+// newChunkedTransferManager creates a manager that keeps partial uploads in tempDir
+func newChunkedTransferManager(downloadPath, tempDir string, chunkSize int64) (*ChunkedTransferManager, error) {
 	// Set default download path if not provided
 	if downloadPath == "" {
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			downloadPath = filepath.Join(homeDir, "Downloads")
-		} else {
-			downloadPath = "."
-		}
+		downloadPath = utils.DefaultDownloadDir()
 	}
 
 	// Create the download directory if it doesn't exist
@@ -117,12 +141,86 @@ func NewChunkedTransferManager(downloadPath string, chunkSize int64) (*ChunkedTr
 	}, nil
 }
 
-// Cleanup cleans up temporary files and directories
+// UseStaging encrypts chunks as they arrive and hands finished uploads to
+// staging instead of the download directory. It must be called before any
+// upload starts.
+func (m *ChunkedTransferManager) UseStaging(staging *Staging) {
+	m.staging = staging
+}
+
+// Cleanup cleans up temporary files and directories. A persistent manager
+// keeps its partial uploads so they can be resumed after a restart.
 func (m *ChunkedTransferManager) Cleanup() error {
+	if m.stateDir != "" {
+		return nil
+	}
 	// Remove the temporary directory
 	return os.RemoveAll(m.tempDir)
 }
 
+// CleanupStale removes uploads that have not received a chunk within maxAge,
+// along with their partial files, and forgets completed uploads. It returns
+// the number of unfinished uploads that were removed.
+func (m *ChunkedTransferManager) CleanupStale(maxAge time.Duration) int {
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+
+	m.uploadsMutex.Lock()
+	defer m.uploadsMutex.Unlock()
+	for id, upload := range m.uploads {
+		if upload.Status == "completed" {
+			delete(m.uploads, id)
+			continue
+		}
+		if upload.LastActive.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(upload.TempPath); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to remove partial upload %s: %v", upload.TempPath, err)
+		}
+		m.removeState(id)
+		delete(m.uploads, id)
+		removed++
+	}
+	return removed
+}
+
+// RunJanitor removes abandoned uploads every few minutes until the context is cancelled
+func (m *ChunkedTransferManager) RunJanitor(ctx context.Context) {
+	ticker := time.NewTicker(staleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if removed := m.CleanupStale(DefaultUploadTimeout); removed > 0 {
+				log.Printf("Removed %d abandoned upload(s)", removed)
+			}
+		}
+	}
+}
+
+// removeStaleTempDirs deletes upload directories of other sessions that
+// have not changed within maxAge
+func removeStaleTempDirs(maxAge time.Duration) {
+	dirs, err := filepath.Glob(filepath.Join(os.TempDir(), tempDirPattern))
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-maxAge)
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Warning: Failed to remove stale upload directory %s: %v", dir, err)
+		}
+	}
+}
+
 // generateID generates a random ID for uploads and downloads
 func generateID() (string, error) {
 	// Generate 16 random bytes
@@ -133,8 +231,9 @@ func generateID() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// InitUpload initializes a file upload
-func (m *ChunkedTransferManager) InitUpload(filename string, fileSize int64) (*UploadInfo, error) {
+// InitUpload initializes a file upload. fileHash is the hex-encoded SHA-256
+// of the whole file; when given, the assembled file is checked against it.
+func (m *ChunkedTransferManager) InitUpload(filename string, fileSize int64, fileHash string) (*UploadInfo, error) {
 	// Generate a unique upload ID
 	uploadID, err := generateID()
 	if err != nil {
@@ -144,7 +243,11 @@ func (m *ChunkedTransferManager) InitUpload(filename string, fileSize int64) (*U
 	// Calculate the number of chunks
 	totalChunks := int((fileSize + m.chunkSize - 1) / m.chunkSize)
 
-	// Create a temporary file for the upload
+	// Create a temporary file for the upload, recreating the directory in
+	// case another session removed it while this one was idle
+	if err := os.MkdirAll(m.tempDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
 	tempPath := filepath.Join(m.tempDir, uploadID)
 	tempFile, err := os.Create(tempPath)
 	if err != nil {
@@ -153,21 +256,28 @@ func (m *ChunkedTransferManager) InitUpload(filename string, fileSize int64) (*U
 	defer tempFile.Close()
 
 	// Preallocate the file if possible
-	if err := tempFile.Truncate(fileSize); err != nil {
+	storedSize := fileSize
+	if m.staging != nil {
+		storedSize = sealedSize(fileSize, m.chunkSize)
+	}
+	if err := tempFile.Truncate(storedSize); err != nil {
 		log.Printf("Warning: Failed to preallocate file: %v", err)
 	}
 
 	// Create upload info
 	uploadInfo := &UploadInfo{
 		UploadID:    uploadID,
-		Filename:    filepath.Base(filename),
+		Filename:    safeFilename(filename),
 		FileSize:    fileSize,
+		FileHash:    fileHash,
 		ChunkSize:   m.chunkSize,
 		TotalChunks: totalChunks,
 		Chunks:      make([]ChunkInfo, totalChunks),
 		StartTime:   time.Now(),
 		Status:      "pending",
 		TempPath:    tempPath,
+		LastActive:  time.Now(),
+		Received:    newChunkBitmap(totalChunks),
 	}
 
 	// Initialize chunk info
@@ -187,6 +297,7 @@ func (m *ChunkedTransferManager) InitUpload(filename string, fileSize int64) (*U
 	// Store the upload info
 	m.uploadsMutex.Lock()
 	m.uploads[uploadID] = uploadInfo
+	m.saveState(uploadInfo)
 	m.uploadsMutex.Unlock()
 
 	return uploadInfo, nil
@@ -222,14 +333,23 @@ func (m *ChunkedTransferManager) UploadChunk(uploadID string, chunkID int, data
 
 	// Write the chunk to the file
 	offset := uploadInfo.Chunks[chunkID].ChunkOffset
-	if _, err := file.WriteAt(data, offset); err != nil {
+	stored := data
+	if m.staging != nil {
+		// Sealed chunks are larger, so each one sits in its own wider slot
+		stored = m.staging.seal(chunkID, data)
+		offset = int64(chunkID) * (uploadInfo.ChunkSize + sealOverhead)
+	}
+	if _, err := file.WriteAt(stored, offset); err != nil {
 		return fmt.Errorf("failed to write chunk: %w", err)
 	}
 
 	// Update the upload status
 	m.uploadsMutex.Lock()
 	uploadInfo.Status = "in_progress"
-	uploadInfo.Chunks[chunkID].ChunkHash = "uploaded" // We could calculate a hash here
+	uploadInfo.LastActive = time.Now()
+	uploadInfo.Chunks[chunkID].ChunkHash = fmt.Sprintf("%x", sha256.Sum256(data))
+	uploadInfo.Received.set(chunkID)
+	m.saveState(uploadInfo)
 	m.uploadsMutex.Unlock()
 
 	return nil
@@ -246,31 +366,56 @@ func (m *ChunkedTransferManager) CompleteUpload(uploadID string) (string, error)
 	}
 
 	// Check if all chunks have been uploaded
-	for _, chunk := range uploadInfo.Chunks {
-		if chunk.ChunkHash == "" {
+	m.uploadsMutex.RLock()
+	for i := 0; i < uploadInfo.TotalChunks; i++ {
+		if !uploadInfo.Received.has(i) {
+			m.uploadsMutex.RUnlock()
 			return "", fmt.Errorf("not all chunks have been uploaded")
 		}
 	}
+	m.uploadsMutex.RUnlock()
 
-	// Create timestamp
-	timestamp := time.Now().Format("20060102_150405")
-
-	// Create filename with timestamp
-	ext := filepath.Ext(uploadInfo.Filename)
-	name := uploadInfo.Filename[:len(uploadInfo.Filename)-len(ext)]
-	newFilename := fmt.Sprintf("%s_%s%s", name, timestamp, ext)
+	// Check the assembled file against the sender's hash
+	if uploadInfo.FileHash != "" {
+		var hash string
+		var err error
+		if m.staging != nil {
+			hash, err = m.staging.hash(uploadInfo.TempPath, uploadInfo.FileSize, uploadInfo.ChunkSize)
+		} else {
+			hash, err = hashFile(uploadInfo.TempPath)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to hash uploaded file: %w", err)
+		}
+		if !strings.EqualFold(hash, uploadInfo.FileHash) {
+			m.uploadsMutex.Lock()
+			uploadInfo.Status = "failed"
+			delete(m.uploads, uploadID)
+			m.uploadsMutex.Unlock()
+			os.Remove(uploadInfo.TempPath)
+			m.removeState(uploadID)
+			return "", fmt.Errorf("uploaded file does not match its hash")
+		}
+	}
 
-	// Create full path
-	filePath := filepath.Join(m.downloadPath, newFilename)
+	// Hold the upload for approval instead of saving it
+	if m.staging != nil {
+		staged, err := m.staging.adopt(uploadInfo.Filename, uploadInfo.TempPath, uploadInfo.FileSize, uploadInfo.ChunkSize)
+		if err != nil {
+			return "", err
+		}
+		m.uploadsMutex.Lock()
+		uploadInfo.Status = "completed"
+		uploadInfo.EndTime = time.Now()
+		m.uploadsMutex.Unlock()
+		recordUpload(uploadInfo)
+		return fmt.Sprintf("%s (awaiting approval as #%d)", staged.Filename, staged.ID), nil
+	}
 
 	// Move the temporary file to the download directory
-	if err := os.Rename(uploadInfo.TempPath, filePath); err != nil {
-		// If rename fails (e.g., across different filesystems), try copy
-		if err := copyFile(uploadInfo.TempPath, filePath); err != nil {
-			return "", fmt.Errorf("failed to move file: %w", err)
-		}
-		// Remove the temporary file
-		os.Remove(uploadInfo.TempPath)
+	filePath, err := placeReceived(uploadInfo.TempPath, m.downloadPath, uploadInfo.Filename)
+	if err != nil {
+		return "", err
 	}
 
 	// Update the upload status
@@ -278,10 +423,33 @@ func (m *ChunkedTransferManager) CompleteUpload(uploadID string) (string, error)
 	uploadInfo.Status = "completed"
 	uploadInfo.EndTime = time.Now()
 	m.uploadsMutex.Unlock()
+	m.removeState(uploadID)
+
+	notifyTransfer("received", filePath, uploadInfo.FileSize)
+	recordUpload(uploadInfo)
 
 	return filePath, nil
 }
 
+// recordUpload records a completed upload in the transfer statistics. The
+// sender does not identify itself, so the peer is unknown.
+func recordUpload(uploadInfo *UploadInfo) {
+	stats := &TransferStats{
+		Time:      uploadInfo.StartTime,
+		Direction: "received",
+		Transport: "chunked",
+		Filename:  uploadInfo.Filename,
+		Bytes:     uploadInfo.FileSize,
+		Duration:  uploadInfo.EndTime.Sub(uploadInfo.StartTime),
+		Success:   true,
+	}
+	if seconds := stats.Duration.Seconds(); seconds > 0 {
+		stats.AverageRate = float64(stats.Bytes) / seconds
+		stats.PeakRate = stats.AverageRate
+	}
+	recordTransfer(stats)
+}
+
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
 	// Open the source file
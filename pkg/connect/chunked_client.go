@@ -6,10 +6,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+const (
+	// resumeAttempts is how many times an interrupted upload is resumed before giving up
+	resumeAttempts = 5
+
+	// resumeDelay is the wait before the first resume attempt; later attempts wait longer
+	resumeDelay = 2 * time.Second
 )
 
 // ChunkedClient is a client for chunked file transfers
@@ -27,12 +38,7 @@ func NewChunkedClient(baseURL, downloadDir string, chunkSize int64) *ChunkedClie
 		baseURL = "http://localhost:7531"
 	}
 	if downloadDir == "" {
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			downloadDir = filepath.Join(homeDir, "Downloads")
-		} else {
-			downloadDir = "."
-		}
+		downloadDir = utils.DefaultDownloadDir()
 	}
 	if chunkSize <= 0 {
 		chunkSize = DefaultChunkSize
@@ -77,79 +83,141 @@ func (c *ChunkedClient) UploadFile(filePath string, progressCallback func(int))
 
 	// Format file size
 	sizeStr := formatFileSize(fileInfo.Size())
-	fmt.Printf("\033[1;32m📤 Uploading file: %s (%s)...\033[0m\n", filename, sizeStr)
+	progress.printf("\033[1;32m📤 Uploading file: %s (%s)...\033[0m\n", filename, sizeStr)
+
+	// Hash the file so the receiver can verify it once all chunks are in
+	fileHash, err := hashFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
 
 	// Initialize the upload
-	uploadInfo, err := c.initUpload(filename, fileInfo.Size())
+	uploadInfo, err := c.initUpload(filename, fileInfo.Size(), fileHash)
 	if err != nil {
 		return "", fmt.Errorf("failed to initialize upload: %w", err)
 	}
 
-	// Calculate total chunks
-	totalChunks := uploadInfo.TotalChunks
+	pending := make([]int, uploadInfo.TotalChunks)
+	for i := range pending {
+		pending[i] = i
+	}
+
+	// If the receiver goes away mid-upload (for example, while its server
+	// restarts), ask it which chunks it kept and send only the rest
+	meter := newRateMeter("sent", filename, fileInfo.Size())
+	for attempt := 1; ; attempt++ {
+		err := c.uploadChunks(file, uploadInfo, pending, meter, progressCallback)
+		if err == nil {
+			break
+		}
+		if attempt > resumeAttempts {
+			meter.finish(c.peer(), "chunked", err)
+			return "", err
+		}
 
-	// Show progress bar
-	fmt.Printf("\033[1;32m[                    ] 0%%\033[0m")
-	fmt.Printf("\r")
+		progress.printf("\033[1;33m⚠️  Upload interrupted (%v), resuming...\033[0m\n", err)
+		meter.retry()
+		time.Sleep(time.Duration(attempt) * resumeDelay)
+
+		status, statusErr := c.UploadStatus(uploadInfo.UploadID)
+		if statusErr != nil {
+			continue
+		}
+		pending = status.MissingChunks
+		meter.reset(min(int64(status.ReceivedChunks)*uploadInfo.ChunkSize, uploadInfo.FileSize))
+	}
+
+	// Complete the upload
+	filePath, err = c.completeUpload(uploadInfo.UploadID)
+	meter.finish(c.peer(), "chunked", err)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete upload: %w", err)
+	}
+
+	progress.printf("\033[1;32m📤 File uploaded successfully!\033[0m\n")
+
+	return filePath, nil
+}
+
+// peer returns the address of the receiver, for transfer statistics
+func (c *ChunkedClient) peer() string {
+	if u, err := url.Parse(c.baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return c.baseURL
+}
+
+// uploadChunks sends the given chunks of a file, reporting overall progress
+func (c *ChunkedClient) uploadChunks(file *os.File, uploadInfo *UploadInfo, chunkIDs []int, meter *rateMeter, progressCallback func(int)) error {
+	totalChunks := uploadInfo.TotalChunks
+	done := totalChunks - len(chunkIDs)
 
-	// Upload each chunk
 	buffer := make([]byte, uploadInfo.ChunkSize)
-	for i := 0; i < totalChunks; i++ {
+	for _, i := range chunkIDs {
 		// Calculate the chunk size
 		chunkSize := uploadInfo.ChunkSize
 		if i == totalChunks-1 {
 			// Last chunk might be smaller
-			chunkSize = fileInfo.Size() - int64(i)*uploadInfo.ChunkSize
+			chunkSize = uploadInfo.FileSize - int64(i)*uploadInfo.ChunkSize
 		}
 
 		// Seek to the correct position
 		if _, err := file.Seek(int64(i)*uploadInfo.ChunkSize, 0); err != nil {
-			return "", fmt.Errorf("failed to seek file: %w", err)
+			return fmt.Errorf("failed to seek file: %w", err)
 		}
 
 		// Read the chunk
 		n, err := io.ReadFull(file, buffer[:chunkSize])
 		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-			return "", fmt.Errorf("failed to read chunk: %w", err)
+			return fmt.Errorf("failed to read chunk: %w", err)
 		}
 
 		// Upload the chunk
 		if err := c.uploadChunk(uploadInfo.UploadID, i, buffer[:n]); err != nil {
-			return "", fmt.Errorf("failed to upload chunk %d: %w", i, err)
+			return fmt.Errorf("failed to upload chunk %d: %w", i, err)
 		}
 
 		// Update progress
-		progress := (i + 1) * 100 / totalChunks
+		done++
 		if progressCallback != nil {
-			progressCallback(progress)
+			progressCallback(done * 100 / totalChunks)
 		}
-
-		// Update progress bar
-		bars := progress / 5
-		spaces := 20 - bars
-		fmt.Printf("\033[1;32m[%s%s] %d%%\033[0m", strings.Repeat("=", bars), strings.Repeat(" ", spaces), progress)
-		fmt.Printf("\r")
+		meter.add(int64(n))
 	}
+	return nil
+}
 
-	// Complete the upload
-	filePath, err = c.completeUpload(uploadInfo.UploadID)
+// UploadStatus asks the receiver how far an upload has progressed
+func (c *ChunkedClient) UploadStatus(uploadID string) (*UploadStatus, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/v1/connect/upload/status?upload_id=%s", c.baseURL, url.QueryEscape(uploadID)))
 	if err != nil {
-		return "", fmt.Errorf("failed to complete upload: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
+	defer resp.Body.Close()
 
-	// Update progress bar to 100%
-	fmt.Printf("\033[1;32m[====================] 100%%\033[0m\n")
-	fmt.Printf("\033[1;32m📤 File uploaded successfully!\033[0m\n")
+	// Check the response status
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("server returned error: %s - %s", resp.Status, strings.TrimSpace(string(body)))
+	}
 
-	return filePath, nil
+	var respBody UploadStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !respBody.Success || respBody.UploadStatus == nil {
+		return nil, fmt.Errorf("server returned error: %s", respBody.Error)
+	}
+	return respBody.UploadStatus, nil
 }
 
 // initUpload initializes a file upload
-func (c *ChunkedClient) initUpload(filename string, fileSize int64) (*UploadInfo, error) {
+func (c *ChunkedClient) initUpload(filename string, fileSize int64, fileHash string) (*UploadInfo, error) {
 	// Create the request body
 	reqBody := map[string]interface{}{
 		"filename":  filename,
 		"file_size": fileSize,
+		"file_hash": fileHash,
 	}
 
 	// Convert the request body to JSON
@@ -203,6 +271,7 @@ func (c *ChunkedClient) initUpload(filename string, fileSize int64) (*UploadInfo
 		UploadID:    respBody.UploadID,
 		Filename:    filename,
 		FileSize:    fileSize,
+		FileHash:    fileHash,
 		ChunkSize:   respBody.ChunkSize,
 		TotalChunks: len(respBody.Chunks),
 		Chunks:      respBody.Chunks,
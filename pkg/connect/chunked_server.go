@@ -0,0 +1,226 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// chunkedPortAttempts is how many ports above the preferred one are tried
+const chunkedPortAttempts = 100
+
+// ChunkedServer is the HTTP listener a connect session uses to receive
+// chunked uploads. Its port is announced to peers in the WebSocket
+// handshake, so it does not need a fixed port or a running lumo server.
+type ChunkedServer struct {
+	manager *ChunkedTransferManager
+	server  *http.Server
+	port    int
+	cancel  context.CancelFunc
+}
+
+// NewChunkedServer creates a chunked upload listener that saves files to downloadPath
+func NewChunkedServer(downloadPath string) (*ChunkedServer, error) {
+	manager, err := NewChunkedTransferManager(downloadPath, DefaultChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkedServer{manager: manager}, nil
+}
+
+// Start listens on the first free port at or above preferredPort and serves
+// uploads until Stop is called. A preferredPort of 0 picks any free port.
+func (s *ChunkedServer) Start(preferredPort int) error {
+	listener, err := listenFrom(preferredPort)
+	if err != nil {
+		return err
+	}
+	s.port = listener.Addr().(*net.TCPAddr).Port
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/connect/upload/init", s.handleInit)
+	mux.HandleFunc("/api/v1/connect/upload/chunk", s.handleChunk)
+	mux.HandleFunc("/api/v1/connect/upload/complete", s.handleComplete)
+	mux.HandleFunc("/api/v1/connect/upload/status", s.handleStatus)
+	s.server = &http.Server{Handler: mux}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.manager.RunJanitor(ctx)
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Error serving chunked uploads: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Port returns the port the listener is bound to, or 0 before Start
+func (s *ChunkedServer) Port() int {
+	return s.port
+}
+
+// Stop closes the listener and removes any partial uploads
+func (s *ChunkedServer) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	var err error
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = s.server.Shutdown(ctx)
+	}
+	if cleanupErr := s.manager.Cleanup(); err == nil {
+		err = cleanupErr
+	}
+	return err
+}
+
+// listenFrom binds the first free TCP port at or above start
+func listenFrom(start int) (net.Listener, error) {
+	if start <= 0 {
+		return net.Listen("tcp", ":0")
+	}
+	for port := start; port < start+chunkedPortAttempts && port < 65536; port++ {
+		if listener, err := net.Listen("tcp", ":"+strconv.Itoa(port)); err == nil {
+			return listener, nil
+		}
+	}
+	return nil, fmt.Errorf("no free port for chunked transfers in %d-%d", start, start+chunkedPortAttempts-1)
+}
+
+// handleInit starts an upload
+func (s *ChunkedServer) handleInit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Filename string `json:"filename"`
+		FileSize int64  `json:"file_size"`
+		FileHash string `json:"file_hash"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.Filename == "" {
+		http.Error(w, "Filename is required", http.StatusBadRequest)
+		return
+	}
+	if request.FileSize <= 0 {
+		http.Error(w, "File size must be greater than 0", http.StatusBadRequest)
+		return
+	}
+
+	uploadInfo, err := s.manager.InitUpload(request.Filename, request.FileSize, request.FileHash)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to initialize upload: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"success":    true,
+		"upload_id":  uploadInfo.UploadID,
+		"chunk_size": uploadInfo.ChunkSize,
+		"chunks":     uploadInfo.Chunks,
+	})
+}
+
+// handleChunk stores one chunk of an upload
+func (s *ChunkedServer) handleChunk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	uploadID := r.URL.Query().Get("upload_id")
+	if uploadID == "" {
+		http.Error(w, "Upload ID is required", http.StatusBadRequest)
+		return
+	}
+	chunkID, err := strconv.Atoi(r.URL.Query().Get("chunk_id"))
+	if err != nil {
+		http.Error(w, "Invalid chunk ID", http.StatusBadRequest)
+		return
+	}
+
+	// A chunk never exceeds MaxChunkSize, so anything larger is rejected unread
+	data, err := io.ReadAll(io.LimitReader(r.Body, MaxChunkSize+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read chunk data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.manager.UploadChunk(uploadID, chunkID, data); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to upload chunk: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"success":  true,
+		"chunk_id": chunkID,
+	})
+}
+
+// handleComplete moves a finished upload into the download directory
+func (s *ChunkedServer) handleComplete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	uploadID := r.URL.Query().Get("upload_id")
+	if uploadID == "" {
+		http.Error(w, "Upload ID is required", http.StatusBadRequest)
+		return
+	}
+
+	filePath, err := s.manager.CompleteUpload(uploadID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to complete upload: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if s.manager.staging == nil {
+		fmt.Printf("\033[1;36m📥 Received file: %s\033[0m\n", filePath)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"success":   true,
+		"file_path": filePath,
+	})
+}
+
+// handleStatus reports which chunks of an upload are still missing
+func (s *ChunkedServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := s.manager.UploadStatus(r.URL.Query().Get("upload_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, UploadStatusResponse{Success: true, UploadStatus: status})
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package connect
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stateFileExt is the extension of the files that record upload progress
+const stateFileExt = ".json"
+
+// chunkBitmap records which chunks of an upload have been received
+type chunkBitmap []byte
+
+// newChunkBitmap creates an empty bitmap for n chunks
+func newChunkBitmap(n int) chunkBitmap {
+	return make(chunkBitmap, (n+7)/8)
+}
+
+// set marks chunk i as received
+func (b chunkBitmap) set(i int) {
+	b[i/8] |= 1 << (i % 8)
+}
+
+// has reports whether chunk i has been received
+func (b chunkBitmap) has(i int) bool {
+	return i/8 < len(b) && b[i/8]&(1<<(i%8)) != 0
+}
+
+// UploadStatus describes how far an upload has progressed, so a sender can
+// resume it by sending only the missing chunks
+type UploadStatus struct {
+	UploadID       string `json:"upload_id"`
+	Filename       string `json:"filename"`
+	FileSize       int64  `json:"file_size"`
+	FileHash       string `json:"file_hash,omitempty"`
+	ChunkSize      int64  `json:"chunk_size"`
+	TotalChunks    int    `json:"total_chunks"`
+	ReceivedChunks int    `json:"received_chunks"`
+	MissingChunks  []int  `json:"missing_chunks"`
+	Status         string `json:"status"`
+}
+
+// UploadStatusResponse is the body of an /api/v1/connect/upload/status response
+type UploadStatusResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	*UploadStatus
+}
+
+// uploadState is the on-disk record of an upload in a persistent manager
+type uploadState struct {
+	Upload     *UploadInfo `json:"upload"`
+	TempPath   string      `json:"temp_path"`
+	Received   []byte      `json:"received"`
+	LastActive time.Time   `json:"last_active"`
+}
+
+// NewPersistentChunkedTransferManager creates a chunked transfer manager that
+// keeps partial uploads and their progress in stateDir, so uploads survive a
+// restart of the receiving process. Uploads left there by a previous run are
+// restored unless they have been idle longer than DefaultUploadTimeout.
+func NewPersistentChunkedTransferManager(downloadPath, stateDir string, chunkSize int64) (*ChunkedTransferManager, error) {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create upload state directory: %w", err)
+	}
+
+	manager, err := newChunkedTransferManager(downloadPath, stateDir, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	manager.stateDir = stateDir
+
+	restored := manager.restoreUploads(DefaultUploadTimeout)
+	if restored > 0 {
+		log.Printf("Restored %d interrupted upload(s) from %s", restored, stateDir)
+	}
+	return manager, nil
+}
+
+// UploadStatus returns the progress of an upload
+func (m *ChunkedTransferManager) UploadStatus(uploadID string) (*UploadStatus, error) {
+	m.uploadsMutex.RLock()
+	defer m.uploadsMutex.RUnlock()
+
+	uploadInfo, ok := m.uploads[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("upload not found: %s", uploadID)
+	}
+
+	status := &UploadStatus{
+		UploadID:      uploadInfo.UploadID,
+		Filename:      uploadInfo.Filename,
+		FileSize:      uploadInfo.FileSize,
+		FileHash:      uploadInfo.FileHash,
+		ChunkSize:     uploadInfo.ChunkSize,
+		TotalChunks:   uploadInfo.TotalChunks,
+		MissingChunks: []int{},
+		Status:        uploadInfo.Status,
+	}
+	for i := 0; i < uploadInfo.TotalChunks; i++ {
+		if uploadInfo.Received.has(i) {
+			status.ReceivedChunks++
+		} else {
+			status.MissingChunks = append(status.MissingChunks, i)
+		}
+	}
+	return status, nil
+}
+
+// statePath returns the path of an upload's state file
+func (m *ChunkedTransferManager) statePath(uploadID string) string {
+	return filepath.Join(m.stateDir, uploadID+stateFileExt)
+}
+
+// saveState records an upload's progress. The caller must hold uploadsMutex.
+func (m *ChunkedTransferManager) saveState(uploadInfo *UploadInfo) {
+	if m.stateDir == "" {
+		return
+	}
+
+	data, err := json.Marshal(uploadState{
+		Upload:     uploadInfo,
+		TempPath:   uploadInfo.TempPath,
+		Received:   uploadInfo.Received,
+		LastActive: uploadInfo.LastActive,
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to encode upload state: %v", err)
+		return
+	}
+
+	// Write through a temporary file so a crash never leaves a truncated record
+	path := m.statePath(uploadInfo.UploadID)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		log.Printf("Warning: Failed to save upload state: %v", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		log.Printf("Warning: Failed to save upload state: %v", err)
+	}
+}
+
+// removeState deletes an upload's state file
+func (m *ChunkedTransferManager) removeState(uploadID string) {
+	if m.stateDir == "" {
+		return
+	}
+	if err := os.Remove(m.statePath(uploadID)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: Failed to remove upload state: %v", err)
+	}
+}
+
+// restoreUploads loads the uploads recorded in the state directory. Records
+// older than maxAge, or whose partial file is gone, are deleted instead.
+func (m *ChunkedTransferManager) restoreUploads(maxAge time.Duration) int {
+	paths, err := filepath.Glob(filepath.Join(m.stateDir, "*"+stateFileExt))
+	if err != nil {
+		return 0
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	restored := 0
+	for _, path := range paths {
+		uploadID := strings.TrimSuffix(filepath.Base(path), stateFileExt)
+		uploadInfo, err := readState(path)
+		if err != nil {
+			log.Printf("Warning: Discarding unreadable upload state %s: %v", path, err)
+		} else if uploadInfo.UploadID == uploadID && uploadInfo.LastActive.After(cutoff) {
+			m.uploads[uploadID] = uploadInfo
+			restored++
+			continue
+		}
+
+		os.Remove(path)
+		if uploadInfo != nil && uploadInfo.TempPath != "" {
+			os.Remove(uploadInfo.TempPath)
+		}
+	}
+	return restored
+}
+
+// readState reads an upload state file and checks that its partial file still matches it
+func readState(path string) (*UploadInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var state uploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Upload == nil {
+		return nil, fmt.Errorf("no upload recorded")
+	}
+
+	uploadInfo := state.Upload
+	uploadInfo.TempPath = state.TempPath
+	uploadInfo.Received = chunkBitmap(state.Received)
+	uploadInfo.LastActive = state.LastActive
+	if len(uploadInfo.Chunks) != uploadInfo.TotalChunks || len(uploadInfo.Received) != (uploadInfo.TotalChunks+7)/8 {
+		return uploadInfo, fmt.Errorf("chunk records do not match the upload")
+	}
+
+	info, err := os.Stat(uploadInfo.TempPath)
+	if err != nil {
+		return uploadInfo, fmt.Errorf("partial file is missing: %w", err)
+	}
+	if info.Size() != uploadInfo.FileSize {
+		return uploadInfo, fmt.Errorf("partial file has the wrong size")
+	}
+	return uploadInfo, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 hash of a file
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -2,6 +2,7 @@ package connect
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -11,24 +12,33 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
 	"github.com/agnath18K/lumo/pkg/utils"
+	"github.com/agnath18K/lumo/pkg/version"
 	"github.com/gorilla/websocket"
 )
 
 // FileTransferMessage represents a message for file transfer
 type FileTransferMessage struct {
-	Type     string `json:"type"`
-	Filename string `json:"filename"`
-	Size     int64  `json:"size,omitempty"`
-	Content  []byte `json:"content,omitempty"`
-	Progress int    `json:"progress,omitempty"` // Progress percentage (0-100)
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Size        int64  `json:"size,omitempty"`
+	Content     []byte `json:"content,omitempty"`
+	Progress    int    `json:"progress,omitempty"`     // Progress percentage (0-100)
+	ChunkedPort int    `json:"chunked_port,omitempty"` // Port of the sender's chunked upload listener, sent in "hello"
 }
 
+// chunkedThreshold is the file size above which chunked transfer is used
+const chunkedThreshold = 10 * 1024 * 1024
+
 // ConnectManager handles WebSocket connections for file transfers
 type ConnectManager struct {
 	upgrader     websocket.Upgrader
@@ -38,7 +48,19 @@ type ConnectManager struct {
 	port         int    // Custom port
 	discoverer   discovery.Discoverer
 	advertised   bool
-	useChunked   bool // Whether to use chunked transfer for all files
+	useChunked   bool           // Whether to use chunked transfer for all files
+	chunked      *ChunkedServer // Listener for chunked uploads from peers
+	staging      *Staging       // Holds received files encrypted until approved; nil saves them directly
+	parallel     int            // How many queued files are sent at once
+	sendDir      func(string)   // Sends dropped folders; nil packs them for peers
+
+	// writeMutex serializes WebSocket writes, which may come from several
+	// queued sends at once
+	writeMutex sync.Mutex
+
+	// peerPorts holds the chunked upload port each peer announced
+	peerPorts map[*websocket.Conn]int
+	peerMutex sync.Mutex
 }
 
 // GetPort returns the current port
@@ -50,12 +72,7 @@ func (m *ConnectManager) GetPort() int {
 func NewConnectManager(downloadPath string, port int, useChunked ...bool) *ConnectManager {
 	// Set default values if not provided
 	if downloadPath == "" {
-		homeDir, err := os.UserHomeDir()
-		if err == nil {
-			downloadPath = filepath.Join(homeDir, "Downloads")
-		} else {
-			downloadPath = "."
-		}
+		downloadPath = utils.DefaultDownloadDir()
 	}
 
 	if port <= 0 {
@@ -85,7 +102,112 @@ func NewConnectManager(downloadPath string, port int, useChunked ...bool) *Conne
 		discoverer:   discoverer,
 		advertised:   false,
 		useChunked:   chunkedTransfer,
+		parallel:     DefaultParallelSends,
+		peerPorts:    make(map[*websocket.Conn]int),
+	}
+}
+
+// SetParallelSends sets how many queued files are sent at once
+func (m *ConnectManager) SetParallelSends(n int) {
+	m.parallel = max(1, min(n, MaxParallelSends))
+}
+
+// UseStaging holds received files encrypted in a staging area until the
+// user accepts them, instead of writing them to the download path
+func (m *ConnectManager) UseStaging() error {
+	staging, err := NewStaging()
+	if err != nil {
+		return err
+	}
+	m.staging = staging
+	return nil
+}
+
+// closeStaging deletes the staging area, warning about files nobody accepted
+func (m *ConnectManager) closeStaging() {
+	if m.staging == nil {
+		return
+	}
+	if pending := len(m.staging.List()); pending > 0 {
+		fmt.Printf("\033[1;33m⚠️ Discarded %d staged file(s) that were not accepted\033[0m\n", pending)
+	}
+	if err := m.staging.Close(); err != nil {
+		log.Printf("Warning: Failed to remove staging directory: %v", err)
+	}
+}
+
+// startChunkedServer starts the chunked upload listener on a port near the
+// WebSocket port. Without it peers fall back to sending whole files over
+// the WebSocket, so a failure is only logged.
+func (m *ConnectManager) startChunkedServer() {
+	server, err := NewChunkedServer(m.downloadPath)
+	if err == nil {
+		if m.staging != nil {
+			server.manager.UseStaging(m.staging)
+		}
+		err = server.Start(m.port + 1)
+	}
+	if err != nil {
+		log.Printf("Warning: Chunked transfers are unavailable: %v", err)
+		return
 	}
+	m.chunked = server
+}
+
+// stopChunkedServer stops the chunked upload listener and removes partial uploads
+func (m *ConnectManager) stopChunkedServer() {
+	if m.chunked == nil {
+		return
+	}
+	if err := m.chunked.Stop(); err != nil {
+		log.Printf("Warning: Failed to stop chunked transfer listener: %v", err)
+	}
+	m.chunked = nil
+}
+
+// chunkedPort returns the port of the chunked upload listener, or 0 if it is not running
+func (m *ConnectManager) chunkedPort() int {
+	if m.chunked == nil {
+		return 0
+	}
+	return m.chunked.Port()
+}
+
+// sendHello tells a peer which port accepts chunked uploads
+func (m *ConnectManager) sendHello(conn *websocket.Conn) error {
+	return m.writeJSON(conn, FileTransferMessage{
+		Type:        "hello",
+		ChunkedPort: m.chunkedPort(),
+	})
+}
+
+// writeJSON sends a message to a peer. The WebSocket library allows only
+// one writer at a time per connection.
+func (m *ConnectManager) writeJSON(conn *websocket.Conn, v interface{}) error {
+	m.writeMutex.Lock()
+	defer m.writeMutex.Unlock()
+	return conn.WriteJSON(v)
+}
+
+// setPeerPort records the chunked upload port a peer announced
+func (m *ConnectManager) setPeerPort(conn *websocket.Conn, port int) {
+	m.peerMutex.Lock()
+	defer m.peerMutex.Unlock()
+	m.peerPorts[conn] = port
+}
+
+// peerPort returns the chunked upload port of a peer, or 0 if it has none
+func (m *ConnectManager) peerPort(conn *websocket.Conn) int {
+	m.peerMutex.Lock()
+	defer m.peerMutex.Unlock()
+	return m.peerPorts[conn]
+}
+
+// forgetPeer drops what is known about a closed connection
+func (m *ConnectManager) forgetPeer(conn *websocket.Conn) {
+	m.peerMutex.Lock()
+	defer m.peerMutex.Unlock()
+	delete(m.peerPorts, conn)
 }
 
 // StartReceiver starts a WebSocket server to receive files
@@ -116,7 +238,9 @@ func (m *ConnectManager) StartReceiver(ctx context.Context) error {
 		// Try to find an available port
 		newPort, err := utils.FindAvailablePort(m.port, 100)
 		if err != nil {
-			return fmt.Errorf("port %d is already in use and no alternative ports are available: %w", m.port, err)
+			return lumoerr.New(lumoerr.ErrPortInUse,
+				fmt.Sprintf("port %d, and no alternative ports are available (%v)", m.port, err)).
+				WithHint("Choose another port with: lumo connect --receive --port <port>")
 		}
 
 		// Log the port change
@@ -135,18 +259,23 @@ func (m *ConnectManager) StartReceiver(ctx context.Context) error {
 		Handler: mux,
 	}
 
+	// Start the listener peers upload large files to
+	m.startChunkedServer()
+	defer m.stopChunkedServer()
+	defer m.closeStaging()
+
 	// Start the discovery service
 	if err := m.discoverer.Start(ctx); err != nil {
 		log.Printf("Warning: Failed to start discovery service: %v", err)
 	}
 
 	// Advertise the service
-	info := map[string]string{
-		"hostname": hostname,
-		"username": username,
-		"mode":     m.mode,
-	}
-	if err := m.discoverer.Advertise(ctx, "Lumo Connect", m.port, info); err != nil {
+	info := discovery.AdvertisedInfo(version.Version, []string{discovery.CapabilityConnectReceiver}, map[string]string{
+		discovery.InfoHostname: hostname,
+		discovery.InfoUsername: username,
+		"mode":                 m.mode,
+	})
+	if err := m.discoverer.Advertise(ctx, discovery.ServiceName, "Lumo Connect", m.port, info); err != nil {
 		log.Printf("Warning: Failed to advertise service: %v", err)
 	} else {
 		m.advertised = true
@@ -164,9 +293,11 @@ func (m *ConnectManager) StartReceiver(ctx context.Context) error {
 	fmt.Printf("│ \033[1;97mMode:\033[1;36m %s                                  │\n", m.mode)
 	fmt.Printf("│ \033[1;97mIP Address:\033[1;36m %-33s │\n", localIP)
 	fmt.Printf("│ \033[1;97mPort:\033[1;36m %-39d │\n", m.port)
+	fmt.Printf("│ \033[1;97mChunked Port:\033[1;36m %-31s │\n", formatChunkedPort(m.chunkedPort()))
 	fmt.Printf("│ \033[1;97mHostname:\033[1;36m %-35s │\n", hostname)
 	fmt.Printf("│ \033[1;97mUser:\033[1;36m %-39s │\n", username)
 	fmt.Printf("│ \033[1;97mDownload Path:\033[1;36m %-30s │\n", m.downloadPath)
+	fmt.Printf("│ \033[1;97mStaging:\033[1;36m %-36s │\n", m.stagingDescription())
 	fmt.Printf("│ \033[1;97mDiscoverable:\033[1;36m %-32v │\n", m.advertised)
 	fmt.Printf("└─────────────────────────────────────────────────┘\n\n")
 
@@ -246,6 +377,15 @@ func (m *ConnectManager) ConnectToPeer(ctx context.Context, peerIP string, peerP
 		return fmt.Errorf("failed to connect to peer: %w", err)
 	}
 	defer conn.Close()
+	defer m.forgetPeer(conn)
+
+	// The peer can send large files back through our own chunked listener
+	m.startChunkedServer()
+	defer m.stopChunkedServer()
+	defer m.closeStaging()
+	if err := m.sendHello(conn); err != nil {
+		return fmt.Errorf("failed to send handshake: %w", err)
+	}
 
 	// Get system information
 	localIP, _ := getLocalIP()
@@ -271,6 +411,7 @@ func (m *ConnectManager) ConnectToPeer(ctx context.Context, peerIP string, peerP
 	fmt.Printf("│ \033[1;97mHostname:\033[1;32m %-35s │\n", hostname)
 	fmt.Printf("│ \033[1;97mUser:\033[1;32m %-39s │\n", username)
 	fmt.Printf("│ \033[1;97mDownload Path:\033[1;32m %-30s │\n", m.downloadPath)
+	fmt.Printf("│ \033[1;97mStaging:\033[1;32m %-36s │\n", m.stagingDescription())
 	fmt.Printf("└─────────────────────────────────────────────────┘\n\n")
 
 	fmt.Printf("📤 \033[1;97mYou can send files by:\033[1;32m\n")
@@ -295,52 +436,69 @@ func (m *ConnectManager) ConnectToPeer(ctx context.Context, peerIP string, peerP
 			}
 
 			// Handle received message
-			if msg.Type == "ack" {
-				fmt.Printf("\033[1;32m✅ File %s received by peer\033[0m\n", msg.Filename)
+			if msg.Type == "hello" {
+				m.setPeerPort(conn, msg.ChunkedPort)
+			} else if msg.Type == "ack" {
+				progress.printf("\033[1;32m✅ File %s received by peer\033[0m\n", msg.Filename)
 			} else if msg.Type == "file" {
-				// Save the file
-				filename := m.saveFile(msg.Filename, msg.Content)
-
-				// Send acknowledgment
-				ack := FileTransferMessage{
-					Type:     "ack",
-					Filename: msg.Filename,
-				}
-				if err := conn.WriteJSON(ack); err != nil {
-					log.Printf("Error sending acknowledgment: %v", err)
-				}
-
-				// Format file size
-				sizeStr := formatFileSize(int64(len(msg.Content)))
-				fmt.Printf("\033[1;36m📥 Received file: %s (%s)\033[0m\n", filename, sizeStr)
+				m.receiveFile(conn, msg)
 			}
 		}
 	}()
 
-	// Read from stdin for file paths
-	return m.readStdinForFilePaths(conn)
+	// Read from stdin for file paths until stdin closes or the context is cancelled
+	done := make(chan error, 1)
+	go func() {
+		done <- m.readStdinForFilePaths(conn)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		m.writeMutex.Lock()
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		m.writeMutex.Unlock()
+		return nil
+	}
 }
 
 // readStdinForFilePaths reads file paths from stdin and sends files
 // If conn is nil, it will send to all connected clients (server mode)
 func (m *ConnectManager) readStdinForFilePaths(conn *websocket.Conn) error {
+	return m.readFilePaths(func(filePath string) {
+		if conn == nil {
+			// Send to all connected clients
+			m.sendFileToAllClients(filePath)
+			return
+		}
+		// Send to specific connection
+		if err := m.sendFile(conn, filePath); err != nil {
+			progress.printf("\033[1;31m❌ Error sending file: %v\033[0m\n", err)
+		}
+	})
+}
+
+// readFilePaths reads file paths from stdin and queues each one to be
+// handed to send, waiting for the queue to empty once stdin closes
+func (m *ConnectManager) readFilePaths(send func(filePath string)) error {
+	queue := newSendQueue(m.parallel, send)
+	queue.sendDir = m.sendDir
+	defer queue.close()
+
 	// Print instructions for manual file entry
-	fmt.Printf("\033[1;33mℹ️ You can type the full path to a file and press Enter\033[0m\n")
-	fmt.Printf("\033[1;33mℹ️ Type 'select' to open a file browser\033[0m\n")
+	fmt.Printf("\033[1;33mℹ️ You can type the full path to a file or folder and press Enter\033[0m\n")
+	fmt.Printf("\033[1;33mℹ️ Type 'select' to open a file browser, or 'stats' to see transfer totals for each peer\033[0m\n")
+	if m.staging != nil {
+		fmt.Printf("\033[1;33mℹ️ Received files wait encrypted until you accept them; type 'pending' to list them\033[0m\n")
+	}
 
 	// Read from stdin for file paths
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
-		filePath := scanner.Text()
-
-		// Handle special formats from drag-and-drop
-		// Some terminals prefix with "file://" or have URL encoding
-		if strings.HasPrefix(filePath, "file://") {
-			filePath = strings.TrimPrefix(filePath, "file://")
-		}
-
-		// Trim any quotes or whitespace that might be around the path
-		filePath = strings.Trim(filePath, "\"' \t\n\r")
+		// Handle special formats from drag-and-drop, such as file:// URLs
+		// and quoted paths
+		filePath := normalizeDroppedPath(scanner.Text(), runtime.GOOS)
 
 		// Skip empty lines
 		if filePath == "" {
@@ -348,6 +506,13 @@ func (m *ConnectManager) readStdinForFilePaths(conn *websocket.Conn) error {
 		}
 
 		// Check for special commands
+		if m.handleStagingCommand(filePath) {
+			continue
+		}
+		if filePath == "stats" {
+			printSessionStats()
+			continue
+		}
 		if filePath == "select" {
 			// Open a file dialog using zenity if available
 			selectedFile, err := openFileDialog()
@@ -355,36 +520,17 @@ func (m *ConnectManager) readStdinForFilePaths(conn *websocket.Conn) error {
 				fmt.Printf("\033[1;31m❌ Error opening file dialog: %v\033[0m\n", err)
 				fmt.Printf("\033[1;33mℹ️ Try dragging and dropping a file instead\033[0m\n")
 			} else if selectedFile != "" {
-				// Try to send the selected file
-				if conn != nil {
-					// Send to specific connection
-					err := m.sendFile(conn, selectedFile)
-					if err != nil {
-						fmt.Printf("\033[1;31m❌ Error sending file: %v\033[0m\n", err)
-					}
-				} else {
-					// Send to all connected clients
-					m.sendFileToAllClients(selectedFile)
-				}
+				// Queue the selected file
+				queue.add(selectedFile)
 			}
 			continue
 		}
 
 		// Check if this looks like a file path
-		if strings.Contains(filePath, "/") || strings.Contains(filePath, "\\") {
-			// Check if the file exists
+		if strings.ContainsAny(filePath, `/\`) || filepath.VolumeName(filePath) != "" {
+			// Check if the file exists; folders are packed and sent as one archive
 			if _, err := os.Stat(filePath); err == nil {
-				// Try to send the file
-				if conn != nil {
-					// Send to specific connection
-					err := m.sendFile(conn, filePath)
-					if err != nil {
-						fmt.Printf("\033[1;31m❌ Error sending file: %v\033[0m\n", err)
-					}
-				} else {
-					// Send to all connected clients
-					m.sendFileToAllClients(filePath)
-				}
+				queue.add(filePath)
 			} else {
 				fmt.Printf("\033[1;33m⚠️ File not found: %s\033[0m\n", filePath)
 				fmt.Printf("\033[1;33mℹ️ Make sure to provide the full path to the file\033[0m\n")
@@ -400,6 +546,18 @@ func (m *ConnectManager) readStdinForFilePaths(conn *websocket.Conn) error {
 	return nil
 }
 
+// sendDirectory packs a directory into an archive, which the peer extracts
+// under its download path, and sends it
+func sendDirectory(dirPath string, send func(filePath string)) {
+	archivePath, cleanup, err := packDirectory(dirPath)
+	if err != nil {
+		progress.printf("\033[1;31m❌ Error packing folder: %v\033[0m\n", err)
+		return
+	}
+	defer cleanup()
+	send(archivePath)
+}
+
 // Global variable to store active connections
 var activeConnections = make(map[*websocket.Conn]bool)
 var connectionsMutex = &sync.Mutex{}
@@ -424,12 +582,19 @@ func (m *ConnectManager) handleWebSocket(w http.ResponseWriter, r *http.Request)
 		connectionsMutex.Lock()
 		delete(activeConnections, conn)
 		connectionsMutex.Unlock()
+		m.forgetPeer(conn)
 	}()
 
 	// Get client IP
 	clientIP := r.RemoteAddr
 	fmt.Printf("\033[1;36m🔗 New connection from %s\033[0m\n", clientIP)
 
+	// Tell the client where to upload large files
+	if err := m.sendHello(conn); err != nil {
+		log.Printf("Error sending handshake: %v", err)
+		return
+	}
+
 	// Handle WebSocket connection
 	for {
 		var msg FileTransferMessage
@@ -442,22 +607,10 @@ func (m *ConnectManager) handleWebSocket(w http.ResponseWriter, r *http.Request)
 		}
 
 		// Handle file transfer message
-		if msg.Type == "file" {
-			// Save the file
-			filename := m.saveFile(msg.Filename, msg.Content)
-
-			// Send acknowledgment
-			ack := FileTransferMessage{
-				Type:     "ack",
-				Filename: msg.Filename,
-			}
-			if err := conn.WriteJSON(ack); err != nil {
-				log.Printf("Error sending acknowledgment: %v", err)
-			}
-
-			// Format file size
-			sizeStr := formatFileSize(int64(len(msg.Content)))
-			fmt.Printf("\033[1;36m📥 Received file: %s (%s)\033[0m\n", filename, sizeStr)
+		if msg.Type == "hello" {
+			m.setPeerPort(conn, msg.ChunkedPort)
+		} else if msg.Type == "file" {
+			m.receiveFile(conn, msg)
 		}
 	}
 }
@@ -471,14 +624,14 @@ func (m *ConnectManager) sendFileToAllClients(filePath string) {
 
 	// Check if there are any connections
 	if numConnections == 0 {
-		fmt.Printf("\033[1;33m⚠️ No connected clients to send file to\033[0m\n")
+		progress.printf("\033[1;33m⚠️ No connected clients to send file to\033[0m\n")
 		return
 	}
 
 	// Open the file
 	file, err := os.Open(filePath)
 	if err != nil {
-		fmt.Printf("\033[1;31m❌ Error opening file: %v\033[0m\n", err)
+		progress.printf("\033[1;31m❌ Error opening file: %v\033[0m\n", err)
 		return
 	}
 	defer file.Close()
@@ -486,13 +639,13 @@ func (m *ConnectManager) sendFileToAllClients(filePath string) {
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
-		fmt.Printf("\033[1;31m❌ Error getting file info: %v\033[0m\n", err)
+		progress.printf("\033[1;31m❌ Error getting file info: %v\033[0m\n", err)
 		return
 	}
 
 	// Check if it's a regular file
 	if !fileInfo.Mode().IsRegular() {
-		fmt.Printf("\033[1;31m❌ Not a regular file\033[0m\n")
+		progress.printf("\033[1;31m❌ Not a regular file\033[0m\n")
 		return
 	}
 
@@ -501,30 +654,31 @@ func (m *ConnectManager) sendFileToAllClients(filePath string) {
 
 	// Format file size
 	sizeStr := formatFileSize(fileInfo.Size())
-	fmt.Printf("\033[1;32m📤 Sending file: %s (%s) to %d clients...\033[0m\n", filename, sizeStr, numConnections)
+	progress.printf("\033[1;32m📤 Sending file: %s (%s) to %d clients...\033[0m\n", filename, sizeStr, numConnections)
 
 	// Check if we should use chunked transfer
-	if m.useChunked || fileInfo.Size() > 10*1024*1024 { // Use chunked if explicitly requested or file is larger than 10MB
-		// For large files, use chunked transfer
-		fmt.Printf("\033[1;33mℹ️ Large file detected. Using chunked transfer...\033[0m\n")
-
-		// Get local IP
-		localIP, err := getLocalIP()
-		if err != nil {
-			localIP = "localhost"
+	if m.useChunked || fileInfo.Size() > chunkedThreshold { // Use chunked if explicitly requested or file is larger than 10MB
+		// Upload to each client separately; uploads can take a while, so
+		// don't hold the connection lock during them
+		connectionsMutex.Lock()
+		conns := make([]*websocket.Conn, 0, len(activeConnections))
+		for conn := range activeConnections {
+			conns = append(conns, conn)
 		}
+		connectionsMutex.Unlock()
 
-		// Create a chunked client
-		client := NewChunkedClient(fmt.Sprintf("http://%s:7531", localIP), m.downloadPath, DefaultChunkSize)
-
-		// Upload the file
-		resultPath, err := client.UploadFile(filePath, nil)
-		if err != nil {
-			fmt.Printf("\033[1;31m❌ Error uploading file using chunked transfer: %v\033[0m\n", err)
-			return
+		sent := 0
+		for _, conn := range conns {
+			if _, err := m.transferFile(conn, filePath); err != nil {
+				progress.printf("\033[1;31m❌ Error sending file to a client: %v\033[0m\n", err)
+				continue
+			}
+			sent++
+		}
+		// Like the WebSocket broadcast, one broadcast is one transfer
+		if sent > 0 {
+			notifyTransfer("sent", filePath, fileInfo.Size())
 		}
-
-		fmt.Printf("\033[1;32m📤 File uploaded successfully to: %s\033[0m\n", resultPath)
 		return
 	}
 
@@ -532,7 +686,7 @@ func (m *ConnectManager) sendFileToAllClients(filePath string) {
 	// Read file content
 	content, err := io.ReadAll(file)
 	if err != nil {
-		fmt.Printf("\033[1;31m❌ Error reading file: %v\033[0m\n", err)
+		progress.printf("\033[1;31m❌ Error reading file: %v\033[0m\n", err)
 		return
 	}
 
@@ -545,37 +699,57 @@ func (m *ConnectManager) sendFileToAllClients(filePath string) {
 	}
 
 	// Send to all connections
+	sent := 0
 	connectionsMutex.Lock()
 	for conn := range activeConnections {
 		// Send the message
-		if err := conn.WriteJSON(msg); err != nil {
-			fmt.Printf("\033[1;31m❌ Error sending file to a client: %v\033[0m\n", err)
+		meter := newRateMeter("sent", filename, fileInfo.Size())
+		err := m.writeJSON(conn, msg)
+		meter.finish(conn.RemoteAddr().String(), "websocket", err)
+		if err != nil {
+			progress.printf("\033[1;31m❌ Error sending file to a client: %v\033[0m\n", err)
 			continue
 		}
+		sent++
 	}
 	connectionsMutex.Unlock()
 
-	fmt.Printf("\033[1;32m📤 File sent to all connected clients!\033[0m\n")
+	if sent == 0 {
+		return
+	}
+	progress.printf("\033[1;32m📤 File sent to all connected clients!\033[0m\n")
+	notifyTransfer("sent", filePath, fileInfo.Size())
 }
 
-// sendFile sends a file over WebSocket
+// sendFile sends a file over WebSocket and fires the post-transfer hook
 func (m *ConnectManager) sendFile(conn *websocket.Conn, filePath string) error {
+	size, err := m.transferFile(conn, filePath)
+	if err != nil {
+		return err
+	}
+	notifyTransfer("sent", filePath, size)
+	return nil
+}
+
+// transferFile sends a file to one peer, uploading it in chunks when it is
+// large and the peer accepts them, and returns the size sent
+func (m *ConnectManager) transferFile(conn *websocket.Conn, filePath string) (int64, error) {
 	// Open the file
 	file, err := os.Open(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return 0, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
-		return fmt.Errorf("failed to get file info: %w", err)
+		return 0, fmt.Errorf("failed to get file info: %w", err)
 	}
 
 	// Check if it's a regular file
 	if !fileInfo.Mode().IsRegular() {
-		return fmt.Errorf("not a regular file")
+		return 0, fmt.Errorf("not a regular file")
 	}
 
 	// Get base filename
@@ -583,46 +757,40 @@ func (m *ConnectManager) sendFile(conn *websocket.Conn, filePath string) error {
 
 	// Format file size
 	sizeStr := formatFileSize(fileInfo.Size())
-	fmt.Printf("\033[1;32m📤 Sending file: %s (%s)...\033[0m\n", filename, sizeStr)
+	progress.printf("\033[1;32m📤 Sending file: %s (%s)...\033[0m\n", filename, sizeStr)
 
 	// Check if we should use chunked transfer
-	if m.useChunked || fileInfo.Size() > 10*1024*1024 { // Use chunked if explicitly requested or file is larger than 10MB
+	peerPort := m.peerPort(conn)
+	if (m.useChunked || fileInfo.Size() > chunkedThreshold) && peerPort == 0 {
+		progress.printf("\033[1;33mℹ️ The peer does not accept chunked transfers. Sending over the connection instead...\033[0m\n")
+	} else if m.useChunked || fileInfo.Size() > chunkedThreshold { // Use chunked if explicitly requested or file is larger than 10MB
 		// For large files, use chunked transfer
-		fmt.Printf("\033[1;33mℹ️ Large file detected. Using chunked transfer...\033[0m\n")
+		progress.printf("\033[1;33mℹ️ Large file detected. Using chunked transfer...\033[0m\n")
 
-		// Get peer IP and port from WebSocket connection
-		peerAddr := conn.RemoteAddr().String()
-		peerIP, _, err := net.SplitHostPort(peerAddr)
+		// Upload to the port the peer announced, at the address we reached it on
+		peerIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
 		if err != nil {
-			// If we can't get the peer IP from the connection, try to get it from the local connection
-			peerIP, err = getLocalIP()
-			if err != nil {
-				peerIP = "localhost"
-			}
+			return 0, fmt.Errorf("failed to get peer address: %w", err)
 		}
 
 		// Create a chunked client
-		client := NewChunkedClient(fmt.Sprintf("http://%s:7531", peerIP), m.downloadPath, DefaultChunkSize)
+		client := NewChunkedClient(fmt.Sprintf("http://%s", net.JoinHostPort(peerIP, strconv.Itoa(peerPort))), m.downloadPath, DefaultChunkSize)
 
 		// Upload the file
 		resultPath, err := client.UploadFile(filePath, nil)
 		if err != nil {
-			return fmt.Errorf("failed to upload file using chunked transfer: %w", err)
+			return 0, fmt.Errorf("failed to upload file using chunked transfer: %w", err)
 		}
 
-		fmt.Printf("\033[1;32m📤 File uploaded successfully to: %s\033[0m\n", resultPath)
-		return nil
+		progress.printf("\033[1;32m📤 File uploaded successfully to: %s\033[0m\n", resultPath)
+		return fileInfo.Size(), nil
 	}
 
 	// For small files, use WebSocket transfer
-	// Show progress bar
-	fmt.Printf("\033[1;32m[                    ] 0%%\033[0m")
-	fmt.Printf("\r")
-
 	// Read file content
 	content, err := io.ReadAll(file)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return 0, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Create file transfer message
@@ -633,15 +801,121 @@ func (m *ConnectManager) sendFile(conn *websocket.Conn, filePath string) error {
 		Content:  content,
 	}
 
-	// Send the message
-	if err := conn.WriteJSON(msg); err != nil {
-		return fmt.Errorf("failed to send file: %w", err)
+	// Send the message, timing how long the peer takes to take it
+	meter := newRateMeter("sent", filename, fileInfo.Size())
+	err = m.writeJSON(conn, msg)
+	meter.finish(conn.RemoteAddr().String(), "websocket", err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send file: %w", err)
 	}
 
-	// Update progress bar to 100%
-	fmt.Printf("\033[1;32m[====================] 100%%\033[0m\n")
-	fmt.Printf("\033[1;32m📤 File sent successfully!\033[0m\n")
-	return nil
+	progress.printf("\033[1;32m📤 File sent successfully!\033[0m\n")
+	return fileInfo.Size(), nil
+}
+
+// receiveFile saves or stages a file sent over the WebSocket and acknowledges it
+func (m *ConnectManager) receiveFile(conn *websocket.Conn, msg FileTransferMessage) {
+	// The file arrives as one message, so there is no rate to measure
+	recordTransfer(&TransferStats{
+		Time:      time.Now(),
+		Peer:      conn.RemoteAddr().String(),
+		Direction: "received",
+		Transport: "websocket",
+		Filename:  msg.Filename,
+		Bytes:     int64(len(msg.Content)),
+		Success:   true,
+	})
+
+	if m.staging != nil {
+		if _, err := m.staging.Stage(msg.Filename, msg.Content); err != nil {
+			log.Printf("Error staging file: %v", err)
+		}
+	}
+
+	// Send acknowledgment
+	ack := FileTransferMessage{
+		Type:     "ack",
+		Filename: msg.Filename,
+	}
+	if err := m.writeJSON(conn, ack); err != nil {
+		log.Printf("Error sending acknowledgment: %v", err)
+	}
+
+	if m.staging == nil {
+		// Save the file
+		filename := m.saveFile(msg.Filename, msg.Content)
+
+		// Format file size
+		sizeStr := formatFileSize(int64(len(msg.Content)))
+		progress.printf("\033[1;36m📥 Received file: %s (%s)\033[0m\n", filename, sizeStr)
+	}
+}
+
+// stagingDescription describes the staging mode for the status box
+func (m *ConnectManager) stagingDescription() string {
+	if m.staging == nil {
+		return "off"
+	}
+	return "encrypted, accept to save"
+}
+
+// handleStagingCommand runs the pending, accept and reject commands and
+// reports whether line was one of them
+func (m *ConnectManager) handleStagingCommand(line string) bool {
+	fields := strings.Fields(line)
+	if m.staging == nil || len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "pending":
+		files := m.staging.List()
+		if len(files) == 0 {
+			fmt.Printf("\033[1;33mℹ️ No files are waiting for approval\033[0m\n")
+		}
+		for _, staged := range files {
+			fmt.Printf("\033[1;36m  #%d  %s (%s), received %s\033[0m\n",
+				staged.ID, staged.Filename, formatFileSize(staged.Size), utils.FormatRelative(staged.Received))
+		}
+	case "accept", "reject":
+		if len(fields) != 2 {
+			fmt.Printf("\033[1;33mℹ️ Usage: %s <id>|all\033[0m\n", fields[0])
+			return true
+		}
+		var ids []int
+		if fields[1] == "all" {
+			for _, staged := range m.staging.List() {
+				ids = append(ids, staged.ID)
+			}
+		} else {
+			id, err := strconv.Atoi(strings.TrimPrefix(fields[1], "#"))
+			if err != nil {
+				fmt.Printf("\033[1;31m❌ Invalid staged file ID: %s\033[0m\n", fields[1])
+				return true
+			}
+			ids = append(ids, id)
+		}
+
+		for _, id := range ids {
+			if fields[0] == "reject" {
+				if err := m.staging.Reject(id); err != nil {
+					fmt.Printf("\033[1;31m❌ Error rejecting file: %v\033[0m\n", err)
+				} else {
+					fmt.Printf("\033[1;33m🗑️ Deleted staged file #%d\033[0m\n", id)
+				}
+				continue
+			}
+			filePath, err := m.staging.Approve(id, m.downloadPath)
+			if err != nil {
+				fmt.Printf("\033[1;31m❌ Error accepting file: %v\033[0m\n", err)
+			} else {
+				fmt.Printf("\033[1;36m📥 Saved file: %s\033[0m\n", filePath)
+			}
+		}
+	default:
+		return false
+	}
+	return true
 }
 
 // saveFile saves a file to the downloads directory
@@ -654,17 +928,19 @@ func (m *ConnectManager) saveFile(filename string, content []byte) string {
 		m.downloadPath = "."
 	}
 
-	// Create timestamp
-	timestamp := time.Now().Format("20060102_150405")
-
-	// Create filename with timestamp
-	baseFilename := filepath.Base(filename)
-	ext := filepath.Ext(baseFilename)
-	name := strings.TrimSuffix(baseFilename, ext)
-	newFilename := fmt.Sprintf("%s_%s%s", name, timestamp, ext)
+	// Unpack directories sent as archives
+	if isDirectoryArchive(filename) {
+		dirPath, err := extractDirectory(bytes.NewReader(content), m.downloadPath, filename)
+		if err != nil {
+			log.Printf("Error extracting directory: %v", err)
+			return filename
+		}
+		notifyTransfer("received", dirPath, int64(len(content)))
+		return dirPath
+	}
 
 	// Create full path
-	filePath := filepath.Join(m.downloadPath, newFilename)
+	filePath := timestampedPath(m.downloadPath, safeFilename(filename))
 
 	// Write file
 	err = os.WriteFile(filePath, content, 0644)
@@ -673,9 +949,29 @@ func (m *ConnectManager) saveFile(filename string, content []byte) string {
 		return filename
 	}
 
+	notifyTransfer("received", filePath, int64(len(content)))
+
 	return filePath
 }
 
+// formatChunkedPort describes the chunked upload port for the status box
+func formatChunkedPort(port int) string {
+	if port == 0 {
+		return "unavailable"
+	}
+	return strconv.Itoa(port)
+}
+
+// notifyTransfer fires the post-transfer hook for a finished transfer
+func notifyTransfer(direction, filePath string, size int64) {
+	hooks.Fire(hooks.EventPostTransfer, map[string]interface{}{
+		"direction": direction,
+		"filename":  filepath.Base(filePath),
+		"path":      filePath,
+		"size":      size,
+	})
+}
+
 // getLocalIP returns the local IP address
 func getLocalIP() (string, error) {
 	addrs, err := net.InterfaceAddrs()
@@ -708,8 +1004,22 @@ func formatFileSize(size int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
 
+// windowsFileDialog shows the Windows open file dialog and prints the chosen path
+const windowsFileDialog = `Add-Type -AssemblyName System.Windows.Forms
+$dialog = New-Object System.Windows.Forms.OpenFileDialog
+$dialog.Title = 'Select a file to send'
+if ($dialog.ShowDialog() -eq [System.Windows.Forms.DialogResult]::OK) { $dialog.FileName }`
+
 // openFileDialog opens a file selection dialog
 func openFileDialog() (string, error) {
+	if runtime.GOOS == "windows" {
+		output, err := utils.RunPowerShell(windowsFileDialog, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to open the file dialog: %w", err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+
 	// Try to use zenity if available
 	cmd := exec.Command("zenity", "--file-selection", "--title=Select a file to send")
 	output, err := cmd.Output()
@@ -726,6 +1036,16 @@ func openFileDialog() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// UseDiscovery switches peer discovery and advertising to the given options
+func (m *ConnectManager) UseDiscovery(opts discovery.Options) error {
+	discoverer, err := discovery.New(opts)
+	if err != nil {
+		return err
+	}
+	m.discoverer = discoverer
+	return nil
+}
+
 // DiscoverServices discovers available Lumo Connect services on the network
 func (m *ConnectManager) DiscoverServices(ctx context.Context) ([]discovery.Service, error) {
 	// Start the discovery service if not already started
@@ -761,7 +1081,7 @@ func (m *ConnectManager) PrintDiscoveredServices(services []discovery.Service) {
 		fmt.Printf("│ \033[1;97m%d.\033[1;36m %-45s │\n", i+1, service.Name)
 		fmt.Printf("│   \033[1;97mIP:\033[1;36m %-43s │\n", service.IP)
 		fmt.Printf("│   \033[1;97mPort:\033[1;36m %-41d │\n", service.Port)
-		if username, ok := service.Info["username"]; ok {
+		if username, ok := service.Info[discovery.InfoUsername]; ok {
 			fmt.Printf("│   \033[1;97mUser:\033[1;36m %-41s │\n", username)
 		}
 		if i < len(services)-1 {
@@ -0,0 +1,137 @@
+package connect
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/gorilla/websocket"
+)
+
+// TestChunkedBroadcastFiresHook tests that sending a file to every client
+// in chunks fires the post-transfer hook once for the broadcast
+func TestChunkedBroadcastFiresHook(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	dir, err := hooks.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Each run writes its payload to a file of its own
+	payloads := filepath.Join(home, "payloads")
+	if err := os.Mkdir(payloads, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ncat > " + payloads + "/$$.tmp && mv " + payloads + "/$$.tmp " + payloads + "/$$.json\n"
+	if err := os.WriteFile(filepath.Join(dir, string(hooks.EventPostTransfer)), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	received := t.TempDir()
+	receiver, err := NewChunkedServer(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.Start(0); err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Stop()
+
+	// Two peers that both accept chunked uploads on the receiver
+	upgrader := websocket.Upgrader{}
+	peers := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer peers.Close()
+
+	m := NewConnectManager(t.TempDir(), 0, true)
+	for i := 0; i < 2; i++ {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(peers.URL, "http"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		m.setPeerPort(conn, receiver.Port())
+
+		connectionsMutex.Lock()
+		activeConnections[conn] = true
+		connectionsMutex.Unlock()
+		defer func() {
+			connectionsMutex.Lock()
+			delete(activeConnections, conn)
+			connectionsMutex.Unlock()
+		}()
+	}
+
+	filePath := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(filePath, []byte("quarterly numbers"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.sendFileToAllClients(filePath)
+
+	// Each upload also fires the hook on the receiving side
+	var sent, got []map[string]interface{}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && len(sent)+len(got) < 3 {
+		time.Sleep(20 * time.Millisecond)
+		sent, got = readTransferPayloads(t, payloads)
+	}
+	time.Sleep(100 * time.Millisecond)
+	sent, got = readTransferPayloads(t, payloads)
+
+	if len(got) != 2 {
+		t.Fatalf("the receiver fired %d hooks, want 2", len(got))
+	}
+	if len(sent) != 1 {
+		t.Fatalf("the broadcast fired %d hooks, want 1", len(sent))
+	}
+	if sent[0]["filename"] != "report.txt" || sent[0]["path"] != filePath {
+		t.Errorf("unexpected hook data: %v", sent[0])
+	}
+}
+
+// readTransferPayloads reads the post-transfer payloads a hook recorded,
+// split by direction
+func readTransferPayloads(t *testing.T, dir string) (sent, received []map[string]interface{}) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var payload hooks.Payload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("bad payload %q: %v", data, err)
+		}
+		switch payload.Data["direction"] {
+		case "sent":
+			sent = append(sent, payload.Data)
+		case "received":
+			received = append(received, payload.Data)
+		}
+	}
+	return sent, received
+}
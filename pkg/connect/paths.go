@@ -0,0 +1,84 @@
+package connect
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// reservedNameChars cannot appear in file names on Windows
+const reservedNameChars = `<>:"/\|?*`
+
+// reservedWindowsNames are device names Windows will not use as file names
+var reservedWindowsNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// safeFilename turns a file name sent by a peer into one that can be saved
+// inside the download directory on any platform. Peers may send full paths
+// with either separator, so only the last element is kept.
+func safeFilename(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(reservedNameChars, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	// Windows drops trailing dots and spaces, which would change the name
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "received_file"
+	}
+
+	base := name
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	if reservedWindowsNames[strings.ToUpper(base)] {
+		name = "_" + name
+	}
+	return name
+}
+
+// normalizeDroppedPath cleans up a path typed or dragged into the terminal.
+// Some terminals insert file:// URLs or quote the path, and Windows
+// terminals use drive letters and backslashes.
+func normalizeDroppedPath(path, goos string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "file://") {
+		path = strings.TrimPrefix(path, "file://")
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+	}
+
+	// Trim any quotes or whitespace that might be around the path
+	path = strings.Trim(path, "\"' \t\n\r")
+
+	if goos == "windows" {
+		// file:///C:/Users/... leaves a slash in front of the drive letter
+		if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		path = strings.ReplaceAll(path, "/", `\`)
+	}
+	return path
+}
+
+// timestampedPath returns where a received file is saved in dir. A
+// timestamp is added to the name so repeated transfers do not overwrite
+// each other.
+func timestampedPath(dir, filename string) string {
+	ext := filepath.Ext(filename)
+	name := strings.TrimSuffix(filename, ext)
+	return filepath.Join(dir, fmt.Sprintf("%s_%s%s", name, time.Now().Format("20060102_150405"), ext))
+}
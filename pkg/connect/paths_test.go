@@ -0,0 +1,47 @@
+package connect
+
+import "testing"
+
+func TestSafeFilename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"/home/sam/report.pdf", "report.pdf"},
+		{`C:\Users\sam\Documents\report.pdf`, "report.pdf"},
+		{`..\..\Windows\System32\evil.dll`, "evil.dll"},
+		{"../../.bashrc", ".bashrc"},
+		{`what?"now".txt`, "what__now_.txt"},
+		{"notes.txt. ", "notes.txt"},
+		{"CON.txt", "_CON.txt"},
+		{"..", "received_file"},
+		{"", "received_file"},
+	}
+
+	for _, tc := range testCases {
+		if got := safeFilename(tc.name); got != tc.expected {
+			t.Errorf("safeFilename(%q) = %q, expected %q", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestNormalizeDroppedPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		goos     string
+		expected string
+	}{
+		{"'/home/sam/My File.txt'", "linux", "/home/sam/My File.txt"},
+		{"file:///home/sam/My%20File.txt", "linux", "/home/sam/My File.txt"},
+		{`"C:\Users\sam\My File.txt"`, "windows", `C:\Users\sam\My File.txt`},
+		{"file:///C:/Users/sam/My%20File.txt", "windows", `C:\Users\sam\My File.txt`},
+		{"select", "windows", "select"},
+	}
+
+	for _, tc := range testCases {
+		if got := normalizeDroppedPath(tc.path, tc.goos); got != tc.expected {
+			t.Errorf("normalizeDroppedPath(%q, %q) = %q, expected %q", tc.path, tc.goos, got, tc.expected)
+		}
+	}
+}
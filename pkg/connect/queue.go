@@ -0,0 +1,167 @@
+package connect
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// DefaultParallelSends is how many queued files are sent at once by default
+	DefaultParallelSends = 1
+
+	// MaxParallelSends limits how many files are sent at once
+	MaxParallelSends = 8
+
+	// sendQueueSize is how many files can wait before adding another blocks
+	sendQueueSize = 256
+)
+
+// queuedFile is a file or folder waiting to be sent
+type queuedFile struct {
+	path  string
+	size  int64
+	isDir bool
+}
+
+// sendQueue sends the files the user drops in the order they were dropped,
+// so the next path can be typed while earlier files are still sending.
+// Several files can be sent at once, but each uses the session's own send
+// function, so files sharing one connection still go one at a time.
+type sendQueue struct {
+	send func(filePath string)
+	// sendDir sends a folder; by default it is packed and sent with send
+	sendDir func(dirPath string)
+	jobs    chan queuedFile
+	wait    sync.WaitGroup
+
+	// The current batch: files queued since the queue was last empty.
+	// Guarded by progress.mutex, since the progress line reads them.
+	batchFiles int
+	batchDone  int
+	batchBytes int64
+	doneBytes  int64
+}
+
+// newSendQueue starts a queue that sends up to parallel files at once
+func newSendQueue(parallel int, send func(filePath string)) *sendQueue {
+	parallel = max(1, min(parallel, MaxParallelSends))
+	q := &sendQueue{
+		send: send,
+		jobs: make(chan queuedFile, sendQueueSize),
+	}
+	for i := 0; i < parallel; i++ {
+		q.wait.Add(1)
+		go q.run()
+	}
+
+	progress.mutex.Lock()
+	progress.queue = q
+	progress.mutex.Unlock()
+	return q
+}
+
+// add queues a file or folder to be sent
+func (q *sendQueue) add(filePath string) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		progress.printf("\033[1;31m❌ Error reading %s: %v\033[0m\n", filePath, err)
+		return
+	}
+	file := queuedFile{path: filePath, size: info.Size(), isDir: info.IsDir()}
+	if file.isDir {
+		file.size = directorySize(filePath)
+	}
+
+	progress.mutex.Lock()
+	q.batchFiles++
+	q.batchBytes += file.size
+	waiting := q.batchFiles - q.batchDone - 1
+	progress.mutex.Unlock()
+
+	if waiting > 0 {
+		progress.printf("\033[1;33m📋 Queued %s (%s), %d ahead of it\033[0m\n", filepath.Base(filePath), formatFileSize(file.size), waiting)
+	}
+	q.jobs <- file
+}
+
+// run sends queued files until the queue is closed
+func (q *sendQueue) run() {
+	defer q.wait.Done()
+	for file := range q.jobs {
+		if file.isDir && q.sendDir != nil {
+			q.sendDir(file.path)
+		} else if file.isDir {
+			sendDirectory(file.path, q.send)
+		} else {
+			q.send(file.path)
+		}
+		q.finished(file)
+	}
+}
+
+// finished counts a sent file, ending the batch once the queue is empty
+func (q *sendQueue) finished(file queuedFile) {
+	progress.mutex.Lock()
+	q.batchDone++
+	q.doneBytes += file.size
+	var summary string
+	if q.batchDone == q.batchFiles {
+		if q.batchFiles > 1 {
+			summary = fmt.Sprintf("\033[1;32m📦 Finished sending %d files (%s)\033[0m\n", q.batchFiles, formatFileSize(q.batchBytes))
+		}
+		q.batchFiles, q.batchDone, q.batchBytes, q.doneBytes = 0, 0, 0, 0
+	}
+	progress.mutex.Unlock()
+
+	if summary != "" {
+		progress.printf("%s", summary)
+	}
+}
+
+// close waits for the queued files to be sent and stops the queue
+func (q *sendQueue) close() {
+	close(q.jobs)
+	q.wait.Wait()
+
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	if progress.queue == q {
+		progress.queue = nil
+	}
+}
+
+// summary describes the progress of the batch for the progress line, or
+// returns "" when only one file is queued. The caller must hold progress.mutex.
+func (q *sendQueue) summary(active []*rateMeter) string {
+	if q.batchFiles <= 1 {
+		return ""
+	}
+	done := q.doneBytes
+	for _, meter := range active {
+		if meter.direction == "sent" {
+			done += meter.done
+		}
+	}
+	percent := 100
+	if q.batchBytes > 0 {
+		percent = int(min(done*100/q.batchBytes, 100))
+	}
+	return fmt.Sprintf("[queue %d/%d files, %d%%]", q.batchDone, q.batchFiles, percent)
+}
+
+// directorySize adds up the sizes of the regular files in a directory
+func directorySize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,73 @@
+package connect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queueFiles creates n small files to queue
+func queueFiles(t *testing.T, n int) []string {
+	dir := t.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("file%d.txt", i))
+		if err := os.WriteFile(paths[i], []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func TestSendQueue(t *testing.T) {
+	for _, parallel := range []int{1, 3} {
+		t.Run(fmt.Sprintf("parallel %d", parallel), func(t *testing.T) {
+			paths := queueFiles(t, 6)
+
+			var mutex sync.Mutex
+			var sent []string
+			running, busiest := 0, 0
+			queue := newSendQueue(parallel, func(filePath string) {
+				mutex.Lock()
+				running++
+				busiest = max(busiest, running)
+				mutex.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+
+				mutex.Lock()
+				running--
+				sent = append(sent, filePath)
+				mutex.Unlock()
+			})
+			for _, path := range paths {
+				queue.add(path)
+			}
+			queue.add(filepath.Join(t.TempDir(), "missing.txt"))
+
+			// close waits for every queued file
+			queue.close()
+			if len(sent) != len(paths) {
+				t.Fatalf("sent %d files, want %d", len(sent), len(paths))
+			}
+			if busiest > parallel {
+				t.Errorf("%d files were sent at once, want at most %d", busiest, parallel)
+			}
+			if parallel == 1 {
+				for i, path := range paths {
+					if sent[i] != path {
+						t.Errorf("file %d sent was %s, want %s", i, sent[i], path)
+					}
+				}
+			} else if busiest < 2 {
+				t.Errorf("files were sent one at a time with parallel %d", parallel)
+			}
+			if queue.batchFiles != 0 || queue.batchDone != 0 {
+				t.Errorf("batch was not reset: %d of %d files done", queue.batchDone, queue.batchFiles)
+			}
+		})
+	}
+}
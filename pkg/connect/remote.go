@@ -0,0 +1,252 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RemoteCredentials sign in to an SFTP server or a WebDAV share
+type RemoteCredentials struct {
+	Username string
+	Password string
+	// KeyFile is a private key for SFTP, tried before the default keys
+	KeyFile string
+}
+
+// RemoteOptions configure pushing files to an SFTP server or a WebDAV share
+type RemoteOptions struct {
+	Credentials RemoteCredentials
+	// Prompt asks the user for what is missing, such as a password, hiding
+	// the answer when secret is set; without it, nothing is asked
+	Prompt func(label string, secret bool) (string, error)
+	// Confirm asks the user a yes or no question, such as whether to trust
+	// an SFTP server seen for the first time; without it, the answer is no
+	Confirm func(question string) bool
+}
+
+// prompt asks for a value, failing when no one can be asked
+func (o RemoteOptions) prompt(label string, secret bool) (string, error) {
+	if o.Prompt == nil {
+		return "", fmt.Errorf("%s is required", strings.ToLower(label[:1])+label[1:])
+	}
+	return o.Prompt(label, secret)
+}
+
+// remoteTarget is a server files are pushed to that does not run lumo
+type remoteTarget interface {
+	// mkdirAll creates a directory, relative to the target's directory,
+	// with its parents
+	mkdirAll(dir string) error
+	// size returns how much of a file the target already has, so an
+	// interrupted upload can resume; 0 means starting over
+	size(name string) (int64, error)
+	// put writes size bytes from r to a file, starting at offset
+	put(ctx context.Context, name string, r io.Reader, offset, size int64) error
+	// reconnect replaces the connection after an upload failed
+	reconnect(ctx context.Context) error
+	close() error
+	// peer and transport name the target in transfer statistics
+	peer() string
+	transport() string
+}
+
+// permanentError is an upload failure that trying again cannot fix, such
+// as a refused login
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// IsRemoteURL reports whether a connect target is an SFTP server
+// (sftp://) or a WebDAV share (webdav://, webdavs://, http://, or
+// https://) rather than a peer running lumo
+func IsRemoteURL(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "sftp", "webdav", "webdavs", "dav", "davs", "http", "https":
+		return true
+	}
+	return false
+}
+
+// dialRemote connects to an SFTP server or a WebDAV share
+func dialRemote(ctx context.Context, target string, opts RemoteOptions) (remoteTarget, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote target: %s", target)
+	}
+	if u.User != nil {
+		if opts.Credentials.Username == "" || u.User.Username() != "" {
+			opts.Credentials.Username = u.User.Username()
+		}
+		if password, ok := u.User.Password(); ok {
+			opts.Credentials.Password = password
+		}
+	}
+	if strings.EqualFold(u.Scheme, "sftp") {
+		return dialSFTP(ctx, u, opts)
+	}
+	return dialWebDAV(ctx, u, opts)
+}
+
+// PushFiles uploads files and folders to an SFTP server or a WebDAV share.
+// Folders are uploaded with their layout instead of being packed, since
+// nothing on the server unpacks them. Interrupted uploads are retried, and
+// resume where they stopped if the server allows it.
+func (m *ConnectManager) PushFiles(ctx context.Context, target string, paths []string, opts RemoteOptions) error {
+	remote, err := dialRemote(ctx, target, opts)
+	if err != nil {
+		return err
+	}
+	defer remote.close()
+
+	var failed int
+	for _, path := range paths {
+		if err := pushPath(ctx, remote, path); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			progress.printf("\033[1;31m❌ Error sending %s: %v\033[0m\n", path, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", failed, len(paths))
+	}
+	return nil
+}
+
+// PushInteractive connects to an SFTP server or a WebDAV share and uploads
+// the files and folders the user drops into the terminal, until stdin
+// closes or the context is cancelled
+func (m *ConnectManager) PushInteractive(ctx context.Context, target string, opts RemoteOptions) error {
+	remote, err := dialRemote(ctx, target, opts)
+	if err != nil {
+		return err
+	}
+	defer remote.close()
+
+	fmt.Printf("\033[1;32m🔌 Connected to %s over %s\033[0m\n", remote.peer(), remote.transport())
+	m.sendDir = func(dir string) {
+		if err := pushPath(ctx, remote, dir); err != nil {
+			progress.printf("\033[1;31m❌ Error sending %s: %v\033[0m\n", dir, err)
+		}
+	}
+	defer func() { m.sendDir = nil }()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.readFilePaths(func(filePath string) {
+			if err := pushPath(ctx, remote, filePath); err != nil {
+				progress.printf("\033[1;31m❌ Error sending %s: %v\033[0m\n", filePath, err)
+			}
+		})
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// pushPath uploads a file, or a folder and everything in it
+func pushPath(ctx context.Context, remote remoteTarget, localPath string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return pushFile(ctx, remote, localPath, filepath.Base(localPath), info.Size())
+	}
+
+	root := filepath.Clean(localPath)
+	base := filepath.Base(root)
+	return filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(base, filepath.ToSlash(rel))
+		switch {
+		case entry.IsDir():
+			return remote.mkdirAll(name)
+		case entry.Type().IsRegular():
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			return pushFile(ctx, remote, p, name, info.Size())
+		}
+		// Links and devices are left out, as when packing folders for peers
+		return nil
+	})
+}
+
+// pushFile uploads one file, resuming it after interruptions as chunked
+// uploads to peers do
+func pushFile(ctx context.Context, remote remoteTarget, localPath, name string, size int64) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	progress.printf("\033[1;32m📤 Uploading %s (%s) to %s...\033[0m\n", name, formatFileSize(size), remote.peer())
+	meter := newRateMeter("sent", name, size)
+	var offset int64
+	for attempt := 1; ; attempt++ {
+		if _, err = file.Seek(offset, io.SeekStart); err == nil {
+			err = remote.put(ctx, name, &meteredReader{r: file, meter: meter}, offset, size)
+		}
+		var permanent *permanentError
+		if err == nil || ctx.Err() != nil || errors.As(err, &permanent) || attempt > resumeAttempts {
+			break
+		}
+
+		progress.printf("\033[1;33m⚠️  Upload interrupted (%v), resuming...\033[0m\n", err)
+		meter.retry()
+		select {
+		case <-time.After(time.Duration(attempt) * resumeDelay):
+		case <-ctx.Done():
+		}
+		if reconnectErr := remote.reconnect(ctx); reconnectErr != nil {
+			continue
+		}
+		offset, _ = remote.size(name)
+		offset = max(0, min(offset, size))
+		meter.reset(offset)
+	}
+	meter.finish(remote.peer(), remote.transport(), err)
+	return err
+}
+
+// meteredReader counts what is read from a file on a transfer's progress
+// line
+type meteredReader struct {
+	r     io.Reader
+	meter *rateMeter
+}
+
+func (m *meteredReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.meter.add(int64(n))
+	return n, err
+}
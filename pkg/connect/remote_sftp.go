@@ -0,0 +1,302 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sftpDialTimeout bounds connecting and signing in to an SFTP server
+const sftpDialTimeout = 30 * time.Second
+
+// defaultKeyFiles are the SSH keys tried when no key is configured, as ssh
+// tries them
+var defaultKeyFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// sftpTarget pushes files to a directory on an SFTP server
+type sftpTarget struct {
+	addr   string
+	config *ssh.ClientConfig
+	// dir is where files go: absolute, or relative to the login directory
+	dir    string
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+// dialSFTP signs in to an SFTP server with a password, the SSH agent, or
+// a key, checking the server against ~/.ssh/known_hosts
+func dialSFTP(ctx context.Context, u *url.URL, opts RemoteOptions) (*sftpTarget, error) {
+	username := opts.Credentials.Username
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("a user name is required: use sftp://user@host/path")
+		}
+		username = current.Username
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	hostKeys, err := hostKeyCallback(opts)
+	if err != nil {
+		return nil, err
+	}
+	t := &sftpTarget{
+		addr: addr,
+		config: &ssh.ClientConfig{
+			User:            username,
+			Auth:            sshAuthMethods(username, u.Hostname(), opts),
+			HostKeyCallback: hostKeys,
+			Timeout:         sftpDialTimeout,
+		},
+		dir: sftpDir(u.Path),
+	}
+	if err := t.reconnect(ctx); err != nil {
+		return nil, err
+	}
+	if err := t.client.MkdirAll(t.path("")); err != nil {
+		t.close()
+		return nil, fmt.Errorf("failed to create %s on %s: %w", t.path(""), u.Hostname(), err)
+	}
+	return t, nil
+}
+
+// sftpDir turns a URL path into the directory files go to. As with most
+// SFTP clients, /~/ starts a path in the login directory.
+func sftpDir(urlPath string) string {
+	if urlPath == "" || urlPath == "/~" {
+		return "."
+	}
+	if rest, ok := strings.CutPrefix(urlPath, "/~/"); ok {
+		return path.Clean(rest)
+	}
+	return path.Clean(urlPath)
+}
+
+// sshAuthMethods returns the ways to sign in, in the order ssh tries them:
+// the SSH agent and keys, then a password, which is asked for at most once
+func sshAuthMethods(username, host string, opts RemoteOptions) []ssh.AuthMethod {
+	var methods []ssh.AuthMethod
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	var keyFiles []string
+	if opts.Credentials.KeyFile != "" {
+		keyFiles = append(keyFiles, opts.Credentials.KeyFile)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range defaultKeyFiles {
+			keyFiles = append(keyFiles, filepath.Join(home, ".ssh", name))
+		}
+	}
+	var signers []ssh.Signer
+	for _, keyFile := range keyFiles {
+		if signer, err := loadSigner(keyFile, opts); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	password := opts.Credentials.Password
+	askPassword := func() (string, error) {
+		if password == "" {
+			answer, err := opts.prompt(fmt.Sprintf("Password for %s@%s", username, host), true)
+			if err != nil {
+				return "", err
+			}
+			password = answer
+		}
+		return password, nil
+	}
+	methods = append(methods, ssh.PasswordCallback(askPassword))
+	methods = append(methods, ssh.KeyboardInteractive(func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+		// Servers that only ask for the password this way are common
+		if len(questions) == 1 && !echos[0] {
+			answer, err := askPassword()
+			return []string{answer}, err
+		}
+		answers := make([]string, len(questions))
+		for i, question := range questions {
+			answer, err := opts.prompt(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(question), ":")), !echos[i])
+			if err != nil {
+				return nil, err
+			}
+			answers[i] = answer
+		}
+		return answers, nil
+	}))
+	return methods
+}
+
+// loadSigner reads a private key, asking for its passphrase if it has one
+func loadSigner(keyFile string, opts RemoteOptions) (ssh.Signer, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) && opts.Prompt != nil {
+		passphrase, promptErr := opts.Prompt(fmt.Sprintf("Passphrase for %s", keyFile), true)
+		if promptErr != nil {
+			return nil, promptErr
+		}
+		return ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+	}
+	return signer, err
+}
+
+// hostKeyCallback checks servers against ~/.ssh/known_hosts. A server seen
+// for the first time is added once the user trusts it; one whose key
+// changed is refused, as ssh does.
+func hostKeyCallback(opts RemoteOptions) (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	knownHostsPath := filepath.Join(home, ".ssh", "known_hosts")
+	if _, err := os.Stat(knownHostsPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(knownHostsPath), 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(knownHostsPath, nil, 0600); err != nil {
+			return nil, err
+		}
+	}
+	known, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", knownHostsPath, err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := known(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			return fmt.Errorf("the host key of %s has changed, which may mean someone is intercepting the connection; if the server was reinstalled, remove its old key from %s", hostname, knownHostsPath)
+		}
+
+		question := fmt.Sprintf("The authenticity of %s can't be established.\n%s key fingerprint is %s.\nTrust it and continue connecting?",
+			hostname, key.Type(), ssh.FingerprintSHA256(key))
+		if opts.Confirm == nil || !opts.Confirm(question) {
+			return fmt.Errorf("%s is not a known host; connect to it with ssh once to trust it", hostname)
+		}
+		file, err := os.OpenFile(knownHostsPath, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = fmt.Fprintln(file, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key))
+		return err
+	}, nil
+}
+
+// path returns where a file or folder relative to the target goes
+func (t *sftpTarget) path(name string) string {
+	return path.Join(t.dir, name)
+}
+
+func (t *sftpTarget) mkdirAll(dir string) error {
+	return t.client.MkdirAll(t.path(dir))
+}
+
+func (t *sftpTarget) size(name string) (int64, error) {
+	info, err := t.client.Stat(t.path(name))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (t *sftpTarget) put(ctx context.Context, name string, r io.Reader, offset, size int64) error {
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := t.client.OpenFile(t.path(name), flags)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return &permanentError{err: fmt.Errorf("cannot write %s: %w", t.path(name), err)}
+		}
+		return err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	// Closing the connection stops a copy in progress when cancelled
+	stop := context.AfterFunc(ctx, func() { t.conn.Close() })
+	defer stop()
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (t *sftpTarget) reconnect(ctx context.Context) error {
+	t.close()
+
+	dialer := net.Dialer{Timeout: sftpDialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", t.addr, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, t.addr, t.config)
+	if err != nil {
+		netConn.Close()
+		return &permanentError{err: fmt.Errorf("failed to sign in to %s: %w", t.addr, err)}
+	}
+	t.conn = ssh.NewClient(sshConn, chans, reqs)
+	t.client, err = sftp.NewClient(t.conn)
+	if err != nil {
+		t.conn.Close()
+		return &permanentError{err: fmt.Errorf("%s does not offer SFTP: %w", t.addr, err)}
+	}
+	return nil
+}
+
+func (t *sftpTarget) close() error {
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+	if t.conn != nil {
+		err := t.conn.Close()
+		t.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (t *sftpTarget) peer() string {
+	return t.config.User + "@" + t.addr
+}
+
+func (t *sftpTarget) transport() string {
+	return "sftp"
+}
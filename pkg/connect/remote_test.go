@@ -0,0 +1,152 @@
+package connect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIsRemoteURL(t *testing.T) {
+	for target, want := range map[string]bool{
+		"sftp://me@nas.local/~/backup":          true,
+		"webdavs://cloud.example.com/dav/":      true,
+		"https://cloud.example.com/remote.php/": true,
+		"192.168.1.5":                           false,
+		"192.168.1.5:9000":                      false,
+		"sftp:///no-host":                       false,
+		"ftp://example.com/":                    false,
+	} {
+		if got := IsRemoteURL(target); got != want {
+			t.Errorf("IsRemoteURL(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestSFTPDir(t *testing.T) {
+	for urlPath, want := range map[string]string{
+		"":                ".",
+		"/~":              ".",
+		"/~/backup/":      "backup",
+		"/srv/uploads/":   "/srv/uploads",
+		"/srv/../uploads": "/uploads",
+	} {
+		if got := sftpDir(urlPath); got != want {
+			t.Errorf("sftpDir(%q) = %q, want %q", urlPath, got, want)
+		}
+	}
+}
+
+// fakeWebDAV is a WebDAV share in memory that wants a password
+type fakeWebDAV struct {
+	mutex   sync.Mutex
+	folders map[string]bool
+	files   map[string]string
+}
+
+func (f *fakeWebDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if username, password, ok := r.BasicAuth(); !ok || username != "ada" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	name := strings.TrimSuffix(r.URL.Path, "/")
+	parent := name[:strings.LastIndex(name, "/")]
+	switch r.Method {
+	case "PROPFIND":
+		if !f.folders[name] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+	case "MKCOL":
+		switch {
+		case f.folders[name]:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case !f.folders[parent]:
+			w.WriteHeader(http.StatusConflict)
+		default:
+			f.folders[name] = true
+			w.WriteHeader(http.StatusCreated)
+		}
+	case http.MethodPut:
+		if !f.folders[parent] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.files[name] = string(data)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestPushFilesWebDAV(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+
+	share := &fakeWebDAV{folders: map[string]bool{"": true, "/dav": true}, files: map[string]string{}}
+	server := httptest.NewServer(share)
+	defer server.Close()
+
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.txt")
+	photos := filepath.Join(dir, "photos")
+	for path, data := range map[string]string{
+		report:                                 "quarterly",
+		filepath.Join(photos, "cat.jpg"):       "meow",
+		filepath.Join(photos, "trip", "a.jpg"): "sea",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The password is asked for once the share refuses the saved user name
+	var asked []string
+	opts := RemoteOptions{
+		Credentials: RemoteCredentials{Username: "ada"},
+		Prompt: func(label string, secret bool) (string, error) {
+			asked = append(asked, label)
+			return "secret", nil
+		},
+	}
+	target := "webdav://" + strings.TrimPrefix(server.URL, "http://") + "/dav/inbox"
+	m := NewConnectManager(t.TempDir(), 0, false)
+	if err := m.PushFiles(context.Background(), target, []string{report, photos}, opts); err != nil {
+		t.Fatalf("PushFiles: %v", err)
+	}
+
+	if len(asked) != 1 || !strings.HasPrefix(asked[0], "Password for ada@") {
+		t.Errorf("Expected only the password to be asked for, got %q", asked)
+	}
+	want := map[string]string{
+		"/dav/inbox/report.txt":        "quarterly",
+		"/dav/inbox/photos/cat.jpg":    "meow",
+		"/dav/inbox/photos/trip/a.jpg": "sea",
+	}
+	for name, data := range want {
+		if share.files[name] != data {
+			t.Errorf("Expected %s to hold %q, got %q", name, data, share.files[name])
+		}
+	}
+	if len(share.files) != len(want) {
+		t.Errorf("Expected %d files, got %v", len(want), share.files)
+	}
+
+	// Without anyone to ask, a missing password fails
+	opts.Prompt = nil
+	if err := m.PushFiles(context.Background(), target, []string{report}, opts); err == nil {
+		t.Error("Expected the push to fail without a password")
+	}
+}
@@ -0,0 +1,195 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// webdavRequestTimeout bounds WebDAV requests other than uploads, which
+// take as long as the file needs
+const webdavRequestTimeout = 30 * time.Second
+
+// webdavLoginAttempts is how many times a refused password is asked for
+// again
+const webdavLoginAttempts = 3
+
+// webdavTarget pushes files to a folder on a WebDAV share
+type webdavTarget struct {
+	// base is the folder's URL, without credentials
+	base     *url.URL
+	username string
+	password string
+	client   *http.Client
+}
+
+// dialWebDAV checks that a WebDAV share can be written to, asking for a user
+// name and password if it wants them, and creates the folder if it is
+// missing. webdav:// and dav:// are plain HTTP; webdavs:// and davs:// are
+// HTTPS.
+func dialWebDAV(ctx context.Context, u *url.URL, opts RemoteOptions) (*webdavTarget, error) {
+	base := *u
+	base.User = nil
+	switch strings.ToLower(base.Scheme) {
+	case "webdav", "dav":
+		base.Scheme = "http"
+	case "webdavs", "davs":
+		base.Scheme = "https"
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	t := &webdavTarget{
+		base:     &base,
+		username: opts.Credentials.Username,
+		password: opts.Credentials.Password,
+		client:   &http.Client{},
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, err := t.request(ctx, "PROPFIND", "", nil, 0, map[string]string{"Depth": "0"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", base.Host, err)
+		}
+		switch {
+		case status == http.StatusUnauthorized:
+			if attempt > webdavLoginAttempts {
+				return nil, fmt.Errorf("%s refused the user name or password", base.Host)
+			}
+			if attempt > 1 || t.username == "" {
+				if t.username, err = opts.prompt(fmt.Sprintf("User name for %s", base.Host), false); err != nil {
+					return nil, err
+				}
+			}
+			if attempt > 1 || t.password == "" {
+				if t.password, err = opts.prompt(fmt.Sprintf("Password for %s@%s", t.username, base.Host), true); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		case status == http.StatusNotFound:
+			if err := t.mkcol(ctx, ""); err != nil {
+				return nil, err
+			}
+		case status >= 400:
+			return nil, fmt.Errorf("%s is not a WebDAV share (%s)", base.String(), http.StatusText(status))
+		}
+		return t, nil
+	}
+}
+
+// url returns the address of a file or folder relative to the target
+func (t *webdavTarget) url(name string) string {
+	if name == "" {
+		return t.base.String()
+	}
+	return t.base.JoinPath(strings.Split(name, "/")...).String()
+}
+
+// request sends a WebDAV request and returns its status
+func (t *webdavTarget) request(ctx context.Context, method, name string, body io.Reader, size int64, header map[string]string) (int, error) {
+	if body == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, webdavRequestTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.url(name), body)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if t.username != "" || t.password != "" {
+		req.SetBasicAuth(t.username, t.password)
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// mkcol creates one folder; one that already exists is fine
+func (t *webdavTarget) mkcol(ctx context.Context, name string) error {
+	status, err := t.request(ctx, "MKCOL", name, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusCreated, http.StatusMethodNotAllowed:
+		return nil
+	case http.StatusConflict:
+		return &permanentError{err: fmt.Errorf("cannot create %s: its parent folder does not exist", t.url(name))}
+	}
+	return statusError(status, t.url(name))
+}
+
+func (t *webdavTarget) mkdirAll(dir string) error {
+	// WebDAV creates one folder at a time, so parents come first
+	parts := strings.Split(dir, "/")
+	for i := range parts {
+		if err := t.mkcol(context.Background(), strings.Join(parts[:i+1], "/")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// size is always 0: plain WebDAV cannot append to a file, so an interrupted
+// upload starts over
+func (t *webdavTarget) size(name string) (int64, error) {
+	return 0, nil
+}
+
+func (t *webdavTarget) put(ctx context.Context, name string, r io.Reader, offset, size int64) error {
+	status, err := t.request(ctx, http.MethodPut, name, r, size-offset, nil)
+	if err != nil {
+		return err
+	}
+	if status >= 300 {
+		return statusError(status, t.url(name))
+	}
+	return nil
+}
+
+// reconnect has nothing to do, as each request makes its own connection
+// when the last one broke
+func (t *webdavTarget) reconnect(ctx context.Context) error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+func (t *webdavTarget) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+func (t *webdavTarget) peer() string {
+	return t.base.Host
+}
+
+func (t *webdavTarget) transport() string {
+	return "webdav"
+}
+
+// statusError describes a failed WebDAV request. Client errors, apart from
+// timeouts and rate limits, are permanent.
+func statusError(status int, target string) error {
+	err := fmt.Errorf("%s: %d %s", target, status, http.StatusText(status))
+	if status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests {
+		return &permanentError{err: err}
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// helloTimeout is how long SendFiles waits for the peer to announce its
+// chunked port before sending everything over the connection
+const helloTimeout = 2 * time.Second
+
+// SendFiles connects to a peer started with "lumo connect --receive" and
+// sends files and folders to it without prompting, returning once the peer
+// has received them all. Folders are packed and sent as one archive each.
+func (m *ConnectManager) SendFiles(ctx context.Context, peerIP string, peerPort int, paths []string) error {
+	m.mode = "client"
+
+	url := fmt.Sprintf("ws://%s:%d/ws", peerIP, peerPort)
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to peer: %w", err)
+	}
+	defer conn.Close()
+	defer m.forgetPeer(conn)
+	if err := m.sendHello(conn); err != nil {
+		return fmt.Errorf("failed to send handshake: %w", err)
+	}
+
+	hello := make(chan struct{})
+	acks := make(chan struct{}, len(paths))
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		var once sync.Once
+		for {
+			var msg FileTransferMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			switch msg.Type {
+			case "hello":
+				m.setPeerPort(conn, msg.ChunkedPort)
+				once.Do(func() { close(hello) })
+			case "ack":
+				select {
+				case acks <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	// Large files go to the peer's chunked listener, which it announces first
+	select {
+	case <-hello:
+	case <-time.After(helloTimeout):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Files sent over the connection are acknowledged; chunked uploads
+	// have finished when sendFile returns
+	pending := 0
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to send %s: %w", path, err)
+		}
+		filePath := path
+		if info.IsDir() {
+			archivePath, cleanup, err := packDirectory(path)
+			if err != nil {
+				return fmt.Errorf("failed to pack folder %s: %w", path, err)
+			}
+			defer cleanup()
+			if info, err = os.Stat(archivePath); err != nil {
+				return err
+			}
+			filePath = archivePath
+		}
+
+		chunked := (m.useChunked || info.Size() > chunkedThreshold) && m.peerPort(conn) != 0
+		if err := m.sendFile(conn, filePath); err != nil {
+			return fmt.Errorf("failed to send %s: %w", path, err)
+		}
+		if !chunked {
+			pending++
+		}
+	}
+
+	for ; pending > 0; pending-- {
+		select {
+		case <-acks:
+		case <-disconnected:
+			return fmt.Errorf("peer disconnected before receiving every file")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	m.writeMutex.Lock()
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	m.writeMutex.Unlock()
+	return nil
+}
@@ -0,0 +1,282 @@
+package connect
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// signalTTL is how long an unfinished signaling exchange is kept
+	signalTTL = 10 * time.Minute
+
+	// signalWait is how long a GET waits for the other peer before the client polls again
+	signalWait = 25 * time.Second
+
+	// maxSignalSize limits the size of a session description
+	maxSignalSize = 64 * 1024
+
+	// MaxSignalSlots is how many exchanges the store holds at once
+	MaxSignalSlots = 256
+)
+
+// ErrSignalStoreFull is returned when a post would start more exchanges than
+// MaxSignalSlots, until older ones finish expiring
+var ErrSignalStoreFull = errors.New("too many connections are being set up, try again later")
+
+// signalSlot holds the offer and answer exchanged under one code
+type signalSlot struct {
+	values  map[string]string
+	ready   map[string]chan struct{}
+	created time.Time
+}
+
+// SignalStore is the mailbox two WebRTC peers use to swap session
+// descriptions. Each exchange is identified by a code the peers share; the
+// offerer posts an offer, the answerer posts an answer, and each waits for
+// the other's. Only the descriptions pass through the store, never file data.
+type SignalStore struct {
+	mutex sync.Mutex
+	slots map[string]*signalSlot
+	// added is closed and replaced whenever a slot is created, waking the
+	// peers waiting for a code nobody has posted to yet
+	added chan struct{}
+}
+
+// NewSignalStore creates an empty signaling mailbox
+func NewSignalStore() *SignalStore {
+	return &SignalStore{
+		slots: make(map[string]*signalSlot),
+		added: make(chan struct{}),
+	}
+}
+
+// dropExpired removes the slots of exchanges older than signalTTL. The
+// caller holds the mutex.
+func (s *SignalStore) dropExpired() {
+	cutoff := time.Now().Add(-signalTTL)
+	for key, slot := range s.slots {
+		if slot.created.Before(cutoff) {
+			delete(s.slots, key)
+		}
+	}
+}
+
+// slot returns the slot for a code, creating it unless the store is full
+func (s *SignalStore) slot(code string) (*signalSlot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.dropExpired()
+	if slot, ok := s.slots[code]; ok {
+		return slot, nil
+	}
+	if len(s.slots) >= MaxSignalSlots {
+		return nil, ErrSignalStoreFull
+	}
+
+	slot := &signalSlot{
+		values: make(map[string]string),
+		ready: map[string]chan struct{}{
+			"offer":  make(chan struct{}),
+			"answer": make(chan struct{}),
+		},
+		created: time.Now(),
+	}
+	s.slots[code] = slot
+	close(s.added)
+	s.added = make(chan struct{})
+	return slot, nil
+}
+
+// lookup returns the slot for a code or, if there is none, a channel that
+// is closed when a slot is next created. Only posts create slots, so
+// waiting for a code costs the store nothing.
+func (s *SignalStore) lookup(code string) (*signalSlot, <-chan struct{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.dropExpired()
+	if slot, ok := s.slots[code]; ok {
+		return slot, nil
+	}
+	return nil, s.added
+}
+
+// Put stores the offer or answer for a code. Each can only be set once, so
+// a third party cannot replace a description that was already posted.
+func (s *SignalStore) Put(code, kind, value string) error {
+	if err := validateSignal(code, kind); err != nil {
+		return err
+	}
+	slot, err := s.slot(code)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, exists := slot.values[kind]; exists {
+		return fmt.Errorf("an %s was already posted for this code", kind)
+	}
+	slot.values[kind] = value
+	close(slot.ready[kind])
+	return nil
+}
+
+// Wait returns the offer or answer for a code once it has been posted
+func (s *SignalStore) Wait(ctx context.Context, code, kind string) (string, error) {
+	if err := validateSignal(code, kind); err != nil {
+		return "", err
+	}
+	slot, added := s.lookup(code)
+	for slot == nil {
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		slot, added = s.lookup(code)
+	}
+
+	select {
+	case <-slot.ready[kind]:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slot.values[kind], nil
+}
+
+// ServeHTTP handles /api/v1/connect/signal?code=<code>&kind=offer|answer.
+// POST stores a description, or answers 503 Service Unavailable when the
+// store is full; GET waits for one and answers 204 No Content if it has not
+// arrived yet, so the client polls again.
+func (s *SignalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("code")
+	kind := r.URL.Query().Get("kind")
+	if err := validateSignal(code, kind); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSignalSize+1))
+		if err != nil || len(body) == 0 || len(body) > maxSignalSize {
+			http.Error(w, "Invalid session description", http.StatusBadRequest)
+			return
+		}
+		if err := s.Put(code, kind, string(body)); errors.Is(err, ErrSignalStoreFull) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		ctx, cancel := context.WithTimeout(r.Context(), signalWait)
+		defer cancel()
+		value, err := s.Wait(ctx, code, kind)
+		if err != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, value)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// validateSignal checks the code and kind of a signaling request
+func validateSignal(code, kind string) error {
+	if code == "" || len(code) > 64 {
+		return fmt.Errorf("a code of up to 64 characters is required")
+	}
+	if kind != "offer" && kind != "answer" {
+		return fmt.Errorf("kind must be offer or answer")
+	}
+	return nil
+}
+
+// signalClient talks to a SignalStore served by a lumo server
+type signalClient struct {
+	baseURL    string
+	code       string
+	httpClient *http.Client
+}
+
+// newSignalClient creates a client for the signaling endpoint at baseURL
+func newSignalClient(baseURL, code string) *signalClient {
+	return &signalClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		code:       code,
+		httpClient: &http.Client{Timeout: signalWait + 10*time.Second},
+	}
+}
+
+// endpoint returns the signaling URL for a kind of description
+func (c *signalClient) endpoint(kind string) string {
+	return fmt.Sprintf("%s/api/v1/connect/signal?code=%s&kind=%s", c.baseURL, url.QueryEscape(c.code), kind)
+}
+
+// post publishes a session description
+func (c *signalClient) post(ctx context.Context, kind string, value []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(kind), bytes.NewReader(value))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the signaling server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("signaling server returned error: %s - %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// wait polls until the other peer's session description is available
+func (c *signalClient) wait(ctx context.Context, kind string) ([]byte, error) {
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(kind), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach the signaling server: %w", err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxSignalSize))
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return body, nil
+		case http.StatusNoContent:
+			// Nothing yet; poll again
+		default:
+			return nil, fmt.Errorf("signaling server returned error: %s - %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package connect
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// stagingSegmentSize is the plaintext size of each encrypted segment of
+	// files received over the WebSocket. Chunked uploads use their chunk size.
+	stagingSegmentSize = 1024 * 1024
+
+	// sealOverhead is the nonce and authentication tag added to each segment
+	sealOverhead = 12 + 16
+
+	// stagingDirPattern names the directories that hold staged files
+	stagingDirPattern = "lumo-connect-staging-*"
+)
+
+// StagedFile is a received file waiting for the user to accept or reject it
+type StagedFile struct {
+	ID       int
+	Filename string
+	Size     int64
+	Received time.Time
+
+	path        string
+	segmentSize int64
+}
+
+// Staging holds received files encrypted at rest until they are approved.
+// Files are sealed with AES-256-GCM under a key that only exists in memory,
+// so anything left behind when the session ends cannot be read.
+type Staging struct {
+	aead   cipher.AEAD
+	dir    string
+	mutex  sync.Mutex
+	files  map[int]*StagedFile
+	nextID int
+}
+
+// NewStaging creates a staging area with a fresh session key
+func NewStaging() (*Staging, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate session key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", stagingDirPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	return &Staging{
+		aead:   aead,
+		dir:    dir,
+		files:  make(map[int]*StagedFile),
+		nextID: 1,
+	}, nil
+}
+
+// Stage encrypts a received file into the staging area
+func (s *Staging) Stage(filename string, content []byte) (*StagedFile, error) {
+	return s.StageReader(filename, bytes.NewReader(content))
+}
+
+// StageReader encrypts a received file read from r into the staging area
+func (s *Staging) StageReader(filename string, r io.Reader) (*StagedFile, error) {
+	file, err := os.CreateTemp(s.dir, "staged-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staged file: %w", err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, stagingSegmentSize)
+	var size int64
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(r, buffer)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			os.Remove(file.Name())
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		// An empty file is still stored as one empty segment
+		if n == 0 && i > 0 {
+			break
+		}
+		if _, err := file.Write(s.seal(i, buffer[:n])); err != nil {
+			os.Remove(file.Name())
+			return nil, fmt.Errorf("failed to write staged file: %w", err)
+		}
+		size += int64(n)
+		if n < len(buffer) {
+			break
+		}
+	}
+
+	return s.add(filename, file.Name(), size, stagingSegmentSize), nil
+}
+
+// adopt takes over a file that was already encrypted segment by segment,
+// such as a chunked upload
+func (s *Staging) adopt(filename, path string, size, segmentSize int64) (*StagedFile, error) {
+	stagedPath := filepath.Join(s.dir, filepath.Base(path))
+	if err := os.Rename(path, stagedPath); err != nil {
+		if err := copyFile(path, stagedPath); err != nil {
+			return nil, fmt.Errorf("failed to stage file: %w", err)
+		}
+		os.Remove(path)
+	}
+	return s.add(filename, stagedPath, size, segmentSize), nil
+}
+
+// add records a staged file and gives it the next ID
+func (s *Staging) add(filename, path string, size, segmentSize int64) *StagedFile {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	staged := &StagedFile{
+		ID:          s.nextID,
+		Filename:    safeFilename(filename),
+		Size:        size,
+		Received:    time.Now(),
+		path:        path,
+		segmentSize: segmentSize,
+	}
+	s.files[staged.ID] = staged
+	s.nextID++
+
+	fmt.Printf("\033[1;36m🔒 Staged %s (%s) as #%d. Type 'accept %d' to save it or 'reject %d' to delete it\033[0m\n",
+		staged.Filename, formatFileSize(size), staged.ID, staged.ID, staged.ID)
+	return staged
+}
+
+// List returns the staged files in the order they arrived
+func (s *Staging) List() []*StagedFile {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	files := make([]*StagedFile, 0, len(s.files))
+	for _, staged := range s.files {
+		files = append(files, staged)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ID < files[j].ID })
+	return files
+}
+
+// Approve decrypts a staged file into downloadDir and returns its path
+func (s *Staging) Approve(id int, downloadDir string) (string, error) {
+	staged, err := s.take(id)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(staged.path)
+
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	// Decrypt next to the destination, so a tampered file never appears under its real name
+	out, err := os.CreateTemp(downloadDir, ".lumo-staged-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	err = s.decrypt(staged, func(plain []byte) error {
+		_, err := out.Write(plain)
+		return err
+	})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+
+	filePath, err := placeReceived(out.Name(), downloadDir, staged.Filename)
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+
+	notifyTransfer("received", filePath, staged.Size)
+	return filePath, nil
+}
+
+// Reject deletes a staged file without decrypting it
+func (s *Staging) Reject(id int) error {
+	staged, err := s.take(id)
+	if err != nil {
+		return err
+	}
+	return os.Remove(staged.path)
+}
+
+// Close deletes the staging area. Files that were not approved are lost.
+func (s *Staging) Close() error {
+	s.mutex.Lock()
+	s.files = make(map[int]*StagedFile)
+	s.mutex.Unlock()
+	return os.RemoveAll(s.dir)
+}
+
+// take removes a staged file from the list so only one caller handles it
+func (s *Staging) take(id int) (*StagedFile, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	staged, ok := s.files[id]
+	if !ok {
+		return nil, fmt.Errorf("no staged file with ID %d", id)
+	}
+	delete(s.files, id)
+	return staged, nil
+}
+
+// hash returns the hex-encoded SHA-256 of a sealed file's plaintext
+func (s *Staging) hash(path string, size, segmentSize int64) (string, error) {
+	hash := sha256.New()
+	err := s.decrypt(&StagedFile{Size: size, path: path, segmentSize: segmentSize}, func(plain []byte) error {
+		hash.Write(plain)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// decrypt opens each segment of a staged file in order
+func (s *Staging) decrypt(staged *StagedFile, write func([]byte) error) error {
+	file, err := os.Open(staged.path)
+	if err != nil {
+		return fmt.Errorf("failed to open staged file: %w", err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, staged.segmentSize+sealOverhead)
+	for i := 0; int64(i)*staged.segmentSize < staged.Size || i == 0; i++ {
+		start := int64(i) * staged.segmentSize
+		length := min(staged.segmentSize, staged.Size-start)
+		sealed := buffer[:length+sealOverhead]
+		if _, err := file.ReadAt(sealed, int64(i)*(staged.segmentSize+sealOverhead)); err != nil {
+			return fmt.Errorf("failed to read staged file: %w", err)
+		}
+
+		plain, err := s.open(i, sealed)
+		if err != nil {
+			return fmt.Errorf("staged file is corrupt or was modified: %w", err)
+		}
+		if err := write(plain); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	return nil
+}
+
+// seal encrypts one segment. The segment index is authenticated so
+// segments cannot be reordered.
+func (s *Staging) seal(index int, plain []byte) []byte {
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(plain)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(fmt.Sprintf("failed to generate nonce: %v", err))
+	}
+	return s.aead.Seal(nonce, nonce, plain, segmentAAD(index))
+}
+
+// open decrypts one segment produced by seal
+func (s *Staging) open(index int, sealed []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	return s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], segmentAAD(index))
+}
+
+// segmentAAD returns the additional authenticated data for a segment
+func segmentAAD(index int) []byte {
+	aad := make([]byte, 8)
+	binary.BigEndian.PutUint64(aad, uint64(index))
+	return aad
+}
+
+// sealedSize returns the size of a file once each of its segments is sealed
+func sealedSize(size, segmentSize int64) int64 {
+	segments := max((size+segmentSize-1)/segmentSize, 1)
+	return size + segments*sealOverhead
+}
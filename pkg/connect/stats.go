@@ -0,0 +1,558 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/usage"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+const (
+	// rateSampleInterval is how often the transfer rate is sampled for the graph
+	rateSampleInterval = 250 * time.Millisecond
+
+	// rateGraphWidth is how many samples the live graph shows
+	rateGraphWidth = 24
+
+	// maxTransferHistory is how many transfers the history file keeps
+	maxTransferHistory = 500
+)
+
+// rateGraphLevels draws a sample relative to the fastest one on screen
+var rateGraphLevels = []rune("▁▂▃▄▅▆▇█")
+
+// TransferStats describes one finished or failed transfer
+type TransferStats struct {
+	Time      time.Time `json:"time"`
+	Peer      string    `json:"peer,omitempty"`
+	Direction string    `json:"direction"` // "sent" or "received"
+	Transport string    `json:"transport"` // "websocket", "chunked" or "webrtc"
+	Filename  string    `json:"filename"`
+	Bytes     int64     `json:"bytes"`
+	// Duration is zero when the transfer arrived as a single message and could not be timed
+	Duration    time.Duration `json:"duration"`
+	AverageRate float64       `json:"average_rate"` // Bytes per second
+	PeakRate    float64       `json:"peak_rate"`    // Bytes per second
+	Retries     int           `json:"retries,omitempty"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// String describes the transfer for the history listing
+func (t TransferStats) String() string {
+	status, preposition, peer := "✅", "from", t.Peer
+	if !t.Success {
+		status = "❌"
+	}
+	if t.Direction == "sent" {
+		preposition = "to"
+	}
+	if peer == "" {
+		peer = "an unknown peer"
+	}
+	line := fmt.Sprintf("%s %s  %s %s (%s) %s %s over %s", status, utils.FormatWhen(t.Time),
+		t.Direction, t.Filename, formatFileSize(t.Bytes), preposition, peer, t.Transport)
+
+	var details []string
+	if t.Duration > 0 {
+		details = append(details, fmt.Sprintf("%s, avg %s, peak %s",
+			formatElapsed(t.Duration), formatRate(t.AverageRate), formatRate(t.PeakRate)))
+	}
+	if t.Retries > 0 {
+		details = append(details, fmt.Sprintf("%d retries", t.Retries))
+	}
+	if t.Error != "" {
+		details = append(details, t.Error)
+	}
+	if len(details) > 0 {
+		line += "\n   " + strings.Join(details, "; ")
+	}
+	return line
+}
+
+// PeerStats totals the transfers with one peer during this session
+type PeerStats struct {
+	Peer          string
+	FilesSent     int
+	FilesReceived int
+	BytesSent     int64
+	BytesReceived int64
+	Failed        int
+	AverageRate   float64 // Over the timed transfers, in bytes per second
+	PeakRate      float64
+}
+
+// rateMeter measures the throughput of a transfer and draws it on the
+// progress line with a graph of the recent transfer rate
+type rateMeter struct {
+	direction   string // "sent" or "received"
+	filename    string
+	total       int64
+	done        int64
+	start       time.Time
+	sampleStart time.Time
+	sampleBytes int64
+	samples     []float64
+	peak        float64
+	retries     int
+}
+
+// newRateMeter starts measuring a transfer of total bytes
+func newRateMeter(direction, filename string, total int64) *rateMeter {
+	now := time.Now()
+	meter := &rateMeter{direction: direction, filename: filename, total: total, start: now, sampleStart: now}
+
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.meters = append(progress.meters, meter)
+	progress.draw()
+	return meter
+}
+
+// add records n more bytes, redrawing the line whenever a sample completes
+func (r *rateMeter) add(n int64) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	r.done += n
+	r.sampleBytes += n
+
+	elapsed := time.Since(r.sampleStart)
+	if elapsed < rateSampleInterval && r.done < r.total {
+		return
+	}
+	if elapsed >= rateSampleInterval {
+		rate := float64(r.sampleBytes) / elapsed.Seconds()
+		r.samples = append(r.samples, rate)
+		r.peak = max(r.peak, rate)
+		r.sampleStart = time.Now()
+		r.sampleBytes = 0
+	}
+	progress.draw()
+}
+
+// reset sets how much of the transfer is done, such as after resuming it
+func (r *rateMeter) reset(done int64) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	r.done = done
+}
+
+// retry records that part of the transfer had to be sent again
+func (r *rateMeter) retry() {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	r.retries++
+}
+
+// percent returns how much of the transfer is done. The caller must hold progress.mutex.
+func (r *rateMeter) percent() int {
+	if r.total <= 0 {
+		return 100
+	}
+	return int(min(r.done*100/r.total, 100))
+}
+
+// currentRate returns the rate of the latest sample. The caller must hold progress.mutex.
+func (r *rateMeter) currentRate() float64 {
+	if len(r.samples) == 0 {
+		return 0
+	}
+	return r.samples[len(r.samples)-1]
+}
+
+// finish removes the transfer from the progress line, prints a summary of
+// it and records it in the session statistics and the transfer history
+func (r *rateMeter) finish(peer, transport string, err error) *TransferStats {
+	progress.mutex.Lock()
+	for i, meter := range progress.meters {
+		if meter == r {
+			progress.meters = append(progress.meters[:i], progress.meters[i+1:]...)
+			break
+		}
+	}
+	if err == nil {
+		r.done = max(r.done, r.total)
+	}
+	stats := &TransferStats{
+		Time:      r.start,
+		Peer:      peer,
+		Direction: r.direction,
+		Transport: transport,
+		Filename:  r.filename,
+		Bytes:     r.done,
+		Duration:  time.Since(r.start),
+		Retries:   r.retries,
+		Success:   err == nil,
+	}
+	peak := r.peak
+	progress.draw()
+	progress.mutex.Unlock()
+
+	if err != nil {
+		stats.Error = err.Error()
+	}
+	if seconds := stats.Duration.Seconds(); seconds > 0 {
+		stats.AverageRate = float64(stats.Bytes) / seconds
+	}
+	// Transfers shorter than one sample only have their average
+	stats.PeakRate = max(peak, stats.AverageRate)
+
+	if err == nil {
+		printTransferSummary(stats)
+	}
+	recordTransfer(stats)
+	return stats
+}
+
+// progress is the live progress line shared by all transfers
+var progress terminalProgress
+
+// terminalProgress keeps one progress line for the running transfers at the
+// bottom of the output. Messages printed through printf appear above it, so
+// concurrent transfers do not write over each other.
+type terminalProgress struct {
+	mutex  sync.Mutex
+	meters []*rateMeter
+	queue  *sendQueue
+	drawn  bool
+}
+
+// printf prints a message above the progress line
+func (t *terminalProgress) printf(format string, args ...any) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.clear()
+	fmt.Printf(format, args...)
+	t.draw()
+}
+
+// clear erases the progress line. The caller must hold the mutex.
+func (t *terminalProgress) clear() {
+	if t.drawn {
+		fmt.Print("\r\033[K")
+		t.drawn = false
+	}
+}
+
+// draw redraws the progress line. One transfer gets a bar and a rate graph;
+// several share the line with their own percentages. The caller must hold
+// the mutex.
+func (t *terminalProgress) draw() {
+	var line string
+	switch len(t.meters) {
+	case 0:
+	case 1:
+		meter := t.meters[0]
+		percent := meter.percent()
+		bars := percent / 5
+		line = fmt.Sprintf("[%s%s] %3d%% %s", strings.Repeat("=", bars), strings.Repeat(" ", 20-bars), percent, rateGraph(meter.samples))
+		if rate := meter.currentRate(); rate > 0 {
+			line += " " + formatRate(rate)
+		}
+	default:
+		var parts []string
+		var rate float64
+		for _, meter := range t.meters {
+			parts = append(parts, fmt.Sprintf("%s %d%%", truncateName(meter.filename, 20), meter.percent()))
+			rate += meter.currentRate()
+		}
+		line = strings.Join(parts, " · ")
+		if rate > 0 {
+			line += " · " + formatRate(rate)
+		}
+	}
+	if t.queue != nil {
+		if summary := t.queue.summary(t.meters); summary != "" {
+			line = strings.TrimSpace(line + "  " + summary)
+		}
+	}
+
+	t.clear()
+	if line != "" {
+		fmt.Printf("\033[1;32m%s\033[0m", line)
+		t.drawn = true
+	}
+}
+
+// truncateName shortens a file name for the shared progress line
+func truncateName(name string, width int) string {
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// rateGraph draws the most recent rate samples as a sparkline
+func rateGraph(samples []float64) string {
+	if len(samples) > rateGraphWidth {
+		samples = samples[len(samples)-rateGraphWidth:]
+	}
+	highest := 0.0
+	for _, rate := range samples {
+		highest = max(highest, rate)
+	}
+
+	var graph strings.Builder
+	for i := len(samples); i < rateGraphWidth; i++ {
+		graph.WriteRune(' ')
+	}
+	for _, rate := range samples {
+		level := 0
+		if highest > 0 {
+			level = int(rate / highest * float64(len(rateGraphLevels)-1))
+		}
+		graph.WriteRune(rateGraphLevels[level])
+	}
+	return graph.String()
+}
+
+// formatElapsed rounds how long a transfer took for display
+func formatElapsed(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// formatRate formats a transfer rate in bytes per second
+func formatRate(rate float64) string {
+	return formatFileSize(int64(rate)) + "/s"
+}
+
+// printTransferSummary prints the statistics of a finished transfer
+func printTransferSummary(stats *TransferStats) {
+	summary := fmt.Sprintf("%s %s", stats.Direction, formatFileSize(stats.Bytes))
+	if stats.Duration > 0 {
+		summary += fmt.Sprintf(" in %s · avg %s · peak %s",
+			formatElapsed(stats.Duration), formatRate(stats.AverageRate), formatRate(stats.PeakRate))
+	}
+	if stats.Retries == 1 {
+		summary += " · 1 retry"
+	} else if stats.Retries > 1 {
+		summary += fmt.Sprintf(" · %d retries", stats.Retries)
+	}
+	progress.printf("\033[1;34m📊 %s\033[0m\n", summary)
+}
+
+// sessionTransfers holds the transfers recorded by this process
+var (
+	sessionTransfers []TransferStats
+	sessionMutex     sync.Mutex
+)
+
+// recordTransfer adds a transfer to the session statistics and the history file
+func recordTransfer(stats *TransferStats) {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	sessionTransfers = append(sessionTransfers, *stats)
+
+	// The traffic counts toward 'lumo usage network' and the monthly cap
+	sent, received := stats.Bytes, int64(0)
+	if stats.Direction == "received" {
+		sent, received = 0, stats.Bytes
+	}
+	if err := usage.RecordNetwork(usage.NetworkConnect, sent, received); err != nil {
+		log.Printf("Warning: Failed to record network usage: %v", err)
+	}
+
+	path, err := transferHistoryPath()
+	if err != nil {
+		log.Printf("Warning: Failed to record transfer: %v", err)
+		return
+	}
+	history, _ := readTransferHistory(path)
+	history = append(history, *stats)
+	if len(history) > maxTransferHistory {
+		history = history[len(history)-maxTransferHistory:]
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		log.Printf("Warning: Failed to record transfer: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("Warning: Failed to record transfer: %v", err)
+		return
+	}
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		log.Printf("Warning: Failed to record transfer: %v", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		log.Printf("Warning: Failed to record transfer: %v", err)
+	}
+}
+
+// SessionStats totals the transfers of this session by peer
+func SessionStats() []PeerStats {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	byPeer := make(map[string]*PeerStats)
+	timedBytes := make(map[string]int64)
+	timedDuration := make(map[string]time.Duration)
+	for _, transfer := range sessionTransfers {
+		peer := transfer.Peer
+		if peer == "" {
+			peer = "unknown"
+		}
+		stats, ok := byPeer[peer]
+		if !ok {
+			stats = &PeerStats{Peer: peer}
+			byPeer[peer] = stats
+		}
+
+		switch {
+		case !transfer.Success:
+			stats.Failed++
+			continue
+		case transfer.Direction == "sent":
+			stats.FilesSent++
+			stats.BytesSent += transfer.Bytes
+		default:
+			stats.FilesReceived++
+			stats.BytesReceived += transfer.Bytes
+		}
+		if transfer.Duration > 0 {
+			timedBytes[peer] += transfer.Bytes
+			timedDuration[peer] += transfer.Duration
+		}
+		stats.PeakRate = max(stats.PeakRate, transfer.PeakRate)
+	}
+
+	peers := make([]PeerStats, 0, len(byPeer))
+	for peer, stats := range byPeer {
+		if timedDuration[peer] > 0 {
+			stats.AverageRate = float64(timedBytes[peer]) / timedDuration[peer].Seconds()
+		}
+		peers = append(peers, *stats)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Peer < peers[j].Peer })
+	return peers
+}
+
+// printSessionStats prints the transfer totals of this session for each peer
+func printSessionStats() {
+	peers := SessionStats()
+	if len(peers) == 0 {
+		fmt.Printf("\033[1;33mℹ️ No transfers yet in this session\033[0m\n")
+		return
+	}
+	for _, stats := range peers {
+		line := fmt.Sprintf("  %s: sent %d (%s), received %d (%s)", stats.Peer,
+			stats.FilesSent, formatFileSize(stats.BytesSent), stats.FilesReceived, formatFileSize(stats.BytesReceived))
+		if stats.AverageRate > 0 {
+			line += fmt.Sprintf(", avg %s, peak %s", formatRate(stats.AverageRate), formatRate(stats.PeakRate))
+		}
+		if stats.Failed > 0 {
+			line += fmt.Sprintf(", %d failed", stats.Failed)
+		}
+		fmt.Printf("\033[1;36m%s\033[0m\n", line)
+	}
+}
+
+// transferHistoryPath returns the file transfers are recorded in
+func transferHistoryPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "transfer_history.json"), nil
+}
+
+// transfersReadPath returns the file recording when the transfer history
+// was last shown
+func transfersReadPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "transfers_read"), nil
+}
+
+// UnreadTransfers counts the files received since the transfer history was
+// last shown
+func UnreadTransfers() (int, error) {
+	history, err := TransferHistory(0)
+	if err != nil {
+		return 0, err
+	}
+	var readAt time.Time
+	if path, err := transfersReadPath(); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			readAt = info.ModTime()
+		}
+	}
+
+	unread := 0
+	for _, transfer := range history {
+		if transfer.Direction == "received" && transfer.Success && transfer.Time.After(readAt) {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// MarkTransfersRead records that the transfer history was shown, so the
+// files received so far are no longer unread
+func MarkTransfersRead() error {
+	path, err := transfersReadPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil {
+		return nil
+	}
+	return os.WriteFile(path, nil, 0644)
+}
+
+// TransferHistory returns up to limit of the most recent transfers, oldest
+// first. A limit of zero or less returns the whole history.
+func TransferHistory(limit int) ([]TransferStats, error) {
+	path, err := transferHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	history, err := readTransferHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history, nil
+}
+
+// readTransferHistory reads the history file; a missing file is an empty history
+func readTransferHistory(path string) ([]TransferStats, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transfer history: %w", err)
+	}
+
+	var history []TransferStats
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse transfer history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,593 @@
+package connect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+)
+
+const (
+	// DefaultSignalURL is the lumo server used for signaling when none is given
+	DefaultSignalURL = "http://localhost:7531"
+
+	// DefaultSTUNServer discovers each peer's public address for hole punching
+	DefaultSTUNServer = "stun:stun.l.google.com:19302"
+
+	// dataChannelLabel names the data channel files are sent over
+	dataChannelLabel = "lumo-connect"
+
+	// dataChannelChunkSize is the size of each binary message; larger
+	// messages are not reliably delivered by every WebRTC implementation
+	dataChannelChunkSize = 16 * 1024
+
+	// maxBufferedAmount is how much unsent data may queue before the sender waits
+	maxBufferedAmount = 1024 * 1024
+
+	// webrtcOpenTimeout is how long to wait for the direct connection once
+	// descriptions have been exchanged
+	webrtcOpenTimeout = 30 * time.Second
+
+	// webrtcAckTimeout is how long a sender waits for the peer to confirm a file
+	webrtcAckTimeout = 30 * time.Second
+)
+
+// WebRTCOptions configures a WebRTC connect session
+type WebRTCOptions struct {
+	// SignalURL is the lumo server both peers use to exchange session descriptions
+	SignalURL string
+	// Code identifies the exchange on the signaling server; both peers use the same code
+	Code string
+	// STUNServers are used to find public addresses. Nil uses
+	// DefaultSTUNServer; an empty slice uses none, for peers on one network.
+	STUNServers []string
+}
+
+// WebRTCPeer is a direct connection to another lumo instance over a WebRTC
+// data channel. Files are sent as a "file-start" message, binary chunks and
+// a "file-end" message; the receiver answers with an "ack".
+type WebRTCPeer struct {
+	pc           *webrtc.PeerConnection
+	channel      *webrtc.DataChannel
+	code         string
+	downloadPath string
+	staging      *Staging
+
+	opened    chan struct{}
+	closed    chan struct{}
+	openOnce  sync.Once
+	closeOnce sync.Once
+
+	sendMutex sync.Mutex
+	lowBuffer chan struct{}
+	acks      chan string
+
+	// incomingMutex guards the file being received
+	incomingMutex sync.Mutex
+	incoming      *os.File
+	incomingName  string
+	incomingSize  int64
+	incomingGot   int64
+	incomingMeter *rateMeter
+}
+
+// newWebRTCPeer creates a peer connection configured for opts
+func newWebRTCPeer(opts WebRTCOptions, downloadPath string, staging *Staging) (*WebRTCPeer, error) {
+	stunServers := opts.STUNServers
+	if stunServers == nil {
+		stunServers = []string{DefaultSTUNServer}
+	}
+	config := webrtc.Configuration{}
+	if len(stunServers) > 0 {
+		config.ICEServers = []webrtc.ICEServer{{URLs: stunServers}}
+	}
+
+	pc, err := webrtc.NewPeerConnection(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create peer connection: %w", err)
+	}
+
+	peer := &WebRTCPeer{
+		pc:           pc,
+		code:         opts.Code,
+		downloadPath: downloadPath,
+		staging:      staging,
+		opened:       make(chan struct{}),
+		closed:       make(chan struct{}),
+		lowBuffer:    make(chan struct{}, 1),
+		acks:         make(chan string, 16),
+	}
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		if state == webrtc.PeerConnectionStateFailed || state == webrtc.PeerConnectionStateClosed {
+			peer.markClosed()
+		}
+	})
+	return peer, nil
+}
+
+// DialWebRTC offers a connection under opts.Code and waits for the peer
+// that is accepting with the same code to answer
+func DialWebRTC(ctx context.Context, opts WebRTCOptions, downloadPath string, staging *Staging) (*WebRTCPeer, error) {
+	peer, err := newWebRTCPeer(opts, downloadPath, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := peer.pc.CreateDataChannel(dataChannelLabel, nil)
+	if err != nil {
+		peer.Close()
+		return nil, fmt.Errorf("failed to create data channel: %w", err)
+	}
+	peer.attach(channel)
+
+	offer, err := peer.pc.CreateOffer(nil)
+	if err != nil {
+		peer.Close()
+		return nil, fmt.Errorf("failed to create offer: %w", err)
+	}
+
+	signal := newSignalClient(opts.SignalURL, opts.Code)
+	if err := peer.publish(ctx, signal, "offer", offer); err != nil {
+		peer.Close()
+		return nil, err
+	}
+
+	answer, err := signal.wait(ctx, "answer")
+	if err != nil {
+		peer.Close()
+		return nil, err
+	}
+	if err := peer.setRemote(answer); err != nil {
+		peer.Close()
+		return nil, err
+	}
+
+	if err := peer.waitOpen(ctx); err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
+
+// AcceptWebRTC waits for an offer under opts.Code and answers it
+func AcceptWebRTC(ctx context.Context, opts WebRTCOptions, downloadPath string, staging *Staging) (*WebRTCPeer, error) {
+	peer, err := newWebRTCPeer(opts, downloadPath, staging)
+	if err != nil {
+		return nil, err
+	}
+	peer.pc.OnDataChannel(func(channel *webrtc.DataChannel) {
+		if channel.Label() == dataChannelLabel {
+			peer.attach(channel)
+		}
+	})
+
+	signal := newSignalClient(opts.SignalURL, opts.Code)
+	offer, err := signal.wait(ctx, "offer")
+	if err != nil {
+		peer.Close()
+		return nil, err
+	}
+	if err := peer.setRemote(offer); err != nil {
+		peer.Close()
+		return nil, err
+	}
+
+	answer, err := peer.pc.CreateAnswer(nil)
+	if err != nil {
+		peer.Close()
+		return nil, fmt.Errorf("failed to create answer: %w", err)
+	}
+	if err := peer.publish(ctx, signal, "answer", answer); err != nil {
+		peer.Close()
+		return nil, err
+	}
+
+	if err := peer.waitOpen(ctx); err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
+
+// publish sets the local description and posts it once all ICE candidates
+// are gathered, so a single exchange is enough to punch through
+func (p *WebRTCPeer) publish(ctx context.Context, signal *signalClient, kind string, description webrtc.SessionDescription) error {
+	gathered := webrtc.GatheringCompletePromise(p.pc)
+	if err := p.pc.SetLocalDescription(description); err != nil {
+		return fmt.Errorf("failed to set local description: %w", err)
+	}
+
+	select {
+	case <-gathered:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	data, err := json.Marshal(p.pc.LocalDescription())
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+	return signal.post(ctx, kind, data)
+}
+
+// setRemote applies the other peer's session description
+func (p *WebRTCPeer) setRemote(data []byte) error {
+	var description webrtc.SessionDescription
+	if err := json.Unmarshal(data, &description); err != nil {
+		return fmt.Errorf("invalid session description from peer: %w", err)
+	}
+	if err := p.pc.SetRemoteDescription(description); err != nil {
+		return fmt.Errorf("failed to set remote description: %w", err)
+	}
+	return nil
+}
+
+// waitOpen waits until the data channel is usable
+func (p *WebRTCPeer) waitOpen(ctx context.Context) error {
+	timer := time.NewTimer(webrtcOpenTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.opened:
+		return nil
+	case <-p.closed:
+		p.Close()
+		return fmt.Errorf("could not open a direct connection; one of the peers may be behind a NAT that does not allow hole punching")
+	case <-timer.C:
+		p.Close()
+		return fmt.Errorf("timed out opening a direct connection; one of the peers may be behind a NAT that does not allow hole punching")
+	case <-ctx.Done():
+		p.Close()
+		return ctx.Err()
+	}
+}
+
+// attach wires up the data channel's callbacks
+func (p *WebRTCPeer) attach(channel *webrtc.DataChannel) {
+	p.channel = channel
+	channel.SetBufferedAmountLowThreshold(maxBufferedAmount / 2)
+	channel.OnBufferedAmountLow(func() {
+		select {
+		case p.lowBuffer <- struct{}{}:
+		default:
+		}
+	})
+	channel.OnOpen(func() {
+		p.openOnce.Do(func() { close(p.opened) })
+	})
+	channel.OnClose(p.markClosed)
+	channel.OnMessage(p.handleMessage)
+}
+
+// Done is closed when the connection ends
+func (p *WebRTCPeer) Done() <-chan struct{} {
+	return p.closed
+}
+
+// Close ends the connection and discards any partly received file
+func (p *WebRTCPeer) Close() error {
+	p.markClosed()
+	err := p.pc.Close()
+
+	p.incomingMutex.Lock()
+	p.discardIncoming()
+	p.incomingMutex.Unlock()
+	return err
+}
+
+// markClosed records that the connection has ended
+func (p *WebRTCPeer) markClosed() {
+	p.closeOnce.Do(func() { close(p.closed) })
+}
+
+// SendFile sends a file to the peer and waits for it to confirm receipt
+func (p *WebRTCPeer) SendFile(filePath string) error {
+	p.sendMutex.Lock()
+	defer p.sendMutex.Unlock()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get file info: %w", err)
+	}
+	if !fileInfo.Mode().IsRegular() {
+		return fmt.Errorf("not a regular file")
+	}
+
+	filename := filepath.Base(filePath)
+	progress.printf("\033[1;32m📤 Sending file: %s (%s)...\033[0m\n", filename, formatFileSize(fileInfo.Size()))
+
+	if err := p.sendControl(FileTransferMessage{Type: "file-start", Filename: filename, Size: fileInfo.Size()}); err != nil {
+		return err
+	}
+
+	meter := newRateMeter("sent", filename, fileInfo.Size())
+	sent, err := p.sendData(file, filename, meter)
+	meter.finish(p.peer(), "webrtc", err)
+	if err != nil {
+		return err
+	}
+	progress.printf("\033[1;32m✅ File %s received by peer\033[0m\n", filename)
+	notifyTransfer("sent", filePath, sent)
+	return nil
+}
+
+// sendData streams an open file to the peer and waits for the peer to
+// confirm it, returning how many bytes were sent
+func (p *WebRTCPeer) sendData(file *os.File, filename string, meter *rateMeter) (int64, error) {
+	buffer := make([]byte, dataChannelChunkSize)
+	var sent int64
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			if err := p.waitForBuffer(); err != nil {
+				return sent, err
+			}
+			if err := p.channel.Send(buffer[:n]); err != nil {
+				return sent, fmt.Errorf("failed to send file: %w", err)
+			}
+			sent += int64(n)
+			meter.add(int64(n))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return sent, fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	if err := p.sendControl(FileTransferMessage{Type: "file-end", Filename: filename, Size: sent}); err != nil {
+		return sent, err
+	}
+
+	// Wait for the peer to confirm, so the caller knows the file arrived
+	timer := time.NewTimer(webrtcAckTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case acked := <-p.acks:
+			if acked != filename {
+				continue
+			}
+			return sent, nil
+		case <-p.closed:
+			return sent, fmt.Errorf("connection closed before the peer confirmed %s", filename)
+		case <-timer.C:
+			return sent, fmt.Errorf("the peer did not confirm %s", filename)
+		}
+	}
+}
+
+// peer names the remote side for transfer statistics
+func (p *WebRTCPeer) peer() string {
+	return "webrtc:" + p.code
+}
+
+// waitForBuffer blocks while too much data is queued on the data channel
+func (p *WebRTCPeer) waitForBuffer() error {
+	for p.channel.BufferedAmount() > maxBufferedAmount {
+		select {
+		case <-p.lowBuffer:
+		case <-p.closed:
+			return fmt.Errorf("connection closed")
+		case <-time.After(time.Second):
+			// Recheck in case the notification raced with the check above
+		}
+	}
+	return nil
+}
+
+// sendControl sends a control message as text
+func (p *WebRTCPeer) sendControl(msg FileTransferMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := p.channel.SendText(string(data)); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// handleMessage processes a message from the peer. The data channel is
+// ordered, so binary chunks always belong to the latest "file-start".
+func (p *WebRTCPeer) handleMessage(message webrtc.DataChannelMessage) {
+	p.incomingMutex.Lock()
+	defer p.incomingMutex.Unlock()
+
+	if !message.IsString {
+		if p.incoming == nil {
+			return
+		}
+		if _, err := p.incoming.Write(message.Data); err != nil {
+			log.Printf("Error writing received data: %v", err)
+			p.discardIncoming()
+			return
+		}
+		p.incomingGot += int64(len(message.Data))
+		p.incomingMeter.add(int64(len(message.Data)))
+		return
+	}
+
+	var msg FileTransferMessage
+	if err := json.Unmarshal(message.Data, &msg); err != nil {
+		log.Printf("Error reading message: %v", err)
+		return
+	}
+
+	switch msg.Type {
+	case "file-start":
+		p.discardIncoming()
+		if err := os.MkdirAll(p.downloadPath, 0755); err != nil {
+			log.Printf("Error creating download directory: %v", err)
+			return
+		}
+		file, err := os.CreateTemp(p.downloadPath, ".lumo-webrtc-*")
+		if err != nil {
+			log.Printf("Error creating file: %v", err)
+			return
+		}
+		p.incoming = file
+		p.incomingName = msg.Filename
+		p.incomingSize = msg.Size
+		p.incomingGot = 0
+		progress.printf("\033[1;36m📥 Receiving file: %s (%s)...\033[0m\n", safeFilename(msg.Filename), formatFileSize(msg.Size))
+		p.incomingMeter = newRateMeter("received", msg.Filename, msg.Size)
+	case "file-end":
+		p.finishIncoming()
+	case "ack":
+		select {
+		case p.acks <- msg.Filename:
+		default:
+		}
+	}
+}
+
+// finishIncoming saves or stages the file that was just received and
+// acknowledges it. The caller must hold incomingMutex.
+func (p *WebRTCPeer) finishIncoming() {
+	if p.incoming == nil {
+		return
+	}
+	file, filename, size := p.incoming, p.incomingName, p.incomingGot
+	p.incoming = nil
+	file.Close()
+	defer os.Remove(file.Name())
+
+	var err error
+	if size != p.incomingSize {
+		err = fmt.Errorf("expected %d bytes, got %d", p.incomingSize, size)
+	}
+	p.incomingMeter.finish(p.peer(), "webrtc", err)
+	if err != nil {
+		log.Printf("Error receiving %s: %v", filename, err)
+		return
+	}
+
+	if p.staging != nil {
+		data, err := os.Open(file.Name())
+		if err != nil {
+			log.Printf("Error staging file: %v", err)
+			return
+		}
+		_, err = p.staging.StageReader(filename, data)
+		data.Close()
+		if err != nil {
+			log.Printf("Error staging file: %v", err)
+			return
+		}
+	} else {
+		filePath, err := placeReceived(file.Name(), p.downloadPath, filename)
+		if err != nil {
+			log.Printf("Error saving file: %v", err)
+			return
+		}
+		notifyTransfer("received", filePath, size)
+		progress.printf("\033[1;36m📥 Received file: %s (%s)\033[0m\n", filePath, formatFileSize(size))
+	}
+
+	if err := p.sendControl(FileTransferMessage{Type: "ack", Filename: filename}); err != nil {
+		log.Printf("Error sending acknowledgment: %v", err)
+	}
+}
+
+// discardIncoming drops a partly received file. The caller must hold incomingMutex.
+func (p *WebRTCPeer) discardIncoming() {
+	if p.incoming == nil {
+		return
+	}
+	p.incoming.Close()
+	os.Remove(p.incoming.Name())
+	p.incoming = nil
+	p.incomingMeter.finish(p.peer(), "webrtc", fmt.Errorf("transfer interrupted"))
+}
+
+// ReceiveWebRTC waits for a peer to connect over WebRTC using opts.Code,
+// generating a code if none is given, and then exchanges files with it
+func (m *ConnectManager) ReceiveWebRTC(ctx context.Context, opts WebRTCOptions) error {
+	defer m.closeStaging()
+
+	if opts.Code == "" {
+		id, err := generateID()
+		if err != nil {
+			return fmt.Errorf("failed to generate a connection code: %w", err)
+		}
+		opts.Code = id[:12]
+	}
+
+	printFancyHeader()
+	fmt.Printf("\033[1;36m")
+	fmt.Printf("┌─────────────────────────────────────────────────┐\n")
+	fmt.Printf("│ 🔌 \033[1;97mLumo Connect (WebRTC)\033[1;36m                      │\n")
+	fmt.Printf("├─────────────────────────────────────────────────┤\n")
+	fmt.Printf("│ \033[1;97mStatus:\033[1;36m Waiting for a peer                    │\n")
+	fmt.Printf("│ \033[1;97mCode:\033[1;36m %-39s │\n", opts.Code)
+	fmt.Printf("│ \033[1;97mSignaling:\033[1;36m %-34s │\n", opts.SignalURL)
+	fmt.Printf("│ \033[1;97mDownload Path:\033[1;36m %-30s │\n", m.downloadPath)
+	fmt.Printf("│ \033[1;97mStaging:\033[1;36m %-36s │\n", m.stagingDescription())
+	fmt.Printf("└─────────────────────────────────────────────────┘\n\n")
+	fmt.Printf("📋 \033[1;97mOn the other machine, run:\033[1;36m\n")
+	fmt.Printf("   lumo connect --webrtc %s --signal %s\n\n", opts.Code, opts.SignalURL)
+	fmt.Printf("🛑 \033[1;97mPress Ctrl+C to stop\033[1;36m\n\n")
+	fmt.Printf("\033[0m")
+
+	peer, err := AcceptWebRTC(ctx, opts, m.downloadPath, m.staging)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	return m.runWebRTCSession(ctx, peer)
+}
+
+// ConnectWebRTC connects to the peer waiting under opts.Code and exchanges files with it
+func (m *ConnectManager) ConnectWebRTC(ctx context.Context, opts WebRTCOptions) error {
+	defer m.closeStaging()
+
+	if opts.Code == "" {
+		return fmt.Errorf("a connection code is required")
+	}
+	fmt.Printf("\033[1;36m🔗 Connecting to peer %s through %s...\033[0m\n", opts.Code, opts.SignalURL)
+
+	peer, err := DialWebRTC(ctx, opts, m.downloadPath, m.staging)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	return m.runWebRTCSession(ctx, peer)
+}
+
+// runWebRTCSession sends the files typed on stdin until either side disconnects
+func (m *ConnectManager) runWebRTCSession(ctx context.Context, peer *WebRTCPeer) error {
+	defer peer.Close()
+
+	fmt.Printf("\033[1;32m✅ Direct connection established\033[0m\n")
+	fmt.Printf("📥 \033[1;97mReceived files will be saved to:\033[0m %s\n\n", m.downloadPath)
+
+	go m.readFilePaths(func(filePath string) {
+		if err := peer.SendFile(filePath); err != nil {
+			progress.printf("\033[1;31m❌ Error sending file: %v\033[0m\n", err)
+		}
+	})
+
+	select {
+	case <-ctx.Done():
+	case <-peer.Done():
+		fmt.Printf("\033[1;33m🔌 Peer disconnected\033[0m\n")
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package contacts keeps names for the machines files are sent to with
+// 'lumo send', along with where each was last seen on the network, so
+// nobody has to remember addresses. Addresses are refreshed whenever
+// discovery sees the machine's advertised hostname.
+package contacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// DefaultPort is the port 'lumo connect --receive' listens on by default
+const DefaultPort = 8080
+
+// validName matches contact names, which are typed after "to"
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,31}$`)
+
+// Contact is a named peer
+type Contact struct {
+	Name string `json:"name"`
+	// Hostname is the name the peer advertises, which is how discovery
+	// recognizes it when its address changes
+	Hostname string `json:"hostname,omitempty"`
+	// Address and Port are where the peer was last seen
+	Address  string    `json:"address,omitempty"`
+	Port     int       `json:"port,omitempty"`
+	LastSeen time.Time `json:"last_seen,omitempty"`
+}
+
+// Target returns the address and port to connect to
+func (c *Contact) Target() (string, int) {
+	port := c.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return c.Address, port
+}
+
+// Path returns the file the contacts are kept in
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contacts.json"), nil
+}
+
+// List returns every contact, sorted by name
+func List() ([]Contact, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contacts: %w", err)
+	}
+	var contacts []Contact
+	if err := json.Unmarshal(data, &contacts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].Name < contacts[j].Name
+	})
+	return contacts, nil
+}
+
+// Get returns the contact with the given name, ignoring case
+func Get(name string) (*Contact, error) {
+	contacts, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range contacts {
+		if strings.EqualFold(contacts[i].Name, name) {
+			return &contacts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no contact named %q", name)
+}
+
+// Add saves a contact for a peer, replacing one with the same name, and
+// reports whether it replaced one. The peer is an IP address, with a port
+// if it is not the default one, or the hostname it advertises.
+func Add(name, peer string) (*Contact, bool, error) {
+	if !validName.MatchString(name) {
+		return nil, false, fmt.Errorf("invalid contact name %q: use up to 32 letters, digits, dots, dashes, and underscores", name)
+	}
+	contact := Contact{Name: name}
+	if address, port, ok := ParseAddress(peer); ok {
+		contact.Address, contact.Port = address, port
+	} else if validHostname(peer) {
+		contact.Hostname = peer
+	} else {
+		return nil, false, fmt.Errorf("%q is neither an IP address nor a hostname", peer)
+	}
+
+	contacts, err := List()
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range contacts {
+		if strings.EqualFold(contacts[i].Name, name) {
+			contacts[i] = contact
+			return &contact, true, write(contacts)
+		}
+	}
+	return &contact, false, write(append(contacts, contact))
+}
+
+// Remove deletes the contact with the given name
+func Remove(name string) error {
+	contacts, err := List()
+	if err != nil {
+		return err
+	}
+	for i := range contacts {
+		if strings.EqualFold(contacts[i].Name, name) {
+			return write(append(contacts[:i], contacts[i+1:]...))
+		}
+	}
+	return fmt.Errorf("no contact named %q", name)
+}
+
+// Refresh updates contacts from discovered services: a contact whose
+// hostname was seen gets the address it was seen at, and one seen at its
+// address learns its hostname. Only connect receivers change the port. It
+// returns the contacts that were seen.
+func Refresh(services []discovery.Service) ([]Contact, error) {
+	contacts, err := List()
+	if err != nil || len(contacts) == 0 || len(services) == 0 {
+		return nil, err
+	}
+
+	var seen []Contact
+	for i := range contacts {
+		contact := &contacts[i]
+		for _, service := range services {
+			hostname := service.Info[discovery.InfoHostname]
+			switch {
+			case contact.Hostname != "" && strings.EqualFold(hostname, contact.Hostname):
+			case contact.Hostname == "" && hostname != "" && service.IP == contact.Address:
+				contact.Hostname = hostname
+			default:
+				continue
+			}
+			contact.Address = service.IP
+			if service.Type == discovery.ServiceName && service.Port != 0 {
+				contact.Port = service.Port
+			}
+			contact.LastSeen = service.LastSeen
+			if contact.LastSeen.IsZero() {
+				contact.LastSeen = time.Now()
+			}
+			seen = append(seen, *contact)
+			break
+		}
+	}
+	if len(seen) == 0 {
+		return nil, nil
+	}
+	return seen, write(contacts)
+}
+
+// MarkSeen records that a contact was reached at its address
+func MarkSeen(name string) error {
+	contacts, err := List()
+	if err != nil {
+		return err
+	}
+	for i := range contacts {
+		if strings.EqualFold(contacts[i].Name, name) {
+			contacts[i].LastSeen = time.Now()
+			return write(contacts)
+		}
+	}
+	return fmt.Errorf("no contact named %q", name)
+}
+
+// ParseAddress splits an IP address with an optional port, like
+// 192.168.1.5 or 192.168.1.5:9000, using the default port without one
+func ParseAddress(peer string) (string, int, bool) {
+	if ip := net.ParseIP(strings.Trim(peer, "[]")); ip != nil {
+		return ip.String(), DefaultPort, true
+	}
+	host, portText, err := net.SplitHostPort(peer)
+	if err != nil || net.ParseIP(host) == nil {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, false
+	}
+	return host, port, true
+}
+
+// validHostname reports whether s can be a hostname
+func validHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// write stores the contacts, privately since they map out the network
+func write(contacts []Contact) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(contacts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode contacts: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write contacts: %w", err)
+	}
+	return nil
+}
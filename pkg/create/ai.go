@@ -0,0 +1,304 @@
+package create
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// aiUsage describes the create ai: arguments
+const aiUsage = `Usage: create ai:"<description>" [--name <name>] [--docker] [--no-git] [--dry-run] [--yes]`
+
+// maxPlanFiles and maxPlanBytes bound what an AI plan may write
+const (
+	maxPlanFiles = 100
+	maxPlanBytes = 1 << 20
+)
+
+// aiProjectName matches the names of projects planned by AI
+var aiProjectName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ProjectPlan is the project the AI provider plans for a description: the
+// files to write, relative to the project directory, with their contents
+type ProjectPlan struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Files       []PlanFile `json:"files"`
+	// Run holds commands for the user to run once the files are written,
+	// such as installing dependencies; they are shown, never run
+	Run []string `json:"run"`
+}
+
+// PlanFile is a file in a project plan
+type PlanFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// SetConfirm replaces how the user is asked to write a project planned by
+// AI; confirm gets the preview of the project and reports whether to write it
+func (g *Generator) SetConfirm(confirm func(preview string) bool) {
+	g.confirm = confirm
+}
+
+// generateFromAI asks the AI provider to plan a project for a description,
+// previews its files, and writes them once the user confirms
+func (g *Generator) generateFromAI(query string) (string, error) {
+	query, docker := cutFlag(query, "--docker")
+	query, noGit := cutGitFlags(query)
+	query, dryRun := cutFlag(query, "--dry-run")
+	query, yes := cutFlag(query, "--yes")
+	query, name, err := cutOption(query, "--name")
+	if err != nil {
+		return "", err
+	}
+	description := strings.Trim(strings.TrimSpace(query), `"'`)
+	if description == "" {
+		return "", fmt.Errorf("a description of the project is required\n%s", aiUsage)
+	}
+	if g.aiClient == nil {
+		return "", fmt.Errorf("an AI provider is required to plan a project")
+	}
+
+	plan, err := g.planProject(description, name, docker)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(plan.Name); err == nil {
+		return "", fmt.Errorf("%s already exists", plan.Name)
+	}
+
+	preview := plan.Preview()
+	if dryRun {
+		return preview + "\nDry run: nothing was written.\n", nil
+	}
+	if !yes && !g.confirm(preview) {
+		return preview + "\nNothing was written. Add --yes to write the files without asking.\n", nil
+	}
+
+	if err := plan.Write(plan.Name); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("✅ Project '%s' created from an AI plan!\n", plan.Name))
+	if plan.Description != "" {
+		b.WriteString(plan.Description + "\n")
+	}
+	b.WriteString(fmt.Sprintf("\nWrote %d files to %s/\n", len(plan.Files), plan.Name))
+	if len(plan.Run) > 0 {
+		b.WriteString("\nNext steps:\n")
+		b.WriteString(fmt.Sprintf("  cd %s\n", plan.Name))
+		for _, command := range plan.Run {
+			b.WriteString(fmt.Sprintf("  %s\n", command))
+		}
+	}
+	options := map[string]string{}
+	if docker {
+		options["docker"] = "true"
+	}
+	if noGit {
+		options["git"] = "false"
+	}
+	return finishProject(b.String(), plan.Name, options), nil
+}
+
+// planProject asks the AI provider for a project plan and checks it
+func (g *Generator) planProject(description, name string, docker bool) (*ProjectPlan, error) {
+	var extra []string
+	if name != "" {
+		extra = append(extra, fmt.Sprintf("Name the project %q.", name))
+	}
+	if docker {
+		extra = append(extra, "Include a Dockerfile, a .dockerignore, and a docker-compose.yml that builds and runs the project.")
+	}
+	prompt := fmt.Sprintf(`
+You are a project creation assistant. Plan a new, working project for the following description, with every file it needs to build and run.
+
+Description: %s
+%s
+Respond with only a JSON object in the following format:
+{
+  "name": "project-name",
+  "description": "One sentence about the project",
+  "files": [
+    {"path": "relative/path/to/file", "content": "the complete file contents"}
+  ],
+  "run": ["commands to run in the project directory afterwards, such as installing dependencies"]
+}
+
+Paths are relative to the project directory. Write complete files, not placeholders, and include a README.md and a .gitignore. Keep the project small: no more than %d files.
+`, description, strings.Join(extra, "\n"), maxPlanFiles)
+
+	response, err := g.aiClient.Query(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan the project: %w", err)
+	}
+	plan, err := ParseProjectPlan(response)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		plan.Name = name
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ParseProjectPlan reads a project plan from an AI response, which may
+// wrap the JSON in prose or a code fence
+func ParseProjectPlan(response string) (*ProjectPlan, error) {
+	start := strings.Index(response, "{")
+	if start < 0 {
+		return nil, fmt.Errorf("the AI response has no project plan")
+	}
+	// A decoder stops at the end of the object, and unlike counting braces
+	// is not confused by braces in the file contents
+	var plan ProjectPlan
+	if err := json.NewDecoder(strings.NewReader(response[start:])).Decode(&plan); err != nil {
+		return nil, fmt.Errorf("failed to parse the project plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// Validate checks that a plan has a usable name and only writes files
+// inside the project directory
+func (p *ProjectPlan) Validate() error {
+	if !aiProjectName.MatchString(p.Name) {
+		return fmt.Errorf("invalid project name %q: use up to 64 letters, digits, dots, dashes, and underscores", p.Name)
+	}
+	if len(p.Files) == 0 {
+		return fmt.Errorf("the project plan has no files")
+	}
+	if len(p.Files) > maxPlanFiles {
+		return fmt.Errorf("the project plan has %d files, more than the %d allowed", len(p.Files), maxPlanFiles)
+	}
+
+	seen := make(map[string]bool, len(p.Files))
+	size := 0
+	for i := range p.Files {
+		path := filepath.Clean(filepath.FromSlash(p.Files[i].Path))
+		if !filepath.IsLocal(path) {
+			return fmt.Errorf("the project plan writes outside the project: %s", p.Files[i].Path)
+		}
+		if first, _, _ := strings.Cut(filepath.ToSlash(path), "/"); first == ".git" {
+			return fmt.Errorf("the project plan writes into .git: %s", p.Files[i].Path)
+		}
+		if seen[path] {
+			return fmt.Errorf("the project plan writes %s twice", p.Files[i].Path)
+		}
+		seen[path] = true
+		p.Files[i].Path = filepath.ToSlash(path)
+		size += len(p.Files[i].Content)
+	}
+	if size > maxPlanBytes {
+		return fmt.Errorf("the project plan writes %d bytes, more than the %d allowed", size, maxPlanBytes)
+	}
+
+	// A file cannot also be a directory of others
+	for path := range seen {
+		for dir := filepath.Dir(path); dir != "."; dir = filepath.Dir(dir) {
+			if seen[dir] {
+				return fmt.Errorf("the project plan writes %s both as a file and a directory", filepath.ToSlash(dir))
+			}
+		}
+	}
+	return nil
+}
+
+// Preview shows the plan's files as a tree, with their line counts
+func (p *ProjectPlan) Preview() string {
+	files := make([]PlanFile, len(p.Files))
+	copy(files, p.Files)
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🗂  %s", p.Name))
+	if p.Description != "" {
+		b.WriteString(" — " + p.Description)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("  %s/\n", p.Name))
+
+	var previous []string
+	for _, file := range files {
+		parts := strings.Split(file.Path, "/")
+		dirs := parts[:len(parts)-1]
+		// Show the directories this file is in that the last one was not
+		common := 0
+		for common < len(dirs) && common < len(previous) && dirs[common] == previous[common] {
+			common++
+		}
+		for depth := common; depth < len(dirs); depth++ {
+			b.WriteString(fmt.Sprintf("  %s%s/\n", strings.Repeat("  ", depth+1), dirs[depth]))
+		}
+		previous = dirs
+
+		lines := strings.Count(file.Content, "\n")
+		if file.Content != "" && !strings.HasSuffix(file.Content, "\n") {
+			lines++
+		}
+		unit := "lines"
+		if lines == 1 {
+			unit = "line"
+		}
+		b.WriteString(fmt.Sprintf("  %s%s (%d %s)\n", strings.Repeat("  ", len(dirs)+1), parts[len(parts)-1], lines, unit))
+	}
+
+	if len(p.Run) > 0 {
+		b.WriteString("\nThen run:\n")
+		for _, command := range p.Run {
+			b.WriteString(fmt.Sprintf("  %s\n", command))
+		}
+	}
+	return b.String()
+}
+
+// Write creates the project directory and writes the plan's files to it
+func (p *ProjectPlan) Write(dir string) error {
+	if err := os.Mkdir(dir, 0755); err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%s already exists", dir)
+		}
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, file := range p.Files {
+		path := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		mode := os.FileMode(0644)
+		if strings.HasPrefix(file.Content, "#!") {
+			mode = 0755
+		}
+		if err := os.WriteFile(path, []byte(file.Content), mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// confirmOnTerminal shows the preview of a planned project and asks on the
+// terminal whether to write it; without a terminal, nothing is written
+func confirmOnTerminal(preview string) bool {
+	if !utils.IsTerminal(os.Stdin) {
+		return false
+	}
+	fmt.Print(preview)
+	fmt.Print("\nWrite these files? (y/n): ")
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
@@ -11,12 +11,15 @@ import (
 // Generator handles project creation
 type Generator struct {
 	aiClient ai.Client
+	// confirm asks whether to write a project planned by AI
+	confirm func(preview string) bool
 }
 
 // NewGenerator creates a new project generator
 func NewGenerator(aiClient ai.Client) *Generator {
 	return &Generator{
 		aiClient: aiClient,
+		confirm:  confirmOnTerminal,
 	}
 }
 
@@ -27,22 +30,217 @@ func (g *Generator) Execute(query string) (string, error) {
 		return g.showHelp(), nil
 	}
 
+	// Go projects and named templates are scaffolded from their
+	// arguments, without AI
+	args := strings.Fields(query)
+	switch strings.ToLower(args[0]) {
+	case "go":
+		return generateGoProject(args[1:])
+	case "list-templates":
+		return listTemplates()
+	case "from":
+		return generateFromTemplate(args[1:])
+	}
+
+	// The AI plans the files of projects no generator covers
+	if rest, ok := cutPrefixFold(query, "ai:"); ok {
+		return g.generateFromAI(rest)
+	}
+
+	// The React tool, --docker, and --git are options, not part of the
+	// description
+	query, docker := cutFlag(query, "--docker")
+	query, noGit := cutGitFlags(query)
+	query, tool, err := cutOption(query, "--tool")
+	if err != nil {
+		return "", err
+	}
+	if tool != "" && !isReactTool(strings.ToLower(tool)) {
+		return "", fmt.Errorf("unknown tool: %s (available: %s)", tool, strings.Join(ReactTools, ", "))
+	}
+
 	// Parse the query to determine project type
 	projectType, framework, options, err := g.parseQuery(query)
 	if err != nil {
 		return "", err
 	}
+	if tool != "" {
+		if !strings.EqualFold(projectType, "react") {
+			return "", fmt.Errorf("--tool only applies to React projects")
+		}
+		options["tool"] = tool
+	}
+	if docker {
+		options["docker"] = "true"
+	}
+	if noGit {
+		options["git"] = "false"
+	}
 
 	// Generate the project
 	return g.generateProject(projectType, framework, options)
 }
 
+// NeedsAI reports whether a create query is described in natural language
+// and so needs an AI provider
+func NeedsAI(query string) bool {
+	args := strings.Fields(query)
+	if len(args) == 0 {
+		return false
+	}
+	switch strings.ToLower(args[0]) {
+	case "go", "list-templates", "from":
+		return false
+	}
+	return true
+}
+
+// cutPrefixFold removes a prefix from s, ignoring case, and reports
+// whether s had it
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// fromUsage describes the create from arguments
+const fromUsage = "Usage: create from <template> <name> [--docker] [--no-git]"
+
+// generateFromTemplate creates a project from a template chosen by name
+func generateFromTemplate(args []string) (string, error) {
+	query, docker := cutFlag(strings.Join(args, " "), "--docker")
+	query, noGit := cutGitFlags(query)
+	args = strings.Fields(query)
+	if len(args) != 2 {
+		return "", fmt.Errorf("a template and a project name are required\n%s", fromUsage)
+	}
+	template, err := LookupTemplate(args[0])
+	if err != nil {
+		return "", err
+	}
+	files, err := template.Create(TemplateData{Name: args[1], Docker: docker})
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("✅ Project '%s' created from the %s template!\n", args[1], template.Name))
+	if len(files) > 0 {
+		b.WriteString("\nFiles:\n")
+		for _, file := range files {
+			b.WriteString(fmt.Sprintf("  • %s\n", file))
+		}
+	}
+	if !noGit {
+		b.WriteString(initGit(args[1]) + "\n")
+	}
+	if docker {
+		b.WriteString(dockerNote(args[1]) + "\n")
+	}
+	return b.String(), nil
+}
+
+// listTemplates lists the built-in and user templates
+func listTemplates() (string, error) {
+	templates, err := Templates()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("📦 Project Templates:\n\n")
+	for _, t := range templates {
+		var tags []string
+		if t.User {
+			tags = append(tags, "user")
+		}
+		if t.Project != "" && t.HasDocker() {
+			tags = append(tags, "--docker")
+		}
+		name := t.Name
+		if len(tags) > 0 {
+			name += " (" + strings.Join(tags, ", ") + ")"
+		}
+		b.WriteString(fmt.Sprintf("  • %s\n", name))
+		if t.Description != "" {
+			b.WriteString(fmt.Sprintf("      %s\n", t.Description))
+		}
+	}
+	if dir, err := UserTemplateDir(); err == nil {
+		b.WriteString(fmt.Sprintf("\nAdd your own templates to %s, each in a directory\n", dir))
+		b.WriteString(fmt.Sprintf("with a %s manifest and its files under files/.\n", ManifestFile))
+	}
+	b.WriteString("\n" + fromUsage + "\n")
+	return b.String(), nil
+}
+
+// cutFlag removes a boolean `--name` flag from a query, and reports
+// whether it was there
+func cutFlag(query, name string) (string, bool) {
+	args := strings.Fields(query)
+	for i, arg := range args {
+		if arg == name {
+			return strings.Join(append(args[:i:i], args[i+1:]...), " "), true
+		}
+	}
+	return query, false
+}
+
+// dockerNote tells users how to start a project created with --docker
+func dockerNote(name string) string {
+	return fmt.Sprintf("🐳 Container-ready: cd %s && docker compose up --build", name)
+}
+
+// cutGitFlags removes --git and --no-git from a query, and reports whether
+// --no-git was there. Projects are put under git unless it is.
+func cutGitFlags(query string) (string, bool) {
+	query, _ = cutFlag(query, "--git")
+	return cutFlag(query, "--no-git")
+}
+
+// finishProject puts a project a generator created under git, unless it
+// was created with --no-git, and adds what was done and the Docker note to
+// the generator's success message
+func finishProject(message, name string, options map[string]string) string {
+	if options["git"] != "false" {
+		message += "\n" + initGit(name)
+	}
+	if options["docker"] != "" {
+		message += "\n" + dockerNote(name)
+	}
+	return message
+}
+
+// cutOption removes a `--name value` or `--name=value` option from a query,
+// and returns the rest of the query and the option's value
+func cutOption(query, name string) (string, string, error) {
+	args := strings.Fields(query)
+	for i, arg := range args {
+		flag, value, hasValue := strings.Cut(arg, "=")
+		if flag != name {
+			continue
+		}
+		end := i + 1
+		if !hasValue {
+			if end == len(args) {
+				return "", "", fmt.Errorf("%s needs a value", name)
+			}
+			value = args[end]
+			end++
+		}
+		rest := append(args[:i:i], args[end:]...)
+		return strings.Join(rest, " "), value, nil
+	}
+	return query, "", nil
+}
+
 // parseQuery analyzes the natural language query to determine project details
 func (g *Generator) parseQuery(query string) (string, string, map[string]string, error) {
 	// Create a prompt for the AI to analyze the query
 	prompt := fmt.Sprintf(`
 You are a project creation assistant. Analyze the following query and extract the following information:
-1. Project type/framework (e.g., Flutter, React, Next.js)
+1. Project type/framework (e.g., Flutter, React, Next.js, FastAPI, Flask, Django, Express)
 2. State management approach (e.g., Bloc, Provider, Riverpod for Flutter)
 3. Any other specific requirements or options
 
@@ -50,8 +248,8 @@ Query: %s
 
 Respond in the following JSON format:
 {
-  "projectType": "flutter|react|nextjs|etc",
-  "framework": "bloc|provider|riverpod|redux|etc",
+  "projectType": "flutter|react|nextjs|python|node|etc",
+  "framework": "bloc|provider|riverpod|redux|fastapi|flask|django|express|etc",
   "options": {
     "name": "project_name",
     "additionalFeatures": ["feature1", "feature2"]
@@ -88,6 +286,8 @@ Only include fields that you can confidently determine from the query. Use snake
 			options["name"] = "my-react-app"
 		case "nextjs":
 			options["name"] = "my-nextjs-app"
+		case "node", "express":
+			options["name"] = "my-express-app"
 		default:
 			options["name"] = "my-app"
 		}
@@ -114,8 +314,14 @@ func (g *Generator) generateProject(projectType, framework string, options map[s
 		return generateNextJSProject(framework, options)
 	case "react":
 		return generateReactProject(framework, options)
-	case "fastapi", "flask", "python":
+	case "fastapi", "flask", "django", "python":
+		// The framework may be given as the project type
+		if framework == "" && projectType != "python" {
+			framework = projectType
+		}
 		return generatePythonProject(framework, options)
+	case "express", "node", "nodejs":
+		return generateNodeProject(framework, options)
 	// Add more project types here as needed
 	default:
 		return "", fmt.Errorf("unsupported project type: %s", projectType)
@@ -141,12 +347,42 @@ func (g *Generator) showHelp() string {
 │    lumo create:"React project with Recoil"                 │
 │    lumo create:"FastAPI project with SQLAlchemy"           │
 │    lumo create:"Flask web application"                     │
+│    lumo create django mysite                               │
+│    lumo create express api                                 │
+│    lumo create go myapi --layout hexagonal                 │
+│                                                            │
+│  React projects use Vite and TypeScript; for               │
+│  create-react-app, add --tool cra:                         │
+│    lumo create:"React app with Redux" --tool cra           │
+│                                                            │
+│  Go modules (no AI needed):                                │
+│    lumo create go <name> [--layout std|cmd|hexagonal]      │
+│                          [--module <path>] [--docker]      │
+│                                                            │
+│  Add --docker for a Dockerfile, .dockerignore, and         │
+│  docker-compose.yml (with PostgreSQL for Flask and Django):│
+│    lumo create:"FastAPI project" --docker                  │
+│                                                            │
+│  Let AI plan the files of any other project, preview them, │
+│  and write them once you confirm:                          │
+│    lumo create ai:"a CLI tool in Go that syncs S3 buckets" │
+│                                                            │
+│  Projects are committed to a new git repository; add       │
+│  --no-git to skip it.                                      │
+│                                                            │
+│  Templates (no AI needed):                                 │
+│    lumo create list-templates                              │
+│    lumo create from <template> <name> [--docker]           │
+│  Add your own under ~/.config/lumo/templates/<name>/, with │
+│  a template.json manifest and the files to write in files/.│
 │                                                            │
 │  Supported Frameworks:                                     │
 │    • Flutter (with Bloc, Provider, Riverpod)               │
 │    • Next.js (with Redux, Context API, Zustand)            │
 │    • React (with Redux, Context API, MobX, Recoil)         │
-│    • Python (FastAPI, Flask)                               │
+│    • Python (FastAPI, Flask, Django)                       │
+│    • Node.js (Express with TypeScript, ESLint, Jest)       │
+│    • Go (std, cmd, or hexagonal layout)                    │
 │                                                            │
 ╰────────────────────────────────────────────────────────────╯
 `
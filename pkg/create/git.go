@@ -0,0 +1,64 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// initGit puts a new project under version control: it initializes a
+// repository, unless the tool that created the project already did, and
+// commits the project's files. A project is usable without git, so
+// failures are reported in the returned line for the success message
+// rather than as errors.
+func initGit(dir string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "⚠️  git is not installed, so the project is not under version control"
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		// A project created inside another repository is left to it
+		if top, err := git(dir, "rev-parse", "--show-toplevel"); err == nil {
+			return fmt.Sprintf("📚 Left version control to the git repository at %s", top)
+		}
+		if _, err := git(dir, "init", "-q"); err != nil {
+			return fmt.Sprintf("⚠️  git init failed: %v", err)
+		}
+	}
+
+	if _, err := git(dir, "add", "-A"); err != nil {
+		return fmt.Sprintf("⚠️  Initialized git, but adding the files failed: %v", err)
+	}
+	// Tools such as create-react-app make a first commit of their own,
+	// which may leave nothing to commit
+	if _, err := git(dir, "diff", "--cached", "--quiet"); err == nil {
+		return "📚 Initialized git"
+	}
+	message, done := "Initial commit", "📚 Initialized git with a first commit"
+	if _, err := git(dir, "rev-parse", "-q", "--verify", "HEAD"); err == nil {
+		message, done = "Add the project template's files", "📚 Committed the template's files to git"
+	}
+	if _, err := git(dir, "commit", "-q", "-m", message); err != nil {
+		return fmt.Sprintf("⚠️  Initialized git, but the commit failed: %v\n   Commit the files with: cd %s && git commit -m %q", err, dir, message)
+	}
+	return done
+}
+
+// git runs git in dir and returns its trimmed output, or the last line of
+// its output, which says what went wrong, as the error if it fails
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		if output == "" {
+			return "", err
+		}
+		return "", errors.New(output[strings.LastIndex(output, "\n")+1:])
+	}
+	return output, nil
+}
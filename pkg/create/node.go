@@ -0,0 +1,42 @@
+package create
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// npmPackageName matches names npm accepts for an unscoped package
+var npmPackageName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// generateNodeProject creates a new Node.js project. Express, in
+// TypeScript with ESLint, Jest, and a Dockerfile, is the only framework.
+func generateNodeProject(framework string, options map[string]string) (string, error) {
+	if framework == "" {
+		framework = "express"
+	}
+	if !strings.EqualFold(framework, "express") {
+		return "", fmt.Errorf("unsupported Node.js framework: %s", framework)
+	}
+
+	// Get project name from options or use a default
+	projectName := options["name"]
+	if projectName == "" {
+		projectName = "my-express-app"
+	}
+	// The name is also the package name in package.json
+	if !npmPackageName.MatchString(projectName) {
+		return "", fmt.Errorf("invalid project name: %q (use lowercase letters, digits, -, _ and .)", projectName)
+	}
+
+	template, err := findTemplate("node", framework, "")
+	if err != nil {
+		return "", err
+	}
+	if _, err := template.Create(TemplateData{Name: projectName, Docker: options["docker"] != ""}); err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("✅ Express project '%s' created successfully with TypeScript, ESLint, and Jest!", projectName)
+	return finishProject(message, projectName, options), nil
+}
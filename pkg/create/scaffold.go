@@ -0,0 +1,464 @@
+package create
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// builtinTemplates holds the templates that ship with lumo
+//
+//go:embed all:templates
+var builtinTemplates embed.FS
+
+// ManifestFile is the name of the file describing a template
+const ManifestFile = "template.json"
+
+// filesDir is the directory of a template holding the files it writes.
+// Files ending in .tmpl are rendered with text/template and written
+// without the suffix; the rest are copied as they are. Like create-vite,
+// templates ship _gitignore files so git keeps tracking the files they
+// ignore, and write them as .gitignore.
+const filesDir = "files"
+
+// dockerDir is the directory of a template holding the files that make
+// the project container-ready, such as a Dockerfile and docker-compose.yml.
+// They are written like those in files/, for projects created with --docker.
+const dockerDir = "docker"
+
+// Manifest describes a project template. Arguments of Init and Commands,
+// Dirs, and file paths are rendered with TemplateData.
+type Manifest struct {
+	Description string `json:"description"`
+	// Project, Architecture and Tool are what natural language requests
+	// are matched against; templates without a project are only used by
+	// name or extended by other templates
+	Project      string `json:"project,omitempty"`
+	Architecture string `json:"architecture,omitempty"`
+	Tool         string `json:"tool,omitempty"`
+	// Default marks the template used when no architecture matches
+	Default bool `json:"default,omitempty"`
+	// Extends lists templates applied before this one, in order
+	Extends []string `json:"extends,omitempty"`
+	// Requires lists the programs that must be installed
+	Requires []string `json:"requires,omitempty"`
+	// Init is the command that creates the project directory, such as
+	// flutter create. Without it an empty directory is created.
+	Init []string `json:"init,omitempty"`
+	// Interactive lets the init command read from the terminal
+	Interactive bool     `json:"interactive,omitempty"`
+	Dirs        []string `json:"dirs,omitempty"`
+	// Commands run in the project directory once the files are written
+	Commands [][]string `json:"commands,omitempty"`
+}
+
+// Template is a project template, built in or from the user's templates
+// directory
+type Template struct {
+	Manifest
+	Name string
+	// User reports whether the template is from the user's templates directory
+	User bool
+	fsys fs.FS
+}
+
+// TemplateData is what templates are rendered with
+type TemplateData struct {
+	Name   string
+	Module string
+	// Python is the Python interpreter, for templates requiring python
+	Python string
+	// VenvBin is the directory of a virtual environment's programs
+	VenvBin string
+	// Docker asks for the template's Docker files as well
+	Docker bool
+}
+
+// installHints tell users where to get the programs templates require
+var installHints = map[string]string{
+	"flutter": "Flutter is not installed or not in PATH. Please install Flutter first: https://flutter.dev/docs/get-started/install",
+	"node":    "Node.js is not installed or not in PATH. Please install Node.js first: https://nodejs.org/",
+	"python":  "Python is not installed or not in PATH. Please install Python first: https://www.python.org/downloads/",
+}
+
+// UserTemplateDir returns the directory of the user's templates. A user
+// template with the name of a built-in one replaces it.
+func UserTemplateDir() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// Templates returns the built-in and user templates, sorted by name
+func Templates() ([]*Template, error) {
+	builtin, err := fs.Sub(builtinTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	templates, err := loadTemplates(builtin, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if dir, err := UserTemplateDir(); err == nil {
+		user, err := loadTemplates(os.DirFS(dir), true)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for name, t := range user {
+			templates[name] = t
+		}
+	}
+
+	list := make([]*Template, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// loadTemplates reads the templates in the subdirectories of fsys
+func loadTemplates(fsys fs.FS, user bool) (map[string]*Template, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	templates := make(map[string]*Template)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(entry.Name(), ManifestFile))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		t := &Template{Name: entry.Name(), User: user}
+		if err := json.Unmarshal(data, &t.Manifest); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", entry.Name(), err)
+		}
+		t.fsys, err = fs.Sub(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		templates[t.Name] = t
+	}
+	return templates, nil
+}
+
+// LookupTemplate returns the template with the given name
+func LookupTemplate(name string) (*Template, error) {
+	templates, err := Templates()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown template: %s (see 'lumo create list-templates')", name)
+}
+
+// findTemplate returns the template for a project and architecture, or the
+// project's default template when no architecture matches
+func findTemplate(project, architecture, tool string) (*Template, error) {
+	templates, err := Templates()
+	if err != nil {
+		return nil, err
+	}
+	var fallback *Template
+	for _, t := range templates {
+		if t.Project != project || t.Tool != tool {
+			continue
+		}
+		if strings.EqualFold(t.Architecture, architecture) {
+			return t, nil
+		}
+		if t.Default {
+			fallback = t
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("no template for %s projects", project)
+	}
+	return fallback, nil
+}
+
+// chain returns the templates t extends followed by t, with each template
+// listed once
+func (t *Template) chain() ([]*Template, error) {
+	var chain []*Template
+	seen := make(map[string]bool)
+	var visit func(t *Template, trail []string) error
+	visit = func(t *Template, trail []string) error {
+		for _, name := range trail {
+			if name == t.Name {
+				return fmt.Errorf("template %s extends itself: %s", t.Name, strings.Join(append(trail, t.Name), " -> "))
+			}
+		}
+		if seen[t.Name] {
+			return nil
+		}
+		for _, name := range t.Extends {
+			base, err := LookupTemplate(name)
+			if err != nil {
+				return fmt.Errorf("template %s: %w", t.Name, err)
+			}
+			if err := visit(base, append(trail, t.Name)); err != nil {
+				return err
+			}
+		}
+		seen[t.Name] = true
+		chain = append(chain, t)
+		return nil
+	}
+	if err := visit(t, nil); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// Create creates a project from the template in the current directory, and
+// returns the paths of the files it wrote
+func (t *Template) Create(data TemplateData) ([]string, error) {
+	if data.Name == "" || data.Name == "." || data.Name == ".." || strings.ContainsAny(data.Name, `/\`) {
+		return nil, fmt.Errorf("invalid project name: %q", data.Name)
+	}
+	if data.Module == "" {
+		data.Module = data.Name
+	}
+
+	chain, err := t.chain()
+	if err != nil {
+		return nil, err
+	}
+	if data.Docker && !hasDocker(chain) {
+		return nil, fmt.Errorf("the %s template has no Docker files", t.Name)
+	}
+	var create []string
+	var interactive bool
+	for _, t := range chain {
+		for _, program := range t.Requires {
+			if err := checkInstalled(program, &data); err != nil {
+				return nil, err
+			}
+		}
+		if len(t.Init) > 0 {
+			create, interactive = t.Init, t.Interactive
+		}
+	}
+
+	// Create the project directory
+	if len(create) == 0 {
+		if _, err := os.Stat(data.Name); err == nil {
+			return nil, fmt.Errorf("%s already exists", data.Name)
+		}
+		if err := os.Mkdir(data.Name, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create project directory: %w", err)
+		}
+	} else if err := runCommand(create, "", interactive, data); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, t := range chain {
+		for _, dir := range t.Dirs {
+			dir, err := projectPath(dir, data)
+			if err != nil {
+				return nil, err
+			}
+			if err := os.MkdirAll(filepath.Join(data.Name, dir), 0755); err != nil {
+				return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+			}
+		}
+		dirs := []string{filesDir}
+		if data.Docker {
+			dirs = append(dirs, dockerDir)
+		}
+		for _, dir := range dirs {
+			files, err := t.writeFiles(dir, data)
+			if err != nil {
+				return nil, err
+			}
+			written = append(written, files...)
+		}
+	}
+
+	for _, t := range chain {
+		for _, command := range t.Commands {
+			if err := runCommand(command, data.Name, false, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// A file can be written by more than one template of the chain
+	sort.Strings(written)
+	unique := written[:0]
+	for i, file := range written {
+		if i == 0 || file != written[i-1] {
+			unique = append(unique, file)
+		}
+	}
+	return unique, nil
+}
+
+// HasDocker reports whether the template, or one it extends, has Docker
+// files for --docker
+func (t *Template) HasDocker() bool {
+	chain, err := t.chain()
+	return err == nil && hasDocker(chain)
+}
+
+// hasDocker reports whether any template of a chain has Docker files
+func hasDocker(chain []*Template) bool {
+	for _, t := range chain {
+		if _, err := fs.Stat(t.fsys, dockerDir); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFiles writes the files in a directory of the template into the
+// project, and returns their slash-separated paths
+func (t *Template) writeFiles(dir string, data TemplateData) ([]string, error) {
+	var written []string
+	err := fs.WalkDir(t.fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && name == dir {
+			return fs.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := fs.ReadFile(t.fsys, name)
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(name, dir+"/")
+		if strings.HasSuffix(rel, ".tmpl") {
+			rel = strings.TrimSuffix(rel, ".tmpl")
+			content, err = render(t.Name+"/"+name, string(content), data)
+			if err != nil {
+				return err
+			}
+		}
+		if path.Base(rel) == "_gitignore" {
+			rel = path.Join(path.Dir(rel), ".gitignore")
+		}
+		if rel, err = projectPath(rel, data); err != nil {
+			return err
+		}
+
+		fullPath := filepath.Join(data.Name, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(fullPath), err)
+		}
+		if err := os.WriteFile(fullPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", fullPath, err)
+		}
+		written = append(written, rel)
+		return nil
+	})
+	return written, err
+}
+
+// projectPath renders a slash-separated path within the project, and
+// rejects paths that leave it
+func projectPath(name string, data TemplateData) (string, error) {
+	if strings.Contains(name, "{{") {
+		rendered, err := render(name, name, data)
+		if err != nil {
+			return "", err
+		}
+		name = string(rendered)
+	}
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("template path %s is outside the project", name)
+	}
+	return name, nil
+}
+
+// render renders text with the template data
+func render(name, text string, data TemplateData) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return b.Bytes(), nil
+}
+
+// checkInstalled verifies that a program a template requires is installed.
+// python is satisfied by python3 or python, and sets the interpreter the
+// templates use.
+func checkInstalled(program string, data *TemplateData) error {
+	candidates := []string{program}
+	if program == "python" {
+		candidates = []string{"python3", "python"}
+	}
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate); err != nil {
+			continue
+		}
+		if program == "python" {
+			data.Python = candidate
+			data.VenvBin = "venv/bin"
+			if runtime.GOOS == "windows" {
+				data.VenvBin = "venv/Scripts"
+			}
+		}
+		return nil
+	}
+	if hint, ok := installHints[program]; ok {
+		return errors.New(hint)
+	}
+	return fmt.Errorf("%s is not installed or not in PATH", program)
+}
+
+// runCommand renders and runs a template command in dir
+func runCommand(command []string, dir string, interactive bool, data TemplateData) error {
+	args := make([]string, len(command))
+	for i, arg := range command {
+		rendered, err := render(arg, arg, data)
+		if err != nil {
+			return err
+		}
+		args[i] = string(rendered)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	if interactive {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run '%s': %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
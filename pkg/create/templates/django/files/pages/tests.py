@@ -0,0 +1,13 @@
+from django.test import TestCase
+from django.urls import reverse
+
+
+class PagesTests(TestCase):
+    def test_home(self):
+        response = self.client.get(reverse('pages:home'))
+        self.assertEqual(response.status_code, 200)
+        self.assertContains(response, 'Welcome')
+
+    def test_health(self):
+        response = self.client.get(reverse('pages:health'))
+        self.assertEqual(response.json(), {'status': 'ok'})
@@ -0,0 +1,10 @@
+from django.http import JsonResponse
+from django.shortcuts import render
+
+
+def home(request):
+    return render(request, 'pages/home.html', {'title': 'Welcome'})
+
+
+def health(request):
+    return JsonResponse({'status': 'ok'})
@@ -0,0 +1,18 @@
+import express from 'express';
+
+import { healthRouter } from './routes/health';
+import { itemsRouter } from './routes/items';
+
+export function createApp() {
+  const app = express();
+  app.use(express.json());
+
+  app.use('/health', healthRouter);
+  app.use('/api/items', itemsRouter);
+
+  app.use((_req, res) => {
+    res.status(404).json({ error: 'Not found' });
+  });
+
+  return app;
+}
@@ -0,0 +1,25 @@
+import { Router } from 'express';
+
+export interface Item {
+  id: number;
+  name: string;
+}
+
+const items: Item[] = [];
+
+export const itemsRouter = Router();
+
+itemsRouter.get('/', (_req, res) => {
+  res.json(items);
+});
+
+itemsRouter.post('/', (req, res) => {
+  const name = typeof req.body?.name === 'string' ? req.body.name.trim() : '';
+  if (!name) {
+    res.status(400).json({ error: 'name is required' });
+    return;
+  }
+  const item: Item = { id: items.length + 1, name };
+  items.push(item);
+  res.status(201).json(item);
+});
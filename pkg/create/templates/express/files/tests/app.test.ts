@@ -0,0 +1,27 @@
+import request from 'supertest';
+
+import { createApp } from '../src/app';
+
+describe('app', () => {
+  const app = createApp();
+
+  it('reports its health', async () => {
+    const response = await request(app).get('/health');
+    expect(response.status).toBe(200);
+    expect(response.body).toEqual({ status: 'ok' });
+  });
+
+  it('creates items', async () => {
+    const response = await request(app).post('/api/items').send({ name: 'First' });
+    expect(response.status).toBe(201);
+    expect(response.body).toMatchObject({ name: 'First' });
+
+    const list = await request(app).get('/api/items');
+    expect(list.body).toHaveLength(1);
+  });
+
+  it('rejects items without a name', async () => {
+    const response = await request(app).post('/api/items').send({});
+    expect(response.status).toBe(400);
+  });
+});
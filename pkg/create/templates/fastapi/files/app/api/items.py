@@ -0,0 +1,26 @@
+from fastapi import APIRouter, HTTPException
+from typing import List, Optional
+
+from app.schemas.item import Item, ItemCreate
+
+router = APIRouter()
+
+# Mock database
+items_db = {}
+
+@router.get("/items/", response_model=List[Item])
+async def read_items(skip: int = 0, limit: int = 100):
+    return list(items_db.values())[skip : skip + limit]
+
+@router.post("/items/", response_model=Item)
+async def create_item(item: ItemCreate):
+    item_id = len(items_db) + 1
+    db_item = Item(id=item_id, **item.dict())
+    items_db[item_id] = db_item
+    return db_item
+
+@router.get("/items/{item_id}", response_model=Item)
+async def read_item(item_id: int):
+    if item_id not in items_db:
+        raise HTTPException(status_code=404, detail="Item not found")
+    return items_db[item_id]
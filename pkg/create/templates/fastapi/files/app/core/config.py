@@ -0,0 +1,15 @@
+from pydantic import BaseSettings
+import os
+
+class Settings(BaseSettings):
+    API_V1_STR: str = "/api/v1"
+    PROJECT_NAME: str = "FastAPI App"
+
+    # CORS
+    BACKEND_CORS_ORIGINS: list = ["*"]
+
+    class Config:
+        case_sensitive = True
+        env_file = ".env"
+
+settings = Settings()
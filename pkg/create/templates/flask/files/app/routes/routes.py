@@ -0,0 +1,15 @@
+from flask import render_template, jsonify
+from app.routes import main_bp
+
+@main_bp.route('/')
+def index():
+    return render_template('index.html', title='Home')
+
+@main_bp.route('/api/users')
+def get_users():
+    return jsonify({
+        'users': [
+            {'id': 1, 'username': 'user1'},
+            {'id': 2, 'username': 'user2'}
+        ]
+    })
@@ -0,0 +1,2 @@
+// Main JavaScript file
+console.log('Flask app loaded');
@@ -0,0 +1,5 @@
+class CounterModel {
+  int count;
+  
+  CounterModel({this.count = 0});
+}
@@ -0,0 +1,18 @@
+import 'package:flutter/foundation.dart';
+import '../models/counter_model.dart';
+
+class CounterViewModel with ChangeNotifier {
+  final CounterModel _model = CounterModel();
+  
+  int get count => _model.count;
+  
+  void increment() {
+    _model.count++;
+    notifyListeners();
+  }
+  
+  void decrement() {
+    _model.count--;
+    notifyListeners();
+  }
+}
@@ -0,0 +1,22 @@
+import React from 'react';
+
+/**
+ * Button component with variants
+ */
+export default function Button({ children, variant = 'primary', onClick }) {
+  const baseStyles = 'px-4 py-2 rounded font-medium focus:outline-none focus:ring-2 focus:ring-offset-2';
+
+  const variantStyles = {
+    primary: 'bg-blue-600 text-white hover:bg-blue-700 focus:ring-blue-500',
+    secondary: 'bg-gray-200 text-gray-800 hover:bg-gray-300 focus:ring-gray-500',
+    danger: 'bg-red-600 text-white hover:bg-red-700 focus:ring-red-500',
+  };
+
+  const styles = baseStyles + ' ' + (variantStyles[variant] || variantStyles.primary);
+
+  return (
+    <button className={styles} onClick={onClick}>
+      {children}
+    </button>
+  );
+}
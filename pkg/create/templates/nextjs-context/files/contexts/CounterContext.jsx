@@ -0,0 +1,35 @@
+import React, { createContext, useContext, useState } from 'react';
+
+// Create the context
+const CounterContext = createContext();
+
+// Create a provider component
+export function CounterProvider({ children }) {
+  const [count, setCount] = useState(0);
+
+  const increment = () => setCount(count + 1);
+  const decrement = () => setCount(count - 1);
+  const reset = () => setCount(0);
+
+  const value = {
+    count,
+    increment,
+    decrement,
+    reset,
+  };
+
+  return (
+    <CounterContext.Provider value={value}>
+      {children}
+    </CounterContext.Provider>
+  );
+}
+
+// Create a custom hook for using the context
+export function useCounter() {
+  const context = useContext(CounterContext);
+  if (context === undefined) {
+    throw new Error('useCounter must be used within a CounterProvider');
+  }
+  return context;
+}
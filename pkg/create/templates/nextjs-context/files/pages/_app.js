@@ -0,0 +1,12 @@
+import { CounterProvider } from '../contexts/CounterContext';
+import '../styles/globals.css';
+
+function MyApp({ Component, pageProps }) {
+  return (
+    <CounterProvider>
+      <Component {...pageProps} />
+    </CounterProvider>
+  );
+}
+
+export default MyApp;
@@ -0,0 +1,12 @@
+import create from 'zustand';
+
+// Create a store with Zustand
+const useCounterStore = create((set) => ({
+  count: 0,
+  increment: () => set((state) => ({ count: state.count + 1 })),
+  decrement: () => set((state) => ({ count: state.count - 1 })),
+  reset: () => set({ count: 0 }),
+  incrementByAmount: (amount) => set((state) => ({ count: state.count + amount })),
+}));
+
+export default useCounterStore;
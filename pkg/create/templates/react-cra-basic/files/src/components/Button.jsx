@@ -0,0 +1,20 @@
+import React from 'react';
+import './Button.css';
+
+/**
+ * Button component with variants
+ */
+function Button({ children, variant = 'primary', onClick }) {
+  const getButtonClass = () => {
+    const baseClass = 'button';
+    return variant ? baseClass + ' ' + baseClass + '--' + variant : baseClass;
+  };
+
+  return (
+    <button className={getButtonClass()} onClick={onClick}>
+      {children}
+    </button>
+  );
+}
+
+export default Button;
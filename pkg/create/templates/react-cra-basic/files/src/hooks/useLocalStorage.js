@@ -0,0 +1,53 @@
+import { useState, useEffect } from 'react';
+
+/**
+ * Custom hook for using localStorage with React state
+ * @param {string} key - The localStorage key
+ * @param {any} initialValue - The initial value
+ * @returns {Array} [storedValue, setValue]
+ */
+function useLocalStorage(key, initialValue) {
+  // Get from local storage then parse stored json or return initialValue
+  const readValue = () => {
+    if (typeof window === 'undefined') {
+      return initialValue;
+    }
+
+    try {
+      const item = window.localStorage.getItem(key);
+      return item ? JSON.parse(item) : initialValue;
+    } catch (error) {
+      console.warn("Error reading localStorage key '" + key + "':", error);
+      return initialValue;
+    }
+  };
+
+  // State to store our value
+  const [storedValue, setStoredValue] = useState(readValue);
+
+  // Return a wrapped version of useState's setter function that persists the new value to localStorage
+  const setValue = (value) => {
+    try {
+      // Allow value to be a function so we have same API as useState
+      const valueToStore = value instanceof Function ? value(storedValue) : value;
+
+      // Save state
+      setStoredValue(valueToStore);
+
+      // Save to local storage
+      if (typeof window !== 'undefined') {
+        window.localStorage.setItem(key, JSON.stringify(valueToStore));
+      }
+    } catch (error) {
+      console.warn("Error setting localStorage key '" + key + "':", error);
+    }
+  };
+
+  useEffect(() => {
+    setStoredValue(readValue());
+  }, []);
+
+  return [storedValue, setValue];
+}
+
+export default useLocalStorage;
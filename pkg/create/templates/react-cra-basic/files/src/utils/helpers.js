@@ -0,0 +1,24 @@
+/**
+ * Format a date string
+ * @param {string} dateString - The date string to format
+ * @returns {string} Formatted date string
+ */
+export function formatDate(dateString) {
+  const date = new Date(dateString);
+  return new Intl.DateTimeFormat('en-US', {
+    year: 'numeric',
+    month: 'long',
+    day: 'numeric',
+  }).format(date);
+}
+
+/**
+ * Truncate text to a specific length
+ * @param {string} text - The text to truncate
+ * @param {number} length - Maximum length
+ * @returns {string} Truncated text
+ */
+export function truncateText(text, length = 100) {
+  if (text.length <= length) return text;
+  return text.slice(0, length) + '...';
+}
@@ -0,0 +1,21 @@
+import React from 'react';
+import { useCounter } from '../contexts/CounterContext';
+import './Counter.css';
+
+function Counter() {
+  const { count, increment, decrement, incrementByAmount } = useCounter();
+
+  return (
+    <div className="counter">
+      <h2>Context API Counter</h2>
+      <div className="counter-value">{count}</div>
+      <div className="counter-buttons">
+        <button onClick={decrement}>-</button>
+        <button onClick={increment}>+</button>
+        <button onClick={() => incrementByAmount(5)}>+5</button>
+      </div>
+    </div>
+  );
+}
+
+export default Counter;
@@ -0,0 +1,20 @@
+import React from 'react';
+import { observer } from 'mobx-react-lite';
+import counterStore from '../stores/counterStore';
+import './Counter.css';
+
+const Counter = observer(() => {
+  return (
+    <div className="counter">
+      <h2>MobX Counter</h2>
+      <div className="counter-value">{counterStore.count}</div>
+      <div className="counter-buttons">
+        <button onClick={() => counterStore.decrement()}>-</button>
+        <button onClick={() => counterStore.increment()}>+</button>
+        <button onClick={() => counterStore.incrementByAmount(5)}>+5</button>
+      </div>
+    </div>
+  );
+});
+
+export default Counter;
@@ -0,0 +1,30 @@
+import { makeAutoObservable } from 'mobx';
+
+class CounterStore {
+  count = 0;
+
+  constructor() {
+    makeAutoObservable(this);
+  }
+
+  increment() {
+    this.count += 1;
+  }
+
+  decrement() {
+    this.count -= 1;
+  }
+
+  incrementByAmount(amount) {
+    this.count += amount;
+  }
+
+  reset() {
+    this.count = 0;
+  }
+}
+
+// Create a singleton instance
+const counterStore = new CounterStore();
+
+export default counterStore;
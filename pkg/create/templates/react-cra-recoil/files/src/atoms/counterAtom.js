@@ -0,0 +1,6 @@
+import { atom } from 'recoil';
+
+export const counterState = atom({
+  key: 'counterState', // unique ID
+  default: 0, // default value
+});
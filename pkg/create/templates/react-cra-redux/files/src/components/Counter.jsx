@@ -0,0 +1,23 @@
+import React from 'react';
+import { useSelector, useDispatch } from 'react-redux';
+import { increment, decrement, incrementByAmount } from '../store/slices/counterSlice';
+import './Counter.css';
+
+function Counter() {
+  const count = useSelector((state) => state.counter.value);
+  const dispatch = useDispatch();
+
+  return (
+    <div className="counter">
+      <h2>Redux Counter</h2>
+      <div className="counter-value">{count}</div>
+      <div className="counter-buttons">
+        <button onClick={() => dispatch(decrement())}>-</button>
+        <button onClick={() => dispatch(increment())}>+</button>
+        <button onClick={() => dispatch(incrementByAmount(5))}>+5</button>
+      </div>
+    </div>
+  );
+}
+
+export default Counter;
@@ -0,0 +1,9 @@
+import { configureStore } from '@reduxjs/toolkit';
+import counterReducer from './slices/counterSlice';
+
+export const store = configureStore({
+  reducer: {
+    counter: counterReducer,
+    // Add more reducers here
+  },
+});
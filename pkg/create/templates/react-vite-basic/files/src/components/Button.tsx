@@ -0,0 +1,21 @@
+import type { ReactNode } from 'react';
+import './Button.css';
+
+interface ButtonProps {
+  children: ReactNode;
+  variant?: 'primary' | 'secondary' | 'danger';
+  onClick?: () => void;
+}
+
+/**
+ * Button component with variants
+ */
+function Button({ children, variant = 'primary', onClick }: ButtonProps) {
+  return (
+    <button className={'button button--' + variant} onClick={onClick}>
+      {children}
+    </button>
+  );
+}
+
+export default Button;
@@ -0,0 +1,38 @@
+import { useState } from 'react';
+
+/**
+ * Custom hook for using localStorage with React state
+ * @param key - The localStorage key
+ * @param initialValue - The initial value
+ * @returns [storedValue, setValue]
+ */
+function useLocalStorage<T>(key: string, initialValue: T) {
+  // Get from local storage then parse stored json or return initialValue
+  const [storedValue, setStoredValue] = useState<T>(() => {
+    try {
+      const item = window.localStorage.getItem(key);
+      return item ? (JSON.parse(item) as T) : initialValue;
+    } catch (error) {
+      console.warn("Error reading localStorage key '" + key + "':", error);
+      return initialValue;
+    }
+  });
+
+  // Return a wrapped version of useState's setter function that persists the new value to localStorage
+  const setValue = (value: T | ((previous: T) => T)) => {
+    try {
+      // Allow value to be a function so we have same API as useState
+      const valueToStore =
+        typeof value === 'function' ? (value as (previous: T) => T)(storedValue) : value;
+
+      setStoredValue(valueToStore);
+      window.localStorage.setItem(key, JSON.stringify(valueToStore));
+    } catch (error) {
+      console.warn("Error setting localStorage key '" + key + "':", error);
+    }
+  };
+
+  return [storedValue, setValue] as const;
+}
+
+export default useLocalStorage;
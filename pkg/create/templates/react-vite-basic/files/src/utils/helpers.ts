@@ -0,0 +1,24 @@
+/**
+ * Format a date string
+ * @param dateString - The date string to format
+ * @returns Formatted date string
+ */
+export function formatDate(dateString: string): string {
+  const date = new Date(dateString);
+  return new Intl.DateTimeFormat('en-US', {
+    year: 'numeric',
+    month: 'long',
+    day: 'numeric',
+  }).format(date);
+}
+
+/**
+ * Truncate text to a specific length
+ * @param text - The text to truncate
+ * @param length - Maximum length
+ * @returns Truncated text
+ */
+export function truncateText(text: string, length = 100): string {
+  if (text.length <= length) return text;
+  return text.slice(0, length) + '...';
+}
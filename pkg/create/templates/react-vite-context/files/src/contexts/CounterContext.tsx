@@ -0,0 +1,46 @@
+import { createContext, useContext, useState } from 'react';
+import type { ReactNode } from 'react';
+
+interface CounterContextValue {
+  count: number;
+  increment: () => void;
+  decrement: () => void;
+  reset: () => void;
+  incrementByAmount: (amount: number) => void;
+}
+
+// Create the context
+const CounterContext = createContext<CounterContextValue | undefined>(undefined);
+
+// Create a provider component
+export function CounterProvider({ children }: { children: ReactNode }) {
+  const [count, setCount] = useState(0);
+
+  const increment = () => setCount(count + 1);
+  const decrement = () => setCount(count - 1);
+  const reset = () => setCount(0);
+  const incrementByAmount = (amount: number) => setCount(count + amount);
+
+  const value = {
+    count,
+    increment,
+    decrement,
+    reset,
+    incrementByAmount,
+  };
+
+  return (
+    <CounterContext.Provider value={value}>
+      {children}
+    </CounterContext.Provider>
+  );
+}
+
+// Create a custom hook for using the context
+export function useCounter() {
+  const context = useContext(CounterContext);
+  if (context === undefined) {
+    throw new Error('useCounter must be used within a CounterProvider');
+  }
+  return context;
+}
@@ -0,0 +1,13 @@
+import { StrictMode } from 'react';
+import { createRoot } from 'react-dom/client';
+import { CounterProvider } from './contexts/CounterContext';
+import './index.css';
+import App from './App';
+
+createRoot(document.getElementById('root')!).render(
+  <StrictMode>
+    <CounterProvider>
+      <App />
+    </CounterProvider>
+  </StrictMode>,
+);
@@ -0,0 +1,25 @@
+import { useRecoilState } from 'recoil';
+import { counterState } from '../atoms/counterAtom';
+import './Counter.css';
+
+function Counter() {
+  const [count, setCount] = useRecoilState(counterState);
+
+  const increment = () => setCount(count + 1);
+  const decrement = () => setCount(count - 1);
+  const incrementByAmount = (amount: number) => setCount(count + amount);
+
+  return (
+    <div className="counter">
+      <h2>Recoil Counter</h2>
+      <div className="counter-value">{count}</div>
+      <div className="counter-buttons">
+        <button onClick={decrement}>-</button>
+        <button onClick={increment}>+</button>
+        <button onClick={() => incrementByAmount(5)}>+5</button>
+      </div>
+    </div>
+  );
+}
+
+export default Counter;
@@ -0,0 +1,12 @@
+import { configureStore } from '@reduxjs/toolkit';
+import counterReducer from './slices/counterSlice';
+
+export const store = configureStore({
+  reducer: {
+    counter: counterReducer,
+    // Add more reducers here
+  },
+});
+
+export type RootState = ReturnType<typeof store.getState>;
+export type AppDispatch = typeof store.dispatch;
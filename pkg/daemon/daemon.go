@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -9,10 +10,25 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/agnath18K/lumo/pkg/bot"
+	"github.com/agnath18K/lumo/pkg/clipboard"
 	"github.com/agnath18K/lumo/pkg/config"
 	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/notes"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/privacy"
+	"github.com/agnath18K/lumo/pkg/report"
 	"github.com/agnath18K/lumo/pkg/server"
+	"github.com/agnath18K/lumo/pkg/snippets"
+	"github.com/agnath18K/lumo/pkg/speedtest"
+	"github.com/agnath18K/lumo/pkg/system"
+	"github.com/agnath18K/lumo/pkg/timetrack"
+	"github.com/agnath18K/lumo/pkg/trigger"
+	"github.com/agnath18K/lumo/pkg/utils"
 )
 
 const (
@@ -36,24 +52,24 @@ func New(cfg *config.Config) *Daemon {
 
 // GetPidFilePath returns the path to the PID file
 func (d *Daemon) GetPidFilePath() string {
-	// Use the user's home directory for the PID file
-	homeDir, err := os.UserHomeDir()
+	// Use the state directory for the PID file
+	dir, err := paths.StateDir()
 	if err != nil {
 		// Fallback to /tmp if we can't get the home directory
 		return filepath.Join("/tmp", PidFileName)
 	}
-	return filepath.Join(homeDir, ".lumo", PidFileName)
+	return filepath.Join(dir, PidFileName)
 }
 
 // GetLogFilePath returns the path to the log file
 func (d *Daemon) GetLogFilePath() string {
-	// Use the user's home directory for the log file
-	homeDir, err := os.UserHomeDir()
+	// Use the state directory for the log file
+	dir, err := paths.StateDir()
 	if err != nil {
 		// Fallback to /tmp if we can't get the home directory
 		return filepath.Join("/tmp", LogFileName)
 	}
-	return filepath.Join(homeDir, ".lumo", LogFileName)
+	return filepath.Join(dir, LogFileName)
 }
 
 // IsRunning checks if the daemon is already running
@@ -105,11 +121,9 @@ func (d *Daemon) Start() error {
 		return fmt.Errorf("daemon is already running with PID %d", pid)
 	}
 
-	// Create the .lumo directory if it doesn't exist
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		lumoDir := filepath.Join(homeDir, ".lumo")
-		os.MkdirAll(lumoDir, 0755)
+	// Create the state directory if it doesn't exist
+	if dir, err := paths.StateDir(); err == nil {
+		os.MkdirAll(dir, 0755)
 	}
 
 	// Get the path to the current executable
@@ -125,7 +139,12 @@ func (d *Daemon) Start() error {
 	}
 
 	// Create a new command to run the server in daemon mode
-	cmd := exec.Command(execPath, "server:daemon")
+	// A configuration file chosen with --config is passed on to the daemon
+	args := []string{"server:daemon"}
+	if configFile := paths.ConfigFileOverride(); configFile != "" {
+		args = append([]string{"--config", configFile}, args...)
+	}
+	cmd := exec.Command(execPath, args...)
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 	cmd.Stdin = nil
@@ -198,9 +217,326 @@ func (d *Daemon) RunServer(exec *executor.Executor) error {
 		log.Printf("Starting Lumo server in daemon mode on port %d", d.config.ServerPort)
 	}
 
+	// A service manager starts the daemon without server:start, so the
+	// daemon records its own PID for server:status and server:stop
+	if dir, err := paths.StateDir(); err == nil {
+		os.MkdirAll(dir, 0755)
+	}
+	if err := os.WriteFile(d.GetPidFilePath(), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		log.Printf("Could not write PID file: %v", err)
+	}
+
+	// Start background tasks alongside the server
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.newScheduler().Start(ctx)
+	d.startBotBridge(ctx, exec)
+	d.startTriggers(ctx, exec)
+	d.startTimeTracking(ctx)
+	d.startSnippetExpansion(ctx)
+	d.startClipboardHistory(ctx)
+
 	// Create a new server in daemon mode
 	srv := server.NewDaemon(d.config, exec)
 
 	// Start the server (this will block in daemon mode)
 	return srv.Start()
 }
+
+// startBotBridge answers chat messages in the background if a bot is configured
+func (d *Daemon) startBotBridge(ctx context.Context, exec *executor.Executor) {
+	if d.config.BotPlatform == "" {
+		return
+	}
+	if privacy.IsStrict(d.config.PrivacyMode) {
+		log.Printf("Strict privacy mode is on, not starting the bot bridge")
+		return
+	}
+	bridge, err := bot.New(d.config, exec)
+	if err != nil {
+		log.Printf("Bot bridge not started: %v", err)
+		return
+	}
+	go bridge.Run(ctx)
+}
+
+// startTriggers runs the filesystem triggers added with 'lumo trigger add'
+// in the background
+func (d *Daemon) startTriggers(ctx context.Context, exec *executor.Executor) {
+	parser := nlp.NewParser(d.config)
+	watcher := trigger.NewWatcher(func(ctx context.Context, t *trigger.Trigger, path string) {
+		command := t.Command(path)
+		log.Printf("Trigger %s runs for %s: %s", t.Name, path, command)
+		cmd, err := parseTriggerCommand(parser, command)
+		if err != nil {
+			log.Printf("Trigger %s failed: %v", t.Name, err)
+			return
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			log.Printf("Trigger %s failed: %v", t.Name, err)
+		} else if result.IsError {
+			log.Printf("Trigger %s failed: %s", t.Name, utils.StripANSI(result.Output))
+		}
+	})
+	go watcher.Start(ctx)
+}
+
+// startTimeTracking samples the application in focus in the background for
+// 'lumo today', if the user turned time tracking on
+func (d *Daemon) startTimeTracking(ctx context.Context) {
+	if !d.config.EnableTimeTracking {
+		return
+	}
+	env, err := executor.DetectDesktopEnvironment()
+	if err != nil {
+		log.Printf("Time tracking unavailable without a desktop environment: %v", err)
+		return
+	}
+	active := func(ctx context.Context) (string, error) {
+		window, err := env.GetActiveWindow(ctx)
+		if err != nil || window == nil {
+			return "", err
+		}
+		return window.Application, nil
+	}
+	threshold := time.Duration(d.config.IdleThresholdMinutes) * time.Minute
+	tracker := timetrack.NewTracker(active, env.GetIdleTime, threshold, d.config.TimeTrackingExclude)
+	go tracker.Start(ctx)
+}
+
+// startClipboardHistory records what is copied for 'lumo clipboard
+// history', if the user turned the history on
+func (d *Daemon) startClipboardHistory(ctx context.Context) {
+	if !d.config.EnableClipboardHistory {
+		return
+	}
+	go func() {
+		err := clipboard.NewClipboard().WatchHistory(ctx, d.config.ClipboardHistoryLimit, nil)
+		if err != nil {
+			log.Printf("Clipboard history stopped: %v", err)
+		}
+	}()
+}
+
+// startSnippetExpansion expands snippet abbreviations typed anywhere on
+// the desktop, if the user turned expansion on
+func (d *Daemon) startSnippetExpansion(ctx context.Context) {
+	if !d.config.EnableSnippetExpansion {
+		return
+	}
+	env, err := executor.DetectDesktopEnvironment()
+	if err != nil {
+		log.Printf("Snippet expansion unavailable without a desktop environment: %v", err)
+		return
+	}
+	paster, err := snippets.NewPaster(env)
+	if err != nil {
+		log.Printf("Snippet expansion not started: %v", err)
+		return
+	}
+	expander := snippets.NewExpander(d.config.SnippetPrefix, func(name string) (string, bool) {
+		snippet, err := snippets.Get(name)
+		if err != nil {
+			return "", false
+		}
+		return snippet.Text, true
+	})
+	go func() {
+		err := snippets.ListenKeyboard(ctx, func(r rune) {
+			if expansion, ok := expander.Type(r); ok {
+				if err := paster.Paste(ctx, expansion); err != nil {
+					log.Printf("Snippet %s not expanded: %v", expansion.Name, err)
+				}
+			}
+		})
+		if err != nil {
+			log.Printf("Snippet expansion stopped: %v", err)
+		}
+	}()
+}
+
+// parseTriggerCommand parses a trigger's command. Triggers are added on
+// the command line, where shell: commands are allowed, so they run even
+// when interactive mode disallows them.
+func parseTriggerCommand(parser *nlp.Parser, command string) (*nlp.Command, error) {
+	if rest, ok := strings.CutPrefix(command, "shell:"); ok {
+		return &nlp.Command{
+			Type:       nlp.CommandTypeShell,
+			Intent:     strings.TrimSpace(rest),
+			RawInput:   command,
+			Parameters: make(map[string]string),
+		}, nil
+	}
+	return parser.Parse(command)
+}
+
+// newScheduler creates the scheduler for the daemon's periodic tasks
+func (d *Daemon) newScheduler() *Scheduler {
+	// Use the desktop backend to detect when the user is away
+	var idle IdleFunc
+	env, err := executor.DetectDesktopEnvironment()
+	if err == nil {
+		idle = env.GetIdleTime
+	} else {
+		log.Printf("Idle detection unavailable, noisy tasks will not be deferred: %v", err)
+	}
+
+	scheduler := NewScheduler(d.config, idle)
+
+	if d.config.ScheduledSpeedTestHours > 0 && privacy.IsStrict(d.config.PrivacyMode) {
+		log.Printf("Strict privacy mode is on, not scheduling speed tests")
+	} else if d.config.ScheduledSpeedTestHours > 0 {
+		scheduler.AddTask(&Task{
+			Name:          "speedtest",
+			Interval:      time.Duration(d.config.ScheduledSpeedTestHours) * time.Hour,
+			Run:           d.runScheduledSpeedTest,
+			SkipOnBattery: d.config.BatterySkipSpeedTests,
+		})
+	}
+
+	if d.config.ScheduledHealthCheckMinutes > 0 {
+		interval := time.Duration(d.config.ScheduledHealthCheckMinutes) * time.Minute
+		task := &Task{
+			Name:     "health",
+			Interval: interval,
+			Run:      d.runScheduledHealthCheck,
+		}
+		if d.config.BatteryHealthCheckFactor > 1 {
+			task.BatteryInterval = interval * time.Duration(d.config.BatteryHealthCheckFactor)
+		}
+		scheduler.AddTask(task)
+	}
+
+	if len(d.config.ReportRecipients) > 0 && privacy.IsStrict(d.config.PrivacyMode) {
+		log.Printf("Strict privacy mode is on, not emailing reports")
+	} else if len(d.config.ReportRecipients) > 0 {
+		// Reports are checked hourly and sent once a week, counted from the
+		// last time each was sent rather than from when the daemon started
+		scheduler.AddTask(&Task{
+			Name:     "reports",
+			Interval: time.Hour,
+			Run:      d.runScheduledReports,
+		})
+	}
+
+	// Rebuilding the notes search index reads every note, so it is noisy
+	// by default and waits for the user to go idle
+	scheduler.AddTask(&Task{
+		Name:     "indexing",
+		Interval: time.Hour,
+		Run:      d.runScheduledIndexing,
+	})
+
+	if d.config.NightLightStart != "" && d.config.NightLightEnd != "" {
+		if env == nil {
+			log.Printf("Night light schedule unavailable without a desktop environment")
+		} else {
+			nightLight := &nightLightSchedule{
+				env:         env,
+				start:       d.config.NightLightStart,
+				end:         d.config.NightLightEnd,
+				temperature: d.config.NightLightTemperature,
+			}
+			scheduler.AddTask(&Task{
+				Name:     "nightlight",
+				Interval: DefaultSchedulerTick,
+				Run:      nightLight.Run,
+			})
+		}
+	}
+
+	// Reminders set with 'lumo remind' are checked every tick
+	reminders := &reminderDelivery{}
+	if env != nil {
+		reminders.notify = func(ctx context.Context, summary, body string) error {
+			_, err := env.SendNotification(ctx, summary, body, "appointment-soon")
+			return err
+		}
+	} else {
+		log.Printf("Reminders will only be logged without a desktop environment")
+	}
+	scheduler.AddTask(&Task{
+		Name:     "reminders",
+		Interval: DefaultSchedulerTick,
+		Run:      reminders.Run,
+	})
+
+	// The network cap is checked hourly, and warned about once a month
+	if d.config.NetworkCapMB > 0 {
+		capCheck := &networkCapCheck{config: d.config, notify: reminders.notify}
+		scheduler.AddTask(&Task{
+			Name:     "network-cap",
+			Interval: time.Hour,
+			Run:      capCheck.Run,
+		})
+	}
+
+	return scheduler
+}
+
+// runScheduledSpeedTest runs a speed test and logs the result, unless the
+// network cap pauses speed tests
+func (d *Daemon) runScheduledSpeedTest(ctx context.Context) error {
+	if d.config.NetworkCapAction == config.NetworkCapPause {
+		if reached, _, err := networkCapReached(d.config, time.Now()); err == nil && reached {
+			log.Printf("Monthly network cap reached, skipping the scheduled speed test")
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(d.config.SpeedTestTimeout)*time.Second)
+	defer cancel()
+
+	tester, err := speedtest.New(d.config.SpeedTestBackend)
+	if err != nil {
+		return err
+	}
+	result, err := tester.RunTest(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Scheduled speed test (%s): %.2f Mbps down, %.2f Mbps up, %d ms latency",
+		result.Backend, result.DownloadSpeed, result.UploadSpeed, result.Latency)
+	return speedtest.RecordResult(result)
+}
+
+// runScheduledReports emails the reports that are due
+func (d *Daemon) runScheduledReports(ctx context.Context) error {
+	emailed, err := report.EmailDue(d.config, time.Now())
+	for _, name := range emailed {
+		log.Printf("Emailed the %s report to %s", name, strings.Join(d.config.ReportRecipients[name], ", "))
+	}
+	return err
+}
+
+// runScheduledIndexing rebuilds the notes search index
+func (d *Daemon) runScheduledIndexing(ctx context.Context) error {
+	count, err := notes.Index()
+	if err != nil {
+		return err
+	}
+	log.Printf("Indexed %d notes for search", count)
+	return nil
+}
+
+// runScheduledHealthCheck checks system health and notifies the on-health-alert hook
+func (d *Daemon) runScheduledHealthCheck(ctx context.Context) error {
+	health, err := system.NewHealthChecker().CheckHealth()
+	if err != nil {
+		return err
+	}
+
+	alerts := health.Alerts()
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	log.Printf("Health check: %s", health.Summary)
+	return hooks.Run(ctx, hooks.EventOnHealthAlert, map[string]interface{}{
+		"hostname": health.Hostname,
+		"summary":  health.Summary,
+		"checks":   alerts,
+	})
+}
@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// networkCapCheck tells the user once a month when the traffic counted by
+// 'lumo usage network' reaches the monthly cap
+type networkCapCheck struct {
+	config *config.Config
+	// notify is nil without a desktop, in which case the warning is only logged
+	notify NotifyFunc
+	// warned is the month the warning was last given, as YYYY-MM
+	warned string
+}
+
+// Run warns if the cap was reached and the warning was not given this month
+func (n *networkCapCheck) Run(ctx context.Context) error {
+	reached, used, err := networkCapReached(n.config, time.Now())
+	month := time.Now().Format("2006-01")
+	if err != nil || !reached || n.warned == month {
+		return err
+	}
+
+	body := fmt.Sprintf("%s of %s used this month.", formatMB(used), formatMB(n.config.NetworkCapBytes()))
+	if n.config.NetworkCapAction == config.NetworkCapPause {
+		body += " Scheduled speed tests are paused until next month."
+	}
+	log.Printf("Monthly network cap reached: %s", body)
+	if n.notify != nil {
+		if err := n.notify(ctx, "📶 Network cap reached", body); err != nil {
+			log.Printf("Network cap notification not shown: %v", err)
+		}
+	}
+	n.warned = month
+	return nil
+}
+
+// networkCapReached reports whether this month's traffic reached the cap,
+// and how much was used
+func networkCapReached(cfg *config.Config, now time.Time) (bool, int64, error) {
+	limit := cfg.NetworkCapBytes()
+	if limit <= 0 {
+		return false, 0, nil
+	}
+	used, err := usage.MonthNetwork(now)
+	if err != nil {
+		return false, 0, err
+	}
+	return used >= limit, used, nil
+}
+
+// formatMB renders a byte count in megabytes
+func formatMB(n int64) string {
+	return fmt.Sprintf("%.0f MB", float64(n)/(1<<20))
+}
@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// nightLightSchedule switches night light on and off at configured times of day
+type nightLightSchedule struct {
+	env         core.DesktopEnvironment
+	start       string
+	end         string
+	temperature int
+	// applied is the last state set by the schedule, nil until the first run
+	applied *bool
+}
+
+// Run applies the night light state for the current time. It only acts when
+// the schedule crosses a boundary, so manual changes stick until the next one.
+func (n *nightLightSchedule) Run(ctx context.Context) error {
+	want, err := InNightLightWindow(time.Now(), n.start, n.end)
+	if err != nil {
+		return err
+	}
+	if n.applied != nil && *n.applied == want {
+		return nil
+	}
+
+	if want && n.temperature > 0 {
+		if err := n.env.SetColorTemperature(ctx, n.temperature); err != nil {
+			return err
+		}
+	}
+	if err := n.env.SetNightLight(ctx, want); err != nil {
+		return err
+	}
+
+	log.Printf("Scheduled night light: on=%v", want)
+	n.applied = &want
+	return nil
+}
+
+// InNightLightWindow reports whether now falls between the start and end
+// times of day (HH:MM). Windows that cross midnight, like 21:00-06:30, are supported.
+func InNightLightWindow(now time.Time, start, end string) (bool, error) {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return false, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return false, err
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	if startMinutes <= endMinutes {
+		return current >= startMinutes && current < endMinutes, nil
+	}
+	return current >= startMinutes || current < endMinutes, nil
+}
+
+// parseClock parses a HH:MM time of day into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, use HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/calendar"
+)
+
+// NotifyFunc shows a desktop notification
+type NotifyFunc func(ctx context.Context, summary, body string) error
+
+// reminderDelivery shows reminders set with 'lumo remind' once they are due
+type reminderDelivery struct {
+	// notify is nil without a desktop, in which case reminders are only logged
+	notify NotifyFunc
+}
+
+// Run notifies every due reminder. Reminders that came due while the
+// daemon was not running are shown late, with the time they were for.
+func (r *reminderDelivery) Run(ctx context.Context) error {
+	now := time.Now()
+	due, err := calendar.Due(now)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range due {
+		body := reminder.Text
+		if now.Sub(reminder.At) > 2*DefaultSchedulerTick {
+			body += fmt.Sprintf("\n(for %s)", reminder.At.Format("Mon Jan 2 15:04"))
+		}
+		if r.notify == nil {
+			log.Printf("Reminder: %s", body)
+		} else if err := r.notify(ctx, "⏰ Reminder", body); err != nil {
+			// Tried again on the next tick
+			return fmt.Errorf("failed to show reminder %d: %w", reminder.ID, err)
+		}
+		if err := calendar.MarkNotified(reminder.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
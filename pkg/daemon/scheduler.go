@@ -0,0 +1,175 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/system"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// DefaultSchedulerTick is how often the scheduler checks for due tasks
+const DefaultSchedulerTick = time.Minute
+
+// IdleFunc reports how long the user has been idle
+type IdleFunc func(ctx context.Context) (time.Duration, error)
+
+// Task is a job the daemon runs periodically
+type Task struct {
+	// Name identifies the task and is matched against the noisy_tasks setting
+	Name string
+	// Interval is the time between runs
+	Interval time.Duration
+	// Run performs the task
+	Run func(ctx context.Context) error
+	// SkipOnBattery skips runs while the machine is conserving power
+	SkipOnBattery bool
+	// BatteryInterval replaces Interval while conserving power, if set
+	BatteryInterval time.Duration
+
+	nextRun  time.Time
+	deferred bool
+}
+
+// Scheduler runs daemon tasks, deferring noisy ones until the user is idle
+type Scheduler struct {
+	config    *config.Config
+	idle      IdleFunc
+	onBattery func() bool
+	tick      time.Duration
+	mu        sync.Mutex
+	tasks     []*Task
+}
+
+// NewScheduler creates a new scheduler. idle may be nil when no desktop
+// backend is available, in which case noisy tasks are never deferred.
+func NewScheduler(cfg *config.Config, idle IdleFunc) *Scheduler {
+	return &Scheduler{
+		config: cfg,
+		idle:   idle,
+		onBattery: func() bool {
+			return system.ShouldConservePower(cfg.PowerMode)
+		},
+		tick: DefaultSchedulerTick,
+	}
+}
+
+// AddTask registers a periodic task. The first run happens one interval from now.
+func (s *Scheduler) AddTask(task *Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task.nextRun = time.Now().Add(task.Interval)
+	s.tasks = append(s.tasks, task)
+}
+
+// Start runs the scheduler loop until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.RunDue(ctx, now)
+		}
+	}
+}
+
+// RunDue runs every task that is due at the given time
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	var due []*Task
+	for _, task := range s.tasks {
+		if !now.Before(task.nextRun) {
+			due = append(due, task)
+		}
+	}
+	s.mu.Unlock()
+
+	conserve := len(due) > 0 && s.onBattery()
+
+	for _, task := range due {
+		if conserve && task.SkipOnBattery {
+			log.Printf("Skipping %s while on battery power", task.Name)
+			s.reschedule(task, now, conserve)
+			continue
+		}
+
+		if s.shouldDefer(ctx, task, now) {
+			s.mu.Lock()
+			first := !task.deferred
+			task.deferred = true
+			s.mu.Unlock()
+			if first {
+				log.Printf("Deferring noisy task %s until the user is idle", task.Name)
+			}
+			continue
+		}
+
+		if err := task.Run(ctx); err != nil {
+			log.Printf("Scheduled task %s failed: %v", task.Name, err)
+		}
+
+		s.reschedule(task, now, conserve)
+		log.Printf("Next %s run %s", task.Name, utils.FormatWhen(task.nextRun))
+	}
+}
+
+// reschedule sets the next run time of a task after it ran or was skipped
+func (s *Scheduler) reschedule(task *Task, now time.Time, conserve bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interval := task.Interval
+	if conserve && task.BatteryInterval > 0 {
+		interval = task.BatteryInterval
+	}
+
+	task.deferred = false
+	task.nextRun = now.Add(interval)
+}
+
+// shouldDefer reports whether a due task should wait for an idle period
+func (s *Scheduler) shouldDefer(ctx context.Context, task *Task, now time.Time) bool {
+	if !s.IsNoisy(task.Name) || s.idle == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	waited, deferred := now.Sub(task.nextRun), task.deferred
+	s.mu.Unlock()
+
+	// Never hold a task back for longer than the configured bound
+	maxDelay := time.Duration(s.config.IdleMaxDelayMinutes) * time.Minute
+	if waited >= maxDelay {
+		if deferred {
+			log.Printf("Running %s after waiting %s for the user to go idle", task.Name, maxDelay)
+		}
+		return false
+	}
+
+	idleTime, err := s.idle(ctx)
+	if err != nil {
+		// Without idle information we cannot tell, so don't hold the task back
+		return false
+	}
+
+	threshold := time.Duration(s.config.IdleThresholdMinutes) * time.Minute
+	return idleTime < threshold
+}
+
+// IsNoisy reports whether a task is configured to run only while the user is idle
+func (s *Scheduler) IsNoisy(name string) bool {
+	for _, noisy := range s.config.NoisyTasks {
+		if noisy == name {
+			return true
+		}
+	}
+	return false
+}
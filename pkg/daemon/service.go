@@ -0,0 +1,231 @@
+package daemon
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+const (
+	// SystemdUnitName is the user unit 'lumo server:install' writes on Linux
+	SystemdUnitName = "lumo.service"
+	// LaunchdLabel names the launch agent 'lumo server:install' writes on macOS
+	LaunchdLabel = "io.github.agnath18k.lumo"
+)
+
+// ServicePath returns where the service definition for the daemon is
+// written on the given operating system: a systemd user unit on Linux and
+// a launch agent on macOS
+func ServicePath(goos string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch goos {
+	case "linux":
+		// Units in the configuration directory follow XDG_CONFIG_HOME
+		dir := os.Getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		return filepath.Join(dir, "systemd", "user", SystemdUnitName), nil
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", LaunchdLabel+".plist"), nil
+	default:
+		return "", fmt.Errorf("installing the daemon as a service is not supported on %s; use 'lumo server:start'", goos)
+	}
+}
+
+// SystemdUnit returns a user unit that runs the daemon and restarts it if
+// it fails, appending its output to logFile
+func SystemdUnit(command []string, logFile string) string {
+	words := make([]string, len(command))
+	for i, word := range command {
+		words[i] = systemdQuote(word)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=Lumo server daemon
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+StandardOutput=append:%s
+StandardError=append:%s
+
+[Install]
+WantedBy=default.target
+`, strings.Join(words, " "), systemdEscape(logFile), systemdEscape(logFile))
+}
+
+// LaunchdPlist returns a launch agent that runs the daemon at login and
+// restarts it if it fails, appending its output to logFile
+func LaunchdPlist(command []string, logFile string) string {
+	var args strings.Builder
+	for _, word := range command {
+		fmt.Fprintf(&args, "\t\t<string>%s</string>\n", html.EscapeString(word))
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, LaunchdLabel, args.String(), html.EscapeString(logFile), html.EscapeString(logFile))
+}
+
+// Install writes a service definition for the daemon and enables it, so the
+// service manager starts it at login and restarts it if it fails. A daemon
+// started with 'lumo server:start' is stopped first, since both would use
+// the same port.
+func (d *Daemon) Install() (string, error) {
+	path, err := ServicePath(runtime.GOOS)
+	if err != nil {
+		return "", err
+	}
+	command, err := d.daemonCommand()
+	if err != nil {
+		return "", err
+	}
+	if dir, err := paths.StateDir(); err == nil {
+		os.MkdirAll(dir, 0755)
+	}
+
+	content := SystemdUnit(command, d.GetLogFilePath())
+	if runtime.GOOS == "darwin" {
+		content = LaunchdPlist(command, d.GetLogFilePath())
+	}
+	if running, _, _ := d.IsRunning(); running {
+		if err := d.Stop(); err != nil {
+			return "", fmt.Errorf("failed to stop the running daemon: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if runtime.GOOS == "darwin" {
+		// Loading an agent that is already loaded fails, so reinstalling
+		// unloads the old one first
+		_ = runServiceManager("launchctl", "unload", path)
+		if err := runServiceManager("launchctl", "load", "-w", path); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Installed %s\nThe daemon is running and starts at every login.\nRemove it with 'lumo server:uninstall'.", path), nil
+	}
+
+	if err := runServiceManager("systemctl", "--user", "daemon-reload"); err != nil {
+		return "", err
+	}
+	if err := runServiceManager("systemctl", "--user", "enable", "--now", SystemdUnitName); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Installed %s\nThe daemon is running and starts at every login.\n"+
+		"To keep it running while you are logged out, run: loginctl enable-linger %s\n"+
+		"Remove it with 'lumo server:uninstall'.", path, os.Getenv("USER")), nil
+}
+
+// Uninstall stops the daemon's service and removes its definition
+func (d *Daemon) Uninstall() (string, error) {
+	path, err := ServicePath(runtime.GOOS)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("no service is installed at %s", path)
+	}
+
+	if runtime.GOOS == "darwin" {
+		if err := runServiceManager("launchctl", "unload", "-w", path); err != nil {
+			return "", err
+		}
+	} else if err := runServiceManager("systemctl", "--user", "disable", "--now", SystemdUnitName); err != nil {
+		return "", err
+	}
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	if runtime.GOOS != "darwin" {
+		_ = runServiceManager("systemctl", "--user", "daemon-reload")
+	}
+	os.Remove(d.GetPidFilePath())
+	return fmt.Sprintf("Removed %s\nThe daemon is stopped and no longer starts at login.", path), nil
+}
+
+// daemonCommand returns the command line that runs this executable as the
+// daemon, passing on a configuration file chosen with --config
+func (d *Daemon) daemonCommand() ([]string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executable path: %w", err)
+	}
+	// Symlinks are kept rather than resolved, so a service installed
+	// through one follows it to the next version after an upgrade
+	if abs, err := filepath.Abs(execPath); err == nil {
+		execPath = abs
+	}
+	command := []string{execPath}
+	if configFile := paths.ConfigFileOverride(); configFile != "" {
+		if abs, err := filepath.Abs(configFile); err == nil {
+			configFile = abs
+		}
+		command = append(command, "--config", configFile)
+	}
+	return append(command, "server:daemon"), nil
+}
+
+// runServiceManager runs systemctl or launchctl, returning its output with
+// any error
+func runServiceManager(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), message)
+	}
+	return nil
+}
+
+// systemdQuote quotes a word of ExecStart when it needs it
+func systemdQuote(word string) string {
+	word = systemdEscape(word)
+	if word != "" && !strings.ContainsAny(word, " \t\"'\\;") {
+		return word
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+}
+
+// systemdEscape escapes the specifiers systemd expands in unit settings
+func systemdEscape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
@@ -0,0 +1,69 @@
+// Package deprecation keeps track of command prefixes that were renamed or
+// retired. Deprecated prefixes keep working for a while, rewritten to their
+// replacement with a warning; retired ones fail. Each has a stable code, such
+// as LUMO-D001, that scripts and the REST API can match on instead of the
+// wording of the warning.
+package deprecation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefix is a command prefix that was renamed or retired
+type Prefix struct {
+	// Code identifies the deprecation in warnings and API responses
+	Code string
+	// Old is the prefix that is going away, e.g. "lumo:"
+	Old string
+	// New is the prefix to use instead
+	New string
+	// Since is the version that deprecated Old
+	Since string
+	// Retired is set once Old no longer works
+	Retired bool
+}
+
+// Prefixes are the renamed and retired command prefixes
+var Prefixes = []Prefix{
+	{Code: "LUMO-D001", Old: "lumo:", New: "ask:", Since: "1.0.2"},
+}
+
+// Lookup returns the deprecation for a command that starts with a renamed
+// or retired prefix
+func Lookup(command string) (Prefix, bool) {
+	command = strings.TrimSpace(command)
+	for _, prefix := range Prefixes {
+		if strings.HasPrefix(command, prefix.Old) {
+			return prefix, true
+		}
+	}
+	return Prefix{}, false
+}
+
+// Apply replaces the old prefix of a command with the new one
+func (p Prefix) Apply(command string) string {
+	return p.New + strings.TrimPrefix(strings.TrimSpace(command), p.Old)
+}
+
+// Warning describes the deprecation on one line that starts with its code,
+// e.g. `LUMO-D001: "lumo:" is deprecated since 1.0.2; use "ask:" instead`
+func (p Prefix) Warning() string {
+	if p.Retired {
+		return fmt.Sprintf("%s: %q was removed; use %q instead", p.Code, p.Old, p.New)
+	}
+	return fmt.Sprintf("%s: %q is deprecated since %s; use %q instead", p.Code, p.Old, p.Since, p.New)
+}
+
+// Rewrite returns a command with a deprecated prefix replaced, and the
+// deprecation that applied, if any. A retired prefix is an error.
+func Rewrite(command string) (string, *Prefix, error) {
+	prefix, ok := Lookup(command)
+	if !ok {
+		return command, nil, nil
+	}
+	if prefix.Retired {
+		return command, &prefix, fmt.Errorf("%s", prefix.Warning())
+	}
+	return prefix.Apply(command), &prefix, nil
+}
@@ -0,0 +1,383 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// BroadcastPort is the UDP port advertisers listen on for discovery queries
+	BroadcastPort = 7530
+	// broadcastMagic starts every discovery packet
+	broadcastMagic = "LUMO1"
+	// broadcastBrowseTimeout is how long a browse waits for replies
+	broadcastBrowseTimeout = 3 * time.Second
+	// maxMessageAge is how old a signed message may be before it is rejected as a replay
+	maxMessageAge = 30 * time.Second
+	// maxPacketSize bounds the size of a discovery packet
+	maxPacketSize = 8192
+)
+
+// Message kinds of the broadcast protocol
+const (
+	// MessageQuery asks advertisers of a service type to announce themselves
+	MessageQuery = "query"
+	// MessageAnnounce describes an advertised service
+	MessageAnnounce = "announce"
+)
+
+// Message is a broadcast discovery message. Packets carry the message as
+// JSON together with an HMAC-SHA256 signature made with a shared secret, so
+// only peers that know the secret can announce services.
+type Message struct {
+	Kind string            `json:"kind"`
+	Type string            `json:"type"`
+	Name string            `json:"name,omitempty"`
+	Host string            `json:"host,omitempty"`
+	Port int               `json:"port,omitempty"`
+	Info map[string]string `json:"info,omitempty"`
+	// Time is when the message was sent, in Unix seconds
+	Time int64 `json:"time"`
+}
+
+// EncodeMessage signs a message and returns the packet to send
+func EncodeMessage(secret []byte, msg Message) ([]byte, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode discovery message: %w", err)
+	}
+
+	var packet bytes.Buffer
+	packet.WriteString(broadcastMagic)
+	packet.WriteByte(' ')
+	packet.WriteString(hex.EncodeToString(sign(secret, payload)))
+	packet.WriteByte('\n')
+	packet.Write(payload)
+	return packet.Bytes(), nil
+}
+
+// DecodeMessage verifies a packet's signature and age and returns its message
+func DecodeMessage(secret []byte, packet []byte, now time.Time) (*Message, error) {
+	header, payload, found := bytes.Cut(packet, []byte("\n"))
+	if !found {
+		return nil, errors.New("malformed discovery packet")
+	}
+	magic, signature, found := bytes.Cut(header, []byte(" "))
+	if !found || string(magic) != broadcastMagic {
+		return nil, errors.New("not a lumo discovery packet")
+	}
+
+	mac, err := hex.DecodeString(string(signature))
+	if err != nil || !hmac.Equal(mac, sign(secret, payload)) {
+		return nil, errors.New("invalid discovery packet signature")
+	}
+
+	var msg Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return nil, fmt.Errorf("malformed discovery message: %w", err)
+	}
+
+	age := now.Sub(time.Unix(msg.Time, 0))
+	if age > maxMessageAge || age < -maxMessageAge {
+		return nil, errors.New("stale discovery message")
+	}
+	return &msg, nil
+}
+
+// sign returns the HMAC-SHA256 of a payload
+func sign(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// BroadcastDiscoverer implements the Discoverer interface with signed UDP
+// broadcasts, for networks that filter mDNS traffic
+type BroadcastDiscoverer struct {
+	secret       []byte
+	port         int
+	hideIdentity bool
+
+	advertMutex sync.Mutex
+	advertConn  net.PacketConn
+	advert      *Message
+
+	entries      map[string]Service
+	entriesMutex sync.RWMutex
+	callbacks    []func(Service)
+	callbackMux  sync.RWMutex
+}
+
+// NewBroadcastDiscoverer creates a BroadcastDiscoverer that signs and
+// verifies messages with the given shared secret
+func NewBroadcastDiscoverer(secret string) (*BroadcastDiscoverer, error) {
+	if secret == "" {
+		return nil, errors.New("broadcast discovery needs a shared secret; set one with 'lumo config:discovery secret generate'")
+	}
+	return &BroadcastDiscoverer{
+		secret:    []byte(secret),
+		port:      BroadcastPort,
+		entries:   make(map[string]Service),
+		callbacks: make([]func(Service), 0),
+	}, nil
+}
+
+// Start starts the discovery service
+func (d *BroadcastDiscoverer) Start(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		d.browseServices(ctx, ServiceName)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.browseServices(ctx, ServiceName)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the discovery service
+func (d *BroadcastDiscoverer) Stop() error {
+	return d.StopAdvertising()
+}
+
+// Advertise answers queries for the given service type until StopAdvertising is called
+func (d *BroadcastDiscoverer) Advertise(ctx context.Context, serviceType, name string, port int, info map[string]string) error {
+	d.StopAdvertising()
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	// A hidden identity leaves the host name and user details out
+	if d.hideIdentity {
+		hostname = ""
+		info = withoutIdentity(info)
+	}
+
+	// Several lumo processes on one machine share the port
+	config := net.ListenConfig{Control: reuseAddr}
+	conn, err := config.ListenPacket(ctx, "udp4", fmt.Sprintf(":%d", d.port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for discovery queries: %w", err)
+	}
+
+	d.advertMutex.Lock()
+	d.advertConn = conn
+	d.advert = &Message{
+		Kind: MessageAnnounce,
+		Type: serviceType,
+		Name: name,
+		Host: hostname,
+		Port: port,
+		Info: info,
+	}
+	d.advertMutex.Unlock()
+
+	go d.answerQueries(conn)
+	return nil
+}
+
+// StopAdvertising stops answering queries
+func (d *BroadcastDiscoverer) StopAdvertising() error {
+	d.advertMutex.Lock()
+	defer d.advertMutex.Unlock()
+
+	if d.advertConn != nil {
+		d.advertConn.Close()
+		d.advertConn = nil
+		d.advert = nil
+	}
+	return nil
+}
+
+// answerQueries replies to valid queries for the advertised service type
+func (d *BroadcastDiscoverer) answerQueries(conn net.PacketConn) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			// The connection was closed by StopAdvertising
+			return
+		}
+
+		query, err := DecodeMessage(d.secret, buf[:n], time.Now())
+		if err != nil || query.Kind != MessageQuery {
+			continue
+		}
+
+		d.advertMutex.Lock()
+		advert := d.advert
+		d.advertMutex.Unlock()
+		if advert == nil || query.Type != advert.Type {
+			continue
+		}
+
+		reply := *advert
+		reply.Time = time.Now().Unix()
+		packet, err := EncodeMessage(d.secret, reply)
+		if err != nil {
+			continue
+		}
+		conn.WriteTo(packet, addr)
+	}
+}
+
+// Browse returns a list of discovered services of the given type
+func (d *BroadcastDiscoverer) Browse(ctx context.Context, serviceType string) ([]Service, error) {
+	if err := d.browseServices(ctx, serviceType); err != nil {
+		return nil, err
+	}
+
+	d.entriesMutex.RLock()
+	defer d.entriesMutex.RUnlock()
+
+	services := make([]Service, 0, len(d.entries))
+	for _, service := range d.entries {
+		if service.Type == serviceType {
+			services = append(services, service)
+		}
+	}
+
+	return services, nil
+}
+
+// AddServiceCallback adds a callback function that is called when a service is discovered
+func (d *BroadcastDiscoverer) AddServiceCallback(callback func(Service)) {
+	d.callbackMux.Lock()
+	defer d.callbackMux.Unlock()
+	d.callbacks = append(d.callbacks, callback)
+}
+
+// browseServices broadcasts a query and collects the announcements that answer it
+func (d *BroadcastDiscoverer) browseServices(ctx context.Context, serviceType string) error {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return fmt.Errorf("failed to open discovery socket: %w", err)
+	}
+	defer conn.Close()
+
+	query, err := EncodeMessage(d.secret, Message{Kind: MessageQuery, Type: serviceType, Time: time.Now().Unix()})
+	if err != nil {
+		return err
+	}
+
+	sent := false
+	for _, addr := range broadcastAddresses() {
+		if _, err := conn.WriteTo(query, &net.UDPAddr{IP: addr, Port: d.port}); err == nil {
+			sent = true
+		}
+	}
+	if !sent {
+		return errors.New("failed to send a discovery broadcast on any interface")
+	}
+
+	deadline := time.Now().Add(broadcastBrowseTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetReadDeadline(deadline)
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			// The deadline ends the browse
+			return nil
+		}
+
+		msg, err := DecodeMessage(d.secret, buf[:n], time.Now())
+		if err != nil {
+			log.Printf("Ignoring discovery reply from %s: %v", addr, err)
+			continue
+		}
+		if msg.Kind != MessageAnnounce || msg.Type != serviceType {
+			continue
+		}
+
+		// Trust the packet's source address over anything the payload claims
+		ip := addr.String()
+		if udpAddr, ok := addr.(*net.UDPAddr); ok {
+			ip = udpAddr.IP.String()
+		}
+		service := Service{
+			ID:       fmt.Sprintf("%s.%s@%s", msg.Name, msg.Type, ip),
+			Type:     msg.Type,
+			Name:     msg.Host,
+			Host:     msg.Host,
+			IP:       ip,
+			Port:     msg.Port,
+			Info:     msg.Info,
+			LastSeen: time.Now(),
+		}
+		if service.Info == nil {
+			service.Info = make(map[string]string)
+		}
+
+		d.entriesMutex.Lock()
+		d.entries[service.ID] = service
+		d.entriesMutex.Unlock()
+
+		d.callbackMux.RLock()
+		for _, callback := range d.callbacks {
+			callback(service)
+		}
+		d.callbackMux.RUnlock()
+	}
+}
+
+// broadcastAddresses returns the limited broadcast address and the directed
+// broadcast address of every IPv4 network this machine is on
+func broadcastAddresses() []net.IP {
+	addresses := []net.IP{net.IPv4bcast}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return addresses
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagBroadcast == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			network, ok := addr.(*net.IPNet)
+			if !ok || network.IP.To4() == nil {
+				continue
+			}
+			ip := network.IP.To4()
+			mask := net.IP(network.Mask).To4()
+			if mask == nil {
+				continue
+			}
+			broadcast := make(net.IP, net.IPv4len)
+			for i := range broadcast {
+				broadcast[i] = ip[i] | ^mask[i]
+			}
+			addresses = append(addresses, broadcast)
+		}
+	}
+	return addresses
+}
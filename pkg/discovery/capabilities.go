@@ -0,0 +1,205 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InstanceServiceName is the service type advertised by lumo daemons running the REST server
+const InstanceServiceName = "_lumo._tcp"
+
+// TXT record keys shared by every lumo advertisement
+const (
+	// InfoVersion is the lumo version of the advertising instance
+	InfoVersion = "version"
+	// InfoCapabilities is a comma-separated list of capabilities
+	InfoCapabilities = "caps"
+	// InfoHostname is the hostname of the advertising machine
+	InfoHostname = "hostname"
+)
+
+// Capabilities a lumo instance can advertise
+const (
+	// CapabilityServerAPI means the instance serves the REST API
+	CapabilityServerAPI = "server-api"
+	// CapabilityConnectReceiver means the instance accepts files over lumo connect
+	CapabilityConnectReceiver = "connect-receiver"
+	// CapabilityFleetManaged means the instance is managed as part of a fleet
+	CapabilityFleetManaged = "fleet-managed"
+)
+
+// capabilityDescriptions explain what each capability lets you do
+var capabilityDescriptions = map[string]string{
+	CapabilityServerAPI:       "REST API",
+	CapabilityConnectReceiver: "receives files",
+	CapabilityFleetManaged:    "fleet-managed",
+}
+
+// AdvertisedInfo builds the TXT record fields for an advertisement
+func AdvertisedInfo(version string, capabilities []string, extra map[string]string) map[string]string {
+	info := make(map[string]string, len(extra)+2)
+	for k, v := range extra {
+		info[k] = v
+	}
+	info[InfoVersion] = version
+	info[InfoCapabilities] = strings.Join(capabilities, ",")
+	return info
+}
+
+// Version returns the lumo version the service advertised, if any
+func (s Service) Version() string {
+	return s.Info[InfoVersion]
+}
+
+// Capabilities returns the capabilities the service advertised. Connect
+// receivers from versions that did not advertise capabilities are still
+// recognized by their service type.
+func (s Service) Capabilities() []string {
+	var capabilities []string
+	for _, capability := range strings.Split(s.Info[InfoCapabilities], ",") {
+		if capability = strings.TrimSpace(capability); capability != "" {
+			capabilities = append(capabilities, capability)
+		}
+	}
+	if len(capabilities) == 0 && s.Type == ServiceName {
+		capabilities = []string{CapabilityConnectReceiver}
+	}
+	return capabilities
+}
+
+// HasCapability reports whether the service advertised a capability
+func (s Service) HasCapability(capability string) bool {
+	for _, c := range s.Capabilities() {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoint is a port on an instance and the capabilities served on it
+type Endpoint struct {
+	Port         int
+	Capabilities []string
+}
+
+// Instance is a lumo installation on the network, combining everything it advertises
+type Instance struct {
+	Hostname  string
+	IP        string
+	Version   string
+	Endpoints []Endpoint
+}
+
+// Capabilities returns every capability of the instance, sorted
+func (i Instance) Capabilities() []string {
+	seen := make(map[string]bool)
+	var capabilities []string
+	for _, endpoint := range i.Endpoints {
+		for _, capability := range endpoint.Capabilities {
+			if !seen[capability] {
+				seen[capability] = true
+				capabilities = append(capabilities, capability)
+			}
+		}
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// Actions describes what you can do with the instance from this machine
+func (i Instance) Actions() []string {
+	var actions []string
+	for _, endpoint := range i.Endpoints {
+		for _, capability := range endpoint.Capabilities {
+			switch capability {
+			case CapabilityServerAPI:
+				actions = append(actions, fmt.Sprintf("Use the REST API at http://%s:%d/api/v1", i.IP, endpoint.Port))
+			case CapabilityConnectReceiver:
+				actions = append(actions, fmt.Sprintf("Send files with: lumo connect %s --port %d", i.IP, endpoint.Port))
+			}
+		}
+	}
+	return actions
+}
+
+// DescribeCapability returns a short human-readable label for a capability
+func DescribeCapability(capability string) string {
+	if description, ok := capabilityDescriptions[capability]; ok {
+		return description
+	}
+	return capability
+}
+
+// GroupInstances merges services advertised by the same machine into
+// instances, sorted by hostname and IP
+func GroupInstances(services []Service) []Instance {
+	byIP := make(map[string]*Instance)
+	var order []string
+	for _, service := range services {
+		key := service.IP
+		if key == "" || key == "<nil>" {
+			key = service.Host
+		}
+
+		instance, ok := byIP[key]
+		if !ok {
+			instance = &Instance{IP: service.IP}
+			byIP[key] = instance
+			order = append(order, key)
+		}
+		if instance.Hostname == "" {
+			instance.Hostname = service.Info[InfoHostname]
+			if instance.Hostname == "" {
+				instance.Hostname = strings.TrimSuffix(service.Host, ".")
+			}
+		}
+		if version := service.Version(); version != "" && instance.Version == "" {
+			instance.Version = version
+		}
+
+		endpoint := Endpoint{Port: service.Port, Capabilities: service.Capabilities()}
+		merged := false
+		for j := range instance.Endpoints {
+			if instance.Endpoints[j].Port == endpoint.Port {
+				instance.Endpoints[j].Capabilities = appendMissing(instance.Endpoints[j].Capabilities, endpoint.Capabilities)
+				merged = true
+			}
+		}
+		if !merged {
+			instance.Endpoints = append(instance.Endpoints, endpoint)
+		}
+	}
+
+	instances := make([]Instance, 0, len(order))
+	for _, key := range order {
+		instance := byIP[key]
+		sort.Slice(instance.Endpoints, func(a, b int) bool { return instance.Endpoints[a].Port < instance.Endpoints[b].Port })
+		instances = append(instances, *instance)
+	}
+	sort.Slice(instances, func(a, b int) bool {
+		if instances[a].Hostname != instances[b].Hostname {
+			return instances[a].Hostname < instances[b].Hostname
+		}
+		return instances[a].IP < instances[b].IP
+	})
+	return instances
+}
+
+// appendMissing appends the values not already in list
+func appendMissing(list, values []string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
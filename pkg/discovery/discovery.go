@@ -2,13 +2,30 @@ package discovery
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// Discovery transports
+const (
+	// TransportMDNS discovers peers with mDNS (Avahi/Bonjour)
+	TransportMDNS = "mdns"
+	// TransportBroadcast discovers peers with signed UDP broadcasts
+	TransportBroadcast = "broadcast"
+	// TransportBoth uses mDNS and UDP broadcasts together
+	TransportBoth = "both"
+)
+
+// Transports lists the supported discovery transports
+var Transports = []string{TransportMDNS, TransportBroadcast, TransportBoth}
+
 // Service represents a discovered service
 type Service struct {
 	// ID is a unique identifier for the service
 	ID string
+	// Type is the service type the service was found under, e.g. ServiceName
+	Type string
 	// Name is the human-readable name of the service
 	Name string
 	// Host is the hostname of the service
@@ -29,18 +46,56 @@ type Discoverer interface {
 	Start(ctx context.Context) error
 	// Stop stops the discovery service
 	Stop() error
-	// Advertise advertises a service
-	Advertise(ctx context.Context, name string, port int, info map[string]string) error
+	// Advertise advertises a service of the given type
+	Advertise(ctx context.Context, serviceType, name string, port int, info map[string]string) error
 	// StopAdvertising stops advertising a service
 	StopAdvertising() error
-	// Browse returns a list of discovered services
+	// Browse returns a list of discovered services of the given type
 	Browse(ctx context.Context, serviceType string) ([]Service, error)
 	// AddServiceCallback adds a callback function that is called when a service is discovered
 	AddServiceCallback(callback func(Service))
 }
 
-// NewDiscoverer creates a new discoverer based on the available implementation
+// NewDiscoverer creates a new mDNS discoverer
 func NewDiscoverer() Discoverer {
-	// Currently we only support mDNS
 	return NewMDNSDiscoverer()
 }
+
+// New creates a discoverer with the given options. The broadcast
+// transport signs its messages with the shared secret.
+func New(opts Options) (Discoverer, error) {
+	transport := opts.Transport
+	if opts.RequireAuth {
+		if opts.Secret == "" {
+			return nil, errors.New("authenticated discovery needs a shared secret; set one with 'lumo config:discovery secret generate'")
+		}
+		transport = TransportBroadcast
+	}
+
+	var discoverer Discoverer
+	switch transport {
+	case TransportMDNS, "":
+		multicast := NewMDNSDiscoverer()
+		multicast.hideIdentity = opts.HideIdentity
+		discoverer = multicast
+	case TransportBroadcast, TransportBoth:
+		broadcast, err := NewBroadcastDiscoverer(opts.Secret)
+		if err != nil {
+			return nil, err
+		}
+		broadcast.hideIdentity = opts.HideIdentity
+		discoverer = broadcast
+		if transport == TransportBoth {
+			multicast := NewMDNSDiscoverer()
+			multicast.hideIdentity = opts.HideIdentity
+			discoverer = &multiDiscoverer{discoverers: []Discoverer{multicast, broadcast}}
+		}
+	default:
+		return nil, fmt.Errorf("unknown discovery transport: %s (use mdns, broadcast, or both)", transport)
+	}
+
+	if opts.DisableAdvertising {
+		discoverer = browseOnly{discoverer}
+	}
+	return discoverer, nil
+}
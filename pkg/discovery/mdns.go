@@ -2,8 +2,11 @@ package discovery
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"strings"
 	"sync"
@@ -13,7 +16,7 @@ import (
 )
 
 const (
-	// ServiceName is the name of the service to advertise
+	// ServiceName is the service type advertised by lumo connect receivers
 	ServiceName = "_lumo-connect._tcp"
 	// ServiceDomain is the domain to advertise the service on
 	ServiceDomain = "local."
@@ -28,6 +31,7 @@ type MDNSDiscoverer struct {
 	entriesMutex sync.RWMutex
 	callbacks    []func(Service)
 	callbackMux  sync.RWMutex
+	hideIdentity bool
 }
 
 // NewMDNSDiscoverer creates a new MDNSDiscoverer
@@ -46,14 +50,14 @@ func (d *MDNSDiscoverer) Start(ctx context.Context) error {
 		defer ticker.Stop()
 
 		// Do an initial browse
-		d.browseServices(ctx)
+		d.browseServices(ctx, ServiceName)
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				d.browseServices(ctx)
+				d.browseServices(ctx, ServiceName)
 			}
 		}
 	}()
@@ -70,8 +74,8 @@ func (d *MDNSDiscoverer) Stop() error {
 	return nil
 }
 
-// Advertise advertises a service
-func (d *MDNSDiscoverer) Advertise(ctx context.Context, name string, port int, info map[string]string) error {
+// Advertise advertises a service of the given type
+func (d *MDNSDiscoverer) Advertise(ctx context.Context, serviceType, name string, port int, info map[string]string) error {
 	// Stop any existing advertisement
 	if d.server != nil {
 		d.server.Shutdown()
@@ -84,6 +88,12 @@ func (d *MDNSDiscoverer) Advertise(ctx context.Context, name string, port int, i
 		return fmt.Errorf("failed to get hostname: %w", err)
 	}
 
+	// A hidden identity advertises a random host name and no user details
+	if d.hideIdentity {
+		hostname = anonymousHostname()
+		info = withoutIdentity(info)
+	}
+
 	// Create TXT record
 	txt := make([]string, 0, len(info))
 	for k, v := range info {
@@ -92,13 +102,13 @@ func (d *MDNSDiscoverer) Advertise(ctx context.Context, name string, port int, i
 
 	// Create service
 	service, err := mdns.NewMDNSService(
-		name,          // Instance name
-		ServiceName,   // Service type
-		ServiceDomain, // Domain
-		hostname,      // Host name
-		port,          // Port
-		nil,           // IPs (nil = all interfaces)
-		txt,           // TXT records
+		name,                       // Instance name
+		serviceType,                // Service type
+		ServiceDomain,              // Domain
+		hostname+"."+ServiceDomain, // Host name (must be fully qualified)
+		port,                       // Port
+		localIPs(),                 // IPs
+		txt,                        // TXT records
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create mDNS service: %w", err)
@@ -125,16 +135,18 @@ func (d *MDNSDiscoverer) StopAdvertising() error {
 	return nil
 }
 
-// Browse returns a list of discovered services
+// Browse returns a list of discovered services of the given type
 func (d *MDNSDiscoverer) Browse(ctx context.Context, serviceType string) ([]Service, error) {
-	d.browseServices(ctx)
+	d.browseServices(ctx, serviceType)
 
 	d.entriesMutex.RLock()
 	defer d.entriesMutex.RUnlock()
 
 	services := make([]Service, 0, len(d.entries))
 	for _, service := range d.entries {
-		services = append(services, service)
+		if service.Type == serviceType {
+			services = append(services, service)
+		}
 	}
 
 	return services, nil
@@ -147,8 +159,8 @@ func (d *MDNSDiscoverer) AddServiceCallback(callback func(Service)) {
 	d.callbacks = append(d.callbacks, callback)
 }
 
-// browseServices browses for services
-func (d *MDNSDiscoverer) browseServices(ctx context.Context) {
+// browseServices browses for services of the given type
+func (d *MDNSDiscoverer) browseServices(ctx context.Context, serviceType string) {
 	// Create a channel for results
 	entriesCh := make(chan *mdns.ServiceEntry, 10)
 	go func() {
@@ -168,6 +180,7 @@ func (d *MDNSDiscoverer) browseServices(ctx context.Context) {
 			// Create service
 			service := Service{
 				ID:       entry.Name,
+				Type:     serviceType,
 				Name:     entry.Host,
 				Host:     entry.Host,
 				IP:       entry.AddrV4.String(),
@@ -191,7 +204,7 @@ func (d *MDNSDiscoverer) browseServices(ctx context.Context) {
 	}()
 
 	// Start browsing
-	params := mdns.DefaultParams(ServiceName)
+	params := mdns.DefaultParams(serviceType)
 	params.Entries = entriesCh
 	params.Timeout = 5 * time.Second
 
@@ -218,3 +231,25 @@ func (d *MDNSDiscoverer) browseServices(ctx context.Context) {
 	}
 	close(entriesCh)
 }
+
+// anonymousHostname returns a random host name that does not reveal the machine's name
+func anonymousHostname() string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return "lumo-" + hex.EncodeToString(buf)
+}
+
+// localIPs returns the machine's non-loopback IP addresses
+func localIPs() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if network, ok := addr.(*net.IPNet); ok && !network.IP.IsLoopback() && !network.IP.IsLinkLocalUnicast() {
+			ips = append(ips, network.IP)
+		}
+	}
+	return ips
+}
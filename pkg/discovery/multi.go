@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// multiDiscoverer runs several discoverers side by side and merges what they find
+type multiDiscoverer struct {
+	discoverers []Discoverer
+}
+
+// Start starts every discoverer
+func (m *multiDiscoverer) Start(ctx context.Context) error {
+	var errs []error
+	for _, d := range m.discoverers {
+		if err := d.Start(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stop stops every discoverer
+func (m *multiDiscoverer) Stop() error {
+	var errs []error
+	for _, d := range m.discoverers {
+		if err := d.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Advertise advertises the service on every transport. It only fails when
+// no transport could advertise it.
+func (m *multiDiscoverer) Advertise(ctx context.Context, serviceType, name string, port int, info map[string]string) error {
+	var errs []error
+	for _, d := range m.discoverers {
+		if err := d.Advertise(ctx, serviceType, name, port, info); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(m.discoverers) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// StopAdvertising stops advertising on every transport
+func (m *multiDiscoverer) StopAdvertising() error {
+	var errs []error
+	for _, d := range m.discoverers {
+		if err := d.StopAdvertising(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Browse browses every transport at once. A service seen on several
+// transports is listed once.
+func (m *multiDiscoverer) Browse(ctx context.Context, serviceType string) ([]Service, error) {
+	type browseResult struct {
+		services []Service
+		err      error
+	}
+	results := make(chan browseResult, len(m.discoverers))
+	for _, d := range m.discoverers {
+		go func(d Discoverer) {
+			services, err := d.Browse(ctx, serviceType)
+			results <- browseResult{services, err}
+		}(d)
+	}
+
+	seen := make(map[string]bool)
+	var services []Service
+	var errs []error
+	for range m.discoverers {
+		result := <-results
+		if result.err != nil {
+			errs = append(errs, result.err)
+			continue
+		}
+		for _, service := range result.services {
+			key := fmt.Sprintf("%s|%s|%d", service.Type, service.IP, service.Port)
+			if !seen[key] {
+				seen[key] = true
+				services = append(services, service)
+			}
+		}
+	}
+
+	if len(errs) == len(m.discoverers) {
+		return nil, errors.Join(errs...)
+	}
+	return services, nil
+}
+
+// AddServiceCallback adds the callback to every discoverer
+func (m *multiDiscoverer) AddServiceCallback(callback func(Service)) {
+	for _, d := range m.discoverers {
+		d.AddServiceCallback(callback)
+	}
+}
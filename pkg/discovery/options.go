@@ -0,0 +1,63 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/privacy"
+)
+
+// InfoUsername is the TXT record key holding the user running the advertised service
+const InfoUsername = "username"
+
+// identityKeys are TXT record keys that identify the machine or its user
+var identityKeys = []string{InfoHostname, InfoUsername}
+
+// Options configures a discoverer
+type Options struct {
+	// Transport is TransportMDNS, TransportBroadcast, or TransportBoth
+	Transport string
+	// Secret is the shared secret that signs broadcast messages
+	Secret string
+	// DisableAdvertising browses for peers without announcing this machine
+	DisableAdvertising bool
+	// HideIdentity leaves the hostname and username out of advertisements
+	HideIdentity bool
+	// RequireAuth only talks to peers that know the shared secret, which
+	// rules out unauthenticated mDNS
+	RequireAuth bool
+}
+
+// ConfigOptions returns the discovery options set in the configuration.
+// Strict privacy mode always hides the machine's identity.
+func ConfigOptions(cfg *config.Config) Options {
+	return Options{
+		Transport:          cfg.DiscoveryTransport,
+		Secret:             cfg.DiscoverySecret,
+		DisableAdvertising: !cfg.DiscoveryAdvertise,
+		HideIdentity:       cfg.DiscoveryHideIdentity || privacy.IsStrict(cfg.PrivacyMode),
+		RequireAuth:        cfg.DiscoveryRequireAuth,
+	}
+}
+
+// withoutIdentity returns a copy of info without the keys that identify the machine or its user
+func withoutIdentity(info map[string]string) map[string]string {
+	stripped := make(map[string]string, len(info))
+	for k, v := range info {
+		stripped[k] = v
+	}
+	for _, key := range identityKeys {
+		delete(stripped, key)
+	}
+	return stripped
+}
+
+// browseOnly is a discoverer that never advertises
+type browseOnly struct {
+	Discoverer
+}
+
+// Advertise does nothing because advertising is disabled
+func (b browseOnly) Advertise(ctx context.Context, serviceType, name string, port int, info map[string]string) error {
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package discovery
+
+import "syscall"
+
+// reuseAddr lets several sockets bind the discovery port and all receive its broadcasts
+func reuseAddr(network, address string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
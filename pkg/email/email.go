@@ -0,0 +1,129 @@
+// Package email sends HTML email through the SMTP server set with
+// config:report.
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// smtpTimeout bounds connecting to the server and sending one message
+const smtpTimeout = time.Minute
+
+// ValidateAddress reports whether address is a single plain email address
+func ValidateAddress(address string) error {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Address != address {
+		return fmt.Errorf("invalid email address: %s", address)
+	}
+	return nil
+}
+
+// Send emails an HTML message to the given addresses
+func Send(cfg *config.Config, to []string, subject, html string) error {
+	if cfg.SMTPServer == "" || cfg.SMTPFrom == "" {
+		return fmt.Errorf("no SMTP server is set (see 'config:report smtp')")
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+	host, port, err := net.SplitHostPort(cfg.SMTPServer)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP server %s: use host:port", cfg.SMTPServer)
+	}
+	for _, address := range append([]string{cfg.SMTPFrom}, to...) {
+		if err := ValidateAddress(address); err != nil {
+			return err
+		}
+	}
+
+	message, err := buildMessage(cfg.SMTPFrom, to, subject, html)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	if port == "465" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", cfg.SMTPServer, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", cfg.SMTPServer)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", cfg.SMTPServer, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to %s: %w", cfg.SMTPServer, err)
+	}
+	defer client.Close()
+
+	if _, isTLS := conn.(*tls.Conn); !isTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+				return fmt.Errorf("STARTTLS failed: %w", err)
+			}
+		}
+	}
+	if cfg.SMTPUsername != "" {
+		// PlainAuth refuses to send the password over an unencrypted
+		// connection to another machine
+		if err := client.Auth(smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(cfg.SMTPFrom); err != nil {
+		return fmt.Errorf("SMTP server refused the sender: %w", err)
+	}
+	for _, address := range to {
+		if err := client.Rcpt(address); err != nil {
+			return fmt.Errorf("SMTP server refused %s: %w", address, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send the message: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("failed to send the message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send the message: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage returns the headers and quoted-printable body of an HTML email
+func buildMessage(from string, to []string, subject, html string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.ReplaceAll(subject, "\n", " ")))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(&b)
+	if _, err := body.Write([]byte(html)); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -2,10 +2,122 @@ package executor
 
 import (
 	"context"
+	"fmt"
 )
 
 // AgentInterface defines the interface for agent implementations
 type AgentInterface interface {
 	// Execute processes a task and executes the necessary commands
 	Execute(ctx context.Context, taskDescription string) (*Result, error)
+	// ExecuteWithContext is Execute with background for planning, such as
+	// the chat conversation that led to the task
+	ExecuteWithContext(ctx context.Context, taskDescription, background string) (*Result, error)
+	// Analyze investigates a question using only commands that inspect the system
+	Analyze(ctx context.Context, question string) (*Result, error)
+}
+
+// planApprovedKey marks a context whose agent run was approved before planning
+type planApprovedKey struct{}
+
+// WithPlanApproved returns a context for agent runs that someone already
+// approved where the plan cannot be shown, such as over the bot bridge by
+// an allowlisted user. The agent runs such plans without asking; steps the
+// safety level asks about still need a terminal.
+func WithPlanApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, planApprovedKey{}, true)
+}
+
+// PlanApproved reports whether ctx comes from WithPlanApproved
+func PlanApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(planApprovedKey{}).(bool)
+	return approved
+}
+
+// RunAgent runs a task with the agent for callers other than the terminal,
+// returning an error if agent mode is unavailable
+func (e *Executor) RunAgent(ctx context.Context, task string) (*Result, error) {
+	if e.agent == nil {
+		return nil, fmt.Errorf("the agent is not available")
+	}
+	if e.offline() {
+		return nil, fmt.Errorf("%s needs an internet connection, and none is available", e.config.AIProvider)
+	}
+	return e.agent.Execute(ctx, task)
+}
+
+// Kinds of AgentEvent
+const (
+	// AgentEventPlan shows the plan before anything runs
+	AgentEventPlan = "plan"
+	// AgentEventConfirm asks to approve the plan, a step, or a revision
+	AgentEventConfirm = "confirm"
+	// AgentEventStepStart reports that a step is starting
+	AgentEventStepStart = "step_start"
+	// AgentEventStepOutput carries a line of a running step's output
+	AgentEventStepOutput = "step_output"
+	// AgentEventStepFinish reports how a step ended
+	AgentEventStepFinish = "step_finish"
+	// AgentEventRevision shows how the remaining steps were revised
+	AgentEventRevision = "revision"
+	// AgentEventDone reports how the run ended
+	AgentEventDone = "done"
+	// AgentEventError reports a run that could not go on
+	AgentEventError = "error"
+)
+
+// AgentEvent is something that happened in an agent run, reported to an
+// AgentObserver. Fields that do not apply to the event's type are empty.
+type AgentEvent struct {
+	Type        string      `json:"type"`
+	RunID       string      `json:"run_id,omitempty"`
+	Task        string      `json:"task,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Steps       []AgentStep `json:"steps,omitempty"`
+	Step        *AgentStep  `json:"step,omitempty"`
+	// Subject is what a confirmation is about: plan, step, or revision
+	Subject string `json:"subject,omitempty"`
+	// Reason says why a confirmation is asked for
+	Reason     string `json:"reason,omitempty"`
+	Output     string `json:"output,omitempty"`
+	Success    bool   `json:"success,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Attempts   int    `json:"attempts,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+}
+
+// AgentStep is a plan step as reported to an AgentObserver
+type AgentStep struct {
+	ID          int      `json:"id"`
+	Command     string   `json:"command"`
+	Description string   `json:"description,omitempty"`
+	Critical    bool     `json:"critical,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// AgentObserver follows an agent run for a client other than the terminal,
+// such as the web UI, and answers the questions the terminal would be asked
+type AgentObserver interface {
+	// Event reports something that happened in the run
+	Event(event AgentEvent)
+	// Confirm asks the client to approve what an AgentEventConfirm event
+	// describes, and reports whether it did. It returns false if ctx is
+	// done first.
+	Confirm(ctx context.Context, event AgentEvent) bool
+}
+
+// agentObserverKey holds the AgentObserver of a context
+type agentObserverKey struct{}
+
+// WithAgentObserver returns a context for agent runs followed by observer.
+// Such runs ask observer instead of the terminal, under the same safety
+// checks.
+func WithAgentObserver(ctx context.Context, observer AgentObserver) context.Context {
+	return context.WithValue(ctx, agentObserverKey{}, observer)
+}
+
+// AgentObserverFrom returns the observer of a context from
+// WithAgentObserver, or nil
+func AgentObserverFrom(ctx context.Context) AgentObserver {
+	observer, _ := ctx.Value(agentObserverKey{}).(AgentObserver)
+	return observer
 }
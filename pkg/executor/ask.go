@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// Ask answers a question for callers that use the answer rather than show
+// it, such as scripts. Like ask:, it applies the privacy mode, budget,
+// persona, and response cache, but the answer comes back without the box
+// or markdown clean-up.
+func (e *Executor) Ask(question, persona string) (string, error) {
+	instructions, err := e.personaInstructions(persona)
+	if err != nil {
+		return "", err
+	}
+	return e.AskWithInstructions(question, instructions)
+}
+
+// AskWithInstructions is Ask for callers that bring their own system
+// instructions instead of naming a persona, such as the server's
+// OpenAI-compatible endpoint
+func (e *Executor) AskWithInstructions(question, instructions string) (string, error) {
+	cmd := &nlp.Command{Type: nlp.CommandTypeAI, Intent: question, RawInput: "ask:" + question}
+	if result := e.enforcePrivacy(cmd); result != nil {
+		return "", errors.New(result.Output)
+	}
+	if result := e.enforceBudget(cmd); result != nil {
+		return "", errors.New(result.Output)
+	}
+
+	answer, cacheKey, cached := e.cachedAnswer(question, instructions)
+	if cached {
+		return answer, nil
+	}
+	if e.offline() {
+		return "", fmt.Errorf("%s needs an internet connection, and none is available", e.config.AIProvider)
+	}
+
+	answer, err := e.queryAs(question, instructions)
+	if err != nil {
+		return "", err
+	}
+	e.cacheAnswer(cacheKey, answer)
+	return answer, nil
+}
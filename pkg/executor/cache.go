@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+)
+
+// responseCache returns the cache for answers to ask: questions, or nil
+// when it is turned off
+func (e *Executor) responseCache() *ai.ResponseCache {
+	if !e.config.EnableResponseCache || e.config.ResponseCacheTTLMinutes <= 0 {
+		return nil
+	}
+	// Mock answers follow their fixture file, which changes as tests are written
+	if e.config.AIProvider == string(ai.ProviderMock) {
+		return nil
+	}
+	// A cassette must capture, and a replay must return, the provider's own answers
+	if ai.Recording() || ai.Replaying() {
+		return nil
+	}
+	if e.responses == nil {
+		cache, err := ai.OpenResponseCache(e.config)
+		if err != nil {
+			return nil
+		}
+		e.responses = cache
+	}
+	return e.responses
+}
+
+// cachedAnswer looks a question up in the response cache. It returns the
+// cached answer, if any, and the key to cache a new answer under, which is
+// empty when the cache is off.
+func (e *Executor) cachedAnswer(query, instructions string) (string, string, bool) {
+	cache := e.responseCache()
+	if cache == nil {
+		return "", "", false
+	}
+
+	// Cloud providers see the working directory, so answers can depend on it
+	pwd, _ := os.Getwd()
+	key := ai.CacheKey(e.aiClient, instructions, query, pwd)
+	if e.config.NoCache {
+		return "", key, false
+	}
+	answer, ok := cache.Get(key)
+	return answer, key, ok
+}
+
+// cacheAnswer keeps an answer for the next time the question is asked
+func (e *Executor) cacheAnswer(key, answer string) {
+	if key == "" || strings.TrimSpace(answer) == "" {
+		return
+	}
+	if err := e.responses.Put(key, answer); err != nil && e.config.Debug {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
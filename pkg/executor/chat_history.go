@@ -0,0 +1,199 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/chat"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// chatHistoryUsage describes the chat:history and chat:rename commands
+const chatHistoryUsage = `Usage:
+  lumo chat:history [list [<word> | #<tag>]]  List saved conversations
+  lumo chat:history show <n|id>               Show a conversation
+  lumo chat:history delete <n|id>             Delete a conversation
+  lumo chat:rename <n|id> <title>             Rename a conversation
+
+<n> is a conversation's number in the list, newest first.`
+
+// chatHistoryCommand runs chat:history and chat:rename. Other chat: input
+// is a message, so it reports whether cmd was one of them; a rename needs
+// a conversation number or ID so that messages starting with the word
+// still reach the AI.
+func (e *Executor) chatHistoryCommand(cmd *nlp.Command) (*Result, bool) {
+	args := strings.Fields(cmd.Intent)
+	switch {
+	case len(args) > 0 && args[0] == "history":
+		if len(args) > 1 && args[1] != "list" && args[1] != "show" && args[1] != "delete" {
+			return nil, false
+		}
+		return e.executeChatHistory(args[1:], cmd), true
+	case len(args) > 2 && args[0] == "rename" && isConversationRef(args[1]):
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmd.Intent), "rename"))
+		title := strings.TrimSpace(strings.TrimPrefix(rest, args[1]))
+		return e.executeChatRename(args[1], title, cmd), true
+	default:
+		return nil, false
+	}
+}
+
+// isConversationRef reports whether s names a saved conversation by its
+// number or ID
+func isConversationRef(s string) bool {
+	if _, err := strconv.Atoi(s); err == nil {
+		return true
+	}
+	return strings.HasPrefix(s, "conv_")
+}
+
+// executeChatHistory lists, shows, and deletes saved conversations
+func (e *Executor) executeChatHistory(args []string, cmd *nlp.Command) *Result {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand = args[0]
+		args = args[1:]
+	}
+
+	var output string
+	switch subcommand {
+	case "list":
+		convs, err := chat.SavedConversations()
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}
+		}
+		output = e.formatConversationList(convs, strings.Join(args, " "))
+
+	case "show", "delete":
+		if len(args) != 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing conversation number or ID\n%s", chatHistoryUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}
+		}
+		var conv *chat.Conversation
+		var err error
+		if subcommand == "show" {
+			conv, err = chat.LoadConversation(args[0])
+		} else {
+			conv, err = chat.DeleteSavedConversation(args[0])
+		}
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}
+		}
+		if subcommand == "show" {
+			output = formatConversation(conv)
+		} else {
+			output = fmt.Sprintf("Deleted conversation %s", conversationTitle(conv))
+		}
+	}
+
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}
+}
+
+// executeChatRename sets the title of a saved conversation
+func (e *Executor) executeChatRename(ref, title string, cmd *nlp.Command) *Result {
+	conv, err := chat.RenameConversation(ref, title)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v\n%s", err, chatHistoryUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}
+	}
+	return &Result{
+		Output:     fmt.Sprintf("Renamed conversation %s to %q", conv.ID, conv.Title),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}
+}
+
+// formatConversationList renders saved conversations one per line with
+// their number, keeping those whose title or tags match filter. A filter
+// starting with # only matches tags.
+func (e *Executor) formatConversationList(convs []*chat.Conversation, filter string) string {
+	if len(convs) == 0 {
+		if !e.config.SaveChatHistory {
+			return "No saved conversations. Saving them is off: lumo config:chat history on"
+		}
+		return "No saved conversations yet. Start one with: lumo chat"
+	}
+
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	var b strings.Builder
+	b.WriteString("💬 Saved conversations\n")
+	shown := 0
+	for i, conv := range convs {
+		if filter != "" && !conversationMatches(conv, filter) {
+			continue
+		}
+		shown++
+		line := fmt.Sprintf("  %2d. %-40s %s", i+1, utils.TruncateString(conversationTitle(conv), 40),
+			utils.FormatRelative(conv.UpdatedAt))
+		if len(conv.Tags) > 0 {
+			line += "  #" + strings.Join(conv.Tags, " #")
+		}
+		b.WriteString(line + "\n")
+	}
+	if shown == 0 {
+		return fmt.Sprintf("No saved conversations match %q.", filter)
+	}
+	b.WriteString("\nShow one with: lumo chat:history show <n>")
+	return b.String()
+}
+
+// conversationMatches reports whether a conversation's title or tags
+// contain filter
+func conversationMatches(conv *chat.Conversation, filter string) bool {
+	if tag, ok := strings.CutPrefix(filter, "#"); ok {
+		return conv.HasTag(tag)
+	}
+	return strings.Contains(strings.ToLower(conversationTitle(conv)), filter) || conv.HasTag(filter)
+}
+
+// conversationTitle returns a conversation's title, or the start of its
+// first question while it has none
+func conversationTitle(conv *chat.Conversation) string {
+	if conv.Title != "" {
+		return conv.Title
+	}
+	if preview := conv.Preview(); preview != "" {
+		return preview
+	}
+	return "(untitled)"
+}
+
+// formatConversation renders a saved conversation's messages
+func formatConversation(conv *chat.Conversation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "💬 %s\n", conversationTitle(conv))
+	fmt.Fprintf(&b, "   %s, updated %s", conv.ID, utils.FormatTimestamp(conv.UpdatedAt))
+	if len(conv.Tags) > 0 {
+		b.WriteString("  #" + strings.Join(conv.Tags, " #"))
+	}
+	b.WriteString("\n")
+	for _, msg := range conv.Messages {
+		switch msg.Role {
+		case chat.RoleUser:
+			fmt.Fprintf(&b, "\n🧑 You: %s\n", msg.Content)
+		case chat.RoleAssistant:
+			fmt.Fprintf(&b, "\n🐦 Lumo: %s\n", utils.CleanMarkdown(msg.Content))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/clipboard"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// clipboardHistoryUsage describes the clipboard history commands
+const clipboardHistoryUsage = `Usage:
+  lumo clipboard history          Show what was copied, newest first
+  lumo clipboard history clear    Forget everything that is not pinned
+  lumo clipboard get <n>          Copy entry n back to the clipboard
+  lumo clipboard pick             Choose an entry to copy back
+  lumo clipboard pin <n>          Keep entry n whatever the limit
+  lumo clipboard unpin <n>        Let entry n go again
+  lumo clipboard watch            Record copies until Ctrl+C`
+
+// executeClipboardHistory runs the clipboard history commands. It reports
+// false for anything else, which the clipboard copies as text; get, pin,
+// and unpin are only history commands with an entry number.
+func (e *Executor) executeClipboardHistory(cmd *nlp.Command) (*Result, bool) {
+	subcommand, rest := cutWord(cmd.Intent)
+
+	var output string
+	var err error
+	switch {
+	case subcommand == "history" && rest == "":
+		var entries []clipboard.HistoryEntry
+		if entries, err = clipboard.History(); err == nil {
+			output = e.formatClipboardHistory(entries)
+		}
+
+	case subcommand == "history" && rest == "clear":
+		var removed int
+		if removed, err = clipboard.ClearHistory(); err == nil {
+			output = fmt.Sprintf("Cleared %d clipboard history entries; pinned ones were kept", removed)
+		}
+
+	case subcommand == "watch" && rest == "":
+		output, err = e.watchClipboard()
+
+	case subcommand == "pick" && rest == "":
+		output, err = e.pickClipboard()
+
+	case subcommand == "get" || subcommand == "pin" || subcommand == "unpin":
+		n, convErr := strconv.Atoi(rest)
+		if convErr != nil {
+			return nil, false
+		}
+		var entry *clipboard.HistoryEntry
+		switch subcommand {
+		case "get":
+			if entry, err = clipboard.HistoryEntryAt(n); err == nil {
+				output, err = e.clipboard.SetContent(entry.Text)
+			}
+		case "pin":
+			if entry, err = clipboard.SetPinned(n, true); err == nil {
+				output = fmt.Sprintf("📌 Pinned: %s", previewClip(entry.Text))
+			}
+		case "unpin":
+			if entry, err = clipboard.SetPinned(n, false); err == nil {
+				output = fmt.Sprintf("Unpinned: %s", previewClip(entry.Text))
+			}
+		}
+
+	default:
+		return nil, false
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Clipboard Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, true
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, true
+}
+
+// watchClipboard records clipboard changes in the foreground until
+// interrupted, for when the daemon does not
+func (e *Executor) watchClipboard() (string, error) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	fmt.Fprintln(os.Stderr, "📋 Recording the clipboard history. Press Ctrl+C to stop.")
+	recorded := 0
+	err := e.clipboard.WatchHistory(ctx, e.config.ClipboardHistoryLimit, func(text string) {
+		recorded++
+		fmt.Fprintf(os.Stderr, "  %s  %s\n", time.Now().Format("15:04:05"), previewClip(text))
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Stopped recording; %d copies recorded", recorded), nil
+}
+
+// pickClipboard lists the history and asks on stdin which entry to copy
+// back; an empty answer takes the newest
+func (e *Executor) pickClipboard() (string, error) {
+	entries, err := clipboard.History()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return e.formatClipboardHistory(entries), nil
+	}
+
+	listing, _, _ := strings.Cut(e.formatClipboardHistory(entries), "\n\nCopy one back")
+	fmt.Println(listing)
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("\nCopy which entry? [1]: ")
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if err != nil && answer == "" {
+			return "Nothing copied", nil
+		}
+		n := 1
+		if answer != "" {
+			n, err = strconv.Atoi(answer)
+		}
+		if err == nil && n >= 1 && n <= len(entries) {
+			return e.clipboard.SetContent(entries[n-1].Text)
+		}
+		fmt.Printf("❌ Please answer a number from 1 to %d\n", len(entries))
+	}
+}
+
+// formatClipboardHistory lists the history with the numbers the other
+// commands take
+func (e *Executor) formatClipboardHistory(entries []clipboard.HistoryEntry) string {
+	if len(entries) == 0 {
+		message := "The clipboard history is empty."
+		if !e.config.EnableClipboardHistory {
+			message += "\nRecord it with: lumo config:clipboard history on\nOr for now with: lumo clipboard watch"
+		}
+		return message
+	}
+
+	var b strings.Builder
+	b.WriteString("📋 Clipboard history\n\n")
+	now := time.Now()
+	for i, entry := range entries {
+		pin := "  "
+		if entry.Pinned {
+			pin = "📌"
+		}
+		fmt.Fprintf(&b, "%3d. %s %-12s %s\n", i+1, pin, formatCopiedAt(entry.CopiedAt, now), previewClip(entry.Text))
+	}
+	b.WriteString("\nCopy one back with: lumo clipboard get <n>")
+	return b.String()
+}
+
+// formatCopiedAt says when an entry was copied, with the time for today
+// and the date before that
+func formatCopiedAt(at, now time.Time) string {
+	if y, m, d := at.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return at.Format("15:04")
+	}
+	return at.Format("Jan 2 15:04")
+}
+
+// previewClip shows copied text on one line
+func previewClip(text string) string {
+	return utils.TruncateString(strings.Join(strings.Fields(text), " "), 60)
+}
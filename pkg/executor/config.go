@@ -11,15 +11,14 @@ import (
 
 	"github.com/agnath18K/lumo/pkg/ai"
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
 	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/privacy"
 )
 
 // getCurrentModel returns the current model based on the provider
 func getCurrentModel(cfg *config.Config) string {
-	if cfg.AIProvider == "gemini" {
-		return cfg.GeminiModel
-	}
-	return cfg.OpenAIModel
+	return ai.ConfiguredModel(cfg.AIProvider, cfg)
 }
 
 // executeConfigCommand handles configuration commands
@@ -34,7 +33,7 @@ func (e *Executor) executeConfigCommand(cmd *nlp.Command) (*Result, error) {
   Commands:
    • config:provider list           List available AI providers
    • config:provider show           Show current AI provider
-   • config:provider set <provider> Set AI provider (gemini/openai/ollama)
+   • config:provider set <provider> Set AI provider (see config:provider list)
 
    • config:model list              List available models
    • config:model show              Show current model
@@ -48,13 +47,84 @@ func (e *Executor) executeConfigCommand(cmd *nlp.Command) (*Result, error) {
    • config:ollama set <url>        Set Ollama URL
    • config:ollama test             Test connection to Ollama server
 
+   • config:test [provider]         Send a tiny prompt to each AI provider
+
    • config:mode show               Show current input mode
    • config:mode ai                 Set AI-first mode (default)
    • config:mode command            Set command-first mode
+   • config:mode local on|off       Handle obvious requests without the AI
 
    • config:server show             Show current server settings
    • config:server quiet on/off     Enable/disable server log messages
 
+   • config:daemon show             Show daemon scheduler settings
+   • config:daemon idle <minutes>   Defer noisy tasks until idle
+
+   • config:power show              Show battery-saving settings
+   • config:power mode <mode>       Set power mode (auto/battery/ac)
+
+   • config:desktop show            Show desktop assistant settings
+   • config:desktop confirm <when>  Confirm shutdown/suspend (always/never)
+
+   • config:agent show              Show agent safety settings
+   • config:agent safety <level>    Confirm steps (strict/normal/fast)
+   • config:agent deny add <cmd>    Never let the agent run a command
+
+   • config:clipboard show          Show clipboard settings
+   • config:clipboard autocopy on   Copy code blocks from AI answers
+
+   • config:persona list            List personas (system prompts)
+   • config:persona default <name>  Answer as a persona by default
+
+   • config:chat show               Show chat history settings
+   • config:chat titles on|off      Title and tag saved conversations
+
+   • config:budget show             Show the monthly AI budget
+   • config:budget set <usd>        Warn or block once it is spent
+
+   • config:network show            Show the monthly network cap
+   • config:network cap <size>      Warn or pause speed tests past it
+
+   • config:cache show              Show cached AI answers
+   • config:cache clear             Remove cached AI answers
+
+   • config:limits show             Show requests allowed at a time
+   • config:limits set <p> <n>      Limit requests to a provider
+
+   • config:time show               Show how times are shown
+   • config:time format <style>     Use local times or iso (ISO-8601)
+
+   • config:privacy show            Show privacy settings
+   • config:privacy strict          Keep prompts and data on this machine
+
+   • config:metrics show            Show local usage metrics settings
+   • config:metrics purge           Delete the collected metrics
+
+   • config:tracking show           Show time tracking settings
+   • config:tracking exclude <app>  Never record an application
+
+   • config:speedtest show          Show speed test settings
+   • config:speedtest backend set <backend> Set the speed test backend
+
+   • config:discovery show          Show peer discovery settings
+   • config:discovery transport <t> Use mdns, broadcast, or both
+
+   • config:bot show                Show chat bot bridge settings
+   • config:bot telegram <token>    Answer messages to a Telegram bot
+
+   • config:report show             Show emailed report settings
+   • config:report email <r> <to>   Email a report every week
+
+   • config:remote show             Show logins for SFTP and WebDAV
+   • config:remote add <host> <user> Save a login for connect
+
+   • config:feature list            List features and their state
+   • config:feature enable <name>   Turn a feature on
+   • config:feature disable <name>  Turn a feature off
+
+   • config:restore-backup list     List earlier versions of settings
+   • config:restore-backup [n]      Go back to one, newest by default
+
 ╰──────────────────────────────────────────────────────────╯
 `,
 			IsError:    false,
@@ -76,6 +146,52 @@ func (e *Executor) executeConfigCommand(cmd *nlp.Command) (*Result, error) {
 		return e.handleModeConfig(parts[1:], cmd)
 	case "server":
 		return e.handleServerConfig(parts[1:], cmd)
+	case "daemon":
+		return e.handleDaemonConfig(parts[1:], cmd)
+	case "power":
+		return e.handlePowerConfig(parts[1:], cmd)
+	case "desktop":
+		return e.handleDesktopConfig(parts[1:], cmd)
+	case "agent":
+		return e.handleAgentConfig(parts[1:], cmd)
+	case "clipboard":
+		return e.handleClipboardConfig(parts[1:], cmd)
+	case "persona":
+		return e.handlePersonaConfig(parts[1:], cmd)
+	case "chat":
+		return e.handleChatConfig(parts[1:], cmd)
+	case "budget":
+		return e.handleBudgetConfig(parts[1:], cmd)
+	case "network":
+		return e.handleNetworkConfig(parts[1:], cmd)
+	case "cache":
+		return e.handleCacheConfig(parts[1:], cmd)
+	case "limits":
+		return e.handleLimitsConfig(parts[1:], cmd)
+	case "time":
+		return e.handleTimeConfig(parts[1:], cmd)
+	case "privacy":
+		return e.handlePrivacyConfig(parts[1:], cmd)
+	case "metrics":
+		return e.handleMetricsConfig(parts[1:], cmd)
+	case "tracking":
+		return e.handleTrackingConfig(parts[1:], cmd)
+	case "speedtest":
+		return e.handleSpeedTestConfig(parts[1:], cmd)
+	case "discovery":
+		return e.handleDiscoveryConfig(parts[1:], cmd)
+	case "bot":
+		return e.handleBotConfig(parts[1:], cmd)
+	case "feature":
+		return e.handleFeatureConfig(parts[1:], cmd)
+	case "report":
+		return e.handleReportConfig(parts[1:], cmd)
+	case "remote":
+		return e.handleRemoteConfig(parts[1:], cmd)
+	case "test":
+		return e.handleTestConfig(parts[1:], cmd)
+	case "restore-backup":
+		return e.handleRestoreBackupConfig(parts[1:], cmd)
 	default:
 		return &Result{
 			Output:     fmt.Sprintf("Unknown configuration command: %s\nUse 'config:' for help.", parts[0]),
@@ -98,13 +214,14 @@ func (e *Executor) handleProviderConfig(args []string, cmd *nlp.Command) (*Resul
 	switch args[0] {
 	case "list":
 		// List available providers
+		var providers strings.Builder
+		for _, info := range ai.Providers() {
+			providers.WriteString(fmt.Sprintf("  • %-7s (%s)\n", info.Name, info.Description))
+		}
 		output := `
 ╭─────────────── 🐦 Available AI Providers ───────────────╮
 
-  • gemini  (Google's Gemini AI models)
-  • openai  (OpenAI's GPT models)
-  • ollama  (Local Ollama models)
-
+` + providers.String() + `
   Current provider: ` + e.config.AIProvider + `
 
 ╰──────────────────────────────────────────────────────────╯
@@ -125,54 +242,65 @@ func (e *Executor) handleProviderConfig(args []string, cmd *nlp.Command) (*Resul
 		// Set provider
 		if len(args) < 2 {
 			return &Result{
-				Output:     "Missing provider name. Use 'gemini', 'openai', or 'ollama'.",
+				Output:     fmt.Sprintf("Missing provider name. Use %s.", providerChoices()),
 				IsError:    true,
 				CommandRun: cmd.RawInput,
 			}, nil
 		}
 
 		provider := strings.ToLower(args[1])
-		if provider != "gemini" && provider != "openai" && provider != "ollama" {
+		info, ok := ai.Lookup(provider)
+		if !ok {
 			return &Result{
-				Output:     fmt.Sprintf("Invalid provider: %s. Use 'gemini', 'openai', or 'ollama'.", provider),
+				Output:     fmt.Sprintf("Invalid provider: %s. Use %s.", provider, providerChoices()),
 				IsError:    true,
 				CommandRun: cmd.RawInput,
 			}, nil
 		}
 
-		// Check if API key is set for the provider (not needed for Ollama)
-		if provider == "gemini" && e.config.GeminiAPIKey == "" {
+		if privacy.IsStrict(e.config.PrivacyMode) && !info.Capabilities.Local {
 			return &Result{
-				Output:     "No API key set for Gemini. Please set an API key first with 'config:key set gemini <key>'.",
+				Output: lumoerr.Format(lumoerr.New(lumoerr.ErrPolicyBlocked,
+					"strict privacy mode only allows providers that run on this machine, such as ollama").
+					WithHint("Run 'config:privacy standard' first.")),
 				IsError:    true,
 				CommandRun: cmd.RawInput,
 			}, nil
 		}
-		if provider == "openai" && e.config.OpenAIAPIKey == "" {
+
+		// Check if API key is set for the provider
+		if ai.MissingAPIKey(provider, e.config) {
 			return &Result{
-				Output:     "No API key set for OpenAI. Please set an API key first with 'config:key set openai <key>'.",
+				Output:     fmt.Sprintf("No API key set for %s. Please set an API key first with 'config:key set %s <key>'.", info.DisplayName, provider),
 				IsError:    true,
 				CommandRun: cmd.RawInput,
 			}, nil
 		}
-		// Ollama doesn't need an API key, but we should check if the URL is accessible
-		if provider == "ollama" {
-			// Try to connect to the Ollama server
-			client := &http.Client{
-				Timeout: 5 * time.Second,
-			}
-			_, err := client.Get(e.config.OllamaURL + "/api/tags")
-			if err != nil {
+		// Make sure the provider can be reached, e.g. that a local server is running
+		if info.Check != nil {
+			if err := info.Check(e.config); err != nil {
 				return &Result{
-					Output:     fmt.Sprintf("Cannot connect to Ollama server at %s. Please make sure Ollama is running and accessible.", e.config.OllamaURL),
+					Output:     fmt.Sprintf("Cannot use %s: %v.", info.DisplayName, err),
 					IsError:    true,
 					CommandRun: cmd.RawInput,
 				}, nil
 			}
 		}
 
+		// Settings the new provider breaks are resolved before switching
+		next := *e.config
+		next.AIProvider = provider
+		notes, ok := e.resolveSwitch(&next)
+		if !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Kept the AI provider %s.", e.config.AIProvider),
+				IsError:    false,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
 		// Set the provider
-		e.config.AIProvider = provider
+		*e.config = next
 
 		// Save the configuration
 		if err := e.config.Save(); err != nil {
@@ -184,17 +312,10 @@ func (e *Executor) handleProviderConfig(args []string, cmd *nlp.Command) (*Resul
 		}
 
 		// Reinitialize the AI client with the new provider
-		switch provider {
-		case "gemini":
-			e.aiClient = ai.NewGeminiClient(e.config.GeminiAPIKey, e.config.GeminiModel)
-		case "ollama":
-			e.aiClient = ai.NewOllamaClient(e.config.OllamaURL, e.config.OllamaModel)
-		default: // Default to OpenAI
-			e.aiClient = ai.NewOpenAIClient(e.config.OpenAIAPIKey, e.config.OpenAIModel)
-		}
+		e.aiClient = info.Factory(e.config)
 
 		return &Result{
-			Output:     fmt.Sprintf("AI provider set to: %s", provider),
+			Output:     strings.Join(append([]string{fmt.Sprintf("AI provider set to: %s", provider)}, notes...), "\n"),
 			IsError:    false,
 			CommandRun: cmd.RawInput,
 		}, nil
@@ -207,6 +328,18 @@ func (e *Executor) handleProviderConfig(args []string, cmd *nlp.Command) (*Resul
 	}
 }
 
+// providerChoices lists the registered provider names for error messages
+func providerChoices() string {
+	names := ai.ProviderNames()
+	for i, name := range names {
+		names[i] = "'" + name + "'"
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", or " + names[len(names)-1]
+}
+
 // handleModelConfig handles model configuration commands
 func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result, error) {
 	if len(args) == 0 {
@@ -217,6 +350,15 @@ func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result,
 		}, nil
 	}
 
+	// The mock provider answers from its fixture file instead of a model
+	if e.config.AIProvider == string(ai.ProviderMock) {
+		return &Result{
+			Output:     fmt.Sprintf("The mock provider has no models; it answers from %s.", ai.MockFixturesPath(e.config)),
+			IsError:    args[0] == "set",
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
 	switch args[0] {
 	case "list":
 		// Use the dedicated model list handler
@@ -281,6 +423,7 @@ func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result,
 		}
 
 		model := args[1]
+		next := *e.config
 
 		// Validate model based on provider
 		switch e.config.AIProvider {
@@ -301,11 +444,7 @@ func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result,
 				}, nil
 			}
 
-			// Set the model
-			e.config.GeminiModel = model
-
-			// Reinitialize the AI client with the new model
-			e.aiClient = ai.NewGeminiClient(e.config.GeminiAPIKey, e.config.GeminiModel)
+			next.GeminiModel = model
 
 		case "ollama":
 			// For Ollama, we need to check if the model exists
@@ -335,11 +474,7 @@ func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result,
 				}, nil
 			}
 
-			// Set the model
-			e.config.OllamaModel = model
-
-			// Reinitialize the AI client with the new model
-			e.aiClient = ai.NewOllamaClient(e.config.OllamaURL, e.config.OllamaModel)
+			next.OllamaModel = model
 
 		default: // OpenAI
 			validModels := []string{"gpt-3.5-turbo", "gpt-4o", "gpt-4o-mini"}
@@ -358,13 +493,23 @@ func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result,
 				}, nil
 			}
 
-			// Set the model
-			e.config.OpenAIModel = model
+			next.OpenAIModel = model
+		}
 
-			// Reinitialize the AI client with the new model
-			e.aiClient = ai.NewOpenAIClient(e.config.OpenAIAPIKey, e.config.OpenAIModel)
+		// Settings the new model breaks are resolved before switching
+		notes, ok := e.resolveSwitch(&next)
+		if !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Kept the %s model %s.", e.config.AIProvider, configuredModel(e.config)),
+				IsError:    false,
+				CommandRun: cmd.RawInput,
+			}, nil
 		}
 
+		// Set the model and reinitialize the AI client with it
+		*e.config = next
+		e.aiClient = newAIClient(e.config)
+
 		// Save the configuration
 		if err := e.config.Save(); err != nil {
 			return &Result{
@@ -375,7 +520,7 @@ func (e *Executor) handleModelConfig(args []string, cmd *nlp.Command) (*Result,
 		}
 
 		return &Result{
-			Output:     fmt.Sprintf("%s model set to: %s", e.config.AIProvider, model),
+			Output:     strings.Join(append([]string{fmt.Sprintf("%s model set to: %s", e.config.AIProvider, configuredModel(e.config))}, notes...), "\n"),
 			IsError:    false,
 			CommandRun: cmd.RawInput,
 		}, nil
@@ -522,7 +667,7 @@ func (e *Executor) handleOllamaConfig(args []string, cmd *nlp.Command) (*Result,
 func (e *Executor) handleModeConfig(args []string, cmd *nlp.Command) (*Result, error) {
 	if len(args) == 0 {
 		return &Result{
-			Output:     "Missing mode command. Use 'show', 'ai', or 'command'.",
+			Output:     "Missing mode command. Use 'show', 'ai', 'command', 'local', or 'model'.",
 			IsError:    true,
 			CommandRun: cmd.RawInput,
 		}, nil
@@ -547,8 +692,18 @@ func (e *Executor) handleModeConfig(args []string, cmd *nlp.Command) (*Result, e
   • Command-first mode: Treats input as shell commands if it
     looks like a command, otherwise as an AI query.
 
+  Local routing: %s
+    Handles obvious requests such as "disk usage", "system
+    report", "use ollama", or "df -h" without asking the AI.
+  Local intent model: %s
+    Also routes phrasings the rules miss, like "is my laptop
+    healthy", with a small model built into lumo.
+
+  Commands:
+   • config:mode local on|off    Toggle local routing
+   • config:mode model on|off    Toggle the local intent model
 ╰──────────────────────────────────────────────────────────╯
-`, modeStr)
+`, modeStr, onOff(e.config.LocalIntentRouting), onOff(e.config.LocalIntentModel))
 
 		return &Result{
 			Output:     output,
@@ -594,9 +749,58 @@ func (e *Executor) handleModeConfig(args []string, cmd *nlp.Command) (*Result, e
 			CommandRun: cmd.RawInput,
 		}, nil
 
+	case "local", "model":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing argument. Usage: config:mode %s on|off", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		var enabled bool
+		switch strings.ToLower(args[1]) {
+		case "on", "true", "yes", "1":
+			enabled = true
+		case "off", "false", "no", "0":
+			enabled = false
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		message := fmt.Sprintf("Local routing %s.", onOff(enabled))
+		if args[0] == "local" {
+			e.config.LocalIntentRouting = enabled
+		} else {
+			e.config.LocalIntentModel = enabled
+			message = fmt.Sprintf("Local intent model %s.", onOff(enabled))
+			if enabled && !e.config.LocalIntentRouting {
+				message += " It is only used while local routing is on: config:mode local on"
+			}
+		}
+
+		// Save the configuration
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     message,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
 	default:
 		return &Result{
-			Output:     fmt.Sprintf("Unknown mode command: %s. Use 'show', 'ai', or 'command'.", args[0]),
+			Output:     fmt.Sprintf("Unknown mode command: %s. Use 'show', 'ai', 'command', 'local', or 'model'.", args[0]),
 			IsError:    true,
 			CommandRun: cmd.RawInput,
 		}, nil
@@ -620,6 +824,9 @@ func (e *Executor) handleServerConfig(args []string, cmd *nlp.Command) (*Result,
    • config:server auth enable    Enable authentication
    • config:server auth disable   Disable authentication
    • config:server auth password  Change the admin password
+   • config:server fleet on|off   Advertise as fleet-managed
+   • config:server ratelimit <n>|off  Requests per minute
+   • config:server maxbody <KB>   Largest request body
 
   Configure these settings in ~/.config/lumo/config.json
 ╰──────────────────────────────────────────────────────────╯
@@ -654,6 +861,9 @@ func (e *Executor) handleServerConfig(args []string, cmd *nlp.Command) (*Result,
   • Server Port: %d
   • Quiet Output: %s
   • Authentication: %s
+  • Fleet-managed: %s
+  • Rate Limit: %s
+  • Max Request Body: %d KB
   • Token Expiration: %d hours
   • Refresh Token Expiration: %d days
 
@@ -669,8 +879,12 @@ func (e *Executor) handleServerConfig(args []string, cmd *nlp.Command) (*Result,
    • config:server auth enable    Enable authentication
    • config:server auth disable   Disable authentication
    • config:server auth password  Change the admin password
+   • config:server fleet on|off   Advertise as fleet-managed
+   • config:server ratelimit <n>|off  Requests per minute
+   • config:server maxbody <KB>   Largest request body
 ╰──────────────────────────────────────────────────────────╯
-`, enabledStr, e.config.ServerPort, quietStr, authStr, e.config.TokenExpirationHours, e.config.RefreshExpirationDays)
+`, enabledStr, e.config.ServerPort, quietStr, authStr, onOff(e.config.FleetManaged), formatRateLimit(e.config),
+			e.config.ServerMaxBodyKB, e.config.TokenExpirationHours, e.config.RefreshExpirationDays)
 
 		return &Result{
 			Output:     output,
@@ -799,6 +1013,126 @@ func (e *Executor) handleServerConfig(args []string, cmd *nlp.Command) (*Result,
 			CommandRun: cmd.RawInput,
 		}, nil
 
+	case "fleet":
+		// Set whether the server advertises itself as fleet-managed
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing argument. Usage: config:server fleet on|off",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		switch strings.ToLower(args[1]) {
+		case "on", "true", "yes", "1":
+			e.config.FleetManaged = true
+		case "off", "false", "no", "0":
+			e.config.FleetManaged = false
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     fmt.Sprintf("Fleet-managed advertising %s. Restart the server for this to take effect.", onOff(e.config.FleetManaged)),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "ratelimit":
+		// Set how many requests each client may make per minute
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing argument. Usage: config:server ratelimit <per-minute> [burst]|off",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		if strings.ToLower(args[1]) == "off" {
+			e.config.ServerRateLimit = 0
+		} else {
+			perMinute, err := strconv.Atoi(args[1])
+			if err != nil || perMinute < 1 {
+				return &Result{
+					Output:     fmt.Sprintf("Invalid rate limit: %s. Use a number of requests per minute, or 'off'.", args[1]),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			burst := e.config.ServerRateBurst
+			if len(args) > 2 {
+				if burst, err = strconv.Atoi(args[2]); err != nil || burst < 1 {
+					return &Result{
+						Output:     fmt.Sprintf("Invalid burst: %s. Use how many requests a client may make at once.", args[2]),
+						IsError:    true,
+						CommandRun: cmd.RawInput,
+					}, nil
+				}
+			}
+			e.config.ServerRateLimit = perMinute
+			e.config.ServerRateBurst = burst
+		}
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     fmt.Sprintf("Server rate limit: %s. Restart the server for this to take effect.", formatRateLimit(e.config)),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "maxbody":
+		// Set the largest request body the server reads
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing size. Usage: config:server maxbody <KB>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		size, err := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(args[1]), "KB"))
+		if err != nil || size < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid size: %s. Use a number of kilobytes.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		e.config.ServerMaxBodyKB = size
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     fmt.Sprintf("Server request bodies limited to %d KB. Restart the server for this to take effect.", size),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
 	case "auth":
 		// Handle authentication settings
 		if len(args) < 2 {
@@ -858,7 +1192,7 @@ func (e *Executor) handleServerConfig(args []string, cmd *nlp.Command) (*Result,
 
 	default:
 		return &Result{
-			Output:     fmt.Sprintf("Unknown server command: %s. Use 'show', 'enable', 'disable', 'port', 'quiet', or 'auth'.", args[0]),
+			Output:     fmt.Sprintf("Unknown server command: %s. Use 'show', 'enable', 'disable', 'port', 'quiet', 'fleet', 'ratelimit', 'maxbody', or 'auth'.", args[0]),
 			IsError:    true,
 			CommandRun: cmd.RawInput,
 		}, nil
@@ -1025,3 +1359,11 @@ func (e *Executor) handleKeyConfig(args []string, cmd *nlp.Command) (*Result, er
 		}, nil
 	}
 }
+
+// formatRateLimit describes the server's rate limit
+func formatRateLimit(cfg *config.Config) string {
+	if cfg.ServerRateLimit <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d requests per minute, bursts of %d", cfg.ServerRateLimit, cfg.ServerRateBurst)
+}
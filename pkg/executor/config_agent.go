@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleAgentConfig handles agent safety configuration commands
+func (e *Executor) handleAgentConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		denyList := "none"
+		if len(e.config.AgentDenyList) > 0 {
+			denyList = "\n     - " + strings.Join(e.config.AgentDenyList, "\n     - ")
+		}
+		output := fmt.Sprintf(`
+╭─────────────────── 🛡️  Agent Safety ────────────────────╮
+
+  • Safety Level: %s
+  • Confirm the Plan Before Running: %s
+  • Deny List: %s
+
+  Levels:
+   • strict   Confirm every critical or destructive step
+   • normal   Confirm destructive steps (rm, dd, mkfs, chmod -R)
+   • fast     Confirm only the plan as a whole
+
+  Commands:
+   • config:agent safety <level>      Set the safety level
+   • config:agent deny add <pattern>  Never run commands matching a pattern
+   • config:agent deny remove <pattern> Remove a pattern from the deny list
+╰──────────────────────────────────────────────────────────╯
+`, e.config.AgentSafety(), onOff(e.config.AgentConfirmBeforeExecution), denyList)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "safety":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing level. Usage: config:agent safety strict|normal|fast",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		level := strings.ToLower(args[1])
+		if level != config.AgentSafetyStrict && level != config.AgentSafetyNormal && level != config.AgentSafetyFast {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid safety level: %s. Use 'strict', 'normal', or 'fast'.", level),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.AgentSafetyLevel = level
+		message = fmt.Sprintf("Agent safety level set to: %s", level)
+
+	case "deny":
+		if len(args) < 3 || (args[1] != "add" && args[1] != "remove") {
+			return &Result{
+				Output:     "Usage: config:agent deny add|remove <pattern>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		pattern := strings.Join(args[2:], " ")
+		index := -1
+		for i, existing := range e.config.AgentDenyList {
+			if strings.Join(strings.Fields(existing), " ") == pattern {
+				index = i
+				break
+			}
+		}
+
+		if args[1] == "add" {
+			if index >= 0 {
+				return &Result{
+					Output:     fmt.Sprintf("Already on the deny list: %s", pattern),
+					IsError:    false,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			e.config.AgentDenyList = append(e.config.AgentDenyList, pattern)
+			message = fmt.Sprintf("Added to the deny list: %s", pattern)
+		} else {
+			if index < 0 {
+				return &Result{
+					Output:     fmt.Sprintf("Not on the deny list: %s", pattern),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			e.config.AgentDenyList = append(e.config.AgentDenyList[:index], e.config.AgentDenyList[index+1:]...)
+			message = fmt.Sprintf("Removed from the deny list: %s", pattern)
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown agent command: %s. Use 'show', 'safety', or 'deny'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
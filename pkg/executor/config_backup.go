@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// handleRestoreBackupConfig lists the configuration file's backups or puts
+// one back
+func (e *Executor) handleRestoreBackupConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) > 0 && args[0] == "list" {
+		backups, err := config.Backups()
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error listing backups: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if len(backups) == 0 {
+			return &Result{
+				Output:     "No configuration backups yet. One is kept each time settings change.",
+				IsError:    false,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		var b strings.Builder
+		b.WriteString("💾 Configuration backups, newest first:\n")
+		for _, backup := range backups {
+			fmt.Fprintf(&b, "  %d. %s  %s\n", backup.Number, utils.FormatTimestamp(backup.ModTime), backup.Path)
+		}
+		b.WriteString("Restore one with: lumo config:restore-backup <n>")
+		return &Result{
+			Output:     b.String(),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	n := 0
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid backup: %s. Usage: config:restore-backup [list|<n>]", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+	}
+
+	restored, err := config.RestoreBackup(n)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error restoring the configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	// Later commands in this session use the restored settings
+	if cfg, err := config.Load(); err == nil {
+		*e.config = *cfg
+	}
+
+	return &Result{
+		Output: fmt.Sprintf("Restored configuration backup %d. The replaced settings are now backup 1.\n"+
+			"Restart a running daemon or server to use the restored settings.", restored),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,195 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleBotConfig handles configuration of the daemon's chat bot bridge
+func (e *Executor) handleBotConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		platform := e.config.BotPlatform
+		if platform == "" {
+			platform = "off"
+		}
+		token := "not set"
+		if e.config.BotToken != "" {
+			token = "set"
+		}
+		room := e.config.BotMatrixRoom
+		if room == "" {
+			room = "every joined room"
+		}
+
+		output := fmt.Sprintf(`
+╭─────────────────── 🤖 Bot Bridge ───────────────────────╮
+
+  • Platform: %s
+  • Token: %s
+  • Matrix homeserver: %s
+  • Matrix room: %s
+  • Allowed users: %s
+  • Agent users: %s
+
+  The server daemon answers messages from allowed users:
+  plain messages chat, /ask asks a single question, and
+  /agent runs a task for agent users. Agent tasks run
+  without showing the plan first; steps the agent safety
+  level asks about are not run.
+
+  Commands:
+   • config:bot telegram <bot-token>                 Use a Telegram bot
+   • config:bot matrix <homeserver> <token> [room]   Use a Matrix account
+   • config:bot allow|disallow <user-id>             Manage allowed users
+   • config:bot agent <user-id> on|off               Allow agent tasks
+   • config:bot off                                  Turn the bridge off
+╰──────────────────────────────────────────────────────────╯
+`, platform, token, valueOrNone(e.config.BotMatrixHomeserver), room,
+			listOrNone(e.config.BotAllowedUsers), listOrNone(e.config.BotAgentUsers))
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "telegram":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing token. Usage: config:bot telegram <bot-token> (from @BotFather)",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.BotPlatform = "telegram"
+		e.config.BotToken = args[1]
+		message = "The bot bridge now uses Telegram."
+
+	case "matrix":
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing arguments. Usage: config:bot matrix <homeserver-url> <access-token> [room-id]",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if !strings.HasPrefix(args[1], "https://") && !strings.HasPrefix(args[1], "http://") {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid homeserver: %s. Use a URL such as https://matrix.org", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.BotPlatform = "matrix"
+		e.config.BotMatrixHomeserver = args[1]
+		e.config.BotToken = args[2]
+		e.config.BotMatrixRoom = ""
+		if len(args) > 3 {
+			e.config.BotMatrixRoom = args[3]
+		}
+		message = "The bot bridge now uses Matrix. The account must already be in the rooms it answers in."
+
+	case "off":
+		e.config.BotPlatform = ""
+		message = "The bot bridge is off."
+
+	case "allow", "disallow":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing user. Usage: config:bot %s <user-id>", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		user := args[1]
+		if args[0] == "allow" {
+			if !slices.Contains(e.config.BotAllowedUsers, user) {
+				e.config.BotAllowedUsers = append(e.config.BotAllowedUsers, user)
+			}
+			message = fmt.Sprintf("%s may now use the bot.", user)
+		} else {
+			e.config.BotAllowedUsers = slices.DeleteFunc(e.config.BotAllowedUsers, func(u string) bool { return u == user })
+			e.config.BotAgentUsers = slices.DeleteFunc(e.config.BotAgentUsers, func(u string) bool { return u == user })
+			message = fmt.Sprintf("%s may no longer use the bot.", user)
+		}
+
+	case "agent":
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing arguments. Usage: config:bot agent <user-id> on|off",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		user := args[1]
+		switch strings.ToLower(args[2]) {
+		case "on":
+			if strings.HasPrefix(user, "@") && !strings.Contains(user, ":") {
+				return &Result{
+					Output:     "Agent tasks need the numeric Telegram user ID, since an @username can be changed and claimed by someone else.",
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			if !slices.Contains(e.config.BotAllowedUsers, user) {
+				e.config.BotAllowedUsers = append(e.config.BotAllowedUsers, user)
+			}
+			if !slices.Contains(e.config.BotAgentUsers, user) {
+				e.config.BotAgentUsers = append(e.config.BotAgentUsers, user)
+			}
+			message = fmt.Sprintf("%s may now run agent tasks from the bot.", user)
+		case "off":
+			e.config.BotAgentUsers = slices.DeleteFunc(e.config.BotAgentUsers, func(u string) bool { return u == user })
+			message = fmt.Sprintf("%s may no longer run agent tasks from the bot.", user)
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[2]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown bot command: %s. Use 'show', 'telegram', 'matrix', 'off', 'allow', 'disallow', or 'agent'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message + "\nRestart the server daemon to apply.",
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// listOrNone joins a list for display, or returns "none" if it is empty
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// valueOrNone returns value, or "none" if it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// handleBudgetConfig handles monthly AI budget configuration commands
+func (e *Executor) handleBudgetConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		budget := "none"
+		if e.config.BudgetUSD > 0 {
+			budget = fmt.Sprintf("$%.2f a month", e.config.BudgetUSD)
+		}
+		spent, _ := usage.MonthCost(time.Now())
+		output := fmt.Sprintf(`
+╭─────────────────── 💰 AI Budget ────────────────────────╮
+
+  • Monthly Budget: %s
+  • Spent This Month: $%.2f (estimated)
+  • When Reached: %s
+
+  Once the budget is spent, cloud AI requests either print
+  a warning or are refused until next month. Local models
+  are never limited.
+
+  Commands:
+   • config:budget set <usd>           Set the monthly budget
+   • config:budget off                 Remove the budget
+   • config:budget action warn|block   Choose what happens
+╰──────────────────────────────────────────────────────────╯
+`, budget, spent, e.config.BudgetAction)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "set":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing amount. Usage: config:budget set <usd>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		amount, err := strconv.ParseFloat(strings.TrimPrefix(args[1], "$"), 64)
+		if err != nil || amount <= 0 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid amount: %s. Use a number of US dollars, like 5 or 2.50.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.BudgetUSD = amount
+		message = fmt.Sprintf("Monthly AI budget set to $%.2f.", amount)
+	case "off":
+		e.config.BudgetUSD = 0
+		message = "Monthly AI budget removed."
+	case "action":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing action. Usage: config:budget action warn|block",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		switch action := strings.ToLower(args[1]); action {
+		case config.BudgetWarn, config.BudgetBlock:
+			e.config.BudgetAction = action
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid action: %s. Use 'warn' or 'block'.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		message = "Cloud AI requests will print a warning once the budget is spent."
+		if e.config.BudgetAction == config.BudgetBlock {
+			message = "Cloud AI requests will be refused once the budget is spent."
+		}
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown budget command: %s. Use 'show', 'set', 'off', or 'action'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
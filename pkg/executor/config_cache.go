@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleCacheConfig handles AI response cache configuration commands
+func (e *Executor) handleCacheConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	cache, err := ai.OpenResponseCache(e.config)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error opening the response cache: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) == 0 || args[0] == "show" {
+		count, size, _ := cache.Stats()
+		output := fmt.Sprintf(`
+╭─────────────────── 📦 Response Cache ───────────────────╮
+
+  • Cache Answers: %s
+  • Keep Answers For: %d minutes
+  • Keep At Most: %d answers
+  • Cached Now: %d answers (%.1f KB)
+
+  Asking the same question again with the same provider,
+  model, and persona in the same directory answers from
+  the cache. Ask again with --no-cache:
+    lumo --no-cache "what is my public IP"
+
+  Commands:
+   • config:cache clear              Remove every cached answer
+   • config:cache on|off             Toggle the cache
+   • config:cache ttl <minutes>      Set how long answers are kept
+   • config:cache size <n>           Set how many answers are kept
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.EnableResponseCache), e.config.ResponseCacheTTLMinutes,
+			e.config.ResponseCacheSize, count, float64(size)/1024)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch strings.ToLower(args[0]) {
+	case "clear":
+		removed, err := cache.Clear()
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error clearing the response cache: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.responses = nil
+		return &Result{
+			Output:     fmt.Sprintf("Removed %d cached answers.", removed),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	case "on":
+		e.config.EnableResponseCache = true
+		message = "Caching AI answers enabled."
+	case "off":
+		e.config.EnableResponseCache = false
+		message = "Caching AI answers disabled. Cached answers are kept until config:cache clear."
+	case "ttl", "size":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing value. Usage: config:cache %s <number>", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		value, err := strconv.Atoi(args[1])
+		if err != nil || value < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use a whole number of 1 or more.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if args[0] == "ttl" {
+			e.config.ResponseCacheTTLMinutes = value
+			message = fmt.Sprintf("Cached answers are kept for %d minutes.", value)
+		} else {
+			e.config.ResponseCacheSize = value
+			message = fmt.Sprintf("Up to %d answers are kept.", value)
+		}
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown cache command: %s. Use 'show', 'clear', 'on', 'off', 'ttl', or 'size'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	// Reopen the cache with the new settings on the next question
+	e.responses = nil
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
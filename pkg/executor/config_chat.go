@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleChatConfig handles chat history configuration commands
+func (e *Executor) handleChatConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		output := fmt.Sprintf(`
+╭──────────────────── 💬 Chat Settings ────────────────────╮
+
+  • Interactive Chat Mode: %s
+  • Save Conversations: %s
+  • Title and Tag Conversations: %s
+
+  Titles and tags come from a short request to the AI
+  provider after the first answer.
+
+  Commands:
+   • config:chat history on|off     Save conversations for chat:history
+   • config:chat titles on|off      Title and tag saved conversations
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.EnableChatREPL), onOff(e.config.SaveChatHistory), onOff(e.config.ChatAutoTitle))
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) < 2 || (args[0] != "history" && args[0] != "titles") {
+		return &Result{
+			Output:     "Usage: config:chat history|titles on|off",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	var enabled bool
+	switch strings.ToLower(args[1]) {
+	case "on", "true", "yes", "1":
+		enabled = true
+	case "off", "false", "no", "0":
+		enabled = false
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[1]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	if args[0] == "history" {
+		e.config.SaveChatHistory = enabled
+		message = "Conversations are saved for chat:history."
+		if !enabled {
+			message = "Conversations are no longer saved. Saved ones are kept; remove them with chat:history delete."
+		}
+	} else {
+		e.config.ChatAutoTitle = enabled
+		message = "Saved conversations are titled and tagged."
+		if !enabled {
+			message = "Saved conversations are no longer titled; name them with chat:rename."
+		}
+	}
+	e.chatManager.SetHistory(e.config.SaveChatHistory, e.config.ChatAutoTitle)
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleClipboardConfig handles clipboard configuration commands
+func (e *Executor) handleClipboardConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		output := fmt.Sprintf(`
+╭─────────────────── 📋 Clipboard ────────────────────────╮
+
+  • Copy Code From Answers: %s
+  • Record History:         %s
+  • History Limit:          %d entries, besides pinned ones
+
+  Copies the first code block of every AI answer to the
+  clipboard. Without it, ask for a copy with --copy, or
+  --copy=2 for the second block:
+    lumo --copy "find files larger than 1GB"
+
+  The history is recorded by the daemon, encrypted on this
+  machine; see it with: lumo clipboard history
+
+  Commands:
+   • config:clipboard autocopy on|off  Toggle copying code blocks
+   • config:clipboard history on|off   Toggle recording the history
+   • config:clipboard limit <n>        Set how many entries to keep
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.AutoCopyCode), onOff(e.config.EnableClipboardHistory), e.config.ClipboardHistoryLimit)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if args[0] != "autocopy" && args[0] != "history" && args[0] != "limit" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown clipboard command: %s. Use 'show', 'autocopy', 'history', or 'limit'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if len(args) < 2 {
+		usage := "config:clipboard " + args[0] + " on|off"
+		if args[0] == "limit" {
+			usage = "config:clipboard limit <n>"
+		}
+		return &Result{
+			Output:     "Missing argument. Usage: " + usage,
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	if args[0] == "limit" {
+		limit, err := strconv.Atoi(args[1])
+		if err != nil || limit < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid limit: %s. Use a number of entries, such as 100.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.ClipboardHistoryLimit = limit
+		message = fmt.Sprintf("The clipboard history keeps the %d newest entries, besides pinned ones.", limit)
+	} else {
+		var on bool
+		switch strings.ToLower(args[1]) {
+		case "on", "true", "yes", "1":
+			on = true
+		case "off", "false", "no", "0":
+			on = false
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if args[0] == "autocopy" {
+			e.config.AutoCopyCode = on
+			message = fmt.Sprintf("Copying code blocks from AI answers %s.", onOff(on))
+		} else {
+			e.config.EnableClipboardHistory = on
+			message = fmt.Sprintf("Recording the clipboard history %s.", onOff(on))
+			if on {
+				message += "\nRestart the daemon with 'lumo server:stop' and 'lumo server:start' to apply."
+			} else {
+				message += "\nWhat was recorded stays until: lumo clipboard history clear"
+			}
+		}
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,217 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/usage"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// ConflictResolver chooses how to resolve a setting that a provider or
+// model change breaks. It is given the problem and the options, the last of
+// which cancels the change, and returns the index of the chosen option.
+type ConflictResolver func(problem string, options []string) int
+
+// SetConflictResolver replaces asking on the terminal when config:provider
+// or config:model breaks a setting
+func (e *Executor) SetConflictResolver(resolve ConflictResolver) {
+	e.resolveConflict = resolve
+}
+
+// settingConflict is something that stops working after a provider or
+// model change
+type settingConflict struct {
+	// problem describes what breaks
+	problem string
+	// hint says how to fix it later, for changes made without a terminal
+	hint string
+	// resolutions are the ways to go ahead with the change, the
+	// recommended one first
+	resolutions []resolution
+}
+
+// resolution is one way to go ahead with a change despite a conflict
+type resolution struct {
+	label string
+	// apply adjusts the new configuration; nil keeps it as it is
+	apply func(next *config.Config)
+}
+
+// configuredModel returns the model of the configuration's provider
+func configuredModel(cfg *config.Config) string {
+	switch cfg.AIProvider {
+	case string(ai.ProviderGemini):
+		return cfg.GeminiModel
+	case string(ai.ProviderOllama):
+		return cfg.OllamaModel
+	case string(ai.ProviderOpenAI):
+		return cfg.OpenAIModel
+	}
+	return ""
+}
+
+// switchConflicts finds the settings that work with the current
+// configuration but not with next
+func (e *Executor) switchConflicts(next *config.Config) []settingConflict {
+	var conflicts []settingConflict
+	current := e.config
+	nextModel := configuredModel(next)
+
+	// Questions about images need a model that reads them
+	if ai.SupportsVision(current.AIProvider, configuredModel(current)) && !ai.SupportsVision(next.AIProvider, nextModel) {
+		conflict := settingConflict{
+			problem: fmt.Sprintf("%s %s cannot read images, so 'ask:--image' stops working.", next.AIProvider, nextModel),
+			hint:    fmt.Sprintf("Ask about images with 'lumo -p %s ask:--image ...'.", current.AIProvider),
+		}
+		if next.AIProvider == string(ai.ProviderOpenAI) {
+			conflict.resolutions = append(conflict.resolutions, resolution{
+				label: "Use gpt-4o-mini, which reads images",
+				apply: func(next *config.Config) { next.OpenAIModel = "gpt-4o-mini" },
+			})
+		}
+		conflict.resolutions = append(conflict.resolutions, resolution{
+			label: fmt.Sprintf("Switch anyway and ask about images with 'lumo -p %s'", current.AIProvider),
+		})
+		conflicts = append(conflicts, conflict)
+	}
+
+	// A spent budget that blocks requests was never hit while the
+	// provider was local
+	if ai.IsLocal(current.AIProvider) && !ai.IsLocal(next.AIProvider) &&
+		next.BudgetUSD > 0 && next.BudgetAction == config.BudgetBlock {
+		if spent, err := usage.MonthCost(time.Now()); err == nil && spent >= next.BudgetUSD {
+			raised := math.Ceil(spent) + next.BudgetUSD
+			conflicts = append(conflicts, settingConflict{
+				problem: fmt.Sprintf("The monthly budget of $%.2f is spent ($%.2f so far), so every %s request would be refused.",
+					next.BudgetUSD, spent, next.AIProvider),
+				hint: "Raise it with 'config:budget set <usd>' or only warn with 'config:budget action warn'.",
+				resolutions: []resolution{
+					{
+						label: "Only warn when over the budget",
+						apply: func(next *config.Config) { next.BudgetAction = config.BudgetWarn },
+					},
+					{
+						label: fmt.Sprintf("Raise the budget to $%.2f", raised),
+						apply: func(next *config.Config) { next.BudgetUSD = raised },
+					},
+					{label: "Switch anyway; requests are refused until next month"},
+				},
+			})
+		}
+	}
+
+	// The Ollama model must be pulled before it answers
+	if next.AIProvider == string(ai.ProviderOllama) && current.AIProvider != next.AIProvider {
+		if conflict, ok := missingOllamaModel(next); ok {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return conflicts
+}
+
+// missingOllamaModel reports a conflict if the configured Ollama model is
+// not installed. A server that cannot list its models is left to the
+// provider check.
+func missingOllamaModel(next *config.Config) (settingConflict, bool) {
+	models, err := ai.NewOllamaClient(next.OllamaURL, next.OllamaModel).ListModels()
+	if err != nil {
+		return settingConflict{}, false
+	}
+	for _, model := range models {
+		if model == next.OllamaModel || model == next.OllamaModel+":latest" {
+			return settingConflict{}, false
+		}
+	}
+
+	conflict := settingConflict{
+		problem: fmt.Sprintf("The Ollama model %s is not installed.", next.OllamaModel),
+		hint:    fmt.Sprintf("Pull it with 'ollama pull %s' or pick another with 'config:model set'.", next.OllamaModel),
+	}
+	// Offer a few of the installed models instead
+	for _, model := range models[:min(len(models), 3)] {
+		conflict.resolutions = append(conflict.resolutions, resolution{
+			label: "Use " + model,
+			apply: func(next *config.Config) { next.OllamaModel = model },
+		})
+	}
+	conflict.resolutions = append(conflict.resolutions, resolution{
+		label: fmt.Sprintf("Switch anyway and run 'ollama pull %s'", next.OllamaModel),
+	})
+	return conflict, true
+}
+
+// resolveSwitch walks through the settings that next breaks and applies
+// the chosen resolutions to it. It returns notes for the result, and false
+// if the change was cancelled. Without a terminal or resolver to ask, the
+// change goes ahead and the notes say how to fix each setting.
+func (e *Executor) resolveSwitch(next *config.Config) ([]string, bool) {
+	conflicts := e.switchConflicts(next)
+	if len(conflicts) == 0 {
+		return nil, true
+	}
+
+	resolve := e.resolveConflict
+	if resolve == nil && utils.IsTerminal(os.Stdin) {
+		resolve = promptConflict
+	}
+
+	var notes []string
+	for _, conflict := range conflicts {
+		if resolve == nil {
+			notes = append(notes, fmt.Sprintf("⚠️  %s\n   %s", conflict.problem, conflict.hint))
+			continue
+		}
+
+		options := make([]string, 0, len(conflict.resolutions)+1)
+		for _, r := range conflict.resolutions {
+			options = append(options, r.label)
+		}
+		options = append(options, fmt.Sprintf("Cancel and keep %s %s", e.config.AIProvider, configuredModel(e.config)))
+
+		choice := resolve(conflict.problem, options)
+		if choice < 0 || choice >= len(conflict.resolutions) {
+			return nil, false
+		}
+		chosen := conflict.resolutions[choice]
+		if chosen.apply != nil {
+			chosen.apply(next)
+		}
+		notes = append(notes, fmt.Sprintf("• %s", chosen.label))
+	}
+	return notes, true
+}
+
+// promptConflict asks on the terminal how to resolve a conflict; an empty
+// answer takes the first option
+func promptConflict(problem string, options []string) int {
+	fmt.Printf("\n⚠️  %s\n", problem)
+	for i, option := range options {
+		fmt.Printf("   %d) %s\n", i+1, option)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Choose [1]: ")
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return len(options) - 1
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return 0
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1
+		}
+		fmt.Printf("❌ Please answer a number from 1 to %d\n", len(options))
+	}
+}
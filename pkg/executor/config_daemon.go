@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleDaemonConfig handles daemon scheduler configuration commands
+func (e *Executor) handleDaemonConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		speedTestStr := "Disabled"
+		if e.config.ScheduledSpeedTestHours > 0 {
+			speedTestStr = fmt.Sprintf("Every %d hours", e.config.ScheduledSpeedTestHours)
+		}
+
+		nightLightStr := "Disabled"
+		if e.config.NightLightStart != "" && e.config.NightLightEnd != "" {
+			nightLightStr = fmt.Sprintf("%s - %s", e.config.NightLightStart, e.config.NightLightEnd)
+			if e.config.NightLightTemperature > 0 {
+				nightLightStr += fmt.Sprintf(" at %dK", e.config.NightLightTemperature)
+			}
+		}
+
+		noisyStr := "None"
+		if len(e.config.NoisyTasks) > 0 {
+			noisyStr = strings.Join(e.config.NoisyTasks, ", ")
+		}
+
+		output := fmt.Sprintf(`
+╭─────────────────── ⏱️ Daemon Scheduler ───────────────────╮
+
+  • Scheduled Speed Test: %s
+  • Idle Threshold: %d minutes
+  • Max Idle Delay: %d minutes
+  • Noisy Tasks: %s
+  • Night Light Schedule: %s
+
+  Noisy tasks wait until you have been idle for the threshold,
+  but never longer than the max delay.
+
+  Commands:
+   • config:daemon speedtest <hours>    Schedule speed tests (0 to disable)
+   • config:daemon idle <minutes>       Set the idle threshold
+   • config:daemon max-delay <minutes>  Set the longest a task may wait
+   • config:daemon noisy <task> on|off  Defer a task until idle
+   • config:daemon nightlight <from> <to> [kelvin]  Schedule night light
+   • config:daemon nightlight off       Disable the night light schedule
+╰──────────────────────────────────────────────────────────╯
+`, speedTestStr, e.config.IdleThresholdMinutes, e.config.IdleMaxDelayMinutes, noisyStr, nightLightStr)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	switch args[0] {
+	case "speedtest", "idle", "max-delay":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing value. Usage: config:daemon %s <number>", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		value, err := strconv.Atoi(args[1])
+		if err != nil || value < 0 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use a non-negative number.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		var message string
+		switch args[0] {
+		case "speedtest":
+			e.config.ScheduledSpeedTestHours = value
+			message = fmt.Sprintf("Scheduled speed test interval set to %d hours", value)
+			if value == 0 {
+				message = "Scheduled speed tests disabled"
+			}
+		case "idle":
+			e.config.IdleThresholdMinutes = value
+			message = fmt.Sprintf("Idle threshold set to %d minutes", value)
+		case "max-delay":
+			e.config.IdleMaxDelayMinutes = value
+			message = fmt.Sprintf("Maximum idle delay set to %d minutes", value)
+		}
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     message + "\nRestart the daemon with 'lumo server:stop' and 'lumo server:start' to apply.",
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "noisy":
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing argument. Usage: config:daemon noisy <task> on|off",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		task := strings.ToLower(args[1])
+		var tasks []string
+		for _, name := range e.config.NoisyTasks {
+			if name != task {
+				tasks = append(tasks, name)
+			}
+		}
+
+		switch strings.ToLower(args[2]) {
+		case "on", "true", "yes", "1":
+			tasks = append(tasks, task)
+		case "off", "false", "no", "0":
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[2]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.NoisyTasks = tasks
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     fmt.Sprintf("Noisy tasks: %s", strings.Join(tasks, ", ")),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "nightlight":
+		return e.handleNightLightSchedule(args[1:], cmd)
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown daemon command: %s. Use 'show', 'speedtest', 'idle', 'max-delay', 'noisy', or 'nightlight'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+}
+
+// handleNightLightSchedule configures the daemon's night light schedule
+func (e *Executor) handleNightLightSchedule(args []string, cmd *nlp.Command) (*Result, error) {
+	var message string
+
+	switch {
+	case len(args) == 1 && strings.ToLower(args[0]) == "off":
+		e.config.NightLightStart = ""
+		e.config.NightLightEnd = ""
+		message = "Night light schedule disabled"
+
+	case len(args) >= 2:
+		for _, value := range args[:2] {
+			if _, err := time.Parse("15:04", value); err != nil {
+				return &Result{
+					Output:     fmt.Sprintf("Invalid time: %s. Use HH:MM, e.g. 21:00.", value),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+		}
+
+		temperature := 0
+		if len(args) > 2 {
+			value, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(args[2]), "k"))
+			if err != nil || value <= 0 {
+				return &Result{
+					Output:     fmt.Sprintf("Invalid color temperature: %s. Use Kelvin, e.g. 4000.", args[2]),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			temperature = value
+		}
+
+		e.config.NightLightStart = args[0]
+		e.config.NightLightEnd = args[1]
+		e.config.NightLightTemperature = temperature
+		message = fmt.Sprintf("Night light scheduled from %s to %s", args[0], args[1])
+
+	default:
+		return &Result{
+			Output:     "Missing argument. Usage: config:daemon nightlight <from> <to> [kelvin] or config:daemon nightlight off",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message + "\nRestart the daemon with 'lumo server:stop' and 'lumo server:start' to apply.",
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleDesktopConfig handles desktop assistant configuration commands
+func (e *Executor) handleDesktopConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		output := fmt.Sprintf(`
+╭────────────────── 🖥️  Desktop Settings ──────────────────╮
+
+  • Desktop Assistant: %s
+  • Desktop Environment: %s
+  • Confirm Shutdown, Suspend, and Logout: %s
+
+  Commands:
+   • config:desktop confirm always   Ask before ending the session
+   • config:desktop confirm never    Allow unattended use, e.g. from cron
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.EnableDesktopAssistant), e.config.DefaultDesktopEnv, e.config.DesktopConfirmPower)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if args[0] != "confirm" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown desktop command: %s. Use 'show' or 'confirm'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) < 2 {
+		return &Result{
+			Output:     "Missing value. Usage: config:desktop confirm always|never",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	value := strings.ToLower(args[1])
+	if value != "always" && value != "never" {
+		return &Result{
+			Output:     fmt.Sprintf("Invalid confirmation policy: %s. Use 'always' or 'never'.", value),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	e.config.DesktopConfirmPower = value
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     fmt.Sprintf("Confirmation before shutdown, suspend, and logout: %s", value),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
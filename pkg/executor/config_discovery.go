@@ -0,0 +1,208 @@
+package executor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// minDiscoverySecretLength is the shortest shared secret accepted for broadcast discovery
+const minDiscoverySecretLength = 16
+
+// handleDiscoveryConfig handles peer discovery configuration commands
+func (e *Executor) handleDiscoveryConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		secret := "not set"
+		if e.config.DiscoverySecret != "" {
+			secret = "set"
+		}
+
+		output := fmt.Sprintf(`
+╭────────────────── 📡 Discovery Settings ─────────────────╮
+
+  • Transport: %s
+  • Shared secret: %s
+  • Advertise this machine: %s
+  • Hostname and username: %s
+  • Require authentication: %s
+
+  Transports:
+   • mdns        mDNS (Avahi/Bonjour), works without setup
+   • broadcast   Signed UDP broadcasts on port %d, for networks
+                 that filter mDNS
+   • both        Use mDNS and broadcasts together
+
+  Commands:
+   • config:discovery transport <transport>   Choose the transport
+   • config:discovery secret generate         Create a shared secret
+   • config:discovery secret <secret>         Use another machine's secret
+   • config:discovery advertise on|off        Announce this machine
+   • config:discovery hide-identity on|off    Hide hostname and username
+   • config:discovery require-auth on|off     Only talk to peers with the secret
+╰──────────────────────────────────────────────────────────╯
+`, e.config.DiscoveryTransport, secret, onOff(e.config.DiscoveryAdvertise), hiddenOrAdvertised(discovery.ConfigOptions(e.config).HideIdentity),
+			onOff(e.config.DiscoveryRequireAuth), discovery.BroadcastPort)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	switch args[0] {
+	case "transport":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing transport. Usage: config:discovery transport " + strings.Join(discovery.Transports, "|"),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		// Creating a discoverer checks the transport and that a secret is set when needed
+		transport := strings.ToLower(args[1])
+		opts := discovery.ConfigOptions(e.config)
+		opts.Transport = transport
+		if _, err := discovery.New(opts); err != nil {
+			return &Result{
+				Output:     err.Error(),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.DiscoveryTransport = transport
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     fmt.Sprintf("Discovery transport set to: %s", transport),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "secret":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing secret. Usage: config:discovery secret generate|<secret>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		secret := args[1]
+		if secret == "generate" {
+			buf := make([]byte, 32)
+			if _, err := rand.Read(buf); err != nil {
+				return &Result{
+					Output:     fmt.Sprintf("Failed to generate a secret: %v", err),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			secret = hex.EncodeToString(buf)
+		} else if len(secret) < minDiscoverySecretLength {
+			return &Result{
+				Output:     fmt.Sprintf("The secret must be at least %d characters long", minDiscoverySecretLength),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.DiscoverySecret = secret
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		output := "Discovery secret updated."
+		if args[1] == "generate" {
+			output = fmt.Sprintf("Discovery secret generated: %s\n\nRun 'lumo config:discovery secret %s' on every machine that should find this one.", secret, secret)
+		}
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "advertise", "hide-identity", "require-auth":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing argument. Usage: config:discovery %s on|off", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		var enabled bool
+		switch strings.ToLower(args[1]) {
+		case "on", "true", "yes", "1":
+			enabled = true
+		case "off", "false", "no", "0":
+			enabled = false
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		var message string
+		switch args[0] {
+		case "advertise":
+			e.config.DiscoveryAdvertise = enabled
+			message = fmt.Sprintf("Advertising this machine: %s", onOff(enabled))
+		case "hide-identity":
+			e.config.DiscoveryHideIdentity = enabled
+			message = fmt.Sprintf("Hiding hostname and username: %s", onOff(enabled))
+		case "require-auth":
+			if enabled && e.config.DiscoverySecret == "" {
+				return &Result{
+					Output:     "Authenticated discovery needs a shared secret. Run 'lumo config:discovery secret generate' first.",
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			e.config.DiscoveryRequireAuth = enabled
+			message = fmt.Sprintf("Authenticated discovery: %s", onOff(enabled))
+			if enabled {
+				message += "\nOnly signed broadcasts are used; mDNS is off until this is disabled."
+			}
+		}
+
+		if err := e.config.Save(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error saving configuration: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		return &Result{
+			Output:     message + "\nRestart running servers and receivers to apply.",
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown discovery command: %s. Use 'show', 'transport', 'secret', 'advertise', 'hide-identity', or 'require-auth'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// featureStatus returns how the help screen shows whether a feature is enabled
+func (e *Executor) featureStatus(name string) string {
+	if e.config.FeatureEnabled(name) {
+		return "ENABLED"
+	}
+	return "DISABLED"
+}
+
+// featureStatusLines lists every feature and whether it is enabled
+func (e *Executor) featureStatusLines(indent string) string {
+	var b strings.Builder
+	for _, feature := range config.Features {
+		status := e.featureStatus(feature.Name)
+		if missing := e.config.MissingRequirements(feature.Name); len(missing) > 0 && e.config.FeatureEnabled(feature.Name) {
+			status += fmt.Sprintf(" (needs %s)", strings.Join(missing, ", "))
+		}
+		fmt.Fprintf(&b, "%s• %s: %s\n", indent, feature.Description, status)
+	}
+	return b.String()
+}
+
+// handleFeatureConfig handles switching optional features on and off
+func (e *Executor) handleFeatureConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "list" || args[0] == "show" {
+		var features strings.Builder
+		for _, feature := range config.Features {
+			line := fmt.Sprintf("  • %-15s %-4s %s", feature.Name, onOff(e.config.FeatureEnabled(feature.Name)), feature.Description)
+			if len(feature.Requires) > 0 {
+				line += fmt.Sprintf(" (requires %s)", strings.Join(feature.Requires, ", "))
+			}
+			features.WriteString(line + "\n")
+		}
+
+		output := fmt.Sprintf(`
+╭─────────────────── 🚩 Features ─────────────────────────╮
+
+%s
+  Commands:
+   • config:feature enable <name>...   Turn features on
+   • config:feature disable <name>...  Turn features off, with
+                                       the features needing them
+╰──────────────────────────────────────────────────────────╯
+`, features.String())
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if args[0] != "enable" && args[0] != "disable" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown feature command: %s. Use 'list', 'enable', or 'disable'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if len(args) < 2 {
+		return &Result{
+			Output:     fmt.Sprintf("Missing feature. Usage: config:feature %s <name>... (see config:feature list)", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	// Features are changed in order, so "enable agent agent-repl" works,
+	// and none are changed if one cannot be
+	next := *e.config
+	var messages []string
+	for _, name := range args[1:] {
+		name = strings.ToLower(name)
+		if args[0] == "enable" {
+			if err := next.EnableFeature(name); err != nil {
+				return &Result{
+					Output:     fmt.Sprintf("Cannot enable %s: %v", name, err),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			messages = append(messages, fmt.Sprintf("Enabled %s.", name))
+			continue
+		}
+
+		dependents, err := next.DisableFeature(name)
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Cannot disable %s: %v", name, err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		message := fmt.Sprintf("Disabled %s.", name)
+		switch len(dependents) {
+		case 0:
+		case 1:
+			message = fmt.Sprintf("Disabled %s and %s, which requires it.", name, dependents[0])
+		default:
+			message = fmt.Sprintf("Disabled %s and %s, which require it.", name, strings.Join(dependents, ", "))
+		}
+		messages = append(messages, message)
+	}
+	*e.config = next
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     strings.Join(messages, "\n"),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleLimitsConfig handles configuration of how many requests may be in
+// flight to each AI provider
+func (e *Executor) handleLimitsConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		var b strings.Builder
+		for _, name := range ai.ProviderNames() {
+			limit := "no limit"
+			if n := e.config.ProviderConcurrency[name]; n > 0 {
+				limit = fmt.Sprintf("%d at a time", n)
+			}
+			fmt.Fprintf(&b, "  • %s: %s\n", name, limit)
+		}
+		output := fmt.Sprintf(`
+╭─────────────────── 🚦 Provider Limits ──────────────────╮
+
+%s  • Queue Timeout: %d seconds
+
+  Requests over a provider's limit wait for a free slot,
+  and fail if none frees up before the timeout. The
+  server, chat bots, and the agent share the limits.
+
+  Commands:
+   • config:limits set <provider> <n>  Allow n requests at a time (0 for no limit)
+   • config:limits timeout <seconds>   Set how long requests wait
+╰──────────────────────────────────────────────────────────╯
+`, b.String(), e.config.ProviderQueueTimeoutSeconds)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch strings.ToLower(args[0]) {
+	case "set":
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing value. Usage: config:limits set <provider> <n>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		provider := strings.ToLower(args[1])
+		if _, ok := ai.Lookup(provider); !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown provider: %s (available: %s)", args[1], strings.Join(ai.ProviderNames(), ", ")),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		limit, err := strconv.Atoi(args[2])
+		if err != nil || limit < 0 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid limit: %s. Use a whole number, or 0 for no limit.", args[2]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if e.config.ProviderConcurrency == nil {
+			e.config.ProviderConcurrency = make(map[string]int)
+		}
+		e.config.ProviderConcurrency[provider] = limit
+		if limit == 0 {
+			message = fmt.Sprintf("Requests to %s are no longer limited.", provider)
+		} else {
+			message = fmt.Sprintf("Up to %d requests to %s are sent at a time.", limit, provider)
+		}
+	case "timeout":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing value. Usage: config:limits timeout <seconds>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		seconds, err := strconv.Atoi(args[1])
+		if err != nil || seconds < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use a whole number of 1 or more.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.ProviderQueueTimeoutSeconds = seconds
+		message = fmt.Sprintf("Requests wait up to %d seconds for a free slot.", seconds)
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown limits command: %s. Use 'show', 'set', or 'timeout'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	ai.SetLimiter(ai.LimiterFromConfig(e.config))
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
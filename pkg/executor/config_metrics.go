@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/metrics"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleMetricsConfig handles turning the local usage metrics on and off
+// and deleting them
+func (e *Executor) handleMetricsConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		path, _ := metrics.Path()
+		events, _ := metrics.Load(time.Time{})
+		output := fmt.Sprintf(`
+╭─────────────────── 📈 Local Metrics ────────────────────╮
+
+  • Collecting: %s
+  • Commands Recorded: %d
+  • Stored In: %s
+
+  Metrics count which kinds of command run and how long
+  they take, for 'lumo stats' and the web dashboard. They
+  never leave this machine and hold no command text.
+
+  Commands:
+   • config:metrics enable    Start collecting
+   • config:metrics disable   Stop collecting, keeping the data
+   • config:metrics purge     Delete everything collected
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.EnableMetrics), len(events), path)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "enable", "on":
+		e.config.EnableMetrics = true
+		message = "Local metrics enabled. See them with 'lumo stats'; they are kept on this machine only."
+	case "disable", "off":
+		e.config.EnableMetrics = false
+		message = "Local metrics disabled. What was collected is kept; delete it with 'config:metrics purge'."
+	case "purge":
+		purged, err := metrics.Purge()
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error deleting metrics: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		message = fmt.Sprintf("Deleted the metrics of %d commands.", purged)
+		if e.config.EnableMetrics {
+			message += " Collection is still on; stop it with 'config:metrics disable'."
+		}
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown metrics command: %s. Use 'show', 'enable', 'disable', or 'purge'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	// Choosing here answers the first-run question too
+	e.config.MetricsAsked = true
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
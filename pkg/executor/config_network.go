@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// handleNetworkConfig handles monthly network cap configuration commands
+func (e *Executor) handleNetworkConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		limit := "none"
+		if e.config.NetworkCapMB > 0 {
+			limit = formatBytes(e.config.NetworkCapBytes()) + " a month"
+		}
+		used, _ := usage.MonthNetwork(time.Now())
+		output := fmt.Sprintf(`
+╭─────────────────── 📶 Network Cap ──────────────────────╮
+
+  • Monthly Cap: %s
+  • Used This Month: %s
+  • When Reached: %s
+
+  Counts files sent and received with connect and requests
+  to cloud AI providers. Once the cap is reached, file
+  transfers and the daemon warn about it; with 'pause',
+  scheduled speed tests also stop until next month.
+
+  Commands:
+   • config:network cap <size>          Set the cap, e.g. 20GB
+   • config:network off                 Remove the cap
+   • config:network action warn|pause   Choose what happens
+╰──────────────────────────────────────────────────────────╯
+`, limit, formatBytes(used), e.config.NetworkCapAction)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "cap":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing size. Usage: config:network cap <size>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		mb, ok := parseMegabytes(args[1])
+		if !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid size: %s. Use megabytes or a unit, like 500MB or 20GB.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.NetworkCapMB = mb
+		message = fmt.Sprintf("Monthly network cap set to %s.", formatBytes(e.config.NetworkCapBytes()))
+	case "off":
+		e.config.NetworkCapMB = 0
+		message = "Monthly network cap removed."
+	case "action":
+		if len(args) < 2 {
+			return &Result{
+				Output:     "Missing action. Usage: config:network action warn|pause",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		switch action := strings.ToLower(args[1]); action {
+		case config.NetworkCapWarn, config.NetworkCapPause:
+			e.config.NetworkCapAction = action
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid action: %s. Use 'warn' or 'pause'.", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		message = "Lumo will warn once the network cap is reached."
+		if e.config.NetworkCapAction == config.NetworkCapPause {
+			message = "Scheduled speed tests will pause once the network cap is reached."
+		}
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown network command: %s. Use 'show', 'cap', 'off', or 'action'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// parseMegabytes reads a size like 500, 500MB, or 1.5GB as megabytes
+func parseMegabytes(s string) (int, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	factor := 1.0
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{{"TB", 1 << 20}, {"GB", 1 << 10}, {"MB", 1}, {"T", 1 << 20}, {"G", 1 << 10}, {"M", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, factor = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.factor
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	mb := int(n*factor + 0.5)
+	return mb, mb > 0
+}
@@ -0,0 +1,168 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// personaName matches names usable with --persona=<name>
+var personaName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// handlePersonaConfig handles persona configuration commands
+func (e *Executor) handlePersonaConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "list" {
+		var b strings.Builder
+		for _, name := range ai.PersonaNames(e.config) {
+			marker := "  "
+			if name == e.config.DefaultPersona {
+				marker = "* "
+			}
+			origin := "built in"
+			if _, ok := e.config.Personas[name]; ok {
+				origin = "custom"
+			}
+			b.WriteString(fmt.Sprintf("   %s%-14s %s\n", marker, name, origin))
+		}
+		defaultPersona := e.config.DefaultPersona
+		if defaultPersona == "" {
+			defaultPersona = "none (Lumo's own instructions)"
+		}
+
+		output := fmt.Sprintf(`
+╭─────────────────── 🎭 Personas ─────────────────────────╮
+
+  Default: %s
+
+%s
+  Use one for a single question, or for a chat session:
+    lumo ask:--persona=tutor how do I find large files
+    chat> persona tutor
+
+  Commands:
+   • config:persona show <name>           Show a persona's prompt
+   • config:persona set <name> <prompt>   Define a persona
+   • config:persona remove <name>         Remove a custom persona
+   • config:persona default <name|none>   Choose the default persona
+╰──────────────────────────────────────────────────────────╯
+`, defaultPersona, b.String())
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) < 2 {
+		return &Result{
+			Output:     fmt.Sprintf("Missing persona name. Usage: config:persona %s <name>", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	name := strings.ToLower(args[1])
+
+	var message string
+	switch args[0] {
+	case "show":
+		instructions, ok := ai.Persona(e.config, name)
+		if !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown persona: %s", name),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		return &Result{
+			Output:     fmt.Sprintf("🎭 %s\n\n%s", name, instructions),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "set":
+		if !personaName.MatchString(name) {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid persona name: %s. Use letters, digits, '-' and '_'.", name),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing prompt. Usage: config:persona set <name> <prompt>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if e.config.Personas == nil {
+			e.config.Personas = make(map[string]string)
+		}
+		e.config.Personas[name] = strings.Join(args[2:], " ")
+		message = fmt.Sprintf("Persona %s saved. Use it with: lumo ask:--persona=%s <question>", name, name)
+
+	case "remove":
+		if _, ok := e.config.Personas[name]; !ok {
+			output := fmt.Sprintf("No custom persona named %s", name)
+			if _, builtin := ai.BuiltinPersonas[name]; builtin {
+				output = fmt.Sprintf("%s is built in and cannot be removed", name)
+			}
+			return &Result{
+				Output:     output,
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		delete(e.config.Personas, name)
+		message = fmt.Sprintf("Removed persona %s", name)
+		if _, builtin := ai.BuiltinPersonas[name]; builtin {
+			message += "; the built-in one of the same name is used again"
+		} else if e.config.DefaultPersona == name {
+			e.config.DefaultPersona = ""
+			message += " and went back to no default persona"
+		}
+
+	case "default":
+		if name == "none" || name == "off" {
+			name = ""
+		} else if _, ok := ai.Persona(e.config, name); !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown persona: %s. Available: %s", name, strings.Join(ai.PersonaNames(e.config), ", ")),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.DefaultPersona = name
+		if instructions, ok := ai.Persona(e.config, name); ok {
+			e.chatManager.SetInstructions(instructions)
+			message = fmt.Sprintf("Default persona set to: %s", name)
+		} else {
+			e.chatManager.SetInstructions(ai.ChatInstructions)
+			message = "Default persona removed; Lumo's own instructions are used."
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown persona command: %s. Use 'list', 'show', 'set', 'remove', or 'default'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
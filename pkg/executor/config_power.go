@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/system"
+)
+
+// handlePowerConfig handles battery and power configuration commands
+func (e *Executor) handlePowerConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		output := fmt.Sprintf(`
+╭─────────────────── 🔋 Power Settings ────────────────────╮
+
+  • Power Mode: %s
+  • Battery Saving Active: %s
+  • Prefer Local Model on Battery: %s
+  • Skip Scheduled Speed Tests on Battery: %s
+  • Health Check Slowdown on Battery: x%d
+
+  Commands:
+   • config:power mode auto|battery|ac   Detect, force, or ignore battery
+   • config:power prefer-local on|off    Use Ollama while on battery
+   • config:power skip-speedtest on|off  Skip scheduled speed tests
+   • config:power health-factor <n>      Slow scheduled health checks
+╰──────────────────────────────────────────────────────────╯
+`, e.config.PowerMode, onOff(system.ShouldConservePower(e.config.PowerMode)),
+			onOff(e.config.BatteryPreferLocalModel), onOff(e.config.BatterySkipSpeedTests),
+			e.config.BatteryHealthCheckFactor)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) < 2 {
+		return &Result{
+			Output:     fmt.Sprintf("Missing value. Usage: config:power %s <value>", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	value := strings.ToLower(args[1])
+	var message string
+
+	switch args[0] {
+	case "mode":
+		if value != system.PowerModeAuto && value != system.PowerModeBattery && value != system.PowerModeAC {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid power mode: %s. Use 'auto', 'battery', or 'ac'.", value),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.PowerMode = value
+		message = fmt.Sprintf("Power mode set to %s", value)
+
+	case "prefer-local", "skip-speedtest":
+		var enabled bool
+		switch value {
+		case "on", "true", "yes", "1":
+			enabled = true
+		case "off", "false", "no", "0":
+			enabled = false
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Invalid value: %s. Use 'on' or 'off'.", value),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+
+		if args[0] == "prefer-local" {
+			e.config.BatteryPreferLocalModel = enabled
+			message = fmt.Sprintf("Prefer local model on battery: %s", onOff(enabled))
+		} else {
+			e.config.BatterySkipSpeedTests = enabled
+			message = fmt.Sprintf("Skip scheduled speed tests on battery: %s", onOff(enabled))
+		}
+
+	case "health-factor":
+		factor, err := strconv.Atoi(value)
+		if err != nil || factor < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid factor: %s. Use a number of 1 or more.", value),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.BatteryHealthCheckFactor = factor
+		message = fmt.Sprintf("Scheduled health checks run %dx less often on battery", factor)
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown power command: %s. Use 'show', 'mode', 'prefer-local', 'skip-speedtest', or 'health-factor'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/privacy"
+)
+
+// handlePrivacyConfig handles privacy mode configuration commands
+func (e *Executor) handlePrivacyConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		strict := privacy.IsStrict(e.config.PrivacyMode)
+		output := fmt.Sprintf(`
+╭────────────────── 🔒 Privacy Settings ───────────────────╮
+
+  • Privacy Mode: %s
+  • AI Provider: %s
+  • Command Logging: %s
+  • Speed Tests: %s
+  • Agent Steps That Upload Data: %s
+  • Hostname and Username on the LAN: %s
+  • Local Usage Metrics: %s (never sent anywhere)
+
+  Commands:
+   • config:privacy strict     Keep prompts and data on this machine
+   • config:privacy standard   Allow cloud providers again
+╰──────────────────────────────────────────────────────────╯
+`, e.config.PrivacyMode, e.config.AIProvider,
+			onOff(e.config.EnableLogging && !strict), onOff(!strict), blockedOrAllowed(strict),
+			hiddenOrAdvertised(strict || e.config.DiscoveryHideIdentity), onOff(e.config.EnableMetrics))
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch strings.ToLower(args[0]) {
+	case privacy.ModeStrict, "on":
+		e.config.PrivacyMode = privacy.ModeStrict
+		e.config.AIProvider = privacy.LocalProvider
+		e.aiClient = ai.NewOllamaClient(e.config.OllamaURL, e.config.OllamaModel)
+		e.chatManager = newChatManager(e.config, e.aiClient)
+		message = fmt.Sprintf(`Strict privacy mode enabled:
+  • AI requests only go to the local Ollama model (%s)
+  • Command logging is off
+  • Speed tests and scheduled speed tests are disabled
+  • Reports are not emailed
+  • Agent steps that would upload data are blocked
+  • Discovery announcements leave out the hostname and username`, e.config.OllamaModel)
+		if !e.isOllamaAvailable() {
+			message += fmt.Sprintf("\nWarning: Ollama is not reachable at %s", e.config.OllamaURL)
+		}
+
+	case privacy.ModeStandard, "off":
+		e.config.PrivacyMode = privacy.ModeStandard
+		message = fmt.Sprintf("Standard privacy mode enabled. The AI provider is still %s; change it with 'config:provider set <provider>'.", e.config.AIProvider)
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown privacy command: %s. Use 'show', 'strict', or 'standard'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// blockedOrAllowed formats whether strict privacy mode blocks a feature
+func blockedOrAllowed(blocked bool) string {
+	if blocked {
+		return "blocked"
+	}
+	return "allowed"
+}
+
+// hiddenOrAdvertised describes whether discovery announcements include the machine's identity
+func hiddenOrAdvertised(hidden bool) string {
+	if hidden {
+		return "hidden"
+	}
+	return "advertised"
+}
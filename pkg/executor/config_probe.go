@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleTestConfig sends a tiny prompt to every configured AI provider and
+// reports whether each answered, how quickly, with which model, and about
+// what it cost. The mock provider is only tested while it is in use.
+func (e *Executor) handleTestConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	names := ai.ProviderNames()
+	if len(args) > 0 {
+		if _, ok := ai.Lookup(args[0]); !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown AI provider: %s. Use one of: %s", args[0], strings.Join(names, ", ")),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		names = args[:1]
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭─────────────────── 🧪 AI Provider Test ───────────────────╮\n\n")
+	tested, answered, activeFailed := 0, 0, false
+	for _, name := range names {
+		if name == string(ai.ProviderMock) && e.config.AIProvider != name && len(args) == 0 {
+			continue
+		}
+		result := ai.Probe(name, e.config)
+		active := ""
+		if name == e.config.AIProvider {
+			active = " (in use)"
+		}
+		label := result.Provider.DisplayName + active
+
+		switch {
+		case result.Skipped != "":
+			b.WriteString(fmt.Sprintf("  ⏭️  %-18s %s\n", label, result.Skipped))
+			activeFailed = activeFailed || active != ""
+		case result.Err != nil:
+			tested++
+			b.WriteString(fmt.Sprintf("  ❌ %-18s %s\n", label, result.Model))
+			b.WriteString(fmt.Sprintf("     %s\n", strings.ReplaceAll(lumoerr.Format(result.Err), "\n", "\n     ")))
+			activeFailed = activeFailed || active != ""
+		default:
+			tested++
+			answered++
+			b.WriteString(fmt.Sprintf("  ✅ %-18s %-24s %5d ms  %s\n", label, result.Model,
+				result.Latency.Milliseconds(), formatProbeCost(result)))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("\n  %d of %d tested providers answered.", answered, tested))
+	if tested > 0 {
+		b.WriteString(" Test requests count toward 'lumo usage'.")
+	}
+	b.WriteString("\n╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    activeFailed || answered < tested || (len(args) > 0 && answered == 0),
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatProbeCost describes the tokens and estimated cost of a test request
+func formatProbeCost(result ai.ProbeResult) string {
+	if !result.HasUsage {
+		return "no token counts"
+	}
+	tokens := result.Usage.PromptTokens + result.Usage.CompletionTokens
+	cost, priced := result.Cost()
+	switch {
+	case !priced:
+		return fmt.Sprintf("%d tokens, unknown price", tokens)
+	case cost == 0:
+		return fmt.Sprintf("%d tokens, free", tokens)
+	default:
+		return fmt.Sprintf("%d tokens, about $%.6f", tokens, cost)
+	}
+}
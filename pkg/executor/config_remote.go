@@ -0,0 +1,165 @@
+package executor
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// handleRemoteConfig handles the logins connect uses for SFTP servers and
+// WebDAV shares
+func (e *Executor) handleRemoteConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		hosts := make([]string, 0, len(e.config.ConnectRemotes))
+		for host := range e.config.ConnectRemotes {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+
+		var b strings.Builder
+		for _, host := range hosts {
+			login := e.config.ConnectRemotes[host]
+			details := []string{"as " + login.Username}
+			if login.Password != "" {
+				details = append(details, "with a password")
+			}
+			if login.KeyFile != "" {
+				details = append(details, "with the key "+login.KeyFile)
+			}
+			fmt.Fprintf(&b, "  • %s: %s\n", host, strings.Join(details, ", "))
+		}
+		if b.Len() == 0 {
+			b.WriteString("  • none\n")
+		}
+
+		output := fmt.Sprintf(`
+╭─────────────────── 🌐 Remote Logins ────────────────────╮
+
+  Saved logins:
+%s
+  'lumo connect sftp://host/path' and WebDAV URLs sign in
+  with the saved login for the host, and ask for what is
+  missing. Passwords are kept in the configuration file.
+
+  Commands:
+   • config:remote add <host> <user> [password]  Save a login
+   • config:remote key <host> <file>   Sign in to SFTP with a key
+   • config:remote remove <host>       Forget a login
+╰──────────────────────────────────────────────────────────╯
+`, b.String())
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) < 2 {
+		return &Result{
+			Output:     fmt.Sprintf("Missing host. Usage: config:remote %s <host> ...", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	host := remoteHost(args[1])
+
+	var message string
+	switch args[0] {
+	case "add":
+		if len(args) < 3 || len(args) > 4 {
+			return &Result{
+				Output:     "Usage: config:remote add <host> <user> [password]",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		login := e.config.ConnectRemotes[host]
+		login.Username = args[2]
+		login.Password = ""
+		if len(args) == 4 {
+			login.Password = args[3]
+		}
+		e.setRemoteLogin(host, login)
+		message = fmt.Sprintf("Saved the login for %s as %s.", host, login.Username)
+		if login.Password == "" {
+			message += " The password is asked for when needed."
+		}
+	case "key":
+		if len(args) != 3 {
+			return &Result{
+				Output:     "Usage: config:remote key <host> <file>",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		keyFile, err := filepath.Abs(args[2])
+		if err == nil {
+			_, err = os.Stat(keyFile)
+		}
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Cannot read the key: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		login := e.config.ConnectRemotes[host]
+		login.KeyFile = keyFile
+		e.setRemoteLogin(host, login)
+		message = fmt.Sprintf("SFTP logins to %s try the key %s first.", host, keyFile)
+	case "remove":
+		if _, ok := e.config.ConnectRemotes[host]; !ok {
+			return &Result{
+				Output:     fmt.Sprintf("No login is saved for %s.", host),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		delete(e.config.ConnectRemotes, host)
+		message = fmt.Sprintf("Forgot the login for %s.", host)
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown remote command: %s. Use 'show', 'add', 'key', or 'remove'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// setRemoteLogin saves the login for a host
+func (e *Executor) setRemoteLogin(host string, login config.RemoteLogin) {
+	if e.config.ConnectRemotes == nil {
+		e.config.ConnectRemotes = make(map[string]config.RemoteLogin)
+	}
+	e.config.ConnectRemotes[host] = login
+}
+
+// remoteHost returns the host logins are saved under, from a host name or
+// a whole URL
+func remoteHost(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return strings.ToLower(u.Hostname())
+	}
+	return strings.ToLower(target)
+}
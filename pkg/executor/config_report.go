@@ -0,0 +1,203 @@
+package executor
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/email"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/privacy"
+	"github.com/agnath18K/lumo/pkg/report"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// handleReportConfig handles configuration of emailed reports
+func (e *Executor) handleReportConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		server := "not set"
+		if e.config.SMTPServer != "" {
+			server = fmt.Sprintf("%s, from %s", e.config.SMTPServer, e.config.SMTPFrom)
+			if e.config.SMTPUsername != "" {
+				server += fmt.Sprintf(", as %s", e.config.SMTPUsername)
+			}
+		}
+
+		var reports strings.Builder
+		for _, name := range report.Names {
+			sent := "never sent"
+			if last := report.LastSent(name); !last.IsZero() {
+				sent = "last sent " + utils.FormatRelative(last)
+			}
+			fmt.Fprintf(&reports, "  • %s: %s (%s)\n", name, listOrNone(e.config.ReportRecipients[name]), sent)
+		}
+
+		output := fmt.Sprintf(`
+╭─────────────────── 📧 Email Reports ────────────────────╮
+
+  • SMTP server: %s
+
+  Recipients:
+%s
+  The server daemon emails each report with recipients once
+  a week: "system" is the system report and health check,
+  "speedtest" summarizes the week's speed tests.
+
+  Commands:
+   • config:report smtp <host:port> <from> [user] [password]
+                                        Set the SMTP server
+   • config:report smtp off             Forget the SMTP server
+   • config:report email <report> <address>...  Set recipients
+   • config:report email <report> off   Stop emailing a report
+   • config:report send <report>        Email a report now
+╰──────────────────────────────────────────────────────────╯
+`, server, reports.String())
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "smtp":
+		if len(args) == 2 && args[1] == "off" {
+			e.config.SMTPServer = ""
+			e.config.SMTPFrom = ""
+			e.config.SMTPUsername = ""
+			e.config.SMTPPassword = ""
+			message = "The SMTP server was removed; reports are no longer emailed."
+			break
+		}
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing arguments. Usage: config:report smtp <host:port> <from-address> [username] [password]",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if _, port, err := net.SplitHostPort(args[1]); err != nil || port == "" {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid SMTP server: %s. Use host:port, e.g. smtp.example.com:587", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if err := email.ValidateAddress(args[2]); err != nil {
+			return &Result{
+				Output:     err.Error(),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.SMTPServer = args[1]
+		e.config.SMTPFrom = args[2]
+		e.config.SMTPUsername = ""
+		e.config.SMTPPassword = ""
+		if len(args) > 3 {
+			e.config.SMTPUsername = args[3]
+		}
+		if len(args) > 4 {
+			e.config.SMTPPassword = args[4]
+		}
+		message = fmt.Sprintf("Reports are emailed through %s.", args[1])
+
+	case "email":
+		if len(args) < 3 {
+			return &Result{
+				Output:     "Missing arguments. Usage: config:report email <report> <address>... or config:report email <report> off",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		name := args[1]
+		if !report.IsReport(name) {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown report: %s. Use %s.", name, strings.Join(report.Names, " or ")),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if len(args) == 3 && args[2] == "off" {
+			delete(e.config.ReportRecipients, name)
+			message = fmt.Sprintf("The %s report is no longer emailed.", name)
+			break
+		}
+		for _, address := range args[2:] {
+			if err := email.ValidateAddress(address); err != nil {
+				return &Result{
+					Output:     err.Error(),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+		}
+		if e.config.ReportRecipients == nil {
+			e.config.ReportRecipients = make(map[string][]string)
+		}
+		e.config.ReportRecipients[name] = args[2:]
+		message = fmt.Sprintf("The %s report is emailed weekly to %s.", name, strings.Join(args[2:], ", "))
+		if e.config.SMTPServer == "" {
+			message += "\nSet the SMTP server with 'config:report smtp' to send it."
+		}
+
+	case "send":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing report. Usage: config:report send <%s>", strings.Join(report.Names, "|")),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if privacy.IsStrict(e.config.PrivacyMode) {
+			return &Result{
+				Output:     "Reports are not emailed in strict privacy mode.",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if !report.IsReport(args[1]) {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown report: %s. Use %s.", args[1], strings.Join(report.Names, " or ")),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if err := report.Email(e.config, args[1], time.Now()); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Failed to email the %s report: %v", args[1], err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		return &Result{
+			Output:     fmt.Sprintf("Emailed the %s report to %s.", args[1], strings.Join(e.config.ReportRecipients[args[1]], ", ")),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown report command: %s. Use 'show', 'smtp', 'email', or 'send'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message + "\nRestart the server daemon to apply.",
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/speedtest"
+)
+
+// handleSpeedTestConfig handles speed test configuration commands
+func (e *Executor) handleSpeedTestConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" || (args[0] == "backend" && (len(args) == 1 || args[1] == "list")) {
+		var backends strings.Builder
+		for _, backend := range speedtest.Backends() {
+			status := ""
+			if !backend.Available() {
+				status = " [not installed]"
+			}
+			backends.WriteString(fmt.Sprintf("   • %-11s %s%s\n", backend.Name, backend.Description, status))
+		}
+
+		output := fmt.Sprintf(`
+╭────────────────── 🚀 Speed Test Settings ────────────────╮
+
+  • Speed Tests: %s
+  • Backend: %s
+  • Timeout: %d seconds
+
+  Backends:
+%s
+  Commands:
+   • config:speedtest backend set <backend>   Choose the backend
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.EnableSpeedTest), e.config.SpeedTestBackend, e.config.SpeedTestTimeout, backends.String())
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if args[0] != "backend" || args[1] != "set" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown speedtest command: %s. Use 'show' or 'backend set <backend>'.", strings.Join(args, " ")),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(args) < 3 {
+		return &Result{
+			Output:     "Missing backend. Usage: config:speedtest backend set builtin|cloudflare|ookla",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	// Creating a tester checks that the backend exists and can run here
+	backend := strings.ToLower(args[2])
+	if _, err := speedtest.New(backend); err != nil {
+		output := err.Error()
+		if backend == speedtest.BackendOokla {
+			output += ". Install the Ookla speedtest CLI from https://www.speedtest.net/apps/cli"
+		}
+		return &Result{
+			Output:     output,
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	e.config.SpeedTestBackend = backend
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     fmt.Sprintf("Speed test backend set to: %s", backend),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// handleTimeConfig handles configuration of how times are shown
+func (e *Executor) handleTimeConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		now := time.Now()
+		output := fmt.Sprintf(`
+╭─────────────────── 🕒 Time Format ──────────────────────╮
+
+  • Format: %s
+  • Example: %s
+  • Relative: %s
+
+  History, transfers, notes, the scheduler, and reports
+  show times this way. The local format shows how long
+  ago something happened and timestamps in your timezone;
+  iso shows ISO-8601 timestamps for scripts.
+
+  Commands:
+   • config:time format local|iso    Choose the format
+╰──────────────────────────────────────────────────────────╯
+`, utils.TimeStyle(), utils.FormatTimestamp(now), utils.FormatRelative(now.Add(-3*time.Minute)))
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if args[0] != "format" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown time command: %s. Use 'show' or 'format'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if len(args) < 2 {
+		return &Result{
+			Output:     "Missing format. Usage: config:time format local|iso",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	switch style := strings.ToLower(args[1]); style {
+	case utils.TimeStyleLocal, utils.TimeStyleISO:
+		e.config.TimeFormat = style
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Invalid format: %s. Use 'local' or 'iso'.", args[1]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	utils.SetTimeStyle(e.config.TimeFormat)
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     fmt.Sprintf("Times are now shown like %s.", utils.FormatTimestamp(time.Now())),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/timetrack"
+)
+
+// handleTrackingConfig handles turning time tracking on and off, the
+// applications it leaves out, and deleting what it recorded
+func (e *Executor) handleTrackingConfig(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 || args[0] == "show" {
+		path, _ := timetrack.Path()
+		samples, _ := timetrack.Load(time.Time{}, time.Now().AddDate(1, 0, 0))
+		excluded := "None"
+		if len(e.config.TimeTrackingExclude) > 0 {
+			excluded = strings.Join(e.config.TimeTrackingExclude, ", ")
+		}
+		output := fmt.Sprintf(`
+╭─────────────────── ⏳ Time Tracking ────────────────────╮
+
+  • Tracking: %s
+  • Excluded Apps: %s
+  • Samples Recorded: %d
+  • Stored In: %s
+
+  The daemon notes the application in focus every %s
+  for 'lumo today', skipping idle time. Window titles are
+  never recorded, and nothing leaves this machine.
+
+  Commands:
+   • config:tracking enable         Start tracking
+   • config:tracking disable        Stop tracking, keeping the data
+   • config:tracking exclude <app>  Never record an application
+   • config:tracking include <app>  Record an excluded one again
+   • config:tracking purge          Delete everything recorded
+╰──────────────────────────────────────────────────────────╯
+`, onOff(e.config.EnableTimeTracking), excluded, len(samples), path, timetrack.SampleInterval)
+
+		return &Result{
+			Output:     output,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var message string
+	switch args[0] {
+	case "enable", "on":
+		e.config.EnableTimeTracking = true
+		message = "Time tracking enabled. Restart the daemon with 'lumo server:stop' and 'lumo server:start' to start it, then see your day with 'lumo today'."
+	case "disable", "off":
+		e.config.EnableTimeTracking = false
+		message = "Time tracking disabled; restart the daemon to stop it. What was recorded is kept; delete it with 'config:tracking purge'."
+	case "exclude", "include":
+		if len(args) < 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing application. Usage: config:tracking %s <app>", args[0]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		app := timetrack.AppName(strings.Join(args[1:], " "))
+		var kept []string
+		for _, excluded := range e.config.TimeTrackingExclude {
+			if !strings.EqualFold(excluded, app) {
+				kept = append(kept, excluded)
+			}
+		}
+		if args[0] == "exclude" {
+			kept = append(kept, app)
+			message = fmt.Sprintf("%s will not be recorded, and is left out of 'lumo today'. Restart the daemon to apply.", app)
+		} else if len(kept) == len(e.config.TimeTrackingExclude) {
+			return &Result{
+				Output:     fmt.Sprintf("%s is not excluded", app),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		} else {
+			message = fmt.Sprintf("%s will be recorded again. Restart the daemon to apply.", app)
+		}
+		e.config.TimeTrackingExclude = kept
+	case "purge":
+		purged, err := timetrack.Purge()
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error deleting the time tracking log: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		return &Result{
+			Output:     fmt.Sprintf("Deleted %d time tracking samples.", purged),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown tracking command: %s. Use 'show', 'enable', 'disable', 'exclude', 'include', or 'purge'.", args[0]),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -2,13 +2,21 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net"
+	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/contacts"
+	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
 	"github.com/agnath18K/lumo/pkg/nlp"
 	"github.com/agnath18K/lumo/pkg/utils"
 )
@@ -22,6 +30,12 @@ func (e *Executor) executeConnectCommand(cmd *nlp.Command) (*Result, error) {
 	var downloadPath string
 	port := 8080
 	useChunked := false
+	staged := false
+	useWebRTC := false
+	receive := false
+	parallel := connect.DefaultParallelSends
+	var signalURL, code string
+	var positionals []string
 
 	// Parse options
 	args := strings.Fields(intent)
@@ -51,30 +65,93 @@ func (e *Executor) executeConnectCommand(cmd *nlp.Command) (*Result, error) {
 		if arg == "--chunked" || arg == "-c" {
 			useChunked = true
 		}
+
+		// Check for encrypted staging option
+		if arg == "--staged" {
+			staged = true
+		}
+
+		// Check for WebRTC transport options
+		switch arg {
+		case "--webrtc":
+			useWebRTC = true
+		case "--receive", "-r":
+			receive = true
+		case "--history":
+			return e.connectHistory(cmd)
+		case "--parallel":
+			if i+1 < len(args) {
+				if n, err := strconv.Atoi(args[i+1]); err == nil && n > 0 {
+					parallel = min(n, connect.MaxParallelSends)
+				}
+				i++ // Skip the next argument
+			}
+		case "--signal", "--code":
+			if i+1 < len(args) {
+				if arg == "--signal" {
+					signalURL = args[i+1]
+				} else {
+					code = args[i+1]
+				}
+				i++ // Skip the next argument
+			}
+		default:
+			if !strings.HasPrefix(arg, "-") {
+				positionals = append(positionals, arg)
+			}
+		}
 	}
 
 	// Create a connect manager with the specified options
 	connectManager := connect.NewConnectManager(downloadPath, port, useChunked)
+	connectManager.SetParallelSends(parallel)
+	if err := connectManager.UseDiscovery(discovery.ConfigOptions(e.config)); err != nil {
+		log.Printf("Warning: %v; falling back to mDNS discovery", err)
+	}
+	if staged {
+		if err := connectManager.UseStaging(); err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error setting up encrypted staging: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+	}
+
+	// WebRTC sessions are set up through a signaling server instead of an address
+	if useWebRTC {
+		if code == "" && len(positionals) > 0 {
+			code = positionals[0]
+		}
+		return e.executeWebRTCConnect(cmd, connectManager, receive, connect.WebRTCOptions{
+			SignalURL: e.signalURL(signalURL),
+			Code:      code,
+		})
+	}
+
+	// SFTP servers and WebDAV shares are pushed to instead of connected to
+	if len(positionals) > 0 && connect.IsRemoteURL(positionals[0]) {
+		return e.executeRemotePush(cmd, connectManager, positionals[0], positionals[1:])
+	}
 
 	// Check if we're in receive mode
 	if strings.Contains(intent, "--receive") || strings.Contains(intent, "-r") {
-		// Start a WebSocket server to receive files
-		ctx, cancel := context.WithCancel(context.Background())
+		// Start a WebSocket server to receive files, stopping it cleanly on Ctrl+C
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
 		// Start the receiver in the current goroutine
 		err := connectManager.StartReceiver(ctx)
 		if err != nil {
 			// Check if it's a port conflict error
-			if strings.Contains(err.Error(), "port") && strings.Contains(err.Error(), "already in use") {
+			if errors.Is(err, lumoerr.ErrPortInUse) {
 				return &Result{
-					Output: fmt.Sprintf("Error: %v\n\n"+
+					Output: fmt.Sprintf("Error: %s\n\n"+
 						"This could be due to:\n"+
 						"1. Another Lumo connect session running\n"+
 						"2. The Lumo server using this port\n"+
 						"3. Another application using this port\n\n"+
-						"Try using a different port with: lumo connect --receive --port <port>\n"+
-						"%s", err, utils.GetPortRangeMessage("connect")),
+						"%s", lumoerr.Format(err), utils.GetPortRangeMessage("connect")),
 					IsError:    true,
 					CommandRun: cmd.RawInput,
 				}, nil
@@ -147,11 +224,25 @@ Usage:
   lumo connect --receive [options]       Start a server to send and receive files
   lumo connect --discover, -d            Discover Lumo Connect services on the network
   lumo connect <peer-ip> [options]       Connect to a peer to send and receive files
+  lumo connect <contact> [options]       Connect to a peer saved with 'lumo contacts add'
+  lumo connect --receive --webrtc        Wait for a peer to connect directly over WebRTC
+  lumo connect --webrtc <code>           Connect directly to a waiting WebRTC peer
+  lumo connect sftp://user@host/path [files...]
+                                         Push files to a folder on an SFTP server
+  lumo connect webdavs://host/path [files...]
+                                         Push files to a folder on a WebDAV share
 
 Options:
   --port, -p <port>            Specify the port to use (default: 8080)
-  --path, -d <directory>       Specify where to save received files (default: ~/Downloads)
+  --path, -d <directory>       Specify where to save received files (default: your Downloads folder)
   --chunked, -c                Use chunked transfer for all files (better for large files)
+  --staged                     Keep received files encrypted until you accept them
+  --webrtc                     Connect through NATs and across subnets over WebRTC
+  --signal <url>               Lumo server both peers can reach, used to set up
+                               WebRTC connections (default: this machine's server)
+  --code <code>                Code that pairs the two WebRTC peers (generated if omitted)
+  --history                    Show recent transfers with their speed and retries
+  --parallel <n>               Send up to n queued files at once (default: 1, max: 8)
   --help, -h                   Show this help message
 
 Examples:
@@ -162,15 +253,52 @@ Examples:
   lumo connect 192.168.1.5              Connect to peer at 192.168.1.5:8080
   lumo connect 192.168.1.5:9000         Connect to peer at 192.168.1.5:9000
   lumo connect 192.168.1.5 --path /tmp  Connect and save files to /tmp
+  lumo connect laptop-anu               Connect to a contact wherever it is now
   lumo connect 192.168.1.5 --chunked    Connect and use chunked transfer for all files
+  lumo connect 192.168.1.5 --chunked --parallel 3
+                                        Upload up to three dropped files at a time
+  lumo connect --receive --staged        Review each received file before it is saved
+  lumo connect --receive --webrtc --signal http://203.0.113.7:7531
+                                        Wait for a WebRTC peer, printing a pairing code
+  lumo connect --webrtc 3f9a1c2b7d4e --signal http://203.0.113.7:7531
+                                        Connect to the peer waiting with that code
+  lumo connect sftp://me@nas.local/~/backup report.pdf photos
+                                        Upload a file and a folder to ~/backup on the NAS
+  lumo connect https://cloud.example.com/remote.php/dav/files/me/Inbox
+                                        Push files dropped into the terminal to a WebDAV folder
 
 Notes:
   - Both sides can send and receive files simultaneously
-  - Drag and drop files into the terminal to send them
+  - Drag and drop files or folders into the terminal to send them. Folders
+    are packed into one archive and unpacked under the receiver's download path
+  - Files dropped while another is sending are queued and sent in order,
+    with a progress line for the whole queue
+  - --parallel mostly helps chunked transfers; smaller files share the
+    connection and still go through it one at a time
   - Type 'select' to open a file browser
+  - Type 'stats' to see totals and speeds for each peer in this session
   - Press Ctrl+C to stop the connection
   - Files larger than 10MB automatically use chunked transfer
   - Use --chunked option for better performance with large files
+  - Chunked transfers use a second port, usually one above --port;
+    both sides announce it when they connect, so allow it in your firewall
+  - With --staged, type 'pending' to list received files, then
+    'accept <id>' or 'reject <id>' ('all' works for both). Files not
+    accepted before the session ends are deleted
+  - WebRTC only uses the signaling server to exchange connection details;
+    files travel directly between the peers. There is no relay, so it
+    fails if neither side's NAT allows hole punching
+  - SFTP servers and WebDAV shares (webdav:// or http:// for plain HTTP,
+    webdavs:// or https:// for HTTPS) don't need lumo. Files given after
+    the URL are pushed and the command exits; otherwise files dropped into
+    the terminal are pushed. Folders are uploaded as they are, not packed
+  - SFTP signs in with the SSH agent, your keys in ~/.ssh, or a password,
+    and checks the server against ~/.ssh/known_hosts. Logins saved with
+    'lumo config:remote add' are used, and anything missing is asked for
+  - Interrupted uploads are retried; SFTP uploads resume where they stopped
+  - Contacts are found on the network by the hostname they advertise, so
+    they keep working when their address changes. To send files without
+    opening a session, use 'lumo send <files...> to <contact>'
 `,
 			IsError:    false,
 			CommandRun: cmd.RawInput,
@@ -189,9 +317,20 @@ Notes:
 		}, nil
 	}
 
-	// Check if the peer IP includes a port
+	// Contacts are connected to by name, at their current address
 	peerPort := port
-	if strings.Contains(peerIP, ":") {
+	if _, err := contacts.Get(peerIP); err == nil {
+		address, contactPort, _, err := e.resolvePeer(context.Background(), peerIP)
+		if err != nil {
+			return &Result{
+				Output:     err.Error(),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		peerIP, peerPort = address, contactPort
+	} else if strings.Contains(peerIP, ":") {
+		// The peer IP includes a port
 		parts := strings.Split(peerIP, ":")
 		peerIP = parts[0]
 
@@ -213,8 +352,8 @@ Notes:
 		}, nil
 	}
 
-	// Connect to the peer
-	ctx, cancel := context.WithCancel(context.Background())
+	// Connect to the peer, disconnecting cleanly on Ctrl+C
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	err := connectManager.ConnectToPeer(ctx, peerIP, peerPort)
@@ -247,3 +386,91 @@ Notes:
 		CommandRun: cmd.RawInput,
 	}, nil
 }
+
+// signalURL returns the signaling server for WebRTC sessions, defaulting to
+// the local lumo server
+func (e *Executor) signalURL(url string) string {
+	if url == "" {
+		port := 7531
+		if e.config != nil && e.config.ServerPort > 0 {
+			port = e.config.ServerPort
+		}
+		return fmt.Sprintf("http://localhost:%d", port)
+	}
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+// executeWebRTCConnect runs a WebRTC connect session until Ctrl+C or the peer disconnects
+func (e *Executor) executeWebRTCConnect(cmd *nlp.Command, connectManager *connect.ConnectManager, receive bool, opts connect.WebRTCOptions) (*Result, error) {
+	if !receive && opts.Code == "" {
+		return &Result{
+			Output:     "A pairing code is required. Use 'lumo connect --webrtc <code>' with the code shown by the receiving side.",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	if receive {
+		err = connectManager.ReceiveWebRTC(ctx, opts)
+	} else {
+		err = connectManager.ConnectWebRTC(ctx, opts)
+	}
+	if err != nil {
+		output := fmt.Sprintf("Error in WebRTC session: %v", err)
+		if strings.Contains(err.Error(), "signaling server") {
+			output += fmt.Sprintf("\n\nMake sure a Lumo server is running at %s and that both machines can reach it "+
+				"(start one with 'lumo server:start').", opts.SignalURL)
+		}
+		return &Result{
+			Output:     output,
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     "Connection closed",
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// connectHistory lists the most recent transfers
+func (e *Executor) connectHistory(cmd *nlp.Command) (*Result, error) {
+	history, err := connect.TransferHistory(20)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error reading transfer history: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if len(history) == 0 {
+		return &Result{
+			Output:     "No transfers recorded yet.",
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	connect.MarkTransfersRead()
+
+	var output strings.Builder
+	output.WriteString("Recent transfers:\n\n")
+	for i := len(history) - 1; i >= 0; i-- {
+		output.WriteString(history[i].String() + "\n")
+	}
+
+	return &Result{
+		Output:     output.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+	"golang.org/x/term"
+)
+
+// executeRemotePush pushes files to an SFTP server or a WebDAV share. With
+// no files given, files dropped into the terminal are pushed until Ctrl+C.
+func (e *Executor) executeRemotePush(cmd *nlp.Command, connectManager *connect.ConnectManager, target string, paths []string) (*Result, error) {
+	login := e.config.ConnectRemotes[remoteHost(target)]
+	opts := connect.RemoteOptions{
+		Credentials: connect.RemoteCredentials{
+			Username: login.Username,
+			Password: login.Password,
+			KeyFile:  login.KeyFile,
+		},
+	}
+	if utils.IsTerminal(os.Stdin) {
+		opts.Prompt = promptTerminal
+		opts.Confirm = confirmTerminal
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	if len(paths) > 0 {
+		err = connectManager.PushFiles(ctx, target, paths, opts)
+	} else {
+		err = connectManager.PushInteractive(ctx, target, opts)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return &Result{
+			Output:     fmt.Sprintf("Error pushing to %s: %v", target, err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	output := "Connection closed"
+	if len(paths) > 0 && err == nil {
+		output = fmt.Sprintf("Pushed %d item(s) to %s", len(paths), target)
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// promptTerminal asks for a value on the terminal, without echoing secrets
+func promptTerminal(label string, secret bool) (string, error) {
+	fmt.Printf("%s: ", label)
+	if secret {
+		answer, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		return string(answer), err
+	}
+	answer, err := readTerminalLine()
+	return strings.TrimSpace(answer), err
+}
+
+// confirmTerminal asks a yes or no question on the terminal
+func confirmTerminal(question string) bool {
+	fmt.Printf("%s (y/n): ", question)
+	response, err := readTerminalLine()
+	if err != nil {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// readTerminalLine reads one line from stdin a byte at a time, so nothing
+// typed after it is taken from the files dropped into the session later
+func readTerminalLine() (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n == 1 {
+			if buf[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agnath18K/lumo/pkg/clipboard"
+)
+
+// SetCopyBlock asks for the nth code block of AI responses to be copied to
+// the clipboard; 0 leaves it to the auto_copy_code setting
+func (e *Executor) SetCopyBlock(n int) {
+	e.copyBlock = n
+}
+
+// copyCodeBlock copies a code block of an AI response to the clipboard
+// when asked to, printing which one was copied. A response without code
+// is only worth a note when --copy was given explicitly.
+func (e *Executor) copyCodeBlock(response string) {
+	block := e.copyBlock
+	if block == 0 {
+		if !e.config.AutoCopyCode || len(clipboard.ExtractCodeBlocks(response)) == 0 {
+			return
+		}
+		block = 1
+	}
+
+	message, err := e.clipboard.CopyCodeBlock(response, block)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Not copied: %v\n", err)
+		return
+	}
+	if !e.config.Quiet {
+		fmt.Fprintln(os.Stderr, message)
+	}
+}
@@ -11,8 +11,7 @@ import (
 // executeCreateCommand executes a project creation command
 func (e *Executor) executeCreateCommand(cmd *nlp.Command) (*Result, error) {
 	// Check if API keys are configured and run setup if needed
-	if (e.config.AIProvider == "gemini" && e.config.GeminiAPIKey == "") ||
-		(e.config.AIProvider == "openai" && e.config.OpenAIAPIKey == "") {
+	if create.NeedsAI(cmd.Intent) && ai.MissingAPIKey(e.config.AIProvider, e.config) {
 
 		// Run interactive setup
 		setupPerformed, err := e.apiSetup.CheckAndSetupAPIKeys()
@@ -26,11 +25,7 @@ func (e *Executor) executeCreateCommand(cmd *nlp.Command) (*Result, error) {
 
 		if setupPerformed {
 			// Reinitialize the AI client with the new API key
-			if e.config.AIProvider == "gemini" {
-				e.aiClient = ai.NewGeminiClient(e.config.GeminiAPIKey, e.config.GeminiModel)
-			} else {
-				e.aiClient = ai.NewOpenAIClient(e.config.OpenAIAPIKey, e.config.OpenAIModel)
-			}
+			e.aiClient = newAIClient(e.config)
 		} else {
 			// Setup was not completed successfully
 			return &Result{
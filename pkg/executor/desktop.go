@@ -1,23 +1,25 @@
 package executor
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/agnath18K/lumo/dbus/gnome"
+	"github.com/agnath18K/lumo/dbus/kde"
 	"github.com/agnath18K/lumo/internal/assistant"
 	"github.com/agnath18K/lumo/internal/core"
 	"github.com/agnath18K/lumo/internal/desktop"
 	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
 )
 
 // executeDesktopCommand executes a desktop command
 func (e *Executor) executeDesktopCommand(cmd *nlp.Command) (*Result, error) {
 	// Create a desktop environment factory
-	factory := desktop.NewFactory()
-
-	// Register desktop environments
-	registerDesktopEnvironments(factory)
+	factory := NewDesktopFactory()
 
 	// Create a desktop assistant with AI capabilities
 	var desktopAssistant *assistant.Assistant
@@ -32,6 +34,8 @@ func (e *Executor) executeDesktopCommand(cmd *nlp.Command) (*Result, error) {
 		desktopAssistant = assistant.NewAssistant(factory)
 	}
 
+	desktopAssistant.SetConfirmFunc(e.confirmSessionAction)
+
 	// Create a context
 	ctx := context.Background()
 
@@ -55,6 +59,45 @@ func (e *Executor) executeDesktopCommand(cmd *nlp.Command) (*Result, error) {
 	}, nil
 }
 
+// confirmSessionAction asks before a desktop command shuts down, restarts,
+// suspends, or logs out, unless the desktop_confirm_power policy is "never".
+// Without a terminal to ask on, the command is refused.
+func (e *Executor) confirmSessionAction(cmd *core.Command) bool {
+	if e.config.DesktopConfirmPower == "never" {
+		return true
+	}
+	if !utils.IsTerminal(os.Stdin) {
+		fmt.Fprintln(os.Stderr, "No terminal to confirm on. Run 'lumo config:desktop confirm never' to allow this unattended.")
+		return false
+	}
+
+	action := strings.ReplaceAll(cmd.Action, "-", " ")
+	if cmd.Target != "" {
+		action += " " + cmd.Target
+	}
+	fmt.Printf("Really %s? (y/n): ", action)
+
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// NewDesktopFactory creates a desktop environment factory with every supported
+// environment registered
+func NewDesktopFactory() *desktop.Factory {
+	factory := desktop.NewFactory()
+	registerDesktopEnvironments(factory)
+	return factory
+}
+
+// DetectDesktopEnvironment detects the desktop environment of the current session
+func DetectDesktopEnvironment() (core.DesktopEnvironment, error) {
+	return NewDesktopFactory().DetectEnvironment()
+}
+
 // registerDesktopEnvironments registers all available desktop environments
 func registerDesktopEnvironments(factory *desktop.Factory) {
 	// Register GNOME environment
@@ -189,7 +232,11 @@ func createKdeEnvironmentDynamic() (core.DesktopEnvironment, error) {
 
 // createKdeEnvironmentFromPackage creates a KDE desktop environment from the package
 func createKdeEnvironmentFromPackage() (core.DesktopEnvironment, error) {
-	return nil, fmt.Errorf("not implemented")
+	kdeEnv, err := kde.NewEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return kdeEnv, nil
 }
 
 // createXfceEnvironmentImpl creates an XFCE desktop environment implementation
@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/agnath18K/lumo/pkg/contacts"
+	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// discoverServiceTypes are the service types lumo instances advertise
+var discoverServiceTypes = []string{discovery.InstanceServiceName, discovery.ServiceName}
+
+// executeDiscover lists the lumo instances on the network and what can be done with each
+func (e *Executor) executeDiscover(cmd *nlp.Command) (*Result, error) {
+	if cmd.Intent != "" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown option: %s\nUsage: lumo discover", cmd.Intent),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	fmt.Println("🔍 Looking for lumo instances on the network...")
+
+	services, err := e.browseServices(context.Background())
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	// Contacts seen on the way get their current addresses
+	if _, err := contacts.Refresh(services); err != nil {
+		fmt.Fprintf(os.Stderr, "Contacts not updated: %v\n", err)
+	}
+
+	return &Result{
+		Output:     formatInstances(discovery.GroupInstances(services)),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// browseServices finds every lumo instance and connect receiver on the
+// network
+func (e *Executor) browseServices(ctx context.Context) ([]discovery.Service, error) {
+	discoverer, err := discovery.New(discovery.ConfigOptions(e.config))
+	if err != nil {
+		return nil, err
+	}
+
+	// Browse every service type at once so the search takes a single timeout
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var services []discovery.Service
+	for _, serviceType := range discoverServiceTypes {
+		wg.Add(1)
+		go func(serviceType string) {
+			defer wg.Done()
+			found, err := discoverer.Browse(ctx, serviceType)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			services = append(services, found...)
+			mu.Unlock()
+		}(serviceType)
+	}
+	wg.Wait()
+
+	// Both browses return everything seen so far, so drop duplicates
+	seen := make(map[string]bool)
+	unique := services[:0]
+	for _, service := range services {
+		key := service.Type + "|" + service.ID
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, service)
+		}
+	}
+	return unique, nil
+}
+
+// formatInstances renders discovered instances for the terminal
+func formatInstances(instances []discovery.Instance) string {
+	if len(instances) == 0 {
+		return "No lumo instances found on the network.\nStart one with 'lumo server:start' or 'lumo connect --receive'."
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭──────────────────── 🔍 Lumo Instances ───────────────────╮\n")
+	for i, instance := range instances {
+		b.WriteString("\n")
+		version := instance.Version
+		if version == "" {
+			version = "unknown version"
+		} else {
+			version = "v" + version
+		}
+		hostname := instance.Hostname
+		if hostname == "" {
+			hostname = "hidden host"
+		}
+		b.WriteString(fmt.Sprintf("  %d. %s (%s) — %s\n", i+1, hostname, instance.IP, version))
+
+		labels := make([]string, 0, len(instance.Capabilities()))
+		for _, capability := range instance.Capabilities() {
+			labels = append(labels, discovery.DescribeCapability(capability))
+		}
+		b.WriteString(fmt.Sprintf("     Capabilities: %s\n", strings.Join(labels, ", ")))
+		for _, action := range instance.Actions() {
+			b.WriteString(fmt.Sprintf("     • %s\n", action))
+		}
+	}
+	b.WriteString("\n╰──────────────────────────────────────────────────────────╯\n")
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/dbus/common"
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/system"
+)
+
+// executeDoctor diagnoses the local Lumo setup
+func (e *Executor) executeDoctor(cmd *nlp.Command) (*Result, error) {
+	var b strings.Builder
+
+	b.WriteString("\n╭─────────────────── 🩺 Lumo Doctor ───────────────────────╮\n\n")
+
+	// AI provider
+	b.WriteString("  AI Provider:\n")
+	b.WriteString(doctorLine(true, fmt.Sprintf("Provider: %s (%s)", e.config.AIProvider, getCurrentModel(e.config))))
+	b.WriteString(doctorLine(true, fmt.Sprintf("Privacy mode: %s", e.config.PrivacyMode)))
+	if info, ok := ai.Lookup(e.config.AIProvider); !ok {
+		b.WriteString(doctorLine(false, "Provider is registered"))
+	} else if info.Capabilities.NeedsAPIKey {
+		b.WriteString(doctorLine(!ai.MissingAPIKey(info.Name, e.config), fmt.Sprintf("%s API key configured", info.DisplayName)))
+	}
+	if e.config.AIProvider == "ollama" || e.config.BatteryPreferLocalModel {
+		b.WriteString(doctorLine(e.isOllamaAvailable(), fmt.Sprintf("Ollama reachable at %s", e.config.OllamaURL)))
+	}
+
+	// Power
+	b.WriteString("\n  Power:\n")
+	status, err := system.GetPowerStatus()
+	if err != nil {
+		b.WriteString(doctorLine(false, "Power source could not be detected"))
+	} else {
+		source := "AC power"
+		if status.OnBattery {
+			source = "Battery"
+		}
+		if status.HasBattery {
+			source = fmt.Sprintf("%s (%.0f%%)", source, status.Percentage)
+		}
+		b.WriteString(doctorLine(true, fmt.Sprintf("Power source: %s via %s", source, status.Source)))
+	}
+	conserve := system.ShouldConservePower(e.config.PowerMode)
+	b.WriteString(doctorLine(true, fmt.Sprintf("Power mode: %s (battery saving %s)", e.config.PowerMode, onOff(conserve))))
+	if conserve {
+		b.WriteString(doctorLine(true, fmt.Sprintf("Prefer local model: %s", onOff(e.config.BatteryPreferLocalModel))))
+		b.WriteString(doctorLine(true, fmt.Sprintf("Skip scheduled speed tests: %s", onOff(e.config.BatterySkipSpeedTests))))
+		b.WriteString(doctorLine(true, fmt.Sprintf("Health check interval: x%d", e.config.BatteryHealthCheckFactor)))
+	}
+
+	// Desktop
+	b.WriteString("\n  Desktop:\n")
+	desktopEnv := common.DetectDesktopEnvironment()
+	b.WriteString(doctorLine(desktopEnv != "" && desktopEnv != "unknown", fmt.Sprintf("Desktop environment: %s", desktopEnv)))
+
+	// Hooks
+	b.WriteString("\n  Hooks:\n")
+	installed := 0
+	for _, event := range hooks.Events() {
+		if _, ok := hooks.Path(event); ok {
+			b.WriteString(doctorLine(true, fmt.Sprintf("%s hook installed", event)))
+			installed++
+		}
+	}
+	if installed == 0 {
+		dir, _ := hooks.Dir()
+		b.WriteString(fmt.Sprintf("   • No hooks installed in %s\n", dir))
+	} else if hooks.Disabled() {
+		b.WriteString("   • Hooks do not run in safe mode\n")
+	}
+
+	b.WriteString("\n╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// doctorLine formats a single doctor check
+func doctorLine(ok bool, text string) string {
+	icon := "✅"
+	if !ok {
+		icon = "⚠️ "
+	}
+	return fmt.Sprintf("   %s %s\n", icon, text)
+}
+
+// onOff formats a boolean setting
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
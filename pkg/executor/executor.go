@@ -2,20 +2,28 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/agnath18K/lumo/pkg/ai"
 	"github.com/agnath18K/lumo/pkg/chat"
+	"github.com/agnath18K/lumo/pkg/cli"
 	"github.com/agnath18K/lumo/pkg/clipboard"
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
 	"github.com/agnath18K/lumo/pkg/magic"
 	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/notes"
+	"github.com/agnath18K/lumo/pkg/privacy"
 	"github.com/agnath18K/lumo/pkg/setup"
+	"github.com/agnath18K/lumo/pkg/shellwords"
 	"github.com/agnath18K/lumo/pkg/system"
 	"github.com/agnath18K/lumo/pkg/utils"
 )
@@ -36,29 +44,30 @@ type Executor struct {
 	chatManager *chat.Manager
 	magic       *magic.Magic
 	clipboard   *clipboard.Clipboard
+	// copyBlock is the code block of AI responses to copy, from --copy
+	copyBlock int
+	// responses caches answers to ask: questions; it is opened on first use
+	responses *ai.ResponseCache
+	// resolveConflict, if set, is asked instead of the terminal how to
+	// resolve settings a provider or model change breaks
+	resolveConflict ConflictResolver
 }
 
 // NewExecutor creates a new executor instance
 func NewExecutor(cfg *config.Config) *Executor {
-	// Create AI client based on configuration
-	var aiClient ai.Client
-	switch cfg.AIProvider {
-	case "gemini":
-		aiClient = ai.NewGeminiClient(cfg.GeminiAPIKey, cfg.GeminiModel)
-	case "ollama":
-		aiClient = ai.NewOllamaClient(cfg.OllamaURL, cfg.OllamaModel)
-	default: // Default to OpenAI
-		aiClient = ai.NewOpenAIClient(cfg.OpenAIAPIKey, cfg.OpenAIModel)
+	// Strict privacy mode only ever talks to the local model
+	if privacy.IsStrict(cfg.PrivacyMode) && !ai.IsLocal(cfg.AIProvider) {
+		cfg.AIProvider = privacy.LocalProvider
 	}
 
-	// Create a chat manager
-	chatManager := chat.NewManager(aiClient, 5, 20)
+	// Create AI client based on configuration
+	aiClient := newAIClient(cfg)
 
-	return &Executor{
+	e := &Executor{
 		config:      cfg,
 		aiClient:    aiClient,
 		apiSetup:    setup.NewAPIKeySetup(cfg),
-		chatManager: chatManager,
+		chatManager: newChatManager(cfg, aiClient),
 		// The agent will be set later by the agent package
 		agent: nil,
 		// Initialize the magic handler
@@ -66,6 +75,36 @@ func NewExecutor(cfg *config.Config) *Executor {
 		// Initialize the clipboard handler
 		clipboard: clipboard.NewClipboard(),
 	}
+
+	// Prefer the local Ollama model while on battery if the user asked for it
+	if cfg.BatteryPreferLocalModel && cfg.AIProvider != "ollama" &&
+		system.ShouldConservePower(cfg.PowerMode) && e.isOllamaAvailable() {
+		e.aiClient = ai.NewOllamaClient(cfg.OllamaURL, cfg.OllamaModel)
+		e.chatManager = newChatManager(cfg, e.aiClient)
+	}
+
+	return e
+}
+
+// newChatManager creates a chat manager talking to aiClient with the
+// default persona and the chat history settings
+func newChatManager(cfg *config.Config, aiClient ai.Client) *chat.Manager {
+	manager := chat.NewManager(aiClient, 5, 20)
+	if instructions, ok := ai.Persona(cfg, cfg.DefaultPersona); ok {
+		manager.SetInstructions(instructions)
+	}
+	manager.SetHistory(cfg.SaveChatHistory, cfg.ChatAutoTitle)
+	return manager
+}
+
+// newAIClient creates a client for the configured AI provider. Unknown
+// providers fall back to OpenAI.
+func newAIClient(cfg *config.Config) ai.Client {
+	client, err := ai.NewClient(cfg.AIProvider, cfg)
+	if err != nil {
+		client, _ = ai.NewClient(string(ai.ProviderOpenAI), cfg)
+	}
+	return client
 }
 
 // SetAgent sets the agent implementation
@@ -73,6 +112,11 @@ func (e *Executor) SetAgent(agent AgentInterface) {
 	e.agent = agent
 }
 
+// SetClipboard replaces the clipboard commands use
+func (e *Executor) SetClipboard(c *clipboard.Clipboard) {
+	e.clipboard = c
+}
+
 // GetAIClient returns the AI client
 func (e *Executor) GetAIClient() ai.Client {
 	return e.aiClient
@@ -85,13 +129,31 @@ func (e *Executor) Execute(cmd *nlp.Command) (*Result, error) {
 
 // ExecuteWithReader executes a command with an optional reader for piped input
 func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Result, error) {
+	start := time.Now()
+	result, err := e.execute(cmd, reader)
+	e.recordMetrics(cmd, time.Since(start), result, err)
+	return result, err
+}
+
+// execute runs a command of any type
+func (e *Executor) execute(cmd *nlp.Command, reader io.Reader) (*Result, error) {
+	if result := e.enforcePrivacy(cmd); result != nil {
+		return result, nil
+	}
+	if result := e.enforceSafeMode(cmd); result != nil {
+		return result, nil
+	}
+	if result := e.enforceBudget(cmd); result != nil {
+		return result, nil
+	}
+	e.warnNetworkCap(cmd)
+
 	switch cmd.Type {
 	case nlp.CommandTypeShell:
 		return e.executeShellCommand(cmd)
 	case nlp.CommandTypeAI:
 		// Check if API keys are configured and run setup if needed
-		if (e.config.AIProvider == "gemini" && e.config.GeminiAPIKey == "") ||
-			(e.config.AIProvider == "openai" && e.config.OpenAIAPIKey == "") {
+		if ai.MissingAPIKey(e.config.AIProvider, e.config) {
 
 			// Run interactive setup
 			setupPerformed, err := e.apiSetup.CheckAndSetupAPIKeys()
@@ -105,11 +167,7 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 
 			if setupPerformed {
 				// Reinitialize the AI client with the new API key
-				if e.config.AIProvider == "gemini" {
-					e.aiClient = ai.NewGeminiClient(e.config.GeminiAPIKey, e.config.GeminiModel)
-				} else {
-					e.aiClient = ai.NewOpenAIClient(e.config.OpenAIAPIKey, e.config.OpenAIModel)
-				}
+				e.aiClient = newAIClient(e.config)
 			} else {
 				// Setup was not completed successfully
 				return &Result{
@@ -121,9 +179,12 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 		}
 		return e.executeAIQuery(cmd)
 	case nlp.CommandTypeChat:
+		// Saved conversations can be browsed without an API key
+		if result, ok := e.chatHistoryCommand(cmd); ok {
+			return result, nil
+		}
 		// Check if API keys are configured and run setup if needed
-		if (e.config.AIProvider == "gemini" && e.config.GeminiAPIKey == "") ||
-			(e.config.AIProvider == "openai" && e.config.OpenAIAPIKey == "") {
+		if ai.MissingAPIKey(e.config.AIProvider, e.config) {
 
 			// Run interactive setup
 			setupPerformed, err := e.apiSetup.CheckAndSetupAPIKeys()
@@ -137,11 +198,7 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 
 			if setupPerformed {
 				// Reinitialize the AI client with the new API key
-				if e.config.AIProvider == "gemini" {
-					e.aiClient = ai.NewGeminiClient(e.config.GeminiAPIKey, e.config.GeminiModel)
-				} else {
-					e.aiClient = ai.NewOpenAIClient(e.config.OpenAIAPIKey, e.config.OpenAIModel)
-				}
+				e.aiClient = newAIClient(e.config)
 			} else {
 				// Setup was not completed successfully
 				return &Result{
@@ -152,7 +209,7 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 			}
 		}
 		return e.executeChatCommand(cmd)
-	case nlp.CommandTypeAgent:
+	case nlp.CommandTypeAgent, nlp.CommandTypeAnalyze:
 		// Check if agent is initialized
 		if e.agent == nil {
 			return &Result{
@@ -172,8 +229,7 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 		}
 
 		// Check if API keys are configured and run setup if needed
-		if (e.config.AIProvider == "gemini" && e.config.GeminiAPIKey == "") ||
-			(e.config.AIProvider == "openai" && e.config.OpenAIAPIKey == "") {
+		if ai.MissingAPIKey(e.config.AIProvider, e.config) {
 
 			// Run interactive setup
 			setupPerformed, err := e.apiSetup.CheckAndSetupAPIKeys()
@@ -187,11 +243,7 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 
 			if setupPerformed {
 				// Reinitialize the AI client with the new API key
-				if e.config.AIProvider == "gemini" {
-					e.aiClient = ai.NewGeminiClient(e.config.GeminiAPIKey, e.config.GeminiModel)
-				} else {
-					e.aiClient = ai.NewOpenAIClient(e.config.OpenAIAPIKey, e.config.OpenAIModel)
-				}
+				e.aiClient = newAIClient(e.config)
 			} else {
 				// Setup was not completed successfully
 				return &Result{
@@ -254,6 +306,54 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 	case nlp.CommandTypeServer:
 		// Execute server command
 		return e.executeServerCommand(cmd)
+	case nlp.CommandTypeDoctor:
+		// Diagnose the local setup
+		return e.executeDoctor(cmd)
+	case nlp.CommandTypeIntegrate:
+		// Register lumo with the desktop
+		return e.executeIntegrate(cmd)
+	case nlp.CommandTypeLast:
+		// Show or replay the last agent run
+		return e.executeLast(cmd)
+	case nlp.CommandTypeDiscover:
+		// List lumo instances on the network
+		return e.executeDiscover(cmd)
+	case nlp.CommandTypeSave:
+		// Bookmark the last result
+		return e.executeSave(cmd)
+	case nlp.CommandTypeNotes:
+		// List, show, or search bookmarked results
+		return e.executeNotes(cmd)
+	case nlp.CommandTypeUsage:
+		// Show AI token usage and estimated cost
+		return e.executeUsage(cmd)
+	case nlp.CommandTypeStats:
+		// Show the local usage metrics
+		return e.executeStats(cmd)
+	case nlp.CommandTypeTrigger:
+		// Manage the daemon's filesystem triggers
+		return e.executeTrigger(cmd)
+	case nlp.CommandTypeToday:
+		// Show the time tracked today
+		return e.executeToday(cmd)
+	case nlp.CommandTypePalette:
+		// Extract the dominant colors of an image
+		return e.executePalette(cmd)
+	case nlp.CommandTypeSnip:
+		// Manage text snippets
+		return e.executeSnip(cmd)
+	case nlp.CommandTypeRemind:
+		// Set, list, or remove reminders
+		return e.executeRemind(cmd)
+	case nlp.CommandTypeAgenda:
+		// Show calendar events and reminders
+		return e.executeAgenda(cmd)
+	case nlp.CommandTypeSend:
+		// Send files to a contact or peer
+		return e.executeSend(cmd)
+	case nlp.CommandTypeContacts:
+		// Manage the peers files are sent to
+		return e.executeContacts(cmd)
 	default:
 		return &Result{
 			Output:     "Unknown command type",
@@ -265,8 +365,8 @@ func (e *Executor) ExecuteWithReader(cmd *nlp.Command, reader io.Reader) (*Resul
 
 // executeShellCommand runs a shell command
 func (e *Executor) executeShellCommand(cmd *nlp.Command) (*Result, error) {
-	// Split the command into parts
-	parts := strings.Fields(cmd.Intent)
+	// Split the command into parts; quoted words stay whole
+	parts := shellwords.Fields(cmd.Intent)
 	if len(parts) == 0 {
 		return &Result{
 			Output:     "Empty command",
@@ -315,47 +415,80 @@ func (e *Executor) executeShellCommand(cmd *nlp.Command) (*Result, error) {
 
 // executeAIQuery sends a query to the AI service
 func (e *Executor) executeAIQuery(cmd *nlp.Command) (*Result, error) {
-	// Check internet connectivity for cloud-based providers
-	if (e.config.AIProvider == "gemini" || e.config.AIProvider == "openai") && !utils.CheckInternetConnectivity() {
-		// We're offline and using a cloud provider
-
-		// Check if Ollama is available locally
-		ollamaAvailable := e.isOllamaAvailable()
-
-		// Use the new function for a more humorous offline warning without a box
+	// A persona chosen with --persona answers in place of the default one
+	opts, query, err := splitAskOptions(cmd.Intent)
+	instructions := ""
+	if err == nil {
+		instructions, err = e.personaInstructions(opts.persona)
+	}
+	if err != nil {
 		return &Result{
-			Output:     utils.FormatOfflineWarning(e.config.AIProvider, ollamaAvailable, false),
+			Output:     fmt.Sprintf("Error: %v", err),
 			IsError:    true,
 			CommandRun: cmd.RawInput,
 		}, nil
 	}
 
-	// Proceed with the query
-	response, err := e.aiClient.Query(cmd.Intent)
-	if err != nil {
-		// Check if the error might be due to connectivity issues
-		if !utils.CheckInternetConnectivity() && (e.config.AIProvider == "gemini" || e.config.AIProvider == "openai") {
+	// An identical question asked before is answered from the cache, even
+	// offline. Questions about images are not cached, since the image may change.
+	var response, cacheKey string
+	cached := false
+	if opts.image == "" {
+		response, cacheKey, cached = e.cachedAnswer(query, instructions)
+	}
+	if !cached {
+		// Check internet connectivity for cloud-based providers
+		if e.offline() {
 			// We're offline and using a cloud provider
+
+			// Check if Ollama is available locally
 			ollamaAvailable := e.isOllamaAvailable()
 
 			// Use the new function for a more humorous offline warning without a box
 			return &Result{
-				Output:     "Error: " + err.Error() + "\n\n" + utils.FormatOfflineWarning(e.config.AIProvider, ollamaAvailable, false),
+				Output:     utils.FormatOfflineWarning(e.config.AIProvider, ollamaAvailable, false),
 				IsError:    true,
 				CommandRun: cmd.RawInput,
 			}, nil
 		}
 
-		// Regular error handling
-		return &Result{
-			Output:     fmt.Sprintf("AI Error: %v", err),
-			IsError:    true,
-			CommandRun: cmd.RawInput,
-		}, nil
+		// Proceed with the query
+		if opts.image != "" {
+			response, err = e.queryImage(opts.image, query, instructions)
+		} else {
+			response, err = e.queryAs(query, instructions)
+		}
+		if err != nil {
+			// Check if the error might be due to connectivity issues
+			if e.offline() || (errors.Is(err, lumoerr.ErrOffline) && e.config.AIProvider != "ollama") {
+				// We're offline and using a cloud provider
+				ollamaAvailable := e.isOllamaAvailable()
+
+				// Use the new function for a more humorous offline warning without a box
+				return &Result{
+					Output: "Error: " + err.Error() + "\n\n" + utils.FormatOfflineWarning(e.config.AIProvider, ollamaAvailable, false) +
+						fmt.Sprintf("\n   [%s] see 'man lumo', ERRORS", lumoerr.ErrOffline.Code),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+
+			// Regular error handling
+			return &Result{
+				Output:     "AI Error: " + lumoerr.Format(err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.cacheAnswer(cacheKey, response)
+	} else if !e.config.Quiet {
+		fmt.Fprintln(os.Stderr, "⚡ Answered from the cache (lumo --no-cache to ask again)")
 	}
 
 	// Clean up markdown formatting for better terminal display
 	cleanResponse := utils.CleanMarkdown(response)
+	rememberResult(notes.KindAnswer, cmd, cleanResponse)
+	e.copyCodeBlock(response)
 
 	// Check if the response already has a box format (either style)
 	hasBox := (strings.Contains(cleanResponse, "┌") && strings.Contains(cleanResponse, "┐") &&
@@ -386,7 +519,7 @@ func (e *Executor) executeChatCommand(cmd *nlp.Command) (*Result, error) {
 	}
 
 	// Check internet connectivity for cloud-based providers
-	if (e.config.AIProvider == "gemini" || e.config.AIProvider == "openai") && !utils.CheckInternetConnectivity() {
+	if e.offline() {
 		// We're offline and using a cloud provider
 
 		// Check if Ollama is available locally
@@ -407,7 +540,7 @@ func (e *Executor) executeChatCommand(cmd *nlp.Command) (*Result, error) {
 	response, err := e.chatManager.ProcessMessage(ctx, cmd.Intent)
 	if err != nil {
 		// Check if the error might be due to connectivity issues
-		if !utils.CheckInternetConnectivity() && (e.config.AIProvider == "gemini" || e.config.AIProvider == "openai") {
+		if e.offline() {
 			// We're offline and using a cloud provider
 			ollamaAvailable := e.isOllamaAvailable()
 
@@ -429,6 +562,7 @@ func (e *Executor) executeChatCommand(cmd *nlp.Command) (*Result, error) {
 
 	// Clean up markdown formatting for better terminal display
 	cleanResponse := utils.CleanMarkdown(response)
+	e.copyCodeBlock(response)
 
 	// Check if the response already has a box format (either style)
 	hasBox := (strings.Contains(cleanResponse, "┌") && strings.Contains(cleanResponse, "┐") &&
@@ -455,6 +589,9 @@ func (e *Executor) executeChatCommand(cmd *nlp.Command) (*Result, error) {
 func (e *Executor) startChatREPL() (*Result, error) {
 	// Create a new REPL instance
 	repl := chat.NewREPL(e.config, e.chatManager, e.aiClient)
+	if e.agent != nil {
+		repl.SetTaskRunner(e.runChatTask)
+	}
 
 	// Start the REPL loop
 	output, err := repl.Start()
@@ -473,10 +610,22 @@ func (e *Executor) startChatREPL() (*Result, error) {
 	}, nil
 }
 
+// runChatTask runs a task handed over from the chat REPL with the agent
+func (e *Executor) runChatTask(ctx context.Context, task, background string) (string, error) {
+	result, err := e.agent.ExecuteWithContext(ctx, task, background)
+	if err != nil {
+		return "", err
+	}
+	if result.IsError {
+		return "", fmt.Errorf("%s", result.Output)
+	}
+	return result.Output, nil
+}
+
 // executeAgentCommand executes a command using the agent
 func (e *Executor) executeAgentCommand(cmd *nlp.Command) (*Result, error) {
 	// Check internet connectivity for cloud-based providers
-	if (e.config.AIProvider == "gemini" || e.config.AIProvider == "openai") && !utils.CheckInternetConnectivity() {
+	if e.offline() {
 		// We're offline and using a cloud provider
 
 		// Check if Ollama is available locally
@@ -493,11 +642,25 @@ func (e *Executor) executeAgentCommand(cmd *nlp.Command) (*Result, error) {
 	// Create a context
 	ctx := context.Background()
 
-	// Execute the command using the agent
-	result, err := e.agent.Execute(ctx, cmd.Intent)
+	// Execute the command using the agent, read-only for analyze:, and
+	// remember the findings or the plan that ran for `lumo save`
+	var result *Result
+	var err error
+	if cmd.Type == nlp.CommandTypeAnalyze {
+		result, err = e.agent.Analyze(ctx, cmd.Intent)
+		if err == nil && result != nil && !result.IsError {
+			rememberResult(notes.KindAnalysis, cmd, result.Output)
+		}
+	} else {
+		started := time.Now()
+		result, err = e.agent.Execute(ctx, cmd.Intent)
+		if err == nil {
+			rememberAgentRun(cmd, started)
+		}
+	}
 
 	// Check if the error might be due to connectivity issues
-	if err != nil && !utils.CheckInternetConnectivity() && (e.config.AIProvider == "gemini" || e.config.AIProvider == "openai") {
+	if err != nil && e.offline() {
 		// We're offline and using a cloud provider
 		ollamaAvailable := e.isOllamaAvailable()
 
@@ -527,8 +690,21 @@ func (e *Executor) executeSystemHealthCheck(cmd *nlp.Command) (*Result, error) {
 		}, nil
 	}
 
+	// Let the user's on-health-alert hook know about unhealthy components
+	if alerts := healthResult.Alerts(); len(alerts) > 0 {
+		data := map[string]interface{}{
+			"hostname": healthResult.Hostname,
+			"summary":  healthResult.Summary,
+			"checks":   alerts,
+		}
+		if err := hooks.Run(context.Background(), hooks.EventOnHealthAlert, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
 	// Format the health check result
 	formattedResult := system.FormatHealthCheck(healthResult)
+	rememberResult(notes.KindReport, cmd, formattedResult)
 
 	return &Result{
 		Output:     formattedResult,
@@ -554,6 +730,7 @@ func (e *Executor) executeSystemReport(cmd *nlp.Command) (*Result, error) {
 
 	// Format the report
 	formattedReport := system.FormatSystemReport(report)
+	rememberResult(notes.KindReport, cmd, formattedReport)
 
 	return &Result{
 		Output:     formattedReport,
@@ -564,64 +741,31 @@ func (e *Executor) executeSystemReport(cmd *nlp.Command) (*Result, error) {
 
 // showHelp displays help information
 func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
-	shellStatus := "DISABLED"
-	if e.config.EnableShellInInteractive {
-		shellStatus = "ENABLED"
-	}
-
-	agentStatus := "DISABLED"
-	if e.config.EnableAgentMode {
-		agentStatus = "ENABLED"
-	}
-
-	replStatus := "DISABLED"
-	if e.config.EnableAgentREPL {
-		replStatus = "ENABLED"
-	}
-
-	pipeStatus := "DISABLED"
-	if e.config.EnablePipeProcessing {
-		pipeStatus = "ENABLED"
-	}
-
-	healthStatus := "DISABLED"
-	if e.config.EnableSystemHealth {
-		healthStatus = "ENABLED"
-	}
-
-	reportStatus := "DISABLED"
-	if e.config.EnableSystemReport {
-		reportStatus = "ENABLED"
-	}
-
-	// Get chat REPL status
-	chatReplStatus := "Disabled"
-	if e.config.EnableChatREPL {
-		chatReplStatus = "Enabled"
-	}
-
-	// Get speed test status
-	speedTestStatus := "DISABLED"
-	if e.config.EnableSpeedTest {
-		speedTestStatus = "ENABLED"
-	}
-
-	// Get server status
-	serverStatus := "DISABLED"
-	if e.config.EnableServer {
-		serverStatus = "ENABLED"
-	}
+	// Feature states come from the feature registry
+	shellStatus := e.featureStatus("shell")
+	agentStatus := e.featureStatus("agent")
+	healthStatus := e.featureStatus("health")
+	reportStatus := e.featureStatus("report")
+	speedTestStatus := e.featureStatus("speedtest")
+	serverStatus := e.featureStatus("server")
 
 	helpText := fmt.Sprintf(`
 ╭──────────────────── 🐦 Lumo CLI Assistant ──────────────────────╮
 
   Commands:
    • ask:<query>                Ask the AI a question
+   • ask:- / ask:--edit         Read the question from stdin or $EDITOR
+   • ask:--image <file> <query> Ask about a screenshot or picture
    • chat:<message>             Start or continue a conversation
    • chat                       Start interactive chat mode
+   • chat:history               Browse saved conversations
    • shell:<command>            Run shell command [%s] (ONLY with shell: prefix)
    • auto:<task>                Use agent mode [%s]
    • agent:<task>               Use agent mode [%s]
+   • agent:resume [run-id]      List or continue interrupted agent runs
+   • agent:approvals            List remote agent runs awaiting approval
+   • agent:approve|reject <tok> Answer a remote agent run's approval
+   • analyze:<question>         Investigate using read-only commands [%s]
    • health:<options>           Check system health [%s]
    • syshealth:<options>        Check system health [%s]
    • report:<options>           Generate system report [%s]
@@ -632,6 +776,7 @@ func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
    • clipboard <text>           Copy text to clipboard
    • clipboard append <text>    Append text to clipboard
    • clipboard clear            Clear clipboard contents
+   • clipboard history          Show what was copied; clipboard get <n> copies it
    • connect --receive [options]  Start a server to send/receive files
    • connect <peer-ip> [options]  Connect to peer to send/receive files
    • connect --help              Show connect command options
@@ -639,15 +784,39 @@ func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
    • desktop:<command>          Execute desktop environment commands
    • server:<command>           Manage the REST server daemon [%s]
    • config:<options>           Configure Lumo settings
-   • version, -v, --version     Show version information
-   • help, -h, --help           Show this help
+   • doctor                     Diagnose your Lumo setup
+   • integrate shortcuts        Register desktop keyboard shortcuts
+   • last [--as-script]         Show or script the last agent run
+   • save <name> [--tag <tag>]  Bookmark the last answer, report, or plan
+   • notes [list|show|search]   Find bookmarked results again
+   • usage [network]            Show AI token usage and cost, or network traffic
+   • stats [all]                Show your local usage stats
+   • discover                   List lumo instances on the network
+   • trigger add --watch <dir> --run <command>  Run a command for new files
+   • today [--date <day>] [--csv]  Show where your time went
+   • palette <image> [--count N]  Show the main colors of an image
+   • snip add <name> <text>     Save a snippet; snip paste <name> copies it
+   • remind "<what> <when>"     Set a reminder, e.g. "call mom tomorrow 6pm"
+   • agenda [today|week|<day>]  Show calendar events and reminders
+   • send <files...> to <name>  Send files to a contact running connect --receive
+   • contacts [add|remove|refresh]  Name the peers you send files to
+   • script run <file.star>     Run an automation script
+   • widget status [--tmux]     One-line status for prompts
+   • completion bash|zsh|fish   Print a shell completion script
+   • version                    Show version information
+   • help                       Show this help
 
+  Options (before the command, e.g. lumo -q --out notes.md ask:...):
+%s
   Examples:
    • lumo "how to find large files"
    • chat:Tell me about Linux
    • chat                       Start interactive chat session
    • shell:ls -la               Execute shell command (ONLY with shell: prefix)
    • auto:"create a backup of my documents"
+   • auto:--dry-run clean up old logs  Preview what a plan would touch
+   • agent:resume 20261016-153012-a1b2c3  Continue a run from its last completed step
+   • analyze:why did my disk fill up  Investigate without changing anything
    • magic:dance                Show a fun dance animation
    • clipboard                  Show current clipboard contents
    • clipboard "Hello World"    Copy text to clipboard
@@ -655,14 +824,21 @@ func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
    • clipboard clear            Clear clipboard contents
    • echo "text" | clipboard    Copy piped text to clipboard
    • echo "more" | clipboard append  Append piped text to clipboard
+   • clipboard pin 3            Keep the third history entry for good
    • connect --receive          Start a server on port 8080
    • connect --receive --port 9000  Start a server on port 9000
    • connect 192.168.1.5        Connect to peer at 192.168.1.5:8080
+   • send report.pdf to laptop-anu  Send a file to a saved contact
    • create:"Flutter app with bloc architecture"  Create a new Flutter project
    • desktop:"close firefox window"  Close the Firefox window
    • desktop:"launch terminal"  Launch the terminal application
+   • desktop:focus on 90m       Do not disturb for 90 minutes
+   • desktop:nightlight temp 4000  Warm the screen color temperature
+   • desktop:take screenshot of window  Save a screenshot to ~/Pictures
+   • desktop:shutdown in 30m    Power off later (desktop:cancel shutdown)
    • speed:                     Run a full internet speed test
    • speed:download             Test download speed only
+   • speed:monitor --duration 10m  Watch ping, jitter, and loss live
    • cat file.txt | lumo        Analyze piped content
    • config:model list          List available AI models
    • config:key show            Show API key status
@@ -682,16 +858,7 @@ func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
    • config:ollama test         Test connection to Ollama server
 
   Status:
-   • Shell in interactive mode: %s
-   • Agent mode: %s
-   • Agent REPL mode: %s
-   • Chat REPL mode: %s
-   • Pipe processing: %s
-   • System health checks: %s
-   • System reports: %s
-   • Speed test: %s
-   • REST server: %s
-   • Current AI provider: %s
+%s   • Current AI provider: %s
    • Current model: %s
 
   API Keys:
@@ -706,7 +873,7 @@ func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
    • Offline mode available with Ollama (config:provider set ollama)
 
 ╰─────────────────────────────────────────────────────────────────────╯
-`, shellStatus, agentStatus, agentStatus, healthStatus, healthStatus, reportStatus, reportStatus, speedTestStatus, serverStatus, shellStatus, agentStatus, replStatus, chatReplStatus, pipeStatus, healthStatus, reportStatus, speedTestStatus, serverStatus, e.config.AIProvider, getCurrentModel(e.config))
+`, shellStatus, agentStatus, agentStatus, agentStatus, healthStatus, healthStatus, reportStatus, reportStatus, speedTestStatus, serverStatus, strings.TrimRight(cli.Usage(), "\n"), e.featureStatusLines("   "), e.config.AIProvider, getCurrentModel(e.config))
 
 	return &Result{
 		Output:     helpText,
@@ -715,6 +882,11 @@ func (e *Executor) showHelp(cmd *nlp.Command) (*Result, error) {
 	}, nil
 }
 
+// Model returns the provider and model answering questions, e.g. "gemini/gemini-2.0-flash-lite"
+func (e *Executor) Model() string {
+	return e.config.AIProvider + "/" + getCurrentModel(e.config)
+}
+
 // GetConfig returns the executor's configuration
 func (e *Executor) GetConfig() *config.Config {
 	return e.config
@@ -770,6 +942,13 @@ func (e *Executor) executeMagicCommand(cmd *nlp.Command) (*Result, error) {
 
 // executeClipboardCommand executes a clipboard command
 func (e *Executor) executeClipboardCommand(cmd *nlp.Command, reader io.Reader) (*Result, error) {
+	// Piped input is always copied, even if it reads like a history command
+	if reader == nil {
+		if result, ok := e.executeClipboardHistory(cmd); ok {
+			return result, nil
+		}
+	}
+
 	// Execute the clipboard command
 	output, err := e.clipboard.Execute(cmd.Intent, reader)
 	if err != nil {
@@ -787,6 +966,12 @@ func (e *Executor) executeClipboardCommand(cmd *nlp.Command, reader io.Reader) (
 	}, nil
 }
 
+// offline reports whether the configured provider needs the internet and
+// it is unreachable. Local providers and replayed cassettes need no network.
+func (e *Executor) offline() bool {
+	return !ai.IsLocal(e.config.AIProvider) && !ai.Replaying() && !utils.CheckInternetConnectivity()
+}
+
 // isOllamaAvailable checks if Ollama is available locally
 func (e *Executor) isOllamaAvailable() bool {
 	client := &http.Client{
@@ -798,6 +983,19 @@ func (e *Executor) isOllamaAvailable() bool {
 
 // executeServerCommand executes a server command
 func (e *Executor) executeServerCommand(cmd *nlp.Command) (*Result, error) {
+	// API keys, users, and the audit log can be used while the server is
+	// disabled
+	if parts := strings.Fields(cmd.Intent); len(parts) > 0 {
+		switch parts[0] {
+		case "apikey":
+			return e.executeAPIKeyCommand(parts[1:], cmd)
+		case "user":
+			return e.executeUserCommand(parts[1:], cmd)
+		case "audit":
+			return e.executeAuditCommand(parts[1:], cmd)
+		}
+	}
+
 	// Check if server is enabled
 	if !e.config.EnableServer {
 		return &Result{
@@ -818,6 +1016,11 @@ func (e *Executor) executeServerCommand(cmd *nlp.Command) (*Result, error) {
    • server:start    - Start the server daemon
    • server:stop     - Stop the server daemon
    • server:status   - Check server daemon status
+   • server:install  - Start the daemon at every login
+   • server:uninstall - Stop starting it at login
+   • server:apikey   - Manage API keys for scripts and CI
+   • server:user     - Manage users and their roles
+   • server:audit    - Review commands the server ran
    • server:help     - Show this help message
 
   The server runs on port ` + fmt.Sprintf("%d", e.config.ServerPort) + ` by default.
@@ -852,6 +1055,12 @@ func (e *Executor) executeServerCommand(cmd *nlp.Command) (*Result, error) {
 			IsError:    false,
 			CommandRun: cmd.RawInput,
 		}, nil
+	case "install", "uninstall":
+		return &Result{
+			Output:     "Use 'lumo server:" + parts[0] + "' directly to manage the daemon's service.",
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
 	case "help":
 		helpText := `
 ╭─────────────────── 🌐 Lumo Server Commands ─────────────────╮
@@ -860,6 +1069,11 @@ func (e *Executor) executeServerCommand(cmd *nlp.Command) (*Result, error) {
    • server:start    - Start the server daemon
    • server:stop     - Stop the server daemon
    • server:status   - Check server daemon status
+   • server:install  - Start the daemon at every login
+   • server:uninstall - Stop starting it at login
+   • server:apikey   - Manage API keys for scripts and CI
+   • server:user     - Manage users and their roles
+   • server:audit    - Review commands the server ran
    • server:help     - Show this help message
 
   The server runs on port ` + fmt.Sprintf("%d", e.config.ServerPort) + ` by default.
@@ -0,0 +1,174 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// lumoShortcut is a lumo action that can be bound to a desktop keyboard shortcut
+type lumoShortcut struct {
+	id      string
+	name    string
+	binding string
+	// command builds the command line from the path of the lumo executable
+	command func(lumo string) string
+}
+
+// lumoShortcuts are the shortcuts registered by `lumo integrate shortcuts`
+var lumoShortcuts = []lumoShortcut{
+	{
+		id:      "toggle-mute",
+		name:    "Lumo: Toggle mute",
+		binding: "<Super><Shift>m",
+		command: func(lumo string) string {
+			return fmt.Sprintf("%s 'desktop:toggle mute'", shellQuote(lumo))
+		},
+	},
+	{
+		id:      "clipboard",
+		name:    "Lumo: Pick from clipboard history",
+		binding: "<Super><Shift>v",
+		command: func(lumo string) string {
+			return fmt.Sprintf("gnome-terminal -- %s clipboard pick", shellQuote(lumo))
+		},
+	},
+	{
+		id:      "quick-chat",
+		name:    "Lumo: Quick chat",
+		binding: "<Super><Shift>l",
+		command: func(lumo string) string {
+			return fmt.Sprintf("gnome-terminal -- %s chat:", shellQuote(lumo))
+		},
+	},
+}
+
+// executeIntegrate executes a desktop integration command
+func (e *Executor) executeIntegrate(cmd *nlp.Command) (*Result, error) {
+	parts := strings.Fields(cmd.Intent)
+	if len(parts) == 0 || parts[0] != "shortcuts" {
+		return &Result{
+			Output: `Usage:
+  lumo integrate shortcuts          Register lumo keyboard shortcuts
+  lumo integrate shortcuts list     List registered shortcuts
+  lumo integrate shortcuts remove   Remove all lumo shortcuts`,
+			IsError:    len(parts) > 0,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	env, err := DetectDesktopEnvironment()
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Desktop Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	action := "install"
+	if len(parts) > 1 {
+		action = parts[1]
+	}
+
+	var output string
+	switch action {
+	case "install":
+		output, err = installShortcuts(env)
+	case "list":
+		output, err = listShortcuts(env)
+	case "remove", "uninstall":
+		output, err = removeShortcuts(env)
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown shortcuts command: %s. Use 'install', 'list', or 'remove'.", action),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Shortcut Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// installShortcuts registers every lumo shortcut with the desktop
+func installShortcuts(env core.DesktopEnvironment) (string, error) {
+	lumo, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate lumo executable: %w", err)
+	}
+
+	ctx := context.Background()
+	var b strings.Builder
+	b.WriteString("Registered keyboard shortcuts:\n")
+	for _, shortcut := range lumoShortcuts {
+		err := env.RegisterShortcut(ctx, core.Shortcut{
+			ID:      shortcut.id,
+			Name:    shortcut.name,
+			Command: shortcut.command(lumo),
+			Binding: shortcut.binding,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to register %s: %w", shortcut.id, err)
+		}
+		b.WriteString(fmt.Sprintf("  • %-18s %s\n", shortcut.binding, shortcut.name))
+	}
+	b.WriteString("\nRemove them with 'lumo integrate shortcuts remove'.")
+
+	return b.String(), nil
+}
+
+// listShortcuts lists the lumo shortcuts registered with the desktop
+func listShortcuts(env core.DesktopEnvironment) (string, error) {
+	shortcuts, err := env.ListShortcuts(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if len(shortcuts) == 0 {
+		return "No lumo keyboard shortcuts registered. Run 'lumo integrate shortcuts' to add them.", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("Lumo keyboard shortcuts:\n")
+	for _, shortcut := range shortcuts {
+		b.WriteString(fmt.Sprintf("  • %-18s %s\n", shortcut.Binding, shortcut.Name))
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// removeShortcuts removes every lumo shortcut from the desktop
+func removeShortcuts(env core.DesktopEnvironment) (string, error) {
+	ctx := context.Background()
+	shortcuts, err := env.ListShortcuts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, shortcut := range shortcuts {
+		if err := env.RemoveShortcut(ctx, shortcut.ID); err != nil {
+			return "", fmt.Errorf("failed to remove %s: %w", shortcut.ID, err)
+		}
+	}
+
+	return fmt.Sprintf("Removed %d lumo keyboard shortcut(s)", len(shortcuts)), nil
+}
+
+// shellQuote quotes a string for use as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
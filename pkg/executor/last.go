@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/replay"
+)
+
+// executeLast shows the last agent run, or renders it as a replayable script
+func (e *Executor) executeLast(cmd *nlp.Command) (*Result, error) {
+	asScript := false
+	for _, arg := range strings.Fields(cmd.Intent) {
+		switch arg {
+		case "--as-script", "--script":
+			asScript = true
+		default:
+			return &Result{
+				Output:     fmt.Sprintf("Unknown option: %s\nUsage: lumo last [--as-script]", arg),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+	}
+
+	record, err := replay.Load()
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if record == nil {
+		return &Result{
+			Output:     "No agent run recorded yet. Run a task with 'lumo auto:<task>' first.",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	output := record.Summary()
+	if asScript {
+		output = strings.TrimRight(record.Script(), "\n")
+	}
+
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/speedtest"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// sparklineWidth is how many recent samples the live sparkline shows
+const sparklineWidth = 40
+
+// monitorUsage describes the speed:monitor options
+const monitorUsage = "Usage: speed:monitor [--target <host>] [--duration <10m>] [--interval <1s>] [--loss-threshold <percent>]"
+
+// executeLatencyMonitor samples ping, jitter, and loss to a target and
+// renders them live until the duration is over or the user interrupts it
+func (e *Executor) executeLatencyMonitor(cmd *nlp.Command, args []string) (*Result, error) {
+	opts, err := parseMonitorArgs(args)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("%v\n%s", err, monitorUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	live := utils.IsTerminal(os.Stdout) && !e.config.Accessible()
+	fmt.Printf("📈 Monitoring latency to %s for %s (Ctrl+C to stop early)\n", opts.Target, opts.Duration)
+
+	opts.OnSample = func(series *speedtest.LatencySeries) {
+		if !live {
+			return
+		}
+		stats := speedtest.Stats(series.Samples)
+		last := series.Samples[len(series.Samples)-1]
+		rtt := "lost"
+		if !last.Lost {
+			rtt = fmt.Sprintf("%.1f ms", last.RTT)
+		}
+		remaining := opts.Duration - time.Since(series.StartedAt)
+		fmt.Printf("\r\033[K%s  %s  jitter %.1f ms  loss %.1f%%  %s left",
+			speedtest.Sparkline(series.Samples, sparklineWidth), rtt, stats.Jitter, stats.Loss, remaining.Round(time.Second))
+	}
+	opts.OnAlert = func(series *speedtest.LatencySeries, window speedtest.LatencyStats) {
+		fmt.Fprintf(os.Stderr, "\n⚠️  Packet loss to %s is %.1f%% over the last %d samples\n", series.Target, window.Loss, window.Samples)
+		hooks.Fire(hooks.EventOnLatencyAlert, map[string]interface{}{
+			"target":         series.Target,
+			"loss_percent":   window.Loss,
+			"threshold":      opts.LossThreshold,
+			"window_samples": window.Samples,
+			"avg_ms":         window.Avg,
+			"jitter_ms":      window.Jitter,
+		})
+	}
+
+	// Stop sampling on Ctrl+C but still report what was collected
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	series := speedtest.MonitorLatency(ctx, opts)
+	if live {
+		fmt.Println()
+	}
+
+	if len(series.Samples) == 0 {
+		return &Result{
+			Output:     "No samples were collected.",
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	path, err := speedtest.SaveSeries(series)
+	saved := fmt.Sprintf("Series saved to %s", path)
+	if err != nil {
+		saved = fmt.Sprintf("Warning: %v", err)
+	}
+
+	stats := series.Stats
+	output := fmt.Sprintf(`
+╭────────────────── 📈 Latency Monitor ────────────────────╮
+
+  Target: %s
+  %s
+  Samples: %d (%d lost, %.1f%% loss)
+  Round trip: min %.1f / avg %.1f / max %.1f ms
+  Jitter: %.1f ms
+  Loss alerts: %d (threshold %.1f%%)
+
+  %s
+╰──────────────────────────────────────────────────────────╯
+`, series.Target, speedtest.Sparkline(series.Samples, sparklineWidth),
+		stats.Samples, stats.Lost, stats.Loss, stats.Min, stats.Avg, stats.Max, stats.Jitter,
+		series.Alerts, opts.LossThreshold, saved)
+
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// parseMonitorArgs parses the speed:monitor options. Both "--flag value"
+// and "--flag=value" are accepted.
+func parseMonitorArgs(args []string) (speedtest.MonitorOptions, error) {
+	opts := speedtest.MonitorOptions{
+		Target:        speedtest.DefaultMonitorTarget,
+		Duration:      speedtest.DefaultMonitorDuration,
+		Interval:      speedtest.DefaultMonitorInterval,
+		LossThreshold: speedtest.DefaultMonitorLossThreshold,
+	}
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("missing value for %s", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		var err error
+		switch name {
+		case "--target", "-t":
+			// Never let a target be read as an option by ping
+			if value == "" || strings.HasPrefix(value, "-") {
+				return opts, fmt.Errorf("invalid target: %q", value)
+			}
+			opts.Target = value
+		case "--duration", "-d":
+			opts.Duration, err = time.ParseDuration(value)
+			if err == nil && opts.Duration <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "--interval", "-i":
+			opts.Interval, err = time.ParseDuration(value)
+			if err == nil && opts.Interval < 200*time.Millisecond {
+				err = fmt.Errorf("must be at least 200ms")
+			}
+		case "--loss-threshold":
+			opts.LossThreshold, err = strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		default:
+			return opts, fmt.Errorf("unknown option: %s", name)
+		}
+		if err != nil {
+			return opts, fmt.Errorf("invalid value for %s: %v", name, err)
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/notes"
+	"github.com/agnath18K/lumo/pkg/replay"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// rememberResult records a result so `lumo save` can bookmark it. Failing
+// to record it only costs the bookmark, so it is a warning.
+func rememberResult(kind string, cmd *nlp.Command, content string) {
+	if strings.TrimSpace(content) == "" {
+		return
+	}
+	if err := notes.RecordLast(kind, cmd.RawInput, content); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record result: %v\n", err)
+	}
+}
+
+// rememberAgentRun records the plan of an agent run that finished after
+// started, as the script `lumo last --as-script` would print
+func rememberAgentRun(cmd *nlp.Command, started time.Time) {
+	record, err := replay.Load()
+	if err != nil || record == nil || record.FinishedAt.Before(started) {
+		return
+	}
+	rememberResult(notes.KindPlan, cmd, record.Script())
+}
+
+// executeSave bookmarks the last result under a name, with optional tags
+func (e *Executor) executeSave(cmd *nlp.Command) (*Result, error) {
+	usage := "Usage: lumo save <name> [--tag <tag>]..."
+
+	var name string
+	var tags []string
+	args := strings.Fields(cmd.Intent)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--tag" || arg == "-t":
+			if i+1 == len(args) {
+				return &Result{
+					Output:     fmt.Sprintf("Missing tag after %s\n%s", arg, usage),
+					IsError:    true,
+					CommandRun: cmd.RawInput,
+				}, nil
+			}
+			i++
+			tags = append(tags, args[i])
+		case strings.HasPrefix(arg, "--tag="):
+			tags = append(tags, strings.TrimPrefix(arg, "--tag="))
+		case strings.HasPrefix(arg, "-") || name != "":
+			return &Result{
+				Output:     fmt.Sprintf("Unexpected argument: %s\n%s", arg, usage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		default:
+			name = arg
+		}
+	}
+	if name == "" {
+		return &Result{
+			Output:     "Missing note name\n" + usage,
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	note, err := notes.Save(name, tags)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	output := fmt.Sprintf("📌 Saved the last %s as %s", note.Kind, note.Name)
+	if len(note.Tags) > 0 {
+		output += " (#" + strings.Join(note.Tags, " #") + ")"
+	}
+	output += fmt.Sprintf("\nShow it with: lumo notes show %s", note.Name)
+
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// executeNotes lists, shows, searches, and removes saved notes
+func (e *Executor) executeNotes(cmd *nlp.Command) (*Result, error) {
+	usage := "Usage: lumo notes [list | show <name> | search <words> | remove <name>]"
+	args := strings.Fields(cmd.Intent)
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand = args[0]
+		args = args[1:]
+	}
+
+	var output string
+	var err error
+	switch subcommand {
+	case "list":
+		var list []*notes.Note
+		if list, err = notes.List(); err == nil {
+			output = formatNoteList(list, "No notes saved yet. Bookmark a result with: lumo save <name>")
+		}
+
+	case "search":
+		var list []*notes.Note
+		if list, err = notes.Search(strings.Join(args, " ")); err == nil {
+			output = formatNoteList(list, "No notes match.")
+		}
+
+	case "show", "remove":
+		if len(args) != 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing note name\n%s", usage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if subcommand == "remove" {
+			if err = notes.Remove(args[0]); err == nil {
+				output = fmt.Sprintf("Removed note %s", args[0])
+			}
+			break
+		}
+		var note *notes.Note
+		if note, err = notes.Load(args[0]); err == nil {
+			output = formatNote(note)
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown notes command: %s\n%s", subcommand, usage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatNoteList renders notes one per line, or empty if there are none
+func formatNoteList(list []*notes.Note, empty string) string {
+	if len(list) == 0 {
+		return empty
+	}
+	lines := make([]string, 0, len(list))
+	for _, note := range list {
+		lines = append(lines, note.Summary())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatNote renders a note with a header saying where it came from
+func formatNote(note *notes.Note) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📌 %s (%s, %s)\n", note.Name, note.Kind, utils.FormatWhen(note.CreatedAt)))
+	if len(note.Tags) > 0 {
+		b.WriteString("Tags: #" + strings.Join(note.Tags, " #") + "\n")
+	}
+	if note.Command != "" {
+		b.WriteString("Command: " + note.Command + "\n")
+	}
+	b.WriteString("───────────────────────────────────────────────\n")
+	b.WriteString(strings.TrimRight(note.Content, "\n"))
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/palette"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// paletteUsage describes the palette command
+const paletteUsage = "Usage: lumo palette <image> [--count <1-16>]"
+
+// executePalette shows the dominant colors of an image as hex, RGB, and HSL
+func (e *Executor) executePalette(cmd *nlp.Command) (*Result, error) {
+	path, count, err := parsePaletteArgs(strings.Fields(cmd.Intent))
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("%v\n%s", err, paletteUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	path, err = utils.ExpandPath(path)
+	if err == nil {
+		_, err = os.Stat(path)
+	}
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Cannot read %s: %v", path, err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	swatches, err := palette.FromFile(path, count)
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	return &Result{
+		Output:     formatPalette(path, swatches, !e.config.Accessible()),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// parsePaletteArgs reads the image path and how many colors to extract. The
+// path may contain spaces and may be quoted.
+func parsePaletteArgs(args []string) (string, int, error) {
+	count := palette.DefaultCount
+	var path []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name != "--count" {
+			path = append(path, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return "", 0, fmt.Errorf("--count needs a value")
+			}
+			i++
+			value = args[i]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > palette.MaxCount {
+			return "", 0, fmt.Errorf("invalid count %q: use a number from 1 to %d", value, palette.MaxCount)
+		}
+		count = n
+	}
+
+	image := strings.Trim(strings.Join(path, " "), `"'`)
+	if image == "" {
+		return "", 0, fmt.Errorf("an image is required")
+	}
+	return image, count, nil
+}
+
+// formatPalette renders the colors of an image, one line each, with a block
+// of the color when the terminal can show it
+func formatPalette(path string, swatches []palette.Swatch, blocks bool) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🎨 Palette of %s:\n\n", path))
+	for _, swatch := range swatches {
+		b.WriteString("  ")
+		if blocks {
+			b.WriteString(swatch.Color.Block() + "  ")
+		}
+		b.WriteString(fmt.Sprintf("%s  %-18s  %-20s %3.0f%%\n",
+			swatch.Color.Hex(), swatch.Color.RGB(), swatch.Color.HSL(), swatch.Share*100))
+	}
+	return b.String()
+}
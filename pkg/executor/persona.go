@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/shellwords"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// askOptions are the flags that may lead an ask: question
+type askOptions struct {
+	// persona answers in place of the default persona
+	persona string
+	// image is a picture the question is about
+	image string
+}
+
+// splitAskOptions takes the leading --persona and --image flags, in any
+// order, off a query, returning them and the rest of the query as typed
+func splitAskOptions(query string) (askOptions, string, error) {
+	var opts askOptions
+	flags := map[string]*string{"--persona": &opts.persona, "--image": &opts.image}
+	for {
+		found := false
+		for flag, target := range flags {
+			value, rest, ok := splitFlag(query, flag)
+			if !ok {
+				continue
+			}
+			if value == "" {
+				return opts, query, fmt.Errorf("%s needs a value, as in ask:%s=<value> <question>", flag, flag)
+			}
+			*target, query, found = value, rest, true
+		}
+		if !found {
+			return opts, query, nil
+		}
+	}
+}
+
+// splitFlag takes a leading <flag>=<value> or <flag> <value> off a query,
+// returning the value and the rest of the query as typed
+func splitFlag(query, flag string) (string, string, bool) {
+	rest := strings.TrimLeft(query, " \t")
+	if !strings.HasPrefix(rest, flag) {
+		return "", query, false
+	}
+	word, rest := nextWord(rest)
+	value, found := strings.CutPrefix(word, flag+"=")
+	if !found {
+		if word != flag {
+			return "", query, false
+		}
+		value, rest = nextWord(rest)
+	}
+	return value, rest, true
+}
+
+// nextWord splits text into its first word and what follows it. A quoted
+// word, such as an image path with spaces, is one word with its quotes removed.
+func nextWord(text string) (string, string) {
+	text = strings.TrimLeft(text, " \t")
+	end := len(text)
+	var quote byte
+	for i := 0; i < len(text) && end == len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ' ' || c == '\t' || c == '\n':
+			end = i
+		}
+	}
+	return strings.Join(shellwords.Fields(text[:end]), ""), strings.TrimLeft(text[end:], " \t")
+}
+
+// personaInstructions returns the system instructions of the named
+// persona, or of the default persona when name is empty. An empty result
+// means Lumo's own instructions.
+func (e *Executor) personaInstructions(name string) (string, error) {
+	if name == "" {
+		name = e.config.DefaultPersona
+		if name == "" {
+			return "", nil
+		}
+	}
+	instructions, ok := ai.Persona(e.config, name)
+	if !ok {
+		return "", fmt.Errorf("unknown persona: %s. Available: %s", name, strings.Join(ai.PersonaNames(e.config), ", "))
+	}
+	return instructions, nil
+}
+
+// queryImage asks the AI a question about the image at path, with a
+// persona's instructions, if any
+func (e *Executor) queryImage(path, query, instructions string) (string, error) {
+	vision, ok := e.aiClient.(ai.VisionClient)
+	if !ok {
+		return "", fmt.Errorf("%s cannot answer questions about images; use %s", e.config.AIProvider, strings.Join(visionProviders(), " or "))
+	}
+	if strings.TrimSpace(query) == "" {
+		query = "Describe this image."
+	}
+	if instructions != "" {
+		query = fmt.Sprintf("System Instructions: %s\n\nUser Query: %s", instructions, query)
+	}
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+	return vision.QueryWithImage(path, query)
+}
+
+// visionProviders returns the providers that answer questions about images
+func visionProviders() []string {
+	var names []string
+	for _, info := range ai.Providers() {
+		if info.Capabilities.Vision && !info.Capabilities.Local {
+			names = append(names, info.Name)
+		}
+	}
+	return names
+}
+
+// queryAs sends a query to the AI with a persona's instructions, if any
+func (e *Executor) queryAs(query string, instructions string) (string, error) {
+	if instructions == "" {
+		return e.aiClient.Query(query)
+	}
+	return ai.QueryAs(e.aiClient, query, instructions)
+}
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/privacy"
+)
+
+// enforcePrivacy blocks commands that strict privacy mode does not allow.
+// It returns nil when the command may run.
+func (e *Executor) enforcePrivacy(cmd *nlp.Command) *Result {
+	if !privacy.IsStrict(e.config.PrivacyMode) {
+		return nil
+	}
+
+	var err error
+	switch cmd.Type {
+	case nlp.CommandTypeSpeedTest:
+		// Speed tests report to and exchange data with third-party servers
+		err = privacy.Blocked("Speed testing")
+	}
+
+	if err == nil {
+		return nil
+	}
+	return &Result{
+		Output:     lumoerr.Format(err),
+		IsError:    true,
+		CommandRun: cmd.RawInput,
+	}
+}
@@ -0,0 +1,219 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/calendar"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// calendarTimeout bounds talking to Evolution Data Server
+const calendarTimeout = 10 * time.Second
+
+// remindUsage describes the remind command
+const remindUsage = `Usage:
+  lumo remind "<what> <when>"   Set a reminder, e.g. "call mom tomorrow 6pm"
+  lumo remind list              Show reminders still to come
+  lumo remind remove <id>       Delete a reminder
+
+When can be a day (today, tomorrow, friday, 2025-12-24), a time (6pm,
+18:30, noon), both, or a delay (in 20 minutes, in 2 hours). The daemon
+shows reminders as desktop notifications; start it with 'lumo server:start'.`
+
+// agendaUsage describes the agenda command
+const agendaUsage = "Usage: lumo agenda [today|tomorrow|week|<weekday>|<YYYY-MM-DD>]"
+
+// executeRemind sets, lists, and removes reminders, which the daemon shows
+// as desktop notifications
+func (e *Executor) executeRemind(cmd *nlp.Command) (*Result, error) {
+	intent := trimQuotes(strings.TrimSpace(cmd.Intent))
+	subcommand, rest := cutWord(intent)
+
+	ctx, cancel := context.WithTimeout(context.Background(), calendarTimeout)
+	defer cancel()
+
+	switch subcommand {
+	case "", "list":
+		if rest != "" {
+			break
+		}
+		reminders, err := calendar.Pending()
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		return &Result{
+			Output:     formatReminders(reminders),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "remove", "cancel", "delete":
+		id, err := strconv.Atoi(rest)
+		if err != nil {
+			break
+		}
+		reminder, err := calendar.Remove(ctx, id)
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Error: %v", err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		return &Result{
+			Output:     fmt.Sprintf("Removed the reminder to %s", reminder.Text),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+
+	case "help", "--help", "-h":
+		return &Result{
+			Output:     remindUsage,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	text, at, err := calendar.ParseReminder(intent, time.Now())
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v\n\n%s", err, remindUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	reminder, err := calendar.Add(ctx, text, at)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving the reminder: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	output := fmt.Sprintf("⏰ I'll remind you to %s %s", reminder.Text, formatReminderTime(reminder.At, time.Now()))
+	if reminder.EventUID != "" {
+		output += "\nAdded to your Personal calendar."
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// executeAgenda shows the calendar events and reminders of a day or a week
+func (e *Executor) executeAgenda(cmd *nlp.Command) (*Result, error) {
+	now := time.Now()
+	day, days := now, 1
+	switch arg := strings.ToLower(strings.TrimSpace(cmd.Intent)); arg {
+	case "", "today":
+	case "week":
+		days = 7
+	default:
+		parsed, ok := calendar.ParseDay(arg, now)
+		if !ok {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown day: %s\n%s", arg, agendaUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		day = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), calendarTimeout)
+	defer cancel()
+	agenda, err := calendar.GetAgenda(ctx, day, days)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭─────────────────── 📅 Agenda ────────────────────────────╮\n")
+	for _, d := range agenda.Days {
+		b.WriteString(formatAgendaDay(d))
+	}
+	if len(agenda.Calendars) > 0 {
+		fmt.Fprintf(&b, "\n  Calendars: %s\n", strings.Join(agenda.Calendars, ", "))
+	} else {
+		b.WriteString("\n  Only reminders are shown: Evolution Data Server, which\n")
+		b.WriteString("  GNOME Calendar uses, is not available.\n")
+	}
+	b.WriteString("╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatAgendaDay renders a day of the agenda, one line per event
+func formatAgendaDay(day calendar.Day) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n  %s:\n", day.Date.Format("Monday, January 2"))
+	if len(day.Events) == 0 {
+		b.WriteString("   • Nothing planned\n")
+		return b.String()
+	}
+	for _, event := range day.Events {
+		var when string
+		switch {
+		case event.AllDay:
+			when = "all day"
+		case event.End.After(event.Start) && !event.Reminder:
+			when = event.Start.Format("15:04") + "–" + event.End.Format("15:04")
+		default:
+			when = event.Start.Format("15:04")
+		}
+		summary := event.Summary
+		if event.Reminder {
+			summary = "⏰ " + summary
+		}
+		if event.Calendar != "" && !event.Reminder {
+			summary += fmt.Sprintf(" (%s)", event.Calendar)
+		}
+		fmt.Fprintf(&b, "   • %-12s %s\n", when, summary)
+	}
+	return b.String()
+}
+
+// formatReminders lists reminders still to come, with their IDs
+func formatReminders(reminders []calendar.Reminder) string {
+	if len(reminders) == 0 {
+		return "No reminders set. Set one with: lumo remind \"call mom tomorrow 6pm\""
+	}
+	now := time.Now()
+	lines := make([]string, 0, len(reminders))
+	for _, r := range reminders {
+		lines = append(lines, fmt.Sprintf("%3d  %-24s %s", r.ID, formatReminderTime(r.At, now), r.Text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatReminderTime describes when a reminder goes off, such as "today at
+// 18:00" or "on Fri Oct 17 at 09:00"
+func formatReminderTime(at, now time.Time) string {
+	days := int(time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	switch days {
+	case 0:
+		return "today at " + at.Format("15:04")
+	case 1:
+		return "tomorrow at " + at.Format("15:04")
+	}
+	return "on " + at.Format("Mon Jan 2 at 15:04")
+}
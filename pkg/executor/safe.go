@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// enforceSafeMode blocks the commands that reach into the desktop session
+// while lumo runs in safe mode. It returns nil when the command may run.
+func (e *Executor) enforceSafeMode(cmd *nlp.Command) *Result {
+	if !e.config.Safe {
+		return nil
+	}
+
+	var feature string
+	switch cmd.Type {
+	case nlp.CommandTypeDesktop:
+		feature = "The desktop assistant"
+	case nlp.CommandTypeIntegrate:
+		feature = "Desktop integration"
+	default:
+		return nil
+	}
+	return &Result{
+		Output:     feature + " is off in safe mode. Run lumo without --safe to use it.",
+		IsError:    true,
+		CommandRun: cmd.RawInput,
+	}
+}
@@ -0,0 +1,258 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/contacts"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// sendUsage describes the send command
+const sendUsage = `Usage:
+  lumo send <files...> to <contact>     Send files and folders to a contact
+  lumo send <files...> to <ip[:port]>   Send them to an address
+
+The peer must be running 'lumo connect --receive'. Save contacts with:
+  lumo contacts add <name> <ip[:port]|hostname>`
+
+// contactsUsage describes the contacts command
+const contactsUsage = `Usage:
+  lumo contacts [list]                         Show your contacts
+  lumo contacts add <name> <ip[:port]|hostname>  Name a peer
+  lumo contacts remove <name>                  Forget a contact
+  lumo contacts refresh                        Look for your contacts' current addresses`
+
+// peerDiscoveryTimeout bounds looking for a contact's current address
+const peerDiscoveryTimeout = 10 * time.Second
+
+// executeSend sends files to a contact or an address without prompting,
+// the way a file is dropped into 'lumo connect'
+func (e *Executor) executeSend(cmd *nlp.Command) (*Result, error) {
+	words := splitQuoted(cmd.Intent)
+	if len(words) < 3 || words[len(words)-2] != "to" {
+		return &Result{
+			Output:     sendUsage,
+			IsError:    cmd.Intent != "",
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	recipient := words[len(words)-1]
+
+	paths := make([]string, 0, len(words)-2)
+	for _, word := range words[:len(words)-2] {
+		path, err := utils.ExpandPath(word)
+		if err == nil {
+			_, err = os.Stat(path)
+		}
+		if err != nil {
+			return &Result{
+				Output:     fmt.Sprintf("Cannot send %s: %v", word, err),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		paths = append(paths, path)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	address, port, contact, err := e.resolvePeer(ctx, recipient)
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	fmt.Printf("📤 Sending %d item(s) to %s (%s:%d)...\n", len(paths), recipient, address, port)
+	connectManager := connect.NewConnectManager("", port)
+	if err := connectManager.SendFiles(ctx, address, port, paths); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return &Result{
+				Output:     "Sending cancelled",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		message := fmt.Sprintf("Error sending to %s: %v", recipient, err)
+		if contact != nil {
+			message += fmt.Sprintf("\nMake sure %s is running 'lumo connect --receive'.", contact.Name)
+		}
+		return &Result{
+			Output:     message,
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if contact != nil {
+		if err := contacts.MarkSeen(contact.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Contact not updated: %v\n", err)
+		}
+	}
+	return &Result{
+		Output:     fmt.Sprintf("✅ Sent %d item(s) to %s", len(paths), recipient),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// resolvePeer returns where to reach a peer given as an address or a
+// contact's name. A contact's address is refreshed by looking for it on the
+// network first; if it is not found, the address it was last seen at is
+// tried.
+func (e *Executor) resolvePeer(ctx context.Context, peer string) (string, int, *contacts.Contact, error) {
+	if address, port, ok := contacts.ParseAddress(peer); ok {
+		return address, port, nil, nil
+	}
+	contact, err := contacts.Get(peer)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("%v\nSave it with: lumo contacts add %s <ip[:port]|hostname>", err, peer)
+	}
+
+	fmt.Printf("🔍 Looking for %s on the network...\n", contact.Name)
+	browseCtx, cancel := context.WithTimeout(ctx, peerDiscoveryTimeout)
+	defer cancel()
+	services, err := e.browseServices(browseCtx)
+	if err == nil {
+		_, err = contacts.Refresh(services)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not refresh %s's address: %v\n", contact.Name, err)
+	} else if refreshed, getErr := contacts.Get(contact.Name); getErr == nil {
+		contact = refreshed
+	}
+
+	if contact.Address == "" {
+		return "", 0, nil, fmt.Errorf("%s (%s) has not been seen on the network yet; make sure it is running 'lumo connect --receive'", contact.Name, contact.Hostname)
+	}
+	address, port := contact.Target()
+	return address, port, contact, nil
+}
+
+// executeContacts lists, adds, removes, and refreshes contacts
+func (e *Executor) executeContacts(cmd *nlp.Command) (*Result, error) {
+	words := splitQuoted(cmd.Intent)
+	subcommand := ""
+	if len(words) > 0 {
+		subcommand = words[0]
+	}
+
+	var output string
+	var err error
+	switch subcommand {
+	case "", "list":
+		var list []contacts.Contact
+		if list, err = contacts.List(); err == nil {
+			output = formatContacts(list)
+		}
+
+	case "add":
+		if len(words) != 3 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing contact name or address\n%s", contactsUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		var contact *contacts.Contact
+		var replaced bool
+		if contact, replaced, err = contacts.Add(words[1], words[2]); err == nil {
+			verb := "Saved"
+			if replaced {
+				verb = "Updated"
+			}
+			output = fmt.Sprintf("👤 %s contact %s\nSend files with: lumo send <files...> to %s", verb, contact.Name, contact.Name)
+			if contact.Address == "" {
+				output += fmt.Sprintf("\nIts address is found when %s runs 'lumo connect --receive'", contact.Hostname)
+			}
+		}
+
+	case "remove":
+		if len(words) != 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing contact name\n%s", contactsUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if err = contacts.Remove(words[1]); err == nil {
+			output = fmt.Sprintf("Removed contact %s", words[1])
+		}
+
+	case "refresh":
+		fmt.Println("🔍 Looking for your contacts on the network...")
+		ctx, cancel := context.WithTimeout(context.Background(), peerDiscoveryTimeout)
+		defer cancel()
+		var seen []contacts.Contact
+		services, browseErr := e.browseServices(ctx)
+		if err = browseErr; err == nil {
+			seen, err = contacts.Refresh(services)
+		}
+		if err == nil {
+			output = fmt.Sprintf("Found %d contact(s) on the network", len(seen))
+			for _, contact := range seen {
+				address, port := contact.Target()
+				output += fmt.Sprintf("\n  • %s at %s:%d", contact.Name, address, port)
+			}
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown contacts command: %s\n%s", subcommand, contactsUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatContacts renders contacts one per line, with where each was last
+// seen
+func formatContacts(list []contacts.Contact) string {
+	if len(list) == 0 {
+		return "No contacts yet.\nAdd one with: lumo contacts add <name> <ip[:port]|hostname>"
+	}
+
+	var b strings.Builder
+	b.WriteString("👤 Contacts\n")
+	for _, contact := range list {
+		where := "not seen yet"
+		if contact.Address != "" {
+			address, port := contact.Target()
+			where = fmt.Sprintf("%s:%d", address, port)
+		}
+		if contact.Hostname != "" {
+			where = contact.Hostname + ", " + where
+		}
+		seen := ""
+		if !contact.LastSeen.IsZero() {
+			seen = ", last seen " + contact.LastSeen.Format("Jan 2 15:04")
+		}
+		fmt.Fprintf(&b, "  • %-16s %s%s\n", contact.Name, where, seen)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
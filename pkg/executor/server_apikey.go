@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/auth"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// apiKeyUsage describes the server:apikey command
+var apiKeyUsage = `Usage:
+  lumo server:apikey create --name <name> [--scope <scope,...>] [--role <role>]
+                                     Create a key
+  lumo server:apikey list            List keys
+  lumo server:apikey revoke <name>   Remove a key
+
+Scopes: ` + strings.Join(auth.Scopes, ", ") + `. Without --scope a key may
+only run commands (execute).
+Roles: ` + strings.Join(auth.Roles, ", ") + `. Without --role a key is an
+operator, which may run shell and agent commands but not change settings;
+a viewer may only ask the AI and read status and stats.
+Clients send the key in an 'Authorization: Bearer <key>' or
+'X-Api-Key: <key>' header.`
+
+// executeAPIKeyCommand creates, lists, and revokes API keys for the REST
+// server
+func (e *Executor) executeAPIKeyCommand(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 {
+		return &Result{
+			Output:     apiKeyUsage,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	authenticator, err := e.serverAuthenticator()
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var output string
+	switch args[0] {
+	case "create":
+		name, scopes, role, parseErr := parseAPIKeyFlags(args[1:])
+		if parseErr != nil {
+			return &Result{
+				Output:     fmt.Sprintf("%v\n%s", parseErr, apiKeyUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		var key string
+		if key, err = authenticator.CreateAPIKey(name, scopes, role); err == nil {
+			output = fmt.Sprintf("🔑 Created API key %s (scopes: %s, role: %s):\n\n  %s\n\n"+
+				"Copy it now; it is stored hashed and cannot be shown again.", name, strings.Join(scopes, ", "), role, key)
+			if !e.config.EnableAuth {
+				output += "\nAuthentication is off, so the server does not ask for keys yet: lumo config:server auth enable"
+			}
+		}
+
+	case "list":
+		var keys []auth.APIKey
+		if keys, err = authenticator.ListAPIKeys(); err == nil {
+			output = formatAPIKeys(keys)
+		}
+
+	case "revoke", "remove":
+		if len(args) != 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing key name\n%s", apiKeyUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if err = authenticator.RevokeAPIKey(args[1]); err == nil {
+			output = fmt.Sprintf("Revoked API key %s", args[1])
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown apikey command: %s\n%s", args[0], apiKeyUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// serverAuthenticator returns an authenticator for the REST server's
+// credentials
+func (e *Executor) serverAuthenticator() (*auth.Authenticator, error) {
+	credentialsDir, err := paths.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewAuthenticator(e.config.JWTSecret, credentialsDir)
+}
+
+// parseAPIKeyFlags reads --name, --role, and --scope, which may be
+// repeated or list scopes separated by commas
+func parseAPIKeyFlags(args []string) (string, []string, string, error) {
+	var name string
+	var scopes []string
+	role := auth.RoleOperator
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if flag != "--name" && flag != "--scope" && flag != "--role" {
+			return "", nil, "", fmt.Errorf("unknown option: %s", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, "", fmt.Errorf("missing value for %s", flag)
+			}
+			i++
+			value = args[i]
+		}
+		switch flag {
+		case "--name":
+			name = value
+			continue
+		case "--role":
+			role = strings.ToLower(value)
+			continue
+		}
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	if name == "" {
+		return "", nil, "", fmt.Errorf("missing --name")
+	}
+	if len(scopes) == 0 {
+		scopes = []string{auth.ScopeExecute}
+	}
+	return name, scopes, role, nil
+}
+
+// formatAPIKeys renders API keys one per line
+func formatAPIKeys(keys []auth.APIKey) string {
+	if len(keys) == 0 {
+		return "No API keys yet.\nCreate one with: lumo server:apikey create --name <name>"
+	}
+	var b strings.Builder
+	b.WriteString("🔑 API keys\n")
+	for _, key := range keys {
+		created := key.CreatedAt
+		if t, err := time.Parse(time.RFC3339, key.CreatedAt); err == nil {
+			created = utils.FormatTimestamp(t)
+		}
+		fmt.Fprintf(&b, "  • %-16s %s…  %-8s  %s  created %s\n", key.Name, key.Hint, auth.EffectiveRole(key.Role),
+			strings.Join(key.Scopes, ","), created)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/audit"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// defaultAuditTail is how many entries server:audit tail shows by default
+const defaultAuditTail = 20
+
+// auditUsage describes the server:audit command
+const auditUsage = `Usage:
+  lumo server:audit tail [n]  Show the last n commands the server ran (default 20)
+
+Every call to /api/v1/execute is logged, including refused ones, with who
+made it, the command, the status the server answered with, and how long
+it took. Admins can read the same log at /api/v1/audit.`
+
+// executeAuditCommand shows the most recent entries of the server's audit
+// log
+func (e *Executor) executeAuditCommand(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 {
+		return &Result{
+			Output:     auditUsage,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	n := defaultAuditTail
+	if args[0] != "tail" || len(args) > 2 {
+		return &Result{
+			Output:     fmt.Sprintf("Invalid audit command: %s\n%s", strings.Join(args, " "), auditUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Invalid number of entries: %s", args[1]),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+	}
+
+	entries, err := audit.Tail(n)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     formatAuditEntries(entries),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatAuditEntries renders audit entries one per line, oldest first
+func formatAuditEntries(entries []audit.Entry) string {
+	if len(entries) == 0 {
+		return "No commands have been run through the server yet."
+	}
+	var b strings.Builder
+	b.WriteString("📜 Server audit log\n")
+	for _, entry := range entries {
+		mark := "✅"
+		if entry.Status >= 400 || !entry.Success {
+			mark = "❌"
+		}
+		user := entry.User
+		if user == "" {
+			user = entry.Client
+		}
+		fmt.Fprintf(&b, "  %s %s  %-16s %-8s %d %6dms  %s\n",
+			mark, entry.Time.Local().Format("2006-01-02 15:04:05"), user, entry.Type,
+			entry.Status, entry.DurationMS, strings.ReplaceAll(entry.Input, "\n", " "))
+		if entry.Error != "" {
+			message, _, _ := strings.Cut(entry.Error, "\n")
+			fmt.Fprintf(&b, "      %s\n", message)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
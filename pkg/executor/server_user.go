@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/auth"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// userUsage describes the server:user command
+var userUsage = `Usage:
+  lumo server:user add <name> [--role <role>]  Add a user
+  lumo server:user list                        List users and their roles
+  lumo server:user role <name> <role>          Change a user's role
+  lumo server:user remove <name>               Remove a user
+
+Roles: ` + strings.Join(auth.Roles, ", ") + `. An admin may do everything; an
+operator may run shell and agent commands but not change settings; a
+viewer may only ask the AI and read status and stats. Without --role a
+user is a viewer.`
+
+// executeUserCommand adds, lists, and removes the users who log in to the
+// REST server, and changes their roles
+func (e *Executor) executeUserCommand(args []string, cmd *nlp.Command) (*Result, error) {
+	if len(args) == 0 {
+		return &Result{
+			Output:     userUsage,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	authenticator, err := e.serverAuthenticator()
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var output string
+	switch {
+	case args[0] == "add" && len(args) >= 2:
+		role := auth.RoleViewer
+		if len(args) == 4 && args[2] == "--role" {
+			role = strings.ToLower(args[3])
+		} else if len(args) == 3 && strings.HasPrefix(args[2], "--role=") {
+			role = strings.ToLower(strings.TrimPrefix(args[2], "--role="))
+		} else if len(args) != 2 {
+			return &Result{
+				Output:     fmt.Sprintf("Unknown option: %s\n%s", args[2], userUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		// The password is generated like an API key and shown once; the
+		// user changes it at /api/v1/auth/change-password
+		var password string
+		if password, err = auth.GenerateSecureToken(12); err == nil {
+			if err = authenticator.AddUser(args[1], password, role); err == nil {
+				output = fmt.Sprintf("👤 Added %s (role: %s) with the password:\n\n  %s\n\n"+
+					"Share it once; they can change it after logging in.", args[1], role, password)
+			}
+		}
+
+	case args[0] == "list":
+		var users []auth.Credentials
+		if users, err = authenticator.ListUsers(); err == nil {
+			output = formatUsers(users)
+		}
+
+	case args[0] == "role" && len(args) == 3:
+		role := strings.ToLower(args[2])
+		if err = authenticator.SetUserRole(args[1], role); err == nil {
+			output = fmt.Sprintf("%s now has the %s role", args[1], role)
+		}
+
+	case (args[0] == "remove" || args[0] == "delete") && len(args) == 2:
+		if err = authenticator.RemoveUser(args[1]); err == nil {
+			output = fmt.Sprintf("Removed user %s", args[1])
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Invalid user command: %s\n%s", strings.Join(args, " "), userUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatUsers renders users one per line with their roles
+func formatUsers(users []auth.Credentials) string {
+	if len(users) == 0 {
+		return "No users yet. The server creates 'admin' when it first starts."
+	}
+	var b strings.Builder
+	b.WriteString("👤 Users\n")
+	for _, user := range users {
+		fmt.Fprintf(&b, "  • %-16s %s\n", user.Username, auth.EffectiveRole(user.Role))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
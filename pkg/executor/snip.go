@@ -0,0 +1,204 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/snippets"
+)
+
+// snipUsage describes the snip command
+const snipUsage = `Usage:
+  lumo snip [list]                Show your snippets
+  lumo snip add <name> <text>     Save a snippet; \n starts a new line
+  lumo snip show <name>           Show a snippet
+  lumo snip paste <name>          Copy a snippet to the clipboard and print it
+  lumo snip remove <name>         Delete a snippet
+  lumo snip expand [on|off]       Expand abbreviations typed anywhere
+  lumo snip expand prefix <text>  Change what starts an abbreviation`
+
+// executeSnip manages text snippets and their expansion on the desktop
+func (e *Executor) executeSnip(cmd *nlp.Command) (*Result, error) {
+	subcommand, rest := cutWord(cmd.Intent)
+	name, text := cutWord(rest)
+
+	var output string
+	var err error
+	switch subcommand {
+	case "", "list":
+		var list []snippets.Snippet
+		if list, err = snippets.List(); err == nil {
+			output = e.formatSnippetList(list)
+		}
+
+	case "add":
+		text = strings.ReplaceAll(trimQuotes(text), `\n`, "\n")
+		if name == "" || text == "" {
+			return &Result{
+				Output:     fmt.Sprintf("Missing snippet name or text\n%s", snipUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		var replaced bool
+		if replaced, err = snippets.Add(name, text); err == nil {
+			verb := "Saved"
+			if replaced {
+				verb = "Updated"
+			}
+			output = fmt.Sprintf("✂️  %s snippet %s\nPaste it with: lumo snip paste %s", verb, name, name)
+			if e.config.EnableSnippetExpansion {
+				output += fmt.Sprintf("\nOr type %s%s and a space anywhere", e.config.SnippetPrefix, name)
+			}
+		}
+
+	case "show", "paste", "remove":
+		if name == "" || text != "" {
+			return &Result{
+				Output:     fmt.Sprintf("Missing snippet name\n%s", snipUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		if subcommand == "remove" {
+			if err = snippets.Remove(name); err == nil {
+				output = fmt.Sprintf("Removed snippet %s", name)
+			}
+			break
+		}
+		var snippet *snippets.Snippet
+		if snippet, err = snippets.Get(name); err != nil {
+			break
+		}
+		output = snippet.Text
+		if subcommand == "paste" {
+			if _, copyErr := e.clipboard.SetContent(snippet.Text); copyErr != nil {
+				fmt.Fprintf(os.Stderr, "Not copied: %v\n", copyErr)
+			}
+		}
+
+	case "expand":
+		return e.handleSnippetExpansion(name, text, cmd)
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown snip command: %s\n%s", subcommand, snipUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// handleSnippetExpansion shows, turns on and off, and sets the prefix of
+// the daemon's expansion of abbreviations
+func (e *Executor) handleSnippetExpansion(action, value string, cmd *nlp.Command) (*Result, error) {
+	var message string
+	switch action {
+	case "", "status":
+		message = fmt.Sprintf("Snippet expansion is %s; abbreviations start with %q.", onOff(e.config.EnableSnippetExpansion), e.config.SnippetPrefix)
+		if !e.config.EnableSnippetExpansion {
+			message += "\nTurn it on with: lumo snip expand on"
+		}
+		return &Result{
+			Output:     message,
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	case "on", "enable":
+		e.config.EnableSnippetExpansion = true
+		message = fmt.Sprintf(`Snippet expansion enabled. Type %s<name> and a space anywhere to replace it with the snippet.
+
+To see abbreviations, the daemon reads every keyboard (you need to be in the
+input group) and keeps only the word being typed, in memory. It pastes with
+ctrl+v using xdotool on X11, or wtype or ydotool on Wayland, so it does not
+work in terminals. Restart the daemon with 'lumo server:stop' and
+'lumo server:start' to start it.`, e.config.SnippetPrefix)
+	case "off", "disable":
+		e.config.EnableSnippetExpansion = false
+		message = "Snippet expansion disabled; restart the daemon to stop reading the keyboard."
+	case "prefix":
+		value = trimQuotes(value)
+		if value == "" || strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+			return &Result{
+				Output:     "The prefix must be text without spaces, such as ; or //",
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		e.config.SnippetPrefix = value
+		message = fmt.Sprintf("Abbreviations now start with %q. Restart the daemon to apply.", value)
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown expand command: %s. Use 'on', 'off', or 'prefix <text>'.", action),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err := e.config.Save(); err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error saving configuration: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     message,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatSnippetList renders snippets one per line, with the start of their
+// text
+func (e *Executor) formatSnippetList(list []snippets.Snippet) string {
+	if len(list) == 0 {
+		return "No snippets yet. Save one with: lumo snip add <name> <text>"
+	}
+	lines := make([]string, 0, len(list))
+	for _, snippet := range list {
+		text := strings.Join(strings.Fields(snippet.Text), " ")
+		if runes := []rune(text); len(runes) > 50 {
+			text = string(runes[:47]) + "..."
+		}
+		lines = append(lines, fmt.Sprintf("%-16s %s", snippet.Name, text))
+	}
+	if e.config.EnableSnippetExpansion {
+		lines = append(lines, fmt.Sprintf("\nType %s<name> and a space anywhere to expand one.", e.config.SnippetPrefix))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cutWord splits s into its first word and the rest, which keeps its
+// spacing
+func cutWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+	return s, ""
+}
+
+// trimQuotes removes one pair of matching quotes around s
+func trimQuotes(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/agnath18K/lumo/pkg/nlp"
@@ -12,6 +13,11 @@ import (
 
 // executeSpeedTest performs an internet speed test
 func (e *Executor) executeSpeedTest(cmd *nlp.Command) (*Result, error) {
+	// Latency monitoring has its own options and works without internet access
+	if fields := strings.Fields(cmd.Intent); len(fields) > 0 && fields[0] == "monitor" {
+		return e.executeLatencyMonitor(cmd, fields[1:])
+	}
+
 	// Check if there's an internet connection
 	if !utils.CheckInternetConnectivity() {
 		return &Result{
@@ -21,8 +27,15 @@ func (e *Executor) executeSpeedTest(cmd *nlp.Command) (*Result, error) {
 		}, nil
 	}
 
-	// Create a speed tester
-	tester := speedtest.NewSpeedTester()
+	// Create a speed tester for the configured backend
+	tester, err := speedtest.New(e.config.SpeedTestBackend)
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("%v. Choose another backend with 'config:speedtest backend set <backend>'.", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
 
 	// Create a context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.config.SpeedTestTimeout)*time.Second)
@@ -30,7 +43,6 @@ func (e *Executor) executeSpeedTest(cmd *nlp.Command) (*Result, error) {
 
 	// Determine which test to run based on the intent
 	var result *speedtest.SpeedTestResult
-	var err error
 
 	intent := cmd.Intent
 	if intent == "" || intent == "full" {
@@ -55,8 +67,14 @@ func (e *Executor) executeSpeedTest(cmd *nlp.Command) (*Result, error) {
 		}, nil
 	}
 
+	// Full tests feed the weekly speed test report; a result that cannot be
+	// recorded is still shown
+	if intent != "download" && intent != "upload" {
+		_ = speedtest.RecordResult(result)
+	}
+
 	// Format the result
-	formattedResult := tester.FormatResult(result)
+	formattedResult := speedtest.FormatResult(result)
 
 	return &Result{
 		Output:     formattedResult,
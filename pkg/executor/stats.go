@@ -0,0 +1,158 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/metrics"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// metricNames name the kinds of command in the metrics, as the REST API
+// names them
+var metricNames = map[nlp.CommandType]string{
+	nlp.CommandTypeShell:        "shell",
+	nlp.CommandTypeAI:           "ai",
+	nlp.CommandTypeHelp:         "help",
+	nlp.CommandTypeSystem:       "system",
+	nlp.CommandTypeAgent:        "agent",
+	nlp.CommandTypeSystemHealth: "system_health",
+	nlp.CommandTypeSystemReport: "system_report",
+	nlp.CommandTypeChat:         "chat",
+	nlp.CommandTypeConfig:       "config",
+	nlp.CommandTypeSpeedTest:    "speed_test",
+	nlp.CommandTypeMagic:        "magic",
+	nlp.CommandTypeClipboard:    "clipboard",
+	nlp.CommandTypeConnect:      "connect",
+	nlp.CommandTypeCreate:       "create",
+	nlp.CommandTypeDesktop:      "desktop",
+	nlp.CommandTypeServer:       "server",
+	nlp.CommandTypeDoctor:       "doctor",
+	nlp.CommandTypeIntegrate:    "integrate",
+	nlp.CommandTypeLast:         "last",
+	nlp.CommandTypeDiscover:     "discover",
+	nlp.CommandTypeAnalyze:      "analyze",
+	nlp.CommandTypeSave:         "save",
+	nlp.CommandTypeNotes:        "notes",
+	nlp.CommandTypeUsage:        "usage",
+	nlp.CommandTypeStats:        "stats",
+	nlp.CommandTypeTrigger:      "trigger",
+	nlp.CommandTypeToday:        "today",
+	nlp.CommandTypePalette:      "palette",
+	nlp.CommandTypeSnip:         "snip",
+	nlp.CommandTypeRemind:       "remind",
+	nlp.CommandTypeAgenda:       "agenda",
+	nlp.CommandTypeSend:         "send",
+	nlp.CommandTypeContacts:     "contacts",
+}
+
+// CommandTypeName returns the name of a kind of command, as the REST API
+// names it
+func CommandTypeName(t nlp.CommandType) string {
+	if name, ok := metricNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// recordMetrics adds a command that ran to the local metrics, if the user
+// agreed to collect them. Purging the metrics is not recorded, so nothing
+// is left behind.
+func (e *Executor) recordMetrics(cmd *nlp.Command, duration time.Duration, result *Result, err error) {
+	if !e.config.EnableMetrics {
+		return
+	}
+	if cmd.Type == nlp.CommandTypeConfig && strings.HasPrefix(strings.TrimSpace(cmd.Intent), "metrics") {
+		return
+	}
+
+	name := CommandTypeName(cmd.Type)
+	failed := err != nil || (result != nil && result.IsError)
+	if err := metrics.Record(name, duration, failed); err != nil && e.config.Debug {
+		fmt.Fprintf(os.Stderr, "Warning: could not record metrics: %v\n", err)
+	}
+}
+
+// executeStats shows the local usage metrics of the last 30 days, or of
+// all time
+func (e *Executor) executeStats(cmd *nlp.Command) (*Result, error) {
+	period := "Last 30 days"
+	since := time.Now().Add(-metrics.Period)
+	switch cmd.Intent {
+	case "":
+	case "all":
+		period = "All time"
+		since = time.Time{}
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown option: %s\nUsage: lumo stats [all]", cmd.Intent),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	events, err := metrics.Load(since)
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	if len(events) == 0 && !e.config.EnableMetrics {
+		return &Result{
+			Output: "Local metrics are off, so there are no stats to show.\n" +
+				"Turn them on with 'config:metrics enable'; they are kept on this machine only.",
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭─────────────────── 📈 Stats ─────────────────────────────╮\n")
+	b.WriteString(formatStats(period, metrics.Summarize(events)))
+	if !e.config.EnableMetrics {
+		b.WriteString("\n  Collection is off; these were recorded before.\n")
+	}
+	b.WriteString("\n  Kept on this machine only. Delete them with:\n")
+	b.WriteString("   config:metrics purge\n")
+	b.WriteString("╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatStats renders the stats of a period, one line per kind of command
+func formatStats(period string, stats metrics.Stats) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n  %s:\n", period))
+	if stats.Runs == 0 {
+		b.WriteString("   • No commands recorded yet\n")
+		return b.String()
+	}
+
+	days := "days"
+	if stats.ActiveDays == 1 {
+		days = "day"
+	}
+	b.WriteString(fmt.Sprintf("   • %d commands on %d %s, %d failed\n", stats.Runs, stats.ActiveDays, days, stats.Failures))
+	b.WriteString(fmt.Sprintf("   • Busiest around %02d:00\n", stats.BusiestHour))
+	b.WriteString(fmt.Sprintf("   • Recorded since %s\n", stats.First.Local().Format("2006-01-02")))
+
+	b.WriteString("\n  Commands:\n")
+	for _, command := range stats.Commands {
+		line := fmt.Sprintf("   • %-14s %5d runs  %10s avg", command.Command, command.Runs,
+			utils.FormatDuration(time.Duration(command.AverageMS)*time.Millisecond))
+		if command.Failures > 0 {
+			line += fmt.Sprintf("  %d failed", command.Failures)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
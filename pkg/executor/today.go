@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/timetrack"
+)
+
+// todayUsage describes the today command
+const todayUsage = "Usage: lumo today [--date <YYYY-MM-DD|yesterday>] [--csv]"
+
+// executeToday shows where the time tracked on a day went, by application,
+// with focus statistics, or writes it as CSV
+func (e *Executor) executeToday(cmd *nlp.Command) (*Result, error) {
+	day, csvOutput, err := parseTodayArgs(strings.Fields(cmd.Intent), time.Now())
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("%v\n%s", err, todayUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	samples, err := timetrack.LoadDay(day)
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	summary := timetrack.Summarize(samples, e.config.TimeTrackingExclude)
+
+	if csvOutput {
+		var b strings.Builder
+		if err := timetrack.WriteCSV(&b, day, summary); err != nil {
+			return &Result{
+				Output:     err.Error(),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		return &Result{
+			Output:     b.String(),
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if len(samples) == 0 && !e.config.EnableTimeTracking {
+		return &Result{
+			Output: "Time tracking is off, so there is nothing to show.\n" +
+				"Turn it on with 'config:tracking enable'; what it records is kept on this machine only.",
+			IsError:    false,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭─────────────────── ⏳ Today ─────────────────────────────╮\n")
+	b.WriteString(formatToday(day, summary))
+	if !e.config.EnableTimeTracking {
+		b.WriteString("\n  Tracking is off; this was recorded before.\n")
+	}
+	b.WriteString("\n  Kept on this machine only. Export it with:\n")
+	b.WriteString("   lumo today --csv > today.csv\n")
+	b.WriteString("╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// parseTodayArgs reads the day to show and whether to write CSV
+func parseTodayArgs(args []string, now time.Time) (time.Time, bool, error) {
+	day := now
+	csvOutput := false
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		switch name {
+		case "--csv":
+			csvOutput = true
+		case "--date":
+			if !hasValue {
+				if i+1 == len(args) {
+					return time.Time{}, false, fmt.Errorf("--date needs a value")
+				}
+				i++
+				value = args[i]
+			}
+			switch value {
+			case "today":
+				day = now
+			case "yesterday":
+				day = now.AddDate(0, 0, -1)
+			default:
+				parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+				if err != nil {
+					return time.Time{}, false, fmt.Errorf("invalid date %q: use YYYY-MM-DD, today, or yesterday", value)
+				}
+				day = parsed
+			}
+		default:
+			return time.Time{}, false, fmt.Errorf("unknown option: %s", args[i])
+		}
+	}
+	return day, csvOutput, nil
+}
+
+// formatToday renders a day's summary, one line per application
+func formatToday(day time.Time, summary timetrack.Summary) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n  %s:\n", day.Format("Monday, January 2")))
+	if summary.Total == 0 {
+		b.WriteString("   • No time tracked\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("   • %s active between %s and %s\n", formatHours(summary.Total),
+		summary.First.Local().Format("15:04"), summary.Last.Local().Format("15:04")))
+	b.WriteString(fmt.Sprintf("   • Longest focus: %s in %s\n", formatHours(summary.Longest), summary.LongestApp))
+	sessions := "sessions"
+	if summary.DeepFocusSessions == 1 {
+		sessions = "session"
+	}
+	b.WriteString(fmt.Sprintf("   • %d deep focus %s of %s or more, %d app switches\n",
+		summary.DeepFocusSessions, sessions, formatHours(timetrack.DeepFocus), summary.Switches))
+
+	b.WriteString("\n  Apps:\n")
+	for _, app := range summary.Apps {
+		share := float64(app.Duration) / float64(summary.Total)
+		bar := strings.Repeat("█", int(share*20+0.5))
+		b.WriteString(fmt.Sprintf("   • %-16s %7s  %-20s %3.0f%%\n",
+			app.App, formatHours(app.Duration), bar, share*100))
+	}
+	return b.String()
+}
+
+// formatHours formats a duration in hours and minutes, such as 2h 05m
+func formatHours(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
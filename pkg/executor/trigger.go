@@ -0,0 +1,258 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/trigger"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// triggerUsage describes the trigger command
+const triggerUsage = `Usage:
+  lumo trigger [list]
+  lumo trigger add --watch <dir> --run <command> [--glob <pattern>] [--name <name>] [--debounce <duration>] [--dry-run]
+  lumo trigger test|enable|disable|remove <name>
+
+The daemon (lumo server:start) runs <command> for each file created or changed
+in <dir> once it stays unchanged for the debounce (5s by default). In the
+command, {file} is the file's path and {name} its name, quoted when needed.`
+
+// triggerOptions are the options of trigger add that take a value
+var triggerOptions = map[string]bool{"--watch": true, "--glob": true, "--run": true, "--name": true, "--debounce": true}
+
+// executeTrigger adds, lists, previews, enables, disables, and removes the
+// filesystem triggers the daemon runs
+func (e *Executor) executeTrigger(cmd *nlp.Command) (*Result, error) {
+	args := splitQuoted(cmd.Intent)
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand = args[0]
+		args = args[1:]
+	}
+
+	var output string
+	var err error
+	switch subcommand {
+	case "list":
+		var triggers []*trigger.Trigger
+		if triggers, err = trigger.Load(); err == nil {
+			output = formatTriggers(triggers)
+		}
+
+	case "add":
+		var t *trigger.Trigger
+		var dryRun bool
+		if t, dryRun, err = parseTriggerAdd(args); err != nil {
+			break
+		}
+		if dryRun {
+			if err = t.Validate(); err == nil {
+				output, err = previewTrigger(t, "Dry run: nothing was saved.")
+			}
+			break
+		}
+		if err = trigger.Add(t); err == nil {
+			output = fmt.Sprintf("✅ Added trigger %s: runs %q for %s\nThe daemon picks it up within seconds; start it with: lumo server:start\nPreview it with: lumo trigger test %s",
+				t.Name, t.Run, t.Describe(), t.Name)
+		}
+
+	case "test", "enable", "disable", "remove":
+		if len(args) != 1 {
+			return &Result{
+				Output:     fmt.Sprintf("Missing trigger name\n%s", triggerUsage),
+				IsError:    true,
+				CommandRun: cmd.RawInput,
+			}, nil
+		}
+		name := args[0]
+		switch subcommand {
+		case "test":
+			var t *trigger.Trigger
+			if t, err = trigger.Get(name); err == nil {
+				output, err = previewTrigger(t, "Nothing was run.")
+			}
+		case "enable", "disable":
+			if err = trigger.SetEnabled(name, subcommand == "enable"); err == nil {
+				output = fmt.Sprintf("Trigger %s is %sd", name, subcommand)
+			}
+		case "remove":
+			if err = trigger.Remove(name); err == nil {
+				output = fmt.Sprintf("Removed trigger %s", name)
+			}
+		}
+
+	default:
+		return &Result{
+			Output:     fmt.Sprintf("Unknown trigger command: %s\n%s", subcommand, triggerUsage),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	if err != nil {
+		return &Result{
+			Output:     fmt.Sprintf("Error: %v", err),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	return &Result{
+		Output:     output,
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// parseTriggerAdd reads the trigger add arguments. The command after --run
+// runs to the next option, so it need not be quoted on the command line.
+func parseTriggerAdd(args []string) (*trigger.Trigger, bool, error) {
+	t := &trigger.Trigger{}
+	var dryRun bool
+	var run []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name == "--dry-run" && !hasValue {
+			dryRun = true
+			continue
+		}
+		if !triggerOptions[name] {
+			return nil, false, fmt.Errorf("unknown option: %s\n%s", args[i], triggerUsage)
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, false, fmt.Errorf("%s needs a value\n%s", name, triggerUsage)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--watch":
+			dir, err := utils.ExpandPath(value)
+			if err == nil {
+				dir, err = filepath.Abs(dir)
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			t.Watch = dir
+		case "--glob":
+			t.Glob = value
+		case "--name":
+			t.Name = value
+		case "--debounce":
+			debounce, err := parseDebounce(value)
+			if err != nil {
+				return nil, false, err
+			}
+			t.DebounceSeconds = debounce
+		case "--run":
+			run = append(run, value)
+			for i+1 < len(args) && !isTriggerOption(args[i+1]) {
+				i++
+				run = append(run, args[i])
+			}
+		}
+	}
+	t.Run = strings.Join(run, " ")
+	return t, dryRun, nil
+}
+
+// isTriggerOption reports whether arg is an option of trigger add
+func isTriggerOption(arg string) bool {
+	name, _, _ := strings.Cut(arg, "=")
+	return triggerOptions[name] || arg == "--dry-run"
+}
+
+// parseDebounce reads a debounce such as 30s, 2m, or 10, in whole seconds
+func parseDebounce(value string) (int, error) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return seconds, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("invalid debounce %q: use a duration of at least a second, such as 30s or 2m", value)
+	}
+	return int(d.Round(time.Second) / time.Second), nil
+}
+
+// previewTrigger shows what a trigger would run for the files that match it
+// now, without running anything
+func previewTrigger(t *trigger.Trigger, footer string) (string, error) {
+	files, err := t.Files()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("⚡ %s watches %s\n", t.Name, t.Describe()))
+	if len(files) == 0 {
+		b.WriteString("\nNo files there match it now.\n")
+	} else {
+		b.WriteString(fmt.Sprintf("\nIf these %d files were new, it would run:\n", len(files)))
+		for _, file := range files {
+			b.WriteString(fmt.Sprintf("  %s\n", t.Command(file)))
+		}
+	}
+	b.WriteString("\n" + footer)
+	return b.String(), nil
+}
+
+// formatTriggers lists the triggers with what they watch and run
+func formatTriggers(triggers []*trigger.Trigger) string {
+	if len(triggers) == 0 {
+		return "No triggers yet. Add one with: lumo trigger add --watch <dir> --run <command>"
+	}
+	var b strings.Builder
+	for i, t := range triggers {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		state := "enabled"
+		if !t.Enabled {
+			state = "disabled"
+		}
+		b.WriteString(fmt.Sprintf("⚡ %s (%s)\n", t.Name, state))
+		b.WriteString(fmt.Sprintf("    Watches %s\n", t.Describe()))
+		b.WriteString(fmt.Sprintf("    Runs: %s\n", t.Run))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// splitQuoted splits s into words like a shell, keeping words in single
+// or double quotes together
+func splitQuoted(s string) []string {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
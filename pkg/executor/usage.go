@@ -0,0 +1,212 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// executeUsage shows the tokens used today and this month with their
+// estimated cost, and how much of the budget is left
+func (e *Executor) executeUsage(cmd *nlp.Command) (*Result, error) {
+	if cmd.Intent == "network" {
+		return e.executeNetworkUsage(cmd)
+	}
+	if cmd.Intent != "" {
+		return &Result{
+			Output:     fmt.Sprintf("Unknown option: %s\nUsage: lumo usage [network]", cmd.Intent),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	now := time.Now()
+	month, err := usage.Load(usage.StartOfMonth(now))
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+	var today []usage.Entry
+	for _, entry := range month {
+		if !entry.Time.Before(usage.StartOfDay(now)) {
+			today = append(today, entry)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭─────────────────── 📊 AI Usage ──────────────────────────╮\n")
+	b.WriteString(formatUsage("Today", usage.Summarize(today)))
+	b.WriteString(formatUsage(now.Format("January 2006"), usage.Summarize(month)))
+
+	b.WriteString("\n  Budget:\n")
+	if e.config.BudgetUSD > 0 {
+		spent, _ := usage.MonthCost(now)
+		b.WriteString(fmt.Sprintf("   • $%.2f of $%.2f spent this month (%s when reached)\n",
+			spent, e.config.BudgetUSD, e.config.BudgetAction))
+	} else {
+		b.WriteString("   • No monthly budget. Set one with: config:budget set <usd>\n")
+	}
+	b.WriteString("\n  Costs are estimates from list prices; local models are free.\n")
+	b.WriteString("╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// formatUsage renders the totals of a period, one line per model
+func formatUsage(period string, totals []usage.Total) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n  %s:\n", period))
+	if len(totals) == 0 {
+		b.WriteString("   • No AI requests\n")
+		return b.String()
+	}
+
+	requests, tokens, cost := 0, 0, 0.0
+	for _, total := range totals {
+		price := fmt.Sprintf("$%.4f", total.Cost)
+		if !total.Priced {
+			price += "+ (unknown price)"
+		}
+		b.WriteString(fmt.Sprintf("   • %-8s %-22s %4d req  %8d in  %8d out  %s\n",
+			total.Provider, total.Model, total.Requests, total.PromptTokens, total.CompletionTokens, price))
+		requests += total.Requests
+		tokens += total.PromptTokens + total.CompletionTokens
+		cost += total.Cost
+	}
+	b.WriteString(fmt.Sprintf("   Total: %d requests, %d tokens, about $%.2f\n", requests, tokens, cost))
+	return b.String()
+}
+
+// enforceBudget warns about, or refuses, commands that query a cloud AI
+// provider once this month's estimated spending reaches the budget
+func (e *Executor) enforceBudget(cmd *nlp.Command) *Result {
+	if e.config.BudgetUSD <= 0 {
+		return nil
+	}
+	switch cmd.Type {
+	case nlp.CommandTypeAI, nlp.CommandTypeChat, nlp.CommandTypeAgent, nlp.CommandTypeAnalyze:
+	default:
+		return nil
+	}
+	if _, local := e.aiClient.(*ai.OllamaClient); local || ai.IsLocal(e.config.AIProvider) {
+		return nil
+	}
+
+	spent, err := usage.MonthCost(time.Now())
+	if err != nil || spent < e.config.BudgetUSD {
+		return nil
+	}
+
+	message := fmt.Sprintf("Monthly AI budget of $%.2f reached ($%.2f spent this month, see 'lumo usage')",
+		e.config.BudgetUSD, spent)
+	if e.config.BudgetAction != config.BudgetBlock {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", message)
+		return nil
+	}
+	blocked := lumoerr.New(lumoerr.ErrPolicyBlocked, strings.ToLower(message[:1])+message[1:]).
+		WithHint("Raise it with 'config:budget set <usd>', or use a local model with 'lumo -p ollama ...'.")
+	return &Result{
+		Output:     lumoerr.Format(blocked),
+		IsError:    true,
+		CommandRun: cmd.RawInput,
+	}
+}
+
+// executeNetworkUsage shows the traffic of connect and AI requests for each
+// day of this month, and how much of the network cap is left
+func (e *Executor) executeNetworkUsage(cmd *nlp.Command) (*Result, error) {
+	now := time.Now()
+	entries, err := usage.LoadNetwork(usage.StartOfMonth(now))
+	if err != nil {
+		return &Result{
+			Output:     err.Error(),
+			IsError:    true,
+			CommandRun: cmd.RawInput,
+		}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╭─────────────────── 📶 Network Usage ─────────────────────╮\n")
+	b.WriteString(fmt.Sprintf("\n  %s:\n", now.Format("January 2006")))
+	var total int64
+	days := usage.SummarizeNetwork(entries)
+	if len(days) == 0 {
+		b.WriteString("   • No traffic recorded\n")
+	}
+	for _, day := range days {
+		date, _ := time.ParseInLocation("2006-01-02", day.Date, now.Location())
+		sources := make([]string, len(day.Sources))
+		for i, source := range day.Sources {
+			sources[i] = fmt.Sprintf("%s %s", source.Source, formatBytes(source.Sent+source.Received))
+		}
+		b.WriteString(fmt.Sprintf("   • %s  ↑ %-9s ↓ %-9s (%s)\n", date.Format("Mon Jan 2"),
+			formatBytes(day.Sent), formatBytes(day.Received), strings.Join(sources, ", ")))
+		total += day.Sent + day.Received
+	}
+	b.WriteString(fmt.Sprintf("   Total: %s\n", formatBytes(total)))
+
+	b.WriteString("\n  Cap:\n")
+	if limit := e.config.NetworkCapBytes(); limit > 0 {
+		b.WriteString(fmt.Sprintf("   • %s of %s used this month (%d%%)\n", formatBytes(total), formatBytes(limit), total*100/limit))
+		if total >= limit && e.config.NetworkCapAction == config.NetworkCapPause {
+			b.WriteString("   • Reached: scheduled speed tests are paused until next month\n")
+		} else if total >= limit {
+			b.WriteString("   • Reached: the daemon and file transfers warn about it\n")
+		}
+	} else {
+		b.WriteString("   • No monthly cap. Set one with: config:network cap <size>\n")
+	}
+	b.WriteString("\n  Counts files sent with connect and requests to cloud AI\n")
+	b.WriteString("  providers; local models use no network.\n")
+	b.WriteString("╰──────────────────────────────────────────────────────────╯\n")
+
+	return &Result{
+		Output:     b.String(),
+		IsError:    false,
+		CommandRun: cmd.RawInput,
+	}, nil
+}
+
+// warnNetworkCap warns before commands that transfer files once this
+// month's traffic reaches the network cap. Transfers are never refused;
+// the cap only pauses the daemon's speed tests.
+func (e *Executor) warnNetworkCap(cmd *nlp.Command) {
+	limit := e.config.NetworkCapBytes()
+	if limit <= 0 || (cmd.Type != nlp.CommandTypeConnect && cmd.Type != nlp.CommandTypeSend) {
+		return
+	}
+	used, err := usage.MonthNetwork(time.Now())
+	if err != nil || used < limit {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Monthly network cap of %s reached (%s used this month, see 'lumo usage network')\n",
+		formatBytes(limit), formatBytes(used))
+}
+
+// formatBytes renders a byte count with binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
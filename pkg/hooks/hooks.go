@@ -0,0 +1,145 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// Event identifies a lumo event that user hooks can subscribe to
+type Event string
+
+const (
+	// EventPreAgentRun fires after a plan is created and before agent mode runs it
+	EventPreAgentRun Event = "pre-agent-run"
+	// EventPostTransfer fires after a connect file transfer completes
+	EventPostTransfer Event = "post-transfer"
+	// EventOnHealthAlert fires when a health check reports a warning or critical component
+	EventOnHealthAlert Event = "on-health-alert"
+	// EventFocusStart fires when desktop focus mode is turned on
+	EventFocusStart Event = "focus-start"
+	// EventFocusEnd fires when desktop focus mode ends or is turned off
+	EventFocusEnd Event = "focus-end"
+	// EventOnLatencyAlert fires when speed:monitor sees packet loss above its threshold
+	EventOnLatencyAlert Event = "on-latency-alert"
+)
+
+// Events returns every event a hook can be installed for
+func Events() []Event {
+	return []Event{EventPreAgentRun, EventPostTransfer, EventOnHealthAlert, EventFocusStart, EventFocusEnd, EventOnLatencyAlert}
+}
+
+// DefaultTimeout is the maximum time a hook is allowed to run
+const DefaultTimeout = 30 * time.Second
+
+// Payload is the JSON document written to a hook's stdin
+type Payload struct {
+	Event     Event                  `json:"event"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Dir returns the directory containing user hooks (~/.config/lumo/hooks)
+func Dir() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hooks"), nil
+}
+
+// disabled is set in safe mode, when no hook runs
+var disabled bool
+
+// SetDisabled stops hooks from running in this process, or lets them run
+// again. Safe mode disables them so that a misbehaving hook cannot get in
+// the way of fixing it.
+func SetDisabled(off bool) {
+	disabled = off
+}
+
+// Disabled reports whether hooks were disabled for this process
+func Disabled() bool {
+	return disabled
+}
+
+// Path returns the executable path for an event and whether it is installed
+func Path(event Event) (string, bool) {
+	dir, err := Dir()
+	if err != nil {
+		return "", false
+	}
+
+	path := filepath.Join(dir, string(event))
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return path, false
+	}
+
+	// Only run hooks the user has marked executable
+	if info.Mode()&0111 == 0 {
+		return path, false
+	}
+
+	return path, true
+}
+
+// Run executes the hook for an event, passing the payload on stdin.
+// It is a no-op when no hook is installed for the event, or when hooks are
+// disabled. A non-zero exit status is returned as an error together with
+// the hook's output.
+func Run(ctx context.Context, event Event, data map[string]interface{}) error {
+	path, ok := Path(event)
+	if !ok || disabled {
+		return nil
+	}
+
+	payload, err := json.Marshal(Payload{
+		Event:     event,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s payload: %w", event, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), "LUMO_HOOK_EVENT="+string(event))
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(output))
+		if msg != "" {
+			return fmt.Errorf("%s hook failed: %w: %s", event, err, msg)
+		}
+		return fmt.Errorf("%s hook failed: %w", event, err)
+	}
+
+	return nil
+}
+
+// Fire runs the hook for an event in the background, ignoring its result.
+// Use this for notification-style events where a failing hook must not
+// interrupt lumo.
+func Fire(event Event, data map[string]interface{}) {
+	if _, ok := Path(event); !ok || disabled {
+		return
+	}
+	go func() {
+		if err := Run(context.Background(), event, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}()
+}
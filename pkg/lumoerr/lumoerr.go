@@ -0,0 +1,136 @@
+// Package lumoerr is the catalog of errors lumo explains to users. Each
+// kind of error has a code, like LUMO-E1001, that is printed with it and
+// documented in lumo(1) and docs/examples.md, and a hint saying what to do
+// about it. Packages return them at their boundaries, wrapped in an Error
+// with the details, so callers can tell them apart with errors.Is instead
+// of matching messages.
+package lumoerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kind is an entry in the error catalog. It is an error itself, so an
+// Error can be matched with errors.Is(err, lumoerr.ErrOffline).
+type Kind struct {
+	// Code identifies the error in the documentation
+	Code string
+	// Summary says what went wrong, in lower case like other errors
+	Summary string
+	// Hint says what to do about it
+	Hint string
+}
+
+func (k *Kind) Error() string {
+	return k.Summary
+}
+
+// The catalog
+var (
+	// ErrProviderAuth is an AI provider refusing the API key
+	ErrProviderAuth = &Kind{
+		Code:    "LUMO-E1001",
+		Summary: "the AI provider rejected the API key",
+		Hint:    "Check it with 'lumo config:key show' and set a new one with 'lumo config:key set <provider> <key>'.",
+	}
+	// ErrOffline is a request that could not reach its server
+	ErrOffline = &Kind{
+		Code:    "LUMO-E1002",
+		Summary: "cannot reach the network",
+		Hint:    "Check your connection, or use a local model with 'lumo -p ollama ...'.",
+	}
+	// ErrPolicyBlocked is something a setting forbids, like strict privacy
+	// mode, the agent deny list, or the AI budget
+	ErrPolicyBlocked = &Kind{
+		Code:    "LUMO-E2001",
+		Summary: "blocked by policy",
+		Hint:    "See 'lumo config:privacy show', 'lumo config:agent show', and 'lumo config:budget show' for what blocks it.",
+	}
+	// ErrPortInUse is a server whose port, and the ports it tried after it,
+	// were taken
+	ErrPortInUse = &Kind{
+		Code:    "LUMO-E3001",
+		Summary: "the port is already in use",
+		Hint:    "Stop the other lumo server or connect session using it, or choose another port.",
+	}
+)
+
+// Catalog lists every kind of error, in code order
+var Catalog = []*Kind{ErrProviderAuth, ErrOffline, ErrPolicyBlocked, ErrPortInUse}
+
+// Error is an error from the catalog with what happened this time
+type Error struct {
+	Kind *Kind
+	// Detail says what happened, like which port was taken
+	Detail string
+	// Hint replaces the kind's hint when there is a better one here
+	Hint string
+	// Err is the underlying error, if any
+	Err error
+}
+
+// New returns an error of the given kind
+func New(kind *Kind, detail string) *Error {
+	return &Error{Kind: kind, Detail: detail}
+}
+
+// Wrap returns an error of the given kind caused by err
+func Wrap(kind *Kind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// WithHint replaces the kind's hint, returning e
+func (e *Error) WithHint(hint string) *Error {
+	e.Hint = hint
+	return e
+}
+
+func (e *Error) Error() string {
+	detail := e.Detail
+	if detail == "" && e.Err != nil {
+		detail = e.Err.Error()
+	}
+	if detail == "" {
+		return e.Kind.Summary
+	}
+	return e.Kind.Summary + ": " + detail
+}
+
+// Unwrap returns the kind and the underlying error
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Code returns the catalog code of err, or "" if it is not in the catalog
+func Code(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind.Code
+	}
+	return ""
+}
+
+// Format renders err for users. Errors from the catalog get their hint and
+// code on the lines after the message; others are rendered as they are.
+func Format(err error) string {
+	var e *Error
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+	hint := e.Hint
+	if hint == "" {
+		hint = e.Kind.Hint
+	}
+	var b strings.Builder
+	b.WriteString(err.Error())
+	if hint != "" {
+		fmt.Fprintf(&b, "\n💡 %s", hint)
+	}
+	fmt.Fprintf(&b, "\n   [%s] see 'man lumo', ERRORS", e.Kind.Code)
+	return b.String()
+}
@@ -0,0 +1,198 @@
+// Package metrics keeps counts of the commands lumo runs, for 'lumo stats'
+// and the web dashboard. Collecting them is off until the user agrees. The
+// counts are only written to the state directory, never sent anywhere, and
+// hold which kind of command ran but not what was typed.
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// Period is how far back 'lumo stats' and the dashboard look by default
+const Period = 30 * 24 * time.Hour
+
+// Event is one command lumo ran
+type Event struct {
+	Time time.Time `json:"time"`
+	// Command is the kind of command, like "ai", "shell", or "agent"
+	Command    string `json:"command"`
+	DurationMS int64  `json:"duration_ms"`
+	Failed     bool   `json:"failed,omitempty"`
+}
+
+// Path returns the file the metrics are kept in
+func Path() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "metrics.jsonl"), nil
+}
+
+// Record adds a command to the metrics
+func Record(command string, duration time.Duration, failed bool) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(Event{
+		Time:       time.Now(),
+		Command:    command,
+		DurationMS: duration.Milliseconds(),
+		Failed:     failed,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open metrics: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return file.Close()
+}
+
+// Load returns the commands run at or after since. Missing metrics are
+// empty, and lines that cannot be read are skipped.
+func Load(since time.Time) ([]Event, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if !event.Time.Before(since) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	return events, nil
+}
+
+// Purge deletes all collected metrics and returns how many commands they
+// held
+func Purge() (int, error) {
+	events, err := Load(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	path, err := Path()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to delete metrics: %w", err)
+	}
+	return len(events), nil
+}
+
+// CommandStats sums the runs of one kind of command
+type CommandStats struct {
+	Command  string `json:"command"`
+	Runs     int    `json:"runs"`
+	Failures int    `json:"failures"`
+	// AverageMS is how long a run took on average, in milliseconds
+	AverageMS int64 `json:"average_ms"`
+}
+
+// Stats are the metrics of a period added up
+type Stats struct {
+	// First and Last are when the first and last commands ran
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+	Runs     int       `json:"runs"`
+	Failures int       `json:"failures"`
+	// ActiveDays are the days on which lumo ran at least once
+	ActiveDays int `json:"active_days"`
+	// BusiestHour is the hour of the day, from 0 to 23, with the most runs
+	BusiestHour int `json:"busiest_hour"`
+	// Commands are the kinds of command run, the most run first
+	Commands []CommandStats `json:"commands"`
+}
+
+// Summarize adds up events, in their own time zone
+func Summarize(events []Event) Stats {
+	var stats Stats
+	byCommand := make(map[string]*CommandStats)
+	totalMS := make(map[string]int64)
+	days := make(map[string]bool)
+	var hours [24]int
+
+	for _, event := range events {
+		if stats.First.IsZero() || event.Time.Before(stats.First) {
+			stats.First = event.Time
+		}
+		if event.Time.After(stats.Last) {
+			stats.Last = event.Time
+		}
+		stats.Runs++
+		if event.Failed {
+			stats.Failures++
+		}
+		days[event.Time.Format("2006-01-02")] = true
+		hours[event.Time.Hour()]++
+
+		command, ok := byCommand[event.Command]
+		if !ok {
+			command = &CommandStats{Command: event.Command}
+			byCommand[event.Command] = command
+		}
+		command.Runs++
+		if event.Failed {
+			command.Failures++
+		}
+		totalMS[event.Command] += event.DurationMS
+	}
+
+	stats.ActiveDays = len(days)
+	for hour, runs := range hours {
+		if runs > hours[stats.BusiestHour] {
+			stats.BusiestHour = hour
+		}
+	}
+
+	stats.Commands = make([]CommandStats, 0, len(byCommand))
+	for name, command := range byCommand {
+		command.AverageMS = totalMS[name] / int64(command.Runs)
+		stats.Commands = append(stats.Commands, *command)
+	}
+	sort.Slice(stats.Commands, func(i, j int) bool {
+		if stats.Commands[i].Runs != stats.Commands[j].Runs {
+			return stats.Commands[i].Runs > stats.Commands[j].Runs
+		}
+		return stats.Commands[i].Command < stats.Commands[j].Command
+	})
+	return stats
+}
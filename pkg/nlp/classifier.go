@@ -0,0 +1,222 @@
+package nlp
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+)
+
+// maxClassifiedWords is the longest input the classifier routes; longer
+// inputs are questions with details the AI should see
+const maxClassifiedWords = 10
+
+// Classification is a decision to handle an input locally instead of
+// sending it to the AI
+type Classification struct {
+	Type   CommandType
+	Intent string
+	// Source names the rule, or "model", that made the decision
+	Source string
+}
+
+// ClassifyOptions controls what the classifier may route
+type ClassifyOptions struct {
+	// AllowShell lets inspection commands such as "df -h" run directly
+	AllowShell bool
+	// UseModel consults the embedded intent model when no rule matches
+	UseModel bool
+}
+
+// intentRule routes inputs matching a pattern. Build returns the command's
+// intent from the submatches, and false if the match should not be routed.
+type intentRule struct {
+	name    string
+	kind    CommandType
+	pattern *regexp.Regexp
+	build   func(match []string) (string, bool)
+}
+
+// fixedIntent builds rules whose command needs no arguments from the input
+func fixedIntent(intent string) func([]string) (string, bool) {
+	return func([]string) (string, bool) {
+		return intent, true
+	}
+}
+
+// providerIntent switches to a provider, if the word names a registered one
+func providerIntent(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if _, ok := ai.Lookup(name); !ok {
+		return "", false
+	}
+	return "provider set " + name, true
+}
+
+// intentRules are checked in order against the normalized input
+var intentRules = []intentRule{
+	{"health", CommandTypeSystemHealth,
+		regexp.MustCompile(`(?i)^((run|do|check|show) )?(a |the |my )?(system |computer |machine )?health( check| status)?$`),
+		fixedIntent("")},
+	{"health", CommandTypeSystemHealth,
+		regexp.MustCompile(`(?i)^(is|how is|how's) (my |the )?(system|computer|machine|pc|laptop) (doing|ok|okay|healthy|running)( ok| okay| well)?$`),
+		fixedIntent("")},
+	{"health", CommandTypeSystemHealth,
+		regexp.MustCompile(`(?i)^((check|show|what is|what's) )?(my |the )?(current )?(cpu|memory|ram|disk|swap) (usage|load|space)$`),
+		fixedIntent("")},
+	{"report", CommandTypeSystemReport,
+		regexp.MustCompile(`(?i)^((show|generate|create|give me|get) )?(a |an |my |the )?(full )?(system|hardware) (report|info|information|specs|summary|overview)$`),
+		fixedIntent("")},
+	{"report", CommandTypeSystemReport,
+		regexp.MustCompile(`(?i)^what are (my|the) (system |hardware |computer )?specs$`),
+		fixedIntent("")},
+	{"config", CommandTypeConfig,
+		regexp.MustCompile(`(?i)^(switch|change|set) (the )?(ai )?provider to ([a-z0-9_-]+)$`),
+		func(m []string) (string, bool) { return providerIntent(m[4]) }},
+	// Only the bare "use <provider>" changes the provider; sentences such as
+	// "switch to ollama for this" or "use gemini instead" are for the AI,
+	// since the change is saved without asking
+	{"config", CommandTypeConfig,
+		regexp.MustCompile(`(?i)^use ([a-z0-9_-]+)$`),
+		func(m []string) (string, bool) { return providerIntent(m[1]) }},
+	{"config", CommandTypeConfig,
+		regexp.MustCompile(`(?i)^(switch|change|set) (the )?(ai )?model to (\S+)$`),
+		func(m []string) (string, bool) { return "model set " + m[4], true }},
+	{"config", CommandTypeConfig,
+		regexp.MustCompile(`(?i)^(show|what is|what's|which) (my |the )?(current )?(ai )?(provider|model)( am i using)?$`),
+		func(m []string) (string, bool) { return strings.ToLower(m[5]) + " show", true }},
+	{"config", CommandTypeConfig,
+		regexp.MustCompile(`(?i)^(show|open) (my |the )?(lumo )?(config|configuration|settings)$`),
+		fixedIntent("")},
+	{"help", CommandTypeHelp,
+		regexp.MustCompile(`(?i)^(what can you do|(show|list) (me )?(the |all )?(available )?commands)$`),
+		fixedIntent("help")},
+}
+
+// howToQuery matches questions about doing something, which need the AI
+// even when they mention a local feature, e.g. "how do I check disk usage"
+var howToQuery = regexp.MustCompile(`(?i)^(how (do|to|can|would|should)|explain|write|why|what does|tell me about|teach)\b`)
+
+// inspectionCommands are shell commands that only print information, so
+// they can run directly when typed without the shell: prefix. For git, only
+// the listed subcommands qualify.
+var inspectionCommands = map[string][]string{
+	"ls": nil, "pwd": nil, "whoami": nil, "date": nil, "uptime": nil, "df": nil,
+	"du": nil, "free": nil, "hostname": nil, "uname": nil, "id": nil, "groups": nil,
+	"ps": nil, "lsblk": nil, "lscpu": nil, "nproc": nil, "which": nil, "cal": nil,
+	"git": {"status", "log", "diff", "branch", "show"},
+}
+
+// Classify decides whether an input's intent is obvious enough to handle
+// locally. Rules come first; the embedded model, if enabled, is only asked
+// about inputs no rule matched.
+func Classify(input string, opts ClassifyOptions) (*Classification, bool) {
+	query := strings.Join(strings.Fields(strings.TrimRight(strings.TrimSpace(input), "?.! ")), " ")
+	if strings.HasPrefix(strings.ToLower(query), "please ") {
+		query = query[len("please "):]
+	}
+	words := strings.Fields(query)
+	if len(words) == 0 || len(words) > maxClassifiedWords || howToQuery.MatchString(query) {
+		return nil, false
+	}
+
+	// Commands are checked as typed, since their trailing "." or "?" counts
+	if command := strings.Fields(input); opts.AllowShell && isInspectionCommand(command) {
+		return &Classification{Type: CommandTypeShell, Intent: strings.Join(command, " "), Source: "shell"}, true
+	}
+
+	for _, rule := range intentRules {
+		match := rule.pattern.FindStringSubmatch(query)
+		if match == nil {
+			continue
+		}
+		if intent, ok := rule.build(match); ok {
+			return &Classification{Type: rule.kind, Intent: intent, Source: rule.name}, true
+		}
+	}
+
+	if isSpeedTestQuery(query) {
+		return &Classification{Type: CommandTypeSpeedTest, Intent: speedTestIntent(query), Source: "speedtest"}, true
+	}
+
+	if opts.UseModel {
+		if kind, ok := embeddedIntentModel().classify(query); ok {
+			return &Classification{Type: kind, Source: "model"}, true
+		}
+	}
+	return nil, false
+}
+
+// isInspectionCommand reports whether words are an inspection command whose
+// arguments are all flags or existing files. Anything else, like "ls my
+// photos", reads as a request for the AI.
+func isInspectionCommand(words []string) bool {
+	subcommands, ok := inspectionCommands[words[0]]
+	if !ok {
+		return false
+	}
+	if _, err := exec.LookPath(words[0]); err != nil {
+		return false
+	}
+
+	args := words[1:]
+	if subcommands != nil {
+		if len(args) == 0 || !containsWord(subcommands, args[0]) {
+			return false
+		}
+		args = args[1:]
+	}
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "|&;<>()$`\\\"'*?[]{}~") {
+			return false
+		}
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Clean(arg)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// speedTestIntent picks the download or upload test when only one is asked for
+func speedTestIntent(query string) string {
+	lower := strings.ToLower(query)
+	download := strings.Contains(lower, "download")
+	upload := strings.Contains(lower, "upload")
+	switch {
+	case download && !upload:
+		return "download"
+	case upload && !download:
+		return "upload"
+	}
+	return ""
+}
+
+// containsWord reports whether words contains word
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	intentModelOnce sync.Once
+	intentModel     *naiveBayesModel
+)
+
+// embeddedIntentModel returns the intent model, training it on first use
+func embeddedIntentModel() *naiveBayesModel {
+	intentModelOnce.Do(func() {
+		intentModel = newNaiveBayesModel(intentExamples)
+	})
+	return intentModel
+}
@@ -0,0 +1,117 @@
+package nlp
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// intentExamples are labeled phrases the intent model is trained on, one
+// "label<TAB>phrase" per line. Phrases labeled "ai" teach the model what
+// questions about the same topics look like, so they stay with the AI.
+//
+//go:embed intents.txt
+var intentExamples string
+
+// Thresholds for trusting the model; a wrong local answer costs more than
+// the round trip it saves
+const (
+	minModelConfidence = 0.75
+	minKnownTokens     = 2
+)
+
+// modelLabels maps the model's labels to the commands they route to
+var modelLabels = map[string]CommandType{
+	"health":    CommandTypeSystemHealth,
+	"report":    CommandTypeSystemReport,
+	"speedtest": CommandTypeSpeedTest,
+}
+
+// naiveBayesModel is a multinomial naive Bayes classifier over words
+type naiveBayesModel struct {
+	labels []string
+	// docs and words count training phrases and words per label
+	docs  map[string]int
+	words map[string]int
+	// counts holds how often each word appears under each label
+	counts map[string]map[string]int
+	vocab  map[string]bool
+	total  int
+}
+
+// newNaiveBayesModel trains a model on "label<TAB>phrase" lines
+func newNaiveBayesModel(examples string) *naiveBayesModel {
+	m := &naiveBayesModel{
+		docs:   make(map[string]int),
+		words:  make(map[string]int),
+		counts: make(map[string]map[string]int),
+		vocab:  make(map[string]bool),
+	}
+	for _, line := range strings.Split(examples, "\n") {
+		label, phrase, found := strings.Cut(strings.TrimSpace(line), "\t")
+		if !found || strings.HasPrefix(label, "#") {
+			continue
+		}
+		if m.counts[label] == nil {
+			m.counts[label] = make(map[string]int)
+			m.labels = append(m.labels, label)
+		}
+		m.docs[label]++
+		m.total++
+		for _, token := range tokenize(phrase) {
+			m.counts[label][token]++
+			m.words[label]++
+			m.vocab[token] = true
+		}
+	}
+	return m
+}
+
+// classify returns the command the model routes a query to. Queries the
+// model thinks belong to the AI, is unsure about, or mostly does not know
+// the words of are not routed.
+func (m *naiveBayesModel) classify(query string) (CommandType, bool) {
+	tokens := tokenize(query)
+	known := 0
+	for _, token := range tokens {
+		if m.vocab[token] {
+			known++
+		}
+	}
+	if known < minKnownTokens || known*2 < len(tokens) {
+		return CommandTypeUnknown, false
+	}
+
+	// Log-probabilities with add-one smoothing, turned into a softmax
+	scores := make(map[string]float64, len(m.labels))
+	best := ""
+	for _, label := range m.labels {
+		score := math.Log(float64(m.docs[label]) / float64(m.total))
+		for _, token := range tokens {
+			score += math.Log(float64(m.counts[label][token]+1) / float64(m.words[label]+len(m.vocab)))
+		}
+		scores[label] = score
+		if best == "" || score > scores[best] {
+			best = label
+		}
+	}
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	confidence := 1 / sum
+
+	kind, routed := modelLabels[best]
+	if !routed || confidence < minModelConfidence {
+		return CommandTypeUnknown, false
+	}
+	return kind, true
+}
+
+// tokenize splits text into lowercase words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
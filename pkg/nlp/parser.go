@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/deprecation"
 )
 
 // Command represents a parsed command with its type and parameters
@@ -54,6 +55,40 @@ const (
 	CommandTypeDesktop
 	// CommandTypeServer represents a server management command
 	CommandTypeServer
+	// CommandTypeDoctor represents an environment diagnostics command
+	CommandTypeDoctor
+	// CommandTypeIntegrate represents a desktop integration command
+	CommandTypeIntegrate
+	// CommandTypeLast represents a command that shows or replays the last agent run
+	CommandTypeLast
+	// CommandTypeDiscover represents a command that lists lumo instances on the network
+	CommandTypeDiscover
+	// CommandTypeAnalyze represents a read-only agent investigation
+	CommandTypeAnalyze
+	// CommandTypeSave represents a command that bookmarks the last result as a note
+	CommandTypeSave
+	// CommandTypeNotes represents a command that lists, shows, or searches saved notes
+	CommandTypeNotes
+	// CommandTypeUsage represents a command that shows AI token usage and cost
+	CommandTypeUsage
+	// CommandTypeStats represents a command that shows the local usage metrics
+	CommandTypeStats
+	// CommandTypeTrigger represents a command that manages filesystem triggers
+	CommandTypeTrigger
+	// CommandTypeToday represents a command that shows the day's tracked time
+	CommandTypeToday
+	// CommandTypePalette represents a command that extracts an image's dominant colors
+	CommandTypePalette
+	// CommandTypeSnip represents a command that manages text snippets
+	CommandTypeSnip
+	// CommandTypeRemind represents a command that sets or lists reminders
+	CommandTypeRemind
+	// CommandTypeAgenda represents a command that shows calendar events and reminders
+	CommandTypeAgenda
+	// CommandTypeSend represents a command that sends files to a contact or peer
+	CommandTypeSend
+	// CommandTypeContacts represents a command that manages the peers files are sent to
+	CommandTypeContacts
 )
 
 // Parser handles natural language parsing
@@ -89,10 +124,7 @@ func (p *Parser) Parse(input string) (*Command, error) {
 	// Check for shell command prefix - ONLY execute shell commands with explicit prefix
 	if strings.HasPrefix(input, "shell:") {
 		// Check if we're in interactive mode and shell commands are disabled
-		args := os.Args
-		isInteractiveMode := len(args) <= 1 || input != strings.Join(args[1:], " ")
-
-		if isInteractiveMode && !p.config.EnableShellInInteractive {
+		if isInteractiveInput(input) && !p.config.EnableShellInInteractive {
 			// Shell commands are disabled in interactive mode
 			cmd.Type = CommandTypeAI
 			cmd.Intent = input
@@ -105,11 +137,15 @@ func (p *Parser) Parse(input string) (*Command, error) {
 		return cmd, nil
 	}
 
-	// Legacy "lumo:" prefix is now treated as an AI query for safety
-	if strings.HasPrefix(input, "lumo:") {
-		cmd.Type = CommandTypeAI
-		cmd.Intent = strings.TrimSpace(input[5:])
-		return cmd, nil
+	// Renamed prefixes, like the legacy "lumo:", run as their replacement;
+	// the deprecation code tells callers to warn about it
+	rewritten, deprecated, err := deprecation.Rewrite(input)
+	if err != nil {
+		return nil, err
+	}
+	if deprecated != nil {
+		input = rewritten
+		cmd.Parameters["deprecated"] = deprecated.Code
 	}
 
 	// Check for AI query prefix
@@ -134,6 +170,13 @@ func (p *Parser) Parse(input string) (*Command, error) {
 		return cmd, nil
 	}
 
+	// Check for read-only analysis prefix
+	if strings.HasPrefix(input, "analyze:") {
+		cmd.Type = CommandTypeAnalyze
+		cmd.Intent = strings.TrimSpace(input[8:])
+		return cmd, nil
+	}
+
 	// Check for system health command prefix
 	if strings.HasPrefix(input, "health:") || strings.HasPrefix(input, "syshealth:") {
 		cmd.Type = CommandTypeSystemHealth
@@ -194,32 +237,6 @@ func (p *Parser) Parse(input string) (*Command, error) {
 		return cmd, nil
 	}
 
-	// Check for clipboard command
-	if input == "clipboard" || strings.HasPrefix(input, "clipboard ") {
-		cmd.Type = CommandTypeClipboard
-		cmd.Intent = strings.TrimSpace(strings.TrimPrefix(input, "clipboard"))
-		return cmd, nil
-	}
-
-	// Check for connect command
-	if input == "connect" || strings.HasPrefix(input, "connect ") {
-		cmd.Type = CommandTypeConnect
-		cmd.Intent = strings.TrimSpace(strings.TrimPrefix(input, "connect"))
-		return cmd, nil
-	}
-
-	// Check for create command prefix
-	if strings.HasPrefix(input, "create:") || input == "create" {
-		cmd.Type = CommandTypeCreate
-		if strings.HasPrefix(input, "create:") {
-			cmd.Intent = strings.TrimSpace(input[7:])
-		} else {
-			// Just "create" shows help
-			cmd.Intent = ""
-		}
-		return cmd, nil
-	}
-
 	// Check for desktop command prefix
 	if strings.HasPrefix(input, "desktop:") {
 		cmd.Type = CommandTypeDesktop
@@ -234,6 +251,20 @@ func (p *Parser) Parse(input string) (*Command, error) {
 		return cmd, nil
 	}
 
+	// Check for commands given as a word
+	for _, c := range commandWords {
+		if isCommandWord(input, c) {
+			cmd.Type = c.typ
+			cmd.Intent = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(input, c.word), ":"))
+			return cmd, nil
+		}
+	}
+
+	// Route inputs whose intent is obvious without a round trip to the AI
+	if routed, ok := p.Classify(input); ok {
+		return routed, nil
+	}
+
 	// Check if this is a command-line argument (first argument is the program name)
 	args := os.Args
 	if len(args) > 1 && input == strings.Join(args[1:], " ") {
@@ -246,8 +277,9 @@ func (p *Parser) Parse(input string) (*Command, error) {
 		return cmd, nil
 	}
 
-	// Check if this looks like a speed test query
-	if isSpeedTestQuery(input) {
+	// Check if this looks like a speed test query; safe mode keeps to
+	// prefixes and sends everything else to the AI
+	if !p.config.Safe && isSpeedTestQuery(input) {
 		cmd.Type = CommandTypeSpeedTest
 		cmd.Intent = input
 		return cmd, nil
@@ -262,6 +294,192 @@ func (p *Parser) Parse(input string) (*Command, error) {
 	return cmd, nil
 }
 
+// commandWord is a command given as a word, like "notes list", rather than
+// a prefix. Each is also accepted with a colon, like "notes:list".
+type commandWord struct {
+	word string
+	typ  CommandType
+	// args reports whether input, the word followed by a space and more,
+	// is the command rather than a question that starts with the same
+	// word; nil accepts anything after the word
+	args func(input string) bool
+}
+
+// commandWords are the commands given as a word, in the order they are checked
+var commandWords = []commandWord{
+	{"clipboard", CommandTypeClipboard, nil},
+	{"connect", CommandTypeConnect, nil},
+	{"create", CommandTypeCreate, isCreateSubcommand},
+	{"doctor", CommandTypeDoctor, noArgs},
+	{"integrate", CommandTypeIntegrate, nil},
+	{"last", CommandTypeLast, hasOptions},
+	{"discover", CommandTypeDiscover, nil},
+	{"save", CommandTypeSave, isSaveCommand},
+	{"notes", CommandTypeNotes, isNotesCommand},
+	{"usage", CommandTypeUsage, oneOf("usage network")},
+	{"stats", CommandTypeStats, oneOf("stats all")},
+	{"trigger", CommandTypeTrigger, nil},
+	// Options are required after a space, so a question that starts with
+	// "today" still goes to the AI
+	{"today", CommandTypeToday, hasOptions},
+	{"palette", CommandTypePalette, nil},
+	{"snip", CommandTypeSnip, nil},
+	{"remind", CommandTypeRemind, nil},
+	{"agenda", CommandTypeAgenda, nil},
+	// Without a recipient, send is a question for the AI
+	{"send", CommandTypeSend, isSendCommand},
+	{"contacts", CommandTypeContacts, isContactsCommand},
+}
+
+// CommandWords returns the words that start a command, such as "notes"
+func CommandWords() []string {
+	words := make([]string, len(commandWords))
+	for i, c := range commandWords {
+		words[i] = c.word
+	}
+	return words
+}
+
+// isCommandWord reports whether input is the command c: its word alone,
+// the word and a colon, or the word followed by arguments it accepts
+func isCommandWord(input string, c commandWord) bool {
+	if input == c.word || strings.HasPrefix(input, c.word+":") {
+		return true
+	}
+	if !strings.HasPrefix(input, c.word+" ") {
+		return false
+	}
+	return c.args == nil || c.args(input)
+}
+
+// noArgs accepts no arguments after a command word
+func noArgs(string) bool {
+	return false
+}
+
+// hasOptions accepts arguments that start with an option, like "today --csv"
+func hasOptions(input string) bool {
+	fields := strings.Fields(input)
+	return len(fields) > 1 && strings.HasPrefix(fields[1], "--")
+}
+
+// oneOf accepts only the given command lines
+func oneOf(commands ...string) func(string) bool {
+	return func(input string) bool {
+		for _, c := range commands {
+			if strings.Join(strings.Fields(input), " ") == c {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Classify returns the command for an input the local classifier can
+// handle without the AI, if routing is enabled and lumo is not in safe mode. Shell commands are only
+// routed where the shell: prefix would be allowed.
+func (p *Parser) Classify(input string) (*Command, bool) {
+	if !p.config.LocalIntentRouting || p.config.Safe {
+		return nil, false
+	}
+	classification, ok := Classify(input, ClassifyOptions{
+		AllowShell: p.config.EnableShellInInteractive || !isInteractiveInput(input),
+		UseModel:   p.config.LocalIntentModel,
+	})
+	if !ok {
+		return nil, false
+	}
+	return &Command{
+		Type:       classification.Type,
+		Intent:     classification.Intent,
+		Parameters: map[string]string{"classifier": classification.Source},
+		RawInput:   strings.TrimSpace(input),
+	}, true
+}
+
+// isInteractiveInput reports whether input was typed in interactive mode
+// rather than given as lumo's command-line arguments
+func isInteractiveInput(input string) bool {
+	args := os.Args
+	return len(args) <= 1 || input != strings.Join(args[1:], " ")
+}
+
+// isSaveCommand reports whether input is "save <name>" followed only by tag
+// options, rather than a question that happens to start with "save"
+func isSaveCommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || fields[0] != "save" || strings.HasPrefix(fields[1], "-") {
+		return false
+	}
+	for i := 2; i < len(fields); i++ {
+		switch {
+		case fields[i] == "--tag" || fields[i] == "-t":
+			i++
+			if i == len(fields) {
+				return false
+			}
+		case strings.HasPrefix(fields[i], "--tag="):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isNotesCommand reports whether input is "notes" followed by one of its subcommands
+func isNotesCommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || fields[0] != "notes" {
+		return false
+	}
+	switch fields[1] {
+	case "list", "show", "search", "remove":
+		return true
+	}
+	return false
+}
+
+// isSendCommand reports whether input is "send", one or more files, "to",
+// and a single recipient
+func isSendCommand(input string) bool {
+	fields := strings.Fields(input)
+	n := len(fields)
+	return n >= 4 && fields[0] == "send" && fields[n-2] == "to"
+}
+
+// isContactsCommand reports whether input is "contacts" followed by one of its subcommands
+func isContactsCommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || fields[0] != "contacts" {
+		return false
+	}
+	switch fields[1] {
+	case "list", "add", "remove", "refresh":
+		return true
+	}
+	return false
+}
+
+// isCreateSubcommand reports whether input is "create" followed by one of
+// its subcommands, by an ai: description, or by a framework and a project
+// name
+func isCreateSubcommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || fields[0] != "create" {
+		return false
+	}
+	if strings.HasPrefix(fields[1], "ai:") {
+		return true
+	}
+	switch fields[1] {
+	case "go", "list-templates", "from":
+		return true
+	case "fastapi", "flask", "django", "express":
+		return len(fields) > 2
+	}
+	return false
+}
+
 // IsNaturalLanguageQuery determines if a string is likely to be a natural language query
 // rather than a shell command. This is exported for use in other packages.
 func IsNaturalLanguageQuery(input string) bool {
@@ -0,0 +1,99 @@
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// index holds every note in one file, so searching reads one file instead
+// of one per note
+type index struct {
+	// DirModified and Files describe the notes directory when the index was
+	// built; the index is stale once either changes
+	DirModified time.Time `json:"dir_modified"`
+	Files       int       `json:"files"`
+	Notes       []*Note   `json:"notes"`
+}
+
+// indexPath returns the path of the search index, which is kept outside
+// the notes directory so it is never listed as a note
+func indexPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "notes_index.json"), nil
+}
+
+// notesState returns the modification time of the notes directory and how
+// many notes it holds. Notes are only ever added or removed, never
+// rewritten, so both change whenever the notes do.
+func notesState() (time.Time, int, error) {
+	dir, err := notesDir()
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return info.ModTime(), len(files), nil
+}
+
+// Index rebuilds the search index from the notes and returns how many notes
+// it holds. The daemon runs it as a noisy task, while the user is idle.
+func Index() (int, error) {
+	modified, files, err := notesState()
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	notes, err := List()
+	if err != nil {
+		return 0, err
+	}
+
+	path, err := indexPath()
+	if err != nil {
+		return 0, err
+	}
+	data, err := json.Marshal(&index{DirModified: modified, Files: files, Notes: notes})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode the notes index: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write the notes index: %w", err)
+	}
+	return len(notes), nil
+}
+
+// indexedNotes returns the notes from the search index, newest first, or
+// false when there is no index or the notes changed since it was built
+func indexedNotes() ([]*Note, bool) {
+	path, err := indexPath()
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, false
+	}
+	modified, files, err := notesState()
+	if err != nil || !modified.Equal(idx.DirModified) || files != idx.Files {
+		return nil, false
+	}
+	return idx.Notes, true
+}
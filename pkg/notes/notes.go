@@ -0,0 +1,295 @@
+// Package notes keeps results the user bookmarked, such as AI answers,
+// reports, and agent plans, so they do not get lost in scrollback.
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// Kinds of results that can be saved
+const (
+	KindAnswer   = "answer"
+	KindReport   = "report"
+	KindPlan     = "plan"
+	KindAnalysis = "analysis"
+)
+
+// validName matches note names, which are also their file names
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Note is a saved result
+type Note struct {
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	// Kind says what produced the result, e.g. "answer" or "plan"
+	Kind string `json:"kind"`
+	// Command is the lumo command that produced the result
+	Command   string    `json:"command"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// dataDir returns the directory lumo keeps its data in
+func dataDir() (string, error) {
+	return paths.DataDir()
+}
+
+// notesDir returns the directory notes are saved in
+func notesDir() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "notes"), nil
+}
+
+// lastPath returns the path of the last result, which is kept outside the
+// notes directory so it is never listed as a note
+func lastPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "last_result.json"), nil
+}
+
+// RecordLast remembers a result so it can be saved with `lumo save`
+func RecordLast(kind, command, content string) error {
+	path, err := lastPath()
+	if err != nil {
+		return err
+	}
+	return writeNote(path, &Note{
+		Kind:      kind,
+		Command:   command,
+		Content:   content,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Last returns the last result, or nil if nothing has been recorded
+func Last() (*Note, error) {
+	path, err := lastPath()
+	if err != nil {
+		return nil, err
+	}
+	note, err := readNote(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return note, err
+}
+
+// Save bookmarks the last result under a name. Names are not reused, so an
+// existing note is never overwritten.
+func Save(name string, tags []string) (*Note, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid note name %q: use up to 64 letters, digits, dots, dashes, and underscores", name)
+	}
+
+	note, err := Last()
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, fmt.Errorf("there is no result to save yet; ask a question or run a task first")
+	}
+
+	dir, err := notesDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name+".json")
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("a note named %q already exists", name)
+	}
+
+	note.Name = name
+	note.Tags = NormalizeTags(tags)
+	if err := writeNote(path, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// Load returns the note with the given name
+func Load(name string) (*Note, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("no note named %q", name)
+	}
+	dir, err := notesDir()
+	if err != nil {
+		return nil, err
+	}
+	note, err := readNote(filepath.Join(dir, name+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no note named %q", name)
+	}
+	return note, err
+}
+
+// Remove deletes the note with the given name
+func Remove(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("no note named %q", name)
+	}
+	dir, err := notesDir()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(dir, name+".json"))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no note named %q", name)
+	}
+	return err
+}
+
+// List returns every note, newest first
+func List() ([]*Note, error) {
+	dir, err := notesDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*Note
+	for _, path := range paths {
+		note, err := readNote(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", filepath.Base(path), err)
+			continue
+		}
+		notes = append(notes, note)
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.After(notes[j].CreatedAt)
+	})
+	return notes, nil
+}
+
+// Search returns the notes that contain every word of the query in their
+// name, tags, command, or content, ignoring case. A word starting with #
+// only matches tags. The search index is used while it is up to date.
+func Search(query string) ([]*Note, error) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return nil, fmt.Errorf("nothing to search for")
+	}
+
+	notes, ok := indexedNotes()
+	if !ok {
+		var err error
+		if notes, err = List(); err != nil {
+			return nil, err
+		}
+	}
+	var matches []*Note
+	for _, note := range notes {
+		if note.matches(words) {
+			matches = append(matches, note)
+		}
+	}
+	return matches, nil
+}
+
+// matches reports whether the note contains every word
+func (n *Note) matches(words []string) bool {
+	text := strings.ToLower(strings.Join([]string{n.Name, n.Command, n.Content}, "\n"))
+	for _, word := range words {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			if !n.HasTag(tag) {
+				return false
+			}
+			continue
+		}
+		if !strings.Contains(text, word) && !n.HasTag(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasTag reports whether the note is tagged with tag
+func (n *Note) HasTag(tag string) bool {
+	for _, t := range n.Tags {
+		if t == strings.ToLower(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags lowercases tags, splits comma-separated lists, and drops
+// duplicates and a leading #
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, list := range tags {
+		for _, tag := range strings.Split(list, ",") {
+			tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// Summary describes the note on one line for listings
+func (n *Note) Summary() string {
+	line := fmt.Sprintf("%-20s %-9s %s", n.Name, n.Kind, utils.FormatRelative(n.CreatedAt))
+	if len(n.Tags) > 0 {
+		line += "  #" + strings.Join(n.Tags, " #")
+	}
+	if command := strings.Join(strings.Fields(n.Command), " "); command != "" {
+		if len(command) > 50 {
+			command = command[:47] + "..."
+		}
+		line += "  " + command
+	}
+	return line
+}
+
+// writeNote stores a note as JSON. Results can contain anything the user
+// asked about, so the file is kept private.
+func writeNote(path string, note *Note) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	data, err := json.MarshalIndent(note, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode note: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write note: %w", err)
+	}
+	return nil
+}
+
+// readNote loads a note from a JSON file
+func readNote(path string) (*Note, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var note Note
+	if err := json.Unmarshal(data, &note); err != nil {
+		return nil, fmt.Errorf("failed to parse note: %w", err)
+	}
+	return &note, nil
+}
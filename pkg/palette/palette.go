@@ -0,0 +1,270 @@
+// Package palette describes colors as hex, RGB, and HSL, and extracts the
+// dominant colors of an image.
+package palette
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // Register the GIF decoder
+	_ "image/jpeg" // Register the JPEG decoder
+	_ "image/png"  // Register the PNG decoder
+	"math"
+	"os"
+	"sort"
+)
+
+// DefaultCount is how many colors a palette has unless asked otherwise,
+// and MaxCount the most it may have
+const (
+	DefaultCount = 5
+	MaxCount     = 16
+)
+
+// maxSamples bounds how many pixels of a large image are looked at
+const maxSamples = 250000
+
+// Color is an opaque RGB color
+type Color struct {
+	R, G, B uint8
+}
+
+// Hex returns the color as #RRGGBB
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// RGB returns the color as rgb(r, g, b)
+func (c Color) RGB() string {
+	return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+}
+
+// HSL returns the color as hsl(h, s%, l%)
+func (c Color) HSL() string {
+	h, s, l := c.hsl()
+	return fmt.Sprintf("hsl(%.0f, %.0f%%, %.0f%%)", h, s*100, l*100)
+}
+
+// hsl returns the hue in degrees and the saturation and lightness from 0 to 1
+func (c Color) hsl() (float64, float64, float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+
+	d := max - min
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return math.Round(h), s, l
+}
+
+// Block returns a block of the color for terminals that show 24-bit color
+func (c Color) Block() string {
+	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm      \x1b[0m", c.R, c.G, c.B)
+}
+
+// FromFloats returns the color with red, green, and blue from 0 to 1, as
+// desktop services report them
+func FromFloats(r, g, b float64) Color {
+	channel := func(v float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+	return Color{R: channel(r), G: channel(g), B: channel(b)}
+}
+
+// Swatch is one of an image's dominant colors with the share of the image
+// it covers, from 0 to 1
+type Swatch struct {
+	Color Color
+	Share float64
+}
+
+// FromFile extracts the dominant colors of a PNG, JPEG, or GIF image
+func FromFile(path string, count int) ([]Swatch, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s as an image: %w (PNG, JPEG, and GIF are supported)", path, err)
+	}
+	return Extract(img, count)
+}
+
+// bucket gathers the pixels whose colors fall in one cell of the color
+// cube
+type bucket struct {
+	r, g, b float64
+	count   float64
+}
+
+// Extract returns up to count dominant colors of an image, the most
+// common first. Similar colors are grouped with k-means, which starts from
+// the most common colors that differ most, so the result is the same on
+// every run. Transparent pixels are left out.
+func Extract(img image.Image, count int) ([]Swatch, error) {
+	if count < 1 || count > MaxCount {
+		return nil, fmt.Errorf("the number of colors must be between 1 and %d", MaxCount)
+	}
+
+	// Group the pixels into a 32×32×32 color cube, keeping the average
+	// color of each cell, so the clustering works on thousands of cells
+	// instead of millions of pixels
+	bounds := img.Bounds()
+	step := 1
+	for (bounds.Dx()/step)*(bounds.Dy()/step) > maxSamples {
+		step++
+	}
+	cells := make(map[int]*bucket)
+	total := 0.0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo the premultiplied alpha
+			r, g, b = r*0xffff/a>>8, g*0xffff/a>>8, b*0xffff/a>>8
+			key := int(r>>3)<<10 | int(g>>3)<<5 | int(b>>3)
+			cell, ok := cells[key]
+			if !ok {
+				cell = &bucket{}
+				cells[key] = cell
+			}
+			cell.r += float64(r)
+			cell.g += float64(g)
+			cell.b += float64(b)
+			cell.count++
+			total++
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("the image has no opaque pixels")
+	}
+
+	points := make([]bucket, 0, len(cells))
+	for _, cell := range cells {
+		points = append(points, bucket{r: cell.r / cell.count, g: cell.g / cell.count, b: cell.b / cell.count, count: cell.count})
+	}
+	// Sort so the result does not depend on map order
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].count != points[j].count {
+			return points[i].count > points[j].count
+		}
+		return rgbKey(points[i]) < rgbKey(points[j])
+	})
+	if count > len(points) {
+		count = len(points)
+	}
+
+	centers := initialCenters(points, count)
+	assignment := make([]int, len(points))
+	for iteration := 0; iteration < 20; iteration++ {
+		changed := false
+		for i, p := range points {
+			nearest := 0
+			for c := range centers {
+				if distance(p, centers[c]) < distance(p, centers[nearest]) {
+					nearest = c
+				}
+			}
+			if assignment[i] != nearest {
+				assignment[i] = nearest
+				changed = true
+			}
+		}
+
+		sums := make([]bucket, len(centers))
+		for i, p := range points {
+			sum := &sums[assignment[i]]
+			sum.r += p.r * p.count
+			sum.g += p.g * p.count
+			sum.b += p.b * p.count
+			sum.count += p.count
+		}
+		for c, sum := range sums {
+			if sum.count > 0 {
+				centers[c] = bucket{r: sum.r / sum.count, g: sum.g / sum.count, b: sum.b / sum.count, count: sum.count}
+			} else {
+				centers[c].count = 0
+			}
+		}
+		if !changed && iteration > 0 {
+			break
+		}
+	}
+
+	var swatches []Swatch
+	for _, center := range centers {
+		if center.count == 0 {
+			continue
+		}
+		swatches = append(swatches, Swatch{
+			Color: Color{R: roundChannel(center.r), G: roundChannel(center.g), B: roundChannel(center.b)},
+			Share: center.count / total,
+		})
+	}
+	sort.SliceStable(swatches, func(i, j int) bool {
+		return swatches[i].Share > swatches[j].Share
+	})
+	return swatches, nil
+}
+
+// initialCenters picks the most common color, and then each time the color
+// that is most common weighed by how far it is from the colors picked
+func initialCenters(points []bucket, count int) []bucket {
+	centers := []bucket{points[0]}
+	nearest := make([]float64, len(points))
+	for i := range points {
+		nearest[i] = distance(points[i], points[0])
+	}
+	for len(centers) < count {
+		best, bestScore := -1, 0.0
+		for i, p := range points {
+			if score := p.count * nearest[i]; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		centers = append(centers, points[best])
+		for i := range points {
+			nearest[i] = math.Min(nearest[i], distance(points[i], points[best]))
+		}
+	}
+	return centers
+}
+
+// distance is the squared distance between two colors, weighted roughly
+// by how sensitive the eye is to each channel
+func distance(a, b bucket) float64 {
+	dr, dg, db := a.r-b.r, a.g-b.g, a.b-b.b
+	return 2*dr*dr + 4*dg*dg + 3*db*db
+}
+
+// rgbKey orders colors for sorting
+func rgbKey(b bucket) float64 {
+	return b.r*65536 + b.g*256 + b.b
+}
+
+// roundChannel rounds a channel to 0-255
+func roundChannel(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(255, v))))
+}
@@ -0,0 +1,151 @@
+// Package paths locates lumo's files following the XDG Base Directory
+// specification: settings in $XDG_CONFIG_HOME/lumo, history and logs in
+// $XDG_STATE_HOME/lumo, saved data in $XDG_DATA_HOME/lumo, and files that
+// can be recreated in $XDG_CACHE_HOME/lumo. Unset variables fall back to
+// the directories the specification names under the home directory.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// appName is the directory lumo uses inside each base directory
+const appName = "lumo"
+
+var (
+	mu sync.RWMutex
+	// configFile is the configuration file chosen with --config, if any
+	configFile string
+)
+
+// baseDir returns $env, or fallback under the home directory when env is
+// unset or not absolute, as the specification asks
+func baseDir(env string, fallback ...string) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(append([]string{homeDir}, fallback...)...), nil
+}
+
+// ConfigDir returns the directory holding lumo's settings, hooks, and
+// credentials (~/.config/lumo)
+func ConfigDir() (string, error) {
+	dir, err := baseDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// StateDir returns the directory holding history, logs, and run records
+// (~/.local/state/lumo)
+func StateDir() (string, error) {
+	dir, err := baseDir("XDG_STATE_HOME", ".local", "state")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// DataDir returns the directory holding data the user saved, like notes
+// (~/.local/share/lumo)
+func DataDir() (string, error) {
+	dir, err := baseDir("XDG_DATA_HOME", ".local", "share")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// CacheDir returns the directory holding files that can be recreated or
+// thrown away (~/.cache/lumo)
+func CacheDir() (string, error) {
+	dir, err := baseDir("XDG_CACHE_HOME", ".cache")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName), nil
+}
+
+// SetConfigFile uses path as the configuration file instead of
+// config.json in ConfigDir, as --config does
+func SetConfigFile(path string) error {
+	if path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("invalid config path %s: %w", path, err)
+		}
+		path = abs
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	configFile = path
+	return nil
+}
+
+// ConfigFileOverride returns the configuration file set with
+// SetConfigFile, or "" when the default one is used
+func ConfigFileOverride() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return configFile
+}
+
+// ConfigFile returns the path of the configuration file
+func ConfigFile() (string, error) {
+	if path := ConfigFileOverride(); path != "" {
+		return path, nil
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// legacyState are files older versions kept under the home directory,
+// relative to it, and their names in StateDir
+var legacyState = map[string]string{
+	".lumo_history":                      "history",
+	".config/lumo/agent_runs":            "agent_runs",
+	".config/lumo/last_run.json":         "last_run.json",
+	".config/lumo/transfer_history.json": "transfer_history.json",
+	".config/lumo/latency":               "latency",
+	".config/lumo/focus.json":            "focus.json",
+}
+
+// MigrateLegacy moves history and run records from where older versions
+// kept them into StateDir. Files already in StateDir are never replaced,
+// and files that cannot be moved stay where they are.
+func MigrateLegacy() {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	stateDir, err := StateDir()
+	if err != nil {
+		return
+	}
+
+	for old, name := range legacyState {
+		from := filepath.Join(homeDir, filepath.FromSlash(old))
+		to := filepath.Join(stateDir, name)
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(stateDir, 0700); err != nil {
+			return
+		}
+		_ = os.Rename(from, to)
+	}
+}
@@ -38,6 +38,48 @@ func (p *Processor) ProcessInput(reader io.Reader) (string, error) {
 	return p.analyzeContent(content)
 }
 
+// maxContextBytes caps how much piped input goes into a prompt. Logs and
+// command output are usually most relevant at the end, so that is kept.
+const maxContextBytes = 64 * 1024
+
+// MergeInput reads piped input and combines it with a question about it,
+// so `cat error.log | lumo ask:"why is this failing"` asks the question
+// with the log as context
+func (p *Processor) MergeInput(reader io.Reader, prompt string) (string, error) {
+	content, err := readAllInput(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read piped input: %w", err)
+	}
+	if strings.TrimSpace(content) == "" {
+		return prompt, nil
+	}
+	return MergePrompt(content, prompt), nil
+}
+
+// MergePrompt combines piped content with a prompt, keeping only the end
+// of content that is too long
+func MergePrompt(content, prompt string) string {
+	note := ""
+	if len(content) > maxContextBytes {
+		content = content[len(content)-maxContextBytes:]
+		if newline := strings.IndexByte(content, '\n'); newline >= 0 {
+			content = content[newline+1:]
+		}
+		note = " (only the end is shown; the beginning was cut for length)"
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	return fmt.Sprintf(`%s
+
+Use the following input, which was piped to this command, as context%s:
+
+--- BEGIN INPUT ---
+%s--- END INPUT ---
+`, strings.TrimSpace(prompt), note, content)
+}
+
 // readAllInput reads all input from a reader
 func readAllInput(reader io.Reader) (string, error) {
 	scanner := bufio.NewScanner(reader)
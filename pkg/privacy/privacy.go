@@ -0,0 +1,175 @@
+// Package privacy implements lumo's strict privacy mode, in which prompts
+// and data stay on this machine.
+package privacy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/shellwords"
+)
+
+// Privacy modes
+const (
+	// ModeStandard allows cloud providers and network features
+	ModeStandard = "standard"
+	// ModeStrict keeps prompts and data on this machine
+	ModeStrict = "strict"
+)
+
+// LocalProvider is the only AI provider used in strict mode
+const LocalProvider = "ollama"
+
+// IsStrict reports whether the given privacy mode is strict
+func IsStrict(mode string) bool {
+	return mode == ModeStrict
+}
+
+// standardHint says how to allow what strict mode blocks
+const standardHint = "Strict privacy mode is on; allow it again with 'lumo config:privacy standard'."
+
+// Blocked returns the error shown when strict mode blocks a feature
+func Blocked(feature string) error {
+	return lumoerr.New(lumoerr.ErrPolicyBlocked, fmt.Sprintf("%s is disabled in strict privacy mode", feature)).
+		WithHint(standardHint)
+}
+
+// UploadBlocked returns the error shown when strict mode blocks a command
+// that would upload data, for the reason UploadReason gave
+func UploadBlocked(reason string) error {
+	return lumoerr.New(lumoerr.ErrPolicyBlocked, "strict privacy mode: "+reason).WithHint(standardHint)
+}
+
+// uploadTools are commands whose only purpose is moving data to another machine
+var uploadTools = map[string]bool{
+	"scp": true, "sftp": true, "ftp": true, "lftp": true, "nc": true, "ncat": true,
+	"netcat": true, "socat": true, "pastebinit": true, "termbin": true,
+}
+
+// uploadSubcommands are subcommands that publish or copy data to a remote service
+var uploadSubcommands = map[string][]string{
+	"git":    {"push"},
+	"rclone": {"copy", "copyto", "sync", "move", "moveto"},
+	"gsutil": {"cp", "rsync", "mv"},
+	"docker": {"push"},
+	"podman": {"push"},
+	"npm":    {"publish"},
+	"twine":  {"upload"},
+	"gh":     {"gist", "release"},
+	"lumo":   {"connect"},
+}
+
+// curlUploadFlags are curl options that send a request body or file
+var curlUploadFlags = []string{
+	"-T", "--upload-file", "-d", "--data", "--data-binary", "--data-raw", "--data-urlencode",
+	"-F", "--form", "--json",
+}
+
+// wgetUploadFlags are wget options that send a request body or file
+var wgetUploadFlags = []string{"--post-data", "--post-file", "--body-data", "--body-file"}
+
+// UploadReason returns why a shell command would send data off this machine,
+// or an empty string if it would not. The check is a heuristic over each
+// command in a pipeline or list, including the scripts passed to sh -c, not
+// a full shell parser.
+func UploadReason(command string) string {
+	for _, cmd := range shellwords.Unwrap(command) {
+		name := filepath.Base(cmd.Words[0])
+		args := cmd.Words[1:]
+
+		if uploadTools[name] {
+			return fmt.Sprintf("%s transfers data to another machine", name)
+		}
+		subArgs := args
+		if name == "git" {
+			subArgs = skipGitOptions(args)
+		}
+		for _, sub := range uploadSubcommands[name] {
+			if len(subArgs) > 0 && subArgs[0] == sub {
+				return fmt.Sprintf("%s %s uploads data", name, sub)
+			}
+		}
+
+		switch name {
+		case "curl":
+			if flag := findFlag(args, curlUploadFlags); flag != "" {
+				return fmt.Sprintf("curl %s sends data to a server", flag)
+			}
+			if method := requestMethod(args, "-X", "--request"); method == "POST" || method == "PUT" {
+				return fmt.Sprintf("curl %s sends data to a server", method)
+			}
+		case "wget":
+			if flag := findFlag(args, wgetUploadFlags); flag != "" {
+				return fmt.Sprintf("wget %s sends data to a server", flag)
+			}
+		case "rsync":
+			for _, arg := range args {
+				if !strings.HasPrefix(arg, "-") && (strings.Contains(arg, ":") || strings.HasPrefix(arg, "rsync://")) {
+					return "rsync copies files to a remote host"
+				}
+			}
+		case "aws":
+			if len(args) > 1 && args[0] == "s3" && (args[1] == "cp" || args[1] == "sync" || args[1] == "mv") {
+				return fmt.Sprintf("aws s3 %s uploads data", args[1])
+			}
+		case "ssh":
+			// ssh fed by a pipe sends the piped data to the remote host
+			if cmd.Piped {
+				return "piping data into ssh sends it to a remote host"
+			}
+		}
+	}
+	return ""
+}
+
+// gitValueOptions are git's global options that take the next word as
+// their value
+var gitValueOptions = map[string]bool{
+	"-C": true, "-c": true, "--git-dir": true, "--work-tree": true, "--namespace": true, "--config-env": true,
+}
+
+// skipGitOptions drops git's global options, such as -C dir, from in front
+// of its subcommand
+func skipGitOptions(args []string) []string {
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if gitValueOptions[args[0]] && len(args) > 1 {
+			args = args[1:]
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// findFlag returns the first argument matching one of the flags, including
+// the --flag=value form and flags with their value attached (-dvalue)
+func findFlag(args, flags []string) string {
+	for _, arg := range args {
+		for _, flag := range flags {
+			if arg == flag || strings.HasPrefix(arg, flag+"=") ||
+				(!strings.HasPrefix(flag, "--") && strings.HasPrefix(arg, flag) && !strings.HasPrefix(arg, "--")) {
+				return flag
+			}
+		}
+	}
+	return ""
+}
+
+// requestMethod returns the HTTP method set with the given flags, upper-cased
+func requestMethod(args []string, flags ...string) string {
+	for i, arg := range args {
+		for _, flag := range flags {
+			switch {
+			case arg == flag && i+1 < len(args):
+				return strings.ToUpper(strings.Trim(args[i+1], `'"`))
+			case strings.HasPrefix(arg, flag+"="):
+				return strings.ToUpper(strings.Trim(strings.TrimPrefix(arg, flag+"="), `'"`))
+			case !strings.HasPrefix(flag, "--") && strings.HasPrefix(arg, flag) && len(arg) > len(flag):
+				// Short flags may carry their value, e.g. -XPOST
+				return strings.ToUpper(strings.Trim(strings.TrimPrefix(arg, flag), `'"`))
+			}
+		}
+	}
+	return ""
+}
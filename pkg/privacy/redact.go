@@ -0,0 +1,45 @@
+package privacy
+
+import "regexp"
+
+// Redacted replaces secrets removed from text
+const Redacted = "[REDACTED]"
+
+// secretPatterns match credentials that should never reach a cloud
+// provider. Patterns with a group keep the group, such as the name a
+// secret was assigned to, and replace the rest.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),                                // OpenAI and Anthropic
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`),                                // Google
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}`),                           // GitHub
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}`),                         // GitHub fine-grained
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),                          // AWS access key IDs
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),                         // Slack
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), // JWTs
+	regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/=-]{16,}`),
+	regexp.MustCompile(`([a-z][a-z0-9+.-]*://[^/\s:@]+:)[^/\s@]+(@)`), // Passwords in URLs
+	regexp.MustCompile(`(?i)(\b(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token|client[_-]?secret)["']?\s*[:=]\s*["']?)[^\s"',;]+`),
+}
+
+// Redact replaces secrets such as API keys, tokens, private keys, and
+// passwords in text with [REDACTED], returning the text and how many were
+// removed. It catches common formats and is no substitute for keeping
+// secrets out of prompts.
+func Redact(text string) (string, int) {
+	count := 0
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			count++
+			groups := pattern.FindStringSubmatch(match)
+			switch len(groups) {
+			case 2:
+				return groups[1] + Redacted
+			case 3:
+				return groups[1] + Redacted + groups[2]
+			}
+			return Redacted
+		})
+	}
+	return text, count
+}
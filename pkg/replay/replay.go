@@ -0,0 +1,337 @@
+// Package replay records the commands lumo ran on the user's behalf so they
+// can be reviewed or replayed elsewhere as a shell script.
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// maxChecksumSize is the largest input file that is checksummed
+const maxChecksumSize = 64 << 20
+
+// Record is a completed run of commands
+type Record struct {
+	// Source is what ran the commands, e.g. "agent"
+	Source      string      `json:"source"`
+	Task        string      `json:"task"`
+	Description string      `json:"description,omitempty"`
+	StartedAt   time.Time   `json:"started_at"`
+	FinishedAt  time.Time   `json:"finished_at"`
+	Success     bool        `json:"success"`
+	Environment Environment `json:"environment"`
+	Steps       []Step      `json:"steps"`
+	// Inputs are the files the commands referenced, checksummed before they ran
+	Inputs []FileChecksum `json:"inputs,omitempty"`
+}
+
+// Environment describes the machine the commands ran on
+type Environment struct {
+	Hostname string `json:"hostname"`
+	User     string `json:"user"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Shell    string `json:"shell"`
+	WorkDir  string `json:"work_dir"`
+	// Tools maps each program the commands call to where it was found
+	Tools map[string]string `json:"tools,omitempty"`
+}
+
+// Step is a single command of a run
+type Step struct {
+	Command     string        `json:"command"`
+	Description string        `json:"description,omitempty"`
+	Critical    bool          `json:"critical"`
+	Executed    bool          `json:"executed"`
+	Success     bool          `json:"success"`
+	Error       string        `json:"error,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	// OutputSHA256 lets a replay be compared against the original output
+	OutputSHA256 string `json:"output_sha256,omitempty"`
+}
+
+// FileChecksum is the SHA-256 checksum of a file
+type FileChecksum struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// OutputChecksum returns the hex SHA-256 checksum of a step's output
+func OutputChecksum(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:])
+}
+
+// CaptureEnvironment describes the current machine and the tools the commands need
+func CaptureEnvironment(commands []string) Environment {
+	env := Environment{
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Shell: os.Getenv("SHELL"),
+		Tools: make(map[string]string),
+	}
+	env.Hostname, _ = os.Hostname()
+	env.WorkDir, _ = os.Getwd()
+	if current, err := user.Current(); err == nil {
+		env.User = current.Username
+	}
+
+	for _, command := range commands {
+		for _, tool := range programs(command) {
+			if path, err := exec.LookPath(tool); err == nil {
+				env.Tools[tool] = path
+			}
+		}
+	}
+	return env
+}
+
+// ChecksumInputs checksums the existing files that the commands refer to
+func ChecksumInputs(commands []string) []FileChecksum {
+	seen := make(map[string]bool)
+	var inputs []FileChecksum
+
+	for _, command := range commands {
+		for _, field := range strings.Fields(command) {
+			path := strings.Trim(field, `'"`)
+			if path == "" || strings.HasPrefix(path, "-") || seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() || info.Size() > maxChecksumSize {
+				continue
+			}
+			sum, err := fileSHA256(path)
+			if err != nil {
+				continue
+			}
+			inputs = append(inputs, FileChecksum{Path: path, SHA256: sum})
+		}
+	}
+	return inputs
+}
+
+// programs returns the programs a command line calls, skipping sudo, env, and
+// variable assignments
+func programs(command string) []string {
+	var tools []string
+	segments := strings.FieldsFunc(command, func(r rune) bool {
+		return r == '|' || r == ';' || r == '&' || r == '(' || r == ')'
+	})
+	for _, segment := range segments {
+		for _, field := range strings.Fields(segment) {
+			if field == "sudo" || field == "env" || (strings.Contains(field, "=") && !strings.HasPrefix(field, "-")) {
+				continue
+			}
+			tools = append(tools, filepath.Base(strings.Trim(field, `'"`)))
+			break
+		}
+	}
+	return tools
+}
+
+// fileSHA256 returns the hex SHA-256 checksum of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Script renders the record as a bash script that replays the executed commands
+func (r *Record) Script() string {
+	var b strings.Builder
+
+	b.WriteString("#!/usr/bin/env bash\n")
+	b.WriteString(fmt.Sprintf("# Replay of a lumo %s run\n", r.Source))
+	b.WriteString(fmt.Sprintf("# Task: %s\n", oneLine(r.Task)))
+	if r.Description != "" {
+		b.WriteString(fmt.Sprintf("# Plan: %s\n", oneLine(r.Description)))
+	}
+	status := "succeeded"
+	if !r.Success {
+		status = "failed"
+	}
+	b.WriteString(fmt.Sprintf("# Recorded: %s (%s)\n", r.FinishedAt.Format(time.RFC3339), status))
+	b.WriteString(fmt.Sprintf("# Environment: %s@%s, %s/%s, shell %s\n",
+		r.Environment.User, r.Environment.Hostname, r.Environment.OS, r.Environment.Arch, r.Environment.Shell))
+	b.WriteString("#\n# Review every command before running this script.\n")
+	b.WriteString("# Set LUMO_REPLAY_FORCE=1 to run even if the input files changed.\n\n")
+	b.WriteString("set -e\n\n")
+
+	if r.Environment.WorkDir != "" {
+		b.WriteString(fmt.Sprintf("cd %s\n\n", shellQuote(r.Environment.WorkDir)))
+	}
+
+	if len(r.Environment.Tools) > 0 {
+		tools := make([]string, 0, len(r.Environment.Tools))
+		for tool := range r.Environment.Tools {
+			tools = append(tools, tool)
+		}
+		sort.Strings(tools)
+
+		b.WriteString("# Tools the commands need\n")
+		b.WriteString(fmt.Sprintf("for tool in %s; do\n", strings.Join(quoteAll(tools), " ")))
+		b.WriteString("  command -v \"$tool\" >/dev/null 2>&1 || { echo \"Missing required tool: $tool\" >&2; exit 1; }\n")
+		b.WriteString("done\n\n")
+	}
+
+	if len(r.Inputs) > 0 {
+		b.WriteString("# Input files as they were before the original run\n")
+		b.WriteString("if ! sha256sum --check --quiet <<'LUMO_CHECKSUMS'; then\n")
+		for _, input := range r.Inputs {
+			b.WriteString(fmt.Sprintf("%s  %s\n", input.SHA256, input.Path))
+		}
+		b.WriteString("LUMO_CHECKSUMS\n")
+		b.WriteString("  echo \"Input files differ from the recorded run\" >&2\n")
+		b.WriteString("  [ \"${LUMO_REPLAY_FORCE:-0}\" = 1 ] || exit 1\n")
+		b.WriteString("fi\n\n")
+	}
+
+	for i, step := range r.Steps {
+		if !step.Executed {
+			b.WriteString(fmt.Sprintf("# Step %d was not executed in the recorded run:\n# %s\n\n", i+1, oneLine(step.Command)))
+			continue
+		}
+
+		b.WriteString(fmt.Sprintf("# Step %d", i+1))
+		if step.Description != "" {
+			b.WriteString(": " + oneLine(step.Description))
+		}
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("# Output sha256: %s\n", step.OutputSHA256))
+
+		command := step.Command
+		if !step.Success {
+			b.WriteString(fmt.Sprintf("# Failed in the recorded run: %s\n", oneLine(step.Error)))
+			if !step.Critical {
+				// The original run carried on past this step
+				command += " || true"
+			}
+		}
+		b.WriteString(command + "\n\n")
+	}
+
+	return b.String()
+}
+
+// Summary describes the record for display
+func (r *Record) Summary() string {
+	var b strings.Builder
+
+	status := "succeeded"
+	if !r.Success {
+		status = "failed"
+	}
+	b.WriteString(fmt.Sprintf("Last %s run %s %s\n", r.Source, status, utils.FormatWhen(r.FinishedAt)))
+	b.WriteString(fmt.Sprintf("Task: %s\n", oneLine(r.Task)))
+	b.WriteString(fmt.Sprintf("Directory: %s\n\n", r.Environment.WorkDir))
+
+	for i, step := range r.Steps {
+		mark := "✓"
+		switch {
+		case !step.Executed:
+			mark = "-"
+		case !step.Success:
+			mark = "✗"
+		}
+		b.WriteString(fmt.Sprintf("  %s %d. %s\n", mark, i+1, oneLine(step.Command)))
+	}
+
+	b.WriteString("\nSave a replayable script with: lumo last --as-script > redo.sh")
+	return b.String()
+}
+
+// oneLine collapses a string onto a single line for use in a comment
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// shellQuote quotes a string for use as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteAll shell-quotes every string
+func quoteAll(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = shellQuote(item)
+	}
+	return quoted
+}
+
+// recordPath returns the path of the last run record
+func recordPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "last_run.json"), nil
+}
+
+// Save stores the record as the last run
+func Save(record *Record) error {
+	path, err := recordPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run record: %w", err)
+	}
+	// The record holds commands and paths, so keep it private
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+	return nil
+}
+
+// Load loads the last run, or nil if nothing has been recorded
+func Load() (*Record, error) {
+	path, err := recordPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run record: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse run record: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,257 @@
+// Package report renders the system report and speed test summary as HTML
+// and emails them. The daemon sends each report to its recipients once a
+// week; config:report send sends one right away.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/email"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/speedtest"
+	"github.com/agnath18K/lumo/pkg/system"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// Names are the reports that can be emailed
+var Names = []string{"system", "speedtest"}
+
+// Period is how often a report is emailed, and how far back the speed
+// test summary looks
+const Period = 7 * 24 * time.Hour
+
+// sentFileName is the file in StateDir recording when each report was last
+// emailed, so restarting the daemon does not send them again
+const sentFileName = "report_emails.json"
+
+// Render returns the subject and HTML body of a report
+func Render(name string, now time.Time) (string, string, error) {
+	hostname, _ := os.Hostname()
+	var body bytes.Buffer
+	switch name {
+	case "system":
+		report, err := system.NewReportGenerator().GenerateReport()
+		if err != nil {
+			return "", "", err
+		}
+		health, err := system.NewHealthChecker().CheckHealth()
+		if err != nil {
+			return "", "", err
+		}
+		err = systemTemplate.Execute(&body, map[string]interface{}{
+			"Report": report,
+			"Health": health,
+			"Date":   utils.FormatTimestamp(now),
+		})
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("System report for %s", hostname), body.String(), nil
+
+	case "speedtest":
+		results, err := speedtest.LoadHistory(now.Add(-Period))
+		if err != nil {
+			return "", "", err
+		}
+		var rows []map[string]interface{}
+		for _, result := range results {
+			rows = append(rows, map[string]interface{}{
+				"Time":   utils.FormatTimestamp(result.Timestamp),
+				"Result": result,
+			})
+		}
+		err = speedTestTemplate.Execute(&body, map[string]interface{}{
+			"Hostname": hostname,
+			"From":     utils.FormatTimestamp(now.Add(-Period)),
+			"To":       utils.FormatTimestamp(now),
+			"Summary":  speedtest.Summarize(results),
+			"Rows":     rows,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("Weekly speed test summary for %s", hostname), body.String(), nil
+	}
+	return "", "", fmt.Errorf("unknown report: %s (use %s)", name, strings.Join(Names, " or "))
+}
+
+// Email renders a report and sends it to its recipients
+func Email(cfg *config.Config, name string, now time.Time) error {
+	recipients := cfg.ReportRecipients[name]
+	if len(recipients) == 0 {
+		return fmt.Errorf("the %s report has no recipients (see 'config:report email')", name)
+	}
+	subject, body, err := Render(name, now)
+	if err != nil {
+		return fmt.Errorf("failed to render the %s report: %w", name, err)
+	}
+	if err := email.Send(cfg, recipients, subject, body); err != nil {
+		return err
+	}
+	return recordSent(name, now)
+}
+
+// EmailDue emails every report with recipients that was last sent at least
+// a Period ago, or never. It returns the names of the reports it sent.
+func EmailDue(cfg *config.Config, now time.Time) ([]string, error) {
+	sent, err := loadSent()
+	if err != nil {
+		return nil, err
+	}
+
+	var emailed []string
+	var errs []error
+	for _, name := range Names {
+		if len(cfg.ReportRecipients[name]) == 0 || now.Sub(sent[name]) < Period {
+			continue
+		}
+		if err := Email(cfg, name, now); err != nil {
+			errs = append(errs, fmt.Errorf("%s report: %w", name, err))
+			continue
+		}
+		emailed = append(emailed, name)
+	}
+	return emailed, errors.Join(errs...)
+}
+
+// LastSent returns when a report was last emailed, or the zero time
+func LastSent(name string) time.Time {
+	sent, _ := loadSent()
+	return sent[name]
+}
+
+// IsReport reports whether name is a report that can be emailed
+func IsReport(name string) bool {
+	return slices.Contains(Names, name)
+}
+
+// sentFile returns the path of the record of sent reports
+func sentFile() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sentFileName), nil
+}
+
+// loadSent reads when each report was last emailed
+func loadSent() (map[string]time.Time, error) {
+	sent := make(map[string]time.Time)
+	path, err := sentFile()
+	if err != nil {
+		return sent, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sent, nil
+	}
+	if err != nil {
+		return sent, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return make(map[string]time.Time), fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return sent, nil
+}
+
+// recordSent records that a report was emailed at the given time
+func recordSent(name string, when time.Time) error {
+	sent, _ := loadSent()
+	sent[name] = when
+
+	path, err := sentFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(sent, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// pageStyle is shared by the reports and kept to basics that mail clients
+// render
+const pageStyle = `<style>
+body { font-family: sans-serif; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { text-align: left; padding: 4px 12px; border-bottom: 1px solid #ddd; }
+.WARNING { color: #b36b00; }
+.CRITICAL { color: #c00; font-weight: bold; }
+</style>`
+
+var systemTemplate = template.Must(template.New("system").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8">` + pageStyle + `</head><body>
+<h2>System report for {{.Report.SystemInfo.Hostname}}</h2>
+<p>{{.Date}}</p>
+
+<h3>Health</h3>
+<p>{{.Health.Summary}}</p>
+<table>
+<tr><th>Component</th><th>Status</th><th>Value</th><th>Advice</th></tr>
+{{range .Health.Checks}}<tr><td>{{.Component}}</td><td class="{{.Status}}">{{.Status}}</td><td>{{.Value}}</td><td>{{.Advice}}</td></tr>
+{{end}}</table>
+
+<h3>System</h3>
+<table>
+<tr><th>Platform</th><td>{{.Report.SystemInfo.Platform}} ({{.Report.SystemInfo.Architecture}})</td></tr>
+<tr><th>Kernel</th><td>{{.Report.SystemInfo.KernelVersion}}</td></tr>
+<tr><th>CPU</th><td>{{.Report.SystemInfo.CPUModel}}, {{.Report.SystemInfo.CPUCores}} cores</td></tr>
+<tr><th>Memory</th><td>{{.Report.SystemInfo.TotalMemory}}</td></tr>
+<tr><th>Disk</th><td>{{.Report.SystemInfo.TotalDisk}}</td></tr>
+<tr><th>Uptime</th><td>{{.Report.SystemInfo.Uptime}}</td></tr>
+</table>
+
+<h3>Network</h3>
+<table>
+<tr><th>Interface</th><th>IP</th><th>MAC</th><th>Status</th></tr>
+{{range .Report.NetworkInfo.Interfaces}}<tr><td>{{.Name}}</td><td>{{.IPAddress}}</td><td>{{.MACAddress}}</td><td>{{.Status}}</td></tr>
+{{else}}<tr><td colspan="4">No network interfaces found</td></tr>
+{{end}}</table>
+
+<h3>Software</h3>
+<table>
+<tr><th>OS</th><td>{{.Report.SoftwareInfo.OS}}</td></tr>
+<tr><th>Shell</th><td>{{.Report.SoftwareInfo.ShellVersion}}</td></tr>
+{{range $name, $version := .Report.SoftwareInfo.PackageInfo}}<tr><th>{{$name}}</th><td>{{$version}}</td></tr>
+{{end}}</table>
+</body></html>
+`))
+
+var speedTestTemplate = template.Must(template.New("speedtest").Funcs(template.FuncMap{
+	"mbps": func(value float64) string { return fmt.Sprintf("%.1f Mbps", value) },
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8">` + pageStyle + `</head><body>
+<h2>Speed test summary for {{.Hostname}}</h2>
+<p>{{.From}} to {{.To}}</p>
+{{if .Rows}}
+<table>
+<tr><th></th><th>Average</th><th>Lowest</th><th>Highest</th></tr>
+<tr><th>Download</th><td>{{mbps .Summary.AvgDownload}}</td><td>{{mbps .Summary.MinDownload}}</td><td>{{mbps .Summary.MaxDownload}}</td></tr>
+<tr><th>Upload</th><td>{{mbps .Summary.AvgUpload}}</td><td>{{mbps .Summary.MinUpload}}</td><td>{{mbps .Summary.MaxUpload}}</td></tr>
+<tr><th>Latency</th><td>{{printf "%.0f ms" .Summary.AvgLatency}}</td><td></td><td></td></tr>
+</table>
+
+<h3>{{.Summary.Tests}} tests</h3>
+<table>
+<tr><th>Time</th><th>Download</th><th>Upload</th><th>Latency</th><th>Backend</th></tr>
+{{range .Rows}}<tr><td>{{.Time}}</td><td>{{mbps .Result.DownloadSpeed}}</td><td>{{mbps .Result.UploadSpeed}}</td><td>{{.Result.Latency}} ms</td><td>{{.Result.Backend}}</td></tr>
+{{end}}</table>
+{{else}}
+<p>No speed tests ran this week. Schedule them with <code>lumo config:daemon speedtest &lt;hours&gt;</code>.</p>
+{{end}}
+</body></html>
+`))
@@ -0,0 +1,306 @@
+// Package script runs small automation scripts written in Starlark, a
+// Python dialect made for embedding. Scripts reach the system only through
+// lumo's own primitives: asking the AI, running shell commands under the
+// agent's safety settings, the clipboard, and sending files with connect.
+// They cannot read files, open connections, or load other code themselves.
+package script
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/lib/math"
+	"go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/clipboard"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// maxSteps ends scripts stuck in a loop; it is far more computation than
+// any script that automates lumo needs
+const maxSteps = 100_000_000
+
+// defaultConnectPort is the port of a peer given without one, as for "lumo connect"
+const defaultConnectPort = 8080
+
+// fileOptions allow the statements scripts commonly need at the top level,
+// which Starlark's configuration language defaults forbid
+var fileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// Runner runs scripts
+type Runner struct {
+	config    *config.Config
+	exec      *executor.Executor
+	clipboard *clipboard.Clipboard
+	stdout    io.Writer
+	// yes runs destructive shell commands without asking
+	yes bool
+	// confirm asks whether to run a destructive shell command
+	confirm func(command, reason string) bool
+}
+
+// NewRunner creates a runner whose scripts ask questions through exec
+func NewRunner(cfg *config.Config, exec *executor.Executor) *Runner {
+	r := &Runner{
+		config:    cfg,
+		exec:      exec,
+		clipboard: clipboard.NewClipboard(),
+		stdout:    os.Stdout,
+	}
+	r.confirm = r.confirmOnTerminal
+	return r
+}
+
+// SetYes runs destructive shell commands without asking, for scripts run
+// unattended
+func (r *Runner) SetYes(yes bool) {
+	r.yes = yes
+}
+
+// SetOutput sets where print writes
+func (r *Runner) SetOutput(w io.Writer) {
+	r.stdout = w
+}
+
+// SetClipboard sets the clipboard scripts use
+func (r *Runner) SetClipboard(c *clipboard.Clipboard) {
+	r.clipboard = c
+}
+
+// SetConfirm sets how the runner asks whether to run a destructive shell command
+func (r *Runner) SetConfirm(confirm func(command, reason string) bool) {
+	r.confirm = confirm
+}
+
+// Run runs the script at path, which sees args as the tuple args
+func (r *Runner) Run(ctx context.Context, path string, args []string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read script: %w", err)
+	}
+
+	thread := &starlark.Thread{
+		Name: path,
+		Print: func(_ *starlark.Thread, msg string) {
+			fmt.Fprintln(r.stdout, msg)
+		},
+		Load: func(_ *starlark.Thread, module string) (starlark.StringDict, error) {
+			return nil, fmt.Errorf("cannot load %s: scripts cannot load other files", module)
+		},
+	}
+	thread.SetMaxExecutionSteps(maxSteps)
+	stop := context.AfterFunc(ctx, func() { thread.Cancel("interrupted") })
+	defer stop()
+
+	_, err = starlark.ExecFileOptions(fileOptions, thread, path, src, r.predeclared(ctx, args))
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+	return err
+}
+
+// predeclared returns the names scripts can use beyond Starlark's built-ins
+func (r *Runner) predeclared(ctx context.Context, args []string) starlark.StringDict {
+	scriptArgs := make(starlark.Tuple, len(args))
+	for i, arg := range args {
+		scriptArgs[i] = starlark.String(arg)
+	}
+
+	return starlark.StringDict{
+		"args": scriptArgs,
+		"ask":  starlark.NewBuiltin("ask", r.ask),
+		"shell": starlark.NewBuiltin("shell", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			return r.shell(ctx, fn, args, kwargs)
+		}),
+		"clipboard": &starlarkstruct.Module{
+			Name: "clipboard",
+			Members: starlark.StringDict{
+				"get":    starlark.NewBuiltin("clipboard.get", r.clipboardGet),
+				"set":    starlark.NewBuiltin("clipboard.set", r.clipboardSet),
+				"append": starlark.NewBuiltin("clipboard.append", r.clipboardAppend),
+			},
+		},
+		"connect": &starlarkstruct.Module{
+			Name: "connect",
+			Members: starlark.StringDict{
+				"send": starlark.NewBuiltin("connect.send", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+					return r.connectSend(ctx, fn, args, kwargs)
+				}),
+			},
+		},
+		"json": json.Module,
+		"math": math.Module,
+		"time": time.Module,
+	}
+}
+
+// ask implements ask(question, persona=""), which returns the AI's answer
+func (r *Runner) ask(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var question, persona string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "question", &question, "persona?", &persona); err != nil {
+		return nil, err
+	}
+	answer, err := r.exec.Ask(question, persona)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(answer), nil
+}
+
+// shell implements shell(command, check=True), which runs a command with
+// bash and returns a struct with its combined output and exit code. With
+// check, a command that fails stops the script.
+func (r *Runner) shell(ctx context.Context, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var command string
+	check := true
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "command", &command, "check?", &check); err != nil {
+		return nil, err
+	}
+
+	confirm := func(reason string) bool {
+		return r.yes || r.confirm(command, reason)
+	}
+	if err := agent.CommandRefusal(r.config, command, confirm); err != nil {
+		return nil, fmt.Errorf("%q %w", command, err)
+	}
+
+	output, err := exec.CommandContext(ctx, "bash", "-c", command).CombinedOutput()
+	code := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	case err != nil:
+		return nil, err
+	}
+	if check && code != 0 {
+		return nil, fmt.Errorf("%q exited with code %d:\n%s", command, code, strings.TrimRight(string(output), "\n"))
+	}
+
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"output": starlark.String(output),
+		"code":   starlark.MakeInt(code),
+	}), nil
+}
+
+// confirmOnTerminal asks on the terminal whether to run a destructive
+// command; without a terminal, the command is refused
+func (r *Runner) confirmOnTerminal(command, reason string) bool {
+	if !utils.IsTerminal(os.Stdin) {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  The script wants to run: %s\n   %s. Run it? (y/n): ", command, reason)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// clipboardGet implements clipboard.get(), which returns the clipboard text
+func (r *Runner) clipboardGet(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	text, err := r.clipboard.Text()
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(text), nil
+}
+
+// clipboardSet implements clipboard.set(text)
+func (r *Runner) clipboardSet(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "text", &text); err != nil {
+		return nil, err
+	}
+	if _, err := r.clipboard.SetContent(text); err != nil {
+		return nil, err
+	}
+	return starlark.None, nil
+}
+
+// clipboardAppend implements clipboard.append(text), which adds a line to the clipboard
+func (r *Runner) clipboardAppend(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "text", &text); err != nil {
+		return nil, err
+	}
+	if _, err := r.clipboard.AppendContent(text); err != nil {
+		return nil, err
+	}
+	return starlark.None, nil
+}
+
+// connectSend implements connect.send(peer, *paths), which sends files and
+// folders to a peer running "lumo connect --receive"
+func (r *Runner) connectSend(ctx context.Context, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("unexpected keyword argument %s", kwargs[0][0])
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("needs a peer and at least one file")
+	}
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		s, ok := starlark.AsString(arg)
+		if !ok {
+			return nil, fmt.Errorf("argument %d is a %s, not a string", i+1, arg.Type())
+		}
+		strs[i] = s
+	}
+
+	host, port, err := splitPeer(strs[0])
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(strs)-1)
+	for i, path := range strs[1:] {
+		if paths[i], err = utils.ExpandPath(path); err != nil {
+			return nil, err
+		}
+	}
+	manager := connect.NewConnectManager("", port)
+	if err := manager.SendFiles(ctx, host, port, paths); err != nil {
+		return nil, err
+	}
+	return starlark.None, nil
+}
+
+// splitPeer splits "host" or "host:port" into its parts
+func splitPeer(peer string) (string, int, error) {
+	host, portText, err := net.SplitHostPort(peer)
+	if err != nil {
+		// No port given
+		return peer, defaultConnectPort, nil
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port in %s", peer)
+	}
+	return host, port, nil
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// agentConfirmTimeout is how long an agent run waits for the web client to
+// answer a confirmation before treating it as declined
+const agentConfirmTimeout = 5 * time.Minute
+
+// Errors answering a streamed run's confirmation
+var (
+	errNotWaiting = errors.New("the agent run is not waiting for a confirmation")
+	errNeedsToken = errors.New("approve the plan at /api/v1/agent/approve with the token shown on the server's console")
+)
+
+// AgentRunRequest represents a request to run a task with the agent
+type AgentRunRequest struct {
+	Task string `json:"task"`
+}
+
+// AgentConfirmRequest represents the answer to a confirmation an agent run
+// asked for
+type AgentConfirmRequest struct {
+	RunID   string `json:"run_id"`
+	Approve bool   `json:"approve"`
+}
+
+// agentRun streams the events of an agent run to a web client as
+// newline-delimited JSON and waits for its answers to confirmations
+type agentRun struct {
+	id      string
+	task    string
+	mu      sync.Mutex
+	encoder *json.Encoder
+	flusher http.Flusher
+	pending bool
+	// subject is what the pending confirmation is about
+	subject string
+	answers chan bool
+	// steps are those of the latest plan or revision
+	steps []executor.AgentStep
+}
+
+// newAgentRun returns a run of task that streams to w
+func newAgentRun(w http.ResponseWriter, task string) *agentRun {
+	b := make([]byte, 8)
+	rand.Read(b)
+	run := &agentRun{
+		id:      hex.EncodeToString(b),
+		task:    task,
+		encoder: json.NewEncoder(w),
+		answers: make(chan bool, 1),
+	}
+	run.flusher, _ = w.(http.Flusher)
+	return run
+}
+
+// Event writes an event to the stream
+func (r *agentRun) Event(event executor.AgentEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.Type == executor.AgentEventPlan || event.Type == executor.AgentEventRevision {
+		r.steps = event.Steps
+	}
+	r.write(event)
+}
+
+// write encodes an event and flushes it to the client. The caller holds r.mu.
+func (r *agentRun) write(event executor.AgentEvent) {
+	event.RunID = r.id
+	event.Output = utils.StripANSI(event.Output)
+	r.encoder.Encode(event)
+	if r.flusher != nil {
+		r.flusher.Flush()
+	}
+}
+
+// Confirm asks the client for an answer and waits for it to be posted to
+// /api/v1/agent/confirm. Anything other than an approval declines. Plans
+// need the approval token from the console, like those of other remote
+// runs, so the client can only reject them here.
+func (r *agentRun) Confirm(ctx context.Context, event executor.AgentEvent) bool {
+	var approval *agent.Approval
+	if event.Subject == "plan" {
+		var err error
+		if approval, err = requestApproval(r.task, event, r.steps); err != nil {
+			return false
+		}
+		event.Reason = fmt.Sprintf("Approve it within %s with the token shown on the server's console, also listed by 'lumo agent:approvals'.", agent.ApprovalTTL)
+	}
+
+	r.mu.Lock()
+	r.pending = true
+	r.subject = event.Subject
+	r.write(event)
+	r.mu.Unlock()
+
+	// Drop an answer that came too late, so it does not answer the next
+	// confirmation
+	defer func() {
+		r.mu.Lock()
+		r.pending = false
+		select {
+		case <-r.answers:
+		default:
+		}
+		r.mu.Unlock()
+	}()
+
+	if approval != nil {
+		return r.awaitPlanApproval(ctx, approval)
+	}
+
+	timer := time.NewTimer(agentConfirmTimeout)
+	defer timer.Stop()
+	select {
+	case approve := <-r.answers:
+		return approve
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return false
+	}
+}
+
+// awaitPlanApproval waits for the plan's approval, or for the client to
+// reject the plan
+func (r *agentRun) awaitPlanApproval(ctx context.Context, approval *agent.Approval) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	approved := make(chan bool, 1)
+	go func() {
+		approved <- awaitApproval(ctx, approval)
+	}()
+	select {
+	case ok := <-approved:
+		return ok
+	case <-r.answers:
+		cancel()
+		<-approved
+		return false
+	}
+}
+
+// answer delivers the client's answer to the confirmation the run is
+// waiting on
+func (r *agentRun) answer(approve bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.pending {
+		return errNotWaiting
+	}
+	if approve && r.subject == "plan" {
+		return errNeedsToken
+	}
+	r.pending = false
+	r.answers <- approve
+	return nil
+}
+
+// handleAgentRun handles the /api/v1/agent/run endpoint, which runs a task
+// with the agent and streams the plan, the confirmations it needs, and each
+// step's progress as newline-delimited JSON
+func (s *Server) handleAgentRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AgentRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Task == "" {
+		http.Error(w, "Task is required", http.StatusBadRequest)
+		return
+	}
+
+	// The agent keeps the state of a single run, so runs take turns
+	run := newAgentRun(w, req.Task)
+	s.agentMu.Lock()
+	if s.agentRun != nil {
+		s.agentMu.Unlock()
+		http.Error(w, "An agent run is already in progress", http.StatusConflict)
+		return
+	}
+	s.agentRun = run
+	s.agentMu.Unlock()
+	defer func() {
+		s.agentMu.Lock()
+		s.agentRun = nil
+		s.agentMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Lumo-Run-ID", run.id)
+	w.WriteHeader(http.StatusOK)
+	if run.flusher != nil {
+		run.flusher.Flush()
+	}
+
+	result, err := s.executor.RunAgent(executor.WithAgentObserver(r.Context(), run), req.Task)
+	if err != nil {
+		run.Event(executor.AgentEvent{Type: executor.AgentEventError, Error: err.Error()})
+		return
+	}
+	run.Event(executor.AgentEvent{
+		Type:    executor.AgentEventDone,
+		Success: !result.IsError,
+		Output:  result.Output,
+	})
+}
+
+// handleAgentConfirm handles the /api/v1/agent/confirm endpoint, which
+// answers the confirmation a streamed agent run is waiting on
+func (s *Server) handleAgentConfirm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AgentConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	s.agentMu.Lock()
+	run := s.agentRun
+	s.agentMu.Unlock()
+	if run == nil || run.id != req.RunID {
+		http.Error(w, fmt.Sprintf("No agent run %q is in progress", req.RunID), http.StatusNotFound)
+		return
+	}
+	switch err := run.answer(req.Approve); err {
+	case errNotWaiting:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errNeedsToken:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/executor"
+)
+
+// ApproveRequest represents the answer to a remote agent run's approval
+type ApproveRequest struct {
+	Token   string `json:"token"`
+	Approve bool   `json:"approve"`
+}
+
+// remoteRun follows an agent run requested through /api/v1/execute, which
+// has no client to ask. Its confirmations wait for an approval answered on
+// this machine's console or at /api/v1/agent/approve.
+type remoteRun struct {
+	task string
+	// steps are those of the latest plan or revision
+	steps []executor.AgentStep
+}
+
+// Event keeps the steps of the plan and its revisions for approvals
+func (r *remoteRun) Event(event executor.AgentEvent) {
+	if event.Type == executor.AgentEventPlan || event.Type == executor.AgentEventRevision {
+		r.steps = event.Steps
+	}
+}
+
+// Confirm waits for the approval of what event describes
+func (r *remoteRun) Confirm(ctx context.Context, event executor.AgentEvent) bool {
+	approval, err := requestApproval(r.task, event, r.steps)
+	if err != nil {
+		return false
+	}
+	return awaitApproval(ctx, approval)
+}
+
+// requestApproval records an approval for a remote run and shows its token
+// on the console, the only place it is shown
+func requestApproval(task string, event executor.AgentEvent, steps []executor.AgentStep) (*agent.Approval, error) {
+	if event.Step != nil {
+		steps = []executor.AgentStep{*event.Step}
+	}
+	approval, err := agent.RequestApproval(task, event, steps)
+	if err != nil {
+		log.Printf("Error requesting approval for a remote agent run: %v", err)
+		return nil, err
+	}
+
+	log.Printf("A remote agent run needs approval of its %s: %s", approval.Subject, task)
+	for _, step := range steps {
+		log.Printf("  %d. %s", step.ID, step.Command)
+	}
+	log.Printf("Approve it within %s with: lumo agent:approve %s (or reject it with: lumo agent:reject %s)",
+		agent.ApprovalTTL, approval.Token, approval.Token)
+	return approval, nil
+}
+
+// awaitApproval waits for an approval and logs how it was answered
+func awaitApproval(ctx context.Context, approval *agent.Approval) bool {
+	approved := approval.Wait(ctx)
+	if approved {
+		log.Printf("Remote agent run approval %s was approved", approval.Token)
+	} else {
+		log.Printf("Remote agent run approval %s was rejected or expired", approval.Token)
+	}
+	return approved
+}
+
+// handleAgentApprove handles the /api/v1/agent/approve endpoint, which
+// answers a remote agent run's approval with the token from the console
+func (s *Server) handleAgentApprove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ApproveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	_, err := agent.AnswerApproval(req.Token, req.Approve)
+	switch {
+	case errors.Is(err, agent.ErrApprovalNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, agent.ErrApprovalExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/audit"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// maxAuditInput is how much of a command the audit log keeps
+const maxAuditInput = 4096
+
+// defaultAuditLimit is how many entries /api/v1/audit returns by default
+const defaultAuditLimit = 100
+
+// AuditResponse is the response of /api/v1/audit
+type AuditResponse struct {
+	Entries []audit.Entry `json:"entries"`
+}
+
+// auditedCall is an /api/v1/execute call being audited. It records the
+// status the handler answers with and, for refusals, their message.
+type auditedCall struct {
+	http.ResponseWriter
+	server *Server
+	r      *http.Request
+	start  time.Time
+	entry  audit.Entry
+}
+
+// startAudit starts auditing an execute call; finish adds it to the log
+func (s *Server) startAudit(w http.ResponseWriter, r *http.Request) *auditedCall {
+	return &auditedCall{
+		ResponseWriter: w,
+		server:         s,
+		r:              r,
+		start:          time.Now(),
+		entry:          audit.Entry{Type: "unknown"},
+	}
+}
+
+// setCommand records which command the call runs
+func (c *auditedCall) setCommand(cmd *nlp.Command) {
+	c.entry.Type = executor.CommandTypeName(cmd.Type)
+	c.entry.Input = cmd.RawInput
+}
+
+// setResult records whether the command succeeded
+func (c *auditedCall) setResult(result *executor.Result) {
+	c.entry.Success = !result.IsError
+	if result.IsError {
+		c.entry.Error = strings.TrimSpace(result.Output)
+	}
+}
+
+// WriteHeader records the status before sending it
+func (c *auditedCall) WriteHeader(status int) {
+	if c.entry.Status == 0 {
+		c.entry.Status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the message of refusals sent with http.Error
+func (c *auditedCall) Write(b []byte) (int, error) {
+	if c.entry.Status == 0 {
+		c.entry.Status = http.StatusOK
+	}
+	if c.entry.Status >= 400 && c.entry.Error == "" &&
+		strings.HasPrefix(c.Header().Get("Content-Type"), "text/plain") {
+		c.entry.Error = strings.TrimSpace(string(b))
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+// finish adds the call to the audit log
+func (c *auditedCall) finish() {
+	if c.entry.Status == 0 {
+		c.entry.Status = http.StatusOK
+	}
+	c.server.recordAudit(c.r, c.entry, c.start)
+}
+
+// recordAudit adds an execute call that started at start to the audit
+// log. The command has run or been refused by then, so failing to log it
+// is only reported.
+func (s *Server) recordAudit(r *http.Request, entry audit.Entry, start time.Time) {
+	entry.Time = start
+	entry.DurationMS = time.Since(start).Milliseconds()
+	entry.User, _ = getUsernameFromContext(r.Context())
+	entry.Client = clientAddress(r)
+	if len(entry.Input) > maxAuditInput {
+		entry.Input = entry.Input[:maxAuditInput] + "…"
+	}
+	if len(entry.Error) > maxAuditInput {
+		entry.Error = entry.Error[:maxAuditInput] + "…"
+	}
+	if err := audit.Record(entry); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+	}
+}
+
+// handleAudit handles the /api/v1/audit endpoint, which returns the most
+// recent execute calls, oldest first. ?limit= sets how many.
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultAuditLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	entries, err := audit.Tail(limit)
+	if err != nil {
+		http.Error(w, "Failed to read the audit log", http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(AuditResponse{Entries: entries}); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
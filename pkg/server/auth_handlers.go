@@ -51,11 +51,15 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The user was just authenticated, so only a failed read loses the role
+	role, _ := s.authenticator.UserRole(req.Username)
+
 	// Create the response
 	resp := LoginResponse{
 		Token:        token,
 		RefreshToken: refreshToken,
 		Username:     req.Username,
+		Role:         role,
 		ExpiresIn:    s.config.TokenExpirationHours * 3600, // Convert hours to seconds
 	}
 
@@ -101,6 +105,13 @@ func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Users removed since logging in cannot refresh
+	role, err := s.authenticator.UserRole(claims.Username)
+	if err != nil {
+		http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+
 	// Generate new tokens
 	token, err := s.authenticator.GenerateToken(claims.Username)
 	if err != nil {
@@ -119,6 +130,7 @@ func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
 		Token:        token,
 		RefreshToken: refreshToken,
 		Username:     claims.Username,
+		Role:         role,
 		ExpiresIn:    s.config.TokenExpirationHours * 3600, // Convert hours to seconds
 	}
 
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,8 @@ import (
 	"sync"
 
 	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/utils"
 )
 
 // Global chunked transfer manager
@@ -23,29 +26,35 @@ var (
 // getChunkedTransferManager returns the global chunked transfer manager
 func (s *Server) getChunkedTransferManager() *connect.ChunkedTransferManager {
 	chunkedTransferManagerOnce.Do(func() {
-		// Get the download path from the config
-		homeDir, err := os.UserHomeDir()
-		downloadPath := filepath.Join(homeDir, "Downloads")
-		if err != nil {
-			log.Printf("Error getting user home directory: %v", err)
-			downloadPath = "."
-		}
-
-		// Create the chunked transfer manager
-		manager, err := connect.NewChunkedTransferManager(downloadPath, connect.DefaultChunkSize)
+		// Create the chunked transfer manager. Its state lives in the state directory so
+		// interrupted uploads can resume after the server restarts.
+		manager, err := connect.NewPersistentChunkedTransferManager(utils.DefaultDownloadDir(), uploadStateDir(), connect.DefaultChunkSize)
 		if err != nil {
 			log.Printf("Error creating chunked transfer manager: %v", err)
 			return
 		}
 		chunkedTransferManager = manager
+
+		// The manager lives as long as the server process
+		go manager.RunJanitor(context.Background())
 	})
 	return chunkedTransferManager
 }
 
+// uploadStateDir returns the directory that holds partial uploads
+func uploadStateDir() string {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "lumo-uploads")
+	}
+	return filepath.Join(dir, "uploads")
+}
+
 // InitUploadRequest represents a request to initialize a file upload
 type InitUploadRequest struct {
 	Filename string `json:"filename"`
 	FileSize int64  `json:"file_size"`
+	FileHash string `json:"file_hash,omitempty"`
 }
 
 // InitUploadResponse represents a response to initialize a file upload
@@ -105,7 +114,7 @@ func (s *Server) handleInitUpload(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Initialize the upload
-	uploadInfo, err := manager.InitUpload(request.Filename, request.FileSize)
+	uploadInfo, err := manager.InitUpload(request.Filename, request.FileSize, request.FileHash)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to initialize upload: %v", err), http.StatusInternalServerError)
 		return
@@ -237,3 +246,46 @@ func (s *Server) handleCompleteUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// handleUploadStatus handles the /api/v1/connect/upload/status endpoint
+func (s *Server) handleUploadStatus(w http.ResponseWriter, r *http.Request) {
+	// Check if the method is GET
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get the upload ID from the query parameters
+	uploadID := r.URL.Query().Get("upload_id")
+	if uploadID == "" {
+		http.Error(w, "Upload ID is required", http.StatusBadRequest)
+		return
+	}
+
+	// Get the chunked transfer manager
+	manager := s.getChunkedTransferManager()
+	if manager == nil {
+		http.Error(w, "Chunked transfer manager not available", http.StatusInternalServerError)
+		return
+	}
+
+	// Look up the upload, which may have been restored after a restart
+	status, err := manager.UploadStatus(uploadID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Set the content type
+	w.Header().Set("Content-Type", "application/json")
+
+	// Write the response
+	response := connect.UploadStatusResponse{
+		Success:      true,
+		UploadStatus: status,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
@@ -83,7 +83,7 @@ func (s *Server) handleConnectDiscover(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create a connect manager
-	connectManager := connect.NewConnectManager("", 0)
+	connectManager := s.newConnectManager("", 0)
 
 	// Create a context with a timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -139,7 +139,7 @@ func (s *Server) handleConnectStartServer(w http.ResponseWriter, r *http.Request
 	}
 
 	// Create a connect manager
-	connectManager := connect.NewConnectManager(request.Path, request.Port)
+	connectManager := s.newConnectManager(request.Path, request.Port)
 
 	// Create a context with cancel
 	ctx, cancel := context.WithCancel(context.Background())
@@ -234,7 +234,7 @@ func (s *Server) handleConnectToPeer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create a connect manager
-	connectManager := connect.NewConnectManager(request.Path, 0)
+	connectManager := s.newConnectManager(request.Path, 0)
 
 	// Create a context with cancel
 	ctx, cancel := context.WithCancel(context.Background())
@@ -498,3 +498,12 @@ func getLocalIP() (string, error) {
 
 	return "127.0.0.1", nil
 }
+
+// newConnectManager creates a connect manager that discovers peers with the configured transport
+func (s *Server) newConnectManager(downloadPath string, port int) *connect.ConnectManager {
+	connectManager := connect.NewConnectManager(downloadPath, port)
+	if err := connectManager.UseDiscovery(discovery.ConfigOptions(s.config)); err != nil {
+		log.Printf("Warning: %v; falling back to mDNS discovery", err)
+	}
+	return connectManager
+}
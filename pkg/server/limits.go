@@ -0,0 +1,228 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRateBuckets is how many clients the rate limiter tracks before it
+// forgets those that have been idle long enough to have a full bucket
+const maxRateBuckets = 4096
+
+// rateLimiter is a token bucket per client: each request takes a token,
+// and tokens come back at a steady rate up to the burst size
+type rateLimiter struct {
+	mu      sync.Mutex
+	perSec  float64
+	burst   float64
+	buckets map[string]*rateBucket
+	now     func() time.Time
+}
+
+// rateBucket holds one client's tokens as of last
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing perMinute requests a minute
+// in bursts of up to burst
+func newRateLimiter(perMinute, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		perSec:  float64(perMinute) / 60,
+		burst:   float64(burst),
+		buckets: make(map[string]*rateBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the client's bucket. It returns how many
+// tokens are left and, when there were none, how long until there is one.
+func (l *rateLimiter) allow(client string) (int, time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxRateBuckets {
+			l.forgetIdle(now)
+		}
+		b = &rateBucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.perSec)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.perSec * float64(time.Second))
+		return 0, wait, false
+	}
+	b.tokens--
+	return int(b.tokens), 0, true
+}
+
+// wait reports how long until the client's bucket has a token, without
+// taking one
+func (l *rateLimiter) wait(client string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[client]
+	if !ok {
+		return 0, false
+	}
+	tokens := math.Min(l.burst, b.tokens+l.now().Sub(b.last).Seconds()*l.perSec)
+	if tokens >= 1 {
+		return 0, false
+	}
+	return time.Duration((1 - tokens) / l.perSec * float64(time.Second)), true
+}
+
+// forgetIdle drops the buckets that have refilled, which behave the same
+// as new ones
+func (l *rateLimiter) forgetIdle(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.perSec >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// isTransferPath reports whether path moves files for connect, which
+// takes many large requests and has its own size limits
+func isTransferPath(path string) bool {
+	return strings.HasPrefix(path, "/api/v1/connect/")
+}
+
+// isAPIPath reports whether path is an API endpoint rather than a page of
+// the web interface
+func isAPIPath(path string) bool {
+	return path == "/ping" || strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/v1/")
+}
+
+// BodyLimitMiddleware refuses request bodies larger than the configured
+// size with 413 Request Entity Too Large
+func (s *Server) BodyLimitMiddleware(next http.Handler) http.Handler {
+	limit := int64(s.config.ServerMaxBodyKB) * 1024
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limit <= 0 || isTransferPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength > limit {
+			http.Error(w, fmt.Sprintf("Request body is larger than %d KB", s.config.ServerMaxBodyKB), http.StatusRequestEntityTooLarge)
+			return
+		}
+		// Bodies sent without a length fail when reading passes the limit
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RateLimitMiddleware limits how often each client calls the API, so an
+// exposed server cannot be used to run commands or AI requests in a loop.
+// Clients are told their limit in X-RateLimit headers and get 429 Too Many
+// Requests, with a Retry-After, once they pass it.
+func (s *Server) RateLimitMiddleware(next http.Handler) http.Handler {
+	limiter := newRateLimiter(s.config.ServerRateLimit, s.config.ServerRateBurst)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAPIPath(r.URL.Path) || isTransferPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		remaining, wait, ok := limiter.allow(rateLimitClient(r))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.config.ServerRateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, fmt.Sprintf("Rate limit exceeded, try again in %d seconds", seconds), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthFailureLimitMiddleware limits how often each address may fail to
+// authenticate, at the same rate as RateLimitMiddleware. It runs before
+// AuthMiddleware, so once an address has used up its failures, guessing
+// passwords, tokens, or API keys gets 429 Too Many Requests without the
+// credentials being checked.
+func (s *Server) AuthFailureLimitMiddleware(next http.Handler) http.Handler {
+	limiter := newRateLimiter(s.config.ServerRateLimit, s.config.ServerRateBurst)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAPIPath(r.URL.Path) || isTransferPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client := "ip:" + clientAddress(r)
+		if wait, limited := limiter.wait(client); limited {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			http.Error(w, fmt.Sprintf("Too many failed logins, try again in %d seconds", seconds), http.StatusTooManyRequests)
+			return
+		}
+
+		status := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(status, r)
+		if status.status == http.StatusUnauthorized {
+			limiter.allow(client)
+		}
+	})
+}
+
+// statusWriter records the status a handler answers with
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status before sending it
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends buffered data to the client, for streamed responses
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// rateLimitClient returns who a request is counted against: the logged-in
+// user or API key, or else the address it came from
+func rateLimitClient(r *http.Request) string {
+	if username, ok := getUsernameFromContext(r.Context()); ok {
+		return "user:" + username
+	}
+	return "ip:" + clientAddress(r)
+}
+
+// clientAddress returns the address a request came from, without its port
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
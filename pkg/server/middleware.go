@@ -1,12 +1,18 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
 
 	"github.com/agnath18K/lumo/pkg/auth"
+	"github.com/agnath18K/lumo/pkg/nlp"
 )
 
 // contextKey is a custom type for context keys
@@ -30,6 +36,17 @@ func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
 
 		// Get the Authorization header
 		authHeader := r.Header.Get("Authorization")
+
+		// Clients without a login send an API key instead of a token
+		apiKey := r.Header.Get("X-Api-Key")
+		if apiKey == "" && strings.HasPrefix(authHeader, "Bearer "+auth.APIKeyPrefix) {
+			apiKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+		if apiKey != "" {
+			s.serveWithAPIKey(w, r, apiKey, next)
+			return
+		}
+
 		if authHeader == "" {
 			log.Printf("Authorization header required for path: %s", r.URL.Path)
 			http.Error(w, "Authorization header required", http.StatusUnauthorized)
@@ -56,12 +73,180 @@ func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Roles are looked up on every request, so a changed role applies
+		// without logging in again
+		role, err := s.authenticator.UserRole(claims.Username)
+		if err != nil {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
 		// Add the username to the request context
 		ctx := context.WithValue(r.Context(), userContextKey, claims.Username)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		s.serveWithRole(w, r.WithContext(ctx), role, next)
 	})
 }
 
+// serveWithAPIKey serves a request authenticated with an API key, if the
+// key's scopes allow the endpoint and, for /api/v1/execute, the command
+func (s *Server) serveWithAPIKey(w http.ResponseWriter, r *http.Request, apiKey string, next http.Handler) {
+	key, err := s.authenticator.ValidateAPIKey(apiKey)
+	if err != nil {
+		http.Error(w, "Invalid API key", http.StatusUnauthorized)
+		return
+	}
+	scope := requiredScope(r.URL.Path)
+	if !key.Allows(scope) {
+		if scope == "" {
+			http.Error(w, "API keys cannot call this endpoint", http.StatusForbidden)
+		} else {
+			http.Error(w, fmt.Sprintf("API key %q lacks the %q scope", key.Name, scope), http.StatusForbidden)
+		}
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), userContextKey, "apikey:"+key.Name)
+	r = r.WithContext(ctx)
+	cmd, ok := s.executeCommand(w, r)
+	if !ok {
+		return
+	}
+	// Agent runs need the agent scope however they are started
+	if cmd != nil {
+		if scope := commandScope(cmd.Type); scope != "" && !key.Allows(scope) {
+			s.refuseCommand(w, r, cmd, fmt.Sprintf("API key %q lacks the %q scope", key.Name, scope))
+			return
+		}
+	}
+	s.serveWithRole(w, r, auth.EffectiveRole(key.Role), next)
+}
+
+// serveWithRole serves a request if role may call the endpoint and, for
+// /api/v1/execute, run the command in the request
+func (s *Server) serveWithRole(w http.ResponseWriter, r *http.Request, role string, next http.Handler) {
+	permission := endpointPermission(r.URL.Path)
+	cmd, ok := s.executeCommand(w, r)
+	if !ok {
+		return
+	}
+	if cmd != nil {
+		permission = commandPermission(cmd.Type)
+	}
+
+	if !auth.RoleAllows(role, permission) {
+		message := fmt.Sprintf("The %s role lacks the %q permission", role, permission)
+		if cmd != nil {
+			s.refuseCommand(w, r, cmd, message)
+		} else {
+			http.Error(w, message, http.StatusForbidden)
+		}
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// executeCommand returns the command of an /api/v1/execute request, leaving
+// the body for handleExecute. The command is nil for other requests and for
+// bodies that do not parse, which handleExecute refuses. It returns false
+// once it has answered a body that could not be read.
+func (s *Server) executeCommand(w http.ResponseWriter, r *http.Request) (*nlp.Command, bool) {
+	if r.URL.Path != "/api/v1/execute" || r.Method != http.MethodPost {
+		return nil, true
+	}
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("Request body is larger than %d KB", tooLarge.Limit/1024), http.StatusRequestEntityTooLarge)
+		return nil, false
+	} else if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req CommandRequest
+	if json.Unmarshal(body, &req) != nil || req.Command == "" {
+		return nil, true
+	}
+	cmd, err := s.requestCommand(&req)
+	if err != nil {
+		return nil, true
+	}
+	return cmd, true
+}
+
+// refuseCommand answers an execute call with 403 Forbidden, auditing it
+// like the commands that run
+func (s *Server) refuseCommand(w http.ResponseWriter, r *http.Request, cmd *nlp.Command, message string) {
+	call := s.startAudit(w, r)
+	call.setCommand(cmd)
+	defer call.finish()
+	http.Error(call, message, http.StatusForbidden)
+}
+
+// endpointPermission returns the permission an endpoint needs. Commands
+// sent to /api/v1/execute need the permission of their type too.
+func endpointPermission(path string) string {
+	switch {
+	case path == "/api/v1/execute", path == "/api/v1/stats", strings.HasPrefix(path, "/v1/"):
+		return auth.PermRead
+	case path == "/api/v1/auth/change-password":
+		// Everyone may change their own password
+		return auth.PermRead
+	case strings.HasPrefix(path, "/api/v1/agent/"), strings.HasPrefix(path, "/api/v1/connect/"):
+		return auth.PermRun
+	default:
+		return auth.PermAdmin
+	}
+}
+
+// commandPermission returns the permission needed to run commands of the
+// given type: questions and reports only read, settings and the server
+// need an admin, and everything else runs something on this machine
+func commandPermission(cmdType nlp.CommandType) string {
+	switch cmdType {
+	case nlp.CommandTypeAI, nlp.CommandTypeChat, nlp.CommandTypeHelp, nlp.CommandTypeSystemHealth,
+		nlp.CommandTypeSystemReport, nlp.CommandTypeStats, nlp.CommandTypeUsage:
+		return auth.PermRead
+	case nlp.CommandTypeConfig, nlp.CommandTypeServer, nlp.CommandTypeIntegrate:
+		return auth.PermAdmin
+	default:
+		return auth.PermRun
+	}
+}
+
+// commandScope returns the API key scope needed to run commands of the
+// given type through /api/v1/execute, besides the execute scope, or ""
+func commandScope(cmdType nlp.CommandType) string {
+	switch cmdType {
+	case nlp.CommandTypeAgent, nlp.CommandTypeAnalyze:
+		return auth.ScopeAgent
+	default:
+		return ""
+	}
+}
+
+// requiredScope returns the API key scope an endpoint needs, or "" for
+// endpoints API keys cannot call, like changing a password
+func requiredScope(path string) string {
+	switch {
+	case path == "/api/v1/execute":
+		return auth.ScopeExecute
+	case strings.HasPrefix(path, "/api/v1/agent/"):
+		return auth.ScopeAgent
+	case strings.HasPrefix(path, "/v1/"):
+		return auth.ScopeChat
+	case path == "/api/v1/stats":
+		return auth.ScopeStats
+	case strings.HasPrefix(path, "/api/v1/connect/"):
+		return auth.ScopeConnect
+	case path == "/api/v1/audit":
+		return auth.ScopeAudit
+	default:
+		return ""
+	}
+}
+
 // isExemptPath returns true if the path is exempt from authentication
 func isExemptPath(path string) bool {
 	// List of paths that don't require authentication
@@ -75,6 +260,8 @@ func isExemptPath(path string) bool {
 		"/api/v1/connect/upload/init",
 		"/api/v1/connect/upload/chunk",
 		"/api/v1/connect/upload/complete",
+		"/api/v1/connect/upload/status",
+		"/api/v1/connect/signal",
 		"/api/v1/connect/discover",
 		"/api/v1/connect/start-server",
 		"/api/v1/connect/connect-to-peer",
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/privacy"
+)
+
+// ChatCompletionRequest is an OpenAI chat completions request. Only the
+// messages are used; lumo answers with its configured provider and model.
+type ChatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []ChatMessage `json:"messages"`
+	Stream   bool          `json:"stream,omitempty"`
+}
+
+// ChatMessage is one message of an OpenAI conversation
+type ChatMessage struct {
+	Role string `json:"role"`
+	// Content is a string, or a list of parts of which text parts are used
+	Content json.RawMessage `json:"content"`
+}
+
+// ChatCompletionResponse is an OpenAI chat completion
+type ChatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []ChatCompletionChoice `json:"choices"`
+}
+
+// ChatCompletionChoice is one answer of a chat completion. Message is set
+// in complete responses and Delta in streamed chunks.
+type ChatCompletionChoice struct {
+	Index        int                `json:"index"`
+	Message      *ChatMessageOutput `json:"message,omitempty"`
+	Delta        *ChatMessageOutput `json:"delta,omitempty"`
+	FinishReason *string            `json:"finish_reason"`
+}
+
+// ChatMessageOutput is a message in a chat completion
+type ChatMessageOutput struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// openAIError is the error body OpenAI clients understand
+type openAIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// handleChatCompletions handles /v1/chat/completions, which lets tools that
+// speak the OpenAI API use lumo as a gateway. Requests go through the same
+// privacy mode, budget, and cache as ask:, and secrets are redacted from
+// prompts bound for a cloud provider.
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeOpenAIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "Method not allowed")
+		return
+	}
+
+	var req ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", "Invalid request body")
+		return
+	}
+	instructions, prompt, err := conversationPrompt(req.Messages)
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	if !ai.IsLocal(s.config.AIProvider) {
+		var redacted, n int
+		instructions, n = privacy.Redact(instructions)
+		redacted += n
+		prompt, n = privacy.Redact(prompt)
+		redacted += n
+		if redacted > 0 && !s.config.ServerQuietOutput {
+			log.Printf("Redacted %d secret(s) from a chat completion request", redacted)
+		}
+	}
+
+	answer, err := s.executor.AskWithInstructions(prompt, instructions)
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadGateway, "api_error", err.Error())
+		return
+	}
+
+	resp := ChatCompletionResponse{
+		ID:      completionID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   s.executor.Model(),
+	}
+	stop := "stop"
+	if !req.Stream {
+		resp.Choices = []ChatCompletionChoice{{
+			Message:      &ChatMessageOutput{Role: "assistant", Content: answer},
+			FinishReason: &stop,
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("Error encoding chat completion: %v", err)
+		}
+		return
+	}
+
+	// Streaming clients get the whole answer as one chunk, since lumo's
+	// providers answer in one piece
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	resp.Object = "chat.completion.chunk"
+	for _, choice := range []ChatCompletionChoice{
+		{Delta: &ChatMessageOutput{Role: "assistant", Content: answer}},
+		{Delta: &ChatMessageOutput{}, FinishReason: &stop},
+	} {
+		resp.Choices = []ChatCompletionChoice{choice}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			log.Printf("Error encoding chat completion chunk: %v", err)
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// handleModels handles /v1/models, which OpenAI clients call to list the
+// models they can pick; lumo offers its configured one
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeOpenAIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"object": "list",
+		"data": []map[string]interface{}{{
+			"id":       s.executor.Model(),
+			"object":   "model",
+			"created":  0,
+			"owned_by": "lumo",
+		}},
+	})
+}
+
+// conversationPrompt turns OpenAI messages into system instructions and a
+// prompt. A single user message is sent as it is; longer conversations are
+// sent as a transcript, as chat mode does.
+func conversationPrompt(messages []ChatMessage) (string, string, error) {
+	var instructions []string
+	var turns []string
+	var last string
+	for _, msg := range messages {
+		text, err := messageText(msg.Content)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid content in %s message: %w", msg.Role, err)
+		}
+		switch msg.Role {
+		case "system", "developer":
+			instructions = append(instructions, text)
+		case "user", "assistant", "tool":
+			turns = append(turns, fmt.Sprintf("%s: %s", msg.Role, text))
+			last = text
+		default:
+			return "", "", fmt.Errorf("unknown message role: %s", msg.Role)
+		}
+	}
+
+	switch len(turns) {
+	case 0:
+		return "", "", fmt.Errorf("messages must include a user message")
+	case 1:
+		return strings.Join(instructions, "\n\n"), last, nil
+	}
+	return strings.Join(instructions, "\n\n"), strings.Join(turns, "\n\n") + "\n\nassistant:", nil
+}
+
+// messageText returns the text of a message's content, which is either a
+// string or a list of parts
+func messageText(content json.RawMessage) (string, error) {
+	if len(content) == 0 || string(content) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return text, nil
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return "", fmt.Errorf("content must be a string or a list of parts")
+	}
+	var texts []string
+	for _, part := range parts {
+		if part.Type != "text" {
+			return "", fmt.Errorf("%s parts are not supported", part.Type)
+		}
+		texts = append(texts, part.Text)
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// completionID returns a new chat completion ID
+func completionID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "chatcmpl-" + hex.EncodeToString(b)
+}
+
+// writeOpenAIError writes an error in the format OpenAI clients expect
+func writeOpenAIError(w http.ResponseWriter, status int, errType, message string) {
+	var body openAIError
+	body.Error.Message = message
+	body.Error.Type = errType
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -7,14 +7,20 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/agnath18K/lumo/pkg/assets"
 	"github.com/agnath18K/lumo/pkg/auth"
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/discovery"
 	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/metrics"
 	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/usage"
 	"github.com/agnath18K/lumo/pkg/utils"
 	"github.com/agnath18K/lumo/pkg/version"
 )
@@ -26,6 +32,11 @@ type Server struct {
 	server        *http.Server
 	isDaemon      bool
 	authenticator *auth.Authenticator
+	discoverer    discovery.Discoverer
+
+	// agentRun is the agent run streaming to a web client, if any
+	agentMu  sync.Mutex
+	agentRun *agentRun
 }
 
 // CommandRequest represents a request to execute a command
@@ -41,6 +52,9 @@ type CommandResponse struct {
 	Output     string `json:"output"`
 	CommandRun string `json:"command_run"`
 	Error      string `json:"error,omitempty"`
+	// Deprecations are the codes of deprecated syntax the command used,
+	// e.g. LUMO-D001
+	Deprecations []string `json:"deprecations,omitempty"`
 }
 
 // StatusResponse represents the server status response
@@ -50,6 +64,17 @@ type StatusResponse struct {
 	Uptime  string `json:"uptime"`
 }
 
+// StatsResponse represents the local usage metrics shown on the dashboard
+type StatsResponse struct {
+	Enabled bool          `json:"enabled"`
+	Stats   metrics.Stats `json:"stats"`
+	// Network is the traffic of connect and AI requests for each day of
+	// the period, which is counted whether or not metrics are enabled
+	Network []usage.NetworkDay `json:"network"`
+	// NetworkCap is the monthly network cap in bytes, or 0 for none
+	NetworkCap int64 `json:"network_cap"`
+}
+
 // LoginRequest represents a login request
 type LoginRequest struct {
 	Username string `json:"username"`
@@ -61,6 +86,7 @@ type LoginResponse struct {
 	Token        string `json:"token"`
 	RefreshToken string `json:"refresh_token"`
 	Username     string `json:"username"`
+	Role         string `json:"role,omitempty"`
 	ExpiresIn    int    `json:"expires_in"` // Seconds until token expires
 }
 
@@ -78,10 +104,9 @@ type ChangePasswordRequest struct {
 // New creates a new REST server instance
 func New(cfg *config.Config, exec *executor.Executor) *Server {
 	// Create the authenticator
-	homeDir, err := os.UserHomeDir()
-	credentialsDir := filepath.Join(homeDir, ".config", "lumo")
+	credentialsDir, err := paths.ConfigDir()
 	if err != nil {
-		log.Printf("Error getting user home directory: %v", err)
+		log.Printf("Error getting the configuration directory: %v", err)
 		credentialsDir = ".config/lumo"
 	}
 
@@ -101,10 +126,9 @@ func New(cfg *config.Config, exec *executor.Executor) *Server {
 // NewDaemon creates a new REST server instance in daemon mode
 func NewDaemon(cfg *config.Config, exec *executor.Executor) *Server {
 	// Create the authenticator
-	homeDir, err := os.UserHomeDir()
-	credentialsDir := filepath.Join(homeDir, ".config", "lumo")
+	credentialsDir, err := paths.ConfigDir()
 	if err != nil {
-		log.Printf("Error getting user home directory: %v", err)
+		log.Printf("Error getting the configuration directory: %v", err)
 		credentialsDir = ".config/lumo"
 	}
 
@@ -136,7 +160,7 @@ func (s *Server) Start() error {
 		// Create a default user
 		defaultUsername := "admin"
 		defaultPassword := "lumo"
-		if err := s.authenticator.AddUser(defaultUsername, defaultPassword); err != nil {
+		if err := s.authenticator.AddUser(defaultUsername, defaultPassword, auth.RoleAdmin); err != nil {
 			log.Printf("Error creating default user: %v", err)
 		} else {
 			log.Printf("Created default user '%s' with password '%s'", defaultUsername, defaultPassword)
@@ -144,18 +168,122 @@ func (s *Server) Start() error {
 		}
 	}
 
+	// Check if the port is available
+	if !utils.IsPortAvailable(s.config.ServerPort) {
+		// Try to find an available port
+		newPort, err := utils.FindAvailablePort(s.config.ServerPort, 100)
+		if err != nil {
+			return lumoerr.New(lumoerr.ErrPortInUse,
+				fmt.Sprintf("port %d, and no alternative ports are available (%v)", s.config.ServerPort, err)).
+				WithHint("Configure another port with: lumo config:server port <port>")
+		}
+
+		// Log the port change
+		if !s.config.ServerQuietOutput {
+			log.Printf("Port %d is already in use. Using port %d instead.", s.config.ServerPort, newPort)
+			log.Printf("This could be due to another Lumo server instance or a Lumo connect session using this port.")
+			log.Printf("To avoid this in the future, configure a different port with: lumo config:server port <port>")
+			log.Printf("%s", utils.GetPortRangeMessage("server"))
+		}
+
+		// Update the port
+		s.config.ServerPort = newPort
+	}
+
+	// Create the server
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf("0.0.0.0:%d", s.config.ServerPort),
+		Handler: s.Handler(),
+	}
+
+	// Let other lumo instances on the LAN find this one
+	s.advertise()
+
+	// If running in daemon mode, start the server in the main goroutine
+	if s.isDaemon {
+		if !s.config.ServerQuietOutput {
+			log.Printf("Starting Lumo REST server in daemon mode on port %d", s.config.ServerPort)
+		}
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if !s.config.ServerQuietOutput {
+				log.Printf("Error starting server: %v", err)
+				if os.IsPermission(err) {
+					log.Printf("This may be due to insufficient permissions to bind to port %d.", s.config.ServerPort)
+					log.Printf("Try using a port number above 1024 with: lumo config:server port <port>")
+				}
+			}
+			return err
+		}
+		return nil
+	}
+
+	// Otherwise, start the server in a goroutine
+	go func() {
+		if !s.config.ServerQuietOutput {
+			log.Printf("Starting Lumo REST server on port %d", s.config.ServerPort)
+		}
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if !s.config.ServerQuietOutput {
+				log.Printf("Error starting server: %v", err)
+				if os.IsPermission(err) {
+					log.Printf("This may be due to insufficient permissions to bind to port %d.", s.config.ServerPort)
+					log.Printf("Try using a port number above 1024 with: lumo config:server port <port>")
+				}
+			}
+		}
+	}()
+
+	// Add a small delay to allow the server to start
+	time.Sleep(100 * time.Millisecond)
+
+	// Test if the server is running
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ping", s.config.ServerPort))
+	if err != nil {
+		if !s.config.ServerQuietOutput {
+			log.Printf("Warning: Server may not be running correctly: %v", err)
+		}
+	} else {
+		if !s.config.ServerQuietOutput {
+			log.Printf("Server is running and responding to requests")
+		}
+		resp.Body.Close()
+	}
+
+	return nil
+}
+
+// Handler returns the server's routes behind its middleware
+func (s *Server) Handler() http.Handler {
 	// Create a new router
 	mux := http.NewServeMux()
 
-	// Create a middleware chain
+	// Create a middleware chain. Rate limits come after authentication, so
+	// they count requests against the user rather than their address;
+	// failed authentication is limited per address before it.
 	var handler http.Handler = mux
+	if s.config.ServerRateLimit > 0 {
+		handler = s.RateLimitMiddleware(handler)
+	}
 	if s.config.EnableAuth {
-		handler = s.AuthMiddleware(mux)
+		handler = s.AuthMiddleware(handler)
+		if s.config.ServerRateLimit > 0 {
+			handler = s.AuthFailureLimitMiddleware(handler)
+		}
 	}
+	handler = s.BodyLimitMiddleware(handler)
 
 	// Register API routes
 	mux.HandleFunc("/api/v1/execute", s.handleExecute)
 	mux.HandleFunc("/api/v1/status", s.handleStatus)
+	mux.HandleFunc("/api/v1/stats", s.handleStats)
+	mux.HandleFunc("/api/v1/audit", s.handleAudit)
+	mux.HandleFunc("/api/v1/agent/run", s.handleAgentRun)
+	mux.HandleFunc("/api/v1/agent/confirm", s.handleAgentConfirm)
+	mux.HandleFunc("/api/v1/agent/approve", s.handleAgentApprove)
+
+	// Register the OpenAI-compatible API, so tools that speak it can use lumo as a gateway
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("/v1/models", s.handleModels)
 
 	// Register authentication routes
 	mux.HandleFunc("/api/v1/auth/login", s.handleLogin)
@@ -174,6 +302,11 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/api/v1/connect/upload/init", s.handleInitUpload)
 	mux.HandleFunc("/api/v1/connect/upload/chunk", s.handleUploadChunk)
 	mux.HandleFunc("/api/v1/connect/upload/complete", s.handleCompleteUpload)
+	mux.HandleFunc("/api/v1/connect/upload/status", s.handleUploadStatus)
+
+	// Register the WebRTC signaling mailbox, which lets two peers that can
+	// both reach this server swap session descriptions and connect directly
+	mux.Handle("/api/v1/connect/signal", connect.NewSignalStore())
 
 	// Add a simple ping endpoint for testing
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
@@ -219,87 +352,44 @@ func (s *Server) Start() error {
 		}
 	}
 
-	// Check if the port is available
-	if !utils.IsPortAvailable(s.config.ServerPort) {
-		// Try to find an available port
-		newPort, err := utils.FindAvailablePort(s.config.ServerPort, 100)
-		if err != nil {
-			return fmt.Errorf("port %d is already in use and no alternative ports are available: %w", s.config.ServerPort, err)
-		}
-
-		// Log the port change
-		if !s.config.ServerQuietOutput {
-			log.Printf("Port %d is already in use. Using port %d instead.", s.config.ServerPort, newPort)
-			log.Printf("This could be due to another Lumo server instance or a Lumo connect session using this port.")
-			log.Printf("To avoid this in the future, configure a different port with: lumo config:server port <port>")
-			log.Printf("%s", utils.GetPortRangeMessage("server"))
-		}
+	return handler
+}
 
-		// Update the port
-		s.config.ServerPort = newPort
+// advertise announces the server and its capabilities over mDNS
+func (s *Server) advertise() {
+	capabilities := []string{discovery.CapabilityServerAPI}
+	if s.config.FleetManaged {
+		capabilities = append(capabilities, discovery.CapabilityFleetManaged)
 	}
 
-	// Create the server
-	s.server = &http.Server{
-		Addr:    fmt.Sprintf("0.0.0.0:%d", s.config.ServerPort),
-		Handler: handler,
-	}
+	hostname, _ := os.Hostname()
+	info := discovery.AdvertisedInfo(version.Version, capabilities, map[string]string{
+		discovery.InfoHostname: hostname,
+	})
 
-	// If running in daemon mode, start the server in the main goroutine
-	if s.isDaemon {
+	discoverer, err := discovery.New(discovery.ConfigOptions(s.config))
+	if err != nil {
 		if !s.config.ServerQuietOutput {
-			log.Printf("Starting Lumo REST server in daemon mode on port %d", s.config.ServerPort)
+			log.Printf("Warning: %v; falling back to mDNS discovery", err)
 		}
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			if !s.config.ServerQuietOutput {
-				log.Printf("Error starting server: %v", err)
-				if os.IsPermission(err) {
-					log.Printf("This may be due to insufficient permissions to bind to port %d.", s.config.ServerPort)
-					log.Printf("Try using a port number above 1024 with: lumo config:server port <port>")
-				}
-			}
-			return err
-		}
-		return nil
+		discoverer = discovery.NewDiscoverer()
 	}
 
-	// Otherwise, start the server in a goroutine
-	go func() {
-		if !s.config.ServerQuietOutput {
-			log.Printf("Starting Lumo REST server on port %d", s.config.ServerPort)
-		}
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			if !s.config.ServerQuietOutput {
-				log.Printf("Error starting server: %v", err)
-				if os.IsPermission(err) {
-					log.Printf("This may be due to insufficient permissions to bind to port %d.", s.config.ServerPort)
-					log.Printf("Try using a port number above 1024 with: lumo config:server port <port>")
-				}
-			}
-		}
-	}()
-
-	// Add a small delay to allow the server to start
-	time.Sleep(100 * time.Millisecond)
-
-	// Test if the server is running
-	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/ping", s.config.ServerPort))
-	if err != nil {
+	s.discoverer = discoverer
+	if err := s.discoverer.Advertise(context.Background(), discovery.InstanceServiceName, "Lumo", s.config.ServerPort, info); err != nil {
 		if !s.config.ServerQuietOutput {
-			log.Printf("Warning: Server may not be running correctly: %v", err)
+			log.Printf("Warning: Failed to advertise server: %v", err)
 		}
-	} else {
-		if !s.config.ServerQuietOutput {
-			log.Printf("Server is running and responding to requests")
-		}
-		resp.Body.Close()
+		s.discoverer = nil
 	}
-
-	return nil
 }
 
 // Stop stops the REST server
 func (s *Server) Stop() error {
+	if s.discoverer != nil {
+		s.discoverer.StopAdvertising()
+		s.discoverer = nil
+	}
 	if s.server != nil {
 		// Create a context with a timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -318,6 +408,11 @@ func (s *Server) GetConfig() *config.Config {
 
 // handleExecute handles the /api/v1/execute endpoint
 func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
+	// Every call is added to the audit log, however it ends
+	call := s.startAudit(w, r)
+	defer call.finish()
+	w = call
+
 	// Only allow POST requests
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -338,35 +433,30 @@ func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create a command based on the request
-	var cmd *nlp.Command
-
-	// If type is specified, use it
-	if req.Type != "" {
-		cmdType := mapStringToCommandType(req.Type)
-		cmd = &nlp.Command{
-			Type:       cmdType,
-			Intent:     req.Command,
-			Parameters: req.Params,
-			RawInput:   req.Command,
-		}
+	cmd, err := s.requestCommand(&req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing command: %v", err), http.StatusBadRequest)
+		return
+	}
+	call.setCommand(cmd)
+
+	// Execute the command. Agent runs requested remotely never run
+	// silently: they wait for approval on this machine's console or at
+	// /api/v1/agent/approve.
+	var result *executor.Result
+	if cmd.Type == nlp.CommandTypeAgent {
+		observer := &remoteRun{task: cmd.Intent}
+		result, err = s.executor.RunAgent(executor.WithAgentObserver(r.Context(), observer), cmd.Intent)
 	} else {
-		// Otherwise, parse the command
-		parser := nlp.NewParser(s.config)
-		var err error
-		cmd, err = parser.Parse(req.Command)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Error parsing command: %v", err), http.StatusBadRequest)
-			return
-		}
+		result, err = s.executor.Execute(cmd)
 	}
-
-	// Execute the command
-	result, err := s.executor.Execute(cmd)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error executing command: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	call.setResult(result)
+
 	// Create the response
 	resp := CommandResponse{
 		Success:    !result.IsError,
@@ -377,6 +467,9 @@ func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
 	if result.IsError {
 		resp.Error = result.Output
 	}
+	if code := cmd.Parameters["deprecated"]; code != "" {
+		resp.Deprecations = []string{code}
+	}
 
 	// Set the content type
 	w.Header().Set("Content-Type", "application/json")
@@ -420,6 +513,52 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleStats handles the /api/v1/stats endpoint, which adds up the local
+// usage metrics and network traffic of the last 30 days for the dashboard
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	events, err := metrics.Load(time.Now().Add(-metrics.Period))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error loading metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+	traffic, err := usage.LoadNetwork(time.Now().Add(-metrics.Period))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error loading network usage: %v", err), http.StatusInternalServerError)
+		return
+	}
+	resp := StatsResponse{
+		Enabled:    s.config.EnableMetrics,
+		Stats:      metrics.Summarize(events),
+		Network:    usage.SummarizeNetwork(traffic),
+		NetworkCap: s.config.NetworkCapBytes(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("Error encoding response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// requestCommand returns the command an execute request runs: one of the
+// given type, or the command parsed like on the command line
+func (s *Server) requestCommand(req *CommandRequest) (*nlp.Command, error) {
+	if req.Type != "" {
+		return &nlp.Command{
+			Type:       mapStringToCommandType(req.Type),
+			Intent:     req.Command,
+			Parameters: req.Params,
+			RawInput:   req.Command,
+		}, nil
+	}
+	return nlp.NewParser(s.config).Parse(req.Command)
+}
+
 // mapStringToCommandType maps a string to a CommandType
 func mapStringToCommandType(cmdType string) nlp.CommandType {
 	switch cmdType {
@@ -451,6 +590,40 @@ func mapStringToCommandType(cmdType string) nlp.CommandType {
 		return nlp.CommandTypeCreate
 	case "desktop":
 		return nlp.CommandTypeDesktop
+	case "doctor":
+		return nlp.CommandTypeDoctor
+	case "integrate":
+		return nlp.CommandTypeIntegrate
+	case "last":
+		return nlp.CommandTypeLast
+	case "discover":
+		return nlp.CommandTypeDiscover
+	case "save":
+		return nlp.CommandTypeSave
+	case "notes":
+		return nlp.CommandTypeNotes
+	case "usage":
+		return nlp.CommandTypeUsage
+	case "stats":
+		return nlp.CommandTypeStats
+	case "trigger":
+		return nlp.CommandTypeTrigger
+	case "today":
+		return nlp.CommandTypeToday
+	case "palette":
+		return nlp.CommandTypePalette
+	case "snip":
+		return nlp.CommandTypeSnip
+	case "remind":
+		return nlp.CommandTypeRemind
+	case "agenda":
+		return nlp.CommandTypeAgenda
+	case "send":
+		return nlp.CommandTypeSend
+	case "contacts":
+		return nlp.CommandTypeContacts
+	case "analyze":
+		return nlp.CommandTypeAnalyze
 	default:
 		return nlp.CommandTypeAI
 	}
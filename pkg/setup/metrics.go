@@ -0,0 +1,45 @@
+package setup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/metrics"
+)
+
+// AskMetricsConsent asks once whether to collect local usage metrics and
+// saves the answer. Anything but yes, including no answer, leaves them off.
+func AskMetricsConsent(cfg *config.Config, in io.Reader, out io.Writer) error {
+	path, err := metrics.Path()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n📈 Lumo can keep usage stats for you, and only for you.")
+	fmt.Fprintln(out, "It would count which kinds of command you run and how long they take,")
+	fmt.Fprintln(out, "for 'lumo stats' and the web dashboard. Nothing is ever sent anywhere:")
+	fmt.Fprintf(out, "the counts stay in %s and hold no command text.\n", path)
+	fmt.Fprintln(out, "Change your mind any time with 'config:metrics disable' or delete")
+	fmt.Fprintln(out, "everything with 'config:metrics purge'.")
+	fmt.Fprint(out, "\nKeep local usage stats? [y/N]: ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	cfg.EnableMetrics = answer == "y" || answer == "yes"
+	cfg.MetricsAsked = true
+
+	if cfg.EnableMetrics {
+		fmt.Fprintln(out, "✅ Local stats are on. See them with 'lumo stats'.")
+	} else {
+		fmt.Fprintln(out, "👍 Nothing will be collected.")
+	}
+	fmt.Fprintln(out)
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("error saving configuration: %w", err)
+	}
+	return nil
+}
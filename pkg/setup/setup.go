@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/paths"
 )
 
 // APIKeySetup handles interactive setup of API keys
@@ -71,7 +72,9 @@ func (s *APIKeySetup) CheckAndSetupAPIKeys() (bool, error) {
 	}
 
 	fmt.Println("\n✅ Great! Your API key has been saved. Let's get started with your query!")
-	fmt.Println("(You can always update your API keys by editing ~/.config/lumo/config.json)")
+	if configPath, err := paths.ConfigFile(); err == nil {
+		fmt.Printf("(You can always update your API keys by editing %s)\n", configPath)
+	}
 
 	return true, nil
 }
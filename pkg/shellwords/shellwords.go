@@ -0,0 +1,428 @@
+// Package shellwords splits shell command lines into the simple commands
+// they run, for the checks that decide whether lumo may run them. It is not
+// a full shell parser: it follows quotes, pipelines, lists, substitutions,
+// and redirections well enough to find every program a command line starts.
+package shellwords
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Command is one simple command of a command line
+type Command struct {
+	// Words are the command's words with quotes removed
+	Words []string
+	// Piped is true when the command reads the previous command's output
+	Piped bool
+}
+
+// keywords start or end compound commands; the command they introduce, if
+// any, follows them
+var keywords = wordSet("if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done",
+	"case", "esac", "in", "time", "!", "{", "}")
+
+// wordSet builds a lookup table from a list of words
+func wordSet(words ...string) map[string]bool {
+	table := make(map[string]bool, len(words))
+	for _, word := range words {
+		table[word] = true
+	}
+	return table
+}
+
+// Split returns the words of each simple command of a command line
+func Split(command string) [][]string {
+	var commands [][]string
+	for _, cmd := range Parse(command) {
+		commands = append(commands, cmd.Words)
+	}
+	return commands
+}
+
+// Parse splits a command line into its simple commands, each as a list of
+// words with quotes removed. The commands of a $(...) substitution are listed
+// on their own, and the substitution stays in the outer command as the word
+// "$(...)". Redirections glued to a word, as in "echo hi>out", are split off
+// into words of their own.
+func Parse(command string) []Command {
+	var commands []Command
+	var words []string
+	piped := false
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	// outer holds the commands a $(...) or (...) interrupted; nil entries
+	// are plain subshells
+	type outerCommand struct {
+		words []string
+		word  string
+		piped bool
+	}
+	var outer []*outerCommand
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+		}
+		word.Reset()
+		inWord = false
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			commands = append(commands, Command{Words: words, Piped: piped})
+		}
+		words = nil
+		piped = false
+	}
+	// startRedirect ends the word before a redirection operator, unless it
+	// is the descriptor being redirected, as in 2>&1
+	startRedirect := func() {
+		if text := word.String(); text != "" && strings.Trim(text, "0123456789") != "" &&
+			!strings.HasSuffix(text, ">") && !strings.HasSuffix(text, "<") && !strings.HasSuffix(text, "&") {
+			endWord()
+		}
+	}
+
+	runes := []rune(stripHeredocs(command))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '$' && i+2 < len(runes) && runes[i+1] == '(' && runes[i+2] == '(':
+			// Arithmetic runs no command; keep it as part of the word
+			depth, end := 0, len(runes)-1
+			for j := i + 1; j < len(runes); j++ {
+				if runes[j] == '(' {
+					depth++
+				} else if runes[j] == ')' {
+					if depth--; depth == 0 {
+						end = j
+						break
+					}
+				}
+			}
+			word.WriteString(string(runes[i : end+1]))
+			inWord = true
+			i = end
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '(':
+			outer = append(outer, &outerCommand{words: words, word: word.String(), piped: piped})
+			words = nil
+			piped = false
+			word.Reset()
+			inWord = false
+			i++
+		case r == '(':
+			outer = append(outer, nil)
+			endCommand()
+		case r == ')':
+			endCommand()
+			if len(outer) > 0 {
+				interrupted := outer[len(outer)-1]
+				outer = outer[:len(outer)-1]
+				if interrupted != nil {
+					words, piped = interrupted.words, interrupted.piped
+					word.WriteString(interrupted.word + "$(...)")
+					inWord = true
+				}
+			}
+		case r == '&' && (strings.HasSuffix(word.String(), ">") || (i+1 < len(runes) && runes[i+1] == '>')):
+			// Part of a redirection such as 2>&1 or &>
+			startRedirect()
+			word.WriteRune(r)
+			inWord = true
+		case r == '>' || r == '<':
+			startRedirect()
+			word.WriteRune(r)
+			inWord = true
+		case r == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			endCommand()
+			i++
+		case r == '|':
+			endCommand()
+			piped = true
+		case r == ';' || r == '&' || r == '\n':
+			endCommand()
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	endCommand()
+
+	return commands
+}
+
+// stripHeredocs removes the bodies of here-documents, which are data rather
+// than commands
+func stripHeredocs(command string) string {
+	var kept []string
+	delimiter := ""
+	for _, line := range strings.Split(command, "\n") {
+		if delimiter != "" {
+			if strings.TrimSpace(line) == delimiter {
+				delimiter = ""
+			}
+			continue
+		}
+		kept = append(kept, line)
+		if _, rest, found := strings.Cut(line, "<<"); found && !strings.HasPrefix(rest, "<") {
+			if words := strings.Fields(strings.TrimPrefix(rest, "-")); len(words) > 0 {
+				delimiter = strings.Trim(words[0], `'"`)
+			}
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// IsAssignment reports whether a word is a shell variable assignment
+func IsAssignment(field string) bool {
+	name, _, found := strings.Cut(field, "=")
+	return found && name != "" && !strings.HasPrefix(name, "-")
+}
+
+// StripKeywords skips keywords such as "do" and "then" to reach the command
+// they run. The words of for and case headers are not commands at all.
+func StripKeywords(words []string) []string {
+	for len(words) > 0 && keywords[words[0]] {
+		if words[0] == "for" || words[0] == "case" {
+			return nil
+		}
+		words = words[1:]
+	}
+	return words
+}
+
+// shellTools run the script passed to -c
+var shellTools = wordSet("sh", "bash", "zsh", "dash", "ksh")
+
+// commandWrappers run the command that follows their options, with the
+// short options that take a value
+var commandWrappers = map[string]string{
+	"sudo":    "ugpChDrtUT",
+	"env":     "uCS",
+	"nice":    "n",
+	"nohup":   "",
+	"command": "",
+	"exec":    "a",
+	"time":    "fo",
+	"timeout": "sk",
+	"xargs":   "adEILnPs",
+	"stdbuf":  "ioe",
+}
+
+// wrapperLongOptions are the long options of commandWrappers that take a
+// value when it is not given with =
+var wrapperLongOptions = wordSet("--user", "--group", "--unset", "--chdir", "--split-string",
+	"--adjustment", "--signal", "--kill-after", "--arg-file", "--delimiter", "--eof", "--replace",
+	"--max-lines", "--max-args", "--max-procs", "--max-chars", "--input", "--output", "--error",
+	"--prompt", "--host", "--role", "--type", "--close-from", "--other-user", "--format")
+
+// maxShellDepth bounds how deeply nested sh -c scripts are unwrapped
+const maxShellDepth = 4
+
+// Unwrap returns the simple commands a command line runs, as checks on what
+// it does should see them: keywords and wrappers such as nice, xargs, and
+// env are skipped to reach the program, and the scripts passed to sh -c are
+// split into their own commands as well
+func Unwrap(command string) []Command {
+	return unwrap(command, 0)
+}
+
+// unwrap implements Unwrap for a script depth levels deep
+func unwrap(command string, depth int) []Command {
+	var commands []Command
+	for _, cmd := range Parse(command) {
+		words, script := skipWrappers(StripKeywords(cmd.Words))
+		if script != "" && depth < maxShellDepth {
+			// env -S splits its string into the command it runs
+			commands = append(commands, unwrap(script, depth+1)...)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		commands = append(commands, Command{Words: words, Piped: cmd.Piped})
+		if script := shellScript(words); script != "" && depth < maxShellDepth {
+			commands = append(commands, unwrap(script, depth+1)...)
+		}
+	}
+	return commands
+}
+
+// skipWrappers drops variable assignments and wrappers such as sudo, nice,
+// and xargs, with their options, from the front of a simple command. For
+// env -S it returns the command line to split instead.
+func skipWrappers(words []string) ([]string, string) {
+	for len(words) > 0 {
+		if IsAssignment(words[0]) {
+			words = words[1:]
+			continue
+		}
+		tool := filepath.Base(words[0])
+		valueOptions, ok := commandWrappers[tool]
+		if !ok {
+			return words, ""
+		}
+		words = words[1:]
+		for len(words) > 0 {
+			word := words[0]
+			if word == "--" {
+				words = words[1:]
+				break
+			}
+			if tool == "env" && IsAssignment(word) {
+				words = words[1:]
+				continue
+			}
+			if !strings.HasPrefix(word, "-") || word == "-" {
+				break
+			}
+			if tool == "command" && strings.ContainsAny(word, "vV") {
+				// command -v only says what would run
+				return nil, ""
+			}
+			words = words[1:]
+
+			if strings.HasPrefix(word, "--") {
+				name, value, found := strings.Cut(word, "=")
+				if !found && wrapperLongOptions[name] && len(words) > 0 {
+					value, found = words[0], true
+					words = words[1:]
+				}
+				if tool == "env" && name == "--split-string" && found {
+					return nil, strings.Join(append([]string{value}, words...), " ")
+				}
+				continue
+			}
+			for i, r := range word[1:] {
+				if !strings.ContainsRune(valueOptions, r) {
+					continue
+				}
+				value := word[i+2:]
+				if value == "" && len(words) > 0 {
+					value = words[0]
+					words = words[1:]
+				}
+				if tool == "env" && r == 'S' {
+					return nil, strings.Join(append([]string{value}, words...), " ")
+				}
+				break
+			}
+		}
+		// timeout's first operand is the duration
+		if tool == "timeout" && len(words) > 0 {
+			words = words[1:]
+		}
+	}
+	return words, ""
+}
+
+// shellScript returns the script a shell runs with -c, or "" if the
+// command is not one. A shell named by a variable, as in $SHELL -c, counts.
+func shellScript(words []string) string {
+	if !shellTools[filepath.Base(words[0])] && !IsVariable(words[0]) {
+		return ""
+	}
+	withScript := false
+	for i := 1; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case word == "-o" || word == "+o" || word == "-O" || word == "+O":
+			i++
+		case strings.HasPrefix(word, "-") || strings.HasPrefix(word, "+"):
+			if !strings.HasPrefix(word, "--") && strings.Contains(word, "c") {
+				withScript = true
+			}
+		case withScript:
+			return word
+		default:
+			// A script file rather than a string
+			return ""
+		}
+	}
+	return ""
+}
+
+// IsVariable reports whether a command word is named by a variable, as in
+// $SHELL or "${EDITOR}"
+func IsVariable(word string) bool {
+	return strings.Contains(word, "$")
+}
+
+// Fields splits a single command into its words, removing quotes and
+// backslash escapes, for running it without a shell. Operators such as |
+// and > are ordinary words here.
+func Fields(command string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				end = len(command) - i - 1
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			for i++; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(command[i])
+			}
+			inWord = true
+		case c == '\\' && i+1 < len(command):
+			i++
+			word.WriteByte(command[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// Quote returns word quoted for a shell, or as it is when it needs no quotes
+func Quote(word string) string {
+	if word == "" {
+		return "''"
+	}
+	if strings.IndexFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("@%+=:,./_-", r)
+	}) < 0 {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
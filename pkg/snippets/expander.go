@@ -0,0 +1,71 @@
+package snippets
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxTyped bounds how much of what was typed the expander remembers
+const maxTyped = 64
+
+// Expansion replaces an abbreviation that was just typed with a snippet
+type Expansion struct {
+	Name string
+	Text string
+	// Erase is how many characters to delete before pasting the text: the
+	// abbreviation and the space or tab that ended it
+	Erase int
+}
+
+// Expander watches what is typed for an abbreviation, the prefix and a
+// snippet's name, followed by a space or a tab. It only remembers the word
+// being typed, never what came before it.
+type Expander struct {
+	prefix string
+	lookup func(name string) (string, bool)
+	word   []rune
+}
+
+// NewExpander creates an expander; lookup returns the text of the snippet
+// with a name, and is called when a word that starts with prefix ends
+func NewExpander(prefix string, lookup func(name string) (string, bool)) *Expander {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return &Expander{prefix: prefix, lookup: lookup}
+}
+
+// Type takes a character that was typed: a printable character, '\b' for
+// backspace, or anything else, such as 0 for a cursor key, to forget the
+// word being typed. It reports the expansion to make when an abbreviation
+// ends.
+func (x *Expander) Type(r rune) (Expansion, bool) {
+	switch {
+	case r == ' ' || r == '\t':
+		word := string(x.word)
+		x.Reset()
+		name, ok := strings.CutPrefix(word, x.prefix)
+		if !ok || name == "" {
+			return Expansion{}, false
+		}
+		text, ok := x.lookup(name)
+		if !ok {
+			return Expansion{}, false
+		}
+		return Expansion{Name: name, Text: text, Erase: len([]rune(word)) + 1}, true
+	case r == '\b':
+		if len(x.word) > 0 {
+			x.word = x.word[:len(x.word)-1]
+		}
+	case unicode.IsPrint(r) && len(x.word) < maxTyped:
+		x.word = append(x.word, r)
+	default:
+		x.Reset()
+	}
+	return Expansion{}, false
+}
+
+// Reset forgets the word being typed, as when the cursor moves
+func (x *Expander) Reset() {
+	x.word = x.word[:0]
+}
@@ -0,0 +1,219 @@
+//go:build linux
+
+package snippets
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Linux input event types and key event values, from linux/input.h
+const (
+	evKey      = 1
+	evRep      = 20
+	keyRelease = 0
+)
+
+// eventSize is the size of a struct input_event, which starts with a
+// struct timeval
+var eventSize = int(unsafe.Sizeof(syscall.Timeval{})) + 8
+
+// Key codes of modifiers, from linux/input-event-codes.h
+const (
+	keyLeftCtrl   = 29
+	keyLeftShift  = 42
+	keyRightShift = 54
+	keyLeftAlt    = 56
+	keyCapsLock   = 58
+	keyRightCtrl  = 97
+	keyRightAlt   = 100
+	keyLeftMeta   = 125
+	keyRightMeta  = 126
+)
+
+// usLayout maps key codes to what they type on a US keyboard, without and
+// with shift
+var usLayout = map[uint16][2]rune{
+	2: {'1', '!'}, 3: {'2', '@'}, 4: {'3', '#'}, 5: {'4', '$'}, 6: {'5', '%'},
+	7: {'6', '^'}, 8: {'7', '&'}, 9: {'8', '*'}, 10: {'9', '('}, 11: {'0', ')'},
+	12: {'-', '_'}, 13: {'=', '+'}, 14: {'\b', '\b'}, 15: {'\t', '\t'},
+	16: {'q', 'Q'}, 17: {'w', 'W'}, 18: {'e', 'E'}, 19: {'r', 'R'}, 20: {'t', 'T'},
+	21: {'y', 'Y'}, 22: {'u', 'U'}, 23: {'i', 'I'}, 24: {'o', 'O'}, 25: {'p', 'P'},
+	26: {'[', '{'}, 27: {']', '}'}, 28: {'\n', '\n'},
+	30: {'a', 'A'}, 31: {'s', 'S'}, 32: {'d', 'D'}, 33: {'f', 'F'}, 34: {'g', 'G'},
+	35: {'h', 'H'}, 36: {'j', 'J'}, 37: {'k', 'K'}, 38: {'l', 'L'},
+	39: {';', ':'}, 40: {'\'', '"'}, 41: {'`', '~'}, 43: {'\\', '|'},
+	44: {'z', 'Z'}, 45: {'x', 'X'}, 46: {'c', 'C'}, 47: {'v', 'V'}, 48: {'b', 'B'},
+	49: {'n', 'N'}, 50: {'m', 'M'}, 51: {',', '<'}, 52: {'.', '>'}, 53: {'/', '?'},
+	57: {' ', ' '},
+}
+
+// keyEvent is a key press, repeat, or release
+type keyEvent struct {
+	code  uint16
+	value int32
+}
+
+// ListenKeyboard reads key presses from every keyboard until the context
+// is cancelled, and calls typed with what each one types, as Expander.Type
+// takes it. Keys are read as on a US layout. Reading keyboards needs access
+// to /dev/input, which members of the input group have.
+func ListenKeyboard(ctx context.Context, typed func(rune)) error {
+	devices, err := keyboards()
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("no keyboards found")
+	}
+
+	events := make(chan keyEvent, 64)
+	opened := 0
+	for _, device := range devices {
+		file, err := os.Open(device)
+		if err != nil {
+			if os.IsPermission(err) {
+				return fmt.Errorf("cannot read %s: add yourself to the input group with 'sudo usermod -aG input $USER' and log in again", device)
+			}
+			continue
+		}
+		opened++
+		go func() {
+			<-ctx.Done()
+			file.Close()
+		}()
+		go readKeys(ctx, file, events)
+	}
+	if opened == 0 {
+		return fmt.Errorf("no keyboards could be opened")
+	}
+
+	var shift, capsLock bool
+	modifiers := make(map[uint16]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-events:
+			switch event.code {
+			case keyLeftShift, keyRightShift:
+				shift = event.value != keyRelease
+				continue
+			case keyLeftCtrl, keyRightCtrl, keyLeftAlt, keyRightAlt, keyLeftMeta, keyRightMeta:
+				modifiers[event.code] = event.value != keyRelease
+				continue
+			case keyCapsLock:
+				if event.value == 1 {
+					capsLock = !capsLock
+				}
+				continue
+			}
+			if event.value == keyRelease {
+				continue
+			}
+
+			// Shortcuts and keys that type nothing, such as the cursor
+			// keys, end the word being typed
+			r := rune(0)
+			if chars, ok := usLayout[event.code]; ok && !anyHeld(modifiers) {
+				r = chars[0]
+				upper := shift
+				if capsLock && chars[0] >= 'a' && chars[0] <= 'z' {
+					upper = !upper
+				}
+				if upper {
+					r = chars[1]
+				}
+			}
+			typed(r)
+		}
+	}
+}
+
+// anyHeld reports whether a modifier key is held down
+func anyHeld(modifiers map[uint16]bool) bool {
+	for _, held := range modifiers {
+		if held {
+			return true
+		}
+	}
+	return false
+}
+
+// readKeys sends the key events of a device until it is closed
+func readKeys(ctx context.Context, file *os.File, events chan<- keyEvent) {
+	buf := make([]byte, eventSize)
+	offset := eventSize - 8
+	for {
+		if _, err := io.ReadFull(file, buf); err != nil {
+			return
+		}
+		if binary.NativeEndian.Uint16(buf[offset:]) != evKey {
+			continue
+		}
+		event := keyEvent{
+			code:  binary.NativeEndian.Uint16(buf[offset+2:]),
+			value: int32(binary.NativeEndian.Uint32(buf[offset+4:])),
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// keyboards returns the event devices of the keyboards listed in
+// /proc/bus/input/devices: devices with a kbd handler that repeat keys,
+// which leaves out power buttons and the like
+func keyboards() ([]string, error) {
+	file, err := os.Open("/proc/bus/input/devices")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list input devices: %w", err)
+	}
+	defer file.Close()
+
+	var devices []string
+	var handlers []string
+	var events uint64
+	flush := func() {
+		isKeyboard := false
+		event := ""
+		for _, handler := range handlers {
+			if handler == "kbd" {
+				isKeyboard = true
+			}
+			if strings.HasPrefix(handler, "event") {
+				event = handler
+			}
+		}
+		if isKeyboard && event != "" && events&(1<<evKey) != 0 && events&(1<<evRep) != 0 {
+			devices = append(devices, filepath.Join("/dev/input", event))
+		}
+		handlers, events = nil, 0
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "H: Handlers="):
+			handlers = strings.Fields(strings.TrimPrefix(line, "H: Handlers="))
+		case strings.HasPrefix(line, "B: EV="):
+			events, _ = strconv.ParseUint(strings.TrimPrefix(line, "B: EV="), 16, 64)
+		}
+	}
+	flush()
+	return devices, scanner.Err()
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package snippets
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListenKeyboard is only available on Linux, where keyboards can be read
+// from /dev/input
+func ListenKeyboard(ctx context.Context, typed func(rune)) error {
+	return fmt.Errorf("snippet expansion is only supported on Linux")
+}
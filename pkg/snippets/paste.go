@@ -0,0 +1,100 @@
+package snippets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// restoreDelay is how long the snippet stays on the clipboard, for the
+// application to paste it, before what was there is put back
+const restoreDelay = 500 * time.Millisecond
+
+// Clipboard reads and writes the desktop clipboard, as the desktop backend
+// does
+type Clipboard interface {
+	GetClipboardText(ctx context.Context) (string, error)
+	SetClipboardText(ctx context.Context, text string) error
+}
+
+// keyTool presses keys for the focused application
+type keyTool struct {
+	name string
+	// args returns the arguments that press backspace erase times and then
+	// ctrl+v
+	args func(erase int) []string
+}
+
+// keyTools press keys on X11 (xdotool), on wlroots compositors (wtype), and
+// anywhere the ydotool daemon runs
+var keyTools = map[string]keyTool{
+	"xdotool": {name: "xdotool", args: func(erase int) []string {
+		args := []string{"key", "--clearmodifiers"}
+		for i := 0; i < erase; i++ {
+			args = append(args, "BackSpace")
+		}
+		return append(args, "ctrl+v")
+	}},
+	"wtype": {name: "wtype", args: func(erase int) []string {
+		var args []string
+		for i := 0; i < erase; i++ {
+			args = append(args, "-k", "BackSpace")
+		}
+		return append(args, "-M", "ctrl", "-k", "v", "-m", "ctrl")
+	}},
+	"ydotool": {name: "ydotool", args: func(erase int) []string {
+		// Linux key codes: 14 is backspace, 29 left ctrl, and 47 v
+		args := []string{"key"}
+		for i := 0; i < erase; i++ {
+			args = append(args, "14:1", "14:0")
+		}
+		return append(args, "29:1", "47:1", "47:0", "29:0")
+	}},
+}
+
+// Paster makes expansions: it erases the abbreviation and pastes the
+// snippet through the clipboard, then puts back what was on the clipboard
+type Paster struct {
+	clipboard Clipboard
+	tool      keyTool
+}
+
+// NewPaster creates a paster with the first key tool installed for the
+// session, X11 or Wayland
+func NewPaster(clipboard Clipboard) (*Paster, error) {
+	order := []string{"xdotool", "ydotool"}
+	if os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "wayland" {
+		order = []string{"wtype", "ydotool"}
+	}
+	for _, name := range order {
+		if _, err := exec.LookPath(name); err == nil {
+			return &Paster{clipboard: clipboard, tool: keyTools[name]}, nil
+		}
+	}
+	return nil, fmt.Errorf("snippet expansion needs %s to press keys; install one of them", strings.Join(order, " or "))
+}
+
+// Paste replaces the abbreviation that was just typed with its snippet.
+// Terminals paste with ctrl+shift+v, so expansions do not work in them.
+func (p *Paster) Paste(ctx context.Context, expansion Expansion) error {
+	previous, previousErr := p.clipboard.GetClipboardText(ctx)
+	if err := p.clipboard.SetClipboardText(ctx, expansion.Text); err != nil {
+		return fmt.Errorf("failed to copy the snippet: %w", err)
+	}
+
+	out, err := exec.CommandContext(ctx, p.tool.name, p.tool.args(expansion.Erase)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %v %s", p.tool.name, err, strings.TrimSpace(string(out)))
+	}
+
+	if previousErr == nil && previous != "" {
+		time.Sleep(restoreDelay)
+		if err := p.clipboard.SetClipboardText(ctx, previous); err != nil {
+			return fmt.Errorf("failed to restore the clipboard: %w", err)
+		}
+	}
+	return nil
+}
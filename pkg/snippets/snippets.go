@@ -0,0 +1,134 @@
+// Package snippets keeps named pieces of text, such as an email signature,
+// to paste with 'lumo snip paste' or, once the user turns it on, to expand
+// from an abbreviation typed anywhere on the desktop.
+package snippets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// DefaultPrefix starts the abbreviations that expand to snippets, so that
+// typing a snippet's name in a sentence does not expand it
+const DefaultPrefix = ";"
+
+// validName matches snippet names, which are typed as abbreviations
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,31}$`)
+
+// Snippet is a named piece of text
+type Snippet struct {
+	Name      string    `json:"name"`
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Path returns the file the snippets are kept in
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snippets.json"), nil
+}
+
+// List returns every snippet, sorted by name
+func List() ([]Snippet, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippets: %w", err)
+	}
+	var snippets []Snippet
+	if err := json.Unmarshal(data, &snippets); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	sort.Slice(snippets, func(i, j int) bool {
+		return snippets[i].Name < snippets[j].Name
+	})
+	return snippets, nil
+}
+
+// Get returns the snippet with the given name
+func Get(name string) (*Snippet, error) {
+	snippets, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range snippets {
+		if snippets[i].Name == name {
+			return &snippets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no snippet named %q", name)
+}
+
+// Add saves a snippet, replacing one with the same name, and reports
+// whether it replaced one
+func Add(name, text string) (bool, error) {
+	if !validName.MatchString(name) {
+		return false, fmt.Errorf("invalid snippet name %q: use up to 32 letters, digits, dots, dashes, and underscores", name)
+	}
+	if text == "" {
+		return false, fmt.Errorf("the snippet %q has no text", name)
+	}
+
+	snippets, err := List()
+	if err != nil {
+		return false, err
+	}
+	snippet := Snippet{Name: name, Text: text, UpdatedAt: time.Now()}
+	for i := range snippets {
+		if snippets[i].Name == name {
+			snippets[i] = snippet
+			return true, write(snippets)
+		}
+	}
+	return false, write(append(snippets, snippet))
+}
+
+// Remove deletes the snippet with the given name
+func Remove(name string) error {
+	snippets, err := List()
+	if err != nil {
+		return err
+	}
+	for i := range snippets {
+		if snippets[i].Name == name {
+			return write(append(snippets[:i], snippets[i+1:]...))
+		}
+	}
+	return fmt.Errorf("no snippet named %q", name)
+}
+
+// write stores the snippets. They may hold addresses or phone numbers, so
+// the file is kept private.
+func write(snippets []Snippet) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snippets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snippets: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write snippets: %w", err)
+	}
+	return nil
+}
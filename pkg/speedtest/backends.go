@@ -0,0 +1,70 @@
+package speedtest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Speed test backend names
+const (
+	// BackendBuiltin is lumo's original built-in tester
+	BackendBuiltin = "builtin"
+	// BackendCloudflare measures against speed.cloudflare.com
+	BackendCloudflare = "cloudflare"
+	// BackendOokla runs the official Ookla speedtest CLI
+	BackendOokla = "ookla"
+)
+
+// Backend describes a selectable speed test backend
+type Backend struct {
+	Name        string
+	Description string
+	// Available reports whether the backend can run on this machine
+	Available func() bool
+	create    func() SpeedTester
+}
+
+// backends lists the speed test backends in display order
+var backends = []Backend{
+	{
+		Name:        BackendBuiltin,
+		Description: "Lumo's built-in tester",
+		Available:   func() bool { return true },
+		create:      func() SpeedTester { return newBuiltinTester() },
+	},
+	{
+		Name:        BackendCloudflare,
+		Description: "Cloudflare speed test (speed.cloudflare.com)",
+		Available:   func() bool { return true },
+		create:      func() SpeedTester { return newCloudflareTester() },
+	},
+	{
+		Name:        BackendOokla,
+		Description: "Ookla speedtest CLI",
+		Available:   ooklaInstalled,
+		create:      func() SpeedTester { return newOoklaTester() },
+	},
+}
+
+// Backends returns the selectable speed test backends
+func Backends() []Backend {
+	return backends
+}
+
+// New creates a speed tester for the named backend. An empty name selects
+// the built-in tester.
+func New(name string) (SpeedTester, error) {
+	if name == "" {
+		name = BackendBuiltin
+	}
+	for _, backend := range backends {
+		if backend.Name != strings.ToLower(name) {
+			continue
+		}
+		if !backend.Available() {
+			return nil, fmt.Errorf("speed test backend %s is not available on this system", backend.Name)
+		}
+		return backend.create(), nil
+	}
+	return nil, fmt.Errorf("unknown speed test backend: %s", name)
+}
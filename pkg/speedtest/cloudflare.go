@@ -0,0 +1,194 @@
+package speedtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	// cloudflareURL is the base URL of Cloudflare's speed test API
+	cloudflareURL = "https://speed.cloudflare.com"
+	// cloudflareDownloadBytes is the size of each download sample
+	cloudflareDownloadBytes = 25_000_000
+	// cloudflareUploadBytes is the size of each upload sample
+	cloudflareUploadBytes = 10_000_000
+	// cloudflareLatencySamples is how many round trips are timed for latency
+	cloudflareLatencySamples = 5
+)
+
+// cloudflareTester measures speed against speed.cloudflare.com
+type cloudflareTester struct {
+	client *http.Client
+}
+
+// newCloudflareTester creates a Cloudflare speed tester
+func newCloudflareTester() *cloudflareTester {
+	return &cloudflareTester{
+		client: &http.Client{},
+	}
+}
+
+// Name returns the backend name
+func (c *cloudflareTester) Name() string {
+	return BackendCloudflare
+}
+
+// RunTest performs a complete speed test (download, upload, and latency)
+func (c *cloudflareTester) RunTest(ctx context.Context) (*SpeedTestResult, error) {
+	return c.run(ctx, true, true)
+}
+
+// RunDownloadTest performs only a download speed test
+func (c *cloudflareTester) RunDownloadTest(ctx context.Context) (*SpeedTestResult, error) {
+	return c.run(ctx, true, false)
+}
+
+// RunUploadTest performs only an upload speed test
+func (c *cloudflareTester) RunUploadTest(ctx context.Context) (*SpeedTestResult, error) {
+	return c.run(ctx, false, true)
+}
+
+// run performs the requested measurements
+func (c *cloudflareTester) run(ctx context.Context, download, upload bool) (*SpeedTestResult, error) {
+	result := &SpeedTestResult{
+		Backend:   BackendCloudflare,
+		Timestamp: time.Now(),
+	}
+
+	// The meta endpoint reports the ISP and the data center serving the test
+	if meta, err := c.meta(ctx); err == nil {
+		result.ISP = meta.ASOrganization
+		result.Server = fmt.Sprintf("Cloudflare %s", meta.Colo)
+		if meta.City != "" {
+			result.Server += fmt.Sprintf(" (%s)", meta.City)
+		}
+	}
+
+	latency, err := c.measureLatency(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure latency: %w", err)
+	}
+	result.Latency = latency
+
+	if download {
+		result.DownloadSpeed, err = c.measureDownload(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure download speed: %w", err)
+		}
+	}
+
+	if upload {
+		result.UploadSpeed, err = c.measureUpload(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure upload speed: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// cloudflareMeta is the response of the meta endpoint
+type cloudflareMeta struct {
+	ASOrganization string `json:"asOrganization"`
+	Colo           string `json:"colo"`
+	City           string `json:"city"`
+}
+
+// meta fetches information about the connection
+func (c *cloudflareTester) meta(ctx context.Context) (*cloudflareMeta, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cloudflareURL+"/meta", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var meta cloudflareMeta
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// measureLatency returns the median round trip time of empty downloads in ms
+func (c *cloudflareTester) measureLatency(ctx context.Context) (int, error) {
+	samples := make([]time.Duration, 0, cloudflareLatencySamples)
+	for i := 0; i < cloudflareLatencySamples; i++ {
+		start := time.Now()
+		if _, err := c.download(ctx, 0); err != nil {
+			return 0, err
+		}
+		samples = append(samples, time.Since(start))
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return int(samples[len(samples)/2].Milliseconds()), nil
+}
+
+// measureDownload downloads a sample and returns the speed in Mbps
+func (c *cloudflareTester) measureDownload(ctx context.Context) (float64, error) {
+	start := time.Now()
+	n, err := c.download(ctx, cloudflareDownloadBytes)
+	if err != nil {
+		return 0, err
+	}
+	return megabitsPerSecond(n, time.Since(start)), nil
+}
+
+// measureUpload uploads a sample and returns the speed in Mbps
+func (c *cloudflareTester) measureUpload(ctx context.Context) (float64, error) {
+	payload := make([]byte, cloudflareUploadBytes)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cloudflareURL+"/__up", bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("upload failed with status %d", resp.StatusCode)
+	}
+
+	return megabitsPerSecond(int64(len(payload)), time.Since(start)), nil
+}
+
+// download fetches the given number of bytes and returns how many arrived
+func (c *cloudflareTester) download(ctx context.Context, size int) (int64, error) {
+	url := fmt.Sprintf("%s/__down?bytes=%d", cloudflareURL, size)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	return io.Copy(io.Discard, resp.Body)
+}
+
+// megabitsPerSecond converts a transfer into Mbps
+func megabitsPerSecond(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / elapsed.Seconds() / 1e6
+}
@@ -0,0 +1,122 @@
+package speedtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// historyFileName is the file in StateDir holding past full speed tests,
+// one JSON result per line
+const historyFileName = "speedtest_history.jsonl"
+
+// Summary summarizes the speed tests of a period. Speeds are in Mbps and
+// latencies in milliseconds.
+type Summary struct {
+	Tests       int     `json:"tests"`
+	AvgDownload float64 `json:"avg_download_mbps"`
+	MinDownload float64 `json:"min_download_mbps"`
+	MaxDownload float64 `json:"max_download_mbps"`
+	AvgUpload   float64 `json:"avg_upload_mbps"`
+	MinUpload   float64 `json:"min_upload_mbps"`
+	MaxUpload   float64 `json:"max_upload_mbps"`
+	AvgLatency  float64 `json:"avg_latency_ms"`
+}
+
+// historyFile returns the path of the speed test history
+func historyFile() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFileName), nil
+}
+
+// RecordResult adds the result of a full speed test to the history
+func RecordResult(result *SpeedTestResult) error {
+	path, err := historyFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	entry := *result
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode speed test result: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open speed test history: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write speed test history: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory returns the recorded speed tests since the given time,
+// oldest first. Lines that cannot be read are skipped.
+func LoadHistory(since time.Time) ([]SpeedTestResult, error) {
+	path, err := historyFile()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open speed test history: %w", err)
+	}
+	defer file.Close()
+
+	var results []SpeedTestResult
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result SpeedTestResult
+		if json.Unmarshal(scanner.Bytes(), &result) != nil || result.Timestamp.Before(since) {
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, scanner.Err()
+}
+
+// Summarize computes the averages and ranges of speed test results
+func Summarize(results []SpeedTestResult) Summary {
+	summary := Summary{Tests: len(results)}
+	if len(results) == 0 {
+		return summary
+	}
+
+	summary.MinDownload, summary.MinUpload = results[0].DownloadSpeed, results[0].UploadSpeed
+	var latency int
+	for _, result := range results {
+		summary.AvgDownload += result.DownloadSpeed
+		summary.AvgUpload += result.UploadSpeed
+		latency += result.Latency
+		summary.MinDownload = min(summary.MinDownload, result.DownloadSpeed)
+		summary.MaxDownload = max(summary.MaxDownload, result.DownloadSpeed)
+		summary.MinUpload = min(summary.MinUpload, result.UploadSpeed)
+		summary.MaxUpload = max(summary.MaxUpload, result.UploadSpeed)
+	}
+	count := float64(len(results))
+	summary.AvgDownload /= count
+	summary.AvgUpload /= count
+	summary.AvgLatency = float64(latency) / count
+	return summary
+}
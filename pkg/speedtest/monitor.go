@@ -0,0 +1,313 @@
+package speedtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// Latency monitor defaults
+const (
+	DefaultMonitorTarget        = "1.1.1.1"
+	DefaultMonitorDuration      = time.Minute
+	DefaultMonitorInterval      = time.Second
+	DefaultMonitorLossThreshold = 5.0
+	// DefaultAlertWindow is how many recent samples the loss alert looks at
+	DefaultAlertWindow = 20
+)
+
+// minAlertSamples is how many samples are needed before loss can raise an alert
+const minAlertSamples = 5
+
+// pingTimeout is how long a single ping may take before it counts as lost
+const pingTimeout = 2 * time.Second
+
+// sparkBlocks are the bar characters of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// pingTimePattern matches the round trip time in ping output, e.g. "time=12.3 ms"
+var pingTimePattern = regexp.MustCompile(`time[=<]\s*([0-9.]+)\s*ms`)
+
+// PingFunc measures one round trip to a target
+type PingFunc func(ctx context.Context, target string) (time.Duration, error)
+
+// Sample is a single latency measurement
+type Sample struct {
+	Time time.Time `json:"time"`
+	RTT  float64   `json:"rtt_ms,omitempty"`
+	Lost bool      `json:"lost,omitempty"`
+}
+
+// LatencyStats summarizes a series of samples. Times are in milliseconds
+// and Loss is a percentage.
+type LatencyStats struct {
+	Samples int     `json:"samples"`
+	Lost    int     `json:"lost"`
+	Loss    float64 `json:"loss_percent"`
+	Min     float64 `json:"min_ms"`
+	Avg     float64 `json:"avg_ms"`
+	Max     float64 `json:"max_ms"`
+	Jitter  float64 `json:"jitter_ms"`
+}
+
+// LatencySeries is the stored result of a monitoring run
+type LatencySeries struct {
+	Target    string        `json:"target"`
+	StartedAt time.Time     `json:"started_at"`
+	Interval  time.Duration `json:"interval"`
+	Samples   []Sample      `json:"samples"`
+	Stats     LatencyStats  `json:"stats"`
+	Alerts    int           `json:"alerts"`
+}
+
+// MonitorOptions configures a latency monitoring run
+type MonitorOptions struct {
+	Target   string
+	Duration time.Duration
+	Interval time.Duration
+	// LossThreshold is the packet loss percentage over the alert window that raises an alert
+	LossThreshold float64
+	AlertWindow   int
+	// Ping measures a round trip; nil uses the system ping command
+	Ping PingFunc
+	// OnSample is called after every sample with the series so far
+	OnSample func(series *LatencySeries)
+	// OnAlert is called when loss first exceeds the threshold. It is called
+	// again only after loss has dropped back below the threshold.
+	OnAlert func(series *LatencySeries, window LatencyStats)
+}
+
+// MonitorLatency samples the latency to a target until the duration is over
+// or the context is cancelled, and returns the series
+func MonitorLatency(ctx context.Context, opts MonitorOptions) *LatencySeries {
+	if opts.Target == "" {
+		opts.Target = DefaultMonitorTarget
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultMonitorInterval
+	}
+	if opts.AlertWindow <= 0 {
+		opts.AlertWindow = DefaultAlertWindow
+	}
+	if opts.Ping == nil {
+		opts.Ping = Ping
+	}
+
+	series := &LatencySeries{
+		Target:    opts.Target,
+		StartedAt: time.Now(),
+		Interval:  opts.Interval,
+	}
+
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	alerting := false
+sampling:
+	for {
+		sample := Sample{Time: time.Now()}
+		rtt, err := opts.Ping(ctx, opts.Target)
+		if ctx.Err() != nil {
+			// A ping cut short by the end of the run is not a lost packet
+			break
+		}
+		if err != nil {
+			sample.Lost = true
+		} else {
+			sample.RTT = float64(rtt.Microseconds()) / 1000
+		}
+		series.Samples = append(series.Samples, sample)
+
+		if opts.OnSample != nil {
+			opts.OnSample(series)
+		}
+
+		// Alert once per episode of loss above the threshold
+		window := Stats(series.Samples[max(0, len(series.Samples)-opts.AlertWindow):])
+		switch {
+		case !alerting && opts.LossThreshold > 0 && window.Samples >= minAlertSamples && window.Loss > opts.LossThreshold:
+			alerting = true
+			series.Alerts++
+			if opts.OnAlert != nil {
+				opts.OnAlert(series, window)
+			}
+		case alerting && window.Loss <= opts.LossThreshold:
+			alerting = false
+		}
+
+		select {
+		case <-ctx.Done():
+			break sampling
+		case <-ticker.C:
+		}
+	}
+
+	series.Stats = Stats(series.Samples)
+	return series
+}
+
+// Stats summarizes samples. Jitter is the mean difference between
+// consecutive successful round trips.
+func Stats(samples []Sample) LatencyStats {
+	stats := LatencyStats{Samples: len(samples)}
+
+	var sum, jitterSum float64
+	var received, pairs int
+	prev := -1.0
+	for _, sample := range samples {
+		if sample.Lost {
+			stats.Lost++
+			continue
+		}
+		if received == 0 || sample.RTT < stats.Min {
+			stats.Min = sample.RTT
+		}
+		if sample.RTT > stats.Max {
+			stats.Max = sample.RTT
+		}
+		sum += sample.RTT
+		received++
+
+		if prev >= 0 {
+			jitterSum += math.Abs(sample.RTT - prev)
+			pairs++
+		}
+		prev = sample.RTT
+	}
+
+	if received > 0 {
+		stats.Avg = sum / float64(received)
+	}
+	if pairs > 0 {
+		stats.Jitter = jitterSum / float64(pairs)
+	}
+	if stats.Samples > 0 {
+		stats.Loss = float64(stats.Lost) / float64(stats.Samples) * 100
+	}
+	return stats
+}
+
+// Sparkline renders the most recent samples as a line of bars scaled
+// between the lowest and highest round trip. Lost samples are shown as "×".
+func Sparkline(samples []Sample, width int) string {
+	if width > 0 && len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+
+	stats := Stats(samples)
+	span := stats.Max - stats.Min
+
+	var b strings.Builder
+	for _, sample := range samples {
+		if sample.Lost {
+			b.WriteRune('×')
+			continue
+		}
+		level := 0
+		if span > 0 {
+			level = int((sample.RTT - stats.Min) / span * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// Ping measures one round trip to a target with the system ping command,
+// falling back to timing a TCP connection when ping is not installed
+func Ping(ctx context.Context, target string) (time.Duration, error) {
+	path, err := exec.LookPath("ping")
+	if err != nil {
+		return tcpPing(ctx, target)
+	}
+
+	var args []string
+	switch runtime.GOOS {
+	case "windows":
+		args = []string{"-n", "1", "-w", strconv.Itoa(int(pingTimeout.Milliseconds())), target}
+	case "darwin":
+		args = []string{"-c", "1", "-W", strconv.Itoa(int(pingTimeout.Milliseconds())), target}
+	default:
+		args = []string{"-c", "1", "-W", strconv.Itoa(int(pingTimeout.Seconds())), target}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout+time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, args...).Output()
+	if err != nil {
+		return 0, fmt.Errorf("no reply from %s", target)
+	}
+
+	match := pingTimePattern.FindSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("no reply from %s", target)
+	}
+	ms, err := strconv.ParseFloat(string(match[1]), 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
+
+// tcpPing times a TCP connection to the target's HTTPS port
+func tcpPing(ctx context.Context, target string) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: pingTimeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(target, "443"))
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+	return time.Since(start), nil
+}
+
+// SaveSeries stores a monitoring run in ~/.local/state/lumo/latency and returns its path
+func SaveSeries(series *LatencySeries) (string, error) {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(stateDir, "latency")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create latency directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(series, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode latency series: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.json", series.StartedAt.Format("20060102-150405"), sanitizeTarget(series.Target))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write latency series: %w", err)
+	}
+	return path, nil
+}
+
+// sanitizeTarget makes a target usable in a file name
+func sanitizeTarget(target string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, target)
+}
@@ -0,0 +1,119 @@
+package speedtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ooklaBinary is the name of the Ookla speedtest CLI
+const ooklaBinary = "speedtest"
+
+// ooklaTester runs the official Ookla speedtest CLI
+type ooklaTester struct{}
+
+// newOoklaTester creates an Ookla speed tester
+func newOoklaTester() *ooklaTester {
+	return &ooklaTester{}
+}
+
+// ooklaInstalled reports whether the Ookla CLI is installed. The Python
+// speedtest-cli uses the same binary name but a different output format.
+func ooklaInstalled() bool {
+	path, err := exec.LookPath(ooklaBinary)
+	if err != nil {
+		return false
+	}
+	output, err := exec.Command(path, "--version").Output()
+	return err == nil && strings.Contains(string(output), "Ookla")
+}
+
+// Name returns the backend name
+func (o *ooklaTester) Name() string {
+	return BackendOokla
+}
+
+// RunTest performs a complete speed test (download, upload, and latency)
+func (o *ooklaTester) RunTest(ctx context.Context) (*SpeedTestResult, error) {
+	return o.run(ctx)
+}
+
+// RunDownloadTest performs only a download speed test. The CLI always
+// measures both directions, so the upload result is dropped.
+func (o *ooklaTester) RunDownloadTest(ctx context.Context) (*SpeedTestResult, error) {
+	result, err := o.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.UploadSpeed = 0
+	return result, nil
+}
+
+// RunUploadTest performs only an upload speed test. The CLI always
+// measures both directions, so the download result is dropped.
+func (o *ooklaTester) RunUploadTest(ctx context.Context) (*SpeedTestResult, error) {
+	result, err := o.run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.DownloadSpeed = 0
+	return result, nil
+}
+
+// ooklaOutput is the JSON printed by the Ookla CLI
+type ooklaOutput struct {
+	Ping struct {
+		Latency float64 `json:"latency"`
+	} `json:"ping"`
+	Download struct {
+		Bandwidth int64 `json:"bandwidth"` // bytes per second
+	} `json:"download"`
+	Upload struct {
+		Bandwidth int64 `json:"bandwidth"` // bytes per second
+	} `json:"upload"`
+	ISP    string `json:"isp"`
+	Server struct {
+		Name     string `json:"name"`
+		Location string `json:"location"`
+	} `json:"server"`
+}
+
+// run runs the CLI and converts its output
+func (o *ooklaTester) run(ctx context.Context) (*SpeedTestResult, error) {
+	cmd := exec.CommandContext(ctx, ooklaBinary, "--format=json", "--accept-license", "--accept-gdpr")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("ookla speedtest failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("ookla speedtest failed: %w", err)
+	}
+
+	return parseOoklaOutput(output)
+}
+
+// parseOoklaOutput converts the CLI's JSON output into a result
+func parseOoklaOutput(output []byte) (*SpeedTestResult, error) {
+	var data ooklaOutput
+	if err := json.Unmarshal(output, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ookla speedtest output: %w", err)
+	}
+
+	server := data.Server.Name
+	if data.Server.Location != "" {
+		server += fmt.Sprintf(" (%s)", data.Server.Location)
+	}
+
+	return &SpeedTestResult{
+		DownloadSpeed: float64(data.Download.Bandwidth) * 8 / 1e6,
+		UploadSpeed:   float64(data.Upload.Bandwidth) * 8 / 1e6,
+		Latency:       int(data.Ping.Latency + 0.5),
+		ISP:           data.ISP,
+		Server:        server,
+		Backend:       BackendOokla,
+		Timestamp:     time.Now(),
+	}, nil
+}
@@ -11,32 +11,51 @@ import (
 	"github.com/agnath18K/lumo/pkg/utils"
 )
 
-// SpeedTestResult represents the result of a speed test
+// SpeedTestResult represents the result of a speed test. Every backend fills
+// in the same fields and units.
 type SpeedTestResult struct {
-	DownloadSpeed float64 // in Mbps
-	UploadSpeed   float64 // in Mbps
-	Latency       int     // in ms
-	ISP           string
-	Server        string
-	Timestamp     time.Time
+	DownloadSpeed float64   `json:"download_mbps"` // in Mbps
+	UploadSpeed   float64   `json:"upload_mbps"`   // in Mbps
+	Latency       int       `json:"latency_ms"`    // in ms
+	ISP           string    `json:"isp,omitempty"`
+	Server        string    `json:"server,omitempty"`
+	Backend       string    `json:"backend"`
+	Timestamp     time.Time `json:"timestamp"`
 }
 
-// SpeedTester handles internet speed testing
-type SpeedTester struct {
+// SpeedTester runs internet speed tests against one backend
+type SpeedTester interface {
+	// Name returns the backend name used in the configuration
+	Name() string
+	// RunTest performs a complete speed test (download, upload, and latency)
+	RunTest(ctx context.Context) (*SpeedTestResult, error)
+	// RunDownloadTest performs only a download speed test
+	RunDownloadTest(ctx context.Context) (*SpeedTestResult, error)
+	// RunUploadTest performs only an upload speed test
+	RunUploadTest(ctx context.Context) (*SpeedTestResult, error)
+}
+
+// builtinTester is the original built-in speed tester
+type builtinTester struct {
 	client *http.Client
 }
 
-// NewSpeedTester creates a new speed tester
-func NewSpeedTester() *SpeedTester {
-	return &SpeedTester{
+// newBuiltinTester creates the built-in speed tester
+func newBuiltinTester() *builtinTester {
+	return &builtinTester{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+// Name returns the backend name
+func (s *builtinTester) Name() string {
+	return BackendBuiltin
+}
+
 // RunTest performs a complete speed test (download, upload, and latency)
-func (s *SpeedTester) RunTest(ctx context.Context) (*SpeedTestResult, error) {
+func (s *builtinTester) RunTest(ctx context.Context) (*SpeedTestResult, error) {
 	// Check if there's an internet connection
 	if !utils.CheckInternetConnectivity() {
 		return nil, fmt.Errorf("no internet connection detected")
@@ -44,6 +63,7 @@ func (s *SpeedTester) RunTest(ctx context.Context) (*SpeedTestResult, error) {
 
 	// Create a result object
 	result := &SpeedTestResult{
+		Backend:   BackendBuiltin,
 		Timestamp: time.Now(),
 	}
 
@@ -80,7 +100,7 @@ func (s *SpeedTester) RunTest(ctx context.Context) (*SpeedTestResult, error) {
 }
 
 // RunDownloadTest performs only a download speed test
-func (s *SpeedTester) RunDownloadTest(ctx context.Context) (*SpeedTestResult, error) {
+func (s *builtinTester) RunDownloadTest(ctx context.Context) (*SpeedTestResult, error) {
 	// Check if there's an internet connection
 	if !utils.CheckInternetConnectivity() {
 		return nil, fmt.Errorf("no internet connection detected")
@@ -88,6 +108,7 @@ func (s *SpeedTester) RunDownloadTest(ctx context.Context) (*SpeedTestResult, er
 
 	// Create a result object
 	result := &SpeedTestResult{
+		Backend:   BackendBuiltin,
 		Timestamp: time.Now(),
 	}
 
@@ -110,7 +131,7 @@ func (s *SpeedTester) RunDownloadTest(ctx context.Context) (*SpeedTestResult, er
 }
 
 // RunUploadTest performs only an upload speed test
-func (s *SpeedTester) RunUploadTest(ctx context.Context) (*SpeedTestResult, error) {
+func (s *builtinTester) RunUploadTest(ctx context.Context) (*SpeedTestResult, error) {
 	// Check if there's an internet connection
 	if !utils.CheckInternetConnectivity() {
 		return nil, fmt.Errorf("no internet connection detected")
@@ -118,6 +139,7 @@ func (s *SpeedTester) RunUploadTest(ctx context.Context) (*SpeedTestResult, erro
 
 	// Create a result object
 	result := &SpeedTestResult{
+		Backend:   BackendBuiltin,
 		Timestamp: time.Now(),
 	}
 
@@ -140,7 +162,7 @@ func (s *SpeedTester) RunUploadTest(ctx context.Context) (*SpeedTestResult, erro
 }
 
 // FormatResult formats the speed test result as a string
-func (s *SpeedTester) FormatResult(result *SpeedTestResult) string {
+func FormatResult(result *SpeedTestResult) string {
 	var sb strings.Builder
 
 	// Get terminal width for proper formatting
@@ -178,9 +200,12 @@ func (s *SpeedTester) FormatResult(result *SpeedTestResult) string {
 	if result.Server != "" {
 		sb.WriteString("│ " + utils.PadRight("Server:", 12) + " " + utils.PadRight(result.Server, termWidth-16) + " │\n")
 	}
+	if result.Backend != "" {
+		sb.WriteString("│ " + utils.PadRight("Backend:", 12) + " " + utils.PadRight(result.Backend, termWidth-16) + " │\n")
+	}
 
 	// Add timestamp
-	sb.WriteString("│ " + utils.PadRight("Time:", 12) + " " + utils.PadRight(result.Timestamp.Format("2006-01-02 15:04:05"), termWidth-16) + " │\n")
+	sb.WriteString("│ " + utils.PadRight("Time:", 12) + " " + utils.PadRight(utils.FormatTimestamp(result.Timestamp), termWidth-16) + " │\n")
 
 	// Add a connection quality rating
 	rating := rateConnection(result.DownloadSpeed, result.UploadSpeed, result.Latency)
@@ -199,7 +224,7 @@ type Server struct {
 }
 
 // findBestServer finds the best server for speed testing
-func (s *SpeedTester) findBestServer() (*Server, error) {
+func (s *builtinTester) findBestServer() (*Server, error) {
 	// In a real implementation, this would query a list of servers
 	// and select the best one based on ping time and distance
 	// For now, we'll return a mock server
@@ -211,21 +236,21 @@ func (s *SpeedTester) findBestServer() (*Server, error) {
 }
 
 // detectISP attempts to detect the user's ISP
-func (s *SpeedTester) detectISP() string {
+func (s *builtinTester) detectISP() string {
 	// In a real implementation, this would query an API to get the ISP
 	// For now, we'll return a mock ISP
 	return "Example ISP"
 }
 
 // measureLatency measures the latency to the server
-func (s *SpeedTester) measureLatency(server *Server) (int, error) {
+func (s *builtinTester) measureLatency(server *Server) (int, error) {
 	// In a real implementation, this would send ping requests to the server
 	// For now, we'll return a mock latency
 	return 25, nil
 }
 
 // measureDownloadSpeed measures the download speed
-func (s *SpeedTester) measureDownloadSpeed(server *Server) (float64, error) {
+func (s *builtinTester) measureDownloadSpeed(server *Server) (float64, error) {
 	// In a real implementation, this would download files from the server
 	// and measure the speed
 	// For now, we'll return a mock download speed
@@ -233,7 +258,7 @@ func (s *SpeedTester) measureDownloadSpeed(server *Server) (float64, error) {
 }
 
 // measureUploadSpeed measures the upload speed
-func (s *SpeedTester) measureUploadSpeed(server *Server) (float64, error) {
+func (s *builtinTester) measureUploadSpeed(server *Server) (float64, error) {
 	// In a real implementation, this would upload files to the server
 	// and measure the speed
 	// For now, we'll return a mock upload speed
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/agnath18K/lumo/pkg/utils"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
@@ -66,6 +67,17 @@ func NewHealthChecker() *HealthChecker {
 
 // CheckHealth performs a comprehensive system health check
 func (h *HealthChecker) CheckHealth() (*SystemHealth, error) {
+	return h.check(true)
+}
+
+// CheckHealthQuick checks memory and disk usage only, leaving out the CPU
+// check, which samples usage for a second
+func (h *HealthChecker) CheckHealthQuick() (*SystemHealth, error) {
+	return h.check(false)
+}
+
+// check runs the health checks, with the CPU check if withCPU is set
+func (h *HealthChecker) check(withCPU bool) (*SystemHealth, error) {
 	// Create a new system health object
 	health := &SystemHealth{
 		Timestamp: time.Now(),
@@ -80,9 +92,11 @@ func (h *HealthChecker) CheckHealth() (*SystemHealth, error) {
 	}
 
 	// Check CPU usage
-	cpuCheck, err := h.checkCPU()
-	if err == nil {
-		health.Checks = append(health.Checks, cpuCheck)
+	if withCPU {
+		cpuCheck, err := h.checkCPU()
+		if err == nil {
+			health.Checks = append(health.Checks, cpuCheck)
+		}
 	}
 
 	// Check memory usage
@@ -103,6 +117,31 @@ func (h *HealthChecker) CheckHealth() (*SystemHealth, error) {
 	return health, nil
 }
 
+// Status returns the worst status of the checks
+func (s *SystemHealth) Status() HealthStatus {
+	status := StatusHealthy
+	for _, check := range s.Checks {
+		if check.Status == StatusCritical {
+			return StatusCritical
+		}
+		if check.Status == StatusWarning {
+			status = StatusWarning
+		}
+	}
+	return status
+}
+
+// Alerts returns the checks that are not in a healthy state
+func (s *SystemHealth) Alerts() []HealthCheck {
+	var alerts []HealthCheck
+	for _, check := range s.Checks {
+		if check.Status != StatusHealthy {
+			alerts = append(alerts, check)
+		}
+	}
+	return alerts
+}
+
 // checkCPU checks CPU usage
 func (h *HealthChecker) checkCPU() (HealthCheck, error) {
 	check := HealthCheck{
@@ -235,7 +274,7 @@ func FormatHealthCheck(health *SystemHealth) string {
 	boxWidth := 60
 
 	// Format header
-	headerText := fmt.Sprintf(" System Health Check (%s) ", health.Timestamp.Format("2006-01-02 15:04:05"))
+	headerText := fmt.Sprintf(" System Health Check (%s) ", utils.FormatTimestamp(health.Timestamp))
 	sb.WriteString("╭" + padCenter(headerText, boxWidth-2, "─") + "╮\n")
 	sb.WriteString("│ " + padRight(fmt.Sprintf("Host: %s", health.Hostname), boxWidth-4) + " │\n")
 	sb.WriteString("│ " + padRight(fmt.Sprintf("Platform: %s", health.Platform), boxWidth-4) + " │\n")
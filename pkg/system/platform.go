@@ -0,0 +1,268 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// osReleasePaths are where Linux distributions describe themselves
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// distroPackageManagers maps distribution IDs, as used in os-release ID and
+// ID_LIKE, to their package managers in order of preference
+var distroPackageManagers = map[string][]string{
+	"fedora":    {"dnf", "yum"},
+	"rhel":      {"dnf", "yum"},
+	"centos":    {"dnf", "yum"},
+	"rocky":     {"dnf", "yum"},
+	"almalinux": {"dnf", "yum"},
+	"amzn":      {"dnf", "yum"},
+	"debian":    {"apt"},
+	"ubuntu":    {"apt"},
+	"arch":      {"pacman"},
+	"opensuse":  {"zypper"},
+	"suse":      {"zypper"},
+	"alpine":    {"apk"},
+	"gentoo":    {"emerge"},
+	"void":      {"xbps-install"},
+	"nixos":     {"nix-env"},
+	"solus":     {"eopkg"},
+}
+
+// knownPackageManagers are tried in order when the distribution is not recognized
+var knownPackageManagers = map[string][]string{
+	"linux":   {"apt", "dnf", "yum", "pacman", "zypper", "apk", "emerge", "xbps-install", "nix-env", "eopkg"},
+	"darwin":  {"brew", "port"},
+	"windows": {"winget", "choco", "scoop"},
+}
+
+// extraPackageManagers install applications alongside the system package manager
+var extraPackageManagers = []string{"flatpak", "snap"}
+
+// Platform describes the operating system commands will run on
+type Platform struct {
+	OS             string   `json:"os"`
+	Arch           string   `json:"arch"`
+	Distro         string   `json:"distro,omitempty"`    // Human readable name, e.g. "Fedora Linux 40"
+	DistroID       string   `json:"distro_id,omitempty"` // os-release ID, e.g. "fedora"
+	DistroLike     []string `json:"distro_like,omitempty"`
+	Version        string   `json:"version,omitempty"`
+	PackageManager string   `json:"package_manager,omitempty"`
+	// OtherPackageManagers are also installed, such as flatpak or snap
+	OtherPackageManagers []string `json:"other_package_managers,omitempty"`
+	InitSystem           string   `json:"init_system,omitempty"`
+	Shell                string   `json:"shell,omitempty"` // The user's login shell
+}
+
+var (
+	platformOnce   sync.Once
+	cachedPlatform *Platform
+)
+
+// DetectPlatform describes the current machine. Detection runs once per
+// process and the result is shared, so callers must not modify it.
+func DetectPlatform() *Platform {
+	platformOnce.Do(func() {
+		cachedPlatform = detectPlatform()
+	})
+	return cachedPlatform
+}
+
+// detectPlatform inspects the current machine
+func detectPlatform() *Platform {
+	platform := &Platform{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		for _, path := range osReleasePaths {
+			file, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			release := ParseOSRelease(file)
+			file.Close()
+
+			platform.DistroID = release["ID"]
+			platform.DistroLike = strings.Fields(release["ID_LIKE"])
+			platform.Version = release["VERSION_ID"]
+			platform.Distro = release["PRETTY_NAME"]
+			if platform.Distro == "" {
+				platform.Distro = strings.TrimSpace(release["NAME"] + " " + platform.Version)
+			}
+			break
+		}
+		platform.InitSystem = linuxInitSystem()
+	case "darwin":
+		platform.Distro = "macOS"
+		if output, err := exec.Command("sw_vers", "-productVersion").Output(); err == nil {
+			platform.Version = strings.TrimSpace(string(output))
+			platform.Distro += " " + platform.Version
+		}
+		platform.InitSystem = "launchd"
+	case "windows":
+		platform.Distro = "Windows"
+		platform.InitSystem = "Windows services"
+	}
+
+	platform.PackageManager = PackageManagerFor(runtime.GOOS, platform.DistroID, platform.DistroLike, commandExists)
+	for _, name := range extraPackageManagers {
+		if name != platform.PackageManager && commandExists(name) {
+			platform.OtherPackageManagers = append(platform.OtherPackageManagers, name)
+		}
+	}
+	platform.Shell = loginShell()
+	return platform
+}
+
+// ParseOSRelease reads the KEY=value pairs of an os-release file
+func ParseOSRelease(r io.Reader) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	return values
+}
+
+// PackageManagerFor picks the package manager for a distribution. The
+// distribution's own manager is preferred, then those of the distributions
+// it is like, then any known manager that is installed. installed reports
+// whether a command is available.
+func PackageManagerFor(goos, distroID string, distroLike []string, installed func(string) bool) string {
+	for _, id := range append([]string{distroID}, distroLike...) {
+		// Variants such as opensuse-tumbleweed share their family's manager
+		family, _, _ := strings.Cut(id, "-")
+		for _, name := range distroPackageManagers[family] {
+			if installed(name) {
+				return name
+			}
+		}
+	}
+	for _, name := range knownPackageManagers[goos] {
+		if installed(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// linuxInitSystem identifies the init system from the running process 1
+func linuxInitSystem() string {
+	if info, err := os.Stat("/run/systemd/system"); err == nil && info.IsDir() {
+		return "systemd"
+	}
+
+	initPath, _ := os.Readlink("/proc/1/exe")
+	if initPath == "" {
+		if comm, err := os.ReadFile("/proc/1/comm"); err == nil {
+			initPath = strings.TrimSpace(string(comm))
+		}
+	}
+	switch name := filepath.Base(initPath); {
+	case strings.Contains(name, "systemd"):
+		return "systemd"
+	case strings.Contains(name, "runit"):
+		return "runit"
+	case strings.Contains(name, "s6"):
+		return "s6"
+	case strings.Contains(name, "openrc"):
+		return "openrc"
+	}
+	if _, err := os.Stat("/sbin/openrc"); err == nil {
+		return "openrc"
+	}
+	if _, err := os.Stat("/etc/init.d"); err == nil {
+		return "sysvinit"
+	}
+	return ""
+}
+
+// loginShell returns the name of the user's shell
+func loginShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return filepath.Base(shell)
+	}
+	if runtime.GOOS == "windows" {
+		if os.Getenv("PSModulePath") != "" {
+			return "powershell"
+		}
+		return "cmd"
+	}
+	return ""
+}
+
+// commandExists reports whether a program is in PATH
+func commandExists(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// serviceTools names the command that manages services under each init system
+var serviceTools = map[string]string{
+	"systemd":  "systemctl",
+	"openrc":   "rc-service and rc-update",
+	"runit":    "sv",
+	"s6":       "s6-rc",
+	"sysvinit": "service",
+	"launchd":  "launchctl",
+}
+
+// PromptContext describes the platform for an AI prompt, so generated
+// commands use the right package manager and service tools
+func (p *Platform) PromptContext() string {
+	var b strings.Builder
+
+	system := p.Distro
+	if system == "" {
+		system = p.OS
+	}
+	b.WriteString(fmt.Sprintf("- Operating system: %s (%s/%s)\n", system, p.OS, p.Arch))
+	if p.DistroID != "" {
+		family := p.DistroID
+		if len(p.DistroLike) > 0 {
+			family += ", like " + strings.Join(p.DistroLike, ", ")
+		}
+		b.WriteString(fmt.Sprintf("- Distribution ID: %s\n", family))
+	}
+
+	if p.PackageManager != "" {
+		line := fmt.Sprintf("- Package manager: %s", p.PackageManager)
+		if len(p.OtherPackageManagers) > 0 {
+			line += fmt.Sprintf(" (also installed: %s)", strings.Join(p.OtherPackageManagers, ", "))
+		}
+		b.WriteString(line + "\n")
+	} else {
+		b.WriteString("- Package manager: none detected\n")
+	}
+
+	if p.InitSystem != "" {
+		line := fmt.Sprintf("- Init system: %s", p.InitSystem)
+		if tool, ok := serviceTools[p.InitSystem]; ok {
+			line += fmt.Sprintf(" (manage services with %s)", tool)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if p.Shell != "" {
+		b.WriteString(fmt.Sprintf("- User's shell: %s\n", p.Shell))
+	}
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// UPower DBus names used to query the power supply
+const (
+	upowerService       = "org.freedesktop.UPower"
+	upowerPath          = "/org/freedesktop/UPower"
+	upowerInterface     = "org.freedesktop.UPower"
+	upowerDisplayDevice = "/org/freedesktop/UPower/devices/DisplayDevice"
+	upowerDeviceIface   = "org.freedesktop.UPower.Device"
+)
+
+// Power modes accepted by the power_mode setting
+const (
+	// PowerModeAuto detects the power source automatically
+	PowerModeAuto = "auto"
+	// PowerModeBattery always uses the lighter on-battery behavior
+	PowerModeBattery = "battery"
+	// PowerModeAC never switches to the on-battery behavior
+	PowerModeAC = "ac"
+)
+
+// PowerStatus describes the current power source
+type PowerStatus struct {
+	OnBattery  bool    `json:"on_battery"`
+	HasBattery bool    `json:"has_battery"`
+	Percentage float64 `json:"percentage,omitempty"`
+	Source     string  `json:"source"` // "upower", "sysfs", or "unknown"
+}
+
+// GetPowerStatus reports whether the machine is running on battery
+func GetPowerStatus() (*PowerStatus, error) {
+	if status, err := getUPowerStatus(); err == nil {
+		return status, nil
+	}
+
+	// Fall back to sysfs when UPower is not running
+	if status, err := getSysfsPowerStatus(); err == nil {
+		return status, nil
+	}
+
+	return &PowerStatus{Source: "unknown"}, fmt.Errorf("unable to determine power source")
+}
+
+// ShouldConservePower reports whether lighter behavior should be used for the given power mode
+func ShouldConservePower(mode string) bool {
+	// An unknown power source comes back as a status that is not on battery
+	status, _ := GetPowerStatus()
+	return ConservePower(mode, status)
+}
+
+// ConservePower reports whether lighter behavior should be used for the
+// given power mode and power status. A nil status is an unknown power
+// source, which is treated as AC.
+func ConservePower(mode string, status *PowerStatus) bool {
+	switch mode {
+	case PowerModeBattery:
+		return true
+	case PowerModeAC:
+		return false
+	default:
+		return status != nil && status.OnBattery
+	}
+}
+
+// getUPowerStatus queries UPower on the system bus
+func getUPowerStatus() (*PowerStatus, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := conn.Object(upowerService, upowerPath).GetProperty(upowerInterface + ".OnBattery")
+	if err != nil {
+		return nil, err
+	}
+	onBattery, _ := variant.Value().(bool)
+
+	status := &PowerStatus{
+		OnBattery: onBattery,
+		Source:    "upower",
+	}
+
+	// The display device aggregates all batteries
+	device := conn.Object(upowerService, dbus.ObjectPath(upowerDisplayDevice))
+	if present, err := device.GetProperty(upowerDeviceIface + ".IsPresent"); err == nil {
+		status.HasBattery, _ = present.Value().(bool)
+	}
+	if percentage, err := device.GetProperty(upowerDeviceIface + ".Percentage"); err == nil {
+		status.Percentage, _ = percentage.Value().(float64)
+	}
+
+	return status, nil
+}
+
+// getSysfsPowerStatus reads /sys/class/power_supply directly
+func getSysfsPowerStatus() (*PowerStatus, error) {
+	return ReadSysfsPowerStatus("/sys/class/power_supply")
+}
+
+// ReadSysfsPowerStatus reads the power status from a directory laid out
+// like /sys/class/power_supply, with one directory per supply
+func ReadSysfsPowerStatus(dir string) (*PowerStatus, error) {
+	supplies, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(supplies) == 0 {
+		return nil, fmt.Errorf("no power supplies found")
+	}
+
+	status := &PowerStatus{Source: "sysfs"}
+	hasMains := false
+	mainsOnline := false
+
+	for _, supply := range supplies {
+		supplyType := readSysfsValue(filepath.Join(supply, "type"))
+		switch supplyType {
+		case "Mains":
+			hasMains = true
+			if readSysfsValue(filepath.Join(supply, "online")) == "1" {
+				mainsOnline = true
+			}
+		case "Battery":
+			status.HasBattery = true
+			var capacity float64
+			if _, err := fmt.Sscanf(readSysfsValue(filepath.Join(supply, "capacity")), "%f", &capacity); err == nil {
+				status.Percentage = capacity
+			}
+		}
+	}
+
+	status.OnBattery = status.HasBattery && hasMains && !mainsOnline
+	return status, nil
+}
+
+// readSysfsValue reads a single sysfs attribute
+func readSysfsValue(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
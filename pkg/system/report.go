@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/agnath18K/lumo/pkg/utils"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
@@ -254,7 +255,7 @@ func FormatSystemReport(report *SystemReport) string {
 	boxWidth := 60
 
 	// Format header
-	headerText := fmt.Sprintf(" System Report (%s) ", report.Timestamp.Format("2006-01-02 15:04:05"))
+	headerText := fmt.Sprintf(" System Report (%s) ", utils.FormatTimestamp(report.Timestamp))
 	sb.WriteString("╭" + padCenter(headerText, boxWidth-2, "─") + "╮\n")
 
 	// Format system information
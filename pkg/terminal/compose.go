@@ -0,0 +1,115 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Where a question's text comes from when it is not on the command line
+const (
+	// PromptFromStdin reads the question from stdin until EOF, as in "lumo -"
+	PromptFromStdin = "-"
+	// PromptFromEditor composes the question in $EDITOR, as in "ask:--edit"
+	PromptFromEditor = "--edit"
+)
+
+// composeScissors separates the question from the instructions in the
+// editor; everything from it on is ignored
+const composeScissors = "# ------------------------ >8 ------------------------"
+
+// ErrEmptyPrompt is returned when no question was written
+var ErrEmptyPrompt = errors.New("empty question")
+
+// ParsePromptSource looks for "-" or "--edit" among the flags leading a
+// question, such as "--persona=tutor -". It returns the source, the other
+// flags, and the text after the flags. The source is empty when the
+// question is on the command line.
+func ParsePromptSource(intent string) (string, string, string) {
+	source := ""
+	var flags []string
+	words := strings.Fields(intent)
+	i := 0
+	for ; i < len(words) && strings.HasPrefix(words[i], "-"); i++ {
+		if source == "" && (words[i] == PromptFromStdin || words[i] == PromptFromEditor) {
+			source = words[i]
+			continue
+		}
+		flags = append(flags, words[i])
+	}
+	if source == "" {
+		return "", "", intent
+	}
+	return source, strings.Join(flags, " "), strings.Join(words[i:], " ")
+}
+
+// ReadPrompt reads a question from r until EOF, keeping its line breaks
+func ReadPrompt(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the question: %w", err)
+	}
+	prompt := strings.TrimSpace(string(data))
+	if prompt == "" {
+		return "", ErrEmptyPrompt
+	}
+	return prompt, nil
+}
+
+// ComposePrompt opens $VISUAL or $EDITOR on a file holding draft and
+// returns the question written there
+func ComposePrompt(draft string) (string, error) {
+	file, err := os.CreateTemp("", "lumo-question-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a file to edit: %w", err)
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	template := fmt.Sprintf("%s\n\n%s\n# Write your question above this line; everything from it on is ignored.\n# Save and close the editor to send it, or leave it empty to cancel.\n",
+		draft, composeScissors)
+	if _, err := file.WriteString(strings.TrimLeft(template, "\n")); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write the draft: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write the draft: %w", err)
+	}
+
+	editor := strings.Fields(editorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the question: %w", err)
+	}
+	prompt, _, _ := strings.Cut(string(data), composeScissors)
+	if prompt = strings.TrimSpace(prompt); prompt == "" {
+		return "", ErrEmptyPrompt
+	}
+	return prompt, nil
+}
+
+// editorCommand returns the user's editor, falling back to one every
+// system has
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
@@ -0,0 +1,39 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// SetOutputFile sends the output of successful commands to a file instead
+// of stdout. The file is replaced unless appendOutput is set; later output
+// in the same session is always appended.
+func (t *Terminal) SetOutputFile(path string, appendOutput bool) {
+	t.outPath = path
+	t.outAppend = appendOutput
+}
+
+// writeOutput writes output to the output file, without escape sequences
+func (t *Terminal) writeOutput(output string) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if t.outAppend {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(t.outPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+
+	output = utils.StripANSI(output)
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	if _, err := file.WriteString(output); err != nil {
+		file.Close()
+		return err
+	}
+	t.outAppend = true
+	return file.Close()
+}
@@ -4,11 +4,15 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/agnath18K/lumo/pkg/a11y"
 	"github.com/agnath18K/lumo/pkg/config"
 	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/privacy"
 )
 
 // Terminal handles terminal interaction
@@ -16,15 +20,17 @@ type Terminal struct {
 	config         *config.Config
 	commandHistory []string
 	historyFile    string
+	// outPath, if set, is the file successful output goes to
+	outPath   string
+	outAppend bool
 }
 
 // NewTerminal creates a new terminal instance
 func NewTerminal(cfg *config.Config) *Terminal {
 	// Set history file path
-	homeDir, err := os.UserHomeDir()
 	historyFile := ".lumo_history"
-	if err == nil {
-		historyFile = homeDir + "/.lumo_history"
+	if dir, err := paths.StateDir(); err == nil {
+		historyFile = filepath.Join(dir, "history")
 	}
 
 	return &Terminal{
@@ -66,13 +72,31 @@ func (t *Terminal) StartInteractiveMode(handler func(string)) {
 	t.saveHistory()
 }
 
-// Display shows the result of a command execution
+// Display shows the result of a command execution. With an output file,
+// successful output goes to the file and errors still go to stderr. In
+// accessible mode the output is linearized for screen readers.
 func (t *Terminal) Display(result *executor.Result) {
+	output := result.Output
+	if t.config.Accessible() {
+		output = a11y.Linearize(output)
+	}
+
 	if result.IsError {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", result.Output)
-	} else {
-		fmt.Println(result.Output)
+		fmt.Fprintf(os.Stderr, "Error: %s\n", output)
+		return
+	}
+
+	if t.outPath != "" {
+		err := t.writeOutput(output)
+		if err == nil {
+			if !t.config.Quiet {
+				fmt.Fprintf(os.Stderr, "📄 Output written to %s\n", t.outPath)
+			}
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error writing to %s: %v\n", t.outPath, err)
 	}
+	fmt.Println(output)
 }
 
 // addToHistory adds a command to the history
@@ -109,6 +133,10 @@ func (t *Terminal) loadHistory() {
 
 // saveHistory saves command history to file
 func (t *Terminal) saveHistory() {
+	if err := os.MkdirAll(filepath.Dir(t.historyFile), 0700); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving history: %v\n", err)
+		return
+	}
 	file, err := os.Create(t.historyFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error saving history: %v\n", err)
@@ -128,18 +156,25 @@ func (t *Terminal) GetCommandHistory() []string {
 
 // LogCommand logs a command and its result
 func (t *Terminal) LogCommand(cmd string, result *executor.Result, duration time.Duration) {
-	if !t.config.EnableLogging {
+	// Strict privacy mode keeps prompts out of log files
+	if !t.config.EnableLogging || privacy.IsStrict(t.config.PrivacyMode) {
 		return
 	}
 
 	// Create logs directory if it doesn't exist
-	if err := os.MkdirAll("logs", 0755); err != nil {
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding logs directory: %v\n", err)
+		return
+	}
+	logsDir := filepath.Join(stateDir, "logs")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating logs directory: %v\n", err)
 		return
 	}
 
 	// Open log file
-	logFile := fmt.Sprintf("logs/lumo_%s.log", time.Now().Format("2006-01-02"))
+	logFile := filepath.Join(logsDir, fmt.Sprintf("lumo_%s.log", time.Now().Format("2006-01-02")))
 	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error opening log file: %v\n", err)
@@ -0,0 +1,261 @@
+// Package timetrack keeps a log of the application in focus, sampled by the
+// daemon, for the daily summaries of 'lumo today'. Tracking is off until the
+// user turns it on. Samples hold the application's name but not window
+// titles, are only written to the state directory, and are never sent
+// anywhere.
+package timetrack
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// DeepFocus is how long one application must stay in focus for the stretch
+// to count as a deep focus session
+const DeepFocus = 25 * time.Minute
+
+// Sample is one look at the application in focus, which is credited with
+// the time until the next look
+type Sample struct {
+	Time    time.Time `json:"time"`
+	App     string    `json:"app"`
+	Seconds int       `json:"seconds"`
+}
+
+// Duration returns the time the sample credits to its application
+func (s Sample) Duration() time.Duration {
+	return time.Duration(s.Seconds) * time.Second
+}
+
+// Path returns the file the samples are kept in
+func Path() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timetrack.jsonl"), nil
+}
+
+// Record adds a sample to the log
+func Record(sample Sample) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open time tracking log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write time tracking log: %w", err)
+	}
+	return file.Close()
+}
+
+// Load returns the samples taken at or after since and before until. A
+// missing log is empty, and lines that cannot be read are skipped.
+func Load(since, until time.Time) ([]Sample, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open time tracking log: %w", err)
+	}
+	defer file.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		if !sample.Time.Before(since) && sample.Time.Before(until) {
+			samples = append(samples, sample)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read time tracking log: %w", err)
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+	return samples, nil
+}
+
+// LoadDay returns the samples taken on the day of t, in t's time zone
+func LoadDay(t time.Time) ([]Sample, error) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return Load(start, start.AddDate(0, 0, 1))
+}
+
+// Purge deletes the log and returns how many samples it held
+func Purge() (int, error) {
+	samples, err := Load(time.Time{}, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		return 0, err
+	}
+	path, err := Path()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to delete the time tracking log: %w", err)
+	}
+	return len(samples), nil
+}
+
+// AppName turns the application a desktop backend reports, such as
+// "org.gnome.Nautilus.desktop", into the name samples are kept under
+func AppName(app string) string {
+	app = strings.TrimSpace(strings.TrimSuffix(app, ".desktop"))
+	// Reverse-DNS application IDs end with the application's name
+	if strings.Count(app, ".") >= 2 && !strings.ContainsAny(app, " /") {
+		app = app[strings.LastIndex(app, ".")+1:]
+	}
+	return app
+}
+
+// Excluded reports whether an application is on the exclusion list, which
+// is matched without regard to case
+func Excluded(app string, exclude []string) bool {
+	for _, excluded := range exclude {
+		if strings.EqualFold(AppName(excluded), app) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppTime is the time one application was in focus
+type AppTime struct {
+	App      string        `json:"app"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Summary adds up the samples of a day
+type Summary struct {
+	// First and Last are when the first and last samples were taken
+	First time.Time     `json:"first"`
+	Last  time.Time     `json:"last"`
+	Total time.Duration `json:"total"`
+	// Apps are the applications in focus, the longest first
+	Apps []AppTime `json:"apps"`
+	// Switches counts the changes from one application to another
+	Switches int `json:"switches"`
+	// LongestApp was in focus for the longest unbroken stretch, Longest
+	LongestApp string        `json:"longest_app"`
+	Longest    time.Duration `json:"longest"`
+	// DeepFocusSessions counts the stretches of at least DeepFocus
+	DeepFocusSessions int `json:"deep_focus_sessions"`
+}
+
+// Summarize adds up samples, sorted by time, leaving out the applications
+// on the exclusion list. A stretch of focus ends when the application
+// changes or when the samples stop, such as while the user was idle.
+func Summarize(samples []Sample, exclude []string) Summary {
+	var summary Summary
+	byApp := make(map[string]time.Duration)
+
+	var stretchApp string
+	var stretch time.Duration
+	var stretchEnd time.Time
+	endStretch := func() {
+		if stretch > summary.Longest {
+			summary.Longest, summary.LongestApp = stretch, stretchApp
+		}
+		if stretch >= DeepFocus {
+			summary.DeepFocusSessions++
+		}
+		stretch = 0
+	}
+
+	for _, sample := range samples {
+		if Excluded(sample.App, exclude) {
+			continue
+		}
+		if summary.First.IsZero() {
+			summary.First = sample.Time
+		}
+		summary.Last = sample.Time
+		summary.Total += sample.Duration()
+		byApp[sample.App] += sample.Duration()
+
+		// Allow some slack for late samples before calling it a gap
+		contiguous := !stretchEnd.IsZero() && sample.Time.Sub(stretchEnd) <= sample.Duration()
+		if stretchApp != "" && contiguous && sample.App != stretchApp {
+			summary.Switches++
+		}
+		if sample.App != stretchApp || !contiguous {
+			endStretch()
+			stretchApp = sample.App
+		}
+		stretch += sample.Duration()
+		stretchEnd = sample.Time.Add(sample.Duration())
+	}
+	endStretch()
+
+	for app, duration := range byApp {
+		summary.Apps = append(summary.Apps, AppTime{App: app, Duration: duration})
+	}
+	sort.Slice(summary.Apps, func(i, j int) bool {
+		if summary.Apps[i].Duration != summary.Apps[j].Duration {
+			return summary.Apps[i].Duration > summary.Apps[j].Duration
+		}
+		return summary.Apps[i].App < summary.Apps[j].App
+	})
+	return summary
+}
+
+// WriteCSV writes a day's summary as CSV, one row per application with the
+// date, the application, the minutes it was in focus, and its share of the
+// day
+func WriteCSV(w io.Writer, day time.Time, summary Summary) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "app", "minutes", "percent"}); err != nil {
+		return err
+	}
+	date := day.Format("2006-01-02")
+	for _, app := range summary.Apps {
+		percent := 0.0
+		if summary.Total > 0 {
+			percent = 100 * float64(app.Duration) / float64(summary.Total)
+		}
+		if err := writer.Write([]string{
+			date,
+			app.App,
+			strconv.FormatFloat(app.Duration.Minutes(), 'f', 1, 64),
+			strconv.FormatFloat(percent, 'f', 1, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,91 @@
+package timetrack
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// SampleInterval is how often the tracker looks at the application in focus
+const SampleInterval = 30 * time.Second
+
+// ActiveAppFunc returns the application in focus, or "" if there is none
+type ActiveAppFunc func(ctx context.Context) (string, error)
+
+// IdleFunc reports how long the user has been idle
+type IdleFunc func(ctx context.Context) (time.Duration, error)
+
+// Tracker samples the application in focus and records it. Nothing is
+// recorded while the user is idle, or for applications on the exclusion
+// list.
+type Tracker struct {
+	active        ActiveAppFunc
+	idle          IdleFunc
+	idleThreshold time.Duration
+	exclude       []string
+	interval      time.Duration
+	record        func(Sample) error
+}
+
+// NewTracker creates a tracker. idle may be nil when the desktop backend
+// cannot tell, in which case every sample counts.
+func NewTracker(active ActiveAppFunc, idle IdleFunc, idleThreshold time.Duration, exclude []string) *Tracker {
+	return &Tracker{
+		active:        active,
+		idle:          idle,
+		idleThreshold: idleThreshold,
+		exclude:       exclude,
+		interval:      SampleInterval,
+		record:        Record,
+	}
+}
+
+// SetRecorder replaces how samples are recorded, which is appending them to
+// the log by default
+func (t *Tracker) SetRecorder(record func(Sample) error) {
+	t.record = record
+}
+
+// Start samples until the context is cancelled
+func (t *Tracker) Start(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	// Log a failure once, not on every sample, until sampling works again
+	var failure string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			err := t.Sample(ctx, now)
+			if err != nil && err.Error() != failure {
+				log.Printf("Time tracking failed: %v", err)
+			}
+			failure = ""
+			if err != nil {
+				failure = err.Error()
+			}
+		}
+	}
+}
+
+// Sample records the application in focus at now, crediting it with one
+// interval
+func (t *Tracker) Sample(ctx context.Context, now time.Time) error {
+	if t.idle != nil && t.idleThreshold > 0 {
+		if idle, err := t.idle(ctx); err == nil && idle >= t.idleThreshold {
+			return nil
+		}
+	}
+
+	app, err := t.active(ctx)
+	if err != nil {
+		return err
+	}
+	app = AppName(app)
+	if app == "" || Excluded(app, t.exclude) {
+		return nil
+	}
+	return t.record(Sample{Time: now, App: app, Seconds: int(t.interval / time.Second)})
+}
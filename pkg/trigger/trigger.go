@@ -0,0 +1,280 @@
+// Package trigger keeps the commands the daemon runs when files appear or
+// change in watched directories, such as summarizing each PDF saved to
+// ~/Downloads, and watches the directories for them.
+package trigger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/shellwords"
+)
+
+// DefaultDebounce is how long a file must stay unchanged before a trigger
+// runs for it, so a download or a save in progress is not picked up early
+const DefaultDebounce = 5 * time.Second
+
+// FilePlaceholder is replaced with the path of the file in a trigger's
+// command, and NamePlaceholder with its name. Both are quoted when they need
+// to be, so they should not be quoted in the command.
+const (
+	FilePlaceholder = "{file}"
+	NamePlaceholder = "{name}"
+)
+
+// validName matches trigger names
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Trigger runs a lumo command for each file created or changed in a
+// directory
+type Trigger struct {
+	Name string `json:"name"`
+	// Watch is the absolute path of the watched directory. Only the files
+	// directly in it are watched, not those in subdirectories.
+	Watch string `json:"watch"`
+	// Glob matches the names of the files the trigger runs for; empty
+	// matches every file
+	Glob string `json:"glob,omitempty"`
+	// Run is the lumo command to run, with {file} and {name} filled in
+	Run string `json:"run"`
+	// DebounceSeconds overrides DefaultDebounce when set
+	DebounceSeconds int       `json:"debounce_seconds,omitempty"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// triggersPath returns the file the triggers are kept in
+func triggersPath() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "triggers.json"), nil
+}
+
+// Load returns every trigger, in the order they were added
+func Load() ([]*Trigger, error) {
+	path, err := triggersPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var triggers []*Trigger
+	if err := json.Unmarshal(data, &triggers); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	sort.SliceStable(triggers, func(i, j int) bool {
+		return triggers[i].CreatedAt.Before(triggers[j].CreatedAt)
+	})
+	return triggers, nil
+}
+
+// save writes the triggers, replacing the file so the daemon never reads
+// it half-written
+func save(triggers []*Trigger) error {
+	path, err := triggersPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(triggers, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to save triggers: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Get returns the trigger with the given name
+func Get(name string) (*Trigger, error) {
+	triggers, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range triggers {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("no trigger named %q", name)
+}
+
+// Validate checks a new trigger and fills in its name, if it has none,
+// from the watched directory
+func (t *Trigger) Validate() error {
+	if t.Watch == "" {
+		return fmt.Errorf("a directory to watch is required")
+	}
+	if !filepath.IsAbs(t.Watch) {
+		return fmt.Errorf("the watched directory must be an absolute path: %s", t.Watch)
+	}
+	if info, err := os.Stat(t.Watch); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", t.Watch)
+	}
+	if _, err := filepath.Match(t.Glob, ""); err != nil {
+		return fmt.Errorf("invalid glob %q: %w", t.Glob, err)
+	}
+	if strings.TrimSpace(t.Run) == "" {
+		return fmt.Errorf("a command to run is required")
+	}
+	if t.DebounceSeconds < 0 {
+		return fmt.Errorf("the debounce cannot be negative")
+	}
+
+	if t.Name == "" {
+		triggers, err := Load()
+		if err != nil {
+			return err
+		}
+		t.Name = uniqueName(defaultName(t.Watch), triggers)
+	}
+	if !validName.MatchString(t.Name) {
+		return fmt.Errorf("invalid trigger name %q: use up to 64 letters, digits, dots, dashes, and underscores", t.Name)
+	}
+	return nil
+}
+
+// defaultName derives a trigger's name from the directory it watches
+func defaultName(dir string) string {
+	name := strings.ToLower(filepath.Base(dir))
+	name = regexp.MustCompile(`[^a-z0-9._-]+`).ReplaceAllString(name, "-")
+	name = strings.TrimLeft(name, "._-")
+	if len(name) > 56 {
+		name = name[:56]
+	}
+	if name == "" {
+		return "trigger"
+	}
+	return name
+}
+
+// uniqueName returns name, or name with a number added if a trigger has it
+func uniqueName(name string, triggers []*Trigger) string {
+	taken := make(map[string]bool, len(triggers))
+	for _, t := range triggers {
+		taken[t.Name] = true
+	}
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	return candidate
+}
+
+// Add validates a trigger and saves it, enabled
+func Add(t *Trigger) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	triggers, err := Load()
+	if err != nil {
+		return err
+	}
+	for _, existing := range triggers {
+		if existing.Name == t.Name {
+			return fmt.Errorf("a trigger named %q already exists", t.Name)
+		}
+	}
+	t.Enabled = true
+	t.CreatedAt = time.Now()
+	return save(append(triggers, t))
+}
+
+// Remove deletes the trigger with the given name
+func Remove(name string) error {
+	return update(name, func(triggers []*Trigger, i int) []*Trigger {
+		return append(triggers[:i], triggers[i+1:]...)
+	})
+}
+
+// SetEnabled turns the trigger with the given name on or off
+func SetEnabled(name string, enabled bool) error {
+	return update(name, func(triggers []*Trigger, i int) []*Trigger {
+		triggers[i].Enabled = enabled
+		return triggers
+	})
+}
+
+// update changes the triggers with change, given the index of the one with
+// the given name, and saves them
+func update(name string, change func(triggers []*Trigger, i int) []*Trigger) error {
+	triggers, err := Load()
+	if err != nil {
+		return err
+	}
+	for i, t := range triggers {
+		if t.Name == name {
+			return save(change(triggers, i))
+		}
+	}
+	return fmt.Errorf("no trigger named %q", name)
+}
+
+// Debounce returns how long a file must stay unchanged before the trigger
+// runs for it
+func (t *Trigger) Debounce() time.Duration {
+	if t.DebounceSeconds > 0 {
+		return time.Duration(t.DebounceSeconds) * time.Second
+	}
+	return DefaultDebounce
+}
+
+// Matches reports whether the trigger runs for a file with the given name
+func (t *Trigger) Matches(name string) bool {
+	if t.Glob == "" {
+		return true
+	}
+	matched, _ := filepath.Match(t.Glob, name)
+	return matched
+}
+
+// Command returns the command the trigger runs for the file at path, which
+// is quoted so a name with spaces stays one argument
+func (t *Trigger) Command(path string) string {
+	return strings.NewReplacer(
+		FilePlaceholder, shellwords.Quote(path),
+		NamePlaceholder, shellwords.Quote(filepath.Base(path)),
+	).Replace(t.Run)
+}
+
+// Files returns the paths of the files in the watched directory that the
+// trigger matches, sorted by name
+func (t *Trigger) Files() ([]string, error) {
+	current, err := currentFiles(t)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(current))
+	for path := range current {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Describe summarizes what the trigger watches for
+func (t *Trigger) Describe() string {
+	glob := t.Glob
+	if glob == "" {
+		glob = "any file"
+	}
+	return fmt.Sprintf("%s in %s, once unchanged for %s", glob, t.Watch, t.Debounce())
+}
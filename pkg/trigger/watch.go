@@ -0,0 +1,165 @@
+package trigger
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// PollInterval is how often the watcher scans the watched directories
+const PollInterval = 2 * time.Second
+
+// RunFunc runs a trigger for the file at path
+type RunFunc func(ctx context.Context, t *Trigger, path string)
+
+// fileState is what the watcher last saw of a file
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// watchState is what the watcher knows of one trigger's directory
+type watchState struct {
+	// watch and glob are those the files were scanned with, so a trigger
+	// changed to watch something else starts over
+	watch string
+	glob  string
+	files map[string]fileState
+	// pending holds when each file created or changed since the last run
+	// last changed
+	pending map[string]time.Time
+}
+
+// Watcher scans the directories of the enabled triggers and runs each
+// trigger for the files created or changed in its directory once they stay
+// unchanged for its debounce. Files already there when a trigger is added,
+// enabled, or first scanned do not run it. Triggers are reloaded on every
+// scan, so changes take effect without restarting the daemon.
+type Watcher struct {
+	run      RunFunc
+	interval time.Duration
+	states   map[string]*watchState
+}
+
+// NewWatcher creates a watcher that runs triggers with run
+func NewWatcher(run RunFunc) *Watcher {
+	return &Watcher{
+		run:      run,
+		interval: PollInterval,
+		states:   make(map[string]*watchState),
+	}
+}
+
+// Start scans until the context is cancelled
+func (w *Watcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.Scan(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.Scan(ctx, now)
+		}
+	}
+}
+
+// Scan looks for changes in the watched directories and runs the triggers
+// whose files have settled by now, one at a time
+func (w *Watcher) Scan(ctx context.Context, now time.Time) {
+	triggers, err := Load()
+	if err != nil {
+		log.Printf("Failed to load triggers: %v", err)
+		return
+	}
+
+	active := make(map[string]bool, len(triggers))
+	for _, t := range triggers {
+		if !t.Enabled {
+			continue
+		}
+		active[t.Name] = true
+		for _, path := range w.scan(t, now) {
+			if ctx.Err() != nil {
+				return
+			}
+			w.run(ctx, t, path)
+		}
+	}
+
+	// Forget removed and disabled triggers, so enabling one again does not
+	// run it for what changed in the meantime
+	for name := range w.states {
+		if !active[name] {
+			delete(w.states, name)
+		}
+	}
+}
+
+// scan updates what the watcher knows of a trigger's directory and returns
+// the files that have settled
+func (w *Watcher) scan(t *Trigger, now time.Time) []string {
+	files, err := currentFiles(t)
+	if err != nil {
+		log.Printf("Trigger %s cannot read %s: %v", t.Name, t.Watch, err)
+		return nil
+	}
+
+	state := w.states[t.Name]
+	if state == nil || state.watch != t.Watch || state.glob != t.Glob {
+		w.states[t.Name] = &watchState{
+			watch:   t.Watch,
+			glob:    t.Glob,
+			files:   files,
+			pending: make(map[string]time.Time),
+		}
+		return nil
+	}
+
+	for path, current := range files {
+		if previous, ok := state.files[path]; !ok || previous != current {
+			state.pending[path] = now
+		}
+	}
+	for path := range state.pending {
+		if _, ok := files[path]; !ok {
+			delete(state.pending, path)
+		}
+	}
+	state.files = files
+
+	var settled []string
+	for path, changed := range state.pending {
+		if now.Sub(changed) >= t.Debounce() {
+			settled = append(settled, path)
+			delete(state.pending, path)
+		}
+	}
+	sort.Strings(settled)
+	return settled
+}
+
+// currentFiles returns the state of the files a trigger matches
+func currentFiles(t *Trigger) (map[string]fileState, error) {
+	entries, err := os.ReadDir(t.Watch)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]fileState)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !t.Matches(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files[filepath.Join(t.Watch, entry.Name())] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+	return files, nil
+}
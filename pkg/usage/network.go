@@ -0,0 +1,175 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// What network traffic is counted for
+const (
+	// NetworkConnect is files sent and received with connect, including
+	// pushes to SFTP servers and WebDAV shares
+	NetworkConnect = "connect"
+	// NetworkAI is requests to AI providers and their answers
+	NetworkAI = "ai"
+)
+
+// NetworkEntry is the traffic of one transfer or AI request
+type NetworkEntry struct {
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"`
+	Sent     int64     `json:"sent"`
+	Received int64     `json:"received"`
+}
+
+// NetworkPath returns the file the network log is kept in
+func NetworkPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "network.jsonl"), nil
+}
+
+// RecordNetwork adds traffic to the network log
+func RecordNetwork(source string, sent, received int64) error {
+	if sent <= 0 && received <= 0 {
+		return nil
+	}
+	path, err := NetworkPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(NetworkEntry{
+		Time:     time.Now(),
+		Source:   source,
+		Sent:     max(sent, 0),
+		Received: max(received, 0),
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open network log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write network log: %w", err)
+	}
+	return file.Close()
+}
+
+// LoadNetwork returns the traffic in the network log at or after since. A
+// missing log is empty, and lines that cannot be read are skipped.
+func LoadNetwork(since time.Time) ([]NetworkEntry, error) {
+	path, err := NetworkPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open network log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []NetworkEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry NetworkEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read network log: %w", err)
+	}
+	return entries, nil
+}
+
+// NetworkTotal sums the traffic of one source
+type NetworkTotal struct {
+	Source   string `json:"source"`
+	Sent     int64  `json:"sent"`
+	Received int64  `json:"received"`
+}
+
+// NetworkDay is the traffic of one day
+type NetworkDay struct {
+	// Date is the day, as YYYY-MM-DD
+	Date     string `json:"date"`
+	Sent     int64  `json:"sent"`
+	Received int64  `json:"received"`
+	// Sources are the day's totals by source, sorted by name
+	Sources []NetworkTotal `json:"sources"`
+}
+
+// SummarizeNetwork adds up entries by day, in their own time zone, oldest
+// first
+func SummarizeNetwork(entries []NetworkEntry) []NetworkDay {
+	byDay := make(map[string]map[string]*NetworkTotal)
+	for _, entry := range entries {
+		date := entry.Time.Format("2006-01-02")
+		sources, ok := byDay[date]
+		if !ok {
+			sources = make(map[string]*NetworkTotal)
+			byDay[date] = sources
+		}
+		total, ok := sources[entry.Source]
+		if !ok {
+			total = &NetworkTotal{Source: entry.Source}
+			sources[entry.Source] = total
+		}
+		total.Sent += entry.Sent
+		total.Received += entry.Received
+	}
+
+	days := make([]NetworkDay, 0, len(byDay))
+	for date, sources := range byDay {
+		day := NetworkDay{Date: date}
+		for _, total := range sources {
+			day.Sent += total.Sent
+			day.Received += total.Received
+			day.Sources = append(day.Sources, *total)
+		}
+		sort.Slice(day.Sources, func(i, j int) bool {
+			return day.Sources[i].Source < day.Sources[j].Source
+		})
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// MonthNetwork returns the bytes sent and received this month, together
+func MonthNetwork(now time.Time) (int64, error) {
+	entries, err := LoadNetwork(StartOfMonth(now))
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.Sent + entry.Received
+	}
+	return total, nil
+}
@@ -0,0 +1,222 @@
+// Package usage keeps a log of the tokens sent to and received from AI
+// providers, and estimates what they cost.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// Entry is the token count of one request to an AI provider
+type Entry struct {
+	Time             time.Time `json:"time"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+}
+
+// price is what a model costs in US dollars per million tokens
+type price struct {
+	model  string
+	prompt float64
+	output float64
+}
+
+// prices are the list prices of cloud models, matched by the longest model
+// name prefix. Providers without prices, like Ollama, run locally for free.
+var prices = map[string][]price{
+	"openai": {
+		{"gpt-3.5-turbo", 0.50, 1.50},
+		{"gpt-4", 30.00, 60.00},
+		{"gpt-4-turbo", 10.00, 30.00},
+		{"gpt-4o", 2.50, 10.00},
+		{"gpt-4o-mini", 0.15, 0.60},
+		{"gpt-4.1", 2.00, 8.00},
+		{"gpt-4.1-mini", 0.40, 1.60},
+		{"gpt-4.1-nano", 0.10, 0.40},
+		{"o3-mini", 1.10, 4.40},
+		{"o4-mini", 1.10, 4.40},
+	},
+	"gemini": {
+		{"gemini-1.5-flash", 0.075, 0.30},
+		{"gemini-1.5-pro", 1.25, 5.00},
+		{"gemini-2.0-flash", 0.10, 0.40},
+		{"gemini-2.0-flash-lite", 0.075, 0.30},
+		{"gemini-2.5-flash", 0.30, 2.50},
+		{"gemini-2.5-pro", 1.25, 10.00},
+	},
+}
+
+// Cost estimates what a request cost in US dollars. It reports false for
+// cloud models without a known price.
+func (e Entry) Cost() (float64, bool) {
+	models, ok := prices[e.Provider]
+	if !ok {
+		return 0, true
+	}
+
+	var match *price
+	for i := range models {
+		if strings.HasPrefix(e.Model, models[i].model) && (match == nil || len(models[i].model) > len(match.model)) {
+			match = &models[i]
+		}
+	}
+	if match == nil {
+		return 0, false
+	}
+	return (float64(e.PromptTokens)*match.prompt + float64(e.CompletionTokens)*match.output) / 1e6, true
+}
+
+// Path returns the file the usage log is kept in
+func Path() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
+
+// Record adds a request to the usage log
+func Record(provider, model string, promptTokens, completionTokens int) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(Entry{
+		Time:             time.Now(),
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write usage log: %w", err)
+	}
+	return file.Close()
+}
+
+// Load returns the requests in the usage log made at or after since. A
+// missing log is empty, and lines that cannot be read are skipped.
+func Load(since time.Time) ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+	return entries, nil
+}
+
+// Total sums the requests made to one provider and model
+type Total struct {
+	Provider         string
+	Model            string
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	// Cost is the estimated cost in US dollars, and Priced is false when
+	// it leaves out requests to models without a known price
+	Cost   float64
+	Priced bool
+}
+
+// Summarize adds up entries by provider and model, sorted by provider and
+// model name
+func Summarize(entries []Entry) []Total {
+	byModel := make(map[string]*Total)
+	var totals []*Total
+	for _, entry := range entries {
+		key := entry.Provider + "\x00" + entry.Model
+		total, ok := byModel[key]
+		if !ok {
+			total = &Total{Provider: entry.Provider, Model: entry.Model, Priced: true}
+			byModel[key] = total
+			totals = append(totals, total)
+		}
+		total.Requests++
+		total.PromptTokens += entry.PromptTokens
+		total.CompletionTokens += entry.CompletionTokens
+		cost, priced := entry.Cost()
+		total.Cost += cost
+		total.Priced = total.Priced && priced
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Provider != totals[j].Provider {
+			return totals[i].Provider < totals[j].Provider
+		}
+		return totals[i].Model < totals[j].Model
+	})
+	result := make([]Total, len(totals))
+	for i, total := range totals {
+		result[i] = *total
+	}
+	return result
+}
+
+// StartOfDay returns when the day of t began, in t's time zone
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfMonth returns when the month of t began, in t's time zone
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// MonthCost returns the estimated cost of this month's requests in US dollars
+func MonthCost(now time.Time) (float64, error) {
+	entries, err := Load(StartOfMonth(now))
+	if err != nil {
+		return 0, err
+	}
+	cost := 0.0
+	for _, entry := range entries {
+		c, _ := entry.Cost()
+		cost += c
+	}
+	return cost, nil
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// powerShellNames are the PowerShell executables to look for, Windows PowerShell first
+var powerShellNames = []string{"powershell", "pwsh"}
+
+// PowerShellPath returns the path of an installed PowerShell, or an empty string
+func PowerShellPath() string {
+	for _, name := range powerShellNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// RunPowerShell runs a PowerShell script with UTF-8 input and output and
+// returns what it printed. stdin may be nil.
+func RunPowerShell(script string, stdin io.Reader) ([]byte, error) {
+	path := PowerShellPath()
+	if path == "" {
+		return nil, fmt.Errorf("PowerShell is not installed")
+	}
+
+	// Without this, non-ASCII text is mangled by the console code page
+	script = "[Console]::InputEncoding = [Text.Encoding]::UTF8; [Console]::OutputEncoding = [Text.Encoding]::UTF8; " + script
+
+	cmd := exec.Command(path, "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return output, nil
+}
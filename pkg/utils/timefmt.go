@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Time styles for times shown in output
+const (
+	// TimeStyleLocal shows relative times ("3m ago") and timestamps in the
+	// local timezone, for people
+	TimeStyleLocal = "local"
+	// TimeStyleISO shows ISO-8601 timestamps everywhere, for scripts
+	TimeStyleISO = "iso"
+)
+
+// localTimeLayout is how timestamps are shown in the local style
+const localTimeLayout = "2006-01-02 15:04:05 MST"
+
+var (
+	timeStyleMu sync.RWMutex
+	timeStyle   = TimeStyleLocal
+)
+
+// SetTimeStyle chooses how times are shown, TimeStyleLocal or TimeStyleISO.
+// Unknown styles are treated as TimeStyleLocal.
+func SetTimeStyle(style string) {
+	if style != TimeStyleISO {
+		style = TimeStyleLocal
+	}
+	timeStyleMu.Lock()
+	defer timeStyleMu.Unlock()
+	timeStyle = style
+}
+
+// TimeStyle returns how times are shown
+func TimeStyle() string {
+	timeStyleMu.RLock()
+	defer timeStyleMu.RUnlock()
+	return timeStyle
+}
+
+// FormatTimestamp formats a time as a timestamp in the local timezone, or
+// as ISO-8601 in the ISO style
+func FormatTimestamp(t time.Time) string {
+	if TimeStyle() == TimeStyleISO {
+		return t.Local().Format(time.RFC3339)
+	}
+	return t.Local().Format(localTimeLayout)
+}
+
+// FormatRelative formats a time relative to now, like "3m ago" or "in 2h".
+// Times more than a week away are shown as a local date, and the ISO style
+// always shows an ISO-8601 timestamp.
+func FormatRelative(t time.Time) string {
+	if TimeStyle() == TimeStyleISO {
+		return FormatTimestamp(t)
+	}
+
+	diff := time.Since(t)
+	future := diff < 0
+	if future {
+		diff = -diff
+	}
+
+	var amount string
+	switch {
+	case diff < time.Minute:
+		return "just now"
+	case diff < time.Hour:
+		amount = fmt.Sprintf("%dm", int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		amount = fmt.Sprintf("%dh", int(diff.Hours()))
+	case diff < 7*24*time.Hour:
+		amount = fmt.Sprintf("%dd", int(diff.Hours()/24))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// FormatWhen formats a time relative to now followed by its timestamp, like
+// "3m ago (2024-05-01 14:03:05 CEST)". The ISO style shows only the timestamp.
+func FormatWhen(t time.Time) string {
+	if TimeStyle() == TimeStyleISO {
+		return FormatTimestamp(t)
+	}
+	return fmt.Sprintf("%s (%s)", FormatRelative(t), FormatTimestamp(t))
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -64,49 +65,23 @@ func ExpandPath(path string) (string, error) {
 	return strings.Replace(path, "~", homeDir, 1), nil
 }
 
-// FormatTimeAgo formats a time as a human-readable "time ago" string
-func FormatTimeAgo(t time.Time) string {
-	now := time.Now()
-	diff := now.Sub(t)
-
-	if diff < time.Minute {
-		return "just now"
-	} else if diff < time.Hour {
-		minutes := int(diff.Minutes())
-		if minutes == 1 {
-			return "1 minute ago"
-		}
-		return fmt.Sprintf("%d minutes ago", minutes)
-	} else if diff < 24*time.Hour {
-		hours := int(diff.Hours())
-		if hours == 1 {
-			return "1 hour ago"
-		}
-		return fmt.Sprintf("%d hours ago", hours)
-	} else if diff < 48*time.Hour {
-		return "yesterday"
-	} else if diff < 7*24*time.Hour {
-		days := int(diff.Hours() / 24)
-		return fmt.Sprintf("%d days ago", days)
-	} else if diff < 30*24*time.Hour {
-		weeks := int(diff.Hours() / 24 / 7)
-		if weeks == 1 {
-			return "1 week ago"
-		}
-		return fmt.Sprintf("%d weeks ago", weeks)
-	} else if diff < 365*24*time.Hour {
-		months := int(diff.Hours() / 24 / 30)
-		if months == 1 {
-			return "1 month ago"
-		}
-		return fmt.Sprintf("%d months ago", months)
-	} else {
-		years := int(diff.Hours() / 24 / 365)
-		if years == 1 {
-			return "1 year ago"
-		}
-		return fmt.Sprintf("%d years ago", years)
+// DefaultDownloadDir returns the user's Downloads folder (%USERPROFILE%\Downloads
+// on Windows), falling back to the current directory when the home directory is unknown
+func DefaultDownloadDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "."
 	}
+	return filepath.Join(homeDir, "Downloads")
+}
+
+// ansiEscape matches terminal escape sequences: colors and cursor movement
+// (CSI), and titles and hyperlinks (OSC)
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// StripANSI removes terminal escape sequences from text
+func StripANSI(text string) string {
+	return ansiEscape.ReplaceAllString(text, "")
 }
 
 // CleanMarkdown removes markdown formatting from a string for cleaner terminal output
@@ -0,0 +1,257 @@
+// Package vcr records the HTTP requests lumo sends to AI providers, and
+// their responses, to cassette files, and replays them later without the
+// network. Cassettes make demos work offline and let a bug report carry
+// exactly what the provider answered. API keys are removed before anything
+// is written, so cassettes can be shared.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cassetteVersion is the format of the cassette files written
+const cassetteVersion = 1
+
+// Redacted replaces secrets in cassettes
+const Redacted = "REDACTED"
+
+// secretParams are URL query parameters that carry API keys
+var secretParams = []string{"key", "api_key", "apikey", "access_token", "token"}
+
+// secretPatterns match API keys that may appear in request and response bodies
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`sk-[A-Za-z0-9_\-]{16,}`),  // OpenAI
+	regexp.MustCompile(`AIza[0-9A-Za-z_\-]{30,}`), // Google
+}
+
+// Cassette is a recording of provider interactions
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request to a provider and its response
+type Interaction struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	Request    Request   `json:"request"`
+	Response   Response  `json:"response"`
+}
+
+// Request is a recorded request, without its headers, which carry API keys
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	if cassette.Version > cassetteVersion {
+		return nil, fmt.Errorf("cassette %s needs a newer version of lumo", path)
+	}
+	return &cassette, nil
+}
+
+// Save writes a cassette file, readable only by the user since it holds
+// prompts and answers
+func (c *Cassette) Save(path string) error {
+	c.Version = cassetteVersion
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Sanitize removes API keys from text
+func Sanitize(text string) string {
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, Redacted)
+	}
+	return text
+}
+
+// sanitizeURL removes API keys from a URL's query
+func sanitizeURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for _, param := range secretParams {
+		if query.Has(param) {
+			query.Set(param, Redacted)
+		}
+	}
+	clean.RawQuery = query.Encode()
+	clean.User = nil
+	return Sanitize(clean.String())
+}
+
+// readBody reads a request or response body and puts it back for the
+// next reader
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), err
+}
+
+// Recorder is an http.RoundTripper that sends requests on and records them
+// with their responses to a cassette file, saved after every interaction so
+// nothing is lost when lumo exits
+type Recorder struct {
+	path     string
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder writing to the cassette at path, sending
+// requests with next, or http.DefaultTransport when next is nil. An
+// existing cassette is replaced.
+func NewRecorder(path string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{path: path, next: next}
+	if err := r.cassette.Save(path); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RoundTrip sends a request and records it with its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		RecordedAt: time.Now(),
+		Request: Request{
+			Method: req.Method,
+			URL:    sanitizeURL(req.URL),
+			Body:   Sanitize(requestBody),
+		},
+		Response: Response{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        Sanitize(responseBody),
+		},
+	})
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Player is an http.RoundTripper that answers requests from a cassette
+// without the network. A request gets the first unused interaction with
+// the same method, URL, and body; failing that, the first unused one with
+// the same method and URL, since prompts include details such as the
+// working directory that differ between runs.
+type Player struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewPlayer creates a player answering from the cassette at path
+func NewPlayer(path string) (*Player, error) {
+	cassette, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Player{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// RoundTrip answers a request with its recorded response
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	method, target, body := req.Method, sanitizeURL(req.URL), Sanitize(body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	match := -1
+	for i, interaction := range p.interactions {
+		if p.used[i] || interaction.Request.Method != method || interaction.Request.URL != target {
+			continue
+		}
+		if interaction.Request.Body == body {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s", method, target)
+	}
+	p.used[match] = true
+
+	recorded := p.interactions[match].Response
+	header := make(http.Header)
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,199 @@
+// Package widget renders a one-line lumo status for tmux status lines and
+// shell prompts. The status is cached for a few seconds, since prompts run
+// it every time they are drawn.
+package widget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/daemon"
+	"github.com/agnath18K/lumo/pkg/paths"
+	"github.com/agnath18K/lumo/pkg/privacy"
+	"github.com/agnath18K/lumo/pkg/system"
+)
+
+// CacheTTL is how long a collected status is reused
+const CacheTTL = 30 * time.Second
+
+// cacheFileName is the file in CacheDir holding the last status
+const cacheFileName = "widget_status.json"
+
+// Formats of the status line
+const (
+	FormatPlain = "plain"
+	FormatTmux  = "tmux"
+	FormatJSON  = "json"
+)
+
+// Status is what the status line shows
+type Status struct {
+	// Provider is the AI provider commands use
+	Provider string `json:"provider"`
+	// Daemon reports whether the server daemon is running
+	Daemon bool `json:"daemon"`
+	// UnreadTransfers counts files received since 'connect:history' was shown
+	UnreadTransfers int `json:"unread_transfers"`
+	// Health is the worst status of the quick health check
+	Health system.HealthStatus `json:"health"`
+	// Time is when the status was collected
+	Time time.Time `json:"time"`
+}
+
+// Get returns the cached status if it is fresh and for the same provider,
+// and otherwise collects and caches a new one
+func Get(cfg *config.Config, now time.Time) *Status {
+	provider := effectiveProvider(cfg)
+	if cached, err := loadCache(); err == nil && cached.Provider == provider &&
+		now.Sub(cached.Time) >= 0 && now.Sub(cached.Time) < CacheTTL {
+		return cached
+	}
+
+	status := Collect(cfg, now)
+	// A prompt is better off with a stale status next time than an error
+	_ = saveCache(status)
+	return status
+}
+
+// Collect gathers the status without the cache. Checks that fail are left
+// at their zero values.
+func Collect(cfg *config.Config, now time.Time) *Status {
+	status := &Status{
+		Provider: effectiveProvider(cfg),
+		Time:     now,
+	}
+	if !cfg.Safe {
+		status.Daemon, _, _ = daemon.New(cfg).IsRunning()
+	}
+	status.UnreadTransfers, _ = connect.UnreadTransfers()
+	if health, err := system.NewHealthChecker().CheckHealthQuick(); err == nil {
+		status.Health = health.Status()
+	}
+	return status
+}
+
+// Format renders a status in one of the formats
+func Format(status *Status, format string) (string, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.Marshal(status)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+
+	case FormatPlain, FormatTmux:
+		daemonMark, daemonColor := "○", "colour244"
+		if status.Daemon {
+			daemonMark, daemonColor = "●", "green"
+		}
+		healthMark, healthColor := healthMark(status.Health)
+
+		parts := []string{
+			status.Provider,
+			colored(format, daemonColor, daemonMark),
+		}
+		if status.UnreadTransfers > 0 {
+			parts = append(parts, colored(format, "cyan", fmt.Sprintf("⇣%d", status.UnreadTransfers)))
+		}
+		if healthMark != "" {
+			parts = append(parts, colored(format, healthColor, healthMark))
+		}
+		return strings.Join(parts, " "), nil
+	}
+	return "", fmt.Errorf("unknown format: %s (use --tmux or --json)", format)
+}
+
+// healthMark returns the symbol and tmux color of a health status, or no
+// symbol when the health check failed
+func healthMark(status system.HealthStatus) (string, string) {
+	switch status {
+	case system.StatusHealthy:
+		return "✔", "green"
+	case system.StatusWarning:
+		return "⚠", "yellow"
+	case system.StatusCritical:
+		return "✖", "red"
+	}
+	return "", ""
+}
+
+// colored wraps text in tmux color markup for the tmux format
+func colored(format, color, text string) string {
+	if format != FormatTmux {
+		return text
+	}
+	return fmt.Sprintf("#[fg=%s]%s#[default]", color, text)
+}
+
+// effectiveProvider returns the provider commands use, which is always the
+// local one in strict privacy mode
+func effectiveProvider(cfg *config.Config) string {
+	if privacy.IsStrict(cfg.PrivacyMode) && !ai.IsLocal(cfg.AIProvider) {
+		return privacy.LocalProvider
+	}
+	return cfg.AIProvider
+}
+
+// cacheFile returns the path of the cached status
+func cacheFile() (string, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cacheFileName), nil
+}
+
+// loadCache reads the cached status
+func loadCache() (*Status, error) {
+	path, err := cacheFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// saveCache writes the status to the cache. Several prompts may refresh it
+// at once, so it is written to a temporary file and renamed into place.
+func saveCache(status *Status) error {
+	path, err := cacheFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), ".widget-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
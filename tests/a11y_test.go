@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/a11y"
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// TestLinearize tests rewriting decorated output for screen readers
+func TestLinearize(t *testing.T) {
+	input := "\n╭─────────────────── 📈 Local Metrics ────────────────────╮\n" +
+		"\n\n  • Collecting: \033[32mon\033[0m\n" +
+		"│ Steps: 2/3 successful          │\n" +
+		"┌─ Output ─────────────────────────\n" +
+		"│ name │ size │\n" +
+		"├──────┼──────┤\n" +
+		"└──────────────────────────────────\n" +
+		"╰──────────────────────────────────────────────────────────╯\n"
+	want := "Section: Local Metrics\n\n" +
+		"• Collecting: on\n" +
+		"Steps: 2/3 successful\n" +
+		"Section: Output\n" +
+		"name, size"
+
+	if got := a11y.Linearize(input); got != want {
+		t.Errorf("Linearize() =\n%s\nwant:\n%s", got, want)
+	}
+	if got := a11y.Linearize("plain text — kept as is"); got != "plain text — kept as is" {
+		t.Errorf("Expected plain text to be kept, got %q", got)
+	}
+}
+
+// TestAccessibleSetting tests switching accessible output on in the
+// configuration and for one run
+func TestAccessibleSetting(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	if cfg.Accessible() {
+		t.Fatal("Expected accessible output to be off by default")
+	}
+	if err := cfg.EnableFeature("accessibility"); err != nil || !cfg.Accessible() {
+		t.Fatalf("Expected the accessibility feature to turn it on (%v)", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// LUMO_A11Y overrides the configuration without being saved
+	t.Setenv("LUMO_A11Y", "0")
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Accessible() || !loaded.AccessibleOutput {
+		t.Errorf("Expected LUMO_A11Y=0 to turn it off for this run only")
+	}
+}
+
+// TestAccessibleAgentPrompts tests that agent questions are plain sentences
+func TestAccessibleAgentPrompts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AccessibleOutput = true
+
+	stdin, input, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	input.WriteString("yes\n")
+	input.Close()
+	stdout, output, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, output
+	feedback := agent.NewFeedback(cfg)
+	feedback.DisplayPlan(&agent.Plan{
+		Task:  &agent.Task{Description: "Clean up"},
+		Steps: []*agent.Step{{ID: 1, Command: "rm -rf build", Description: "Remove the build", IsCritical: true, Retries: 2}},
+	})
+	confirmed, err := feedback.ConfirmExecution()
+	os.Stdin, os.Stdout = oldStdin, oldStdout
+	output.Close()
+
+	if err != nil || !confirmed {
+		t.Fatalf("Expected yes to confirm, got %v (%v)", confirmed, err)
+	}
+	printed, _ := io.ReadAll(stdout)
+	for _, want := range []string{"Section: Clean up", "1. rm -rf build (critical) (retried up to 2 times)", "Do you want to execute this plan? Type yes or no: "} {
+		if !strings.Contains(string(printed), want) {
+			t.Errorf("Expected %q in:\n%s", want, printed)
+		}
+	}
+	if strings.ContainsAny(string(printed), "─╭│⚠🧐") {
+		t.Errorf("Expected no decorations in:\n%s", printed)
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/tests/mocks"
+)
+
+// TestDesktopActionParsing tests the text form of desktop action steps
+func TestDesktopActionParsing(t *testing.T) {
+	cmd := &core.Command{
+		Type:      core.CommandTypeNotification,
+		Action:    "send",
+		Target:    `Backup "nightly" finished`,
+		Arguments: map[string]interface{}{"body": "3 files can't be read"},
+	}
+	text := assistant.FormatAction(cmd)
+	parsed, spec, err := assistant.ParseAction(text)
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", text, err)
+	}
+	if parsed.Type != cmd.Type || parsed.Action != cmd.Action || parsed.Target != cmd.Target || parsed.Arguments["body"] != cmd.Arguments["body"] {
+		t.Errorf("Expected %q to parse back to %+v, got %+v", text, cmd, parsed)
+	}
+	if spec.ReadOnly {
+		t.Errorf("Expected sending a notification not to be read-only")
+	}
+
+	invalid := []string{
+		"desktop:system:shutdown",
+		"desktop:sound:set-volume",
+		"desktop:sound:get-volume 50",
+		"desktop:notification:send hi --urgency=high",
+		"desktop:notification:send 'unterminated",
+	}
+	for _, text := range invalid {
+		if _, _, err := assistant.ParseAction(text); err == nil {
+			t.Errorf("Expected %q to be rejected", text)
+		}
+	}
+}
+
+// TestDesktopActionSteps tests planning, checking, and simulating desktop action steps
+func TestDesktopActionSteps(t *testing.T) {
+	cfg := config.DefaultConfig()
+	aiClient := mocks.NewMockAIClient()
+	aiClient.CompletionResponse = `{"description": "quiet down", "steps": [
+  {"id": 1, "desktop": {"type": "sound", "action": "set-volume", "target": 20}, "description": "lower the volume"},
+  {"id": 2, "desktop": {"type": "focus", "action": "status"}, "description": "check focus mode"},
+  {"id": 3, "command": "echo done", "description": "finish"}
+]}`
+
+	plan, err := agent.NewPlanner(cfg, aiClient).CreatePlan(context.Background(), &agent.Task{Description: "quiet down"})
+	if err != nil {
+		t.Fatalf("CreatePlan failed: %v", err)
+	}
+	if plan.Steps[0].Command != "desktop:sound:set-volume 20" || plan.Steps[1].Command != "desktop:focus:status" {
+		t.Fatalf("Expected desktop steps in their text form, got %q and %q", plan.Steps[0].Command, plan.Steps[1].Command)
+	}
+
+	if agent.ReadOnlyViolation(plan.Steps[0].Command) == "" {
+		t.Errorf("Expected changing the volume to be refused in read-only mode")
+	}
+	if reason := agent.ReadOnlyViolation(plan.Steps[1].Command); reason != "" {
+		t.Errorf("Expected reporting focus mode to be allowed in read-only mode, refused: %s", reason)
+	}
+
+	report := agent.SimulatePlan(plan, t.TempDir())
+	if effects := report.Steps[0].Effects; len(effects) != 1 || effects[0].Kind != agent.EffectSystem {
+		t.Errorf("Expected changing the volume to be a system change, got %+v", effects)
+	}
+	if effects := report.Steps[1].Effects; len(effects) != 0 {
+		t.Errorf("Expected reporting focus mode to change nothing, got %+v", effects)
+	}
+
+	// An invalid desktop action fails without reaching the shell
+	step := &agent.Step{ID: 1, Command: "desktop:sound:set-volume"}
+	result, err := agent.NewExecutor(cfg, nil).ExecuteStep(context.Background(), step)
+	if err != nil {
+		t.Fatalf("ExecuteStep returned an error: %v", err)
+	}
+	if result.Success || !strings.Contains(result.Error.Error(), "needs a level") {
+		t.Errorf("Expected the invalid action to fail validation, got %v", result.Error)
+	}
+}
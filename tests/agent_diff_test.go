@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+)
+
+// TestDiffSteps tests diffing the steps of a plan before and after a refinement
+func TestDiffSteps(t *testing.T) {
+	oldSteps := []*agent.Step{
+		{ID: 1, Command: "mkdir backup", Description: "Create the backup directory"},
+		{ID: 2, Command: "cp *.log backup/", Description: "Copy the logs"},
+		{ID: 3, Command: "rm *.log", Description: "Remove the logs", IsCritical: true},
+	}
+	newSteps := []*agent.Step{
+		{ID: 1, Command: "mkdir backup", Description: "Create the backup directory"},
+		{ID: 2, Command: "cp -p *.log backup/", Description: "Copy the logs"},
+		{ID: 3, Command: "gzip backup/*.log", Description: "Compress the copies"},
+		{ID: 4, Command: "rm *.log", Description: "Delete the originals", IsCritical: true},
+	}
+
+	changes := agent.DiffSteps(oldSteps, newSteps)
+	kinds := make([]agent.ChangeKind, len(changes))
+	for i, change := range changes {
+		kinds[i] = change.Kind
+	}
+	want := []agent.ChangeKind{agent.StepUnchanged, agent.StepModified, agent.StepAdded, agent.StepModified}
+	if len(kinds) != len(want) {
+		t.Fatalf("Expected %d changes, got %d: %v", len(want), len(kinds), kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("Change %d: expected kind %d, got %d", i, want[i], kinds[i])
+		}
+	}
+
+	output := agent.FormatStepDiff(changes, false)
+	for _, line := range []string{
+		"  mkdir backup",
+		"- cp *.log backup/",
+		"~ cp -p *.log backup/",
+		"+ gzip backup/*.log",
+		"~ rm *.log [critical]",
+		"Remove the logs → Delete the originals",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", line, output)
+		}
+	}
+	if strings.Contains(output, "\033[") {
+		t.Errorf("Expected no color codes when color is disabled")
+	}
+
+	if agent.HasChanges(agent.DiffSteps(oldSteps, oldSteps)) {
+		t.Errorf("Expected no changes when diffing a plan against itself")
+	}
+}
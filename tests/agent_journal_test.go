@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// TestAgentJournalResume tests resuming a failed run from its journal without repeating completed steps
+func TestAgentJournalResume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "counter")
+	marker := filepath.Join(dir, "ready")
+	done := filepath.Join(dir, "done")
+
+	cfg := config.DefaultConfig()
+	journal, err := agent.NewJournal()
+	if err != nil {
+		t.Fatalf("NewJournal failed: %v", err)
+	}
+	if !agent.IsRunID(journal.ID()) {
+		t.Fatalf("Run ID %q is not recognized as one", journal.ID())
+	}
+
+	plan := &agent.Plan{
+		Task: &agent.Task{Description: "test resume"},
+		Steps: []*agent.Step{
+			{ID: 1, Command: "echo ran >> " + counter},
+			// Fails until the marker exists, stopping the run
+			{ID: 2, Command: "test -f " + marker, IsCritical: true},
+			{ID: 3, Command: "touch " + done},
+		},
+		Journal: journal,
+	}
+	executor := agent.NewExecutor(cfg, nil)
+	if _, err := executor.ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg)); err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+
+	run, err := agent.LoadRun(journal.ID())
+	if err != nil {
+		t.Fatalf("LoadRun failed: %v", err)
+	}
+	if run.Task != "test resume" || len(run.Plan.Steps) != 3 {
+		t.Fatalf("Expected the journaled plan, got task %q with %d steps", run.Task, len(run.Plan.Steps))
+	}
+	if run.Status() != "failed" || run.Completed() != 1 {
+		t.Errorf("Expected a failed run with 1 completed step, got %q with %d", run.Status(), run.Completed())
+	}
+
+	// A line cut short by a crash must not stop the run from resuming
+	stateDir, err := paths.StateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	journalPath := filepath.Join(stateDir, "agent_runs", journal.ID()+".jsonl")
+	file, err := os.OpenFile(journalPath, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"time":"2026-10-16T10:00:00Z","event":"step-sta`)
+	file.Close()
+
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	run, err = agent.LoadRun(journal.ID())
+	if err != nil {
+		t.Fatalf("LoadRun failed on a truncated journal: %v", err)
+	}
+	resumed, err := run.ResumePlan()
+	if err != nil {
+		t.Fatalf("ResumePlan failed: %v", err)
+	}
+	result, err := executor.ExecutePlan(context.Background(), resumed, agent.NewFeedback(cfg))
+	if err != nil {
+		t.Fatalf("ExecutePlan returned an error on resume: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected the resumed run to succeed: %s", result.Message)
+	}
+
+	data, _ := os.ReadFile(counter)
+	if runs := strings.Count(string(data), "ran"); runs != 1 {
+		t.Errorf("Expected the completed step to run once, ran %d times", runs)
+	}
+	if _, err := os.Stat(done); err != nil {
+		t.Errorf("Expected the remaining steps to run: %v", err)
+	}
+
+	run, err = agent.LoadRun(journal.ID())
+	if err != nil {
+		t.Fatalf("LoadRun failed after resuming: %v", err)
+	}
+	if run.Status() != "completed" || run.Completed() != 3 {
+		t.Errorf("Expected a completed run, got %q with %d steps completed", run.Status(), run.Completed())
+	}
+
+	runs, err := agent.ListRuns()
+	if err != nil || len(runs) != 1 || runs[0].ID != journal.ID() {
+		t.Errorf("Expected ListRuns to return the run, got %d runs (%v)", len(runs), err)
+	}
+}
+
+// TestAgentJournalShellState tests resuming a run in the directory and environment its earlier steps left
+func TestAgentJournalShellState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "project")
+	marker := filepath.Join(dir, "ready")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	journal, err := agent.NewJournal()
+	if err != nil {
+		t.Fatalf("NewJournal failed: %v", err)
+	}
+	plan := &agent.Plan{
+		Task: &agent.Task{Description: "test shell state"},
+		Steps: []*agent.Step{
+			{ID: 1, Command: "cd " + sub + " && export LUMO_TEST_GREETING=hello DEPLOY_TOKEN=hunter2"},
+			{ID: 2, Command: "test -f " + marker, IsCritical: true},
+			{ID: 3, Command: `echo "$PWD $LUMO_TEST_GREETING [$DEPLOY_TOKEN]" > out`},
+		},
+		Journal: journal,
+	}
+	executor := agent.NewExecutor(cfg, nil)
+	if _, err := executor.ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg)); err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+
+	run, err := agent.LoadRun(journal.ID())
+	if err != nil {
+		t.Fatalf("LoadRun failed: %v", err)
+	}
+	if run.Shell == nil || run.Shell.Dir != sub || run.Shell.Env["LUMO_TEST_GREETING"] != "hello" {
+		t.Fatalf("Expected the shell state after step 1 to be journaled, got %+v", run.Shell)
+	}
+	if _, recorded := run.Shell.Env["DEPLOY_TOKEN"]; recorded || len(run.Shell.Withheld) != 1 {
+		t.Errorf("Expected the secret to be withheld from the journal, got %+v", run.Shell)
+	}
+
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := run.ResumePlan()
+	if err != nil {
+		t.Fatalf("ResumePlan failed: %v", err)
+	}
+	if _, err := executor.ExecutePlan(context.Background(), resumed, agent.NewFeedback(cfg)); err != nil {
+		t.Fatalf("ExecutePlan returned an error on resume: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(sub, "out"))
+	if err != nil {
+		t.Fatalf("Expected the resumed step to run in %s: %v", sub, err)
+	}
+	if got := strings.TrimSpace(string(data)); got != sub+" hello []" {
+		t.Errorf("Expected the resumed session to have the earlier directory and variables, got %q", got)
+	}
+}
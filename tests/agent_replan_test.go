@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/tests/mocks"
+)
+
+// TestAgentReplan tests revising the rest of a plan after a step fails
+func TestAgentReplan(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	original := filepath.Join(dir, "original")
+	revised := filepath.Join(dir, "revised")
+
+	cfg := config.DefaultConfig()
+	cfg.AgentConfirmBeforeExecution = false
+	cfg.AgentMaxIterations = 2
+
+	aiClient := mocks.NewMockAIClient()
+	aiClient.CompletionResponse = fmt.Sprintf(`Here is the revision:
+{"done": false, "reason": "the first step failed", "steps": [
+  {"command": "touch %s", "description": "create the revised file", "isCritical": true}
+]}`, revised)
+
+	journal, err := agent.NewJournal()
+	if err != nil {
+		t.Fatalf("NewJournal failed: %v", err)
+	}
+	plan := &agent.Plan{
+		Task: &agent.Task{Description: "test replan"},
+		Steps: []*agent.Step{
+			{ID: 1, Command: "echo probing; false"},
+			{ID: 2, Command: "touch " + original},
+		},
+		Journal: journal,
+	}
+
+	if _, err := agent.NewExecutor(cfg, aiClient).ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg)); err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+
+	// One revision is allowed, so the revised last step is not revised again
+	if len(aiClient.CompletionCalls) != 1 {
+		t.Fatalf("Expected 1 revision request, got %d", len(aiClient.CompletionCalls))
+	}
+	if prompt := aiClient.CompletionCalls[0]; !strings.Contains(prompt, "probing") || !strings.Contains(prompt, "touch "+original) {
+		t.Errorf("Expected the prompt to include the output and the remaining steps, got:\n%s", prompt)
+	}
+
+	if len(plan.Steps) != 2 || plan.Steps[1].ID != 2 || plan.Steps[1].Command != "touch "+revised {
+		t.Fatalf("Expected the remaining step to be replaced, got %+v", plan.Steps[len(plan.Steps)-1])
+	}
+	if _, err := os.Stat(revised); err != nil {
+		t.Errorf("Expected the revised step to run: %v", err)
+	}
+	if _, err := os.Stat(original); !os.IsNotExist(err) {
+		t.Errorf("Expected the replaced step not to run")
+	}
+
+	run, err := agent.LoadRun(journal.ID())
+	if err != nil {
+		t.Fatalf("LoadRun failed: %v", err)
+	}
+	if len(run.Plan.Steps) != 2 || run.Plan.Steps[1].Command != "touch "+revised || !run.Plan.Steps[1].Executed {
+		t.Errorf("Expected the journal to record the revised plan, got %+v", run.Plan.Steps)
+	}
+}
+
+// TestAgentReplanDone tests stopping a plan early once the AI reports the task done
+func TestAgentReplanDone(t *testing.T) {
+	dir := t.TempDir()
+	skipped := filepath.Join(dir, "skipped")
+
+	cfg := config.DefaultConfig()
+	cfg.AgentConfirmBeforeExecution = false
+
+	aiClient := mocks.NewMockAIClient()
+	aiClient.CompletionResponse = `{"done": true, "reason": "already set up", "steps": []}`
+
+	plan := &agent.Plan{
+		Task: &agent.Task{Description: "test done"},
+		Steps: []*agent.Step{
+			{ID: 1, Command: "true"},
+			{ID: 2, Command: "true"},
+			{ID: 3, Command: "true"},
+			{ID: 4, Command: "touch " + skipped},
+		},
+	}
+
+	result, err := agent.NewExecutor(cfg, aiClient).ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg))
+	if err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected the run to succeed: %s", result.Message)
+	}
+	if plan.Steps[3].Executed {
+		t.Errorf("Expected the step after the task was done to be skipped")
+	}
+	if _, err := os.Stat(skipped); !os.IsNotExist(err) {
+		t.Errorf("Expected the skipped step not to run")
+	}
+
+	// A single iteration never revises the plan
+	cfg.AgentMaxIterations = 1
+	aiClient.Reset()
+	for _, step := range plan.Steps {
+		step.Executed, step.Result = false, nil
+	}
+	if _, err := agent.NewExecutor(cfg, aiClient).ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg)); err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+	if len(aiClient.CompletionCalls) != 0 || !plan.Steps[3].Executed {
+		t.Errorf("Expected the plan to run unrevised, got %d revision requests", len(aiClient.CompletionCalls))
+	}
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// TestAgentSafetyLevels tests which steps each safety level asks about; with
+// no terminal to ask on, those steps are refused
+func TestAgentSafetyLevels(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "keep.txt")
+	newPlan := func() *agent.Plan {
+		return &agent.Plan{
+			Task: &agent.Task{Description: "test safety levels"},
+			Steps: []*agent.Step{
+				{ID: 1, Command: "rm -f " + target},
+				{ID: 2, Command: "echo critical", IsCritical: true},
+			},
+		}
+	}
+	run := func(level string) *agent.Plan {
+		if err := os.WriteFile(target, []byte("data"), 0644); err != nil {
+			t.Fatalf("Failed to write target: %v", err)
+		}
+		cfg := config.DefaultConfig()
+		cfg.AgentSafetyLevel = level
+		plan := newPlan()
+		if _, err := agent.NewExecutor(cfg, nil).ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg)); err != nil {
+			t.Fatalf("ExecutePlan returned an error: %v", err)
+		}
+		return plan
+	}
+
+	// Older configs said "medium", which is the normal level
+	plan := run("medium")
+	if plan.Steps[0].Result.Success || !strings.Contains(plan.Steps[0].Result.Error.Error(), "rm deletes files") {
+		t.Errorf("Expected the normal level to refuse an unconfirmed rm, got %+v", plan.Steps[0].Result)
+	}
+	if _, err := os.Stat(target); err != nil {
+		t.Errorf("Expected the refused rm not to delete the file")
+	}
+	if !plan.Steps[1].Result.Success {
+		t.Errorf("Expected the normal level to run a critical step that is not destructive")
+	}
+
+	plan = run(config.AgentSafetyStrict)
+	if plan.Steps[1].Result.Success {
+		t.Errorf("Expected the strict level to refuse an unconfirmed critical step")
+	}
+
+	plan = run(config.AgentSafetyFast)
+	if !plan.Steps[0].Result.Success || !plan.Steps[1].Result.Success {
+		t.Errorf("Expected the fast level to run every step of a confirmed plan")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("Expected rm to delete the file at the fast level")
+	}
+}
+
+// TestAgentDenyList tests that deny-listed commands never run, even inside a
+// longer command line and at the fast level
+func TestAgentDenyList(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentSafetyLevel = config.AgentSafetyFast
+	cfg.AgentDenyList = []string{"echo forbidden*"}
+	plan := &agent.Plan{
+		Task: &agent.Task{Description: "test the deny list"},
+		Steps: []*agent.Step{
+			{ID: 1, Command: "cd /tmp && echo   forbidden  words"},
+			{ID: 2, Command: "echo allowed"},
+		},
+	}
+
+	if _, err := agent.NewExecutor(cfg, nil).ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg)); err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+	if plan.Steps[0].Result.Success || !strings.Contains(plan.Steps[0].Result.Error.Error(), "deny list") {
+		t.Errorf("Expected the deny list to block step 1, got %+v", plan.Steps[0].Result)
+	}
+	if !plan.Steps[1].Result.Success {
+		t.Errorf("Expected step 2 to run")
+	}
+
+	// Steps run on their own are checked too
+	result, err := agent.NewExecutor(cfg, nil).ExecuteStep(context.Background(), &agent.Step{ID: 1, Command: "echo forbidden"})
+	if err != nil {
+		t.Fatalf("ExecuteStep returned an error: %v", err)
+	}
+	if result.Success {
+		t.Errorf("Expected ExecuteStep to refuse a deny-listed command")
+	}
+}
+
+// TestDenyListVariants tests that deny list patterns catch other spellings
+// of the same command
+func TestDenyListVariants(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentSafetyLevel = config.AgentSafetyFast
+	confirm := func(reason string) bool { return true }
+
+	denied := []string{
+		"rm -rf /",
+		"rm -fr /",
+		"rm -r -f /",
+		"rm -f -r /",
+		"rm --recursive --force /",
+		"rm -R --force /",
+		"rm   -rf    /",
+		"sudo rm -rf --no-preserve-root /",
+		"rm -rfv /*",
+		"cd /tmp && rm -r -f ~",
+		"chmod --recursive 777 /",
+		"chown -vR root:root /",
+		"dd bs=4M if=image.iso of=/dev/sda",
+		"mkfs.ext4 /dev/sdb1",
+	}
+	for _, command := range denied {
+		if err := agent.CommandRefusal(cfg, command, confirm); err == nil || !strings.Contains(err.Error(), "deny list") {
+			t.Errorf("Expected %q to be blocked by the deny list, got %v", command, err)
+		}
+	}
+
+	allowed := []string{
+		"rm -rf ./build",
+		"rm -r ./cache",
+		"chmod -R 755 ./site",
+		"echo rm -rf /tmp",
+		"find / -name core",
+	}
+	for _, command := range allowed {
+		if err := agent.CommandRefusal(cfg, command, confirm); err != nil && strings.Contains(err.Error(), "deny list") {
+			t.Errorf("Expected %q not to match the deny list: %v", command, err)
+		}
+	}
+}
+
+// TestWrappedCommandSafety tests that shells, wrappers, and programs named
+// by a variable do not get destructive commands past the deny list or
+// confirmation
+func TestWrappedCommandSafety(t *testing.T) {
+	cfg := config.DefaultConfig()
+	asked := ""
+	refuse := func(reason string) bool {
+		asked = reason
+		return false
+	}
+
+	tests := []struct {
+		command string
+		denied  bool
+	}{
+		{"bash -c 'rm -rf /'", true},
+		{`sh -c "rm -rf ~"`, true},
+		{"bash -ec 'cd /tmp && rm -rf ~'", true},
+		{`sh -c "sh -c 'rm -rf /'"`, true},
+		{"$SHELL -c 'rm -rf ~'", true},
+		{"nice rm -rf ~", true},
+		{"nice -n 10 rm -rf ~", true},
+		{"command rm -rf ~", true},
+		{"exec rm -rf ~", true},
+		{"nohup rm -rf / &", true},
+		{"timeout -s KILL 10 rm -rf ~", true},
+		{"stdbuf -oL rm -rf ~", true},
+		{"env -i FOO=1 rm -rf ~", true},
+		{"env -S 'rm -rf ~'", true},
+		{"sudo -u root rm -rf /", true},
+		{"xargs rm -rf < list", false},
+		{"xargs -n 1 -P 4 shred -u < list", false},
+		{`"$SHELL" script.sh`, false},
+		{"$HOME/bin/cleanup.sh", false},
+		{"bash -c 'find . -delete'", false},
+	}
+	for _, tt := range tests {
+		asked = ""
+		err := agent.CommandRefusal(cfg, tt.command, refuse)
+		switch {
+		case err == nil:
+			t.Errorf("Expected %q to be refused", tt.command)
+		case tt.denied && !strings.Contains(err.Error(), "deny list"):
+			t.Errorf("Expected %q to be blocked by the deny list, got %v", tt.command, err)
+		case !tt.denied && asked == "":
+			t.Errorf("Expected %q to ask for confirmation, got %v", tt.command, err)
+		}
+	}
+
+	for _, command := range []string{"bash -c 'ls -la'", "nice -n 5 make", "xargs echo < list", "command -v rm", "sh build.sh"} {
+		if err := agent.CommandRefusal(cfg, command, refuse); err != nil {
+			t.Errorf("Expected %q to run without asking, got %v", command, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/server"
+)
+
+// newAgentServer returns a test server whose agent plans with the mock
+// provider, and its executor
+func newAgentServer(t *testing.T, confirm bool) (*httptest.Server, *executor.Executor) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "mock"
+	cfg.MockFixtures = filepath.Join(t.TempDir(), "missing.json")
+	cfg.EnableAgentMode = true
+	cfg.AgentConfirmBeforeExecution = confirm
+	cfg.EnableAuth = false
+	cfg.ServerQuietOutput = true
+
+	exec := executor.NewExecutor(cfg)
+	agent.Initialize(cfg, exec)
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	t.Cleanup(ts.Close)
+	return ts, exec
+}
+
+// pendingApprovalToken waits for a remote run to ask for approval and
+// returns its token, as agent:approvals shows it on the console
+func pendingApprovalToken(t *testing.T) string {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		approvals, err := agent.PendingApprovals()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(approvals) > 0 {
+			return approvals[0].Token
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("Expected a remote run to ask for approval")
+	return ""
+}
+
+// approve answers an approval at the approval endpoint
+func approve(t *testing.T, ts *httptest.Server, token string, approve bool) int {
+	t.Helper()
+	body, _ := json.Marshal(server.ApproveRequest{Token: token, Approve: approve})
+	resp, err := http.Post(ts.URL+"/api/v1/agent/approve", "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestAgentRunStream tests streaming an agent run to a web client, with the
+// plan approved by its token and the rest answered through the confirm
+// endpoint
+func TestAgentRunStream(t *testing.T) {
+	ts, _ := newAgentServer(t, true)
+
+	confirm := func(runID string, approve bool) int {
+		body, _ := json.Marshal(server.AgentConfirmRequest{RunID: runID, Approve: approve})
+		resp, err := http.Post(ts.URL+"/api/v1/agent/confirm", "application/json", strings.NewReader(string(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	resp, err := http.Post(ts.URL+"/api/v1/agent/run", "application/json", strings.NewReader(`{"task": "say hello"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("Expected a stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	runID := resp.Header.Get("X-Lumo-Run-ID")
+
+	var types []string
+	var output, done executor.AgentEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var event executor.AgentEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid event %q: %v", scanner.Text(), err)
+		}
+		if event.RunID != runID {
+			t.Errorf("Expected run ID %s, got %s", runID, event.RunID)
+		}
+		types = append(types, event.Type)
+
+		switch event.Type {
+		case executor.AgentEventPlan:
+			if len(event.Steps) != 1 || !strings.Contains(event.Steps[0].Command, "mock step") {
+				t.Errorf("Expected the mock plan, got %+v", event.Steps)
+			}
+		case executor.AgentEventConfirm:
+			// Only one run streams at a time, and answers go to that run
+			second, err := http.Post(ts.URL+"/api/v1/agent/run", "application/json", strings.NewReader(`{"task": "again"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			second.Body.Close()
+			if second.StatusCode != http.StatusConflict {
+				t.Errorf("Expected a second run to be refused, got %d", second.StatusCode)
+			}
+			if status := confirm("someone-else", true); status != http.StatusNotFound {
+				t.Errorf("Expected an unknown run to be reported, got %d", status)
+			}
+			// Plans need the approval token from the console
+			if status := confirm(runID, true); status != http.StatusForbidden {
+				t.Errorf("Expected approving the plan without its token to be refused, got %d", status)
+			}
+			if status := approve(t, ts, pendingApprovalToken(t), true); status != http.StatusNoContent {
+				t.Errorf("Expected the plan to be approved, got %d", status)
+			}
+		case executor.AgentEventStepOutput:
+			output = event
+		case executor.AgentEventDone:
+			done = event
+		}
+	}
+
+	want := []string{executor.AgentEventPlan, executor.AgentEventConfirm, executor.AgentEventStepStart}
+	if len(types) < len(want) || strings.Join(types[:len(want)], ",") != strings.Join(want, ",") {
+		t.Errorf("Expected the run to start with %v, got %v", want, types)
+	}
+	if output.Output != "mock step" {
+		t.Errorf("Expected the step's output to stream, got %+v", output)
+	}
+	if !done.Success || done.Output != "All steps completed successfully" {
+		t.Errorf("Expected the run to succeed, got %+v (events %v)", done, types)
+	}
+
+	// Answers after the run has finished have no run to go to
+	if status := confirm(runID, true); status != http.StatusNotFound {
+		t.Errorf("Expected a finished run to be reported, got %d", status)
+	}
+}
+
+// TestRemoteAgentApproval tests that agent runs requested through the
+// execute endpoint wait for approval, even with confirmation turned off
+func TestRemoteAgentApproval(t *testing.T) {
+	ts, exec := newAgentServer(t, false)
+
+	execute := func() <-chan server.CommandResponse {
+		responses := make(chan server.CommandResponse, 1)
+		go func() {
+			var response server.CommandResponse
+			resp, err := http.Post(ts.URL+"/api/v1/execute", "application/json", strings.NewReader(`{"command": "say hello", "type": "agent"}`))
+			if err == nil {
+				json.NewDecoder(resp.Body).Decode(&response)
+				resp.Body.Close()
+			}
+			responses <- response
+		}()
+		return responses
+	}
+
+	// Approved on the console
+	responses := execute()
+	token := pendingApprovalToken(t)
+	if status := approve(t, ts, "0123456789", true); status != http.StatusNotFound {
+		t.Errorf("Expected an unknown token to be refused, got %d", status)
+	}
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeAgent, Intent: "approve " + token, RawInput: "agent:approve " + token})
+	if err != nil || result.IsError || !strings.Contains(result.Output, "say hello") {
+		t.Fatalf("Expected agent:approve to approve the plan, got %+v (%v)", result, err)
+	}
+	if response := <-responses; !response.Success || response.Output != "All steps completed successfully" {
+		t.Errorf("Expected the approved run to succeed, got %+v", response)
+	}
+	if status := approve(t, ts, token, true); status != http.StatusNotFound {
+		t.Errorf("Expected a used token to be refused, got %d", status)
+	}
+
+	// Rejected at the approval endpoint
+	responses = execute()
+	if status := approve(t, ts, pendingApprovalToken(t), false); status != http.StatusNoContent {
+		t.Errorf("Expected the plan to be rejected, got %d", status)
+	}
+	if response := <-responses; response.Output != "Execution cancelled by user." {
+		t.Errorf("Expected the rejected run to be cancelled, got %+v", response)
+	}
+	if approvals, _ := agent.PendingApprovals(); len(approvals) != 0 {
+		t.Errorf("Expected no approvals to be left, got %d", len(approvals))
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// TestProviderRegistry tests registering and looking up AI providers
+func TestProviderRegistry(t *testing.T) {
+	for _, name := range []string{"gemini", "openai", "ollama"} {
+		if _, ok := ai.Lookup(name); !ok {
+			t.Errorf("Expected built-in provider %s to be registered", name)
+		}
+	}
+
+	mock := &MockAIClient{QueryResponse: "hello"}
+	ai.Register(ai.ProviderInfo{
+		Name:         "test-registry",
+		Description:  "Provider registered by tests",
+		Factory:      func(cfg *config.Config) ai.Client { return mock },
+		Capabilities: ai.Capabilities{NeedsAPIKey: true},
+		APIKey:       func(cfg *config.Config) string { return "" },
+	})
+
+	client, err := ai.NewClient("test-registry", config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Expected registered provider to create a client, got: %v", err)
+	}
+	if response, _ := client.Query("hi"); response != "hello" {
+		t.Errorf("Expected the provider's factory to be used, got response %q", response)
+	}
+	if !ai.MissingAPIKey("test-registry", config.DefaultConfig()) {
+		t.Errorf("Expected the provider to report a missing API key")
+	}
+	if ai.IsLocal("test-registry") {
+		t.Errorf("Expected the provider not to be local")
+	}
+
+	if _, err := ai.NewClient("no-such-provider", config.DefaultConfig()); err == nil {
+		t.Errorf("Expected an error for an unknown provider")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering a provider twice to panic")
+		}
+	}()
+	ai.Register(ai.ProviderInfo{
+		Name:    "test-registry",
+		Factory: func(cfg *config.Config) ai.Client { return mock },
+	})
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// TestReadOnlyViolation tests telling inspection commands apart from those that change the system
+func TestReadOnlyViolation(t *testing.T) {
+	allowed := []string{
+		"du -sh /var/* 2>/dev/null | sort -rh | head -20",
+		"df -h",
+		"find / -xdev -size +500M -type f 2>/dev/null",
+		"journalctl -u nginx --since today | tail -50",
+		"sudo du -x --max-depth=1 /var/log",
+		"systemctl status docker",
+		"git status && git log --oneline -5",
+		"docker ps -a",
+		"ps aux --sort=-%mem | head",
+		"awk '$3 > 100 {print $1}' data.txt",
+		"for d in /var/log /tmp; do du -sh $d; done",
+		"ls -la $(dirname /etc/hosts)",
+		"ip addr show",
+		`echo "$(date)"`,
+		`echo "used: $(du -sh /var | cut -f1)" 'not $(run)'`,
+		`echo "$((1 + 2))"`,
+		"ss -tlnp",
+		"sar -u 1 3",
+		"git log --oneline --stat",
+		"echo $(whoami) $(hostname)",
+		"ls /var/log 2>/dev/null | sort -r -k2",
+		"sort -t, -k2 data.csv",
+		"[ -d /tmp ] && echo yes",
+	}
+	for _, command := range allowed {
+		if reason := agent.ReadOnlyViolation(command); reason != "" {
+			t.Errorf("Expected %q to be allowed, refused: %s", command, reason)
+		}
+	}
+
+	refused := []string{
+		"rm -rf /tmp/cache",
+		"du -sh * > sizes.txt",
+		"ls | tee listing.txt",
+		"find . -name '*.tmp' -delete",
+		"find . -type f -exec rm {} +",
+		"systemctl restart nginx",
+		"docker rm old",
+		"git checkout main",
+		"apt install -y ncdu",
+		"echo `rm -rf x`",
+		"ls $(rm -rf x)",
+		"sort -o out.txt in.txt",
+		"awk '{print > \"out.txt\"}' in.txt",
+		"awk 'BEGIN {system(\"reboot\")}'",
+		"date -s '2020-01-01'",
+		"ip link set eth0 down",
+		"journalctl --vacuum-time=1d",
+		"frobnicate --all",
+		"python3 -c 'import os'",
+		`echo "$(touch /tmp/p)"`,
+		`echo "a $(echo "$(rm -rf x)")"`,
+		"echo \"`touch /tmp/p`\"",
+		"xxd -r in out",
+		"xxd in.bin out.bin",
+		"git diff --output=FILE",
+		"git log --output FILE",
+		"git grep -Ovim main",
+		"ss -K dst 10.0.0.1",
+		"ss -tK",
+		"ss -D dump.bin",
+		"sar -o FILE 1 3",
+		"sar -uo FILE 1 3",
+		"$SHELL -c 'touch /tmp/pwned'",
+		`"$SHELL" -c 'rm -rf ~/x'`,
+		"$HOME/bin/cleanup.sh",
+		"$HOME/bin/ls",
+		"$(echo touch) /tmp/p",
+		"ls; $(echo reboot)",
+		"cat a>b",
+		"echo hi>/tmp/a",
+		"echo hi>>/tmp/a",
+		"sort --output=/tmp/x a",
+		"sort --out /tmp/x a",
+		"sort -o/tmp/x a",
+		"sort -ruo /tmp/x a",
+		"",
+	}
+	for _, command := range refused {
+		if agent.ReadOnlyViolation(command) == "" {
+			t.Errorf("Expected %q to be refused", command)
+		}
+	}
+}
+
+// TestReadOnlyPlanExecution tests that a read-only plan refuses steps that would change the system
+func TestReadOnlyPlanExecution(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "created")
+
+	cfg := config.DefaultConfig()
+	plan := &agent.Plan{
+		Task: &agent.Task{Description: "test read-only", ReadOnly: true},
+		Steps: []*agent.Step{
+			{ID: 1, Command: "ls " + dir},
+			{ID: 2, Command: "touch " + target},
+		},
+		ReadOnly: true,
+	}
+
+	result, err := agent.NewExecutor(cfg, nil).ExecutePlan(context.Background(), plan, agent.NewFeedback(cfg))
+	if err != nil {
+		t.Fatalf("ExecutePlan returned an error: %v", err)
+	}
+	if !plan.Steps[0].Result.Success {
+		t.Errorf("Expected the inspection step to run: %v", plan.Steps[0].Result.Error)
+	}
+	if plan.Steps[1].Result.Success || !strings.Contains(plan.Steps[1].Result.Error.Error(), "read-only") {
+		t.Errorf("Expected the mutating step to be refused, got %v", plan.Steps[1].Result.Error)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("Expected the refused step not to create %s", target)
+	}
+	if result.Success {
+		t.Errorf("Expected the plan to be reported as failed")
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/server"
+)
+
+// TestAPIKeys tests creating API keys and the endpoints their scopes allow
+func TestAPIKeys(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	cfg := config.DefaultConfig()
+	cfg.EnableAuth = true
+	cfg.ServerQuietOutput = true
+	cfg.JWTSecret = "test-secret"
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	result := run("server:apikey create --name ci --scope stats,chat")
+	if result.IsError {
+		t.Fatalf("Expected the key to be created: %s", result.Output)
+	}
+	var key string
+	for _, field := range strings.Fields(result.Output) {
+		if strings.HasPrefix(field, "lumo_") {
+			key = field
+		}
+	}
+	if key == "" {
+		t.Fatalf("Expected the key in the output:\n%s", result.Output)
+	}
+	if result := run("server:apikey create --name ci"); !result.IsError {
+		t.Errorf("Expected a duplicate name to be refused: %s", result.Output)
+	}
+	if result := run("server:apikey create --name bad --scope root"); !result.IsError || !strings.Contains(result.Output, "unknown scope") {
+		t.Errorf("Expected an unknown scope to be refused: %s", result.Output)
+	}
+	if result := run("server:apikey list"); !strings.Contains(result.Output, "chat,stats") || strings.Contains(result.Output, key) {
+		t.Errorf("Expected the key listed without its secret:\n%s", result.Output)
+	}
+
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	defer ts.Close()
+	request := func(method, path string, header http.Header) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(`{}`))
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		want   int
+	}{
+		{"scoped endpoint", "GET", "/api/v1/stats", http.Header{"X-Api-Key": {key}}, http.StatusOK},
+		{"bearer key", "GET", "/api/v1/stats", http.Header{"Authorization": {"Bearer " + key}}, http.StatusOK},
+		{"missing scope", "POST", "/api/v1/execute", http.Header{"X-Api-Key": {key}}, http.StatusForbidden},
+		{"password change", "POST", "/api/v1/auth/change-password", http.Header{"X-Api-Key": {key}}, http.StatusForbidden},
+		{"unknown key", "GET", "/api/v1/stats", http.Header{"X-Api-Key": {"lumo_not-a-key"}}, http.StatusUnauthorized},
+		{"no credentials", "GET", "/api/v1/stats", http.Header{}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		if got := request(tt.method, tt.path, tt.header); got != tt.want {
+			t.Errorf("%s: got status %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if result := run("server:apikey revoke ci"); result.IsError {
+		t.Fatalf("Expected the key revoked: %s", result.Output)
+	}
+	if got := request("GET", "/api/v1/stats", http.Header{"X-Api-Key": {key}}); got != http.StatusUnauthorized {
+		t.Errorf("Expected a revoked key to be refused, got status %d", got)
+	}
+}
+
+// TestAPIKeyAgentScope tests that starting agent runs through
+// /api/v1/execute needs the agent scope
+func TestAPIKeyAgentScope(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	cfg := config.DefaultConfig()
+	cfg.EnableAuth = true
+	cfg.EnableAgentMode = false
+	cfg.ServerQuietOutput = true
+	cfg.JWTSecret = "test-secret"
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	create := func(input string) string {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil || result.IsError {
+			t.Fatalf("Expected the key to be created: %v %s", err, result.Output)
+		}
+		for _, field := range strings.Fields(result.Output) {
+			if strings.HasPrefix(field, "lumo_") {
+				return field
+			}
+		}
+		t.Fatalf("Expected the key in the output:\n%s", result.Output)
+		return ""
+	}
+	executeKey := create("server:apikey create --name ci")
+	agentKey := create("server:apikey create --name ops --scope execute,agent")
+
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	defer ts.Close()
+	execute := func(key, body string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest("POST", ts.URL+"/api/v1/execute", strings.NewReader(body))
+		req.Header.Set("X-Api-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	for _, body := range []string{
+		`{"command": "clean up the tmp folder", "type": "agent"}`,
+		`{"command": "agent:clean up the tmp folder"}`,
+		`{"command": "why is the disk full", "type": "analyze"}`,
+	} {
+		if status, data := execute(executeKey, body); status != http.StatusForbidden || !strings.Contains(data, `"agent" scope`) {
+			t.Errorf("Expected an execute-only key to be refused %s, got status %d: %s", body, status, data)
+		}
+		if status, data := execute(agentKey, body); status == http.StatusForbidden {
+			t.Errorf("Expected a key with the agent scope to run %s: %s", body, data)
+		}
+	}
+	if status, data := execute(executeKey, `{"command": "echo hi", "type": "shell"}`); status != http.StatusOK {
+		t.Errorf("Expected an execute-only key to run shell commands, got status %d: %s", status, data)
+	}
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/audit"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/server"
+)
+
+// TestServerAudit tests that execute calls are audited and can be reviewed
+func TestServerAudit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	cfg := config.DefaultConfig()
+	cfg.EnableAuth = true
+	cfg.ServerQuietOutput = true
+	cfg.JWTSecret = "test-secret"
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+	secret := func(result *executor.Result) string {
+		t.Helper()
+		if result.IsError {
+			t.Fatalf("Expected success: %s", result.Output)
+		}
+		return strings.TrimSpace(strings.Split(result.Output, "\n")[2])
+	}
+
+	if result := run("server:audit tail"); result.IsError || !strings.Contains(result.Output, "No commands") {
+		t.Errorf("Expected an empty audit log: %s", result.Output)
+	}
+
+	adminKey := secret(run("server:apikey create --name ops --scope all --role admin"))
+	viewerKey := secret(run("server:apikey create --name dashboard --scope all --role viewer"))
+
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	defer ts.Close()
+	request := func(method, path, body, key string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("X-Api-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	if status, body := request("POST", "/api/v1/execute", `{"command": "echo audited", "type": "shell"}`, adminKey); status != http.StatusOK {
+		t.Fatalf("Expected the admin to run a shell command, got status %d: %s", status, body)
+	}
+	if status, body := request("POST", "/api/v1/execute", `{"command": "echo refused", "type": "shell"}`, viewerKey); status != http.StatusForbidden {
+		t.Fatalf("Expected the viewer to be refused, got status %d: %s", status, body)
+	}
+	if status, _ := request("GET", "/api/v1/audit", "", viewerKey); status != http.StatusForbidden {
+		t.Errorf("Expected the audit log to be admin-only, got status %d", status)
+	}
+
+	status, body := request("GET", "/api/v1/audit?limit=10", "", adminKey)
+	if status != http.StatusOK {
+		t.Fatalf("Expected the admin to read the audit log, got status %d: %s", status, body)
+	}
+	var resp server.AuditResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Entries) != 2 {
+		t.Fatalf("Expected 2 audited calls, got %d: %s", len(resp.Entries), body)
+	}
+	ran, refused := resp.Entries[0], resp.Entries[1]
+	if ran.User != "apikey:ops" || ran.Type != "shell" || ran.Input != "echo audited" || ran.Status != http.StatusOK || !ran.Success {
+		t.Errorf("Unexpected entry for the command that ran: %+v", ran)
+	}
+	if refused.User != "apikey:dashboard" || refused.Input != "echo refused" || refused.Status != http.StatusForbidden ||
+		refused.Success || !strings.Contains(refused.Error, "viewer role") {
+		t.Errorf("Unexpected entry for the refused command: %+v", refused)
+	}
+	if ran.Client == "" || ran.Time.IsZero() {
+		t.Errorf("Expected the client and time to be recorded: %+v", ran)
+	}
+	if status, _ := request("GET", "/api/v1/audit?limit=0", "", adminKey); status != http.StatusBadRequest {
+		t.Errorf("Expected an invalid limit to be refused, got status %d", status)
+	}
+
+	result := run("server:audit tail 1")
+	if result.IsError || !strings.Contains(result.Output, "echo refused") || strings.Contains(result.Output, "echo audited") {
+		t.Errorf("Expected only the last entry:\n%s", result.Output)
+	}
+	if result := run("server:audit tail"); !strings.Contains(result.Output, "apikey:ops") || !strings.Contains(result.Output, "403") {
+		t.Errorf("Expected both entries:\n%s", result.Output)
+	}
+	if result := run("server:audit tail many"); !result.IsError {
+		t.Errorf("Expected an invalid count to be refused: %s", result.Output)
+	}
+}
+
+// TestAuditRotation tests that a full audit log is rotated and still read
+func TestAuditRotation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+
+	path, err := audit.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Record(audit.Entry{Type: "shell", Input: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	// Pad the log to its limit so the next entry rotates it
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte(strings.Repeat(" ", audit.MaxSize) + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	if err := audit.Record(audit.Entry{Type: "shell", Input: "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("Expected the full log to be rotated: %v", err)
+	}
+	entries, err := audit.Tail(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Input != "first" || entries[1].Input != "second" {
+		t.Errorf("Expected entries from both logs, oldest first: %+v", entries)
+	}
+}
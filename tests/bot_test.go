@@ -0,0 +1,249 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/bot"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+)
+
+// fakeBotTransport delivers queued messages once and records replies
+type fakeBotTransport struct {
+	mu       sync.Mutex
+	incoming []bot.Message
+	sent     []string
+	replied  chan struct{}
+}
+
+func (f *fakeBotTransport) Name() string { return "fake" }
+
+func (f *fakeBotTransport) Receive(ctx context.Context) ([]bot.Message, error) {
+	f.mu.Lock()
+	messages := f.incoming
+	f.incoming = nil
+	f.mu.Unlock()
+	if len(messages) > 0 {
+		return messages, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *fakeBotTransport) Send(ctx context.Context, chat, text string) error {
+	f.mu.Lock()
+	f.sent = append(f.sent, chat+": "+text)
+	f.mu.Unlock()
+	f.replied <- struct{}{}
+	return nil
+}
+
+// newBotConfig returns a configuration answering with the mock provider
+func newBotConfig(t *testing.T) *config.Config {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "mock"
+	cfg.MockFixtures = writeMockFixtures(t, `{
+		"rules": [
+			{"contains": "user: hi", "response": "with history"},
+			{"contains": "capital", "response": "Paris"}
+		],
+		"default": "hello there"
+	}`)
+	cfg.BotAllowedUsers = []string{"42", "@alice"}
+	return cfg
+}
+
+// TestBotBridgeHandle tests answering messages from allowed and other users
+func TestBotBridgeHandle(t *testing.T) {
+	cfg := newBotConfig(t)
+	bridge := bot.NewWithTransport(cfg, executor.NewExecutor(cfg), &fakeBotTransport{})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		msg  bot.Message
+		want string
+	}{
+
+		{"allowed by username", bot.Message{Chat: "2", Sender: "8", SenderName: "@alice", Text: "/ask the capital of France"}, "Paris"},
+		{"group command", bot.Message{Chat: "1", Sender: "42", Text: "/ask@lumo_bot capital?"}, "Paris"},
+		{"help", bot.Message{Chat: "1", Sender: "42", Text: "/help"}, "/agent <task>"},
+		{"unknown command", bot.Message{Chat: "1", Sender: "42", Text: "/reboot"}, "Unknown command: /reboot"},
+		{"agent not allowed", bot.Message{Chat: "1", Sender: "42", Text: "/agent clean up"}, "not allowed to run agent tasks"},
+		{"first chat message", bot.Message{Chat: "1", Sender: "42", Text: "hi"}, "hello there"},
+		{"chat with history", bot.Message{Chat: "1", Sender: "42", Text: "and then?"}, "with history"},
+		{"new conversation", bot.Message{Chat: "1", Sender: "42", Text: "/new"}, "new conversation"},
+		{"after new conversation", bot.Message{Chat: "1", Sender: "42", Text: "and then?"}, "hello there"},
+	} {
+		if got := bridge.Handle(ctx, tc.msg); !strings.Contains(got, tc.want) {
+			t.Errorf("%s: expected a reply containing %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	if got := bridge.Handle(ctx, bot.Message{Chat: "1", Sender: "7", Text: "hi"}); got != "" {
+		t.Errorf("Expected strangers to be ignored, got %q", got)
+	}
+
+	// Agent tasks are only allowed by user ID, never by @username
+	cfg.BotAgentUsers = []string{"@alice"}
+	if got := bridge.Handle(ctx, bot.Message{Chat: "2", Sender: "8", SenderName: "@alice", Text: "/agent clean up"}); !strings.Contains(got, "not allowed to run agent tasks") {
+		t.Errorf("Expected an agent task allowed by @username to be refused, got %q", got)
+	}
+}
+
+// TestBotBridgeRun tests that received messages are answered
+func TestBotBridgeRun(t *testing.T) {
+	cfg := newBotConfig(t)
+	transport := &fakeBotTransport{
+		incoming: []bot.Message{{Chat: "9", Sender: "42", Text: "/ask capital?"}},
+		replied:  make(chan struct{}, 1),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bot.NewWithTransport(cfg, executor.NewExecutor(cfg), transport).Run(ctx)
+
+	select {
+	case <-transport.replied:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a reply")
+	}
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	if len(transport.sent) != 1 || transport.sent[0] != "9: Paris" {
+		t.Errorf("Unexpected replies: %q", transport.sent)
+	}
+}
+
+// TestTelegramTransport tests receiving and sending with the Telegram Bot API
+func TestTelegramTransport(t *testing.T) {
+	var offsets []string
+	var sent []map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botTOKEN/getUpdates":
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			io.WriteString(w, `{"ok": true, "result": [
+				{"update_id": 10, "message": {"from": {"id": 42, "username": "alice"}, "chat": {"id": -5}, "text": "hi"}},
+				{"update_id": 11, "message": {"from": {"id": 42}, "chat": {"id": -5}}}
+			]}`)
+		case "/botTOKEN/sendMessage":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = append(sent, body)
+			io.WriteString(w, `{"ok": true, "result": {}}`)
+		default:
+			io.WriteString(w, `{"ok": false, "description": "Unauthorized"}`)
+		}
+	}))
+	defer ts.Close()
+
+	telegram := bot.NewTelegram(ts.URL, "TOKEN")
+	ctx := context.Background()
+	messages, err := telegram.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bot.Message{Chat: "-5", Sender: "42", SenderName: "@alice", Text: "hi"}
+	if len(messages) != 1 || messages[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, messages)
+	}
+	if _, err := telegram.Receive(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(offsets) != 2 || offsets[0] != "0" || offsets[1] != "12" {
+		t.Errorf("Expected updates to be acknowledged, got offsets %v", offsets)
+	}
+
+	if err := telegram.Send(ctx, "-5", strings.Repeat("x", 5000)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 2 || sent[0]["chat_id"] != "-5" || len(sent[0]["text"]) != 4096 || len(sent[1]["text"]) != 904 {
+		t.Errorf("Expected a long reply in two messages, got %d", len(sent))
+	}
+
+	if _, err := bot.NewTelegram(ts.URL, "WRONG").Receive(ctx); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+}
+
+// TestMatrixTransport tests receiving and sending with the Matrix client-server API
+func TestMatrixTransport(t *testing.T) {
+	syncs := 0
+	var sentPath, sentBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer TOKEN" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"errcode": "M_UNKNOWN_TOKEN", "error": "Invalid access token"}`)
+			return
+		}
+		switch {
+		case r.URL.Path == "/_matrix/client/v3/account/whoami":
+			io.WriteString(w, `{"user_id": "@lumo:example.org"}`)
+		case r.URL.Path == "/_matrix/client/v3/sync":
+			syncs++
+			if syncs == 1 {
+				io.WriteString(w, `{"next_batch": "s1", "rooms": {"join": {"!room:example.org": {"timeline": {"events": [
+					{"type": "m.room.message", "sender": "@alice:example.org", "content": {"msgtype": "m.text", "body": "old"}}
+				]}}}}}`)
+				return
+			}
+			if r.URL.Query().Get("since") != "s1" {
+				t.Errorf("Expected to sync from s1, got %s", r.URL.RawQuery)
+			}
+			io.WriteString(w, `{"next_batch": "s2", "rooms": {"join": {
+				"!room:example.org": {"timeline": {"events": [
+					{"type": "m.room.message", "sender": "@alice:example.org", "content": {"msgtype": "m.text", "body": "hi"}},
+					{"type": "m.room.message", "sender": "@lumo:example.org", "content": {"msgtype": "m.text", "body": "my reply"}},
+					{"type": "m.reaction", "sender": "@alice:example.org", "content": {}}
+				]}},
+				"!other:example.org": {"timeline": {"events": [
+					{"type": "m.room.message", "sender": "@bob:example.org", "content": {"msgtype": "m.text", "body": "elsewhere"}}
+				]}}
+			}}}`)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/_matrix/client/v3/rooms/"):
+			sentPath = r.URL.Path
+			data, _ := io.ReadAll(r.Body)
+			sentBody = string(data)
+			io.WriteString(w, `{"event_id": "$1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	matrix := bot.NewMatrix(ts.URL+"/", "TOKEN", "!room:example.org")
+	ctx := context.Background()
+	if messages, err := matrix.Receive(ctx); err != nil || len(messages) != 0 {
+		t.Fatalf("Expected the first sync to skip old messages, got %+v (%v)", messages, err)
+	}
+	messages, err := matrix.Receive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bot.Message{Chat: "!room:example.org", Sender: "@alice:example.org", Text: "hi"}
+	if len(messages) != 1 || messages[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, messages)
+	}
+
+	if err := matrix.Send(ctx, "!room:example.org", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sentPath, "/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/") ||
+		!strings.Contains(sentBody, `"body":"hello"`) {
+		t.Errorf("Unexpected message sent: %s %s", sentPath, sentBody)
+	}
+
+	if _, err := bot.NewMatrix(ts.URL, "WRONG", "").Receive(ctx); err == nil || !strings.Contains(err.Error(), "Invalid access token") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+)
+
+// TestResponseCache tests caching answers in memory and on disk
+func TestResponseCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := ai.NewResponseCache(dir, time.Hour, 2)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("Expected an empty cache")
+	}
+	for _, key := range []string{"a", "b"} {
+		if err := cache.Put(key, "answer "+key); err != nil {
+			t.Fatalf("Failed to cache an answer: %v", err)
+		}
+	}
+	if answer, ok := cache.Get("a"); !ok || answer != "answer a" {
+		t.Errorf("Expected the cached answer, got %q", answer)
+	}
+
+	// A new cache on the same directory, like the next run, sees the answers
+	later := ai.NewResponseCache(dir, time.Hour, 2)
+	if answer, ok := later.Get("b"); !ok || answer != "answer b" {
+		t.Errorf("Expected the answer from disk, got %q", answer)
+	}
+
+	// Only the most recent answers are kept
+	time.Sleep(10 * time.Millisecond)
+	if err := cache.Put("c", "answer c"); err != nil {
+		t.Fatalf("Failed to cache an answer: %v", err)
+	}
+	if count, _, _ := cache.Stats(); count != 2 {
+		t.Errorf("Expected 2 cached answers, got %d", count)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 2 {
+		t.Errorf("Expected 2 answers to be removed, got %d (%v)", removed, err)
+	}
+	if _, ok := ai.NewResponseCache(dir, time.Hour, 2).Get("c"); ok {
+		t.Errorf("Expected no answers after clearing the cache")
+	}
+}
+
+// TestResponseCacheExpiry tests that old answers are not used
+func TestResponseCacheExpiry(t *testing.T) {
+	cache := ai.NewResponseCache(t.TempDir(), 10*time.Millisecond, 10)
+	if err := cache.Put("q", "answer"); err != nil {
+		t.Fatalf("Failed to cache an answer: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("q"); ok {
+		t.Errorf("Expected an expired answer not to be used")
+	}
+}
+
+// TestResponseCacheKey tests that answers are kept apart by model and instructions
+func TestResponseCacheKey(t *testing.T) {
+	first := ai.NewOllamaClient("http://localhost:11434", "llama3")
+	second := ai.NewOllamaClient("http://localhost:11434", "mistral")
+
+	key := ai.CacheKey(first, "", "list files", "/tmp")
+	if key != ai.CacheKey(first, "", "list files", "/tmp") {
+		t.Errorf("Expected the same question to have the same key")
+	}
+	for name, other := range map[string]string{
+		"model":        ai.CacheKey(second, "", "list files", "/tmp"),
+		"instructions": ai.CacheKey(first, "Answer as a pirate.", "list files", "/tmp"),
+		"directory":    ai.CacheKey(first, "", "list files", "/home"),
+	} {
+		if other == key {
+			t.Errorf("Expected a different %s to change the key", name)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/chat"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/tests/mocks"
+)
+
+// TestChatHandoff tests turning a chat conversation into an agent task that carries its context
+func TestChatHandoff(t *testing.T) {
+	aiClient := mocks.NewMockAIClient()
+	manager := chat.NewManager(aiClient, 5, 20)
+	conv := manager.StartNewConversation()
+
+	if _, err := manager.Handoff(context.Background(), conv, ""); err == nil {
+		t.Errorf("Expected an empty conversation to have no task")
+	}
+
+	conv.AddUserMessage("How should I back up ~/projects to my NAS?")
+	conv.AddAssistantMessage("Use rsync -a --delete to /mnt/nas/backups, mounted over NFS.")
+	aiClient.CompletionResponse = `Sure:
+{"task": "Back up ~/projects to the NAS with rsync", "context": "Destination /mnt/nas/backups; use rsync -a --delete"}`
+
+	handoff, err := manager.Handoff(context.Background(), conv, "skip node_modules")
+	if err != nil {
+		t.Fatalf("Handoff failed: %v", err)
+	}
+	if handoff.Task != "Back up ~/projects to the NAS with rsync" || !strings.Contains(handoff.Context, "/mnt/nas/backups") {
+		t.Errorf("Unexpected handoff: %+v", handoff)
+	}
+	prompt := aiClient.CompletionCalls[len(aiClient.CompletionCalls)-1]
+	if !strings.Contains(prompt, "rsync -a --delete") || !strings.Contains(prompt, "skip node_modules") {
+		t.Errorf("Expected the prompt to carry the conversation and the note, got:\n%s", prompt)
+	}
+
+	// The context reaches the planning prompt
+	aiClient.Reset()
+	aiClient.CompletionResponse = `{"description": "back up", "steps": [{"id": 1, "command": "echo backup", "description": "back up"}]}`
+	task := &agent.Task{Description: handoff.Task, Context: handoff.Context}
+	if _, err := agent.NewPlanner(config.DefaultConfig(), aiClient).CreatePlan(context.Background(), task); err != nil {
+		t.Fatalf("CreatePlan failed: %v", err)
+	}
+	if !strings.Contains(aiClient.CompletionCalls[0], "Destination /mnt/nas/backups") {
+		t.Errorf("Expected the planning prompt to include the conversation's context")
+	}
+}
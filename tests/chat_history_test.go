@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/chat"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/tests/mocks"
+)
+
+// TestChatHistory tests titling, tagging, listing, and renaming saved conversations
+func TestChatHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	aiClient := mocks.NewMockAIClient()
+	aiClient.CompletionResponse = `{"title": "\"Cleaning up Docker disk space.\"", "tags": ["Docker", "disk space", "docker"]}`
+	manager := chat.NewManager(aiClient, 5, 20)
+	if _, err := manager.ProcessMessage(context.Background(), "how do I free disk space used by docker?"); err != nil {
+		t.Fatal(err)
+	}
+	if convs, _ := chat.SavedConversations(); len(convs) != 0 {
+		t.Errorf("Expected nothing saved before history is enabled, got %d", len(convs))
+	}
+
+	manager.SetHistory(true, true)
+	manager.StartNewConversation()
+	if _, err := manager.ProcessMessage(context.Background(), "how do I free disk space used by docker?"); err != nil {
+		t.Fatal(err)
+	}
+	calls := len(aiClient.CompletionCalls)
+	if _, err := manager.ProcessMessage(context.Background(), "and unused volumes?"); err != nil {
+		t.Fatal(err)
+	}
+	if len(aiClient.CompletionCalls) != calls+1 {
+		t.Errorf("Expected a titled conversation not to be titled again")
+	}
+
+	convs, err := chat.SavedConversations()
+	if err != nil || len(convs) != 1 {
+		t.Fatalf("Expected one saved conversation, got %d (%v)", len(convs), err)
+	}
+	conv := convs[0]
+	if conv.Title != "Cleaning up Docker disk space" || strings.Join(conv.Tags, ",") != "docker,disk-space" {
+		t.Errorf("Unexpected title and tags: %q %v", conv.Title, conv.Tags)
+	}
+	if len(conv.Messages) != 5 {
+		t.Errorf("Expected the instructions and two exchanges to be saved, got %d messages", len(conv.Messages))
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	exec := executor.NewExecutor(cfg)
+	parser := nlp.NewParser(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := run("chat:history list"); !strings.Contains(result.Output, " 1. Cleaning up Docker disk space") || !strings.Contains(result.Output, "#docker #disk-space") {
+		t.Errorf("Expected the conversation with its title and tags, got:\n%s", result.Output)
+	}
+	if result := run("chat:history list #networking"); !strings.Contains(result.Output, "No saved conversations match") {
+		t.Errorf("Expected the tag filter to hide the conversation, got:\n%s", result.Output)
+	}
+	if result := run("chat:history show 1"); !strings.Contains(result.Output, "and unused volumes?") {
+		t.Errorf("Expected the conversation's messages, got:\n%s", result.Output)
+	}
+
+	if result := run("chat:rename 1 Docker cleanup"); result.IsError || !strings.Contains(result.Output, `"Docker cleanup"`) {
+		t.Errorf("Expected the conversation to be renamed, got:\n%s", result.Output)
+	}
+	if renamed, err := chat.LoadConversation(conv.ID); err != nil || renamed.Title != "Docker cleanup" || !renamed.UpdatedAt.Equal(conv.UpdatedAt) {
+		t.Errorf("Expected the new title to be saved without reordering, got %+v (%v)", renamed, err)
+	}
+	if result := run("chat:rename 7 Nothing"); !result.IsError {
+		t.Errorf("Expected renaming a missing conversation to fail")
+	}
+
+	if result := run("chat:history delete " + conv.ID); result.IsError {
+		t.Errorf("Expected the conversation to be deleted, got:\n%s", result.Output)
+	}
+	if convs, _ := chat.SavedConversations(); len(convs) != 0 {
+		t.Errorf("Expected no saved conversations, got %d", len(convs))
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestClassify tests routing obvious requests locally while questions stay with the AI
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		input    string
+		routed   bool
+		wantType nlp.CommandType
+		intent   string
+	}{
+		{"check system health", true, nlp.CommandTypeSystemHealth, ""},
+		{"How is my laptop doing?", true, nlp.CommandTypeSystemHealth, ""},
+		{"please show disk usage", true, nlp.CommandTypeSystemHealth, ""},
+		{"system info", true, nlp.CommandTypeSystemReport, ""},
+		{"what are my specs", true, nlp.CommandTypeSystemReport, ""},
+		{"use ollama", true, nlp.CommandTypeConfig, "provider set ollama"},
+		{"switch to ollama", false, nlp.CommandTypeUnknown, ""},
+		{"use ollama instead", false, nlp.CommandTypeUnknown, ""},
+		{"use ollama for this", false, nlp.CommandTypeUnknown, ""},
+		{"change provider to OpenAI", true, nlp.CommandTypeConfig, "provider set openai"},
+		{"use a hammer", false, nlp.CommandTypeUnknown, ""},
+		{"set model to gpt-4o", true, nlp.CommandTypeConfig, "model set gpt-4o"},
+		{"which model am I using", true, nlp.CommandTypeConfig, "model show"},
+		{"what can you do", true, nlp.CommandTypeHelp, "help"},
+		{"check my upload speed", true, nlp.CommandTypeSpeedTest, "upload"},
+		{"how do I check disk usage in Python", false, nlp.CommandTypeUnknown, ""},
+		{"explain system health metrics", false, nlp.CommandTypeUnknown, ""},
+		{"what is the capital of France", false, nlp.CommandTypeUnknown, ""},
+		{"ls -la", true, nlp.CommandTypeShell, "ls -la"},
+		{"ls my holiday photos", false, nlp.CommandTypeUnknown, ""},
+		{"git status", true, nlp.CommandTypeShell, "git status"},
+		{"git push --force", false, nlp.CommandTypeUnknown, ""},
+		{"ls -la; rm -rf x", false, nlp.CommandTypeUnknown, ""},
+	}
+
+	for _, tc := range testCases {
+		classification, ok := nlp.Classify(tc.input, nlp.ClassifyOptions{AllowShell: true})
+		if ok != tc.routed {
+			t.Errorf("Classify(%q): expected routed=%v, got %v (%+v)", tc.input, tc.routed, ok, classification)
+			continue
+		}
+		if ok && (classification.Type != tc.wantType || classification.Intent != tc.intent) {
+			t.Errorf("Classify(%q): expected type %v intent %q, got type %v intent %q",
+				tc.input, tc.wantType, tc.intent, classification.Type, classification.Intent)
+		}
+	}
+
+	// Shell commands need the shell to be allowed
+	if _, ok := nlp.Classify("ls -la", nlp.ClassifyOptions{}); ok {
+		t.Errorf("Expected shell commands not to be routed when the shell is not allowed")
+	}
+
+	// The model catches phrasings the rules miss, but only when enabled
+	if _, ok := nlp.Classify("is my internet fast", nlp.ClassifyOptions{}); ok {
+		t.Errorf("Expected the model to be off by default")
+	}
+	classification, ok := nlp.Classify("is my internet fast", nlp.ClassifyOptions{UseModel: true})
+	if !ok || classification.Type != nlp.CommandTypeSpeedTest || classification.Source != "model" {
+		t.Errorf("Expected the model to route a speed question, got %+v", classification)
+	}
+	if _, ok := nlp.Classify("how do I check memory in rust", nlp.ClassifyOptions{UseModel: true}); ok {
+		t.Errorf("Expected the model to leave programming questions to the AI")
+	}
+}
+
+// TestParserLocalRouting tests that the parser uses the classifier only when enabled
+func TestParserLocalRouting(t *testing.T) {
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+
+	cmd, err := parser.Parse("disk usage")
+	if err != nil || cmd.Type != nlp.CommandTypeSystemHealth || cmd.Parameters["classifier"] != "health" {
+		t.Errorf("Expected disk usage to be routed to a health check, got %+v (%v)", cmd, err)
+	}
+
+	// Shell commands typed interactively follow enable_shell_in_interactive
+	cmd, _ = parser.Parse("pwd")
+	if cmd.Type == nlp.CommandTypeShell {
+		t.Errorf("Expected no shell routing while interactive shell commands are disabled")
+	}
+	cfg.EnableShellInInteractive = true
+	cmd, _ = parser.Parse("pwd")
+	if cmd.Type != nlp.CommandTypeShell {
+		t.Errorf("Expected pwd to run locally, got type %v", cmd.Type)
+	}
+
+	cfg.LocalIntentRouting = false
+	cmd, _ = parser.Parse("disk usage")
+	if cmd.Type != nlp.CommandTypeAI {
+		t.Errorf("Expected disk usage to go to the AI with local routing off, got type %v", cmd.Type)
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestCLIParse tests reading global flags ahead of the command
+func TestCLIParse(t *testing.T) {
+	opts, args, err := cli.Parse([]string{"-q", "--out", "r.txt", "--append", "--provider=OpenAI", "report:"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !opts.Quiet || opts.Out != "r.txt" || !opts.Append || opts.Provider != "openai" {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if strings.Join(args, " ") != "report:" {
+		t.Errorf("Expected the command to be left, got %v", args)
+	}
+
+	// Flags after the command belong to the command
+	opts, args, _ = cli.Parse([]string{"shell:grep", "-q", "--copy", "x"})
+	if opts.Quiet || opts.Copy != 0 || len(args) != 4 {
+		t.Errorf("Expected flags after the command to be left alone, got %+v %v", opts, args)
+	}
+
+	if opts, _, _ := cli.Parse([]string{"--copy", "list", "files"}); opts.Copy != 1 {
+		t.Errorf("Expected block 1 for a plain --copy, got %d", opts.Copy)
+	}
+	if opts, _, _ := cli.Parse([]string{"--copy=3", "x"}); opts.Copy != 3 {
+		t.Errorf("Expected block 3, got %d", opts.Copy)
+	}
+	if opts, _, _ := cli.Parse([]string{"-c", "work.json", "notes"}); opts.Config != "work.json" {
+		t.Errorf("Expected the config file, got %q", opts.Config)
+	}
+	if _, args, _ := cli.Parse([]string{"--", "--help", "me"}); strings.Join(args, " ") != "--help me" {
+		t.Errorf("Expected -- to end the flags, got %v", args)
+	}
+	if _, args, _ := cli.Parse([]string{"-q", "-"}); len(args) != 1 || args[0] != "-" {
+		t.Errorf("Expected - to be the command, got %v", args)
+	}
+
+	for _, bad := range [][]string{
+		{"--out"},
+		{"--append", "ask:hi"},
+		{"--copy=first"},
+		{"--quiet=yes"},
+		{"--frobnicate", "ask:hi"},
+		{"--record", "a.json", "--replay", "b.json", "ask:hi"},
+	} {
+		if _, _, err := cli.Parse(bad); err == nil {
+			t.Errorf("Expected %v to fail", bad)
+		}
+	}
+}
+
+// TestCLIIsCommand tests telling commands from questions
+func TestCLIIsCommand(t *testing.T) {
+	for _, command := range []string{"ask:hi", "config:provider show", "notes add x", "create:react", "server:status", "desktop:toggle mute"} {
+		if !cli.IsCommand(command) {
+			t.Errorf("Expected %q to be a command", command)
+		}
+	}
+	for _, question := range []string{"how do I list files", "creative ideas for a server"} {
+		if cli.IsCommand(question) {
+			t.Errorf("Expected %q to be a question", question)
+		}
+	}
+	if !strings.Contains(cli.Usage(), "-q, --quiet") {
+		t.Errorf("Expected the usage to list --quiet, got:\n%s", cli.Usage())
+	}
+}
+
+// TestCLIIsCommandWords tests that every command word the parser knows is a
+// command, so it is not sent to the AI before the parser sees it
+func TestCLIIsCommandWords(t *testing.T) {
+	parser := nlp.NewParser(&config.Config{})
+	for _, word := range nlp.CommandWords() {
+		for _, command := range []string{word, word + ":", word + " x"} {
+			if !cli.IsCommand(command) {
+				t.Errorf("Expected %q to be a command", command)
+			}
+		}
+		if cmd, _ := parser.Parse(word); cmd.Type == nlp.CommandTypeAI {
+			t.Errorf("Expected the parser to run %q, got an AI query", word)
+		}
+	}
+}
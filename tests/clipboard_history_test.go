@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/clipboard"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestClipboardHistory tests recording, recalling, pinning, and pruning the
+// clipboard history, and that it is not kept in plain text
+func TestClipboardHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	for _, text := range []string{"first secret", "second", "second", "third"} {
+		if _, err := clipboard.Record(text, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := clipboard.History()
+	if err != nil || len(entries) != 2 || entries[0].Text != "third" || entries[1].Text != "second" {
+		t.Fatalf("Expected the two newest entries, got %+v (%v)", entries, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".local", "share", "lumo", "clipboard-history.enc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "third") {
+		t.Error("Expected the history to be encrypted")
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.ClipboardHistoryLimit = 2
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	provider := &MockClipboardProvider{}
+	exec.SetClipboard(clipboard.NewClipboardWithProvider(provider))
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil || cmd.Type != nlp.CommandTypeClipboard {
+			t.Fatalf("Expected %q to parse as a clipboard command, got %+v (%v)", input, cmd, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("clipboard history"); !strings.Contains(result.Output, "1.") || !strings.Contains(result.Output, "third") {
+		t.Errorf("Expected the history listed:\n%s", result.Output)
+	}
+	if result := run("clipboard get 2"); result.IsError || provider.content != "second" {
+		t.Errorf("Expected entry 2 copied back, got %q: %+v", provider.content, result)
+	}
+	if result := run("clipboard get 9"); !result.IsError {
+		t.Errorf("Expected a missing entry to fail: %+v", result)
+	}
+	// Without an entry number it is text to copy
+	if run("clipboard get milk"); provider.content != "get milk" {
+		t.Errorf("Expected text to be copied, got %q", provider.content)
+	}
+
+	// The picker asks which entry to copy, taking the newest by default
+	pick := func(answers string) *executor.Result {
+		t.Helper()
+		stdin, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stdin.Close()
+		writer.WriteString(answers)
+		writer.Close()
+		output, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer output.Close()
+		oldStdin, oldStdout := os.Stdin, os.Stdout
+		os.Stdin, os.Stdout = stdin, output
+		defer func() { os.Stdin, os.Stdout = oldStdin, oldStdout }()
+		return run("clipboard pick")
+	}
+	if result := pick("9\nmilk\n2\n"); result.IsError || provider.content != "second" {
+		t.Errorf("Expected entry 2 picked after the invalid answers, got %q: %+v", provider.content, result)
+	}
+	if result := pick("\n"); result.IsError || provider.content != "third" {
+		t.Errorf("Expected the newest entry picked by default, got %q: %+v", provider.content, result)
+	}
+	provider.content = ""
+	if result := pick(""); result.IsError || provider.content != "" || result.Output != "Nothing copied" {
+		t.Errorf("Expected nothing copied without an answer, got %q: %+v", provider.content, result)
+	}
+
+	// A pinned entry outlives the limit and clearing
+	if result := run("clipboard pin 2"); result.IsError {
+		t.Fatalf("Expected entry 2 pinned: %+v", result)
+	}
+	for _, text := range []string{"fourth", "fifth", "sixth"} {
+		if _, err := clipboard.Record(text, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, _ = clipboard.History()
+	if len(entries) != 3 || entries[2].Text != "second" || !entries[2].Pinned {
+		t.Errorf("Expected the pinned entry kept, got %+v", entries)
+	}
+	run("clipboard history clear")
+	entries, _ = clipboard.History()
+	if len(entries) != 1 || entries[0].Text != "second" {
+		t.Errorf("Expected only the pinned entry left, got %+v", entries)
+	}
+}
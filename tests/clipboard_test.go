@@ -218,3 +218,34 @@ func TestClipboardWithPipedInput(t *testing.T) {
 		t.Errorf("Expected mock provider content to contain '%s', got '%s'", appendContent, mockProvider.content)
 	}
 }
+
+// TestCopyCodeBlock tests copying a code block from an AI response
+func TestCopyCodeBlock(t *testing.T) {
+	response := "List them with:\n```bash\nls -la\n```\nor in Python:\n```python\nimport os\nprint(os.listdir('.'))\n```\n"
+
+	blocks := clipboard.ExtractCodeBlocks(response)
+	if len(blocks) != 2 || blocks[0].Language != "bash" || blocks[0].Code != "ls -la" {
+		t.Fatalf("Unexpected code blocks: %+v", blocks)
+	}
+
+	mockProvider := &MockClipboardProvider{}
+	clip := clipboard.NewClipboardWithProvider(mockProvider)
+
+	message, err := clip.CopyCodeBlock(response, 2)
+	if err != nil {
+		t.Fatalf("Failed to copy code block: %v", err)
+	}
+	if mockProvider.content != "import os\nprint(os.listdir('.'))" {
+		t.Errorf("Expected the second block on the clipboard, got %q", mockProvider.content)
+	}
+	if !strings.Contains(message, "block 2 of 2 (python)") {
+		t.Errorf("Expected the message to name the copied block, got %q", message)
+	}
+
+	if _, err := clip.CopyCodeBlock(response, 3); err == nil {
+		t.Errorf("Expected an error for a block that does not exist")
+	}
+	if _, err := clip.CopyCodeBlock("No code here.", 1); err == nil {
+		t.Errorf("Expected an error for a response without code")
+	}
+}
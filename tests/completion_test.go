@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/completion"
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// TestComplete tests completing partial command lines
+func TestComplete(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "ollama"
+	cfg.OllamaModel = "qwen2.5"
+
+	testCases := []struct {
+		line     string
+		expected []string
+	}{
+		{"con", []string{"connect", "config:", "contacts"}},
+		{"config:pro", []string{"config:provider"}},
+		{"config:provider ", []string{"list", "show", "set"}},
+		{"config:provider set o", []string{"ollama", "openai"}},
+		{"config:model set ", []string{"qwen2.5", "gemini-2.0-flash-lite", "gpt-3.5-turbo"}},
+		{"clipboard a", []string{"append"}},
+		{"connect --port 8080 --r", []string{"--receive"}},
+		{"completion ", completion.Shells},
+		{"ask: how do I", nil},
+	}
+
+	for _, tc := range testCases {
+		got := completion.Complete(cfg, tc.line)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Complete(%q) = %v, expected %v", tc.line, got, tc.expected)
+		}
+	}
+}
+
+// TestCompletionScripts tests generating completion scripts
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range completion.Shells {
+		script, err := completion.Script(shell)
+		if err != nil {
+			t.Errorf("Script(%q) failed: %v", shell, err)
+			continue
+		}
+		if !strings.Contains(script, "lumo __complete") {
+			t.Errorf("Script for %s does not ask lumo for completions", shell)
+		}
+	}
+
+	if _, err := completion.Script("tcsh"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
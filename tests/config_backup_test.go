@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestConfigBackups tests that saving keeps earlier versions and that they
+// can be restored
+func TestConfigBackups(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path := filepath.Join(home, ".config", "lumo", "config.json")
+
+	cfg := config.DefaultConfig()
+	cfg.JWTSecret = "test-secret"
+	for _, model := range []string{"llama3", "mistral", "phi3", "qwen2", "gemma2"} {
+		cfg.OllamaModel = model
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("Failed to save: %v", err)
+		}
+	}
+	// Saving unchanged settings keeps the backups as they are
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	backups, err := config.Backups()
+	if err != nil || len(backups) != config.BackupCount {
+		t.Fatalf("Expected %d backups, got %+v (%v)", config.BackupCount, backups, err)
+	}
+	if data, _ := os.ReadFile(backups[0].Path); !strings.Contains(string(data), `"qwen2"`) {
+		t.Errorf("Expected the newest backup to hold the previous settings, got:\n%s", data)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp")); len(leftovers) != 0 {
+		t.Errorf("Expected no temporary files left, got %v", leftovers)
+	}
+
+	// A damaged file is not overwritten by loading, and the newest valid
+	// backup replaces it
+	if err := os.WriteFile(path, []byte(`{"ollama_model": "gem`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"ollama_model": "gem` {
+		t.Fatalf("Expected the damaged file to be left alone, got:\n%s", data)
+	}
+
+	exec := executor.NewExecutor(cfg)
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: "restore-backup", RawInput: "config:restore-backup"})
+	if err != nil || result.IsError || !strings.Contains(result.Output, "backup 1") {
+		t.Fatalf("Expected the newest backup restored, got %+v (%v)", result, err)
+	}
+	restored, err := config.Load()
+	if err != nil || restored.OllamaModel != "qwen2" {
+		t.Errorf("Expected the restored settings, got %q (%v)", restored.OllamaModel, err)
+	}
+	// The damaged file became a backup, so asking for it is refused
+	result, _ = exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: "restore-backup 1", RawInput: "config:restore-backup 1"})
+	if !result.IsError || !strings.Contains(result.Output, "not a valid configuration") {
+		t.Errorf("Expected the damaged backup to be refused, got %+v", result)
+	}
+}
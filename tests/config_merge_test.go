@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+)
+
+// TestConfigConcurrentSaves tests that two lumo processes saving the same
+// configuration keep each other's changes and report real conflicts
+func TestConfigConcurrentSaves(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	// The daemon and a CLI session load the same file
+	daemon, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daemon.OllamaModel = "phi3"
+	daemon.ConnectRemotes = map[string]config.RemoteLogin{"nas.local": {Username: "me"}}
+	if err := daemon.Save(); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	cli.OpenAIModel = "gpt-4o"
+	if err := cli.Save(); err != nil {
+		t.Fatalf("Expected different settings to merge, got: %v", err)
+	}
+	if cli.OllamaModel != "phi3" || len(cli.ConnectRemotes) != 1 {
+		t.Errorf("Expected the daemon's changes in the CLI's settings, got %q %v", cli.OllamaModel, cli.ConnectRemotes)
+	}
+	saved, _ := config.Load()
+	if saved.OllamaModel != "phi3" || saved.OpenAIModel != "gpt-4o" {
+		t.Errorf("Expected both changes saved, got %q and %q", saved.OllamaModel, saved.OpenAIModel)
+	}
+
+	// The daemon picks up the CLI's change, and clearing a map is kept
+	daemon.ConnectRemotes = nil
+	if err := daemon.Save(); err != nil {
+		t.Fatalf("Expected the daemon to merge, got: %v", err)
+	}
+	if daemon.OpenAIModel != "gpt-4o" {
+		t.Errorf("Expected the CLI's change in the daemon's settings, got %q", daemon.OpenAIModel)
+	}
+
+	// Both changing the same settings is a conflict, and nothing is saved
+	daemon.OllamaModel = "mistral"
+	daemon.GeminiAPIKey = "daemon-secret"
+	if err := daemon.Save(); err != nil {
+		t.Fatal(err)
+	}
+	cli.OllamaModel = "qwen2"
+	cli.GeminiAPIKey = "cli-secret"
+	err = cli.Save()
+	var conflict *config.ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Conflicts) != 2 {
+		t.Fatalf("Expected a conflict on two settings, got %v", err)
+	}
+	message := err.Error()
+	if !strings.Contains(message, `ollama_model: "mistral" in the file, "qwen2" here`) ||
+		!strings.Contains(message, "gemini_api_key: (hidden)") || strings.Contains(message, "secret") {
+		t.Errorf("Unexpected conflict message:\n%s", message)
+	}
+	if saved, _ := config.Load(); saved.OllamaModel != "mistral" || len(saved.ConnectRemotes) != 0 {
+		t.Errorf("Expected the daemon's settings kept, got %q %v", saved.OllamaModel, saved.ConnectRemotes)
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestConfigTest tests sending a test prompt to every configured provider
+func TestConfigTest(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	ai.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := ""
+		switch {
+		case req.URL.Host == "api.openai.com":
+			body = `{"choices": [{"message": {"role": "assistant", "content": "OK"}}], "usage": {"prompt_tokens": 20, "completion_tokens": 1}}`
+		case req.URL.Path == "/api/tags":
+			body = `{"models": [{"name": "llama3:latest"}]}`
+		case req.URL.Path == "/api/chat":
+			body = `{"message": {"role": "assistant", "content": "OK"}, "done": true, "prompt_eval_count": 25, "eval_count": 2}`
+		default:
+			t.Errorf("Unexpected request to %s", req.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}))
+	defer ai.SetTransport(nil)
+
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "openai"
+	cfg.OpenAIAPIKey = "sk-test"
+	cfg.OpenAIModel = "gpt-4o-mini"
+	cfg.GeminiAPIKey = ""
+	cfg.OllamaModel = "llama3"
+	cfg.JWTSecret = "test-secret"
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	result := run("config:test")
+	if result.IsError {
+		t.Errorf("Expected every tested provider to answer:\n%s", result.Output)
+	}
+	for _, want := range []string{
+		"OpenAI (in use)", "gpt-4o-mini", "21 tokens, about $0.000004",
+		"Ollama", "llama3", "27 tokens, free",
+		"Gemini", "no API key",
+		"2 of 2 tested providers answered",
+	} {
+		if !strings.Contains(result.Output, want) {
+			t.Errorf("Expected %q in:\n%s", want, result.Output)
+		}
+	}
+	if strings.Contains(result.Output, "Mock") {
+		t.Errorf("Expected the mock provider to be left out while not in use:\n%s", result.Output)
+	}
+
+	cfg.OllamaModel = "mistral"
+	result = run("config:test ollama")
+	if !result.IsError || !strings.Contains(result.Output, "ollama pull mistral") {
+		t.Errorf("Expected a missing Ollama model to be reported:\n%s", result.Output)
+	}
+	if result := run("config:test gemini"); !result.IsError {
+		t.Errorf("Expected testing only a provider without a key to fail:\n%s", result.Output)
+	}
+	if result := run("config:test nothing"); !result.IsError || !strings.Contains(result.Output, "Unknown AI provider") {
+		t.Errorf("Expected an unknown provider to be refused:\n%s", result.Output)
+	}
+}
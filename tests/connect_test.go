@@ -0,0 +1,431 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/connect"
+)
+
+// TestChunkedServerUpload tests uploading a file to a session's chunked listener
+func TestChunkedServerUpload(t *testing.T) {
+	// Keep transfers out of the real transfer history
+	t.Setenv("HOME", t.TempDir())
+
+	downloadDir := t.TempDir()
+	server, err := connect.NewChunkedServer(downloadDir)
+	if err != nil {
+		t.Fatalf("NewChunkedServer failed: %v", err)
+	}
+	if err := server.Start(0); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Stop()
+	if server.Port() == 0 {
+		t.Fatal("Expected the listener to report its port")
+	}
+
+	// Two and a half chunks, so the last chunk is a partial one
+	content := bytes.Repeat([]byte("lumo"), connect.MinChunkSize*5/8)
+	source := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(source, content, 0644); err != nil {
+		t.Fatalf("Failed to write the source file: %v", err)
+	}
+
+	client := connect.NewChunkedClient(fmt.Sprintf("http://127.0.0.1:%d", server.Port()), "", connect.MinChunkSize)
+	received, err := client.UploadFile(source, nil)
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	if filepath.Dir(received) != downloadDir {
+		t.Errorf("Expected the file in %s, got %s", downloadDir, received)
+	}
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatalf("Failed to read the received file: %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("Received file differs from the original (%d vs %d bytes)", len(data), len(content))
+	}
+}
+
+// TestChunkedCleanupStale tests removing abandoned partial uploads
+func TestChunkedCleanupStale(t *testing.T) {
+	manager, err := connect.NewChunkedTransferManager(t.TempDir(), connect.MinChunkSize)
+	if err != nil {
+		t.Fatalf("NewChunkedTransferManager failed: %v", err)
+	}
+	defer manager.Cleanup()
+
+	upload, err := manager.InitUpload("partial.bin", 2*connect.MinChunkSize, "")
+	if err != nil {
+		t.Fatalf("InitUpload failed: %v", err)
+	}
+
+	if removed := manager.CleanupStale(time.Hour); removed != 0 {
+		t.Errorf("Expected an active upload to be kept, %d removed", removed)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if removed := manager.CleanupStale(time.Millisecond); removed != 1 {
+		t.Errorf("Expected 1 abandoned upload to be removed, got %d", removed)
+	}
+	if _, err := os.Stat(upload.TempPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the partial file to be deleted, got %v", err)
+	}
+	if err := manager.UploadChunk(upload.UploadID, 0, make([]byte, connect.MinChunkSize)); err == nil {
+		t.Error("Expected chunks for a removed upload to be rejected")
+	}
+}
+
+// TestChunkedResumeAfterRestart tests resuming an upload with a new manager on the same state directory
+func TestChunkedResumeAfterRestart(t *testing.T) {
+	// Keep transfers out of the real transfer history
+	t.Setenv("HOME", t.TempDir())
+
+	downloadDir := t.TempDir()
+	stateDir := t.TempDir()
+
+	first := bytes.Repeat([]byte("a"), connect.MinChunkSize)
+	second := bytes.Repeat([]byte("b"), connect.MinChunkSize/2)
+	content := append(append([]byte{}, first...), second...)
+	fileHash := fmt.Sprintf("%x", sha256.Sum256(content))
+
+	manager, err := connect.NewPersistentChunkedTransferManager(downloadDir, stateDir, connect.MinChunkSize)
+	if err != nil {
+		t.Fatalf("NewPersistentChunkedTransferManager failed: %v", err)
+	}
+	upload, err := manager.InitUpload("resumed.bin", int64(len(content)), fileHash)
+	if err != nil {
+		t.Fatalf("InitUpload failed: %v", err)
+	}
+	if err := manager.UploadChunk(upload.UploadID, 0, first); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+
+	// A new manager stands in for the restarted receiver
+	restarted, err := connect.NewPersistentChunkedTransferManager(downloadDir, stateDir, connect.MinChunkSize)
+	if err != nil {
+		t.Fatalf("NewPersistentChunkedTransferManager failed after restart: %v", err)
+	}
+	status, err := restarted.UploadStatus(upload.UploadID)
+	if err != nil {
+		t.Fatalf("UploadStatus failed after restart: %v", err)
+	}
+	if status.ReceivedChunks != 1 || len(status.MissingChunks) != 1 || status.MissingChunks[0] != 1 {
+		t.Fatalf("Expected chunk 1 to be missing, got %d received and missing %v", status.ReceivedChunks, status.MissingChunks)
+	}
+
+	if err := restarted.UploadChunk(upload.UploadID, 1, second); err != nil {
+		t.Fatalf("UploadChunk failed after restart: %v", err)
+	}
+	received, err := restarted.CompleteUpload(upload.UploadID)
+	if err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatalf("Failed to read the received file: %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Error("Resumed file differs from the original")
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(stateDir, "*"))
+	if len(leftovers) != 0 {
+		t.Errorf("Expected the state directory to be empty, found %v", leftovers)
+	}
+}
+
+// TestStagingApproveAndReject tests that staged files are encrypted on disk until accepted
+func TestStagingApproveAndReject(t *testing.T) {
+	staging, err := connect.NewStaging()
+	if err != nil {
+		t.Fatalf("NewStaging failed: %v", err)
+	}
+	defer staging.Close()
+
+	// More than one segment, so segment boundaries are exercised
+	content := bytes.Repeat([]byte("plaintext marker "), 100000)
+	accepted, err := staging.Stage("../report.txt", content)
+	if err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	rejected, err := staging.Stage("unwanted.sh", []byte("#!/bin/sh\necho hi\n"))
+	if err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	if accepted.Filename != "report.txt" {
+		t.Errorf("Expected the staged name to be sanitized, got %q", accepted.Filename)
+	}
+
+	// Nothing readable may reach the disk before approval
+	stagedFiles, _ := filepath.Glob(filepath.Join(os.TempDir(), "lumo-connect-staging-*", "*"))
+	for _, path := range stagedFiles {
+		data, err := os.ReadFile(path)
+		if err == nil && bytes.Contains(data, []byte("plaintext marker")) {
+			t.Errorf("Staged file %s contains plaintext", path)
+		}
+	}
+
+	if err := staging.Reject(rejected.ID); err != nil {
+		t.Fatalf("Reject failed: %v", err)
+	}
+	if _, err := staging.Approve(rejected.ID, t.TempDir()); err == nil {
+		t.Error("Expected a rejected file to be gone")
+	}
+
+	downloadDir := t.TempDir()
+	saved, err := staging.Approve(accepted.ID, downloadDir)
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	data, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatalf("Failed to read the approved file: %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Error("Approved file differs from the original")
+	}
+	if len(staging.List()) != 0 {
+		t.Errorf("Expected no pending files, got %d", len(staging.List()))
+	}
+}
+
+// TestChunkedStagedUpload tests that chunked uploads are held in staging instead of the download directory
+func TestChunkedStagedUpload(t *testing.T) {
+	// Keep transfers out of the real transfer history
+	t.Setenv("HOME", t.TempDir())
+
+	staging, err := connect.NewStaging()
+	if err != nil {
+		t.Fatalf("NewStaging failed: %v", err)
+	}
+	defer staging.Close()
+
+	downloadDir := t.TempDir()
+	manager, err := connect.NewChunkedTransferManager(downloadDir, connect.MinChunkSize)
+	if err != nil {
+		t.Fatalf("NewChunkedTransferManager failed: %v", err)
+	}
+	defer manager.Cleanup()
+	manager.UseStaging(staging)
+
+	content := append(bytes.Repeat([]byte("x"), connect.MinChunkSize), []byte("tail")...)
+	upload, err := manager.InitUpload("big.bin", int64(len(content)), fmt.Sprintf("%x", sha256.Sum256(content)))
+	if err != nil {
+		t.Fatalf("InitUpload failed: %v", err)
+	}
+	// Out of order, as a resumed upload would send them
+	if err := manager.UploadChunk(upload.UploadID, 1, content[connect.MinChunkSize:]); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+	if err := manager.UploadChunk(upload.UploadID, 0, content[:connect.MinChunkSize]); err != nil {
+		t.Fatalf("UploadChunk failed: %v", err)
+	}
+	if _, err := manager.CompleteUpload(upload.UploadID); err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+
+	if entries, _ := os.ReadDir(downloadDir); len(entries) != 0 {
+		t.Fatalf("Expected nothing in the download directory before approval, found %d entries", len(entries))
+	}
+	pending := staging.List()
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 staged file, got %d", len(pending))
+	}
+
+	saved, err := staging.Approve(pending[0].ID, downloadDir)
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	data, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatalf("Failed to read the approved file: %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Error("Approved file differs from the original")
+	}
+}
+
+// TestSignalStore tests exchanging session descriptions through the signaling mailbox
+func TestSignalStore(t *testing.T) {
+	store := connect.NewSignalStore()
+
+	if err := store.Put("abc123", "offer", "offer-sdp"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := store.Put("abc123", "offer", "other-sdp"); err == nil {
+		t.Error("Expected a second offer for the same code to be rejected")
+	}
+	if err := store.Put("abc123", "candidate", "x"); err == nil {
+		t.Error("Expected an unknown kind to be rejected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	offer, err := store.Wait(ctx, "abc123", "offer")
+	if err != nil || offer != "offer-sdp" {
+		t.Fatalf("Wait returned %q, %v; want the posted offer", offer, err)
+	}
+
+	// An answer that never arrives ends with the context
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer shortCancel()
+	if _, err := store.Wait(shortCtx, "abc123", "answer"); err == nil {
+		t.Error("Expected Wait to fail when no answer is posted")
+	}
+}
+
+// TestSignalStoreLimit tests that waiting creates no slots and that posts
+// are refused once the store holds MaxSignalSlots exchanges
+func TestSignalStoreLimit(t *testing.T) {
+	store := connect.NewSignalStore()
+
+	// A peer waiting before the other posts gets the description once it is
+	done := make(chan string)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		offer, _ := store.Wait(ctx, "early", "offer")
+		done <- offer
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if err := store.Put("early", "offer", "offer-sdp"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if offer := <-done; offer != "offer-sdp" {
+		t.Errorf("Expected the waiting peer to get the offer, got %q", offer)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2*connect.MaxSignalSlots; i++ {
+		store.Wait(cancelled, fmt.Sprintf("wait-%d", i), "offer")
+	}
+	for i := 1; i < connect.MaxSignalSlots; i++ {
+		if err := store.Put(fmt.Sprintf("code-%d", i), "offer", "sdp"); err != nil {
+			t.Fatalf("Expected post %d to fit in the store: %v", i, err)
+		}
+	}
+	if err := store.Put("one-more", "offer", "sdp"); !errors.Is(err, connect.ErrSignalStoreFull) {
+		t.Errorf("Expected a full store to refuse a new exchange, got %v", err)
+	}
+	if err := store.Put("code-1", "answer", "sdp"); err != nil {
+		t.Errorf("Expected exchanges already in the store to go on: %v", err)
+	}
+
+	server := httptest.NewServer(store)
+	defer server.Close()
+	resp, err := http.Post(server.URL+"?code=one-more&kind=offer", "application/json", strings.NewReader("sdp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 from a full store, got status %d", resp.StatusCode)
+	}
+}
+
+// TestWebRTCTransfer tests sending a file between two local WebRTC peers
+func TestWebRTCTransfer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/connect/signal", connect.NewSignalStore())
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// No STUN servers, so the test only uses host candidates
+	opts := connect.WebRTCOptions{
+		SignalURL:   server.URL,
+		Code:        "webrtc-test",
+		STUNServers: []string{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	receiveDir := t.TempDir()
+	type result struct {
+		peer *connect.WebRTCPeer
+		err  error
+	}
+	accepted := make(chan result, 1)
+	go func() {
+		peer, err := connect.AcceptWebRTC(ctx, opts, receiveDir, nil)
+		accepted <- result{peer, err}
+	}()
+
+	sender, err := connect.DialWebRTC(ctx, opts, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("DialWebRTC failed: %v", err)
+	}
+	defer sender.Close()
+
+	receiver := <-accepted
+	if receiver.err != nil {
+		t.Fatalf("AcceptWebRTC failed: %v", receiver.err)
+	}
+	defer receiver.peer.Close()
+
+	content := bytes.Repeat([]byte("lumo webrtc "), 10000)
+	source := filepath.Join(t.TempDir(), "payload.txt")
+	if err := os.WriteFile(source, content, 0644); err != nil {
+		t.Fatalf("Failed to write source file: %v", err)
+	}
+	if err := sender.SendFile(source); err != nil {
+		t.Fatalf("SendFile failed: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(receiveDir, "payload*.txt"))
+	if len(matches) != 1 {
+		t.Fatalf("Expected one received file, found %v", matches)
+	}
+	received, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("Failed to read received file: %v", err)
+	}
+	if !bytes.Equal(received, content) {
+		t.Error("Received file does not match the sent file")
+	}
+
+	// Both sides recorded the transfer against the session's peer
+	history, err := connect.TransferHistory(0)
+	if err != nil {
+		t.Fatalf("TransferHistory failed: %v", err)
+	}
+	directions := map[string]bool{}
+	for _, transfer := range history {
+		if transfer.Filename == "payload.txt" && transfer.Transport == "webrtc" && transfer.Success {
+			directions[transfer.Direction] = true
+			if transfer.Bytes != int64(len(content)) || transfer.AverageRate <= 0 || transfer.PeakRate < transfer.AverageRate {
+				t.Errorf("Unexpected statistics for %s transfer: %+v", transfer.Direction, transfer)
+			}
+		}
+	}
+	if !directions["sent"] || !directions["received"] {
+		t.Errorf("Expected the transfer to be recorded in both directions, got %v", directions)
+	}
+
+	for _, peer := range connect.SessionStats() {
+		if peer.Peer == "webrtc:webrtc-test" {
+			if peer.FilesSent < 1 || peer.FilesReceived < 1 || peer.BytesSent < int64(len(content)) {
+				t.Errorf("Unexpected session statistics: %+v", peer)
+			}
+			return
+		}
+	}
+	t.Error("No session statistics for the WebRTC peer")
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/contacts"
+	"github.com/agnath18K/lumo/pkg/discovery"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestSendParsing tests that only sends with a recipient leave the AI
+func TestSendParsing(t *testing.T) {
+	parser := nlp.NewParser(config.DefaultConfig())
+	tests := []struct {
+		input string
+		want  nlp.CommandType
+	}{
+		{"send report.pdf to laptop-anu", nlp.CommandTypeSend},
+		{"send a.txt b.txt to 192.168.1.5:9000", nlp.CommandTypeSend},
+		{"send", nlp.CommandTypeSend},
+		{"contacts", nlp.CommandTypeContacts},
+		{"contacts list", nlp.CommandTypeContacts},
+		{"contacts add nas 192.168.1.20", nlp.CommandTypeContacts},
+	}
+	for _, tt := range tests {
+		cmd, err := parser.Parse(tt.input)
+		if err != nil || cmd.Type != tt.want {
+			t.Errorf("Parse(%q) = %+v (%v), want type %v", tt.input, cmd, err, tt.want)
+		}
+		if !cli.IsCommand(tt.input) {
+			t.Errorf("Expected %q to be a command", tt.input)
+		}
+	}
+	for _, input := range []string{"send to", "contacts app for linux"} {
+		cmd, err := parser.Parse(input)
+		if err == nil && (cmd.Type == nlp.CommandTypeSend || cmd.Type == nlp.CommandTypeContacts) {
+			t.Errorf("Expected %q not to parse as a command, got %v", input, cmd.Type)
+		}
+	}
+}
+
+// TestContacts tests saving contacts and refreshing their addresses from
+// discovery
+func TestContacts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("contacts add laptop-anu anus-laptop"); result.IsError {
+		t.Fatalf("Expected the contact to be saved: %+v", result)
+	}
+	if result := run("contacts add nas 192.168.1.20:9000"); result.IsError {
+		t.Fatalf("Expected the contact to be saved: %+v", result)
+	}
+	if result := run("contacts add bad not/a/host"); !result.IsError {
+		t.Errorf("Expected an invalid address to be refused: %+v", result)
+	}
+	result := run("contacts")
+	if !strings.Contains(result.Output, "laptop-anu") || !strings.Contains(result.Output, "not seen yet") ||
+		!strings.Contains(result.Output, "192.168.1.20:9000") {
+		t.Errorf("Expected both contacts listed:\n%s", result.Output)
+	}
+
+	// Discovery finds the laptop by hostname and the NAS by address
+	seenAt := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.Local)
+	seen, err := contacts.Refresh([]discovery.Service{
+		{Type: discovery.ServiceName, IP: "192.168.1.42", Port: 8090, LastSeen: seenAt,
+			Info: map[string]string{discovery.InfoHostname: "ANUS-LAPTOP"}},
+		{Type: discovery.InstanceServiceName, IP: "192.168.1.20", Port: 7531, LastSeen: seenAt,
+			Info: map[string]string{discovery.InfoHostname: "nas"}},
+	})
+	if err != nil || len(seen) != 2 {
+		t.Fatalf("Expected both contacts seen, got %+v (%v)", seen, err)
+	}
+	laptop, err := contacts.Get("Laptop-Anu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address, port := laptop.Target(); address != "192.168.1.42" || port != 8090 || !laptop.LastSeen.Equal(seenAt) {
+		t.Errorf("Expected the laptop's address refreshed, got %+v", laptop)
+	}
+	// A server instance tells the hostname but not the receiver's port
+	nas, _ := contacts.Get("nas")
+	if address, port := nas.Target(); nas.Hostname != "nas" || address != "192.168.1.20" || port != 9000 {
+		t.Errorf("Expected the NAS's hostname learned and port kept, got %+v", nas)
+	}
+
+	if result := run("send missing.pdf to nas"); !result.IsError || !strings.Contains(result.Output, "missing.pdf") {
+		t.Errorf("Expected a missing file to be refused: %+v", result)
+	}
+	if result := run("send contacts_test.go to nobody"); !result.IsError || !strings.Contains(result.Output, "contacts add nobody") {
+		t.Errorf("Expected an unknown contact to be refused: %+v", result)
+	}
+	if result := run("contacts remove nas"); result.IsError {
+		t.Errorf("Expected the contact removed: %+v", result)
+	}
+	if _, err := contacts.Get("nas"); err == nil {
+		t.Error("Expected the contact to be gone")
+	}
+}
@@ -1,7 +1,17 @@
 package tests
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/create"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/tests/mocks"
 )
 
 // TestCreateShowHelp tests the help text display for the create command
@@ -105,3 +115,612 @@ func TestCreateProject(t *testing.T) {
 func TestCreateGenerateProject(t *testing.T) {
 	t.Skip("Skipping test that requires mocking the create generator")
 }
+
+// TestCreateGoProject tests scaffolding a Go module in each layout, and
+// runs the generated tests when a Go toolchain is available
+func TestCreateGoProject(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	generator := create.NewGenerator(nil)
+	layouts := map[string][]string{
+		"std":       {"cmd/svc_std/main.go", "internal/server/server_test.go"},
+		"cmd":       {"cmd/server/main.go", "cmd/healthcheck/main.go", "internal/server/server.go"},
+		"hexagonal": {"cmd/svc_hexagonal/main.go", "internal/core/ports/ports.go", "internal/adapters/httpapi/handler_test.go"},
+	}
+	goTool, _ := exec.LookPath("go")
+
+	for layout, expected := range layouts {
+		t.Run(layout, func(t *testing.T) {
+			name := "svc_" + layout
+			output, err := generator.Execute("go " + name + " --layout=" + layout + " --module example.com/" + name)
+			if err != nil {
+				t.Fatalf("Execute() error: %v", err)
+			}
+			if !strings.Contains(output, "Go project '"+name+"' created") {
+				t.Errorf("Unexpected output:\n%s", output)
+			}
+
+			for _, file := range append(expected, "go.mod", "Makefile", ".golangci.yml", "internal/config/config_test.go") {
+				if _, err := os.Stat(filepath.Join(name, file)); err != nil {
+					t.Errorf("Expected %s to be generated", file)
+				}
+			}
+			goMod, _ := os.ReadFile(filepath.Join(name, "go.mod"))
+			if !strings.HasPrefix(string(goMod), "module example.com/"+name+"\n") {
+				t.Errorf("Expected the module path in go.mod, got:\n%s", goMod)
+			}
+
+			if goTool == "" || testing.Short() {
+				return
+			}
+			for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+				cmd := exec.Command(goTool, args...)
+				cmd.Dir = name
+				cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Errorf("go %s failed in the generated project: %v\n%s", args[0], err, out)
+				}
+			}
+		})
+	}
+
+	// An existing project is never overwritten
+	if _, err := generator.Execute("go svc_std"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error for an existing directory, got %v", err)
+	}
+}
+
+// TestCreateGoArguments tests the arguments create go rejects, and that
+// it needs no AI provider
+func TestCreateGoArguments(t *testing.T) {
+	generator := create.NewGenerator(nil)
+	for query, want := range map[string]string{
+		"go":                    "project name is required",
+		"go app --layout onion": "unknown layout",
+		"go app --layout":       "--layout needs a value",
+		"go app --verbose":      "unknown option",
+		"go app other":          "unexpected argument",
+		"go ../app":             "invalid project name",
+	} {
+		if _, err := generator.Execute(query); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Execute(%q) error = %v, want %q", query, err, want)
+		}
+	}
+
+	if create.NeedsAI("go app") || create.NeedsAI("Go app --layout cmd") {
+		t.Error("Expected create go to need no AI provider")
+	}
+	if !create.NeedsAI("Flutter app with bloc architecture") {
+		t.Error("Expected descriptions to need an AI provider")
+	}
+
+	parser := nlp.NewParser(config.DefaultConfig())
+	cmd, err := parser.Parse("create go app --layout cmd")
+	if err != nil || cmd.Type != nlp.CommandTypeCreate || cmd.Intent != "go app --layout cmd" {
+		t.Errorf("Expected create go to be a create command, got %+v (%v)", cmd, err)
+	}
+}
+
+// TestCreateReactTool tests the --tool option, which is checked before the
+// description is sent to an AI provider
+func TestCreateReactTool(t *testing.T) {
+	generator := create.NewGenerator(nil)
+	for query, want := range map[string]string{
+		"React app with Redux --tool parcel": "unknown tool: parcel",
+		"React app --tool=webpack":           "unknown tool: webpack",
+		"React app with Redux --tool":        "--tool needs a value",
+	} {
+		if _, err := generator.Execute(query); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Execute(%q) error = %v, want %q", query, err, want)
+		}
+	}
+}
+
+// TestCreateTemplates tests listing templates and creating projects from
+// user templates
+func TestCreateTemplates(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// Every built-in template extends templates that exist
+	templates, err := create.Templates()
+	if err != nil {
+		t.Fatalf("Templates() error: %v", err)
+	}
+	for _, name := range []string{"flutter-bloc", "nextjs-zustand", "react-vite-redux", "react-cra-mobx", "fastapi", "go-hexagonal"} {
+		if _, err := create.LookupTemplate(name); err != nil {
+			t.Errorf("Expected the built-in template %s: %v", name, err)
+		}
+	}
+	for _, tmpl := range templates {
+		for _, base := range tmpl.Extends {
+			if _, err := create.LookupTemplate(base); err != nil {
+				t.Errorf("Template %s extends a missing template: %v", tmpl.Name, err)
+			}
+		}
+	}
+
+	userDir, err := create.UserTemplateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTemplate := func(name, manifest string, files map[string]string) {
+		for path, content := range files {
+			path = filepath.Join(userDir, name, "files", path)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		os.MkdirAll(filepath.Join(userDir, name), 0755)
+		if err := os.WriteFile(filepath.Join(userDir, name, create.ManifestFile), []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTemplate("notes", `{"description": "Markdown notes", "extends": ["go"], "dirs": ["drafts"]}`, map[string]string{
+		"README.md.tmpl":      "# {{.Name}}\n",
+		"{{.Name}}.md":        "notes\n",
+		"_gitignore":          "drafts/\n",
+		"templates/page.html": "{{ .Title }}\n",
+	})
+	writeTemplate("fastapi", `{"description": "My FastAPI"}`, map[string]string{"main.py": "app = None\n"})
+	writeTemplate("needs-tool", `{"requires": ["lumo-missing-tool"]}`, nil)
+	writeTemplate("loop", `{"extends": ["loop"]}`, nil)
+
+	generator := create.NewGenerator(nil)
+	output, err := generator.Execute("list-templates")
+	if err != nil {
+		t.Fatalf("list-templates error: %v", err)
+	}
+	for _, want := range []string{"go-hexagonal", "notes (user)", "My FastAPI", userDir} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the template list:\n%s", want, output)
+		}
+	}
+
+	if output, err = generator.Execute("from notes journal"); err != nil {
+		t.Fatalf("from notes error: %v", err)
+	}
+	expected := map[string]string{
+		"README.md":           "# journal\n",
+		"journal.md":          "notes\n",
+		".gitignore":          "drafts/\n",
+		"templates/page.html": "{{ .Title }}\n",
+		"go.mod":              "module journal\n",
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join("journal", path))
+		if err != nil || !strings.HasPrefix(string(content), want) {
+			t.Errorf("Expected %s to start with %q, got %q (%v)", path, want, content, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join("journal", "drafts")); err != nil {
+		t.Errorf("Expected the drafts directory: %v", err)
+	}
+	if !strings.Contains(output, "journal.md") {
+		t.Errorf("Expected the files in the output:\n%s", output)
+	}
+
+	// A user template replaces the built-in one of the same name
+	if _, err := generator.Execute("from fastapi api"); err != nil {
+		t.Fatalf("from fastapi error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("api", "main.py")); err != nil {
+		t.Errorf("Expected the user's fastapi template: %v", err)
+	}
+
+	for query, want := range map[string]string{
+		"from notes journal":    "journal already exists",
+		"from teleport app":     "unknown template: teleport",
+		"from notes":            "a template and a project name are required",
+		"from notes ../outside": "invalid project name",
+		"from needs-tool app":   "lumo-missing-tool is not installed",
+		"from loop app":         "extends itself",
+	} {
+		if _, err := generator.Execute(query); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Execute(%q) error = %v, want %q", query, err, want)
+		}
+	}
+	if create.NeedsAI("list-templates") || create.NeedsAI("from notes journal") {
+		t.Error("Expected templates to be used without AI")
+	}
+
+	parser := nlp.NewParser(config.DefaultConfig())
+	cmd, err := parser.Parse("create from notes journal")
+	if err != nil || cmd.Type != nlp.CommandTypeCreate || cmd.Intent != "from notes journal" {
+		t.Errorf("Expected a create command, got %+v (%v)", cmd, err)
+	}
+}
+
+// TestCreateDocker tests creating Go projects with --docker, and that
+// templates without Docker files refuse it
+func TestCreateDocker(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	generator := create.NewGenerator(nil)
+	for query, dockerfile := range map[string]string{
+		"go svc_std --docker":              "./cmd/svc_std",
+		"go svc_cmd --layout cmd --docker": `HEALTHCHECK --interval=30s --timeout=5s CMD ["/healthcheck"]`,
+	} {
+		output, err := generator.Execute(query)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", query, err)
+		}
+		name := strings.Fields(query)[1]
+		if !strings.Contains(output, "cd "+name+" && docker compose up --build") {
+			t.Errorf("Expected how to start the containers in the output:\n%s", output)
+		}
+		for path, want := range map[string]string{
+			"Dockerfile":         dockerfile,
+			".dockerignore":      "bin",
+			"docker-compose.yml": `"8080:8080"`,
+		} {
+			content, err := os.ReadFile(filepath.Join(name, path))
+			if err != nil || !strings.Contains(string(content), want) {
+				t.Errorf("Expected %s/%s to contain %q (%v)", name, path, want, err)
+			}
+		}
+	}
+	if _, err := generator.Execute("go svc_plain"); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("svc_plain", "Dockerfile")); err == nil {
+		t.Error("Expected a Dockerfile only with --docker")
+	}
+
+	userDir, err := create.UserTemplateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(userDir, "notes"), 0755)
+	if err := os.WriteFile(filepath.Join(userDir, "notes", create.ManifestFile), []byte(`{"description": "Markdown notes"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generator.Execute("from notes journal --docker"); err == nil || !strings.Contains(err.Error(), "the notes template has no Docker files") {
+		t.Errorf("Expected --docker to be refused, got %v", err)
+	}
+	if _, err := os.Stat("journal"); err == nil {
+		t.Error("Expected no project to be created")
+	}
+
+	output, err := generator.Execute("list-templates")
+	if err != nil {
+		t.Fatalf("list-templates error: %v", err)
+	}
+	for _, want := range []string{"go-cmd (--docker)", "flask (--docker)", "notes (user)\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the template list:\n%s", want, output)
+		}
+	}
+}
+
+// TestCreateGit tests that projects are committed to a new git repository
+// unless they are created with --no-git or inside another repository
+func TestCreateGit(t *testing.T) {
+	gitTool, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "Lumo Test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command(gitTool, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	generator := create.NewGenerator(nil)
+	output, err := generator.Execute("go svc --git")
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if !strings.Contains(output, "Initialized git with a first commit") {
+		t.Errorf("Expected the commit in the output:\n%s", output)
+	}
+	if log := git("svc", "log", "--format=%s"); log != "Initial commit" {
+		t.Errorf("Expected one initial commit, got %q", log)
+	}
+	if status := git("svc", "status", "--porcelain"); status != "" {
+		t.Errorf("Expected every file to be committed, got:\n%s", status)
+	}
+	if files := git("svc", "ls-files"); !strings.Contains(files, ".gitignore") || !strings.Contains(files, "go.mod") {
+		t.Errorf("Expected .gitignore and go.mod to be committed, got:\n%s", files)
+	}
+
+	if output, err = generator.Execute("go plain --no-git"); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("plain", ".git")); err == nil || strings.Contains(output, "📚") {
+		t.Errorf("Expected --no-git to leave the project out of git:\n%s", output)
+	}
+
+	// A project inside a repository is left to it
+	if err := os.Chdir("svc"); err != nil {
+		t.Fatal(err)
+	}
+	if output, err = generator.Execute("from go-std nested"); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("nested", ".git")); err == nil || !strings.Contains(output, "Left version control to the git repository") {
+		t.Errorf("Expected no repository inside another:\n%s", output)
+	}
+}
+
+// TestCreateDjangoExpress tests the Django and Express generators, with
+// stand-ins for Python and npm
+func TestCreateDjangoExpress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stand-in programs are shell scripts")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// python3 -m venv venv creates a pip that does nothing
+	bin := t.TempDir()
+	scripts := map[string]string{
+		"python3": "#!/bin/sh\nmkdir -p venv/bin && printf '#!/bin/sh\\n' > venv/bin/pip && chmod +x venv/bin/pip\n",
+		"npm":     "#!/bin/sh\nexit 0\n",
+		"node":    "#!/bin/sh\nexit 0\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	aiClient := mocks.NewMockAIClient()
+	generator := create.NewGenerator(aiClient)
+	projects := []struct {
+		response string
+		name     string
+		files    map[string]string
+	}{
+		{
+			response: `{"projectType": "python", "framework": "django", "options": {"name": "mysite"}}`,
+			name:     "mysite",
+			files: map[string]string{
+				"manage.py":          "'mysite.settings'",
+				"mysite/settings.py": "ROOT_URLCONF = 'mysite.urls'",
+				"pages/views.py":     "def health",
+			},
+		},
+		{
+			response: `{"projectType": "express", "options": {"name": "api"}}`,
+			name:     "api",
+			files: map[string]string{
+				"package.json": `"name": "api"`,
+				"Dockerfile":   "npm ci --omit=dev",
+				"src/app.ts":   "createApp",
+			},
+		},
+	}
+	for _, project := range projects {
+		aiClient.QueryResponse = project.response
+		output, err := generator.Execute("a project")
+		if err != nil {
+			t.Fatalf("Execute() error for %s: %v", project.name, err)
+		}
+		if !strings.Contains(output, "'"+project.name+"' created successfully") {
+			t.Errorf("Unexpected output:\n%s", output)
+		}
+		for path, want := range project.files {
+			content, err := os.ReadFile(filepath.Join(project.name, path))
+			if err != nil || !strings.Contains(string(content), want) {
+				t.Errorf("Expected %s/%s to contain %q (%v)", project.name, path, want, err)
+			}
+		}
+	}
+
+	// --docker adds the Docker files, and Django switches to PostgreSQL in
+	// the container
+	aiClient.QueryResponse = `{"projectType": "python", "framework": "django", "options": {"name": "dockersite"}}`
+	output, err := generator.Execute("a project --docker")
+	if err != nil {
+		t.Fatalf("Execute() error with --docker: %v", err)
+	}
+	if !strings.Contains(output, "cd dockersite && docker compose up --build") {
+		t.Errorf("Expected how to start the containers in the output:\n%s", output)
+	}
+	for path, want := range map[string]string{
+		"Dockerfile":             "gunicorn dockersite.wsgi:application",
+		".dockerignore":          "venv",
+		"docker-compose.yml":     "image: postgres:16-alpine",
+		"dockersite/settings.py": "os.environ.get('POSTGRES_HOST')",
+	} {
+		content, err := os.ReadFile(filepath.Join("dockersite", path))
+		if err != nil || !strings.Contains(string(content), want) {
+			t.Errorf("Expected dockersite/%s to contain %q (%v)", path, want, err)
+		}
+	}
+	if settings, _ := os.ReadFile(filepath.Join("mysite", "mysite", "settings.py")); strings.Contains(string(settings), "POSTGRES_HOST") {
+		t.Error("Expected PostgreSQL settings only with --docker")
+	}
+	if _, err := os.Stat(filepath.Join("mysite", "docker-compose.yml")); err == nil {
+		t.Error("Expected docker-compose.yml only with --docker")
+	}
+
+	// Project names must work as a Python package and an npm package
+	for response, want := range map[string]string{
+		`{"projectType": "django", "options": {"name": "my-site"}}`: "invalid project name",
+		`{"projectType": "node", "options": {"name": "My API"}}`:    "invalid project name",
+		`{"projectType": "node", "framework": "koa"}`:               "unsupported Node.js framework: koa",
+	} {
+		aiClient.QueryResponse = response
+		if _, err := generator.Execute("a project"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Execute() error for %s = %v, want %q", response, err, want)
+		}
+	}
+
+	parser := nlp.NewParser(config.DefaultConfig())
+	for input, intent := range map[string]string{
+		"create django mysite": "django mysite",
+		"create express api":   "express api",
+	} {
+		cmd, err := parser.Parse(input)
+		if err != nil || cmd.Type != nlp.CommandTypeCreate || cmd.Intent != intent {
+			t.Errorf("Parse(%q) = %+v (%v), want a create command", input, cmd, err)
+		}
+	}
+}
+
+// TestCreateAI tests projects planned by AI: the preview, the confirmation,
+// and the plans that are refused
+func TestCreateAI(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	aiClient := mocks.NewMockAIClient()
+	aiClient.QueryResponse = "Here is the plan:\n```json\n" + `{
+  "name": "s3sync",
+  "description": "Syncs S3 buckets",
+  "files": [
+    {"path": "go.mod", "content": "module s3sync\n\ngo 1.23\n"},
+    {"path": "cmd/s3sync/main.go", "content": "package main\n\nfunc main() {}\n"},
+    {"path": "internal/sync/sync.go", "content": "package sync\n\n// Plan { has braces }\n"},
+    {"path": "scripts/release.sh", "content": "#!/bin/sh\necho release\n"}
+  ],
+  "run": ["go mod tidy"]
+}` + "\n```"
+	generator := create.NewGenerator(aiClient)
+	var previews []string
+	answer := false
+	generator.SetConfirm(func(preview string) bool {
+		previews = append(previews, preview)
+		return answer
+	})
+
+	output, err := generator.Execute(`ai:"a CLI tool in Go that syncs S3 buckets" --dry-run`)
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if !strings.Contains(aiClient.QueryCalls[0], "Description: a CLI tool in Go that syncs S3 buckets\n") {
+		t.Errorf("Expected the description in the prompt:\n%s", aiClient.QueryCalls[0])
+	}
+	for _, want := range []string{"s3sync/", "    cmd/\n      s3sync/\n        main.go (3 lines)", "go.mod (3 lines)", "go mod tidy", "nothing was written"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the dry run:\n%s", want, output)
+		}
+	}
+	if len(previews) != 0 {
+		t.Error("Expected a dry run not to ask")
+	}
+	if _, err := os.Stat("s3sync"); err == nil {
+		t.Fatal("Expected a dry run to write nothing")
+	}
+
+	// Nothing is written unless the user confirms
+	if output, err = generator.Execute("ai:a CLI tool in Go that syncs S3 buckets --no-git"); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(previews) != 1 || !strings.Contains(previews[0], "release.sh (2 lines)") || !strings.Contains(output, "Nothing was written") {
+		t.Errorf("Expected the preview to be confirmed, got %q:\n%s", previews, output)
+	}
+	if _, err := os.Stat("s3sync"); err == nil {
+		t.Fatal("Expected nothing to be written without confirmation")
+	}
+
+	answer = true
+	if output, err = generator.Execute("ai:a CLI tool in Go that syncs S3 buckets --no-git"); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if !strings.Contains(output, "Project 's3sync' created from an AI plan") || !strings.Contains(output, "Wrote 4 files") {
+		t.Errorf("Unexpected output:\n%s", output)
+	}
+	if content, _ := os.ReadFile(filepath.Join("s3sync", "internal", "sync", "sync.go")); string(content) != "package sync\n\n// Plan { has braces }\n" {
+		t.Errorf("Unexpected sync.go: %q", content)
+	}
+	if info, err := os.Stat(filepath.Join("s3sync", "scripts", "release.sh")); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm()&0100 == 0) {
+		t.Errorf("Expected release.sh to be executable: %v", err)
+	}
+	if _, err := generator.Execute("ai:sync S3 buckets --yes"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an existing project to be refused, got %v", err)
+	}
+
+	for response, want := range map[string]string{
+		`{"name": "evil", "files": [{"path": "../outside", "content": "x"}]}`:                       "writes outside the project",
+		`{"name": "evil", "files": [{"path": "/etc/passwd", "content": "x"}]}`:                      "writes outside the project",
+		`{"name": "evil", "files": [{"path": ".git/config", "content": "x"}]}`:                      "writes into .git",
+		`{"name": "evil", "files": [{"path": "a", "content": ""}, {"path": "./a", "content": ""}]}`: "writes ./a twice",
+		`{"name": "evil", "files": [{"path": "a", "content": ""}, {"path": "a/b", "content": ""}]}`: "both as a file and a directory",
+		`{"name": "../evil", "files": [{"path": "a", "content": ""}]}`:                              "invalid project name",
+		`{"name": "empty", "files": []}`:                                                            "has no files",
+		`I cannot plan that`:                                                                        "has no project plan",
+	} {
+		aiClient.QueryResponse = response
+		if _, err := generator.Execute("ai:something --yes"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Execute() error for %s = %v, want %q", response, err, want)
+		}
+	}
+	if _, err := os.Stat("evil"); err == nil {
+		t.Error("Expected refused plans to write nothing")
+	}
+
+	parser := nlp.NewParser(config.DefaultConfig())
+	cmd, err := parser.Parse("create ai:a CLI tool in Go")
+	if err != nil || cmd.Type != nlp.CommandTypeCreate || cmd.Intent != "ai:a CLI tool in Go" {
+		t.Errorf("Expected create ai: to be a create command, got %+v (%v)", cmd, err)
+	}
+	if !create.NeedsAI("ai:a CLI tool in Go") {
+		t.Error("Expected create ai: to need an AI provider")
+	}
+}
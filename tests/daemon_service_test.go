@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/daemon"
+)
+
+// TestDaemonServiceFiles tests the service definitions server:install writes
+func TestDaemonServiceFiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if path, err := daemon.ServicePath("linux"); err != nil || path != filepath.Join(home, ".config", "systemd", "user", "lumo.service") {
+		t.Errorf("Unexpected systemd unit path %q (%v)", path, err)
+	}
+	if path, err := daemon.ServicePath("darwin"); err != nil || !strings.HasSuffix(path, "Library/LaunchAgents/"+daemon.LaunchdLabel+".plist") {
+		t.Errorf("Unexpected launch agent path %q (%v)", path, err)
+	}
+	if _, err := daemon.ServicePath("windows"); err == nil {
+		t.Error("Expected services to be unsupported on Windows")
+	}
+
+	command := []string{"/opt/My Apps/lumo", "--config", "/home/me/lumo-50%.json", "server:daemon"}
+	unit := daemon.SystemdUnit(command, "/home/me/.local/state/lumo/lumo-server.log")
+	for _, want := range []string{
+		`ExecStart="/opt/My Apps/lumo" --config /home/me/lumo-50%%.json server:daemon`,
+		"Restart=on-failure",
+		"StandardOutput=append:/home/me/.local/state/lumo/lumo-server.log",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Expected the unit to contain %q:\n%s", want, unit)
+		}
+	}
+
+	plist := daemon.LaunchdPlist([]string{"/opt/lumo & co/lumo", "server:daemon"}, "/tmp/lumo.log")
+	for _, want := range []string{
+		"<string>" + daemon.LaunchdLabel + "</string>",
+		"<string>/opt/lumo &amp; co/lumo</string>\n\t\t<string>server:daemon</string>",
+		"<key>RunAtLoad</key>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("Expected the plist to contain %q:\n%s", want, plist)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/deprecation"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestDeprecatedPrefixRewrite tests that renamed prefixes run as their replacement
+func TestDeprecatedPrefixRewrite(t *testing.T) {
+	rewritten, prefix, err := deprecation.Rewrite("lumo: what is a zombie process")
+	if err != nil || prefix == nil || prefix.Code != "LUMO-D001" {
+		t.Fatalf("Expected lumo: to be deprecated as LUMO-D001, got %v (%v)", prefix, err)
+	}
+	if rewritten != "ask: what is a zombie process" {
+		t.Errorf("Expected lumo: to become ask:, got %q", rewritten)
+	}
+	if !strings.HasPrefix(prefix.Warning(), "LUMO-D001: ") {
+		t.Errorf("Expected the warning to start with its code, got %q", prefix.Warning())
+	}
+
+	if _, prefix, _ := deprecation.Rewrite("ask: hello"); prefix != nil {
+		t.Errorf("Expected ask: not to be deprecated, got %v", prefix)
+	}
+
+	parser := nlp.NewParser(config.DefaultConfig())
+	cmd, err := parser.Parse("lumo:ls -la")
+	if err != nil {
+		t.Fatalf("Failed to parse a deprecated prefix: %v", err)
+	}
+	if cmd.Type != nlp.CommandTypeAI || cmd.Intent != "ls -la" {
+		t.Errorf("Expected an AI query for ls -la, got type %v intent %q", cmd.Type, cmd.Intent)
+	}
+	if cmd.Parameters["deprecated"] != "LUMO-D001" {
+		t.Errorf("Expected the deprecation code on the command, got %q", cmd.Parameters["deprecated"])
+	}
+}
+
+// TestRetiredPrefix tests that a retired prefix fails with its code
+func TestRetiredPrefix(t *testing.T) {
+	saved := deprecation.Prefixes
+	defer func() { deprecation.Prefixes = saved }()
+	deprecation.Prefixes = append([]deprecation.Prefix{}, saved...)
+	deprecation.Prefixes[0].Retired = true
+
+	_, err := nlp.NewParser(config.DefaultConfig()).Parse("lumo: hello")
+	if err == nil || !strings.Contains(err.Error(), "LUMO-D001") {
+		t.Errorf("Expected a retired prefix to fail with its code, got %v", err)
+	}
+}
+
+// TestConfigMigration tests that old configuration files are upgraded and backed up
+func TestConfigMigration(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	path := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "lumo", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	old := `{"ai_provider": "ollama", "personas": {"quick": "lumo: answer briefly"}}`
+	if err := os.WriteFile(path, []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.SchemaVersion != config.CurrentSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", config.CurrentSchemaVersion, cfg.SchemaVersion)
+	}
+	if cfg.Personas["quick"] != "ask: answer briefly" {
+		t.Errorf("Expected the persona to be rewritten, got %q", cfg.Personas["quick"])
+	}
+	if cfg.AIProvider != "ollama" {
+		t.Errorf("Expected other settings to be kept, got provider %q", cfg.AIProvider)
+	}
+
+	migration := cfg.Migration()
+	if migration.From != 0 || len(migration.Changes) != 1 || !strings.Contains(migration.Changes[0], "LUMO-D001") {
+		t.Errorf("Expected one LUMO-D001 change from version 0, got %+v", migration)
+	}
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != old {
+		t.Errorf("Expected the original file to be backed up, got %q (%v)", backup, err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(saved), `"schema_version": 1`) {
+		t.Errorf("Expected the upgraded file to be saved, got %s (%v)", saved, err)
+	}
+
+	// Loading an upgraded file changes nothing
+	cfg, err = config.Load()
+	if err != nil || cfg.Migration().To != cfg.Migration().From {
+		t.Errorf("Expected no migration on an upgraded file, got %+v (%v)", cfg.Migration(), err)
+	}
+}
@@ -0,0 +1,565 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/dbus/common"
+	"github.com/agnath18K/lumo/dbus/gnome"
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/internal/core"
+	"github.com/agnath18K/lumo/internal/desktop"
+	"github.com/godbus/dbus/v5"
+)
+
+// TestEnvironmentName tests mapping detected desktop names to registered environments
+func TestEnvironmentName(t *testing.T) {
+	testCases := []struct {
+		detected string
+		expected string
+	}{
+		{"KDE", "kde"},
+		{"plasma", "kde"},
+		{"plasmawayland", "kde"},
+		{"ubuntu:GNOME", "gnome"},
+		{"GNOME-Classic:GNOME", "gnome"},
+		{"XFCE", "xfce"},
+		{"sway", "sway"},
+	}
+
+	for _, tc := range testCases {
+		if name := desktop.EnvironmentName(tc.detected); name != tc.expected {
+			t.Errorf("EnvironmentName(%q) = %q, expected %q", tc.detected, name, tc.expected)
+		}
+	}
+}
+
+// TestScreenshotCommandParsing tests parsing of screenshot commands
+func TestScreenshotCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for _, tc := range []struct {
+		input string
+		mode  core.ScreenshotMode
+		delay interface{}
+	}{
+		{"take screenshot", core.ScreenshotScreen, nil},
+		{"take a screenshot of the window", core.ScreenshotWindow, nil},
+		{"take screenshot of area in 5 seconds", core.ScreenshotArea, "5"},
+		{"screen shot after 3s", core.ScreenshotScreen, "3"},
+	} {
+		cmd, err := processor.Process(tc.input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", tc.input, err)
+		}
+		if cmd.Type != core.CommandTypeScreenshot || cmd.Action != "take" || cmd.Target != string(tc.mode) {
+			t.Errorf("Expected screenshot:take:%s for %q, got %s:%s:%s", tc.mode, tc.input, cmd.Type, cmd.Action, cmd.Target)
+		}
+		if cmd.Arguments["delay"] != tc.delay {
+			t.Errorf("Expected delay %v for %q, got %v", tc.delay, tc.input, cmd.Arguments["delay"])
+		}
+	}
+
+	// Agent plans can take screenshots too
+	if _, _, err := assistant.ParseAction("desktop:screenshot:take window --delay=2"); err != nil {
+		t.Errorf("Expected the screenshot action to parse: %v", err)
+	}
+}
+
+// TestPickColorCommandParsing tests parsing of the color picker command
+func TestPickColorCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for _, input := range []string{"pick-color", "pick a color", "open the color picker"} {
+		cmd, err := processor.Process(input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", input, err)
+		}
+		if cmd.Type != core.CommandTypeScreenshot || cmd.Action != "pick-color" {
+			t.Errorf("Expected screenshot:pick-color for %q, got %s:%s", input, cmd.Type, cmd.Action)
+		}
+	}
+
+	if _, _, err := assistant.ParseAction("desktop:screenshot:pick-color"); err != nil {
+		t.Errorf("Expected the pick-color action to parse: %v", err)
+	}
+}
+
+// TestWifiNetworkCommandParsing tests parsing of Wi-Fi network commands
+func TestWifiNetworkCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for _, tc := range []struct {
+		input    string
+		action   string
+		ssid     string
+		password interface{}
+	}{
+		{"list wifi networks", "list-networks", "", nil},
+		{"show available networks", "list-networks", "", nil},
+		{"connect to wifi HomeNet", "connect-network", "HomeNet", nil},
+		{"Connect to the WiFi network 'Cafe Guest' with password S3cret!", "connect-network", "Cafe Guest", "S3cret!"},
+		{"join wifi named Office password hunter2", "connect-network", "Office", "hunter2"},
+		{"forget wifi network CafeGuest", "forget-network", "CafeGuest", nil},
+	} {
+		cmd, err := processor.Process(tc.input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", tc.input, err)
+		}
+		if cmd.Type != core.CommandTypeConnectivity || cmd.Action != tc.action || cmd.Target != tc.ssid {
+			t.Errorf("Expected connectivity:%s:%s for %q, got %s:%s:%s", tc.action, tc.ssid, tc.input, cmd.Type, cmd.Action, cmd.Target)
+		}
+		if cmd.Arguments["password"] != tc.password {
+			t.Errorf("Expected password %v for %q, got %v", tc.password, tc.input, cmd.Arguments["password"])
+		}
+	}
+
+	if _, err := processor.Process("connect to wifi"); err == nil {
+		t.Error("Expected an error for a missing SSID")
+	}
+}
+
+// TestParseWifiNetworks tests parsing of nmcli's Wi-Fi network list
+func TestParseWifiNetworks(t *testing.T) {
+	output := `:Cafe Guest:40:
+*:HomeNet:72:WPA2
+:HomeNet:88:WPA2
+::90:WPA2
+:Lab\:5G:55:WPA1 WPA2
+`
+	want := []core.WifiNetwork{
+		{SSID: "HomeNet", Signal: 88, Security: "WPA2", Active: true},
+		{SSID: "Lab:5G", Signal: 55, Security: "WPA1 WPA2"},
+		{SSID: "Cafe Guest", Signal: 40},
+	}
+	if got := gnome.ParseWifiNetworks(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	for signal, bars := range map[int]string{0: "____", 40: "▂▄__", 88: "▂▄▆█"} {
+		if got := gnome.SignalBars(signal); got != bars {
+			t.Errorf("SignalBars(%d) = %q, expected %q", signal, got, bars)
+		}
+	}
+}
+
+// TestVPNCommandParsing tests parsing of VPN commands
+func TestVPNCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for _, tc := range []struct {
+		input  string
+		action string
+		name   string
+	}{
+		{"turn on my work vpn", "enable-vpn", "work"},
+		{"Connect to the Office-OpenVPN VPN", "enable-vpn", "Office-OpenVPN"},
+		{"enable vpn", "enable-vpn", ""},
+		{"turn off my work vpn", "disable-vpn", "work"},
+		{"switch the vpn off", "disable-vpn", ""},
+		{"vpn status", "vpn-status", ""},
+		{"is the vpn on", "vpn-status", ""},
+	} {
+		cmd, err := processor.Process(tc.input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", tc.input, err)
+		}
+		if cmd.Type != core.CommandTypeConnectivity || cmd.Action != tc.action || cmd.Target != tc.name {
+			t.Errorf("Expected connectivity:%s:%s for %q, got %s:%s:%s", tc.action, tc.name, tc.input, cmd.Type, cmd.Action, cmd.Target)
+		}
+	}
+}
+
+// TestVPNConnections tests parsing and matching NetworkManager VPN connections
+func TestVPNConnections(t *testing.T) {
+	connections := gnome.ParseVPNConnections(`HomeNet:802-11-wireless:yes
+Work VPN:vpn:no
+Home\:WG:wireguard:yes
+Wired connection 1:802-3-ethernet:no
+`)
+	want := []core.VPNConnection{
+		{Name: "Work VPN", Type: "vpn"},
+		{Name: "Home:WG", Type: "wireguard", Active: true},
+	}
+	if !reflect.DeepEqual(connections, want) {
+		t.Fatalf("Expected %+v, got %+v", want, connections)
+	}
+
+	for name, expected := range map[string]string{"work vpn": "Work VPN", "work": "Work VPN", "wg": "Home:WG"} {
+		if vpn, err := gnome.MatchVPNConnection(connections, name); err != nil || vpn.Name != expected {
+			t.Errorf("MatchVPNConnection(%q) = %q, %v; expected %q", name, vpn.Name, err, expected)
+		}
+	}
+	for _, name := range []string{"", "o", "office"} {
+		if _, err := gnome.MatchVPNConnection(connections, name); err == nil {
+			t.Errorf("Expected no single VPN to match %q", name)
+		}
+	}
+	if vpn, err := gnome.MatchVPNConnection(connections[:1], ""); err != nil || vpn.Name != "Work VPN" {
+		t.Errorf("Expected the only VPN to be used without a name, got %q, %v", vpn.Name, err)
+	}
+}
+
+// TestSoundOutputCommandParsing tests parsing requests to switch the sound output
+func TestSoundOutputCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for _, tc := range []struct {
+		input  string
+		output string
+	}{
+		{"switch audio to headphones", "headphones"},
+		{"switch sound to the hdmi output", "hdmi"},
+		{"use HDMI sound", "hdmi"},
+		{"change the audio output to my laptop speakers", "laptop speakers"},
+		{"route sound through the TV", "tv"},
+	} {
+		cmd, err := processor.Process(tc.input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", tc.input, err)
+		}
+		if cmd.Type != core.CommandTypeSound || cmd.Action != "switch-output" || cmd.Target != tc.output {
+			t.Errorf("Expected sound:switch-output:%s for %q, got %s:%s:%s", tc.output, tc.input, cmd.Type, cmd.Action, cmd.Target)
+		}
+	}
+
+	if _, err := processor.Process("switch audio to"); err == nil {
+		t.Error("Expected an error without an output")
+	}
+}
+
+// TestMatchSoundOutput tests finding an output by a friendly name
+func TestMatchSoundOutput(t *testing.T) {
+	devices := []core.SoundDevice{
+		{ID: "0", Name: "alsa_output.pci-0000_00_1f.3.analog-stereo", Description: "Built-in Audio Analog Stereo"},
+		{ID: "1", Name: "alsa_output.pci-0000_00_1f.3.hdmi-stereo", Description: "Built-in Audio Digital Stereo (HDMI)"},
+		{ID: "2", Name: "bluez_output.AA_BB_CC_DD_EE_FF.1", Description: "WH-1000XM4"},
+		{ID: "0", Name: "alsa_input.pci-0000_00_1f.3.analog-stereo", Description: "Built-in Audio Headset Microphone", IsInput: true},
+	}
+
+	for name, expected := range map[string]string{
+		"headphones":      "bluez_output.AA_BB_CC_DD_EE_FF.1",
+		"bluetooth":       "bluez_output.AA_BB_CC_DD_EE_FF.1",
+		"wh-1000xm4":      "bluez_output.AA_BB_CC_DD_EE_FF.1",
+		"hdmi":            "alsa_output.pci-0000_00_1f.3.hdmi-stereo",
+		"tv":              "alsa_output.pci-0000_00_1f.3.hdmi-stereo",
+		"laptop speakers": "alsa_output.pci-0000_00_1f.3.analog-stereo",
+		"1":               "alsa_output.pci-0000_00_1f.3.hdmi-stereo",
+	} {
+		if device, err := gnome.MatchSoundOutput(devices, name); err != nil || device.Name != expected {
+			t.Errorf("MatchSoundOutput(%q) = %q, %v; expected %q", name, device.Name, err, expected)
+		}
+	}
+
+	// "built-in" fits two outputs, and nothing fits "kitchen"
+	for _, name := range []string{"built-in", "kitchen"} {
+		if _, err := gnome.MatchSoundOutput(devices, name); err == nil {
+			t.Errorf("Expected no single output to match %q", name)
+		}
+	}
+}
+
+// TestMediaCommandParsing tests parsing requests about what is playing
+func TestMediaCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for _, tc := range []struct {
+		input  string
+		action string
+		target string
+	}{
+		{"what's playing", "now-playing", ""},
+		{"which song is playing", "now-playing", ""},
+		{"skip ahead 30 seconds", "seek", "30"},
+		{"fast forward 2 minutes", "seek", "120"},
+		{"rewind 15s", "seek", "-15"},
+		{"go back 10 seconds", "seek", "-10"},
+		{"go back a minute", "seek", "-60"},
+		{"skip forward", "seek", "10"},
+		{"set player spotify", "set-player", "spotify"},
+		{"use vlc as the media player", "set-player", "vlc"},
+		{"next track", "next", ""},
+	} {
+		cmd, err := processor.Process(tc.input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", tc.input, err)
+		}
+		if cmd.Type != core.CommandTypeMedia || cmd.Action != tc.action || cmd.Target != tc.target {
+			t.Errorf("Expected media:%s:%s for %q, got %s:%s:%s", tc.action, tc.target, tc.input, cmd.Type, cmd.Action, cmd.Target)
+		}
+	}
+}
+
+// fakePlayers answers MPRIS property reads from a map and records seeks
+type fakePlayers struct {
+	core.DBusHandler
+	properties map[string]interface{}
+	seeks      []int64
+}
+
+func (f *fakePlayers) GetProperty(service, objectPath, interfaceName, property string) (interface{}, error) {
+	if value, ok := f.properties[service+" "+property]; ok {
+		return value, nil
+	}
+	return nil, fmt.Errorf("no property %s", property)
+}
+
+func (f *fakePlayers) Call(service, objectPath, interfaceName, method string, args ...interface{}) ([]interface{}, error) {
+	if method != "Seek" {
+		return nil, fmt.Errorf("unexpected call %s", method)
+	}
+	offset := args[0].(int64)
+	f.seeks = append(f.seeks, offset)
+	f.properties[service+" Position"] = f.properties[service+" Position"].(int64) + offset
+	return nil, nil
+}
+
+// TestMPRISPlayers tests choosing a player and reading what it plays
+func TestMPRISPlayers(t *testing.T) {
+	const (
+		spotify = "org.mpris.MediaPlayer2.spotify"
+		vlc     = "org.mpris.MediaPlayer2.vlc.instance4321"
+	)
+	players := common.MediaPlayers([]string{"org.freedesktop.Notifications", spotify, vlc})
+	if !reflect.DeepEqual(players, []string{spotify, vlc}) || common.PlayerName(vlc) != "vlc" {
+		t.Fatalf("Unexpected players: %v", players)
+	}
+
+	fake := &fakePlayers{properties: map[string]interface{}{
+		spotify + " PlaybackStatus": "Paused",
+		vlc + " PlaybackStatus":     "Playing",
+		vlc + " Identity":           "VLC media player",
+		vlc + " Position":           int64(83 * time.Second / time.Microsecond),
+		vlc + " Metadata": map[string]dbus.Variant{
+			"xesam:title":  dbus.MakeVariant("Blue in Green"),
+			"xesam:artist": dbus.MakeVariant([]string{"Miles Davis", "Bill Evans"}),
+			"xesam:album":  dbus.MakeVariant("Kind of Blue"),
+			"mpris:length": dbus.MakeVariant(uint64(337 * time.Second / time.Microsecond)),
+		},
+	}}
+
+	// The playing player is chosen unless another is preferred
+	if player, _ := common.ChoosePlayer(fake, players, ""); player != vlc {
+		t.Errorf("Expected the playing player, got %s", player)
+	}
+	if player, _ := common.ChoosePlayer(fake, players, "Spotify"); player != spotify {
+		t.Errorf("Expected the preferred player, got %s", player)
+	}
+	if player, _ := common.ChoosePlayer(fake, players, "rhythmbox"); player != vlc {
+		t.Errorf("Expected the playing player when the preferred one is not running, got %s", player)
+	}
+	if _, err := common.ChoosePlayer(fake, nil, ""); err == nil {
+		t.Error("Expected an error without players")
+	}
+
+	nowPlaying, err := common.ReadNowPlaying(fake, vlc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "▶ Blue in Green — Miles Davis, Bill Evans (Kind of Blue)\n  1:23 / 5:37 on VLC media player"
+	if nowPlaying.String() != want {
+		t.Errorf("Unexpected now playing:\n%s\nwant:\n%s", nowPlaying, want)
+	}
+
+	position, err := common.Seek(fake, vlc, -30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.seeks) != 1 || fake.seeks[0] != -30000000 {
+		t.Errorf("Expected a seek of -30s in microseconds, got %v", fake.seeks)
+	}
+	if result := common.SeekResult(-30*time.Second, position); result != "Went back 30 seconds (now at 0:53)" {
+		t.Errorf("Unexpected seek result: %q", result)
+	}
+
+	fake.properties[vlc+" CanSeek"] = false
+	if _, err := common.Seek(fake, vlc, time.Second); err == nil || !strings.Contains(err.Error(), "cannot seek") {
+		t.Errorf("Expected players that cannot seek to be refused, got %v", err)
+	}
+}
+
+// TestParseSeekOffset tests reading how far to seek
+func TestParseSeekOffset(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"30":     30 * time.Second,
+		"+30":    30 * time.Second,
+		"-10":    -10 * time.Second,
+		"45s":    45 * time.Second,
+		"2m":     2 * time.Minute,
+		"1:30":   90 * time.Second,
+		"-1 min": -time.Minute,
+	} {
+		if got, err := common.ParseSeekOffset(input); err != nil || got != want {
+			t.Errorf("ParseSeekOffset(%q) = %s, %v; want %s", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "0", "ahead", "10h"} {
+		if _, err := common.ParseSeekOffset(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+// TestPreferredPlayer tests remembering the player media commands control
+func TestPreferredPlayer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	players := []string{"org.mpris.MediaPlayer2.vlc"}
+
+	if result, err := common.SetPlayerResult(players, "Spotify"); err != nil || !strings.Contains(result, "once it is running (running now: vlc)") {
+		t.Errorf("Unexpected result: %q (%v)", result, err)
+	}
+	if preferred := common.PreferredPlayer(); preferred != "spotify" {
+		t.Errorf("Expected spotify to be remembered, got %q", preferred)
+	}
+	if result, _ := common.SetPlayerResult(players, "vlc"); result != "Media commands now control vlc" {
+		t.Errorf("Unexpected result: %q", result)
+	}
+	if _, err := common.SetPlayerResult(players, "auto"); err != nil || common.PreferredPlayer() != "" {
+		t.Errorf("Expected the preferred player to be forgotten, got %q (%v)", common.PreferredPlayer(), err)
+	}
+}
+
+// TestDoNotDisturbParsing tests parsing do not disturb and missed notification requests
+func TestDoNotDisturbParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	for input, action := range map[string]string{
+		"enable do not disturb":         "enable-dnd",
+		"turn on dnd":                   "enable-dnd",
+		"turn off do not disturb":       "disable-dnd",
+		"disable dnd":                   "disable-dnd",
+		"missed notifications":          "list-notifications",
+		"what notifications did i miss": "list-notifications",
+		"send notification hello":       "send",
+	} {
+		cmd, err := processor.Process(input)
+		if err != nil {
+			t.Fatalf("Failed to process %q: %v", input, err)
+		}
+		if cmd.Type != core.CommandTypeNotification || cmd.Action != action {
+			t.Errorf("Expected notification:%s for %q, got %s:%s", action, input, cmd.Type, cmd.Action)
+		}
+	}
+}
+
+// TestMissedNotifications tests keeping the notifications that arrive during do not disturb
+func TestMissedNotifications(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// Notify is called with app name, replaced ID, icon, summary, body, actions, hints, and timeout
+	notification, ok := common.NotificationFromCall([]interface{}{
+		"Slack", uint32(0), "", "New message", "Are you\nthere?", []string{}, map[string]dbus.Variant{}, int32(-1),
+	})
+	if !ok || notification.App != "Slack" || notification.Summary != "New message" {
+		t.Fatalf("Unexpected notification: %+v", notification)
+	}
+	if _, ok := common.NotificationFromCall([]interface{}{"Slack"}); ok {
+		t.Error("Expected calls without a summary to be ignored")
+	}
+
+	session := &common.DNDSession{Since: time.Now().Add(-time.Minute), Recording: true}
+	if err := common.SaveDNDSession(session); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := common.LoadDNDSession(); err != nil || loaded == nil || !loaded.Recording {
+		t.Fatalf("Expected the session to be saved, got %+v (%v)", loaded, err)
+	}
+
+	earlier := common.MissedNotification{Time: session.Since.Add(-time.Hour), Summary: "Old"}
+	for _, missed := range []common.MissedNotification{earlier, notification} {
+		if err := common.AddMissedNotification(missed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missed, err := common.LoadMissedNotifications(session.Since)
+	if err != nil || len(missed) != 1 {
+		t.Fatalf("Expected one notification since the session started, got %v (%v)", missed, err)
+	}
+	output := common.FormatMissedNotifications(missed)
+	if !strings.HasPrefix(output, "1 missed notification:") || !strings.Contains(output, "Slack: New message — Are you there?") {
+		t.Errorf("Unexpected list:\n%s", output)
+	}
+	if output := common.FormatMissedNotifications(nil); output != "No missed notifications" {
+		t.Errorf("Unexpected empty list: %q", output)
+	}
+
+	for i := 0; i < common.MaxMissedNotifications; i++ {
+		common.AddMissedNotification(notification)
+	}
+	if err := common.TrimMissedNotifications(); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := common.LoadMissedNotifications(time.Time{}); len(all) != common.MaxMissedNotifications || all[0].Summary == "Old" {
+		t.Errorf("Expected the oldest notifications to be dropped, got %d", len(all))
+	}
+
+	if err := common.EndDNDSession(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := common.LoadDNDSession(); loaded != nil {
+		t.Errorf("Expected the session to end, got %+v", loaded)
+	}
+}
+
+// TestNightLightRestoresSchedule tests that turning night light off puts
+// back the schedule turning it on replaced
+func TestNightLightRestoresSchedule(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+
+	// A fake gsettings keeps each key in a file named after it
+	settings := t.TempDir()
+	bin := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = set ]; then printf '%s' "$4" > "` + settings + `/$3"; else cat "` + settings + `/$3"; fi
+`
+	if err := os.WriteFile(filepath.Join(bin, "gsettings"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	setting := func(key string) string {
+		data, _ := os.ReadFile(filepath.Join(settings, key))
+		return string(data)
+	}
+	user := map[string]string{
+		"night-light-schedule-automatic": "true",
+		"night-light-schedule-from":      "20.5",
+		"night-light-schedule-to":        "6.0",
+		"night-light-enabled":            "true",
+	}
+	for key, value := range user {
+		os.WriteFile(filepath.Join(settings, key), []byte(value), 0644)
+	}
+
+	env := &gnome.Environment{}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := env.SetNightLight(ctx, true); err != nil {
+			t.Fatalf("SetNightLight(true) error: %v", err)
+		}
+	}
+	if setting("night-light-schedule-automatic") != "false" || setting("night-light-schedule-to") != "23.99" {
+		t.Fatalf("Expected the schedule widened to the whole day, got %s to %s",
+			setting("night-light-schedule-from"), setting("night-light-schedule-to"))
+	}
+
+	if err := env.SetNightLight(ctx, false); err != nil {
+		t.Fatalf("SetNightLight(false) error: %v", err)
+	}
+	for _, key := range []string{"night-light-schedule-automatic", "night-light-schedule-from", "night-light-schedule-to"} {
+		if got := setting(key); got != user[key] {
+			t.Errorf("Expected %s restored to %q, got %q", key, user[key], got)
+		}
+	}
+	if setting("night-light-enabled") != "false" {
+		t.Errorf("Expected night light off")
+	}
+}
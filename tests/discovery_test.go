@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/discovery"
+)
+
+// TestGroupInstances tests merging advertisements into lumo instances
+func TestGroupInstances(t *testing.T) {
+	services := []discovery.Service{
+		{
+			ID:   "Lumo._lumo._tcp.local.",
+			Type: discovery.InstanceServiceName,
+			Host: "desk.local.",
+			IP:   "192.168.1.20",
+			Port: 7531,
+			Info: discovery.AdvertisedInfo("1.0.2",
+				[]string{discovery.CapabilityServerAPI, discovery.CapabilityFleetManaged},
+				map[string]string{discovery.InfoHostname: "desk"}),
+		},
+		{
+			ID:   "Lumo Connect._lumo-connect._tcp.local.",
+			Type: discovery.ServiceName,
+			Host: "desk.local.",
+			IP:   "192.168.1.20",
+			Port: 8080,
+			Info: discovery.AdvertisedInfo("1.0.2", []string{discovery.CapabilityConnectReceiver}, nil),
+		},
+		{
+			// Older versions advertised neither a version nor capabilities
+			ID:   "Lumo Connect._lumo-connect._tcp.local.",
+			Type: discovery.ServiceName,
+			Host: "laptop.local.",
+			IP:   "192.168.1.30",
+			Port: 8080,
+			Info: map[string]string{"username": "sam"},
+		},
+	}
+
+	instances := discovery.GroupInstances(services)
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d: %+v", len(instances), instances)
+	}
+
+	desk := instances[0]
+	if desk.Hostname != "desk" || desk.Version != "1.0.2" || len(desk.Endpoints) != 2 {
+		t.Errorf("Unexpected instance: %+v", desk)
+	}
+	want := []string{discovery.CapabilityConnectReceiver, discovery.CapabilityFleetManaged, discovery.CapabilityServerAPI}
+	if got := desk.Capabilities(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected capabilities %v, got %v", want, got)
+	}
+	actions := strings.Join(desk.Actions(), "\n")
+	if !strings.Contains(actions, "http://192.168.1.20:7531/api/v1") || !strings.Contains(actions, "lumo connect 192.168.1.20 --port 8080") {
+		t.Errorf("Unexpected actions:\n%s", actions)
+	}
+
+	laptop := instances[1]
+	if laptop.Hostname != "laptop.local" || laptop.Version != "" {
+		t.Errorf("Unexpected instance: %+v", laptop)
+	}
+	if !reflect.DeepEqual(laptop.Capabilities(), []string{discovery.CapabilityConnectReceiver}) {
+		t.Errorf("Expected legacy connect receiver, got %v", laptop.Capabilities())
+	}
+}
+
+// TestBroadcastMessageSigning tests signing and verifying broadcast discovery messages
+func TestBroadcastMessageSigning(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	now := time.Now()
+	msg := discovery.Message{
+		Kind: discovery.MessageAnnounce,
+		Type: discovery.ServiceName,
+		Name: "Lumo Connect",
+		Host: "desk",
+		Port: 8080,
+		Info: map[string]string{discovery.InfoVersion: "1.0.2"},
+		Time: now.Unix(),
+	}
+
+	packet, err := discovery.EncodeMessage(secret, msg)
+	if err != nil {
+		t.Fatalf("EncodeMessage failed: %v", err)
+	}
+
+	decoded, err := discovery.DecodeMessage(secret, packet, now)
+	if err != nil {
+		t.Fatalf("DecodeMessage failed: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, msg) {
+		t.Errorf("Expected %+v, got %+v", msg, *decoded)
+	}
+
+	if _, err := discovery.DecodeMessage([]byte("another secret"), packet, now); err == nil {
+		t.Error("Expected a packet signed with another secret to be rejected")
+	}
+
+	tampered := []byte(strings.Replace(string(packet), "8080", "9090", 1))
+	if _, err := discovery.DecodeMessage(secret, tampered, now); err == nil {
+		t.Error("Expected a tampered packet to be rejected")
+	}
+
+	if _, err := discovery.DecodeMessage(secret, packet, now.Add(time.Minute)); err == nil {
+		t.Error("Expected a replayed packet to be rejected")
+	}
+
+	if _, err := discovery.New(discovery.Options{Transport: discovery.TransportBroadcast}); err == nil {
+		t.Error("Expected broadcast discovery without a secret to fail")
+	}
+}
+
+// TestDiscoveryPrivacy tests hiding the machine's identity and turning advertising off
+func TestDiscoveryPrivacy(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if opts := discovery.ConfigOptions(cfg); opts.HideIdentity || opts.DisableAdvertising {
+		t.Errorf("Expected identity and advertising by default, got %+v", opts)
+	}
+	cfg.PrivacyMode = "strict"
+	if !discovery.ConfigOptions(cfg).HideIdentity {
+		t.Error("Expected strict privacy mode to hide the identity")
+	}
+
+	if _, err := discovery.New(discovery.Options{RequireAuth: true}); err == nil {
+		t.Error("Expected authenticated discovery without a secret to fail")
+	}
+	authenticated, err := discovery.New(discovery.Options{Transport: discovery.TransportMDNS, Secret: "0123456789abcdef", RequireAuth: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, ok := authenticated.(*discovery.BroadcastDiscoverer); !ok {
+		t.Errorf("Expected authenticated discovery to use signed broadcasts, got %T", authenticated)
+	}
+
+	secret := "privacy test secret"
+	ctx := context.Background()
+	info := map[string]string{discovery.InfoHostname: "desk", discovery.InfoUsername: "sam", "mode": "duplex"}
+
+	hidden, _ := discovery.New(discovery.Options{Transport: discovery.TransportBroadcast, Secret: secret, HideIdentity: true})
+	if err := hidden.Advertise(ctx, "_lumo-privacy-test._tcp", "Hidden", 9001, info); err != nil {
+		t.Skipf("Broadcast discovery is not available here: %v", err)
+	}
+	defer hidden.StopAdvertising()
+
+	silent, _ := discovery.New(discovery.Options{Transport: discovery.TransportBroadcast, Secret: secret, DisableAdvertising: true})
+	if err := silent.Advertise(ctx, "_lumo-privacy-test._tcp", "Silent", 9002, info); err != nil {
+		t.Fatalf("Advertise with advertising disabled failed: %v", err)
+	}
+
+	browser, _ := discovery.New(discovery.Options{Transport: discovery.TransportBroadcast, Secret: secret})
+	services, err := browser.Browse(ctx, "_lumo-privacy-test._tcp")
+	if err != nil {
+		t.Skipf("Broadcast discovery is not available here: %v", err)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected only the advertising instance, got %+v", services)
+	}
+	service := services[0]
+	if service.Port != 9001 || service.Host != "" || service.Info[discovery.InfoHostname] != "" || service.Info[discovery.InfoUsername] != "" {
+		t.Errorf("Expected an announcement without identity, got %+v", service)
+	}
+	if service.Info["mode"] != "duplex" {
+		t.Errorf("Expected other fields to be kept, got %+v", service.Info)
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+)
+
+// TestSimulatePlan tests predicting what each step of a plan would touch
+func TestSimulatePlan(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.log", "b.log", "old.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan := &agent.Plan{Steps: []*agent.Step{
+		{ID: 1, Command: "mkdir backup"},
+		{ID: 2, Command: "cp *.log backup/"},
+		{ID: 3, Command: "rm old.txt missing.txt"},
+		{ID: 4, Command: "echo done > out.txt 2>&1"},
+		{ID: 5, Command: "ls -la | grep log"},
+		{ID: 6, Command: "cd backup && touch a.log"},
+		{ID: 7, Command: "sudo apt install -y jq"},
+		{ID: 8, Command: "frobnicate --all"},
+	}}
+
+	report := agent.SimulatePlan(plan, dir)
+	if len(report.Steps) != len(plan.Steps) {
+		t.Fatalf("Expected %d steps, got %d", len(plan.Steps), len(report.Steps))
+	}
+
+	type effect struct {
+		kind agent.EffectKind
+		path string
+	}
+	want := [][]effect{
+		{{agent.EffectCreate, "backup/"}},
+		{{agent.EffectCreate, "backup/a.log"}, {agent.EffectCreate, "backup/b.log"}},
+		{{agent.EffectDelete, "old.txt"}},
+		{{agent.EffectCreate, "out.txt"}},
+		nil,
+		{{agent.EffectModify, "backup/a.log"}},
+		{{agent.EffectSystem, ""}},
+		{{agent.EffectUnknown, ""}},
+	}
+
+	for i, step := range report.Steps {
+		if len(step.Effects) != len(want[i]) {
+			t.Errorf("Step %d: expected %d effects, got %+v", i+1, len(want[i]), step.Effects)
+			continue
+		}
+		for j, got := range step.Effects {
+			if got.Kind != want[i][j].kind || got.Path != want[i][j].path {
+				t.Errorf("Step %d effect %d: expected %v %q, got %v %q", i+1, j, want[i][j].kind, want[i][j].path, got.Kind, got.Path)
+			}
+		}
+	}
+
+	// Nothing may have been touched on disk
+	if _, err := os.Stat(filepath.Join(dir, "backup")); !os.IsNotExist(err) {
+		t.Error("Dry run created the backup directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.txt")); err != nil {
+		t.Error("Dry run removed old.txt")
+	}
+
+	output := report.Format(false)
+	if !strings.Contains(output, "Summary: 4 created, 1 modified, 1 deleted") {
+		t.Errorf("Unexpected summary:\n%s", output)
+	}
+	if !strings.Contains(output, "   + backup/") || !strings.Contains(output, "   - old.txt") {
+		t.Errorf("Expected diff-style lines:\n%s", output)
+	}
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/lumoerr"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/privacy"
+	"github.com/agnath18K/lumo/pkg/vcr"
+)
+
+// roundTripFunc sends requests with a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestTypedErrors tests matching and formatting errors from the catalog
+func TestTypedErrors(t *testing.T) {
+	err := fmt.Errorf("starting: %w", lumoerr.New(lumoerr.ErrPortInUse, "port 8080").WithHint("Pick another."))
+	if !errors.Is(err, lumoerr.ErrPortInUse) || errors.Is(err, lumoerr.ErrOffline) {
+		t.Errorf("Expected only ErrPortInUse to match %v", err)
+	}
+	if lumoerr.Code(err) != "LUMO-E3001" {
+		t.Errorf("Expected LUMO-E3001, got %q", lumoerr.Code(err))
+	}
+	formatted := lumoerr.Format(err)
+	for _, want := range []string{"starting: the port is already in use: port 8080", "Pick another.", "[LUMO-E3001]"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("Expected %q in %q", want, formatted)
+		}
+	}
+	if plain := lumoerr.Format(errors.New("plain")); plain != "plain" {
+		t.Errorf("Expected other errors unchanged, got %q", plain)
+	}
+
+	cause := errors.New("connection refused")
+	if wrapped := lumoerr.Wrap(lumoerr.ErrOffline, cause); !errors.Is(wrapped, cause) || !errors.Is(wrapped, lumoerr.ErrOffline) {
+		t.Errorf("Expected the kind and the cause to match %v", wrapped)
+	}
+
+	codes := make(map[string]bool)
+	for _, kind := range lumoerr.Catalog {
+		if codes[kind.Code] || !strings.HasPrefix(kind.Code, "LUMO-E") || kind.Hint == "" {
+			t.Errorf("Expected a unique code and a hint for %+v", kind)
+		}
+		codes[kind.Code] = true
+	}
+
+	if err := privacy.Blocked("Speed testing"); !errors.Is(err, lumoerr.ErrPolicyBlocked) {
+		t.Errorf("Expected strict privacy mode to block by policy, got %v", err)
+	}
+}
+
+// TestProviderErrors tests that a refused key and an unreachable provider
+// are reported with their codes
+func TestProviderErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cassette := filepath.Join(t.TempDir(), "openai.json")
+	os.WriteFile(cassette, []byte(`{
+		"version": 1,
+		"interactions": [{
+			"request": {"method": "POST", "url": "https://api.openai.com/v1/chat/completions"},
+			"response": {"status": 401, "content_type": "application/json",
+				"body": "{\"error\": {\"message\": \"Incorrect API key provided\"}}"}
+		}, {
+			"request": {"method": "POST", "url": "https://api.openai.com/v1/chat/completions"},
+			"response": {"status": 401, "content_type": "application/json",
+				"body": "{\"error\": {\"message\": \"Incorrect API key provided\"}}"}
+		}]
+	}`), 0600)
+	player, err := vcr.NewPlayer(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai.SetTransport(player)
+	defer ai.SetTransport(nil)
+
+	_, err = ai.NewOpenAIClient("sk-wrong", "gpt-4o").Query("hello")
+	if !errors.Is(err, lumoerr.ErrProviderAuth) || !strings.Contains(err.Error(), "Incorrect API key provided") {
+		t.Errorf("Expected ErrProviderAuth with the provider's message, got %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "openai"
+	cfg.OpenAIAPIKey = "sk-wrong"
+	cfg.EnableResponseCache = false
+	exec := executor.NewExecutor(cfg)
+	result, _ := exec.Execute(&nlp.Command{Type: nlp.CommandTypeAI, Intent: "hello", RawInput: "ask:hello"})
+	if !result.IsError || !strings.Contains(result.Output, "[LUMO-E1001]") || !strings.Contains(result.Output, "config:key") {
+		t.Errorf("Expected the code and hint for a refused key, got %q", result.Output)
+	}
+
+	ai.SetTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}))
+	_, err = ai.NewOllamaClient("http://localhost:11434", "llama3").Query("hello")
+	if !errors.Is(err, lumoerr.ErrOffline) || !strings.Contains(lumoerr.Format(err), "ollama serve") {
+		t.Errorf("Expected ErrOffline with a hint to start Ollama, got %q", lumoerr.Format(err))
+	}
+}
+
+// TestPortInUseError tests that a receiver without a free port reports ErrPortInUse
+func TestPortInUseError(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+	if port > 65535-100 {
+		t.Skip("No room for the ports after the listener")
+	}
+	var others []net.Listener
+	for p := port + 1; p <= port+100; p++ {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", p))
+		if err != nil {
+			t.Skipf("Port %d is taken by another program", p)
+		}
+		others = append(others, l)
+	}
+	defer func() {
+		for _, l := range others {
+			l.Close()
+		}
+	}()
+
+	manager := connect.NewConnectManager(t.TempDir(), port)
+	err = manager.StartReceiver(context.Background())
+	if !errors.Is(err, lumoerr.ErrPortInUse) || lumoerr.Code(err) != "LUMO-E3001" {
+		t.Errorf("Expected ErrPortInUse, got %v", err)
+	}
+}
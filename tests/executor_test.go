@@ -11,6 +11,9 @@ import (
 
 // TestExecutorCommandRouting tests the executor's ability to route commands to the correct handler
 func TestExecutorCommandRouting(t *testing.T) {
+	// Reports are recorded for `lumo save`, so keep them out of the real home
+	t.Setenv("HOME", t.TempDir())
+
 	// Create a default config for testing
 	cfg := &config.Config{
 		EnableShellInInteractive: true,
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestFeatureDependencies tests that features are only enabled with what they require
+func TestFeatureDependencies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EnableAgentMode = false
+	cfg.EnableAgentREPL = false
+
+	if err := cfg.EnableFeature("agent-repl"); err == nil || !strings.Contains(err.Error(), "requires agent") {
+		t.Errorf("Expected agent-repl to require agent, got %v", err)
+	}
+	if cfg.EnableAgentREPL {
+		t.Error("Expected agent-repl to stay disabled")
+	}
+
+	for _, name := range []string{"agent", "agent-repl"} {
+		if err := cfg.EnableFeature(name); err != nil {
+			t.Fatalf("Failed to enable %s: %v", name, err)
+		}
+	}
+	if !cfg.EnableAgentMode || !cfg.EnableAgentREPL || !cfg.FeatureEnabled("agent-repl") {
+		t.Error("Expected agent and agent-repl to be enabled")
+	}
+
+	dependents, err := cfg.DisableFeature("agent")
+	if err != nil || len(dependents) != 1 || dependents[0] != "agent-repl" {
+		t.Errorf("Expected disabling agent to disable agent-repl, got %v, %v", dependents, err)
+	}
+	if cfg.EnableAgentMode || cfg.EnableAgentREPL {
+		t.Error("Expected agent and agent-repl to be disabled")
+	}
+
+	if err := cfg.EnableFeature("teleport"); err == nil {
+		t.Error("Expected an error for an unknown feature")
+	}
+	if cfg.FeatureEnabled("teleport") {
+		t.Error("Expected an unknown feature to be disabled")
+	}
+}
+
+// TestFeatureConfig tests config:feature and the help screen built from the registry
+func TestFeatureConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.EnableAgentMode = false
+	cfg.EnableAgentREPL = false
+	exec := executor.NewExecutor(cfg)
+
+	run := func(intent string) *executor.Result {
+		t.Helper()
+		result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: intent, RawInput: "config:" + intent})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// A failed enable changes nothing, even features named before it
+	if result := run("feature enable pipe agent-repl"); !result.IsError {
+		t.Errorf("Expected agent-repl to need agent, got %q", result.Output)
+	}
+	if result := run("feature enable agent agent-repl"); result.IsError {
+		t.Fatalf("Failed to enable agent and agent-repl: %s", result.Output)
+	}
+	if saved, err := config.Load(); err != nil || !saved.EnableAgentMode || !saved.EnableAgentREPL {
+		t.Errorf("Expected the features to be saved, got %v", err)
+	}
+
+	list := run("feature list").Output
+	for _, name := range config.FeatureNames() {
+		if !strings.Contains(list, name) {
+			t.Errorf("Expected %s in the feature list:\n%s", name, list)
+		}
+	}
+
+	help, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeHelp, Intent: "help", RawInput: "help"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(help.Output, "• Agent REPL mode: ENABLED") || !strings.Contains(help.Output, "• Local intent model: DISABLED") {
+		t.Errorf("Expected feature states in the help screen:\n%s", help.Output)
+	}
+
+	if result := run("feature disable agent"); !strings.Contains(result.Output, "Disabled agent and agent-repl, which requires it.") {
+		t.Errorf("Unexpected output: %q", result.Output)
+	}
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// TestFocusCommandParsing tests parsing of focus mode commands
+func TestFocusCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	cmd, err := processor.Process("focus on 90m --pause-media")
+	if err != nil {
+		t.Fatalf("Failed to process focus command: %v", err)
+	}
+	if cmd.Type != core.CommandTypeFocus || cmd.Action != "on" {
+		t.Fatalf("Expected focus:on, got %s:%s", cmd.Type, cmd.Action)
+	}
+	if cmd.Target != "90m" {
+		t.Errorf("Expected duration 90m, got %q", cmd.Target)
+	}
+	if pause, _ := cmd.Arguments["pause_media"].(bool); !pause {
+		t.Errorf("Expected pause_media to be set")
+	}
+
+	cmd, err = processor.Process("focus off 1700000000")
+	if err != nil {
+		t.Fatalf("Failed to process focus off command: %v", err)
+	}
+	if cmd.Action != "off" || cmd.Target != "1700000000" {
+		t.Errorf("Expected focus:off with session ID, got %s with target %q", cmd.Action, cmd.Target)
+	}
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/hooks"
+)
+
+// TestHooksReceivePayload tests that an installed hook receives the event payload on stdin
+func TestHooksReceivePayload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell hooks are not supported on Windows")
+	}
+
+	// Point the hooks directory at a temporary home
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir, err := hooks.Dir()
+	if err != nil {
+		t.Fatalf("Failed to get hooks directory: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create hooks directory: %v", err)
+	}
+
+	// Install a hook that copies its stdin to a file
+	outFile := filepath.Join(home, "payload.json")
+	script := "#!/bin/sh\ncat > " + outFile + "\n"
+	hookPath := filepath.Join(dir, string(hooks.EventPostTransfer))
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	err = hooks.Run(context.Background(), hooks.EventPostTransfer, map[string]interface{}{
+		"filename": "report.pdf",
+	})
+	if err != nil {
+		t.Fatalf("Expected hook to succeed, got: %v", err)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("Hook did not write payload: %v", err)
+	}
+
+	var payload hooks.Payload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Failed to parse payload: %v", err)
+	}
+	if payload.Event != hooks.EventPostTransfer {
+		t.Errorf("Expected event '%s', got '%s'", hooks.EventPostTransfer, payload.Event)
+	}
+	if payload.Data["filename"] != "report.pdf" {
+		t.Errorf("Expected filename 'report.pdf', got '%v'", payload.Data["filename"])
+	}
+}
+
+// TestHooksMissingIsNoop tests that running an event without a hook does nothing
+func TestHooksMissingIsNoop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := hooks.Run(context.Background(), hooks.EventOnHealthAlert, nil); err != nil {
+		t.Errorf("Expected no error for missing hook, got: %v", err)
+	}
+}
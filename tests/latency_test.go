@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/speedtest"
+)
+
+// TestLatencyStats tests summarizing latency samples
+func TestLatencyStats(t *testing.T) {
+	samples := []speedtest.Sample{
+		{RTT: 10}, {RTT: 14}, {Lost: true}, {RTT: 12},
+	}
+
+	stats := speedtest.Stats(samples)
+	if stats.Samples != 4 || stats.Lost != 1 || stats.Loss != 25 {
+		t.Errorf("Expected 4 samples with 25%% loss, got %+v", stats)
+	}
+	if stats.Min != 10 || stats.Max != 14 || stats.Avg != 12 {
+		t.Errorf("Expected min 10, avg 12, max 14, got %+v", stats)
+	}
+	// |14-10| and |12-14|
+	if stats.Jitter != 3 {
+		t.Errorf("Expected jitter 3, got %v", stats.Jitter)
+	}
+
+	if line := speedtest.Sparkline(samples, 0); line != "▁█×▄" {
+		t.Errorf("Unexpected sparkline %q", line)
+	}
+}
+
+// TestLatencyMonitorAlert tests that sustained loss raises a single alert
+func TestLatencyMonitorAlert(t *testing.T) {
+	calls := 0
+	ping := func(ctx context.Context, target string) (time.Duration, error) {
+		calls++
+		if calls > 2 {
+			return 0, errors.New("timeout")
+		}
+		return 5 * time.Millisecond, nil
+	}
+
+	alerts := 0
+	series := speedtest.MonitorLatency(context.Background(), speedtest.MonitorOptions{
+		Target:        "192.0.2.1",
+		Duration:      300 * time.Millisecond,
+		Interval:      20 * time.Millisecond,
+		LossThreshold: 10,
+		Ping:          ping,
+		OnAlert: func(series *speedtest.LatencySeries, window speedtest.LatencyStats) {
+			alerts++
+		},
+	})
+
+	if len(series.Samples) < 5 {
+		t.Fatalf("Expected at least 5 samples, got %d", len(series.Samples))
+	}
+	if alerts != 1 || series.Alerts != 1 {
+		t.Errorf("Expected exactly one alert, got %d (series %d)", alerts, series.Alerts)
+	}
+	if series.Stats.Lost != len(series.Samples)-2 {
+		t.Errorf("Expected all but two samples to be lost, got %+v", series.Stats)
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestLimiterQueue tests that requests over the limit wait for a free slot
+// and fail once the queue timeout passes
+func TestLimiterQueue(t *testing.T) {
+	limiter := ai.NewLimiter(map[string]int{"openai": 1, "ollama": 0}, 50*time.Millisecond)
+
+	release, err := limiter.Acquire(context.Background(), "openai")
+	if err != nil {
+		t.Fatalf("Failed to acquire a free slot: %v", err)
+	}
+	if limiter.InFlight("openai") != 1 {
+		t.Errorf("Expected 1 request in flight, got %d", limiter.InFlight("openai"))
+	}
+
+	if _, err := limiter.Acquire(context.Background(), "openai"); !errors.Is(err, ai.ErrProviderBusy) {
+		t.Errorf("Expected ErrProviderBusy after the timeout, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.Acquire(ctx, "openai"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the context's error, got %v", err)
+	}
+
+	// A queued request gets the slot once it is freed
+	done := make(chan error)
+	go func() {
+		release, err := limiter.Acquire(context.Background(), "openai")
+		if err == nil {
+			release()
+		}
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	release()
+	release() // freeing twice is harmless
+	if err := <-done; err != nil {
+		t.Errorf("Expected the queued request to get the slot, got %v", err)
+	}
+	if limiter.InFlight("openai") != 0 {
+		t.Errorf("Expected no requests in flight, got %d", limiter.InFlight("openai"))
+	}
+
+	// Providers with no limit never wait
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Acquire(context.Background(), "ollama"); err != nil {
+			t.Errorf("Expected no limit for ollama, got %v", err)
+		}
+	}
+}
+
+// TestLimiterProviderRequests tests that concurrent provider requests keep
+// to the configured limit
+func TestLimiterProviderRequests(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	var inFlight, most int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			old := atomic.LoadInt32(&most)
+			if n <= old || atomic.CompareAndSwapInt32(&most, old, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "ok"}, "done": true}`))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.ProviderConcurrency = map[string]int{"ollama": 2}
+	ai.SetLimiter(ai.LimiterFromConfig(cfg))
+	defer ai.SetLimiter(nil)
+
+	client := ai.NewOllamaClient(server.URL, "llama3")
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if answer, err := client.GenerateText("hello", ""); err != nil || answer != "ok" {
+				t.Errorf("Expected an answer, got %q (%v)", answer, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if most > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", most)
+	}
+}
+
+// TestLimitsConfig tests config:limits
+func TestLimitsConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer ai.SetLimiter(nil)
+
+	cfg := config.DefaultConfig()
+	exec := executor.NewExecutor(cfg)
+	run := func(intent string) *executor.Result {
+		result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: intent, RawInput: "config:" + intent})
+		if err != nil {
+			t.Fatalf("config:%s failed: %v", intent, err)
+		}
+		return result
+	}
+
+	if result := run("limits show"); !strings.Contains(result.Output, "ollama: 1 at a time") {
+		t.Errorf("Expected the default Ollama limit, got:\n%s", result.Output)
+	}
+	if result := run("limits set openai 2"); result.IsError || cfg.ProviderConcurrency["openai"] != 2 {
+		t.Errorf("Expected the OpenAI limit to be 2, got %v: %s", cfg.ProviderConcurrency, result.Output)
+	}
+	if result := run("limits timeout 5"); result.IsError || cfg.ProviderQueueTimeoutSeconds != 5 {
+		t.Errorf("Expected a 5 second timeout, got %d: %s", cfg.ProviderQueueTimeoutSeconds, result.Output)
+	}
+	for _, intent := range []string{"limits set teleport 2", "limits set openai -1", "limits timeout 0"} {
+		if result := run(intent); !result.IsError {
+			t.Errorf("Expected config:%s to fail, got %s", intent, result.Output)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/metrics"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/server"
+	"github.com/agnath18K/lumo/pkg/setup"
+)
+
+// newMetricsConfig returns the default configuration with lumo's files in
+// temporary directories
+func newMetricsConfig(t *testing.T) *config.Config {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	return config.DefaultConfig()
+}
+
+// TestMetricsSummarize tests adding up recorded commands
+func TestMetricsSummarize(t *testing.T) {
+	day := time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC)
+	events := []metrics.Event{
+		{Time: day, Command: "ai", DurationMS: 1000},
+		{Time: day.Add(10 * time.Minute), Command: "ai", DurationMS: 3000, Failed: true},
+		{Time: day.Add(20 * time.Minute), Command: "shell", DurationMS: 50},
+		{Time: day.Add(26 * time.Hour), Command: "ai", DurationMS: 2000},
+	}
+
+	stats := metrics.Summarize(events)
+	if stats.Runs != 4 || stats.Failures != 1 || stats.ActiveDays != 2 || stats.BusiestHour != 9 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if !stats.First.Equal(day) || !stats.Last.Equal(day.Add(26*time.Hour)) {
+		t.Errorf("Unexpected period: %s to %s", stats.First, stats.Last)
+	}
+	want := []metrics.CommandStats{
+		{Command: "ai", Runs: 3, Failures: 1, AverageMS: 2000},
+		{Command: "shell", Runs: 1, AverageMS: 50},
+	}
+	if len(stats.Commands) != len(want) {
+		t.Fatalf("Expected %d commands, got %+v", len(want), stats.Commands)
+	}
+	for i := range want {
+		if stats.Commands[i] != want[i] {
+			t.Errorf("Commands[%d] = %+v, want %+v", i, stats.Commands[i], want[i])
+		}
+	}
+}
+
+// TestMetricsCollection tests that commands are only recorded once metrics
+// are enabled, and that purging leaves nothing behind
+func TestMetricsCollection(t *testing.T) {
+	cfg := newMetricsConfig(t)
+	exec := executor.NewExecutor(cfg)
+	help := &nlp.Command{Type: nlp.CommandTypeHelp, Intent: "help", RawInput: "help"}
+
+	// Nothing is recorded by default
+	if _, err := exec.Execute(help); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := metrics.Load(time.Time{}); len(events) != 0 {
+		t.Fatalf("Expected nothing recorded before enabling metrics, got %v", events)
+	}
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeStats, RawInput: "stats"})
+	if err != nil || !strings.Contains(result.Output, "config:metrics enable") {
+		t.Fatalf("Expected a hint to enable metrics, got %q (%v)", result.Output, err)
+	}
+
+	runConfig(t, exec, "metrics enable")
+	if saved, err := config.Load(); err != nil || !saved.EnableMetrics || !saved.MetricsAsked {
+		t.Fatalf("Expected enabled metrics to be saved, got %v", err)
+	}
+	exec.Execute(help)
+	exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: "nonsense", RawInput: "config:nonsense"})
+
+	events, err := metrics.Load(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Command != "help" || events[0].Failed || events[1].Command != "config" || !events[1].Failed {
+		t.Fatalf("Unexpected events: %+v", events)
+	}
+
+	// The command line goes to the stats command rather than the AI
+	cmd, err := nlp.NewParser(cfg).Parse("stats all")
+	if !cli.IsCommand("stats all") || err != nil || cmd.Type != nlp.CommandTypeStats {
+		t.Fatalf("Expected \"stats all\" to be a stats command, got %+v (%v)", cmd, err)
+	}
+	result, err = exec.Execute(cmd)
+	if err != nil || result.IsError || !strings.Contains(result.Output, "All time") || !strings.Contains(result.Output, "2 commands on 1 day, 1 failed") {
+		t.Errorf("Unexpected stats: %q (%v)", result.Output, err)
+	}
+
+	// Purging deletes everything, and is not recorded itself
+	if output := runConfig(t, exec, "metrics purge"); !strings.Contains(output, "Deleted the metrics of 3 commands") {
+		t.Errorf("Unexpected purge output: %q", output)
+	}
+	path, _ := metrics.Path()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be deleted, got %v", path, err)
+	}
+}
+
+// TestMetricsConsent tests the first-run question
+func TestMetricsConsent(t *testing.T) {
+	for _, tc := range []struct {
+		answer string
+		want   bool
+	}{
+		{"y\n", true},
+		{"Yes\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	} {
+		cfg := newMetricsConfig(t)
+		var out bytes.Buffer
+		if err := setup.AskMetricsConsent(cfg, strings.NewReader(tc.answer), &out); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.EnableMetrics != tc.want || !cfg.MetricsAsked {
+			t.Errorf("Answer %q: enabled %v, asked %v", tc.answer, cfg.EnableMetrics, cfg.MetricsAsked)
+		}
+		if !strings.Contains(out.String(), "config:metrics purge") {
+			t.Errorf("Expected the question to say how to delete the metrics, got %q", out.String())
+		}
+		if saved, err := config.Load(); err != nil || saved.EnableMetrics != tc.want || !saved.MetricsAsked {
+			t.Errorf("Answer %q was not saved (%v)", tc.answer, err)
+		}
+	}
+}
+
+// TestStatsEndpoint tests the stats the dashboard shows
+func TestStatsEndpoint(t *testing.T) {
+	cfg := newMetricsConfig(t)
+	cfg.EnableAuth = false
+	cfg.ServerQuietOutput = true
+	cfg.EnableMetrics = true
+	if err := metrics.Record("ai", time.Second, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(server.New(cfg, executor.NewExecutor(cfg)).Handler())
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/api/v1/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var stats server.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Enabled || stats.Stats.Runs != 1 || stats.Stats.Commands[0].Command != "ai" || stats.Stats.Commands[0].AverageMS != 1000 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// writeMockFixtures writes a fixture file for the mock provider
+func writeMockFixtures(t *testing.T, fixtures string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mock.json")
+	if err := os.WriteFile(path, []byte(fixtures), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestMockProviderFixtures tests answering from rules in a fixture file
+func TestMockProviderFixtures(t *testing.T) {
+	client := ai.NewMockClient(writeMockFixtures(t, `{
+		"rules": [
+			{"contains": "DISK", "response": "df -h"},
+			{"pattern": "^deploy \\w+$", "responses": ["first", "second"]}
+		],
+		"default": "no idea"
+	}`))
+
+	for _, tc := range []struct{ prompt, want string }{
+		{"how much disk is free", "df -h"},
+		{"deploy web", "first"},
+		{"deploy web", "second"},
+		{"deploy web", "second"},
+		{"deploy web now", "no idea"},
+	} {
+		if got, err := client.Query(tc.prompt); err != nil || got != tc.want {
+			t.Errorf("Expected %q for %q, got %q (%v)", tc.want, tc.prompt, got, err)
+		}
+	}
+
+	if _, err := ai.NewMockClient(writeMockFixtures(t, `{"rules": [`)).Query("hi"); err == nil {
+		t.Errorf("Expected invalid fixtures to be reported")
+	}
+}
+
+// TestMockProviderPlansWithoutFixtures tests that agent planning works with the built-in answers
+func TestMockProviderPlansWithoutFixtures(t *testing.T) {
+	client := ai.NewMockClient(filepath.Join(t.TempDir(), "missing.json"))
+
+	cfg := config.DefaultConfig()
+	plan, err := agent.NewPlanner(cfg, client).CreatePlan(context.Background(), &agent.Task{Description: "say hello"})
+	if err != nil || len(plan.Steps) != 1 || !strings.Contains(plan.Steps[0].Command, "mock step") {
+		t.Fatalf("Expected a one-step mock plan, got %+v (%v)", plan, err)
+	}
+
+	if answer, _ := client.Query("hello"); answer != "Mock answer to: hello" {
+		t.Errorf("Expected the built-in answer, got %q", answer)
+	}
+}
+
+// TestMockProviderFromEnvironment tests choosing the mock provider with LUMO_MOCK
+func TestMockProviderFromEnvironment(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("LUMO_MOCK", "1")
+	t.Setenv("LUMO_MOCK_FIXTURES", writeMockFixtures(t, `{"default": "from the fixture"}`))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AIProvider != "mock" {
+		t.Fatalf("Expected the mock provider, got %s", cfg.AIProvider)
+	}
+
+	exec := executor.NewExecutor(cfg)
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeAI, Intent: "anything", RawInput: "ask:anything"})
+	if err != nil || result.IsError || !strings.Contains(result.Output, "from the fixture") {
+		t.Fatalf("Expected the fixture's answer, got %+v (%v)", result, err)
+	}
+
+	// LUMO_MOCK lasts for the run; the saved provider is unchanged
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LUMO_MOCK", "")
+	if saved, _ := config.Load(); saved.AIProvider == "mock" {
+		t.Errorf("Expected the mock provider not to be saved")
+	}
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/notes"
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// TestNotes tests bookmarking the last result and finding it again
+func TestNotes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := notes.Save("early", nil); err == nil {
+		t.Errorf("Expected saving with no result recorded to fail")
+	}
+
+	if err := notes.RecordLast(notes.KindAnswer, "ask: how do I reload nginx", "Run: sudo systemctl reload nginx"); err != nil {
+		t.Fatalf("RecordLast failed: %v", err)
+	}
+	note, err := notes.Save("nginx-reload", []string{"Ops,#nginx", "ops"})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if strings.Join(note.Tags, ",") != "ops,nginx" {
+		t.Errorf("Expected normalized tags ops,nginx, got %v", note.Tags)
+	}
+	if _, err := notes.Save("nginx-reload", nil); err == nil {
+		t.Errorf("Expected saving over an existing note to fail")
+	}
+	if _, err := notes.Save("../escape", nil); err == nil {
+		t.Errorf("Expected a name with a path to be rejected")
+	}
+
+	if err := notes.RecordLast(notes.KindReport, "report:", "Disk: 80% used"); err != nil {
+		t.Fatalf("RecordLast failed: %v", err)
+	}
+	if _, err := notes.Save("disk", []string{"ops"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	list, err := notes.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Expected 2 notes, got %d (%v)", len(list), err)
+	}
+
+	for query, want := range map[string]int{
+		"systemctl nginx": 1,
+		"#ops":            2,
+		"#nginx disk":     0,
+		"USED":            1,
+	} {
+		matches, err := notes.Search(query)
+		if err != nil || len(matches) != want {
+			t.Errorf("Search(%q): expected %d matches, got %d (%v)", query, want, len(matches), err)
+		}
+	}
+
+	loaded, err := notes.Load("nginx-reload")
+	if err != nil || loaded.Content != "Run: sudo systemctl reload nginx" || loaded.Kind != notes.KindAnswer {
+		t.Errorf("Unexpected note: %+v (%v)", loaded, err)
+	}
+	if err := notes.Remove("disk"); err != nil {
+		t.Errorf("Remove failed: %v", err)
+	}
+	if _, err := notes.Load("disk"); err == nil {
+		t.Errorf("Expected the removed note to be gone")
+	}
+}
+
+// TestNotesIndex tests that search uses the index the daemon builds only
+// while it matches the notes
+func TestNotesIndex(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+
+	if count, err := notes.Index(); err != nil || count != 0 {
+		t.Fatalf("Expected an empty index without notes, got %d (%v)", count, err)
+	}
+	save := func(name, content string) {
+		t.Helper()
+		if err := notes.RecordLast(notes.KindAnswer, "ask: "+name, content); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := notes.Save(name, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	search := func(query string) int {
+		t.Helper()
+		matches, err := notes.Search(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(matches)
+	}
+	save("nginx", "Run: sudo systemctl reload nginx")
+	save("disk", "Disk: 80% used")
+	if count, err := notes.Index(); err != nil || count != 2 {
+		t.Fatalf("Expected 2 notes indexed, got %d (%v)", count, err)
+	}
+
+	// Search reads the index, not the note files, while it is up to date
+	dataDir, _ := paths.DataDir()
+	if err := os.WriteFile(filepath.Join(dataDir, "notes", "disk.json"), []byte(`{"kind": "answer", "content": "rewritten"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if search("80%") != 1 || search("rewritten") != 0 {
+		t.Errorf("Expected search to use the index")
+	}
+
+	// Adding or removing a note makes the index stale
+	save("backup", "Run: restic backup ~")
+	if search("restic") != 1 {
+		t.Errorf("Expected a note added after indexing to be found")
+	}
+	if err := notes.Remove("nginx"); err != nil {
+		t.Fatal(err)
+	}
+	if search("systemctl") != 0 {
+		t.Errorf("Expected a removed note not to be found")
+	}
+}
+
+// TestNotesCommands tests parsing and running save and notes commands
+func TestNotesCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+
+	for input, want := range map[string]nlp.CommandType{
+		"save fix":                nlp.CommandTypeSave,
+		"save fix --tag ops -t x": nlp.CommandTypeSave,
+		"save: fix":               nlp.CommandTypeSave,
+		"save money on groceries": nlp.CommandTypeAI,
+		"notes":                   nlp.CommandTypeNotes,
+		"notes search nginx":      nlp.CommandTypeNotes,
+		"notes about go channels": nlp.CommandTypeAI,
+	} {
+		cmd, err := parser.Parse(input)
+		if err != nil || cmd.Type != want {
+			t.Errorf("Parse(%q): expected type %v, got %v (%v)", input, want, cmd.Type, err)
+		}
+	}
+
+	if err := notes.RecordLast(notes.KindAnswer, "ask: list ports", "ss -tlnp"); err != nil {
+		t.Fatalf("RecordLast failed: %v", err)
+	}
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		cmd, _ := parser.Parse(input)
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) failed: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("save ports --tag=net"); result.IsError || !strings.Contains(result.Output, "#net") {
+		t.Errorf("Unexpected save result: %+v", result)
+	}
+	if result := run("notes list"); !strings.Contains(result.Output, "ports") {
+		t.Errorf("Expected the note to be listed, got: %s", result.Output)
+	}
+	if result := run("notes show ports"); !strings.Contains(result.Output, "ss -tlnp") {
+		t.Errorf("Expected the note's content, got: %s", result.Output)
+	}
+	if result := run("notes show missing"); !result.IsError {
+		t.Errorf("Expected showing a missing note to fail")
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/server"
+)
+
+// newGatewayServer returns a test server for lumo's routes, answering with the mock provider
+func newGatewayServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "mock"
+	cfg.MockFixtures = writeMockFixtures(t, `{
+		"rules": [
+			{"contains": "user: hi", "response": "transcript"},
+			{"contains": "pirate", "response": "instructions sent as the question"}
+		],
+		"default": "plain"
+	}`)
+	cfg.EnableAuth = false
+	cfg.ServerQuietOutput = true
+
+	ts := httptest.NewServer(server.New(cfg, executor.NewExecutor(cfg)).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// postChatCompletion sends a chat completion request and returns the response
+func postChatCompletion(t *testing.T, ts *httptest.Server, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(ts.URL+"/v1/chat/completions", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// TestChatCompletions tests answering OpenAI chat completion requests
+func TestChatCompletions(t *testing.T) {
+	ts := newGatewayServer(t)
+
+	for _, tc := range []struct{ name, messages, want string }{
+		{"single message", `[{"role": "user", "content": "what time is it"}]`, "plain"},
+		{"system instructions", `[{"role": "system", "content": "Talk like a pirate"}, {"role": "user", "content": "hello"}]`, "plain"},
+		{"content parts", `[{"role": "user", "content": [{"type": "text", "text": "what"}, {"type": "text", "text": "now"}]}]`, "plain"},
+		{"conversation", `[{"role": "user", "content": "hi"}, {"role": "assistant", "content": "hello"}, {"role": "user", "content": "and?"}]`, "transcript"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postChatCompletion(t, ts, `{"model": "gpt-4o", "messages": `+tc.messages+`}`)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected 200, got %d", resp.StatusCode)
+			}
+			var completion server.ChatCompletionResponse
+			if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+				t.Fatal(err)
+			}
+			if completion.Object != "chat.completion" || len(completion.Choices) != 1 ||
+				completion.Choices[0].Message.Role != "assistant" || completion.Choices[0].Message.Content != tc.want {
+				t.Errorf("Expected the answer %q, got %+v", tc.want, completion)
+			}
+			if !strings.HasPrefix(completion.ID, "chatcmpl-") || completion.Model != "mock/canned responses" {
+				t.Errorf("Unexpected completion ID or model: %s, %s", completion.ID, completion.Model)
+			}
+		})
+	}
+}
+
+// TestChatCompletionsStream tests streamed answers and errors in OpenAI's format
+func TestChatCompletionsStream(t *testing.T) {
+	ts := newGatewayServer(t)
+
+	resp := postChatCompletion(t, ts, `{"stream": true, "messages": [{"role": "user", "content": "hello"}]}`)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %s", ct)
+	}
+	var body strings.Builder
+	buf := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(buf)
+		body.WriteString(string(buf[:n]))
+		if err != nil {
+			break
+		}
+	}
+	events := strings.Split(strings.TrimSpace(body.String()), "\n\n")
+	if len(events) != 3 || events[2] != "data: [DONE]" ||
+		!strings.Contains(events[0], `"content":"plain"`) || !strings.Contains(events[1], `"finish_reason":"stop"`) {
+		t.Errorf("Unexpected stream: %q", body.String())
+	}
+
+	resp = postChatCompletion(t, ts, `{"messages": [{"role": "system", "content": "no question"}]}`)
+	var apiErr struct {
+		Error struct{ Message, Type string }
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || apiErr.Error.Type != "invalid_request_error" {
+		t.Errorf("Expected an invalid request error, got %d %+v", resp.StatusCode, apiErr)
+	}
+
+	models, err := http.Get(ts.URL + "/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer models.Body.Close()
+	var list struct {
+		Data []struct{ ID string }
+	}
+	if err := json.NewDecoder(models.Body).Decode(&list); err != nil || len(list.Data) != 1 || list.Data[0].ID != "mock/canned responses" {
+		t.Errorf("Expected the configured model, got %+v (%v)", list, err)
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/palette"
+)
+
+// TestPaletteColors tests the color formats
+func TestPaletteColors(t *testing.T) {
+	for _, tc := range []struct {
+		color         palette.Color
+		hex, rgb, hsl string
+	}{
+		{palette.Color{R: 255, G: 0, B: 0}, "#FF0000", "rgb(255, 0, 0)", "hsl(0, 100%, 50%)"},
+		{palette.Color{R: 255, G: 255, B: 255}, "#FFFFFF", "rgb(255, 255, 255)", "hsl(0, 0%, 100%)"},
+		{palette.Color{R: 53, G: 132, B: 228}, "#3584E4", "rgb(53, 132, 228)", "hsl(213, 76%, 55%)"},
+	} {
+		if got := tc.color.Hex(); got != tc.hex {
+			t.Errorf("Expected %s, got %s", tc.hex, got)
+		}
+		if got := tc.color.RGB(); got != tc.rgb {
+			t.Errorf("Expected %s, got %s", tc.rgb, got)
+		}
+		if got := tc.color.HSL(); got != tc.hsl {
+			t.Errorf("Expected %s, got %s", tc.hsl, got)
+		}
+	}
+
+	// Color pickers report channels from 0 to 1
+	if got := palette.FromFloats(1, 0.5, 0).Hex(); got != "#FF8000" {
+		t.Errorf("Expected #FF8000, got %s", got)
+	}
+}
+
+// TestPaletteExtract tests extracting the dominant colors of an image
+func TestPaletteExtract(t *testing.T) {
+	// Three quarters blue, one quarter orange, with a transparent strip
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 44))
+	for y := 0; y < 44; y++ {
+		for x := 0; x < 40; x++ {
+			switch {
+			case y >= 40:
+				img.Set(x, y, color.NRGBA{R: 0, G: 255, B: 0, A: 0})
+			case x < 30:
+				img.Set(x, y, color.NRGBA{R: 53, G: 132, B: 228, A: 255})
+			default:
+				img.Set(x, y, color.NRGBA{R: 255, G: 120, B: 0, A: 255})
+			}
+		}
+	}
+
+	swatches, err := palette.Extract(img, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(swatches) != 2 {
+		t.Fatalf("Expected two colors, got %+v", swatches)
+	}
+	if swatches[0].Color.Hex() != "#3584E4" || swatches[1].Color.Hex() != "#FF7800" {
+		t.Errorf("Unexpected colors: %s, %s", swatches[0].Color.Hex(), swatches[1].Color.Hex())
+	}
+	if swatches[0].Share != 0.75 {
+		t.Errorf("Expected the blue to cover 75%%, got %v", swatches[0].Share)
+	}
+	if _, err := palette.Extract(img, palette.MaxCount+1); err == nil {
+		t.Error("Expected too many colors to be refused")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "two tone.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.AccessibleOutput = true
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if !cli.IsCommand(input) || err != nil || cmd.Type != nlp.CommandTypePalette {
+			t.Fatalf("Expected %q to be a palette command, got %+v (%v)", input, cmd, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	result := run("palette " + path + " --count 3")
+	for _, want := range []string{"#3584E4", "rgb(53, 132, 228)", "hsl(213, 76%, 55%)", "75%", "#FF7800", "25%"} {
+		if !strings.Contains(result.Output, want) {
+			t.Errorf("Expected %q in:\n%s", want, result.Output)
+		}
+	}
+	if strings.Contains(result.Output, "\x1b[") {
+		t.Errorf("Expected no color blocks in accessible output:\n%s", result.Output)
+	}
+	if result := run("palette " + path + " --count=0"); !result.IsError || !strings.Contains(result.Output, "invalid count") {
+		t.Errorf("Expected a count of 0 to be refused: %+v", result)
+	}
+	if result := run("palette " + filepath.Join(dir, "missing.png")); !result.IsError {
+		t.Errorf("Expected a missing image to be an error: %+v", result)
+	}
+	if result := run("palette"); !result.IsError || !strings.Contains(result.Output, "image is required") {
+		t.Errorf("Expected an image to be required: %+v", result)
+	}
+}
@@ -40,6 +40,7 @@ func TestParserCommandTypeDetection(t *testing.T) {
 		// Agent commands
 		{"agent:create a backup", nlp.CommandTypeAgent, "Agent command with agent: prefix"},
 		{"auto:install nodejs", nlp.CommandTypeAgent, "Agent command with auto: prefix"},
+		{"analyze:why is my disk full", nlp.CommandTypeAnalyze, "Read-only analysis with analyze: prefix"},
 
 		// System health commands
 		{"health:cpu", nlp.CommandTypeSystemHealth, "System health command with health: prefix"},
@@ -75,6 +76,11 @@ func TestParserCommandTypeDetection(t *testing.T) {
 		// Create commands
 		{"create", nlp.CommandTypeCreate, "Create command"},
 		{"create:flutter app", nlp.CommandTypeCreate, "Create command with project type"},
+
+		// Desktop integration commands
+		{"integrate shortcuts", nlp.CommandTypeIntegrate, "Integrate command"},
+		{"last --as-script", nlp.CommandTypeLast, "Last run command"},
+		{"discover", nlp.CommandTypeDiscover, "Discover command"},
 	}
 
 	// Run test cases
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/paths"
+)
+
+// TestPathsXDG tests following the XDG base directories, with fallbacks under the home directory
+func TestPathsXDG(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "relative/is/ignored")
+
+	for name, tc := range map[string]struct {
+		dir  func() (string, error)
+		want string
+	}{
+		"config": {paths.ConfigDir, filepath.Join(home, ".config", "lumo")},
+		"state":  {paths.StateDir, filepath.Join(home, ".local", "state", "lumo")},
+		"cache":  {paths.CacheDir, filepath.Join(home, ".cache", "lumo")},
+	} {
+		if dir, err := tc.dir(); err != nil || dir != tc.want {
+			t.Errorf("Expected the %s directory %s, got %s (%v)", name, tc.want, dir, err)
+		}
+	}
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", xdg)
+	if dir, _ := paths.StateDir(); dir != filepath.Join(xdg, "lumo") {
+		t.Errorf("Expected $XDG_STATE_HOME to be used, got %s", dir)
+	}
+}
+
+// TestPathsConfigFile tests choosing another configuration file
+func TestPathsConfigFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	defer paths.SetConfigFile("")
+
+	if err := paths.SetConfigFile("work.json"); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := paths.ConfigFile()
+	if !filepath.IsAbs(path) || filepath.Base(path) != "work.json" {
+		t.Errorf("Expected an absolute path to work.json, got %s", path)
+	}
+
+	paths.SetConfigFile("")
+	if path, _ := paths.ConfigFile(); filepath.Base(filepath.Dir(path)) != "lumo" {
+		t.Errorf("Expected the default config file, got %s", path)
+	}
+}
+
+// TestPathsMigrateLegacy tests moving files from where older versions kept them
+func TestPathsMigrateLegacy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", "")
+	if err := os.WriteFile(filepath.Join(home, ".lumo_history"), []byte("ask:hi\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	paths.MigrateLegacy()
+
+	stateDir, _ := paths.StateDir()
+	data, err := os.ReadFile(filepath.Join(stateDir, "history"))
+	if err != nil || string(data) != "ask:hi\n" {
+		t.Errorf("Expected the history to be moved, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".lumo_history")); !os.IsNotExist(err) {
+		t.Errorf("Expected the old history file to be gone")
+	}
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/chat"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// instructedClient is a mock AI client that takes system instructions
+type instructedClient struct {
+	MockAIClient
+	instructions []string
+}
+
+// QueryWithInstructions records the instructions and answers like Query
+func (m *instructedClient) QueryWithInstructions(query string, instructions string) (string, error) {
+	m.instructions = append(m.instructions, instructions)
+	return m.Query(query)
+}
+
+// TestQueryAs tests sending a query with a persona's instructions
+func TestQueryAs(t *testing.T) {
+	instructed := &instructedClient{}
+	if _, err := ai.QueryAs(instructed, "list files", "Be terse."); err != nil {
+		t.Fatalf("QueryAs failed: %v", err)
+	}
+	if len(instructed.instructions) != 1 || instructed.instructions[0] != "Be terse." || instructed.QueryCalls[0] != "list files" {
+		t.Errorf("Expected the instructions to be passed separately, got %v %v", instructed.instructions, instructed.QueryCalls)
+	}
+
+	// Clients without instructions get them in the query
+	plain := &MockAIClient{}
+	if _, err := ai.QueryAs(plain, "list files", "Be terse."); err != nil {
+		t.Fatalf("QueryAs failed: %v", err)
+	}
+	if !strings.Contains(plain.QueryCalls[0], "Be terse.") || !strings.HasSuffix(plain.QueryCalls[0], "list files") {
+		t.Errorf("Expected the instructions at the start of the query, got %q", plain.QueryCalls[0])
+	}
+}
+
+// TestPersonaLookup tests that custom personas replace built-in ones of the same name
+func TestPersonaLookup(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if _, ok := ai.Persona(cfg, "tutor"); !ok {
+		t.Errorf("Expected a built-in tutor persona")
+	}
+
+	cfg.Personas = map[string]string{"tutor": "Explain like I'm five.", "pirate": "Talk like a pirate."}
+	if instructions, _ := ai.Persona(cfg, "tutor"); instructions != "Explain like I'm five." {
+		t.Errorf("Expected the custom tutor persona, got %q", instructions)
+	}
+	if names := strings.Join(ai.PersonaNames(cfg), ","); names != "pirate,sysadmin,tutor" {
+		t.Errorf("Unexpected persona names: %s", names)
+	}
+}
+
+// TestPersonaConfig tests defining personas and choosing one per query
+func TestPersonaConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	// A local provider needs no API key, so queries reach the persona check
+	cfg.AIProvider = "ollama"
+	exec := executor.NewExecutor(cfg)
+
+	run := func(kind nlp.CommandType, intent string) *executor.Result {
+		result, err := exec.Execute(&nlp.Command{Type: kind, Intent: intent, Parameters: map[string]string{}, RawInput: intent})
+		if err != nil {
+			t.Fatalf("Execute(%q) failed: %v", intent, err)
+		}
+		return result
+	}
+
+	if result := run(nlp.CommandTypeConfig, "persona set pirate Talk like a pirate."); result.IsError {
+		t.Fatalf("Failed to define a persona: %s", result.Output)
+	}
+	if cfg.Personas["pirate"] != "Talk like a pirate." {
+		t.Errorf("Expected the persona to be stored, got %v", cfg.Personas)
+	}
+	if result := run(nlp.CommandTypeConfig, "persona default pirate"); result.IsError || cfg.DefaultPersona != "pirate" {
+		t.Errorf("Failed to set the default persona: %s", result.Output)
+	}
+	if result := run(nlp.CommandTypeConfig, "persona remove tutor"); !result.IsError {
+		t.Errorf("Expected built-in personas not to be removable")
+	}
+	if result := run(nlp.CommandTypeConfig, "persona remove pirate"); result.IsError || cfg.DefaultPersona != "" {
+		t.Errorf("Expected removing the default persona to clear the default: %s", result.Output)
+	}
+
+	result := run(nlp.CommandTypeAI, "--persona=ghost how do I list files")
+	if !result.IsError || !strings.Contains(result.Output, "unknown persona: ghost") {
+		t.Errorf("Expected an error for an unknown persona, got %q", result.Output)
+	}
+}
+
+// TestConversationSetInstructions tests switching a conversation's persona mid-session
+func TestConversationSetInstructions(t *testing.T) {
+	conv := chat.NewConversation("Be friendly.", 10)
+	conv.AddUserMessage("hello")
+	conv.SetInstructions("Be terse.")
+
+	messages := conv.GetMessages()
+	if len(messages) != 2 || messages[0].Role != chat.RoleSystem || messages[0].Content != "Be terse." {
+		t.Errorf("Expected the instructions replaced and the history kept, got %+v", messages)
+	}
+}
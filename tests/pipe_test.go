@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/pipe"
+)
+
+// TestPipeMergeInput tests combining piped input with a question about it
+func TestPipeMergeInput(t *testing.T) {
+	processor := pipe.NewProcessor(nil)
+
+	prompt, err := processor.MergeInput(strings.NewReader("panic: nil map\n"), "why is this failing")
+	if err != nil {
+		t.Fatalf("Failed to merge input: %v", err)
+	}
+	if !strings.HasPrefix(prompt, "why is this failing") || !strings.Contains(prompt, "panic: nil map\n--- END INPUT ---") {
+		t.Errorf("Expected the question followed by the piped input, got %q", prompt)
+	}
+
+	// Empty input leaves the question alone
+	if prompt, _ := processor.MergeInput(strings.NewReader("\n"), "hello"); prompt != "hello" {
+		t.Errorf("Expected the prompt unchanged for empty input, got %q", prompt)
+	}
+
+	// Long input keeps its end, starting at a whole line
+	long := strings.Repeat("old line\n", 20000) + "the real error\n"
+	prompt = pipe.MergePrompt(long, "what went wrong")
+	if len(prompt) > 70*1024 || !strings.Contains(prompt, "the real error") || !strings.Contains(prompt, "only the end is shown") {
+		t.Errorf("Expected long input to be cut to its end, got %d bytes", len(prompt))
+	}
+	if !strings.Contains(prompt, "--- BEGIN INPUT ---\nold line\n") {
+		t.Errorf("Expected the cut to fall on a line boundary")
+	}
+}
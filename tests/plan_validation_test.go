@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+)
+
+// TestPlanValidation tests checking plan steps against installed tools and files
+func TestPlanValidation(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	plan := &agent.Plan{
+		Steps: []*agent.Step{
+			{ID: 1, Command: "lumo-definitely-not-installed --version"},
+			{ID: 2, Command: "cat " + missing},
+			{ID: 3, Command: "echo 'a | b' > notes.txt && cat notes.txt 2>&1"},
+			{ID: 4, Command: "cat <<EOF > report.txt\nnot a command\nEOF"},
+		},
+	}
+
+	warnings := agent.NewPlanValidator().Validate(plan)
+	if len(warnings) != len(plan.Steps) {
+		t.Fatalf("Expected warnings for %d steps, got %d", len(plan.Steps), len(warnings))
+	}
+	if len(warnings[0]) != 1 || !strings.Contains(warnings[0][0], "not installed") {
+		t.Errorf("Expected a missing tool warning for step 1, got %v", warnings[0])
+	}
+	if len(warnings[1]) != 1 || !strings.Contains(warnings[1][0], "does not exist") {
+		t.Errorf("Expected a missing file warning for step 2, got %v", warnings[1])
+	}
+	if len(warnings[2]) != 0 {
+		t.Errorf("Expected no warnings for a file the plan creates, got %v", warnings[2])
+	}
+	if len(warnings[3]) != 0 {
+		t.Errorf("Expected here-document bodies to be ignored, got %v", warnings[3])
+	}
+}
+
+// TestPlanValidationFlags tests probing --help for unsupported flags
+func TestPlanValidationFlags(t *testing.T) {
+	if out, err := exec.Command("ls", "--help").CombinedOutput(); err != nil || !strings.Contains(string(out), "--all") {
+		t.Skip("ls --help is not available")
+	}
+
+	plan := &agent.Plan{
+		Steps: []*agent.Step{
+			{ID: 1, Command: "ls -la --all"},
+			{ID: 2, Command: "ls --lumo-no-such-flag"},
+		},
+	}
+
+	warnings := agent.NewPlanValidator().Validate(plan)
+	if len(warnings[0]) != 0 {
+		t.Errorf("Expected no warnings for supported flags, got %v", warnings[0])
+	}
+	if len(warnings[1]) != 1 || !strings.Contains(warnings[1][0], "--lumo-no-such-flag") {
+		t.Errorf("Expected an unsupported flag warning, got %v", warnings[1])
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/agent"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/system"
+	"github.com/agnath18K/lumo/tests/mocks"
+)
+
+// TestParseOSRelease tests reading an os-release file
+func TestParseOSRelease(t *testing.T) {
+	release := system.ParseOSRelease(strings.NewReader(`# Comment
+NAME="Fedora Linux"
+VERSION_ID=40
+ID=fedora
+ID_LIKE="rhel centos"
+PRETTY_NAME="Fedora Linux 40 (Workstation Edition)"
+`))
+
+	expected := map[string]string{
+		"NAME":        "Fedora Linux",
+		"VERSION_ID":  "40",
+		"ID":          "fedora",
+		"ID_LIKE":     "rhel centos",
+		"PRETTY_NAME": "Fedora Linux 40 (Workstation Edition)",
+	}
+	for key, value := range expected {
+		if release[key] != value {
+			t.Errorf("%s = %q, expected %q", key, release[key], value)
+		}
+	}
+}
+
+// TestPackageManagerFor tests choosing the package manager for a distribution
+func TestPackageManagerFor(t *testing.T) {
+	installed := func(names ...string) func(string) bool {
+		return func(name string) bool {
+			for _, n := range names {
+				if n == name {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		goos       string
+		distroID   string
+		distroLike []string
+		installed  []string
+		expected   string
+	}{
+		{"fedora", "linux", "fedora", nil, []string{"dnf", "apt"}, "dnf"},
+		{"debian", "linux", "debian", nil, []string{"apt", "dnf"}, "apt"},
+		{"derivative", "linux", "pop", []string{"ubuntu", "debian"}, []string{"apt"}, "apt"},
+		{"variant", "linux", "opensuse-tumbleweed", []string{"suse"}, []string{"zypper"}, "zypper"},
+		{"old rhel", "linux", "centos", []string{"rhel", "fedora"}, []string{"yum"}, "yum"},
+		{"unknown distro", "linux", "mystery", nil, []string{"pacman"}, "pacman"},
+		{"macos", "darwin", "", nil, []string{"brew"}, "brew"},
+		{"nothing installed", "linux", "fedora", nil, nil, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := system.PackageManagerFor(tc.goos, tc.distroID, tc.distroLike, installed(tc.installed...))
+			if got != tc.expected {
+				t.Errorf("PackageManagerFor = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestPlatformPromptContext tests describing a platform for the planner
+func TestPlatformPromptContext(t *testing.T) {
+	platform := &system.Platform{
+		OS:                   "linux",
+		Arch:                 "amd64",
+		Distro:               "Fedora Linux 40",
+		DistroID:             "fedora",
+		PackageManager:       "dnf",
+		OtherPackageManagers: []string{"flatpak"},
+		InitSystem:           "systemd",
+		Shell:                "zsh",
+	}
+
+	description := platform.PromptContext()
+	for _, expected := range []string{"Fedora Linux 40", "Package manager: dnf", "flatpak", "systemctl", "zsh"} {
+		if !strings.Contains(description, expected) {
+			t.Errorf("Expected prompt context to mention %q, got:\n%s", expected, description)
+		}
+	}
+}
+
+// TestPlannerIncludesPlatform tests that plans are requested for the detected platform
+func TestPlannerIncludesPlatform(t *testing.T) {
+	client := mocks.NewMockAIClientWithCustomResponses("", `{"description": "noop", "steps": []}`, "")
+	planner := agent.NewPlanner(config.DefaultConfig(), client)
+
+	if _, err := planner.CreatePlan(context.Background(), &agent.Task{Description: "install htop"}); err != nil {
+		t.Fatalf("CreatePlan failed: %v", err)
+	}
+	if len(client.CompletionCalls) != 1 {
+		t.Fatalf("Expected one completion call, got %d", len(client.CompletionCalls))
+	}
+	if !strings.Contains(client.CompletionCalls[0], system.DetectPlatform().PromptContext()) {
+		t.Error("Expected the planning prompt to describe the platform")
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/system"
+)
+
+// TestSysfsPowerStatus tests reading the power source from sysfs
+func TestSysfsPowerStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		supplies   map[string]map[string]string
+		wantErr    bool
+		onBattery  bool
+		hasBattery bool
+		percentage float64
+	}{
+		{
+			name:    "no supplies",
+			wantErr: true,
+		},
+		{
+			name: "laptop on AC",
+			supplies: map[string]map[string]string{
+				"AC":   {"type": "Mains", "online": "1"},
+				"BAT0": {"type": "Battery", "capacity": "80"},
+			},
+			hasBattery: true,
+			percentage: 80,
+		},
+		{
+			name: "laptop on battery",
+			supplies: map[string]map[string]string{
+				"AC":   {"type": "Mains", "online": "0\n"},
+				"BAT0": {"type": "Battery\n", "capacity": "42\n"},
+			},
+			onBattery:  true,
+			hasBattery: true,
+			percentage: 42,
+		},
+		{
+			name: "desktop",
+			supplies: map[string]map[string]string{
+				"AC": {"type": "Mains", "online": "1"},
+			},
+		},
+		{
+			name: "battery without a mains supply",
+			supplies: map[string]map[string]string{
+				"BAT0": {"type": "Battery", "capacity": "not a number"},
+			},
+			hasBattery: true,
+		},
+		{
+			name: "other supplies are ignored",
+			supplies: map[string]map[string]string{
+				"AC":         {"type": "Mains", "online": "1"},
+				"hidpp_bat0": {"type": "USB", "capacity": "10"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for supply, files := range tt.supplies {
+				if err := os.Mkdir(filepath.Join(dir, supply), 0755); err != nil {
+					t.Fatal(err)
+				}
+				for name, value := range files {
+					if err := os.WriteFile(filepath.Join(dir, supply, name), []byte(value), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			status, err := system.ReadSysfsPowerStatus(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSysfsPowerStatus() error: %v", err)
+			}
+			if status.OnBattery != tt.onBattery || status.HasBattery != tt.hasBattery ||
+				status.Percentage != tt.percentage || status.Source != "sysfs" {
+				t.Errorf("Got %+v, want on battery %v, has battery %v, %v%%",
+					status, tt.onBattery, tt.hasBattery, tt.percentage)
+			}
+		})
+	}
+}
+
+// TestConservePower tests when each power mode switches to lighter behavior
+func TestConservePower(t *testing.T) {
+	onBattery := &system.PowerStatus{OnBattery: true, HasBattery: true}
+	onAC := &system.PowerStatus{HasBattery: true}
+
+	tests := []struct {
+		mode   string
+		status *system.PowerStatus
+		want   bool
+	}{
+		{system.PowerModeAuto, onBattery, true},
+		{system.PowerModeAuto, onAC, false},
+		{system.PowerModeAuto, nil, false},
+		{"", onBattery, true},
+		{system.PowerModeBattery, onAC, true},
+		{system.PowerModeBattery, nil, true},
+		{system.PowerModeAC, onBattery, false},
+	}
+	for _, tt := range tests {
+		if got := system.ConservePower(tt.mode, tt.status); got != tt.want {
+			t.Errorf("ConservePower(%q, %+v) = %v, want %v", tt.mode, tt.status, got, tt.want)
+		}
+	}
+
+	if !system.ShouldConservePower(system.PowerModeBattery) {
+		t.Error("Expected the battery mode to always conserve power")
+	}
+	if system.ShouldConservePower(system.PowerModeAC) {
+		t.Error("Expected the AC mode never to conserve power")
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// newConflictExecutor returns an executor using OpenAI's gpt-4o with its
+// configuration saved to a temporary directory
+func newConflictExecutor(t *testing.T) (*config.Config, *executor.Executor) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "openai"
+	cfg.OpenAIAPIKey = "test-key"
+	cfg.OpenAIModel = "gpt-4o"
+	return cfg, executor.NewExecutor(cfg)
+}
+
+// runConfig runs a config: command and fails the test on an error result
+func runConfig(t *testing.T, exec *executor.Executor, intent string) string {
+	t.Helper()
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: intent, RawInput: "config:" + intent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError {
+		t.Fatalf("config:%s failed: %s", intent, result.Output)
+	}
+	return result.Output
+}
+
+// TestModelSwitchLosingVision tests resolving a model change that breaks questions about images
+func TestModelSwitchLosingVision(t *testing.T) {
+	cfg, exec := newConflictExecutor(t)
+
+	var problems []string
+	choose := func(choice int) executor.ConflictResolver {
+		return func(problem string, options []string) int {
+			problems = append(problems, problem)
+			if choice < 0 {
+				return len(options) - 1
+			}
+			return choice
+		}
+	}
+
+	// Cancelling keeps the current model
+	exec.SetConflictResolver(choose(-1))
+	if output := runConfig(t, exec, "model set gpt-3.5-turbo"); !strings.Contains(output, "Kept the openai model gpt-4o") {
+		t.Errorf("Expected the change to be cancelled, got %q", output)
+	}
+	if cfg.OpenAIModel != "gpt-4o" {
+		t.Errorf("Expected gpt-4o to be kept, got %s", cfg.OpenAIModel)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "cannot read images") {
+		t.Fatalf("Expected to be asked about images, got %v", problems)
+	}
+
+	// The recommended resolution picks a model that reads images
+	exec.SetConflictResolver(choose(0))
+	if output := runConfig(t, exec, "model set gpt-3.5-turbo"); !strings.Contains(output, "openai model set to: gpt-4o-mini") {
+		t.Errorf("Expected gpt-4o-mini to be used instead, got %q", output)
+	}
+	if saved, err := config.Load(); err != nil || saved.OpenAIModel != "gpt-4o-mini" {
+		t.Errorf("Expected gpt-4o-mini to be saved, got %v", err)
+	}
+
+	// Models that keep reading images raise no conflict
+	problems = nil
+	runConfig(t, exec, "model set gpt-4o")
+	if len(problems) != 0 {
+		t.Errorf("Expected no conflicts, got %v", problems)
+	}
+
+	// Without anyone to ask, the change goes ahead with a hint
+	stdin, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+	defer stdin.Close()
+	oldStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = oldStdin }()
+	exec.SetConflictResolver(nil)
+	output := runConfig(t, exec, "model set gpt-3.5-turbo")
+	if cfg.OpenAIModel != "gpt-3.5-turbo" || !strings.Contains(output, "lumo -p openai ask:--image") {
+		t.Errorf("Expected the model to change with a hint, got %s: %q", cfg.OpenAIModel, output)
+	}
+}
+
+// TestProviderSwitchOverBudget tests resolving a switch to a cloud provider once the budget is spent
+func TestProviderSwitchOverBudget(t *testing.T) {
+	cfg, _ := newConflictExecutor(t)
+	cfg.AIProvider = "mock"
+	cfg.BudgetUSD = 5
+	cfg.BudgetAction = config.BudgetBlock
+	exec := executor.NewExecutor(cfg)
+	if err := usage.Record("openai", "gpt-4o", 0, 1000000); err != nil {
+		t.Fatal(err)
+	}
+
+	var asked []string
+	exec.SetConflictResolver(func(problem string, options []string) int {
+		asked = append(asked, problem)
+		return 1
+	})
+	output := runConfig(t, exec, "provider set openai")
+	if len(asked) != 1 || !strings.Contains(asked[0], "budget of $5.00 is spent ($10.00 so far)") {
+		t.Fatalf("Expected to be asked about the budget, got %v", asked)
+	}
+	if cfg.AIProvider != "openai" || cfg.BudgetUSD != 15 || cfg.BudgetAction != config.BudgetBlock {
+		t.Errorf("Expected the budget to be raised to $15, got %s $%.2f %s", cfg.AIProvider, cfg.BudgetUSD, cfg.BudgetAction)
+	}
+	if !strings.Contains(output, "Raise the budget to $15.00") {
+		t.Errorf("Expected the resolution in the output, got %q", output)
+	}
+
+	// Switching between cloud providers changes nothing about the budget
+	cfg.GeminiAPIKey = "test-key"
+	cfg.BudgetUSD = 5
+	asked = nil
+	runConfig(t, exec, "provider set gemini")
+	if len(asked) != 0 {
+		t.Errorf("Expected no conflicts, got %v", asked)
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/server"
+)
+
+// TestServerLimits tests the server's rate limit and request size limit
+func TestServerLimits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	cfg := config.DefaultConfig()
+	cfg.EnableAuth = true
+	cfg.ServerQuietOutput = true
+	cfg.JWTSecret = "test-secret"
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("config:server ratelimit 0"); !result.IsError {
+		t.Errorf("Expected a zero rate limit to be refused: %s", result.Output)
+	}
+	if result := run("config:server ratelimit 6 3"); result.IsError || cfg.ServerRateLimit != 6 || cfg.ServerRateBurst != 3 {
+		t.Fatalf("Expected the rate limit to be set: %s", result.Output)
+	}
+	if result := run("config:server maxbody 1KB"); result.IsError || cfg.ServerMaxBodyKB != 1 {
+		t.Fatalf("Expected the body limit to be set: %s", result.Output)
+	}
+	if result := run("config:server show"); !strings.Contains(result.Output, "6 requests per minute, bursts of 3") {
+		t.Errorf("Expected the limits in the settings:\n%s", result.Output)
+	}
+	key := ""
+	for _, field := range strings.Fields(run("server:apikey create --name ci --scope all").Output) {
+		if strings.HasPrefix(field, "lumo_") {
+			key = field
+		}
+	}
+
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	defer ts.Close()
+	request := func(method, path string, body io.Reader, header http.Header) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, body)
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	for i := 0; i < 3; i++ {
+		resp := request("GET", "/api/v1/status", nil, http.Header{})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected request %d within the burst to succeed, got status %d", i+1, resp.StatusCode)
+		}
+		if got, want := resp.Header.Get("X-RateLimit-Remaining"), strconv.Itoa(2-i); got != want {
+			t.Errorf("Expected %s requests remaining, got %q", want, got)
+		}
+	}
+	resp := request("GET", "/api/v1/status", nil, http.Header{})
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "10" {
+		t.Errorf("Expected 429 with Retry-After 10 past the burst, got status %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if resp := request("GET", "/static/app.js", nil, http.Header{}); resp.StatusCode == http.StatusTooManyRequests {
+		t.Errorf("Expected the web interface not to be rate limited")
+	}
+
+	// Requests with an API key count against the key, not the address
+	withKey := http.Header{"X-Api-Key": {key}}
+	if resp := request("GET", "/api/v1/stats", nil, withKey); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the key to have its own limit, got status %d", resp.StatusCode)
+	}
+
+	large := strings.Repeat("x", 2048)
+	if resp := request("POST", "/api/v1/execute", strings.NewReader(`{"command": "`+large+`"}`), withKey); resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected a large body to be refused, got status %d", resp.StatusCode)
+	}
+	// Without a Content-Length the body is cut off while it is read
+	chunked := io.MultiReader(strings.NewReader(`{"command": "` + large + `"}`))
+	if resp := request("POST", "/api/v1/execute", chunked, withKey); resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected a large chunked body to be refused, got status %d", resp.StatusCode)
+	}
+}
+
+// TestAuthFailureLimit tests that failed logins and unknown credentials are
+// limited per address before they are checked
+func TestAuthFailureLimit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	cfg := config.DefaultConfig()
+	cfg.EnableAuth = true
+	cfg.ServerQuietOutput = true
+	cfg.JWTSecret = "test-secret"
+	cfg.ServerRateLimit = 6
+	cfg.ServerRateBurst = 3
+	exec := executor.NewExecutor(cfg)
+
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	defer ts.Close()
+	request := func(method, path, body string, header http.Header) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// Requests that pass authentication do not count
+	for i := 0; i < 3; i++ {
+		if resp := request("GET", "/api/v1/status", "", http.Header{}); resp.StatusCode == http.StatusTooManyRequests {
+			t.Fatalf("Expected request %d to stay within the per-address limit", i+1)
+		}
+	}
+
+	badKey := http.Header{"X-Api-Key": {"lumo_guess"}}
+	for i := 0; i < 3; i++ {
+		if resp := request("GET", "/api/v1/stats", "", badKey); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("Expected guess %d to be refused with 401, got status %d", i+1, resp.StatusCode)
+		}
+	}
+	resp := request("GET", "/api/v1/stats", "", badKey)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "10" {
+		t.Errorf("Expected 429 with Retry-After 10 after repeated 401s, got status %d, Retry-After %q",
+			resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if resp := request("POST", "/api/v1/auth/login", `{"username": "admin", "password": "guess"}`, http.Header{}); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected password guesses from the address to be limited too, got status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/server"
+)
+
+// TestRoles tests the endpoints and commands each role may use
+func TestRoles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"message": {"role": "assistant", "content": "Use df -h."}, "done": true}`)
+	}))
+	defer ollama.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.EnableAuth = true
+	cfg.ServerQuietOutput = true
+	cfg.JWTSecret = "test-secret"
+	cfg.AIProvider = "ollama"
+	cfg.OllamaURL = ollama.URL
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+	secret := func(result *executor.Result) string {
+		t.Helper()
+		if result.IsError {
+			t.Fatalf("Expected success: %s", result.Output)
+		}
+		lines := strings.Split(result.Output, "\n")
+		return strings.TrimSpace(lines[2])
+	}
+
+	viewerKey := secret(run("server:apikey create --name dashboard --role viewer"))
+	operatorKey := secret(run("server:apikey create --name ci --scope all"))
+	if result := run("server:apikey create --name bad --role root"); !result.IsError || !strings.Contains(result.Output, "unknown role") {
+		t.Errorf("Expected an unknown role to be refused: %s", result.Output)
+	}
+	if result := run("server:apikey list"); !strings.Contains(result.Output, "viewer") || !strings.Contains(result.Output, "operator") {
+		t.Errorf("Expected the keys listed with their roles:\n%s", result.Output)
+	}
+
+	rootPassword := secret(run("server:user add root --role admin"))
+	anaPassword := secret(run("server:user add ana"))
+	if result := run("server:user role root viewer"); !result.IsError || !strings.Contains(result.Output, "only admin") {
+		t.Errorf("Expected the last admin to keep the role: %s", result.Output)
+	}
+	if result := run("server:user remove root"); !result.IsError {
+		t.Errorf("Expected the last admin not to be removed: %s", result.Output)
+	}
+	if result := run("server:user list"); !strings.Contains(result.Output, "ana") || !strings.Contains(result.Output, "viewer") {
+		t.Errorf("Expected the users listed with their roles:\n%s", result.Output)
+	}
+
+	ts := httptest.NewServer(server.New(cfg, exec).Handler())
+	defer ts.Close()
+	request := func(method, path, body string, header http.Header) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	login := func(username, password string) (string, string) {
+		t.Helper()
+		status, body := request("POST", "/api/v1/auth/login",
+			fmt.Sprintf(`{"username": %q, "password": %q}`, username, password), http.Header{})
+		if status != http.StatusOK {
+			t.Fatalf("Expected %s to log in, got status %d: %s", username, status, body)
+		}
+		var resp server.LoginResponse
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + resp.Token, resp.Role
+	}
+	anaToken, anaRole := login("ana", anaPassword)
+	if anaRole != "viewer" {
+		t.Errorf("Expected the login to report the viewer role, got %q", anaRole)
+	}
+	rootToken, _ := login("root", rootPassword)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		header http.Header
+		want   int
+	}{
+		{"viewer asks the AI", "/api/v1/execute", `{"command": "how much disk is free?", "type": "ai"}`, http.Header{"X-Api-Key": {viewerKey}}, http.StatusOK},
+		{"viewer runs a shell command", "/api/v1/execute", `{"command": "echo hi", "type": "shell"}`, http.Header{"X-Api-Key": {viewerKey}}, http.StatusForbidden},
+		{"viewer parsed agent command", "/api/v1/execute", `{"command": "agent:clean up the tmp folder"}`, http.Header{"X-Api-Key": {viewerKey}}, http.StatusForbidden},
+		{"viewer runs an agent", "/api/v1/execute", `{"command": "clean up", "type": "agent"}`, http.Header{"X-Api-Key": {viewerKey}}, http.StatusForbidden},
+		{"viewer agent endpoint", "/api/v1/agent/run", `{}`, http.Header{"X-Api-Key": {viewerKey}}, http.StatusForbidden},
+		{"logged-in viewer", "/api/v1/execute", `{"command": "echo hi", "type": "shell"}`, http.Header{"Authorization": {anaToken}}, http.StatusForbidden},
+		{"logged-in viewer stats", "/api/v1/stats", ``, http.Header{"Authorization": {anaToken}}, http.StatusOK},
+		{"operator runs a shell command", "/api/v1/execute", `{"command": "echo hi", "type": "shell"}`, http.Header{"X-Api-Key": {operatorKey}}, http.StatusOK},
+		{"operator changes settings", "/api/v1/execute", `{"command": "config:server auth disable"}`, http.Header{"X-Api-Key": {operatorKey}}, http.StatusForbidden},
+		{"admin runs a shell command", "/api/v1/execute", `{"command": "echo hi", "type": "shell"}`, http.Header{"Authorization": {rootToken}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		method := "POST"
+		if tt.body == "" {
+			method = "GET"
+		}
+		if got, body := request(method, tt.path, tt.body, tt.header); got != tt.want {
+			t.Errorf("%s: got status %d, want %d: %s", tt.name, got, tt.want, body)
+		}
+	}
+	if !cfg.EnableAuth {
+		t.Errorf("Expected an operator not to change settings")
+	}
+
+	if result := run("server:user role ana operator"); result.IsError {
+		t.Fatalf("Expected the role to change: %s", result.Output)
+	}
+	if got, body := request("POST", "/api/v1/execute", `{"command": "echo hi", "type": "shell"}`, http.Header{"Authorization": {anaToken}}); got != http.StatusOK {
+		t.Errorf("Expected a changed role to apply without logging in again, got status %d: %s", got, body)
+	}
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/calendar"
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestParseReminder tests reading what and when from a reminder
+func TestParseReminder(t *testing.T) {
+	// A Friday afternoon
+	now := time.Date(2026, time.October, 16, 14, 20, 0, 0, time.Local)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		input string
+		what  string
+		when  time.Time
+	}{
+		{"call mom tomorrow 6pm", "call mom", at(17, 18, 0)},
+		{"me to call mom tomorrow at 6:30 pm", "call mom", at(17, 18, 30)},
+		{"stand up in 45 minutes", "stand up", at(16, 15, 5)},
+		{"check the oven in 2h", "check the oven", at(16, 16, 20)},
+		{"pay rent on monday", "pay rent", at(19, 9, 0)},
+		{"team lunch friday noon", "team lunch", at(23, 12, 0)},
+		{"water the plants at 9am", "water the plants", at(17, 9, 0)},
+		{"take out the trash tonight", "take out the trash", at(16, 20, 0)},
+		{"buy 2 apples 2026-10-20 18:15", "buy 2 apples", at(20, 18, 15)},
+		{"call back tomorrow evening at 7", "call back", at(17, 19, 0)},
+	}
+	for _, tt := range tests {
+		what, when, err := calendar.ParseReminder(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseReminder(%q) error: %v", tt.input, err)
+			continue
+		}
+		if what != tt.what || !when.Equal(tt.when) {
+			t.Errorf("ParseReminder(%q) = %q at %v, want %q at %v", tt.input, what, when, tt.what, tt.when)
+		}
+	}
+
+	for _, input := range []string{"call mom", "tomorrow 6pm", "call mom today 8am"} {
+		if _, _, err := calendar.ParseReminder(input, now); err == nil {
+			t.Errorf("Expected ParseReminder(%q) to fail", input)
+		}
+	}
+}
+
+// TestRemindCommand tests setting, listing, delivering, and removing
+// reminders, and showing them on the agenda, without a desktop calendar
+func TestRemindCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/nonexistent")
+
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string, want nlp.CommandType) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if !cli.IsCommand(input) || err != nil || cmd.Type != want {
+			t.Fatalf("Expected %q to parse as %v, got %+v (%v)", input, want, cmd, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("remind list", nlp.CommandTypeRemind); !strings.Contains(result.Output, "No reminders") {
+		t.Errorf("Expected no reminders:\n%s", result.Output)
+	}
+	result := run(`remind "call mom tomorrow 6pm"`, nlp.CommandTypeRemind)
+	if result.IsError || !strings.Contains(result.Output, "remind you to call mom tomorrow at 18:00") {
+		t.Fatalf("Expected the reminder to be set: %+v", result)
+	}
+	if result := run("remind call mom", nlp.CommandTypeRemind); !result.IsError {
+		t.Errorf("Expected a reminder without a time to be refused: %+v", result)
+	}
+	if result := run("remind", nlp.CommandTypeRemind); !strings.Contains(result.Output, "call mom") {
+		t.Errorf("Expected the reminder listed:\n%s", result.Output)
+	}
+	if result := run("agenda tomorrow", nlp.CommandTypeAgenda); !strings.Contains(result.Output, "18:00") || !strings.Contains(result.Output, "⏰ call mom") {
+		t.Errorf("Expected the reminder on tomorrow's agenda:\n%s", result.Output)
+	}
+	if result := run("agenda someday", nlp.CommandTypeAgenda); !result.IsError {
+		t.Errorf("Expected an unknown day to be refused: %+v", result)
+	}
+
+	// The daemon delivers reminders once they are due
+	due, err := calendar.Due(time.Now().Add(48 * time.Hour))
+	if err != nil || len(due) != 1 || due[0].Text != "call mom" {
+		t.Fatalf("Expected the reminder to be due, got %+v (%v)", due, err)
+	}
+	if err := calendar.MarkNotified(due[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := calendar.Pending(); len(pending) != 0 {
+		t.Errorf("Expected no pending reminders, got %+v", pending)
+	}
+
+	run("remind water the plants in 10 minutes", nlp.CommandTypeRemind)
+	pending, _ := calendar.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("Expected one pending reminder, got %+v", pending)
+	}
+	if result := run("remind remove 99", nlp.CommandTypeRemind); !result.IsError {
+		t.Errorf("Expected removing an unknown reminder to fail: %+v", result)
+	}
+	if result := run(fmt.Sprintf("remind remove %d", pending[0].ID), nlp.CommandTypeRemind); result.IsError {
+		t.Errorf("Expected the reminder to be removed: %+v", result)
+	}
+}
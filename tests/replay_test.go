@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/replay"
+)
+
+// TestReplayScript tests rendering a recorded run as a replay script
+func TestReplayScript(t *testing.T) {
+	record := &replay.Record{
+		Source:     "agent",
+		Task:       "archive the logs",
+		FinishedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Environment: replay.Environment{
+			WorkDir: "/home/user/project",
+			Tools:   map[string]string{"tar": "/usr/bin/tar"},
+		},
+		Inputs: []replay.FileChecksum{{Path: "app.log", SHA256: "abc123"}},
+		Steps: []replay.Step{
+			{Command: "tar czf logs.tgz app.log", Executed: true, Success: true, OutputSHA256: replay.OutputChecksum("")},
+			{Command: "rm missing.log", Executed: true, Success: false, Error: "exit status 1"},
+			{Command: "echo skipped", Executed: false},
+		},
+	}
+
+	script := record.Script()
+	for _, want := range []string{
+		"#!/usr/bin/env bash",
+		"cd '/home/user/project'",
+		"for tool in 'tar'; do",
+		"abc123  app.log",
+		"tar czf logs.tgz app.log\n",
+		"rm missing.log || true\n",
+		"# echo skipped",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Expected script to contain %q, got:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "\necho skipped") {
+		t.Errorf("Expected the unexecuted step to be commented out")
+	}
+}
+
+// TestReplayChecksumInputs tests checksumming files referenced by commands
+func TestReplayChecksumInputs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	inputs := replay.ChecksumInputs([]string{"wc -l " + path + " missing.txt", "cat '" + path + "'"})
+	if len(inputs) != 1 {
+		t.Fatalf("Expected 1 input, got %d: %v", len(inputs), inputs)
+	}
+	// sha256 of "hello\n"
+	want := "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+	if inputs[0].SHA256 != want {
+		t.Errorf("Expected checksum %s, got %s", want, inputs[0].SHA256)
+	}
+}
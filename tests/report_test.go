@@ -0,0 +1,282 @@
+package tests
+
+import (
+	"bufio"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/email"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/report"
+	"github.com/agnath18K/lumo/pkg/speedtest"
+)
+
+// fakeSMTPServer accepts mail without authentication and records it
+type fakeSMTPServer struct {
+	addr string
+	mu   sync.Mutex
+	// messages are the recipients and data of each message received
+	messages []fakeMail
+}
+
+type fakeMail struct {
+	to   []string
+	data string
+}
+
+// startFakeSMTPServer starts an SMTP server on a local port
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{addr: listener.Addr().String()}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	var mail fakeMail
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			mail = fakeMail{}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			mail.to = append(mail.to, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			mail.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, mail)
+			s.mu.Unlock()
+			reply("250 Queued")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Not implemented")
+		}
+	}
+}
+
+// received returns the messages received so far
+func (s *fakeSMTPServer) received() []fakeMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeMail(nil), s.messages...)
+}
+
+// decodedBody returns the quoted-printable body of a message
+func decodedBody(t *testing.T, data string) string {
+	t.Helper()
+	_, body, _ := strings.Cut(data, "\r\n\r\n")
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(decoded)
+}
+
+// newReportConfig returns a configuration that emails reports through server
+func newReportConfig(t *testing.T, server *fakeSMTPServer) *config.Config {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.SMTPServer = server.addr
+	cfg.SMTPFrom = "lumo@example.com"
+	return cfg
+}
+
+// TestSendEmail tests sending an HTML email over SMTP
+func TestSendEmail(t *testing.T) {
+	server := startFakeSMTPServer(t)
+	cfg := newReportConfig(t, server)
+
+	html := "<p>Café " + strings.Repeat("x", 100) + "</p>"
+	if err := email.Send(cfg, []string{"a@example.com", "b@example.com"}, "Weekly report", html); err != nil {
+		t.Fatal(err)
+	}
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("Expected one message, got %d", len(messages))
+	}
+	if strings.Join(messages[0].to, ",") != "a@example.com,b@example.com" {
+		t.Errorf("Unexpected recipients: %v", messages[0].to)
+	}
+	for _, header := range []string{"From: lumo@example.com\r\n", "To: a@example.com, b@example.com\r\n", "Subject: Weekly report\r\n", "Content-Type: text/html; charset=UTF-8\r\n"} {
+		if !strings.Contains(messages[0].data, header) {
+			t.Errorf("Expected header %q in:\n%s", header, messages[0].data)
+		}
+	}
+	if body := strings.TrimSuffix(decodedBody(t, messages[0].data), "\r\n"); body != html {
+		t.Errorf("Expected body %q, got %q", html, body)
+	}
+
+	if err := email.Send(cfg, []string{"not an address"}, "x", "x"); err == nil {
+		t.Error("Expected an error for an invalid address")
+	}
+	cfg.SMTPServer = ""
+	if err := email.Send(cfg, []string{"a@example.com"}, "x", "x"); err == nil || !strings.Contains(err.Error(), "config:report smtp") {
+		t.Errorf("Expected an error about the missing SMTP server, got %v", err)
+	}
+}
+
+// TestSpeedTestHistory tests recording and summarizing speed tests
+func TestSpeedTestHistory(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	now := time.Now()
+
+	for _, result := range []speedtest.SpeedTestResult{
+		{DownloadSpeed: 500, UploadSpeed: 50, Latency: 30, Backend: "builtin", Timestamp: now.Add(-10 * 24 * time.Hour)},
+		{DownloadSpeed: 100, UploadSpeed: 10, Latency: 20, Backend: "builtin", Timestamp: now.Add(-2 * time.Hour)},
+		{DownloadSpeed: 300, UploadSpeed: 30, Latency: 40, Backend: "cloudflare", Timestamp: now.Add(-time.Hour)},
+	} {
+		if err := speedtest.RecordResult(&result); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := speedtest.LoadHistory(now.Add(-report.Period))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].DownloadSpeed != 100 || results[1].Backend != "cloudflare" {
+		t.Fatalf("Expected the two tests of the last week, got %+v", results)
+	}
+
+	summary := speedtest.Summarize(results)
+	want := speedtest.Summary{Tests: 2, AvgDownload: 200, MinDownload: 100, MaxDownload: 300, AvgUpload: 20, MinUpload: 10, MaxUpload: 30, AvgLatency: 30}
+	if summary != want {
+		t.Errorf("Expected %+v, got %+v", want, summary)
+	}
+}
+
+// TestEmailDueReports tests that reports are emailed once per period to their own recipients
+func TestEmailDueReports(t *testing.T) {
+	server := startFakeSMTPServer(t)
+	cfg := newReportConfig(t, server)
+	cfg.ReportRecipients = map[string][]string{"speedtest": {"me@example.com"}}
+	now := time.Now()
+	speedtest.RecordResult(&speedtest.SpeedTestResult{DownloadSpeed: 123.4, UploadSpeed: 5, Latency: 12, Backend: "builtin", Timestamp: now.Add(-time.Hour)})
+
+	emailed, err := report.EmailDue(cfg, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emailed) != 1 || emailed[0] != "speedtest" {
+		t.Fatalf("Expected the speedtest report to be emailed, got %v", emailed)
+	}
+	messages := server.received()
+	if len(messages) != 1 || messages[0].to[0] != "me@example.com" {
+		t.Fatalf("Unexpected messages: %+v", messages)
+	}
+	if !strings.Contains(messages[0].data, "Subject: Weekly speed test summary") ||
+		!strings.Contains(decodedBody(t, messages[0].data), "123.4 Mbps") {
+		t.Errorf("Expected the speed test summary, got:\n%s", messages[0].data)
+	}
+
+	// Not due again until a week has passed, even in a new daemon
+	if emailed, _ := report.EmailDue(cfg, now.Add(24*time.Hour)); len(emailed) != 0 {
+		t.Errorf("Expected no report to be due a day later, got %v", emailed)
+	}
+	if emailed, _ := report.EmailDue(cfg, now.Add(report.Period)); len(emailed) != 1 {
+		t.Errorf("Expected the report to be due a week later, got %v", emailed)
+	}
+}
+
+// TestReportConfig tests configuring emailed reports
+func TestReportConfig(t *testing.T) {
+	server := startFakeSMTPServer(t)
+	cfg := newReportConfig(t, server)
+	cfg.SMTPServer = ""
+	exec := executor.NewExecutor(cfg)
+
+	run := func(intent string) *executor.Result {
+		t.Helper()
+		result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeConfig, Intent: intent, RawInput: "config:" + intent})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	for _, intent := range []string{
+		"report smtp smtp.example.com lumo@example.com",
+		"report smtp smtp.example.com:587 not-an-address",
+		"report email weekly me@example.com",
+		"report email system me@example.com nobody",
+		"report send system",
+	} {
+		if result := run(intent); !result.IsError {
+			t.Errorf("Expected %q to fail, got %q", intent, result.Output)
+		}
+	}
+
+	if result := run("report smtp " + server.addr + " lumo@example.com"); result.IsError {
+		t.Fatalf("Failed to set the SMTP server: %s", result.Output)
+	}
+	if result := run("report email system Me@Example.com"); result.IsError {
+		t.Fatalf("Failed to set recipients: %s", result.Output)
+	}
+	if got := cfg.ReportRecipients["system"]; len(got) != 1 || got[0] != "Me@Example.com" {
+		t.Errorf("Expected the system report recipient to be saved, got %v", got)
+	}
+	if result := run("report show"); !strings.Contains(result.Output, "system: Me@Example.com (never sent)") {
+		t.Errorf("Expected the recipients in:\n%s", result.Output)
+	}
+
+	if result := run("report send system"); result.IsError {
+		t.Fatalf("Failed to send the system report: %s", result.Output)
+	}
+	if messages := server.received(); len(messages) != 1 || !strings.Contains(messages[0].data, "Subject: System report for") {
+		t.Errorf("Expected the system report to be emailed, got %+v", messages)
+	}
+
+	if result := run("report email system off"); result.IsError || len(cfg.ReportRecipients["system"]) != 0 {
+		t.Errorf("Expected the system report to stop, got %q", result.Output)
+	}
+}
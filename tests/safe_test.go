@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/hooks"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// TestSafeModeParsing tests that safe mode keeps to prefixed commands
+func TestSafeModeParsing(t *testing.T) {
+	opts, args, err := cli.Parse([]string{"--safe", "doctor"})
+	if err != nil || !opts.Safe || strings.Join(args, " ") != "doctor" {
+		t.Fatalf("Expected --safe before the command, got %+v %v (%v)", opts, args, err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Safe = true
+	parser := nlp.NewParser(cfg)
+
+	cmd, _ := parser.Parse("disk usage")
+	if cmd.Type != nlp.CommandTypeAI {
+		t.Errorf("Expected no local routing in safe mode, got type %v", cmd.Type)
+	}
+	cmd, _ = parser.Parse("check my internet speed")
+	if cmd.Type != nlp.CommandTypeAI {
+		t.Errorf("Expected no speed test guessing in safe mode, got type %v", cmd.Type)
+	}
+	cmd, _ = parser.Parse("config:provider")
+	if cmd.Type != nlp.CommandTypeConfig {
+		t.Errorf("Expected prefixed commands to work in safe mode, got type %v", cmd.Type)
+	}
+}
+
+// TestSafeModeDesktop tests that safe mode refuses desktop commands
+func TestSafeModeDesktop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	cfg := config.DefaultConfig()
+	cfg.Safe = true
+	exec := executor.NewExecutor(cfg)
+
+	for _, cmd := range []*nlp.Command{
+		{Type: nlp.CommandTypeDesktop, Intent: "lock screen", RawInput: "desktop:lock screen"},
+		{Type: nlp.CommandTypeIntegrate, Intent: "install", RawInput: "integrate install"},
+	} {
+		result, err := exec.Execute(cmd)
+		if err != nil || !result.IsError || !strings.Contains(result.Output, "off in safe mode") {
+			t.Errorf("Expected %s to be refused in safe mode, got %+v (%v)", cmd.RawInput, result, err)
+		}
+	}
+}
+
+// TestSafeModeHooks tests that disabled hooks do not run
+func TestSafeModeHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell hooks are not supported on Windows")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir, err := hooks.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	marker := filepath.Join(home, "ran")
+	hook := filepath.Join(dir, string(hooks.EventPreAgentRun))
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\ntouch "+marker+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks.SetDisabled(true)
+	defer hooks.SetDisabled(false)
+	if err := hooks.Run(context.Background(), hooks.EventPreAgentRun, nil); err != nil {
+		t.Errorf("Expected a disabled hook to be skipped, got %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Expected the hook not to run in safe mode")
+	}
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/daemon"
+)
+
+// TestSchedulerDefersNoisyTasks tests that noisy tasks wait for the user to go idle
+func TestSchedulerDefersNoisyTasks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.IdleThresholdMinutes = 5
+	cfg.IdleMaxDelayMinutes = 60
+
+	idleTime := time.Duration(0)
+	scheduler := daemon.NewScheduler(cfg, func(ctx context.Context) (time.Duration, error) {
+		return idleTime, nil
+	})
+
+	runs := 0
+	scheduler.AddTask(&daemon.Task{
+		Name:     "speedtest",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			runs++
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+
+	// Due, but the user is active
+	scheduler.RunDue(ctx, start.Add(time.Hour))
+	if runs != 0 {
+		t.Fatalf("Expected noisy task to be deferred while user is active, ran %d times", runs)
+	}
+
+	// The user has gone idle
+	idleTime = 10 * time.Minute
+	scheduler.RunDue(ctx, start.Add(time.Hour+time.Minute))
+	if runs != 1 {
+		t.Fatalf("Expected noisy task to run once the user is idle, ran %d times", runs)
+	}
+}
+
+// TestSchedulerMaxDelay tests that noisy tasks run once the max delay has passed
+func TestSchedulerMaxDelay(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.IdleThresholdMinutes = 5
+	cfg.IdleMaxDelayMinutes = 30
+
+	scheduler := daemon.NewScheduler(cfg, func(ctx context.Context) (time.Duration, error) {
+		return 0, nil // The user never goes idle
+	})
+
+	runs := 0
+	scheduler.AddTask(&daemon.Task{
+		Name:     "speedtest",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			runs++
+			return nil
+		},
+	})
+
+	start := time.Now()
+	scheduler.RunDue(context.Background(), start.Add(time.Hour+31*time.Minute))
+	if runs != 1 {
+		t.Errorf("Expected noisy task to run after the max delay, ran %d times", runs)
+	}
+}
+
+// TestSchedulerOnBattery tests that tasks are skipped or slowed down on
+// battery, and that noisy tasks still wait for the user to go idle
+func TestSchedulerOnBattery(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.PowerMode = "battery"
+	cfg.IdleThresholdMinutes = 5
+	cfg.IdleMaxDelayMinutes = 60
+
+	idleTime := time.Duration(0)
+	scheduler := daemon.NewScheduler(cfg, func(ctx context.Context) (time.Duration, error) {
+		return idleTime, nil
+	})
+
+	runs := map[string]int{}
+	task := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			runs[name]++
+			return nil
+		}
+	}
+	scheduler.AddTask(&daemon.Task{
+		Name:          "speedtest",
+		Interval:      time.Hour,
+		Run:           task("speedtest"),
+		SkipOnBattery: true,
+	})
+	scheduler.AddTask(&daemon.Task{
+		Name:     "indexing",
+		Interval: time.Hour,
+		Run:      task("indexing"),
+	})
+	scheduler.AddTask(&daemon.Task{
+		Name:            "health",
+		Interval:        time.Hour,
+		Run:             task("health"),
+		BatteryInterval: 4 * time.Hour,
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+
+	// Due while the user is active
+	scheduler.RunDue(ctx, start.Add(time.Hour))
+	if runs["speedtest"] != 0 {
+		t.Errorf("Expected the speed test to be skipped on battery, ran %d times", runs["speedtest"])
+	}
+	if runs["indexing"] != 0 {
+		t.Errorf("Expected the noisy task to be deferred while the user is active, ran %d times", runs["indexing"])
+	}
+	if runs["health"] != 1 {
+		t.Errorf("Expected the health check to run, ran %d times", runs["health"])
+	}
+
+	// The user goes idle; the skipped speed test is not due again until
+	// its next interval, and the health check waits its battery interval
+	idleTime = 10 * time.Minute
+	scheduler.RunDue(ctx, start.Add(time.Hour+time.Minute))
+	scheduler.RunDue(ctx, start.Add(3*time.Hour))
+	if runs["speedtest"] != 0 {
+		t.Errorf("Expected the speed test to stay skipped on battery, ran %d times", runs["speedtest"])
+	}
+	if runs["indexing"] != 2 {
+		t.Errorf("Expected the noisy task to run once the user is idle, ran %d times", runs["indexing"])
+	}
+	if runs["health"] != 1 {
+		t.Errorf("Expected the health check to wait its battery interval, ran %d times", runs["health"])
+	}
+	scheduler.RunDue(ctx, start.Add(5*time.Hour))
+	if runs["health"] != 2 {
+		t.Errorf("Expected the health check after its battery interval, ran %d times", runs["health"])
+	}
+
+	// Back on AC, the speed test runs again
+	cfg.PowerMode = "ac"
+	scheduler.RunDue(ctx, start.Add(6*time.Hour))
+	if runs["speedtest"] != 1 {
+		t.Errorf("Expected the speed test to run on AC, ran %d times", runs["speedtest"])
+	}
+}
+
+// TestNightLightWindow tests night light schedules, including ones that cross midnight
+func TestNightLightWindow(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, _ := time.Parse("15:04", clock)
+		return parsed
+	}
+
+	tests := []struct {
+		now, start, end string
+		want            bool
+	}{
+		{"22:00", "21:00", "06:30", true},
+		{"03:00", "21:00", "06:30", true},
+		{"06:30", "21:00", "06:30", false},
+		{"12:00", "21:00", "06:30", false},
+		{"14:00", "13:00", "15:00", true},
+		{"16:00", "13:00", "15:00", false},
+	}
+
+	for _, tc := range tests {
+		got, err := daemon.InNightLightWindow(at(tc.now), tc.start, tc.end)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("InNightLightWindow(%s, %s-%s) = %v, want %v", tc.now, tc.start, tc.end, got, tc.want)
+		}
+	}
+
+	if _, err := daemon.InNightLightWindow(time.Now(), "9pm", "06:30"); err == nil {
+		t.Error("Expected an error for an invalid time of day")
+	}
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/clipboard"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/script"
+)
+
+// newScriptRunner returns a runner using the mock provider and a fake
+// clipboard, and the buffer its scripts print to
+func newScriptRunner(t *testing.T, cfg *config.Config) (*script.Runner, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	cfg.AIProvider = "mock"
+	cfg.MockFixtures = writeMockFixtures(t, `{"rules": [{"contains": "capital", "response": "Paris"}], "default": "no idea"}`)
+
+	runner := script.NewRunner(cfg, executor.NewExecutor(cfg))
+	var out bytes.Buffer
+	runner.SetOutput(&out)
+	runner.SetClipboard(clipboard.NewClipboardWithProvider(&MockClipboardProvider{}))
+	runner.SetConfirm(func(command, reason string) bool { return false })
+	return runner, &out
+}
+
+// runScriptSource writes src to a script file and runs it
+func runScriptSource(t *testing.T, runner *script.Runner, src string, args ...string) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.star")
+	if err := os.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return runner.Run(context.Background(), path, args)
+}
+
+// TestScriptPrimitives tests ask, shell, clipboard, and args from a script
+func TestScriptPrimitives(t *testing.T) {
+	runner, out := newScriptRunner(t, config.DefaultConfig())
+
+	err := runScriptSource(t, runner, `
+print(ask("What is the capital of France?"))
+r = shell("echo hello; exit 3", check=False)
+print(r.output.strip(), r.code)
+clipboard.set("one")
+clipboard.append("two")
+print(clipboard.get())
+print(args)
+print(json.encode({"n": len(args)}))
+`, "a", "b")
+	if err != nil {
+		t.Fatalf("Expected the script to run, got %v", err)
+	}
+
+	want := "Paris\nhello 3\none\ntwo\n(\"a\", \"b\")\n{\"n\":2}\n"
+	if out.String() != want {
+		t.Errorf("Expected output %q, got %q", want, out.String())
+	}
+}
+
+// TestScriptShellSafety tests that shell commands follow the agent's safety settings
+func TestScriptShellSafety(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AgentDenyList = []string{"echo denied"}
+	runner, _ := newScriptRunner(t, cfg)
+	dir := t.TempDir()
+	target := filepath.Join(dir, "keep.txt")
+	if err := os.WriteFile(target, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runScriptSource(t, runner, `shell("echo denied")`)
+	if err == nil || !strings.Contains(err.Error(), "deny list") {
+		t.Errorf("Expected the deny list to block the command, got %v", err)
+	}
+
+	// Destructive commands need confirmation
+	err = runScriptSource(t, runner, `shell("rm `+target+`")`)
+	if err == nil || !strings.Contains(err.Error(), "not confirmed") {
+		t.Errorf("Expected rm to need confirmation, got %v", err)
+	}
+	if _, statErr := os.Stat(target); statErr != nil {
+		t.Errorf("Expected the file to be kept, got %v", statErr)
+	}
+
+	// Shells and wrappers do not get commands past either check
+	err = runScriptSource(t, runner, `shell("bash -c 'echo denied'")`)
+	if err == nil || !strings.Contains(err.Error(), "deny list") {
+		t.Errorf("Expected the deny list to block the command inside bash -c, got %v", err)
+	}
+	for _, command := range []string{"bash -c 'rm " + target + "'", "nice rm " + target, "$SHELL -c 'rm " + target + "'"} {
+		err = runScriptSource(t, runner, `shell("`+command+`")`)
+		if err == nil || !strings.Contains(err.Error(), "not confirmed") {
+			t.Errorf("Expected %q to need confirmation, got %v", command, err)
+		}
+	}
+	if _, statErr := os.Stat(target); statErr != nil {
+		t.Errorf("Expected the file to be kept, got %v", statErr)
+	}
+
+	runner.SetYes(true)
+	if err := runScriptSource(t, runner, `shell("rm `+target+`")`); err != nil {
+		t.Errorf("Expected --yes to run rm, got %v", err)
+	}
+	if _, statErr := os.Stat(target); !os.IsNotExist(statErr) {
+		t.Errorf("Expected the file to be removed, got %v", statErr)
+	}
+
+	err = runScriptSource(t, runner, `shell("false")`)
+	if err == nil || !strings.Contains(err.Error(), "exited with code 1") {
+		t.Errorf("Expected a failing command to stop the script, got %v", err)
+	}
+}
+
+// TestScriptSandbox tests that scripts cannot load files and can be stopped
+func TestScriptSandbox(t *testing.T) {
+	runner, _ := newScriptRunner(t, config.DefaultConfig())
+
+	err := runScriptSource(t, runner, `load("other.star", "x")`)
+	if err == nil || !strings.Contains(err.Error(), "cannot load") {
+		t.Errorf("Expected load to fail, got %v", err)
+	}
+
+	for _, name := range []string{"open", "read_file", "http"} {
+		if err := runScriptSource(t, runner, name+"()"); err == nil {
+			t.Errorf("Expected %s to be undefined", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "loop.star")
+	if err := os.WriteFile(path, []byte("while True:\n    pass\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = runner.Run(ctx, path, nil)
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Errorf("Expected the loop to be interrupted, got %v", err)
+	}
+}
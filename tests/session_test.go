@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// TestSessionCommandParsing tests parsing of suspend and scheduled shutdown commands
+func TestSessionCommandParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	testCases := []struct {
+		input          string
+		expectedAction string
+		expectedTarget string
+		expectedType   string
+	}{
+		{"suspend", "suspend", "", ""},
+		{"hibernate", "hibernate", "", ""},
+		{"shutdown in 30m", "schedule-shutdown", "30m", "poweroff"},
+		{"shutdown system in 45 minutes", "schedule-shutdown", "45m", "poweroff"},
+		{"shutdown at 22:30", "schedule-shutdown", "22:30", "poweroff"},
+		{"restart in 1h", "schedule-shutdown", "1h", "reboot"},
+		{"cancel shutdown", "cancel-shutdown", "", ""},
+		{"shutdown status", "shutdown-status", "", ""},
+		{"shutdown system", "shutdown", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			cmd, err := processor.Process(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to process %q: %v", tc.input, err)
+			}
+			if cmd.Type != core.CommandTypeSystem || cmd.Action != tc.expectedAction {
+				t.Fatalf("Expected system:%s, got %s:%s", tc.expectedAction, cmd.Type, cmd.Action)
+			}
+			if cmd.Target != tc.expectedTarget {
+				t.Errorf("Expected target %q, got %q", tc.expectedTarget, cmd.Target)
+			}
+			if shutdownType, _ := cmd.Arguments["type"].(string); shutdownType != tc.expectedType {
+				t.Errorf("Expected shutdown type %q, got %q", tc.expectedType, shutdownType)
+			}
+			if !assistant.RequiresConfirmation(cmd) && tc.expectedAction != "cancel-shutdown" && tc.expectedAction != "shutdown-status" {
+				t.Errorf("Expected %s to require confirmation", tc.expectedAction)
+			}
+		})
+	}
+}
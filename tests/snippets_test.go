@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/clipboard"
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/snippets"
+)
+
+// TestSnippetExpander tests spotting abbreviations in what is typed
+func TestSnippetExpander(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "sig" {
+			return "Best regards,\nAda", true
+		}
+		return "", false
+	}
+	expander := snippets.NewExpander(";", lookup)
+	typeText := func(text string) (snippets.Expansion, bool) {
+		var expansion snippets.Expansion
+		expanded := false
+		for _, r := range text {
+			if e, ok := expander.Type(r); ok {
+				expansion, expanded = e, true
+			}
+		}
+		return expansion, expanded
+	}
+
+	expansion, ok := typeText("thanks ;sig ")
+	if !ok || expansion.Name != "sig" || expansion.Text != "Best regards,\nAda" || expansion.Erase != 5 {
+		t.Errorf("Expected ;sig to expand, erasing 5 characters, got %+v (%v)", expansion, ok)
+	}
+	for _, text := range []string{"sig ", ";signature ", ";nope ", "a;sig "} {
+		if expansion, ok := typeText(text); ok {
+			t.Errorf("Expected %q not to expand, got %+v", text, expansion)
+		}
+	}
+	if _, ok := typeText(";sog\b\big\t"); !ok {
+		t.Error("Expected a corrected abbreviation to expand")
+	}
+	// A cursor key in the middle ends the word
+	if _, ok := typeText(";s\x00ig "); ok {
+		t.Error("Expected a cursor key to end the word")
+	}
+}
+
+// TestSnipCommand tests saving, pasting, and removing snippets
+func TestSnipCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	mock := &MockClipboardProvider{}
+	exec.SetClipboard(clipboard.NewClipboardWithProvider(mock))
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if !cli.IsCommand(input) || err != nil || cmd.Type != nlp.CommandTypeSnip {
+			t.Fatalf("Expected %q to be a snip command, got %+v (%v)", input, cmd, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("snip"); !strings.Contains(result.Output, "No snippets yet") {
+		t.Errorf("Expected no snippets:\n%s", result.Output)
+	}
+	if result := run(`snip add sig "Best regards,\nAda  Lovelace"`); result.IsError || !strings.Contains(result.Output, "Saved snippet sig") {
+		t.Fatalf("Expected the snippet to be saved: %+v", result)
+	}
+	if result := run("snip paste sig"); result.IsError || result.Output != "Best regards,\nAda  Lovelace" {
+		t.Errorf("Expected the snippet's text, got %+v", result)
+	}
+	if mock.content != "Best regards,\nAda  Lovelace" {
+		t.Errorf("Expected the snippet on the clipboard, got %q", mock.content)
+	}
+	if result := run("snip add sig Cheers"); !strings.Contains(result.Output, "Updated snippet sig") {
+		t.Errorf("Expected the snippet to be updated: %+v", result)
+	}
+	if result := run("snip list"); !strings.Contains(result.Output, "sig") || !strings.Contains(result.Output, "Cheers") {
+		t.Errorf("Expected the snippet listed:\n%s", result.Output)
+	}
+	if result := run("snip add ../x text"); !result.IsError {
+		t.Errorf("Expected an invalid name to be refused: %+v", result)
+	}
+	if result := run("snip remove sig"); result.IsError {
+		t.Errorf("Expected the snippet to be removed: %+v", result)
+	}
+	if result := run("snip show sig"); !result.IsError || !strings.Contains(result.Output, "no snippet named") {
+		t.Errorf("Expected a removed snippet to be gone: %+v", result)
+	}
+
+	// Expansion is off until the user turns it on
+	if result := run("snip expand"); !strings.Contains(result.Output, "expansion is off") {
+		t.Errorf("Expected expansion to be off:\n%s", result.Output)
+	}
+	run("snip expand on")
+	run("snip expand prefix //")
+	if saved, err := config.Load(); err != nil || !saved.EnableSnippetExpansion || saved.SnippetPrefix != "//" {
+		t.Errorf("Expected expansion on with the prefix //, got %+v (%v)", saved, err)
+	}
+	if result := run("snip expand prefix a b"); !result.IsError {
+		t.Errorf("Expected a prefix with a space to be refused: %+v", result)
+	}
+}
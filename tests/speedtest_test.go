@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/speedtest"
+)
+
+// TestSpeedTestBackends tests selecting speed test backends
+func TestSpeedTestBackends(t *testing.T) {
+	names := make([]string, 0)
+	for _, backend := range speedtest.Backends() {
+		names = append(names, backend.Name)
+	}
+	if strings.Join(names, ",") != "builtin,cloudflare,ookla" {
+		t.Errorf("Unexpected backends: %v", names)
+	}
+
+	tester, err := speedtest.New("")
+	if err != nil {
+		t.Fatalf("Expected the default backend to be available, got: %v", err)
+	}
+	if tester.Name() != speedtest.BackendBuiltin {
+		t.Errorf("Expected the default backend to be %s, got %s", speedtest.BackendBuiltin, tester.Name())
+	}
+
+	if _, err := speedtest.New("carrier-pigeon"); err == nil {
+		t.Errorf("Expected an error for an unknown backend")
+	}
+}
+
+// TestSpeedTestFormatResult tests that formatted results name their backend
+func TestSpeedTestFormatResult(t *testing.T) {
+	tester, _ := speedtest.New(speedtest.BackendBuiltin)
+	result, err := tester.RunDownloadTest(context.Background())
+	if err != nil {
+		t.Skipf("Speed test unavailable: %v", err)
+	}
+	if result.Backend != speedtest.BackendBuiltin {
+		t.Errorf("Expected result backend %s, got %s", speedtest.BackendBuiltin, result.Backend)
+	}
+	if output := speedtest.FormatResult(result); !strings.Contains(output, "Backend:") {
+		t.Errorf("Expected formatted result to include the backend, got:\n%s", output)
+	}
+}
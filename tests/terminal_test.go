@@ -3,6 +3,7 @@ package tests
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -73,12 +74,9 @@ func TestTerminalLogCommand(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Create a logs directory
-	logsDir := filepath.Join(tempDir, "logs")
-	err = os.Mkdir(logsDir, 0755)
-	if err != nil {
-		t.Fatalf("Failed to create logs directory: %v", err)
-	}
+	// Logs are kept in the state directory
+	t.Setenv("XDG_STATE_HOME", tempDir)
+	logsDir := filepath.Join(tempDir, "lumo", "logs")
 
 	// Create a default config for testing
 	cfg := &config.Config{
@@ -87,11 +85,6 @@ func TestTerminalLogCommand(t *testing.T) {
 		EnableLogging:            true,
 	}
 
-	// Change the working directory to the logs directory for this test
-	originalDir, _ := os.Getwd()
-	os.Chdir(tempDir)
-	defer os.Chdir(originalDir)
-
 	// Create a terminal instance
 	term := terminal.NewTerminal(cfg)
 
@@ -191,3 +184,75 @@ func TestTerminalDisplayFormatting(t *testing.T) {
 		})
 	}
 }
+
+// TestTerminalOutputFile tests writing command output to a file with --out and --append
+func TestTerminalOutputFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.WriteFile(out, []byte("old\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	term := terminal.NewTerminal(&config.Config{})
+	term.SetOutputFile(out, false)
+	term.Display(&executor.Result{Output: "\033[1;32mCPU: OK\033[0m"})
+	term.Display(&executor.Result{Output: "Something failed", IsError: true})
+	term.Display(&executor.Result{Output: "Memory: OK\n"})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if string(data) != "CPU: OK\nMemory: OK\n" {
+		t.Errorf("Expected the file to be replaced, then appended to without colors or errors, got %q", data)
+	}
+
+	appended := terminal.NewTerminal(&config.Config{})
+	appended.SetOutputFile(out, true)
+	appended.Display(&executor.Result{Output: "Disk: OK"})
+	if data, _ := os.ReadFile(out); !strings.HasPrefix(string(data), "CPU: OK") || !strings.HasSuffix(string(data), "Disk: OK\n") {
+		t.Errorf("Expected --append to keep the earlier output, got %q", data)
+	}
+}
+
+// TestPromptSource tests reading a question from stdin or the editor
+func TestPromptSource(t *testing.T) {
+	testCases := []struct {
+		intent, source, flags, rest string
+	}{
+		{"-", terminal.PromptFromStdin, "", ""},
+		{"--persona=tutor -", terminal.PromptFromStdin, "--persona=tutor", ""},
+		{"--edit start of a draft", terminal.PromptFromEditor, "", "start of a draft"},
+		{"what does - mean", "", "", "what does - mean"},
+		{"--persona=tutor how do pipes work", "", "", "--persona=tutor how do pipes work"},
+	}
+	for _, tc := range testCases {
+		source, flags, rest := terminal.ParsePromptSource(tc.intent)
+		if source != tc.source || flags != tc.flags || rest != tc.rest {
+			t.Errorf("ParsePromptSource(%q) = %q, %q, %q; expected %q, %q, %q",
+				tc.intent, source, flags, rest, tc.source, tc.flags, tc.rest)
+		}
+	}
+
+	prompt, err := terminal.ReadPrompt(strings.NewReader("Why does this fail?\n\n  exit status 1\n"))
+	if err != nil || prompt != "Why does this fail?\n\n  exit status 1" {
+		t.Errorf("Expected the question with its line breaks, got %q (%v)", prompt, err)
+	}
+	if _, err := terminal.ReadPrompt(strings.NewReader(" \n")); err != terminal.ErrEmptyPrompt {
+		t.Errorf("Expected an empty question to be refused, got %v", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	// The editor appends to the draft; the instructions below the marker are dropped
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	script := "#!/bin/sh\n{ printf 'second line\\n'; cat \"$1\"; } > \"$1.new\" && mv \"$1.new\" \"$1\"\n"
+	if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write editor: %v", err)
+	}
+	t.Setenv("VISUAL", editor)
+	prompt, err = terminal.ComposePrompt("first line")
+	if err != nil || prompt != "second line\nfirst line" {
+		t.Errorf("Expected the edited question without instructions, got %q (%v)", prompt, err)
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/utils"
+)
+
+// TestFormatRelative tests relative times in the local style
+func TestFormatRelative(t *testing.T) {
+	utils.SetTimeStyle(utils.TimeStyleLocal)
+
+	now := time.Now()
+	for _, tc := range []struct {
+		time time.Time
+		want string
+	}{
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-3*time.Minute - time.Second), "3m ago"},
+		{now.Add(-5*time.Hour - time.Second), "5h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+		{now.Add(2*time.Hour + time.Minute), "in 2h"},
+		{now.Add(-30 * 24 * time.Hour), now.Add(-30 * 24 * time.Hour).Format("2006-01-02")},
+	} {
+		if got := utils.FormatRelative(tc.time); got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+
+	when := utils.FormatWhen(now.Add(-3*time.Minute - time.Second))
+	if !strings.HasPrefix(when, "3m ago (") || !strings.Contains(when, now.Format("2006-01-02")) {
+		t.Errorf("Expected a relative time with a timestamp, got %q", when)
+	}
+}
+
+// TestFormatISO tests that the ISO style shows ISO-8601 timestamps everywhere
+func TestFormatISO(t *testing.T) {
+	utils.SetTimeStyle(utils.TimeStyleISO)
+	defer utils.SetTimeStyle(utils.TimeStyleLocal)
+
+	moment := time.Date(2024, 5, 1, 12, 3, 5, 0, time.UTC)
+	want := moment.Local().Format(time.RFC3339)
+	for name, got := range map[string]string{
+		"timestamp": utils.FormatTimestamp(moment),
+		"relative":  utils.FormatRelative(moment),
+		"when":      utils.FormatWhen(moment),
+	} {
+		if got != want {
+			t.Errorf("Expected the %s to be %s, got %s", name, want, got)
+		}
+		if _, err := time.Parse(time.RFC3339, got); err != nil {
+			t.Errorf("Expected the %s to parse as ISO-8601: %v", name, err)
+		}
+	}
+
+	// Unknown styles fall back to local times
+	utils.SetTimeStyle("fancy")
+	if style := utils.TimeStyle(); style != utils.TimeStyleLocal {
+		t.Errorf("Expected the local style, got %s", style)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/timetrack"
+)
+
+// TestTimeTracker tests that the tracker records the application in focus,
+// but not while the user is idle or for excluded applications
+func TestTimeTracker(t *testing.T) {
+	app, idle := "org.gnome.Nautilus.desktop", time.Duration(0)
+	var activeErr error
+	tracker := timetrack.NewTracker(
+		func(ctx context.Context) (string, error) { return app, activeErr },
+		func(ctx context.Context) (time.Duration, error) { return idle, nil },
+		5*time.Minute, []string{"KeePassXC"})
+	var recorded []timetrack.Sample
+	tracker.SetRecorder(func(sample timetrack.Sample) error {
+		recorded = append(recorded, sample)
+		return nil
+	})
+
+	ctx := context.Background()
+	now := time.Now()
+	sample := func() {
+		t.Helper()
+		if err := tracker.Sample(ctx, now); err != nil {
+			t.Fatalf("Sample failed: %v", err)
+		}
+	}
+
+	sample()
+	if len(recorded) != 1 || recorded[0].App != "Nautilus" || recorded[0].Seconds != 30 {
+		t.Fatalf("Expected one 30s sample of Nautilus, got %+v", recorded)
+	}
+	idle = 10 * time.Minute
+	sample()
+	idle = 0
+	app = "keepassxc"
+	sample()
+	app = ""
+	sample()
+	if len(recorded) != 1 {
+		t.Errorf("Expected idle time, excluded apps, and no app to be left out, got %+v", recorded)
+	}
+	activeErr = errors.New("no desktop")
+	if err := tracker.Sample(ctx, now); err == nil {
+		t.Error("Expected a failure to get the active window to be reported")
+	}
+}
+
+// TestTimeTrackingSummary tests the daily summary, its focus statistics,
+// and the today command
+func TestTimeTrackingSummary(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	at := day
+	add := func(app string, minutes int) {
+		for i := 0; i < minutes*2; i++ {
+			if err := timetrack.Record(timetrack.Sample{Time: at, App: app, Seconds: 30}); err != nil {
+				t.Fatal(err)
+			}
+			at = at.Add(30 * time.Second)
+		}
+	}
+	add("code", 40)
+	add("firefox", 10)
+	add("code", 20)
+	at = at.Add(time.Hour) // away from the desk
+	add("code", 10)
+	add("slack", 5)
+	// The next day is not part of this one
+	at = day.AddDate(0, 0, 1)
+	add("code", 30)
+
+	samples, err := timetrack.LoadDay(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := timetrack.Summarize(samples, nil)
+	if summary.Total != 85*time.Minute || len(summary.Apps) != 3 || summary.Apps[0].App != "code" || summary.Apps[0].Duration != 70*time.Minute {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+	if summary.Longest != 40*time.Minute || summary.LongestApp != "code" || summary.DeepFocusSessions != 1 || summary.Switches != 3 {
+		t.Errorf("Unexpected focus statistics: %+v", summary)
+	}
+	if excluded := timetrack.Summarize(samples, []string{"Slack"}); excluded.Total != 80*time.Minute || len(excluded.Apps) != 2 {
+		t.Errorf("Expected excluded apps to be left out, got %+v", excluded)
+	}
+
+	var csv strings.Builder
+	if err := timetrack.WriteCSV(&csv, day, summary); err != nil {
+		t.Fatal(err)
+	}
+	want := "date,app,minutes,percent\n2026-03-02,code,70.0,82.4\n2026-03-02,firefox,10.0,11.8\n2026-03-02,slack,5.0,5.9\n"
+	if csv.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", csv.String(), want)
+	}
+
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if !cli.IsCommand(input) || err != nil || cmd.Type != nlp.CommandTypeToday {
+			t.Fatalf("Expected %q to be a today command, got %+v (%v)", input, cmd, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	result := run("today --date 2026-03-02")
+	for _, want := range []string{"Monday, March 2", "1h 25m active between 09:00 and", "Longest focus: 40m in code", "1 deep focus session", "code", "82%", "Tracking is off"} {
+		if !strings.Contains(result.Output, want) {
+			t.Errorf("Expected %q in:\n%s", want, result.Output)
+		}
+	}
+	if result := run("today --date=2026-03-02 --csv"); result.Output != want {
+		t.Errorf("Unexpected CSV export:\n%s", result.Output)
+	}
+	if result := run("today"); !strings.Contains(result.Output, "Time tracking is off") {
+		t.Errorf("Expected a day without samples to say tracking is off:\n%s", result.Output)
+	}
+	if result := run("today --date march"); !result.IsError || !strings.Contains(result.Output, "invalid date") {
+		t.Errorf("Expected an invalid date to be refused: %+v", result)
+	}
+	if cmd, _ := parser.Parse("today is a good day to learn go"); cmd.Type == nlp.CommandTypeToday {
+		t.Error("Expected a question starting with today not to be a today command")
+	}
+
+	// Excluding an application leaves it out of the summary right away
+	for _, input := range []string{"config:tracking enable", "config:tracking exclude slack"} {
+		cmd, _ := parser.Parse(input)
+		if result, err := exec.Execute(cmd); err != nil || result.IsError {
+			t.Fatalf("%s failed: %+v (%v)", input, result, err)
+		}
+	}
+	if saved, err := config.Load(); err != nil || !saved.EnableTimeTracking || len(saved.TimeTrackingExclude) != 1 {
+		t.Errorf("Expected the tracking settings to be saved, got %+v (%v)", saved, err)
+	}
+	if result := run("today --date 2026-03-02"); strings.Contains(result.Output, "slack") || strings.Contains(result.Output, "Tracking is off") {
+		t.Errorf("Expected slack to be left out:\n%s", result.Output)
+	}
+}
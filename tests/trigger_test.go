@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/cli"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/trigger"
+)
+
+// TestTriggerWatcher tests that triggers run for new and changed files once
+// they settle, and not for files that were already there
+func TestTriggerWatcher(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("old.pdf", "old")
+
+	if err := trigger.Add(&trigger.Trigger{Watch: dir, Glob: "*.pdf", Run: "ask:summarize {file}", DebounceSeconds: 10}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	var ran []string
+	watcher := trigger.NewWatcher(func(ctx context.Context, tr *trigger.Trigger, path string) {
+		ran = append(ran, tr.Command(path))
+	})
+	ctx := context.Background()
+	start := time.Now()
+	scan := func(after time.Duration) []string {
+		ran = nil
+		watcher.Scan(ctx, start.Add(after))
+		return ran
+	}
+
+	// The first scan only notes what is there
+	if got := scan(0); len(got) != 0 {
+		t.Errorf("Expected existing files to be left alone, ran %v", got)
+	}
+
+	write("new.pdf", "new")
+	write("notes.txt", "not a pdf")
+	if got := scan(2 * time.Second); len(got) != 0 {
+		t.Errorf("Expected a new file to wait for the debounce, ran %v", got)
+	}
+	// Writing to it again restarts the wait
+	write("new.pdf", "new, and longer")
+	if got := scan(8 * time.Second); len(got) != 0 {
+		t.Errorf("Expected a changing file to wait, ran %v", got)
+	}
+	want := "ask:summarize " + filepath.Join(dir, "new.pdf")
+	if got := scan(18 * time.Second); len(got) != 1 || got[0] != want {
+		t.Errorf("Expected %q to run, ran %v", want, got)
+	}
+	if got := scan(30 * time.Second); len(got) != 0 {
+		t.Errorf("Expected a trigger to run once per change, ran %v", got)
+	}
+
+	// Files added while a trigger is disabled do not run it
+	if err := trigger.SetEnabled(filepath.Base(dir), false); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	scan(32 * time.Second)
+	write("while-off.pdf", "missed")
+	scan(34 * time.Second)
+	if err := trigger.SetEnabled(filepath.Base(dir), true); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	scan(36 * time.Second)
+	if got := scan(60 * time.Second); len(got) != 0 {
+		t.Errorf("Expected files added while disabled to be left alone, ran %v", got)
+	}
+}
+
+// TestTriggerCommands tests parsing and running trigger commands
+func TestTriggerCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	dir := filepath.Join(t.TempDir(), "Downloads")
+	os.Mkdir(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("pdf"), 0644)
+	os.WriteFile(filepath.Join(dir, "photo.png"), []byte("png"), 0644)
+
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if !cli.IsCommand(input) || err != nil || cmd.Type != nlp.CommandTypeTrigger {
+			t.Fatalf("Expected %q to be a trigger command, got %+v (%v)", input, cmd, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	// The command after --run need not be quoted, as when the shell has
+	// removed the quotes
+	add := "trigger add --watch " + dir + ` --glob "*.pdf" --run ask:summarize {file} --debounce 30s`
+	result := run(add + " --dry-run")
+	if result.IsError || !strings.Contains(result.Output, "ask:summarize "+filepath.Join(dir, "report.pdf")) ||
+		strings.Contains(result.Output, "photo.png") || !strings.Contains(result.Output, "nothing was saved") {
+		t.Errorf("Unexpected dry run:\n%s", result.Output)
+	}
+	if triggers, _ := trigger.Load(); len(triggers) != 0 {
+		t.Errorf("Expected a dry run to save nothing, got %d triggers", len(triggers))
+	}
+
+	if result := run(add); result.IsError || !strings.Contains(result.Output, "Added trigger downloads") {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	saved, err := trigger.Get("downloads")
+	if err != nil || saved.Run != "ask:summarize {file}" || saved.Glob != "*.pdf" || saved.Debounce() != 30*time.Second || !saved.Enabled {
+		t.Errorf("Unexpected trigger: %+v (%v)", saved, err)
+	}
+	if result := run(add); !strings.Contains(result.Output, "Added trigger downloads-2") {
+		t.Errorf("Expected a second trigger to get its own name, got %q", result.Output)
+	}
+
+	if result := run("trigger disable downloads-2"); result.IsError {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	result = run("trigger list")
+	if !strings.Contains(result.Output, "downloads (enabled)") || !strings.Contains(result.Output, "downloads-2 (disabled)") {
+		t.Errorf("Unexpected list:\n%s", result.Output)
+	}
+	if result := run("trigger test downloads"); !strings.Contains(result.Output, "Nothing was run") {
+		t.Errorf("Unexpected preview:\n%s", result.Output)
+	}
+	if result := run("trigger remove downloads-2"); result.IsError {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	for input, want := range map[string]string{
+		"trigger add --run ask:hi":                                 "a directory to watch is required",
+		"trigger add --watch " + dir:                               "a command to run is required",
+		"trigger add --watch /no/such/dir --run ask:hi":            "is not a directory",
+		"trigger add --watch " + dir + " --glob [ --run ask:hi":    "invalid glob",
+		"trigger add --watch " + dir + " --debounce 0 --run ask:x": "invalid debounce",
+		"trigger add --watch " + dir + " --every 5m --run ask:hi":  "unknown option: --every",
+		"trigger remove downloads-2":                               "no trigger named",
+		"trigger enable":                                           "Missing trigger name",
+		"trigger watch":                                            "Unknown trigger command",
+	} {
+		if result := run(input); !result.IsError || !strings.Contains(result.Output, want) {
+			t.Errorf("%q: expected an error containing %q, got %+v", input, want, result)
+		}
+	}
+}
+
+// TestTriggerFileWithSpaces tests that the path and name of a file with
+// spaces reach the trigger's command as one argument each
+func TestTriggerFileWithSpaces(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Invoice March.pdf")
+	if err := os.WriteFile(path, []byte("pdf"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "mock"
+	cfg.MockFixtures = writeMockFixtures(t, `{"rules": [{"contains": "describe", "response": "A blank picture"}]}`)
+	exec := executor.NewExecutor(cfg)
+	run := func(tr *trigger.Trigger, path string) *executor.Result {
+		t.Helper()
+		command := tr.Command(path)
+		cmd := &nlp.Command{Type: nlp.CommandTypeAI, Intent: strings.TrimPrefix(command, "ask:"), RawInput: command}
+		if rest, ok := strings.CutPrefix(command, "shell:"); ok {
+			cmd = &nlp.Command{Type: nlp.CommandTypeShell, Intent: rest, RawInput: command}
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", command, err)
+		}
+		return result
+	}
+
+	if result := run(&trigger.Trigger{Watch: dir, Run: "shell:ls {file}"}, path); result.IsError || strings.TrimSpace(result.Output) != path {
+		t.Errorf("Expected ls to get the path as one argument, got %+v", result)
+	}
+	if result := run(&trigger.Trigger{Watch: dir, Run: "shell:echo {name}"}, path); strings.TrimSpace(result.Output) != "Invoice March.pdf" {
+		t.Errorf("Expected the name as one argument, got %q", result.Output)
+	}
+
+	picture := filepath.Join(dir, "Screenshot from today.png")
+	if err := os.Rename(writePNG(t, 40, 20), picture); err != nil {
+		t.Fatal(err)
+	}
+	if result := run(&trigger.Trigger{Watch: dir, Run: "ask:--image {file} describe this screenshot"}, picture); result.IsError || !strings.Contains(result.Output, "A blank picture") {
+		t.Errorf("Expected the image to be read, got %+v", result)
+	}
+}
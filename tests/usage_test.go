@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/usage"
+)
+
+// TestUsageCost tests estimating the cost of requests
+func TestUsageCost(t *testing.T) {
+	// The longest matching model name sets the price
+	cost, priced := usage.Entry{Provider: "openai", Model: "gpt-4o-mini-2024-07-18", PromptTokens: 1000000}.Cost()
+	if !priced || cost != 0.15 {
+		t.Errorf("Expected gpt-4o-mini pricing, got %v (priced %v)", cost, priced)
+	}
+	if cost, priced := (usage.Entry{Provider: "ollama", Model: "llama3", PromptTokens: 5000}).Cost(); !priced || cost != 0 {
+		t.Errorf("Expected local models to be free, got %v", cost)
+	}
+	if _, priced := (usage.Entry{Provider: "openai", Model: "future-model", PromptTokens: 5000}).Cost(); priced {
+		t.Errorf("Expected an unknown cloud model to have no price")
+	}
+}
+
+// TestUsageRecordAndSummarize tests the usage log and its totals
+func TestUsageRecordAndSummarize(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := usage.Record("gemini", "gemini-2.0-flash", 1000, 500); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+	if err := usage.Record("gemini", "gemini-2.0-flash", 2000, 100); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+	if err := usage.Record("ollama", "llama3", 300, 300); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+
+	entries, err := usage.Load(usage.StartOfMonth(time.Now()))
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d (%v)", len(entries), err)
+	}
+	if later, _ := usage.Load(time.Now().Add(time.Hour)); len(later) != 0 {
+		t.Errorf("Expected no entries after now, got %d", len(later))
+	}
+
+	totals := usage.Summarize(entries)
+	if len(totals) != 2 || totals[0].Provider != "gemini" || totals[0].Requests != 2 || totals[0].PromptTokens != 3000 {
+		t.Fatalf("Unexpected totals: %+v", totals)
+	}
+}
+
+// TestBudgetBlocksCloudQueries tests refusing cloud AI queries over budget
+func TestBudgetBlocksCloudQueries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := usage.Record("openai", "gpt-4", 1000000, 0); err != nil {
+		t.Fatalf("Failed to record usage: %v", err)
+	}
+
+	cfg := &config.Config{
+		AIProvider:   "openai",
+		OpenAIAPIKey: "test-key",
+		BudgetUSD:    5,
+		BudgetAction: config.BudgetBlock,
+	}
+	exec := executor.NewExecutor(cfg)
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeAI, Intent: "hello", RawInput: "ask:hello"})
+	if err != nil || !result.IsError || !strings.Contains(result.Output, "budget of $5.00 reached") {
+		t.Fatalf("Expected the query to be refused, got %+v (%v)", result, err)
+	}
+
+	// Usage and configuration still work over budget
+	result, _ = exec.Execute(&nlp.Command{Type: nlp.CommandTypeUsage, RawInput: "usage"})
+	if result.IsError || !strings.Contains(result.Output, "$30.00 of $5.00") {
+		t.Errorf("Expected the usage report to show the budget, got:\n%s", result.Output)
+	}
+}
+
+// TestNetworkUsage tests the network log, its daily totals, and the cap
+func TestNetworkUsage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if err := usage.RecordNetwork(usage.NetworkConnect, 3<<20, 0); err != nil {
+		t.Fatalf("Failed to record traffic: %v", err)
+	}
+	if err := usage.RecordNetwork(usage.NetworkAI, 1000, 24000); err != nil {
+		t.Fatalf("Failed to record traffic: %v", err)
+	}
+	if err := usage.RecordNetwork(usage.NetworkAI, 0, 0); err != nil {
+		t.Fatalf("Failed to skip empty traffic: %v", err)
+	}
+
+	entries, err := usage.LoadNetwork(usage.StartOfMonth(time.Now()))
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d (%v)", len(entries), err)
+	}
+	days := usage.SummarizeNetwork(entries)
+	if len(days) != 1 || len(days[0].Sources) != 2 || days[0].Sources[0].Source != usage.NetworkAI ||
+		days[0].Sent != 3<<20+1000 || days[0].Received != 24000 {
+		t.Fatalf("Unexpected daily totals: %+v", days)
+	}
+	if total, err := usage.MonthNetwork(time.Now()); err != nil || total != 3<<20+25000 {
+		t.Errorf("Expected this month's traffic summed, got %d (%v)", total, err)
+	}
+
+	cfg := config.DefaultConfig()
+	parser := nlp.NewParser(cfg)
+	exec := executor.NewExecutor(cfg)
+	run := func(input string) *executor.Result {
+		t.Helper()
+		cmd, err := parser.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		result, err := exec.Execute(cmd)
+		if err != nil {
+			t.Fatalf("Execute(%q) error: %v", input, err)
+		}
+		return result
+	}
+
+	if result := run("config:network cap 1.5GB"); result.IsError || cfg.NetworkCapMB != 1536 {
+		t.Fatalf("Expected a 1536 MB cap, got %d: %+v", cfg.NetworkCapMB, result)
+	}
+	if result := run("config:network action pause"); result.IsError || cfg.NetworkCapAction != config.NetworkCapPause {
+		t.Errorf("Expected the pause action, got %+v", result)
+	}
+	if result := run("config:network cap lots"); !result.IsError || cfg.NetworkCapMB != 1536 {
+		t.Errorf("Expected an invalid size to be refused, got %+v", result)
+	}
+
+	result := run("usage network")
+	if result.IsError || !strings.Contains(result.Output, "of 1.5 GiB") || !strings.Contains(result.Output, "connect") {
+		t.Errorf("Expected the network report with the cap, got:\n%s", result.Output)
+	}
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+	"github.com/agnath18K/lumo/pkg/vcr"
+)
+
+// TestVCRRecordAndReplay tests recording requests to a sanitized cassette and replaying them offline
+func TestVCRRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"echo": "`+string(body)+`"}`)
+	}))
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, err := vcr.NewRecorder(cassette, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+	post := func(client *http.Client, body string) (string, error) {
+		resp, err := client.Post(server.URL+"/v1/generate?key=AIzaSyA1234567890abcdefghijklmnopqrstu", "application/json", strings.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+	for _, body := range []string{"first", "second sk-abcdefghijklmnopqrstuvwx"} {
+		if _, err := post(client, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server.Close()
+
+	data, _ := os.ReadFile(cassette)
+	if strings.Contains(string(data), "AIzaSy") || strings.Contains(string(data), "sk-abc") {
+		t.Errorf("Expected API keys to be removed from the cassette:\n%s", data)
+	}
+	if !strings.Contains(string(data), "key="+vcr.Redacted) {
+		t.Errorf("Expected the key parameter to be redacted:\n%s", data)
+	}
+
+	// The server is gone; the player answers from the cassette, matching
+	// by body before order
+	player, err := vcr.NewPlayer(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: player}
+	if got, err := post(client, "second sk-abcdefghijklmnopqrstuvwx"); err != nil || !strings.Contains(got, "second") {
+		t.Errorf("Expected the second recorded answer, got %q (%v)", got, err)
+	}
+	if got, err := post(client, "changed"); err != nil || !strings.Contains(got, "first") {
+		t.Errorf("Expected the next answer for the same URL, got %q (%v)", got, err)
+	}
+	if _, err := post(client, "first"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("Expected an error once the cassette is used up, got %v", err)
+	}
+}
+
+// TestVCRReplayWithoutAPIKey tests answering a question from a cassette with no API key or network
+func TestVCRReplayWithoutAPIKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cassette := filepath.Join(t.TempDir(), "openai.json")
+	os.WriteFile(cassette, []byte(`{
+		"version": 1,
+		"interactions": [{
+			"request": {"method": "POST", "url": "https://api.openai.com/v1/chat/completions"},
+			"response": {"status": 200, "content_type": "application/json",
+				"body": "{\"choices\": [{\"message\": {\"role\": \"assistant\", \"content\": \"Use du -sh *\"}}]}"}
+		}]
+	}`), 0600)
+
+	player, err := vcr.NewPlayer(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai.SetTransport(player)
+	defer ai.SetTransport(nil)
+
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "openai"
+	cfg.OpenAIAPIKey = ""
+	exec := executor.NewExecutor(cfg)
+	result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeAI, Intent: "what uses disk space", RawInput: "ask:what uses disk space"})
+	if err != nil || result.IsError || !strings.Contains(result.Output, "du -sh") {
+		t.Fatalf("Expected the recorded answer, got %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnath18K/lumo/pkg/ai"
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/executor"
+	"github.com/agnath18K/lumo/pkg/nlp"
+)
+
+// writePNG writes a solid PNG image of the given size
+func writePNG(t *testing.T, width, height int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 30, B: 30, A: 255})
+		}
+	}
+	path := filepath.Join(t.TempDir(), "screenshot.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadImage tests preparing images for multimodal models
+func TestLoadImage(t *testing.T) {
+	small := writePNG(t, 40, 20)
+	img, err := ai.LoadImage(small)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, _ := os.ReadFile(small)
+	if img.MIMEType != "image/png" || !bytes.Equal(img.Data, original) {
+		t.Errorf("Expected a small PNG to be sent as is, got %s", img.MIMEType)
+	}
+	if !strings.HasPrefix(img.DataURL(), "data:image/png;base64,") {
+		t.Errorf("Unexpected data URL prefix: %.30s", img.DataURL())
+	}
+
+	// Large images are scaled down, keeping their aspect ratio
+	img, err = ai.LoadImage(writePNG(t, 4096, 1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scaled, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil || scaled.Width != 2048 || scaled.Height != 512 {
+		t.Errorf("Expected a 2048x512 image, got %dx%d (%v)", scaled.Width, scaled.Height, err)
+	}
+
+	notImage := filepath.Join(t.TempDir(), "notes.txt")
+	os.WriteFile(notImage, []byte("not an image"), 0600)
+	if _, err := ai.LoadImage(notImage); err == nil {
+		t.Errorf("Expected an error for a file that is not an image")
+	}
+}
+
+// TestAskAboutImage tests ask:--image with a provider that sees images and one that does not
+func TestAskAboutImage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	picture := writePNG(t, 40, 20)
+
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "mock"
+	cfg.MockFixtures = writeMockFixtures(t, `{"rules": [{"contains": "what error", "response": "Permission denied"}]}`)
+	exec := executor.NewExecutor(cfg)
+	ask := func(intent string) *executor.Result {
+		result, err := exec.Execute(&nlp.Command{Type: nlp.CommandTypeAI, Intent: intent, RawInput: "ask:" + intent})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := ask("--image " + picture + " what error is shown"); result.IsError || !strings.Contains(result.Output, "Permission denied") {
+		t.Errorf("Expected an answer about the image, got %+v", result)
+	}
+	if result := ask("--persona=tutor --image=" + picture + " what error is shown"); result.IsError {
+		t.Errorf("Expected --persona and --image to combine, got %+v", result)
+	}
+	if result := ask("--image"); !result.IsError || !strings.Contains(result.Output, "--image needs a value") {
+		t.Errorf("Expected a missing image to be reported, got %+v", result)
+	}
+	if result := ask("--image missing.png what is this"); !result.IsError {
+		t.Errorf("Expected a missing file to be reported, got %+v", result)
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.AIProvider = "ollama"
+	exec = executor.NewExecutor(cfg)
+	if result := ask("--image " + picture + " what is this"); !result.IsError || !strings.Contains(result.Output, "cannot answer questions about images") {
+		t.Errorf("Expected Ollama to be refused, got %+v", result)
+	}
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnath18K/lumo/pkg/config"
+	"github.com/agnath18K/lumo/pkg/connect"
+	"github.com/agnath18K/lumo/pkg/privacy"
+	"github.com/agnath18K/lumo/pkg/system"
+	"github.com/agnath18K/lumo/pkg/widget"
+)
+
+// writeTransferHistory replaces the transfer history in the state directory
+func writeTransferHistory(t *testing.T, stateHome string, history []connect.TransferStats) {
+	t.Helper()
+	dir := filepath.Join(stateHome, "lumo")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "transfer_history.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestUnreadTransfers tests counting files received since the history was shown
+func TestUnreadTransfers(t *testing.T) {
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	now := time.Now()
+
+	history := []connect.TransferStats{
+		{Time: now.Add(-2 * time.Hour), Direction: "received", Filename: "a.txt", Success: true},
+		{Time: now.Add(-time.Hour), Direction: "received", Filename: "b.txt", Success: false},
+		{Time: now.Add(-time.Hour), Direction: "sent", Filename: "c.txt", Success: true},
+	}
+	writeTransferHistory(t, stateHome, history)
+	if unread, err := connect.UnreadTransfers(); err != nil || unread != 1 {
+		t.Fatalf("Expected one unread transfer, got %d (%v)", unread, err)
+	}
+
+	if err := connect.MarkTransfersRead(); err != nil {
+		t.Fatal(err)
+	}
+	if unread, _ := connect.UnreadTransfers(); unread != 0 {
+		t.Errorf("Expected no unread transfers after reading the history, got %d", unread)
+	}
+
+	history = append(history, connect.TransferStats{Time: now.Add(time.Minute), Direction: "received", Filename: "d.txt", Success: true})
+	writeTransferHistory(t, stateHome, history)
+	if unread, _ := connect.UnreadTransfers(); unread != 1 {
+		t.Errorf("Expected the new file to be unread, got %d", unread)
+	}
+}
+
+// TestWidgetFormat tests rendering the status line
+func TestWidgetFormat(t *testing.T) {
+	status := &widget.Status{Provider: "openai", Daemon: true, UnreadTransfers: 2, Health: system.StatusWarning}
+
+	tests := []struct {
+		format string
+		status *widget.Status
+		want   string
+	}{
+		{widget.FormatPlain, status, "openai ● ⇣2 ⚠"},
+		{widget.FormatPlain, &widget.Status{Provider: "ollama"}, "ollama ○"},
+		{widget.FormatPlain, &widget.Status{Provider: "gemini", Health: system.StatusCritical}, "gemini ○ ✖"},
+		{widget.FormatTmux, status, "openai #[fg=green]●#[default] #[fg=cyan]⇣2#[default] #[fg=yellow]⚠#[default]"},
+	}
+	for _, test := range tests {
+		got, err := widget.Format(test.status, test.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("Format(%+v, %s) = %q, want %q", test.status, test.format, got, test.want)
+		}
+	}
+
+	line, err := widget.Format(status, widget.FormatJSON)
+	if err != nil || strings.Contains(line, "\n") {
+		t.Fatalf("Expected one line of JSON, got %q (%v)", line, err)
+	}
+	var decoded widget.Status
+	if err := json.Unmarshal([]byte(line), &decoded); err != nil || decoded.UnreadTransfers != 2 || !decoded.Daemon {
+		t.Errorf("Unexpected JSON status: %s", line)
+	}
+
+	if _, err := widget.Format(status, "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+// TestWidgetCache tests that the status is reused for a short time
+func TestWidgetCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "openai"
+	now := time.Now()
+
+	first := widget.Get(cfg, now)
+	if first.Provider != "openai" || first.Daemon || first.UnreadTransfers != 0 {
+		t.Fatalf("Unexpected status: %+v", first)
+	}
+
+	// A file arriving within the cache lifetime shows up once it expires
+	writeTransferHistory(t, stateHome, []connect.TransferStats{{Time: now, Direction: "received", Success: true}})
+	if cached := widget.Get(cfg, now.Add(widget.CacheTTL/2)); cached.UnreadTransfers != 0 {
+		t.Errorf("Expected the cached status, got %+v", cached)
+	}
+	if fresh := widget.Get(cfg, now.Add(widget.CacheTTL)); fresh.UnreadTransfers != 1 {
+		t.Errorf("Expected a fresh status after the cache expired, got %+v", fresh)
+	}
+
+	// Strict privacy mode changes the provider, which is not served from the cache
+	cfg.PrivacyMode = privacy.ModeStrict
+	if status := widget.Get(cfg, now.Add(widget.CacheTTL)); status.Provider != privacy.LocalProvider {
+		t.Errorf("Expected the local provider in strict mode, got %q", status.Provider)
+	}
+}
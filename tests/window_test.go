@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/agnath18K/lumo/internal/assistant"
+	"github.com/agnath18K/lumo/internal/core"
+)
+
+// TestWindowTilingParsing tests parsing of tiling, monitor, and workspace commands
+func TestWindowTilingParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	testCases := []struct {
+		input    string
+		action   string
+		target   string
+		argument string
+		value    string
+	}{
+		{"move firefox to workspace 2", "move-to-workspace", "firefox", "workspace", "2"},
+		{"switch to workspace three", "switch-workspace", "3", "", ""},
+		{"tile firefox left", "tile", "firefox", "position", "left"},
+		{"snap terminal to the top right corner", "tile", "terminal", "position", "top-right"},
+		{"move the window to the bottom half", "tile", "current", "position", "bottom"},
+		{"move code to monitor 2", "move-to-monitor", "code", "monitor", "2"},
+		{"move firefox to the next monitor", "move-to-monitor", "firefox", "monitor", "next"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			cmd, err := processor.Process(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to process command: %v", err)
+			}
+			if cmd.Type != core.CommandTypeWindow || cmd.Action != tc.action {
+				t.Fatalf("Expected window:%s, got %s:%s", tc.action, cmd.Type, cmd.Action)
+			}
+			if cmd.Target != tc.target {
+				t.Errorf("Expected target %q, got %q", tc.target, cmd.Target)
+			}
+			if tc.argument != "" && cmd.Arguments[tc.argument] != tc.value {
+				t.Errorf("Expected %s=%s, got %v", tc.argument, tc.value, cmd.Arguments[tc.argument])
+			}
+		})
+	}
+}
+
+// TestLaunchApplicationParsing tests that launch flags are parsed and URIs keep their case
+func TestLaunchApplicationParsing(t *testing.T) {
+	processor := assistant.NewProcessor()
+
+	cmd, err := processor.Process("Open firefox https://Example.com/Path --wait")
+	if err != nil {
+		t.Fatalf("Failed to process command: %v", err)
+	}
+	if cmd.Type != core.CommandTypeApplication || cmd.Action != "launch" {
+		t.Fatalf("Expected application:launch, got %s:%s", cmd.Type, cmd.Action)
+	}
+	if cmd.Target != "firefox" {
+		t.Errorf("Expected target firefox, got %q", cmd.Target)
+	}
+	args, _ := cmd.Arguments["args"].([]string)
+	if len(args) != 1 || args[0] != "https://Example.com/Path" {
+		t.Errorf("Expected URI argument with original case, got %v", cmd.Arguments["args"])
+	}
+	if cmd.Arguments["wait"] != "true" {
+		t.Errorf("Expected wait flag to be set")
+	}
+}